@@ -0,0 +1,353 @@
+package commands
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"text/tabwriter"
+
+	"mcs-mcp/internal/mcp"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/spf13/cobra"
+)
+
+// cliClient identifies the analysis subcommands in the audit trail.
+const cliClient = "mcs-mcp cli"
+
+// analysisFlags are the flags shared by the analysis subcommands.
+type analysisFlags struct {
+	instance    string
+	project     string
+	board       int
+	jql         string
+	issueTypes  []string // Registered by the subcommands whose tool filters by type
+	historyDays int
+	jsonOutput  bool
+}
+
+func (f *analysisFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVar(&f.instance, "instance", "", "Named Jira instance (JIRA_INSTANCES); default connection if empty")
+	cmd.Flags().StringVar(&f.project, "project", "", "Project key")
+	cmd.Flags().IntVar(&f.board, "board", 0, "Board ID")
+	cmd.Flags().StringVar(&f.jql, "jql", "", "Analyze the issues of this JQL instead of a board")
+	cmd.Flags().IntVar(&f.historyDays, "history-days", 0, "Lookback window in days; default: the tool's default")
+	cmd.Flags().BoolVar(&f.jsonOutput, "json", false, "Print the tool's response envelope as JSON instead of tables")
+}
+
+// args returns the tool arguments of the shared flags.
+func (f *analysisFlags) args() map[string]any {
+	args := map[string]any{}
+	set := func(key string, v any, ok bool) {
+		if ok {
+			args[key] = v
+		}
+	}
+	set("project_key", f.project, f.project != "")
+	set("board_id", f.board, f.board != 0)
+	set("jql", f.jql, f.jql != "")
+	set("issue_types", f.issueTypes, len(f.issueTypes) > 0)
+	set("history_window_days", f.historyDays, f.historyDays > 0)
+	return args
+}
+
+// cliEnvelope is the response envelope of a tool with its data left raw.
+type cliEnvelope struct {
+	Context    map[string]any          `json:"context"`
+	Data       json.RawMessage         `json:"data"`
+	Guardrails *mcp.ResponseGuardrails `json:"guardrails"`
+}
+
+// runTool calls a tool like an MCP client would and prints its envelope as
+// JSON, or decodes it and hands it to table for human-readable output.
+func runTool(cmd *cobra.Command, f *analysisFlags, tool string, args map[string]any, table func(io.Writer, cliEnvelope) error) error {
+	if f.project == "" && f.jql == "" {
+		return fmt.Errorf("--project (with --board) or --jql is required")
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := mcp.NewServer(cfg, jiraClient)
+	if err := server.UseInstance(f.instance); err != nil {
+		return err
+	}
+	raw, err := server.CallTool(ctx, cliClient, tool, args)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if f.jsonOutput {
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, raw, "", "  "); err != nil {
+			return err
+		}
+		buf.WriteByte('\n')
+		_, err := buf.WriteTo(out)
+		return err
+	}
+	var env cliEnvelope
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return fmt.Errorf("unexpected %s result: %w", tool, err)
+	}
+	if err := table(out, env); err != nil {
+		return err
+	}
+	printGuardrails(out, env.Guardrails)
+	return nil
+}
+
+// printGuardrails prints the warnings and insights of a result.
+func printGuardrails(out io.Writer, g *mcp.ResponseGuardrails) {
+	if g == nil {
+		return
+	}
+	for _, w := range g.Warnings {
+		fmt.Fprintf(out, "\nWARNING: %s", w)
+	}
+	for _, i := range g.Insights {
+		fmt.Fprintf(out, "\nNOTE: %s", i)
+	}
+	if len(g.Warnings)+len(g.Insights) > 0 {
+		fmt.Fprintln(out)
+	}
+}
+
+// percentileRows lists the percentiles of a result in confidence order.
+func percentileRows(p simulation.Percentiles) []struct {
+	label string
+	value float64
+} {
+	return []struct {
+		label string
+		value float64
+	}{
+		{"P10 aggressive", p.Aggressive},
+		{"P30 unlikely", p.Unlikely},
+		{"P50 coin toss", p.CoinToss},
+		{"P70 probable", p.Probable},
+		{"P85 likely", p.Likely},
+		{"P90 conservative", p.Conservative},
+		{"P95 safe", p.Safe},
+		{"P98 almost certain", p.AlmostCertain},
+	}
+}
+
+var (
+	forecastFlags    analysisFlags
+	forecastMode     string
+	forecastBacklog  bool
+	forecastWIP      bool
+	forecastItems    int
+	forecastDays     int
+	forecastDate     string
+	forecastAsOf     string
+	forecastEnsemble bool
+)
+
+var forecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Run a Monte-Carlo forecast (forecast_monte_carlo)",
+	Long: `Forecasts when the backlog of a board is done (--mode duration) or how many
+items are done by a date (--mode scope), exactly as the forecast_monte_carlo tool
+does, and prints the percentiles. The board needs a confirmed workflow mapping
+(see the setup command).`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		args := forecastFlags.args()
+		args["mode"] = forecastMode
+		args["include_existing_backlog"] = forecastBacklog
+		args["include_wip"] = forecastWIP
+		if forecastItems > 0 {
+			args["additional_items"] = forecastItems
+		}
+		if forecastDays > 0 {
+			args["target_days"] = forecastDays
+		}
+		if forecastDate != "" {
+			args["target_date"] = forecastDate
+		}
+		if forecastAsOf != "" {
+			args["as_of_date"] = forecastAsOf
+		}
+		if forecastEnsemble {
+			args["ensemble"] = true
+		}
+		return runTool(cmd, &forecastFlags, "forecast_monte_carlo", args, printForecast)
+	},
+}
+
+func printForecast(out io.Writer, env cliEnvelope) error {
+	var res simulation.Result
+	if err := json.Unmarshal(env.Data, &res); err != nil {
+		return fmt.Errorf("unexpected forecast result: %w", err)
+	}
+	dates, _ := res.Context["completion_dates"].(map[string]any)
+	keys := []string{"aggressive", "unlikely", "coin_toss", "probable", "likely", "conservative", "safe", "almost_certain"}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if dates != nil {
+		fmt.Fprintln(w, "CONFIDENCE\tDAYS\tDATE")
+	} else {
+		fmt.Fprintln(w, "CONFIDENCE\tITEMS")
+	}
+	for i, row := range percentileRows(res.Percentiles) {
+		if dates != nil {
+			fmt.Fprintf(w, "%s\t%.0f\t%v\n", row.label, row.value, dates[keys[i]])
+		} else {
+			fmt.Fprintf(w, "%s\t%.0f\n", row.label, row.value)
+		}
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\nPredictability: %s (fat-tail ratio %.2f)\n", res.Predictability, res.FatTailRatio)
+	if e := res.Ensemble; e != nil {
+		fmt.Fprintf(out, "Cycle-time model: P85 %.0f days with %d item(s) at once; disagreement %.0f%%, model risk %s\n", e.Percentiles.Likely, e.Parallelism, e.Disagreement*100, e.ModelRisk)
+	}
+	return nil
+}
+
+var (
+	cycleTimeFlags  analysisFlags
+	cycleTimeByType bool
+	cycleTimeStart  string
+	cycleTimeEnd    string
+)
+
+var cycleTimeCmd = &cobra.Command{
+	Use:   "cycle-time",
+	Short: "Assess the cycle times of delivered items (analyze_cycle_time)",
+	Long: `Computes the cycle-time percentiles (the service level expectation) of a board,
+exactly as the analyze_cycle_time tool does, and prints them, per issue type
+with --by-type.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		args := cycleTimeFlags.args()
+		if cycleTimeByType {
+			args["by_type"] = true
+		}
+		if cycleTimeStart != "" {
+			args["start_status"] = cycleTimeStart
+		}
+		if cycleTimeEnd != "" {
+			args["end_status"] = cycleTimeEnd
+		}
+		return runTool(cmd, &cycleTimeFlags, "analyze_cycle_time", args, printCycleTime)
+	},
+}
+
+func printCycleTime(out io.Writer, env cliEnvelope) error {
+	var res simulation.Result
+	if err := json.Unmarshal(env.Data, &res); err != nil {
+		return fmt.Errorf("unexpected cycle-time result: %w", err)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PERCENTILE\tDAYS")
+	for _, row := range percentileRows(res.Percentiles) {
+		fmt.Fprintf(w, "%s\t%.1f\n", row.label, row.value)
+	}
+	w.Flush()
+	fmt.Fprintf(out, "\nPredictability: %s (fat-tail ratio %.2f)\n", res.Predictability, res.FatTailRatio)
+
+	if len(res.TypeBreakdown) > 0 {
+		fmt.Fprintln(out)
+		w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tITEMS\tP50\tP85\tP95\tPREDICTABILITY")
+		for _, t := range res.TypeBreakdown {
+			fmt.Fprintf(w, "%s\t%d\t%.1f\t%.1f\t%.1f\t%s\n", t.IssueType, t.Count, t.Percentiles.CoinToss, t.Percentiles.Likely, t.Percentiles.Safe, t.Predictability)
+		}
+		w.Flush()
+	}
+	return nil
+}
+
+var (
+	agingFlags   analysisFlags
+	agingAgeType string
+	agingTier    string
+	agingAsOf    string
+)
+
+var agingCmd = &cobra.Command{
+	Use:   "aging",
+	Short: "List the age of the items in progress (analyze_work_item_age)",
+	Long: `Lists the items in progress of a board, oldest first, with their age against the
+historical cycle times, exactly as the analyze_work_item_age tool does.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		args := agingFlags.args()
+		args["age_type"] = agingAgeType
+		if agingTier != "" {
+			args["tier_filter"] = agingTier
+		}
+		if agingAsOf != "" {
+			args["as_of_date"] = agingAsOf
+		}
+		return runTool(cmd, &agingFlags, "analyze_work_item_age", args, printAging)
+	},
+}
+
+func printAging(out io.Writer, env cliEnvelope) error {
+	var res struct {
+		Aging   []stats.InventoryAge `json:"aging"`
+		Summary stats.AgingSummary   `json:"summary"`
+	}
+	if err := json.Unmarshal(env.Data, &res); err != nil {
+		return fmt.Errorf("unexpected aging result: %w", err)
+	}
+	age := func(a stats.InventoryAge) float64 {
+		if agingAgeType == "wip" && a.AgeSinceCommitment != nil {
+			return *a.AgeSinceCommitment
+		}
+		return a.TotalAgeSinceCreation
+	}
+	slices.SortStableFunc(res.Aging, func(a, b stats.InventoryAge) int {
+		switch {
+		case age(a) > age(b):
+			return -1
+		case age(a) < age(b):
+			return 1
+		}
+		return 0
+	})
+
+	fmt.Fprintf(out, "%d item(s); historical cycle time P50 %.1f, P85 %.1f, P95 %.1f days\n\n", res.Summary.TotalItems, res.Summary.P50Threshold, res.Summary.P85Threshold, res.Summary.P95Threshold)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "KEY\tTYPE\tSTATUS\tAGE (DAYS)\tIN STATUS\tPERCENTILE\tFLAG")
+	for _, a := range res.Aging {
+		flag := ""
+		if a.IsAgingOutlier {
+			flag = "outlier"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%.1f\t%.1f\t%d\t%s\n", a.Key, a.Type, a.Status, age(a), a.AgeInCurrentStatus, a.Percentile, flag)
+	}
+	return w.Flush()
+}
+
+func init() {
+	forecastFlags.register(forecastCmd)
+	forecastCmd.Flags().StringSliceVar(&forecastFlags.issueTypes, "types", nil, "Restrict the scope and the throughput to these issue types (comma-separated)")
+	forecastCmd.Flags().StringVar(&forecastMode, "mode", "duration", "duration (when is the scope done) or scope (how many items by a date)")
+	forecastCmd.Flags().BoolVar(&forecastBacklog, "backlog", true, "Include the unstarted items of the board")
+	forecastCmd.Flags().BoolVar(&forecastWIP, "wip", true, "Include the items in progress")
+	forecastCmd.Flags().IntVar(&forecastItems, "items", 0, "Additional items not yet in Jira")
+	forecastCmd.Flags().IntVar(&forecastDays, "days", 0, "Scope mode: horizon in days")
+	forecastCmd.Flags().StringVar(&forecastDate, "date", "", "Scope mode: target date (YYYY-MM-DD)")
+	forecastCmd.Flags().StringVar(&forecastAsOf, "as-of", "", "Forecast as of this past date (YYYY-MM-DD)")
+	forecastCmd.Flags().BoolVar(&forecastEnsemble, "ensemble", false, "Duration mode: also run the cycle-time model and report the disagreement")
+
+	cycleTimeFlags.register(cycleTimeCmd)
+	cycleTimeCmd.Flags().StringSliceVar(&cycleTimeFlags.issueTypes, "types", nil, "Restrict to these issue types (comma-separated)")
+	cycleTimeCmd.Flags().BoolVar(&cycleTimeByType, "by-type", false, "Also print the percentiles per issue type")
+	cycleTimeCmd.Flags().StringVar(&cycleTimeStart, "start-status", "", "Start status; default: the commitment point")
+	cycleTimeCmd.Flags().StringVar(&cycleTimeEnd, "end-status", "", "End status; default: the Finished tier")
+
+	agingFlags.register(agingCmd)
+	agingCmd.Flags().StringVar(&agingAgeType, "age-type", "wip", "wip (since the commitment point) or total (since creation)")
+	agingCmd.Flags().StringVar(&agingTier, "tier", "", "WIP (default), Upstream, Downstream, or All")
+	agingCmd.Flags().StringVar(&agingAsOf, "as-of", "", "Ages as of this past date (YYYY-MM-DD)")
+
+	rootCmd.AddCommand(forecastCmd, cycleTimeCmd, agingCmd)
+}
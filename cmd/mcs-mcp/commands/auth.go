@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/jira"
+
+	"github.com/pkg/browser"
+	"github.com/spf13/cobra"
+)
+
+// loginTimeout bounds how long the login waits for the user's consent.
+const loginTimeout = 5 * time.Minute
+
+var authInstance string
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage the Jira OAuth 2.0 login",
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Authorize MCS-MCP against Jira Cloud via OAuth 2.0 (3LO)",
+	Long: `Opens the Atlassian consent page in the browser and stores the resulting
+access and refresh tokens in the data directory (readable by the owner only).
+The server refreshes the access token automatically from then on.
+
+Requires an OAuth 2.0 (3LO) app in the Atlassian developer console with the
+scopes read:jira-work and read:jira-user, its callback URL set to
+http://localhost:<JIRA_OAUTH_CALLBACK_PORT>/callback, and JIRA_TOKEN_TYPE=oauth,
+JIRA_OAUTH_CLIENT_ID, and JIRA_OAUTH_CLIENT_SECRET in the .env file.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jc, err := authConfig(authInstance)
+		if err != nil {
+			return err
+		}
+		if strings.ToLower(jc.TokenType) != jira.TokenTypeOAuth {
+			fmt.Fprintf(os.Stderr, "Note: JIRA_TOKEN_TYPE is %q; set it to %q for the server to use this login.\n", jc.TokenType, jira.TokenTypeOAuth)
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		ctx, cancel := context.WithTimeout(ctx, loginTimeout)
+		defer cancel()
+
+		tok, err := jira.OAuthLogin(ctx, jc.OAuth, jc.BaseURL, func(url string) error {
+			fmt.Fprintf(os.Stderr, "Opening the Atlassian consent page. If no browser opens, visit:\n\n  %s\n\n", url)
+			_ = browser.OpenURL(url) // The printed URL is the fallback
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("login failed: %w", err)
+		}
+		if err := jira.SaveOAuthToken(jc.OAuth.TokenFile, tok); err != nil {
+			return fmt.Errorf("failed to store the tokens: %w", err)
+		}
+		fmt.Fprintf(os.Stderr, "Logged in to %s. Tokens stored in %s.\n", tok.SiteURL, jc.OAuth.TokenFile)
+		return nil
+	},
+}
+
+// authConfig returns the Jira connection of an instance ("" for the default one).
+func authConfig(instance string) (jira.Config, error) {
+	name := strings.ToLower(instance)
+	if name == "" || name == config.DefaultInstance {
+		return cfg.Jira, nil
+	}
+	jc, ok := cfg.JiraInstances[name]
+	if !ok {
+		return jira.Config{}, fmt.Errorf("unknown Jira instance %q; add it to JIRA_INSTANCES", instance)
+	}
+	return jc, nil
+}
+
+func init() {
+	authLoginCmd.Flags().StringVar(&authInstance, "instance", "", "Named Jira instance (JIRA_INSTANCES) to log in to; default connection if empty")
+	authCmd.AddCommand(authLoginCmd)
+	rootCmd.AddCommand(authCmd)
+}
@@ -0,0 +1,120 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"mcs-mcp/internal/mcp"
+	"mcs-mcp/internal/notify"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var (
+	digestWebhook  string
+	digestKind     string
+	digestSources  []string
+	digestAnalyses []string
+	digestInterval time.Duration
+	digestDryRun   bool
+)
+
+var digestCmd = &cobra.Command{
+	Use:   "digest",
+	Short: "Post a digest of alerts, aging outliers, flow debt, and forecast drift to Slack or Teams",
+	Long: `Runs the digest analyses (fired alert rules, aging outliers, flow debt, and the
+drift of a backlog forecast) for every configured source and posts the findings to
+a Slack or Teams webhook. Sources, analyses, and the webhook default to
+MCS_DIGEST_SOURCES, MCS_DIGEST_ANALYSES, MCS_DIGEST_WEBHOOK_URL, and
+MCS_DIGEST_WEBHOOK_KIND. Each source needs a confirmed workflow mapping. Without --interval it posts once and
+exits (suitable for cron); with --interval it repeats until interrupted.
+--dry-run prints the digest instead of posting it.`,
+	RunE: func(cmd *cobra.Command, _ []string) error {
+		if digestInterval < 0 {
+			return fmt.Errorf("--interval must not be negative")
+		}
+		raw := cfg.DigestSources
+		if len(digestSources) > 0 {
+			raw = digestSources
+		}
+		if len(raw) == 0 {
+			return fmt.Errorf("no sources: set MCS_DIGEST_SOURCES or pass --source")
+		}
+		var sources []mcp.DigestSource
+		for _, r := range raw {
+			src, err := mcp.ParseDigestSource(r)
+			if err != nil {
+				return err
+			}
+			sources = append(sources, src)
+		}
+		analyses := cfg.DigestAnalyses
+		if len(digestAnalyses) > 0 {
+			analyses = digestAnalyses
+		}
+
+		hook := notify.Webhook{Kind: cfg.DigestWebhookKind, URL: cfg.DigestWebhookURL}
+		if digestWebhook != "" {
+			hook.URL = digestWebhook
+		}
+		if digestKind != "" {
+			kind, err := notify.ParseKind(digestKind)
+			if err != nil {
+				return err
+			}
+			hook.Kind = kind
+		}
+		if hook.URL == "" && !digestDryRun {
+			return fmt.Errorf("no webhook: set MCS_DIGEST_WEBHOOK_URL, pass --webhook, or use --dry-run")
+		}
+
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		server := mcp.NewServer(cfg, jiraClient)
+		for {
+			d, err := server.BuildDigest(ctx, sources, analyses)
+			if err != nil {
+				if ctx.Err() != nil {
+					return nil
+				}
+				return err
+			}
+			if digestDryRun {
+				fmt.Fprint(cmd.OutOrStdout(), d.String())
+			} else if err := hook.Send(ctx, d); err != nil {
+				if digestInterval == 0 {
+					return err
+				}
+				log.Warn().Err(err).Msg("Digest delivery failed")
+			} else {
+				log.Info().Int("sources", len(d.Sections)).Int("alerts", d.Alerts()).Msg("Digest posted")
+			}
+
+			if digestInterval == 0 {
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(digestInterval):
+			}
+		}
+	},
+}
+
+func init() {
+	digestCmd.Flags().StringVar(&digestWebhook, "webhook", "", "Webhook URL; default: MCS_DIGEST_WEBHOOK_URL")
+	digestCmd.Flags().StringVar(&digestKind, "kind", "", "Webhook kind, slack or teams; default: MCS_DIGEST_WEBHOOK_KIND")
+	digestCmd.Flags().StringSliceVar(&digestSources, "source", nil, "Source as [instance/]PROJECT[:board] (repeatable); default: MCS_DIGEST_SOURCES")
+	digestCmd.Flags().StringSliceVar(&digestAnalyses, "analyses", nil, "Analyses to run: "+strings.Join(mcp.DigestAnalyses, ", ")+"; default: MCS_DIGEST_ANALYSES or all")
+	digestCmd.Flags().DurationVar(&digestInterval, "interval", 0, "Repeat the digest at this interval (e.g. 24h) until interrupted; 0 posts once")
+	digestCmd.Flags().BoolVar(&digestDryRun, "dry-run", false, "Print the digest instead of posting it")
+	rootCmd.AddCommand(digestCmd)
+}
@@ -2,6 +2,8 @@ package commands
 
 import (
 	"context"
+	"net"
+	"strconv"
 
 	"mcs-mcp/internal/charts"
 	"mcs-mcp/internal/config"
@@ -24,6 +26,9 @@ var (
 	cfg *config.AppConfig
 
 	jiraClient jira.Client
+
+	webhookPort int
+	webhookHost string
 )
 
 var rootCmd = &cobra.Command{
@@ -50,36 +55,72 @@ using Monte-Carlo-Simulation based on Jira data.`,
 			Str("buildDate", BuildDate).
 			Msg("MCS-MCP starting")
 	},
-	Run: func(cmd *cobra.Command, args []string) {
-		server := mcp.NewServer(cfg, jiraClient)
-
-		// If chart rendering is enabled, start the HTTP server alongside stdio.
-		if server.ChartBuf() != nil {
-			httpSrv, err := httpd.New(server.ChartBuf(), charts.RenderChart)
-			if err != nil {
-				log.Fatal().Err(err).Msg("Failed to start chart HTTP server")
-			}
-			server.SetHTTPPort(httpSrv.Port())
-
-			g, ctx := errgroup.WithContext(context.Background())
-			g.Go(func() error { return httpSrv.Start(ctx) })
-			g.Go(func() error { return server.Run(ctx, Version) })
-
-			if err := g.Wait(); err != nil {
-				log.Fatal().Err(err).Msg("Server exited with error")
-			}
-		} else {
-			log.Info().Msg("MCP Server starting Stdio loop (chart rendering disabled)")
-			if err := server.Run(context.Background(), Version); err != nil {
-				log.Fatal().Err(err).Msg("MCP server exited with error")
-			}
+	Run: runServer,
+}
+
+// runServer serves the MCP tools over stdio, with the chart HTTP server and
+// the Jira webhook receiver alongside when enabled.
+func runServer(cmd *cobra.Command, args []string) {
+	server := mcp.NewServer(cfg, jiraClient)
+	server.RestoreActiveContext()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	g, ctx := errgroup.WithContext(ctx)
+
+	// If chart rendering is enabled, start the HTTP server alongside stdio.
+	if server.ChartBuf() != nil {
+		httpSrv, err := httpd.New(server.ChartBuf(), charts.RenderChart)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to start chart HTTP server")
 		}
-	},
+		server.SetHTTPPort(httpSrv.Port())
+		g.Go(func() error { return httpSrv.Start(ctx) })
+	} else {
+		log.Info().Msg("MCP Server starting Stdio loop (chart rendering disabled)")
+	}
+
+	if webhookPort > 0 {
+		if cfg.WebhookSecret == "" {
+			log.Fatal().Msg("MCS_WEBHOOK_SECRET is not set; the Jira webhook receiver does not accept unsigned deliveries")
+		}
+		webhookSrv, err := httpd.NewWebhook(net.JoinHostPort(webhookHost, strconv.Itoa(webhookPort)), cfg.WebhookSecret, server.ApplyWebhook)
+		if err != nil {
+			log.Fatal().Err(err).Msg("Failed to start Jira webhook receiver")
+		}
+		server.EnableWebhooks()
+		g.Go(func() error { return webhookSrv.Start(ctx) })
+	}
+
+	// The stdio loop ends when the client disconnects; stop the HTTP servers with it.
+	g.Go(func() error {
+		defer cancel()
+		return server.Run(ctx, Version)
+	})
+	if err := g.Wait(); err != nil {
+		log.Fatal().Err(err).Msg("Server exited with error")
+	}
 }
 
 func Execute() error {
 	return rootCmd.Execute()
 }
 
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Serve the MCP tools over stdio (the default command)",
+	Long: `Serves the MCP tools over stdio, like running mcs-mcp without a command. With
+--webhook-port it also accepts Jira issue webhooks (POST /webhook) and applies
+them to the event cache as they arrive, so synced boards stay current without
+polling Jira on every tool call. The receiver listens on 127.0.0.1 unless
+--webhook-host says otherwise, and requires MCS_WEBHOOK_SECRET.`,
+	Run: runServer,
+}
+
 func init() {
+	for _, cmd := range []*cobra.Command{rootCmd, serveCmd} {
+		cmd.Flags().IntVar(&webhookPort, "webhook-port", 0, "Accept Jira issue webhooks on this port (POST /webhook); 0 disables the receiver")
+		cmd.Flags().StringVar(&webhookHost, "webhook-host", "127.0.0.1", "Interface the webhook receiver listens on; 0.0.0.0 for all interfaces")
+	}
+	rootCmd.AddCommand(serveCmd)
 }
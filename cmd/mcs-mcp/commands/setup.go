@@ -0,0 +1,178 @@
+package commands
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"text/tabwriter"
+
+	"mcs-mcp/internal/mcp"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	setupInstance string
+	setupProject  string
+	setupBoard    int
+)
+
+var setupCmd = &cobra.Command{
+	Use:   "setup",
+	Short: "Connect a Jira board and confirm its workflow mapping interactively",
+	Long: `Walks through getting a board ready for forecasting without an AI client:
+tests the Jira connection, lists the boards of a project, ingests the history
+of the chosen board, and proposes a workflow mapping (tiers, roles, outcomes,
+commitment point, and status order). The mapping is only persisted once you
+confirm it; it is stored in the cache directory exactly as workflow_set_mapping
+and workflow_set_order store it, so the server picks it up as confirmed.
+
+--project and --board skip the corresponding prompts.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+		in := bufio.NewReader(cmd.InOrStdin())
+		out := cmd.OutOrStdout()
+		server := mcp.NewServer(cfg, jiraClient)
+
+		jc, err := authConfig(setupInstance)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Testing the connection to %s ...\n", jc.BaseURL)
+		projects, err := server.CheckConnection(ctx, setupInstance)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Connected. %d project(s) visible to this account.\n\n", projects)
+
+		projectKey := strings.ToUpper(strings.TrimSpace(setupProject))
+		for projectKey == "" {
+			if projectKey, err = prompt(in, out, "Project key: "); err != nil {
+				return err
+			}
+			projectKey = strings.ToUpper(projectKey)
+		}
+
+		boardID := setupBoard
+		if boardID == 0 {
+			if boardID, err = chooseBoard(ctx, server, in, out, projectKey); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(out, "\nIngesting the history of %s board %d. The first run fetches every issue and may take a while ...\n", projectKey, boardID)
+		proposal, err := server.ProposeSetup(ctx, projectKey, boardID, func(fetched, total int, message string) {
+			if total > 0 {
+				fmt.Fprintf(out, "\r  %d/%d issues (%s)", fetched, total, message)
+			}
+		})
+		fmt.Fprintln(out)
+		if err != nil {
+			return err
+		}
+		printProposal(out, proposal)
+
+		question := "Save this mapping? [y/N] "
+		if proposal.Confirmed {
+			question = "This board already has a confirmed mapping. Replace it with this proposal? [y/N] "
+		}
+		answer, err := prompt(in, out, question)
+		if err != nil {
+			return err
+		}
+		if !strings.EqualFold(answer, "y") && !strings.EqualFold(answer, "yes") {
+			fmt.Fprintln(out, "Nothing saved. Adjust the mapping later with workflow_set_mapping, or run setup again.")
+			return nil
+		}
+		if err := server.ConfirmSetup(ctx, proposal); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Saved the workflow of %s board %d. Connect your AI client and ask it to analyze project %s, board %d.\n", projectKey, boardID, projectKey, boardID)
+		return nil
+	},
+}
+
+// chooseBoard lists the boards of a project and asks for one by number or ID.
+func chooseBoard(ctx context.Context, server *mcp.Server, in *bufio.Reader, out io.Writer, projectKey string) (int, error) {
+	boards, err := server.SetupBoards(ctx, projectKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(boards) == 0 {
+		return 0, fmt.Errorf("no boards visible for project %s; check the project key and the account's permissions", projectKey)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tID\tNAME\tTYPE")
+	for i, b := range boards {
+		fmt.Fprintf(w, "%d\t%d\t%s\t%s\n", i+1, b.ID, b.Name, b.Type)
+	}
+	w.Flush()
+
+	for {
+		answer, err := prompt(in, out, "Board (# or ID): ")
+		if err != nil {
+			return 0, err
+		}
+		n, err := strconv.Atoi(answer)
+		if err != nil {
+			continue
+		}
+		if n >= 1 && n <= len(boards) {
+			return boards[n-1].ID, nil
+		}
+		for _, b := range boards {
+			if b.ID == n {
+				return n, nil
+			}
+		}
+		fmt.Fprintf(out, "No board %d in the list.\n", n)
+	}
+}
+
+// printProposal prints a proposed mapping as a table in status order.
+func printProposal(out io.Writer, p mcp.SetupProposal) {
+	fmt.Fprintf(out, "\nProposed workflow (%d issues):\n\n", p.Issues)
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "STATUS\tTIER\tROLE\tOUTCOME")
+	for _, st := range p.Statuses {
+		name := st.Name
+		if st.ID == p.CommitmentPoint {
+			name += " (commitment point)"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, st.Tier, st.Role, st.Outcome)
+	}
+	w.Flush()
+	if len(p.Resolutions) > 0 {
+		fmt.Fprintln(out, "\nResolutions:")
+		for _, name := range slices.Sorted(maps.Keys(p.Resolutions)) {
+			fmt.Fprintf(out, "  %s → %s\n", name, p.Resolutions[name])
+		}
+	}
+	fmt.Fprintf(out, "\nCommitment point: %s\n\n", p.CommitmentPointName())
+}
+
+// prompt asks a question and returns the trimmed answer.
+func prompt(in *bufio.Reader, out io.Writer, question string) (string, error) {
+	fmt.Fprint(out, question)
+	line, err := in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		return "", fmt.Errorf("setup aborted: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
+
+func init() {
+	setupCmd.Flags().StringVar(&setupInstance, "instance", "", "Named Jira instance (JIRA_INSTANCES) to set up a board of; default connection if empty")
+	setupCmd.Flags().StringVar(&setupProject, "project", "", "Project key; prompted for if empty")
+	setupCmd.Flags().IntVar(&setupBoard, "board", 0, "Board ID; chosen from the project's boards if 0")
+	rootCmd.AddCommand(setupCmd)
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"mcs-mcp/internal/mcp"
+
+	"github.com/rs/zerolog/log"
+	"github.com/spf13/cobra"
+)
+
+var syncInterval time.Duration
+
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Refresh the event cache of every known source from Jira",
+	Long: `Runs the import_history_update catch-up for every source with an event log in the
+cache directory, so that tool calls are served from a warm cache instead of waiting
+for Jira. Without --interval it makes one pass and exits (suitable for cron); with
+--interval it repeats until interrupted.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if syncInterval < 0 {
+			return fmt.Errorf("--interval must not be negative")
+		}
+		ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+		defer stop()
+
+		server := mcp.NewServer(cfg, jiraClient)
+		for {
+			results, err := server.SyncKnownSources(ctx)
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+			failed := 0
+			for _, r := range results {
+				if r.Err != nil {
+					failed++
+				}
+			}
+			log.Info().Int("sources", len(results)).Int("failed", failed).Msg("Sync pass complete")
+
+			if syncInterval == 0 {
+				if failed > 0 {
+					return fmt.Errorf("%d of %d sources failed to sync", failed, len(results))
+				}
+				return nil
+			}
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(syncInterval):
+			}
+		}
+	},
+}
+
+func init() {
+	syncCmd.Flags().DurationVar(&syncInterval, "interval", 0, "Repeat the sync at this interval (e.g. 1h) until interrupted; 0 syncs once")
+	rootCmd.AddCommand(syncCmd)
+}
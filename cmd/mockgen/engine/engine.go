@@ -19,6 +19,7 @@ type GeneratorConfig struct {
 	Distribution string // "uniform" or "weibull"
 	Count        int
 	Now          time.Time
+	ProjectKey   string // Prefix of the issue keys; "" = MCSTEST
 }
 
 type WorkflowMetadata struct {
@@ -31,10 +32,16 @@ type WorkflowMetadata struct {
 	NameRegistry    *jira.NameRegistry              `json:"name_registry,omitempty"`
 }
 
+// StatusOrder is the workflow order of the generated statuses.
+var StatusOrder = []string{"1", "2", "3", "4", "5"}
+
 func Generate(cfg GeneratorConfig) ([]eventlog.IssueEvent, map[string]stats.StatusMetadata) {
 	if cfg.Now.IsZero() {
 		cfg.Now = time.Now()
 	}
+	if cfg.ProjectKey == "" {
+		cfg.ProjectKey = "MCSTEST"
+	}
 
 	mapping := map[string]stats.StatusMetadata{
 		"1": {Name: "Open", Tier: "Demand", Role: "active"},
@@ -49,7 +56,7 @@ func Generate(cfg GeneratorConfig) ([]eventlog.IssueEvent, map[string]stats.Stat
 	var events []eventlog.IssueEvent
 
 	for i := 0; i < cfg.Count; i++ {
-		key := fmt.Sprintf("MCSTEST-%d", i+1)
+		key := fmt.Sprintf("%s-%d", cfg.ProjectKey, i+1)
 		k, lambda := 2.5, 9.5 // Mild: Targeted at ~5.0 day In-Progress residency
 		switch cfg.Scenario {
 		case "chaos":
@@ -207,7 +214,7 @@ func Save(outDir string, sourceID string, events []eventlog.IssueEvent, mapping
 		SourceID:        sourceID,
 		Mapping:         mapping,
 		Resolutions:     map[string]string{"1": "delivered", "2": "abandoned"},
-		StatusOrder:     []string{"1", "2", "3", "4", "5"},
+		StatusOrder:     StatusOrder,
 		CommitmentPoint: "3",
 		NameRegistry: &jira.NameRegistry{
 			Statuses: map[string]string{
@@ -217,11 +224,7 @@ func Save(outDir string, sourceID string, events []eventlog.IssueEvent, mapping
 				"4": "Done",
 				"5": "Closed",
 			},
-			Resolutions: map[string]string{
-				"1": "Fixed",
-				"2": "Won't Do",
-				"3": "Duplicate",
-			},
+			Resolutions: Resolutions,
 		},
 	}
 
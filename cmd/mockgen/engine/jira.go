@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+	"time"
+)
+
+// Resolutions are the Jira resolutions the generated events use.
+var Resolutions = map[string]string{
+	"1": "Fixed",
+	"2": "Won't Do",
+	"3": "Duplicate",
+}
+
+// Issues turns generated events back into the issues a Jira search returns:
+// the current status and resolution as fields, and each status change as a
+// changelog history, so that eventlog.TransformIssue recovers the events.
+// The issues keep the order in which they were generated.
+func Issues(events []eventlog.IssueEvent, mapping map[string]stats.StatusMetadata) []jira.IssueDTO {
+	byKey := make(map[string]*jira.IssueDTO)
+	var order []string
+	for _, e := range events {
+		dto, ok := byKey[e.IssueKey]
+		if !ok {
+			dto = &jira.IssueDTO{Key: e.IssueKey, Changelog: &jira.ChangelogDTO{}}
+			dto.Fields.IssueType.Name = e.IssueType
+			byKey[e.IssueKey] = dto
+			order = append(order, e.IssueKey)
+		}
+
+		at := jira.FormatTime(time.UnixMicro(e.Timestamp))
+		switch e.EventType {
+		case eventlog.Created:
+			dto.Fields.Created = at
+		case eventlog.Change:
+			items := []jira.ItemDTO{{Field: "status", From: e.FromStatusID, FromString: e.FromStatus, To: e.ToStatusID, ToString: e.ToStatus}}
+			if e.Resolution != "" {
+				items = append(items, jira.ItemDTO{Field: "resolution", To: e.ResolutionID, ToString: e.Resolution})
+				dto.Fields.Resolution.ID, dto.Fields.Resolution.Name = e.ResolutionID, e.Resolution
+				dto.Fields.ResolutionDate = at
+			}
+			dto.Changelog.Histories = append(dto.Changelog.Histories, jira.HistoryDTO{Created: at, Items: items})
+		}
+		if e.ToStatusID != "" {
+			dto.Fields.Status.ID, dto.Fields.Status.Name = e.ToStatusID, e.ToStatus
+			dto.Fields.Status.StatusCategory.Key = StatusCategory(mapping[e.ToStatusID])
+		}
+		dto.Fields.Updated = at
+	}
+
+	issues := make([]jira.IssueDTO, 0, len(order))
+	for _, key := range order {
+		dto := byKey[key]
+		n := len(dto.Changelog.Histories)
+		dto.Changelog.MaxResults, dto.Changelog.Total = n, n
+		issues = append(issues, *dto)
+	}
+	return issues
+}
+
+// StatusCategory returns the Jira status category key of a mapped status.
+func StatusCategory(m stats.StatusMetadata) string {
+	switch m.Tier {
+	case "Finished":
+		return "done"
+	case "Downstream":
+		return "indeterminate"
+	}
+	return "new"
+}
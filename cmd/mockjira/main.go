@@ -0,0 +1,61 @@
+// Command mockjira serves a synthetic dataset (generated like cmd/mockgen)
+// behind a fake Jira REST API, so the full MCS-MCP server, demos, and
+// integration tests can run against realistic data without a Jira instance.
+//
+// Point the server at it with JIRA_URL=http://localhost:8089 and any
+// JIRA_TOKEN; the fake accepts every request.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"mcs-mcp/cmd/mockgen/engine"
+)
+
+func main() {
+	addr := flag.String("addr", "localhost:8089", "Address to listen on")
+	scenario := flag.String("scenario", "mild", "Scenario to generate: mild, chaos, drift")
+	distribution := flag.String("distribution", "uniform", "Distribution to use: uniform, weibull")
+	count := flag.Int("count", 200, "Number of issues to generate")
+	project := flag.String("project", "MOCK", "Project key of the generated issues")
+	boardID := flag.Int("board", 1, "ID of the Kanban board")
+	flag.Parse()
+
+	key := strings.ToUpper(*project)
+	if key == "MCSTEST" {
+		fmt.Println("MCSTEST sources are served from the local cache without Jira; choose another project key.")
+		os.Exit(1)
+	}
+
+	cfg := engine.GeneratorConfig{
+		Scenario:     *scenario,
+		Distribution: *distribution,
+		Count:        *count,
+		Now:          time.Now(),
+		ProjectKey:   key,
+	}
+	events, mapping := engine.Generate(cfg)
+
+	d := &dataset{
+		ProjectKey:  key,
+		ProjectName: "Mock Project " + key,
+		BoardID:     *boardID,
+		BoardName:   key + " Kanban",
+		FilterID:    "10000",
+		Issues:      engine.Issues(events, mapping),
+		Mapping:     mapping,
+		StatusOrder: engine.StatusOrder,
+	}
+
+	fmt.Printf("Serving scenario '%s' (Distribution: %s, Count: %d) as project %s, board %d on http://%s\n",
+		cfg.Scenario, cfg.Distribution, cfg.Count, key, d.BoardID, *addr)
+	if err := http.ListenAndServe(*addr, newHandler(d)); err != nil {
+		fmt.Printf("Failed to serve: %v\n", err)
+		os.Exit(1)
+	}
+}
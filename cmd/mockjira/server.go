@@ -0,0 +1,291 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcs-mcp/cmd/mockgen/engine"
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+// dataset is what the fake Jira serves: one project with one Kanban board
+// whose filter selects every generated issue.
+type dataset struct {
+	ProjectKey  string
+	ProjectName string
+	BoardID     int
+	BoardName   string
+	FilterID    string
+	Issues      []jira.IssueDTO
+	Mapping     map[string]stats.StatusMetadata
+	StatusOrder []string
+}
+
+// The JQL clauses the fake evaluates: the key lookup of webhooks and the
+// updated bounds of delta syncs, catch-ups, and resumed backfills. Every other
+// clause (project, lookback windows, sub-task exclusion) matches all issues.
+var (
+	jqlKey     = regexp.MustCompile(`(?i)\bkey\s*=\s*"?([A-Z][A-Z0-9_]+-[0-9]+)"?`)
+	jqlUpdated = regexp.MustCompile(`(?i)\bupdated\s*(>=|>|<=|<)\s*"(\d{4}-\d{2}-\d{2} \d{2}:\d{2})"`)
+	jqlOrder   = regexp.MustCompile(`(?i)\border\s+by\s+updated\s+(asc|desc)\b`)
+)
+
+// jqlDateTime is the format of absolute dates in JQL.
+const jqlDateTime = "2006-01-02 15:04"
+
+// newHandler serves the Jira REST and Agile endpoints the MCS-MCP client
+// calls, for both the Data Center (v2) and Cloud (v3) API paths.
+func newHandler(d *dataset) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /rest/api/{v}/search", d.handleSearch)
+	mux.HandleFunc("GET /rest/api/{v}/search/jql", d.handleSearch)
+	mux.HandleFunc("GET /rest/api/{v}/issue/{key}", d.handleIssue)
+	mux.HandleFunc("GET /rest/api/{v}/issue/{key}/changelog", d.handleChangelog)
+	mux.HandleFunc("GET /rest/api/{v}/project/{key}", d.handleProject)
+	mux.HandleFunc("GET /rest/api/{v}/project/{key}/statuses", d.handleStatuses)
+	mux.HandleFunc("GET /rest/api/{v}/project/search", d.handleProjectSearch)
+	mux.HandleFunc("GET /rest/api/{v}/projects/picker", d.handleProjectPicker)
+	mux.HandleFunc("GET /rest/api/{v}/resolution", d.handleResolutions)
+	mux.HandleFunc("GET /rest/api/{v}/filter/{id}", d.handleFilter)
+	mux.HandleFunc("GET /rest/agile/1.0/board", d.handleBoards)
+	mux.HandleFunc("GET /rest/agile/1.0/board/{id}", d.handleBoard)
+	mux.HandleFunc("GET /rest/agile/1.0/board/{id}/configuration", d.handleBoardConfig)
+	mux.HandleFunc("GET /rest/agile/1.0/board/{id}/sprint", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "The board does not support sprints", http.StatusBadRequest)
+	})
+	return mux
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+func (d *dataset) handleSearch(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	issues, err := d.search(q.Get("jql"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	startAt, _ := strconv.Atoi(q.Get("startAt"))
+	maxResults, err := strconv.Atoi(q.Get("maxResults"))
+	if err != nil || maxResults <= 0 {
+		maxResults = 50
+	}
+
+	page := issues[min(startAt, len(issues)):min(startAt+maxResults, len(issues))]
+	writeJSON(w, jira.SearchResponse{Total: len(issues), Issues: page})
+}
+
+// search returns the issues a JQL query selects, in its order.
+func (d *dataset) search(jql string) ([]jira.IssueDTO, error) {
+	var bounds []func(time.Time) bool
+	for _, m := range jqlUpdated.FindAllStringSubmatch(jql, -1) {
+		at, err := time.ParseInLocation(jqlDateTime, m[2], time.Local)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date in JQL: %q", m[2])
+		}
+		switch m[1] {
+		case ">=":
+			bounds = append(bounds, func(t time.Time) bool { return !t.Before(at) })
+		case ">":
+			bounds = append(bounds, func(t time.Time) bool { return t.After(at) })
+		case "<=":
+			bounds = append(bounds, func(t time.Time) bool { return !t.After(at) })
+		case "<":
+			bounds = append(bounds, func(t time.Time) bool { return t.Before(at) })
+		}
+	}
+	key := ""
+	if m := jqlKey.FindStringSubmatch(jql); m != nil {
+		key = m[1]
+	}
+
+	var out []jira.IssueDTO
+	for _, dto := range d.Issues {
+		if key != "" && dto.Key != key {
+			continue
+		}
+		updated, _ := jira.ParseTime(dto.Fields.Updated)
+		match := true
+		for _, in := range bounds {
+			match = match && in(updated)
+		}
+		if match {
+			out = append(out, dto)
+		}
+	}
+
+	if m := jqlOrder.FindStringSubmatch(jql); m != nil {
+		desc := strings.EqualFold(m[1], "desc")
+		slices.SortStableFunc(out, func(a, b jira.IssueDTO) int {
+			ta, _ := jira.ParseTime(a.Fields.Updated)
+			tb, _ := jira.ParseTime(b.Fields.Updated)
+			c := ta.Compare(tb)
+			if desc {
+				return -c
+			}
+			return c
+		})
+	}
+	return out, nil
+}
+
+func (d *dataset) issue(key string) (jira.IssueDTO, bool) {
+	i := slices.IndexFunc(d.Issues, func(dto jira.IssueDTO) bool { return dto.Key == key })
+	if i < 0 {
+		return jira.IssueDTO{}, false
+	}
+	return d.Issues[i], true
+}
+
+func (d *dataset) handleIssue(w http.ResponseWriter, r *http.Request) {
+	dto, ok := d.issue(r.PathValue("key"))
+	if !ok {
+		http.Error(w, "Issue does not exist", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, dto)
+}
+
+func (d *dataset) handleChangelog(w http.ResponseWriter, r *http.Request) {
+	dto, ok := d.issue(r.PathValue("key"))
+	if !ok {
+		http.Error(w, "Issue does not exist", http.StatusNotFound)
+		return
+	}
+	histories := dto.Changelog.Histories
+	writeJSON(w, map[string]any{"startAt": 0, "maxResults": len(histories), "total": len(histories), "isLast": true, "values": histories})
+}
+
+func (d *dataset) project() map[string]any {
+	return map[string]any{"id": "10000", "key": d.ProjectKey, "name": d.ProjectName}
+}
+
+func (d *dataset) handleProject(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.PathValue("key"), d.ProjectKey) {
+		http.Error(w, "No project could be found", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, d.project())
+}
+
+func (d *dataset) handleStatuses(w http.ResponseWriter, r *http.Request) {
+	if !strings.EqualFold(r.PathValue("key"), d.ProjectKey) {
+		http.Error(w, "No project could be found", http.StatusNotFound)
+		return
+	}
+	statuses := make([]map[string]any, 0, len(d.StatusOrder))
+	for _, id := range d.StatusOrder {
+		m := d.Mapping[id]
+		statuses = append(statuses, map[string]any{"id": id, "name": m.Name, "statusCategory": map[string]string{"key": engine.StatusCategory(m)}})
+	}
+	writeJSON(w, []map[string]any{{"name": "Story", "statuses": statuses}})
+}
+
+// matchesProject reports whether a project search query selects the project.
+func (d *dataset) matchesProject(query string) bool {
+	query = strings.ToLower(query)
+	return strings.Contains(strings.ToLower(d.ProjectKey), query) || strings.Contains(strings.ToLower(d.ProjectName), query)
+}
+
+func (d *dataset) handleProjectSearch(w http.ResponseWriter, r *http.Request) {
+	values := []map[string]any{}
+	if d.matchesProject(r.URL.Query().Get("query")) {
+		values = append(values, d.project())
+	}
+	writeJSON(w, map[string]any{"values": values, "total": len(values), "isLast": true})
+}
+
+func (d *dataset) handleProjectPicker(w http.ResponseWriter, r *http.Request) {
+	projects := []map[string]any{}
+	if d.matchesProject(r.URL.Query().Get("query")) {
+		projects = append(projects, d.project())
+	}
+	writeJSON(w, map[string]any{"projects": projects, "total": len(projects)})
+}
+
+func (d *dataset) handleResolutions(w http.ResponseWriter, r *http.Request) {
+	ids := make([]string, 0, len(engine.Resolutions))
+	for id := range engine.Resolutions {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+	out := make([]jira.ResolutionDTO, 0, len(ids))
+	for _, id := range ids {
+		out = append(out, jira.ResolutionDTO{ID: id, Name: engine.Resolutions[id]})
+	}
+	writeJSON(w, out)
+}
+
+func (d *dataset) handleFilter(w http.ResponseWriter, r *http.Request) {
+	if r.PathValue("id") != d.FilterID {
+		http.Error(w, "The selected filter is not available", http.StatusNotFound)
+		return
+	}
+	writeJSON(w, map[string]any{
+		"id":   d.FilterID,
+		"name": "Filter for " + d.BoardName,
+		"jql":  fmt.Sprintf("project = %s ORDER BY Rank ASC", d.ProjectKey),
+	})
+}
+
+func (d *dataset) board() map[string]any {
+	return map[string]any{
+		"id":       d.BoardID,
+		"name":     d.BoardName,
+		"type":     "kanban",
+		"location": map[string]any{"projectKey": d.ProjectKey, "projectName": d.ProjectName},
+	}
+}
+
+// boardFound reports whether a request addresses the board, answering 404 if not.
+func (d *dataset) boardFound(w http.ResponseWriter, r *http.Request) bool {
+	if r.PathValue("id") != strconv.Itoa(d.BoardID) {
+		http.Error(w, "The requested board cannot be viewed because it either does not exist or you do not have permission to view it.", http.StatusNotFound)
+		return false
+	}
+	return true
+}
+
+func (d *dataset) handleBoards(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	values := []map[string]any{}
+	if p := q.Get("projectKeyOrId"); p == "" || strings.EqualFold(p, d.ProjectKey) {
+		if name := q.Get("name"); name == "" || strings.Contains(strings.ToLower(d.BoardName), strings.ToLower(name)) {
+			values = append(values, d.board())
+		}
+	}
+	writeJSON(w, map[string]any{"values": values, "total": len(values), "isLast": true})
+}
+
+func (d *dataset) handleBoard(w http.ResponseWriter, r *http.Request) {
+	if d.boardFound(w, r) {
+		writeJSON(w, d.board())
+	}
+}
+
+func (d *dataset) handleBoardConfig(w http.ResponseWriter, r *http.Request) {
+	if !d.boardFound(w, r) {
+		return
+	}
+	columns := make([]map[string]any, 0, len(d.StatusOrder))
+	for _, id := range d.StatusOrder {
+		columns = append(columns, map[string]any{"name": d.Mapping[id].Name, "statuses": []map[string]string{{"id": id}}})
+	}
+	writeJSON(w, map[string]any{
+		"id":           d.BoardID,
+		"name":         d.BoardName,
+		"type":         "kanban",
+		"filter":       map[string]any{"id": d.FilterID},
+		"columnConfig": map[string]any{"columns": columns},
+	})
+}
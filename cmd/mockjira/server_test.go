@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"mcs-mcp/cmd/mockgen/engine"
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+)
+
+func TestFakeJira_HydratesThroughClient(t *testing.T) {
+	now := time.Now()
+	events, mapping := engine.Generate(engine.GeneratorConfig{Scenario: "mild", Count: 60, Now: now, ProjectKey: "MOCK"})
+	d := &dataset{
+		ProjectKey: "MOCK", ProjectName: "Mock", BoardID: 7, BoardName: "MOCK Kanban", FilterID: "10000",
+		Issues: engine.Issues(events, mapping), Mapping: mapping, StatusOrder: engine.StatusOrder,
+	}
+	srv := httptest.NewServer(newHandler(d))
+	defer srv.Close()
+
+	ctx := context.Background()
+	client := jira.NewClient(jira.Config{BaseURL: srv.URL, Token: "any", RequestDelay: time.Nanosecond})
+
+	board, err := client.GetBoard(ctx, 7)
+	if err != nil || board.(map[string]any)["location"].(map[string]any)["projectKey"] != "MOCK" {
+		t.Fatalf("GetBoard: %v (err %v)", board, err)
+	}
+	cfg, err := client.GetBoardConfig(ctx, 7)
+	if err != nil {
+		t.Fatal(err)
+	}
+	filterID := cfg.(map[string]any)["filter"].(map[string]any)["id"].(string)
+	filter, err := client.GetFilter(ctx, filterID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	jql := filter.(map[string]any)["jql"].(string)
+
+	reg, err := client.GetRegistry(ctx, "MOCK")
+	if err != nil || reg.Statuses["3"] != "In Progress" || reg.Resolutions["2"] != "Won't Do" {
+		t.Fatalf("GetRegistry: %+v (err %v)", reg, err)
+	}
+	if _, err := client.GetBoard(ctx, 8); err == nil {
+		t.Error("Expected an unknown board to fail")
+	}
+
+	p := eventlog.NewLogProvider(client, eventlog.NewEventStore(nil), t.TempDir(), 24, 36, 5000, 0)
+	if _, err := p.Hydrate(ctx, "MOCK_7", "MOCK", jql, reg); err != nil {
+		t.Fatalf("Hydrate: %v", err)
+	}
+	if got := p.GetEventCount("MOCK_7"); got != len(events) {
+		t.Errorf("Expected the %d generated events to round-trip, got %d", len(events), got)
+	}
+	journey := p.GetEventsForIssue("MOCK_7", "MOCK-1")
+	if len(journey) == 0 || journey[0].EventType != eventlog.Created {
+		t.Errorf("Expected MOCK-1 to start with its Created event, got %+v", journey)
+	}
+
+	// Delta syncs only see issues updated since the watermark.
+	resp, err := client.SearchIssues(ctx, `(`+jql+`) AND updated >= "`+now.Add(time.Hour).Format(jqlDateTime)+`" ORDER BY updated ASC`, 0, 50)
+	if err != nil || resp.Total != 0 {
+		t.Errorf("Expected no issues updated in the future, got %v (err %v)", resp, err)
+	}
+	resp, err = client.SearchIssues(ctx, `key = "MOCK-2"`, 0, 1)
+	if err != nil || len(resp.Issues) != 1 || resp.Issues[0].Key != "MOCK-2" {
+		t.Errorf("Expected the key lookup to return MOCK-2, got %v (err %v)", resp, err)
+	}
+}
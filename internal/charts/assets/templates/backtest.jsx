@@ -45,6 +45,7 @@ const BACKTEST = RAW ? {
     hit:    c.is_within_cone,
     drift:  c.drift_detected,
   })),
+  calibration:     RAW.calibration || [],
 } : null;
 // Backtest is always scope mode (hardcoded in the handler).
 const isDuration = false;
@@ -91,6 +92,48 @@ function MissRow({ d, i, isDuration, unitLong }) {
   );
 }
 
+const VERDICT_COLORS = {
+  calibrated:        POSITIVE,
+  overconfident:     ALARM,
+  underconfident:    CAUTION,
+  insufficient_data: MUTED,
+};
+
+// Reliability table: how often each nominal percentile was honored.
+function CalibrationPanel({ levels }) {
+  if (!levels.length) return null;
+  const th = { padding: "6px 8px", textAlign: "left", color: MUTED, fontSize: 10, fontWeight: 700 };
+  return (
+    <div style={{ background: PANEL_BG, borderRadius: 12,
+      border: `1px solid ${BORDER}`, padding: "14px 12px", marginBottom: 16 }}>
+      <div style={{ fontSize: 11, color: MUTED, marginBottom: 10, letterSpacing: "0.05em",
+        textTransform: "uppercase" }}>Calibration</div>
+      <table style={{ width: "100%", borderCollapse: "collapse", fontSize: 11 }}>
+        <thead>
+          <tr style={{ borderBottom: `1px solid ${BORDER}` }}>
+            <th style={th}>PERCENTILE</th>
+            <th style={th}>NOMINAL</th>
+            <th style={th}>OBSERVED</th>
+            <th style={th}>HONORED</th>
+            <th style={th}>VERDICT</th>
+          </tr>
+        </thead>
+        <tbody>
+          {levels.map(l => (
+            <tr key={l.percentile}>
+              <td style={{ padding: "5px 8px", fontWeight: 700 }}>{l.percentile}</td>
+              <td style={{ padding: "5px 8px", color: MUTED }}>{Math.round(l.nominal * 100)}%</td>
+              <td style={{ padding: "5px 8px" }}>{Math.round(l.observed * 100)}%</td>
+              <td style={{ padding: "5px 8px", color: MUTED }}>{l.hits} / {l.checkpoints}</td>
+              <td style={{ padding: "5px 8px", color: VERDICT_COLORS[l.verdict] || MUTED }}>{l.verdict.replace("_", " ")}</td>
+            </tr>
+          ))}
+        </tbody>
+      </table>
+    </div>
+  );
+}
+
 // ── BACKTEST PANEL ────────────────────────────────────────────────────────────
 
 function BacktestPanel({ data, isDuration }) {
@@ -162,6 +205,8 @@ function BacktestPanel({ data, isDuration }) {
         )}
       </div>
 
+      <CalibrationPanel levels={data.calibration} />
+
       {/* Badges */}
       <div style={{ display: "flex", flexWrap: "wrap", gap: 6, marginBottom: 16, alignItems: "center" }}>
         <Badge text={`Accuracy: ${pct}%`} color={ac} />
@@ -315,6 +360,12 @@ export default function BacktestChart() {
             3-way control chart) is detected — drift-contaminated checkpoints are excluded from
             accuracy scoring.
           </div>
+          <div style={{ marginTop: 6 }}>
+            <b style={{ color: TEXT }}>Calibration: </b>
+            A P85 forecast should be honored at about 85% of checkpoints. Overconfident means
+            forecasts were honored less often than promised; underconfident means they were
+            needlessly conservative.
+          </div>
         </div>
 
       </div>
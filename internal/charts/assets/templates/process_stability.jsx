@@ -70,7 +70,7 @@ const formatDate = (d) =>
 
 function buildChartData(scatterplot, signals) {
   const outliers = new Set((signals || []).filter(s => s.type === "outlier").map(s => s.index));
-  const shifts   = new Set((signals || []).filter(s => s.type === "shift").map(s => s.index));
+  const shifts   = new Set((signals || []).filter(s => s.type !== "outlier").map(s => s.index));
   const keyMap   = Object.fromEntries((signals || []).map(s => [s.index, s.key]));
   return scatterplot.map((pt, i) => ({
     date:      pt.date,
@@ -295,7 +295,7 @@ export default function ProcessStabilityChart() {
   const lnpl = xmr?.lower_natural_process_limit ?? 0;
 
   const outlierCount = (xmr?.signals || []).filter(s => s.type === "outlier").length;
-  const shiftCount   = (xmr?.signals || []).filter(s => s.type === "shift").length;
+  const shiftCount   = (xmr?.signals || []).filter(s => s.type !== "outlier").length;
 
   const dateRange = RAW_SCATTERPLOT.length > 0
     ? `${formatDate(RAW_SCATTERPLOT[0].date)} – ${formatDate(RAW_SCATTERPLOT[RAW_SCATTERPLOT.length - 1].date)}`
@@ -312,7 +312,7 @@ export default function ProcessStabilityChart() {
       si:       str?.stability_index ?? 0,
       elt:      str?.expected_lead_time ?? 0,
       outliers: (str?.xmr?.signals || []).filter(s => s.type === "outlier").length,
-      shifts:   (str?.xmr?.signals || []).filter(s => s.type === "shift").length,
+      shifts:   (str?.xmr?.signals || []).filter(s => s.type !== "outlier").length,
     };
   });
 
@@ -388,15 +388,16 @@ export default function ProcessStabilityChart() {
             time of one delivered item, plotted by its completion date. Multiple items may share
             a date — this is a scatterplot, not a line chart. The dashed X̄ is the process mean.
             The UNPL defines the outer boundary of natural variation — points above it are outliers
-            caused by special circumstances, not normal process noise. Yellow dots mark the anchor
-            of an 8-point run on one side of the mean (Process Shift signal). The Stability Index
+            caused by special circumstances, not normal process noise. Yellow dots mark where a
+            cluster, run, or trend signal fired (Wheeler rules 2–4). The Stability Index
             (WIP ÷ Throughput ÷ Mean Cycle Time) summarises system health: below {CFG.siMarginal} =
             stable, {CFG.siMarginal}–{CFG.siClogged} = marginal, above {CFG.siClogged} = clogged.
           </div>
           <div>
             <b style={{ color: TEXT }}>Data provenance:</b> Wheeler XmR applied to
-            individual item cycle times. Signals: outlier = above UNPL; shift = 8+ consecutive
-            points on one side of X̄. Values near 0 indicate items committed and resolved the
+            individual item cycle times. Signals: outlier = beyond the limits; cluster = 3 of 4
+            points beyond one sigma; shift = 8+ consecutive points on one side of X̄; trend = 6
+            points steadily rising or falling. Values near 0 indicate items committed and resolved the
             same day.
           </div>
         </div>
@@ -7,12 +7,19 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"mcs-mcp/internal/chartbuf"
+	"mcs-mcp/internal/i18n"
 	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/notify"
 	"mcs-mcp/internal/paths"
+	"mcs-mcp/internal/render"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
 
 	"github.com/joho/godotenv"
 	"github.com/rs/zerolog/log"
@@ -20,7 +27,8 @@ import (
 
 // AppConfig holds the complete application configuration.
 type AppConfig struct {
-	Jira                jira.Config
+	Jira                    jira.Config
+	JiraInstances           map[string]jira.Config // JIRA_INSTANCES: named connections besides the default one, by lower-case name
 	DataPath                string
 	LogDir                  string
 	CacheDir                string
@@ -28,12 +36,41 @@ type AppConfig struct {
 	Engine                  string         // MCS_ENGINE: "crude" (default), "bbak", "auto"
 	EngineWeights           map[string]int // MCS_ENGINE_<NAME>: 0 = disabled, 1-100 = weight
 	ChartsBufferSize        int            // MCS_CHARTS_BUFFER_SIZE: 0 = disabled, 1-100 = enabled
+	OutputFormat            render.Format  // MCS_OUTPUT_FORMAT: "json" (default), "markdown", "csv"
+	MaxResponseBytes        int            // MCS_MAX_RESPONSE_BYTES: size budget of a tool result, above which its longest lists are cut; 0 = no budget
+	WebhookSecret           string         // MCS_WEBHOOK_SECRET: HMAC secret of Jira webhook deliveries; required by the receiver
+	Anonymize               bool           // MCS_ANONYMIZE: pseudonymize issue keys and drop Jira names in tool results
+	AnonymizeSalt           string         // MCS_ANONYMIZE_SALT: key of the pseudonyms; "" = random per server start
+	Locale                  i18n.Locale    // MCS_LOCALE: "en" (default), "de"; a locale announced by the client at initialize takes precedence
 
-	IngestionUpdatedLookback int // INGESTION_UPDATED_LOOKBACK (months) for initial hydration JQL
-	IngestionCreatedLookback int // INGESTION_CREATED_LOOKBACK (months) for initial hydration JQL
-	IngestionMaxItems        int // INGESTION_MAX_ITEMS — page-cap for initial hydration
+	ReadOnly     bool     // MCS_READ_ONLY: disable the tools that change a board's configuration
+	EnabledTools []string // MCS_ENABLED_TOOLS: comma-separated allowlist of tool names; empty = all
+
+	SubtaskPolicy stats.SubtaskPolicy // MCS_SUBTASK_POLICY: "exclude" (default), "include", "rollup"; anything but exclude ingests sub-tasks
+	OutlierPolicy stats.OutlierPolicy // MCS_OUTLIER_POLICY: "none" (default), "winsorize", "iqr"
+
+	ContainerPolicy     stats.ContainerPolicy // MCS_CONTAINER_POLICY: "exclude" (default), "include" — epics and other containers in flow analyses
+	PortfolioDuplicates stats.DuplicatePolicy // MCS_PORTFOLIO_DUPLICATES: "first" (default), "split", "all" — issues shared by several portfolio boards
+
+	IngestionUpdatedLookback int  // INGESTION_UPDATED_LOOKBACK (months) for initial hydration JQL
+	IngestionCreatedLookback int  // INGESTION_CREATED_LOOKBACK (months) for initial hydration JQL
+	IngestionMaxItems        int  // INGESTION_MAX_ITEMS — page-cap for initial hydration
+	IngestionSyncInterval    int  // INGESTION_SYNC_INTERVAL (minutes) — cache freshness before a Jira delta sync; 0 = every call
+	IngestionCacheTTL        int  // INGESTION_CACHE_TTL (minutes) — lifetime of the issues projected for a tool call, reused by later calls; 0 = off
+	IngestionStreaming       bool // INGESTION_STREAMING — keep event logs on disk between syncs and stream them into projections
+
+	Calendar *simulation.Calendar // MCS_WORKDAYS, MCS_HOLIDAYS, MCS_FREEZE_PERIODS; nil = not configured
+
+	DigestWebhookURL  string      // MCS_DIGEST_WEBHOOK_URL: Slack or Teams webhook of the digest command
+	DigestWebhookKind notify.Kind // MCS_DIGEST_WEBHOOK_KIND: "slack" (default), "teams"
+	DigestSources     []string    // MCS_DIGEST_SOURCES: comma-separated [instance/]PROJECT[:board] entries
+	DigestAnalyses    []string    // MCS_DIGEST_ANALYSES: comma-separated subset of alerts, aging, flow_debt, forecast_drift; empty = all
 }
 
+// DefaultMaxResponseBytes is the size budget of a tool result without
+// MCS_MAX_RESPONSE_BYTES: about 25,000 tokens, a fraction of an LLM context.
+const DefaultMaxResponseBytes = 100000
+
 // Load loads the configuration from .env files and environment variables.
 func Load() (*AppConfig, error) {
 	// 1. Try to load from the executable's directory (highest priority for MCP servers)
@@ -78,6 +115,49 @@ func Load() (*AppConfig, error) {
 		return nil, fmt.Errorf("MCS_CHARTS_BUFFER_SIZE=%d exceeds maximum %d", chartsBufferSize, chartbuf.MaxBufferSize)
 	}
 
+	outputFormat, err := render.ParseFormat(getEnv("MCS_OUTPUT_FORMAT", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MCS_OUTPUT_FORMAT: %w", err)
+	}
+
+	locale, err := i18n.ParseLocale(getEnv("MCS_LOCALE", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MCS_LOCALE: %w", err)
+	}
+
+	subtaskPolicy, err := stats.ParseSubtaskPolicy(getEnv("MCS_SUBTASK_POLICY", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MCS_SUBTASK_POLICY: %w", err)
+	}
+
+	outlierPolicy, err := stats.ParseOutlierPolicy(getEnv("MCS_OUTLIER_POLICY", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MCS_OUTLIER_POLICY: %w", err)
+	}
+
+	containerPolicy, err := stats.ParseContainerPolicy(getEnv("MCS_CONTAINER_POLICY", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MCS_CONTAINER_POLICY: %w", err)
+	}
+
+	portfolioDuplicates, err := stats.ParseDuplicatePolicy(getEnv("MCS_PORTFOLIO_DUPLICATES", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MCS_PORTFOLIO_DUPLICATES: %w", err)
+	}
+
+	digestKind, err := notify.ParseKind(getEnv("MCS_DIGEST_WEBHOOK_KIND", ""))
+	if err != nil {
+		return nil, fmt.Errorf("MCS_DIGEST_WEBHOOK_KIND: %w", err)
+	}
+
+	var calendar *simulation.Calendar
+	workdays, holidays, freezes := getEnv("MCS_WORKDAYS", ""), getEnv("MCS_HOLIDAYS", ""), getEnv("MCS_FREEZE_PERIODS", "")
+	if workdays != "" || holidays != "" || freezes != "" {
+		if calendar, err = simulation.ParseCalendar(workdays, holidays, freezes); err != nil {
+			return nil, fmt.Errorf("working calendar: %w", err)
+		}
+	}
+
 	cfg := &AppConfig{
 		Jira: jira.Config{
 			BaseURL:      getEnv("JIRA_URL", ""),
@@ -90,6 +170,22 @@ func Load() (*AppConfig, error) {
 			GCILB:        getEnv("JIRA_GCILB", ""),
 			GCLB:         getEnv("JIRA_GCLB", ""),
 			RequestDelay: time.Duration(delaySecs) * time.Second,
+
+			OAuth: jira.OAuthConfig{
+				ClientID:     getEnv("JIRA_OAUTH_CLIENT_ID", ""),
+				ClientSecret: getEnv("JIRA_OAUTH_CLIENT_SECRET", ""),
+				CallbackPort: getEnvInt("JIRA_OAUTH_CALLBACK_PORT", jira.DefaultOAuthCallbackPort),
+				TokenFile:    filepath.Join(dataPath, "jira_oauth_token.json"),
+			},
+
+			RequestsPerMinute: getEnvInt("JIRA_REQUESTS_PER_MINUTE", 60),
+			MaxRetries:        getEnvInt("JIRA_MAX_RETRIES", 5),
+			RetryBaseDelay:    time.Duration(getEnvInt("JIRA_RETRY_BASE_DELAY_SECONDS", 2)) * time.Second,
+			IncludeSubtasks:   subtaskPolicy != stats.SubtasksExclude,
+			EstimateField:     getEnv("JIRA_ESTIMATE_FIELD", ""),
+			TimeInStatusField: getEnv("JIRA_TIME_IN_STATUS_FIELD", ""),
+			TeamField:         getEnv("JIRA_TEAM_FIELD", ""),
+			FetchAssignee:     getEnvBool("JIRA_FETCH_ASSIGNEE", false),
 		},
 		DataPath:                dataPath,
 		LogDir:                  logDir,
@@ -101,15 +197,91 @@ func Load() (*AppConfig, error) {
 			"bbak":  getEnvInt("MCS_ENGINE_BBAK", 50),
 		},
 		ChartsBufferSize: chartsBufferSize,
+		OutputFormat:     outputFormat,
+		MaxResponseBytes: getEnvInt("MCS_MAX_RESPONSE_BYTES", DefaultMaxResponseBytes),
+		WebhookSecret:    getEnv("MCS_WEBHOOK_SECRET", ""),
+		Anonymize:        getEnvBool("MCS_ANONYMIZE", false),
+		AnonymizeSalt:    getEnv("MCS_ANONYMIZE_SALT", ""),
+		Locale:           locale,
+		SubtaskPolicy:    subtaskPolicy,
+		OutlierPolicy:    outlierPolicy,
+		ReadOnly:         getEnvBool("MCS_READ_ONLY", false),
+		EnabledTools:     getEnvList("MCS_ENABLED_TOOLS"),
+
+		ContainerPolicy:     containerPolicy,
+		PortfolioDuplicates: portfolioDuplicates,
 
 		IngestionUpdatedLookback: getEnvInt("INGESTION_UPDATED_LOOKBACK", 24),
 		IngestionCreatedLookback: getEnvInt("INGESTION_CREATED_LOOKBACK", 36),
 		IngestionMaxItems:        getEnvInt("INGESTION_MAX_ITEMS", 5000),
+		IngestionSyncInterval:    getEnvInt("INGESTION_SYNC_INTERVAL", 10),
+		IngestionCacheTTL:        getEnvInt("INGESTION_CACHE_TTL", 10),
+		IngestionStreaming:       getEnvBool("INGESTION_STREAMING", false),
+
+		Calendar: calendar,
+
+		DigestWebhookURL:  getEnv("MCS_DIGEST_WEBHOOK_URL", ""),
+		DigestWebhookKind: digestKind,
+		DigestSources:     getEnvList("MCS_DIGEST_SOURCES"),
+		DigestAnalyses:    getEnvList("MCS_DIGEST_ANALYSES"),
+	}
+
+	if cfg.JiraInstances, err = loadJiraInstances(getEnv("JIRA_INSTANCES", ""), cfg.Jira); err != nil {
+		return nil, err
 	}
 
 	return cfg, nil
 }
 
+// DefaultInstance is the name of the connection configured by the plain
+// JIRA_* variables.
+const DefaultInstance = "default"
+
+var instanceNamePattern = regexp.MustCompile(`^[a-z][a-z0-9]*$`)
+
+// loadJiraInstances reads the named Jira connections listed in JIRA_INSTANCES
+// (e.g. "cloud,dc"). Each reads its URL and credentials from JIRA_<NAME>_*
+// (e.g. JIRA_DC_URL, JIRA_DC_TOKEN) and inherits the request pacing and
+// retry settings of the default connection. An OAuth login is stored per
+// instance, next to the default one's token file.
+func loadJiraInstances(list string, base jira.Config) (map[string]jira.Config, error) {
+	instances := make(map[string]jira.Config)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		if !instanceNamePattern.MatchString(name) || name == DefaultInstance {
+			return nil, fmt.Errorf("JIRA_INSTANCES: invalid instance name %q (lower-case letters and digits, not %q)", name, DefaultInstance)
+		}
+		prefix := "JIRA_" + strings.ToUpper(name) + "_"
+		c := base
+		c.BaseURL = getEnv(prefix+"URL", "")
+		if c.BaseURL == "" {
+			return nil, fmt.Errorf("JIRA_INSTANCES: %sURL is not set for instance %q", prefix, name)
+		}
+		c.XsrfToken = getEnv(prefix+"XSRF_TOKEN", "")
+		c.SessionID = getEnv(prefix+"SESSION_ID", "")
+		c.RememberMe = getEnv(prefix+"REMEMBERME_COOKIE", "")
+		c.Token = getEnv(prefix+"TOKEN", "")
+		c.TokenType = getEnv(prefix+"TOKEN_TYPE", "pat")
+		c.UserEmail = getEnv(prefix+"USER_EMAIL", "")
+		c.GCILB = getEnv(prefix+"GCILB", "")
+		c.GCLB = getEnv(prefix+"GCLB", "")
+		c.EstimateField = getEnv(prefix+"ESTIMATE_FIELD", "")
+		c.TimeInStatusField = getEnv(prefix+"TIME_IN_STATUS_FIELD", "")
+		c.TeamField = getEnv(prefix+"TEAM_FIELD", "")
+		c.OAuth = jira.OAuthConfig{
+			ClientID:     getEnv(prefix+"OAUTH_CLIENT_ID", ""),
+			ClientSecret: getEnv(prefix+"OAUTH_CLIENT_SECRET", ""),
+			CallbackPort: getEnvInt(prefix+"OAUTH_CALLBACK_PORT", base.OAuth.CallbackPort),
+			TokenFile:    filepath.Join(filepath.Dir(base.OAuth.TokenFile), "jira_oauth_token_"+name+".json"),
+		}
+		instances[name] = c
+	}
+	return instances, nil
+}
+
 func getEnv(key, fallback string) string {
 	if value, ok := os.LookupEnv(key); ok {
 		return value
@@ -126,6 +298,18 @@ func getEnvInt(key string, fallback int) int {
 	return fallback
 }
 
+// getEnvList returns the comma-separated entries of an environment variable,
+// trimmed and lower-cased, without empty ones.
+func getEnvList(key string) []string {
+	var list []string
+	for _, v := range strings.Split(getEnv(key, ""), ",") {
+		if v = strings.ToLower(strings.TrimSpace(v)); v != "" {
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
 func getEnvBool(key string, fallback bool) bool {
 	if value, ok := os.LookupEnv(key); ok {
 		if boolVal, err := strconv.ParseBool(value); err == nil {
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestLoadJiraInstances(t *testing.T) {
+	base := jira.Config{BaseURL: "https://default.example.com", Token: "default-token", RequestDelay: 7 * time.Second, MaxRetries: 3}
+
+	t.Run("named instances inherit pacing but not credentials", func(t *testing.T) {
+		t.Setenv("JIRA_DC_URL", "https://dc.example.com")
+		t.Setenv("JIRA_DC_TOKEN", "dc-token")
+		t.Setenv("JIRA_CLOUD_URL", "https://cloud.atlassian.net")
+		t.Setenv("JIRA_CLOUD_TOKEN_TYPE", "api")
+		t.Setenv("JIRA_CLOUD_USER_EMAIL", "me@example.com")
+
+		got, err := loadJiraInstances(" Cloud, dc ,", base)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != 2 {
+			t.Fatalf("expected 2 instances, got %d", len(got))
+		}
+		dc := got["dc"]
+		if dc.BaseURL != "https://dc.example.com" || dc.Token != "dc-token" || dc.TokenType != "pat" {
+			t.Errorf("dc: unexpected connection %+v", dc)
+		}
+		if dc.RequestDelay != base.RequestDelay || dc.MaxRetries != base.MaxRetries {
+			t.Errorf("dc: pacing not inherited: %+v", dc)
+		}
+		cloud := got["cloud"]
+		if cloud.Token != "" || cloud.TokenType != "api" || cloud.UserEmail != "me@example.com" {
+			t.Errorf("cloud: unexpected credentials %+v", cloud)
+		}
+	})
+
+	t.Run("empty list", func(t *testing.T) {
+		got, err := loadJiraInstances("", base)
+		if err != nil || len(got) != 0 {
+			t.Errorf("expected no instances, got %v (err %v)", got, err)
+		}
+	})
+
+	for _, list := range []string{"default", "dc-2", "9dc"} {
+		t.Run("invalid name "+list, func(t *testing.T) {
+			if _, err := loadJiraInstances(list, base); err == nil {
+				t.Errorf("expected an error for %q", list)
+			}
+		})
+	}
+
+	t.Run("missing URL", func(t *testing.T) {
+		if _, err := loadJiraInstances("nourl", base); err == nil {
+			t.Error("expected an error for an instance without JIRA_NOURL_URL")
+		}
+	})
+}
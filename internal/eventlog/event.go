@@ -4,7 +4,11 @@
 // snapshots via ReconstructIssue. Carries no analytical semantics.
 package eventlog
 
-import "fmt"
+import (
+	"fmt"
+
+	"mcs-mcp/internal/jira"
+)
 
 // EventType defines the objective nature of a Jira state change.
 type EventType string
@@ -47,6 +51,42 @@ type IssueEvent struct {
 	// Flagged represents the "Blocked" state (e.g., "Impediment", "Blocked", or empty).
 	Flagged string `json:"flagged,omitempty"`
 
+	// Delivery stream attributes (optional, carried on the Created event only).
+	// They reflect the issue snapshot at fetch time, not a historical value.
+	Components []string `json:"components,omitempty"`
+	Labels     []string `json:"labels,omitempty"`
+	ParentKey  string   `json:"parentKey,omitempty"`
+	Subtask    bool     `json:"subtask,omitempty"`
+
+	// HierarchyLevel is the level of the issue type in the Jira hierarchy:
+	// 1 for epics, 2+ above (snapshot at fetch time, Created event only).
+	HierarchyLevel int `json:"hierarchyLevel,omitempty"`
+
+	// FixVersions carries release markers (snapshot at fetch time, Created event only).
+	FixVersions []jira.FixVersion `json:"fixVersions,omitempty"`
+
+	// Estimate is the value of the estimation field, e.g. story points
+	// (snapshot at fetch time, Created event only).
+	Estimate *float64 `json:"estimate,omitempty"`
+
+	// Assignee (display name) and Team (value of the team field) attribute the
+	// issue to a person or team (snapshot at fetch time, Created event only).
+	Assignee string `json:"assignee,omitempty"`
+	Team     string `json:"team,omitempty"`
+
+	// TimeInStatus is the value of the time-in-status field in seconds per
+	// status ID (snapshot at fetch time, Created event only).
+	TimeInStatus map[string]int64 `json:"timeInStatus,omitempty"`
+
+	// FormerKeys are the keys the issue had before it was moved between
+	// projects, oldest first, from the Key changes of its changelog (Created
+	// event only). They are the source of the IdentityMap.
+	FormerKeys []string `json:"formerKeys,omitempty"`
+
+	// HistoryTruncated marks an issue whose changelog Jira returned only in part
+	// (Created event only); its earliest transitions are missing.
+	HistoryTruncated bool `json:"historyTruncated,omitempty"`
+
 	// IsHealed indicates if the event was synthetically created/modified during history healing.
 	IsHealed bool `json:"isHealed,omitempty"`
 
@@ -1,6 +1,7 @@
 package eventlog
 
 import (
+	"context"
 	"testing"
 	"time"
 
@@ -11,20 +12,31 @@ type MockJiraClient struct {
 	SearchIssuesFunc func(jql string, startAt, maxResults int) (*jira.SearchResponse, error)
 }
 
-func (m *MockJiraClient) FindProjects(query string) ([]any, error) { return nil, nil }
-func (m *MockJiraClient) FindBoards(projectKey, nameFilter string) ([]any, error) {
+func (m *MockJiraClient) FindProjects(_ context.Context, query string) ([]any, error) {
 	return nil, nil
 }
-func (m *MockJiraClient) GetBoard(id int) (any, error)                           { return nil, nil }
-func (m *MockJiraClient) GetIssueWithHistory(key string) (*jira.IssueDTO, error) { return nil, nil }
-func (m *MockJiraClient) GetProject(key string) (any, error)                     { return nil, nil }
-func (m *MockJiraClient) GetProjectStatuses(key string) (any, error)             { return nil, nil }
-func (m *MockJiraClient) GetBoardConfig(id int) (any, error)                     { return nil, nil }
-func (m *MockJiraClient) GetFilter(id string) (any, error)                       { return nil, nil }
-func (m *MockJiraClient) SearchIssues(jql string, startAt int, maxResults int) (*jira.SearchResponse, error) {
+func (m *MockJiraClient) FindBoards(_ context.Context, projectKey, nameFilter string) ([]any, error) {
+	return nil, nil
+}
+func (m *MockJiraClient) GetBoard(_ context.Context, id int) (any, error) { return nil, nil }
+func (m *MockJiraClient) GetIssueWithHistory(_ context.Context, key string) (*jira.IssueDTO, error) {
+	return nil, nil
+}
+func (m *MockJiraClient) GetProject(_ context.Context, key string) (any, error) { return nil, nil }
+func (m *MockJiraClient) GetProjectStatuses(_ context.Context, key string) (any, error) {
+	return nil, nil
+}
+func (m *MockJiraClient) GetBoardConfig(_ context.Context, id int) (any, error) { return nil, nil }
+func (m *MockJiraClient) GetFilter(_ context.Context, id string) (any, error)   { return nil, nil }
+func (m *MockJiraClient) SearchIssues(_ context.Context, jql string, startAt int, maxResults int) (*jira.SearchResponse, error) {
 	return m.SearchIssuesFunc(jql, startAt, maxResults)
 }
-func (m *MockJiraClient) GetRegistry(projectKey string) (*jira.NameRegistry, error) { return nil, nil }
+func (m *MockJiraClient) GetRegistry(_ context.Context, projectKey string) (*jira.NameRegistry, error) {
+	return nil, nil
+}
+func (m *MockJiraClient) GetSprints(_ context.Context, boardID int, limit int) ([]jira.Sprint, error) {
+	return nil, nil
+}
 
 func TestLogProvider_MergeStrategy(t *testing.T) {
 	now := time.Now().Truncate(time.Minute)
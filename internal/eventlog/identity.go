@@ -0,0 +1,31 @@
+package eventlog
+
+// IdentityMap maps the former keys of moved issues to their current key, so
+// lookups and links by an old key reach the issue under its current one.
+type IdentityMap map[string]string
+
+// BuildIdentityMap collects the former keys recorded on the Created events.
+func BuildIdentityMap(events []IssueEvent) IdentityMap {
+	ids := make(IdentityMap)
+	for _, e := range events {
+		if e.EventType == Created {
+			ids.add(e.IssueKey, e.FormerKeys)
+		}
+	}
+	return ids
+}
+
+func (m IdentityMap) add(current string, former []string) {
+	for _, key := range former {
+		m[key] = current
+	}
+}
+
+// Resolve returns the current key of key: key itself unless it is a former
+// key of a moved issue.
+func (m IdentityMap) Resolve(key string) string {
+	if current, ok := m[key]; ok {
+		return current
+	}
+	return key
+}
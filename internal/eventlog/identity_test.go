@@ -0,0 +1,51 @@
+package eventlog
+
+import (
+	"slices"
+	"testing"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestIdentityMap_MovedIssue(t *testing.T) {
+	dto := jira.IssueDTO{
+		Key:    "NEW-7",
+		Fields: jira.FieldsDTO{Created: "2024-01-01T10:00:00.000+0000"},
+		Changelog: &jira.ChangelogDTO{
+			Histories: []jira.HistoryDTO{
+				{Created: "2024-03-01T12:00:00.000+0000", Items: []jira.ItemDTO{{Field: "Key", FromString: "MID-5", ToString: "NEW-7"}}},
+				{Created: "2024-02-01T12:00:00.000+0000", Items: []jira.ItemDTO{{Field: "Key", FromString: "OLD-3", ToString: "MID-5"}}},
+				{Created: "2024-03-02T12:00:00.000+0000", Items: []jira.ItemDTO{{Field: "status", FromString: "To Do", ToString: "Doing"}}},
+			},
+		},
+	}
+	events := TransformIssue(dto, nil)
+	i := slices.IndexFunc(events, func(e IssueEvent) bool { return e.EventType == Created })
+	if i < 0 || !slices.Equal(events[i].FormerKeys, []string{"OLD-3", "MID-5"}) {
+		t.Fatalf("Expected former keys OLD-3 and MID-5 on the Created event, got %v", events)
+	}
+
+	store := NewEventStore(nil)
+	store.Append("src", []IssueEvent{
+		{IssueKey: "OLD-3", EventType: Created, ToStatus: "Open", Timestamp: 1},
+		{IssueKey: "OLD-3", EventType: Change, FromStatus: "Open", ToStatus: "Doing", Timestamp: 2},
+		{IssueKey: "OTHER-1", EventType: Created, ToStatus: "Open", Timestamp: 3},
+	})
+	store.Merge("src", events)
+
+	if got := store.GetEventsForIssue("src", "OLD-3"); len(got) != len(events) || got[0].IssueKey != "NEW-7" {
+		t.Errorf("Expected the former key to find NEW-7 and not its stale events, got %v", got)
+	}
+	if got := store.Count("src"); got != len(events)+1 {
+		t.Errorf("Expected the stale events under OLD-3 to be replaced, got %d events", got)
+	}
+	if got := store.IdentityMap("src").Resolve("MID-5"); got != "NEW-7" {
+		t.Errorf("Expected MID-5 to resolve to NEW-7, got %s", got)
+	}
+	if source, got := store.FindIssueInAllSources("MID-5"); source != "src" || len(got) == 0 {
+		t.Errorf("Expected MID-5 to be found in src, got %q with %d events", source, len(got))
+	}
+	if got := store.IdentityMap("src").Resolve("OTHER-1"); got != "OTHER-1" {
+		t.Errorf("Expected an unmoved key to resolve to itself, got %s", got)
+	}
+}
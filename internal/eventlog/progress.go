@@ -0,0 +1,49 @@
+package eventlog
+
+import "context"
+
+// Ingestion phases reported through a ProgressFunc.
+const (
+	PhaseCache     = "cache"      // Event log served from the on-disk cache
+	PhaseHydration = "hydration"  // Initial Jira hydration
+	PhaseDeltaSync = "delta_sync" // Incremental sync since the NMRC
+	PhaseCatchUp   = "catch_up"   // Forward catch-up (import_history_update)
+)
+
+// Progress describes how far an ingestion phase of a source has come.
+type Progress struct {
+	SourceID string
+	Phase    string
+	Fetched  int  // Issues fetched from Jira so far in this phase
+	Total    int  // Expected issues of the phase; 0 when unknown
+	Done     bool // The phase has finished
+}
+
+// ProgressFunc receives ingestion progress, with the context of the call that
+// started the ingestion. It is called synchronously from the ingestion loop and
+// must not block.
+type ProgressFunc func(context.Context, Progress)
+
+// SetProgressFunc registers the receiver of ingestion progress (nil = none).
+func (p *LogProvider) SetProgressFunc(fn ProgressFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.progress = fn
+}
+
+func (p *LogProvider) reportProgress(ctx context.Context, pr Progress) {
+	p.mu.Lock()
+	fn := p.progress
+	p.mu.Unlock()
+	if fn != nil {
+		fn(ctx, pr)
+	}
+}
+
+// expectedTotal caps the total reported by Jira at the page-cap of the phase.
+func expectedTotal(reported, limit int) int {
+	if limit > 0 && reported > limit {
+		return limit
+	}
+	return reported
+}
@@ -0,0 +1,96 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestLogProvider_HydrateReportsProgress(t *testing.T) {
+	created := time.Now().Add(-48 * time.Hour).Format("2006-01-02T15:04:05.000-0700")
+	client := &MockJiraClient{SearchIssuesFunc: func(jql string, startAt, maxResults int) (*jira.SearchResponse, error) {
+		resp := &jira.SearchResponse{Total: 2}
+		if startAt == 0 {
+			for _, key := range []string{"PROJ-1", "PROJ-2"} {
+				dto := jira.IssueDTO{Key: key}
+				dto.Fields.Created = created
+				dto.Fields.Updated = created
+				resp.Issues = append(resp.Issues, dto)
+			}
+		}
+		return resp, nil
+	}}
+	p := NewLogProvider(client, NewEventStore(nil), t.TempDir(), 24, 36, 5000, 10*time.Minute)
+
+	var got []Progress
+	p.SetProgressFunc(func(_ context.Context, pr Progress) { got = append(got, pr) })
+
+	if _, err := p.Hydrate(context.Background(), "PROJ_1", "PROJ", "project = PROJ", nil); err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	want := []Progress{
+		{SourceID: "PROJ_1", Phase: PhaseHydration},
+		{SourceID: "PROJ_1", Phase: PhaseHydration, Fetched: 2, Total: 2},
+		{SourceID: "PROJ_1", Phase: PhaseHydration, Fetched: 2, Total: 2, Done: true},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Expected %d progress reports, got %+v", len(want), got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("Report %d: expected %+v, got %+v", i, want[i], got[i])
+		}
+	}
+
+	// A second call within the sync interval is served from the cache
+	got = nil
+	if _, err := p.Hydrate(context.Background(), "PROJ_1", "PROJ", "project = PROJ", nil); err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	if len(got) != 1 || got[0].Phase != PhaseCache || !got[0].Done {
+		t.Errorf("Expected a single cache report, got %+v", got)
+	}
+}
+
+func TestExpectedTotal(t *testing.T) {
+	if got := expectedTotal(12000, 5000); got != 5000 {
+		t.Errorf("Expected the page-cap to bound the total, got %d", got)
+	}
+	if got := expectedTotal(300, 0); got != 300 {
+		t.Errorf("Expected an uncapped total, got %d", got)
+	}
+}
+
+func TestLogProvider_HydrateCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	created := time.Now().Add(-48 * time.Hour).Format("2006-01-02T15:04:05.000-0700")
+	client := &MockJiraClient{SearchIssuesFunc: func(jql string, startAt, maxResults int) (*jira.SearchResponse, error) {
+		// A full page, then the caller gives up
+		resp := &jira.SearchResponse{Total: 1000}
+		for i := range maxResults {
+			dto := jira.IssueDTO{Key: fmt.Sprintf("PROJ-%d", startAt+i)}
+			dto.Fields.Created = created
+			dto.Fields.Updated = created
+			resp.Issues = append(resp.Issues, dto)
+		}
+		cancel()
+		return resp, nil
+	}}
+	store := NewEventStore(nil)
+	p := NewLogProvider(client, store, t.TempDir(), 24, 36, 5000, 10*time.Minute)
+
+	_, err := p.Hydrate(ctx, "PROJ_1", "PROJ", "project = PROJ", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected the hydration to be cancelled, got %v", err)
+	}
+	if store.Count("PROJ_1") != 0 {
+		t.Errorf("Expected an aborted initial hydration to leave no events, got %d", store.Count("PROJ_1"))
+	}
+	if st := p.SyncState("PROJ_1"); !st.LastSync.IsZero() {
+		t.Errorf("Expected no watermark after an aborted hydration, got %+v", st)
+	}
+}
@@ -1,10 +1,15 @@
 package eventlog
 
 import (
+	"cmp"
+	"context"
 	"fmt"
 	"mcs-mcp/internal/jira"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -18,9 +23,19 @@ type LogProvider struct {
 	updatedLookbackM int
 	createdLookbackM int
 	maxItems         int
+	syncInterval     time.Duration // minimum age of the last sync before Hydrate asks Jira again; 0 = always
+
+	mu        sync.Mutex
+	loadedMod map[string]time.Time // modification time of the cache file last read into memory, per source
+	progress  ProgressFunc
+	liveSince time.Time                   // start of the webhook receiver; zero = no webhooks
+	live      map[string]liveSource       // sources synced by this process, for webhooks
+	streaming bool                        // keep event logs on disk between syncs (SetStreaming)
+	diskStat  map[string]cacheStat        // summary of each streamed cache file
+	filters   map[string]jira.IssueFilter // in-memory narrowing per source (SetFilter)
 }
 
-func NewLogProvider(client jira.Client, store *EventStore, cacheDir string, updatedLookbackM, createdLookbackM, maxItems int) *LogProvider {
+func NewLogProvider(client jira.Client, store *EventStore, cacheDir string, updatedLookbackM, createdLookbackM, maxItems int, syncInterval time.Duration) *LogProvider {
 	return &LogProvider{
 		client:           client,
 		store:            store,
@@ -28,16 +43,115 @@ func NewLogProvider(client jira.Client, store *EventStore, cacheDir string, upda
 		updatedLookbackM: updatedLookbackM,
 		createdLookbackM: createdLookbackM,
 		maxItems:         maxItems,
+		syncInterval:     syncInterval,
+		loadedMod:        make(map[string]time.Time),
+		live:             make(map[string]liveSource),
+		diskStat:         make(map[string]cacheStat),
+		filters:          make(map[string]jira.IssueFilter),
 	}
 }
 
+// ensureLoaded reads a source's event log from the on-disk cache unless the
+// in-memory copy already reflects the current cache file.
+func (p *LogProvider) ensureLoaded(sourceID string) {
+	if p.cacheDir == "" {
+		return
+	}
+	info, err := os.Stat(filepath.Join(p.cacheDir, fmt.Sprintf("%s.jsonl", sourceID)))
+	if err != nil {
+		return // No cache yet
+	}
+
+	p.mu.Lock()
+	loaded, ok := p.loadedMod[sourceID]
+	p.mu.Unlock()
+	if ok && loaded.Equal(info.ModTime()) && p.store.Count(sourceID) > 0 {
+		return
+	}
+
+	if err := p.store.Load(p.cacheDir, sourceID); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to load events from cache")
+		return
+	}
+	p.mu.Lock()
+	p.loadedMod[sourceID] = info.ModTime()
+	p.mu.Unlock()
+}
+
+func (p *LogProvider) isStreaming() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.streaming
+}
+
+// checkpoint saves a source's event log and the progress of its initial
+// hydration, so that a process dying mid-backfill resumes from there.
+func (p *LogProvider) checkpoint(sourceID string, cp Backfill) {
+	if p.cacheDir == "" {
+		return
+	}
+	if err := p.store.Save(p.cacheDir, sourceID); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to save backfill checkpoint")
+		return
+	}
+	if info, err := os.Stat(filepath.Join(p.cacheDir, fmt.Sprintf("%s.jsonl", sourceID))); err == nil {
+		p.mu.Lock()
+		p.loadedMod[sourceID] = info.ModTime()
+		p.mu.Unlock()
+	}
+	st := p.SyncState(sourceID)
+	st.OMRC, st.NMRC = p.store.GetMostRecentUpdates(sourceID)
+	st.Backfill = &cp
+	if err := SaveSyncState(p.cacheDir, sourceID, st); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to save backfill checkpoint")
+	}
+}
+
+// persist saves a source's watermark, and its event log if changed. lastSync
+// is recorded as the time of the last Jira sync; a pending backfill checkpoint
+// is cleared.
+func (p *LogProvider) persist(sourceID string, changed bool, lastSync time.Time) {
+	if p.cacheDir == "" {
+		return
+	}
+	if !changed {
+		// Nothing to rewrite
+	} else if err := p.store.Save(p.cacheDir, sourceID); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to save cache")
+	} else if info, err := os.Stat(filepath.Join(p.cacheDir, fmt.Sprintf("%s.jsonl", sourceID))); err == nil {
+		p.mu.Lock()
+		p.loadedMod[sourceID] = info.ModTime()
+		p.mu.Unlock()
+	}
+
+	st := p.SyncState(sourceID)
+	st.OMRC, st.NMRC = p.store.GetMostRecentUpdates(sourceID)
+	st.LastSync = lastSync
+	st.Backfill = nil
+	if err := SaveSyncState(p.cacheDir, sourceID, st); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to save sync state")
+	}
+}
+
+// SyncState returns the persisted watermark of a source (zero if none).
+func (p *LogProvider) SyncState(sourceID string) SyncState {
+	if p.cacheDir == "" {
+		return SyncState{}
+	}
+	st, err := LoadSyncState(p.cacheDir, sourceID)
+	if err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Ignoring unreadable sync state")
+	}
+	return st
+}
+
 // getRegistryHelper fetches the name registry for a given project.
-func (p *LogProvider) getRegistryHelper(projectKey string) *jira.NameRegistry {
+func (p *LogProvider) getRegistryHelper(ctx context.Context, projectKey string) *jira.NameRegistry {
 	if p.client == nil || projectKey == "" {
 		return nil
 	}
 
-	reg, err := p.client.GetRegistry(projectKey)
+	reg, err := p.client.GetRegistry(ctx, projectKey)
 	if err != nil {
 		log.Warn().Err(err).Str("project", projectKey).Msg("Failed to fetch name registry, proceeding without stable labels")
 		return nil
@@ -46,68 +160,114 @@ func (p *LogProvider) getRegistryHelper(projectKey string) *jira.NameRegistry {
 }
 
 // Hydrate ensures the event log is populated with sufficient history for
-// analysis. Initial hydration uses a single generous JQL bounded by the
+// analysis. The on-disk cache is read only when it changed since it was last
+// loaded, and Jira is asked only when the last sync is older than the sync
+// interval. Initial hydration uses a single generous JQL bounded by the
 // configured updated/created lookback windows and capped at maxItems.
 // Incremental sync (when a cache exists) fetches everything updated since
-// the latest cached timestamp.
-func (p *LogProvider) Hydrate(sourceID string, projectKey string, jql string, reg *jira.NameRegistry) (*jira.NameRegistry, error) {
+// the NMRC watermark and replaces the histories of the changed issues.
+// Cancelling ctx aborts the sync between pages. Initial hydration checkpoints
+// the cache after each batch; when it was interrupted, the next call resumes
+// below the checkpoint and then delta-syncs the changes made meanwhile.
+// Without a cache directory, an aborted initial hydration leaves nothing behind.
+func (p *LogProvider) Hydrate(ctx context.Context, sourceID string, projectKey string, jql string, reg *jira.NameRegistry) (*jira.NameRegistry, error) {
 	const BatchSize = 300
 
-	// 1. Try to Load from Cache
-	if p.cacheDir != "" {
-		if err := p.store.Load(p.cacheDir, sourceID); err == nil {
-			log.Debug().Str("source", sourceID).Msg("Hydrate: Loaded from cache")
-		}
+	// 1. Load from Cache (no-op when memory is current). In streaming mode
+	// the log is only loaded once a sync has to merge into it.
+	if !p.isStreaming() {
+		p.ensureLoaded(sourceID)
 	}
 
-	// 1.5. Intercept MCSTEST: Never query Jira for this source.
+	// 1.5. Intercept MCSTEST: Never query Jira for any of its boards.
 	// We rely purely on what was just loaded from cache.
-	if sourceID == "MCSTEST_0" || filepath.Base(sourceID) == "MCSTEST" {
+	if strings.HasPrefix(sourceID, "MCSTEST_") || filepath.Base(sourceID) == "MCSTEST" {
 		log.Info().Str("source", sourceID).Msg("Hydrate: MCSTEST detected, skipping Jira sync")
+		p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseCache, Done: true})
 		return reg, nil
 	}
 
-	latest := p.store.GetLatestTimestamp(sourceID)
+	// 1.6. Serve from cache while the last sync is fresh, or webhooks keep it current
+	state := p.SyncState(sourceID)
+	_, live := p.isLive(sourceID, state)
+	if state.Backfill == nil && p.GetEventCount(sourceID) > 0 && !state.LastSync.IsZero() && (live || time.Since(state.LastSync) < p.syncInterval) {
+		log.Debug().Str("source", sourceID).Time("last_sync", state.LastSync).Msg("Hydrate: cache is fresh, skipping Jira sync")
+		p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseCache, Done: true})
+		if reg == nil {
+			reg = p.getRegistryHelper(ctx, projectKey)
+		}
+		return reg, nil
+	}
+
+	p.ensureLoaded(sourceID)
+	defer p.release(sourceID)
+	_, latest := p.store.GetMostRecentUpdates(sourceID)
 
-	// 2. Validate Cache Recency (2-month rule)
-	if !latest.IsZero() && time.Since(latest) > (60*24*time.Hour) {
-		log.Info().Str("source", sourceID).Time("latest", latest).Msg("Cache is older than 2 months, evicting and performing full re-ingestion")
+	// 2. Validate Cache Recency (2-month rule, measured from the last sync when known)
+	lastSeen := state.LastSync
+	if lastSeen.IsZero() {
+		lastSeen = latest
+	}
+	if !latest.IsZero() && time.Since(lastSeen) > (60*24*time.Hour) {
+		log.Info().Str("source", sourceID).Time("last_seen", lastSeen).Msg("Cache is older than 2 months, evicting and performing full re-ingestion")
 		p.store.Clear(sourceID)
 		if p.cacheDir != "" {
 			_ = DeleteCache(p.cacheDir, sourceID)
+			_ = DeleteSyncState(p.cacheDir, sourceID)
 			workflowPath := filepath.Join(p.cacheDir, fmt.Sprintf("%s-workflow.json", sourceID))
 			_ = os.Remove(workflowPath)
 		}
 		latest = time.Time{} // Treat as fresh
+		state = SyncState{}
+	}
+	syncStarted := time.Now()
+
+	registry := reg
+	if registry == nil {
+		registry = p.getRegistryHelper(ctx, projectKey)
 	}
 
 	// 3. Identification: Is this an Incremental Sync or Initial Hydration?
 	isIncremental := !latest.IsZero()
-
-	log.Info().Str("source", sourceID).Bool("incremental", isIncremental).Msg("Starting hydration process")
-
-	var hydrateJQL string
-	if isIncremental {
-		// Incremental Sync: process changes in chronological order
-		tsStr := latest.Format(DateTimeFormat)
-		hydrateJQL = fmt.Sprintf(`(%s) AND updated >= "%s" ORDER BY updated ASC`, jql, tsStr)
-	} else {
-		// Initial Hydration: wide OR predicate captures both recently-updated
-		// items AND long-lived items born in the window. Newest first so the
-		// max-items cap evicts the oldest tail rather than the active head.
-		hydrateJQL = fmt.Sprintf(
-			`(%s) AND (updated >= startOfDay("-%dM") OR created >= startOfDay("-%dM")) ORDER BY updated DESC`,
-			jql, p.updatedLookbackM, p.createdLookbackM)
+	if !isIncremental {
+		log.Info().Str("source", sourceID).Bool("incremental", false).Msg("Starting hydration process")
+		fetched, err := p.backfill(ctx, sourceID, jql, registry, Backfill{Started: syncStarted})
+		if err != nil {
+			if p.cacheDir == "" {
+				p.store.Clear(sourceID)
+			}
+			return registry, err
+		}
+		p.persist(sourceID, fetched > 0, syncStarted)
+		p.track(sourceID, jql, registry)
+		p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseHydration, Fetched: fetched, Total: fetched, Done: true})
+		log.Info().Int("total", fetched).Bool("incremental", false).Msg("Hydration complete")
+		return registry, nil
 	}
 
-	registry := reg
-	if registry == nil {
-		registry = p.getRegistryHelper(projectKey)
+	// 3.5. Gap detection: finish an interrupted initial hydration first. The
+	// delta sync below then fills the changes made since it was interrupted.
+	if state.Backfill != nil {
+		log.Info().Str("source", sourceID).Time("boundary", state.Backfill.Boundary).Int("fetched", state.Backfill.Fetched).Msg("Resuming interrupted hydration")
+		fetched, err := p.backfill(ctx, sourceID, jql, registry, *state.Backfill)
+		if err != nil {
+			return registry, err
+		}
+		p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseHydration, Fetched: fetched, Total: fetched, Done: true})
 	}
 
+	log.Info().Str("source", sourceID).Bool("incremental", true).Msg("Starting hydration process")
+	p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseDeltaSync})
+
+	// Incremental Sync: process changes in chronological order
+	hydrateJQL := jira.AndJQL(jql, jira.DateClause("updated", ">=", latest)) + " ORDER BY updated ASC"
+
 	totalFetched := 0
 	for {
-		resp, err := p.client.SearchIssues(hydrateJQL, totalFetched, BatchSize)
+		resp, err := p.client.SearchIssues(ctx, hydrateJQL, totalFetched, BatchSize)
+		if err == nil {
+			err = ctx.Err()
+		}
 		if err != nil {
 			return registry, fmt.Errorf("hydration failed at offset %d: %w", totalFetched, err)
 		}
@@ -121,40 +281,194 @@ func (p *LogProvider) Hydrate(sourceID string, projectKey string, jql string, re
 			batchEvents = append(batchEvents, TransformIssue(dto, registry)...)
 		}
 
-		p.store.Append(sourceID, batchEvents)
+		p.store.Merge(sourceID, batchEvents)
 		totalFetched += len(resp.Issues)
+		p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseDeltaSync, Fetched: totalFetched, Total: resp.Total})
+
+		if len(resp.Issues) < BatchSize {
+			break
+		}
+	}
+
+	// 4. Save to Cache and advance the watermark
+	p.persist(sourceID, totalFetched > 0 || state.Backfill != nil, syncStarted)
+	p.track(sourceID, jql, registry)
+	p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseDeltaSync, Fetched: totalFetched, Total: totalFetched, Done: true})
+
+	log.Info().Int("total", totalFetched).Bool("incremental", true).Msg("Hydration complete")
+	return registry, nil
+}
+
+// backfill runs an initial hydration, or resumes one from its checkpoint,
+// and returns the issues fetched in total. A wide OR predicate captures both
+// recently-updated items AND long-lived items born in the lookback window.
+// Issues come newest first, so the max-items cap evicts the oldest tail
+// rather than the active head, and each full batch is checkpointed with the
+// oldest 'updated' timestamp fetched. A resumed run fetches only issues
+// updated at or before that boundary; those fetched twice replace their
+// cached history.
+func (p *LogProvider) backfill(ctx context.Context, sourceID, jql string, registry *jira.NameRegistry, cp Backfill) (int, error) {
+	const BatchSize = 300
 
-		if isIncremental {
-			if len(resp.Issues) < BatchSize {
-				break
+	resuming := !cp.Boundary.IsZero()
+	clauses := []string{fmt.Sprintf(`(updated >= startOfDay("-%dM") OR created >= startOfDay("-%dM"))`, p.updatedLookbackM, p.createdLookbackM)}
+	if resuming {
+		// JQL dates have minute precision: round up so the boundary minute is included.
+		clauses = append(clauses, jira.DateClause("updated", "<=", cp.Boundary.Truncate(time.Minute).Add(time.Minute)))
+	}
+	hydrateJQL := jira.AndJQL(jql, clauses...) + " ORDER BY updated DESC"
+
+	p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseHydration, Fetched: cp.Fetched})
+	resumedAt, offset := cp.Fetched, 0
+	for cp.Fetched < p.maxItems {
+		resp, err := p.client.SearchIssues(ctx, hydrateJQL, offset, BatchSize)
+		if err == nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			return cp.Fetched, fmt.Errorf("hydration failed at offset %d: %w", cp.Fetched, err)
+		}
+
+		if len(resp.Issues) == 0 {
+			break
+		}
+
+		var batchEvents []IssueEvent
+		for _, dto := range resp.Issues {
+			batchEvents = append(batchEvents, TransformIssue(dto, registry)...)
+			if t, err := jira.ParseTime(dto.Fields.Updated); err == nil && (cp.Boundary.IsZero() || t.Before(cp.Boundary)) {
+				cp.Boundary = t
 			}
+		}
+
+		if resuming {
+			p.store.Merge(sourceID, batchEvents)
 		} else {
-			if totalFetched >= p.maxItems {
-				log.Info().Int("total", totalFetched).Int("cap", p.maxItems).Msg("Initial hydration reached INGESTION_MAX_ITEMS cap")
-				break
-			}
-			if len(resp.Issues) < BatchSize {
-				break
-			}
+			p.store.Append(sourceID, batchEvents)
 		}
-	}
+		offset += len(resp.Issues)
+		cp.Fetched += len(resp.Issues)
+		p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseHydration, Fetched: cp.Fetched, Total: expectedTotal(resumedAt+resp.Total, p.maxItems)})
 
-	// 4. Save to Cache
-	if p.cacheDir != "" {
-		if err := p.store.Save(p.cacheDir, sourceID); err != nil {
-			log.Warn().Err(err).Str("source", sourceID).Msg("Hydrate: Failed to save cache")
+		if len(resp.Issues) < BatchSize {
+			return cp.Fetched, nil
 		}
+		if cp.Fetched < p.maxItems {
+			p.checkpoint(sourceID, cp)
+		}
+	}
+	capped := cp.Fetched >= p.maxItems
+	if capped {
+		log.Info().Int("total", cp.Fetched).Int("cap", p.maxItems).Msg("Initial hydration reached INGESTION_MAX_ITEMS cap")
 	}
+	p.markCapped(sourceID, capped)
+	return cp.Fetched, nil
+}
 
-	log.Info().Int("total", totalFetched).Bool("incremental", isIncremental).Msg("Hydration complete")
-	return registry, nil
+// markCapped records whether the initial hydration of a source stopped at the
+// max-items cap.
+func (p *LogProvider) markCapped(sourceID string, capped bool) {
+	if p.cacheDir == "" {
+		return
+	}
+	st := p.SyncState(sourceID)
+	if st.Capped == capped {
+		return
+	}
+	st.Capped = capped
+	if err := SaveSyncState(p.cacheDir, sourceID, st); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to save sync state")
+	}
+}
+
+// SetFilter narrows the issues GetIssuesInRange and IssuesInRange return for a
+// source to those passing f, judged by the labels and components on their
+// Created event. Their ingestion JQL already carries the same clauses; this
+// keeps events cached before the narrowing, or of sources whose query Jira did
+// not apply, out of the analysis. A zero filter removes the narrowing.
+func (p *LogProvider) SetFilter(sourceID string, f jira.IssueFilter) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if f.IsZero() {
+		delete(p.filters, sourceID)
+		return
+	}
+	p.filters[sourceID] = f
+}
+
+func (p *LogProvider) filter(sourceID string) (jira.IssueFilter, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	f, ok := p.filters[sourceID]
+	return f, ok
+}
+
+// matchesFilter reports whether the snapshot of an issue history passes f.
+func matchesFilter(history []IssueEvent, f jira.IssueFilter) bool {
+	for _, e := range history {
+		if e.EventType == Created {
+			return f.Matches(e.Labels, e.Components)
+		}
+	}
+	return f.Matches(nil, nil)
 }
 
 func (p *LogProvider) GetIssuesInRange(sourceID string, start, end time.Time) []IssueEvent {
+	if f, ok := p.filter(sourceID); ok && !p.onDisk(sourceID) {
+		var events []IssueEvent
+		for history := range GroupByIssue(p.store.GetIssuesInRange(sourceID, start, end)) {
+			if matchesFilter(history, f) {
+				events = append(events, history...)
+			}
+		}
+		slices.SortStableFunc(events, func(a, b IssueEvent) int {
+			return cmp.Compare(a.Timestamp, b.Timestamp)
+		})
+		return events
+	}
+	if p.onDisk(sourceID) {
+		var events []IssueEvent
+		for history := range p.IssuesInRange(sourceID, start, end) {
+			events = append(events, history...)
+		}
+		slices.SortStableFunc(events, func(a, b IssueEvent) int {
+			return cmp.Compare(a.Timestamp, b.Timestamp)
+		})
+		return events
+	}
 	return p.store.GetIssuesInRange(sourceID, start, end)
 }
 
+// GetEventsForIssue returns the events of an issue, by its current key or a
+// former one.
 func (p *LogProvider) GetEventsForIssue(sourceID, issueKey string) []IssueEvent {
+	if p.onDisk(sourceID) {
+		ids := make(IdentityMap)
+		err := readCache(p.cachePath(sourceID), func(e IssueEvent) bool {
+			if e.EventType == Created {
+				ids.add(e.IssueKey, e.FormerKeys)
+			}
+			return true
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("source", sourceID).Msg("Failed to read events from cache")
+			return nil
+		}
+		issueKey = ids.Resolve(issueKey)
+
+		var events []IssueEvent
+		clockLimit := p.store.clock().UnixMicro()
+		err = readCache(p.cachePath(sourceID), func(e IssueEvent) bool {
+			if e.IssueKey == issueKey && e.Timestamp <= clockLimit {
+				events = append(events, e)
+			}
+			return true
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("source", sourceID).Msg("Failed to read events from cache")
+		}
+		return events
+	}
 	return p.store.GetEventsForIssue(sourceID, issueKey)
 }
 
@@ -163,10 +477,18 @@ func (p *LogProvider) GetEventsForIssueInAllSources(issueKey string) (string, []
 }
 
 func (p *LogProvider) GetLatestTimestamp(sourceID string) time.Time {
+	if p.onDisk(sourceID) {
+		_, latest := p.diskStats(sourceID)
+		return latest
+	}
 	return p.store.GetLatestTimestamp(sourceID)
 }
 
 func (p *LogProvider) GetEventCount(sourceID string) int {
+	if p.onDisk(sourceID) {
+		events, _ := p.diskStats(sourceID)
+		return events
+	}
 	return p.store.Count(sourceID)
 }
 
@@ -174,8 +496,12 @@ func (p *LogProvider) PruneExcept(keepSourceID string) {
 	p.store.PruneExcept(keepSourceID)
 }
 
-// CatchUp fetches new items since the last sync (NMRC).
-func (p *LogProvider) CatchUp(sourceID string, projectKey string, jql string, reg *jira.NameRegistry) (int, time.Time, *jira.NameRegistry, error) {
+// CatchUp fetches new items since the last sync (NMRC), regardless of the sync interval.
+// An interrupted initial hydration is resumed first.
+// Cancelling ctx aborts it between pages; the pages merged so far are kept.
+func (p *LogProvider) CatchUp(ctx context.Context, sourceID string, projectKey string, jql string, reg *jira.NameRegistry) (int, time.Time, *jira.NameRegistry, error) {
+	p.ensureLoaded(sourceID)
+	defer p.release(sourceID)
 	_, nmrc := p.store.GetMostRecentUpdates(sourceID)
 	if nmrc.IsZero() {
 		return 0, time.Time{}, nil, fmt.Errorf("cannot catch up: no existing cache for %s", sourceID)
@@ -184,18 +510,30 @@ func (p *LogProvider) CatchUp(sourceID string, projectKey string, jql string, re
 	const BatchSize = 300
 	totalFetched := 0
 
-	tsStr := nmrc.Format(DateTimeFormat)
-	catchUpJQL := fmt.Sprintf(`(%s) AND updated > "%s" ORDER BY updated ASC`, jql, tsStr)
+	catchUpJQL := jira.AndJQL(jql, jira.DateClause("updated", ">", nmrc)) + " ORDER BY updated ASC"
 
 	registry := reg
 	if registry == nil {
-		registry = p.getRegistryHelper(projectKey)
+		registry = p.getRegistryHelper(ctx, projectKey)
+	}
+
+	syncStarted := time.Now()
+	st := p.SyncState(sourceID)
+	if st.Backfill != nil {
+		log.Info().Str("source", sourceID).Time("boundary", st.Backfill.Boundary).Int("fetched", st.Backfill.Fetched).Msg("Resuming interrupted hydration")
+		if _, err := p.backfill(ctx, sourceID, jql, registry, *st.Backfill); err != nil {
+			return 0, nmrc, registry, err
+		}
 	}
 
 	log.Info().Str("source", sourceID).Time("nmrc", nmrc).Msg("Starting catch-up process")
+	p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseCatchUp})
 
 	for {
-		resp, err := p.client.SearchIssues(catchUpJQL, totalFetched, BatchSize)
+		resp, err := p.client.SearchIssues(ctx, catchUpJQL, totalFetched, BatchSize)
+		if err == nil {
+			err = ctx.Err()
+		}
 		if err != nil {
 			return totalFetched, nmrc, registry, fmt.Errorf("catch-up failed at offset %d: %w", totalFetched, err)
 		}
@@ -211,15 +549,16 @@ func (p *LogProvider) CatchUp(sourceID string, projectKey string, jql string, re
 
 		p.store.Merge(sourceID, batchEvents)
 		totalFetched += len(resp.Issues)
+		p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseCatchUp, Fetched: totalFetched, Total: resp.Total})
 
 		if len(resp.Issues) < BatchSize {
 			break
 		}
 	}
 
-	if totalFetched > 0 && p.cacheDir != "" {
-		_ = p.store.Save(p.cacheDir, sourceID)
-	}
+	p.persist(sourceID, totalFetched > 0 || st.Backfill != nil, syncStarted)
+	p.track(sourceID, jql, registry)
+	p.reportProgress(ctx, Progress{SourceID: sourceID, Phase: PhaseCatchUp, Fetched: totalFetched, Total: totalFetched, Done: true})
 
 	log.Info().Int("fetched", totalFetched).Msg("Catch-up complete")
 	return totalFetched, nmrc, registry, nil
@@ -33,6 +33,18 @@ func ReconstructIssue(events []IssueEvent, referenceDate time.Time) jira.Issue {
 				issue.HasSyntheticBirth = false // We have a real birth event!
 				issue.BirthStatus = e.ToStatus
 				issue.BirthStatusID = e.ToStatusID
+				issue.Components = e.Components
+				issue.Labels = e.Labels
+				issue.ParentKey = e.ParentKey
+				issue.IsSubtask = e.Subtask
+				issue.HierarchyLevel = e.HierarchyLevel
+				issue.FixVersions = e.FixVersions
+				issue.Estimate = e.Estimate
+				issue.FieldResidency = e.TimeInStatus
+				issue.Assignee = e.Assignee
+				issue.Team = e.Team
+				issue.HistoryTruncated = e.HistoryTruncated
+				issue.FormerKeys = e.FormerKeys
 			} else {
 				issue.Transitions = append(issue.Transitions, jira.StatusTransition{
 					FromStatus:   e.FromStatus,
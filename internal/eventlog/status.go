@@ -0,0 +1,155 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Gap kinds reported by Status.
+const (
+	GapBackfill  = "backfill"  // Initial hydration interrupted; older issues not fetched yet
+	GapTruncated = "truncated" // Initial hydration stopped at INGESTION_MAX_ITEMS
+	GapHead      = "head"      // Changes since the last sync not fetched yet (none while webhooks keep it live)
+	GapWatermark = "watermark" // Persisted watermark disagrees with the cached events
+)
+
+// Gap is a part of a source's history that the cache does not cover.
+type Gap struct {
+	Kind   string    `json:"kind"`
+	From   time.Time `json:"from"`
+	To     time.Time `json:"to"`
+	Detail string    `json:"detail"`
+}
+
+// SourceStatus describes the cached history of one source: its boundaries,
+// volume, sync watermark, and the gaps a sync would still have to fill.
+type SourceStatus struct {
+	SourceID   string    `json:"source_id"`
+	Events     int       `json:"events"`
+	Issues     int       `json:"issues"`
+	FirstEvent time.Time `json:"first_event"`
+	LastEvent  time.Time `json:"last_event"`
+	OMRC       time.Time `json:"omrc"` // Of the cached events
+	NMRC       time.Time `json:"nmrc"` // Of the cached events
+	LastSync   time.Time `json:"last_sync"`
+	Backfill   *Backfill `json:"backfill,omitempty"`
+	Complete   bool      `json:"complete"` // Initial hydration finished without hitting the cap
+	Gaps       []Gap     `json:"gaps"`
+}
+
+// Status reads the on-disk cache and watermark of a source without loading it
+// into the store, and detects the gaps in its history.
+func (p *LogProvider) Status(sourceID string) (SourceStatus, error) {
+	st := SourceStatus{SourceID: sourceID, Gaps: make([]Gap, 0)}
+	if p.cacheDir == "" {
+		return st, fmt.Errorf("no cache directory configured")
+	}
+	if err := scanCache(filepath.Join(p.cacheDir, fmt.Sprintf("%s.jsonl", sourceID)), &st); err != nil {
+		return st, err
+	}
+	state, err := LoadSyncState(p.cacheDir, sourceID)
+	if err != nil {
+		return st, err
+	}
+	st.LastSync = state.LastSync
+	st.Backfill = state.Backfill
+	st.Complete = st.Events > 0 && state.Backfill == nil && !state.Capped
+
+	if state.Backfill != nil {
+		st.Gaps = append(st.Gaps, Gap{
+			Kind: GapBackfill,
+			To:   state.Backfill.Boundary,
+			Detail: fmt.Sprintf("The initial hydration stopped after %d issues; issues last updated before %s are not cached yet. The next sync resumes from there.",
+				state.Backfill.Fetched, state.Backfill.Boundary.Format(DateTimeFormat)),
+		})
+	}
+	if state.Capped {
+		st.Gaps = append(st.Gaps, Gap{
+			Kind:   GapTruncated,
+			To:     st.OMRC,
+			Detail: fmt.Sprintf("The initial hydration reached INGESTION_MAX_ITEMS (%d); issues last updated before %s were never fetched.", p.maxItems, st.OMRC.Format(DateTimeFormat)),
+		})
+	}
+	if st.Events > 0 {
+		since := state.LastSync
+		if since.IsZero() {
+			since = st.NMRC
+		}
+		if _, live := p.isLive(sourceID, state); !live && time.Since(since) > p.syncInterval {
+			st.Gaps = append(st.Gaps, Gap{
+				Kind:   GapHead,
+				From:   since,
+				To:     time.Now(),
+				Detail: fmt.Sprintf("Changes made after %s are not cached yet; 'import_history_update' or the next tool call fetches them.", since.Format(DateTimeFormat)),
+			})
+		}
+	}
+	if !state.NMRC.IsZero() && !state.NMRC.Equal(st.NMRC) {
+		st.Gaps = append(st.Gaps, Gap{
+			Kind: GapWatermark,
+			Detail: fmt.Sprintf("The persisted NMRC (%s) differs from the cached events (%s), e.g. after a crash between writes. Syncs start from the cached events, so this heals on the next sync.",
+				state.NMRC.Format(DateTimeFormat), st.NMRC.Format(DateTimeFormat)),
+		})
+	}
+	return st, nil
+}
+
+// scanCache counts the events and issues of a JSONL cache file and derives
+// its time boundaries. A missing file leaves st empty.
+func scanCache(path string, st *SourceStatus) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open cache: %w", err)
+	}
+	defer file.Close()
+
+	issueMax := make(map[string]int64)
+	var first, last int64
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e struct {
+			IssueKey  string `json:"issueKey"`
+			Timestamp int64  `json:"ts"`
+		}
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue // Load skips invalid lines too
+		}
+		st.Events++
+		if first == 0 || e.Timestamp < first {
+			first = e.Timestamp
+		}
+		if e.Timestamp > last {
+			last = e.Timestamp
+		}
+		if e.Timestamp > issueMax[e.IssueKey] {
+			issueMax[e.IssueKey] = e.Timestamp
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading cache: %w", err)
+	}
+	if st.Events == 0 {
+		return nil
+	}
+
+	st.Issues = len(issueMax)
+	st.FirstEvent, st.LastEvent = time.UnixMicro(first), time.UnixMicro(last)
+	var omrc, nmrc int64
+	for _, ts := range issueMax {
+		if omrc == 0 || ts < omrc {
+			omrc = ts
+		}
+		if ts > nmrc {
+			nmrc = ts
+		}
+	}
+	st.OMRC, st.NMRC = time.UnixMicro(omrc), time.UnixMicro(nmrc)
+	return nil
+}
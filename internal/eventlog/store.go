@@ -77,6 +77,7 @@ func (s *EventStore) Append(sourceID string, events []IssueEvent) {
 
 // Merge replaces existing events for the issues contained in 'events' with the new history.
 // This is more fail-safe than simple deduplication as it handles deletions or corrections in Jira.
+// Events of a moved issue under its former keys are replaced as well.
 func (s *EventStore) Merge(sourceID string, events []IssueEvent) {
 	if len(events) == 0 {
 		return
@@ -89,6 +90,9 @@ func (s *EventStore) Merge(sourceID string, events []IssueEvent) {
 	affectedIssues := make(map[string]bool)
 	for _, e := range events {
 		affectedIssues[e.IssueKey] = true
+		for _, key := range e.FormerKeys {
+			affectedIssues[key] = true
+		}
 	}
 
 	// 2. Purge old events for these issues
@@ -288,9 +292,6 @@ func (s *EventStore) GetIssuesInRange(sourceID string, start, end time.Time) []I
 		return nil
 	}
 
-	startTs := start.UnixMicro()
-	endTs := end.UnixMicro()
-
 	// 1. Identify issues that were alive during the window
 	// Alive = (First Event <= end) AND (Last Event >= start)
 	relevantKeys := make(map[string]bool)
@@ -307,33 +308,32 @@ func (s *EventStore) GetIssuesInRange(sourceID string, start, end time.Time) []I
 	}
 
 	for key, min := range issueMinTs {
-		max := issueMaxTs[key]
 		// Condition: First event is before window end AND Last event is after window start
-		if min <= endTs && (start.IsZero() || max >= startTs) {
+		if aliveInRange(min, issueMaxTs[key], start, end) {
 			relevantKeys[key] = true
 		}
 	}
 
 	// 2. Collect all events for relevant keys up to 'end' (respecting clock)
-	clockLimit := s.clock().UnixMicro()
+	bound := upperBound(end, s.clock().UnixMicro())
 	var result []IssueEvent
 	for _, e := range logData {
-		if relevantKeys[e.IssueKey] {
-			// Respect both the requested window end and the hard clock limit
-			upperBound := clockLimit
-			if !end.IsZero() && endTs < upperBound {
-				upperBound = endTs
-			}
-
-			if e.Timestamp <= upperBound {
-				result = append(result, e)
-			}
+		if relevantKeys[e.IssueKey] && e.Timestamp <= bound {
+			result = append(result, e)
 		}
 	}
 	return result
 }
 
-// GetEventsForIssue returns the full event history for a single issue.
+// IdentityMap returns the former keys of the moved issues of a source.
+func (s *EventStore) IdentityMap(sourceID string) IdentityMap {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return BuildIdentityMap(s.logs[sourceID])
+}
+
+// GetEventsForIssue returns the full event history for a single issue, by its
+// current key or a former one.
 func (s *EventStore) GetEventsForIssue(sourceID string, issueKey string) []IssueEvent {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -342,6 +342,7 @@ func (s *EventStore) GetEventsForIssue(sourceID string, issueKey string) []Issue
 	if !ok {
 		return nil
 	}
+	issueKey = BuildIdentityMap(logData).Resolve(issueKey)
 
 	var result []IssueEvent
 	clockLimit := s.clock().UnixMicro()
@@ -356,16 +357,18 @@ func (s *EventStore) GetEventsForIssue(sourceID string, issueKey string) []Issue
 	return result
 }
 
-// FindIssueInAllSources searches for an issue across all loaded sources.
+// FindIssueInAllSources searches for an issue, by its current key or a
+// former one, across all loaded sources.
 func (s *EventStore) FindIssueInAllSources(issueKey string) (string, []IssueEvent) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
 	clockLimit := s.clock().UnixMicro()
 	for sourceID, logData := range s.logs {
+		key := BuildIdentityMap(logData).Resolve(issueKey)
 		var result []IssueEvent
 		for _, e := range logData {
-			if e.IssueKey == issueKey {
+			if e.IssueKey == key {
 				if e.Timestamp > clockLimit {
 					continue
 				}
@@ -0,0 +1,237 @@
+package eventlog
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"iter"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// GroupByIssue yields the events of each issue as one chronological history,
+// in the order the issues first appear in events.
+func GroupByIssue(events []IssueEvent) iter.Seq[[]IssueEvent] {
+	return func(yield func([]IssueEvent) bool) {
+		var order []string
+		grouped := make(map[string][]IssueEvent)
+		for _, e := range events {
+			if _, ok := grouped[e.IssueKey]; !ok {
+				order = append(order, e.IssueKey)
+			}
+			grouped[e.IssueKey] = append(grouped[e.IssueKey], e)
+		}
+		for _, key := range order {
+			if !yield(grouped[key]) {
+				return
+			}
+		}
+	}
+}
+
+// aliveInRange reports whether an issue whose events span [first, last] was
+// alive between start and end: it has an event at or before end, and its last
+// event is at or after start.
+func aliveInRange(first, last int64, start, end time.Time) bool {
+	return first <= end.UnixMicro() && (start.IsZero() || last >= start.UnixMicro())
+}
+
+// upperBound returns the latest timestamp an event may carry to be read for a
+// window ending at end: the end itself, capped by the clock.
+func upperBound(end time.Time, clockLimit int64) int64 {
+	if !end.IsZero() && end.UnixMicro() < clockLimit {
+		return end.UnixMicro()
+	}
+	return clockLimit
+}
+
+// streamCache yields the histories of the issues alive between start and end
+// from a JSONL cache file, with the semantics of EventStore.GetIssuesInRange,
+// without reading the whole log into memory. A first pass records the
+// boundaries and event count of each issue; the second pass buffers only the
+// issues whose last event has not been read yet, and yields each history as
+// soon as it is complete. A missing file yields nothing; read errors are
+// logged and end the stream.
+func streamCache(path string, start, end time.Time, clockLimit int64) iter.Seq[[]IssueEvent] {
+	return func(yield func([]IssueEvent) bool) {
+		type span struct {
+			first, last int64
+			events      int // events at or before the upper bound
+		}
+		bound := upperBound(end, clockLimit)
+		spans := make(map[string]*span)
+		err := readCache(path, func(e IssueEvent) bool {
+			sp, ok := spans[e.IssueKey]
+			if !ok {
+				sp = &span{first: e.Timestamp, last: e.Timestamp}
+				spans[e.IssueKey] = sp
+			}
+			sp.first, sp.last = min(sp.first, e.Timestamp), max(sp.last, e.Timestamp)
+			if e.Timestamp <= bound {
+				sp.events++
+			}
+			return true
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("Failed to stream events from cache")
+			return
+		}
+		for key, sp := range spans {
+			if sp.events == 0 || !aliveInRange(sp.first, sp.last, start, end) {
+				delete(spans, key)
+			}
+		}
+
+		open := make(map[string][]IssueEvent)
+		err = readCache(path, func(e IssueEvent) bool {
+			sp, ok := spans[e.IssueKey]
+			if !ok || e.Timestamp > bound {
+				return true
+			}
+			history := append(open[e.IssueKey], e)
+			if len(history) < sp.events {
+				open[e.IssueKey] = history
+				return true
+			}
+			delete(open, e.IssueKey)
+			return yield(history)
+		})
+		if err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("Failed to stream events from cache")
+		}
+	}
+}
+
+// readCache calls fn with each event of a JSONL cache file in file order
+// until fn returns false. Invalid lines are skipped, as Load does; a missing
+// file reads as empty.
+func readCache(path string, fn func(IssueEvent) bool) error {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to open cache: %w", err)
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var e IssueEvent
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			continue
+		}
+		if !fn(e) {
+			return nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("error reading cache: %w", err)
+	}
+	return nil
+}
+
+// SetStreaming switches the provider to streaming mode (INGESTION_STREAMING):
+// a source's event log stays on disk and is only held in memory while Jira
+// changes are merged into it. Reads stream the cache file instead, so memory
+// no longer grows with the size of the cached boards. It has no effect
+// without a cache directory.
+func (p *LogProvider) SetStreaming(on bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.streaming = on
+}
+
+func (p *LogProvider) cachePath(sourceID string) string {
+	return filepath.Join(p.cacheDir, fmt.Sprintf("%s.jsonl", sourceID))
+}
+
+// onDisk reports whether reads of a source are served from its cache file
+// rather than from memory.
+func (p *LogProvider) onDisk(sourceID string) bool {
+	if !p.isStreaming() || p.cacheDir == "" || p.store.Count(sourceID) > 0 {
+		return false
+	}
+	_, err := os.Stat(p.cachePath(sourceID))
+	return err == nil
+}
+
+// release drops a source's in-memory event log in streaming mode, once it is
+// saved to the cache file.
+func (p *LogProvider) release(sourceID string) {
+	if !p.isStreaming() || p.cacheDir == "" {
+		return
+	}
+	p.mu.Lock()
+	delete(p.loadedMod, sourceID)
+	p.mu.Unlock()
+	p.store.Clear(sourceID)
+}
+
+// diskStats returns the event count and latest event of a source's cache
+// file, rescanning it only when it changed since the last call.
+func (p *LogProvider) diskStats(sourceID string) (int, time.Time) {
+	info, err := os.Stat(p.cachePath(sourceID))
+	if err != nil {
+		return 0, time.Time{}
+	}
+	p.mu.Lock()
+	if ds, ok := p.diskStat[sourceID]; ok && ds.mod.Equal(info.ModTime()) {
+		p.mu.Unlock()
+		return ds.events, ds.latest
+	}
+	p.mu.Unlock()
+
+	var st SourceStatus
+	if err := scanCache(p.cachePath(sourceID), &st); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to scan cache")
+		return 0, time.Time{}
+	}
+	p.mu.Lock()
+	p.diskStat[sourceID] = cacheStat{mod: info.ModTime(), events: st.Events, latest: st.LastEvent}
+	p.mu.Unlock()
+	return st.Events, st.LastEvent
+}
+
+// cacheStat is the summary diskStats keeps of a cache file.
+type cacheStat struct {
+	mod    time.Time
+	events int
+	latest time.Time
+}
+
+// IssuesInRange yields the history of each issue alive between start and end,
+// one issue at a time, with the semantics of GetIssuesInRange. In streaming
+// mode the histories are read from the cache file as they complete, so only
+// the issues being read are held in memory.
+func (p *LogProvider) IssuesInRange(sourceID string, start, end time.Time) iter.Seq[[]IssueEvent] {
+	histories := p.issuesInRange(sourceID, start, end)
+	f, ok := p.filter(sourceID)
+	if !ok {
+		return histories
+	}
+	return func(yield func([]IssueEvent) bool) {
+		for history := range histories {
+			if matchesFilter(history, f) && !yield(history) {
+				return
+			}
+		}
+	}
+}
+
+func (p *LogProvider) issuesInRange(sourceID string, start, end time.Time) iter.Seq[[]IssueEvent] {
+	if p.onDisk(sourceID) {
+		return streamCache(p.cachePath(sourceID), start, end, p.store.clock().UnixMicro())
+	}
+	// Grouped lazily, so a session holding the iterator holds no events.
+	return func(yield func([]IssueEvent) bool) {
+		for history := range GroupByIssue(p.store.GetIssuesInRange(sourceID, start, end)) {
+			if !yield(history) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package eventlog
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestLogProvider_StreamingMatchesMemory(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) int64 { return base.AddDate(0, 0, days).UnixMicro() }
+	clock := func() time.Time { return base.AddDate(0, 0, 40) }
+
+	// Interleaved histories: one before the window, two spanning it, one
+	// with an event past the clock.
+	events := []IssueEvent{
+		{IssueKey: "OLD-1", EventType: Created, Timestamp: at(0)},
+		{IssueKey: "A-1", EventType: Created, Timestamp: at(1)},
+		{IssueKey: "OLD-1", EventType: Change, ToStatus: "Done", Timestamp: at(2)},
+		{IssueKey: "B-1", EventType: Created, Timestamp: at(3)},
+		{IssueKey: "A-1", EventType: Change, ToStatus: "Doing", Timestamp: at(12)},
+		{IssueKey: "B-1", EventType: Change, ToStatus: "Doing", Timestamp: at(15)},
+		{IssueKey: "A-1", EventType: Change, ToStatus: "Done", Timestamp: at(20)},
+		{IssueKey: "B-1", EventType: Change, ToStatus: "Done", Timestamp: at(45)},
+	}
+
+	cacheDir := t.TempDir()
+	store := NewEventStore(clock)
+	store.Append("S", events)
+	if err := store.Save(cacheDir, "S"); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	start, end := base.AddDate(0, 0, 10), base.AddDate(0, 0, 50)
+
+	collect := func(p *LogProvider) map[string][]IssueEvent {
+		got := make(map[string][]IssueEvent)
+		for history := range p.IssuesInRange("S", start, end) {
+			got[history[0].IssueKey] = history
+		}
+		return got
+	}
+
+	inMemory := NewLogProvider(nil, store, cacheDir, 24, 36, 5000, 10*time.Minute)
+	want := collect(inMemory)
+	if len(want) != 2 || len(want["B-1"]) != 2 {
+		t.Fatalf("expected A-1 and B-1 with B-1 cut at the clock, got %v", want)
+	}
+
+	streamed := NewLogProvider(nil, NewEventStore(clock), cacheDir, 24, 36, 5000, 10*time.Minute)
+	streamed.SetStreaming(true)
+	if got := collect(streamed); !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed histories differ from memory:\n got %v\nwant %v", got, want)
+	}
+	if got, want := streamed.GetIssuesInRange("S", start, end), inMemory.GetIssuesInRange("S", start, end); !reflect.DeepEqual(got, want) {
+		t.Errorf("streamed events differ from memory:\n got %v\nwant %v", got, want)
+	}
+	if got := streamed.GetEventsForIssue("S", "A-1"); len(got) != 3 {
+		t.Errorf("expected 3 events of A-1 from disk, got %d", len(got))
+	}
+	if streamed.GetEventCount("S") != len(events) || !streamed.GetLatestTimestamp("S").Equal(time.UnixMicro(at(45))) {
+		t.Errorf("disk stats = %d events, latest %v", streamed.GetEventCount("S"), streamed.GetLatestTimestamp("S"))
+	}
+	if streamed.store.Count("S") != 0 {
+		t.Error("streaming reads must not load the log into memory")
+	}
+}
+
+func TestLogProvider_Filter(t *testing.T) {
+	base := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	at := func(days int) int64 { return base.AddDate(0, 0, days).UnixMicro() }
+	clock := func() time.Time { return base.AddDate(0, 0, 40) }
+
+	events := []IssueEvent{
+		{IssueKey: "A-1", EventType: Created, Timestamp: at(1), Labels: []string{"payments"}},
+		{IssueKey: "A-2", EventType: Created, Timestamp: at(2), Labels: []string{"search"}},
+		{IssueKey: "A-3", EventType: Created, Timestamp: at(3), Labels: []string{"payments"}, Components: []string{"API"}},
+		{IssueKey: "A-1", EventType: Change, ToStatus: "Done", Timestamp: at(4)},
+		{IssueKey: "A-2", EventType: Change, ToStatus: "Done", Timestamp: at(5)},
+	}
+	store := NewEventStore(clock)
+	store.Append("S", events)
+	p := NewLogProvider(nil, store, "", 24, 36, 5000, 10*time.Minute)
+	start, end := base, base.AddDate(0, 0, 30)
+
+	keys := func() []string {
+		var got []string
+		for history := range p.IssuesInRange("S", start, end) {
+			got = append(got, history[0].IssueKey)
+		}
+		return got
+	}
+
+	p.SetFilter("S", jira.IssueFilter{Labels: []string{"payments"}})
+	if got := keys(); !reflect.DeepEqual(got, []string{"A-1", "A-3"}) {
+		t.Errorf("Expected the payments items, got %v", got)
+	}
+	if got := p.GetIssuesInRange("S", start, end); len(got) != 3 || got[2].IssueKey != "A-1" {
+		t.Errorf("Expected the 3 events of A-1 and A-3 in time order, got %v", got)
+	}
+
+	p.SetFilter("S", jira.IssueFilter{Labels: []string{"payments"}, Components: []string{"api"}})
+	if got := keys(); !reflect.DeepEqual(got, []string{"A-3"}) {
+		t.Errorf("Expected labels and components to both apply, got %v", got)
+	}
+
+	p.SetFilter("S", jira.IssueFilter{})
+	if got := keys(); len(got) != 3 {
+		t.Errorf("Expected a zero filter to remove the narrowing, got %v", got)
+	}
+}
@@ -0,0 +1,80 @@
+package eventlog
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// SyncState is the persisted watermark of a source's event log cache.
+// OMRC and NMRC are the oldest and newest most-recent-change across the cached
+// issues; delta syncs fetch everything updated since NMRC. LastSync is the wall
+// time of the last successful Jira sync and decides whether a tool call can be
+// served from the cache alone.
+type SyncState struct {
+	OMRC     time.Time `json:"omrc"`
+	NMRC     time.Time `json:"nmrc"`
+	LastSync time.Time `json:"last_sync"`
+
+	// Backfill is set while an initial hydration is incomplete: the cache holds
+	// the newest issues down to its boundary, and the next sync resumes below it.
+	Backfill *Backfill `json:"backfill,omitempty"`
+	// Capped records that the initial hydration stopped at INGESTION_MAX_ITEMS,
+	// so issues last updated before the OMRC were never fetched.
+	Capped bool `json:"capped,omitempty"`
+}
+
+// Backfill is the checkpoint of an initial hydration, persisted with the cache
+// after each batch. Hydration runs newest first, so everything updated after
+// Boundary is cached.
+type Backfill struct {
+	Started  time.Time `json:"started"`  // Wall time the hydration began
+	Boundary time.Time `json:"boundary"` // Oldest Jira 'updated' timestamp fetched so far
+	Fetched  int       `json:"fetched"`  // Issues fetched so far, counted against INGESTION_MAX_ITEMS
+}
+
+func syncStatePath(cacheDir, sourceID string) string {
+	return filepath.Join(cacheDir, fmt.Sprintf("%s.sync.json", sourceID))
+}
+
+// LoadSyncState reads the persisted watermark of a source. It returns the zero
+// state if none was recorded yet.
+func LoadSyncState(cacheDir, sourceID string) (SyncState, error) {
+	var st SyncState
+	data, err := os.ReadFile(syncStatePath(cacheDir, sourceID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return st, nil
+		}
+		return st, err
+	}
+	if err := json.Unmarshal(data, &st); err != nil {
+		return SyncState{}, fmt.Errorf("invalid sync state for %s: %w", sourceID, err)
+	}
+	return st, nil
+}
+
+// SaveSyncState persists the watermark of a source next to its event log.
+func SaveSyncState(cacheDir, sourceID string, st SyncState) error {
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := syncStatePath(cacheDir, sourceID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// DeleteSyncState removes the persisted watermark of a source.
+func DeleteSyncState(cacheDir, sourceID string) error {
+	err := os.Remove(syncStatePath(cacheDir, sourceID))
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package eventlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestLogProvider_HydrateServesFreshCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	sourceID := "PROJ_1"
+	now := time.Now().Truncate(time.Minute)
+
+	seed := NewEventStore(nil)
+	seed.Append(sourceID, []IssueEvent{
+		{IssueKey: "PROJ-1", EventType: Created, Timestamp: now.Add(-2 * time.Hour).UnixMicro()},
+		{IssueKey: "PROJ-2", EventType: Created, Timestamp: now.Add(-time.Hour).UnixMicro()},
+	})
+	if err := seed.Save(cacheDir, sourceID); err != nil {
+		t.Fatalf("Save failed: %v", err)
+	}
+	if err := SaveSyncState(cacheDir, sourceID, SyncState{LastSync: time.Now()}); err != nil {
+		t.Fatalf("SaveSyncState failed: %v", err)
+	}
+
+	var queries []string
+	client := &MockJiraClient{SearchIssuesFunc: func(jql string, startAt, maxResults int) (*jira.SearchResponse, error) {
+		queries = append(queries, jql)
+		return &jira.SearchResponse{}, nil
+	}}
+	store := NewEventStore(nil)
+	p := NewLogProvider(client, store, cacheDir, 24, 36, 5000, 10*time.Minute)
+
+	// 1. Fresh watermark: served from disk, no Jira call
+	if _, err := p.Hydrate(context.Background(), sourceID, "PROJ", "project = PROJ", nil); err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	if len(queries) != 0 {
+		t.Errorf("Expected no Jira sync for a fresh cache, got %v", queries)
+	}
+	if store.Count(sourceID) != 2 {
+		t.Errorf("Expected 2 cached events, got %d", store.Count(sourceID))
+	}
+
+	// 2. Stale watermark: delta sync from the NMRC
+	if err := SaveSyncState(cacheDir, sourceID, SyncState{LastSync: time.Now().Add(-time.Hour)}); err != nil {
+		t.Fatalf("SaveSyncState failed: %v", err)
+	}
+	if _, err := p.Hydrate(context.Background(), sourceID, "PROJ", "project = PROJ", nil); err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	if len(queries) != 1 || !strings.Contains(queries[0], now.Add(-time.Hour).Format(DateTimeFormat)) {
+		t.Fatalf("Expected one delta sync from the NMRC, got %v", queries)
+	}
+
+	st, err := LoadSyncState(cacheDir, sourceID)
+	if err != nil {
+		t.Fatalf("LoadSyncState failed: %v", err)
+	}
+	if time.Since(st.LastSync) > time.Minute {
+		t.Errorf("Expected the sync to advance last_sync, got %v", st.LastSync)
+	}
+	if !st.NMRC.Equal(now.Add(-time.Hour)) || !st.OMRC.Equal(now.Add(-2*time.Hour)) {
+		t.Errorf("Expected OMRC/NMRC watermarks of the cached issues, got %+v", st)
+	}
+}
+
+func TestLogProvider_HydrateResumesInterruptedBackfill(t *testing.T) {
+	cacheDir := t.TempDir()
+	sourceID := "PROJ_1"
+	base := time.Now().Add(-24 * time.Hour).Truncate(time.Minute)
+	issue := func(i int) jira.IssueDTO {
+		ts := base.Add(-time.Duration(i) * time.Minute).Format("2006-01-02T15:04:05.000-0700")
+		dto := jira.IssueDTO{Key: fmt.Sprintf("PROJ-%d", i)}
+		dto.Fields.Created = ts
+		dto.Fields.Updated = ts
+		return dto
+	}
+
+	// 1. A full page, then the connection drops
+	client := &MockJiraClient{SearchIssuesFunc: func(jql string, startAt, maxResults int) (*jira.SearchResponse, error) {
+		if startAt > 0 {
+			return nil, errors.New("connection reset")
+		}
+		resp := &jira.SearchResponse{Total: 302}
+		for i := range maxResults {
+			resp.Issues = append(resp.Issues, issue(i))
+		}
+		return resp, nil
+	}}
+	p := NewLogProvider(client, NewEventStore(nil), cacheDir, 24, 36, 5000, 10*time.Minute)
+	if _, err := p.Hydrate(context.Background(), sourceID, "PROJ", "project = PROJ", nil); err == nil {
+		t.Fatal("Expected the hydration to fail on the second page")
+	}
+	st := p.SyncState(sourceID)
+	if st.Backfill == nil || st.Backfill.Fetched != 300 || !st.Backfill.Boundary.Equal(base.Add(-299*time.Minute)) {
+		t.Fatalf("Expected a checkpoint after the first page, got %+v", st.Backfill)
+	}
+	if !st.LastSync.IsZero() {
+		t.Errorf("Expected no last sync for an incomplete hydration, got %v", st.LastSync)
+	}
+	status, err := p.Status(sourceID)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Issues != 300 || status.Complete || len(status.Gaps) == 0 || status.Gaps[0].Kind != GapBackfill {
+		t.Errorf("Expected 300 cached issues and a backfill gap, got %+v", status)
+	}
+
+	// 2. After a restart: resume below the boundary, then delta-sync the head
+	var queries []string
+	client = &MockJiraClient{SearchIssuesFunc: func(jql string, startAt, maxResults int) (*jira.SearchResponse, error) {
+		queries = append(queries, jql)
+		resp := &jira.SearchResponse{}
+		if len(queries) == 1 {
+			resp.Issues = []jira.IssueDTO{issue(299), issue(300), issue(301)} // 299 again: the boundary minute
+			resp.Total = 3
+		}
+		return resp, nil
+	}}
+	p = NewLogProvider(client, NewEventStore(nil), cacheDir, 24, 36, 5000, 10*time.Minute)
+	if _, err := p.Hydrate(context.Background(), sourceID, "PROJ", "project = PROJ", nil); err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	if len(queries) != 2 {
+		t.Fatalf("Expected a resume and a delta sync, got %v", queries)
+	}
+	if want := `updated <= "` + base.Add(-298*time.Minute).Format(DateTimeFormat) + `"`; !strings.Contains(queries[0], want) || !strings.Contains(queries[0], "ORDER BY updated DESC") {
+		t.Errorf("Expected the resume to page below the boundary (%s), got %s", want, queries[0])
+	}
+	if !strings.Contains(queries[1], "ORDER BY updated ASC") {
+		t.Errorf("Expected a delta sync after the resume, got %s", queries[1])
+	}
+
+	status, err = p.Status(sourceID)
+	if err != nil {
+		t.Fatalf("Status failed: %v", err)
+	}
+	if status.Issues != 302 || status.Events != 302 || !status.Complete || len(status.Gaps) != 0 {
+		t.Errorf("Expected 302 issues without duplicates and no gaps, got %+v", status)
+	}
+	if !status.OMRC.Equal(base.Add(-301 * time.Minute)) {
+		t.Errorf("Expected the OMRC to reach the oldest issue, got %v", status.OMRC)
+	}
+}
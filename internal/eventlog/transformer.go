@@ -217,14 +217,26 @@ func TransformIssue(dto jira.IssueDTO, registry *jira.NameRegistry) []IssueEvent
 	createdTime, _ := jira.ParseTime(dto.Fields.Created)
 	createdTS := createdTime.UnixMicro()
 	events = append(events, IssueEvent{
-		IssueKey:   issueKey,
-		IssueType:  issueType,
-		EventType:  Created,
-		Timestamp:  createdTS,
-		ToStatus:   initialStatus,
-		ToStatusID: initialStatusID,
-		Flagged:    initialFlagged,
-		IsHealed:   stopProcessing, // Flag that we hit a boundary
+		IssueKey:         issueKey,
+		IssueType:        issueType,
+		EventType:        Created,
+		Timestamp:        createdTS,
+		ToStatus:         initialStatus,
+		ToStatusID:       initialStatusID,
+		Flagged:          initialFlagged,
+		IsHealed:         stopProcessing, // Flag that we hit a boundary
+		Components:       dto.Fields.ComponentNames(),
+		Labels:           dto.Fields.Labels,
+		ParentKey:        dto.Fields.ParentKey(),
+		Subtask:          dto.Fields.IssueType.Subtask,
+		HierarchyLevel:   dto.Fields.IssueType.HierarchyLevel,
+		FixVersions:      dto.Fields.FixVersions,
+		Estimate:         dto.Fields.Estimate,
+		TimeInStatus:     dto.Fields.TimeInStatus,
+		Assignee:         dto.Fields.AssigneeName(),
+		Team:             dto.Fields.Team,
+		FormerKeys:       formerKeys(dto),
+		HistoryTruncated: dto.Changelog.IsTruncated(),
 	})
 
 	// 4. Handle Snapshot Resolution (Fallthrough/De-duplication)
@@ -318,3 +330,28 @@ func extractFlaggedValue(val any) string {
 	}
 	return ""
 }
+
+// formerKeys returns the keys an issue had before its moves, oldest first,
+// from the Key changes of its changelog (sorted chronologically by
+// TransformIssue).
+func formerKeys(dto jira.IssueDTO) []string {
+	if dto.Changelog == nil {
+		return nil
+	}
+	var keys []string
+	for _, history := range dto.Changelog.Histories {
+		for _, item := range history.Items {
+			if !strings.EqualFold(item.Field, "Key") {
+				continue
+			}
+			key := item.FromString
+			if key == "" {
+				key = item.From
+			}
+			if key != "" && key != dto.Key && !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+	}
+	return keys
+}
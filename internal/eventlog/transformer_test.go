@@ -4,6 +4,7 @@ import (
 	"mcs-mcp/internal/eventlog"
 	"mcs-mcp/internal/jira"
 	"testing"
+	"time"
 )
 
 func TestTransformIssue_DuplicateResolved(t *testing.T) {
@@ -15,6 +16,7 @@ func TestTransformIssue_DuplicateResolved(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -83,6 +85,7 @@ func TestTransformIssue_ResolutionGracePeriod(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -139,6 +142,7 @@ func TestTransformIssue_MisconfiguredWorkflow(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -191,6 +195,7 @@ func TestTransformIssue_ExplicitUnresolved(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -242,6 +247,7 @@ func TestTransformIssue_Case1_Preserve(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -317,6 +323,7 @@ func TestTransformIssue_Case2_Heal(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -405,6 +412,7 @@ func TestTransformIssue_ExternalMove_NoHeal(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -473,6 +481,7 @@ func TestTransformIssue_BoundaryWithLaterEvent(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -554,6 +563,7 @@ func TestTransformIssue_LostTerminalEvent_UserReproduction(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -623,6 +633,7 @@ func TestTransformIssue_LostTerminalEvent_SameTimestamp(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -680,6 +691,7 @@ func TestTransformIssue_GlitchReproduction(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Feature"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -761,6 +773,7 @@ func TestTransformIssue_FlaggedHistory(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -851,6 +864,7 @@ func TestTransformIssue_MoveBoundaryWithFlagged(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -952,6 +966,7 @@ func TestTransformIssue_RegistryHealing(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Bug"},
 			Status: struct {
 				ID               string `json:"id"`
@@ -1022,3 +1037,48 @@ func TestTransformIssue_RegistryHealing(t *testing.T) {
 		}
 	}
 }
+
+func TestTransformIssue_StreamAttributes(t *testing.T) {
+	dto := jira.IssueDTO{
+		Key: "TEST-4",
+		Fields: jira.FieldsDTO{
+			Created:    "2024-03-20T10:00:00.000+0000",
+			Components: []jira.ComponentDTO{{ID: "1", Name: "API"}, {ID: "2", Name: "Web"}},
+			Labels:     []string{"checkout"},
+			Parent:     &jira.ParentDTO{ID: "100", Key: "TEST-1"},
+		},
+	}
+	dto.Fields.Status.ID = "1"
+	dto.Fields.Status.Name = "Open"
+
+	events := eventlog.TransformIssue(dto, nil)
+	if len(events) != 1 || events[0].EventType != eventlog.Created {
+		t.Fatalf("Expected a single Created event, got %+v", events)
+	}
+
+	issue := eventlog.ReconstructIssue(events, time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC))
+	if len(issue.Components) != 2 || issue.Components[0] != "API" || issue.Components[1] != "Web" {
+		t.Errorf("Expected components [API Web], got %v", issue.Components)
+	}
+	if len(issue.Labels) != 1 || issue.Labels[0] != "checkout" {
+		t.Errorf("Expected labels [checkout], got %v", issue.Labels)
+	}
+	if issue.ParentKey != "TEST-1" {
+		t.Errorf("Expected parent TEST-1, got %q", issue.ParentKey)
+	}
+}
+
+func TestTransformIssue_TruncatedHistory(t *testing.T) {
+	dto := jira.IssueDTO{Key: "TEST-1", Fields: jira.FieldsDTO{Created: "2024-01-01T10:00:00.000+0000"}}
+	dto.Changelog = &jira.ChangelogDTO{MaxResults: 100, Total: 120}
+
+	issue := eventlog.ReconstructIssue(eventlog.TransformIssue(dto, nil), time.Time{})
+	if !issue.HistoryTruncated {
+		t.Error("Expected a partial changelog to mark the history as truncated")
+	}
+
+	dto.Changelog = &jira.ChangelogDTO{MaxResults: 120, Total: 120}
+	if issue := eventlog.ReconstructIssue(eventlog.TransformIssue(dto, nil), time.Time{}); issue.HistoryTruncated {
+		t.Error("Expected a complete changelog not to be marked as truncated")
+	}
+}
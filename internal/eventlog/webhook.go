@@ -0,0 +1,145 @@
+package eventlog
+
+import (
+	"context"
+	"fmt"
+	"mcs-mcp/internal/jira"
+	"slices"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Webhook event types applied by ApplyWebhook.
+const (
+	WebhookIssueCreated = "jira:issue_created"
+	WebhookIssueUpdated = "jira:issue_updated"
+)
+
+// liveSource is what a webhook needs to update a source synced by this process.
+type liveSource struct {
+	jql      string
+	registry *jira.NameRegistry
+}
+
+// EnableWebhooks records that a webhook receiver applies Jira changes as they
+// happen from since on. A source synced at or after since is then kept
+// current by ApplyWebhook, and Hydrate serves it from the cache regardless of
+// the sync interval.
+func (p *LogProvider) EnableWebhooks(since time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.liveSince = since
+}
+
+// track remembers the query and registry of a source after a successful sync.
+func (p *LogProvider) track(sourceID, jql string, registry *jira.NameRegistry) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.live[sourceID] = liveSource{jql: jql, registry: registry}
+}
+
+// isLive reports whether webhooks keep a source current: it was synced by
+// this process after the webhook receiver started, and no backfill is pending.
+func (p *LogProvider) isLive(sourceID string, state SyncState) (liveSource, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	src, ok := p.live[sourceID]
+	if !ok || p.liveSince.IsZero() || state.Backfill != nil || state.LastSync.Before(p.liveSince) {
+		return liveSource{}, false
+	}
+	return src, true
+}
+
+// ApplyWebhook applies a Jira issue webhook to every live source and returns
+// the number of sources whose event log changed.
+//
+// For an issue already cached, the status, resolution, and flag changes of
+// the delivered changelog entry are appended (a redelivery is deduplicated).
+// An issue not cached yet, e.g. a new one, is fetched with its full history
+// through the source's query, which also decides whether it belongs to the
+// source. Either way every change after the last sync reaches the log, so the
+// NMRC the next delta sync starts from stays a safe boundary. Deletions and
+// other event types are left to the next full re-ingestion.
+func (p *LogProvider) ApplyWebhook(ctx context.Context, hook jira.WebhookDTO) (int, error) {
+	if hook.WebhookEvent != WebhookIssueCreated && hook.WebhookEvent != WebhookIssueUpdated {
+		return 0, nil
+	}
+	if hook.Issue.Key == "" {
+		return 0, fmt.Errorf("webhook %s carries no issue key", hook.WebhookEvent)
+	}
+
+	p.mu.Lock()
+	sourceIDs := make([]string, 0, len(p.live))
+	for sourceID := range p.live {
+		sourceIDs = append(sourceIDs, sourceID)
+	}
+	p.mu.Unlock()
+	slices.Sort(sourceIDs)
+
+	changed := 0
+	for _, sourceID := range sourceIDs {
+		state := p.SyncState(sourceID)
+		src, ok := p.isLive(sourceID, state)
+		if !ok {
+			continue
+		}
+		p.ensureLoaded(sourceID)
+		updated, err := p.applyHook(ctx, sourceID, src, hook)
+		p.release(sourceID)
+		if err != nil {
+			return changed, err
+		}
+		if !updated {
+			continue
+		}
+		changed++
+		log.Info().Str("source", sourceID).Str("issue", hook.Issue.Key).Str("event", hook.WebhookEvent).Msg("Applied Jira webhook")
+	}
+	return changed, nil
+}
+
+// applyHook merges a webhook into the loaded log of one source and saves it,
+// reporting whether the log changed.
+func (p *LogProvider) applyHook(ctx context.Context, sourceID string, src liveSource, hook jira.WebhookDTO) (bool, error) {
+	state := p.SyncState(sourceID)
+	updated := false
+	if hook.Changelog != nil && len(p.store.GetEventsForIssue(sourceID, hook.Issue.Key)) > 0 {
+		before := p.store.Count(sourceID)
+		p.store.Append(sourceID, webhookEvents(hook, src.registry))
+		updated = p.store.Count(sourceID) > before
+	} else {
+		q := jira.AndJQL(src.jql, jira.FieldEquals("key", hook.Issue.Key))
+		resp, err := p.client.SearchIssues(ctx, q, 0, 1)
+		if err != nil {
+			return false, fmt.Errorf("failed to fetch %s for %s: %w", hook.Issue.Key, sourceID, err)
+		}
+		for _, dto := range resp.Issues {
+			p.store.Merge(sourceID, TransformIssue(dto, src.registry))
+			updated = true
+		}
+	}
+	if updated {
+		// The cache is current, but last_sync stays the time Jira was last asked.
+		p.persist(sourceID, true, state.LastSync)
+	}
+	return updated, nil
+}
+
+// webhookEvents transforms the changelog entry of a webhook into the events
+// it adds to an issue's cached history.
+func webhookEvents(hook jira.WebhookDTO, registry *jira.NameRegistry) []IssueEvent {
+	at := time.UnixMilli(hook.Timestamp)
+	dto := hook.Issue
+	dto.Changelog = &jira.ChangelogDTO{Histories: []jira.HistoryDTO{{Created: jira.FormatTime(at), Items: hook.Changelog.Items}}}
+
+	// TransformIssue also derives the Created event and the snapshot
+	// resolution, both of which the cached history already has.
+	var events []IssueEvent
+	for _, e := range TransformIssue(dto, registry) {
+		if e.EventType != Created && e.Timestamp == at.UnixMicro() {
+			events = append(events, e)
+		}
+	}
+	return events
+}
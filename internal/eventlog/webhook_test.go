@@ -0,0 +1,102 @@
+package eventlog
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestLogProvider_ApplyWebhook(t *testing.T) {
+	cacheDir := t.TempDir()
+	sourceID := "PROJ_1"
+	created := time.Now().Add(-48 * time.Hour).Truncate(time.Millisecond)
+	issue := func(key string) jira.IssueDTO {
+		dto := jira.IssueDTO{Key: key}
+		dto.Fields.Status.ID = "1"
+		dto.Fields.Status.Name = "To Do"
+		dto.Fields.Created = jira.FormatTime(created)
+		dto.Fields.Updated = dto.Fields.Created
+		return dto
+	}
+
+	var queries []string
+	client := &MockJiraClient{SearchIssuesFunc: func(jql string, startAt, maxResults int) (*jira.SearchResponse, error) {
+		queries = append(queries, jql)
+		switch {
+		case startAt > 0:
+			return &jira.SearchResponse{}, nil
+		case strings.Contains(jql, `key = "PROJ-2"`):
+			return &jira.SearchResponse{Total: 1, Issues: []jira.IssueDTO{issue("PROJ-2")}}, nil
+		case strings.Contains(jql, "key ="):
+			return &jira.SearchResponse{}, nil
+		}
+		return &jira.SearchResponse{Total: 1, Issues: []jira.IssueDTO{issue("PROJ-1")}}, nil
+	}}
+	// A zero sync interval would delta-sync on every call without webhooks.
+	p := NewLogProvider(client, NewEventStore(nil), cacheDir, 24, 36, 5000, 0)
+	p.EnableWebhooks(time.Now())
+	if _, err := p.Hydrate(context.Background(), sourceID, "PROJ", "project = PROJ", nil); err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	lastSync := p.SyncState(sourceID).LastSync
+
+	// 1. A status change of a cached issue is appended, once
+	moved := jira.WebhookDTO{
+		Timestamp:    time.Now().UnixMilli(),
+		WebhookEvent: WebhookIssueUpdated,
+		Issue:        issue("PROJ-1"),
+		Changelog: &jira.WebhookChangelogDTO{Items: []jira.ItemDTO{
+			{Field: "status", From: "1", FromString: "To Do", To: "3", ToString: "In Progress"},
+		}},
+	}
+	moved.Issue.Fields.Status.ID = "3"
+	for i, want := range []int{1, 0} {
+		changed, err := p.ApplyWebhook(context.Background(), moved)
+		if err != nil || changed != want {
+			t.Fatalf("Delivery %d: expected %d changed sources, got %d (%v)", i+1, want, changed, err)
+		}
+	}
+	events := p.GetEventsForIssue(sourceID, "PROJ-1")
+	if len(events) != 2 || events[0].EventType != Created || events[0].ToStatusID != "1" || events[1].ToStatusID != "3" || events[1].Timestamp != moved.Timestamp*1000 {
+		t.Errorf("Expected the birth status and the webhook transition, got %+v", events)
+	}
+
+	// 2. A new issue is fetched with its history through the source query
+	queries = nil
+	if changed, err := p.ApplyWebhook(context.Background(), jira.WebhookDTO{WebhookEvent: WebhookIssueCreated, Issue: issue("PROJ-2")}); err != nil || changed != 1 {
+		t.Fatalf("Expected the new issue to be fetched, got %d (%v)", changed, err)
+	}
+	if len(queries) != 1 || queries[0] != `(project = PROJ) AND key = "PROJ-2"` {
+		t.Errorf("Expected one query for the new issue, got %v", queries)
+	}
+	// An issue outside the source query is not added.
+	if changed, _ := p.ApplyWebhook(context.Background(), jira.WebhookDTO{WebhookEvent: WebhookIssueCreated, Issue: issue("OTHER-1")}); changed != 0 {
+		t.Errorf("Expected an issue outside the query to be skipped, got %d changed", changed)
+	}
+
+	// 3. Persisted without advancing last_sync; Hydrate serves the live cache
+	st := p.SyncState(sourceID)
+	if !st.LastSync.Equal(lastSync) || !st.NMRC.Equal(time.UnixMilli(moved.Timestamp)) {
+		t.Errorf("Expected the NMRC of the webhook and an unchanged last_sync, got %+v", st)
+	}
+	queries = nil
+	if _, err := p.Hydrate(context.Background(), sourceID, "PROJ", "project = PROJ", nil); err != nil {
+		t.Fatalf("Hydrate failed: %v", err)
+	}
+	if len(queries) != 0 {
+		t.Errorf("Expected no delta sync for a live source, got %v", queries)
+	}
+	reloaded := NewEventStore(nil)
+	if err := reloaded.Load(cacheDir, sourceID); err != nil || reloaded.Count(sourceID) != 3 {
+		t.Errorf("Expected 3 events on disk, got %d (%v)", reloaded.Count(sourceID), err)
+	}
+
+	// 4. Without a receiver, webhooks change nothing
+	other := NewLogProvider(client, NewEventStore(nil), cacheDir, 24, 36, 5000, 0)
+	if changed, _ := other.ApplyWebhook(context.Background(), moved); changed != 0 {
+		t.Errorf("Expected no change without EnableWebhooks, got %d", changed)
+	}
+}
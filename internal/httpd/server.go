@@ -1,6 +1,7 @@
 // Package httpd runs a lightweight localhost HTTP server that serves rendered
 // chart pages from chartbuf via UUID lookup. Binds to a random port in
-// [3000, 4000] alongside the stdio MCP transport.
+// [3000, 4000] alongside the stdio MCP transport. It also hosts the optional
+// Jira webhook receiver (WebhookServer).
 package httpd
 
 import (
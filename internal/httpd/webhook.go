@@ -0,0 +1,161 @@
+package httpd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+
+	"mcs-mcp/internal/jira"
+
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// maxWebhookBody bounds a webhook delivery; Jira sends the full issue
+	// with its fields, which stays well below this.
+	maxWebhookBody = 10 << 20
+
+	// webhookQueueSize is the number of deliveries buffered while the
+	// previous ones are applied. A full queue answers 503 so Jira retries.
+	webhookQueueSize = 256
+)
+
+// WebhookFunc applies one Jira webhook delivery of a Jira instance ("" for
+// the default connection).
+type WebhookFunc func(ctx context.Context, instance string, hook jira.WebhookDTO) error
+
+// WebhookServer receives Jira issue webhooks on POST /webhook (default
+// instance) and POST /webhook/{instance}, and applies them one at a time, in
+// the order received.
+type WebhookServer struct {
+	apply    WebhookFunc
+	secret   string
+	listener net.Listener
+	srv      *http.Server
+	queue    chan delivery
+}
+
+// delivery is a queued webhook of an instance.
+type delivery struct {
+	instance string
+	hook     jira.WebhookDTO
+}
+
+// NewWebhook creates a webhook receiver listening on addr (e.g.
+// "127.0.0.1:8080"). Deliveries must carry the HMAC-SHA256 signature of the
+// secret that Jira sends in the X-Hub-Signature header, so a secret is
+// required.
+func NewWebhook(addr, secret string, apply WebhookFunc) (*WebhookServer, error) {
+	if secret == "" {
+		return nil, errors.New("a webhook secret is required to verify deliveries")
+	}
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &WebhookServer{
+		apply:    apply,
+		secret:   secret,
+		listener: ln,
+		queue:    make(chan delivery, webhookQueueSize),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /webhook", s.handleWebhook)
+	mux.HandleFunc("POST /webhook/{instance}", s.handleWebhook)
+
+	s.srv = &http.Server{Handler: mux}
+	return s, nil
+}
+
+// Port returns the port the receiver is listening on.
+func (s *WebhookServer) Port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+// Start serves webhook deliveries and applies them. It blocks until ctx is
+// cancelled, then gracefully shuts down the receiver.
+func (s *WebhookServer) Start(ctx context.Context) error {
+	log.Info().Int("port", s.Port()).Msg("Jira webhook receiver listening")
+
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case d := <-s.queue:
+				if err := s.apply(ctx, d.instance, d.hook); err != nil {
+					log.Warn().Err(err).Str("instance", d.instance).Str("issue", d.hook.Issue.Key).Str("event", d.hook.WebhookEvent).Msg("Failed to apply Jira webhook")
+				}
+			}
+		}
+	}()
+
+	errCh := make(chan error, 1)
+	go func() {
+		if err := s.srv.Serve(s.listener); err != http.ErrServerClosed {
+			errCh <- err
+		}
+		close(errCh)
+	}()
+
+	select {
+	case <-ctx.Done():
+		log.Info().Msg("Jira webhook receiver shutting down")
+		return s.srv.Shutdown(context.Background())
+	case err := <-errCh:
+		return err
+	}
+}
+
+func (s *WebhookServer) handleWebhook(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxWebhookBody))
+	if err != nil {
+		http.Error(w, "request body too large or unreadable", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if !validSignature(s.secret, body, r.Header.Get("X-Hub-Signature")) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var hook jira.WebhookDTO
+	if err := json.Unmarshal(body, &hook); err != nil {
+		http.Error(w, "invalid webhook payload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	if hook.Issue.Key == "" {
+		http.Error(w, "webhook payload carries no issue", http.StatusBadRequest)
+		return
+	}
+
+	select {
+	case s.queue <- delivery{instance: r.PathValue("instance"), hook: hook}:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		http.Error(w, "webhook queue full", http.StatusServiceUnavailable)
+	}
+}
+
+// validSignature checks a "sha256=<hex>" HMAC signature of body.
+func validSignature(secret string, body []byte, header string) bool {
+	sig, ok := strings.CutPrefix(header, "sha256=")
+	if !ok {
+		return false
+	}
+	got, err := hex.DecodeString(sig)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(got, mac.Sum(nil))
+}
@@ -0,0 +1,79 @@
+package httpd
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestWebhook(t *testing.T) {
+	applied := make(chan delivery, 1)
+	srv, err := NewWebhook("127.0.0.1:0", "s3cret", func(_ context.Context, instance string, hook jira.WebhookDTO) error {
+		applied <- delivery{instance: instance, hook: hook}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("NewWebhook failed: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		_ = srv.Start(ctx)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	body := []byte(`{"timestamp":1760000000000,"webhookEvent":"jira:issue_updated","issue":{"key":"PROJ-1"},"changelog":{"items":[{"field":"status","from":"1","to":"3"}]}}`)
+	post := func(signature string, payload []byte) int {
+		req, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("http://127.0.0.1:%d/webhook/dc", srv.Port()), bytes.NewReader(payload))
+		if signature != "" {
+			req.Header.Set("X-Hub-Signature", signature)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("POST failed: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+	sign := func(payload []byte) string {
+		mac := hmac.New(sha256.New, []byte("s3cret"))
+		mac.Write(payload)
+		return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	}
+
+	if code := post("", body); code != http.StatusUnauthorized {
+		t.Errorf("Unsigned delivery: got status %d, want 401", code)
+	}
+	if code := post("sha256=00", body); code != http.StatusUnauthorized {
+		t.Errorf("Wrong signature: got status %d, want 401", code)
+	}
+	if code := post(sign([]byte(`{}`)), []byte(`{}`)); code != http.StatusBadRequest {
+		t.Errorf("Delivery without issue: got status %d, want 400", code)
+	}
+	if code := post(sign(body), body); code != http.StatusAccepted {
+		t.Fatalf("Signed delivery: got status %d, want 202", code)
+	}
+
+	select {
+	case d := <-applied:
+		if d.instance != "dc" || d.hook.Issue.Key != "PROJ-1" || d.hook.WebhookEvent != "jira:issue_updated" || len(d.hook.Changelog.Items) != 1 {
+			t.Errorf("Unexpected delivery: %+v", d)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Delivery was not applied")
+	}
+}
+
+func TestWebhook_RequiresSecret(t *testing.T) {
+	if _, err := NewWebhook("127.0.0.1:0", "", func(context.Context, string, jira.WebhookDTO) error { return nil }); err == nil {
+		t.Error("Expected an error without a webhook secret")
+	}
+}
@@ -0,0 +1,126 @@
+package i18n
+
+// german translates the guidance, the recurring insights and warnings, and
+// the percentile labels. Tool names, parameter names, and JSON field names
+// stay as they are, since the agent passes them back verbatim.
+var german = map[string]string{
+	// Percentile labels (simulation.Result.PercentileLabels)
+	"P10 (Aggressive / Best Case)":                                   "P10 (Aggressiv / Bestfall)",
+	"P30 (Unlikely / High Risk)":                                     "P30 (Unwahrscheinlich / Hohes Risiko)",
+	"P50 (Coin Toss / Median / 50% probability)":                     "P50 (Münzwurf / Median / 50 % Wahrscheinlichkeit)",
+	"P70 (Probable)":                                                 "P70 (Wahrscheinlich)",
+	"P85 (Likely / Professional Standard / Professional commitment)": "P85 (Sehr wahrscheinlich / Professioneller Standard / Professionelle Zusage)",
+	"P90 (Conservative / Buffer)":                                    "P90 (Konservativ / Puffer)",
+	"P95 (Safe Bet / High Confidence)":                               "P95 (Sichere Wette / Hohe Zuversicht)",
+	"P98 (Limit / Extreme Outlier Boundaries)":                       "P98 (Grenze / Grenze extremer Ausreißer)",
+	"P10 (10% probability to deliver at least this much)":            "P10 (10 % Wahrscheinlichkeit, mindestens so viel zu liefern)",
+	"P30 (30% probability to deliver at least this much)":            "P30 (30 % Wahrscheinlichkeit, mindestens so viel zu liefern)",
+	"P70 (70% probability to deliver at least this much)":            "P70 (70 % Wahrscheinlichkeit, mindestens so viel zu liefern)",
+	"P85 (85% probability to deliver at least this much)":            "P85 (85 % Wahrscheinlichkeit, mindestens so viel zu liefern)",
+	"P90 (90% probability to deliver at least this much)":            "P90 (90 % Wahrscheinlichkeit, mindestens so viel zu liefern)",
+	"P95 (95% probability to deliver at least this much)":            "P95 (95 % Wahrscheinlichkeit, mindestens so viel zu liefern)",
+	"P98 (98% probability to deliver at least this much)":            "P98 (98 % Wahrscheinlichkeit, mindestens so viel zu liefern)",
+	"P10 (Aggressive / Fast Outliers)":                               "P10 (Aggressiv / Schnelle Ausreißer)",
+	"P30 (Unlikely / Fast Pace)":                                     "P30 (Unwahrscheinlich / Hohes Tempo)",
+	"P85 (Likely / SLE / Service Level Expectation)":                 "P85 (Sehr wahrscheinlich / SLE / Service Level Expectation)",
+	"P95 (Safe Bet)":                 "P95 (Sichere Wette)",
+	"P98 (Limit / Extreme Outliers)": "P98 (Grenze / Extreme Ausreißer)",
+
+	// Guidance rules (mcp.guidanceRules)
+	"Data Ingestion Complete: History is loaded and analyzed.":                                                                                                                                                                                                       "Datenimport abgeschlossen: Die Historie ist geladen und analysiert.",
+	"Review the 'data_summary' to understand volume and issue types.":                                                                                                                                                                                                "Prüfen Sie 'data_summary', um Volumen und Vorgangstypen zu verstehen.",
+	"Next Step: Call 'workflow_discover_mapping' to establish the semantic process mapping.":                                                                                                                                                                         "Nächster Schritt: Rufen Sie 'workflow_discover_mapping' auf, um das semantische Prozess-Mapping festzulegen.",
+	"Once mapping is confirmed, use 'guide_diagnostic_roadmap' to plan your analysis.":                                                                                                                                                                               "Sobald das Mapping bestätigt ist, planen Sie die Analyse mit 'guide_diagnostic_roadmap'.",
+	"A previously confirmed workflow mapping is loaded for this board. Use 'guide_diagnostic_roadmap' to plan your analysis, or 'workflow_discover_mapping' to review the mapping.":                                                                                  "Für dieses Board ist ein bereits bestätigtes Workflow-Mapping geladen. Planen Sie die Analyse mit 'guide_diagnostic_roadmap' oder prüfen Sie das Mapping mit 'workflow_discover_mapping'.",
+	"AI MUST summarize the mapping of ALL statuses to TIERS (Demand, Upstream, Downstream, Finished) for the user in a clear table or list.":                                                                                                                         "Die KI MUSS die Zuordnung ALLER Status zu den TIERS (Demand, Upstream, Downstream, Finished) für den Benutzer in einer übersichtlichen Tabelle oder Liste zusammenfassen.",
+	"AI MUST confirm the 'Outcome Strategy' (Value vs. Abandonment):\n  - PRIMARY: Jira Resolutions (if they exist).\n  - SECONDARY: Status mapping (only if resolutions are missing).":                                                                              "Die KI MUSS die 'Outcome Strategy' (Wert vs. Abbruch) bestätigen lassen:\n  - PRIMÄR: Jira-Lösungen (falls vorhanden).\n  - SEKUNDÄR: Status-Mapping (nur wenn Lösungen fehlen).",
+	"AI MUST ask the user to confirm the 'Commitment Point' (the status where work officially starts).":                                                                                                                                                              "Die KI MUSS den Benutzer bitten, den 'Commitment Point' zu bestätigen (den Status, in dem die Arbeit offiziell beginnt).",
+	"PROCESS STABILITY: Understand that Stability measures Cycle-Time predictability, NOT throughput volume.":                                                                                                                                                        "PROZESSSTABILITÄT: Stabilität misst die Vorhersagbarkeit der Durchlaufzeit, NICHT das Durchsatzvolumen.",
+	"No confirmed workflow mapping is active, so tier coverage and divergences cannot be assessed. Run 'workflow_discover_mapping' and persist the mapping first.":                                                                                                   "Es ist kein bestätigtes Workflow-Mapping aktiv, daher lassen sich Tier-Abdeckung und Abweichungen nicht bewerten. Führen Sie zuerst 'workflow_discover_mapping' aus und speichern Sie das Mapping.",
+	"'common_paths' lists each type's most frequent status sequences in first-visit order. Divergences compare each type against the board as a whole, not against an ideal process.":                                                                                "'common_paths' listet die häufigsten Statusfolgen je Typ in der Reihenfolge des ersten Besuchs. Abweichungen vergleichen jeden Typ mit dem gesamten Board, nicht mit einem Idealprozess.",
+	"FAT TAIL: The P98/P50 ratio exceeds 5.6 (Kanban University heuristic), so a few extreme items dominate the distribution. Quote P95 or higher for commitments and investigate the outliers (e.g. via 'analyze_process_stability') before relying on the median.": "FAT TAIL: Das Verhältnis P98/P50 liegt über 5,6 (Heuristik der Kanban University), wenige extreme Elemente dominieren also die Verteilung. Nennen Sie für Zusagen P95 oder höher und untersuchen Sie die Ausreißer (z. B. mit 'analyze_process_stability'), bevor Sie sich auf den Median verlassen.",
+	"Look for 'Batching' (bursts of delivery followed by silence) vs. 'Steady Flow'.":                                                                                                                                                                                "Achten Sie auf 'Batching' (Lieferschübe gefolgt von Stille) im Gegensatz zu 'Steady Flow'.",
+	"XmR charts detect 'Special Cause' variation. If stability is low (outliers/shifts), forecasts are unreliable.":                                                                                                                                                  "XmR-Charts erkennen Streuung mit 'Special Cause'. Ist die Stabilität gering (Ausreißer/Verschiebungen), sind Prognosen unzuverlässig.",
+	"CLOGGED: Stability Index = (WIP / Throughput) / Average Cycle Time exceeds 1.3. More work is in progress than the delivery rate supports — expect cycle times to inflate until WIP is reduced.":                                                                 "VERSTOPFT: Der Stabilitätsindex = (WIP / Durchsatz) / mittlere Durchlaufzeit liegt über 1,3. Es ist mehr Arbeit in Bearbeitung, als die Lieferrate trägt — die Durchlaufzeiten werden steigen, bis das WIP reduziert ist.",
+	"The 'scatterplot' array contains one entry per delivered work item with cycle time (value), date (the work item's outcome date), pooled moving range, and issue type. Render a Cycle Time Scatterplot: X=date, Y=value. Reference lines from stability.xmr: average (center), upper_natural_process_limit, lower_natural_process_limit. For type-specific limits, use stratified[type].xmr.": "Das Array 'scatterplot' enthält je geliefertem Element einen Eintrag mit Durchlaufzeit (value), Datum (Abschlussdatum des Elements), gepoolter gleitender Spannweite und Vorgangstyp. Zeichnen Sie ein Durchlaufzeit-Streudiagramm: X=date, Y=value. Referenzlinien aus stability.xmr: average (Mitte), upper_natural_process_limit, lower_natural_process_limit. Für typspezifische Grenzen verwenden Sie stratified[type].xmr.",
+	"Render a Cycle Time Scatterplot from 'points': X=date (completion), Y=value (cycle time in days), one dot per item, colored by issue_type. Draw 'bands' p50/p85/p95 as horizontal reference lines; 'bands_by_type' holds type-specific lines. Use each point's key for drill-down.":                                                                                                          "Zeichnen Sie aus 'points' ein Durchlaufzeit-Streudiagramm: X=date (Abschluss), Y=value (Durchlaufzeit in Tagen), ein Punkt je Element, gefärbt nach issue_type. Zeichnen Sie 'bands' p50/p85/p95 als horizontale Referenzlinien; 'bands_by_type' enthält typspezifische Linien. Nutzen Sie den key jedes Punkts für den Drill-down.",
+	"SPARSE SAMPLE: Fewer than 30 delivered items (or sprints) back this forecast, so bootstrapped percentiles are jagged and overconfident. Re-run with sampling='parametric' to sample from a fitted Weibull or lognormal distribution, and present both results.":                                                                                                                              "DÜNNE STICHPROBE: Weniger als 30 gelieferte Elemente (oder Sprints) stützen diese Prognose, die gebootstrappten Perzentile sind daher sprunghaft und zu optimistisch. Wiederholen Sie den Lauf mit sampling='parametric', um aus einer angepassten Weibull- oder Lognormalverteilung zu ziehen, und stellen Sie beide Ergebnisse vor.",
+	"SLE recorded. Call 'analyze_sle_compliance' to track the hit rate and the in-flight items on track to breach.": "SLE gespeichert. Rufen Sie 'analyze_sle_compliance' auf, um die Trefferquote und die laufenden Elemente zu verfolgen, die das SLE zu verletzen drohen.",
+	"Aliases apply to forecasts and backtests: the throughput histogram, type mix, stratification, targets, and mix_overrides use the canonical type. Diagnostics such as 'analyze_cycle_time' still report Jira's own types. Re-run 'forecast_monte_carlo' to see the merged streams in 'context.stratification_decisions'.": "Aliase gelten für Prognosen und Backtests: Durchsatzhistogramm, Typmix, Stratifizierung, Ziele und mix_overrides verwenden den kanonischen Typ. Diagnosen wie 'analyze_cycle_time' zeigen weiterhin Jiras eigene Typen. Führen Sie 'forecast_monte_carlo' erneut aus, um die zusammengeführten Ströme in 'context.stratification_decisions' zu sehen.",
+	"Compare 'hit_rate' with 'expected_hit_rate' (the SLE percentile): a P85 SLE is met when at least 85% of delivered items finished in time. 'breach_probability' of a WIP item is the share of historical items of the same age that went on to breach; act on 'at_risk' items before they become 'breached'.":             "Vergleichen Sie 'hit_rate' mit 'expected_hit_rate' (dem SLE-Perzentil): Ein P85-SLE ist erfüllt, wenn mindestens 85 % der gelieferten Elemente rechtzeitig fertig wurden. 'breach_probability' eines WIP-Elements ist der Anteil historischer Elemente gleichen Alters, die das SLE danach verletzten; handeln Sie bei 'at_risk'-Elementen, bevor sie 'breached' werden.",
+	"WIP limits recorded. Call 'analyze_wip_limits' to see how often and how long they were exceeded.": "WIP-Limits gespeichert. Rufen Sie 'analyze_wip_limits' auf, um zu sehen, wie oft und wie lange sie überschritten wurden.",
+	"WIP is counted at the end of each day, as in the CFD. A 'violation_rate' above 0.2 means the limit is not working as a policy — discuss lowering demand or raising the limit deliberately. 'inflation' > 1 means items caught in a violation took longer; treat it as correlation, and confirm with 'analyze_status_persistence' before blaming the status.": "WIP wird wie im CFD am Ende jedes Tages gezählt. Eine 'violation_rate' über 0,2 bedeutet, dass das Limit als Regel nicht wirkt — besprechen Sie, die Nachfrage zu senken oder das Limit bewusst anzuheben. 'inflation' > 1 bedeutet, dass Elemente während einer Überschreitung länger brauchten; werten Sie das als Korrelation und prüfen Sie es mit 'analyze_status_persistence', bevor Sie dem Status die Schuld geben.",
+	"Attribute drift before reporting it: a change in 'composition.total' means the scope moved, a change in 'throughput' means delivery capability moved. Surface every warning — runs with different horizons, issue types, or engines are not like-for-like.":                                                                                                  "Ordnen Sie Abweichungen zu, bevor Sie sie berichten: Eine Änderung von 'composition.total' bedeutet, dass sich der Umfang verschoben hat, eine Änderung von 'throughput', dass sich die Lieferfähigkeit verändert hat. Nennen Sie jede Warnung — Läufe mit unterschiedlichen Horizonten, Vorgangstypen oder Engines sind nicht direkt vergleichbar.",
+	"Process evolution is a long-term trend metric. This tool ignores the session window's Start and uses ONLY its End as the right edge. Lookback is fixed: 12 complete months (bucket='month') or 26 complete weeks (bucket='week'). To shift the trend's right edge, set the session window's End via 'set_analysis_window'.":                                  "Die Prozessentwicklung ist eine langfristige Trendmetrik. Dieses Tool ignoriert den Start des Sitzungsfensters und verwendet NUR dessen Ende als rechten Rand. Der Rückblick ist fest: 12 volle Monate (bucket='month') oder 26 volle Wochen (bucket='week'). Um den rechten Rand des Trends zu verschieben, setzen Sie das Ende des Sitzungsfensters mit 'set_analysis_window'.",
+	"High 'Abandoned Upstream' often points to discovery/refinement issues.":                                                                                  "Ein hoher Wert bei 'Abandoned Upstream' deutet oft auf Probleme in Discovery/Refinement hin.",
+	"High 'Abandoned Downstream' points to execution or commitment issues.":                                                                                   "Ein hoher Wert bei 'Abandoned Downstream' deutet auf Probleme in Umsetzung oder Zusage hin.",
+	"WIP Stability provides a daily historical view of system population.":                                                                                    "Die WIP-Stabilität zeigt den täglichen historischen Bestand des Systems.",
+	"Signals (Outliers/Shifts) indicate that WIP was not actively managed or constrained, which violates Little's Law.":                                       "Signale (Ausreißer/Verschiebungen) zeigen, dass das WIP nicht aktiv gesteuert oder begrenzt wurde, was Little's Law verletzt.",
+	"The system is 'unstable': flow metrics (Cycle Time, Throughput) will be unpredictable and simulations may fail.":                                         "Das System ist 'instabil': Flussmetriken (Durchlaufzeit, Durchsatz) werden unvorhersehbar sein, und Simulationen können fehlschlagen.",
+	"Total WIP Age reveals the cumulative age burden on the system.":                                                                                          "Das Gesamt-WIP-Alter zeigt die kumulierte Altersbelastung des Systems.",
+	"While WIP Count tells how many items are in progress, Total WIP Age tells how long they have collectively been there.":                                   "Die WIP-Anzahl sagt, wie viele Elemente in Bearbeitung sind; das Gesamt-WIP-Alter sagt, wie lange sie zusammen schon dort sind.",
+	"A growing Total WIP Age means items are aging without being delivered — it is a leading indicator of delivery problems.":                                 "Ein wachsendes Gesamt-WIP-Alter bedeutet, dass Elemente altern, ohne geliefert zu werden — ein Frühindikator für Lieferprobleme.",
+	"Even with stable WIP count, Total WIP Age can grow if items stagnate.":                                                                                   "Auch bei stabiler WIP-Anzahl kann das Gesamt-WIP-Alter wachsen, wenn Elemente stagnieren.",
+	"Natural behavior: Total WIP Age grows by (WIP count x 1 day) per day when no items enter or exit.":                                                       "Natürliches Verhalten: Das Gesamt-WIP-Alter wächst pro Tag um (WIP-Anzahl x 1 Tag), wenn keine Elemente hinzukommen oder abgehen.",
+	"Average WIP Age is provided for convenience but is less informative — it can mask individual outliers and assumes nothing about the distribution shape.": "Das mittlere WIP-Alter wird der Vollständigkeit halber angegeben, ist aber weniger aussagekräftig — es kann einzelne Ausreißer verdecken und sagt nichts über die Form der Verteilung.",
+	"The XmR analysis on Total WIP Age is the most defensible signal — it detects process changes without distribution assumptions.":                          "Die XmR-Analyse des Gesamt-WIP-Alters ist das belastbarste Signal — sie erkennt Prozessänderungen ohne Verteilungsannahmen.",
+	"Positive Flow Debt (Arrivals > Departures) is a leading indicator of cycle time inflation.":                                                              "Positive Flow Debt (Zugänge > Abgänge) ist ein Frühindikator für steigende Durchlaufzeiten.",
+	"Zero or Negative Flow Debt indicates a stable or improving system throughput-to-workload ratio.":                                                         "Flow Debt von null oder darunter zeigt ein stabiles oder sich verbesserndes Verhältnis von Durchsatz zu Arbeitslast.",
+	"CFD (Cumulative Flow Diagram) provides a snapshot of work items by status and issue type.":                                                               "Das CFD (Cumulative Flow Diagram) zeigt die Arbeitselemente nach Status und Vorgangstyp.",
+	"The visualization agent should use this data to render a stacked area chart.":                                                                            "Der Visualisierungs-Agent sollte aus diesen Daten ein gestapeltes Flächendiagramm zeichnen.",
+	"Status Persistence EXCLUSIVELY analyzes items that have successfully finished ('delivered') to prevent active WIP from skewing historical norms.":        "Die Status-Verweildauer analysiert AUSSCHLIESSLICH erfolgreich abgeschlossene Elemente ('delivered'), damit laufendes WIP die historischen Normen nicht verzerrt.",
+	"Persistence stats (coin_toss, likely, etc.) measure INTERNAL residency time WITHIN one status. They ARE NOT end-to-end completion forecasts.":            "Die Verweildauer-Statistiken (coin_toss, likely usw.) messen die INTERNE Verweilzeit INNERHALB eines Status. Sie sind KEINE Prognosen der Gesamtfertigstellung.",
+	"Inner80 and IQR help distinguish between 'Stable Flow' and 'High Variance' bottlenecks.":                                                                 "Inner80 und IQR helfen, Engpässe mit 'Stable Flow' von solchen mit 'High Variance' zu unterscheiden.",
+	"Tier Summary aggregates performance by meta-workflow phase (Demand, Upstream, Downstream).":                                                              "Die Tier-Zusammenfassung fasst die Leistung je Meta-Workflow-Phase zusammen (Demand, Upstream, Downstream).",
+	"Flow efficiency counts only time in statuses with an 'active' or 'queue' role; 'coverage' says how much of the cycle time that is. Time spent blocked inside an active status still counts as active, so the figure is an upper bound. Compare 'by_tier' to see whether waiting happens before or after work starts.": "Die Flusseffizienz zählt nur Zeit in Status mit der Rolle 'active' oder 'queue'; 'coverage' gibt an, welchen Anteil der Durchlaufzeit das ausmacht. Blockierte Zeit in einem aktiven Status zählt weiterhin als aktiv, der Wert ist also eine Obergrenze. Vergleichen Sie 'by_tier', um zu sehen, ob vor oder nach Arbeitsbeginn gewartet wird.",
+	"'rework_rate' of a pair is the share of items that reached its 'from' status and were sent back. Compare 'reworked_p85' with 'first_time_right_p85' to show what rework costs in predictability, and follow up on the worst items with 'analyze_item_journey'.":                                                       "'rework_rate' eines Paars ist der Anteil der Elemente, die seinen 'from'-Status erreichten und zurückgeschickt wurden. Vergleichen Sie 'reworked_p85' mit 'first_time_right_p85', um zu zeigen, was Nacharbeit an Vorhersagbarkeit kostet, und gehen Sie den schlimmsten Elementen mit 'analyze_item_journey' nach.",
+	"Work item age is a point-in-time metric, NOT a range metric. This tool ignores the session window's Start and uses ONLY its End as the as-of date for in-flight items and age calculation. To analyse 'as of' a different date, set the session window's End via 'set_analysis_window'.":                              "Das Alter von Arbeitselementen ist eine Stichtagsmetrik, KEINE Zeitraummetrik. Dieses Tool ignoriert den Start des Sitzungsfensters und verwendet NUR dessen Ende als Stichtag für laufende Elemente und die Altersberechnung. Um zu einem anderen Stichtag zu analysieren, setzen Sie das Ende des Sitzungsfensters mit 'set_analysis_window'.",
+	"Items in 'Demand' or 'Finished' tiers are usually excluded from WIP Age unless explicitly requested.":                                                                                                                                                                                                                                                                      "Elemente in den Tiers 'Demand' oder 'Finished' werden beim WIP-Alter meist ausgeschlossen, sofern nicht ausdrücklich angefordert.",
+	"PercentileRelative helps identify which individual items are 'neglect' risks compared to historical performance.":                                                                                                                                                                                                                                                          "PercentileRelative hilft zu erkennen, welche einzelnen Elemente im Vergleich zur historischen Leistung zu verwahrlosen drohen ('neglect').",
+	"AgeSinceCommitment reflects time since the LAST commitment (resets on backflow to Demand/Upstream).":                                                                                                                                                                                                                                                                       "AgeSinceCommitment gibt die Zeit seit der LETZTEN Zusage an (wird bei Rückfluss nach Demand/Upstream zurückgesetzt).",
+	"The 'path' shows chronological flow, while 'residency' shows cumulative totals.":                                                                                                                                                                                                                                                                                           "'path' zeigt den chronologischen Fluss, 'residency' die kumulierten Summen.",
+	"Mermaid xy charts have no legend; each diagram's title names its bars and lines. Quote numbers from 'data', not values read off a diagram.":                                                                                                                                                                                                                                "Mermaid-xy-Diagramme haben keine Legende; der Titel jedes Diagramms benennt seine Balken und Linien. Zitieren Sie Zahlen aus 'data', nicht aus einem Diagramm abgelesene Werte.",
+	"Jira does not record when an item joined a fixVersion, so scope is dated by item creation. Items moved into the release late appear as early scope, and items moved out of it disappear from the whole history.":                                                                                                                                                           "Jira speichert nicht, wann ein Element einer fixVersion zugeordnet wurde, daher wird der Umfang nach dem Erstellungsdatum datiert. Spät ins Release verschobene Elemente erscheinen als früher Umfang, herausgenommene verschwinden aus der gesamten Historie.",
+	"The forecast assumes the item follows the remaining statuses in 'context.remaining_path' at historical residence times and visit rates. It does not know about blockers, priority changes, or rework; if the item is stuck, use 'analyze_item_journey' to see where its time went.":                                                                                        "Die Prognose nimmt an, dass das Element die restlichen Status in 'context.remaining_path' mit historischen Verweilzeiten und Besuchsraten durchläuft. Sie kennt keine Blocker, Prioritätswechsel oder Nacharbeit; steckt das Element fest, zeigt 'analyze_item_journey', wo seine Zeit geblieben ist.",
+	"Read the cone per line, not per point: P85 is the count delivered at least with 85% probability by that date, so plan on P85 and treat P50 as a coin toss. The cone widens with the horizon; re-run it as weeks pass instead of relying on far points. Work that arrives meanwhile is not subtracted.":                                                                     "Lesen Sie den Trichter je Linie, nicht je Punkt: P85 ist die Anzahl, die bis zu diesem Datum mit 85 % Wahrscheinlichkeit mindestens geliefert ist; planen Sie also mit P85 und betrachten Sie P50 als Münzwurf. Der Trichter weitet sich mit dem Horizont; wiederholen Sie den Lauf im Wochenverlauf, statt sich auf ferne Punkte zu verlassen. Zwischenzeitlich eintreffende Arbeit wird nicht abgezogen.",
+	"Sprint 'throughput' counts all items delivered during the sprint, including unplanned work; it is the sample used by sprint-mode forecasts.":                                                                                                                                                                                                                               "Der Sprint-'throughput' zählt alle im Sprint gelieferten Elemente einschließlich ungeplanter Arbeit; er ist die Stichprobe der Prognosen im Sprint-Modus.",
+	"Carry-over is measured at the sprint close. Items re-assigned to the next sprint count as carry-over in each sprint they were open at.":                                                                                                                                                                                                                                    "Der Übertrag wird beim Sprint-Abschluss gemessen. Elemente, die in den nächsten Sprint verschoben werden, zählen in jedem Sprint als Übertrag, in dem sie beim Abschluss offen waren.",
+	"Each source is projected with its own workflow mapping. Pass the same boards as 'sources' to forecast_monte_carlo, analyze_throughput, or analyze_work_item_age for program-level results.":                                                                                                                                                                                "Jede Quelle wird mit ihrem eigenen Workflow-Mapping ausgewertet. Übergeben Sie dieselben Boards als 'sources' an forecast_monte_carlo, analyze_throughput oder analyze_work_item_age, um Ergebnisse auf Programmebene zu erhalten.",
+	"Portfolio result: 'portfolio.shared_issues' counts the issues listed by several boards, and 'portfolio.duplicates' says how they were attributed ('first' and 'split' count each once, 'all' once per board). Per-board shares are in 'portfolio.sources'.":                                                                                                                "Portfolio-Ergebnis: 'portfolio.shared_issues' zählt die Vorgänge, die mehrere Boards führen, und 'portfolio.duplicates' gibt an, wie sie zugeordnet wurden ('first' und 'split' zählen jeden einmal, 'all' einmal je Board). Die Anteile je Board stehen in 'portfolio.sources'.",
+	"'outliers.items' lists the slowest delivered items, slowest first, with cycle time, its percentile, and blocked time. 'outliers.bands' holds P50/P85/P95 of the ranked measure: items far above P95 are exceptions, items near it are the usual tail. Follow up with 'analyze_item_journey' on their keys before naming causes; a summary alone does not explain a delay.": "'outliers.items' listet die langsamsten gelieferten Elemente, das langsamste zuerst, mit Durchlaufzeit, deren Perzentil und blockierter Zeit. 'outliers.bands' enthält P50/P85/P95 des gewählten Maßes: Elemente weit über P95 sind Ausnahmen, Elemente nahe daran der übliche Ausläufer. Untersuchen Sie die Keys mit 'analyze_item_journey', bevor Sie Ursachen nennen; eine Zusammenfassung allein erklärt keine Verzögerung.",
+	"Each abandoned item is placed in the last status it held before a Finished status. 'wasted_downstream_days' counts only the time in Downstream statuses, the capacity the team had committed; time in Demand and Upstream is the cost of deciding.":                                                                                                                        "Jedes abgebrochene Element wird dem letzten Status vor einem Finished-Status zugeordnet. 'wasted_downstream_days' zählt nur die Zeit in Downstream-Status, also die Kapazität, die das Team zugesagt hatte; Zeit in Demand und Upstream ist der Preis der Entscheidungsfindung.",
+	"A template fills only the parameters a forecast_monte_carlo call leaves out; include_wip and include_existing_backlog stay on once a template sets them. Name the template when presenting a forecast made with it, and confirm changes to a template with the user, since later forecasts inherit them.":                                                                  "Eine Vorlage füllt nur die Parameter, die ein Aufruf von forecast_monte_carlo weglässt; include_wip und include_existing_backlog bleiben aktiv, sobald eine Vorlage sie setzt. Nennen Sie die Vorlage, wenn Sie eine damit erstellte Prognose vorstellen, und stimmen Sie Änderungen an einer Vorlage mit dem Benutzer ab, da spätere Prognosen sie übernehmen.",
+	"WEEKLY BATCHING: Weekly throughput varies more than independent days imply ('batching.dispersion_ratio'), so good and slow days cluster in weeks. Run 'forecast_monte_carlo' with sampling_granularity=week; daily sampling would understate the spread of the forecast.":                                                                                                  "WÖCHENTLICHE BÜNDELUNG: Der Wochendurchsatz schwankt stärker, als unabhängige Tage erwarten lassen ('batching.dispersion_ratio'); gute und schwache Tage häufen sich also in Wochen. Führen Sie 'forecast_monte_carlo' mit sampling_granularity=week aus; tägliches Sampling würde die Streuung der Prognose unterschätzen.",
+	"Present 'recommended_actions' in rank order and quote each action's evidence. Do not substitute free-form advice for the ranked list.":                                                                                                                                                                                                                                     "Stellen Sie 'recommended_actions' in Rangfolge vor und nennen Sie die Belege jeder Maßnahme. Ersetzen Sie die Rangliste nicht durch freie Ratschläge.",
+
+	// Import guidance (handleImportProjects, handleImportBoards)
+	"Project located. If you plan to run analytical diagnostics (Aging, Simulations, Stability), you MUST find the project's boards using 'import_boards' next.": "Projekt gefunden. Wenn Sie Diagnosen (Alter, Simulationen, Stabilität) ausführen wollen, MÜSSEN Sie als Nächstes mit 'import_boards' die Boards des Projekts suchen.",
+	"Board located. You MUST now call 'import_board_context' to anchor on the data distribution and metadata before performing workflow discovery.":              "Board gefunden. Sie MÜSSEN jetzt 'import_board_context' aufrufen, um Datenverteilung und Metadaten zu erfassen, bevor Sie die Workflow-Erkennung durchführen.",
+	"This analysis uses the session analysis window (%s … %s). Adjust via 'set_analysis_window' or read it via 'get_analysis_window'.":                           "Diese Analyse verwendet das Analysefenster der Sitzung (%s … %s). Ändern Sie es mit 'set_analysis_window' oder lesen Sie es mit 'get_analysis_window'.",
+
+	// Data quality warnings (getQualityWarnings)
+	"DATA INTEGRITY WARNING: %d item(s) are missing their creation events. Cycle Times and Stability metrics for these items are based on the earliest recorded event, which likely understates their true age.": "WARNUNG DATENINTEGRITÄT: Bei %d Element(en) fehlt das Erstellungsereignis. Durchlaufzeiten und Stabilitätsmetriken dieser Elemente beruhen auf dem frühesten erfassten Ereignis und unterschätzen ihr wahres Alter wahrscheinlich.",
+	"SYSTEM PRESSURE WARNING: %.0f%% of your current WIP is currently flagged as blocked. This high level of impediment makes historical throughput a potentially over-optimistic proxy for the future.":         "WARNUNG SYSTEMDRUCK: %.0f %% des aktuellen WIP sind als blockiert markiert. Bei so vielen Hindernissen ist der historische Durchsatz ein möglicherweise zu optimistischer Maßstab für die Zukunft.",
+
+	// Simulation insights and warnings (simulation.Engine)
+	"Fat-Tail Warning (Ratio %.2f): Extreme outliers are in control of this process (Kanban heuristic >= 5.6). Your forecasts are high-risk.":                                                                              "Fat-Tail-Warnung (Verhältnis %.2f): Extreme Ausreißer bestimmen diesen Prozess (Kanban-Heuristik >= 5,6). Ihre Prognosen sind hochriskant.",
+	"Heavy-Tail Warning (Ratio %.2f): The process is highly volatile, indicating a significant risk of extreme delay (Volatility heuristic > 3).":                                                                          "Heavy-Tail-Warnung (Verhältnis %.2f): Der Prozess ist sehr volatil, es besteht ein erhebliches Risiko extremer Verzögerung (Volatilitätsheuristik > 3).",
+	"Significant throughput drop recently (%.0f%% below average). WIP may have increased or capacity dropped.":                                                                                                             "Deutlicher Durchsatzrückgang in letzter Zeit (%.0f %% unter dem Durchschnitt). Das WIP ist möglicherweise gestiegen oder die Kapazität gesunken.",
+	"Throughput is significantly higher recently (%.0f%% above average). Monitor if this is sustainable.":                                                                                                                  "Der Durchsatz ist in letzter Zeit deutlich höher (%.0f %% über dem Durchschnitt). Beobachten Sie, ob das nachhaltig ist.",
+	"Simulation based on a small sample size (%d items); results may have limited statistical significance.":                                                                                                               "Die Simulation beruht auf einer kleinen Stichprobe (%d Elemente); die Ergebnisse sind statistisch möglicherweise wenig aussagekräftig.",
+	"CAUTION: %d item(s) of type(s) [%s] have no delivery history and were excluded from the duration forecast. Their completion cannot be estimated.":                                                                     "ACHTUNG: %d Element(e) der Typen [%s] haben keine Lieferhistorie und wurden aus der Dauerprognose ausgeschlossen. Ihre Fertigstellung lässt sich nicht schätzen.",
+	"Volatility Alert: Item Type '%s' shows chaotic delivery patterns (Ratio %.2f). This type is the primary driver of forecast uncertainty.":                                                                              "Volatilitätsalarm: Der Typ '%s' zeigt chaotische Liefermuster (Verhältnis %.2f). Dieser Typ ist der Haupttreiber der Prognoseunsicherheit.",
+	"No historical throughput found for the selected criteria. The duration forecast is theoretically infinite based on current data.":                                                                                     "Für die gewählten Kriterien wurde kein historischer Durchsatz gefunden. Nach den aktuellen Daten ist die Dauerprognose theoretisch unendlich.",
+	"WARNING: Forecast exceeds 10 years. This usually indicates 'Throughput Collapse' due to overly restrictive filters (Issue Types or Resolutions).":                                                                     "WARNUNG: Die Prognose übersteigt 10 Jahre. Das deutet meist auf einen 'Throughput Collapse' durch zu enge Filter hin (Vorgangstypen oder Lösungen).",
+	"CAUTION: Low Outcome Density. %.1f%% of resolved items were excluded because they were not 'delivered' (e.g. abandoned). This may skew results if 'delivered' items are missing. Check your 'resolutions' parameter.": "ACHTUNG: Geringe Ergebnisdichte. %.1f %% der gelösten Elemente wurden ausgeschlossen, weil sie nicht 'delivered' waren (z. B. abgebrochen). Das kann die Ergebnisse verzerren, wenn 'delivered'-Elemente fehlen. Prüfen Sie den Parameter 'resolutions'.",
+	"WARNING: %d items (%d%%) were excluded because they fall outside the analysis time window. This suggests your time window is too narrow or the project is less active recently.":                                      "WARNUNG: %d Elemente (%d %%) wurden ausgeschlossen, weil sie außerhalb des Analysefensters liegen. Das Zeitfenster ist vermutlich zu eng, oder das Projekt war zuletzt weniger aktiv.",
+
+	// Response budget (mcp.budgetResult)
+	"TRUNCATED: the result exceeded the response budget of %d bytes, so %d list(s) were cut to %d items each (item lists keep their most extreme items); see context.truncation. Request specific slices instead — limit, offset, and sort_by on item lists, a narrower history window, or issue_types — or raise max_bytes for this call.": "GEKÜRZT: Das Ergebnis überschritt das Antwortbudget von %d Bytes, daher wurden %d Liste(n) auf je %d Elemente gekürzt (Elementlisten behalten ihre extremsten Elemente); siehe context.truncation. Fordern Sie stattdessen gezielte Ausschnitte an — limit, offset und sort_by bei Elementlisten, ein engeres Historienfenster oder issue_types — oder erhöhen Sie max_bytes für diesen Aufruf.",
+}
+
+// germanMonths abbreviates the month names of bucket labels ("Mar 2024").
+var germanMonths = map[string]string{
+	"Jan": "Jan", "Feb": "Feb", "Mar": "Mär", "Apr": "Apr", "May": "Mai", "Jun": "Jun",
+	"Jul": "Jul", "Aug": "Aug", "Sep": "Sep", "Oct": "Okt", "Nov": "Nov", "Dec": "Dez",
+}
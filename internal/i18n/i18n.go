@@ -0,0 +1,209 @@
+// Package i18n translates the human-facing texts of tool results — guidance,
+// insights, warnings, and percentile labels — into the configured locale.
+//
+// The catalog is keyed by the English text, as gettext is: the analytics keep
+// producing English, and results are translated once, at the response
+// boundary. Keys without fmt verbs are matched verbatim. A key with fmt verbs
+// (%d, %.1f, %s, ...) matches any text that fmt.Sprintf could have produced
+// from it; the formatted arguments are carried over verbatim into the verbs of
+// the translation, in order. Such translations write a literal percent as %%.
+package i18n
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Locale is a supported output language.
+type Locale string
+
+const (
+	// English is the language the server produces its texts in (default).
+	English Locale = "en"
+	// German translates the catalog into German.
+	German Locale = "de"
+)
+
+// catalogs holds the translations of each locale but English, keyed by the
+// English text.
+var catalogs = map[Locale]map[string]string{
+	German: german,
+}
+
+// months holds the month abbreviations of each locale but English, keyed by
+// the English abbreviation.
+var months = map[Locale]map[string]string{
+	German: germanMonths,
+}
+
+// monthLabel matches the label of a month bucket, e.g. "Mar 2024".
+var monthLabel = regexp.MustCompile(`^(Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec) (\d{4})$`)
+
+// ParseLocale validates a locale name. It accepts a language tag such as
+// "de", "de-DE", or "de_AT" and matches on the language; "" is English.
+func ParseLocale(s string) (Locale, error) {
+	lang, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(s)), "-")
+	lang, _, _ = strings.Cut(lang, "_")
+	switch l := Locale(lang); l {
+	case "":
+		return English, nil
+	case English, German:
+		return l, nil
+	}
+	return "", fmt.Errorf("unsupported locale %q: expected en or de", s)
+}
+
+// Translate returns msg in locale l. Texts the catalog of l does not cover,
+// and every text in English, are returned unchanged. Month bucket labels
+// ("Mar 2024") take the month abbreviation of l.
+func Translate(l Locale, msg string) string {
+	catalog, ok := catalogs[l]
+	if !ok || msg == "" {
+		return msg
+	}
+	if m := monthLabel.FindStringSubmatch(msg); m != nil {
+		if name, ok := months[l][m[1]]; ok {
+			return name + " " + m[2]
+		}
+		return msg
+	}
+	if t, ok := catalog[msg]; ok && verbCount(msg) == 0 {
+		return t
+	}
+	for _, p := range patternsOf(l) {
+		if args := p.match.FindStringSubmatch(msg); args != nil {
+			return p.fill(args[1:])
+		}
+	}
+	return msg
+}
+
+// jsonString matches a string literal of a JSON document.
+var jsonString = regexp.MustCompile(`"(?:[^"\\]|\\.)*"`)
+
+// TranslateJSON translates every string of a JSON document into locale l.
+// It rewrites the encoding in place, so the document keeps its field order.
+func TranslateJSON(l Locale, raw []byte) []byte {
+	if _, ok := catalogs[l]; !ok {
+		return raw
+	}
+	return jsonString.ReplaceAllFunc(raw, func(lit []byte) []byte {
+		var msg string
+		if err := json.Unmarshal(lit, &msg); err != nil {
+			return lit
+		}
+		t := Translate(l, msg)
+		if t == msg {
+			return lit
+		}
+		out, err := json.Marshal(t)
+		if err != nil {
+			return lit
+		}
+		return out
+	})
+}
+
+// Has reports whether the catalog of l covers msg verbatim. English covers
+// everything.
+func Has(l Locale, msg string) bool {
+	if l == English {
+		return true
+	}
+	_, ok := catalogs[l][msg]
+	return ok
+}
+
+// verb matches an fmt verb with optional argument index, flags, width, and
+// precision, or an escaped percent sign.
+var verb = regexp.MustCompile(`%%|%(?:\[(\d+)\])?[-+# 0]*\d*(?:\.\d+)?[vsdfgqxXeEtT]`)
+
+// pattern is a catalog entry with fmt verbs.
+type pattern struct {
+	match       *regexp.Regexp
+	translation string
+}
+
+var (
+	patternsMu sync.Mutex
+	patterns   = make(map[Locale][]pattern)
+)
+
+// patternsOf compiles the entries of the catalog of l that have fmt verbs,
+// once per locale, longest key first so specific texts win over generic ones.
+func patternsOf(l Locale) []pattern {
+	patternsMu.Lock()
+	defer patternsMu.Unlock()
+	if ps, ok := patterns[l]; ok {
+		return ps
+	}
+	var keys []string
+	for key := range catalogs[l] {
+		if verbCount(key) > 0 {
+			keys = append(keys, key)
+		}
+	}
+	slices.SortFunc(keys, func(a, b string) int {
+		if c := cmp.Compare(len(b), len(a)); c != 0 {
+			return c
+		}
+		return strings.Compare(a, b)
+	})
+	ps := make([]pattern, 0, len(keys))
+	for _, key := range keys {
+		var expr strings.Builder
+		expr.WriteString("^")
+		last := 0
+		for _, loc := range verb.FindAllStringIndex(key, -1) {
+			expr.WriteString(regexp.QuoteMeta(key[last:loc[0]]))
+			if key[loc[0]:loc[1]] == "%%" {
+				expr.WriteString("%")
+			} else {
+				expr.WriteString("(.*?)")
+			}
+			last = loc[1]
+		}
+		expr.WriteString(regexp.QuoteMeta(key[last:]) + "$")
+		ps = append(ps, pattern{match: regexp.MustCompile("(?s)" + expr.String()), translation: catalogs[l][key]})
+	}
+	patterns[l] = ps
+	return ps
+}
+
+// fill substitutes the formatted arguments of the English text for the verbs
+// of the translation: the n-th verb, or the one an explicit index names.
+func (p pattern) fill(args []string) string {
+	n := 0
+	return verb.ReplaceAllStringFunc(p.translation, func(v string) string {
+		if v == "%%" {
+			return "%"
+		}
+		i := n
+		if m := verb.FindStringSubmatch(v); m[1] != "" {
+			idx, _ := strconv.Atoi(m[1])
+			i = idx - 1
+		}
+		n++
+		if i < 0 || i >= len(args) {
+			return v
+		}
+		return args[i]
+	})
+}
+
+// verbCount returns the number of fmt verbs in s, not counting "%%".
+func verbCount(s string) int {
+	n := 0
+	for _, v := range verb.FindAllString(s, -1) {
+		if v != "%%" {
+			n++
+		}
+	}
+	return n
+}
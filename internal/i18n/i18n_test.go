@@ -0,0 +1,80 @@
+package i18n
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestParseLocale(t *testing.T) {
+	for in, want := range map[string]Locale{"": English, "en": English, "EN-us": English, "de": German, "de-DE": German, " de_AT ": German} {
+		if got, err := ParseLocale(in); err != nil || got != want {
+			t.Errorf("ParseLocale(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseLocale("fr"); err == nil {
+		t.Error("Expected an error for an unsupported locale")
+	}
+}
+
+func TestTranslate(t *testing.T) {
+	if got := Translate(German, "P70 (Probable)"); got != "P70 (Wahrscheinlich)" {
+		t.Errorf("Expected the verbatim translation, got %q", got)
+	}
+	if got := Translate(English, "P70 (Probable)"); got != "P70 (Probable)" {
+		t.Errorf("Expected English to pass through, got %q", got)
+	}
+	if got := Translate(German, "Not in the catalog."); got != "Not in the catalog." {
+		t.Errorf("Expected an unknown text to pass through, got %q", got)
+	}
+
+	msg := fmt.Sprintf("Significant throughput drop recently (%.0f%% below average). WIP may have increased or capacity dropped.", 42.0)
+	want := "Deutlicher Durchsatzrückgang in letzter Zeit (42 % unter dem Durchschnitt). Das WIP ist möglicherweise gestiegen oder die Kapazität gesunken."
+	if got := Translate(German, msg); got != want {
+		t.Errorf("Expected the arguments to carry over:\n got %q\nwant %q", got, want)
+	}
+	msg = fmt.Sprintf("WARNING: %d items (%d%%) were excluded because they fall outside the analysis time window. This suggests your time window is too narrow or the project is less active recently.", 12, 30)
+	if got := Translate(German, msg); got[:33] != "WARNUNG: 12 Elemente (30 %) wurde" {
+		t.Errorf("Expected both arguments to carry over, got %q", got)
+	}
+
+	if got := Translate(German, "Mar 2024"); got != "Mär 2024" {
+		t.Errorf("Expected a German month label, got %q", got)
+	}
+	if got := Translate(German, "May the fourth"); got != "May the fourth" {
+		t.Errorf("Expected only month labels to be translated, got %q", got)
+	}
+}
+
+// Every translation of a text with arguments must take the same arguments;
+// texts without arguments are translated verbatim.
+func TestGermanCatalog(t *testing.T) {
+	for key, tr := range german {
+		if n := verbCount(key); n == 0 {
+			if Translate(German, key) != tr {
+				t.Errorf("%q does not translate verbatim", key)
+			}
+		} else if n != verbCount(tr) {
+			t.Errorf("%q: %d verbs in the key, %d in the translation", key, n, verbCount(tr))
+		}
+	}
+}
+
+func TestTranslateJSON(t *testing.T) {
+	raw := []byte(`{"status":"In Progress","labels":{"probable":"P70 (Probable)"},"_guidance":["Total WIP Age reveals the cumulative age burden on the system."]}`)
+	out := TranslateJSON(German, raw)
+	var got struct {
+		Status   string            `json:"status"`
+		Labels   map[string]string `json:"labels"`
+		Guidance []string          `json:"_guidance"`
+	}
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("Expected valid JSON, got %s: %v", out, err)
+	}
+	if got.Status != "In Progress" || got.Labels["probable"] != "P70 (Wahrscheinlich)" || got.Guidance[0] != german["Total WIP Age reveals the cumulative age burden on the system."] {
+		t.Errorf("Unexpected translation: %s", out)
+	}
+	if string(TranslateJSON(English, raw)) != string(raw) {
+		t.Error("Expected English to leave the document unchanged")
+	}
+}
@@ -4,6 +4,7 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
 	"strings"
 	"time"
@@ -28,13 +29,52 @@ type Issue struct {
 	StatusCategory    string           // Name of the status category
 	StatusResidency   map[string]int64 // Seconds spent in each status
 	BlockedResidency  map[string]int64 // Total seconds spent in 'Blocked' state per status
+	FieldResidency    map[string]int64 // Seconds per status ID from the time-in-status field, nil if not fetched
 	Transitions       []StatusTransition
 	IsSubtask         bool
+	HierarchyLevel    int // Level of the issue type in the Jira hierarchy: 1 for epics, 2+ above; 0 for standard items and when not reported
 	IsMoved           bool
 	Flagged           string
 	HasSyntheticBirth bool       // True if birth date was inferred from earliest event
+	HistoryTruncated  bool       // True if Jira returned only part of the changelog
 	Outcome           string     // Empty if not finished, else it's 'delivered' or 'abandoned'
 	OutcomeDate       *time.Time // The time when the issue was delivered or abandoned
+	Components        []string   // Names of the components assigned to the issue
+	Labels            []string   // Labels assigned to the issue
+	ParentKey         string     // Key of the hierarchy parent (typically the Epic), empty if none
+	FixVersions       []FixVersion
+	Estimate          *float64 // Value of the estimation field (e.g. story points), nil if unestimated or not fetched
+	Assignee          string   // Display name of the assignee, empty if unassigned
+	Team              string   // Value of the team field, empty if unset or not fetched
+	FormerKeys        []string // Keys the issue had before it was moved between projects, oldest first
+}
+
+// FixVersion is a Jira release (version) an issue is assigned to.
+// The same shape is used in the search response and in the event log.
+type FixVersion struct {
+	Name        string `json:"name"`
+	Released    bool   `json:"released,omitempty"`
+	ReleaseDate string `json:"releaseDate,omitempty"` // YYYY-MM-DD; empty if not scheduled
+}
+
+// Sprint is a Scrum sprint on a board together with the issues assigned to it.
+type Sprint struct {
+	ID        int
+	Name      string
+	State     string     // "active" or "closed"
+	Start     time.Time  // Actual start
+	End       time.Time  // Planned end
+	Complete  *time.Time // Actual close; nil while active
+	IssueKeys []string   // Issues assigned to the sprint, including carry-over
+}
+
+// BoardColumn is a column of a board and the statuses it shows, in the order
+// of the board's column configuration.
+type BoardColumn struct {
+	Name      string   `json:"name"`
+	StatusIDs []string `json:"statusIds"`
+	Min       int      `json:"min,omitempty"` // Column constraint, 0 if none
+	Max       int      `json:"max,omitempty"`
 }
 
 // SourceContext formalizes the analytical "Center of Gravity" for a tool call.
@@ -42,6 +82,7 @@ type SourceContext struct {
 	ProjectKey string
 	BoardID    int
 	JQL        string
+	Filter     IssueFilter // Labels and components the JQL is narrowed to, re-applied to the event log
 	FetchedAt  time.Time
 }
 
@@ -133,16 +174,17 @@ func (nr *NameRegistry) GetResolutionName(id string) string {
 
 // Client is the interface for interacting with Jira.
 type Client interface {
-	SearchIssues(jql string, startAt int, maxResults int) (*SearchResponse, error)
-	GetIssueWithHistory(key string) (*IssueDTO, error)
-	GetProject(key string) (any, error)
-	GetProjectStatuses(key string) (any, error)
-	GetBoard(id int) (any, error)
-	GetBoardConfig(id int) (any, error)
-	GetFilter(id string) (any, error)
-	FindProjects(query string) ([]any, error)
-	FindBoards(projectKey string, nameFilter string) ([]any, error)
-	GetRegistry(projectKey string) (*NameRegistry, error)
+	SearchIssues(ctx context.Context, jql string, startAt int, maxResults int) (*SearchResponse, error)
+	GetIssueWithHistory(ctx context.Context, key string) (*IssueDTO, error)
+	GetProject(ctx context.Context, key string) (any, error)
+	GetProjectStatuses(ctx context.Context, key string) (any, error)
+	GetBoard(ctx context.Context, id int) (any, error)
+	GetBoardConfig(ctx context.Context, id int) (any, error)
+	GetFilter(ctx context.Context, id string) (any, error)
+	FindProjects(ctx context.Context, query string) ([]any, error)
+	FindBoards(ctx context.Context, projectKey string, nameFilter string) ([]any, error)
+	GetRegistry(ctx context.Context, projectKey string) (*NameRegistry, error)
+	GetSprints(ctx context.Context, boardID int, limit int) ([]Sprint, error)
 }
 
 // Config holds the authentication and connection settings for Jira.
@@ -156,15 +198,51 @@ type Config struct {
 
 	// Token Authentication
 	Token     string
-	TokenType string // "pat" or "api"
+	TokenType string // "pat", "api", or "oauth"
 	UserEmail string // Required for Jira Cloud (api token)
 
+	// OAuth 2.0 (3LO) app and token storage, used with TokenType "oauth"
+	OAuth OAuthConfig
+
 	// Load Balancer Cookies
 	GCILB string
 	GCLB  string
 
 	// Performance Settings
 	RequestDelay time.Duration
+
+	// Throttling Resilience
+	RequestsPerMinute int           // Cap across all requests; 0 disables the limiter
+	MaxRetries        int           // Retries of a 429/502/503/504 response; 0 disables retrying
+	RetryBaseDelay    time.Duration // First backoff step, doubled per retry
+
+	// IncludeSubtasks keeps sub-tasks in searches; by default every search excludes them.
+	IncludeSubtasks bool
+
+	// EstimateField is the ID of the custom field holding estimates (e.g.
+	// customfield_10016 for story points); fetched with every issue when set.
+	EstimateField string
+
+	// TimeInStatusField is the ID of a custom field holding time in status as
+	// kept by Jira or a marketplace app; fetched with every issue when set, to
+	// reconcile it with the residency derived from the changelog.
+	TimeInStatusField string
+
+	// TeamField is the ID of the custom field naming the team of an issue
+	// (e.g. customfield_10001 for Atlassian Teams); fetched with every issue
+	// when set, to segment throughput and cycle time by team.
+	TeamField string
+
+	// FetchAssignee fetches the display name of the assignee with every issue,
+	// to segment by assignee. Off by default: assignees are personal data.
+	FetchAssignee bool
+}
+
+// IsCloud reports whether the configuration targets Jira Cloud (API token or
+// OAuth) rather than Jira Data Center.
+func (c Config) IsCloud() bool {
+	t := strings.ToLower(c.TokenType)
+	return t == "api" || t == TokenTypeOAuth
 }
 
 // NewClient creates a new Jira client based on the provided configuration.
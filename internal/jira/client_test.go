@@ -1,6 +1,14 @@
 package jira
 
-import "testing"
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
 
 func TestNameRegistry_GetStatusName(t *testing.T) {
 	nr := &NameRegistry{
@@ -119,3 +127,135 @@ func TestNameRegistry_GetResolutionID(t *testing.T) {
 		}
 	}
 }
+
+func TestSearchIssues_FetchesEstimateField(t *testing.T) {
+	var fields string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fields = r.URL.Query().Get("fields")
+		_, _ = w.Write([]byte(`{"total": 3, "issues": [
+			{"key": "PROJ-1", "fields": {"customfield_10016": 5}},
+			{"key": "PROJ-2", "fields": {"customfield_10016": "2.5"}},
+			{"key": "PROJ-3", "fields": {"customfield_10016": null}}
+		]}`))
+	}))
+	t.Cleanup(srv.Close)
+	c := NewDataCenterClient(Config{BaseURL: srv.URL, EstimateField: "customfield_10016"})
+
+	resp, err := c.SearchIssues(context.Background(), "project = PROJ", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasSuffix(fields, ",customfield_10016") {
+		t.Errorf("Expected the estimation field to be requested, got %q", fields)
+	}
+	var got []any
+	for _, issue := range resp.Issues {
+		if issue.Fields.Estimate == nil {
+			got = append(got, nil)
+		} else {
+			got = append(got, *issue.Fields.Estimate)
+		}
+	}
+	if len(got) != 3 || got[0] != 5.0 || got[1] != 2.5 || got[2] != nil {
+		t.Errorf("Expected estimates [5 2.5 <nil>], got %v", got)
+	}
+}
+
+func TestSearchIssues_RepairsTruncatedChangelog(t *testing.T) {
+	histories := func(n int) string {
+		entries := make([]string, n)
+		for i := range entries {
+			entries[i] = `{"created": "2024-01-01T10:00:00.000+0000", "items": []}`
+		}
+		return "[" + strings.Join(entries, ",") + "]"
+	}
+	var paths []string
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/rest/api/2/search":
+			_, _ = w.Write([]byte(`{"total": 2, "issues": [
+				{"key": "PROJ-1", "fields": {}, "changelog": {"maxResults": 100, "total": 120, "histories": ` + histories(100) + `}},
+				{"key": "PROJ-2", "fields": {}, "changelog": {"maxResults": 100, "total": 120, "histories": ` + histories(100) + `}}
+			]}`))
+		case "/rest/api/2/issue/PROJ-1":
+			_, _ = w.Write([]byte(`{"key": "PROJ-1", "fields": {}, "changelog": {"maxResults": 120, "total": 120, "histories": ` + histories(120) + `}}`))
+		case "/rest/api/2/issue/PROJ-2":
+			_, _ = w.Write([]byte(`{"key": "PROJ-2", "fields": {}, "changelog": {"maxResults": 100, "total": 120, "histories": ` + histories(100) + `}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}, 0)
+
+	resp, err := c.SearchIssues(context.Background(), "project = PROJ", 0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(paths, "/rest/api/2/issue/PROJ-1/changelog") {
+		t.Errorf("Expected the changelog endpoint to be tried first, got %v", paths)
+	}
+	if got := resp.Issues[0].Changelog; got.IsTruncated() || len(got.Histories) != 120 {
+		t.Errorf("Expected the full changelog from the issue endpoint, got %d of %d", len(got.Histories), got.Total)
+	}
+	if got := resp.Issues[1].Changelog; !got.IsTruncated() {
+		t.Error("Expected an unrepairable changelog to stay marked as truncated")
+	}
+}
+
+func TestFieldsDTO_TimeInStatusField(t *testing.T) {
+	f := FieldsDTO{Custom: map[string]json.RawMessage{
+		"customfield_10020": json.RawMessage(`"1_*:*_1_*:*_86400000_*|*_3_*:*_2_*:*_7200500_*|*_bad"`),
+		"customfield_10021": json.RawMessage(`null`),
+	}}
+
+	got, ok := f.TimeInStatusField("customfield_10020")
+	if !ok || len(got) != 2 || got["1"] != 86400 || got["3"] != 7200 {
+		t.Errorf("Expected 1 day in status 1 and 2 hours in status 3, got %v", got)
+	}
+	if _, ok := f.TimeInStatusField("customfield_10021"); ok {
+		t.Error("Expected an empty field to yield nothing")
+	}
+}
+
+func TestFieldsDTO_TextField(t *testing.T) {
+	f := FieldsDTO{Custom: map[string]json.RawMessage{
+		"customfield_1": json.RawMessage(`"Payments"`),
+		"customfield_2": json.RawMessage(`{"id":"10","value":"Checkout"}`),
+		"customfield_3": json.RawMessage(`[{"name":"Search"},{"name":"Ads"}]`),
+		"customfield_4": json.RawMessage(`null`),
+	}}
+
+	for id, want := range map[string]string{"customfield_1": "Payments", "customfield_2": "Checkout", "customfield_3": "Search"} {
+		if got, ok := f.TextField(id); !ok || got != want {
+			t.Errorf("%s: expected %q, got %q", id, want, got)
+		}
+	}
+	if _, ok := f.TextField("customfield_4"); ok {
+		t.Error("Expected an empty field to yield nothing")
+	}
+}
+
+func TestBoardColumns(t *testing.T) {
+	var config any
+	raw := `{"columnConfig":{"columns":[
+		{"name":"Backlog","statuses":[{"id":"1"}]},
+		{"name":"In Progress","statuses":[{"id":"2"},{"id":"3"}],"max":5},
+		{"name":"Unmapped","statuses":[]},
+		{"name":"Done","statuses":[{"id":"5"}]}
+	]}}`
+	if err := json.Unmarshal([]byte(raw), &config); err != nil {
+		t.Fatal(err)
+	}
+
+	got := BoardColumns(config)
+
+	if len(got) != 3 {
+		t.Fatalf("Expected the empty column to be skipped, got %+v", got)
+	}
+	if c := got[1]; c.Name != "In Progress" || len(c.StatusIDs) != 2 || c.StatusIDs[1] != "3" || c.Max != 5 {
+		t.Errorf("Expected In Progress to show statuses 2 and 3 with a max of 5, got %+v", c)
+	}
+	if BoardColumns(nil) != nil {
+		t.Error("Expected no columns without a configuration")
+	}
+}
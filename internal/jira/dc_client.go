@@ -1,7 +1,9 @@
 package jira
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -14,9 +16,16 @@ import (
 
 type dcClient struct {
 	cfg         Config
+	apiBase     string // BaseURL, or the Atlassian API gateway of the site with OAuth
 	httpClient  *http.Client
 	lastRequest time.Time
 
+	// Rate Limiter (see retry.go)
+	limiterMu sync.Mutex
+	nextSlot  time.Time
+	now       func() time.Time
+	sleep     func(context.Context, time.Duration) error
+
 	// Session Cache
 	cache      map[string]*cacheEntry
 	cacheMutex sync.RWMutex
@@ -38,13 +47,25 @@ func NewDataCenterClient(cfg Config) Client {
 	if cfg.RequestDelay == 0 {
 		cfg.RequestDelay = 10 * time.Second
 	}
-	return &dcClient{
-		cfg: cfg,
+	if cfg.RetryBaseDelay == 0 {
+		cfg.RetryBaseDelay = 2 * time.Second
+	}
+	c := &dcClient{
+		cfg:     cfg,
+		apiBase: strings.TrimSuffix(cfg.BaseURL, "/"),
 		httpClient: &http.Client{
 			Timeout: 90 * time.Second,
 		},
+		now:   time.Now,
+		sleep: sleepContext,
 		cache: make(map[string]*cacheEntry),
 	}
+	// OAuth requests are authorized by the transport, which refreshes the
+	// access token, and go through the API gateway of the Cloud site.
+	if strings.ToLower(cfg.TokenType) == TokenTypeOAuth {
+		c.httpClient.Transport, c.apiBase = newOAuthTransport(cfg.OAuth)
+	}
+	return c
 }
 
 func (c *dcClient) getFromCache(key string) (any, bool) {
@@ -86,7 +107,7 @@ func (c *dcClient) addToCache(key string, value any, ttl time.Duration) {
 	log.Debug().Str("key", key).Dur("ttl", ttl).Msg("Added to cache")
 }
 
-func (c *dcClient) throttle(isMetadata bool) {
+func (c *dcClient) throttle(ctx context.Context, isMetadata bool) {
 	// Metadata requests (Board, Config, Project) are allowed to "burst" sequentially
 	// to avoid artificial delay during the setup phase.
 	if isMetadata {
@@ -117,7 +138,7 @@ func (c *dcClient) throttle(isMetadata bool) {
 		}
 
 		if wait > 0 {
-			time.Sleep(wait)
+			_ = c.sleep(ctx, wait) // A cancelled ctx fails the request that follows
 		}
 	}
 	c.lastRequest = time.Now()
@@ -167,57 +188,92 @@ func (c *dcClient) authenticateRequest(req *http.Request) {
 func (c *dcClient) restPath(apiVersion string, resourcePath string) string {
 	// If apiVersion is empty, we detect the appropriate default
 	if apiVersion == "" {
-		if strings.ToLower(c.cfg.TokenType) == "api" {
+		if c.cfg.IsCloud() {
 			apiVersion = "3" // Jira Cloud: v3 is the current version
 		} else {
 			apiVersion = "2" // Jira Data Center: v2 is standard
 		}
 	}
 
-	return fmt.Sprintf("%s/rest/api/%s/%s", strings.TrimSuffix(c.cfg.BaseURL, "/"), apiVersion, strings.TrimPrefix(resourcePath, "/"))
+	return fmt.Sprintf("%s/rest/api/%s/%s", c.apiBase, apiVersion, strings.TrimPrefix(resourcePath, "/"))
 }
 
 func (c *dcClient) agilePath(resourcePath string) string {
-	return fmt.Sprintf("%s/rest/agile/1.0/%s", strings.TrimSuffix(c.cfg.BaseURL, "/"), strings.TrimPrefix(resourcePath, "/"))
+	return fmt.Sprintf("%s/rest/agile/1.0/%s", c.apiBase, strings.TrimPrefix(resourcePath, "/"))
 }
 
 func (c *dcClient) excludeSubTasks(jql string) string {
-	if jql == "" {
-		return "issuetype not in subTaskIssueTypes()"
+	// Inject the exclusion before the ORDER BY clause
+	query, orderBy := SplitOrderBy(jql)
+	if strings.TrimSpace(query) == "" {
+		return strings.TrimSpace(NotSubTask + " " + strings.TrimSpace(orderBy))
 	}
+	return AndJQL(query, NotSubTask) + orderBy
+}
 
-	// Find the ORDER BY clause to inject the exclusion before it
-	orderByIdx := strings.Index(strings.ToUpper(jql), " ORDER BY ")
-	if orderByIdx != -1 {
-		originalJQL := jql[:orderByIdx]
-		orderByClause := jql[orderByIdx:]
-		return fmt.Sprintf("(%s) AND issuetype not in subTaskIssueTypes()%s", originalJQL, orderByClause)
+// issueFields lists the issue fields requested from Jira, with the configured
+// estimation field if any.
+func (c *dcClient) issueFields() string {
+	fields := "summary,issuetype,status,resolution,resolutiondate,created,updated," + FlaggedField + ",components,labels,parent,fixVersions"
+	if c.cfg.EstimateField != "" {
+		fields += "," + c.cfg.EstimateField
+	}
+	if c.cfg.TimeInStatusField != "" {
+		fields += "," + c.cfg.TimeInStatusField
+	}
+	if c.cfg.TeamField != "" {
+		fields += "," + c.cfg.TeamField
+	}
+	if c.cfg.FetchAssignee {
+		fields += ",assignee"
 	}
+	return fields
+}
 
-	return fmt.Sprintf("(%s) AND issuetype not in subTaskIssueTypes()", jql)
+// fillCustomFields reads the configured estimation field into Fields.Estimate,
+// the time-in-status field into Fields.TimeInStatus, and the team field into
+// Fields.Team.
+func (c *dcClient) fillCustomFields(dto *IssueDTO) {
+	if c.cfg.EstimateField != "" {
+		if n, ok := dto.Fields.NumberField(c.cfg.EstimateField); ok {
+			dto.Fields.Estimate = &n
+		}
+	}
+	if c.cfg.TimeInStatusField != "" {
+		if residency, ok := dto.Fields.TimeInStatusField(c.cfg.TimeInStatusField); ok {
+			dto.Fields.TimeInStatus = residency
+		}
+	}
+	if c.cfg.TeamField != "" {
+		if team, ok := dto.Fields.TextField(c.cfg.TeamField); ok {
+			dto.Fields.Team = team
+		}
+	}
 }
 
-func (c *dcClient) SearchIssues(jql string, startAt int, maxResults int) (*SearchResponse, error) {
-	return c.searchInternal(jql, startAt, maxResults, "changelog")
+func (c *dcClient) SearchIssues(ctx context.Context, jql string, startAt int, maxResults int) (*SearchResponse, error) {
+	return c.searchInternal(ctx, jql, startAt, maxResults, "changelog")
 }
 
-func (c *dcClient) searchInternal(jql string, startAt int, maxResults int, expand string) (*SearchResponse, error) {
+func (c *dcClient) searchInternal(ctx context.Context, jql string, startAt int, maxResults int, expand string) (*SearchResponse, error) {
 	cacheKey := fmt.Sprintf("search:%s:%d:%d:%s", jql, startAt, maxResults, expand)
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val.(*SearchResponse), nil
 	}
 
-	c.throttle(false)
+	c.throttle(ctx, false)
 
 	// Automatically exclude sub-tasks to avoid noise in analysis
-	jql = c.excludeSubTasks(jql)
+	if !c.cfg.IncludeSubtasks {
+		jql = c.excludeSubTasks(jql)
+	}
 
 	// Use url.Values for better query param handling
 	params := url.Values{}
 	params.Set("jql", jql)
 	params.Set("startAt", fmt.Sprintf("%d", startAt))
 	params.Set("maxResults", fmt.Sprintf("%d", maxResults))
-	params.Set("fields", "issuetype,status,resolution,resolutiondate,created,updated,customfield_10014")
+	params.Set("fields", c.issueFields())
 	if expand != "" {
 		params.Set("expand", expand)
 	}
@@ -225,20 +281,20 @@ func (c *dcClient) searchInternal(jql string, startAt int, maxResults int, expan
 	// Use restPath() - defaults to v2 for DC, v3 for Cloud.
 	// Jira Cloud recently migrated search to /search/jql
 	resourcePath := "search"
-	if strings.ToLower(c.cfg.TokenType) == "api" {
+	if c.cfg.IsCloud() {
 		resourcePath = "search/jql"
 	}
 	searchURL := c.restPath("", resourcePath) + "?" + params.Encode()
 	log.Info().Msg("Requesting issues from Jira")
 	log.Debug().Str("url", searchURL).Str("jql", jql).Msg("Jira search details")
-	req, err := http.NewRequest("GET", searchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -251,9 +307,9 @@ func (c *dcClient) searchInternal(jql string, startAt int, maxResults int, expan
 		case http.StatusTooManyRequests:
 			retryAfter := resp.Header.Get("Retry-After")
 			if retryAfter != "" {
-				return nil, fmt.Errorf("jira rate limit exceeded (429); retry after %s seconds", retryAfter)
+				return nil, fmt.Errorf("jira rate limit exceeded (429) after %d retries; retry after %s seconds", c.cfg.MaxRetries, retryAfter)
 			}
-			return nil, fmt.Errorf("jira rate limit exceeded (429)")
+			return nil, fmt.Errorf("jira rate limit exceeded (429) after %d retries; lower JIRA_REQUESTS_PER_MINUTE", c.cfg.MaxRetries)
 		default:
 			return nil, fmt.Errorf("jira API returned status %d; check jira availability", resp.StatusCode)
 		}
@@ -269,19 +325,8 @@ func (c *dcClient) searchInternal(jql string, startAt int, maxResults int, expan
 	// guaranteed, so we discard it and replace it with a full paginated fetch.
 	for i := range result.Issues {
 		dto := &result.Issues[i]
-		if dto.Changelog != nil && dto.Changelog.MaxResults > 0 && dto.Changelog.MaxResults < dto.Changelog.Total {
-			log.Warn().
-				Str("key", dto.Key).
-				Int("maxResults", dto.Changelog.MaxResults).
-				Int("total", dto.Changelog.Total).
-				Msg("Embedded changelog truncated; fetching full changelog")
-			full, err := c.fetchFullChangelog(dto.Key)
-			if err != nil {
-				log.Error().Err(err).Str("key", dto.Key).Msg("Failed to fetch full changelog; proceeding with truncated data")
-			} else {
-				dto.Changelog = full
-			}
-		}
+		c.fillCustomFields(dto)
+		c.repairChangelog(ctx, dto, true)
 	}
 
 	c.addToCache(cacheKey, &result, 10*time.Minute)
@@ -289,23 +334,23 @@ func (c *dcClient) searchInternal(jql string, startAt int, maxResults int, expan
 	return &result, nil
 }
 
-func (c *dcClient) GetIssueWithHistory(key string) (*IssueDTO, error) {
+func (c *dcClient) GetIssueWithHistory(ctx context.Context, key string) (*IssueDTO, error) {
 	cacheKey := "issue:" + key
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val.(*IssueDTO), nil
 	}
 
-	c.throttle(true) // Treat as metadata/lightweight
+	c.throttle(ctx, true) // Treat as metadata/lightweight
 
-	issueURL := c.restPath("", fmt.Sprintf("issue/%s", key)) + "?expand=changelog&fields=issuetype,status,resolution,resolutiondate,created,updated,customfield_10014"
-	req, err := http.NewRequest("GET", issueURL, nil)
+	issueURL := c.restPath("", fmt.Sprintf("issue/%s", key)) + "?expand=changelog&fields=" + c.issueFields()
+	req, err := http.NewRequestWithContext(ctx, "GET", issueURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -322,28 +367,87 @@ func (c *dcClient) GetIssueWithHistory(key string) (*IssueDTO, error) {
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, fmt.Errorf("failed to decode issue response: %w", err)
 	}
+	c.fillCustomFields(&result)
 
 	// Truncation repair: same as in searchInternal — discard and replace any capped
 	// embedded changelog before caching the IssueDTO.
-	if result.Changelog != nil && result.Changelog.MaxResults > 0 && result.Changelog.MaxResults < result.Changelog.Total {
-		log.Warn().
-			Str("key", key).
-			Int("maxResults", result.Changelog.MaxResults).
-			Int("total", result.Changelog.Total).
-			Msg("Embedded changelog truncated; fetching full changelog")
-		full, err := c.fetchFullChangelog(key)
-		if err != nil {
-			log.Error().Err(err).Str("key", key).Msg("Failed to fetch full changelog; proceeding with truncated data")
-		} else {
-			result.Changelog = full
-		}
-	}
+	c.repairChangelog(ctx, &result, false)
 
 	c.addToCache(cacheKey, &result, 10*time.Minute)
 
 	return &result, nil
 }
 
+// repairChangelog replaces a truncated embedded changelog of dto with the full
+// one from the dedicated changelog endpoint. Data Center versions without that
+// endpoint answer 404; for changelogs embedded in a search, the issue endpoint
+// with expand=changelog is tried next, since Data Center does not cap it the
+// way it caps search results. When every attempt fails, dto keeps the
+// truncated changelog, which the event log reports as a truncated history.
+func (c *dcClient) repairChangelog(ctx context.Context, dto *IssueDTO, fromSearch bool) {
+	if !dto.Changelog.IsTruncated() {
+		return
+	}
+	log.Warn().
+		Str("key", dto.Key).
+		Int("maxResults", dto.Changelog.MaxResults).
+		Int("total", dto.Changelog.Total).
+		Msg("Embedded changelog truncated; fetching full changelog")
+
+	full, err := c.fetchFullChangelog(ctx, dto.Key)
+	if errors.Is(err, errNoChangelogEndpoint) && fromSearch {
+		log.Debug().Str("key", dto.Key).Msg("No changelog endpoint; falling back to the issue endpoint")
+		full, err = c.fetchIssueChangelog(ctx, dto.Key)
+	}
+	if err != nil {
+		log.Error().Err(err).Str("key", dto.Key).Msg("Failed to fetch full changelog; proceeding with truncated data")
+		return
+	}
+	dto.Changelog = full
+}
+
+// errNoChangelogEndpoint is returned by fetchFullChangelog when the server does
+// not offer the dedicated changelog endpoint.
+var errNoChangelogEndpoint = errors.New("changelog endpoint not available")
+
+// fetchIssueChangelog retrieves the changelog embedded in the single-issue
+// response. It fails when that changelog is truncated as well.
+func (c *dcClient) fetchIssueChangelog(ctx context.Context, key string) (*ChangelogDTO, error) {
+	c.throttle(ctx, false)
+
+	issueURL := c.restPath("", fmt.Sprintf("issue/%s", key)) + "?expand=changelog&fields=status"
+	req, err := http.NewRequestWithContext(ctx, "GET", issueURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build issue request for %s: %w", key, err)
+	}
+	c.authenticateRequest(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return nil, fmt.Errorf("issue request failed for %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("jira API returned status %d for issue %s", resp.StatusCode, key)
+	}
+
+	var issue IssueDTO
+	if err := json.NewDecoder(resp.Body).Decode(&issue); err != nil {
+		return nil, fmt.Errorf("failed to decode issue response for %s: %w", key, err)
+	}
+	if issue.Changelog == nil {
+		return nil, fmt.Errorf("issue response for %s carries no changelog", key)
+	}
+	if issue.Changelog.IsTruncated() {
+		return nil, fmt.Errorf("issue changelog for %s truncated as well (%d of %d entries)", key, issue.Changelog.MaxResults, issue.Changelog.Total)
+	}
+
+	log.Info().Str("key", key).Int("total", len(issue.Changelog.Histories)).Msg("Fetched full changelog from the issue endpoint")
+
+	return issue.Changelog, nil
+}
+
 // fetchFullChangelog retrieves the complete changelog for an issue by paginating
 // through the dedicated Jira changelog endpoint. It is called transparently when
 // the embedded changelog returned by the search or issue endpoint is detected as
@@ -353,13 +457,13 @@ func (c *dcClient) GetIssueWithHistory(key string) (*IssueDTO, error) {
 // Both Jira Cloud (v3, response key: "values") and Jira DC (v2, response key: "histories")
 // expose this endpoint at the same resource path; restPath() selects the correct version,
 // and ChangelogDTO.UnmarshalJSON normalises the response key difference transparently.
-func (c *dcClient) fetchFullChangelog(key string) (*ChangelogDTO, error) {
+func (c *dcClient) fetchFullChangelog(ctx context.Context, key string) (*ChangelogDTO, error) {
 	const pageSize = 100
 	var allHistories []HistoryDTO
 	startAt := 0
 
 	for {
-		c.throttle(false)
+		c.throttle(ctx, false)
 
 		params := url.Values{}
 		params.Set("startAt", fmt.Sprintf("%d", startAt))
@@ -368,17 +472,21 @@ func (c *dcClient) fetchFullChangelog(key string) (*ChangelogDTO, error) {
 
 		log.Debug().Str("key", key).Int("startAt", startAt).Str("url", changelogURL).Msg("Fetching full changelog page")
 
-		req, err := http.NewRequest("GET", changelogURL, nil)
+		req, err := http.NewRequestWithContext(ctx, "GET", changelogURL, nil)
 		if err != nil {
 			return nil, fmt.Errorf("failed to build changelog request for %s: %w", key, err)
 		}
 		c.authenticateRequest(req)
 
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err != nil {
 			return nil, fmt.Errorf("changelog request failed for %s: %w", key, err)
 		}
 
+		if resp.StatusCode == http.StatusNotFound && startAt == 0 {
+			resp.Body.Close()
+			return nil, fmt.Errorf("%w for issue %s", errNoChangelogEndpoint, key)
+		}
 		if resp.StatusCode != http.StatusOK {
 			resp.Body.Close()
 			return nil, fmt.Errorf("changelog API returned status %d for issue %s", resp.StatusCode, key)
@@ -412,24 +520,24 @@ func (c *dcClient) fetchFullChangelog(key string) (*ChangelogDTO, error) {
 	}, nil
 }
 
-func (c *dcClient) GetProject(key string) (any, error) {
+func (c *dcClient) GetProject(ctx context.Context, key string) (any, error) {
 	cacheKey := "project:" + key
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val, nil
 	}
 
-	c.throttle(true)
+	c.throttle(ctx, true)
 
 	// Use restPath() - defaults to v2 for DC, v3 for Cloud.
 	url := c.restPath("", fmt.Sprintf("project/%s", key))
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -457,23 +565,23 @@ func (c *dcClient) GetProject(key string) (any, error) {
 	return project, nil
 }
 
-func (c *dcClient) GetProjectStatuses(key string) (any, error) {
+func (c *dcClient) GetProjectStatuses(ctx context.Context, key string) (any, error) {
 	cacheKey := "statuses:" + key
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val, nil
 	}
 
-	c.throttle(true)
+	c.throttle(ctx, true)
 
 	url := c.restPath("", fmt.Sprintf("project/%s/statuses", key))
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -499,23 +607,23 @@ func (c *dcClient) GetProjectStatuses(key string) (any, error) {
 	return statuses, nil
 }
 
-func (c *dcClient) GetBoard(id int) (any, error) {
+func (c *dcClient) GetBoard(ctx context.Context, id int) (any, error) {
 	cacheKey := fmt.Sprintf("board:%d", id)
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val, nil
 	}
 
-	c.throttle(true)
+	c.throttle(ctx, true)
 
 	url := c.agilePath(fmt.Sprintf("board/%d", id))
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -541,20 +649,20 @@ func (c *dcClient) GetBoard(id int) (any, error) {
 	c.updateInventory(&c.boardInventory, []any{board}, 1000, "id")
 	return board, nil
 }
-func (c *dcClient) FindProjects(query string) ([]any, error) {
+func (c *dcClient) FindProjects(ctx context.Context, query string) ([]any, error) {
 	cacheKey := "find_projects:" + query
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val.([]any), nil
 	}
 
-	c.throttle(true)
+	c.throttle(ctx, true)
 
 	params := url.Values{}
 	params.Set("query", query)
 	params.Set("maxResults", "30")
 
 	var searchURL string
-	isCloud := strings.ToLower(c.cfg.TokenType) == "api"
+	isCloud := c.cfg.IsCloud()
 
 	if isCloud {
 		// Jira Cloud: Uses project/search (v3 is better)
@@ -565,13 +673,13 @@ func (c *dcClient) FindProjects(query string) ([]any, error) {
 	}
 
 	log.Debug().Str("url", searchURL).Msg("Searching for projects")
-	req, err := http.NewRequest("GET", searchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -620,13 +728,13 @@ func (c *dcClient) FindProjects(query string) ([]any, error) {
 	return c.filterInventory(c.projectInventory, query, 30, "key", "name"), nil
 }
 
-func (c *dcClient) FindBoards(projectKey string, nameFilter string) ([]any, error) {
+func (c *dcClient) FindBoards(ctx context.Context, projectKey string, nameFilter string) ([]any, error) {
 	cacheKey := fmt.Sprintf("find_boards:%s:%s", projectKey, nameFilter)
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val.([]any), nil
 	}
 
-	c.throttle(true)
+	c.throttle(ctx, true)
 
 	params := url.Values{}
 	if projectKey != "" {
@@ -638,14 +746,14 @@ func (c *dcClient) FindBoards(projectKey string, nameFilter string) ([]any, erro
 	params.Set("maxResults", "30")
 
 	searchURL := c.agilePath("board") + "?" + params.Encode()
-	req, err := http.NewRequest("GET", searchURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", searchURL, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -755,23 +863,26 @@ func (c *dcClient) filterInventory(inventory []any, query string, limit int, fie
 
 	return matches
 }
-func (c *dcClient) GetBoardConfig(id int) (any, error) {
+
+// GetBoardConfig returns the configuration of a board: its filter, estimation
+// field, and column configuration (see BoardColumns).
+func (c *dcClient) GetBoardConfig(ctx context.Context, id int) (any, error) {
 	cacheKey := fmt.Sprintf("board_config:%d", id)
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val, nil
 	}
 
-	c.throttle(true)
+	c.throttle(ctx, true)
 
 	url := c.agilePath(fmt.Sprintf("board/%d/configuration", id))
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -798,23 +909,23 @@ func (c *dcClient) GetBoardConfig(id int) (any, error) {
 	return config, nil
 }
 
-func (c *dcClient) GetFilter(id string) (any, error) {
+func (c *dcClient) GetFilter(ctx context.Context, id string) (any, error) {
 	cacheKey := "filter:" + id
 	if val, ok := c.getFromCache(cacheKey); ok {
 		return val, nil
 	}
 
-	c.throttle(true)
+	c.throttle(ctx, true)
 
 	url := c.restPath("", fmt.Sprintf("filter/%s", id))
-	req, err := http.NewRequest("GET", url, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
 		return nil, err
 	}
 
 	c.authenticateRequest(req)
 
-	resp, err := c.httpClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, err
 	}
@@ -841,14 +952,14 @@ func (c *dcClient) GetFilter(id string) (any, error) {
 	return filter, nil
 }
 
-func (c *dcClient) GetRegistry(projectKey string) (*NameRegistry, error) {
+func (c *dcClient) GetRegistry(ctx context.Context, projectKey string) (*NameRegistry, error) {
 	registry := &NameRegistry{
 		Statuses:    make(map[string]string),
 		Resolutions: make(map[string]string),
 	}
 
 	// 1. Fetch Statuses
-	rawStatuses, err := c.GetProjectStatuses(projectKey)
+	rawStatuses, err := c.GetProjectStatuses(ctx, projectKey)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch statuses for registry: %w", err)
 	}
@@ -891,12 +1002,12 @@ func (c *dcClient) GetRegistry(projectKey string) (*NameRegistry, error) {
 	}
 
 	// 2. Fetch Resolutions (Global)
-	c.throttle(true)
+	c.throttle(ctx, true)
 	resURL := c.restPath("", "resolution")
-	req, err := http.NewRequest("GET", resURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", resURL, nil)
 	if err == nil {
 		c.authenticateRequest(req)
-		resp, err := c.httpClient.Do(req)
+		resp, err := c.do(req)
 		if err == nil && resp.StatusCode == http.StatusOK {
 			defer resp.Body.Close()
 			var resolutions []ResolutionDTO
@@ -914,3 +1025,114 @@ func (c *dcClient) GetRegistry(projectKey string) (*NameRegistry, error) {
 
 	return registry, nil
 }
+
+// GetSprints returns the most recent closed and active sprints of a Scrum
+// board (oldest first, at most limit), each with the keys of its assigned
+// issues. Kanban boards have no sprints and return an error.
+func (c *dcClient) GetSprints(ctx context.Context, boardID int, limit int) ([]Sprint, error) {
+	cacheKey := fmt.Sprintf("sprints:%d:%d", boardID, limit)
+	if val, ok := c.getFromCache(cacheKey); ok {
+		return val.([]Sprint), nil
+	}
+
+	// 1. List sprints (the Agile API returns them oldest first)
+	var dtos []SprintDTO
+	for startAt := 0; ; {
+		params := url.Values{}
+		params.Set("state", "active,closed")
+		params.Set("startAt", fmt.Sprintf("%d", startAt))
+		params.Set("maxResults", "50")
+
+		var page SprintPageDTO
+		if err := c.getAgileJSON(ctx, c.agilePath(fmt.Sprintf("board/%d/sprint", boardID))+"?"+params.Encode(), fmt.Sprintf("sprints of board %d", boardID), &page); err != nil {
+			return nil, err
+		}
+		dtos = append(dtos, page.Values...)
+		if page.IsLast || len(page.Values) == 0 {
+			break
+		}
+		startAt += len(page.Values)
+	}
+	if limit > 0 && len(dtos) > limit {
+		dtos = dtos[len(dtos)-limit:]
+	}
+
+	// 2. Resolve issue assignments per sprint
+	sprints := make([]Sprint, 0, len(dtos))
+	for _, d := range dtos {
+		sp := Sprint{ID: d.ID, Name: d.Name, State: d.State}
+		if t, err := ParseAgileTime(d.StartDate); err == nil {
+			sp.Start = t
+		}
+		if t, err := ParseAgileTime(d.EndDate); err == nil {
+			sp.End = t
+		}
+		if t, err := ParseAgileTime(d.CompleteDate); err == nil {
+			sp.Complete = &t
+		}
+		if sp.Start.IsZero() {
+			log.Warn().Int("sprint", d.ID).Msg("Skipping sprint without a start date")
+			continue
+		}
+
+		for startAt := 0; ; {
+			params := url.Values{}
+			params.Set("fields", "key")
+			params.Set("startAt", fmt.Sprintf("%d", startAt))
+			params.Set("maxResults", "100")
+
+			var page SprintIssuesDTO
+			if err := c.getAgileJSON(ctx, c.agilePath(fmt.Sprintf("board/%d/sprint/%d/issue", boardID, d.ID))+"?"+params.Encode(), fmt.Sprintf("issues of sprint %d", d.ID), &page); err != nil {
+				return nil, err
+			}
+			for _, is := range page.Issues {
+				sp.IssueKeys = append(sp.IssueKeys, is.Key)
+			}
+			startAt += len(page.Issues)
+			if len(page.Issues) == 0 || startAt >= page.Total {
+				break
+			}
+		}
+		sprints = append(sprints, sp)
+	}
+
+	c.addToCache(cacheKey, sprints, 10*time.Minute)
+	return sprints, nil
+}
+
+// getAgileJSON performs a throttled, authenticated GET against the Agile API
+// and decodes the JSON body into out.
+func (c *dcClient) getAgileJSON(ctx context.Context, reqURL string, what string, out any) error {
+	c.throttle(ctx, true)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", reqURL, nil)
+	if err != nil {
+		return err
+	}
+
+	c.authenticateRequest(req)
+
+	resp, err := c.do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		switch resp.StatusCode {
+		case http.StatusBadRequest:
+			return fmt.Errorf("jira rejected the request for %s (400); the board may not support sprints (Kanban board)", what)
+		case http.StatusNotFound:
+			return fmt.Errorf("%s not found", what)
+		case http.StatusUnauthorized, http.StatusForbidden:
+			return fmt.Errorf("jira authentication failed (401/403); check your session cookies")
+		default:
+			return fmt.Errorf("jira API returned status %d for %s", resp.StatusCode, what)
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", what, err)
+	}
+	return nil
+}
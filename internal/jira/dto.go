@@ -2,6 +2,8 @@ package jira
 
 import (
 	"encoding/json"
+	"strconv"
+	"strings"
 	"time"
 )
 
@@ -18,12 +20,17 @@ type IssueDTO struct {
 	Changelog *ChangelogDTO `json:"changelog,omitempty"`
 }
 
+// FlaggedField is the ID of Jira's standard Flagged field.
+const FlaggedField = "customfield_10014"
+
 // FieldsDTO contains the specific fields we care about.
 type FieldsDTO struct {
+	Summary   string `json:"summary,omitempty"`
 	IssueType struct {
 		Name             string `json:"name"`
 		UntranslatedName string `json:"untranslatedName,omitempty"`
 		Subtask          bool   `json:"subtask"`
+		HierarchyLevel   int    `json:"hierarchyLevel,omitempty"` // Cloud only: -1 sub-task, 0 standard, 1 epic, 2+ above
 	} `json:"issuetype"`
 	Status struct {
 		ID               string `json:"id"`
@@ -39,15 +46,229 @@ type FieldsDTO struct {
 		Name             string `json:"name"`
 		UntranslatedName string `json:"untranslatedName,omitempty"`
 	} `json:"resolution"`
-	ResolutionDate string `json:"resolutiondate"`
-	Flagged        any    `json:"customfield_10014,omitempty"` // Standard Flagged field ID or common alias
-	Created        string `json:"created"`
-	Updated        string `json:"updated"`
+	ResolutionDate string         `json:"resolutiondate"`
+	Flagged        any            `json:"customfield_10014,omitempty"` // Standard Flagged field ID or common alias
+	Created        string         `json:"created"`
+	Updated        string         `json:"updated"`
+	Components     []ComponentDTO `json:"components,omitempty"`
+	Labels         []string       `json:"labels,omitempty"`
+	Parent         *ParentDTO     `json:"parent,omitempty"` // Epic (or other hierarchy parent)
+	FixVersions    []FixVersion   `json:"fixVersions,omitempty"`
+	Assignee       *UserDTO       `json:"assignee,omitempty"`
+
+	// Custom holds the raw values of the other custom fields in the response,
+	// such as the estimation field of a board, keyed by field ID.
+	Custom map[string]json.RawMessage `json:"-"`
+	// Estimate is the value of the configured estimation field (e.g. story
+	// points), filled in by the client; nil when unestimated or not fetched.
+	Estimate *float64 `json:"-"`
+	// TimeInStatus is the value of the configured time-in-status field in
+	// seconds per status ID, filled in by the client; nil when not fetched.
+	TimeInStatus map[string]int64 `json:"-"`
+	// Team is the value of the configured team field, filled in by the
+	// client; empty when unset or not fetched.
+	Team string `json:"-"`
+}
+
+// UnmarshalJSON decodes the known fields and keeps the remaining custom fields raw.
+func (f *FieldsDTO) UnmarshalJSON(data []byte) error {
+	type alias FieldsDTO
+	var a alias
+	if err := json.Unmarshal(data, &a); err != nil {
+		return err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	for id, v := range raw {
+		if !strings.HasPrefix(id, "customfield_") || id == FlaggedField {
+			continue
+		}
+		if a.Custom == nil {
+			a.Custom = make(map[string]json.RawMessage)
+		}
+		a.Custom[id] = v
+	}
+	*f = FieldsDTO(a)
+	return nil
+}
+
+// NumberField returns the value of a numeric custom field. Jira returns
+// numbers, but some estimation fields hold them as strings.
+func (f FieldsDTO) NumberField(id string) (float64, bool) {
+	raw, ok := f.Custom[id]
+	if !ok || string(raw) == "null" {
+		return 0, false
+	}
+	var n float64
+	if err := json.Unmarshal(raw, &n); err == nil {
+		return n, true
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		if n, err := strconv.ParseFloat(strings.TrimSpace(str), 64); err == nil {
+			return n, true
+		}
+	}
+	return 0, false
+}
+
+// TextField returns the display value of a custom field holding a name: a
+// plain string, or an object with a value, name, or title (select options,
+// Atlassian Teams). Of a multi-valued field, the first value is returned.
+func (f FieldsDTO) TextField(id string) (string, bool) {
+	raw, ok := f.Custom[id]
+	if !ok || string(raw) == "null" {
+		return "", false
+	}
+	return textValue(raw)
+}
+
+func textValue(raw json.RawMessage) (string, bool) {
+	var str string
+	if err := json.Unmarshal(raw, &str); err == nil {
+		str = strings.TrimSpace(str)
+		return str, str != ""
+	}
+	var obj struct {
+		Value string `json:"value"`
+		Name  string `json:"name"`
+		Title string `json:"title"`
+	}
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		for _, v := range []string{obj.Value, obj.Name, obj.Title} {
+			if v = strings.TrimSpace(v); v != "" {
+				return v, true
+			}
+		}
+		return "", false
+	}
+	var list []json.RawMessage
+	if err := json.Unmarshal(raw, &list); err == nil {
+		for _, item := range list {
+			if v, ok := textValue(item); ok {
+				return v, true
+			}
+		}
+	}
+	return "", false
+}
+
+// AssigneeName returns the display name of the assignee, or "" if unassigned.
+func (f FieldsDTO) AssigneeName() string {
+	if f.Assignee == nil {
+		return ""
+	}
+	return f.Assignee.DisplayName
+}
+
+// TimeInStatusField returns the value of a time-in-status custom field in
+// seconds per status ID. The field holds entries of the form
+// "<status ID>_*:*_<visits>_*:*_<milliseconds>" joined by "_*|*_", the format
+// of Jira's charting "Time in Status" field that marketplace apps adopt.
+func (f FieldsDTO) TimeInStatusField(id string) (map[string]int64, bool) {
+	raw, ok := f.Custom[id]
+	if !ok || string(raw) == "null" {
+		return nil, false
+	}
+	var str string
+	if err := json.Unmarshal(raw, &str); err != nil {
+		return nil, false
+	}
+	residency := make(map[string]int64)
+	for _, entry := range strings.Split(str, "_*|*_") {
+		parts := strings.Split(strings.TrimSpace(entry), "_*:*_")
+		if len(parts) != 3 || parts[0] == "" {
+			continue
+		}
+		ms, err := strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			continue
+		}
+		residency[parts[0]] += ms / 1000
+	}
+	if len(residency) == 0 {
+		return nil, false
+	}
+	return residency, true
+}
+
+// BoardColumns reads the columns of a board configuration as returned by
+// GetBoardConfig (columnConfig.columns). Columns without statuses, such as an
+// empty "Backlog" column, are skipped.
+func BoardColumns(config any) []BoardColumn {
+	conf, _ := config.(map[string]any)
+	columnConfig, _ := conf["columnConfig"].(map[string]any)
+	raw, _ := columnConfig["columns"].([]any)
+	var columns []BoardColumn
+	for _, c := range raw {
+		cm, _ := c.(map[string]any)
+		name, _ := cm["name"].(string)
+		col := BoardColumn{Name: strings.TrimSpace(name)}
+		statuses, _ := cm["statuses"].([]any)
+		for _, st := range statuses {
+			sm, _ := st.(map[string]any)
+			if id, _ := sm["id"].(string); id != "" {
+				col.StatusIDs = append(col.StatusIDs, id)
+			}
+		}
+		if col.Name == "" || len(col.StatusIDs) == 0 {
+			continue
+		}
+		if n, ok := cm["min"].(float64); ok {
+			col.Min = int(n)
+		}
+		if n, ok := cm["max"].(float64); ok {
+			col.Max = int(n)
+		}
+		columns = append(columns, col)
+	}
+	return columns
+}
+
+// UserDTO is a Jira user, such as the assignee of an issue.
+type UserDTO struct {
+	DisplayName string `json:"displayName"`
+}
+
+// ComponentDTO is a project component assigned to an issue.
+type ComponentDTO struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// ParentDTO is the hierarchy parent of an issue (typically its Epic).
+type ParentDTO struct {
+	ID  string `json:"id"`
+	Key string `json:"key"`
+}
+
+// ComponentNames returns the names of the components assigned to the issue.
+func (f FieldsDTO) ComponentNames() []string {
+	if len(f.Components) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(f.Components))
+	for _, c := range f.Components {
+		if c.Name != "" {
+			names = append(names, c.Name)
+		}
+	}
+	return names
+}
+
+// ParentKey returns the key of the hierarchy parent, or empty if none.
+func (f FieldsDTO) ParentKey() string {
+	if f.Parent == nil {
+		return ""
+	}
+	return f.Parent.Key
 }
 
 // ChangelogDTO contains historical transitions.
 // StartAt, MaxResults, and Total are populated from the Jira pagination envelope
-// and are used solely for truncation detection; they are not passed downstream.
+// and are used solely for truncation detection (see IsTruncated).
 type ChangelogDTO struct {
 	StartAt    int          `json:"startAt"`
 	MaxResults int          `json:"maxResults"`
@@ -55,6 +276,12 @@ type ChangelogDTO struct {
 	Histories  []HistoryDTO `json:"histories"`
 }
 
+// IsTruncated reports whether Jira returned only part of the changelog
+// (maxResults < total), as it does for embedded changelogs of more than 100 entries.
+func (c *ChangelogDTO) IsTruncated() bool {
+	return c != nil && c.MaxResults > 0 && c.MaxResults < c.Total
+}
+
 // UnmarshalJSON handles both the embedded-changelog format (key: "histories") used by
 // the search and single-issue endpoints, and the dedicated changelog endpoint format
 // (key: "values") used by the Jira Cloud standalone changelog API.
@@ -96,11 +323,54 @@ type ItemDTO struct {
 	From       string `json:"from"` // ID
 }
 
+// WebhookDTO is a Jira issue webhook delivery (jira:issue_created,
+// jira:issue_updated, ...). Changelog holds only the change that triggered it.
+type WebhookDTO struct {
+	Timestamp    int64                `json:"timestamp"` // Unix milliseconds
+	WebhookEvent string               `json:"webhookEvent"`
+	Issue        IssueDTO             `json:"issue"`
+	Changelog    *WebhookChangelogDTO `json:"changelog,omitempty"`
+}
+
+// WebhookChangelogDTO is the single history entry of a webhook delivery.
+type WebhookChangelogDTO struct {
+	ID    string    `json:"id"`
+	Items []ItemDTO `json:"items"`
+}
+
 // FindBoardsResponse is used for the board search API.
 type FindBoardsResponse struct {
 	Values []any `json:"values"`
 }
 
+// SprintDTO is a sprint as returned by the Agile API.
+type SprintDTO struct {
+	ID           int    `json:"id"`
+	Name         string `json:"name"`
+	State        string `json:"state"`
+	StartDate    string `json:"startDate,omitempty"`
+	EndDate      string `json:"endDate,omitempty"`
+	CompleteDate string `json:"completeDate,omitempty"`
+}
+
+// SprintPageDTO is one page of the board sprint listing.
+type SprintPageDTO struct {
+	StartAt    int         `json:"startAt"`
+	MaxResults int         `json:"maxResults"`
+	IsLast     bool        `json:"isLast"`
+	Values     []SprintDTO `json:"values"`
+}
+
+// SprintIssuesDTO is one page of the issues assigned to a sprint.
+type SprintIssuesDTO struct {
+	StartAt    int `json:"startAt"`
+	MaxResults int `json:"maxResults"`
+	Total      int `json:"total"`
+	Issues     []struct {
+		Key string `json:"key"`
+	} `json:"issues"`
+}
+
 // ProjectStatusDTO represents the nested status structure in Jira Cloud.
 type ProjectStatusDTO struct {
 	Statuses []Status `json:"statuses"`
@@ -125,6 +395,20 @@ func ParseTime(s string) (time.Time, error) {
 	return time.Parse("2006-01-02T15:04:05.000-0700", s)
 }
 
+// FormatTime formats t in the strict Jira time format read by ParseTime.
+func FormatTime(t time.Time) string {
+	return t.Format("2006-01-02T15:04:05.000-0700")
+}
+
+// ParseAgileTime parses Agile API timestamps, which use either the strict Jira
+// format or RFC 3339 depending on the deployment.
+func ParseAgileTime(s string) (time.Time, error) {
+	if t, err := ParseTime(s); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, s)
+}
+
 // ExtractProjectKey extracts the project key portion from a Jira issue key (e.g., "PROJ" from "PROJ-123").
 func ExtractProjectKey(issueKey string) string {
 	for i := 0; i < len(issueKey); i++ {
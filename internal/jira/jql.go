@@ -0,0 +1,203 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// JQLDateTimeFormat is the minute-precision layout of absolute dates in JQL.
+const JQLDateTimeFormat = "2006-01-02 15:04"
+
+// MaxJQLLength bounds the length of a source query. Jira rejects very long
+// queries anyway; the bound keeps a broken filter from bloating every query
+// built on it.
+const MaxJQLLength = 10000
+
+// Clauses composed onto source queries.
+const (
+	NotSubTask        = "issuetype not in subtaskIssueTypes()"
+	ResolutionIsEmpty = "resolution is EMPTY"
+)
+
+// ErrUnsafeJQL is returned for source queries that cannot be safely embedded
+// into a larger query.
+var ErrUnsafeJQL = errors.New("unsafe JQL")
+
+// orderByClause matches the keywords of an ORDER BY clause at the start of a string.
+var orderByClause = regexp.MustCompile(`(?i)^order\s+by\b`)
+
+// NormalizeJQL validates a source query (typically the JQL of a saved filter
+// or one supplied by the user) and returns it without its ORDER BY clause, so
+// that it can be wrapped in parentheses and extended. It rejects queries with
+// unterminated strings, unbalanced parentheses, or control characters: a
+// query closing a parenthesis it never opened could otherwise escape its
+// wrapping group and turn every added clause into an alternative.
+func NormalizeJQL(jql string) (string, error) {
+	query, _ := SplitOrderBy(jql)
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return "", fmt.Errorf("%w: the query is empty", ErrUnsafeJQL)
+	}
+	if len(query) > MaxJQLLength {
+		return "", fmt.Errorf("%w: the query is longer than %d characters", ErrUnsafeJQL, MaxJQLLength)
+	}
+
+	depth := 0
+	var quote rune
+	escaped := false
+	for i, r := range query {
+		if unicode.IsControl(r) && r != '\t' && r != '\n' && r != '\r' {
+			return "", fmt.Errorf("%w: control character %U at offset %d", ErrUnsafeJQL, r, i)
+		}
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if r == '\\' {
+				escaped = true
+			} else if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+			if depth < 0 {
+				return "", fmt.Errorf("%w: unmatched ')' at offset %d", ErrUnsafeJQL, i)
+			}
+		}
+	}
+	if quote != 0 {
+		return "", fmt.Errorf("%w: unterminated %c string", ErrUnsafeJQL, quote)
+	}
+	if depth > 0 {
+		return "", fmt.Errorf("%w: %d unclosed '('", ErrUnsafeJQL, depth)
+	}
+	return query, nil
+}
+
+// SplitOrderBy splits a query at its top-level ORDER BY clause. Keywords
+// inside quoted strings or parentheses are not clauses. orderBy keeps its
+// leading whitespace, so query+orderBy is the original query.
+func SplitOrderBy(jql string) (query, orderBy string) {
+	depth := 0
+	var quote byte
+	escaped := false
+	for i := 0; i < len(jql); i++ {
+		c := jql[i]
+		switch {
+		case escaped:
+			escaped = false
+		case quote != 0:
+			if c == '\\' {
+				escaped = true
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case depth == 0 && (i == 0 || isJQLSeparator(jql[i-1])) && orderByClause.MatchString(jql[i:]):
+			start := i
+			for start > 0 && isJQLSeparator(jql[start-1]) && jql[start-1] != ')' {
+				start--
+			}
+			return jql[:start], jql[start:]
+		}
+	}
+	return jql, ""
+}
+
+func isJQLSeparator(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ')'
+}
+
+// AndJQL wraps a source query in parentheses and adds clauses to it. Clauses
+// must come from the builders of this file or constants; values are never
+// spliced in unquoted.
+func AndJQL(base string, clauses ...string) string {
+	if len(clauses) == 0 {
+		return base
+	}
+	return fmt.Sprintf("(%s) AND %s", base, strings.Join(clauses, " AND "))
+}
+
+// QuoteJQL returns value as a double-quoted JQL string literal.
+func QuoteJQL(value string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// FieldEquals returns the clause `field = "value"`.
+func FieldEquals(field, value string) string {
+	return field + " = " + QuoteJQL(value)
+}
+
+// FieldIn returns the clause `field in ("a", "b")`.
+func FieldIn(field string, values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = QuoteJQL(v)
+	}
+	return field + " in (" + strings.Join(quoted, ", ") + ")"
+}
+
+// IssueFilter narrows a source to the issues carrying any of Labels and any
+// of Components, so that sub-teams sharing a board can be analyzed apart. An
+// empty list does not narrow.
+type IssueFilter struct {
+	Labels     []string `json:"labels,omitempty"`
+	Components []string `json:"components,omitempty"`
+}
+
+// IsZero reports whether the filter lets every issue through.
+func (f IssueFilter) IsZero() bool {
+	return len(f.Labels) == 0 && len(f.Components) == 0
+}
+
+// Clauses returns the JQL clauses of the filter.
+func (f IssueFilter) Clauses() []string {
+	var clauses []string
+	if len(f.Labels) > 0 {
+		clauses = append(clauses, FieldIn("labels", f.Labels))
+	}
+	if len(f.Components) > 0 {
+		clauses = append(clauses, FieldIn("component", f.Components))
+	}
+	return clauses
+}
+
+// Matches reports whether an issue with the given labels and components
+// passes the filter. Labels compare exactly, as in Jira; component names
+// ignore case.
+func (f IssueFilter) Matches(labels, components []string) bool {
+	if len(f.Labels) > 0 && !slices.ContainsFunc(labels, func(l string) bool { return slices.Contains(f.Labels, l) }) {
+		return false
+	}
+	if len(f.Components) > 0 && !slices.ContainsFunc(components, func(c string) bool {
+		return slices.ContainsFunc(f.Components, func(want string) bool { return strings.EqualFold(c, want) })
+	}) {
+		return false
+	}
+	return true
+}
+
+// DateClause returns the clause comparing a date field with t, at minute
+// precision in t's location. op is one of =, <, <=, > or >=.
+func DateClause(field, op string, t time.Time) string {
+	return fmt.Sprintf("%s %s %s", field, op, QuoteJQL(t.Format(JQLDateTimeFormat)))
+}
+
+// ResolvedWithin returns the clause selecting issues resolved in [start, end).
+func ResolvedWithin(start, end time.Time) string {
+	return DateClause("resolved", ">=", start) + " AND " + DateClause("resolved", "<", end)
+}
@@ -0,0 +1,82 @@
+package jira
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNormalizeJQL(t *testing.T) {
+	valid := map[string]string{
+		" project = PROJ ORDER BY Rank ASC":                `project = PROJ`,
+		`summary ~ "sort order by date" order by created`:  `summary ~ "sort order by date"`,
+		`project in (A, B) AND (labels = x OR labels = y)`: `project in (A, B) AND (labels = x OR labels = y)`,
+		`summary ~ 'it\'s (done'`:                          `summary ~ 'it\'s (done'`,
+		"project = A\nORDER BY updated":                    `project = A`,
+	}
+	for in, want := range valid {
+		got, err := NormalizeJQL(in)
+		if err != nil || got != want {
+			t.Errorf("NormalizeJQL(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+
+	for _, in := range []string{
+		"",
+		"  ORDER BY created",
+		`project = A) OR (project = B`,
+		`project = A)`,
+		`(project = A`,
+		`summary ~ "open`,
+		"project = A\x00",
+	} {
+		if _, err := NormalizeJQL(in); !errors.Is(err, ErrUnsafeJQL) {
+			t.Errorf("Expected NormalizeJQL(%q) to be rejected, got %v", in, err)
+		}
+	}
+}
+
+func TestSplitOrderBy(t *testing.T) {
+	query, orderBy := SplitOrderBy(`(labels = "order by") ORDER BY rank`)
+	if query != `(labels = "order by")` || orderBy != " ORDER BY rank" {
+		t.Errorf("Unexpected split: %q | %q", query, orderBy)
+	}
+	if query, orderBy := SplitOrderBy("reorder = 1"); query != "reorder = 1" || orderBy != "" {
+		t.Errorf("Expected no ORDER BY in %q, got %q", query, orderBy)
+	}
+}
+
+func TestComposeJQL(t *testing.T) {
+	if got := AndJQL("project = A", FieldEquals("key", `A-1" OR project = B`)); got != `(project = A) AND key = "A-1\" OR project = B"` {
+		t.Errorf("Expected the value to be quoted, got %s", got)
+	}
+	if got := AndJQL("project = A"); got != "project = A" {
+		t.Errorf("Expected no clauses to keep the query, got %s", got)
+	}
+
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	got := AndJQL("project = A", ResolvedWithin(start, start.AddDate(0, 1, 0)), ResolutionIsEmpty)
+	if want := `(project = A) AND resolved >= "2026-01-01 00:00" AND resolved < "2026-02-01 00:00" AND resolution is EMPTY`; got != want {
+		t.Errorf("Expected %s, got %s", want, got)
+	}
+}
+
+func TestIssueFilter(t *testing.T) {
+	f := IssueFilter{Labels: []string{"payments", `team "a"`}, Components: []string{"API"}}
+
+	if got := AndJQL("project = A", f.Clauses()...); got != `(project = A) AND labels in ("payments", "team \"a\"") AND component in ("API")` {
+		t.Errorf("Expected quoted in-clauses, got %s", got)
+	}
+	if !f.Matches([]string{"ux", "payments"}, []string{"api"}) {
+		t.Error("Expected any label and a component of another case to match")
+	}
+	if f.Matches([]string{"Payments"}, []string{"API"}) {
+		t.Error("Expected labels to compare exactly")
+	}
+	if f.Matches([]string{"payments"}, nil) {
+		t.Error("Expected an issue without components to fail a component filter")
+	}
+	if !(IssueFilter{}).Matches(nil, nil) || !(IssueFilter{}).IsZero() {
+		t.Error("Expected the zero filter to let every issue through")
+	}
+}
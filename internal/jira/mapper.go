@@ -30,6 +30,13 @@ func MapIssue(item IssueDTO, finishedStatuses map[string]bool) Issue {
 		Resolution:      item.Fields.Resolution.Name,
 		StatusResidency: make(map[string]int64),
 		IsSubtask:       item.Fields.IssueType.Subtask,
+		HierarchyLevel:  item.Fields.IssueType.HierarchyLevel,
+		Components:      item.Fields.ComponentNames(),
+		Labels:          item.Fields.Labels,
+		ParentKey:       item.Fields.ParentKey(),
+		FixVersions:     item.Fields.FixVersions,
+		Assignee:        item.Fields.AssigneeName(),
+		Team:            item.Fields.Team,
 	}
 
 	for i := 0; i < len(issue.Key); i++ {
@@ -0,0 +1,271 @@
+package jira
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+	"golang.org/x/oauth2"
+)
+
+// TokenTypeOAuth selects Atlassian OAuth 2.0 (3LO) authentication for Jira
+// Cloud. The tokens are obtained once via 'mcs-mcp auth login' and refreshed
+// automatically from then on.
+const TokenTypeOAuth = "oauth"
+
+// DefaultOAuthCallbackPort is the local port of the login callback. Atlassian
+// requires the exact callback URL to be registered with the OAuth app.
+const DefaultOAuthCallbackPort = 8765
+
+// OAuthScopes are the Atlassian scopes requested at login: read access to
+// issues, boards, and users, plus offline_access for refresh tokens.
+var OAuthScopes = []string{"read:jira-work", "read:jira-user", "offline_access"}
+
+// Atlassian 3LO endpoints; variables so that tests can point them at a fake.
+var (
+	oauthEndpoint = oauth2.Endpoint{
+		AuthURL:  "https://auth.atlassian.com/authorize",
+		TokenURL: "https://auth.atlassian.com/oauth/token",
+	}
+	accessibleResourcesURL = "https://api.atlassian.com/oauth/token/accessible-resources"
+	oauthAPIBaseURL        = "https://api.atlassian.com/ex/jira/"
+)
+
+// OAuthConfig holds the Atlassian OAuth 2.0 app credentials and where the
+// tokens of the login are stored.
+type OAuthConfig struct {
+	ClientID     string
+	ClientSecret string
+	CallbackPort int    // 0 = DefaultOAuthCallbackPort
+	TokenFile    string // JSON file holding the tokens, readable by the owner only
+}
+
+// OAuthToken is the persisted result of 'mcs-mcp auth login': the token pair
+// and the Jira Cloud site it grants access to.
+type OAuthToken struct {
+	Token   *oauth2.Token `json:"token"`
+	CloudID string        `json:"cloud_id"`
+	SiteURL string        `json:"site_url"`
+}
+
+// CallbackURL returns the redirect URL to register with the OAuth app.
+func (o OAuthConfig) CallbackURL() string {
+	return fmt.Sprintf("http://%s/callback", o.callbackAddr())
+}
+
+func (o OAuthConfig) callbackAddr() string {
+	port := o.CallbackPort
+	if port == 0 {
+		port = DefaultOAuthCallbackPort
+	}
+	return fmt.Sprintf("localhost:%d", port)
+}
+
+func (o OAuthConfig) oauth2Config() *oauth2.Config {
+	return &oauth2.Config{
+		ClientID:     o.ClientID,
+		ClientSecret: o.ClientSecret,
+		Endpoint:     oauthEndpoint,
+		RedirectURL:  o.CallbackURL(),
+		Scopes:       OAuthScopes,
+	}
+}
+
+// LoadOAuthToken reads the tokens stored by SaveOAuthToken.
+func LoadOAuthToken(path string) (*OAuthToken, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var tok OAuthToken
+	if err := json.Unmarshal(data, &tok); err != nil {
+		return nil, fmt.Errorf("invalid OAuth token file %s: %w", path, err)
+	}
+	if tok.Token == nil || tok.CloudID == "" {
+		return nil, fmt.Errorf("incomplete OAuth token file %s", path)
+	}
+	return &tok, nil
+}
+
+// SaveOAuthToken writes the tokens atomically, readable by the owner only.
+func SaveOAuthToken(path string, tok *OAuthToken) error {
+	data, err := json.MarshalIndent(tok, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// OAuthLogin runs the Atlassian 3LO authorization code flow: it serves the
+// callback on localhost, has open show the consent page to the user, exchanges
+// the code for tokens, and resolves the Jira Cloud site they grant access to.
+// With several accessible sites, siteURL (JIRA_URL) picks one.
+func OAuthLogin(ctx context.Context, cfg OAuthConfig, siteURL string, open func(url string) error) (*OAuthToken, error) {
+	if cfg.ClientID == "" || cfg.ClientSecret == "" {
+		return nil, fmt.Errorf("OAuth client ID and secret are required")
+	}
+	oc := cfg.oauth2Config()
+
+	stateBytes := make([]byte, 16)
+	if _, err := rand.Read(stateBytes); err != nil {
+		return nil, err
+	}
+	state := hex.EncodeToString(stateBytes)
+
+	ln, err := net.Listen("tcp", cfg.callbackAddr())
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen for the OAuth callback: %w", err)
+	}
+	codes := make(chan string, 1)
+	errs := make(chan error, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		switch {
+		case q.Get("state") != state:
+			http.Error(w, "invalid state", http.StatusBadRequest)
+			return
+		case q.Get("error") != "":
+			http.Error(w, "authorization failed: "+q.Get("error_description"), http.StatusBadRequest)
+			select {
+			case errs <- fmt.Errorf("authorization failed: %s %s", q.Get("error"), q.Get("error_description")):
+			default:
+			}
+			return
+		}
+		fmt.Fprintln(w, "MCS-MCP is authorized. You can close this window.")
+		select {
+		case codes <- q.Get("code"):
+		default: // A repeated callback; the first code is being exchanged
+		}
+	})
+	srv := &http.Server{Handler: mux}
+	go func() { _ = srv.Serve(ln) }()
+	defer srv.Close()
+
+	authURL := oc.AuthCodeURL(state,
+		oauth2.SetAuthURLParam("audience", "api.atlassian.com"),
+		oauth2.SetAuthURLParam("prompt", "consent"))
+	if err := open(authURL); err != nil {
+		return nil, err
+	}
+
+	var code string
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case err := <-errs:
+		return nil, err
+	case code = <-codes:
+	}
+
+	token, err := oc.Exchange(ctx, code)
+	if err != nil {
+		return nil, fmt.Errorf("failed to exchange the authorization code: %w", err)
+	}
+	cloudID, site, err := resolveCloudSite(ctx, oc.Client(ctx, token), siteURL)
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthToken{Token: token, CloudID: cloudID, SiteURL: site}, nil
+}
+
+// resolveCloudSite picks the Jira Cloud site the token grants access to.
+func resolveCloudSite(ctx context.Context, client *http.Client, siteURL string) (string, string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", accessibleResourcesURL, nil)
+	if err != nil {
+		return "", "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", "", err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", "", fmt.Errorf("atlassian API returned status %d for accessible resources", resp.StatusCode)
+	}
+	var resources []struct {
+		ID   string `json:"id"`
+		URL  string `json:"url"`
+		Name string `json:"name"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&resources); err != nil {
+		return "", "", fmt.Errorf("failed to decode accessible resources: %w", err)
+	}
+
+	siteURL = strings.TrimSuffix(siteURL, "/")
+	var urls []string
+	for _, r := range resources {
+		if len(resources) == 1 || strings.EqualFold(strings.TrimSuffix(r.URL, "/"), siteURL) {
+			return r.ID, r.URL, nil
+		}
+		urls = append(urls, r.URL)
+	}
+	if len(resources) == 0 {
+		return "", "", fmt.Errorf("the authorization grants access to no Jira site")
+	}
+	return "", "", fmt.Errorf("the authorization grants access to several Jira sites (%s); set JIRA_URL to one of them", strings.Join(urls, ", "))
+}
+
+// storedTokenSource refreshes the access token when it expires and writes the
+// rotated token pair back to the token file.
+type storedTokenSource struct {
+	mu   sync.Mutex
+	path string
+	tok  *OAuthToken
+	src  oauth2.TokenSource
+}
+
+func (s *storedTokenSource) Token() (*oauth2.Token, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.src.Token()
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh the Jira OAuth token (run 'mcs-mcp auth login' again): %w", err)
+	}
+	if t.AccessToken != s.tok.Token.AccessToken {
+		s.tok.Token = t
+		if err := SaveOAuthToken(s.path, s.tok); err != nil {
+			log.Warn().Err(err).Str("path", s.path).Msg("Failed to store the refreshed Jira OAuth token")
+		}
+	}
+	return t, nil
+}
+
+// missingTokenSource fails every request of an OAuth client without a login.
+type missingTokenSource struct{ err error }
+
+func (s missingTokenSource) Token() (*oauth2.Token, error) { return nil, s.err }
+
+// newOAuthTransport returns the transport that authorizes the requests of an
+// OAuth client, and the API base URL of its Jira Cloud site.
+func newOAuthTransport(cfg OAuthConfig) (http.RoundTripper, string) {
+	tok, err := LoadOAuthToken(cfg.TokenFile)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			err = fmt.Errorf("no Jira OAuth login found; run 'mcs-mcp auth login'")
+		}
+		log.Warn().Err(err).Msg("Jira OAuth authentication unavailable")
+		return &oauth2.Transport{Source: missingTokenSource{err: err}}, ""
+	}
+	src := &storedTokenSource{
+		path: cfg.TokenFile,
+		tok:  tok,
+		src:  cfg.oauth2Config().TokenSource(context.Background(), tok.Token),
+	}
+	return &oauth2.Transport{Source: src}, oauthAPIBaseURL + tok.CloudID
+}
@@ -0,0 +1,131 @@
+package jira
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"golang.org/x/oauth2"
+)
+
+// fakeAtlassian serves the token and accessible-resources endpoints and a
+// Jira API behind the gateway, and points the package endpoints at itself.
+func fakeAtlassian(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /oauth/token", func(w http.ResponseWriter, r *http.Request) {
+		_ = r.ParseForm()
+		if r.Form.Get("grant_type") != "refresh_token" || r.Form.Get("refresh_token") != "refresh-1" {
+			http.Error(w, `{"error":"invalid_grant"}`, http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"access_token":"access-2","refresh_token":"refresh-2","token_type":"Bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("GET /resources", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`[{"id":"c1","url":"https://a.atlassian.net","name":"a"},{"id":"c2","url":"https://b.atlassian.net","name":"b"}]`))
+	})
+	mux.HandleFunc("GET /ex/jira/c2/rest/api/3/myself", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer access-2" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		_, _ = w.Write([]byte(`{}`))
+	})
+	srv := httptest.NewServer(mux)
+	t.Cleanup(srv.Close)
+
+	prevEndpoint, prevResources, prevAPI := oauthEndpoint, accessibleResourcesURL, oauthAPIBaseURL
+	oauthEndpoint = oauth2.Endpoint{AuthURL: srv.URL + "/authorize", TokenURL: srv.URL + "/oauth/token", AuthStyle: oauth2.AuthStyleInParams}
+	accessibleResourcesURL = srv.URL + "/resources"
+	oauthAPIBaseURL = srv.URL + "/ex/jira/"
+	t.Cleanup(func() { oauthEndpoint, accessibleResourcesURL, oauthAPIBaseURL = prevEndpoint, prevResources, prevAPI })
+	return srv
+}
+
+func TestOAuthTransport_RefreshesAndStoresRotatedToken(t *testing.T) {
+	fakeAtlassian(t)
+	path := filepath.Join(t.TempDir(), "token.json")
+	expired := &OAuthToken{
+		Token:   &oauth2.Token{AccessToken: "access-1", RefreshToken: "refresh-1", Expiry: time.Now().Add(-time.Minute)},
+		CloudID: "c2",
+		SiteURL: "https://b.atlassian.net",
+	}
+	if err := SaveOAuthToken(path, expired); err != nil {
+		t.Fatal(err)
+	}
+
+	c := NewDataCenterClient(Config{TokenType: "oauth", OAuth: OAuthConfig{ClientID: "id", ClientSecret: "secret", TokenFile: path}}).(*dcClient)
+	req, _ := http.NewRequest("GET", c.restPath("", "myself"), nil)
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("expected the refreshed token on the gateway URL, got status %d for %s", resp.StatusCode, req.URL)
+	}
+
+	stored, err := LoadOAuthToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored.Token.AccessToken != "access-2" || stored.Token.RefreshToken != "refresh-2" || stored.CloudID != "c2" {
+		t.Errorf("rotated token not stored: %+v", stored.Token)
+	}
+	if info, err := os.Stat(path); err != nil || info.Mode().Perm() != 0600 {
+		t.Errorf("token file must be owner-only, got %v (err %v)", info.Mode().Perm(), err)
+	}
+}
+
+func TestOAuthTransport_WithoutLogin(t *testing.T) {
+	c := NewDataCenterClient(Config{TokenType: "oauth", OAuth: OAuthConfig{TokenFile: filepath.Join(t.TempDir(), "missing.json")}}).(*dcClient)
+	req, _ := http.NewRequest("GET", "http://127.0.0.1:1/rest/api/3/myself", nil)
+	_, err := c.httpClient.Do(req)
+	if err == nil || !strings.Contains(err.Error(), "mcs-mcp auth login") {
+		t.Errorf("expected a login hint, got %v", err)
+	}
+}
+
+func TestResolveCloudSite(t *testing.T) {
+	fakeAtlassian(t)
+	ctx := context.Background()
+
+	id, site, err := resolveCloudSite(ctx, http.DefaultClient, "https://B.atlassian.net/")
+	if err != nil || id != "c2" || site != "https://b.atlassian.net" {
+		t.Errorf("expected site b, got %q %q (err %v)", id, site, err)
+	}
+	if _, _, err := resolveCloudSite(ctx, http.DefaultClient, ""); err == nil || !strings.Contains(err.Error(), "several Jira sites") {
+		t.Errorf("expected an ambiguity error, got %v", err)
+	}
+}
+
+func TestOAuthTokenFile_Roundtrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "token.json")
+	if err := os.WriteFile(path, []byte(`{"token":{"access_token":"a"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadOAuthToken(path); err == nil {
+		t.Error("expected an error for a token file without cloud ID")
+	}
+
+	want := &OAuthToken{Token: &oauth2.Token{AccessToken: "a", RefreshToken: "r"}, CloudID: "c", SiteURL: "https://x.atlassian.net"}
+	if err := SaveOAuthToken(path, want); err != nil {
+		t.Fatal(err)
+	}
+	got, err := LoadOAuthToken(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a, _ := json.Marshal(want)
+	b, _ := json.Marshal(got)
+	if string(a) != string(b) {
+		t.Errorf("roundtrip mismatch:\n got %s\nwant %s", b, a)
+	}
+}
@@ -0,0 +1,133 @@
+package jira
+
+import (
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxRetryWait caps both the exponential backoff and a server-provided
+// Retry-After, so a misbehaving proxy cannot stall an ingestion indefinitely.
+const maxRetryWait = 5 * time.Minute
+
+// do sends req, pacing it to the configured rate limit. Throttled (429) and
+// temporarily unavailable (502/503/504) responses are retried up to MaxRetries
+// times with exponential backoff; a Retry-After header takes precedence over
+// the computed backoff and pauses all requests of the client, not just this one.
+// Once the retries are exhausted, the last response is returned to the caller.
+// Waiting stops as soon as the request's context is cancelled.
+func (c *dcClient) do(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if err := c.pace(req.Context()); err != nil {
+			return nil, err
+		}
+
+		resp, err := c.httpClient.Do(req)
+		if err != nil || !isRetryableStatus(resp.StatusCode) || attempt >= c.cfg.MaxRetries {
+			if err == nil && isRetryableStatus(resp.StatusCode) && c.cfg.MaxRetries > 0 {
+				log.Error().Int("status", resp.StatusCode).Int("attempts", attempt+1).Str("path", req.URL.Path).Msg("Jira request still throttled; giving up")
+			}
+			return resp, err
+		}
+
+		wait := c.backoff(attempt)
+		source := "backoff"
+		if ra, ok := parseRetryAfter(resp.Header.Get("Retry-After"), c.now()); ok {
+			wait, source = min(ra, maxRetryWait), "retry-after"
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		log.Warn().
+			Int("status", resp.StatusCode).
+			Int("attempt", attempt+1).
+			Int("max_retries", c.cfg.MaxRetries).
+			Dur("wait", wait).
+			Str("source", source).
+			Str("path", req.URL.Path).
+			Msg("Jira throttled the request; waiting before retry")
+		c.cooldown(wait)
+	}
+}
+
+// pace blocks until the next request slot of the rate limiter is due, or ctx
+// is cancelled. Slots are reserved under the lock, so concurrent callers queue
+// up instead of all firing when the same slot opens.
+func (c *dcClient) pace(ctx context.Context) error {
+	c.limiterMu.Lock()
+	now := c.now()
+	wait := max(c.nextSlot.Sub(now), 0)
+	interval := time.Duration(0)
+	if c.cfg.RequestsPerMinute > 0 {
+		interval = time.Minute / time.Duration(c.cfg.RequestsPerMinute)
+	}
+	c.nextSlot = now.Add(wait + interval)
+	c.limiterMu.Unlock()
+
+	if wait > 0 {
+		log.Debug().Dur("wait", wait).Msg("Rate limiting Jira request")
+		return c.sleep(ctx, wait)
+	}
+	return ctx.Err()
+}
+
+// sleepContext waits for d, returning early with ctx.Err() if ctx is cancelled.
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// cooldown pushes the next request slot at least d into the future.
+func (c *dcClient) cooldown(d time.Duration) {
+	c.limiterMu.Lock()
+	defer c.limiterMu.Unlock()
+	if until := c.now().Add(d); until.After(c.nextSlot) {
+		c.nextSlot = until
+	}
+}
+
+// backoff returns the exponential delay before retry attempt+1, capped at maxRetryWait.
+func (c *dcClient) backoff(attempt int) time.Duration {
+	d := time.Duration(float64(c.cfg.RetryBaseDelay) * math.Pow(2, float64(attempt)))
+	if d < 0 || d > maxRetryWait {
+		return maxRetryWait
+	}
+	return d
+}
+
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter reads a Retry-After header given either as delay seconds
+// or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if t, err := http.ParseTime(value); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,148 @@
+package jira
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// withFakeClock makes the client's waits advance a fake clock instead of
+// sleeping, and records them.
+func withFakeClock(c *dcClient) *[]time.Duration {
+	clock := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	var waits []time.Duration
+	c.now = func() time.Time { return clock }
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		waits = append(waits, d)
+		clock = clock.Add(d)
+		return nil
+	}
+	return &waits
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc, maxRetries int) (*dcClient, *[]time.Duration) {
+	t.Helper()
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	c := NewDataCenterClient(Config{BaseURL: srv.URL, MaxRetries: maxRetries, RetryBaseDelay: time.Second}).(*dcClient)
+	return c, withFakeClock(c)
+}
+
+func TestDo_RetriesThrottledResponses(t *testing.T) {
+	var calls atomic.Int32
+	c, waits := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		default:
+			_, _ = w.Write([]byte(`{"key":"PROJ","name":"Project"}`))
+		}
+	}, 5)
+
+	if _, err := c.GetProject(context.Background(), "PROJ"); err != nil {
+		t.Fatalf("Expected the request to succeed after retries, got %v", err)
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls.Load())
+	}
+	// 1st wait honors Retry-After, 2nd falls back to the backoff of attempt 2
+	if len(*waits) != 2 || (*waits)[0] != 7*time.Second || (*waits)[1] != 2*time.Second {
+		t.Errorf("Expected waits of ~7s (Retry-After) and ~2s (backoff), got %v", *waits)
+	}
+}
+
+func TestDo_GivesUpAfterMaxRetries(t *testing.T) {
+	var calls atomic.Int32
+	c, _ := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusTooManyRequests)
+	}, 2)
+
+	if _, err := c.SearchIssues(context.Background(), "project = PROJ", 0, 50); err == nil {
+		t.Fatal("Expected an error once the retries are exhausted")
+	}
+	if calls.Load() != 3 {
+		t.Errorf("Expected 1 attempt + 2 retries, got %d", calls.Load())
+	}
+}
+
+func TestDo_DoesNotRetryClientErrors(t *testing.T) {
+	var calls atomic.Int32
+	c, waits := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}, 5)
+
+	if _, err := c.GetProject(context.Background(), "NOPE"); err == nil {
+		t.Fatal("Expected a not-found error")
+	}
+	if calls.Load() != 1 || len(*waits) != 0 {
+		t.Errorf("Expected a single attempt without waiting, got %d attempts and waits %v", calls.Load(), *waits)
+	}
+}
+
+func TestPace_SpacesRequests(t *testing.T) {
+	c := NewDataCenterClient(Config{RequestsPerMinute: 60}).(*dcClient)
+	waits := withFakeClock(c)
+
+	for range 3 {
+		if err := c.pace(context.Background()); err != nil {
+			t.Fatalf("pace: %v", err)
+		}
+	}
+	if len(*waits) != 2 || (*waits)[0] != time.Second || (*waits)[1] != time.Second {
+		t.Errorf("Expected the 2nd and 3rd requests to wait 1s each, got %v", *waits)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value string
+		want  time.Duration
+		ok    bool
+	}{
+		{"30", 30 * time.Second, true},
+		{now.Add(time.Minute).Format(http.TimeFormat), time.Minute, true},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0, true},
+		{"", 0, false},
+		{"soon", 0, false},
+		{"-5", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("parseRetryAfter(%q) = %v, %v; want %v, %v", tt.value, got, ok, tt.want, tt.ok)
+		}
+	}
+}
+
+func TestDo_StopsWaitingWhenCancelled(t *testing.T) {
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	t.Cleanup(srv.Close)
+	c := NewDataCenterClient(Config{BaseURL: srv.URL, MaxRetries: 5}).(*dcClient)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err := c.GetProject(ctx, "PROJ")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Expected the deadline to abort the Retry-After wait, got %v", err)
+	}
+	if time.Since(start) > 5*time.Second || calls.Load() != 1 {
+		t.Errorf("Expected a prompt abort after 1 attempt, took %v and %d attempts", time.Since(start), calls.Load())
+	}
+}
@@ -0,0 +1,177 @@
+package mcp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog/log"
+)
+
+// issueKeyPattern matches Jira issue keys (PROJ-123) anywhere in a text.
+var issueKeyPattern = regexp.MustCompile(`\b[A-Z][A-Z0-9_]+-[0-9]+\b`)
+
+// pseudonymPrefix starts every pseudonymized issue key, personPrefix every
+// pseudonymized person name.
+const (
+	pseudonymPrefix = "ITEM-"
+	personPrefix    = "PERSON-"
+)
+
+// redactedContextKeys are the envelope context and chart workflow fields that
+// carry human-readable Jira names.
+var redactedContextKeys = []string{"board_name", "project_name"}
+
+// anonymizer replaces issue keys in tool results by stable pseudonyms (a keyed
+// hash, so they cannot be reversed by hashing candidate keys) and remembers
+// them, so the agent can pass a pseudonym back to tools taking an issue key.
+type anonymizer struct {
+	salt []byte
+
+	mu   sync.Mutex
+	keys map[string]string // pseudonym → issue key
+}
+
+// newAnonymizer creates an anonymizer. Without a salt, a random one is used,
+// so pseudonyms stay stable only until the server restarts.
+func newAnonymizer(salt string) *anonymizer {
+	a := &anonymizer{salt: []byte(salt), keys: make(map[string]string)}
+	if salt == "" {
+		a.salt = make([]byte, 32)
+		if _, err := rand.Read(a.salt); err != nil {
+			log.Warn().Err(err).Msg("Failed to generate an anonymization salt")
+		}
+	}
+	return a
+}
+
+// pseudonym returns the pseudonym of an issue key.
+func (a *anonymizer) pseudonym(key string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(key))
+	p := pseudonymPrefix + strings.ToUpper(hex.EncodeToString(mac.Sum(nil))[:10])
+
+	a.mu.Lock()
+	a.keys[p] = key
+	a.mu.Unlock()
+	return p
+}
+
+// person returns the pseudonym of a person's name, such as an assignee. Unlike
+// issue keys, person pseudonyms are never resolved back.
+func (a *anonymizer) person(name string) string {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(personPrefix + name))
+	return personPrefix + strings.ToUpper(hex.EncodeToString(mac.Sum(nil))[:10])
+}
+
+// text replaces every issue key in s by its pseudonym.
+func (a *anonymizer) text(s string) string {
+	return issueKeyPattern.ReplaceAllStringFunc(s, func(key string) string {
+		if strings.HasPrefix(key, pseudonymPrefix) {
+			return key
+		}
+		return a.pseudonym(key)
+	})
+}
+
+// issueKey resolves a pseudonym handed back by the agent to its issue key.
+// Anything else is returned unchanged.
+func (a *anonymizer) issueKey(key string) string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if k, ok := a.keys[strings.ToUpper(strings.TrimSpace(key))]; ok {
+		return k
+	}
+	return key
+}
+
+// jsonValue pseudonymizes the issue keys in the JSON encoding of v. Keys only
+// occur inside JSON strings, and a pseudonym needs no escaping, so the
+// rewritten encoding stays valid and keeps the field order of v.
+func (a *anonymizer) jsonValue(v any) (json.RawMessage, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(a.text(string(raw))), nil
+}
+
+// anonymizeResult pseudonymizes the issue keys of a tool result and drops the
+// board and project names from its context when MCS_ANONYMIZE is set. It runs
+// before the result reaches the chart buffer, the Mermaid generators, and the
+// result resources, so none of them sees the original keys.
+func (s *Server) anonymizeResult(data any) any {
+	if s.anonymizer == nil {
+		return data
+	}
+	envelope, ok := data.(ResponseEnvelope)
+	if !ok {
+		return data
+	}
+
+	if envelope.Data != nil {
+		raw, err := s.anonymizer.jsonValue(envelope.Data)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to anonymize tool result; withholding data")
+			raw = json.RawMessage("null")
+		}
+		envelope.Data = raw
+	}
+	envelope.Context = s.anonymizeMap(envelope.Context)
+	envelope.Diagnostics = s.anonymizeMap(envelope.Diagnostics)
+	if g := envelope.Guardrails; g != nil {
+		envelope.Guardrails = &ResponseGuardrails{Insights: s.anonymizeTexts(g.Insights), Warnings: s.anonymizeTexts(g.Warnings)}
+	}
+	return envelope
+}
+
+func (s *Server) anonymizeMap(m map[string]any) map[string]any {
+	if m == nil {
+		return nil
+	}
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		if slices.Contains(redactedContextKeys, k) {
+			continue
+		}
+		raw, err := s.anonymizer.jsonValue(v)
+		if err != nil {
+			continue
+		}
+		out[k] = raw
+	}
+	return out
+}
+
+func (s *Server) anonymizeTexts(texts []string) []string {
+	out := make([]string, len(texts))
+	for i, t := range texts {
+		out[i] = s.anonymizer.text(t)
+	}
+	return out
+}
+
+// anonymizeError pseudonymizes the issue keys of a tool error.
+func (s *Server) anonymizeError(err error) error {
+	if s.anonymizer == nil {
+		return err
+	}
+	return errors.New(s.anonymizer.text(err.Error()))
+}
+
+// resolveIssueKey returns the issue key behind a pseudonym passed as a tool
+// argument, or the argument itself.
+func (s *Server) resolveIssueKey(key string) string {
+	if s.anonymizer == nil {
+		return key
+	}
+	return s.anonymizer.issueKey(key)
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/config"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAnonymizeResult(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir(), Anonymize: true, AnonymizeSalt: "salt"}, &mockJiraClient{})
+	text := func(res *mcp.CallToolResult) string { return res.Content[0].(*mcp.TextContent).Text }
+
+	type row struct {
+		Key    string `json:"key"`
+		Status string `json:"status"`
+	}
+	envelope := WrapResponse([]row{{Key: "PROJ-12", Status: "In Progress"}, {Key: "PROJ-7", Status: "Done"}}, "PROJ", 42,
+		map[string]any{"stale": []string{"PROJ-7"}}, []string{"PROJ-12 is older than the P85 (P50-P85 band)."}, nil)
+	envelope.Context["board_name"] = "ACME Customer Board"
+
+	res, _, _ := handleResult(ctx, s, "analyze_work_item_age", envelope, nil)
+	out := text(res)
+	for _, leaked := range []string{"PROJ-12", "PROJ-7", "ACME Customer Board"} {
+		if strings.Contains(out, leaked) {
+			t.Errorf("Expected %q to be anonymized, got:\n%s", leaked, out)
+		}
+	}
+	if !strings.Contains(out, "P50-P85") || !strings.Contains(out, `"project_key": "PROJ"`) {
+		t.Errorf("Expected percentile bands and the project key to be kept, got:\n%s", out)
+	}
+
+	p := s.anonymizer.pseudonym("PROJ-12")
+	if !strings.Contains(out, p) || p != newAnonymizer("salt").pseudonym("PROJ-12") {
+		t.Errorf("Expected the stable pseudonym %s in the output", p)
+	}
+	if got := s.resolveIssueKey(strings.ToLower(p)); got != "PROJ-12" {
+		t.Errorf("Expected the pseudonym to resolve to PROJ-12, got %q", got)
+	}
+	if got := s.resolveIssueKey("PROJ-99"); got != "PROJ-99" {
+		t.Errorf("Expected a plain issue key to pass through, got %q", got)
+	}
+
+	// Field order of typed results survives the rewrite.
+	var sc struct {
+		Data json.RawMessage `json:"data"`
+	}
+	raw, _ := json.Marshal(res.StructuredContent)
+	if err := json.Unmarshal(raw, &sc); err != nil || !strings.HasPrefix(string(sc.Data), `[{"key":"ITEM-`) {
+		t.Errorf("Expected data rows to keep their field order, got %s (err %v)", sc.Data, err)
+	}
+
+	res, _, _ = handleResult(ctx, s, "analyze_item_journey", nil, errors.New("issue PROJ-12 not found"))
+	if !res.IsError || strings.Contains(text(res), "PROJ-12") {
+		t.Errorf("Expected the error to be anonymized, got %q", text(res))
+	}
+}
+
+func TestAnonymizeResult_Disabled(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	res, _, _ := handleResult(ctx, s, "analyze_work_item_age", WrapResponse(map[string]string{"key": "PROJ-12"}, "", 0, nil, nil, nil), nil)
+	if !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "PROJ-12") {
+		t.Error("Expected issue keys to be kept without MCS_ANONYMIZE")
+	}
+}
@@ -0,0 +1,135 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// apiClient identifies calls through the Go API (pkg/mcsanalytics) in the
+// audit trail.
+const apiClient = "mcsanalytics"
+
+// Ingest hydrates the event log of a board (catching up a cached one) and
+// summarizes its history, as import_board_context does. Unlike the tool, a
+// failed hydration is an error. progress receives the ingestion progress; it
+// may be nil.
+func (s *Server) Ingest(ctx context.Context, projectKey string, boardID int, progress func(fetched, total int, message string)) (stats.MetadataSummary, error) {
+	ctx = directContext(ctx, "import_board_context", apiClient, progress)
+	sourceID := getCombinedID(projectKey, boardID)
+
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
+	if err != nil {
+		return stats.MetadataSummary{}, err
+	}
+	if err := s.anchorContext(sc.ProjectKey, sc.BoardID); err != nil {
+		return stats.MetadataSummary{}, err
+	}
+	reg, err := s.events.Hydrate(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
+	if err != nil {
+		return stats.MetadataSummary{}, fmt.Errorf("ingestion of %s failed: %w", sourceID, err)
+	}
+	s.activeRegistry = reg
+	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+		return stats.MetadataSummary{}, fmt.Errorf("failed to save workflow metadata: %w", err)
+	}
+	return s.probeSource(ctx, sourceID), nil
+}
+
+// Forecast runs forecast_monte_carlo for the board of args and returns the
+// forecast with its guardrails. The call goes through the parameter
+// middleware of the tools (withToolOptions), so as_of_date, the history
+// window, and the policies of args apply as they do for the tool. Portfolio
+// sources and dry_run are tool-only.
+func (s *Server) Forecast(ctx context.Context, args ForecastMonteCarloInput) (simulation.Result, ResponseGuardrails, error) {
+	ctx = directContext(ctx, "forecast_monte_carlo", apiClient, nil)
+	switch {
+	case len(args.Sources) > 0:
+		return simulation.Result{}, ResponseGuardrails{}, fmt.Errorf("portfolio forecasts (sources) are not supported by the Go API")
+	case args.DryRun:
+		return simulation.Result{}, ResponseGuardrails{}, fmt.Errorf("dry_run is not supported by the Go API")
+	}
+
+	var res any
+	var err error
+	rejected, _, _ := withToolOptions(s, func(ctx context.Context, _ *mcp.CallToolRequest, args ForecastMonteCarloInput) (*mcp.CallToolResult, any, error) {
+		res, err = s.handleForecastMonteCarlo(ctx, args)
+		return nil, nil, nil
+	})(ctx, nil, args)
+	if rejected != nil && rejected.IsError {
+		return simulation.Result{}, ResponseGuardrails{}, errors.New(resultText(rejected))
+	}
+	if err != nil {
+		return simulation.Result{}, ResponseGuardrails{}, err
+	}
+	env, _ := res.(ResponseEnvelope)
+	result, ok := env.Data.(simulation.Result)
+	if !ok {
+		return simulation.Result{}, ResponseGuardrails{}, fmt.Errorf("forecast returned %T instead of a simulation result", env.Data)
+	}
+	var guardrails ResponseGuardrails
+	if env.Guardrails != nil {
+		guardrails = *env.Guardrails
+	}
+	return result, guardrails, nil
+}
+
+// UseInstance makes a named Jira instance ("" for the default one) the
+// connection of the following calls.
+func (s *Server) UseInstance(name string) error {
+	return s.useInstance(name)
+}
+
+// inProcessVersion is the server and client version of the in-memory sessions
+// of CallTool. Their callers parse the full result, so the response budget
+// does not apply to them.
+const inProcessVersion = "local"
+
+// CallTool runs a tool in-process over an in-memory MCP session, with the
+// middleware and result processing of a call from an MCP client, and returns
+// its structured result (the response envelope) as JSON. client names the
+// caller in the audit trail. A failed call is returned as an error.
+func (s *Server) CallTool(ctx context.Context, client, name string, args any) (json.RawMessage, error) {
+	mcpSrv, err := NewMCPServer(s, inProcessVersion)
+	if err != nil {
+		return nil, err
+	}
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := mcpSrv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer ss.Close()
+	cs, err := mcp.NewClient(&mcp.Implementation{Name: client, Version: inProcessVersion}, nil).Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer cs.Close()
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		return nil, err
+	}
+	if res.IsError {
+		return nil, fmt.Errorf("%s: %s", name, resultText(res))
+	}
+	return json.Marshal(res.StructuredContent)
+}
+
+// resultText joins the text content of a tool result.
+func resultText(res *mcp.CallToolResult) string {
+	var msgs []string
+	for _, c := range res.Content {
+		if text, ok := c.(*mcp.TextContent); ok {
+			msgs = append(msgs, text.Text)
+		}
+	}
+	return strings.Join(msgs, "; ")
+}
@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/stats"
+)
+
+func TestCallTool(t *testing.T) {
+	srv := newGoldenServer(t)
+	ctx := context.Background()
+
+	raw, err := srv.CallTool(ctx, "test-cli", "analyze_cycle_time", map[string]any{"project_key": testProject, "board_id": testBoard})
+	if err != nil {
+		t.Fatalf("CallTool: %v", err)
+	}
+	var env struct {
+		Context map[string]any `json:"context"`
+		Data    struct {
+			Percentiles map[string]float64 `json:"percentiles"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		t.Fatalf("Expected the response envelope, got %s (%v)", raw, err)
+	}
+	if env.Context["project_key"] != testProject || env.Data.Percentiles["likely"] <= 0 {
+		t.Errorf("Expected the cycle times of %s, got %s", testProject, raw)
+	}
+
+	_, err = srv.CallTool(ctx, "test-cli", "forecast_monte_carlo", map[string]any{"project_key": testProject, "board_id": testBoard, "mode": "bogus"})
+	if err == nil || !strings.Contains(err.Error(), "forecast_monte_carlo") {
+		t.Errorf("Expected the invalid mode reported as an error, got %v", err)
+	}
+}
+
+func TestForecast_AppliesToolOptions(t *testing.T) {
+	srv := newGoldenServer(t)
+	ctx := context.Background()
+	args := ForecastMonteCarloInput{ProjectKey: testProject, BoardID: testBoard, Mode: SimModeDuration, Targets: map[string]int{"Story": 10}}
+
+	plain, _, err := srv.Forecast(ctx, args)
+	if err != nil {
+		t.Fatalf("Forecast: %v", err)
+	}
+	if _, ok := plain.Context["outliers"]; ok {
+		t.Errorf("Expected no outlier trimming by default, got %v", plain.Context["outliers"])
+	}
+
+	args.OutlierOption = OutlierOption{Outliers: stats.OutliersIQR}
+	trimmed, _, err := srv.Forecast(ctx, args)
+	if err != nil {
+		t.Fatalf("Forecast (iqr): %v", err)
+	}
+	if _, ok := trimmed.Context["outliers"]; !ok {
+		t.Errorf("Expected the outliers policy of the call to apply, got context %v", trimmed.Context)
+	}
+
+	args.OutlierOption = OutlierOption{Outliers: "bogus"}
+	if _, _, err := srv.Forecast(ctx, args); err == nil {
+		t.Error("Expected an invalid outliers policy to be rejected")
+	}
+	args.OutlierOption = OutlierOption{}
+	args.AsOfOption = AsOfOption{AsOfDate: "2999-01-01"}
+	if _, _, err := srv.Forecast(ctx, args); err == nil || !strings.Contains(err.Error(), "future") {
+		t.Errorf("Expected a future as_of_date to be rejected, got %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// asOfDated is implemented by tool inputs that embed AsOfOption.
+type asOfDated interface {
+	asOfDate() string
+}
+
+func (o AsOfOption) asOfDate() string { return o.AsOfDate }
+
+// withAsOfDate validates the as_of_date parameter of a tool input and makes
+// it the clock of the call: the event log is projected only up to that date,
+// and every window and age is measured from it.
+func withAsOfDate[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		ad, ok := any(args).(asOfDated)
+		if !ok || ad.asOfDate() == "" {
+			return handler(ctx, req, args)
+		}
+		t, err := parseAsOfDate(ad.asOfDate())
+		if err != nil {
+			return formatToolError(err), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.asOf = &t }), req, args)
+	}
+}
+
+// parseAsOfDate parses an as_of_date, which must not lie in the future.
+func parseAsOfDate(date string) (time.Time, error) {
+	t, err := time.Parse(stats.DateFormat, date)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid as_of_date format: %w", err)
+	}
+	if t.After(time.Now()) {
+		return time.Time{}, fmt.Errorf("as_of_date %s is in the future", date)
+	}
+	return t, nil
+}
+
+// callAsOfDate returns the as_of_date of the call ctx belongs to, or nil.
+func callAsOfDate(ctx context.Context) *time.Time {
+	return callStateOf(ctx).asOf
+}
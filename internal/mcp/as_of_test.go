@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestAsOfDate(t *testing.T) {
+	srv := newGoldenServer(t)
+	srv.simulationSeed = 42
+	mcpSrv, err := NewMCPServer(srv, "test")
+	if err != nil {
+		t.Fatalf("NewMCPServer: %v", err)
+	}
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := mcpSrv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	defer ss.Close()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{})
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer cs.Close()
+
+	call := func(tool string, args map[string]any) (*mcp.CallToolResult, map[string]any) {
+		t.Helper()
+		args["project_key"], args["board_id"] = testProject, testBoard
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: tool, Arguments: args})
+		if err != nil {
+			t.Fatalf("%s: %v", tool, err)
+		}
+		raw, _ := json.Marshal(res.StructuredContent)
+		var envelope map[string]any
+		_ = json.Unmarshal(raw, &envelope)
+		return res, envelope
+	}
+
+	now := srv.Clock(ctx)
+	asOf := now.AddDate(0, 0, -60).Format(stats.DateFormat)
+	asOfTime, _ := time.Parse(stats.DateFormat, asOf)
+
+	res, envelope := call("analyze_throughput", map[string]any{"as_of_date": asOf})
+	if res.IsError {
+		t.Fatalf("analyze_throughput as of %s failed: %+v", asOf, res.Content)
+	}
+	resCtx := envelope["context"].(map[string]any)
+	if resCtx["as_of_date"] != asOf || resCtx["session_window"].(map[string]any)["end"] != asOf {
+		t.Errorf("Expected the window to end on the as-of date %s, got %v", asOf, resCtx)
+	}
+
+	res, envelope = call("forecast_monte_carlo", map[string]any{"mode": "duration", "include_existing_backlog": true, "include_wip": true, "as_of_date": asOf})
+	if res.IsError {
+		t.Fatalf("forecast_monte_carlo as of %s failed: %+v", asOf, res.Content)
+	}
+	dates := envelope["data"].(map[string]any)["context"].(map[string]any)["completion_dates"].(map[string]any)
+	for label, d := range dates {
+		date, _ := time.Parse(stats.DateFormat, d.(string))
+		if !date.After(asOfTime) || date.After(asOfTime.AddDate(1, 0, 0)) {
+			t.Errorf("Expected %s to count from the as-of date, got %s", label, d)
+		}
+	}
+
+	if !srv.Clock(ctx).Equal(now) {
+		t.Errorf("Expected the as-of date to end with the call, got %v", srv.Clock(ctx))
+	}
+
+	for name, args := range map[string]map[string]any{
+		"future":      {"as_of_date": time.Now().AddDate(0, 0, 2).Format(stats.DateFormat)},
+		"malformed":   {"as_of_date": "01/02/2024"},
+		"sprint_mode": {"as_of_date": asOf, "mode": "duration", "sprint_mode": true},
+	} {
+		if res, _ := call("forecast_monte_carlo", args); !res.IsError {
+			t.Errorf("Expected a %s as_of_date to be rejected", name)
+		}
+	}
+}
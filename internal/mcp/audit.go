@@ -0,0 +1,255 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultAuditLimit is the number of audit entries workflow_get_audit returns
+// when no limit is given.
+const DefaultAuditLimit = 50
+
+// auditFields are the parts of the workflow metadata the audit trail tracks:
+// the semantic configuration every forecast and diagnostic depends on.
+var auditFields = []string{
+	"mapping", "resolutions", "commitment_point", "type_commitment_points",
+	"status_order", "sles", "wip_limits", "alert_rules", "type_aliases", "settings", "forecast_templates",
+	"evaluation_date",
+}
+
+// AuditEntry records one change of a board's semantic configuration.
+type AuditEntry struct {
+	Time     time.Time       `json:"time"`
+	Tool     string          `json:"tool,omitempty"`
+	Client   string          `json:"client,omitempty"` // MCP client of the call, "name/version"
+	SourceID string          `json:"source_id"`
+	Field    string          `json:"field"`
+	Previous json.RawMessage `json:"previous,omitempty"` // omitted when the field was unset
+	Value    json.RawMessage `json:"value,omitempty"`    // omitted when the field was cleared
+}
+
+// callInfo identifies a tool call in the audit trail.
+type callInfo struct {
+	tool   string
+	client string
+}
+
+// identifyCall returns the tool name and the client of a call.
+func identifyCall(tool string, req *mcp.CallToolRequest) callInfo {
+	info := callInfo{tool: tool}
+	if req != nil && req.Session != nil {
+		if params := req.Session.InitializeParams(); params != nil && params.ClientInfo != nil {
+			info.client = params.ClientInfo.Name
+			if params.ClientInfo.Version != "" {
+				info.client += "/" + params.ClientInfo.Version
+			}
+		}
+	}
+	return info
+}
+
+// configSnapshot captures the audited fields of the active workflow metadata.
+func (s *Server) configSnapshot() map[string]json.RawMessage {
+	return snapshotFields(map[string]any{
+		"mapping":                s.activeMapping,
+		"resolutions":            s.activeResolutions,
+		"commitment_point":       s.activeCommitmentPoint,
+		"type_commitment_points": s.activeTypeCommitments,
+		"status_order":           s.activeStatusOrder,
+		"sles":                   s.activeSLEs,
+		"wip_limits":             s.activeWIPLimits,
+		"alert_rules":            s.activeAlertRules,
+		"type_aliases":           s.activeTypeAliases,
+		"settings":               s.activeSettings,
+		"forecast_templates":     s.activeForecastTemplates,
+		"evaluation_date":        s.activeEvaluationDate,
+	})
+}
+
+// workflowSnapshot captures the audited fields of persisted workflow metadata,
+// for changes that bypass the active configuration (import_workspace).
+func workflowSnapshot(meta WorkflowMetadata) map[string]json.RawMessage {
+	var settings SourceSettings
+	if meta.Settings != nil {
+		settings = *meta.Settings
+	}
+	return snapshotFields(map[string]any{
+		"mapping":                meta.Mapping,
+		"resolutions":            meta.Resolutions,
+		"commitment_point":       meta.CommitmentPoint,
+		"type_commitment_points": meta.TypeCommitmentPoints,
+		"status_order":           meta.StatusOrder,
+		"sles":                   meta.SLEs,
+		"wip_limits":             meta.WIPLimits,
+		"alert_rules":            meta.AlertRules,
+		"type_aliases":           meta.TypeAliases,
+		"settings":               settings,
+		"forecast_templates":     meta.ForecastTemplates,
+		"evaluation_date":        meta.EvaluationDate,
+	})
+}
+
+func snapshotFields(values map[string]any) map[string]json.RawMessage {
+	snap := make(map[string]json.RawMessage, len(values))
+	for field, v := range values {
+		raw, err := json.Marshal(v)
+		if err != nil {
+			continue
+		}
+		// Unset and empty values read the same in the trail.
+		switch string(raw) {
+		case "null", `""`, "{}", "[]":
+			raw = nil
+		}
+		snap[field] = raw
+	}
+	return snap
+}
+
+// recordAudit appends an entry to the board's audit trail for each field of
+// the active configuration that differs from before. It is called by the
+// set_* handlers once the change is persisted. A failed write is logged; the
+// change itself stands.
+func (s *Server) recordAudit(ctx context.Context, projectKey string, boardID int, before map[string]json.RawMessage) {
+	s.recordAuditChanges(ctx, getCombinedID(projectKey, boardID), before, s.configSnapshot())
+}
+
+// recordAuditChanges appends an entry to the source's audit trail for each
+// field that differs between the two snapshots.
+func (s *Server) recordAuditChanges(ctx context.Context, sourceID string, before, after map[string]json.RawMessage) {
+	if s.cacheDir == "" {
+		return
+	}
+	call := callStateOf(ctx).info
+
+	now := time.Now().UTC()
+	var entries []AuditEntry
+	for _, field := range auditFields {
+		if bytes.Equal(before[field], after[field]) {
+			continue
+		}
+		entries = append(entries, AuditEntry{
+			Time:     now,
+			Tool:     call.tool,
+			Client:   call.client,
+			SourceID: sourceID,
+			Field:    field,
+			Previous: before[field],
+			Value:    after[field],
+		})
+	}
+	if len(entries) == 0 {
+		return
+	}
+	if err := appendAudit(s.sourceAuditPath(sourceID), entries); err != nil {
+		log.Error().Err(err).Str("source", sourceID).Msg("Failed to write audit trail")
+	}
+}
+
+func (s *Server) auditPath(projectKey string, boardID int) string {
+	return s.sourceAuditPath(getCombinedID(projectKey, boardID))
+}
+
+func (s *Server) sourceAuditPath(sourceID string) string {
+	return filepath.Join(s.cacheDir, fmt.Sprintf("%s_audit.jsonl", sourceID))
+}
+
+// appendAudit appends entries to an audit trail file. The file is only ever
+// appended to.
+func appendAudit(path string, entries []AuditEntry) error {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	encoder := json.NewEncoder(file)
+	for _, e := range entries {
+		if err := encoder.Encode(e); err != nil {
+			file.Close()
+			return err
+		}
+	}
+	return file.Close()
+}
+
+// readAudit reads an audit trail file in append order. A missing file reads
+// as an empty trail.
+func readAudit(path string) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to open audit trail: %w", err)
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // mappings of large workflows make long lines
+	for scanner.Scan() {
+		var e AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			log.Warn().Err(err).Str("path", path).Msg("Skipping invalid line in audit trail")
+			continue
+		}
+		entries = append(entries, e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading audit trail: %w", err)
+	}
+	return entries, nil
+}
+
+// handleGetWorkflowAudit returns the audit trail of a board, newest first,
+// optionally narrowed to one configuration field.
+func (s *Server) handleGetWorkflowAudit(projectKey string, boardID int, field string, limit int) (any, error) {
+	if field != "" && !slices.Contains(auditFields, field) {
+		return nil, fmt.Errorf("invalid field %q: expected one of %s", field, strings.Join(auditFields, ", "))
+	}
+	if limit < 0 {
+		return nil, fmt.Errorf("limit must not be negative")
+	}
+	if limit == 0 {
+		limit = DefaultAuditLimit
+	}
+	if s.cacheDir == "" {
+		return nil, fmt.Errorf("no cache directory configured; the audit trail is not recorded")
+	}
+
+	entries, err := readAudit(s.auditPath(projectKey, boardID))
+	if err != nil {
+		return nil, err
+	}
+	if field != "" {
+		entries = slices.DeleteFunc(entries, func(e AuditEntry) bool { return e.Field != field })
+	}
+	total := len(entries)
+	slices.Reverse(entries)
+	if len(entries) > limit {
+		entries = entries[:limit]
+	}
+	if entries == nil {
+		entries = []AuditEntry{}
+	}
+
+	var warnings []string
+	if total == 0 {
+		warnings = append(warnings, fmt.Sprintf("No configuration changes are recorded for %s. Changes are audited from the first set_* call after the audit trail was introduced.", getCombinedID(projectKey, boardID)))
+	}
+	res := map[string]any{
+		"entries": entries,
+		"total":   total,
+	}
+	return WrapResponse(res, projectKey, boardID, nil, warnings, s.guidanceFor("workflow_get_audit", guidanceFacts{})), nil
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+)
+
+func TestWorkflowAudit(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	if _, err := srv.handleSetSLE(ctx, testProject, testBoard, "Story", 85, 10, false); err != nil {
+		t.Fatalf("set_sle: %v", err)
+	}
+	if _, err := srv.handleSetSLE(ctx, testProject, testBoard, "Story", 85, 12, false); err != nil {
+		t.Fatalf("set_sle: %v", err)
+	}
+	if _, err := srv.handleSetWIPLimits(ctx, testProject, testBoard, []WIPLimitEntry{{Status: "In Progress", Limit: 3}}, false); err != nil {
+		t.Fatalf("set_wip_limits: %v", err)
+	}
+
+	res, err := srv.handleGetWorkflowAudit(testProject, testBoard, "", 0)
+	if err != nil {
+		t.Fatalf("workflow_get_audit: %v", err)
+	}
+	data := res.(ResponseEnvelope).Data.(map[string]any)
+	entries := data["entries"].([]AuditEntry)
+	if data["total"] != 3 || len(entries) != 3 {
+		t.Fatalf("Expected 3 entries, got %d of %v", len(entries), data["total"])
+	}
+	if entries[0].Field != "wip_limits" || entries[0].Previous != nil {
+		t.Errorf("Expected the WIP limits first, with no previous value, got %+v", entries[0])
+	}
+
+	res, err = srv.handleGetWorkflowAudit(testProject, testBoard, "sles", 1)
+	if err != nil {
+		t.Fatalf("workflow_get_audit sles: %v", err)
+	}
+	data = res.(ResponseEnvelope).Data.(map[string]any)
+	entries = data["entries"].([]AuditEntry)
+	if data["total"] != 2 || len(entries) != 1 {
+		t.Fatalf("Expected the latest of 2 SLE entries, got %d of %v", len(entries), data["total"])
+	}
+	var previous, value map[string]struct {
+		DurationDays float64 `json:"duration_days"`
+	}
+	if err := json.Unmarshal(entries[0].Previous, &previous); err != nil {
+		t.Fatalf("previous: %v", err)
+	}
+	if err := json.Unmarshal(entries[0].Value, &value); err != nil {
+		t.Fatalf("value: %v", err)
+	}
+	if previous["Story"].DurationDays != 10 || value["Story"].DurationDays != 12 {
+		t.Errorf("Expected the SLE to change from 10 to 12 days, got %s -> %s", entries[0].Previous, entries[0].Value)
+	}
+
+	if _, err := srv.handleGetWorkflowAudit(testProject, testBoard, "discovery_cutoff", 0); err == nil {
+		t.Errorf("Expected an unaudited field to be rejected")
+	}
+}
+
+// auditTrail returns the audit entries of one field, newest first.
+func auditTrail(t *testing.T, srv *Server, field string) []AuditEntry {
+	t.Helper()
+	res, err := srv.handleGetWorkflowAudit(testProject, testBoard, field, 0)
+	if err != nil {
+		t.Fatalf("workflow_get_audit %s: %v", field, err)
+	}
+	return res.(ResponseEnvelope).Data.(map[string]any)["entries"].([]AuditEntry)
+}
+
+func TestWorkflowAudit_Settings(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	if _, err := srv.handleSetSourceSettings(ctx, testProject, testBoard, SourceSettings{WindowWeeks: 12}, false); err != nil {
+		t.Fatalf("set_source_settings: %v", err)
+	}
+	if _, err := srv.handleSetSourceSettings(ctx, testProject, testBoard, SourceSettings{}, true); err != nil {
+		t.Fatalf("set_source_settings reset: %v", err)
+	}
+	entries := auditTrail(t, srv, "settings")
+	if len(entries) != 2 || entries[0].Value != nil || entries[1].Previous != nil {
+		t.Fatalf("Expected the settings to be set and reset, got %+v", entries)
+	}
+	var set SourceSettings
+	if err := json.Unmarshal(entries[1].Value, &set); err != nil || set.WindowWeeks != 12 {
+		t.Errorf("Expected window_weeks 12 in the trail, got %s", entries[1].Value)
+	}
+}
+
+func TestWorkflowAudit_AlertRules(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	if _, err := srv.handleSetAlertRules(ctx, testProject, testBoard, []AlertRuleEntry{{Metric: "wip_count", Operator: ">", Threshold: 10}}, nil, false); err != nil {
+		t.Fatalf("set_alert_rules: %v", err)
+	}
+	if _, err := srv.handleSetAlertRules(ctx, testProject, testBoard, nil, nil, true); err != nil {
+		t.Fatalf("set_alert_rules clear: %v", err)
+	}
+	entries := auditTrail(t, srv, "alert_rules")
+	if len(entries) != 2 || entries[0].Value != nil || entries[1].Previous != nil || entries[1].Value == nil {
+		t.Fatalf("Expected the alert rules to be recorded and cleared, got %+v", entries)
+	}
+	if source := getCombinedID(testProject, testBoard); entries[1].SourceID != source {
+		t.Errorf("Expected the entry for %s, got %+v", source, entries[1])
+	}
+}
+
+func TestWorkflowAudit_ForecastTemplates(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	if _, err := srv.handleSaveForecastTemplate(ctx, testProject, testBoard, ForecastTemplate{Name: "roadmap", HistoryWindowDays: 60}, false); err != nil {
+		t.Fatalf("save_forecast_template: %v", err)
+	}
+	if _, err := srv.handleSaveForecastTemplate(ctx, testProject, testBoard, ForecastTemplate{Name: "roadmap"}, true); err != nil {
+		t.Fatalf("save_forecast_template remove: %v", err)
+	}
+	entries := auditTrail(t, srv, "forecast_templates")
+	if len(entries) != 2 || entries[0].Value != nil || entries[1].Previous != nil {
+		t.Fatalf("Expected the template to be saved and removed, got %+v", entries)
+	}
+	var templates map[string]ForecastTemplate
+	if err := json.Unmarshal(entries[1].Value, &templates); err != nil || templates["roadmap"].HistoryWindowDays != 60 {
+		t.Errorf("Expected the roadmap template in the trail, got %s", entries[1].Value)
+	}
+}
@@ -0,0 +1,122 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/jira"
+
+	"github.com/rs/zerolog/log"
+)
+
+// SourceSync is the outcome of one source in a background sync pass.
+type SourceSync struct {
+	Instance string // Named Jira instance; empty = default
+	SourceID string
+	Fetched  int
+	Err      error
+}
+
+// knownSources returns the sources with an event log in the cache directory,
+// sorted by source ID. MCSTEST sources are never synced with Jira.
+func (s *Server) knownSources() ([]PortfolioSource, error) {
+	if s.cacheDir == "" {
+		return nil, nil
+	}
+	entries, err := os.ReadDir(s.cacheDir)
+	if err != nil {
+		return nil, err
+	}
+	var sources []PortfolioSource
+	for _, e := range entries {
+		sourceID, ok := strings.CutSuffix(e.Name(), ".jsonl")
+		if !ok || e.IsDir() {
+			continue
+		}
+		// Only {projectKey}_{boardID}.jsonl is an event log; this skips
+		// other JSONL files such as forecast registries.
+		i := strings.LastIndex(sourceID, "_")
+		if i <= 0 {
+			continue
+		}
+		boardID, err := strconv.Atoi(sourceID[i+1:])
+		if err != nil || sourceID[:i] == "MCSTEST" {
+			continue
+		}
+		sources = append(sources, PortfolioSource{ProjectKey: sourceID[:i], BoardID: boardID})
+	}
+	slices.SortFunc(sources, func(a, b PortfolioSource) int {
+		return strings.Compare(getCombinedID(a.ProjectKey, a.BoardID), getCombinedID(b.ProjectKey, b.BoardID))
+	})
+	return sources, nil
+}
+
+// SyncKnownSources runs the import_history_update catch-up for every cached
+// source of every Jira instance, so that a server sharing the cache directory
+// serves tool calls from a warm cache. It does not anchor a context or touch
+// workflow metadata; the serving process picks up the rewritten event logs by
+// modification time. A failing source is reported and does not stop the others.
+func (s *Server) SyncKnownSources(ctx context.Context) ([]SourceSync, error) {
+	var results []SourceSync
+	for _, name := range s.instanceNames() {
+		if err := s.useInstance(name); err != nil {
+			return results, err
+		}
+		sources, err := s.knownSources()
+		if err != nil {
+			return results, err
+		}
+
+		for _, src := range sources {
+			if ctx.Err() != nil {
+				return results, ctx.Err()
+			}
+			res := SourceSync{Instance: s.namedInstance(), SourceID: getCombinedID(src.ProjectKey, src.BoardID)}
+			sc, err := s.resolveSourceContext(ctx, src.ProjectKey, src.BoardID)
+			if err == nil {
+				res.Fetched, _, _, err = s.events.CatchUp(ctx, res.SourceID, src.ProjectKey, sc.JQL, nil)
+			}
+			res.Err = err
+			if err != nil {
+				log.Warn().Err(err).Str("instance", name).Str("source", res.SourceID).Msg("Background sync failed")
+			} else {
+				log.Info().Str("instance", name).Str("source", res.SourceID).Int("fetched", res.Fetched).Msg("Background sync complete")
+			}
+			results = append(results, res)
+
+			// Keep memory flat across passes; each source is reloaded from disk when needed.
+			s.events.PruneExcept("")
+		}
+	}
+	return results, nil
+}
+
+// EnableWebhooks marks the start of a Jira webhook receiver feeding
+// ApplyWebhook. Sources this server syncs from now on are kept current by
+// webhooks instead of delta syncs on every stale tool call.
+func (s *Server) EnableWebhooks() {
+	now := time.Now()
+	for _, inst := range s.instances {
+		inst.events.EnableWebhooks(now)
+	}
+}
+
+// ApplyWebhook applies a Jira issue webhook of an instance ("" = default) to
+// the sources this server synced since the receiver started.
+func (s *Server) ApplyWebhook(ctx context.Context, instance string, hook jira.WebhookDTO) error {
+	if instance == "" {
+		instance = config.DefaultInstance
+	}
+	inst, ok := s.instances[instance]
+	if !ok {
+		return fmt.Errorf("unknown Jira instance %q", instance)
+	}
+	_, err := inst.events.ApplyWebhook(ctx, hook)
+	return err
+}
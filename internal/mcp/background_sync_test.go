@@ -0,0 +1,28 @@
+package mcp
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcs-mcp/internal/config"
+)
+
+func TestKnownSources(t *testing.T) {
+	cacheDir := t.TempDir()
+	for _, name := range []string{"PROJ_12.jsonl", "PROJ_12.sync.json", "PROJ_12_forecasts.jsonl", "JQL_123.jsonl", "MCSTEST_0.jsonl", "notes.jsonl"} {
+		if err := os.WriteFile(filepath.Join(cacheDir, name), []byte("{}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := NewServer(&config.AppConfig{CacheDir: cacheDir}, &mockJiraClient{})
+
+	sources, err := s.knownSources()
+	if err != nil {
+		t.Fatalf("knownSources: %v", err)
+	}
+	want := []PortfolioSource{{ProjectKey: "JQL", BoardID: 123}, {ProjectKey: "PROJ", BoardID: 12}}
+	if len(sources) != len(want) || sources[0] != want[0] || sources[1] != want[1] {
+		t.Errorf("Expected only the event logs of JQL_123 and PROJ_12, got %+v", sources)
+	}
+}
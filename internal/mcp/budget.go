@@ -0,0 +1,224 @@
+package mcp
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"mcs-mcp/internal/i18n"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// budgetKeep is the number of items a list keeps when it is cut to fit the
+// response budget.
+const budgetKeep = 10
+
+// budgeted is implemented by tool inputs that embed ResponseBudget.
+type budgeted interface {
+	maxBytesOption() int
+}
+
+func (b ResponseBudget) maxBytesOption() int { return b.MaxBytes }
+
+// withResponseBudget validates the max_bytes parameter of a tool input and
+// makes it the response budget for the duration of the call.
+func withResponseBudget[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		b, ok := any(args).(budgeted)
+		if !ok || b.maxBytesOption() == 0 {
+			return handler(ctx, req, args)
+		}
+		if b.maxBytesOption() < 0 {
+			return formatToolError(fmt.Errorf("max_bytes must not be negative")), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.budget = b.maxBytesOption() }), req, args)
+	}
+}
+
+// responseBudget returns the size budget of tool results in bytes: the
+// max_bytes parameter of the call, else the MCS_MAX_RESPONSE_BYTES setting.
+// 0 means no budget, as for in-process calls (see CallTool), whose callers
+// parse the full result.
+func (s *Server) responseBudget(ctx context.Context) int {
+	st := callStateOf(ctx)
+	if strings.HasSuffix(st.info.client, "/"+inProcessVersion) {
+		return 0
+	}
+	if st.budget > 0 {
+		return st.budget
+	}
+	return s.maxResponseBytes
+}
+
+// Truncation records a list of a result that was cut to fit the response
+// budget.
+type Truncation struct {
+	Path  string `json:"path"`  // Field of the data, e.g. "aging" or "stratified.Story.items"
+	Total int    `json:"total"` // Items before the cut
+	Kept  int    `json:"kept"`
+}
+
+// budgetResult keeps a result within the response budget. While the tool
+// result is larger (see resultSize), the list of its data with the most bytes is cut to budgetKeep
+// items: item lists keep their most extreme items (see salientFirst), other
+// lists their first ones. A cut result carries context.truncated and
+// context.truncation, and a warning on how to request specific slices.
+func (s *Server) budgetResult(ctx context.Context, data any) any {
+	budget := s.responseBudget(ctx)
+	envelope, ok := data.(ResponseEnvelope)
+	if budget <= 0 || !ok || envelope.Data == nil {
+		return data
+	}
+	size, err := s.resultSize(ctx, envelope)
+	if err != nil || size <= budget {
+		return data
+	}
+
+	rawData, err := json.Marshal(envelope.Data)
+	if err != nil {
+		return data
+	}
+	dec := json.NewDecoder(bytes.NewReader(rawData))
+	dec.UseNumber()
+	var tree any
+	if err := dec.Decode(&tree); err != nil {
+		log.Warn().Err(err).Msg("Failed to decode tool result for the response budget")
+		return data
+	}
+
+	var cuts []Truncation
+	for size > budget {
+		var lists []budgetList
+		collectLists(tree, "", &lists)
+		if len(lists) == 0 {
+			break
+		}
+		longest := slices.MaxFunc(lists, func(a, b budgetList) int { return cmp.Compare(a.size, b.size) })
+		longest.set(salientFirst(longest.path, longest.items)[:budgetKeep])
+		cuts = append(cuts, Truncation{Path: longest.path, Total: len(longest.items), Kept: budgetKeep})
+
+		envelope.Data = tree
+		if size, err = s.resultSize(ctx, envelope); err != nil {
+			return data
+		}
+	}
+	if len(cuts) == 0 {
+		return data
+	}
+
+	if envelope.Context == nil {
+		envelope.Context = map[string]any{}
+	}
+	envelope.Context["truncated"] = true
+	envelope.Context["truncation"] = cuts
+	if envelope.Guardrails == nil {
+		envelope.Guardrails = &ResponseGuardrails{Insights: []string{}, Warnings: []string{}}
+	}
+	warning := fmt.Sprintf("TRUNCATED: the result exceeded the response budget of %d bytes, so %d list(s) were cut to %d items each (item lists keep their most extreme items); see context.truncation. Request specific slices instead — limit, offset, and sort_by on item lists, a narrower history window, or issue_types — or raise max_bytes for this call.", budget, len(cuts), budgetKeep)
+	envelope.Guardrails.Warnings = append(envelope.Guardrails.Warnings, i18n.Translate(s.resultLocale(), warning))
+	return envelope
+}
+
+// resultSize returns the size of the tool result formatToolResult makes of an
+// envelope: the text content in the format of the call plus the envelope
+// again as structuredContent.
+func (s *Server) resultSize(ctx context.Context, envelope ResponseEnvelope) (int, error) {
+	raw, err := json.Marshal(structuredContent(envelope))
+	if err != nil {
+		return 0, err
+	}
+	return len(s.formatResult(ctx, envelope)) + len(raw), nil
+}
+
+// budgetList is a list of a decoded result that is long enough to be cut.
+type budgetList struct {
+	path  string
+	items []any
+	size  int
+	set   func([]any)
+}
+
+// collectLists adds the lists of node longer than budgetKeep to lists, in
+// field order, with path naming their place in the data.
+func collectLists(node any, path string, lists *[]budgetList) {
+	add := func(p string, items []any, set func([]any)) {
+		if len(items) <= budgetKeep {
+			return
+		}
+		raw, _ := json.Marshal(items)
+		*lists = append(*lists, budgetList{path: p, items: items, size: len(raw), set: set})
+	}
+	switch v := node.(type) {
+	case map[string]any:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		for _, k := range keys {
+			p := k
+			if path != "" {
+				p = path + "." + k
+			}
+			if items, ok := v[k].([]any); ok {
+				add(p, items, func(cut []any) { v[k] = cut })
+			}
+			collectLists(v[k], p, lists)
+		}
+	case []any:
+		for i, item := range v {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			if items, ok := item.([]any); ok {
+				add(p, items, func(cut []any) { v[i] = cut })
+			}
+			collectLists(item, p, lists)
+		}
+	}
+}
+
+// salientFirst orders the items of the item lists of results so a cut keeps
+// the ones an agent looks for first: aging items by percentile and age,
+// scatterplot points by cycle time, highest first. Other lists keep their
+// order, which their tools already rank or keep chronological.
+func salientFirst(path string, items []any) []any {
+	name := path[strings.LastIndex(path, ".")+1:]
+	var keys []string
+	switch name {
+	case "aging":
+		keys = []string{"percentile", "age_since_commitment_days", "total_age_since_creation_days"}
+	case "points", "scatterplot":
+		keys = []string{"value"}
+	default:
+		return items
+	}
+	sorted := slices.Clone(items)
+	slices.SortStableFunc(sorted, func(a, b any) int {
+		for _, key := range keys {
+			if c := cmp.Compare(numberField(b, key), numberField(a, key)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	})
+	return sorted
+}
+
+// numberField returns the number in field key of a decoded object, or 0.
+func numberField(item any, key string) float64 {
+	obj, ok := item.(map[string]any)
+	if !ok {
+		return 0
+	}
+	n, ok := obj[key].(json.Number)
+	if !ok {
+		return 0
+	}
+	f, _ := n.Float64()
+	return f
+}
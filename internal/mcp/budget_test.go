@@ -0,0 +1,92 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestBudgetResult(t *testing.T) {
+	ctx := context.Background()
+	var aging []stats.InventoryAge
+	for i := range 200 {
+		aging = append(aging, stats.InventoryAge{Key: "PROJ-1", Status: "In Progress", Percentile: i % 100})
+	}
+	result := func() ResponseEnvelope {
+		return WrapResponse(map[string]any{"aging": aging, "summary": map[string]int{"total_items": len(aging)}}, "PROJ", 1, nil, nil, nil)
+	}
+
+	srv := NewServer(&config.AppConfig{CacheDir: t.TempDir(), MaxResponseBytes: 4000}, &mockJiraClient{})
+	env, ok := srv.budgetResult(ctx, result()).(ResponseEnvelope)
+	if !ok || env.Context["truncated"] != true {
+		t.Fatalf("Expected a truncated result, got %v", env.Context)
+	}
+	data := env.Data.(map[string]any)
+	items := data["aging"].([]any)
+	if len(items) != budgetKeep {
+		t.Fatalf("Expected %d aging items, got %d", budgetKeep, len(items))
+	}
+	if got := numberField(items[0], "percentile"); got != 99 {
+		t.Errorf("Expected the highest percentile first, got %v", got)
+	}
+	cuts := env.Context["truncation"].([]Truncation)
+	if len(cuts) != 1 || cuts[0].Path != "aging" || cuts[0].Total != 200 {
+		t.Errorf("Expected aging cut from 200 items, got %+v", cuts)
+	}
+	if len(env.Guardrails.Warnings) != 1 {
+		t.Errorf("Expected a truncation warning, got %v", env.Guardrails.Warnings)
+	}
+
+	within := withCallOption(ctx, func(st *callState) { st.budget = 1 << 20 })
+	if env := srv.budgetResult(within, result()).(ResponseEnvelope); env.Context["truncated"] != nil {
+		t.Error("Expected a result within the call's budget to be left whole")
+	}
+
+	// The text content and structuredContent both carry the envelope, so an
+	// envelope of more than half the budget no longer fits.
+	raw, _ := json.Marshal(result())
+	twice := withCallOption(ctx, func(st *callState) { st.budget = len(raw) + 100 })
+	env = srv.budgetResult(twice, result()).(ResponseEnvelope)
+	if env.Context["truncated"] != true {
+		t.Error("Expected the text and structured copies to count against the budget")
+	}
+	if size, _ := srv.resultSize(twice, env); size > len(raw)+100 {
+		t.Errorf("Expected the cut result to fit %d bytes, got %d", len(raw)+100, size)
+	}
+
+	inProcess := withCallOption(ctx, func(st *callState) { st.info = callInfo{client: digestClient + "/" + inProcessVersion} })
+	if env := srv.budgetResult(inProcess, result()).(ResponseEnvelope); env.Context["truncated"] != nil {
+		t.Error("Expected in-process calls to get the full result")
+	}
+}
+
+func TestWithResponseBudget(t *testing.T) {
+	ctx := context.Background()
+	srv := NewServer(&config.AppConfig{CacheDir: t.TempDir(), MaxResponseBytes: 1000}, &mockJiraClient{})
+	var seen int
+	call := func(maxBytes int) *mcp.CallToolResult {
+		res, _, _ := withResponseBudget(srv, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeThroughputInput) (*mcp.CallToolResult, any, error) {
+			seen = srv.responseBudget(ctx)
+			return formatToolResult(ctx, srv, nil), nil, nil
+		})(context.Background(), nil, AnalyzeThroughputInput{ResponseBudget: ResponseBudget{MaxBytes: maxBytes}})
+		return res
+	}
+
+	if res := call(0); res.IsError || seen != 1000 {
+		t.Errorf("Expected the server budget, got %d", seen)
+	}
+	if res := call(50000); res.IsError || seen != 50000 {
+		t.Errorf("Expected the call's budget, got %d", seen)
+	}
+	if res := call(-1); !res.IsError {
+		t.Error("Expected a negative budget to fail")
+	}
+	if got := srv.responseBudget(ctx); got != 1000 {
+		t.Errorf("Expected the call's budget to be reset after the call, got %d", got)
+	}
+}
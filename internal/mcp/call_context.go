@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"time"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/render"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// callState holds what shapes a single tool call: its ingestion progress
+// sink, its identity in the audit trail, and the per-call parameters the tool
+// middleware parsed. It travels on the context of the call, so calls the SDK
+// runs concurrently never see each other's state. The zero value stands for
+// a call without parameters.
+type callState struct {
+	progress   *progressReporter     // ingestion progress sink; nil = none
+	info       callInfo              // tool and client, for the audit trail
+	format     render.Format         // format parameter; "" = outputFormat
+	window     *windowOverride       // history window parameters; nil = session window
+	subtasks   stats.SubtaskPolicy   // subtask_policy parameter; "" = subtaskPolicy
+	outliers   stats.OutlierPolicy   // outliers parameter; "" = outlierPolicy
+	containers stats.ContainerPolicy // containers parameter; "" = containerPolicy
+	outcomes   stats.OutcomeScope    // outcome_scope parameter; "" = delivered
+	asOf       *time.Time            // as_of_date parameter; nil = evaluation date or now
+	page       *PageOption           // limit, offset, and sort_by parameters; nil = every item
+	budget     int                   // max_bytes parameter; 0 = maxResponseBytes
+}
+
+type callStateKey struct{}
+
+// withCallState returns ctx carrying st.
+func withCallState(ctx context.Context, st *callState) context.Context {
+	return context.WithValue(ctx, callStateKey{}, st)
+}
+
+// callStateOf returns the state of the call ctx belongs to. Outside tool
+// calls (startup restore, tests) it returns an empty state.
+func callStateOf(ctx context.Context) *callState {
+	if st, ok := ctx.Value(callStateKey{}).(*callState); ok {
+		return st
+	}
+	return &callState{}
+}
+
+// withCallOption returns ctx with a copy of its call state changed by set. The
+// tool middleware applies the parameters of a call this way.
+func withCallOption(ctx context.Context, set func(*callState)) context.Context {
+	st := *callStateOf(ctx)
+	set(&st)
+	return withCallState(ctx, &st)
+}
+
+// withCallContext binds the ingestion progress sink and the identity (tool
+// and client) of a tool call to its context. The SDK cancels the context when
+// the client sends notifications/cancelled or disconnects, which aborts Jira
+// requests, hydration paging, and simulation workers.
+func withCallContext[In any](name string, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		st := &callState{
+			progress: &progressReporter{notify: progressNotifier(ctx, req)},
+			info:     identifyCall(name, req),
+		}
+		return handler(withCallState(ctx, st), req, args)
+	}
+}
+
+// directContext returns ctx bound to a call made without MCP (the setup
+// command, the Go API), so changes are audited under tool and client.
+// progress receives the ingestion progress; it may be nil.
+func directContext(ctx context.Context, tool, client string, progress func(fetched, total int, message string)) context.Context {
+	st := &callState{info: callInfo{tool: tool, client: client}}
+	if progress != nil {
+		st.progress = &progressReporter{notify: progress}
+	}
+	return withCallState(ctx, st)
+}
+
+// reportIngestionProgress is the eventlog.ProgressFunc of the server. It
+// forwards the progress to the call whose ingestion it belongs to.
+func reportIngestionProgress(ctx context.Context, p eventlog.Progress) {
+	if r := callStateOf(ctx).progress; r != nil {
+		r.report(p)
+	}
+}
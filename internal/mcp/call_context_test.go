@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/config"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCallContext_CancelledForecastAborts(t *testing.T) {
+	srv := newGoldenServer(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "scope",
+		TargetDays:        60,
+		HistoryWindowDays: 90,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Expected a cancelled forecast, got %v", err)
+	}
+
+	if _, err := srv.handleRunSimulation(context.Background(), ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "scope",
+		TargetDays:        60,
+		HistoryWindowDays: 90,
+	}); err != nil {
+		t.Errorf("Expected the forecast to succeed after the cancelled call, got %v", err)
+	}
+}
+
+func TestCallContext_OverlappingCallsKeepTheirState(t *testing.T) {
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	eval := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	s.activeEvaluationDate = &eval
+
+	// Both calls are inside their handler before either reads its state.
+	var entered sync.WaitGroup
+	entered.Add(2)
+	handler := withCallContext("analyze_yield", withHistoryWindow(s, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
+		entered.Done()
+		entered.Wait()
+		start, end, _ := s.Window(ctx)
+		return nil, end.Sub(start), nil
+	}))
+
+	var wg sync.WaitGroup
+	lengths := make([]any, 2)
+	for i, days := range []int{30, 90} {
+		wg.Go(func() {
+			_, lengths[i], _ = handler(context.Background(), nil, AnalyzeYieldInput{HistoryWindow: HistoryWindow{HistoryWindowDays: days}})
+		})
+	}
+	wg.Wait()
+	if lengths[0] != 30*24*time.Hour || lengths[1] != 90*24*time.Hour {
+		t.Errorf("Expected each call to see its own window, got %v", lengths)
+	}
+}
@@ -0,0 +1,101 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+// columnMappingPrefix marks a workflow mapping key or commitment point that
+// names a board column rather than a status.
+const columnMappingPrefix = "column:"
+
+// columnView returns the column view of a board from its column configuration.
+func (s *Server) columnView(ctx context.Context, boardID int) (stats.ColumnView, error) {
+	if boardID <= 0 {
+		return stats.ColumnView{}, fmt.Errorf("by_column needs a board: query sources have no columns")
+	}
+	cfg, err := s.jira.GetBoardConfig(ctx, boardID)
+	if err != nil {
+		return stats.ColumnView{}, fmt.Errorf("by_column: %w", err)
+	}
+	columns := jira.BoardColumns(cfg)
+	if len(columns) == 0 {
+		return stats.ColumnView{}, fmt.Errorf("by_column: board %d has no columns with statuses", boardID)
+	}
+	return stats.NewColumnView(columns), nil
+}
+
+// expandColumnMapping replaces the "column:<name>" keys of a workflow mapping
+// by the statuses of that board column. Entries for single statuses win over
+// the column they are in.
+func (s *Server) expandColumnMapping(ctx context.Context, boardID int, mapping map[string]any) (map[string]any, error) {
+	var columnKeys []string
+	for k := range mapping {
+		if strings.HasPrefix(k, columnMappingPrefix) {
+			columnKeys = append(columnKeys, k)
+		}
+	}
+	if len(columnKeys) == 0 {
+		return mapping, nil
+	}
+	view, err := s.columnView(ctx, boardID)
+	if err != nil {
+		return nil, fmt.Errorf("column keys in the mapping: %w", err)
+	}
+	out := make(map[string]any, len(mapping))
+	for k, v := range mapping {
+		if !strings.HasPrefix(k, columnMappingPrefix) {
+			out[k] = v
+		}
+	}
+	for _, k := range columnKeys {
+		col, err := findColumn(view, strings.TrimPrefix(k, columnMappingPrefix))
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range col.StatusIDs {
+			if _, ok := out[id]; ok {
+				continue
+			}
+			if name := s.activeRegistry.GetStatusName(id); name != "" {
+				if _, ok := out[name]; ok {
+					continue
+				}
+			}
+			out[id] = mapping[k]
+		}
+	}
+	return out, nil
+}
+
+// columnCommitmentPoint resolves a "column:<name>" commitment point to the
+// first status of that board column; other values are returned unchanged.
+func (s *Server) columnCommitmentPoint(ctx context.Context, boardID int, commitmentPoint string) (string, error) {
+	if !strings.HasPrefix(commitmentPoint, columnMappingPrefix) {
+		return commitmentPoint, nil
+	}
+	view, err := s.columnView(ctx, boardID)
+	if err != nil {
+		return "", fmt.Errorf("column commitment point: %w", err)
+	}
+	col, err := findColumn(view, strings.TrimPrefix(commitmentPoint, columnMappingPrefix))
+	if err != nil {
+		return "", err
+	}
+	return col.StatusIDs[0], nil
+}
+
+// findColumn looks a column up by name, case-insensitively.
+func findColumn(view stats.ColumnView, name string) (jira.BoardColumn, error) {
+	name = strings.TrimSpace(name)
+	for _, c := range view.Columns {
+		if strings.EqualFold(c.Name, name) {
+			return c, nil
+		}
+	}
+	return jira.BoardColumn{}, fmt.Errorf("unknown board column %q; columns: %s", name, strings.Join(view.Order(), ", "))
+}
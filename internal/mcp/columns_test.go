@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+// goldenColumnBoard is the board whose columns withGoldenColumns configures;
+// the golden data itself is not scoped to a board.
+const goldenColumnBoard = 7
+
+// withGoldenColumns gives the golden server a board that shows developing and
+// its queue in one column, and the QA statuses in another.
+func withGoldenColumns(srv *Server) {
+	srv.jira = &mockJiraClient{getBoardConfig: func(int) (any, error) {
+		column := func(name string, ids ...string) map[string]any {
+			statuses := make([]any, len(ids))
+			for i, id := range ids {
+				statuses[i] = map[string]any{"id": id}
+			}
+			return map[string]any{"name": name, "statuses": statuses}
+		}
+		return map[string]any{"columnConfig": map[string]any{"columns": []any{
+			column("Backlog", "1", "38775"),
+			column("Development", "38776", "38777"),
+			column("QA", "38778", "38779", "38780", "38781"),
+			column("Release", "38782", "38783"),
+			column("Done", "10003", "6"),
+		}}}, nil
+	}}
+}
+
+func TestStatusPersistence_ByColumnNeedsBoard(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	withGoldenColumns(srv)
+
+	if _, err := srv.handleGetStatusPersistence(ctx, testProject, testBoard, "", false, true); err == nil {
+		t.Error("Expected by_column on a query source to fail")
+	}
+}
+
+func TestExpandColumnMapping(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	withGoldenColumns(srv)
+
+	queue := map[string]any{"tier": "Downstream", "role": "queue"}
+	active := map[string]any{"tier": "Downstream", "role": "active"}
+	got, err := srv.expandColumnMapping(ctx, goldenColumnBoard, map[string]any{"column:qa": active, "awaiting UAT": queue})
+	if err != nil {
+		t.Fatalf("expandColumnMapping: %v", err)
+	}
+
+	if len(got) != 4 || got["38779"] == nil || got["awaiting UAT"] == nil {
+		t.Errorf("Expected the QA statuses except awaiting UAT to take the column entry, got %v", got)
+	}
+	if _, ok := got["38780"]; ok {
+		t.Error("Expected the status entry to win over its column")
+	}
+
+	if _, err := srv.expandColumnMapping(ctx, goldenColumnBoard, map[string]any{"column:Nope": active}); err == nil {
+		t.Error("Expected an unknown column to fail")
+	}
+	if cp, err := srv.columnCommitmentPoint(ctx, goldenColumnBoard, "column:Development"); err != nil || cp != "38776" {
+		t.Errorf("Expected the Development column to commit at its first status, got %q (%v)", cp, err)
+	}
+}
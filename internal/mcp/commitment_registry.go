@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"mcs-mcp/internal/simulation"
+)
+
+// Commitment statuses. Only open commitments are checked; the others are
+// closed on ClosedOn.
+const (
+	CommitmentOpen      = "open"
+	CommitmentMet       = "met"
+	CommitmentMissed    = "missed"
+	CommitmentWithdrawn = "withdrawn"
+)
+
+// CommitmentForecast is the snapshot of the forecast a commitment was made from.
+type CommitmentForecast struct {
+	ForecastID      string                  `json:"forecast_id"`
+	AsOf            string                  `json:"as_of"`
+	Percentiles     simulation.Percentiles  `json:"percentiles"`
+	CompletionDates map[string]string       `json:"completion_dates"`
+	Composition     *simulation.Composition `json:"composition,omitempty"`
+}
+
+// CommitmentCheck is the state of a commitment on one evaluation date.
+type CommitmentCheck struct {
+	AsOf        string  `json:"as_of"`
+	Remaining   int     `json:"remaining"`   // Committed items not delivered by the target date
+	DaysLeft    int     `json:"days_left"`   // Calendar days to the target date, counting today
+	Probability float64 `json:"probability"` // Share of trials delivering the remaining items by the target date
+}
+
+// Commitment is a recorded delivery commitment: a scope promised by a date
+// at a confidence level, and the checks made against it since.
+type Commitment struct {
+	ID         string              `json:"id"`
+	SourceID   string              `json:"source_id"`
+	Name       string              `json:"name"`
+	MadeOn     string              `json:"made_on"`     // Evaluation date the commitment was made on
+	RecordedAt time.Time           `json:"recorded_at"` // Wall-clock time of the record
+	TargetDate string              `json:"target_date"`
+	Percentile int                 `json:"percentile"` // Confidence the date was committed at
+	Items      int                 `json:"items"`
+	IssueKeys  []string            `json:"issue_keys,omitempty"`  // The committed items; without them progress counts the board's deliveries
+	IssueTypes []string            `json:"issue_types,omitempty"` // Deliveries counted towards the scope; empty = all types
+	Forecast   *CommitmentForecast `json:"forecast,omitempty"`
+	Status     string              `json:"status"`
+	ClosedOn   string              `json:"closed_on,omitempty"`
+	Baseline   CommitmentCheck     `json:"baseline"`         // The check made when the commitment was recorded
+	Checks     []CommitmentCheck   `json:"checks,omitempty"` // One per analyze_commitment_health day, oldest first
+}
+
+// commitmentIDSeparator joins the source ID and the per-source commitment
+// number in a commitment ID (e.g. PROJ_12-C3).
+const commitmentIDSeparator = "-C"
+
+func (s *Server) commitmentRegistryPath(sourceID string) string {
+	return filepath.Join(s.cacheDir, fmt.Sprintf("%s_commitments.json", sourceID))
+}
+
+// loadCommitments returns the recorded commitments of a source, oldest first.
+func (s *Server) loadCommitments(sourceID string) ([]Commitment, error) {
+	if s.cacheDir == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(s.commitmentRegistryPath(sourceID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var commitments []Commitment
+	if err := json.Unmarshal(data, &commitments); err != nil {
+		return nil, fmt.Errorf("invalid commitment registry for %s: %w", sourceID, err)
+	}
+	return commitments, nil
+}
+
+// saveCommitments replaces the commitment registry of a source. Unlike the
+// forecast registry it is rewritten as a whole, since every health check
+// updates the commitments it checked.
+func (s *Server) saveCommitments(sourceID string, commitments []Commitment) error {
+	if s.cacheDir == "" {
+		return fmt.Errorf("commitments need a cache directory to be tracked")
+	}
+	data, err := json.MarshalIndent(commitments, "", "  ")
+	if err != nil {
+		return err
+	}
+	path := s.commitmentRegistryPath(sourceID)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, path)
+}
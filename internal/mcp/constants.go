@@ -6,6 +6,39 @@ const (
 	// Used by forecast_monte_carlo and the BBak engine; not the diagnostic session window.
 	DefaultForecastSampleDays = 90
 
+	// DefaultSprintSampleDays is the default forecast sampling window in sprint
+	// mode; 26 weeks covers about 13 two-week sprints.
+	DefaultSprintSampleDays = 182
+
+	// SprintHistoryLimit caps how many recent sprints are fetched from the Agile API.
+	SprintHistoryLimit = 26
+
 	// DataProbeSampleSize is the number of issues sampled during tier-neutral data probes.
 	DataProbeSampleSize = 200
+
+	// MinReleaseLagSample is the minimum number of released items required
+	// before release lag is used to extend a forecast to production dates.
+	MinReleaseLagSample = 5
+
+	// MinItemForecastTypeSample is the minimum number of delivered items of an
+	// issue's type before forecast_item samples residence times from that type
+	// alone instead of from all delivered items.
+	MinItemForecastTypeSample = 20
+
+	// DefaultBurnUpWeeks is the default horizon of forecast_burnup; MaxBurnUpWeeks
+	// caps it at a year.
+	DefaultBurnUpWeeks = 12
+	MaxBurnUpWeeks     = 52
+
+	// MaxCapacityFactor caps capacity_factor, which is a multiplier: values
+	// above it are almost always percentages (70 instead of 0.7).
+	MaxCapacityFactor = 5.0
+
+	// PointsItemsDivergence is the relative P85 difference above which a
+	// points forecast is reported as diverging from its item forecast.
+	PointsItemsDivergence = 0.25
+
+	// MaxForecastScenarios caps the scenarios of one forecast_scenarios call;
+	// each is a full simulation.
+	MaxForecastScenarios = 10
 )
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// containerPolicied is implemented by tool inputs that embed ContainerOption.
+type containerPolicied interface {
+	containerPolicyOption() stats.ContainerPolicy
+}
+
+func (o ContainerOption) containerPolicyOption() stats.ContainerPolicy { return o.Containers }
+
+// withContainerPolicy validates the containers parameter of a tool input and
+// makes it the container policy for the duration of the call.
+func withContainerPolicy[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		cp, ok := any(args).(containerPolicied)
+		if !ok || cp.containerPolicyOption() == "" {
+			return handler(ctx, req, args)
+		}
+		policy, err := stats.ParseContainerPolicy(string(cp.containerPolicyOption()))
+		if err != nil {
+			return formatToolError(err), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.containers = policy }), req, args)
+	}
+}
+
+// containers returns the container policy of analyses: the containers
+// parameter of the call, else the MCS_CONTAINER_POLICY setting.
+func (s *Server) containers(ctx context.Context) stats.ContainerPolicy {
+	if p := callStateOf(ctx).containers; p != "" {
+		return p
+	}
+	if s.containerPolicy == "" {
+		return stats.ContainersExclude
+	}
+	return s.containerPolicy
+}
+
+// excludedContainers returns the keys of the container items among issues
+// that the container policy leaves out of flow analyses; nil when containers
+// are included.
+func (s *Server) excludedContainers(ctx context.Context, issues []jira.Issue) map[string]bool {
+	if s.containers(ctx) != stats.ContainersExclude {
+		return nil
+	}
+	return stats.ContainerKeys(issues)
+}
@@ -0,0 +1,84 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWithContainerPolicy(t *testing.T) {
+	ctx := context.Background()
+	var seen stats.ContainerPolicy
+	wrap := func(s *Server) func(context.Context, *mcp.CallToolRequest, AnalyzeThroughputInput) (*mcp.CallToolResult, any, error) {
+		return withContainerPolicy(s, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeThroughputInput) (*mcp.CallToolResult, any, error) {
+			seen = s.containers(ctx)
+			return formatToolResult(ctx, s, nil), nil, nil
+		})
+	}
+	call := func(s *Server, policy stats.ContainerPolicy) *mcp.CallToolResult {
+		res, _, _ := wrap(s)(context.Background(), nil, AnalyzeThroughputInput{ContainerOption: ContainerOption{Containers: policy}})
+		return res
+	}
+
+	srv := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	if res := call(srv, ""); res.IsError || seen != stats.ContainersExclude {
+		t.Errorf("Expected the default policy exclude, got %q", seen)
+	}
+	if res := call(srv, stats.ContainersInclude); res.IsError || seen != stats.ContainersInclude {
+		t.Errorf("Expected the call policy include, got %q", seen)
+	}
+	if res := call(srv, "epics"); !res.IsError {
+		t.Error("Expected an unknown policy to fail")
+	}
+	if got := srv.containers(ctx); got != stats.ContainersExclude {
+		t.Errorf("Expected the call policy to be reset after the call, got %q", got)
+	}
+
+	including := NewServer(&config.AppConfig{CacheDir: t.TempDir(), ContainerPolicy: stats.ContainersInclude}, &mockJiraClient{})
+	if res := call(including, ""); res.IsError || seen != stats.ContainersInclude {
+		t.Errorf("Expected the server policy include, got %q", seen)
+	}
+}
+
+func TestContainersLeaveThroughputAndBacklog(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	now := srv.Clock(ctx)
+	ts := now.AddDate(0, 0, -5).UnixMicro()
+	day := int64(86400 * 1e6)
+	appendCachedEvents(t, srv, []eventlog.IssueEvent{
+		{EventType: eventlog.Created, IssueKey: "EPIC-1", IssueType: "Epic", HierarchyLevel: 1, ToStatus: "Open", ToStatusID: "1", Timestamp: ts},
+		{EventType: eventlog.Change, IssueKey: "EPIC-1", IssueType: "Epic", ToStatus: "Done", ToStatusID: "10003", Resolution: "Done", Timestamp: ts + 2*day},
+		{EventType: eventlog.Created, IssueKey: "EPIC-2", IssueType: "Epic", HierarchyLevel: 1, ToStatus: "Open", ToStatusID: "1", Timestamp: ts},
+	})
+
+	delivered := func(policy stats.ContainerPolicy) (epics int, backlog bool) {
+		t.Helper()
+		ctx := withCallOption(ctx, func(st *callState) { st.containers = policy })
+		hctx, err := srv.prepareHandler(ctx, testProject, testBoard)
+		if err != nil {
+			t.Fatalf("prepare: %v", err)
+		}
+		session := srv.openSession(ctx, hctx, srv.AnalysisWindow(ctx, "day"))
+		for _, issue := range session.GetDelivered() {
+			if issue.IssueType == "Epic" {
+				epics++
+			}
+		}
+		all := session.GetAllIssues()
+		targets, _, _, _ := srv.forecastScope(ctx, all, session.GetWIP(), srv.prepareAnalysisContext(testProject, testBoard, all), "", true, false)
+		return epics, targets["Epic"] > 0
+	}
+
+	if epics, backlog := delivered(stats.ContainersExclude); epics != 0 || backlog {
+		t.Errorf("exclude: expected no epics delivered or in the backlog, got %d delivered, backlog %v", epics, backlog)
+	}
+	if epics, backlog := delivered(stats.ContainersInclude); epics != 1 || !backlog {
+		t.Errorf("include: expected the delivered epic and the open one in the backlog, got %d delivered, backlog %v", epics, backlog)
+	}
+}
@@ -14,9 +14,15 @@ type AnalysisContext struct {
 	FinishedStatuses         map[string]bool
 	CommitmentPoint          string
 	CommitmentPointIsDefault bool
+	TypeCommitmentPoints     map[string]string // Issue type → commitment point override
 	StatusOrder              []string
 }
 
+// Commitments returns the board commitment point with its per-type overrides.
+func (c *AnalysisContext) Commitments() stats.CommitmentPoints {
+	return stats.CommitmentPoints{Default: c.CommitmentPoint, ByType: c.TypeCommitmentPoints}
+}
+
 func (s *Server) prepareAnalysisContext(projectKey string, boardID int, issues []jira.Issue) *AnalysisContext {
 	sourceID := getCombinedID(projectKey, boardID)
 	statusWeights := s.getStatusWeights(issues)
@@ -59,6 +65,21 @@ func (s *Server) prepareAnalysisContext(projectKey string, boardID int, issues [
 		FinishedStatuses:         finished,
 		CommitmentPoint:          commitment,
 		CommitmentPointIsDefault: found,
+		TypeCommitmentPoints:     s.activeTypeCommitments,
 		StatusOrder:              s.activeStatusOrder,
 	}
 }
+
+// ByColumn returns the context at the column granularity of view: mappings,
+// weights, finished statuses, commitment points, and order are keyed by column.
+func (c *AnalysisContext) ByColumn(view stats.ColumnView) *AnalysisContext {
+	cp := view.CommitmentPoints(c.Commitments())
+	out := *c
+	out.StatusWeights = view.Weights(c.StatusWeights)
+	out.WorkflowMappings = view.Mapping(c.WorkflowMappings)
+	out.FinishedStatuses = view.Statuses(c.FinishedStatuses)
+	out.CommitmentPoint = cp.Default
+	out.TypeCommitmentPoints = cp.ByType
+	out.StatusOrder = view.Order()
+	return &out
+}
@@ -0,0 +1,333 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"mcs-mcp/internal/notify"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// digestClient identifies the digest command in the audit trail.
+const digestClient = "mcs-mcp digest"
+
+// Digest analyses.
+const (
+	DigestAging         = "aging"
+	DigestFlowDebt      = "flow_debt"
+	DigestForecastDrift = "forecast_drift"
+	DigestAlerts        = "alerts"
+)
+
+// DigestAnalyses lists the digest analyses in report order.
+var DigestAnalyses = []string{DigestAlerts, DigestAging, DigestFlowDebt, DigestForecastDrift}
+
+const (
+	digestMaxItems       = 5 // Aging outliers listed per source
+	digestDriftAlertDays = 7 // Slip of the P85 completion date that raises an alert
+)
+
+// DigestSource is a board reported in a digest.
+type DigestSource struct {
+	Instance   string // Named Jira instance; "" = default
+	ProjectKey string
+	BoardID    int
+}
+
+// ParseDigestSource parses a source written as [instance/]PROJECT[:board],
+// e.g. "PROJ:42" or "dc/OPS:7".
+func ParseDigestSource(s string) (DigestSource, error) {
+	var src DigestSource
+	rest := strings.TrimSpace(s)
+	if i := strings.Index(rest, "/"); i >= 0 {
+		src.Instance, rest = rest[:i], rest[i+1:]
+	}
+	if i := strings.LastIndex(rest, ":"); i >= 0 {
+		board, err := strconv.Atoi(rest[i+1:])
+		if err != nil || board < 0 {
+			return DigestSource{}, fmt.Errorf("invalid digest source %q: board ID %q is not a number", s, rest[i+1:])
+		}
+		src.BoardID, rest = board, rest[:i]
+	}
+	if src.ProjectKey = strings.ToUpper(rest); src.ProjectKey == "" {
+		return DigestSource{}, fmt.Errorf("invalid digest source %q: expected [instance/]PROJECT[:board]", s)
+	}
+	return src, nil
+}
+
+func (src DigestSource) String() string {
+	name := src.ProjectKey
+	if src.BoardID != 0 {
+		name += fmt.Sprintf(" board %d", src.BoardID)
+	}
+	if src.Instance != "" {
+		name += " (" + src.Instance + ")"
+	}
+	return name
+}
+
+// digestEnvelope is a tool result with its data left raw.
+type digestEnvelope struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// BuildDigest runs the analyses for every source through CallTool, so the
+// middleware (anonymization included) applies as for an agent, and reports
+// the findings per source. Each source needs a confirmed workflow mapping. A
+// failing analysis is reported as an alert and does not stop the others.
+func (s *Server) BuildDigest(ctx context.Context, sources []DigestSource, analyses []string) (notify.Digest, error) {
+	if len(analyses) == 0 {
+		analyses = DigestAnalyses
+	}
+	for _, a := range analyses {
+		if !slices.Contains(DigestAnalyses, a) {
+			return notify.Digest{}, fmt.Errorf("unknown digest analysis %q: expected %s", a, strings.Join(DigestAnalyses, ", "))
+		}
+	}
+
+	d := notify.Digest{Title: "Flow digest " + s.Clock(ctx).Format(stats.DateFormat)}
+	for _, src := range sources {
+		if ctx.Err() != nil {
+			return d, ctx.Err()
+		}
+		section := notify.Section{Title: src.String()}
+		if err := s.useInstance(src.Instance); err != nil {
+			section.Findings = append(section.Findings, notify.Finding{Analysis: "Source", Summary: err.Error(), Alert: true})
+			d.Sections = append(d.Sections, section)
+			continue
+		}
+		for _, a := range DigestAnalyses {
+			if !slices.Contains(analyses, a) {
+				continue
+			}
+			var f notify.Finding
+			var err error
+			switch a {
+			case DigestAlerts:
+				f, err = s.digestAlerts(ctx, src)
+			case DigestAging:
+				f, err = s.digestAging(ctx, src)
+			case DigestFlowDebt:
+				f, err = s.digestFlowDebt(ctx, src)
+			case DigestForecastDrift:
+				f, err = s.digestForecastDrift(ctx, src)
+			}
+			if err != nil {
+				f.Summary, f.Alert = err.Error(), true
+			}
+			section.Findings = append(section.Findings, f)
+		}
+		d.Sections = append(d.Sections, section)
+	}
+	return d, nil
+}
+
+func (s *Server) callDigestTool(ctx context.Context, src DigestSource, tool string, args map[string]any) (digestEnvelope, error) {
+	args["project_key"] = src.ProjectKey
+	if src.BoardID != 0 {
+		args["board_id"] = src.BoardID
+	}
+	var env digestEnvelope
+	raw, err := s.CallTool(ctx, digestClient, tool, args)
+	if err != nil {
+		return env, err
+	}
+	if err := json.Unmarshal(raw, &env); err != nil {
+		return env, fmt.Errorf("unexpected %s result: %w", tool, err)
+	}
+	return env, nil
+}
+
+// digestAlerts evaluates the recorded alert rules of a board and lists the
+// fired ones. A board without rules reports so without an alert.
+func (s *Server) digestAlerts(ctx context.Context, src DigestSource) (notify.Finding, error) {
+	f := notify.Finding{Analysis: "Alerts"}
+	if err := s.anchorContext(src.ProjectKey, src.BoardID); err != nil {
+		return f, err
+	}
+	if len(s.activeAlertRules) == 0 {
+		f.Summary = "No alert rules recorded; define them with 'set_alert_rules'."
+		return f, nil
+	}
+	env, err := s.callDigestTool(ctx, src, "evaluate_alerts", map[string]any{})
+	if err != nil {
+		return f, err
+	}
+	var res struct {
+		Fired       int                     `json:"fired"`
+		Evaluations []stats.AlertEvaluation `json:"evaluations"`
+	}
+	if err := json.Unmarshal(env.Data, &res); err != nil {
+		return f, fmt.Errorf("unexpected alerts result: %w", err)
+	}
+
+	f.Summary = fmt.Sprintf("%d of %d rule(s) fired.", res.Fired, len(res.Evaluations))
+	f.Alert = res.Fired > 0
+	for _, ev := range res.Evaluations {
+		if !ev.Fired {
+			continue
+		}
+		label := ev.Rule.Name
+		if label != ev.Condition {
+			label += " (" + ev.Condition + ")"
+		}
+		f.Details = append(f.Details, label+": "+ev.Evidence)
+	}
+	return f, nil
+}
+
+// digestAging lists the items in progress older than the historical P85
+// cycle time, oldest first.
+func (s *Server) digestAging(ctx context.Context, src DigestSource) (notify.Finding, error) {
+	f := notify.Finding{Analysis: "Aging"}
+	env, err := s.callDigestTool(ctx, src, "analyze_work_item_age", map[string]any{"age_type": "wip"})
+	if err != nil {
+		return f, err
+	}
+	var res struct {
+		Aging   []stats.InventoryAge `json:"aging"`
+		Summary stats.AgingSummary   `json:"summary"`
+	}
+	if err := json.Unmarshal(env.Data, &res); err != nil {
+		return f, fmt.Errorf("unexpected aging result: %w", err)
+	}
+
+	age := func(a stats.InventoryAge) float64 {
+		if a.AgeSinceCommitment != nil {
+			return *a.AgeSinceCommitment
+		}
+		return a.CumulativeWIPDays
+	}
+	var outliers []stats.InventoryAge
+	for _, a := range res.Aging {
+		if a.IsAgingOutlier {
+			outliers = append(outliers, a)
+		}
+	}
+	slices.SortStableFunc(outliers, func(a, b stats.InventoryAge) int {
+		switch {
+		case age(a) > age(b):
+			return -1
+		case age(a) < age(b):
+			return 1
+		}
+		return 0
+	})
+
+	if len(outliers) == 0 {
+		f.Summary = fmt.Sprintf("None of the %d items in progress is older than the historical P85 cycle time (%.1f days).", res.Summary.TotalItems, res.Summary.P85Threshold)
+		return f, nil
+	}
+	f.Alert = true
+	f.Summary = fmt.Sprintf("%d of %d items in progress are older than the historical P85 cycle time (%.1f days).", len(outliers), res.Summary.TotalItems, res.Summary.P85Threshold)
+	for i, a := range outliers {
+		if i == digestMaxItems {
+			f.Details = append(f.Details, fmt.Sprintf("and %d more", len(outliers)-digestMaxItems))
+			break
+		}
+		f.Details = append(f.Details, fmt.Sprintf("%s (%s) %.1f days in progress, %.1f in '%s'", a.Key, a.Type, age(a), a.AgeInCurrentStatus, a.Status))
+	}
+	return f, nil
+}
+
+// digestFlowDebt reports whether work is started faster than it is finished.
+func (s *Server) digestFlowDebt(ctx context.Context, src DigestSource) (notify.Finding, error) {
+	f := notify.Finding{Analysis: "Flow debt"}
+	env, err := s.callDigestTool(ctx, src, "analyze_flow_debt", map[string]any{})
+	if err != nil {
+		return f, err
+	}
+	var res struct {
+		FlowDebt stats.FlowDebtResult `json:"flow_debt"`
+	}
+	if err := json.Unmarshal(env.Data, &res); err != nil {
+		return f, fmt.Errorf("unexpected flow debt result: %w", err)
+	}
+
+	debt := res.FlowDebt
+	arrivals, departures := 0, 0
+	for _, b := range debt.Buckets {
+		arrivals += b.Arrivals
+		departures += b.Departures
+	}
+	f.Summary = fmt.Sprintf("%d items committed and %d finished over the last %d weeks (flow debt %+d).", arrivals, departures, len(debt.Buckets), debt.TotalDebt)
+	if debt.TotalDebt <= 0 {
+		return f, nil
+	}
+	f.Alert = true
+	if debt.AccumulatingStatus != "" {
+		f.Details = append(f.Details, fmt.Sprintf("Work accumulates in '%s'.", debt.AccumulatingStatus))
+	}
+	if p := debt.Projection; p != nil && p.InflationPct > 0 {
+		f.Details = append(f.Details, fmt.Sprintf("At this rate, Little's Law cycle time grows by %.0f%% (%.1f → %.1f days) within %d days.", p.InflationPct, p.CycleTimeDays, p.ProjectedCycleTimeDays, p.HorizonDays))
+	}
+	return f, nil
+}
+
+// digestForecastDrift forecasts the backlog and WIP of a board and compares
+// it with the previous forecast of the same inputs, normally the one of the
+// previous digest. The run is recorded like any forecast_monte_carlo run.
+func (s *Server) digestForecastDrift(ctx context.Context, src DigestSource) (notify.Finding, error) {
+	f := notify.Finding{Analysis: "Forecast"}
+	env, err := s.callDigestTool(ctx, src, "forecast_monte_carlo", map[string]any{
+		"mode":                     string(SimModeDuration),
+		"include_existing_backlog": true,
+		"include_wip":              true,
+	})
+	if err != nil {
+		return f, err
+	}
+	var res simulation.Result
+	if err := json.Unmarshal(env.Data, &res); err != nil {
+		return f, fmt.Errorf("unexpected forecast result: %w", err)
+	}
+	f.Summary = fmt.Sprintf("Backlog and WIP done within %.0f days at 85%% confidence", res.Percentiles.Likely)
+	if dates, ok := res.Context["completion_dates"].(map[string]any); ok && dates["likely"] != nil {
+		f.Summary += fmt.Sprintf(" (%v)", dates["likely"])
+	}
+
+	id, _ := res.Context["forecast_id"].(string)
+	if id == "" {
+		return f, nil
+	}
+	current, err := s.findForecast(id)
+	if err != nil {
+		return f, err
+	}
+	records, err := s.loadForecasts(current.SourceID)
+	if err != nil {
+		return f, err
+	}
+	idx := slices.IndexFunc(records, func(r ForecastRecord) bool { return r.ID == current.ID })
+	for i := idx - 1; i >= 0; i-- {
+		if !digestComparable(records[i], current) {
+			continue
+		}
+		drift := compareForecasts(records[i], current).Drift["p85"]
+		if drift.DateShiftDays != nil {
+			f.Summary += fmt.Sprintf("; the P85 date moved %+d days since the forecast of %s (%s).", *drift.DateShiftDays, records[i].AsOf, drift.BaselineDate)
+			f.Alert = *drift.DateShiftDays >= digestDriftAlertDays
+		} else {
+			f.Summary += fmt.Sprintf("; P85 changed by %+.0f since the forecast of %s.", drift.Change, records[i].AsOf)
+		}
+		return f, nil
+	}
+	f.Summary += ". First forecast of these inputs; drift is reported from the next digest."
+	return f, nil
+}
+
+// digestComparable reports whether two forecast runs forecast the same
+// kind of scope in the same way, as the digest's own runs do. The per-type
+// targets are derived from the backlog then, so they may differ.
+func digestComparable(a, b ForecastRecord) bool {
+	x, y := a.Inputs, b.Inputs
+	return x.Mode == y.Mode && x.TimeUnit == y.TimeUnit && x.Units == y.Units &&
+		x.IncludeExistingBacklog == y.IncludeExistingBacklog && x.IncludeWIP == y.IncludeWIP &&
+		x.AdditionalItems == y.AdditionalItems &&
+		slices.Equal(x.IssueTypes, y.IssueTypes) && slices.Equal(x.Sources, y.Sources)
+}
@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestParseDigestSource(t *testing.T) {
+	cases := map[string]DigestSource{
+		"PROJ:42":  {ProjectKey: "PROJ", BoardID: 42},
+		" proj ":   {ProjectKey: "PROJ"},
+		"dc/OPS:7": {Instance: "dc", ProjectKey: "OPS", BoardID: 7},
+	}
+	for in, want := range cases {
+		if got, err := ParseDigestSource(in); err != nil || got != want {
+			t.Errorf("ParseDigestSource(%q) = %+v, %v; want %+v", in, got, err, want)
+		}
+	}
+	for _, in := range []string{"", "PROJ:x", ":42"} {
+		if _, err := ParseDigestSource(in); err == nil {
+			t.Errorf("Expected %q rejected", in)
+		}
+	}
+}
+
+func TestBuildDigest(t *testing.T) {
+	srv := newGoldenServer(t)
+	ctx := context.Background()
+	sources := []DigestSource{{ProjectKey: testProject, BoardID: testBoard}}
+
+	if _, err := srv.BuildDigest(ctx, sources, []string{"velocity"}); err == nil {
+		t.Error("Expected an unknown analysis rejected")
+	}
+
+	first, err := srv.BuildDigest(ctx, sources, nil)
+	if err != nil {
+		t.Fatalf("BuildDigest: %v", err)
+	}
+	if len(first.Sections) != 1 || len(first.Sections[0].Findings) != len(DigestAnalyses) {
+		t.Fatalf("Expected one section with a finding per analysis, got %+v", first)
+	}
+	for _, f := range first.Sections[0].Findings {
+		if f.Summary == "" {
+			t.Errorf("Expected a summary for %s", f.Analysis)
+		}
+	}
+	if got := first.Sections[0].Findings[0]; got.Alert || !strings.Contains(got.Summary, "No alert rules") {
+		t.Errorf("Expected no alerts without rules, got %+v", got)
+	}
+	if got := first.Sections[0].Findings[3].Summary; !strings.Contains(got, "First forecast") {
+		t.Errorf("Expected no drift on the first digest, got %q", got)
+	}
+
+	second, err := srv.BuildDigest(ctx, sources, []string{DigestForecastDrift})
+	if err != nil {
+		t.Fatalf("BuildDigest: %v", err)
+	}
+	if got := second.Sections[0].Findings; len(got) != 1 || !strings.Contains(got[0].Summary, "moved") {
+		t.Errorf("Expected the drift since the first digest, got %+v", got)
+	}
+}
@@ -0,0 +1,195 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ForecastInputs are the parameters a forecast run was made with.
+type ForecastInputs struct {
+	Mode                   string             `json:"mode"`
+	TimeUnit               string             `json:"time_unit"`            // "day" or "sprint"
+	Units                  string             `json:"units,omitempty"`      // "points", or empty for items
+	Sources                []string           `json:"sources,omitempty"`    // Portfolio boards, when more than one
+	Duplicates             string             `json:"duplicates,omitempty"` // Portfolio: attribution of issues shared by several boards
+	Targets                map[string]int     `json:"targets,omitempty"`
+	TargetDays             int                `json:"target_days,omitempty"`
+	TargetSprints          int                `json:"target_sprints,omitempty"`
+	IssueTypes             []string           `json:"issue_types,omitempty"`
+	IncludeExistingBacklog bool               `json:"include_existing_backlog,omitempty"`
+	IncludeWIP             bool               `json:"include_wip,omitempty"`
+	AdditionalItems        int                `json:"additional_items,omitempty"`
+	StartStatus            string             `json:"start_status,omitempty"`
+	MixOverrides           map[string]float64 `json:"mix_overrides,omitempty"`
+	Sampling               string             `json:"sampling,omitempty"`
+	ModelArrivals          bool               `json:"model_arrivals,omitempty"`
+	CapacityFactor         float64            `json:"capacity_factor,omitempty"`
+	TeamChange             *TeamChange        `json:"team_change,omitempty"`
+	SampleStart            string             `json:"sample_start"`
+	SampleEnd              string             `json:"sample_end"`
+}
+
+// ForecastSample is the throughput histogram metadata a forecast was drawn from.
+type ForecastSample struct {
+	DaysInSample      int                `json:"days_in_sample,omitempty"`
+	IssuesAnalyzed    int                `json:"issues_analyzed,omitempty"`
+	ThroughputOverall float64            `json:"throughput_overall,omitempty"`
+	ThroughputRecent  float64            `json:"throughput_recent,omitempty"`
+	TypeDistribution  map[string]float64 `json:"type_distribution,omitempty"`
+}
+
+// ForecastRecord is a persisted forecast_monte_carlo run.
+type ForecastRecord struct {
+	ID          string                  `json:"id"`
+	SourceID    string                  `json:"source_id"`
+	AsOf        string                  `json:"as_of"`       // Evaluation date the forecast was made for
+	RecordedAt  time.Time               `json:"recorded_at"` // Wall-clock time of the run
+	Engine      string                  `json:"engine,omitempty"`
+	Inputs      ForecastInputs          `json:"inputs"`
+	Sample      ForecastSample          `json:"sample"`
+	Percentiles simulation.Percentiles  `json:"percentiles"`
+	Composition *simulation.Composition `json:"composition,omitempty"`
+}
+
+// forecastIDSeparator joins the source ID and the per-source run number in a
+// forecast ID (e.g. PROJ_12-F3). Source IDs never contain it.
+const forecastIDSeparator = "-F"
+
+func (s *Server) forecastRegistryPath(sourceID string) string {
+	return filepath.Join(s.cacheDir, fmt.Sprintf("%s_forecasts.jsonl", sourceID))
+}
+
+// forecastSource returns the source ID encoded in a forecast ID.
+func forecastSource(id string) (string, error) {
+	i := strings.LastIndex(id, forecastIDSeparator)
+	if i <= 0 {
+		return "", fmt.Errorf("invalid forecast ID %q: expected <source>%s<n> (e.g. PROJ_12-F3)", id, forecastIDSeparator)
+	}
+	if _, err := strconv.Atoi(id[i+len(forecastIDSeparator):]); err != nil {
+		return "", fmt.Errorf("invalid forecast ID %q: expected <source>%s<n> (e.g. PROJ_12-F3)", id, forecastIDSeparator)
+	}
+	return id[:i], nil
+}
+
+// recordForecast assigns the next forecast ID of the source, appends the run
+// to the source's forecast registry, and returns the ID. Returns "" without
+// a cache directory, since the run could not be compared later.
+func (s *Server) recordForecast(ctx context.Context, sourceID, engine string, inputs ForecastInputs, res *simulation.Result) (string, error) {
+	if s.cacheDir == "" {
+		return "", nil
+	}
+	existing, err := s.loadForecasts(sourceID)
+	if err != nil {
+		return "", err
+	}
+
+	rec := ForecastRecord{
+		ID:          fmt.Sprintf("%s%s%d", sourceID, forecastIDSeparator, len(existing)+1),
+		SourceID:    sourceID,
+		AsOf:        s.Clock(ctx).Format(stats.DateFormat),
+		RecordedAt:  time.Now().UTC().Truncate(time.Second),
+		Engine:      engine,
+		Inputs:      inputs,
+		Sample:      forecastSample(res.Context),
+		Percentiles: res.Percentiles,
+		Composition: res.Composition,
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := os.OpenFile(s.forecastRegistryPath(sourceID), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		return "", err
+	}
+	return rec.ID, nil
+}
+
+// trackForecast records a finished run and stamps its ID into the result
+// context as forecast_id. A failing registry never fails the forecast itself.
+func (s *Server) trackForecast(ctx context.Context, sourceID, engine string, inputs ForecastInputs, res *simulation.Result) {
+	id, err := s.recordForecast(ctx, sourceID, engine, inputs, res)
+	if err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to record forecast")
+		return
+	}
+	if id != "" {
+		res.Context["forecast_id"] = id
+	}
+}
+
+// forecastSample extracts the histogram metadata from a result context.
+func forecastSample(ctx map[string]any) ForecastSample {
+	var sample ForecastSample
+	sample.DaysInSample, _ = ctx["days_in_sample"].(int)
+	sample.IssuesAnalyzed, _ = ctx["issues_analyzed"].(int)
+	sample.ThroughputOverall, _ = ctx["throughput_overall"].(float64)
+	sample.ThroughputRecent, _ = ctx["throughput_recent"].(float64)
+	sample.TypeDistribution, _ = ctx["type_distribution"].(map[string]float64)
+	return sample
+}
+
+// loadForecasts returns the recorded runs of a source, oldest first.
+func (s *Server) loadForecasts(sourceID string) ([]ForecastRecord, error) {
+	if s.cacheDir == "" {
+		return nil, nil
+	}
+	f, err := os.Open(s.forecastRegistryPath(sourceID))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []ForecastRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var rec ForecastRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("invalid forecast registry for %s: %w", sourceID, err)
+		}
+		records = append(records, rec)
+	}
+	return records, scanner.Err()
+}
+
+// findForecast returns the recorded run with the given ID.
+func (s *Server) findForecast(id string) (ForecastRecord, error) {
+	sourceID, err := forecastSource(id)
+	if err != nil {
+		return ForecastRecord{}, err
+	}
+	records, err := s.loadForecasts(sourceID)
+	if err != nil {
+		return ForecastRecord{}, err
+	}
+	for _, rec := range records {
+		if rec.ID == id {
+			return rec, nil
+		}
+	}
+	return ForecastRecord{}, fmt.Errorf("unknown forecast %s", id)
+}
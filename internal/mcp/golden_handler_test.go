@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"testing"
 )
 
@@ -11,6 +12,7 @@ import (
 // Subtests run sequentially on a shared server (one server = one session),
 // mirroring real-world usage. Do NOT add t.Parallel() inside subtests.
 func TestHandlers_Golden(t *testing.T) {
+	ctx := context.Background()
 	checkFixtureHash(t)
 
 	srv := newGoldenServer(t)
@@ -22,97 +24,98 @@ func TestHandlers_Golden(t *testing.T) {
 		{
 			"analyze_cycle_time",
 			func() (any, error) {
-				return srv.handleGetCycleTimeAssessment(testProject, testBoard, "", "", nil, 0, 0)
+				return srv.handleGetCycleTimeAssessment(ctx, testProject, testBoard, "", "", nil, 0, 0, false, "")
 			},
 		},
 		{
 			"analyze_throughput",
 			func() (any, error) {
-				return srv.handleGetDeliveryCadence(testProject, testBoard, "week", false)
+				return srv.handleGetDeliveryCadence(ctx, testProject, testBoard, "week", "", false, "")
 			},
 		},
 		{
 			"analyze_status_persistence",
 			func() (any, error) {
-				return srv.handleGetStatusPersistence(testProject, testBoard)
+				return srv.handleGetStatusPersistence(ctx, testProject, testBoard, "", false, false)
 			},
 		},
 		{
 			"analyze_work_item_age",
 			func() (any, error) {
-				return srv.handleGetAgingAnalysis(testProject, testBoard, "wip", "")
+				return srv.handleGetAgingAnalysis(ctx, testProject, testBoard, "wip", "", false)
 			},
 		},
 		{
 			"analyze_process_stability",
 			func() (any, error) {
-				return srv.handleGetProcessStability(testProject, testBoard, true)
+				return srv.handleGetProcessStability(ctx, testProject, testBoard, true)
 			},
 		},
 		{
 			"analyze_wip_stability",
 			func() (any, error) {
-				return srv.handleAnalyzeWIPStability(testProject, testBoard)
+				return srv.handleAnalyzeWIPStability(ctx, testProject, testBoard, false)
 			},
 		},
 		{
 			"analyze_wip_age_stability",
 			func() (any, error) {
-				return srv.handleAnalyzeWIPAgeStability(testProject, testBoard)
+				return srv.handleAnalyzeWIPAgeStability(ctx, testProject, testBoard)
 			},
 		},
 		{
 			"analyze_process_evolution",
 			func() (any, error) {
-				return srv.handleGetProcessEvolution(testProject, testBoard, "month")
+				return srv.handleGetProcessEvolution(ctx, testProject, testBoard, "month")
 			},
 		},
 		{
 			"analyze_yield",
 			func() (any, error) {
-				return srv.handleGetProcessYield(testProject, testBoard)
+				return srv.handleGetProcessYield(ctx, testProject, testBoard)
 			},
 		},
 		{
 			"analyze_flow_debt",
 			func() (any, error) {
-				return srv.handleGetFlowDebt(testProject, testBoard, "week")
+				return srv.handleGetFlowDebt(ctx, testProject, testBoard, "week", "")
 			},
 		},
 		{
 			"generate_cfd_data",
 			func() (any, error) {
-				return srv.handleGetCFDData(testProject, testBoard, "")
+				return srv.handleGetCFDData(ctx, testProject, testBoard, "")
 			},
 		},
 		{
 			"analyze_residence_time",
 			func() (any, error) {
-				return srv.handleAnalyzeResidenceTime(testProject, testBoard, nil, "day")
+				return srv.handleAnalyzeResidenceTime(ctx, testProject, testBoard, nil, "day")
 			},
 		},
 		{
 			"forecast_monte_carlo_scope",
 			func() (any, error) {
-				return srv.handleRunSimulation(
-					testProject, testBoard,
-					"scope",
-					false, 0, 60, "", // targetDays=60
-					"", nil, false,
-					90, "", "", nil, nil,
-				)
+				return srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+					ProjectKey:        testProject,
+					BoardID:           testBoard,
+					Mode:              "scope",
+					TargetDays:        60,
+					HistoryWindowDays: 90,
+				})
 			},
 		},
 		{
 			"forecast_monte_carlo_duration",
 			func() (any, error) {
-				return srv.handleRunSimulation(
-					testProject, testBoard,
-					"duration",
-					true, 0, 0, "", // includeExistingBacklog=true
-					"", nil, true, // includeWIP=true
-					90, "", "", nil, nil,
-				)
+				return srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+					ProjectKey:             testProject,
+					BoardID:                testBoard,
+					Mode:                   "duration",
+					IncludeExistingBacklog: true,
+					IncludeWIP:             true,
+					HistoryWindowDays:      90,
+				})
 			},
 		},
 	}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"fmt"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+// groupBy segments throughput and cycle time by an attribute of the items:
+// their assignee, the team custom field (JIRA_TEAM_FIELD), or their
+// components. Groups reuse the stream attribution of analyze_throughput_streams
+// (stats.StreamKeys), so an item with several components counts in each.
+
+// groupDimension returns the stream dimension of a group_by value.
+func groupDimension(groupBy GroupDimension) (string, error) {
+	switch groupBy {
+	case GroupByAssignee:
+		return stats.StreamByAssignee, nil
+	case GroupByTeam:
+		return stats.StreamByTeam, nil
+	case GroupByComponent:
+		return stats.StreamByComponent, nil
+	}
+	return "", fmt.Errorf("invalid group_by %q: must be 'assignee', 'team', or 'component'", groupBy)
+}
+
+// groupableIssues returns the issues to group by dimension. With
+// MCS_ANONYMIZE set, assignees are replaced by pseudonyms first. It fails when
+// no issue has a value for the dimension, which for assignees and teams
+// usually means they are not fetched.
+func (s *Server) groupableIssues(issues []jira.Issue, dimension string) ([]jira.Issue, error) {
+	if dimension == stats.StreamByAssignee && s.anonymizer != nil {
+		issues = append([]jira.Issue(nil), issues...)
+		for i := range issues {
+			if issues[i].Assignee != "" {
+				issues[i].Assignee = s.anonymizer.person(issues[i].Assignee)
+			}
+		}
+	}
+	for _, issue := range issues {
+		if len(stats.StreamKeys(issue, dimension)) > 0 {
+			return issues, nil
+		}
+	}
+	switch dimension {
+	case stats.StreamByTeam:
+		return nil, fmt.Errorf("group_by=team needs the team custom field, but no item in the window has a value. Set JIRA_TEAM_FIELD to the field (e.g. customfield_10001) and re-import the history")
+	case stats.StreamByAssignee:
+		return nil, fmt.Errorf("group_by=assignee needs assignees, but no item in the window has one. Assignees are personal data and only fetched with JIRA_FETCH_ASSIGNEE=true; set it and re-import the history")
+	}
+	return nil, fmt.Errorf("group_by=%s: no item in the window has a %s", dimension, dimension)
+}
+
+// groupCycleTimes splits cycle times by the groups of their items; items
+// maps each cycle time to its item. It also returns the number of items
+// without a group.
+func groupCycleTimes(items []jira.Issue, cycleTimes []float64, dimension string) (map[string][]float64, int) {
+	groups := make(map[string][]float64)
+	ungrouped := 0
+	for i, issue := range items {
+		keys := stats.StreamKeys(issue, dimension)
+		if len(keys) == 0 {
+			ungrouped++
+			continue
+		}
+		for _, k := range keys {
+			groups[k] = append(groups[k], cycleTimes[i])
+		}
+	}
+	return groups, ungrouped
+}
@@ -0,0 +1,38 @@
+package mcp
+
+import (
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+func TestGroupableIssues(t *testing.T) {
+	srv := &Server{anonymizer: newAnonymizer("salt")}
+	issues := []jira.Issue{{Key: "A-1", Assignee: "Ada Lovelace"}, {Key: "A-2"}}
+
+	got, err := srv.groupableIssues(issues, stats.StreamByAssignee)
+	if err != nil {
+		t.Fatalf("groupableIssues: %v", err)
+	}
+	if !strings.HasPrefix(got[0].Assignee, personPrefix) || issues[0].Assignee != "Ada Lovelace" {
+		t.Errorf("Expected a pseudonym in a copy of the issues, got %q", got[0].Assignee)
+	}
+
+	if _, err := srv.groupableIssues(issues, stats.StreamByTeam); err == nil || !strings.Contains(err.Error(), "JIRA_TEAM_FIELD") {
+		t.Errorf("Expected the missing team field to be named, got %v", err)
+	}
+}
+
+func TestGroupCycleTimes(t *testing.T) {
+	items := []jira.Issue{
+		{Components: []string{"API"}},
+		{Components: []string{"API", "Web"}},
+		{},
+	}
+	groups, ungrouped := groupCycleTimes(items, []float64{1, 2, 3}, stats.StreamByComponent)
+	if len(groups["API"]) != 2 || len(groups["Web"]) != 1 || ungrouped != 1 {
+		t.Errorf("Expected API x2, Web x1 and one ungrouped item, got %v (%d)", groups, ungrouped)
+	}
+}
@@ -0,0 +1,524 @@
+package mcp
+
+import (
+	"slices"
+
+	"mcs-mcp/internal/simulation"
+)
+
+// guidanceFacts are the result-derived conditions that guidance rules are
+// evaluated against. Handlers fill in only what they measured; zero values
+// never satisfy a data-conditioned rule.
+type guidanceFacts struct {
+	MappingVerified bool    // A user-confirmed workflow mapping is active
+	FatTailRatio    float64 // P98/P50 of the primary distribution
+	StabilityIndex  float64 // Little's Law stability index
+	SignalCount     int     // XmR special-cause signals (outliers + shifts)
+	FlowDebt        int     // Cumulative arrivals minus departures over the window
+	HasFlowDebt     bool    // FlowDebt was measured (distinguishes 0 from "not computed")
+	ActionCount     int     // Entries in 'recommended_actions'
+	Portfolio       bool    // The result consolidates several boards
+	SparseSample    bool    // The forecast bootstrapped fewer than 30 items or sprints
+	Explained       bool    // The forecast carries an 'explain' section
+	Reconciled      bool    // The result carries a 'residency_reconciliation'
+	ByColumn        bool    // The result is aggregated to board columns
+	Batched         bool    // Weekly throughput varies more than independent days imply
+}
+
+// guidanceRule is a single piece of agent-facing advice and the data
+// condition under which it applies.
+type guidanceRule struct {
+	ID    string
+	Tools []string                   // Tools the rule applies to
+	When  func(f guidanceFacts) bool // nil means the rule always applies
+	Text  string
+}
+
+// Guidance thresholds.
+const (
+	// clogThreshold is the stability index above which the system is "Clogged".
+	clogThreshold = 1.3
+)
+
+// guidanceRules is the ordered rule table. Within a tool, matching rules are
+// emitted in table order.
+var guidanceRules = []guidanceRule{
+	// Import & Setup
+	{
+		ID:    "ingestion_complete",
+		Tools: []string{"import_project_context", "import_board_context"},
+		Text:  "Data Ingestion Complete: History is loaded and analyzed.",
+	},
+	{
+		ID:    "review_data_summary",
+		Tools: []string{"import_project_context", "import_board_context"},
+		Text:  "Review the 'data_summary' to understand volume and issue types.",
+	},
+	{
+		ID:    "mapping_unverified",
+		Tools: []string{"import_project_context", "import_board_context"},
+		When:  func(f guidanceFacts) bool { return !f.MappingVerified },
+		Text:  "Next Step: Call 'workflow_discover_mapping' to establish the semantic process mapping.",
+	},
+	{
+		ID:    "roadmap_after_mapping",
+		Tools: []string{"import_project_context", "import_board_context"},
+		When:  func(f guidanceFacts) bool { return !f.MappingVerified },
+		Text:  "Once mapping is confirmed, use 'guide_diagnostic_roadmap' to plan your analysis.",
+	},
+	{
+		ID:    "mapping_verified",
+		Tools: []string{"import_board_context"},
+		When:  func(f guidanceFacts) bool { return f.MappingVerified },
+		Text:  "A previously confirmed workflow mapping is loaded for this board. Use 'guide_diagnostic_roadmap' to plan your analysis, or 'workflow_discover_mapping' to review the mapping.",
+	},
+
+	// Workflow discovery
+	{
+		ID:    "summarize_tiers",
+		Tools: []string{"workflow_discover_mapping"},
+		Text:  "AI MUST summarize the mapping of ALL statuses to TIERS (Demand, Upstream, Downstream, Finished) for the user in a clear table or list.",
+	},
+	{
+		ID:    "confirm_outcome_strategy",
+		Tools: []string{"workflow_discover_mapping"},
+		Text: "AI MUST confirm the 'Outcome Strategy' (Value vs. Abandonment):\n" +
+			"  - PRIMARY: Jira Resolutions (if they exist).\n" +
+			"  - SECONDARY: Status mapping (only if resolutions are missing).",
+	},
+	{
+		ID:    "confirm_commitment_point",
+		Tools: []string{"workflow_discover_mapping"},
+		Text:  "AI MUST ask the user to confirm the 'Commitment Point' (the status where work officially starts).",
+	},
+	{
+		ID:    "stability_definition",
+		Tools: []string{"workflow_discover_mapping"},
+		Text:  "PROCESS STABILITY: Understand that Stability measures Cycle-Time predictability, NOT throughput volume.",
+	},
+
+	{
+		ID:    "workflow_definition_mapping",
+		Tools: []string{"analyze_definition_of_workflow"},
+		When:  func(f guidanceFacts) bool { return !f.MappingVerified },
+		Text:  "No confirmed workflow mapping is active, so tier coverage and divergences cannot be assessed. Run 'workflow_discover_mapping' and persist the mapping first.",
+	},
+	{
+		ID:    "workflow_definition_paths",
+		Tools: []string{"analyze_definition_of_workflow"},
+		Text:  "'common_paths' lists each type's most frequent status sequences in first-visit order. Divergences compare each type against the board as a whole, not against an ideal process.",
+	},
+
+	// Forecasting & cycle time
+	{
+		ID:    "fat_tail",
+		Tools: []string{"analyze_cycle_time", "forecast_monte_carlo", "forecast_burnup"},
+		When:  func(f guidanceFacts) bool { return f.FatTailRatio >= simulation.FatTailThreshold },
+		Text:  "FAT TAIL: The P98/P50 ratio exceeds 5.6 (Kanban University heuristic), so a few extreme items dominate the distribution. Quote P95 or higher for commitments and investigate the outliers (e.g. via 'analyze_process_stability') before relying on the median.",
+	},
+
+	// Throughput
+	{
+		ID:    "batching",
+		Tools: []string{"analyze_throughput"},
+		Text:  "Look for 'Batching' (bursts of delivery followed by silence) vs. 'Steady Flow'.",
+	},
+	{
+		ID:    "weekly_batching",
+		Tools: []string{"analyze_throughput"},
+		When:  func(f guidanceFacts) bool { return f.Batched },
+		Text:  "WEEKLY BATCHING: Weekly throughput varies more than independent days imply ('batching.dispersion_ratio'), so good and slow days cluster in weeks. Run 'forecast_monte_carlo' with sampling_granularity=week; daily sampling would understate the spread of the forecast.",
+	},
+	{
+		ID:    "throughput_histogram_sample",
+		Tools: []string{"analyze_throughput_histogram"},
+		Text: "When a type is eligible in 'meta.stratification_decisions', the simulation draws each type from its own row in 'stratified_counts'; otherwise it draws every simulated day from 'counts' and splits it by 'meta.type_distribution'. " +
+			"Many zero days or a few very large days mean wide forecasts; check 'meta.dropped_by_window' and 'meta.dropped_by_outcome' when delivered items seem to be missing.",
+	},
+
+	// Stability
+	{
+		ID:    "xmr_special_cause",
+		Tools: []string{"analyze_process_stability"},
+		Text:  "XmR charts detect 'Special Cause' variation with Wheeler's rules 1–4 (outliers, clusters, shifts, trends). If stability is low, forecasts are unreliable; a shift or trend means the process changed within the window.",
+	},
+	{
+		ID:    "clogged",
+		Tools: []string{"analyze_process_stability"},
+		When:  func(f guidanceFacts) bool { return f.StabilityIndex > clogThreshold },
+		Text:  "CLOGGED: Stability Index = (WIP / Throughput) / Average Cycle Time exceeds 1.3. More work is in progress than the delivery rate supports — expect cycle times to inflate until WIP is reduced.",
+	},
+	{
+		ID:    "scatterplot_rendering",
+		Tools: []string{"analyze_process_stability"},
+		Text: "The 'scatterplot' array contains one entry per delivered work item with cycle time (value), date (the work item's outcome date), pooled moving range, and issue type. " +
+			"Render a Cycle Time Scatterplot: X=date, Y=value. " +
+			"Reference lines from stability.xmr: average (center), upper_natural_process_limit, lower_natural_process_limit. " +
+			"For type-specific limits, use stratified[type].xmr.",
+	},
+	{
+		ID:    "cycle_time_scatter_rendering",
+		Tools: []string{"analyze_cycle_time_scatter"},
+		Text: "Render a Cycle Time Scatterplot from 'points': X=date (completion), Y=value (cycle time in days), one dot per item, colored by issue_type. " +
+			"Draw 'bands' p50/p85/p95 as horizontal reference lines; 'bands_by_type' holds type-specific lines. Use each point's key for drill-down.",
+	},
+	{
+		ID:    "outlier_drilldown",
+		Tools: []string{"find_outliers"},
+		Text: "'outliers.items' lists the slowest delivered items, slowest first, with cycle time, its percentile, and blocked time. 'outliers.bands' holds P50/P85/P95 of the ranked measure: items far above P95 are exceptions, items near it are the usual tail. " +
+			"Follow up with 'analyze_item_journey' on their keys before naming causes; a summary alone does not explain a delay.",
+	},
+	{
+		ID:    "sparse_sample",
+		Tools: []string{"forecast_monte_carlo"},
+		When:  func(f guidanceFacts) bool { return f.SparseSample },
+		Text:  "SPARSE SAMPLE: Fewer than 30 delivered items (or sprints) back this forecast, so bootstrapped percentiles are jagged and overconfident. Re-run with sampling='parametric' to sample from a fitted Weibull or lognormal distribution, and present both results.",
+	},
+	{
+		ID:    "sle_next",
+		Tools: []string{"set_sle"},
+		Text:  "SLE recorded. Call 'analyze_sle_compliance' to track the hit rate and the in-flight items on track to breach.",
+	},
+	{
+		ID:    "audit_reading",
+		Tools: []string{"workflow_get_audit"},
+		Text: "Changes to 'mapping', 'commitment_point', or 'resolutions' redefine cycle time, WIP, and throughput: results before and after such an entry are not comparable. " +
+			"Tell the user which forecasts or diagnostics the change affects before comparing them.",
+	},
+	{
+		ID:    "type_aliases_scope",
+		Tools: []string{"workflow_set_type_aliases"},
+		Text: "Aliases apply to forecasts and backtests: the throughput histogram, type mix, stratification, targets, and mix_overrides use the canonical type. " +
+			"Diagnostics such as 'analyze_cycle_time' still report Jira's own types. Re-run 'forecast_monte_carlo' to see the merged streams in 'context.stratification_decisions'.",
+	},
+	{
+		ID:    "sle_compliance_reading",
+		Tools: []string{"analyze_sle_compliance"},
+		Text: "Compare 'hit_rate' with 'expected_hit_rate' (the SLE percentile): a P85 SLE is met when at least 85% of delivered items finished in time. " +
+			"'breach_probability' of a WIP item is the share of historical items of the same age that went on to breach; act on 'at_risk' items before they become 'breached'.",
+	},
+	{
+		ID:    "wip_limits_next",
+		Tools: []string{"set_wip_limits"},
+		Text:  "WIP limits recorded. Call 'analyze_wip_limits' to see how often and how long they were exceeded.",
+	},
+	{
+		ID:    "wip_limits_reading",
+		Tools: []string{"analyze_wip_limits"},
+		Text: "WIP is counted at the end of each day, as in the CFD. A 'violation_rate' above 0.2 means the limit is not working as a policy — discuss lowering demand or raising the limit deliberately. " +
+			"'inflation' > 1 means items caught in a violation took longer; treat it as correlation, and confirm with 'analyze_status_persistence' before blaming the status.",
+	},
+	{
+		ID:    "alert_rules_next",
+		Tools: []string{"set_alert_rules"},
+		Text:  "Alert rules recorded. Call 'evaluate_alerts' to check them now; 'mcs-mcp digest' evaluates them on a schedule.",
+	},
+	{
+		ID:    "alerts_reading",
+		Tools: []string{"evaluate_alerts"},
+		Text: "A fired rule is a prompt to look, not a diagnosis: report its evidence, then run the analysis behind the metric before recommending action. " +
+			"Rules on small samples (few delivered items or weeks) fire on noise — surface the warnings.",
+	},
+	{
+		ID:    "forecast_drift_reading",
+		Tools: []string{"compare_forecasts"},
+		Text: "Attribute drift before reporting it: a change in 'composition.total' means the scope moved, a change in 'throughput' means delivery capability moved. " +
+			"Surface every warning — runs with different horizons, issue types, or engines are not like-for-like.",
+	},
+	{
+		ID:    "commitment_next",
+		Tools: []string{"record_commitment"},
+		Text:  "Commitment recorded. Run 'analyze_commitment_health' on later days — each run adds a check, so the trend builds up over time.",
+	},
+	{
+		ID:    "commitment_health_reading",
+		Tools: []string{"analyze_commitment_health"},
+		Text: "Report at-risk commitments with their trend and remaining items, and leave de-scoping or re-committing to the user; a renegotiated date is recorded as a new commitment after withdrawing the old one. " +
+			"Commitments without issue_keys count every delivery since the commitment, unplanned work included, so their probability is optimistic.",
+	},
+	{
+		ID:    "evolution_window",
+		Tools: []string{"analyze_process_evolution"},
+		Text:  "Process evolution is a long-term trend metric. This tool ignores the session window's Start and uses ONLY its End as the right edge. Lookback is fixed: 12 complete months (bucket='month') or 26 complete weeks (bucket='week'). To shift the trend's right edge, set the session window's End via 'set_analysis_window'.",
+	},
+	{
+		ID:    "yield_upstream",
+		Tools: []string{"analyze_yield"},
+		Text:  "High 'Abandoned Upstream' often points to discovery/refinement issues.",
+	},
+	{
+		ID:    "yield_downstream",
+		Tools: []string{"analyze_yield"},
+		Text:  "High 'Abandoned Downstream' points to execution or commitment issues.",
+	},
+
+	{
+		ID:    "abandonment_tiers",
+		Tools: []string{"analyze_abandonment"},
+		Text:  "Each abandoned item is placed in the last status it held before a Finished status. 'wasted_downstream_days' counts only the time in Downstream statuses, the capacity the team had committed; time in Demand and Upstream is the cost of deciding.",
+	},
+
+	// WIP
+	{
+		ID:    "wip_run_chart",
+		Tools: []string{"analyze_wip_stability"},
+		Text:  "WIP Stability provides a daily historical view of system population.",
+	},
+	{
+		ID:    "wip_signals",
+		Tools: []string{"analyze_wip_stability"},
+		When:  func(f guidanceFacts) bool { return f.SignalCount > 0 },
+		Text:  "Signals (Outliers/Shifts) indicate that WIP was not actively managed or constrained, which violates Little's Law.",
+	},
+	{
+		ID:    "wip_unstable",
+		Tools: []string{"analyze_wip_stability"},
+		When:  func(f guidanceFacts) bool { return f.SignalCount > 0 },
+		Text:  "The system is 'unstable': flow metrics (Cycle Time, Throughput) will be unpredictable and simulations may fail.",
+	},
+	{
+		ID:    "wip_age_burden",
+		Tools: []string{"analyze_wip_age_stability"},
+		Text:  "Total WIP Age reveals the cumulative age burden on the system.",
+	},
+	{
+		ID:    "wip_age_vs_count",
+		Tools: []string{"analyze_wip_age_stability"},
+		Text:  "While WIP Count tells how many items are in progress, Total WIP Age tells how long they have collectively been there.",
+	},
+	{
+		ID:    "wip_age_leading",
+		Tools: []string{"analyze_wip_age_stability"},
+		Text:  "A growing Total WIP Age means items are aging without being delivered — it is a leading indicator of delivery problems.",
+	},
+	{
+		ID:    "wip_age_stagnation",
+		Tools: []string{"analyze_wip_age_stability"},
+		Text:  "Even with stable WIP count, Total WIP Age can grow if items stagnate.",
+	},
+	{
+		ID:    "wip_age_natural",
+		Tools: []string{"analyze_wip_age_stability"},
+		Text:  "Natural behavior: Total WIP Age grows by (WIP count x 1 day) per day when no items enter or exit.",
+	},
+	{
+		ID:    "wip_age_average",
+		Tools: []string{"analyze_wip_age_stability"},
+		Text:  "Average WIP Age is provided for convenience but is less informative — it can mask individual outliers and assumes nothing about the distribution shape.",
+	},
+	{
+		ID:    "wip_age_xmr",
+		Tools: []string{"analyze_wip_age_stability"},
+		Text:  "The XmR analysis on Total WIP Age is the most defensible signal — it detects process changes without distribution assumptions.",
+	},
+
+	// Flow debt & CFD
+	{
+		ID:    "flow_debt_positive",
+		Tools: []string{"analyze_flow_debt"},
+		When:  func(f guidanceFacts) bool { return f.HasFlowDebt && f.FlowDebt > 0 },
+		Text:  "Positive Flow Debt (Arrivals > Departures) is a leading indicator of cycle time inflation.",
+	},
+	{
+		ID:    "flow_debt_balanced",
+		Tools: []string{"analyze_flow_debt"},
+		When:  func(f guidanceFacts) bool { return f.HasFlowDebt && f.FlowDebt <= 0 },
+		Text:  "Zero or Negative Flow Debt indicates a stable or improving system throughput-to-workload ratio.",
+	},
+	{
+		ID:    "cfd_definition",
+		Tools: []string{"generate_cfd_data"},
+		Text:  "CFD (Cumulative Flow Diagram) provides a snapshot of work items by status and issue type.",
+	},
+	{
+		ID:    "cfd_rendering",
+		Tools: []string{"generate_cfd_data"},
+		Text:  "The visualization agent should use this data to render a stacked area chart.",
+	},
+
+	// Persistence & aging
+	{
+		ID:    "persistence_delivered_only",
+		Tools: []string{"analyze_status_persistence"},
+		Text:  "Status Persistence EXCLUSIVELY analyzes items that have successfully finished ('delivered') to prevent active WIP from skewing historical norms.",
+	},
+	{
+		ID:    "persistence_not_forecast",
+		Tools: []string{"analyze_status_persistence"},
+		Text:  "Persistence stats (coin_toss, likely, etc.) measure INTERNAL residency time WITHIN one status. They ARE NOT end-to-end completion forecasts.",
+	},
+	{
+		ID:    "persistence_spread",
+		Tools: []string{"analyze_status_persistence"},
+		Text:  "Inner80 and IQR help distinguish between 'Stable Flow' and 'High Variance' bottlenecks.",
+	},
+	{
+		ID:    "persistence_tiers",
+		Tools: []string{"analyze_status_persistence"},
+		Text:  "Tier Summary aggregates performance by meta-workflow phase (Demand, Upstream, Downstream).",
+	},
+	{
+		ID:    "column_granularity",
+		Tools: []string{"analyze_status_persistence", "analyze_work_item_age", "analyze_wip_stability"},
+		When:  func(f guidanceFacts) bool { return f.ByColumn },
+		Text: "Results are per board column: the time in all statuses of a column is summed, and moves between statuses of the same column do not count. " +
+			"Each column takes the tier and role of its first mapped status, and the clock starts when an item enters the column of the commitment point. Name the columns as the team sees them on the board.",
+	},
+	{
+		ID:    "persistence_reconciliation",
+		Tools: []string{"analyze_status_persistence"},
+		When:  func(f guidanceFacts) bool { return f.Reconciled },
+		Text: "'residency_reconciliation' compares the residency derived from the changelog with the time-in-status field, status by status. The current status of an item is not compared. " +
+			"Where the two agree, the persistence figures are trustworthy. A status that disagrees systematically (large 'mean_difference_days') usually means the field counts differently (e.g. working hours only) or the history is incomplete; name it before drawing conclusions from that status.",
+	},
+	{
+		ID:    "flow_efficiency_reading",
+		Tools: []string{"analyze_flow_efficiency"},
+		Text: "Flow efficiency counts only time in statuses with an 'active' or 'queue' role; 'coverage' says how much of the cycle time that is. " +
+			"Time spent blocked inside an active status still counts as active, so the figure is an upper bound. Compare 'by_tier' to see whether waiting happens before or after work starts.",
+	},
+	{
+		ID:    "rework_reading",
+		Tools: []string{"analyze_rework"},
+		Text: "'rework_rate' of a pair is the share of items that reached its 'from' status and were sent back. " +
+			"Compare 'reworked_p85' with 'first_time_right_p85' to show what rework costs in predictability, and follow up on the worst items with 'analyze_item_journey'.",
+	},
+	{
+		ID:    "age_point_in_time",
+		Tools: []string{"analyze_work_item_age"},
+		Text:  "Work item age is a point-in-time metric, NOT a range metric. This tool ignores the session window's Start and uses ONLY its End as the as-of date for in-flight items and age calculation. To analyse 'as of' a different date, set the session window's End via 'set_analysis_window'.",
+	},
+	{
+		ID:    "age_tier_scope",
+		Tools: []string{"analyze_work_item_age"},
+		Text:  "Items in 'Demand' or 'Finished' tiers are usually excluded from WIP Age unless explicitly requested.",
+	},
+	{
+		ID:    "age_percentile_relative",
+		Tools: []string{"analyze_work_item_age"},
+		Text:  "PercentileRelative helps identify which individual items are 'neglect' risks compared to historical performance.",
+	},
+	{
+		ID:    "age_since_commitment",
+		Tools: []string{"analyze_work_item_age"},
+		Text:  "AgeSinceCommitment reflects time since the LAST commitment (resets on backflow to Demand/Upstream).",
+	},
+	{
+		ID:    "journey_path",
+		Tools: []string{"analyze_item_journey"},
+		Text:  "The 'path' shows chronological flow, while 'residency' shows cumulative totals.",
+	},
+	{
+		ID:    "mermaid_visuals",
+		Tools: []string{"set_visual_preferences"},
+		Text: "Mermaid xy charts have no legend; each diagram's title names its bars and lines. " +
+			"Quote numbers from 'data', not values read off a diagram.",
+	},
+	{
+		ID:    "release_burnup_scope",
+		Tools: []string{"analyze_release_burnup"},
+		Text: "Jira does not record when an item joined a fixVersion, so scope is dated by item creation. " +
+			"Items moved into the release late appear as early scope, and items moved out of it disappear from the whole history.",
+	},
+	{
+		ID:    "item_forecast_path",
+		Tools: []string{"forecast_item"},
+		Text: "The forecast assumes the item follows the remaining statuses in 'context.remaining_path' at historical residence times and visit rates. " +
+			"It does not know about blockers, priority changes, or rework; if the item is stuck, use 'analyze_item_journey' to see where its time went.",
+	},
+	{
+		ID:    "forecast_explain",
+		Tools: []string{"forecast_monte_carlo"},
+		When:  func(f guidanceFacts) bool { return f.Explained },
+		Text: "Present 'explain.drivers' as one-at-a-time sensitivities: each changes one input on the same random seed, so the effects do not add up. " +
+			"A large gap between 'recent_throughput' and 'older_throughput' means the throughput changed within the sample; say which half the forecast should trust before blaming the backlog.",
+	},
+	{
+		ID:    "scenario_comparison",
+		Tools: []string{"forecast_scenarios"},
+		Text: "Compare scenarios on P85, not P50: a what-if that only moves the median has not changed what you can commit to. " +
+			"A 'p85_change' of a day or an item or two is small against the spread between P50 and P95; present a scenario as better only when the change clearly matters for the decision.",
+	},
+	{
+		ID:    "burnup_cone_reading",
+		Tools: []string{"forecast_burnup"},
+		Text: "Read the cone per line, not per point: P85 is the count delivered at least with 85% probability by that date, so plan on P85 and treat P50 as a coin toss. " +
+			"The cone widens with the horizon; re-run it as weeks pass instead of relying on far points. Work that arrives meanwhile is not subtracted.",
+	},
+	{
+		ID:    "timebox_unplanned_work",
+		Tools: []string{"forecast_timebox"},
+		Text: "The sampled throughput counts every delivered item, including unplanned work. If unplanned work will keep arriving during the timebox, it competes with the commitment and the probability is optimistic. " +
+			"De-scope counts are items to move out of the timebox, not a ranking: pick them by value.",
+	},
+
+	// Sprints
+	{
+		ID:    "sprint_throughput_sample",
+		Tools: []string{"analyze_sprint_history"},
+		Text:  "Sprint 'throughput' counts all items delivered during the sprint, including unplanned work; it is the sample used by sprint-mode forecasts.",
+	},
+	{
+		ID:    "sprint_carry_over",
+		Tools: []string{"analyze_sprint_history"},
+		Text:  "Carry-over is measured at the sprint close. Items re-assigned to the next sprint count as carry-over in each sprint they were open at.",
+	},
+
+	// Portfolio
+	{
+		ID:    "portfolio_sources",
+		Tools: []string{"import_portfolio"},
+		Text:  "Each source is projected with its own workflow mapping. Pass the same boards as 'sources' to forecast_monte_carlo, analyze_throughput, or analyze_work_item_age for program-level results.",
+	},
+	{
+		ID:    "portfolio_dedup",
+		Tools: []string{"import_portfolio", "forecast_monte_carlo", "analyze_throughput", "analyze_work_item_age"},
+		When:  func(f guidanceFacts) bool { return f.Portfolio },
+		Text:  "Portfolio result: 'portfolio.shared_issues' counts the issues listed by several boards, and 'portfolio.duplicates' says how they were attributed ('first' and 'split' count each once, 'all' once per board). Per-board shares are in 'portfolio.sources'.",
+	},
+
+	// Source settings
+	{
+		ID:    "source_settings",
+		Tools: []string{"get_source_settings", "set_source_settings"},
+		Text:  "A parameter passed to a call wins over its source setting, which wins over the server setting. An active set_analysis_window wins over window_weeks.",
+	},
+
+	// Forecast templates
+	{
+		ID:    "save_forecast_template",
+		Tools: []string{"save_forecast_template", "list_forecast_templates"},
+		Text:  "A template fills only the parameters a forecast_monte_carlo call leaves out; include_wip and include_existing_backlog stay on once a template sets them. Name the template when presenting a forecast made with it, and confirm changes to a template with the user, since later forecasts inherit them.",
+	},
+
+	// Recommended actions
+	{
+		ID:    "recommended_actions",
+		Tools: []string{"analyze_flow_debt", "analyze_work_item_age"},
+		When:  func(f guidanceFacts) bool { return f.ActionCount > 0 },
+		Text:  "Present 'recommended_actions' in rank order and quote each action's evidence. Do not substitute free-form advice for the ranked list.",
+	},
+}
+
+// selectGuidance returns the text of every rule that applies to tool and
+// whose condition holds for the given facts, in rule-table order.
+func selectGuidance(tool string, f guidanceFacts) []string {
+	var out []string
+	for _, r := range guidanceRules {
+		if !slices.Contains(r.Tools, tool) {
+			continue
+		}
+		if r.When != nil && !r.When(f) {
+			continue
+		}
+		out = append(out, r.Text)
+	}
+	return out
+}
+
+// guidanceFor evaluates the rule table for tool, filling in the facts that
+// derive from server state rather than from the tool's result.
+func (s *Server) guidanceFor(tool string, f guidanceFacts) []string {
+	f.MappingVerified = len(s.activeMapping) > 0
+	return selectGuidance(tool, f)
+}
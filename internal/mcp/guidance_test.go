@@ -0,0 +1,65 @@
+package mcp
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSelectGuidance_MappingReminder(t *testing.T) {
+	unverified := selectGuidance("import_board_context", guidanceFacts{})
+	if !slices.ContainsFunc(unverified, func(g string) bool {
+		return strings.Contains(g, "workflow_discover_mapping") && strings.HasPrefix(g, "Next Step")
+	}) {
+		t.Errorf("Expected mapping reminder when mapping is unverified, got %v", unverified)
+	}
+
+	verified := selectGuidance("import_board_context", guidanceFacts{MappingVerified: true})
+	if slices.ContainsFunc(verified, func(g string) bool { return strings.HasPrefix(g, "Next Step") }) {
+		t.Errorf("Did not expect mapping reminder when mapping is verified, got %v", verified)
+	}
+	if !slices.ContainsFunc(verified, func(g string) bool { return strings.Contains(g, "previously confirmed") }) {
+		t.Errorf("Expected confirmed-mapping guidance, got %v", verified)
+	}
+}
+
+func TestSelectGuidance_FatTail(t *testing.T) {
+	isFatTail := func(g string) bool { return strings.HasPrefix(g, "FAT TAIL") }
+
+	if g := selectGuidance("analyze_cycle_time", guidanceFacts{FatTailRatio: 3.2}); slices.ContainsFunc(g, isFatTail) {
+		t.Errorf("Did not expect fat-tail guidance below threshold, got %v", g)
+	}
+	if g := selectGuidance("analyze_cycle_time", guidanceFacts{FatTailRatio: 7.5}); !slices.ContainsFunc(g, isFatTail) {
+		t.Errorf("Expected fat-tail guidance above threshold, got %v", g)
+	}
+	if g := selectGuidance("analyze_throughput", guidanceFacts{FatTailRatio: 7.5}); slices.ContainsFunc(g, isFatTail) {
+		t.Errorf("Fat-tail guidance must not leak into unrelated tools, got %v", g)
+	}
+}
+
+func TestSelectGuidance_FlowDebt(t *testing.T) {
+	positive := selectGuidance("analyze_flow_debt", guidanceFacts{FlowDebt: 12, HasFlowDebt: true})
+	if len(positive) != 1 || !strings.HasPrefix(positive[0], "Positive") {
+		t.Errorf("Expected only positive-debt guidance, got %v", positive)
+	}
+	balanced := selectGuidance("analyze_flow_debt", guidanceFacts{FlowDebt: 0, HasFlowDebt: true})
+	if len(balanced) != 1 || !strings.HasPrefix(balanced[0], "Zero or Negative") {
+		t.Errorf("Expected only balanced-debt guidance, got %v", balanced)
+	}
+	if g := selectGuidance("analyze_flow_debt", guidanceFacts{}); len(g) != 0 {
+		t.Errorf("Expected no flow-debt guidance when debt was not measured, got %v", g)
+	}
+}
+
+func TestGuidanceRules_UniqueIDs(t *testing.T) {
+	seen := make(map[string]bool)
+	for _, r := range guidanceRules {
+		if r.ID == "" || r.Text == "" || len(r.Tools) == 0 {
+			t.Errorf("Rule %q is incomplete", r.ID)
+		}
+		if seen[r.ID] {
+			t.Errorf("Duplicate rule ID %q", r.ID)
+		}
+		seen[r.ID] = true
+	}
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -11,23 +12,36 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
+// probeSource summarizes the ingested history of a source without a workflow
+// mapping: its boundaries and a tier-neutral sample.
+func (s *Server) probeSource(ctx context.Context, sourceID string) stats.MetadataSummary {
+	events := s.events.GetIssuesInRange(sourceID, time.Time{}, s.Clock(ctx))
+	first, last, total := stats.DiscoverDatasetBoundaries(events)
+	sample := stats.ProjectNeutralSample(events, DataProbeSampleSize)
+
+	summary := discovery.AnalyzeProbe(sample, total)
+	summary.Whole.FirstEventAt = first
+	summary.Whole.LastEventAt = last
+	return summary
+}
+
 // handleGetBoardDetails fetches metadata and triggers Eager Ingestion (Hydrate).
-func (s *Server) handleGetBoardDetails(projectKey string, boardID int) (any, error) {
+func (s *Server) handleGetBoardDetails(ctx context.Context, projectKey string, boardID int) (any, error) {
 	sourceID := getCombinedID(projectKey, boardID)
 
 	// 1. Resolve Source Context (ensures consistent JQL and validates board exists)
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Anchor Context (Memory Pruning + Metadata Loading)
-	if err := s.anchorContext(ctx.ProjectKey, ctx.BoardID); err != nil {
+	if err := s.anchorContext(sc.ProjectKey, sc.BoardID); err != nil {
 		return nil, err
 	}
 
 	// 3. Hydrate Protocol (Synchronous Eager Ingestion)
-	reg, err := s.events.Hydrate(sourceID, projectKey, ctx.JQL, s.activeRegistry)
+	reg, err := s.events.Hydrate(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		log.Error().Err(err).Str("source", sourceID).Msg("Hydration failed")
 		// Proceed anyway to show board metadata
@@ -38,17 +52,18 @@ func (s *Server) handleGetBoardDetails(projectKey string, boardID int) (any, err
 	}
 
 	// 4. Data Probe (Tier-Neutral Discovery)
-	events := s.events.GetIssuesInRange(sourceID, time.Time{}, s.Clock())
-	first, last, total := stats.DiscoverDatasetBoundaries(events)
-	sample := stats.ProjectNeutralSample(events, DataProbeSampleSize)
-
-	summary := discovery.AnalyzeProbe(sample, total)
-	summary.Whole.FirstEventAt = first
-	summary.Whole.LastEventAt = last
+	summary := s.probeSource(ctx, sourceID)
 
 	// 5. Fetch Board Metadata for the response (uses internal Jira cache)
 	var board any
-	if strings.ToUpper(projectKey) == "MCSTEST" {
+	if isQuerySource(projectKey) {
+		board = map[string]any{
+			"id":   boardID,
+			"name": getCombinedID(projectKey, boardID),
+			"type": strings.ToLower(projectKey),
+			"jql":  sc.JQL,
+		}
+	} else if strings.ToUpper(projectKey) == "MCSTEST" {
 		board = map[string]any{
 			"id":   boardID,
 			"name": fmt.Sprintf("Mock Test Board %d (Synthetic)", boardID),
@@ -56,7 +71,7 @@ func (s *Server) handleGetBoardDetails(projectKey string, boardID int) (any, err
 		}
 	} else {
 		var boardErr error
-		board, boardErr = s.jira.GetBoard(boardID)
+		board, boardErr = s.jira.GetBoard(ctx, boardID)
 		if boardErr != nil {
 			log.Warn().Err(boardErr).Int("boardID", boardID).Msg("Failed to fetch board metadata from Jira")
 		}
@@ -68,12 +83,7 @@ func (s *Server) handleGetBoardDetails(projectKey string, boardID int) (any, err
 		"data_summary": summary,
 	}
 
-	guidance := []string{
-		"Data Ingestion Complete: History is loaded and analyzed.",
-		"Review the 'data_summary' to understand volume and issue types.",
-		"Next Step: Call 'workflow_discover_mapping' to establish the semantic process mapping.",
-		"Once mapping is confirmed, use 'guide_diagnostic_roadmap' to plan your analysis.",
-	}
+	guidance := s.guidanceFor("import_board_context", guidanceFacts{})
 
 	return WrapResponse(res, projectKey, boardID, nil, nil, guidance), nil
 }
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 	"mcs-mcp/cmd/mockgen/engine"
 	"mcs-mcp/internal/config"
@@ -13,20 +14,30 @@ import (
 
 type DummyClient struct{}
 
-func (d *DummyClient) SearchIssues(jql string, startAt int, maxResults int) (*jira.SearchResponse, error) {
+func (d *DummyClient) SearchIssues(_ context.Context, jql string, startAt int, maxResults int) (*jira.SearchResponse, error) {
+	return nil, nil
+}
+func (d *DummyClient) GetIssueWithHistory(_ context.Context, key string) (*jira.IssueDTO, error) {
+	return nil, nil
+}
+func (d *DummyClient) GetProject(_ context.Context, key string) (any, error)         { return nil, nil }
+func (d *DummyClient) GetProjectStatuses(_ context.Context, key string) (any, error) { return nil, nil }
+func (d *DummyClient) GetBoard(_ context.Context, id int) (any, error)               { return nil, nil }
+func (d *DummyClient) GetBoardConfig(_ context.Context, id int) (any, error)         { return nil, nil }
+func (d *DummyClient) GetFilter(_ context.Context, id string) (any, error)           { return nil, nil }
+func (d *DummyClient) FindProjects(_ context.Context, query string) ([]any, error)   { return nil, nil }
+func (d *DummyClient) FindBoards(_ context.Context, pKey string, nFilter string) ([]any, error) {
+	return nil, nil
+}
+func (d *DummyClient) GetRegistry(_ context.Context, projectKey string) (*jira.NameRegistry, error) {
+	return nil, nil
+}
+func (d *DummyClient) GetSprints(_ context.Context, boardID int, limit int) ([]jira.Sprint, error) {
 	return nil, nil
 }
-func (d *DummyClient) GetIssueWithHistory(key string) (*jira.IssueDTO, error)    { return nil, nil }
-func (d *DummyClient) GetProject(key string) (any, error)                        { return nil, nil }
-func (d *DummyClient) GetProjectStatuses(key string) (any, error)                { return nil, nil }
-func (d *DummyClient) GetBoard(id int) (any, error)                              { return nil, nil }
-func (d *DummyClient) GetBoardConfig(id int) (any, error)                        { return nil, nil }
-func (d *DummyClient) GetFilter(id string) (any, error)                          { return nil, nil }
-func (d *DummyClient) FindProjects(query string) ([]any, error)                  { return nil, nil }
-func (d *DummyClient) FindBoards(pKey string, nFilter string) ([]any, error)     { return nil, nil }
-func (d *DummyClient) GetRegistry(projectKey string) (*jira.NameRegistry, error) { return nil, nil }
 
 func TestMCSTEST_Integration(t *testing.T) {
+	ctx := context.Background()
 	dists := []string{"uniform", "weibull"}
 	scenarios := []string{"mild", "chaos", "drift"}
 
@@ -50,7 +61,7 @@ func TestMCSTEST_Integration(t *testing.T) {
 				server.simulationSeed = 1
 
 				// 1. Verify Board Details
-				res, err := server.handleGetBoardDetails("MCSTEST", 0)
+				res, err := server.handleGetBoardDetails(ctx, "MCSTEST", 0)
 				if err != nil {
 					t.Fatalf("Failed to get board details: %v", err)
 				}
@@ -63,7 +74,7 @@ func TestMCSTEST_Integration(t *testing.T) {
 				}
 
 				// 2. Verify Status Persistence
-				pRes, err := server.handleGetStatusPersistence("MCSTEST", 0)
+				pRes, err := server.handleGetStatusPersistence(ctx, "MCSTEST", 0, "", false, false)
 				if err != nil {
 					t.Fatalf("Failed to get status persistence: %v", err)
 				}
@@ -104,20 +115,19 @@ func TestMCSTEST_Integration(t *testing.T) {
 				}
 
 				// 3. Verify Aging Analysis (WIP presence in Downstream)
-				aRes, err := server.handleGetAgingAnalysis("MCSTEST", 0, "wip", "Downstream")
+				aRes, err := server.handleGetAgingAnalysis(ctx, "MCSTEST", 0, "wip", "Downstream", false)
 				if err != nil {
 					t.Fatalf("Failed to get aging analysis: %v", err)
 				}
 				aEnv := aRes.(ResponseEnvelope)
-				aging := aEnv.Data.(map[string]any)
-				itemsFound := aging["aging"].([]stats.InventoryAge)
+				itemsFound := aEnv.Data.(AgingResult).Aging
 				if len(itemsFound) == 0 {
 					t.Errorf("Expected WIP items in Downstream, got 0")
 				}
 
 				// 4. Verify WFA Accuracy (only for Weibull which is tuned for stability)
 				if dist == "weibull" && scen == "mild" {
-					wfaRes, err := server.handleGetForecastAccuracy("MCSTEST", 0, "scope", 0, 14, nil, 90, "", "")
+					wfaRes, err := server.handleGetForecastAccuracy(ctx, "MCSTEST", 0, "scope", 0, 14, nil, 90, "", "", "", nil, nil)
 					if err != nil {
 						t.Fatalf("Failed to get forecast accuracy: %v", err)
 					}
@@ -134,7 +144,7 @@ func TestMCSTEST_Integration(t *testing.T) {
 				}
 
 				// 5. Verify Flow Debt
-				fRes, err := server.handleGetFlowDebt("MCSTEST", 0, "week")
+				fRes, err := server.handleGetFlowDebt(ctx, "MCSTEST", 0, "week", "")
 				if err != nil {
 					t.Fatalf("Failed to get flow debt: %v", err)
 				}
@@ -148,7 +158,7 @@ func TestMCSTEST_Integration(t *testing.T) {
 				t.Logf("[%s/%s] Flow Debt: TotalDebt=%d", dist, scen, flowDebt.TotalDebt)
 
 				// 6. Verify CFD Data
-				cRes, err := server.handleGetCFDData("MCSTEST", 0, "")
+				cRes, err := server.handleGetCFDData(ctx, "MCSTEST", 0, "")
 				if err != nil {
 					t.Fatalf("Failed to get CFD data: %v", err)
 				}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 
 	"mcs-mcp/internal/eventlog"
@@ -8,11 +9,11 @@ import (
 	"github.com/rs/zerolog/log"
 )
 
-func (s *Server) handleCacheCatchUp(projectKey string, boardID int) (any, error) {
+func (s *Server) handleCacheCatchUp(ctx context.Context, projectKey string, boardID int) (any, error) {
 	sourceID := getCombinedID(projectKey, boardID)
 
 	// 1. Resolve Source Context
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -23,24 +24,67 @@ func (s *Server) handleCacheCatchUp(projectKey string, boardID int) (any, error)
 	}
 
 	// 3. CatchUp
-	fetched, nmrc, reg, err := s.events.CatchUp(sourceID, projectKey, ctx.JQL, s.activeRegistry)
+	fetched, nmrc, reg, err := s.events.CatchUp(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		return nil, err
 	}
 	s.activeRegistry = reg
+	s.sessions.invalidate(sourceID)
 
 	// 4. Re-calculate DiscoveryCutoff (just in case)
-	s.recalculateDiscoveryCutoff(sourceID)
+	s.recalculateDiscoveryCutoff(ctx, sourceID)
 	if err := s.saveWorkflow(projectKey, boardID); err != nil {
 		log.Warn().Err(err).Msg("Failed to persist workflow metadata to disk")
 	}
 
+	state := s.events.SyncState(sourceID)
 	res := map[string]any{
-		"message": fmt.Sprintf("%d items fetched that were updated since %s", fetched, nmrc.Format(eventlog.DateTimeFormat)),
-		"fetched": fetched,
-		"nmrc":    nmrc,
+		"message":   fmt.Sprintf("%d items fetched that were updated since %s", fetched, nmrc.Format(eventlog.DateTimeFormat)),
+		"fetched":   fetched,
+		"nmrc":      nmrc,
+		"watermark": state,
 	}
 
 	return WrapResponse(res, projectKey, boardID, nil, nil, nil), nil
 }
 
+// handleImportHistoryStatus reports the cached history of a source, or of
+// every cached source when none is given: boundaries, volume, watermark, and
+// the gaps a sync still has to fill.
+func (s *Server) handleImportHistoryStatus(projectKey string, boardID int) (any, error) {
+	var sourceIDs []string
+	if projectKey != "" || boardID != 0 {
+		sourceIDs = []string{getCombinedID(projectKey, boardID)}
+	} else {
+		sources, err := s.knownSources()
+		if err != nil {
+			return nil, err
+		}
+		for _, src := range sources {
+			sourceIDs = append(sourceIDs, getCombinedID(src.ProjectKey, src.BoardID))
+		}
+	}
+
+	statuses := make([]eventlog.SourceStatus, 0, len(sourceIDs))
+	var insights []string
+	for _, sourceID := range sourceIDs {
+		st, err := s.events.Status(sourceID)
+		if err != nil {
+			return nil, err
+		}
+		statuses = append(statuses, st)
+		switch {
+		case st.Events == 0:
+			insights = append(insights, fmt.Sprintf("%s has no cached history; 'import_board_context' hydrates it.", sourceID))
+		case st.Backfill != nil:
+			insights = append(insights, fmt.Sprintf("%s: the initial hydration was interrupted after %d issues. The next tool call on this source (or 'import_history_update') resumes it below %s instead of starting over.",
+				sourceID, st.Backfill.Fetched, st.Backfill.Boundary.Format(eventlog.DateTimeFormat)))
+		}
+	}
+	if len(statuses) == 0 {
+		insights = append(insights, "No cached sources yet; 'import_board_context' hydrates a board.")
+	}
+
+	res := map[string]any{"sources": statuses}
+	return WrapResponse(res, projectKey, boardID, nil, nil, insights), nil
+}
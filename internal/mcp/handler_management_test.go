@@ -0,0 +1,44 @@
+package mcp
+
+import (
+	"testing"
+
+	"mcs-mcp/internal/eventlog"
+)
+
+func TestImportHistoryStatus(t *testing.T) {
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleImportHistoryStatus(testProject, testBoard)
+	if err != nil {
+		t.Fatalf("import_history_status: %v", err)
+	}
+	envelope := res.(ResponseEnvelope)
+	statuses := envelope.Data.(map[string]any)["sources"].([]eventlog.SourceStatus)
+	if len(statuses) != 1 || statuses[0].SourceID != testSourceID {
+		t.Fatalf("Expected the status of %s, got %+v", testSourceID, statuses)
+	}
+	st := statuses[0]
+	if st.Events == 0 || st.Issues == 0 || st.Issues > st.Events {
+		t.Errorf("Expected event and issue counts of the cached log, got %d events and %d issues", st.Events, st.Issues)
+	}
+	if st.FirstEvent.After(st.OMRC) || st.OMRC.After(st.NMRC) || !st.NMRC.Equal(st.LastEvent) {
+		t.Errorf("Expected first event <= OMRC <= NMRC = last event, got %+v", st)
+	}
+	if !st.Complete || st.Backfill != nil {
+		t.Errorf("Expected a complete cache without a backfill checkpoint, got %+v", st)
+	}
+	// The fixture was never synced, so changes since its newest event are missing.
+	if len(st.Gaps) != 1 || st.Gaps[0].Kind != eventlog.GapHead || !st.Gaps[0].From.Equal(st.NMRC) {
+		t.Errorf("Expected a head gap from the NMRC, got %+v", st.Gaps)
+	}
+
+	// MCSTEST sources are not listed among the cached boards.
+	res, err = srv.handleImportHistoryStatus("", 0)
+	if err != nil {
+		t.Fatalf("import_history_status: %v", err)
+	}
+	if statuses := res.(ResponseEnvelope).Data.(map[string]any)["sources"].([]eventlog.SourceStatus); len(statuses) != 0 {
+		t.Errorf("Expected no listed sources, got %+v", statuses)
+	}
+}
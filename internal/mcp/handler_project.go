@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 	"strings"
 	"time"
@@ -13,12 +14,12 @@ import (
 )
 
 // handleGetProjectDetails fetches metadata and performs a data probe for a project.
-func (s *Server) handleGetProjectDetails(projectKey string) (any, error) {
+func (s *Server) handleGetProjectDetails(ctx context.Context, projectKey string) (any, error) {
 	// 1. Resolve Source Context (ensures consistent JQL for the project)
-	ctx, err := s.resolveSourceContext(projectKey, 0)
+	sc, err := s.resolveSourceContext(ctx, projectKey, 0)
 	if err != nil {
 		// Fallback: If no context exists, create a default project-based JQL
-		ctx = &jira.SourceContext{
+		sc = &jira.SourceContext{
 			ProjectKey: projectKey,
 			BoardID:    0,
 			JQL:        fmt.Sprintf("project = \"%s\"", projectKey),
@@ -26,12 +27,12 @@ func (s *Server) handleGetProjectDetails(projectKey string) (any, error) {
 	}
 
 	// 2. Anchor Context (Memory Pruning + Metadata Loading)
-	if err := s.anchorContext(ctx.ProjectKey, ctx.BoardID); err != nil {
+	if err := s.anchorContext(sc.ProjectKey, sc.BoardID); err != nil {
 		return nil, err
 	}
 
 	// 3. Hydrate Protocol (Synchronous Eager Ingestion)
-	reg, err := s.events.Hydrate(projectKey, projectKey, ctx.JQL, s.activeRegistry)
+	reg, err := s.events.Hydrate(ctx, projectKey, projectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		log.Error().Err(err).Str("project", projectKey).Msg("Hydration failed")
 	}
@@ -41,7 +42,7 @@ func (s *Server) handleGetProjectDetails(projectKey string) (any, error) {
 	}
 
 	// 4. Data Probe (Tier-Neutral Discovery)
-	events := s.events.GetIssuesInRange(projectKey, time.Time{}, s.Clock())
+	events := s.events.GetIssuesInRange(projectKey, time.Time{}, s.Clock(ctx))
 	first, last, total := stats.DiscoverDatasetBoundaries(events)
 	sample := stats.ProjectNeutralSample(events, DataProbeSampleSize)
 
@@ -57,7 +58,7 @@ func (s *Server) handleGetProjectDetails(projectKey string) (any, error) {
 			"name": "Mock Test Project (Synthetic)",
 		}
 	} else {
-		project, err = s.jira.GetProject(projectKey)
+		project, err = s.jira.GetProject(ctx, projectKey)
 		if err != nil {
 			return nil, err
 		}
@@ -69,12 +70,7 @@ func (s *Server) handleGetProjectDetails(projectKey string) (any, error) {
 		"data_summary": summary,
 	}
 
-	guidance := []string{
-		"Data Ingestion Complete: History is loaded and analyzed.",
-		"Review the 'data_summary' to understand volume and issue types.",
-		"Next Step: Call 'workflow_discover_mapping' to establish the semantic process mapping.",
-		"Once mapping is confirmed, use 'guide_diagnostic_roadmap' to plan your analysis.",
-	}
+	guidance := selectGuidance("import_project_context", guidanceFacts{})
 
 	return WrapResponse(res, projectKey, 0, nil, nil, guidance), nil
 }
@@ -0,0 +1,308 @@
+package mcp
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Workspace bundle format identifiers.
+const (
+	workspaceBundleFormat   = "mcs-workspace"
+	workspaceBundleVersion  = 1
+	workspaceManifestName   = "manifest.json"
+	workspaceMaxEntryBytes  = 32 << 20 // Guard against zip bombs; config files are tiny.
+	workspaceDefaultPattern = "workspace_%s.zip"
+)
+
+// workspaceFileSuffixes lists the cache-dir files that make up a workspace:
+// analyst configuration, forecast registries, and commitments, never raw
+// Jira data (event logs are excluded).
+// Add a suffix here when a new kind of per-board configuration is persisted.
+// Audit trails stay local: they are append-only and record the changes made
+// on this machine, including the ones import_workspace makes.
+var workspaceFileSuffixes = []string{
+	"_workflow.json",
+	"_query.json",
+	"_throughput.json",
+	"_forecasts.jsonl",
+	"_commitments.json",
+}
+
+// WorkspaceManifest describes the contents of a workspace bundle.
+type WorkspaceManifest struct {
+	Format    string    `json:"format"`
+	Version   int       `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []string  `json:"files"`
+}
+
+// isWorkspaceFile reports whether a bare file name belongs in a workspace bundle.
+func isWorkspaceFile(name string) bool {
+	if name != filepath.Base(name) || strings.HasPrefix(name, ".") {
+		return false
+	}
+	for _, suffix := range workspaceFileSuffixes {
+		if strings.HasSuffix(name, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// workspaceFiles lists the workspace files currently present in the cache dir.
+func (s *Server) workspaceFiles() ([]string, error) {
+	entries, err := os.ReadDir(s.cacheDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var files []string
+	for _, e := range entries {
+		if !e.IsDir() && isWorkspaceFile(e.Name()) {
+			files = append(files, e.Name())
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
+
+func (s *Server) handleExportWorkspace(path string) (any, error) {
+	files, err := s.workspaceFiles()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list workspace files: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("nothing to export: no workspace configuration found in %s", s.cacheDir)
+	}
+
+	now := time.Now().UTC()
+	if path == "" {
+		path = filepath.Join(s.cacheDir, fmt.Sprintf(workspaceDefaultPattern, now.Format("20060102_150405")))
+	}
+
+	tmp := path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create bundle: %w", err)
+	}
+	zw := zip.NewWriter(out)
+
+	writeErr := func() error {
+		manifest := WorkspaceManifest{
+			Format:    workspaceBundleFormat,
+			Version:   workspaceBundleVersion,
+			CreatedAt: now,
+			Files:     files,
+		}
+		w, err := zw.Create(workspaceManifestName)
+		if err != nil {
+			return err
+		}
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(manifest); err != nil {
+			return err
+		}
+		for _, name := range files {
+			data, err := os.ReadFile(filepath.Join(s.cacheDir, name))
+			if err != nil {
+				return err
+			}
+			w, err := zw.Create(name)
+			if err != nil {
+				return err
+			}
+			if _, err := w.Write(data); err != nil {
+				return err
+			}
+		}
+		return zw.Close()
+	}()
+	if closeErr := out.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to write bundle: %w", writeErr)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to finalize bundle: %w", err)
+	}
+
+	log.Info().Str("path", path).Int("files", len(files)).Msg("Workspace exported")
+
+	res := map[string]any{
+		"path":  path,
+		"files": files,
+	}
+	guidance := []string{
+		"The bundle contains analyst configuration (workflow mappings, status order, commitment points, resolutions) the forecast registries used by 'compare_forecasts', and the recorded commitments. No Jira issue data is included.",
+		"Restore it on another machine with 'import_workspace'. Issue history is re-fetched from Jira on first use.",
+	}
+	return WrapResponse(res, "", 0, nil, nil, guidance), nil
+}
+
+func (s *Server) handleImportWorkspace(ctx context.Context, path string, overwrite bool) (any, error) {
+	if path == "" {
+		return nil, fmt.Errorf("path is required")
+	}
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle: %w", err)
+	}
+	defer zr.Close()
+
+	manifest, err := readWorkspaceManifest(&zr.Reader)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(s.cacheDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+
+	var imported, skipped, rejected []string
+	for _, f := range zr.File {
+		if f.Name == workspaceManifestName {
+			continue
+		}
+		if !isWorkspaceFile(f.Name) {
+			rejected = append(rejected, f.Name)
+			continue
+		}
+		dest := filepath.Join(s.cacheDir, f.Name)
+		if _, err := os.Stat(dest); err == nil && !overwrite {
+			skipped = append(skipped, f.Name)
+			continue
+		}
+		sourceID, isWorkflow := strings.CutSuffix(f.Name, "_workflow.json")
+		var before map[string]json.RawMessage
+		if isWorkflow {
+			before = persistedWorkflowSnapshot(dest)
+		}
+		if err := extractWorkspaceFile(f, dest); err != nil {
+			return nil, fmt.Errorf("failed to restore %s: %w", f.Name, err)
+		}
+		imported = append(imported, f.Name)
+		if isWorkflow {
+			s.recordAuditChanges(ctx, sourceID, before, persistedWorkflowSnapshot(dest))
+		}
+
+		// Force the active board to reload its configuration on next use.
+		if s.activeSourceID != "" && f.Name == s.activeSourceID+"_workflow.json" {
+			s.activeSourceID = ""
+		}
+	}
+
+	log.Info().Str("path", path).Int("imported", len(imported)).Int("skipped", len(skipped)).Msg("Workspace imported")
+
+	res := map[string]any{
+		"created_at": manifest.CreatedAt,
+		"imported":   imported,
+		"skipped":    skipped,
+	}
+	var warnings []string
+	if len(skipped) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d file(s) already exist locally and were kept. Re-run with overwrite=true to replace them.", len(skipped)))
+	}
+	if len(rejected) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d unexpected file(s) in the bundle were ignored: %v", len(rejected), rejected))
+	}
+	guidance := []string{
+		"Restored configuration takes effect the next time each board is imported via 'import_board_context'.",
+	}
+	return WrapResponse(res, "", 0, nil, warnings, guidance), nil
+}
+
+// persistedWorkflowSnapshot captures the audited fields of a workflow file.
+// A missing or unreadable file reads as an unset configuration.
+func persistedWorkflowSnapshot(path string) map[string]json.RawMessage {
+	var meta WorkflowMetadata
+	if data, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(data, &meta)
+	}
+	return workflowSnapshot(meta)
+}
+
+// readWorkspaceManifest validates the bundle manifest.
+func readWorkspaceManifest(zr *zip.Reader) (WorkspaceManifest, error) {
+	var manifest WorkspaceManifest
+	f, err := zr.Open(workspaceManifestName)
+	if err != nil {
+		return manifest, fmt.Errorf("not a workspace bundle: missing %s", workspaceManifestName)
+	}
+	defer f.Close()
+	if err := json.NewDecoder(io.LimitReader(f, workspaceMaxEntryBytes)).Decode(&manifest); err != nil {
+		return manifest, fmt.Errorf("invalid workspace manifest: %w", err)
+	}
+	if manifest.Format != workspaceBundleFormat {
+		return manifest, fmt.Errorf("not a workspace bundle: format %q", manifest.Format)
+	}
+	if manifest.Version > workspaceBundleVersion {
+		return manifest, fmt.Errorf("workspace bundle version %d is newer than supported version %d", manifest.Version, workspaceBundleVersion)
+	}
+	return manifest, nil
+}
+
+// validateWorkspaceEntry checks that an entry is valid JSON, or for JSONL
+// files, that every non-empty line is.
+func validateWorkspaceEntry(name string, data []byte) error {
+	if !strings.HasSuffix(name, ".jsonl") {
+		if !json.Valid(data) {
+			return fmt.Errorf("entry is not valid JSON")
+		}
+		return nil
+	}
+	for i, line := range bytes.Split(data, []byte("\n")) {
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if !json.Valid(line) {
+			return fmt.Errorf("line %d is not valid JSON", i+1)
+		}
+	}
+	return nil
+}
+
+// extractWorkspaceFile writes a single bundle entry atomically (temp file + rename).
+func extractWorkspaceFile(f *zip.File, dest string) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	data, err := io.ReadAll(io.LimitReader(rc, workspaceMaxEntryBytes+1))
+	if err != nil {
+		return err
+	}
+	if len(data) > workspaceMaxEntryBytes {
+		return fmt.Errorf("entry exceeds %d bytes", workspaceMaxEntryBytes)
+	}
+	if err := validateWorkspaceEntry(f.Name, data); err != nil {
+		return err
+	}
+
+	tmp := dest + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dest); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+package mcp
+
+import (
+	"archive/zip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcs-mcp/internal/config"
+)
+
+func TestWorkspace_ExportImportRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	files := map[string]string{
+		"PROJ_1_workflow.json": `{"source_id":"PROJ_1","commitment_point":"In Progress"}`,
+		"PROJ_2_workflow.json": `{"source_id":"PROJ_2"}`,
+		"PROJ_1.jsonl":         `{"issueKey":"PROJ-1"}`, // raw Jira data: must not be exported
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(srcDir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	src := NewServer(&config.AppConfig{CacheDir: srcDir}, &DummyClient{})
+	bundle := filepath.Join(t.TempDir(), "ws.zip")
+	if _, err := src.handleExportWorkspace(bundle); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	zr, err := zip.OpenReader(bundle)
+	if err != nil {
+		t.Fatalf("open bundle: %v", err)
+	}
+	var names []string
+	for _, f := range zr.File {
+		names = append(names, f.Name)
+	}
+	zr.Close()
+	if len(names) != 3 { // manifest + 2 workflow files
+		t.Fatalf("Expected manifest and 2 workflow files, got %v", names)
+	}
+
+	dstDir := t.TempDir()
+	existing := filepath.Join(dstDir, "PROJ_2_workflow.json")
+	if err := os.WriteFile(existing, []byte(`{"source_id":"local"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	dst := NewServer(&config.AppConfig{CacheDir: dstDir}, &DummyClient{})
+
+	res, err := dst.handleImportWorkspace(context.Background(), bundle, false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	data := res.(ResponseEnvelope).Data.(map[string]any)
+	if imported := data["imported"].([]string); len(imported) != 1 || imported[0] != "PROJ_1_workflow.json" {
+		t.Errorf("Expected only PROJ_1 imported, got %v", imported)
+	}
+	if raw, _ := os.ReadFile(existing); string(raw) != `{"source_id":"local"}` {
+		t.Errorf("Existing file was overwritten without overwrite=true: %s", raw)
+	}
+	if _, err := os.Stat(filepath.Join(dstDir, "PROJ_1.jsonl")); !os.IsNotExist(err) {
+		t.Errorf("Raw event log must not be restored")
+	}
+	trail, _ := readAudit(dst.sourceAuditPath("PROJ_1"))
+	if len(trail) != 1 || trail[0].Field != "commitment_point" || trail[0].Previous != nil || string(trail[0].Value) != `"In Progress"` {
+		t.Errorf("Expected the restored commitment point in the audit trail, got %+v", trail)
+	}
+
+	if _, err := dst.handleImportWorkspace(context.Background(), bundle, true); err != nil {
+		t.Fatalf("import overwrite: %v", err)
+	}
+	if raw, _ := os.ReadFile(existing); string(raw) != files["PROJ_2_workflow.json"] {
+		t.Errorf("Expected overwrite, got %s", raw)
+	}
+	if trail, _ := readAudit(dst.sourceAuditPath("PROJ_1")); len(trail) != 1 {
+		t.Errorf("Expected no audit entries for an unchanged workflow, got %+v", trail)
+	}
+}
+
+func TestWorkspace_ImportRejectsForeignArchive(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "foreign.zip")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	w, _ := zw.Create("../evil_workflow.json")
+	w.Write([]byte(`{}`))
+	zw.Close()
+	f.Close()
+
+	srv := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &DummyClient{})
+	if _, err := srv.handleImportWorkspace(context.Background(), path, true); err == nil {
+		t.Errorf("Expected error for archive without manifest")
+	}
+}
+
+func TestWorkspace_HistoryRoundTrip(t *testing.T) {
+	srcDir := t.TempDir()
+	registry := `{"id":"PROJ_1-F1","source_id":"PROJ_1"}` + "\n" + `{"id":"PROJ_1-F2","source_id":"PROJ_1"}` + "\n"
+	if err := os.WriteFile(filepath.Join(srcDir, "PROJ_1_forecasts.jsonl"), []byte(registry), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srcDir, "PROJ_1_commitments.json"), []byte(`[{"id":"PROJ_1-C1","source_id":"PROJ_1"}]`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	src := NewServer(&config.AppConfig{CacheDir: srcDir}, &DummyClient{})
+	bundle := filepath.Join(t.TempDir(), "ws.zip")
+	if _, err := src.handleExportWorkspace(bundle); err != nil {
+		t.Fatalf("export: %v", err)
+	}
+
+	dst := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &DummyClient{})
+	if _, err := dst.handleImportWorkspace(context.Background(), bundle, false); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	records, err := dst.loadForecasts("PROJ_1")
+	if err != nil || len(records) != 2 {
+		t.Fatalf("Expected 2 restored forecasts, got %d (%v)", len(records), err)
+	}
+	commitments, err := dst.loadCommitments("PROJ_1")
+	if err != nil || len(commitments) != 1 {
+		t.Errorf("Expected 1 restored commitment, got %d (%v)", len(commitments), err)
+	}
+}
+
+func TestValidateWorkspaceEntry(t *testing.T) {
+	cases := []struct {
+		name, data string
+		valid      bool
+	}{
+		{"PROJ_1_workflow.json", `{"source_id":"PROJ_1"}`, true},
+		{"PROJ_1_workflow.json", `{"source_id":`, false},
+		{"PROJ_1_forecasts.jsonl", "{\"id\":\"PROJ_1-F1\"}\n\n{\"id\":\"PROJ_1-F2\"}\n", true},
+		{"PROJ_1_forecasts.jsonl", "{\"id\":\"PROJ_1-F1\"}\n{\"id\":\n", false},
+	}
+	for _, tc := range cases {
+		if err := validateWorkspaceEntry(tc.name, []byte(tc.data)); (err == nil) != tc.valid {
+			t.Errorf("%s %q: expected valid=%v, got %v", tc.name, tc.data, tc.valid, err)
+		}
+	}
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 
 	"mcs-mcp/internal/jira"
@@ -8,54 +9,68 @@ import (
 	"mcs-mcp/internal/discovery"
 )
 
-func (s *Server) handleGetDiagnosticRoadmap(goal string) (any, error) {
-	roadmaps := map[string]any{
-		"forecasting": map[string]any{
-			"title":       "Analytical Workflow: Professional Forecasting",
-			"description": "Recommended sequence to produce reliable delivery dates or volume forecasts.",
-			"steps": []any{
-				map[string]any{"step": 1, "tool": "workflow_discover_mapping", "description": "Verify the semantic workflow mapping (tiers and roles) and data shape."},
-				map[string]any{"step": 2, "tool": "analyze_process_stability", "description": "Verify that the process is predictable (Stable Cycle Time and Throughput)."},
-				map[string]any{"step": 3, "tool": "analyze_wip_stability", "description": "Verify that Work-In-Progress is stable and managed (Little's Law assumption)."},
-				map[string]any{"step": 4, "tool": "analyze_cycle_time", "description": "Understand baseline SLE (Service Level Expectations) for different work items."},
-				map[string]any{"step": 5, "tool": "analyze_work_item_age", "description": "Identify items aging beyond historical norms. Includes P85 threshold, risk-band distribution, and Little's Law stability index."},
-				map[string]any{"step": 6, "tool": "forecast_monte_carlo", "description": "Perform Monte-Carlo simulation using the historical baseline."},
-				map[string]any{"step": 7, "tool": "forecast_backtest", "description": "Perform a 'Walk-Forward Analysis' to validate the reliability of the forecast model."},
-			},
+// diagnosticRoadmap is a recommended sequence of analysis steps for a goal.
+// It backs both guide_diagnostic_roadmap and the MCP prompts.
+type diagnosticRoadmap struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Steps       []roadmapStep `json:"steps"`
+}
+
+type roadmapStep struct {
+	Step        int    `json:"step"`
+	Tool        string `json:"tool"`
+	Description string `json:"description"`
+}
+
+var diagnosticRoadmaps = map[DiagnosticGoal]diagnosticRoadmap{
+	GoalForecasting: {
+		Title:       "Analytical Workflow: Professional Forecasting",
+		Description: "Recommended sequence to produce reliable delivery dates or volume forecasts.",
+		Steps: []roadmapStep{
+			{Step: 1, Tool: "workflow_discover_mapping", Description: "Verify the semantic workflow mapping (tiers and roles) and data shape."},
+			{Step: 2, Tool: "analyze_process_stability", Description: "Verify that the process is predictable (Stable Cycle Time and Throughput)."},
+			{Step: 3, Tool: "analyze_wip_stability", Description: "Verify that Work-In-Progress is stable and managed (Little's Law assumption)."},
+			{Step: 4, Tool: "analyze_cycle_time", Description: "Understand baseline SLE (Service Level Expectations) for different work items."},
+			{Step: 5, Tool: "analyze_work_item_age", Description: "Identify items aging beyond historical norms. Includes P85 threshold, risk-band distribution, and Little's Law stability index."},
+			{Step: 6, Tool: "forecast_monte_carlo", Description: "Perform Monte-Carlo simulation using the historical baseline."},
+			{Step: 7, Tool: "forecast_backtest", Description: "Perform a 'Walk-Forward Analysis' to validate the reliability of the forecast model."},
 		},
-		"bottlenecks": map[string]any{
-			"title":       "Analytical Workflow: Bottleneck & Flow Analysis",
-			"description": "Recommended sequence to identify systemic delays and batching behavior.",
-			"steps": []any{
-				map[string]any{"step": 1, "tool": "workflow_discover_mapping", "description": "Map the workflow tiers to differentiate between analysis, execution, and finished states."},
-				map[string]any{"step": 2, "tool": "analyze_status_persistence", "description": "Identify tiers and statuses where items spend the most time (High Persistence)."},
-				map[string]any{"step": 3, "tool": "analyze_throughput", "description": "Analyze throughput pulse to detect batching (uneven delivery) vs. steady flow."},
-				map[string]any{"step": 4, "tool": "analyze_flow_debt", "description": "Analyze the balance between work committed vs. work delivered (Flow Debt) to find leading indicators of cycle time inflation."},
-				map[string]any{"step": 5, "tool": "analyze_yield", "description": "Check for high abandonment rates between tiers."},
-				map[string]any{"step": 6, "tool": "analyze_item_journey", "description": "Drill down into specific 'Long Tail' outlier items to see exact path delays."},
-			},
+	},
+	GoalBottlenecks: {
+		Title:       "Analytical Workflow: Bottleneck & Flow Analysis",
+		Description: "Recommended sequence to identify systemic delays and batching behavior.",
+		Steps: []roadmapStep{
+			{Step: 1, Tool: "workflow_discover_mapping", Description: "Map the workflow tiers to differentiate between analysis, execution, and finished states."},
+			{Step: 2, Tool: "analyze_status_persistence", Description: "Identify tiers and statuses where items spend the most time (High Persistence)."},
+			{Step: 3, Tool: "analyze_throughput", Description: "Analyze throughput pulse to detect batching (uneven delivery) vs. steady flow."},
+			{Step: 4, Tool: "analyze_flow_debt", Description: "Analyze the balance between work committed vs. work delivered (Flow Debt) to find leading indicators of cycle time inflation."},
+			{Step: 5, Tool: "analyze_yield", Description: "Check for high abandonment rates between tiers."},
+			{Step: 6, Tool: "analyze_item_journey", Description: "Drill down into specific 'Long Tail' outlier items to see exact path delays."},
 		},
-		"capacity_planning": map[string]any{
-			"title":       "Analytical Workflow: Capacity & Volume Planning",
-			"description": "Recommended sequence to determine if the team can take on more scope.",
-			"steps": []any{
-				map[string]any{"step": 1, "tool": "analyze_throughput", "description": "Determine the current weekly throughput baseline."},
-				map[string]any{"step": 2, "tool": "analyze_process_stability", "description": "Compare current WIP against historical capacity (Stability Index)."},
-				map[string]any{"step": 3, "tool": "analyze_flow_debt", "description": "Verify System Balance (Arrival vs. Departure) to ensure capacity is not being exceeded."},
-				map[string]any{"step": 4, "tool": "forecast_monte_carlo", "description": "Use 'scope' mode to see how much we can reasonably finish in the next period."},
-			},
+	},
+	GoalCapacityPlanning: {
+		Title:       "Analytical Workflow: Capacity & Volume Planning",
+		Description: "Recommended sequence to determine if the team can take on more scope.",
+		Steps: []roadmapStep{
+			{Step: 1, Tool: "analyze_throughput", Description: "Determine the current weekly throughput baseline."},
+			{Step: 2, Tool: "analyze_process_stability", Description: "Compare current WIP against historical capacity (Stability Index)."},
+			{Step: 3, Tool: "analyze_flow_debt", Description: "Verify System Balance (Arrival vs. Departure) to ensure capacity is not being exceeded."},
+			{Step: 4, Tool: "forecast_monte_carlo", Description: "Use 'scope' mode to see how much we can reasonably finish in the next period."},
 		},
-		"system_health": map[string]any{
-			"title":       "Analytical Workflow: Strategic System Health",
-			"description": "Recommended sequence for long-term process oversight and strategic shift detection.",
-			"steps": []any{
-				map[string]any{"step": 1, "tool": "analyze_process_evolution", "description": "Perform a longitudinal audit (Three-Way Control Charts)."},
-				map[string]any{"step": 2, "tool": "analyze_yield", "description": "Evaluate long-term conversion efficiency across the entire pipe."},
-			},
+	},
+	GoalSystemHealth: {
+		Title:       "Analytical Workflow: Strategic System Health",
+		Description: "Recommended sequence for long-term process oversight and strategic shift detection.",
+		Steps: []roadmapStep{
+			{Step: 1, Tool: "analyze_process_evolution", Description: "Perform a longitudinal audit (Three-Way Control Charts)."},
+			{Step: 2, Tool: "analyze_yield", Description: "Evaluate long-term conversion efficiency across the entire pipe."},
 		},
-	}
+	},
+}
 
-	res, ok := roadmaps[goal]
+func (s *Server) handleGetDiagnosticRoadmap(goal string) (any, error) {
+	res, ok := diagnosticRoadmaps[DiagnosticGoal(goal)]
 	if !ok {
 		return nil, fmt.Errorf("unknown goal: %s. Available goals: forecasting, bottlenecks, capacity_planning, system_health", goal)
 	}
@@ -139,13 +154,46 @@ func (s *Server) getEarliestCommitment(projectKey string, boardID int, issues []
 	return "", false
 }
 
-func (s *Server) getCycleTimes(projectKey string, boardID int, issues []jira.Issue, startStatus, endStatus string, issueTypes []string) ([]float64, []jira.Issue) {
+// cycleTimeRange returns a lookup of the status range a cycle time is summed
+// over, per issue type. Per-type commitment point overrides replace startStatus
+// only when it is the board commitment point; an explicitly different start
+// status applies to every type.
+func (s *Server) cycleTimeRange(projectKey string, boardID int, startStatus, endStatus string, issues []jira.Issue) func(issueType string) []string {
+	base := s.getInferredRange(projectKey, boardID, startStatus, endStatus, issues)
+	if len(s.activeTypeCommitments) == 0 {
+		return func(string) []string { return base }
+	}
+	boardCP := s.activeCommitmentPoint
+	if boardCP == "" {
+		boardCP, _ = s.getEarliestCommitment(projectKey, boardID, issues)
+	}
+	if startStatus != boardCP {
+		return func(string) []string { return base }
+	}
+
+	byStart := make(map[string][]string)
+	return func(issueType string) []string {
+		cp, ok := s.activeTypeCommitments[issueType]
+		if !ok {
+			return base
+		}
+		r, ok := byStart[cp]
+		if !ok {
+			r = s.getInferredRange(projectKey, boardID, cp, endStatus, issues)
+			byStart[cp] = r
+		}
+		return r
+	}
+}
+
+func (s *Server) getCycleTimes(ctx context.Context, projectKey string, boardID int, issues []jira.Issue, startStatus, endStatus string, issueTypes []string) ([]float64, []jira.Issue) {
 	typeMap := make(map[string]bool)
 	for _, t := range issueTypes {
 		typeMap[t] = true
 	}
+	outcomes := s.outcomes(ctx)
 
-	rangeStatuses := s.getInferredRange(projectKey, boardID, startStatus, endStatus, issues)
+	rangeFor := s.cycleTimeRange(projectKey, boardID, startStatus, endStatus, issues)
 
 	var cycleTimes []float64
 	var matchedIssues []jira.Issue
@@ -161,12 +209,12 @@ func (s *Server) getCycleTimes(projectKey string, boardID int, issues []jira.Iss
 			continue
 		}
 
-		// Only count "delivered" work
-		if !stats.IsDelivered(issue) {
+		// Only count work that is done under the outcome scope
+		if !outcomes.Includes(issue) {
 			continue
 		}
 
-		duration := stats.SumRangeDuration(issue, rangeStatuses)
+		duration := stats.SumRangeDuration(issue, rangeFor(issue.IssueType))
 		if duration > 0 {
 			cycleTimes = append(cycleTimes, duration)
 			matchedIssues = append(matchedIssues, issue)
@@ -176,13 +224,14 @@ func (s *Server) getCycleTimes(projectKey string, boardID int, issues []jira.Iss
 	return cycleTimes, matchedIssues
 }
 
-func (s *Server) getCycleTimesByType(projectKey string, boardID int, issues []jira.Issue, startStatus, endStatus string, issueTypes []string) map[string][]float64 {
+func (s *Server) getCycleTimesByType(ctx context.Context, projectKey string, boardID int, issues []jira.Issue, startStatus, endStatus string, issueTypes []string) map[string][]float64 {
 	typeMap := make(map[string]bool)
 	for _, t := range issueTypes {
 		typeMap[t] = true
 	}
+	outcomes := s.outcomes(ctx)
 
-	rangeStatuses := s.getInferredRange(projectKey, boardID, startStatus, endStatus, issues)
+	rangeFor := s.cycleTimeRange(projectKey, boardID, startStatus, endStatus, issues)
 
 	cycleTimes := make(map[string][]float64)
 	for _, issue := range issues {
@@ -196,12 +245,12 @@ func (s *Server) getCycleTimesByType(projectKey string, boardID int, issues []ji
 			continue
 		}
 
-		// Only count "delivered" work
-		if !stats.IsDelivered(issue) {
+		// Only count work that is done under the outcome scope
+		if !outcomes.Includes(issue) {
 			continue
 		}
 
-		duration := stats.SumRangeDuration(issue, rangeStatuses)
+		duration := stats.SumRangeDuration(issue, rangeFor(issue.IssueType))
 		if duration > 0 {
 			t := issue.IssueType
 			if t == "" {
@@ -213,15 +262,19 @@ func (s *Server) getCycleTimesByType(projectKey string, boardID int, issues []ji
 
 	return cycleTimes
 }
-func (s *Server) getQualityWarnings(issues []jira.Issue) []string {
+func (s *Server) getQualityWarnings(ctx context.Context, issues []jira.Issue) []string {
 	var warnings []string
 	syntheticCount := 0
+	truncatedCount := 0
 	var active []jira.Issue
 
 	for _, issue := range issues {
 		if issue.HasSyntheticBirth {
 			syntheticCount++
 		}
+		if issue.HistoryTruncated {
+			truncatedCount++
+		}
 		if issue.ResolutionDate == nil {
 			active = append(active, issue)
 		}
@@ -231,6 +284,10 @@ func (s *Server) getQualityWarnings(issues []jira.Issue) []string {
 		warnings = append(warnings, fmt.Sprintf("DATA INTEGRITY WARNING: %d item(s) are missing their creation events. Cycle Times and Stability metrics for these items are based on the earliest recorded event, which likely understates their true age.", syntheticCount))
 	}
 
+	if truncatedCount > 0 {
+		warnings = append(warnings, fmt.Sprintf("DATA INTEGRITY WARNING: %d item(s) have a truncated Jira history (the full changelog could not be fetched). Their earliest transitions are missing, so their Cycle Times and status residencies are likely understated.", truncatedCount))
+	}
+
 	// System Pressure Check (Stability Guardrail)
 	if len(active) > 0 {
 		pressure := stats.CalculateSystemPressure(active)
@@ -239,5 +296,43 @@ func (s *Server) getQualityWarnings(issues []jira.Issue) []string {
 		}
 	}
 
+	// Workflow Change Check (Mapping Freshness Guardrail)
+	if w := s.workflowChangeWarning(issues); w != "" {
+		warnings = append(warnings, w)
+	}
+
+	// Distribution Drift Check (Baseline Freshness Guardrail)
+	if drift := s.assessDrift(ctx, issues); drift != nil {
+		warnings = append(warnings, drift.Warnings...)
+	}
+
 	return warnings
 }
+
+// assessDrift compares the cycle times of items delivered in the last
+// stats.DriftRecentWeeks of the session window against those delivered earlier.
+// Cycle time is measured from the active commitment point. Returns nil when
+// there is not enough data on either side of the split.
+func (s *Server) assessDrift(ctx context.Context, issues []jira.Issue) *stats.DriftAssessment {
+	order := s.activeStatusOrder
+	if len(order) == 0 {
+		order = discovery.DiscoverStatusOrder(issues)
+	}
+	rangeStatuses := s.sliceRange(order, s.activeCommitmentPoint, "")
+
+	var delivered []jira.Issue
+	var cycleTimes []float64
+	for _, issue := range issues {
+		if issue.OutcomeDate == nil || !stats.IsDelivered(issue) {
+			continue
+		}
+		if d := stats.SumRangeDuration(issue, rangeStatuses); d > 0 {
+			delivered = append(delivered, issue)
+			cycleTimes = append(cycleTimes, d)
+		}
+	}
+
+	_, end, _ := s.Window(ctx)
+	recentStart := stats.SnapToStart(end, "day").AddDate(0, 0, -stats.DriftRecentWeeks*7)
+	return stats.AssessCycleTimeDrift(delivered, cycleTimes, recentStart)
+}
@@ -0,0 +1,190 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// handleSetAlertRules records (or removes) the alert rules of the active board
+// and persists them with the workflow metadata. The entries are validated
+// together: one invalid entry leaves the recorded rules unchanged.
+func (s *Server) handleSetAlertRules(ctx context.Context, projectKey string, boardID int, entries []AlertRuleEntry, remove []string, clear bool) (any, error) {
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 && len(remove) == 0 && !clear {
+		return nil, fmt.Errorf("no rules given: pass at least one entry in 'rules', names in 'remove', or clear=true")
+	}
+
+	before := s.configSnapshot()
+	rules := make(map[string]stats.AlertRule)
+	if !clear {
+		maps.Copy(rules, s.activeAlertRules)
+	}
+	for _, name := range remove {
+		if _, ok := rules[name]; !ok {
+			return nil, fmt.Errorf("no alert rule named %q recorded", name)
+		}
+		delete(rules, name)
+	}
+	for _, e := range entries {
+		rule := stats.AlertRule{
+			Name:        strings.TrimSpace(e.Name),
+			Metric:      strings.ToLower(strings.TrimSpace(e.Metric)),
+			Operator:    strings.TrimSpace(e.Operator),
+			Threshold:   e.Threshold,
+			Consecutive: e.Consecutive,
+		}
+		if err := rule.Validate(); err != nil {
+			return nil, err
+		}
+		if rule.Name == "" {
+			rule.Name = rule.Condition()
+		}
+		rules[rule.Name] = rule
+	}
+	s.activeAlertRules = rules
+	if len(rules) == 0 {
+		s.activeAlertRules = nil
+	}
+
+	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+		log.Error().Err(err).Msg("Failed to save workflow metadata")
+		return nil, fmt.Errorf("alert rules updated in memory but failed to save to disk: %w", err)
+	}
+	s.recordAudit(ctx, projectKey, boardID, before)
+
+	res := map[string]any{
+		"alert_rules": s.sortedAlertRules(),
+	}
+	guidance := s.guidanceFor("set_alert_rules", guidanceFacts{})
+	return WrapResponse(res, projectKey, boardID, nil, nil, guidance), nil
+}
+
+// sortedAlertRules returns the recorded rules by name.
+func (s *Server) sortedAlertRules() []stats.AlertRule {
+	rules := slices.Collect(maps.Values(s.activeAlertRules))
+	slices.SortFunc(rules, func(a, b stats.AlertRule) int { return strings.Compare(a.Name, b.Name) })
+	return rules
+}
+
+// handleEvaluateAlerts computes the metrics the recorded rules watch and
+// reports every rule with its current value, fired ones first. Point-in-time
+// metrics (WIP, aging) are taken at the window's End as in
+// analyze_work_item_age; cycle times and weekly series cover the session
+// window, split into whole weeks counted back from its End.
+func (s *Server) handleEvaluateAlerts(ctx context.Context, projectKey string, boardID int) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.activeAlertRules) == 0 {
+		return nil, fmt.Errorf("no alert rule recorded for %s; record one first with 'set_alert_rules' (e.g. metric 'stale_wip_pct', operator '>', threshold 30)", hctx.SourceID)
+	}
+	rules := s.sortedAlertRules()
+	needs := func(metrics ...string) bool {
+		return slices.ContainsFunc(rules, func(r stats.AlertRule) bool { return slices.Contains(metrics, r.Metric) })
+	}
+
+	values := make(map[string]float64)
+	weekly := make(map[string][]float64)
+
+	window, err := s.AlignedWindow(ctx, "week", AlignRolling)
+	if err != nil {
+		return nil, err
+	}
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+
+	if needs(stats.AlertStaleWIPPct, stats.AlertAgingOutliers, stats.AlertWIPCount) {
+		// As in analyze_work_item_age: the whole history up to the window's End.
+		full := s.openSession(ctx, hctx, stats.NewAnalysisWindow(time.Time{}, window.End, "day", s.activeCutoff()))
+		fullCtx := s.prepareAnalysisContext(projectKey, boardID, full.GetAllIssues())
+		cycleTimes, _ := s.getCycleTimes(ctx, projectKey, boardID, full.GetDelivered(), fullCtx.CommitmentPoint, "", nil)
+		aging := stats.CalculateInventoryAgeByType(full.GetWIP(), fullCtx.Commitments(), fullCtx.StatusWeights, fullCtx.WorkflowMappings, cycleTimes, string(AgeTypeWIP), s.commitmentBackflowReset, window.End)
+		aging = filterAgingByTier(aging, "WIP")
+
+		outliers := 0
+		for _, a := range aging {
+			if a.IsAgingOutlier {
+				outliers++
+			}
+		}
+		values[stats.AlertWIPCount] = float64(len(aging))
+		values[stats.AlertAgingOutliers] = float64(outliers)
+		if len(aging) > 0 {
+			values[stats.AlertStaleWIPPct] = float64(outliers) / float64(len(aging)) * 100
+		}
+	}
+
+	if needs(stats.AlertCycleTimeP85, stats.AlertFatTailRatio) {
+		cycleTimes, _ := s.getCycleTimes(ctx, projectKey, boardID, session.GetDelivered(), analysisCtx.CommitmentPoint, "", nil)
+		if len(cycleTimes) == 0 {
+			return nil, fmt.Errorf("no delivered items in the analysis window of %s; cycle-time rules cannot be evaluated", hctx.SourceID)
+		}
+		sorted := slices.Sorted(slices.Values(cycleTimes))
+		values[stats.AlertCycleTimeP85] = stats.CalculatePercentile(sorted, 0.85)
+		if p50 := stats.CalculatePercentile(sorted, 0.50); p50 > 0 {
+			values[stats.AlertFatTailRatio] = stats.CalculatePercentile(sorted, 0.98) / p50
+		}
+	}
+
+	// Weekly series skip a partial first week.
+	buckets := window.Subdivide()
+	complete := func(i int) bool { return !window.IsPartial(buckets[i]) }
+
+	if needs(stats.AlertFlowDebt, stats.AlertWeeklyFlowDebt) {
+		debt := stats.CalculateFlowDebt(all, window, analysisCtx.CommitmentPoint, analysisCtx.StatusWeights, s.activeResolutions, s.activeMapping)
+		values[stats.AlertFlowDebt] = float64(debt.TotalDebt)
+		for i, b := range debt.Buckets {
+			if complete(i) {
+				weekly[stats.AlertWeeklyFlowDebt] = append(weekly[stats.AlertWeeklyFlowDebt], float64(b.Debt))
+			}
+		}
+	}
+
+	if needs(stats.AlertWeeklyThroughput) {
+		throughput := stats.GetStratifiedThroughput(session.GetDelivered(), window)
+		for i, n := range throughput.Pooled {
+			if complete(i) {
+				weekly[stats.AlertWeeklyThroughput] = append(weekly[stats.AlertWeeklyThroughput], float64(n))
+			}
+		}
+	}
+
+	evaluations := make([]stats.AlertEvaluation, 0, len(rules))
+	fired := 0
+	for _, r := range rules {
+		ev := stats.EvaluateAlertRule(r, values[r.Metric], weekly[r.Metric])
+		if ev.Fired {
+			fired++
+		}
+		evaluations = append(evaluations, ev)
+	}
+	slices.SortStableFunc(evaluations, func(a, b stats.AlertEvaluation) int {
+		switch {
+		case a.Fired && !b.Fired:
+			return -1
+		case !a.Fired && b.Fired:
+			return 1
+		}
+		return 0
+	})
+
+	res := map[string]any{
+		"fired":       fired,
+		"evaluations": evaluations,
+	}
+	insights := []string{fmt.Sprintf("%d of %d alert rule(s) fired as of %s.", fired, len(rules), window.End.Format(stats.DateFormat))}
+	insights = append(insights, s.guidanceFor("evaluate_alerts", guidanceFacts{})...)
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), insights), nil
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/stats"
+)
+
+func TestAlertRules(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	if _, err := srv.handleEvaluateAlerts(ctx, testProject, testBoard); err == nil {
+		t.Fatalf("Expected an error before any rule is recorded")
+	}
+	if _, err := srv.handleSetAlertRules(ctx, testProject, testBoard, []AlertRuleEntry{
+		{Metric: "stale_wip_pct", Operator: ">", Threshold: 30},
+		{Metric: "wip_count", Operator: ">", Threshold: 1, Consecutive: 2},
+	}, nil, false); err == nil {
+		t.Fatalf("Expected the batch with an invalid rule to be rejected")
+	}
+	if len(srv.activeAlertRules) != 0 {
+		t.Fatalf("Expected a rejected batch to record nothing, got %+v", srv.activeAlertRules)
+	}
+
+	if _, err := srv.handleSetAlertRules(ctx, testProject, testBoard, []AlertRuleEntry{
+		{Metric: "stale_wip_pct", Operator: ">", Threshold: 30},
+		{Name: "never", Metric: "wip_count", Operator: "<", Threshold: 0},
+		{Name: "debt streak", Metric: "weekly_flow_debt", Operator: ">=", Threshold: -1000, Consecutive: 3},
+		{Name: "tail", Metric: "fat_tail_ratio", Operator: ">", Threshold: 0},
+	}, nil, false); err != nil {
+		t.Fatalf("set_alert_rules: %v", err)
+	}
+	if _, ok := srv.activeAlertRules["stale_wip_pct > 30"]; !ok {
+		t.Errorf("Expected an unnamed rule keyed by its condition, got %+v", srv.activeAlertRules)
+	}
+
+	res, err := srv.handleEvaluateAlerts(ctx, testProject, testBoard)
+	if err != nil {
+		t.Fatalf("evaluate_alerts: %v", err)
+	}
+	data := res.(ResponseEnvelope).Data.(map[string]any)
+	evals := data["evaluations"].([]stats.AlertEvaluation)
+	if len(evals) != 4 {
+		t.Fatalf("Expected four evaluations, got %+v", evals)
+	}
+	byName := make(map[string]stats.AlertEvaluation)
+	for _, ev := range evals {
+		byName[ev.Rule.Name] = ev
+	}
+	if ev := byName["never"]; ev.Fired || ev.Value <= 0 {
+		t.Errorf("Expected a WIP count and no fire, got %+v", ev)
+	}
+	if ev := byName["debt streak"]; !ev.Fired || len(ev.Weeks) != 3 {
+		t.Errorf("Expected the weekly rule to fire on three weeks, got %+v", ev)
+	}
+	if ev := byName["tail"]; !ev.Fired || ev.Value < 1 {
+		t.Errorf("Expected a fat-tail ratio of at least 1, got %+v", ev)
+	}
+	if evals[len(evals)-1].Fired || !evals[0].Fired {
+		t.Errorf("Expected fired rules first, got %+v", evals)
+	}
+
+	// Rules survive a context switch via the workflow file.
+	srv.activeAlertRules = nil
+	if _, err := srv.loadWorkflow(testProject, testBoard); err != nil {
+		t.Fatalf("loadWorkflow: %v", err)
+	}
+	if len(srv.activeAlertRules) != 4 {
+		t.Errorf("Expected all rules to be persisted, got %+v", srv.activeAlertRules)
+	}
+
+	if _, err := srv.handleSetAlertRules(ctx, testProject, testBoard, nil, []string{"no such rule"}, false); err == nil {
+		t.Errorf("Expected removing an unknown rule to fail")
+	}
+	if _, err := srv.handleSetAlertRules(ctx, testProject, testBoard, nil, []string{"never", "tail"}, false); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if len(srv.activeAlertRules) != 2 {
+		t.Errorf("Expected two rules left, got %+v", srv.activeAlertRules)
+	}
+	if _, err := srv.handleSetAlertRules(ctx, testProject, testBoard, nil, nil, true); err != nil || srv.activeAlertRules != nil {
+		t.Errorf("Expected clear to drop every rule, got %+v (%v)", srv.activeAlertRules, err)
+	}
+}
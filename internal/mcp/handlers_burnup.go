@@ -0,0 +1,110 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// handleForecastBurnUp forecasts the cumulative number of items delivered at
+// the end of each week up to the horizon (a burn-up cone), from the same
+// daily throughput sample as a scope-mode forecast_monte_carlo run.
+func (s *Server) handleForecastBurnUp(ctx context.Context, projectKey string, boardID int, horizonWeeks int, issueTypes []string, sampleDays int, holidays, freezePeriods []string) (any, error) {
+	if horizonWeeks == 0 {
+		horizonWeeks = DefaultBurnUpWeeks
+	}
+	if horizonWeeks < 0 || horizonWeeks > MaxBurnUpWeeks {
+		return nil, fmt.Errorf("horizon_weeks must be between 1 and %d, got %d", MaxBurnUpWeeks, horizonWeeks)
+	}
+
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	issueTypes = s.forecastIssueTypes(issueTypes)
+	calendar, err := s.resolveCalendar(holidays, freezePeriods)
+	if err != nil {
+		return nil, err
+	}
+
+	histStart, histEnd, err := s.forecastSampleWindow(ctx, sampleDays, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+	window := stats.NewAnalysisWindow(histStart, histEnd, "day", s.activeCutoff())
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+
+	finished := session.GetFinished()
+	if len(issueTypes) > 0 {
+		typeSet := make(map[string]bool, len(issueTypes))
+		for _, t := range issueTypes {
+			typeSet[s.activeTypeAliases.Canonical(t)] = true
+		}
+		filtered := make([]jira.Issue, 0, len(finished))
+		for _, issue := range finished {
+			if typeSet[s.activeTypeAliases.Canonical(issue.IssueType)] {
+				filtered = append(filtered, issue)
+			}
+		}
+		finished = filtered
+	}
+
+	h := simulation.NewHistogram(finished, window.Start, window.End, nil, s.activeMapping, s.activeResolutions, s.outcomes(ctx))
+	if delivered, _ := h.Meta["issues_analyzed"].(int); delivered == 0 {
+		return nil, fmt.Errorf("no delivered items between %s and %s to sample throughput from; widen the window via history_window_days", window.Start.Format(stats.DateFormat), window.End.Format(stats.DateFormat))
+	}
+	h.RestrictToWorkingDays(calendar, window.Start)
+
+	now := s.Clock(ctx)
+	engine := simulation.NewEngine(h)
+	engine.SetContext(ctx)
+	if s.simulationSeed != 0 {
+		engine.SetSeed(s.simulationSeed)
+	}
+	if calendar != nil {
+		engine.SetCalendar(calendar, now)
+	}
+	checkpoints := make([]int, horizonWeeks)
+	for i := range checkpoints {
+		checkpoints[i] = 7 * (i + 1)
+	}
+	engine.SetBurnUpCheckpoints(checkpoints)
+
+	horizonDays := 7 * horizonWeeks
+	resObj := engine.RunScopeSimulation(horizonDays, simulation.DefaultTrials)
+	if err := engine.Err(); err != nil {
+		return nil, fmt.Errorf("simulation cancelled: %w", err)
+	}
+	resObj.Round()
+	for i := range resObj.BurnUp {
+		resObj.BurnUp[i].Date = now.AddDate(0, 0, resObj.BurnUp[i].Day).Format(stats.DateFormat)
+	}
+
+	s.annotateForecast(ctx, &resObj, "scope", horizonDays, calendar)
+	resObj.Context["horizon_weeks"] = horizonWeeks
+	resObj.Context["sample"] = map[string]any{
+		"delivered_items": h.Meta["issues_analyzed"],
+		"start_date":      window.Start.Format(stats.DateFormat),
+		"end_date":        window.End.Format(stats.DateFormat),
+	}
+	if len(issueTypes) > 0 {
+		resObj.Context["issue_types"] = issueTypes
+	}
+
+	insights := resObj.Insights
+	if n := len(resObj.BurnUp); n > 0 {
+		last := resObj.BurnUp[n-1]
+		insights = append(insights, fmt.Sprintf("By %s (week %d): at least %.0f items with 50%% probability, %.0f with 85%% and %.0f with 95%%. The gap between P50 and P95 is the uncertainty the plan has to absorb.",
+			last.Date, n, last.P50, last.P85, last.P95))
+	}
+	insights = append(insights, s.guidanceFor("forecast_burnup", guidanceFacts{FatTailRatio: resObj.FatTailRatio})...)
+	warnings := append(resObj.Warnings, s.getQualityWarnings(ctx, all)...)
+	resObj.Warnings = nil
+	resObj.Insights = nil
+
+	return WrapResponse(resObj, projectKey, boardID, nil, warnings, insights), nil
+}
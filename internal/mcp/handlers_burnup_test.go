@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/simulation"
+)
+
+func TestForecastBurnUp(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.simulationSeed = 42
+
+	res, err := srv.handleForecastBurnUp(ctx, testProject, testBoard, 4, nil, 365, nil, nil)
+	if err != nil {
+		t.Fatalf("forecast_burnup: %v", err)
+	}
+	result := res.(ResponseEnvelope).Data.(simulation.Result)
+	if len(result.BurnUp) != 4 {
+		t.Fatalf("Expected one cone point per week, got %+v", result.BurnUp)
+	}
+	for i, p := range result.BurnUp {
+		if p.Day != 7*(i+1) || p.Date == "" {
+			t.Errorf("Expected week %d on day %d with a date, got %+v", i+1, 7*(i+1), p)
+		}
+		if p.P95 > p.P85 || p.P85 > p.P50 {
+			t.Errorf("Expected P95 <= P85 <= P50, got %+v", p)
+		}
+		if i > 0 && p.P50 < result.BurnUp[i-1].P50 {
+			t.Errorf("Expected a non-decreasing cone, got %+v", result.BurnUp)
+		}
+	}
+	if last := result.BurnUp[3]; last.P50 != result.Percentiles.CoinToss {
+		t.Errorf("Expected the last week to match the horizon median %v, got %+v", result.Percentiles.CoinToss, last)
+	}
+	if result.Context["target_days"] != 28 {
+		t.Errorf("Expected a 28-day horizon, got %v", result.Context["target_days"])
+	}
+
+	if _, err := srv.handleForecastBurnUp(ctx, testProject, testBoard, 53, nil, 0, nil, nil); err == nil {
+		t.Errorf("Expected an error beyond %d weeks", MaxBurnUpWeeks)
+	}
+	if _, err := srv.handleForecastBurnUp(ctx, testProject, testBoard, 4, []string{"NoSuchType"}, 365, nil, nil); err == nil {
+		t.Errorf("Expected an error without delivered items of the requested type")
+	}
+}
@@ -0,0 +1,421 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// commitmentTrendThreshold is the probability change (in points of 0–1)
+// below which a commitment's trend counts as stable.
+const commitmentTrendThreshold = 0.05
+
+// forecastPercentiles are the percentiles a forecast records.
+var forecastPercentiles = []int{10, 30, 50, 70, 85, 90, 95, 98}
+
+// commitmentProbe is the board data a commitment check needs: every cached
+// issue, to find the committed items and the deliveries since the commitment,
+// and the throughput sample of the forecast.
+type commitmentProbe struct {
+	issues   []jira.Issue
+	byKey    map[string]jira.Issue
+	session  *stats.AnalysisSession
+	window   stats.AnalysisWindow
+	calendar *simulation.Calendar
+}
+
+func (s *Server) newCommitmentProbe(ctx context.Context, hctx *handlerContext, sampleDays int) (*commitmentProbe, error) {
+	calendar, err := s.resolveCalendar(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	now := s.Clock(ctx)
+	p := &commitmentProbe{byKey: make(map[string]jira.Issue), calendar: calendar}
+	p.issues = s.openSession(ctx, hctx, stats.NewAnalysisWindow(time.Time{}, now, "day", s.activeCutoff())).GetAllIssues()
+	for _, issue := range p.issues {
+		p.byKey[issue.Key] = issue
+	}
+
+	histStart, histEnd, err := s.forecastSampleWindow(ctx, sampleDays, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+	p.window = stats.NewAnalysisWindow(histStart, histEnd, "day", s.activeCutoff())
+	p.session = s.openSession(ctx, hctx, p.window)
+	return p, nil
+}
+
+// remaining counts the committed items not delivered by the end of the
+// target date. Committed keys that were abandoned leave the scope; keys
+// missing from the cache remain. Without keys, every delivery of the
+// committed types since the commitment counts towards the scope.
+func (p *commitmentProbe) remaining(c Commitment) int {
+	target, _ := time.Parse(stats.DateFormat, c.TargetDate)
+	deadline := target.AddDate(0, 0, 1)
+	deliveredBy := func(issue jira.Issue, from time.Time) bool {
+		return stats.IsDelivered(issue) && issue.OutcomeDate != nil &&
+			!issue.OutcomeDate.Before(from) && issue.OutcomeDate.Before(deadline)
+	}
+
+	if len(c.IssueKeys) > 0 {
+		n := 0
+		for _, key := range c.IssueKeys {
+			issue, ok := p.byKey[key]
+			switch {
+			case !ok:
+				n++
+			case deliveredBy(issue, time.Time{}):
+			case stats.HasExited(issue) && !stats.IsDelivered(issue):
+			default:
+				n++
+			}
+		}
+		return n
+	}
+
+	madeOn, _ := time.Parse(stats.DateFormat, c.MadeOn)
+	delivered := 0
+	for _, issue := range p.issues {
+		if (len(c.IssueTypes) == 0 || slices.Contains(c.IssueTypes, issue.IssueType)) && deliveredBy(issue, madeOn) {
+			delivered++
+		}
+	}
+	return max(0, c.Items-delivered)
+}
+
+// checkCommitment measures how likely the commitment is hit as of today: the
+// share of trials delivering the remaining items by the target date, as in
+// forecast_timebox.
+func (s *Server) checkCommitment(ctx context.Context, p *commitmentProbe, c Commitment) (CommitmentCheck, error) {
+	now := s.Clock(ctx)
+	target, _ := time.Parse(stats.DateFormat, c.TargetDate)
+	check := CommitmentCheck{
+		AsOf:      now.Format(stats.DateFormat),
+		Remaining: p.remaining(c),
+		DaysLeft:  max(0, stats.CalendarDaysBetween(now, target)+1),
+	}
+	switch {
+	case check.Remaining == 0:
+		check.Probability = 1
+	case check.DaysLeft > 0:
+		res, _, err := s.simulateTimebox(ctx, p.session, p.window, p.calendar, check.Remaining, check.DaysLeft)
+		if err != nil {
+			return check, err
+		}
+		check.Probability = res.Timebox.Probability
+	}
+	return check, nil
+}
+
+// commitmentSnapshot returns the snapshot of a recorded duration forecast of
+// the source, and the date it projects at the percentile.
+func (s *Server) commitmentSnapshot(sourceID, forecastID string, percentile int) (*CommitmentForecast, ForecastRecord, string, error) {
+	rec, err := s.findForecast(forecastID)
+	if err != nil {
+		return nil, rec, "", err
+	}
+	switch {
+	case rec.SourceID != sourceID:
+		return nil, rec, "", fmt.Errorf("forecast %s belongs to %s, not %s", forecastID, rec.SourceID, sourceID)
+	case rec.Inputs.Mode != "duration" || rec.Inputs.TimeUnit != "day":
+		return nil, rec, "", fmt.Errorf("forecast %s is a %s forecast in %ss; commitments are made from duration forecasts in days", forecastID, rec.Inputs.Mode, rec.Inputs.TimeUnit)
+	case rec.Inputs.Units == string(UnitsPoints):
+		return nil, rec, "", fmt.Errorf("forecast %s forecasts points; commitments track items", forecastID)
+	}
+	asOf, err := time.Parse(stats.DateFormat, rec.AsOf)
+	if err != nil {
+		return nil, rec, "", fmt.Errorf("forecast %s has an invalid evaluation date %q", forecastID, rec.AsOf)
+	}
+	days := percentileFromResult(rec.Percentiles, percentile)
+	snapshot := &CommitmentForecast{
+		ForecastID:      rec.ID,
+		AsOf:            rec.AsOf,
+		Percentiles:     rec.Percentiles,
+		CompletionDates: simulation.CompletionDates(rec.Percentiles, asOf),
+		Composition:     rec.Composition,
+	}
+	return snapshot, rec, asOf.AddDate(0, 0, int(math.Ceil(days))).Format(stats.DateFormat), nil
+}
+
+// forecastScopeTotal returns the number of items a recorded forecast simulated.
+func forecastScopeTotal(rec ForecastRecord) int {
+	if rec.Composition != nil && rec.Composition.Total > 0 {
+		return rec.Composition.Total
+	}
+	total := 0
+	for _, n := range rec.Inputs.Targets {
+		total += n
+	}
+	return total
+}
+
+// handleRecordCommitment records a delivery commitment of the board, made
+// from a recorded forecast or given directly, and checks it once as its
+// baseline. Withdrawn commitments are closed first.
+func (s *Server) handleRecordCommitment(ctx context.Context, projectKey string, boardID int, name, forecastID string, percentile int, targetDate string, items int, issueKeys, issueTypes, withdraw []string, sampleDays int) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if s.cacheDir == "" {
+		return nil, fmt.Errorf("commitments need a cache directory to be tracked")
+	}
+	sourceID := hctx.SourceID
+	commitments, err := s.loadCommitments(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	today := s.Clock(ctx).Format(stats.DateFormat)
+
+	var withdrawn []string
+	for _, id := range withdraw {
+		i := slices.IndexFunc(commitments, func(c Commitment) bool { return c.ID == id })
+		switch {
+		case i < 0:
+			return nil, fmt.Errorf("no commitment %s recorded for %s", id, sourceID)
+		case commitments[i].Status != CommitmentOpen:
+			return nil, fmt.Errorf("commitment %s is already closed (%s)", id, commitments[i].Status)
+		}
+		commitments[i].Status = CommitmentWithdrawn
+		commitments[i].ClosedOn = today
+		withdrawn = append(withdrawn, id)
+	}
+
+	var keys []string
+	for _, k := range issueKeys {
+		if k = strings.ToUpper(strings.TrimSpace(k)); k != "" && !slices.Contains(keys, k) {
+			keys = append(keys, k)
+		}
+	}
+	recording := forecastID != "" || targetDate != "" || items != 0 || len(keys) > 0
+	if !recording && len(withdrawn) == 0 {
+		return nil, fmt.Errorf("nothing to record: pass a forecast_id, or items or issue_keys with a target_date (or commitment IDs in 'withdraw')")
+	}
+
+	res := map[string]any{}
+	var insights []string
+	if recording {
+		c := Commitment{
+			ID:         fmt.Sprintf("%s%s%d", sourceID, commitmentIDSeparator, len(commitments)+1),
+			SourceID:   sourceID,
+			Name:       strings.TrimSpace(name),
+			MadeOn:     today,
+			RecordedAt: time.Now().UTC().Truncate(time.Second),
+			TargetDate: targetDate,
+			Percentile: percentile,
+			Items:      items,
+			IssueKeys:  keys,
+			IssueTypes: issueTypes,
+			Status:     CommitmentOpen,
+		}
+		if c.Percentile == 0 {
+			c.Percentile = 85
+		}
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("Commitment %d", len(commitments)+1)
+		}
+		if forecastID != "" {
+			if !slices.Contains(forecastPercentiles, c.Percentile) {
+				return nil, fmt.Errorf("invalid percentile %d: expected 10, 30, 50, 70, 85, 90, 95, or 98", c.Percentile)
+			}
+			snapshot, rec, date, err := s.commitmentSnapshot(sourceID, forecastID, c.Percentile)
+			if err != nil {
+				return nil, err
+			}
+			c.Forecast = snapshot
+			if c.TargetDate == "" {
+				c.TargetDate = date
+			}
+			if c.Items == 0 && len(keys) == 0 {
+				c.Items = forecastScopeTotal(rec)
+			}
+			if len(c.IssueTypes) == 0 {
+				c.IssueTypes = rec.Inputs.IssueTypes
+			}
+		} else if c.Percentile < 1 || c.Percentile > 99 {
+			return nil, fmt.Errorf("invalid percentile %d: expected 1–99", c.Percentile)
+		}
+		if len(keys) > 0 {
+			c.Items = len(keys)
+		}
+
+		switch target, err := time.Parse(stats.DateFormat, c.TargetDate); {
+		case c.TargetDate == "":
+			return nil, fmt.Errorf("target_date is required without a forecast_id")
+		case err != nil:
+			return nil, fmt.Errorf("invalid target_date %q: expected YYYY-MM-DD", c.TargetDate)
+		case stats.CalendarDaysBetween(s.Clock(ctx), target) < 0:
+			return nil, fmt.Errorf("target_date %s is in the past", c.TargetDate)
+		case c.Items <= 0:
+			return nil, fmt.Errorf("the commitment has no items: pass items or issue_keys")
+		}
+
+		probe, err := s.newCommitmentProbe(ctx, hctx, sampleDays)
+		if err != nil {
+			return nil, err
+		}
+		if c.Baseline, err = s.checkCommitment(ctx, probe, c); err != nil {
+			return nil, err
+		}
+		commitments = append(commitments, c)
+		res["commitment"] = c
+		insights = append(insights, fmt.Sprintf("Recorded %s: %d item(s) by %s at P%d. Probability as of today: %.0f%% for the %d remaining item(s).",
+			c.ID, c.Items, c.TargetDate, c.Percentile, c.Baseline.Probability*100, c.Baseline.Remaining))
+		if c.Baseline.Probability*100 < float64(c.Percentile) {
+			insights = append(insights, fmt.Sprintf("The commitment starts below its P%d confidence: the current throughput delivers the scope by %s with %.0f%% probability.",
+				c.Percentile, c.TargetDate, c.Baseline.Probability*100))
+		}
+	}
+	if len(withdrawn) > 0 {
+		res["withdrawn"] = withdrawn
+		insights = append(insights, fmt.Sprintf("Withdrawn: %s.", strings.Join(withdrawn, ", ")))
+	}
+
+	if err := s.saveCommitments(sourceID, commitments); err != nil {
+		log.Error().Err(err).Str("source", sourceID).Msg("Failed to save commitments")
+		return nil, fmt.Errorf("failed to save the commitment registry: %w", err)
+	}
+	insights = append(insights, s.guidanceFor("record_commitment", guidanceFacts{})...)
+	return WrapResponse(res, projectKey, boardID, nil, nil, insights), nil
+}
+
+// CommitmentHealth is an open commitment as of today and how its probability
+// has moved.
+type CommitmentHealth struct {
+	Commitment
+	Probability           float64 `json:"probability"`
+	Remaining             int     `json:"remaining"`
+	AtRisk                bool    `json:"at_risk"`                 // Open with a probability below the committed percentile
+	ChangeSincePrevious   float64 `json:"change_since_previous"`   // Against the previous check, or the baseline
+	ChangeSinceCommitment float64 `json:"change_since_commitment"` // Against the baseline
+	Trend                 string  `json:"trend"`                   // improving, declining, or stable
+}
+
+// handleAnalyzeCommitmentHealth checks every open commitment of the board as
+// of today and records the check, so that later runs can report the trend.
+// Commitments whose items are all delivered are closed as met; those past
+// their target date as missed.
+func (s *Server) handleAnalyzeCommitmentHealth(ctx context.Context, projectKey string, boardID int, sampleDays int) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	sourceID := hctx.SourceID
+	commitments, err := s.loadCommitments(sourceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(commitments) == 0 {
+		return nil, fmt.Errorf("no commitments recorded for %s; record one first with 'record_commitment'", sourceID)
+	}
+
+	var probe *commitmentProbe
+	today := s.Clock(ctx).Format(stats.DateFormat)
+	health := []CommitmentHealth{}
+	closed := map[string]int{}
+	var insights []string
+	atRisk := 0
+	for i := range commitments {
+		c := &commitments[i]
+		if c.Status != CommitmentOpen {
+			closed[c.Status]++
+			continue
+		}
+		if probe == nil {
+			if probe, err = s.newCommitmentProbe(ctx, hctx, sampleDays); err != nil {
+				return nil, err
+			}
+		}
+		check, err := s.checkCommitment(ctx, probe, *c)
+		if err != nil {
+			return nil, fmt.Errorf("commitment %s: %w", c.ID, err)
+		}
+
+		previous := c.Baseline
+		if n := len(c.Checks); n > 0 && c.Checks[n-1].AsOf == today {
+			c.Checks = c.Checks[:n-1]
+		}
+		if n := len(c.Checks); n > 0 {
+			previous = c.Checks[n-1]
+		}
+		c.Checks = append(c.Checks, check)
+		switch {
+		case check.Remaining == 0:
+			c.Status, c.ClosedOn = CommitmentMet, today
+		case check.DaysLeft == 0:
+			c.Status, c.ClosedOn = CommitmentMissed, today
+		}
+
+		h := CommitmentHealth{
+			Commitment:            *c,
+			Probability:           check.Probability,
+			Remaining:             check.Remaining,
+			AtRisk:                c.Status == CommitmentOpen && check.Probability*100 < float64(c.Percentile),
+			ChangeSincePrevious:   stats.Round2(check.Probability - previous.Probability),
+			ChangeSinceCommitment: stats.Round2(check.Probability - c.Baseline.Probability),
+		}
+		switch {
+		case h.ChangeSincePrevious >= commitmentTrendThreshold:
+			h.Trend = "improving"
+		case h.ChangeSincePrevious <= -commitmentTrendThreshold:
+			h.Trend = "declining"
+		default:
+			h.Trend = "stable"
+		}
+		if h.AtRisk {
+			atRisk++
+		}
+		health = append(health, h)
+
+		label := fmt.Sprintf("%s (%s)", c.ID, c.Name)
+		switch c.Status {
+		case CommitmentMet:
+			insights = append(insights, fmt.Sprintf("%s met: all %d item(s) delivered by %s.", label, c.Items, c.TargetDate))
+			closed[c.Status]++
+		case CommitmentMissed:
+			insights = append(insights, fmt.Sprintf("%s missed: %d of %d item(s) not delivered by %s.", label, check.Remaining, c.Items, c.TargetDate))
+			closed[c.Status]++
+		default:
+			insights = append(insights, fmt.Sprintf("%s: %.0f%% probability of delivering the remaining %d item(s) by %s (committed at P%d; %+.0f points since the commitment, %s).",
+				label, check.Probability*100, check.Remaining, c.TargetDate, c.Percentile, h.ChangeSinceCommitment*100, h.Trend))
+		}
+	}
+
+	if err := s.saveCommitments(sourceID, commitments); err != nil {
+		log.Error().Err(err).Str("source", sourceID).Msg("Failed to save commitments")
+		return nil, fmt.Errorf("failed to save the commitment checks: %w", err)
+	}
+
+	open := 0
+	for _, h := range health {
+		if h.Status == CommitmentOpen {
+			open++
+		}
+	}
+	switch {
+	case len(health) == 0:
+		insights = append(insights, fmt.Sprintf("No open commitments for %s; record one with 'record_commitment'.", sourceID))
+	case atRisk > 0:
+		insights = append([]string{fmt.Sprintf("%d of %d open commitment(s) are below their committed confidence.", atRisk, open)}, insights...)
+	}
+
+	res := map[string]any{
+		"commitments": health,
+		"closed":      closed,
+	}
+	insights = append(insights, s.guidanceFor("analyze_commitment_health", guidanceFacts{})...)
+	var warnings []string
+	if probe != nil {
+		warnings = s.getQualityWarnings(ctx, probe.session.GetAllIssues())
+	}
+	return WrapResponse(res, projectKey, boardID, nil, warnings, insights), nil
+}
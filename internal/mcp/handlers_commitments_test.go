@@ -0,0 +1,121 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/stats"
+)
+
+func TestCommitments(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.simulationSeed = 42
+	now := srv.Clock(ctx)
+
+	// ITEM-1 and ITEM-2 are delivered one and two days after the commitments.
+	ts := now.AddDate(0, 0, -3).UnixMicro()
+	day := int64(86400 * 1e6)
+	var events []eventlog.IssueEvent
+	for i, key := range []string{"ITEM-1", "ITEM-2"} {
+		events = append(events,
+			eventlog.IssueEvent{EventType: eventlog.Created, IssueKey: key, IssueType: "Story", ToStatus: "Open", ToStatusID: "1", Timestamp: ts},
+			eventlog.IssueEvent{EventType: eventlog.Change, IssueKey: key, IssueType: "Story", ToStatus: "Done", ToStatusID: "10003", Resolution: "Done", Timestamp: now.UnixMicro() + int64(i+1)*day})
+	}
+	appendCachedEvents(t, srv, events)
+
+	if _, err := srv.handleAnalyzeCommitmentHealth(ctx, testProject, testBoard, 0); err == nil {
+		t.Fatalf("Expected an error before any commitment is recorded")
+	}
+	if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "duration",
+		HistoryWindowDays: 90,
+		Targets:           map[string]int{"Story": 10},
+	}); err != nil {
+		t.Fatalf("forecast_monte_carlo: %v", err)
+	}
+
+	record := func(name, forecastID, targetDate string, items int, keys []string) Commitment {
+		t.Helper()
+		res, err := srv.handleRecordCommitment(ctx, testProject, testBoard, name, forecastID, 0, targetDate, items, keys, nil, nil, 0)
+		if err != nil {
+			t.Fatalf("record_commitment %s: %v", name, err)
+		}
+		return res.(ResponseEnvelope).Data.(map[string]any)["commitment"].(Commitment)
+	}
+	fromForecast := record("Q3", "MCSTEST_0-F1", "", 0, nil)
+	if fromForecast.ID != "MCSTEST_0-C1" || fromForecast.Items != 10 || fromForecast.Percentile != 85 {
+		t.Fatalf("Expected 10 items at P85 from the forecast, got %+v", fromForecast)
+	}
+	if fromForecast.Forecast == nil || fromForecast.TargetDate != fromForecast.Forecast.CompletionDates["likely"] {
+		t.Errorf("Expected the P85 completion date of the forecast as target, got %+v", fromForecast)
+	}
+	if p := fromForecast.Baseline.Probability; fromForecast.Baseline.Remaining != 10 || p <= 0 || p > 1 {
+		t.Errorf("Expected a baseline with 10 remaining items and a probability in (0, 1], got %+v", fromForecast.Baseline)
+	}
+	byKeys := record("", "", now.AddDate(0, 0, 6).Format(stats.DateFormat), 0, []string{"item-1", " ITEM-2", "ITEM-1"})
+	if byKeys.Items != 2 || byKeys.Name != "Commitment 2" || byKeys.Baseline.Remaining != 2 {
+		t.Errorf("Expected the two deduplicated keys to remain, got %+v", byKeys)
+	}
+	record("Too much", "", now.Format(stats.DateFormat), 1000, nil)
+
+	for _, tc := range []struct {
+		name, forecastID, targetDate string
+		percentile, items            int
+		withdraw                     []string
+	}{
+		{name: "nothing"},
+		{name: "unknown forecast", forecastID: "MCSTEST_0-F9"},
+		{name: "unrecorded percentile", forecastID: "MCSTEST_0-F1", percentile: 80},
+		{name: "past target", targetDate: "2020-01-01", items: 5},
+		{name: "no target", items: 5},
+		{name: "unknown withdraw", withdraw: []string{"MCSTEST_0-C9"}},
+	} {
+		if _, err := srv.handleRecordCommitment(ctx, testProject, testBoard, "", tc.forecastID, tc.percentile, tc.targetDate, tc.items, nil, nil, tc.withdraw, 0); err == nil {
+			t.Errorf("%s: expected an error", tc.name)
+		}
+	}
+
+	// Three days later both items are delivered and the third target has passed.
+	later := now.AddDate(0, 0, 3)
+	srv.activeEvaluationDate = &later
+	health := func() ([]CommitmentHealth, map[string]int) {
+		t.Helper()
+		res, err := srv.handleAnalyzeCommitmentHealth(ctx, testProject, testBoard, 0)
+		if err != nil {
+			t.Fatalf("analyze_commitment_health: %v", err)
+		}
+		data := res.(ResponseEnvelope).Data.(map[string]any)
+		return data["commitments"].([]CommitmentHealth), data["closed"].(map[string]int)
+	}
+	checked, _ := health()
+	if len(checked) != 3 {
+		t.Fatalf("Expected three commitments checked, got %+v", checked)
+	}
+	if c := checked[0]; c.Status != CommitmentOpen || c.Remaining != 8 || len(c.Checks) != 1 {
+		t.Errorf("Expected the forecast commitment to stay open with 8 items left, got %+v", c)
+	}
+	if c := checked[1]; c.Status != CommitmentMet || c.Probability != 1 || c.ClosedOn != later.Format(stats.DateFormat) {
+		t.Errorf("Expected the key commitment to be met, got %+v", c)
+	}
+	if c := checked[2]; c.Status != CommitmentMissed || c.Probability != 0 || c.Remaining != 1000 {
+		t.Errorf("Expected the overdue commitment to be missed, got %+v", c)
+	}
+
+	// A second check on the same day replaces the first.
+	checked, closed := health()
+	if len(checked) != 1 || len(checked[0].Checks) != 1 || closed[CommitmentMet] != 1 || closed[CommitmentMissed] != 1 {
+		t.Errorf("Expected one open commitment with one check, and one met and one missed, got %+v %v", checked, closed)
+	}
+
+	if _, err := srv.handleRecordCommitment(ctx, testProject, testBoard, "", "", 0, "", 0, nil, nil, []string{"MCSTEST_0-C1"}, 0); err != nil {
+		t.Fatalf("withdraw: %v", err)
+	}
+	checked, closed = health()
+	if len(checked) != 0 || closed[CommitmentWithdrawn] != 1 {
+		t.Errorf("Expected no open commitments and one withdrawn, got %+v %v", checked, closed)
+	}
+}
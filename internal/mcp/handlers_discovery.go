@@ -1,12 +1,15 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
 	"mcs-mcp/internal/discovery"
+	"mcs-mcp/internal/visuals"
 
 	"github.com/rs/zerolog/log"
 )
@@ -16,9 +19,9 @@ import (
 // on prepareHandler's anchor-context reset because that path assumes a mapping
 // already exists. Keep the inline loadWorkflow/Hydrate/saveWorkflow sequence
 // on purpose.
-func (s *Server) handleGetWorkflowDiscovery(projectKey string, boardID int, forceRefresh bool) (any, error) {
+func (s *Server) handleGetWorkflowDiscovery(ctx context.Context, projectKey string, boardID int, forceRefresh bool) (any, error) {
 	// 1. Resolve Source Context (ensures consistent JQL)
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -31,14 +34,14 @@ func (s *Server) handleGetWorkflowDiscovery(projectKey string, boardID int, forc
 	}
 
 	// 2. Hydrate
-	reg, err := s.events.Hydrate(sourceID, projectKey, ctx.JQL, s.activeRegistry)
+	reg, err := s.events.Hydrate(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		log.Error().Err(err).Str("source", sourceID).Msg("Hydration failed")
 	}
 	s.activeRegistry = reg
 
 	// 3. Data Probe (Tier-Neutral Discovery for Summary)
-	events := s.events.GetIssuesInRange(sourceID, time.Time{}, s.Clock())
+	events := s.events.GetIssuesInRange(sourceID, time.Time{}, s.Clock(ctx))
 	first, last, total := stats.DiscoverDatasetBoundaries(events)
 
 	issues := stats.ProjectNeutralSample(events, DataProbeSampleSize)
@@ -164,14 +167,7 @@ func (s *Server) presentWorkflowMetadata(sourceID string, sample []jira.Issue, t
 		"discovery_source": discoverySource,
 	}
 
-	guidance := []string{
-		"AI MUST summarize the mapping of ALL statuses to TIERS (Demand, Upstream, Downstream, Finished) for the user in a clear table or list.",
-		"AI MUST confirm the 'Outcome Strategy' (Value vs. Abandonment):",
-		"  - PRIMARY: Jira Resolutions (if they exist).",
-		"  - SECONDARY: Status mapping (only if resolutions are missing).",
-		"AI MUST ask the user to confirm the 'Commitment Point' (the status where work officially starts).",
-		"PROCESS STABILITY: Understand that Stability measures Cycle-Time predictability, NOT throughput volume.",
-	}
+	guidance := s.guidanceFor("workflow_discover_mapping", guidanceFacts{})
 
 	var insights []string
 	if discoverySource == "LOADED_FROM_CACHE" {
@@ -184,7 +180,7 @@ func (s *Server) presentWorkflowMetadata(sourceID string, sample []jira.Issue, t
 	return WrapResponse(res, "", 0, diagnostics, guidance, insights), discoveredOrder
 }
 
-func (s *Server) handleSetWorkflowMapping(projectKey string, boardID int, mapping map[string]any, resolutions map[string]any, commitmentPoint string) (any, error) {
+func (s *Server) handleSetWorkflowMapping(ctx context.Context, projectKey string, boardID int, mapping map[string]any, resolutions map[string]any, commitmentPoint string, typeCommitmentPoints map[string]string) (any, error) {
 	sourceID := getCombinedID(projectKey, boardID)
 
 	// Ensure we are anchored
@@ -192,6 +188,32 @@ func (s *Server) handleSetWorkflowMapping(projectKey string, boardID int, mappin
 		return nil, err
 	}
 
+	// Expand board columns ("column:<name>") to their statuses
+	mapping, err := s.expandColumnMapping(ctx, boardID, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if commitmentPoint, err = s.columnCommitmentPoint(ctx, boardID, commitmentPoint); err != nil {
+		return nil, err
+	}
+	if len(typeCommitmentPoints) > 0 {
+		resolved := make(map[string]string, len(typeCommitmentPoints))
+		for issueType, cp := range typeCommitmentPoints {
+			if resolved[issueType], err = s.columnCommitmentPoint(ctx, boardID, cp); err != nil {
+				return nil, err
+			}
+		}
+		typeCommitmentPoints = resolved
+	}
+
+	// Reject statuses the project and the board's history do not know
+	history := s.refreshStatusRegistry(ctx, projectKey, sourceID)
+	if err := s.validateMappingStatuses(sourceID, history, mapping, commitmentPoint, typeCommitmentPoints); err != nil {
+		return nil, err
+	}
+
+	before := s.configSnapshot()
+
 	// Map names to IDs for internal stability
 	m := make(map[string]stats.StatusMetadata)
 	for k, v := range mapping {
@@ -240,20 +262,47 @@ func (s *Server) handleSetWorkflowMapping(projectKey string, boardID int, mappin
 	} else {
 		s.activeCommitmentPoint = commitmentPoint
 	}
+	s.activeTypeCommitments = s.resolveTypeCommitments(typeCommitmentPoints)
 
 	// Calculate and persist DiscoveryCutoff based on confirmed mapping
-	s.recalculateDiscoveryCutoff(sourceID)
+	s.recalculateDiscoveryCutoff(ctx, sourceID)
 
 	// Save to disk
+	now := s.Clock(ctx)
+	s.activeMappingConfirmed = &now
 	if err := s.saveWorkflow(projectKey, boardID); err != nil {
 		log.Error().Err(err).Msg("Failed to save workflow metadata")
 		return nil, fmt.Errorf("metadata updated in memory but failed to save to disk: %w", err)
 	}
+	s.recordAudit(ctx, projectKey, boardID, before)
 
-	return WrapResponse(map[string]string{"status": "success", "message": fmt.Sprintf("Stored and PERSISTED workflow mapping for source %s", sourceID)}, projectKey, boardID, nil, nil, nil), nil
+	var warnings []string
+	if unmapped := s.unmappedStatuses(history); len(unmapped) > 0 {
+		warnings = append(warnings, fmt.Sprintf("UNMAPPED STATUSES: %d status(es) in the history of %s are not covered by the mapping: %s. Items in them are ignored by tier and role based analyses; map them unless that is intended.", len(unmapped), sourceID, strings.Join(unmapped, ", ")))
+	}
+	return WrapResponse(map[string]string{"status": "success", "message": fmt.Sprintf("Stored and PERSISTED workflow mapping for source %s", sourceID)}, projectKey, boardID, nil, warnings, nil), nil
 }
 
-func (s *Server) handleSetWorkflowOrder(projectKey string, boardID int, order []string) (any, error) {
+// resolveTypeCommitments converts per-type commitment point status names to IDs.
+// Entries with an empty status are dropped; unknown names are kept as-is.
+func (s *Server) resolveTypeCommitments(byType map[string]string) map[string]string {
+	if len(byType) == 0 {
+		return nil
+	}
+	resolved := make(map[string]string, len(byType))
+	for issueType, status := range byType {
+		if status == "" {
+			continue
+		}
+		if id := s.activeRegistry.GetStatusID(status); id != "" {
+			status = id
+		}
+		resolved[issueType] = status
+	}
+	return resolved
+}
+
+func (s *Server) handleSetWorkflowOrder(ctx context.Context, projectKey string, boardID int, order []string) (any, error) {
 	sourceID := getCombinedID(projectKey, boardID)
 
 	// Ensure we are anchored
@@ -261,6 +310,8 @@ func (s *Server) handleSetWorkflowOrder(projectKey string, boardID int, order []
 		return nil, err
 	}
 
+	before := s.configSnapshot()
+
 	// Map incoming names to IDs for internal stability
 	var resolvedOrder []string
 	for _, entry := range order {
@@ -278,15 +329,16 @@ func (s *Server) handleSetWorkflowOrder(projectKey string, boardID int, order []
 		log.Error().Err(err).Msg("Failed to save workflow metadata")
 		return nil, fmt.Errorf("metadata updated in memory but failed to save to disk: %w", err)
 	}
+	s.recordAudit(ctx, projectKey, boardID, before)
 
 	return WrapResponse(map[string]string{"status": "success", "message": fmt.Sprintf("Stored and PERSISTED workflow order for source %s", sourceID)}, projectKey, boardID, nil, nil, nil), nil
 }
 
-func (s *Server) handleSetAnalysisWindow(startDate, endDate string, durationDays int, reset bool) (any, error) {
+func (s *Server) handleSetAnalysisWindow(ctx context.Context, startDate, endDate string, durationDays int, reset bool) (any, error) {
 	if reset {
 		s.activeWindowStart = nil
 		s.activeWindowEnd = nil
-		start, end, _ := s.Window()
+		start, end, _ := s.Window(ctx)
 		return WrapResponse(map[string]any{
 			"status":        "reset",
 			"message":       "Session window cleared. Diagnostics will use the default rolling 26-week range.",
@@ -303,7 +355,7 @@ func (s *Server) handleSetAnalysisWindow(startDate, endDate string, durationDays
 		return nil, fmt.Errorf("set_analysis_window requires exactly one of start_date or duration_days (set reset=true to clear)")
 	}
 
-	end := s.Clock()
+	end := s.Clock(ctx)
 	if endDate != "" {
 		t, err := time.Parse(stats.DateFormat, endDate)
 		if err != nil {
@@ -311,7 +363,7 @@ func (s *Server) handleSetAnalysisWindow(startDate, endDate string, durationDays
 		}
 		end = t
 	}
-	if end.After(s.Clock().Add(24 * time.Hour)) {
+	if end.After(s.Clock(ctx).Add(24 * time.Hour)) {
 		return nil, fmt.Errorf("end_date cannot be in the future relative to the active evaluation date")
 	}
 
@@ -347,8 +399,8 @@ func (s *Server) handleSetAnalysisWindow(startDate, endDate string, durationDays
 	}), nil
 }
 
-func (s *Server) handleGetAnalysisWindow() (any, error) {
-	start, end, explicit := s.Window()
+func (s *Server) handleGetAnalysisWindow(ctx context.Context) (any, error) {
+	start, end, explicit := s.Window(ctx)
 	source := "default"
 	if explicit {
 		source = "session"
@@ -361,11 +413,25 @@ func (s *Server) handleGetAnalysisWindow() (any, error) {
 	}, "", 0, nil, nil, nil), nil
 }
 
-func (s *Server) handleSetEvaluationDate(projectKey string, boardID int, dateStr string) (any, error) {
+// handleSetVisualPreferences toggles Mermaid diagrams in analytical responses
+// for the rest of the session.
+func (s *Server) handleSetVisualPreferences(mermaidCharts bool) (any, error) {
+	s.enableMermaidCharts = mermaidCharts
+	res := map[string]any{"mermaid_charts": mermaidCharts}
+	var guidance []string
+	if mermaidCharts {
+		res["tools"] = visuals.Tools()
+		guidance = s.guidanceFor("set_visual_preferences", guidanceFacts{})
+	}
+	return WrapResponse(res, "", 0, nil, nil, guidance), nil
+}
+
+func (s *Server) handleSetEvaluationDate(ctx context.Context, projectKey string, boardID int, dateStr string) (any, error) {
 	// Ensure we are anchored before saving
 	if err := s.anchorContext(projectKey, boardID); err != nil {
 		return nil, err
 	}
+	before := s.configSnapshot()
 	if dateStr == "" {
 		s.activeEvaluationDate = nil
 	} else {
@@ -381,6 +447,7 @@ func (s *Server) handleSetEvaluationDate(projectKey string, boardID int, dateStr
 		log.Error().Err(err).Msg("Failed to save workflow metadata")
 		return nil, fmt.Errorf("metadata updated in memory but failed to save to disk: %w", err)
 	}
+	s.recordAudit(ctx, projectKey, boardID, before)
 
 	var guidance []string
 	msg := "Successfully cleared the evaluation date. Analysis will use real-time time.Now()."
@@ -392,3 +459,33 @@ func (s *Server) handleSetEvaluationDate(projectKey string, boardID int, dateStr
 	return WrapResponse(map[string]string{"status": "success", "message": msg}, projectKey, boardID, nil, nil, guidance), nil
 }
 
+
+func (s *Server) handleAnalyzeDefinitionOfWorkflow(ctx context.Context, projectKey string, boardID int) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+
+	all := session.GetAllIssues()
+	if len(all) == 0 {
+		return nil, fmt.Errorf("no issues found in the analysis window")
+	}
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+
+	drift := stats.AnalyzeWorkflowDrift(all, s.activeMapping, analysisCtx.CommitmentPoint)
+	drift.Round()
+
+	res := map[string]any{
+		"workflow_by_type": drift,
+	}
+
+	guidance := append(s.guidanceFor("analyze_definition_of_workflow", guidanceFacts{}),
+		s.windowingGuidance(ctx),
+		fmt.Sprintf("Types with fewer than %d items are profiled but never flagged.", stats.MinTypeWorkflowSample),
+	)
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
+}
@@ -0,0 +1,95 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// ensembleForecast is the ensemble branch of forecast_monte_carlo. It
+// forecasts the scope of req again from the cycle times of its delivered
+// items, with wipIssues (the in-progress items in scope) finishing first
+// from their current age, and attaches the result and its disagreement with
+// the throughput forecast of resObj. wipLimit overrides the parallelism.
+func (s *Server) ensembleForecast(ctx context.Context, projectKey string, boardID int, req simulation.ForecastRequest, resObj *simulation.Result, wipIssues []jira.Issue, analysisCtx *AnalysisContext, wipLimit int) error {
+	cycleTimes, _ := s.getCycleTimes(ctx, projectKey, boardID, req.Finished, analysisCtx.CommitmentPoint, "", req.IssueTypes)
+	if len(cycleTimes) == 0 {
+		return fmt.Errorf("ensemble needs cycle times, but no item in the sampling window was delivered past the commitment point")
+	}
+
+	var wipAges []float64
+	for _, a := range stats.CalculateInventoryAgeByType(wipIssues, analysisCtx.Commitments(), analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, cycleTimes, "wip", s.commitmentBackflowReset, s.Clock(ctx)) {
+		age := 0.0
+		if a.AgeSinceCommitment != nil {
+			age = *a.AgeSinceCommitment
+		}
+		wipAges = append(wipAges, age)
+	}
+	total := 0
+	for _, n := range req.Targets {
+		total += n
+	}
+
+	parallelism, source := s.ensembleParallelism(req, cycleTimes, wipLimit)
+	engine := simulation.NewEngine(nil)
+	if req.SimulationSeed != 0 {
+		engine.SetSeed(req.SimulationSeed)
+	}
+	engine.SetContext(ctx)
+	p, warnings := engine.RunCycleTimeForecast(cycleTimes, wipAges, max(total-len(wipAges), 0), parallelism, simulation.DefaultTrials)
+	if err := engine.Err(); err != nil {
+		return err
+	}
+
+	disagreement, risk := simulation.CompareModels(resObj.Percentiles, p)
+	resObj.Ensemble = &simulation.EnsembleForecast{
+		Percentiles:       p,
+		Parallelism:       parallelism,
+		ParallelismSource: source,
+		CycleTimeSample:   len(cycleTimes),
+		Disagreement:      disagreement,
+		ModelRisk:         risk,
+	}
+	resObj.Warnings = append(resObj.Warnings, warnings...)
+
+	text := fmt.Sprintf("ENSEMBLE: at P85 the throughput model needs %.0f days; the cycle-time model, working %d item(s) at once (%s), needs %.0f days. They disagree by %.0f%% (model risk %s).",
+		resObj.Percentiles.Likely, parallelism, source, p.Likely, disagreement*100, risk)
+	switch risk {
+	case "high":
+		resObj.Warnings = append(resObj.Warnings, text+" At least one model misreads the system: batch deliveries, a parallelism far from the actual one, or aged WIP. Present both dates and plan on the later one.")
+	case "moderate":
+		resObj.Insights = append(resObj.Insights, text+" The choice of model matters; present the range between them.")
+	default:
+		resObj.Insights = append(resObj.Insights, text+" Both views of the system agree, which supports the forecast.")
+	}
+	return nil
+}
+
+// ensembleParallelism returns the number of items the cycle-time model works
+// at once and where it comes from: the wip_limit parameter, the Downstream
+// tier limit of set_wip_limits, the current WIP, or Little's law (delivered
+// items per day times the mean cycle time).
+func (s *Server) ensembleParallelism(req simulation.ForecastRequest, cycleTimes []float64, wipLimit int) (int, string) {
+	if wipLimit > 0 {
+		return wipLimit, "wip_limit"
+	}
+	for _, l := range s.activeWIPLimits {
+		if l.Scope == stats.WIPLimitTier && l.Target == stats.TierDownstream {
+			return l.Limit, "tier_limit"
+		}
+	}
+	if len(req.WIP) > 0 {
+		return len(req.WIP), "current_wip"
+	}
+	days := max(stats.CalendarDaysBetween(req.WindowStart, req.WindowEnd), 1)
+	mean := 0.0
+	for _, ct := range cycleTimes {
+		mean += ct
+	}
+	mean /= float64(len(cycleTimes))
+	return max(int(math.Round(float64(len(cycleTimes))/float64(days)*mean)), 1), "littles_law"
+}
@@ -0,0 +1,244 @@
+package mcp
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Dataset export file names, written side by side into one directory.
+const (
+	datasetItemsFile     = "items.csv"
+	datasetResidencyFile = "residency.csv"
+	datasetEventsFile    = "events.csv"
+	datasetDirPattern    = "%s_%s" // sourceID, timestamp
+)
+
+// datasetTable is one CSV file of a dataset export.
+type datasetTable struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+func (s *Server) handleExportDataset(ctx context.Context, projectKey string, boardID int, dir string) (any, error) {
+	sourceID := getCombinedID(projectKey, boardID)
+	dir, err := s.datasetDir(dir, sourceID, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+	delivered := session.GetDelivered()
+	if len(delivered) == 0 {
+		return nil, fmt.Errorf("no historical delivery data found")
+	}
+
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, session.GetAllIssues())
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+	cycleTimeOf := make(map[string]float64, len(matchedIssues))
+	for i, issue := range matchedIssues {
+		cycleTimeOf[issue.Key] = cycleTimes[i]
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create export directory: %w", err)
+	}
+
+	tables := []datasetTable{
+		s.datasetItems(delivered, cycleTimeOf),
+		s.datasetResidency(delivered),
+		s.datasetEvents(s.events.GetIssuesInRange(sourceID, window.Start, window.End)),
+	}
+	files := make(map[string]any, len(tables))
+	for _, t := range tables {
+		path := filepath.Join(dir, t.name)
+		if err := writeCSV(path, t.header, t.rows); err != nil {
+			return nil, fmt.Errorf("failed to write %s: %w", t.name, err)
+		}
+		files[strings.TrimSuffix(t.name, ".csv")] = map[string]any{"path": path, "rows": len(t.rows)}
+	}
+	log.Info().Str("source", sourceID).Str("dir", dir).Int("items", len(delivered)).Msg("Dataset exported")
+
+	res := map[string]any{
+		"dir":              dir,
+		"files":            files,
+		"commitment_point": analysisCtx.CommitmentPoint,
+		"window":           map[string]any{"start": window.Start.Format(time.DateOnly), "end": window.End.Format(time.DateOnly)},
+	}
+	var warnings []string
+	if missing := len(delivered) - len(matchedIssues); missing > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d delivered item(s) have no cycle time from the commitment point (e.g. delivered before the discovery cutoff); their cycle_time_days is empty.", missing))
+	}
+	if s.anonymizer != nil {
+		warnings = append(warnings, "MCS_ANONYMIZE is set: issue keys and assignees in the files are pseudonyms.")
+	}
+	guidance := []string{
+		"The files are UTF-8 CSV with a header row: 'items' has one row per delivered item, 'residency' one row per item and status, 'events' one row per event of the session window. Join them on 'key'.",
+		"Load them with pandas.read_csv or polars.read_csv. No Parquet files are written; convert with DataFrame.to_parquet (pandas) or DataFrame.write_parquet (polars).",
+	}
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
+
+// datasetDir resolves the directory of a dataset export under the exports
+// directory of the cache: a new one per export by default, else dir, which
+// may be relative to the exports directory or an absolute path inside the
+// cache directory. Paths leaving the cache directory are rejected.
+func (s *Server) datasetDir(dir, sourceID string, now time.Time) (string, error) {
+	if s.cacheDir == "" {
+		return "", fmt.Errorf("no cache directory configured")
+	}
+	exports := filepath.Join(s.cacheDir, "exports")
+	if dir == "" {
+		return filepath.Join(exports, fmt.Sprintf(datasetDirPattern, sourceID, now.Format("20060102_150405"))), nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(exports, dir)
+	}
+	rel, err := filepath.Rel(s.cacheDir, dir)
+	if err != nil || rel == "." || !filepath.IsLocal(rel) {
+		return "", fmt.Errorf("dir %q is outside the cache directory: use a subdirectory of %s", dir, exports)
+	}
+	return dir, nil
+}
+
+// datasetKey returns the issue key to write to a dataset: its pseudonym when
+// MCS_ANONYMIZE is set, since exported files bypass the result anonymization.
+func (s *Server) datasetKey(key string) string {
+	if s.anonymizer == nil || key == "" {
+		return key
+	}
+	return s.anonymizer.pseudonym(key)
+}
+
+// datasetItems lists the delivered items with their cycle times in days,
+// empty for items that never passed the commitment point.
+func (s *Server) datasetItems(delivered []jira.Issue, cycleTimeOf map[string]float64) datasetTable {
+	t := datasetTable{
+		name:   datasetItemsFile,
+		header: []string{"key", "issue_type", "created", "delivered", "resolution", "cycle_time_days", "components", "labels", "parent_key", "fix_versions", "estimate", "assignee", "team"},
+	}
+	for _, issue := range delivered {
+		var cycleTime, estimate, assignee string
+		if ct, ok := cycleTimeOf[issue.Key]; ok {
+			cycleTime = formatDays(ct)
+		}
+		if issue.Estimate != nil {
+			estimate = strconv.FormatFloat(*issue.Estimate, 'f', -1, 64)
+		}
+		assignee = issue.Assignee
+		if s.anonymizer != nil && assignee != "" {
+			assignee = s.anonymizer.person(assignee)
+		}
+		versions := make([]string, len(issue.FixVersions))
+		for i, v := range issue.FixVersions {
+			versions[i] = v.Name
+		}
+		t.rows = append(t.rows, []string{
+			s.datasetKey(issue.Key), issue.IssueType, formatTimestamp(&issue.Created), formatTimestamp(issue.OutcomeDate),
+			issue.Resolution, cycleTime, strings.Join(issue.Components, ";"), strings.Join(issue.Labels, ";"),
+			s.datasetKey(issue.ParentKey), strings.Join(versions, ";"), estimate, assignee, issue.Team,
+		})
+	}
+	return t
+}
+
+// datasetResidency lists the days each delivered item spent per status, with
+// the tier of the status in the confirmed workflow mapping.
+func (s *Server) datasetResidency(delivered []jira.Issue) datasetTable {
+	t := datasetTable{
+		name:   datasetResidencyFile,
+		header: []string{"key", "status_id", "status", "tier", "days", "blocked_days"},
+	}
+	for _, issue := range delivered {
+		ids := make([]string, 0, len(issue.StatusResidency))
+		for id := range issue.StatusResidency {
+			ids = append(ids, id)
+		}
+		slices.Sort(ids)
+		for _, id := range ids {
+			t.rows = append(t.rows, []string{
+				s.datasetKey(issue.Key), id, s.statusName(id), s.activeMapping[id].Tier,
+				formatDays(float64(issue.StatusResidency[id]) / 86400.0),
+				formatDays(float64(issue.BlockedResidency[id]) / 86400.0),
+			})
+		}
+	}
+	return t
+}
+
+// datasetEvents lists the events of the event log in chronological order.
+// The snapshot attributes of the Created events are in items.csv instead.
+func (s *Server) datasetEvents(events []eventlog.IssueEvent) datasetTable {
+	t := datasetTable{
+		name:   datasetEventsFile,
+		header: []string{"key", "issue_type", "event_type", "timestamp", "from_status_id", "from_status", "to_status_id", "to_status", "resolution", "unresolved", "flagged"},
+	}
+	for _, e := range events {
+		ts := time.UnixMicro(e.Timestamp)
+		var unresolved string
+		if e.IsUnresolved {
+			unresolved = "true"
+		}
+		t.rows = append(t.rows, []string{
+			s.datasetKey(e.IssueKey), e.IssueType, string(e.EventType), formatTimestamp(&ts),
+			e.FromStatusID, e.FromStatus, e.ToStatusID, e.ToStatus, e.Resolution, unresolved, e.Flagged,
+		})
+	}
+	return t
+}
+
+// formatTimestamp formats a time as RFC 3339 in UTC, or empty for nil.
+func formatTimestamp(t *time.Time) string {
+	if t == nil || t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatDays formats a duration in days to four decimals (under ten seconds).
+func formatDays(days float64) string {
+	return strconv.FormatFloat(days, 'f', 4, 64)
+}
+
+// writeCSV writes a CSV file atomically (temp file + rename).
+func writeCSV(path string, header []string, rows [][]string) error {
+	tmp := path + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	w := csv.NewWriter(out)
+	writeErr := w.Write(header)
+	if writeErr == nil {
+		writeErr = w.WriteAll(rows)
+	}
+	if closeErr := out.Close(); writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		os.Remove(tmp)
+		return writeErr
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"encoding/csv"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readDatasetFile(t *testing.T, path string) [][]string {
+	t.Helper()
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open %s: %v", path, err)
+	}
+	defer f.Close()
+	rows, err := csv.NewReader(f).ReadAll()
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return rows
+}
+
+func TestExportDataset(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	dir := filepath.Join(srv.cacheDir, "exports", "dataset")
+
+	if _, err := srv.handleExportDataset(ctx, testProject, testBoard, "dataset"); err != nil {
+		t.Fatalf("handleExportDataset: %v", err)
+	}
+
+	items := readDatasetFile(t, filepath.Join(dir, datasetItemsFile))
+	if len(items) < 2 || items[0][0] != "key" || items[0][5] != "cycle_time_days" {
+		t.Fatalf("Expected a header and delivered items, got %d rows", len(items))
+	}
+	withCycleTime := 0
+	for _, row := range items[1:] {
+		if row[5] != "" {
+			withCycleTime++
+		}
+	}
+	if withCycleTime == 0 {
+		t.Error("Expected delivered items to carry cycle times")
+	}
+
+	residency := readDatasetFile(t, filepath.Join(dir, datasetResidencyFile))
+	events := readDatasetFile(t, filepath.Join(dir, datasetEventsFile))
+	if len(residency) <= len(items) || len(events) <= len(items) {
+		t.Errorf("Expected several residency rows and events per item, got %d and %d for %d items", len(residency), len(events), len(items))
+	}
+}
+
+func TestExportDataset_Anonymized(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.anonymizer = newAnonymizer("salt")
+	dir := filepath.Join(srv.cacheDir, "dataset")
+
+	if _, err := srv.handleExportDataset(ctx, testProject, testBoard, dir); err != nil {
+		t.Fatalf("handleExportDataset: %v", err)
+	}
+
+	for _, name := range []string{datasetItemsFile, datasetResidencyFile, datasetEventsFile} {
+		for _, row := range readDatasetFile(t, filepath.Join(dir, name))[1:] {
+			if !strings.HasPrefix(row[0], pseudonymPrefix) {
+				t.Fatalf("Expected pseudonymized keys in %s, got %q", name, row[0])
+			}
+		}
+	}
+}
+
+func TestExportDataset_StaysInCacheDir(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	for _, dir := range []string{"../../outside", t.TempDir(), srv.cacheDir} {
+		if _, err := srv.handleExportDataset(ctx, testProject, testBoard, dir); err == nil {
+			t.Errorf("Expected dir %q to be rejected", dir)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(srv.cacheDir), "outside")); !os.IsNotExist(err) {
+		t.Errorf("Expected nothing written next to the cache directory, got %v", err)
+	}
+}
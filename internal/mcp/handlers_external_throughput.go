@@ -0,0 +1,264 @@
+package mcp
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Imported throughput limits.
+const (
+	externalThroughputMaxBytes = 1 << 20
+	externalThroughputMaxRows  = 10000 // ~27 years of daily samples
+)
+
+// ExternalThroughputFile is the throughput of a board imported from outside
+// Jira, persisted as {sourceID}_throughput.json. Forecasts sample it for the
+// days before the first Jira delivery of their sample window.
+type ExternalThroughputFile struct {
+	Source      string             `json:"source"`      // File name, or "inline"
+	Granularity Granularity        `json:"granularity"` // Of the samples: daily or weekly
+	ImportedAt  time.Time          `json:"imported_at"`
+	Samples     []ThroughputSample `json:"samples"` // Sorted by date
+}
+
+// ThroughputSample is the number of items delivered on a day, or in the week
+// starting on that day.
+type ThroughputSample struct {
+	Date  string `json:"date"` // YYYY-MM-DD
+	Count int    `json:"count"`
+}
+
+func (s *Server) externalThroughputPath(sourceID string) string {
+	return filepath.Join(s.cacheDir, fmt.Sprintf("%s_throughput.json", sourceID))
+}
+
+// loadExternalThroughput returns the imported throughput of a source, or nil
+// if none was imported.
+func (s *Server) loadExternalThroughput(sourceID string) (*ExternalThroughputFile, error) {
+	if s.cacheDir == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(s.externalThroughputPath(sourceID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var f ExternalThroughputFile
+	if err := json.Unmarshal(data, &f); err != nil {
+		return nil, fmt.Errorf("invalid imported throughput %s: %w", s.externalThroughputPath(sourceID), err)
+	}
+	return &f, nil
+}
+
+// externalThroughput returns the imported throughput of a source as daily
+// counts between start and end, or nil if none was imported for that range.
+// A failure to read it is logged; forecasts then sample Jira history only.
+func (s *Server) externalThroughput(sourceID string, start, end time.Time) *simulation.ExternalThroughput {
+	f, err := s.loadExternalThroughput(sourceID)
+	if err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to load imported throughput")
+		return nil
+	}
+	if f == nil {
+		return nil
+	}
+	return f.daily(start, end)
+}
+
+// daily expands the samples to one count per calendar day, clipped to
+// [start, end]. Days between samples count as days without deliveries; a
+// weekly count is spread evenly over its seven days.
+func (f ExternalThroughputFile) daily(start, end time.Time) *simulation.ExternalThroughput {
+	if len(f.Samples) == 0 {
+		return nil
+	}
+	period := 1
+	if f.Granularity == GranularityWeekly {
+		period = 7
+	}
+	first, _ := time.ParseInLocation(time.DateOnly, f.Samples[0].Date, start.Location())
+	last, _ := time.ParseInLocation(time.DateOnly, f.Samples[len(f.Samples)-1].Date, start.Location())
+	counts := make([]int, stats.CalendarDaysBetween(first, last)+period)
+	for _, sample := range f.Samples {
+		day, _ := time.ParseInLocation(time.DateOnly, sample.Date, start.Location())
+		i := stats.CalendarDaysBetween(first, day)
+		for d := range period {
+			counts[i+d] = sample.Count / period
+			if d < sample.Count%period {
+				counts[i+d]++
+			}
+		}
+	}
+
+	start, end = stats.SnapToStart(start, "day"), stats.SnapToStart(end, "day")
+	lo := max(0, stats.CalendarDaysBetween(first, start))
+	hi := min(len(counts), stats.CalendarDaysBetween(first, end)+1)
+	if lo >= hi {
+		return nil
+	}
+	return &simulation.ExternalThroughput{Source: f.Source, Start: first.AddDate(0, 0, lo), Counts: counts[lo:hi]}
+}
+
+// parseThroughputCSV reads date,count rows. A first row whose date does not
+// parse is taken as a header. Dates must be unique; weekly dates must be at
+// least seven days apart.
+func parseThroughputCSV(r io.Reader, granularity Granularity) ([]ThroughputSample, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(rows) > externalThroughputMaxRows+1 {
+		return nil, fmt.Errorf("too many rows: at most %d samples", externalThroughputMaxRows)
+	}
+
+	var samples []ThroughputSample
+	for i, row := range rows {
+		if len(row) < 2 {
+			return nil, fmt.Errorf("row %d: expected date,count", i+1)
+		}
+		date := strings.TrimSpace(row[0])
+		if _, err := time.Parse(time.DateOnly, date); err != nil {
+			if i == 0 {
+				continue // header
+			}
+			return nil, fmt.Errorf("row %d: invalid date %q (use YYYY-MM-DD)", i+1, date)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(row[1]))
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("row %d: invalid count %q (use a whole number of delivered items)", i+1, row[1])
+		}
+		samples = append(samples, ThroughputSample{Date: date, Count: count})
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("no samples: expected date,count rows")
+	}
+
+	slices.SortFunc(samples, func(a, b ThroughputSample) int { return strings.Compare(a.Date, b.Date) })
+	minGap := 1
+	if granularity == GranularityWeekly {
+		minGap = 7
+	}
+	for i := 1; i < len(samples); i++ {
+		prev, _ := time.Parse(time.DateOnly, samples[i-1].Date)
+		cur, _ := time.Parse(time.DateOnly, samples[i].Date)
+		if stats.CalendarDaysBetween(prev, cur) < minGap {
+			return nil, fmt.Errorf("samples %s and %s overlap: %s dates must be unique and %d day(s) apart", samples[i-1].Date, samples[i].Date, granularity, minGap)
+		}
+	}
+	return samples, nil
+}
+
+func (s *Server) handleImportThroughputCSV(projectKey string, boardID int, path, content string, granularity Granularity, remove bool) (any, error) {
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	if s.cacheDir == "" {
+		return nil, fmt.Errorf("no cache directory configured")
+	}
+	sourceID := getCombinedID(projectKey, boardID)
+	target := s.externalThroughputPath(sourceID)
+
+	if remove {
+		if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove imported throughput: %w", err)
+		}
+		log.Info().Str("source", sourceID).Msg("Imported throughput removed")
+		return WrapResponse(map[string]any{"removed": true}, projectKey, boardID, nil, nil, []string{"Forecasts sample Jira history only again."}), nil
+	}
+
+	if granularity == "" {
+		granularity = GranularityWeekly
+	}
+	if granularity != GranularityDaily && granularity != GranularityWeekly {
+		return nil, fmt.Errorf("invalid granularity %q: must be 'daily' or 'weekly'", granularity)
+	}
+
+	source := "inline"
+	switch {
+	case (path == "") == (strings.TrimSpace(content) == ""):
+		return nil, fmt.Errorf("set either path or csv")
+	case path != "":
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open CSV: %w", err)
+		}
+		defer file.Close()
+		data, err := io.ReadAll(io.LimitReader(file, externalThroughputMaxBytes+1))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+		content = string(data)
+		source = filepath.Base(path)
+	}
+	if len(content) > externalThroughputMaxBytes {
+		return nil, fmt.Errorf("CSV exceeds %d bytes", externalThroughputMaxBytes)
+	}
+	samples, err := parseThroughputCSV(strings.NewReader(content), granularity)
+	if err != nil {
+		return nil, err
+	}
+
+	f := ExternalThroughputFile{Source: source, Granularity: granularity, ImportedAt: time.Now().UTC(), Samples: samples}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	tmp := target + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return nil, fmt.Errorf("failed to save imported throughput: %w", err)
+	}
+	if err := os.Rename(tmp, target); err != nil {
+		os.Remove(tmp)
+		return nil, fmt.Errorf("failed to save imported throughput: %w", err)
+	}
+	log.Info().Str("source", sourceID).Int("samples", len(samples)).Msg("Throughput imported")
+
+	total := 0
+	for _, sample := range samples {
+		total += sample.Count
+	}
+	first, _ := time.Parse(time.DateOnly, samples[0].Date)
+	last, _ := time.Parse(time.DateOnly, samples[len(samples)-1].Date)
+	if granularity == GranularityWeekly {
+		last = last.AddDate(0, 0, 6)
+	}
+	days := stats.CalendarDaysBetween(first, last) + 1
+	res := map[string]any{
+		"source":      source,
+		"granularity": granularity,
+		"samples":     len(samples),
+		"first_date":  samples[0].Date,
+		"last_date":   last.Format(time.DateOnly),
+		"items":       total,
+		"per_day":     math.Round(float64(total)/float64(days)*1000) / 1000,
+	}
+	var warnings []string
+	if granularity == GranularityWeekly {
+		warnings = append(warnings, "Weekly counts are spread evenly over their seven days: the weekly volume is kept, the day-to-day variation within a week is not. Import daily counts if you have them.")
+	}
+	guidance := []string{
+		"Forecasts on this board now sample the imported days before the first Jira delivery of their sample window, and flag the hybrid sample in their warnings. Jira history wins where both cover a day.",
+		"Widen the sample window (sample_days or sample_start_date) to reach back into the imported period. 'analyze_throughput_histogram' shows how many sampled days are imported.",
+	}
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
@@ -0,0 +1,81 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseThroughputCSV(t *testing.T) {
+	samples, err := parseThroughputCSV(strings.NewReader("week,items\n2024-01-08,4\n2024-01-01, 3\n"), GranularityWeekly)
+	if err != nil {
+		t.Fatalf("parseThroughputCSV: %v", err)
+	}
+	if len(samples) != 2 || samples[0].Date != "2024-01-01" || samples[0].Count != 3 {
+		t.Errorf("Expected two samples sorted by date, got %+v", samples)
+	}
+
+	for name, input := range map[string]string{
+		"negative count": "2024-01-01,-1",
+		"bad date":       "2024-01-01,1\n01/08/2024,2",
+		"weekly overlap": "2024-01-01,1\n2024-01-03,2",
+		"empty":          "date,count\n",
+	} {
+		if _, err := parseThroughputCSV(strings.NewReader(input), GranularityWeekly); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestExternalThroughputFile_Daily(t *testing.T) {
+	f := ExternalThroughputFile{Source: "legacy.csv", Granularity: GranularityWeekly, Samples: []ThroughputSample{
+		{Date: "2024-01-01", Count: 9},
+		{Date: "2024-01-15", Count: 7},
+	}}
+	start := time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC)
+	got := f.daily(start, time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC))
+
+	if got == nil || !got.Start.Equal(start) || len(got.Counts) != 19 {
+		t.Fatalf("Expected 19 days from Jan 3 to Jan 21, got %+v", got)
+	}
+	if got.Counts[4] != 1 || got.Counts[5] != 0 || got.Counts[11] != 0 || got.Counts[12] != 1 {
+		t.Errorf("Expected 9 items spread over the first week, an empty second week, and 7 over the third, got %v", got.Counts)
+	}
+	if f.daily(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)) != nil {
+		t.Error("Expected nothing outside the imported range")
+	}
+}
+
+func TestImportThroughputCSV_HybridHistogram(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	var csv strings.Builder
+	csv.WriteString("date,count\n")
+	for d := time.Date(2017, 1, 2, 0, 0, 0, 0, time.UTC); d.Year() == 2017 && d.Month() < 7; d = d.AddDate(0, 0, 7) {
+		csv.WriteString(d.Format(time.DateOnly) + ",7\n")
+	}
+	if _, err := srv.handleImportThroughputCSV(testProject, testBoard, "", csv.String(), "", false); err != nil {
+		t.Fatalf("import: %v", err)
+	}
+
+	res, err := srv.handleAnalyzeThroughputHistogram(ctx, testProject, testBoard, 0, "2017-01-01", "2017-12-31", nil, nil)
+	if err != nil {
+		t.Fatalf("histogram: %v", err)
+	}
+	hist := res.(ResponseEnvelope).Data.(ThroughputHistogram)
+	if hist.Summary.External == nil || hist.Summary.External.Days == 0 || hist.Summary.External.Source != "inline" {
+		t.Fatalf("Expected imported days in the sample, got %+v", hist.Summary.External)
+	}
+	if len(hist.Dates) > 0 && hist.Dates[0] != "2017-01-02" {
+		t.Errorf("Expected the buckets to start with the imported days, got %s", hist.Dates[0])
+	}
+
+	if _, err := srv.handleImportThroughputCSV(testProject, testBoard, "", "", "", true); err != nil {
+		t.Fatalf("remove: %v", err)
+	}
+	if f, _ := srv.loadExternalThroughput(testSourceID); f != nil {
+		t.Error("Expected the import to be removed")
+	}
+}
@@ -1,21 +1,48 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"slices"
+	"strings"
 	"time"
 
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
 	"mcs-mcp/internal/stats"
 )
 
-func (s *Server) handleGetDeliveryCadence(projectKey string, boardID int, bucket string, _ bool) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+// ThroughputResult is the data of analyze_throughput. Fields are in JSON key
+// order so the result reads as it did when it was a map.
+type ThroughputResult struct {
+	Buckets              []map[string]string            `json:"@metadata,omitempty"`
+	Batching             *simulation.BatchingAssessment `json:"batching,omitempty"`
+	GroupedThroughput    *stats.StreamThroughputResult  `json:"grouped_throughput,omitempty"`
+	Portfolio            *PortfolioSummary              `json:"portfolio,omitempty"`
+	Stability            *stats.XmRResult               `json:"stability,omitempty"`
+	StratifiedThroughput map[string][]int               `json:"stratified_throughput"`
+	TotalThroughput      []int                          `json:"total_throughput"`
+}
+
+func (s *Server) handleGetDeliveryCadence(ctx context.Context, projectKey string, boardID int, bucket string, alignment BucketAlignment, _ bool, groupBy GroupDimension) (any, error) {
+	var dimension string
+	if groupBy != "" {
+		var err error
+		if dimension, err = groupDimension(groupBy); err != nil {
+			return nil, err
+		}
+	}
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Project
-	window := s.AnalysisWindow(bucket)
-	session := s.openSession(hctx, window)
+	window, err := s.AlignedWindow(ctx, bucket, alignment)
+	if err != nil {
+		return nil, err
+	}
+	session := s.openSession(ctx, hctx, window)
 
 	delivered := session.GetDelivered()
 	throughput := stats.GetStratifiedThroughput(delivered, window)
@@ -25,7 +52,7 @@ func (s *Server) handleGetDeliveryCadence(projectKey string, boardID int, bucket
 	bucketMetadata := make([]map[string]string, 0)
 	buckets := window.Subdivide()
 	for i, bucketStart := range buckets {
-		bucketEnd := stats.SnapToEnd(bucketStart, window.Bucket)
+		bucketEnd := window.BucketEnd(bucketStart)
 		bucketMetadata = append(bucketMetadata, map[string]string{
 			"index":      fmt.Sprintf("%d", i+1),
 			"start_date": bucketStart.Format(stats.DateFormat),
@@ -35,42 +62,199 @@ func (s *Server) handleGetDeliveryCadence(projectKey string, boardID int, bucket
 		})
 	}
 
-	res := map[string]any{
-		"total_throughput":      throughput.Pooled,
-		"stratified_throughput": throughput.ByType,
-		"@metadata":             bucketMetadata,
+	res := ThroughputResult{
+		TotalThroughput:      throughput.Pooled,
+		StratifiedThroughput: throughput.ByType,
+		Buckets:              bucketMetadata,
 	}
 
 	if throughput.XmR != nil {
+		throughput.XmR.SetSignalDates(window.BucketDates())
 		throughput.XmR.Round()
-		res["stability"] = throughput.XmR
+		res.Stability = throughput.XmR
 	}
 
-	guidance := []string{
-		"Look for 'Batching' (bursts of delivery followed by silence) vs. 'Steady Flow'.",
-		s.windowingGuidance(),
+	// Judge weekly batching on the working days forecasts sample.
+	calendar, err := s.resolveCalendar(nil, nil)
+	if err != nil {
+		return nil, err
+	}
+	h := simulation.NewHistogram(session.GetFinished(), window.Start, window.End, nil, s.activeMapping, s.activeResolutions, s.outcomes(ctx))
+	h.RestrictToWorkingDays(calendar, window.Start)
+	batching := h.AssessBatching(simulation.WeekLength(calendar))
+	res.Batching = &batching
+
+	guidance := append(s.guidanceFor("analyze_throughput", guidanceFacts{Batched: batching.Batched}),
+		s.windowingGuidance(ctx),
 		fmt.Sprintf("Throughput is grouped by %s.", bucket),
+	)
+	if window.Alignment == stats.AlignRolling {
+		guidance = append(guidance, fmt.Sprintf("Buckets are rolling: whole %ss counted back from %s, so they do not match calendar weeks or months.", bucket, window.End.Format(stats.DateFormat)))
+	}
+
+	if dimension != "" {
+		issues, err := s.groupableIssues(delivered, dimension)
+		if err != nil {
+			return nil, err
+		}
+		grouped := stats.GetStreamThroughput(issues, window, dimension)
+		grouped.Round()
+		res.GroupedThroughput = &grouped
+		if grouped.Unattributed > 0 {
+			guidance = append(guidance, fmt.Sprintf("%d delivered item(s) have no %s and are left out of 'grouped_throughput'.", grouped.Unattributed, dimension))
+		}
+		if grouped.MultiAttributed > 0 {
+			guidance = append(guidance, fmt.Sprintf("%d item(s) have more than one %s and are counted in each; group totals can exceed pooled throughput.", grouped.MultiAttributed, dimension))
+		}
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, delivered), guidance), nil
+}
+
+func (s *Server) handleAnalyzeThroughputStreams(ctx context.Context, projectKey string, boardID int, streamBy string, bucket string) (any, error) {
+	switch streamBy {
+	case stats.StreamByComponent, stats.StreamByEpic, stats.StreamByLabel:
+	default:
+		return nil, fmt.Errorf("invalid stream_by %q: must be 'component', 'epic', or 'label'", streamBy)
+	}
+
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.AnalysisWindow(ctx, bucket)
+	session := s.openSession(ctx, hctx, window)
+
+	delivered := session.GetDelivered()
+	streams := stats.GetStreamThroughput(delivered, window, streamBy)
+	streams.Round()
+
+	res := map[string]any{
+		"throughput_streams": streams,
+	}
+
+	guidance := []string{
+		s.windowingGuidance(ctx),
+		fmt.Sprintf("Throughput is grouped by %s and attributed by %s.", bucket, streamBy),
+	}
+	var starving []string
+	for _, st := range streams.Streams {
+		if st.Starving {
+			starving = append(starving, st.Stream)
+		}
+	}
+	if len(starving) > 0 {
+		guidance = append(guidance, fmt.Sprintf("STARVING STREAMS: %v — their share of delivery over the last %d buckets is less than half of their share over the whole window. Check whether capacity was redirected.", starving, stats.StreamRecentBuckets))
+	}
+	if total := len(delivered); total > 0 && streams.Unattributed*2 > total {
+		guidance = append(guidance, fmt.Sprintf("CAUTION: %d of %d delivered items have no %s. Stream attribution covers less than half of delivery.", streams.Unattributed, total, streamBy))
+	}
+	if streams.MultiAttributed > 0 {
+		guidance = append(guidance, fmt.Sprintf("%d items belong to more than one stream and are counted in each; stream totals can exceed pooled throughput.", streams.MultiAttributed))
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, delivered), guidance), nil
+}
+
+func (s *Server) handleAnalyzeReleaseLag(ctx context.Context, projectKey string, boardID int, releaseStatus string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+
+	delivered := session.GetDelivered()
+	lag := stats.AnalyzeReleaseLag(delivered, releaseStatus)
+	lag.Round()
+
+	res := map[string]any{
+		"release_lag": lag,
+	}
+
+	guidance := []string{s.windowingGuidance(ctx)}
+	if lag.ReleasedCount == 0 {
+		source := "no delivered item has a released fixVersion with a release date"
+		if releaseStatus != "" {
+			source = fmt.Sprintf("no delivered item ever transitioned to '%s'", releaseStatus)
+		}
+		guidance = append(guidance, fmt.Sprintf("NO RELEASE MARKERS: %s. Pass release_status if deployments are tracked by a workflow status.", source))
+	} else {
+		guidance = append(guidance, fmt.Sprintf("Release lag is measured from the delivery outcome date to the release date (%s). Releases dated before resolution count as zero lag.", lag.Source))
+	}
+	if lag.UnreleasedCount > 0 {
+		guidance = append(guidance, fmt.Sprintf("%d of %d delivered item(s) have no release marker yet — they are done but not in production, or their release was not recorded.", lag.UnreleasedCount, lag.DeliveredCount))
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, delivered), guidance), nil
+}
+
+// handleAnalyzeReleaseBurnup charts the scope of a fixVersion against the
+// items delivered from it, over the whole life of the release.
+func (s *Server) handleAnalyzeReleaseBurnup(ctx context.Context, projectKey string, boardID int, fixVersion, bucket string) (any, error) {
+	fixVersion = strings.TrimSpace(fixVersion)
+	if fixVersion == "" {
+		return nil, fmt.Errorf("fix_version is required")
+	}
+	if bucket == "" {
+		bucket = "week"
+	}
+	if bucket != "day" && bucket != "week" && bucket != "month" {
+		return nil, fmt.Errorf("invalid bucket %q: use 'day', 'week', or 'month'", bucket)
+	}
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Clock(ctx)
+	all := s.openSession(ctx, hctx, stats.NewAnalysisWindow(time.Time{}, now, "day", s.activeCutoff())).GetAllIssues()
+	var start time.Time
+	for _, issue := range all {
+		inVersion := slices.ContainsFunc(issue.FixVersions, func(v jira.FixVersion) bool { return v.Name == fixVersion })
+		if inVersion && (start.IsZero() || issue.Created.Before(start)) {
+			start = issue.Created
+		}
+	}
+	if start.IsZero() {
+		return nil, fmt.Errorf("no issues assigned to fixVersion %q found; check the exact release name", fixVersion)
+	}
+
+	window := stats.NewAnalysisWindow(start, now, bucket, time.Time{})
+	burnup := stats.CalculateReleaseBurnup(all, fixVersion, window)
+
+	guidance := []string{fmt.Sprintf("%s: %d of %d item(s) delivered, %d remaining; scope changed %+.0f%% since the first %s.",
+		fixVersion, burnup.Delivered, burnup.Scope, burnup.Remaining, burnup.ScopeGrowthPct, bucket)}
+	var warnings []string
+	if burnup.Released && burnup.Remaining > 0 {
+		warnings = append(warnings, fmt.Sprintf("%s is marked released, but %d of its item(s) are unfinished. Move them to a later version or close them.", fixVersion, burnup.Remaining))
+	}
+	if burnup.Remaining > 0 && !burnup.Released {
+		guidance = append(guidance, fmt.Sprintf("To forecast the release, run 'forecast_monte_carlo' in duration mode with fix_version=%q, include_wip=true, and include_existing_backlog=true.", fixVersion))
 	}
+	guidance = append(guidance, s.guidanceFor("analyze_release_burnup", guidanceFacts{})...)
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(delivered), guidance), nil
+	return WrapResponse(map[string]any{"release_burnup": burnup}, projectKey, boardID, nil, append(warnings, s.getQualityWarnings(ctx, all)...), guidance), nil
 }
 
-func (s *Server) handleAnalyzeWIPStability(projectKey string, boardID int) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleAnalyzeWIPStability(ctx context.Context, projectKey string, boardID int, byColumn bool) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Project EVERYTHING from the beginning of time to capture stagnant WIP
 	cutoff := s.activeCutoff()
-	fullWindow := stats.NewAnalysisWindow(time.Time{}, s.Clock(), "day", cutoff)
-	session := s.openSession(hctx, fullWindow)
+	fullWindow := stats.NewAnalysisWindow(time.Time{}, s.Clock(ctx), "day", cutoff)
+	session := s.openSession(ctx, hctx, fullWindow)
 
 	all := session.GetAllIssues()
 	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
 
 	// 3. Bound the chart output strictly to the session analysis window
-	displayWindow := s.AnalysisWindow("day")
+	displayWindow := s.AnalysisWindow(ctx, "day")
 	wipStability := stats.AnalyzeHistoricalWIP(all, displayWindow, analysisCtx.CommitmentPoint, analysisCtx.StatusWeights, analysisCtx.WorkflowMappings)
 	wipStability.XmR.Round()
 
@@ -78,31 +262,46 @@ func (s *Server) handleAnalyzeWIPStability(projectKey string, boardID int) (any,
 		"wip_stability": wipStability,
 	}
 
-	guidance := []string{
-		"WIP Stability provides a daily historical view of system population.",
-		"Signals (Outliers/Shifts) indicate that WIP was not actively managed or constrained, which violates Little's Law.",
-		"If the system is 'unstable', flow metrics (Cycle Time, Throughput) will be unpredictable and simulations may fail.",
+	var insights []string
+	if byColumn {
+		view, err := s.columnView(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		columnWIP := stats.AnalyzeColumnWIP(view.Issues(all), displayWindow, view.Order(), view.Mapping(analysisCtx.WorkflowMappings))
+		res["column_wip"] = columnWIP
+		var unstable []string
+		for _, c := range columnWIP {
+			if c.Status == "unstable" {
+				unstable = append(unstable, c.Column)
+			}
+		}
+		if len(unstable) > 0 {
+			insights = append(insights, fmt.Sprintf("The weekly WIP of these columns left its natural process limits: %s. Look at what enters and leaves them.", strings.Join(unstable, ", ")))
+		}
 	}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(all), guidance), nil
+	guidance := append(insights, s.guidanceFor("analyze_wip_stability", guidanceFacts{SignalCount: len(wipStability.XmR.Signals), ByColumn: byColumn})...)
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
 }
 
-func (s *Server) handleAnalyzeWIPAgeStability(projectKey string, boardID int) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleAnalyzeWIPAgeStability(ctx context.Context, projectKey string, boardID int) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Project EVERYTHING from the beginning of time to capture stagnant WIP
 	cutoff := s.activeCutoff()
-	fullWindow := stats.NewAnalysisWindow(time.Time{}, s.Clock(), "day", cutoff)
-	session := s.openSession(hctx, fullWindow)
+	fullWindow := stats.NewAnalysisWindow(time.Time{}, s.Clock(ctx), "day", cutoff)
+	session := s.openSession(ctx, hctx, fullWindow)
 
 	all := session.GetAllIssues()
 	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
 
 	// 3. Bound the chart output strictly to the session analysis window
-	displayWindow := s.AnalysisWindow("day")
+	displayWindow := s.AnalysisWindow(ctx, "day")
 	wipAgeStability := stats.AnalyzeHistoricalWIPAge(all, displayWindow, analysisCtx.CommitmentPoint, analysisCtx.StatusWeights, analysisCtx.WorkflowMappings)
 	wipAgeStability.XmR.Round()
 
@@ -110,21 +309,13 @@ func (s *Server) handleAnalyzeWIPAgeStability(projectKey string, boardID int) (a
 		"wip_age_stability": wipAgeStability,
 	}
 
-	guidance := []string{
-		"Total WIP Age reveals the cumulative age burden on the system.",
-		"While WIP Count tells how many items are in progress, Total WIP Age tells how long they have collectively been there.",
-		"A growing Total WIP Age means items are aging without being delivered — it is a leading indicator of delivery problems.",
-		"Even with stable WIP count, Total WIP Age can grow if items stagnate.",
-		"Natural behavior: Total WIP Age grows by (WIP count x 1 day) per day when no items enter or exit.",
-		"Average WIP Age is provided for convenience but is less informative — it can mask individual outliers and assumes nothing about the distribution shape.",
-		"The XmR analysis on Total WIP Age is the most defensible signal — it detects process changes without distribution assumptions.",
-	}
+	guidance := s.guidanceFor("analyze_wip_age_stability", guidanceFacts{})
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(all), guidance), nil
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
 }
 
-func (s *Server) handleGetFlowDebt(projectKey string, boardID int, bucket string) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleGetFlowDebt(ctx context.Context, projectKey string, boardID int, bucket string, alignment BucketAlignment) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -134,30 +325,52 @@ func (s *Server) handleGetFlowDebt(projectKey string, boardID int, bucket string
 	}
 
 	// 2. Project
-	window := s.AnalysisWindow(bucket)
-	session := s.openSession(hctx, window)
+	window, err := s.AlignedWindow(ctx, bucket, alignment)
+	if err != nil {
+		return nil, err
+	}
+	session := s.openSession(ctx, hctx, window)
 
 	all := session.GetAllIssues()
 	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
 
 	flowDebt := stats.CalculateFlowDebt(all, window, analysisCtx.CommitmentPoint, analysisCtx.StatusWeights, s.activeResolutions, s.activeMapping)
 
+	actions := stats.RankActions(stats.FlowDebtActions(flowDebt, bucket))
+
 	res := map[string]any{
-		"flow_debt": flowDebt,
+		"flow_debt":           flowDebt,
+		"recommended_actions": actions,
 	}
 
-	guidance := []string{
-		"Positive Flow Debt (Arrivals > Departures) is a leading indicator of cycle time inflation.",
-		"Zero or Negative Flow Debt indicates a stable or improving system throughput-to-workload ratio.",
-		s.windowingGuidance(),
+	guidance := append(s.guidanceFor("analyze_flow_debt", guidanceFacts{FlowDebt: flowDebt.TotalDebt, HasFlowDebt: true, ActionCount: len(actions)}),
+		s.windowingGuidance(ctx),
 		fmt.Sprintf("Commitment Point: %s.", analysisCtx.CommitmentPoint),
-	}
+	)
+	guidance = append(guidance, netFlowGuidance(flowDebt)...)
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
+}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(all), guidance), nil
+// netFlowGuidance names the stage accumulating inventory and the cycle time
+// Little's Law projects if the flow debt continues.
+func netFlowGuidance(res stats.FlowDebtResult) []string {
+	var out []string
+	for _, st := range res.Statuses {
+		if st.Name == res.AccumulatingStatus {
+			out = append(out, fmt.Sprintf("Inventory accumulates in '%s' (%s): %+d items over the window. Look there first for the constraint.", st.Name, st.Tier, st.TotalNet))
+			break
+		}
+	}
+	if p := res.Projection; p != nil && p.InflationPct > 0 {
+		out = append(out, fmt.Sprintf("Little's Law: %d items in flight at %.2f departures/day imply a cycle time of %.1f days. If flow debt continues at %.2f items/day, it reaches %.1f days in %d days (%+.0f%%).",
+			p.WIP, p.ThroughputPerDay, p.CycleTimeDays, p.DebtPerDay, p.ProjectedCycleTimeDays, p.HorizonDays, p.InflationPct))
+	}
+	return out
 }
 
-func (s *Server) handleGetCFDData(projectKey string, boardID int, granularity string) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleGetCFDData(ctx context.Context, projectKey string, boardID int, granularity string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -165,7 +378,7 @@ func (s *Server) handleGetCFDData(projectKey string, boardID int, granularity st
 
 	// 2. Prepare Window and Reconstruction Context
 	// CFD intentionally does not apply the discovery cutoff (full population view).
-	winStart, winEnd, _ := s.Window()
+	winStart, winEnd, _ := s.Window(ctx)
 	window := stats.NewAnalysisWindow(winStart, winEnd, "day", time.Time{})
 
 	// GetIssuesInRange returns events for all issues active in the window, including full history.
@@ -220,10 +433,7 @@ func (s *Server) handleGetCFDData(projectKey string, boardID int, granularity st
 		"cfd_data": cfd,
 	}
 
-	guidance := []string{
-		"CFD (Cumulative Flow Diagram) provides a snapshot of work items by status and issue type.",
-		"The visualization agent should use this data to render a stacked area chart.",
-	}
+	guidance := s.guidanceFor("generate_cfd_data", guidanceFacts{})
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(allIssues), guidance), nil
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, allIssues), guidance), nil
 }
@@ -0,0 +1,277 @@
+package mcp
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// ValueChange is a metric in two forecast runs.
+type ValueChange struct {
+	Baseline float64 `json:"baseline"`
+	Current  float64 `json:"current"`
+	Change   float64 `json:"change"`
+}
+
+func newValueChange(baseline, current float64) ValueChange {
+	return ValueChange{Baseline: stats.Round2(baseline), Current: stats.Round2(current), Change: stats.Round2(current - baseline)}
+}
+
+// PercentileDrift is how one forecast percentile moved between two runs.
+type PercentileDrift struct {
+	ValueChange
+	ChangePct     *float64 `json:"change_pct,omitempty"`      // nil when the baseline is 0
+	BaselineDate  string   `json:"baseline_date,omitempty"`   // Day-based duration forecasts: projected completion date
+	CurrentDate   string   `json:"current_date,omitempty"`    // Day-based duration forecasts: projected completion date
+	DateShiftDays *int     `json:"date_shift_days,omitempty"` // Positive when the date moved later
+}
+
+// ForecastComparison is the drift between two recorded forecast runs.
+type ForecastComparison struct {
+	Baseline    ForecastRecord             `json:"baseline"`
+	Current     ForecastRecord             `json:"current"`
+	Drift       map[string]PercentileDrift `json:"drift"` // p50 and p85
+	Composition map[string]ValueChange     `json:"composition,omitempty"`
+	Targets     map[string]ValueChange     `json:"targets,omitempty"` // Per issue type, only types that changed
+	Throughput  map[string]ValueChange     `json:"throughput"`
+	TypeMix     map[string]ValueChange     `json:"type_mix,omitempty"` // Share of each issue type in the sample
+}
+
+// handleCompareForecasts diffs two recorded forecast runs. Without currentID
+// the latest run of the board is used; without baselineID the latest earlier
+// run made on or before baselineDate, or simply the run before current.
+func (s *Server) handleCompareForecasts(projectKey string, boardID int, baselineID, currentID, baselineDate string) (any, error) {
+	var current ForecastRecord
+	var err error
+	switch {
+	case currentID != "":
+		if current, err = s.findForecast(currentID); err != nil {
+			return nil, err
+		}
+	case projectKey != "":
+		sourceID := getCombinedID(projectKey, boardID)
+		records, err := s.loadForecasts(sourceID)
+		if err != nil {
+			return nil, err
+		}
+		if len(records) == 0 {
+			return nil, fmt.Errorf("no forecasts recorded for %s; run 'forecast_monte_carlo' first", sourceID)
+		}
+		current = records[len(records)-1]
+	default:
+		return nil, fmt.Errorf("project_key is required unless current_id is set")
+	}
+
+	var baseline ForecastRecord
+	if baselineID != "" {
+		if baseline, err = s.findForecast(baselineID); err != nil {
+			return nil, err
+		}
+	} else if baseline, err = s.previousForecast(current, baselineDate); err != nil {
+		return nil, err
+	}
+	if baseline.ID == current.ID {
+		return nil, fmt.Errorf("baseline and current are the same forecast %s", current.ID)
+	}
+	if baseline.Inputs.Mode != current.Inputs.Mode || baseline.Inputs.TimeUnit != current.Inputs.TimeUnit {
+		return nil, fmt.Errorf("cannot compare a %s forecast in %ss (%s) with a %s forecast in %ss (%s)",
+			baseline.Inputs.Mode, baseline.Inputs.TimeUnit, baseline.ID, current.Inputs.Mode, current.Inputs.TimeUnit, current.ID)
+	}
+
+	res := compareForecasts(baseline, current)
+	warnings := forecastComparabilityWarnings(baseline, current)
+	insights := forecastDriftInsights(res)
+	guidance := append(insights, s.guidanceFor("compare_forecasts", guidanceFacts{})...)
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
+
+// previousForecast returns the run of current's source recorded before it,
+// or, with a date, the latest such run made on or before that date.
+func (s *Server) previousForecast(current ForecastRecord, date string) (ForecastRecord, error) {
+	if date != "" {
+		if _, err := time.Parse(stats.DateFormat, date); err != nil {
+			return ForecastRecord{}, fmt.Errorf("invalid baseline_date %q: expected YYYY-MM-DD", date)
+		}
+	}
+	records, err := s.loadForecasts(current.SourceID)
+	if err != nil {
+		return ForecastRecord{}, err
+	}
+	idx := slices.IndexFunc(records, func(r ForecastRecord) bool { return r.ID == current.ID })
+	for i := idx - 1; i >= 0; i-- {
+		if date == "" || records[i].AsOf <= date {
+			return records[i], nil
+		}
+	}
+	if date != "" {
+		return ForecastRecord{}, fmt.Errorf("no forecast of %s recorded on or before %s", current.SourceID, date)
+	}
+	return ForecastRecord{}, fmt.Errorf("no forecast of %s recorded before %s; run 'forecast_monte_carlo' again later to compare", current.SourceID, current.ID)
+}
+
+func compareForecasts(baseline, current ForecastRecord) ForecastComparison {
+	res := ForecastComparison{
+		Baseline: baseline,
+		Current:  current,
+		Drift: map[string]PercentileDrift{
+			"p50": percentileDrift(baseline, current, baseline.Percentiles.CoinToss, current.Percentiles.CoinToss),
+			"p85": percentileDrift(baseline, current, baseline.Percentiles.Likely, current.Percentiles.Likely),
+		},
+		Throughput: map[string]ValueChange{
+			"overall":         newValueChange(baseline.Sample.ThroughputOverall, current.Sample.ThroughputOverall),
+			"recent":          newValueChange(baseline.Sample.ThroughputRecent, current.Sample.ThroughputRecent),
+			"issues_analyzed": newValueChange(float64(baseline.Sample.IssuesAnalyzed), float64(current.Sample.IssuesAnalyzed)),
+		},
+	}
+
+	if baseline.Composition != nil && current.Composition != nil {
+		b, c := baseline.Composition, current.Composition
+		res.Composition = map[string]ValueChange{
+			"existing_backlog": newValueChange(float64(b.ExistingBacklog), float64(c.ExistingBacklog)),
+			"wip":              newValueChange(float64(b.WIP), float64(c.WIP)),
+			"additional_items": newValueChange(float64(b.AdditionalItems), float64(c.AdditionalItems)),
+			"total":            newValueChange(float64(b.Total), float64(c.Total)),
+		}
+	}
+
+	for _, t := range unionKeys(baseline.Inputs.Targets, current.Inputs.Targets) {
+		if b, c := baseline.Inputs.Targets[t], current.Inputs.Targets[t]; b != c {
+			if res.Targets == nil {
+				res.Targets = make(map[string]ValueChange)
+			}
+			res.Targets[t] = newValueChange(float64(b), float64(c))
+		}
+	}
+	for _, t := range unionKeys(baseline.Sample.TypeDistribution, current.Sample.TypeDistribution) {
+		if res.TypeMix == nil {
+			res.TypeMix = make(map[string]ValueChange)
+		}
+		res.TypeMix[t] = newValueChange(baseline.Sample.TypeDistribution[t], current.Sample.TypeDistribution[t])
+	}
+	return res
+}
+
+func percentileDrift(baseline, current ForecastRecord, b, c float64) PercentileDrift {
+	d := PercentileDrift{ValueChange: newValueChange(b, c)}
+	if b != 0 {
+		pct := stats.Round2((c - b) / b * 100)
+		d.ChangePct = &pct
+	}
+	if current.Inputs.Mode == "duration" && current.Inputs.TimeUnit == "day" {
+		bStart, errB := time.Parse(stats.DateFormat, baseline.AsOf)
+		cStart, errC := time.Parse(stats.DateFormat, current.AsOf)
+		if errB == nil && errC == nil {
+			bDate := bStart.AddDate(0, 0, int(math.Ceil(b)))
+			cDate := cStart.AddDate(0, 0, int(math.Ceil(c)))
+			shift := int(cDate.Sub(bDate).Hours() / 24)
+			d.BaselineDate = bDate.Format(stats.DateFormat)
+			d.CurrentDate = cDate.Format(stats.DateFormat)
+			d.DateShiftDays = &shift
+		}
+	}
+	return d
+}
+
+// forecastComparabilityWarnings flags input differences that explain part of
+// the drift, so it is not read as a change in delivery capability alone.
+func forecastComparabilityWarnings(baseline, current ForecastRecord) []string {
+	var warnings []string
+	if baseline.SourceID != current.SourceID {
+		warnings = append(warnings, fmt.Sprintf("The runs forecast different sources (%s vs %s).", baseline.SourceID, current.SourceID))
+	}
+	if baseline.Inputs.Mode == "scope" && baseline.Inputs.TargetDays != current.Inputs.TargetDays {
+		warnings = append(warnings, fmt.Sprintf("The runs forecast different horizons (%d vs %d days); scope drift partly reflects the horizon change.", baseline.Inputs.TargetDays, current.Inputs.TargetDays))
+	}
+	if baseline.Inputs.TargetSprints != current.Inputs.TargetSprints {
+		warnings = append(warnings, fmt.Sprintf("The runs forecast different horizons (%d vs %d sprints).", baseline.Inputs.TargetSprints, current.Inputs.TargetSprints))
+	}
+	if !slices.Equal(slices.Sorted(slices.Values(baseline.Inputs.IssueTypes)), slices.Sorted(slices.Values(current.Inputs.IssueTypes))) {
+		warnings = append(warnings, fmt.Sprintf("The runs use different issue type filters (%v vs %v).", baseline.Inputs.IssueTypes, current.Inputs.IssueTypes))
+	}
+	if !slices.Equal(baseline.Inputs.Sources, current.Inputs.Sources) {
+		warnings = append(warnings, fmt.Sprintf("The runs use different portfolio boards (%v vs %v).", baseline.Inputs.Sources, current.Inputs.Sources))
+	}
+	if len(current.Inputs.Sources) > 0 && baseline.Inputs.Duplicates != current.Inputs.Duplicates {
+		warnings = append(warnings, fmt.Sprintf("The runs attribute issues shared by several boards differently (duplicates=%s vs %s).", baseline.Inputs.Duplicates, current.Inputs.Duplicates))
+	}
+	if baseline.Engine != current.Engine {
+		warnings = append(warnings, fmt.Sprintf("The runs used different forecast engines (%s vs %s).", baseline.Engine, current.Engine))
+	}
+	if baseline.Inputs.Sampling != current.Inputs.Sampling {
+		warnings = append(warnings, fmt.Sprintf("The runs used different throughput sampling (%s vs %s).", samplingLabel(baseline.Inputs.Sampling), samplingLabel(current.Inputs.Sampling)))
+	}
+	if b, c := capacityLabel(baseline.Inputs), capacityLabel(current.Inputs); b != c {
+		warnings = append(warnings, fmt.Sprintf("The runs used different capacity scenarios (%s vs %s).", b, c))
+	}
+	return warnings
+}
+
+func capacityLabel(in ForecastInputs) string {
+	var parts []string
+	if in.CapacityFactor != 0 {
+		parts = append(parts, fmt.Sprintf("capacity %.2f", in.CapacityFactor))
+	}
+	if tc := in.TeamChange; tc != nil {
+		parts = append(parts, fmt.Sprintf("team %d → %d from %s", tc.From, tc.To, tc.EffectiveDate))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+func samplingLabel(sampling string) string {
+	if sampling == "" {
+		return simulation.SamplingEmpirical
+	}
+	return sampling
+}
+
+// forecastDriftInsights summarizes the drift in plain sentences.
+func forecastDriftInsights(c ForecastComparison) []string {
+	unit := "days"
+	switch {
+	case c.Current.Inputs.TimeUnit == "sprint":
+		unit = "sprints"
+	case c.Current.Inputs.Mode == "scope":
+		unit = "items"
+	}
+
+	var insights []string
+	for _, p := range []string{"p50", "p85"} {
+		d := c.Drift[p]
+		msg := fmt.Sprintf("%s moved from %.4g to %.4g %s", p, d.Baseline, d.Current, unit)
+		if d.ChangePct != nil {
+			msg += fmt.Sprintf(" (%+.0f%%)", *d.ChangePct)
+		}
+		if d.DateShiftDays != nil {
+			msg += fmt.Sprintf("; projected completion %s → %s (%+d days)", d.BaselineDate, d.CurrentDate, *d.DateShiftDays)
+		}
+		insights = append(insights, msg+".")
+	}
+	if total, ok := c.Composition["total"]; ok && total.Change != 0 {
+		insights = append(insights, fmt.Sprintf("Forecast scope changed from %.0f to %.0f items (%+.0f).", total.Baseline, total.Current, total.Change))
+	}
+	if tp := c.Throughput["overall"]; tp.Change != 0 {
+		insights = append(insights, fmt.Sprintf("Sampled throughput changed from %.2f to %.2f items/day.", tp.Baseline, tp.Current))
+	}
+	return insights
+}
+
+// unionKeys returns the sorted keys present in either map.
+func unionKeys[V any](a, b map[string]V) []string {
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
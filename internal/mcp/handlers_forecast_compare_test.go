@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCompareForecasts(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	forecast := func(mode SimulationMode, targets map[string]int, targetDays int) {
+		t.Helper()
+		if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:        testProject,
+			BoardID:           testBoard,
+			Mode:              mode,
+			TargetDays:        targetDays,
+			HistoryWindowDays: 90,
+			Targets:           targets,
+		}); err != nil {
+			t.Fatalf("forecast_monte_carlo %s: %v", mode, err)
+		}
+	}
+
+	if _, err := srv.handleCompareForecasts(testProject, testBoard, "", "", ""); err == nil {
+		t.Fatalf("Expected an error before any forecast is recorded")
+	}
+	forecast("duration", map[string]int{"Story": 10}, 0)
+	if _, err := srv.handleCompareForecasts(testProject, testBoard, "", "", ""); err == nil {
+		t.Errorf("Expected an error with a single recorded forecast")
+	}
+	forecast("duration", map[string]int{"Story": 30}, 0)
+
+	res, err := srv.handleCompareForecasts(testProject, testBoard, "", "", "")
+	if err != nil {
+		t.Fatalf("compare_forecasts: %v", err)
+	}
+	cmp := res.(ResponseEnvelope).Data.(ForecastComparison)
+	if cmp.Baseline.ID != "MCSTEST_0-F1" || cmp.Current.ID != "MCSTEST_0-F2" {
+		t.Fatalf("Expected F1 compared with F2, got %s and %s", cmp.Baseline.ID, cmp.Current.ID)
+	}
+	if story := cmp.Targets["Story"]; story.Baseline != 10 || story.Current != 30 || story.Change != 20 {
+		t.Errorf("Expected the Story target to grow by 20, got %+v", story)
+	}
+	p85 := cmp.Drift["p85"]
+	if p85.Change <= 0 || p85.DateShiftDays == nil || *p85.DateShiftDays <= 0 {
+		t.Errorf("Expected a larger scope to push P85 later, got %+v", p85)
+	}
+	if cmp.Current.Sample.IssuesAnalyzed == 0 || cmp.Current.Inputs.SampleStart == "" {
+		t.Errorf("Expected the histogram metadata and sample window to be recorded, got %+v", cmp.Current)
+	}
+
+	// A scope run cannot be compared with the duration run before it.
+	forecast("scope", nil, 30)
+	if _, err := srv.handleCompareForecasts(testProject, testBoard, "", "", ""); err == nil {
+		t.Errorf("Expected an error comparing a scope forecast with a duration forecast")
+	}
+	if _, err := srv.handleCompareForecasts("", 0, "MCSTEST_0-F1", "MCSTEST_0-F2", ""); err != nil {
+		t.Errorf("compare by ID: %v", err)
+	}
+	if _, err := srv.handleCompareForecasts("", 0, "", "MCSTEST_0-F2", "2000-01-01"); err == nil {
+		t.Errorf("Expected an error when no forecast precedes baseline_date")
+	}
+	if _, err := srv.handleCompareForecasts("", 0, "", "not-an-id", ""); err == nil {
+		t.Errorf("Expected an error for an invalid forecast ID")
+	}
+}
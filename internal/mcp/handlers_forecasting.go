@@ -1,7 +1,11 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/rs/zerolog/log"
@@ -10,40 +14,130 @@ import (
 	"mcs-mcp/internal/stats"
 )
 
+// handleForecastMonteCarlo is forecast_monte_carlo: it applies the template
+// the call names and runs the portfolio forecast when sources are set, else
+// the forecast of the board.
+func (s *Server) handleForecastMonteCarlo(ctx context.Context, args ForecastMonteCarloInput) (any, error) {
+	args, err := s.applyForecastTemplate(args)
+	if err != nil {
+		return nil, err
+	}
+	if len(args.Sources) > 0 {
+		if args.SprintMode || args.StartStatus != "" || args.ToRelease || args.ModelArrivals || args.FixVersion != "" {
+			return nil, fmt.Errorf("sprint_mode, start_status, to_release, model_arrivals, and fix_version are board-specific and cannot be combined with sources")
+		}
+		if len(args.Labels) > 0 || len(args.Components) > 0 {
+			return nil, errSubTeamSources
+		}
+		if args.Units == UnitsPoints {
+			return nil, fmt.Errorf("units=points cannot be combined with sources")
+		}
+		if args.DryRun {
+			return nil, fmt.Errorf("dry_run previews single-board forecasts and cannot be combined with sources")
+		}
+		if args.Explain {
+			return nil, fmt.Errorf("explain reruns single-board forecasts and cannot be combined with sources")
+		}
+		if args.Ensemble {
+			return nil, fmt.Errorf("ensemble samples the cycle times of one board and cannot be combined with sources")
+		}
+		if args.Stratification != "" || args.MinStratumSize != 0 {
+			return nil, fmt.Errorf("stratification and stratification_min_sample cannot be combined with sources")
+		}
+		if args.Granularity != "" {
+			return nil, fmt.Errorf("sampling_granularity cannot be combined with sources")
+		}
+		data, err := s.handlePortfolioSimulation(ctx, args)
+		return withTemplateContext(data, args.Template), err
+	}
+	data, err := s.handleRunSimulation(ctx, args)
+	return withTemplateContext(data, args.Template), err
+}
+
 // handleRunSimulation does NOT use prepareHandler. Forecasting needs the full
 // jira.SourceContext after hydration to build a simulation.ForecastRequest, and
 // it manages its own sampling window (independent of the session analysis
 // window). Keep the inline anchor/hydrate/save sequence here on purpose.
-func (s *Server) handleRunSimulation(projectKey string, boardID int, mode string, includeExistingBacklog bool, additionalItems int, targetDays int, targetDate string, startStatus string, issueTypes []string, includeWIP bool, sampleDays int, sampleStartDate, sampleEndDate string, targets map[string]int, mixOverrides map[string]float64) (any, error) {
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+// args is the forecast_monte_carlo input after applyForecastTemplate; its
+// sources are handled by handleForecastMonteCarlo, the query and outcome
+// options by the tool middleware (withToolOptions).
+func (s *Server) handleRunSimulation(ctx context.Context, args ForecastMonteCarloInput) (any, error) {
+	mode, sampling, stratification := string(args.Mode), string(args.Sampling), string(args.Stratification)
+	issueTypes, startStatus := args.IssueTypes, args.StartStatus
+	sc, err := s.resolveSourceContext(ctx, args.ProjectKey, args.BoardID)
 	if err != nil {
 		return nil, err
 	}
-	sourceID := getCombinedID(projectKey, boardID)
+	sourceID := getCombinedID(args.ProjectKey, args.BoardID)
 
 	// Ensure we are anchored before analysis
-	if err := s.anchorContext(projectKey, boardID); err != nil {
+	if err := s.anchorContext(args.ProjectKey, args.BoardID); err != nil {
 		return nil, err
 	}
+	if len(args.Targets) == 0 {
+		issueTypes = s.forecastIssueTypes(issueTypes)
+	}
 
-	// 1. Determine Sampling Window
-	histEnd := s.Clock()
-	if sampleEndDate != "" {
-		if t, err := time.Parse(stats.DateFormat, sampleEndDate); err == nil {
-			histEnd = t
-		} else {
-			return nil, fmt.Errorf("invalid sample_end_date format: %w", err)
-		}
+	if err := validateSampling(sampling); err != nil {
+		return nil, err
 	}
-	histStart := histEnd.AddDate(0, 0, -DefaultForecastSampleDays) // Default 90 days
-	if sampleStartDate != "" {
-		if t, err := time.Parse(stats.DateFormat, sampleStartDate); err == nil {
-			histStart = t
-		} else {
-			return nil, fmt.Errorf("invalid sample_start_date format: %w", err)
-		}
-	} else if sampleDays > 0 {
-		histStart = histEnd.AddDate(0, 0, -sampleDays)
+	if args.ModelArrivals && (mode != "duration" || args.SprintMode) {
+		return nil, fmt.Errorf("model_arrivals applies to day-based duration forecasts only")
+	}
+	pointsMode, err := validateUnits(string(args.Units))
+	if err != nil {
+		return nil, err
+	}
+	if pointsMode && (args.SprintMode || args.ModelArrivals || len(args.Targets) > 0 || len(args.MixOverrides) > 0) {
+		return nil, fmt.Errorf("units=points forecasts the estimates of the backlog, WIP, and additional_items by day; it cannot be combined with sprint_mode, model_arrivals, targets, or mix_overrides")
+	}
+	if args.DryRun && (args.SprintMode || pointsMode) {
+		return nil, fmt.Errorf("dry_run previews day-based item forecasts; it cannot be combined with sprint_mode or units=points")
+	}
+	if args.SprintMode && callAsOfDate(ctx) != nil {
+		return nil, fmt.Errorf("as_of_date cannot be combined with sprint_mode: sprints are read from Jira as they are today")
+	}
+	if args.Explain && (args.SprintMode || pointsMode || args.DryRun) {
+		return nil, fmt.Errorf("explain applies to day-based item forecasts; it cannot be combined with sprint_mode, units=points, or dry_run")
+	}
+	if args.Ensemble && (mode != "duration" || args.SprintMode || pointsMode || args.DryRun) {
+		return nil, fmt.Errorf("ensemble compares day-based duration forecasts of items; it cannot be combined with scope mode, sprint_mode, units=points, or dry_run")
+	}
+	if args.WIPLimit < 0 || (args.WIPLimit > 0 && !args.Ensemble) {
+		return nil, fmt.Errorf("wip_limit must be positive and applies to ensemble forecasts only")
+	}
+	if err := validateStratification(stratification, args.MinStratumSize); err != nil {
+		return nil, err
+	}
+	if (stratification != "" || args.MinStratumSize != 0) && (args.SprintMode || pointsMode) {
+		return nil, fmt.Errorf("stratification applies to day-based item forecasts; it cannot be combined with sprint_mode or units=points")
+	}
+	if stratification == simulation.StratificationOn && sampling == simulation.SamplingParametric {
+		return nil, fmt.Errorf("stratification 'on' cannot be combined with sampling=parametric, which samples one pooled stream")
+	}
+	granularity, err := simulation.ParseGranularity(string(args.Granularity))
+	if err != nil {
+		return nil, err
+	}
+	if granularity == simulation.GranularityWeek && (args.SprintMode || pointsMode || sampling == simulation.SamplingParametric) {
+		return nil, fmt.Errorf("sampling_granularity=week samples whole weeks of observed days; it cannot be combined with sprint_mode, units=points, or sampling=parametric")
+	}
+	deadlineDays, err := s.resolveDeadline(ctx, mode, args.TargetDays, args.TargetDate)
+	if err != nil {
+		return nil, err
+	}
+	if deadlineDays > 0 && (args.SprintMode || pointsMode) {
+		return nil, fmt.Errorf("a target date in duration mode checks day-based item forecasts; it cannot be combined with sprint_mode or units=points")
+	}
+	capacity, err := capacityScenario(args.CapacityFactor, args.TeamChange)
+	if err != nil {
+		return nil, err
+	}
+
+	// 1. Determine Sampling Window
+	histStart, histEnd, err := s.forecastSampleWindow(ctx, args.HistoryWindowDays, args.HistoryStartDate, args.HistoryEndDate, args.SprintMode)
+	if err != nil {
+		return nil, err
 	}
 
 	cutoff := time.Time{}
@@ -51,74 +145,68 @@ func (s *Server) handleRunSimulation(projectKey string, boardID int, mode string
 		cutoff = *s.activeDiscoveryCutoff
 	}
 
+	calendar, err := s.resolveCalendar(args.Holidays, args.FreezePeriods)
+	if err != nil {
+		return nil, err
+	}
+
 	// 2. Hydrate
-	reg, err := s.events.Hydrate(sourceID, projectKey, ctx.JQL, s.activeRegistry)
+	reg, err := s.events.Hydrate(ctx, sourceID, args.ProjectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		return nil, err
 	}
 	s.activeRegistry = reg
-	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+	if err := s.saveWorkflow(args.ProjectKey, args.BoardID); err != nil {
 		log.Warn().Err(err).Msg("Failed to persist workflow metadata to disk")
 	}
 
 	// 3. Project using AnalysisSession
 	window := stats.NewAnalysisWindow(histStart, histEnd, "day", cutoff)
-	events := s.events.GetIssuesInRange(sourceID, window.Start, window.End)
-	session := stats.NewAnalysisSession(events, sourceID, *ctx, s.activeMapping, s.activeResolutions, window)
+	session := s.openSession(ctx, &handlerContext{SourceID: sourceID, Ctx: sc}, window)
 
 	all := session.GetAllIssues()
 	wip := session.GetWIP()
 	finished := session.GetFinished()
 
-	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+	analysisCtx := s.prepareAnalysisContext(args.ProjectKey, args.BoardID, all)
 	if startStatus == "" {
 		startStatus = analysisCtx.CommitmentPoint
 	}
 
-	// Apply Backflow Policy weight
-	cWeight := 2
-	if startStatus != "" {
-		if w, ok := analysisCtx.StatusWeights[startStatus]; ok {
-			cWeight = w
-		}
-	}
-
 	actualTargets := make(map[string]int)
 	var backlogCount, wipCount int
+	var scope []jira.Issue // Backlog and WIP items to forecast, for units=points
 
-	if len(targets) > 0 {
-		for k, v := range targets {
+	if len(args.Targets) > 0 {
+		for k, v := range args.Targets {
 			actualTargets[k] = v
 		}
 	} else {
-		if includeExistingBacklog {
-			// Backlog items (Demand + Upstream)
-			for _, issue := range all {
-				if m, ok := s.activeMapping[issue.StatusID]; ok && (m.Tier == "Demand" || m.Tier == "Upstream") {
-					actualTargets[issue.IssueType]++
-					backlogCount++
-				}
-			}
-		}
+		actualTargets, backlogCount, wipCount, scope = s.forecastScope(ctx, all, wip, analysisCtx, startStatus, args.IncludeExistingBacklog, args.IncludeWIP)
+		addAdditionalItems(actualTargets, issueTypes, args.AdditionalItems)
+	}
 
-		if includeWIP {
-			wipIssues := wip
-			if s.commitmentBackflowReset {
-				wipIssues = stats.ApplyBackflowPolicy(wip, analysisCtx.StatusWeights, cWeight, s.Clock())
-			}
-			for _, issue := range wipIssues {
-				actualTargets[issue.IssueType]++
-				wipCount++
-			}
-		}
+	inputs := ForecastInputs{
+		Mode:                   mode,
+		TimeUnit:               "day",
+		Targets:                actualTargets,
+		IssueTypes:             issueTypes,
+		IncludeExistingBacklog: args.IncludeExistingBacklog,
+		IncludeWIP:             args.IncludeWIP,
+		AdditionalItems:        args.AdditionalItems,
+		StartStatus:            startStatus,
+		MixOverrides:           args.MixOverrides,
+		Sampling:               sampling,
+		ModelArrivals:          args.ModelArrivals,
+		CapacityFactor:         args.CapacityFactor,
+		TeamChange:             args.TeamChange,
+		SampleStart:            histStart.Format(stats.DateFormat),
+		SampleEnd:              histEnd.Format(stats.DateFormat),
+	}
 
-		if additionalItems > 0 {
-			if len(issueTypes) == 1 {
-				actualTargets[issueTypes[0]] += additionalItems
-			} else {
-				actualTargets["Unknown"] += additionalItems
-			}
-		}
+	if args.SprintMode {
+		comp := simulation.Composition{ExistingBacklog: backlogCount, WIP: wipCount, AdditionalItems: args.AdditionalItems}
+		return s.runSprintForecast(ctx, args.ProjectKey, args.BoardID, mode, actualTargets, comp, args.TargetSprints, issueTypes, finished, histStart, all, inputs)
 	}
 
 	// 4. Stationarity assessment via residence time analysis
@@ -137,13 +225,9 @@ func (s *Server) handleRunSimulation(projectKey string, boardID int, mode string
 		Msg("tool executed")
 
 	// Resolve target days for scope mode
-	finalTargetDays := targetDays
-	if mode == "scope" && targetDate != "" {
-		t, err := time.Parse(stats.DateFormat, targetDate)
-		if err != nil {
-			return nil, fmt.Errorf("invalid target_date format: %w", err)
-		}
-		finalTargetDays = stats.CalendarDaysBetween(s.Clock(), t)
+	finalTargetDays, err := s.resolveTargetDays(ctx, mode, args.TargetDays, args.TargetDate)
+	if err != nil {
+		return nil, err
 	}
 
 	// Build ForecastRequest
@@ -156,60 +240,449 @@ func (s *Server) handleRunSimulation(projectKey string, boardID int, mode string
 		WindowEnd:        window.End,
 		DiscoveryCutoff:  cutoff,
 		Targets:          actualTargets,
-		MixOverrides:     mixOverrides,
+		MixOverrides:     args.MixOverrides,
+		ModelArrivals:    args.ModelArrivals,
 		TargetDays:       finalTargetDays,
+		DeadlineDays:     deadlineDays,
+		Calendar:         calendar,
+		Sampling:         sampling,
+		Capacity:         capacity,
+		Outliers:         s.outliers(ctx),
+		Outcomes:         s.outcomes(ctx),
+		External:         s.externalThroughput(sourceID, histStart, histEnd),
+		Stratification:   stratification,
+		MinStratumSize:   args.MinStratumSize,
+		Granularity:      granularity,
+
 		IssueTypes:       issueTypes,
+		TypeAliases:      s.activeTypeAliases,
 		CommitmentPoint:  analysisCtx.CommitmentPoint,
 		StatusWeights:    analysisCtx.StatusWeights,
 		WorkflowMappings: analysisCtx.WorkflowMappings,
 		Resolutions:      s.activeResolutions,
 		SimulationSeed:   s.simulationSeed,
-		Clock:            s.Clock(),
+		Clock:            s.Clock(ctx),
+	}
+
+	if args.DryRun {
+		comp := simulation.Composition{ExistingBacklog: backlogCount, WIP: wipCount, AdditionalItems: args.AdditionalItems, Total: backlogCount + wipCount + args.AdditionalItems}
+		return s.previewSimulation(ctx, args.ProjectKey, args.BoardID, req, comp, inputs, all), nil
 	}
 
 	// Resolve engine
-	selectedEngine, err := s.resolveEngine(req)
+	selectedEngine, err := s.resolveEngine(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("engine resolution failed: %w", err)
 	}
 
-	resObj, err := selectedEngine.Run(req)
+	if args.Explain && req.SimulationSeed == 0 {
+		// The drivers rerun the forecast; a shared seed isolates their effect.
+		req.SimulationSeed = time.Now().UnixNano()
+	}
+	resObj, err := selectedEngine.Run(ctx, req)
 	if err != nil {
 		return nil, fmt.Errorf("simulation failed: %w", err)
 	}
+	if args.Explain {
+		variants := simulation.ThroughputVariants(req)
+		if v, ok := simulation.ResolutionVariant(req); ok {
+			variants = append(variants, v)
+		}
+		if mode == "duration" && len(args.Targets) == 0 && args.IncludeWIP {
+			if v, ok := s.backflowVariant(ctx, req, all, wip, analysisCtx, startStatus, args.IncludeExistingBacklog, issueTypes, args.AdditionalItems, wipCount); ok {
+				variants = append(variants, v)
+			}
+		}
+		resObj.Explanation, err = simulation.ExplainForecast(ctx, selectedEngine, mode, resObj.Percentiles, variants)
+		if err != nil {
+			return nil, fmt.Errorf("explain failed: %w", err)
+		}
+		if d, ok := resObj.Explanation.DominantDriver(); ok {
+			resObj.Insights = append(resObj.Insights, fmt.Sprintf("Largest driver (%s): %s", d.Driver, d.Summary))
+		}
+	}
+	if args.Ensemble {
+		if err := s.ensembleForecast(ctx, args.ProjectKey, args.BoardID, req, &resObj, scope[min(backlogCount, len(scope)):], analysisCtx, args.WIPLimit); err != nil {
+			return nil, err
+		}
+	}
+	if pointsMode {
+		resObj, err = s.pointsForecast(ctx, req, resObj, scope, args.AdditionalItems, args.BoardID)
+		if err != nil {
+			return nil, err
+		}
+		inputs.Units = string(UnitsPoints)
+	}
 
 	// Post-processing (shared across all engines)
+	if args.ToRelease {
+		s.applyReleaseLag(&resObj, mode, finished, args.ReleaseStatus)
+	}
 	resObj.Round()
 	resObj.Insights = s.addCommitmentInsights(resObj.Insights, analysisCtx, startStatus)
-	resObj.Warnings = append(resObj.Warnings, s.getQualityWarnings(all)...)
+	resObj.Warnings = append(resObj.Warnings, s.getQualityWarnings(ctx, all)...)
 	s.applyStationarity(&resObj, stationarityAssessment)
 	resObj.Composition = &simulation.Composition{
 		ExistingBacklog: backlogCount,
 		WIP:             wipCount,
-		AdditionalItems: additionalItems,
-		Total:           backlogCount + wipCount + additionalItems,
+		AdditionalItems: args.AdditionalItems,
+		Total:           backlogCount + wipCount + args.AdditionalItems,
+	}
+
+	s.annotateForecast(ctx, &resObj, mode, finalTargetDays, calendar)
+	annotateCapacity(&resObj, inputs)
+	if mode == "scope" {
+		inputs.TargetDays = finalTargetDays
+	}
+	s.trackForecast(ctx, sourceID, selectedEngine.Name(), inputs, &resObj)
+
+	warnings := resObj.Warnings
+	insights := append(resObj.Insights, s.guidanceFor("forecast_monte_carlo", guidanceFacts{FatTailRatio: resObj.FatTailRatio, SparseSample: sparseEmpiricalSample(resObj.Context), Explained: resObj.Explanation != nil})...)
+	resObj.Warnings = nil
+	resObj.Insights = nil
+
+	return WrapResponse(resObj, args.ProjectKey, args.BoardID, nil, warnings, insights), nil
+}
+
+// forecastScope counts the existing backlog (Demand and Upstream) and WIP
+// items a forecast includes, per type. WIP that moved back before its
+// commitment point is dropped when COMMITMENT_POINT_BACKFLOW_RESET_CLOCK is set.
+func (s *Server) forecastScope(ctx context.Context, all, wip []jira.Issue, analysisCtx *AnalysisContext, startStatus string, includeExistingBacklog, includeWIP bool) (targets map[string]int, backlogCount, wipCount int, scope []jira.Issue) {
+	return s.forecastScopeWith(ctx, all, wip, analysisCtx, startStatus, includeExistingBacklog, includeWIP, s.commitmentBackflowReset)
+}
+
+// forecastScopeWith is forecastScope under the given backflow policy.
+func (s *Server) forecastScopeWith(ctx context.Context, all, wip []jira.Issue, analysisCtx *AnalysisContext, startStatus string, includeExistingBacklog, includeWIP, backflowReset bool) (targets map[string]int, backlogCount, wipCount int, scope []jira.Issue) {
+	// Apply Backflow Policy weight per commitment point. Per-type overrides only
+	// apply when no explicit start status was requested.
+	commitments := analysisCtx.Commitments()
+	if startStatus != analysisCtx.CommitmentPoint {
+		commitments = stats.CommitmentPoints{Default: startStatus}
+	}
+	commitmentWeight := func(status string) int {
+		if status != "" {
+			if w, ok := analysisCtx.StatusWeights[status]; ok {
+				return w
+			}
+		}
+		return 2
+	}
+
+	targets = make(map[string]int)
+	if includeExistingBacklog {
+		// Backlog items (Demand + Upstream); containers group the backlog items
+		// below them and are left out under the container policy.
+		containers := s.excludedContainers(ctx, all)
+		for _, issue := range all {
+			if containers[issue.Key] {
+				continue
+			}
+			if m, ok := s.activeMapping[issue.StatusID]; ok && (m.Tier == "Demand" || m.Tier == "Upstream") {
+				targets[issue.IssueType]++
+				backlogCount++
+				scope = append(scope, issue)
+			}
+		}
+	}
+
+	if includeWIP {
+		wipIssues := wip
+		if backflowReset {
+			wipIssues = nil
+			keys, groups := commitments.Group(wip)
+			for _, cp := range keys {
+				wipIssues = append(wipIssues, stats.ApplyBackflowPolicy(groups[cp], analysisCtx.StatusWeights, commitmentWeight(cp), s.Clock(ctx))...)
+			}
+		}
+		for _, issue := range wipIssues {
+			targets[issue.IssueType]++
+			wipCount++
+			scope = append(scope, issue)
+		}
+	}
+	return targets, backlogCount, wipCount, scope
+}
+
+// backflowVariant returns the explain variant forecasting the scope under the
+// opposite backflow policy (COMMITMENT_POINT_BACKFLOW_RESET_CLOCK). ok is
+// false when the policy does not change the WIP in scope.
+func (s *Server) backflowVariant(ctx context.Context, req simulation.ForecastRequest, all, wip []jira.Issue, analysisCtx *AnalysisContext, startStatus string, includeExistingBacklog bool, issueTypes []string, additionalItems, wipCount int) (simulation.ExplainVariant, bool) {
+	targets, _, altWIP, _ := s.forecastScopeWith(ctx, all, wip, analysisCtx, startStatus, includeExistingBacklog, true, !s.commitmentBackflowReset)
+	if altWIP == wipCount {
+		return simulation.ExplainVariant{}, false
+	}
+	addAdditionalItems(targets, issueTypes, additionalItems)
+	req.Targets = targets
+	variation := fmt.Sprintf("backflow reset off, counting %d WIP item(s) that moved back before the commitment point", altWIP-wipCount)
+	if !s.commitmentBackflowReset {
+		variation = fmt.Sprintf("backflow reset on, dropping %d WIP item(s) that moved back before the commitment point", wipCount-altWIP)
+	}
+	return simulation.ExplainVariant{Driver: "backflow_policy", Variation: variation, Req: req}, true
+}
+
+// addAdditionalItems adds items not yet in Jira to the targets: as the one
+// requested issue type, else as "Unknown".
+func addAdditionalItems(targets map[string]int, issueTypes []string, n int) {
+	if n <= 0 {
+		return
+	}
+	if len(issueTypes) == 1 {
+		targets[issueTypes[0]] += n
+	} else {
+		targets["Unknown"] += n
+	}
+}
+
+// ForecastPreview is the data of a forecast_monte_carlo dry run.
+type ForecastPreview struct {
+	Composition simulation.Composition  `json:"composition"`
+	DryRun      bool                    `json:"dry_run"`
+	Inputs      simulation.InputPreview `json:"inputs"`
+}
+
+// previewSimulation reports what handleRunSimulation would simulate for req:
+// the scope composition, the targets per type, and the throughput sample.
+// Nothing is simulated or recorded as a forecast run.
+func (s *Server) previewSimulation(ctx context.Context, projectKey string, boardID int, req simulation.ForecastRequest, comp simulation.Composition, inputs ForecastInputs, all []jira.Issue) ResponseEnvelope {
+	preview := simulation.PreviewInputs(req)
+	res := ForecastPreview{DryRun: true, Composition: comp, Inputs: preview}
+	warnings := append(preview.Warnings, s.getQualityWarnings(ctx, all)...)
+	insights := []string{
+		"DRY RUN: no trials were run. 'inputs.throughput' is the daily sample the engine would draw from; 'inputs.naive_days' divides the scope by its mean and is no forecast. Re-run without dry_run for percentiles.",
+	}
+	if req.Capacity != nil {
+		insights = append(insights, "The capacity scenario scales the sampled throughput during the trials; the preview shows the throughput as observed.")
 	}
+	envelope := WrapResponse(res, projectKey, boardID, nil, warnings, insights)
+	envelope.Context["engine"] = s.engineName
+	envelope.Context["sample_window"] = map[string]string{"start": inputs.SampleStart, "end": inputs.SampleEnd}
+	if req.Calendar != nil {
+		envelope.Context["calendar"] = req.Calendar.Summary()
+	}
+	return envelope
+}
 
+// validateUnits checks the units parameter and reports whether the forecast
+// is in estimate points.
+func validateUnits(units string) (bool, error) {
+	switch ForecastUnits(units) {
+	case "", UnitsItems:
+		return false, nil
+	case UnitsPoints:
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid units %q: must be 'items' or 'points'", units)
+}
+
+// forecastSampleWindow resolves the throughput sampling range of a forecast:
+// explicit dates win over a day count, which wins over the default lookback
+// (the forecast_sample_days source setting outside sprint mode).
+func (s *Server) forecastSampleWindow(ctx context.Context, sampleDays int, sampleStartDate, sampleEndDate string, sprintMode bool) (time.Time, time.Time, error) {
+	histEnd := s.Clock(ctx)
+	if sampleEndDate != "" {
+		t, err := time.Parse(stats.DateFormat, sampleEndDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid sample_end_date format: %w", err)
+		}
+		histEnd = t
+	}
+	histStart := histEnd.AddDate(0, 0, -DefaultForecastSampleDays) // Default 90 days
+	if s.activeSettings.ForecastSampleDays > 0 {
+		histStart = histEnd.AddDate(0, 0, -s.activeSettings.ForecastSampleDays)
+	}
+	if sprintMode {
+		histStart = histEnd.AddDate(0, 0, -DefaultSprintSampleDays)
+	}
+	if sampleStartDate != "" {
+		t, err := time.Parse(stats.DateFormat, sampleStartDate)
+		if err != nil {
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid sample_start_date format: %w", err)
+		}
+		histStart = t
+	} else if sampleDays > 0 {
+		histStart = histEnd.AddDate(0, 0, -sampleDays)
+	}
+	return histStart, histEnd, nil
+}
+
+// resolveTargetDays returns the scope-mode horizon in days, derived from
+// targetDate when one is given.
+func (s *Server) resolveTargetDays(ctx context.Context, mode string, targetDays int, targetDate string) (int, error) {
+	if mode != "scope" || targetDate == "" {
+		return targetDays, nil
+	}
+	t, err := time.Parse(stats.DateFormat, targetDate)
+	if err != nil {
+		return 0, fmt.Errorf("invalid target_date format: %w", err)
+	}
+	return stats.CalendarDaysBetween(s.Clock(ctx), t), nil
+}
+
+// resolveDeadline returns the days from the evaluation date to the target
+// date (or target_days) a duration forecast is checked against, 0 when
+// neither is given.
+func (s *Server) resolveDeadline(ctx context.Context, mode string, targetDays int, targetDate string) (int, error) {
+	if mode != "duration" || (targetDays == 0 && targetDate == "") {
+		return 0, nil
+	}
+	days := targetDays
+	if targetDate != "" {
+		t, err := time.Parse(stats.DateFormat, targetDate)
+		if err != nil {
+			return 0, fmt.Errorf("invalid target_date format: %w", err)
+		}
+		days = stats.CalendarDaysBetween(s.Clock(ctx), t)
+	}
+	if days <= 0 {
+		return 0, fmt.Errorf("the target date must lie after the evaluation date %s", s.Clock(ctx).Format(stats.DateFormat))
+	}
+	return days, nil
+}
+
+// deadlineInsight summarizes how a duration forecast fares against its
+// target date.
+func deadlineInsight(d *simulation.DeadlineOutcome, targetDate string) string {
+	if d.GapDaysP85 == 0 {
+		return fmt.Sprintf("TARGET DATE: %.0f%% of trials deliver the %d items by %s; the P85 date falls on or before it.", d.Probability*100, d.Items, targetDate)
+	}
+	return fmt.Sprintf("TARGET DATE: %.0f%% of trials deliver the %d items by %s. At P85 the forecast lands %d days later; drop %d items to deliver the rest by then with 85%% confidence.",
+		d.Probability*100, d.Items, targetDate, d.GapDaysP85, d.GapItemsP85)
+}
+
+// annotateForecast records the simulation mode, scope horizon or completion
+// dates (checked against the target date, if any), and the working calendar
+// in the result context.
+func (s *Server) annotateForecast(ctx context.Context, resObj *simulation.Result, mode string, targetDays int, calendar *simulation.Calendar) {
 	if resObj.Context == nil {
 		resObj.Context = make(map[string]any)
 	}
 	resObj.Context["simulation_mode"] = mode
 	if mode == "scope" {
-		resObj.Context["target_days"] = finalTargetDays
+		resObj.Context["target_days"] = targetDays
+	} else {
+		resObj.Context["completion_dates"] = simulation.CompletionDates(resObj.Percentiles, s.Clock(ctx))
+		if d := resObj.Deadline; d != nil {
+			targetDate := s.Clock(ctx).AddDate(0, 0, d.TargetDays).Format(stats.DateFormat)
+			resObj.Context["target_date"] = targetDate
+			resObj.Insights = append(resObj.Insights, deadlineInsight(d, targetDate))
+		}
+		if resObj.WithArrivals != nil {
+			resObj.WithArrivals.CompletionDates = simulation.CompletionDates(resObj.WithArrivals.Percentiles, s.Clock(ctx))
+		}
+	}
+	if calendar != nil {
+		resObj.Context["calendar"] = calendar.Summary()
+		resObj.Insights = append(resObj.Insights, "Working calendar applied: throughput is sampled from working days only, and non-working days (weekends, holidays, freeze periods) deliver nothing. Durations are calendar days.")
 	}
+}
 
-	warnings := resObj.Warnings
-	insights := resObj.Insights
-	resObj.Warnings = nil
-	resObj.Insights = nil
+// resolveCalendar merges per-call holidays and freeze periods into the
+// configured working calendar (Mon–Fri when none is configured). Returns nil
+// when neither is set, so every calendar day counts as a working day.
+func (s *Server) resolveCalendar(holidays, freezePeriods []string) (*simulation.Calendar, error) {
+	if s.calendar == nil && len(holidays) == 0 && len(freezePeriods) == 0 {
+		return nil, nil
+	}
+	cal := simulation.DefaultCalendar()
+	if s.calendar != nil {
+		cal = s.calendar.Clone()
+	}
+	if err := cal.AddHolidays(holidays); err != nil {
+		return nil, err
+	}
+	if err := cal.AddFreezes(freezePeriods); err != nil {
+		return nil, err
+	}
+	return cal, nil
+}
 
-	return WrapResponse(resObj, projectKey, boardID, nil, warnings, insights), nil
+// sparseEmpiricalSample reports whether a forecast bootstrapped fewer than
+// simulation.SparseSampleSize delivered items or sprints.
+func sparseEmpiricalSample(ctx map[string]any) bool {
+	if ctx["sampling"] == simulation.SamplingParametric {
+		return false
+	}
+	if n, ok := ctx["sprints_sampled"].(int); ok {
+		return n < simulation.SparseSampleSize
+	}
+	n, ok := ctx["issues_analyzed"].(int)
+	return ok && n < simulation.SparseSampleSize
+}
+
+// capacityScenario converts the capacity inputs of a forecast into the
+// engines' scenario. Returns nil when neither is set.
+func capacityScenario(capacityFactor float64, teamChange *TeamChange) (*simulation.CapacityScenario, error) {
+	if capacityFactor == 0 && teamChange == nil {
+		return nil, nil
+	}
+	if capacityFactor < 0 || capacityFactor > MaxCapacityFactor {
+		return nil, fmt.Errorf("invalid capacity_factor %g: must be a multiplier between 0 and %g (e.g. 0.7 for 70%% capacity)", capacityFactor, MaxCapacityFactor)
+	}
+	c := &simulation.CapacityScenario{Factor: capacityFactor}
+	if teamChange != nil {
+		if teamChange.From <= 0 || teamChange.To <= 0 {
+			return nil, fmt.Errorf("invalid team_change: from and to must be positive team sizes, got %d and %d", teamChange.From, teamChange.To)
+		}
+		date, err := time.Parse(stats.DateFormat, teamChange.EffectiveDate)
+		if err != nil {
+			return nil, fmt.Errorf("invalid team_change.effective_date %q: expected YYYY-MM-DD", teamChange.EffectiveDate)
+		}
+		c.ChangeFactor = float64(teamChange.To) / float64(teamChange.From)
+		c.ChangeDate = date
+	}
+	return c, nil
+}
+
+// annotateCapacity records the capacity scenario of a forecast, if any, in
+// the result context and explains it in the insights.
+func annotateCapacity(resObj *simulation.Result, inputs ForecastInputs) {
+	if inputs.CapacityFactor == 0 && inputs.TeamChange == nil {
+		return
+	}
+	if resObj.Context == nil {
+		resObj.Context = make(map[string]any)
+	}
+	scenario := make(map[string]any)
+	var parts []string
+	if inputs.CapacityFactor != 0 {
+		scenario["capacity_factor"] = inputs.CapacityFactor
+		parts = append(parts, fmt.Sprintf("%.2f throughout", inputs.CapacityFactor))
+	}
+	if tc := inputs.TeamChange; tc != nil {
+		scenario["team_change"] = tc
+		parts = append(parts, fmt.Sprintf("%d/%d from %s on (team of %d → %d)", tc.To, tc.From, tc.EffectiveDate, tc.From, tc.To))
+	}
+	resObj.Context["capacity_scenario"] = scenario
+	resObj.Insights = append(resObj.Insights, fmt.Sprintf("Capacity scenario: sampled throughput is scaled by %s. Throughput is assumed to scale linearly with capacity; onboarding time and changes in the work mix are not modeled.", strings.Join(parts, " and ")))
+}
+
+// validateSampling rejects unknown throughput sampling modes ("" is empirical).
+func validateSampling(sampling string) error {
+	switch sampling {
+	case "", simulation.SamplingEmpirical, simulation.SamplingParametric:
+		return nil
+	}
+	return fmt.Errorf("invalid sampling %q: must be '%s' or '%s'", sampling, simulation.SamplingEmpirical, simulation.SamplingParametric)
+}
+
+// validateStratification rejects unknown stratification modes ("" is auto)
+// and negative minimum samples (0 is the default).
+func validateStratification(mode string, minSample int) error {
+	switch mode {
+	case "", simulation.StratificationAuto, simulation.StratificationOn, simulation.StratificationOff:
+	default:
+		return fmt.Errorf("invalid stratification %q: must be '%s', '%s', or '%s'", mode, simulation.StratificationAuto, simulation.StratificationOn, simulation.StratificationOff)
+	}
+	if minSample < 0 {
+		return fmt.Errorf("stratification_min_sample must not be negative")
+	}
+	return nil
 }
 
 // resolveEngine returns the engine to use for a given forecast request.
 // For "auto" mode, it runs a walk-forward backtest with all enabled engines
 // and selects the best one. For named engines, it does a direct lookup.
-func (s *Server) resolveEngine(req simulation.ForecastRequest) (simulation.ForecastEngine, error) {
+func (s *Server) resolveEngine(ctx context.Context, req simulation.ForecastRequest) (simulation.ForecastEngine, error) {
 	if s.engineName != "auto" {
 		return s.engineRegistry.Get(s.engineName)
 	}
@@ -224,7 +697,7 @@ func (s *Server) resolveEngine(req simulation.ForecastRequest) (simulation.Forec
 	}
 
 	// Defer to multi-engine backtest (Step 8)
-	result, err := s.runMultiEngineBacktest(req, engines)
+	result, err := s.runMultiEngineBacktest(ctx, req, engines)
 	if err != nil {
 		// Fallback to crude on backtest failure
 		log.Warn().Err(err).Msg("auto engine selection failed, falling back to crude")
@@ -246,7 +719,7 @@ func (s *Server) resolveEngine(req simulation.ForecastRequest) (simulation.Forec
 
 // runMultiEngineBacktest uses the walk-forward engine to compare multiple engines
 // on the currently active source and returns the selection result.
-func (s *Server) runMultiEngineBacktest(req simulation.ForecastRequest, engines []simulation.ForecastEngine) (*simulation.MultiEngineResult, error) {
+func (s *Server) runMultiEngineBacktest(ctx context.Context, req simulation.ForecastRequest, engines []simulation.ForecastEngine) (*simulation.MultiEngineResult, error) {
 	sourceID := s.activeSourceID
 
 	const walkForwardStepDays = 7
@@ -271,6 +744,7 @@ func (s *Server) runMultiEngineBacktest(req simulation.ForecastRequest, engines
 		LookbackWindow:  lookbackDays,
 		StepSize:        walkForwardStepDays,
 		ForecastHorizon: 14,
+		TypeAliases:     s.activeTypeAliases,
 		Resolutions:     s.activeResolutions,
 		EvaluationDate:  req.Clock,
 		CommitmentPoint: req.CommitmentPoint,
@@ -278,11 +752,18 @@ func (s *Server) runMultiEngineBacktest(req simulation.ForecastRequest, engines
 		SimulationSeed:  s.simulationSeed,
 	}
 
-	return wfa.ExecuteMultiEngine(cfg, engines, s.engineWeights)
+	return wfa.ExecuteMultiEngine(ctx, cfg, engines, s.engineWeights)
 }
 
-func (s *Server) handleGetCycleTimeAssessment(projectKey string, boardID int, startStatus, endStatus string, issueTypes []string, slePercentile int, sleDurationDays float64) (any, error) {
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+func (s *Server) handleGetCycleTimeAssessment(ctx context.Context, projectKey string, boardID int, startStatus, endStatus string, issueTypes []string, slePercentile int, sleDurationDays float64, byType bool, groupBy GroupDimension) (any, error) {
+	var dimension string
+	if groupBy != "" {
+		var err error
+		if dimension, err = groupDimension(groupBy); err != nil {
+			return nil, err
+		}
+	}
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -292,7 +773,7 @@ func (s *Server) handleGetCycleTimeAssessment(projectKey string, boardID int, st
 		return nil, err
 	}
 
-	reg, err := s.events.Hydrate(sourceID, projectKey, ctx.JQL, s.activeRegistry)
+	reg, err := s.events.Hydrate(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		return nil, err
 	}
@@ -301,9 +782,8 @@ func (s *Server) handleGetCycleTimeAssessment(projectKey string, boardID int, st
 		log.Warn().Err(err).Msg("Failed to persist workflow metadata to disk")
 	}
 
-	window := s.AnalysisWindow("day")
-	events := s.events.GetIssuesInRange(sourceID, window.Start, window.End)
-	session := stats.NewAnalysisSession(events, sourceID, *ctx, s.activeMapping, s.activeResolutions, window)
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, &handlerContext{SourceID: sourceID, Ctx: sc}, window)
 
 	delivered := session.GetDelivered()
 	finished := session.GetFinished()
@@ -318,26 +798,75 @@ func (s *Server) handleGetCycleTimeAssessment(projectKey string, boardID int, st
 		startStatus = analysisCtx.CommitmentPoint
 	}
 
-	cycleTimes, matchedIssues := s.getCycleTimes(projectKey, boardID, delivered, startStatus, endStatus, issueTypes)
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, startStatus, endStatus, issueTypes)
 	if len(cycleTimes) == 0 {
 		return nil, fmt.Errorf("no cycle times found for criteria")
 	}
 
-	scatterplot := stats.BuildScatterplot(matchedIssues, cycleTimes)
+	scatterplot, page, err := pageItems(ctx, "scatterplot", stats.BuildScatterplot(matchedIssues, cycleTimes), scatterOrders)
+	if err != nil {
+		return nil, err
+	}
 
-	h := simulation.NewHistogram(finished, window.Start, window.End, issueTypes, analysisCtx.WorkflowMappings, s.activeResolutions)
+	h := simulation.NewHistogram(finished, window.Start, window.End, issueTypes, analysisCtx.WorkflowMappings, s.activeResolutions, s.outcomes(ctx))
 	engine := simulation.NewEngine(h)
 	if s.simulationSeed != 0 {
 		engine.SetSeed(s.simulationSeed)
 	}
 
-	ctByType := s.getCycleTimesByType(projectKey, boardID, delivered, startStatus, endStatus, issueTypes)
-	resObj := engine.RunCycleTimeAnalysis(cycleTimes, ctByType)
+	// Outliers are trimmed from the percentiles only; the scatterplot and
+	// the SLE adherence show every delivered item.
+	outliers := s.outliers(ctx)
+	sample, _, trim := stats.TrimOutliers(cycleTimes, outliers)
+	ctByType := s.getCycleTimesByType(ctx, projectKey, boardID, delivered, startStatus, endStatus, issueTypes)
+	for t, cts := range ctByType {
+		ctByType[t], _, _ = stats.TrimOutliers(cts, outliers)
+	}
+	resObj := engine.RunCycleTimeAnalysis(sample, ctByType)
+	if byType {
+		resObj.TypeBreakdown = simulation.CycleTimeByType(ctByType)
+	}
+	var ungrouped int
+	if dimension != "" {
+		items, err := s.groupableIssues(matchedIssues, dimension)
+		if err != nil {
+			return nil, err
+		}
+		var ctByGroup map[string][]float64
+		ctByGroup, ungrouped = groupCycleTimes(items, cycleTimes, dimension)
+		for g, cts := range ctByGroup {
+			ctByGroup[g], _, _ = stats.TrimOutliers(cts, outliers)
+		}
+		resObj.GroupBy = dimension
+		resObj.GroupBreakdown = simulation.CycleTimeByGroup(ctByGroup)
+	}
 	resObj.Round()
 	resObj.Scatterplot = scatterplot
+	if outliers != stats.OutliersNone {
+		if resObj.Context == nil {
+			resObj.Context = make(map[string]any)
+		}
+		resObj.Context["outliers"] = trim
+	}
+	if page != nil {
+		if resObj.Context == nil {
+			resObj.Context = make(map[string]any)
+		}
+		resObj.Context["page"] = page
+	}
 
-	warnings := append(resObj.Warnings, s.getQualityWarnings(all)...)
+	warnings := append(resObj.Warnings, s.getQualityWarnings(ctx, all)...)
+	if small := smallSampleTypes(resObj.TypeBreakdown); len(small) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Fewer than %d delivered items for %s: their percentiles are indicative only.", simulation.SmallSampleSize, strings.Join(small, ", ")))
+	}
+	if small := smallSampleGroups(resObj.GroupBreakdown); len(small) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Fewer than %d delivered items for %d %s group(s): their percentiles are indicative only.", simulation.SmallSampleSize, len(small), dimension))
+	}
+	if ungrouped > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d delivered item(s) have no %s and are left out of 'group_breakdown'.", ungrouped, dimension))
+	}
 	insights := s.addCommitmentInsights(resObj.Insights, analysisCtx, startStatus)
+	insights = append(insights, s.guidanceFor("analyze_cycle_time", guidanceFacts{FatTailRatio: resObj.FatTailRatio})...)
 
 	adherence, adherenceInsight := s.computeSLEAdherence(matchedIssues, cycleTimes, resObj.Percentiles, slePercentile, sleDurationDays, window)
 	if adherence != nil {
@@ -354,6 +883,30 @@ func (s *Server) handleGetCycleTimeAssessment(projectKey string, boardID int, st
 	return WrapResponse(resObj, projectKey, boardID, nil, warnings, insights), nil
 }
 
+// smallSampleTypes returns the types of a per-type breakdown whose sample is
+// too small for reliable percentiles.
+func smallSampleTypes(table []simulation.TypeCycleTime) []string {
+	var small []string
+	for _, row := range table {
+		if row.SmallSample {
+			small = append(small, row.IssueType)
+		}
+	}
+	return small
+}
+
+// smallSampleGroups returns the groups of a per-group breakdown whose sample
+// is too small for reliable percentiles.
+func smallSampleGroups(table []simulation.GroupCycleTime) []string {
+	var small []string
+	for _, row := range table {
+		if row.SmallSample {
+			small = append(small, row.Group)
+		}
+	}
+	return small
+}
+
 // computeSLEAdherence resolves the effective SLE threshold (user-supplied vs. derived),
 // builds the weekly attainment series, and returns an optional AI-facing nudge insight
 // when the SLE was auto-derived (suggesting the agent ask the user for a fixed baseline).
@@ -428,8 +981,17 @@ func percentileFromResult(p simulation.Percentiles, percentile int) float64 {
 	return 0
 }
 
-func (s *Server) handleGetForecastAccuracy(projectKey string, boardID int, mode string, itemsToForecast, forecastHorizon int, issueTypes []string, sampleDays int, sampleStartDate, sampleEndDate string) (any, error) {
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+func (s *Server) handleGetForecastAccuracy(ctx context.Context, projectKey string, boardID int, mode string, itemsToForecast, forecastHorizon int, issueTypes []string, sampleDays int, sampleStartDate, sampleEndDate string, precision string, sweepSampleDays []int, progress progressFunc) (any, error) {
+	switch BacktestPrecision(precision) {
+	case "", PrecisionStandard, PrecisionFast:
+	default:
+		return nil, fmt.Errorf("invalid precision %q: must be '%s' or '%s'", precision, PrecisionStandard, PrecisionFast)
+	}
+	sweep, err := sweepWindows(sweepSampleDays)
+	if err != nil {
+		return nil, err
+	}
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -438,8 +1000,9 @@ func (s *Server) handleGetForecastAccuracy(projectKey string, boardID int, mode
 	if err := s.anchorContext(projectKey, boardID); err != nil {
 		return nil, err
 	}
+	issueTypes = s.forecastIssueTypes(issueTypes)
 
-	reg, err := s.events.Hydrate(sourceID, projectKey, ctx.JQL, s.activeRegistry)
+	reg, err := s.events.Hydrate(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		return nil, err
 	}
@@ -452,7 +1015,7 @@ func (s *Server) handleGetForecastAccuracy(projectKey string, boardID int, mode
 	const walkForwardSteps = 25
 	const walkForwardDefaultLookback = walkForwardStepDays * walkForwardSteps // 175 days → 25 checkpoints
 
-	histEnd := s.Clock()
+	histEnd := s.Clock(ctx)
 	if sampleEndDate != "" {
 		if t, err := time.Parse(stats.DateFormat, sampleEndDate); err == nil {
 			histEnd = t
@@ -479,9 +1042,14 @@ func (s *Server) handleGetForecastAccuracy(projectKey string, boardID int, mode
 	}
 
 	// Load events from the global maximum lookback so that each checkpoint's
-	// 90-day per-checkpoint histogram always has backing data. The earliest
-	// checkpoint is at histStart; its histogram reaches back another 90 days.
-	eventsStart := histStart.AddDate(0, 0, -DefaultForecastSampleDays)
+	// per-checkpoint histogram always has backing data. The earliest checkpoint
+	// is at histStart; its histogram reaches back another 90 days (or the
+	// longest swept window).
+	longestWindow := DefaultForecastSampleDays
+	if len(sweep) > 0 {
+		longestWindow = max(longestWindow, sweep[len(sweep)-1])
+	}
+	eventsStart := histStart.AddDate(0, 0, -longestWindow)
 	if s.activeDiscoveryCutoff != nil && eventsStart.Before(*s.activeDiscoveryCutoff) {
 		eventsStart = *s.activeDiscoveryCutoff
 	}
@@ -495,7 +1063,7 @@ func (s *Server) handleGetForecastAccuracy(projectKey string, boardID int, mode
 
 	if itemsToForecast <= 0 {
 		window := stats.NewAnalysisWindow(histStart, histEnd, "day", cutoff)
-		session := stats.NewAnalysisSession(events, sourceID, *ctx, s.activeMapping, s.activeResolutions, window)
+		session := stats.NewAnalysisSession(events, sourceID, *sc, s.activeMapping, s.activeResolutions, window).WithSubtaskPolicy(s.subtasks(ctx)).WithContainerPolicy(s.containers(ctx))
 		delivered := session.GetDelivered()
 		// Simple adaptive heuristic
 		itemsToForecast = int(float64(len(delivered)) / 10.0 * 2.0)
@@ -515,14 +1083,27 @@ func (s *Server) handleGetForecastAccuracy(projectKey string, boardID int, mode
 		ForecastHorizon:  forecastHorizon,
 		ItemsToForecast:  itemsToForecast,
 		IssueTypes:       issueTypes,
+		TypeAliases:      s.activeTypeAliases,
 		Resolutions:      s.activeResolutions,
-		EvaluationDate:   s.Clock(),
+		EvaluationDate:   s.Clock(ctx),
 		CommitmentPoint:  analysisCtx.CommitmentPoint,
 		StatusWeights:    analysisCtx.StatusWeights,
 		SimulationSeed:   s.simulationSeed,
 	}
+	if precision == string(PrecisionFast) {
+		cfg.Trials = simulation.FastTrials
+	}
+	if progress != nil {
+		cfg.OnCheckpoint = func(done, total int) {
+			progress(done, total, fmt.Sprintf("Backtest checkpoint %d/%d", done, total))
+		}
+	}
+
+	if len(sweep) > 0 {
+		return s.backtestWindowSweep(ctx, wfa, cfg, sweep, projectKey, boardID)
+	}
 
-	res, err := wfa.Execute(cfg)
+	res, err := wfa.Execute(ctx, cfg)
 	if err != nil {
 		return nil, err
 	}
@@ -531,7 +1112,83 @@ func (s *Server) handleGetForecastAccuracy(projectKey string, boardID int, mode
 		"accuracy": res,
 	}
 
-	return WrapResponse(resMap, projectKey, boardID, nil, s.getQualityWarnings(wfa.GetAnalyzedIssues()), nil), nil
+	return WrapResponse(resMap, projectKey, boardID, nil, s.getQualityWarnings(ctx, wfa.GetAnalyzedIssues()), nil), nil
+}
+
+// sweepWindows validates the sampling windows of a backtest window sweep and
+// returns them sorted and deduplicated.
+func sweepWindows(days []int) ([]int, error) {
+	if len(days) == 0 {
+		return nil, nil
+	}
+	windows := slices.Clone(days)
+	slices.Sort(windows)
+	windows = slices.Compact(windows)
+	if len(windows) > simulation.MaxSweepWindows {
+		return nil, fmt.Errorf("sweep_sample_days: at most %d windows can be compared in one call, got %d", simulation.MaxSweepWindows, len(windows))
+	}
+	if windows[0] < 14 || windows[len(windows)-1] > 730 {
+		return nil, fmt.Errorf("sweep_sample_days: windows must be between 14 and 730 days, got %v", windows)
+	}
+	return windows, nil
+}
+
+// backtestWindowSweep backtests each sampling window and returns the
+// comparison, with the recommended window's backtest as "accuracy".
+func (s *Server) backtestWindowSweep(ctx context.Context, wfa *simulation.WalkForwardEngine, cfg simulation.WalkForwardConfig, windows []int, projectKey string, boardID int) (any, error) {
+	sweep, err := wfa.Sweep(ctx, cfg, windows)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without a recommendation, show the default window (or the first swept one).
+	shown := sweep.Windows[0].Result
+	for _, w := range sweep.Windows {
+		if w.SampleDays == sweep.Recommended || (sweep.Recommended == 0 && w.SampleDays == simulation.DefaultCheckpointSampleDays) {
+			shown = w.Result
+		}
+	}
+
+	var insights []string
+	if sweep.Recommended > 0 {
+		insights = append(insights, fmt.Sprintf(
+			"WINDOW SWEEP: a %d-day sampling window forecast best in the past. %s Use history_window_days=%d with forecast_monte_carlo.",
+			sweep.Recommended, sweep.Reason, sweep.Recommended))
+	}
+
+	resMap := map[string]any{
+		"accuracy":     shown,
+		"window_sweep": sweep,
+	}
+	return WrapResponse(resMap, projectKey, boardID, nil, s.getQualityWarnings(ctx, wfa.GetAnalyzedIssues()), insights), nil
+}
+
+// applyReleaseLag extends a duration forecast to the released ("done-done")
+// definition of done by convolving it with the historical release lag.
+func (s *Server) applyReleaseLag(resObj *simulation.Result, mode string, finished []jira.Issue, releaseStatus string) {
+	if mode != "duration" {
+		resObj.Warnings = append(resObj.Warnings, "to_release applies to duration mode only and was ignored.")
+		return
+	}
+	lag := stats.AnalyzeReleaseLag(finished, releaseStatus)
+	if lag.ReleasedCount < MinReleaseLagSample {
+		resObj.Warnings = append(resObj.Warnings, fmt.Sprintf(
+			"RELEASE LAG UNAVAILABLE: only %d delivered item(s) in the sample carry a release marker (need %d). Forecast reflects resolution dates, not production dates.",
+			lag.ReleasedCount, MinReleaseLagSample))
+		return
+	}
+
+	released := simulation.ExtendWithLag(resObj.Percentiles, lag.Lags, simulation.DefaultTrials, s.simulationSeed)
+	released.Round()
+	lag.Round()
+	if resObj.Context == nil {
+		resObj.Context = make(map[string]any)
+	}
+	resObj.Context["released_percentiles"] = released
+	resObj.Context["release_lag"] = lag
+	resObj.Insights = append(resObj.Insights, fmt.Sprintf(
+		"Released (in-production) forecast: P85 %.1f days vs. %.1f days to resolution — release lag P85 is %.1f days (n=%d).",
+		released.Likely, resObj.Percentiles.Likely, lag.P85, lag.ReleasedCount))
 }
 
 // applyStationarity injects stationarity warnings and insights into a simulation result.
@@ -559,5 +1216,17 @@ func (s *Server) addCommitmentInsights(insights []string, analysisCtx *AnalysisC
 	} else {
 		insights = append(insights, "CAUTION: Analysis uses NO commitment point. Lifecycle timing starts from Creation.")
 	}
+	if len(analysisCtx.TypeCommitmentPoints) > 0 && (explicitStart == "" || explicitStart == analysisCtx.CommitmentPoint) {
+		types := slices.Sorted(maps.Keys(analysisCtx.TypeCommitmentPoints))
+		overrides := make([]string, 0, len(types))
+		for _, t := range types {
+			status := analysisCtx.TypeCommitmentPoints[t]
+			if name := s.activeRegistry.GetStatusName(status); name != "" {
+				status = name
+			}
+			overrides = append(overrides, fmt.Sprintf("%s → '%s'", t, status))
+		}
+		insights = append(insights, fmt.Sprintf("Per-type commitment points override the board default: %s.", strings.Join(overrides, ", ")))
+	}
 	return insights
 }
@@ -0,0 +1,578 @@
+package mcp
+
+import (
+	"context"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+func TestRunSimulation_ParametricSampling(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	run := func(sampling SamplingMode) (simulation.Result, error) {
+		res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:        testProject,
+			BoardID:           testBoard,
+			Mode:              "duration",
+			HistoryWindowDays: 90,
+			Targets:           map[string]int{"Story": 10},
+			Sampling:          sampling,
+		})
+		if err != nil {
+			return simulation.Result{}, err
+		}
+		return res.(ResponseEnvelope).Data.(simulation.Result), nil
+	}
+
+	if _, err := run("bayesian"); err == nil {
+		t.Errorf("Expected an unknown sampling mode to be rejected")
+	}
+	res, err := run(simulation.SamplingParametric)
+	if err != nil {
+		t.Fatalf("parametric forecast: %v", err)
+	}
+	fit, ok := res.Context["throughput_fit"].(simulation.ThroughputFit)
+	if !ok || res.Context["sampling"] != simulation.SamplingParametric {
+		t.Fatalf("Expected the fitted distribution in the context, got %v", res.Context)
+	}
+	if fit.Family != simulation.FamilyWeibull && fit.Family != simulation.FamilyLognormal {
+		t.Errorf("Unexpected family %q", fit.Family)
+	}
+	if res.Percentiles.Likely <= 0 {
+		t.Errorf("Expected a positive P85, got %v", res.Percentiles.Likely)
+	}
+}
+
+func TestRunSimulation_TargetDate(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	forecast := func(targetDays int, targetDate string, sprintMode bool) (any, error) {
+		return srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:        testProject,
+			BoardID:           testBoard,
+			Mode:              "duration",
+			TargetDays:        targetDays,
+			TargetDate:        targetDate,
+			HistoryWindowDays: 90,
+			Targets:           map[string]int{"Story": 10},
+			SprintMode:        sprintMode,
+		})
+	}
+	if _, err := forecast(0, srv.Clock(ctx).Format(stats.DateFormat), false); err == nil {
+		t.Errorf("Expected a target date on the evaluation date to be rejected")
+	}
+	if _, err := forecast(30, "", true); err == nil {
+		t.Errorf("Expected a target date to be rejected in sprint_mode")
+	}
+
+	res, err := forecast(0, srv.Clock(ctx).AddDate(0, 0, 30).Format(stats.DateFormat), false)
+	if err != nil {
+		t.Fatalf("forecast with target date: %v", err)
+	}
+	env := res.(ResponseEnvelope)
+	r := env.Data.(simulation.Result)
+	if r.Deadline == nil || r.Deadline.TargetDays != 30 || r.Deadline.Items != 10 {
+		t.Fatalf("Expected a check of 10 items against 30 days, got %+v", r.Deadline)
+	}
+	if p85 := int(math.Ceil(r.Percentiles.Likely)); r.Deadline.GapDaysP85 != max(0, p85-30) {
+		t.Errorf("Expected the P85 gap to follow the P85 of %d days, got %+v", p85, r.Deadline)
+	}
+	if r.Context["target_date"] != srv.Clock(ctx).AddDate(0, 0, 30).Format(stats.DateFormat) {
+		t.Errorf("Expected the target date in the context, got %v", r.Context["target_date"])
+	}
+	if !slices.ContainsFunc(env.Guardrails.Insights, func(s string) bool { return strings.HasPrefix(s, "TARGET DATE:") }) {
+		t.Errorf("Expected a TARGET DATE insight, got %v", env.Guardrails.Insights)
+	}
+
+	res, _ = forecast(0, "", false)
+	if r := res.(ResponseEnvelope).Data.(simulation.Result); r.Deadline != nil {
+		t.Errorf("Expected no deadline check without a target date, got %+v", r.Deadline)
+	}
+}
+
+func TestRunSimulation_SamplingGranularity(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	forecast := func(sampling SamplingMode, sprintMode bool, granularity SampleGranularity) (any, error) {
+		return srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:        testProject,
+			BoardID:           testBoard,
+			Mode:              "duration",
+			HistoryWindowDays: 90,
+			Targets:           map[string]int{"Story": 10},
+			SprintMode:        sprintMode,
+			Sampling:          sampling,
+			Granularity:       granularity,
+		})
+	}
+	if _, err := forecast("", false, "month"); err == nil {
+		t.Errorf("Expected an unknown sampling_granularity to be rejected")
+	}
+	if _, err := forecast(simulation.SamplingParametric, false, simulation.GranularityWeek); err == nil {
+		t.Errorf("Expected weekly sampling to be rejected with sampling=parametric")
+	}
+	if _, err := forecast("", true, simulation.GranularityWeek); err == nil {
+		t.Errorf("Expected weekly sampling to be rejected in sprint_mode")
+	}
+
+	res, err := forecast("", false, simulation.GranularityWeek)
+	if err != nil {
+		t.Fatalf("forecast with weekly sampling: %v", err)
+	}
+	r := res.(ResponseEnvelope).Data.(simulation.Result)
+	if g, ok := r.Context["sampling_granularity"].(simulation.Granularity); !ok || g.Unit != simulation.GranularityWeek || g.BlockDays != 7 {
+		t.Errorf("Expected weeks of 7 days (no working calendar) in the context, got %v", r.Context["sampling_granularity"])
+	}
+
+	res, _ = forecast("", false, "")
+	if g, ok := res.(ResponseEnvelope).Data.(simulation.Result).Context["sampling_granularity"]; ok {
+		t.Errorf("Expected no granularity in the context of a daily forecast, got %v", g)
+	}
+
+	// analyze_throughput assesses the batching that recommends the granularity.
+	res, err = srv.handleGetDeliveryCadence(ctx, testProject, testBoard, "week", "", false, "")
+	if err != nil {
+		t.Fatalf("analyze_throughput: %v", err)
+	}
+	b := res.(ResponseEnvelope).Data.(ThroughputResult).Batching
+	if b == nil || b.WeekDays != 7 || b.Weeks < simulation.BatchingMinWeeks || b.DispersionRatio <= 0 {
+		t.Errorf("Expected a batching assessment over whole weeks, got %+v", b)
+	}
+	if b.Batched != (b.Recommended == simulation.GranularityWeek) {
+		t.Errorf("Expected weekly sampling to be recommended only when batched, got %+v", b)
+	}
+}
+
+func TestRunSimulation_ModelArrivals(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "scope",
+		TargetDays:        30,
+		HistoryWindowDays: 90,
+		ModelArrivals:     true,
+	}); err == nil {
+		t.Errorf("Expected model_arrivals to be rejected in scope mode")
+	}
+
+	res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "duration",
+		HistoryWindowDays: 90,
+		Targets:           map[string]int{"Story": 10},
+		ModelArrivals:     true,
+	})
+	if err != nil {
+		t.Fatalf("forecast with arrivals: %v", err)
+	}
+	r := res.(ResponseEnvelope).Data.(simulation.Result)
+	if r.WithArrivals == nil {
+		t.Fatalf("Expected a moving-target forecast next to the fixed-scope one")
+	}
+	if r.WithArrivals.ArrivalRate <= 0 || len(r.WithArrivals.CompletionDates) == 0 {
+		t.Errorf("Expected an arrival rate and completion dates, got %+v", r.WithArrivals)
+	}
+	if r.WithArrivals.Percentiles.Likely < r.Percentiles.Likely {
+		t.Errorf("Expected arrivals not to shorten the forecast: P85 %v with arrivals vs %v fixed", r.WithArrivals.Percentiles.Likely, r.Percentiles.Likely)
+	}
+}
+
+func TestRunSimulation_CapacityScenario(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	run := func(capacityFactor float64, teamChange *TeamChange) (simulation.Result, error) {
+		res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:        testProject,
+			BoardID:           testBoard,
+			Mode:              "duration",
+			HistoryWindowDays: 90,
+			Targets:           map[string]int{"Story": 10},
+			CapacityFactor:    capacityFactor,
+			TeamChange:        teamChange,
+		})
+		if err != nil {
+			return simulation.Result{}, err
+		}
+		return res.(ResponseEnvelope).Data.(simulation.Result), nil
+	}
+
+	if _, err := run(70, nil); err == nil {
+		t.Errorf("Expected a percentage capacity_factor to be rejected")
+	}
+	if _, err := run(0, &TeamChange{From: 5, To: 3, EffectiveDate: "March"}); err == nil {
+		t.Errorf("Expected an invalid effective_date to be rejected")
+	}
+
+	base, err := run(0, nil)
+	if err != nil {
+		t.Fatalf("baseline forecast: %v", err)
+	}
+	if _, ok := base.Context["capacity_scenario"]; ok {
+		t.Errorf("Expected no capacity scenario without capacity inputs")
+	}
+	halved, err := run(0, &TeamChange{From: 6, To: 3, EffectiveDate: "2000-01-01"})
+	if err != nil {
+		t.Fatalf("team change forecast: %v", err)
+	}
+	if halved.Percentiles.Likely <= base.Percentiles.Likely {
+		t.Errorf("Expected a halved team to take longer: P85 %v vs %v", halved.Percentiles.Likely, base.Percentiles.Likely)
+	}
+	if _, ok := halved.Context["capacity_scenario"]; !ok {
+		t.Errorf("Expected the capacity scenario in the context, got %v", halved.Context)
+	}
+}
+
+func TestRunSimulation_DryRun(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "duration",
+		AdditionalItems:   5,
+		HistoryWindowDays: 90,
+		Targets:           map[string]int{"Story": 10, "Spike": 2},
+		DryRun:            true,
+	})
+	if err != nil {
+		t.Fatalf("dry run: %v", err)
+	}
+	env := res.(ResponseEnvelope)
+	preview := env.Data.(ForecastPreview).Inputs
+	if preview.TotalItems != 12 || preview.Throughput.Delivered == 0 || preview.Throughput.MeanPerDay <= 0 {
+		t.Errorf("Expected the targets and a non-empty throughput sample, got %+v", preview)
+	}
+	if preview.NaiveDays <= 0 {
+		t.Errorf("Expected a naive duration, got %v", preview.NaiveDays)
+	}
+	if len(preview.UnforecastableTypes) != 1 || preview.UnforecastableTypes[0] != "Spike" {
+		t.Errorf("Expected Spike to be flagged as unforecastable, got %v", preview.UnforecastableTypes)
+	}
+	if !strings.Contains(strings.Join(env.Guardrails.Warnings, " "), "[Spike]") {
+		t.Errorf("Expected a warning about Spike, got %v", env.Guardrails.Warnings)
+	}
+	if _, ok := env.Context["forecast_id"]; ok {
+		t.Errorf("Expected a dry run not to be recorded as a forecast run")
+	}
+
+	if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "duration",
+		AdditionalItems:   5,
+		HistoryWindowDays: 90,
+		SprintMode:        true,
+		DryRun:            true,
+	}); err == nil {
+		t.Errorf("Expected dry_run to be rejected in sprint_mode")
+	}
+}
+
+func TestRunSimulation_Explain(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:             testProject,
+		BoardID:                testBoard,
+		Mode:                   "duration",
+		IncludeExistingBacklog: true,
+		IncludeWIP:             true,
+		HistoryWindowDays:      90,
+		Explain:                true,
+	})
+	if err != nil {
+		t.Fatalf("explain: %v", err)
+	}
+	exp := res.(ResponseEnvelope).Data.(simulation.Result).Explanation
+	if exp == nil || exp.Unit != "days" || exp.BaselineP85 <= 0 {
+		t.Fatalf("Expected an explanation in days, got %+v", exp)
+	}
+	drivers := make(map[string][]simulation.ForecastDriver)
+	for _, d := range exp.Drivers {
+		drivers[d.Driver] = append(drivers[d.Driver], d)
+		if d.Summary == "" || d.P85Change != d.P85-exp.BaselineP85 {
+			t.Errorf("Inconsistent driver %+v", d)
+		}
+	}
+	if len(drivers["recent_throughput"]) != 1 || len(drivers["older_throughput"]) != 1 {
+		t.Errorf("Expected the recent and older halves of the sample, got %v", drivers)
+	}
+	shifts := drivers["throughput_sensitivity"]
+	if len(shifts) != 2 || shifts[0].P85 < exp.BaselineP85 || shifts[1].P85 > exp.BaselineP85 {
+		t.Errorf("Expected less throughput to lengthen and more to shorten the forecast, got %+v", shifts)
+	}
+
+	if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "duration",
+		AdditionalItems:   5,
+		HistoryWindowDays: 90,
+		DryRun:            true,
+		Explain:           true,
+	}); err == nil {
+		t.Errorf("Expected explain to be rejected with dry_run")
+	}
+}
+
+func TestRunSimulation_Ensemble(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.simulationSeed = 42
+	run := func(wipLimit int) (simulation.Result, ResponseEnvelope) {
+		t.Helper()
+		res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:             testProject,
+			BoardID:                testBoard,
+			Mode:                   "duration",
+			IncludeExistingBacklog: true,
+			IncludeWIP:             true,
+			HistoryWindowDays:      90,
+			Ensemble:               true,
+			WIPLimit:               wipLimit,
+		})
+		if err != nil {
+			t.Fatalf("ensemble: %v", err)
+		}
+		env := res.(ResponseEnvelope)
+		return env.Data.(simulation.Result), env
+	}
+
+	resObj, env := run(0)
+	ens := resObj.Ensemble
+	if ens == nil || ens.Percentiles.Likely <= 0 || ens.CycleTimeSample == 0 {
+		t.Fatalf("Expected a cycle-time forecast next to the throughput forecast, got %+v", ens)
+	}
+	if ens.ParallelismSource != "current_wip" || ens.ModelRisk == "" {
+		t.Errorf("Expected the current WIP as parallelism and a model risk, got %+v", ens)
+	}
+	if !slices.ContainsFunc(append(env.Guardrails.Insights, env.Guardrails.Warnings...), func(s string) bool { return strings.HasPrefix(s, "ENSEMBLE:") }) {
+		t.Errorf("Expected the model comparison to be reported")
+	}
+
+	serial, _ := run(1)
+	if serial.Ensemble.ParallelismSource != "wip_limit" || serial.Ensemble.Percentiles.Likely <= ens.Percentiles.Likely {
+		t.Errorf("Expected one item at a time to take longer than %v, got %+v", ens.Percentiles.Likely, serial.Ensemble)
+	}
+
+	if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "scope",
+		TargetDays:        30,
+		HistoryWindowDays: 90,
+		Ensemble:          true,
+	}); err == nil {
+		t.Errorf("Expected ensemble to be rejected in scope mode")
+	}
+	if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:             testProject,
+		BoardID:                testBoard,
+		Mode:                   "duration",
+		IncludeExistingBacklog: true,
+		IncludeWIP:             true,
+		HistoryWindowDays:      90,
+		WIPLimit:               3,
+	}); err == nil {
+		t.Errorf("Expected wip_limit without ensemble to be rejected")
+	}
+}
+
+func TestForecastBacktest_Precision(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	if _, err := srv.handleGetForecastAccuracy(ctx, testProject, testBoard, "scope", 0, 14, nil, 0, "", "", "fsat", nil, nil); err == nil {
+		t.Errorf("Expected an unknown precision to be rejected")
+	}
+}
+
+func TestForecastBacktest_WindowSweep(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	backtest := func(sweep []int) (ResponseEnvelope, error) {
+		res, err := srv.handleGetForecastAccuracy(ctx, testProject, testBoard, "scope", 0, 14, nil, 0, "", "", string(PrecisionFast), sweep, nil)
+		if err != nil {
+			return ResponseEnvelope{}, err
+		}
+		return res.(ResponseEnvelope), nil
+	}
+
+	for _, bad := range [][]int{{7, 90}, {30, 60, 90, 120, 180, 270, 365}} {
+		if _, err := backtest(bad); err == nil {
+			t.Errorf("Expected sweep_sample_days %v to be rejected", bad)
+		}
+	}
+
+	env, err := backtest([]int{90, 30, 60, 30})
+	if err != nil {
+		t.Fatalf("window sweep: %v", err)
+	}
+	data := env.Data.(map[string]any)
+	sweep := data["window_sweep"].(simulation.WindowSweepResult)
+	if len(sweep.Windows) != 3 || sweep.Windows[0].SampleDays != 30 || sweep.Windows[2].SampleDays != 90 {
+		t.Fatalf("Expected the windows 30, 60 and 90 in order, got %+v", sweep.Windows)
+	}
+	shown := data["accuracy"].(simulation.WalkForwardResult)
+	if sweep.Recommended != 0 && shown.SampleDays != sweep.Recommended {
+		t.Errorf("Expected the recommended %d-day backtest as accuracy, got %d days", sweep.Recommended, shown.SampleDays)
+	}
+	t.Logf("Recommended %d days: %s", sweep.Recommended, sweep.Reason)
+}
+
+func TestRunSimulation_PointsUnits(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	run := func(units ForecastUnits, targets map[string]int) error {
+		_, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:             testProject,
+			BoardID:                testBoard,
+			Mode:                   "duration",
+			IncludeExistingBacklog: true,
+			IncludeWIP:             true,
+			HistoryWindowDays:      90,
+			Targets:                targets,
+			Units:                  units,
+		})
+		return err
+	}
+	if err := run("hours", nil); err == nil {
+		t.Error("Expected unknown units to be rejected")
+	}
+	if err := run("points", map[string]int{"Story": 10}); err == nil {
+		t.Error("Expected units=points to be rejected with targets")
+	}
+	// The fixture carries no estimates.
+	if err := run("points", nil); err == nil || !strings.Contains(err.Error(), "JIRA_ESTIMATE_FIELD") {
+		t.Errorf("Expected a hint to configure the estimation field, got %v", err)
+	}
+}
+
+func TestPointsForecast(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	now := srv.Clock(ctx)
+	start := now.AddDate(0, 0, -59)
+	var finished []jira.Issue
+	for day := 0; day < 60; day++ {
+		resolved := start.AddDate(0, 0, day)
+		estimate := 3.0
+		finished = append(finished, jira.Issue{Key: "DONE", IssueType: "Story", Outcome: "delivered", ResolutionDate: &resolved, Estimate: &estimate})
+	}
+	eight := 8.0
+	scope := []jira.Issue{{Key: "BIG", Estimate: &eight}, {Key: "NONE"}}
+	req := simulation.ForecastRequest{Mode: "duration", Finished: finished, WindowStart: start, WindowEnd: now, SimulationSeed: 7, Clock: now}
+	items := simulation.Result{Percentiles: simulation.Percentiles{Likely: 2}}
+
+	res, err := srv.pointsForecast(ctx, req, items, scope, 1, testBoard)
+	if err != nil {
+		t.Fatalf("pointsForecast: %v", err)
+	}
+	// 8 estimated + 2 × 3 at the median estimate.
+	if res.Context["scope_points"] != 14 || res.Context["units"] != "points" {
+		t.Errorf("Expected 14 scope points, got %v", res.Context)
+	}
+	joined := strings.Join(res.Warnings, "\n")
+	if !strings.Contains(joined, "UNESTIMATED SCOPE: 2 item(s)") || !strings.Contains(joined, "POINTS VS ITEMS") {
+		t.Errorf("Expected the unestimated-scope and comparison warnings, got %v", res.Warnings)
+	}
+	// 14 points at 3 per day take about 5 days, far more than the 2 of the item forecast.
+	if !strings.Contains(joined, "diverge") {
+		t.Errorf("Expected the forecasts to diverge, got %v", res.Warnings)
+	}
+}
+
+func TestCycleTimeAssessment_ByType(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleGetCycleTimeAssessment(ctx, testProject, testBoard, "", "", nil, 0, 0, false, "")
+	if err != nil {
+		t.Fatalf("analyze_cycle_time: %v", err)
+	}
+	if pooled := res.(ResponseEnvelope).Data.(simulation.Result); pooled.TypeBreakdown != nil {
+		t.Errorf("Expected no breakdown without by_type, got %+v", pooled.TypeBreakdown)
+	}
+
+	res, err = srv.handleGetCycleTimeAssessment(ctx, testProject, testBoard, "", "", nil, 0, 0, true, "")
+	if err != nil {
+		t.Fatalf("analyze_cycle_time by_type: %v", err)
+	}
+	table := res.(ResponseEnvelope).Data.(simulation.Result).TypeBreakdown
+	if len(table) < 2 {
+		t.Fatalf("Expected a row per delivered type, got %+v", table)
+	}
+	for i, row := range table {
+		if row.Count == 0 || row.Percentiles.Likely < row.Percentiles.CoinToss || row.Predictability == "" {
+			t.Errorf("Incomplete row %+v", row)
+		}
+		if i > 0 && row.Count > table[i-1].Count {
+			t.Errorf("Expected rows ordered by sample size, got %s (%d) after %s (%d)", row.IssueType, row.Count, table[i-1].IssueType, table[i-1].Count)
+		}
+		if row.SmallSample != (row.Count < simulation.SmallSampleSize) {
+			t.Errorf("Wrong small-sample flag on %+v", row)
+		}
+	}
+}
+
+func TestRunSimulation_Stratification(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	run := func(sampling SamplingMode, mode StratificationMode, minSample int) (simulation.Result, error) {
+		res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:        testProject,
+			BoardID:           testBoard,
+			Mode:              "duration",
+			HistoryWindowDays: 90,
+			Targets:           map[string]int{"Story": 10},
+			Sampling:          sampling,
+			Stratification:    mode,
+			MinStratumSize:    minSample,
+		})
+		if err != nil {
+			return simulation.Result{}, err
+		}
+		return res.(ResponseEnvelope).Data.(simulation.Result), nil
+	}
+
+	if _, err := run("", "always", 0); err == nil {
+		t.Errorf("Expected an unknown stratification mode to be rejected")
+	}
+	if _, err := run("", "", -1); err == nil {
+		t.Errorf("Expected a negative minimum sample to be rejected")
+	}
+	if _, err := run(simulation.SamplingParametric, simulation.StratificationOn, 0); err == nil {
+		t.Errorf("Expected stratification on to be rejected with parametric sampling")
+	}
+
+	res, err := run("", simulation.StratificationOn, 1)
+	if err != nil {
+		t.Fatalf("stratified forecast: %v", err)
+	}
+	settings, ok := res.Context["stratification"].(simulation.StratificationSettings)
+	if !ok || settings.Mode != simulation.StratificationOn || settings.MinSample != 1 || len(settings.Stratified) == 0 {
+		t.Fatalf("Expected every type with an item stratified, got %+v", res.Context["stratification"])
+	}
+
+	res, err = run("", simulation.StratificationOff, 0)
+	if err != nil {
+		t.Fatalf("pooled forecast: %v", err)
+	}
+	settings, _ = res.Context["stratification"].(simulation.StratificationSettings)
+	if len(settings.Stratified) != 0 || !strings.HasPrefix(res.ModelingInsight, "Pooled") {
+		t.Errorf("Expected a pooled forecast, got %+v (%s)", settings, res.ModelingInsight)
+	}
+	for _, d := range res.Context["stratification_decisions"].([]simulation.StratificationDecision) {
+		if d.Eligible {
+			t.Errorf("Expected %s to be pooled, got %+v", d.Type, d)
+		}
+	}
+}
@@ -0,0 +1,77 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// handleForecastEpic rolls up the items below a parent issue (epic or
+// initiative) and forecasts the completion of its unfinished leaves with a
+// duration simulation. The rollup projects the full history so that long-lived
+// children are found regardless of the session analysis window.
+func (s *Server) handleForecastEpic(ctx context.Context, projectKey string, boardID int, epicKey string, sampleDays int) (any, error) {
+	epicKey = strings.ToUpper(strings.TrimSpace(epicKey))
+	if epicKey == "" {
+		return nil, fmt.Errorf("epic_key is required")
+	}
+
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	fullWindow := stats.NewAnalysisWindow(time.Time{}, s.Clock(ctx), "day", s.activeCutoff())
+	session := s.openSession(ctx, hctx, fullWindow)
+	all := session.GetAllIssues()
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+
+	rollup := stats.RollupDescendants(all, epicKey, analysisCtx.Commitments(), analysisCtx.StatusWeights)
+	rollup.Round()
+	if rollup.Leaves == 0 {
+		return nil, fmt.Errorf("no child items of %s found on board %d; children outside the board's filter are not visible", epicKey, boardID)
+	}
+
+	remaining := rollup.WIP + rollup.Backlog
+	if remaining == 0 {
+		res := map[string]any{"epic_rollup": rollup}
+		guidance := []string{fmt.Sprintf("%s has no unfinished child items (%d delivered, %d abandoned). Nothing to forecast.", epicKey, rollup.Delivered, rollup.Abandoned)}
+		return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
+	}
+
+	data, err := s.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:        projectKey,
+		BoardID:           boardID,
+		Mode:              SimModeDuration,
+		HistoryWindowDays: sampleDays,
+		Targets:           rollup.RemainingByType,
+	})
+	if err != nil {
+		return nil, err
+	}
+	envelope := data.(ResponseEnvelope)
+	resObj := envelope.Data.(simulation.Result)
+
+	resObj.Composition = &simulation.Composition{
+		ExistingBacklog: rollup.Backlog,
+		WIP:             rollup.WIP,
+		Total:           remaining,
+	}
+	resObj.Context["epic_rollup"] = rollup
+	envelope.Data = resObj
+
+	envelope.Guardrails.Insights = append(envelope.Guardrails.Insights,
+		fmt.Sprintf("%s: %d of %d child item(s) remain (%d in progress, %d not started); %.0f%% of non-abandoned children delivered.", epicKey, remaining, rollup.Leaves, rollup.WIP, rollup.Backlog, rollup.PercentComplete),
+		"The forecast covers only children that exist today. Epics typically grow after they start — re-run as children are added, or use forecast_monte_carlo with targets to model expected growth.",
+	)
+	if rollup.Containers > 0 {
+		envelope.Guardrails.Insights = append(envelope.Guardrails.Insights,
+			fmt.Sprintf("%d intermediate parent(s) were treated as containers; only their leaf items are forecast.", rollup.Containers))
+	}
+
+	return envelope, nil
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+func TestForecastEpic(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	ts := srv.Clock(ctx).AddDate(0, 0, -30).UnixMicro()
+	day := int64(86400 * 1e6)
+	created := func(key, issueType string, offset int64) eventlog.IssueEvent {
+		return eventlog.IssueEvent{EventType: eventlog.Created, IssueKey: key, IssueType: issueType, ParentKey: "EPIC-1", ToStatus: "Open", ToStatusID: "1", Timestamp: ts + offset}
+	}
+	change := func(key, issueType, toStatus, toStatusID, resolution string, offset int64) eventlog.IssueEvent {
+		return eventlog.IssueEvent{EventType: eventlog.Change, IssueKey: key, IssueType: issueType, ToStatus: toStatus, ToStatusID: toStatusID, Resolution: resolution, Timestamp: ts + offset}
+	}
+	appendCachedEvents(t, srv, []eventlog.IssueEvent{
+		created("CHILD-1", "Story", 0),
+		created("CHILD-2", "Story", 1),
+		created("CHILD-3", "Bug", 2),
+		change("CHILD-1", "Story", "developing", "38777", "", day),
+		change("CHILD-1", "Story", "Done", "10003", "Done", 5*day),
+		change("CHILD-2", "Story", "developing", "38777", "", 2*day),
+	})
+
+	res, err := srv.handleForecastEpic(ctx, testProject, testBoard, "epic-1", 0)
+	if err != nil {
+		t.Fatalf("forecast_epic: %v", err)
+	}
+	result := res.(ResponseEnvelope).Data.(simulation.Result)
+	rollup := result.Context["epic_rollup"].(stats.RollupResult)
+	if rollup.Leaves != 3 || rollup.Delivered != 1 || rollup.WIP != 1 || rollup.Backlog != 1 {
+		t.Errorf("Expected 1 delivered, 1 WIP, 1 backlog child, got %+v", rollup)
+	}
+	if result.Composition == nil || result.Composition.Total != 2 {
+		t.Errorf("Expected the forecast to cover the 2 remaining children, got %+v", result.Composition)
+	}
+	if result.Percentiles.Likely <= 0 {
+		t.Errorf("Expected a positive P85 duration, got %v", result.Percentiles.Likely)
+	}
+
+	if _, err := srv.handleForecastEpic(ctx, testProject, testBoard, "NOPE-1", 0); err == nil {
+		t.Errorf("Expected an error for a parent without visible children")
+	}
+}
+
+// appendCachedEvents adds events to the golden server's cache file, which is
+// loaded on the next hydration.
+func appendCachedEvents(t *testing.T, srv *Server, events []eventlog.IssueEvent) {
+	t.Helper()
+	f, err := os.OpenFile(filepath.Join(srv.cacheDir, testSourceID+".jsonl"), os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		t.Fatalf("open events cache: %v", err)
+	}
+	defer f.Close()
+	enc := json.NewEncoder(f)
+	for _, e := range events {
+		if err := enc.Encode(e); err != nil {
+			t.Fatalf("append event: %v", err)
+		}
+	}
+}
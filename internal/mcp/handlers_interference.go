@@ -0,0 +1,104 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// Interference parameter bounds.
+const (
+	defaultTaxerFactor     = 1.5
+	maxTaxerFactor         = 10
+	maxInterferenceHorizon = 365
+)
+
+func (s *Server) handleAnalyzeInterference(ctx context.Context, projectKey string, boardID int, by InterferenceDimension, taxerFactor float64, horizonDays int) (any, error) {
+	dimension := stats.StreamByIssueType
+	switch by {
+	case "", InterferenceByIssueType:
+	case InterferenceByComponent:
+		dimension = stats.StreamByComponent
+	default:
+		return nil, fmt.Errorf("invalid by %q: must be 'issue_type' or 'component'", by)
+	}
+	if taxerFactor == 0 {
+		taxerFactor = defaultTaxerFactor
+	}
+	if taxerFactor < 0 || taxerFactor > maxTaxerFactor {
+		return nil, fmt.Errorf("taxer_factor must be between 0 and %d, got %g", maxTaxerFactor, taxerFactor)
+	}
+	if horizonDays == 0 {
+		horizonDays = simulation.DefaultInterferenceHorizonDays
+	}
+	if horizonDays < 1 || horizonDays > maxInterferenceHorizon {
+		return nil, fmt.Errorf("horizon_days must be between 1 and %d, got %d", maxInterferenceHorizon, horizonDays)
+	}
+
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+
+	delivered := session.GetDelivered()
+	if dimension == stats.StreamByIssueType {
+		delivered = s.activeTypeAliases.Issues(delivered)
+	}
+	streams := stats.GetStreamThroughput(delivered, window, dimension)
+	counts := make(map[string][]int, len(streams.Streams))
+	for _, st := range streams.Streams {
+		counts[st.Stream] = st.Counts
+	}
+	interference := simulation.AnalyzeInterference(streams.Pooled, counts, taxerFactor, horizonDays, s.simulationSeed)
+	interference.Round()
+
+	res := map[string]any{
+		"dimension":    dimension,
+		"interference": interference,
+	}
+
+	warnings := s.getQualityWarnings(ctx, delivered)
+	if interference.DeliveryDays < simulation.InterferenceMinDays {
+		warnings = append(warnings, fmt.Sprintf("INSUFFICIENT DATA: only %d days with deliveries in the window; at least %d are needed to correlate streams. Widen the analysis window.", interference.DeliveryDays, simulation.InterferenceMinDays))
+	}
+
+	guidance := []string{
+		s.windowingGuidance(ctx),
+		fmt.Sprintf("Daily throughput per %s is correlated over the %d days with at least one delivery; days without any delivery are left out so they do not mask crowding-out.", dimension, interference.DeliveryDays),
+	}
+	if len(interference.Sparse) > 0 {
+		guidance = append(guidance, fmt.Sprintf("Skipped as too sparse (fewer than %d delivered items): %s.", simulation.InterferenceMinItems, strings.Join(interference.Sparse, ", ")))
+	}
+	var significant, unmodeled []string
+	for _, p := range interference.Pairs {
+		if !p.Significant {
+			continue
+		}
+		label := fmt.Sprintf("%s → %s", p.Taxer, p.Taxed)
+		significant = append(significant, label)
+		if !p.ModeledByForecasts {
+			unmodeled = append(unmodeled, label)
+		}
+	}
+	if len(significant) == 0 {
+		guidance = append(guidance, "No significant interference: the streams do not crowd each other out day by day. Pooled or per-type forecasts need no capacity-tax adjustment.")
+	} else {
+		guidance = append(guidance,
+			fmt.Sprintf("INTERFERENCE (taxer → taxed): %s. On busy taxer days the taxed stream delivers less; 'impact' simulates the taxed delivery over %d days with %gx the taxer volume.", strings.Join(significant, ", "), horizonDays, taxerFactor),
+			"Correlation shows shared capacity, not its cause. Check whether the taxer work is unplanned (e.g. incidents, support) before protecting capacity for it.")
+	}
+	if len(unmodeled) > 0 && dimension == stats.StreamByIssueType {
+		guidance = append(guidance, fmt.Sprintf("Stratified forecasts only apply the capacity tax below r = %.1f, so they do not model: %s. Use 'forecast_scenarios' with a capacity factor to size the effect instead.", simulation.DependencyCorrelationThreshold, strings.Join(unmodeled, ", ")))
+	}
+	if streams.MultiAttributed > 0 {
+		guidance = append(guidance, fmt.Sprintf("%d items belong to more than one component and are counted in each, which dampens negative correlations between those components.", streams.MultiAttributed))
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
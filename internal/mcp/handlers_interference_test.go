@@ -0,0 +1,50 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/simulation"
+)
+
+func TestAnalyzeInterference_Golden(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.simulationSeed = 42
+
+	data, err := srv.handleAnalyzeInterference(ctx, testProject, testBoard, InterferenceByIssueType, 2, 30)
+	if err != nil {
+		t.Fatalf("handleAnalyzeInterference: %v", err)
+	}
+	res := data.(ResponseEnvelope).Data.(map[string]any)
+	got := res["interference"].(simulation.InterferenceResult)
+	if got.DeliveryDays < simulation.InterferenceMinDays {
+		t.Fatalf("Expected enough delivery days in the golden window, got %d", got.DeliveryDays)
+	}
+	if len(got.Streams) < 2 {
+		t.Fatalf("Expected several issue types to correlate, got %v", got.Streams)
+	}
+	for _, p := range got.Pairs {
+		if p.Correlation >= 0 || p.Taxer == p.Taxed {
+			t.Errorf("Expected only negative correlations between distinct types, got %+v", p)
+		}
+		if p.Significant != (p.Impact != nil) {
+			t.Errorf("Expected an impact for significant pairs only, got %+v", p)
+		}
+	}
+}
+
+func TestAnalyzeInterference_InvalidInput(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	if _, err := srv.handleAnalyzeInterference(ctx, testProject, testBoard, "epic", 0, 0); err == nil {
+		t.Error("Expected an error for an unsupported dimension")
+	}
+	if _, err := srv.handleAnalyzeInterference(ctx, testProject, testBoard, InterferenceByComponent, -1, 0); err == nil {
+		t.Error("Expected an error for a negative taxer_factor")
+	}
+	if _, err := srv.handleAnalyzeInterference(ctx, testProject, testBoard, InterferenceByIssueType, 0, 1000); err == nil {
+		t.Error("Expected an error for a horizon beyond a year")
+	}
+}
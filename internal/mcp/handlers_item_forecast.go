@@ -0,0 +1,148 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/discovery"
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// handleForecastItem forecasts when a single in-flight issue will be done from
+// its position in the workflow: the time it still spends in its current status
+// (given how long it has been there), plus the residence times of the statuses
+// after it in the status order, each visited at its historical rate.
+func (s *Server) handleForecastItem(ctx context.Context, projectKey string, boardID int, issueKey string, sampleDays int) (any, error) {
+	issueKey = strings.ToUpper(strings.TrimSpace(issueKey))
+	if issueKey == "" {
+		return nil, fmt.Errorf("issue_key is required")
+	}
+
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := s.Clock(ctx)
+	fullWindow := stats.NewAnalysisWindow(time.Time{}, now, "day", s.activeCutoff())
+	all := s.openSession(ctx, hctx, fullWindow).GetAllIssues()
+	idx := slices.IndexFunc(all, func(i jira.Issue) bool { return i.Key == issueKey })
+	if idx < 0 {
+		return nil, fmt.Errorf("issue %s not found on the current Project (%s) and Board (%d)", issueKey, projectKey, boardID)
+	}
+	issue := all[idx]
+
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+	tier := stats.DetermineTier(issue, analysisCtx.CommitmentPoint, analysisCtx.WorkflowMappings)
+	if tier == stats.TierFinished {
+		return nil, fmt.Errorf("%s is already finished (status '%s'); use analyze_item_journey to see where it spent its time", issueKey, issue.Status)
+	}
+
+	var warnings []string
+	order := s.activeStatusOrder
+	if len(order) == 0 {
+		order = discovery.DiscoverStatusOrder(all)
+		warnings = append(warnings, "No confirmed status order: the remaining path follows the order inferred from history. Confirm it with 'workflow_set_order'.")
+	}
+	pos := slices.Index(order, issue.StatusID)
+	if pos < 0 {
+		return nil, fmt.Errorf("status '%s' of %s is not part of the status order; confirm the order with workflow_set_order", issue.Status, issueKey)
+	}
+
+	// Residence samples come from delivered items, of the same type when there are enough.
+	window := s.AnalysisWindow(ctx, "day")
+	if sampleDays > 0 {
+		window = stats.NewAnalysisWindow(now.AddDate(0, 0, -sampleDays), now, "day", s.activeCutoff())
+	}
+	delivered := s.openSession(ctx, hctx, window).GetDelivered()
+	sample := delivered
+	sampleType := "all"
+	if sameType := slices.DeleteFunc(slices.Clone(delivered), func(i jira.Issue) bool { return i.IssueType != issue.IssueType }); len(sameType) >= MinItemForecastTypeSample {
+		sample, sampleType = sameType, issue.IssueType
+	}
+	if len(sample) == 0 {
+		return nil, fmt.Errorf("no delivered items in the sampling window to forecast %s from", issueKey)
+	}
+	residence := stats.StatusResidenceDays(sample)
+
+	statusName := func(id string) string {
+		if m, ok := analysisCtx.WorkflowMappings[id]; ok && m.Name != "" {
+			return m.Name
+		}
+		if name := s.activeRegistry.GetStatusName(id); name != "" {
+			return name
+		}
+		return id
+	}
+	current := simulation.NewStatusStep(statusName(issue.StatusID), residence[issue.StatusID], len(sample))
+	var remaining []simulation.StatusStep
+	for _, id := range order[pos+1:] {
+		// Finished statuses end the journey; statuses no delivered item visited add nothing.
+		if analysisCtx.WorkflowMappings[id].Tier == stats.TierFinished || len(residence[id]) == 0 {
+			continue
+		}
+		remaining = append(remaining, simulation.NewStatusStep(statusName(id), residence[id], len(sample)))
+	}
+
+	cycleTimes, _ := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+	age := stats.CalculateInventoryAgeByType([]jira.Issue{issue}, analysisCtx.Commitments(), analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, cycleTimes, "wip", s.commitmentBackflowReset, now)[0]
+
+	engine := simulation.NewEngine(nil)
+	if s.simulationSeed != 0 {
+		engine.SetSeed(s.simulationSeed)
+	}
+	engine.SetContext(ctx)
+	resObj := engine.RunItemSimulation(current, age.AgeInCurrentStatus, remaining, simulation.DefaultTrials)
+	if err := engine.Err(); err != nil {
+		return nil, err
+	}
+	resObj.Round()
+	current.Round()
+	for i := range remaining {
+		remaining[i].Round()
+	}
+
+	resObj.Context = map[string]any{
+		"item": map[string]any{
+			"key":                        issue.Key,
+			"type":                       issue.IssueType,
+			"status":                     current.Status,
+			"tier":                       tier,
+			"age_in_current_status_days": stats.Round2(age.AgeInCurrentStatus),
+			"age_since_commitment_days":  age.AgeSinceCommitment,
+		},
+		"current_status":   current,
+		"remaining_path":   remaining,
+		"completion_dates": simulation.CompletionDates(resObj.Percentiles, now),
+		"sample": map[string]any{
+			"issue_type":      sampleType,
+			"delivered_items": len(sample),
+			"start_date":      window.Start.Format(stats.DateFormat),
+			"end_date":        window.End.Format(stats.DateFormat),
+		},
+	}
+
+	path := make([]string, 0, len(remaining))
+	for _, step := range remaining {
+		path = append(path, step.Status)
+	}
+	insights := append(resObj.Insights, fmt.Sprintf("%s has been in '%s' for %.1f days; %d status(es) remain after it (%s). There is a 50%% chance it is done within %.1f days and an 85%% chance within %.1f days.",
+		issueKey, current.Status, age.AgeInCurrentStatus, len(remaining), strings.Join(path, " → "), resObj.Percentiles.CoinToss, resObj.Percentiles.Likely))
+	if sampleType == "all" {
+		insights = append(insights, fmt.Sprintf("Fewer than %d delivered %s items in the sampling window: residence times are sampled from all delivered items.", MinItemForecastTypeSample, issue.IssueType))
+	}
+	if tier == stats.TierDemand {
+		warnings = append(warnings, fmt.Sprintf("%s is not committed yet. The forecast includes its remaining backlog time, which depends on prioritisation more than on flow.", issueKey))
+	}
+	warnings = append(warnings, resObj.Warnings...)
+	resObj.Warnings = nil
+	resObj.Insights = nil
+
+	insights = append(insights, s.guidanceFor("forecast_item", guidanceFacts{})...)
+	return WrapResponse(resObj, projectKey, boardID, nil, append(warnings, s.getQualityWarnings(ctx, []jira.Issue{issue})...), insights), nil
+}
@@ -0,0 +1,47 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/simulation"
+)
+
+func TestForecastItem(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.activeStatusOrder = []string{"1", "38775", "38776", "38777", "38778", "38779", "38780", "38781", "38782", "38783", "10003", "6"}
+
+	ts := srv.Clock(ctx).AddDate(0, 0, -10).UnixMicro()
+	day := int64(86400 * 1e6)
+	appendCachedEvents(t, srv, []eventlog.IssueEvent{
+		{EventType: eventlog.Created, IssueKey: "ITEM-1", IssueType: "Story", ToStatus: "Open", ToStatusID: "1", Timestamp: ts},
+		{EventType: eventlog.Change, IssueKey: "ITEM-1", IssueType: "Story", ToStatus: "developing", ToStatusID: "38777", Timestamp: ts + day},
+		{EventType: eventlog.Created, IssueKey: "ITEM-2", IssueType: "Story", ToStatus: "Open", ToStatusID: "1", Timestamp: ts},
+		{EventType: eventlog.Change, IssueKey: "ITEM-2", IssueType: "Story", ToStatus: "Done", ToStatusID: "10003", Resolution: "Done", Timestamp: ts + day},
+	})
+
+	res, err := srv.handleForecastItem(ctx, testProject, testBoard, "item-1", 0)
+	if err != nil {
+		t.Fatalf("forecast_item: %v", err)
+	}
+	result := res.(ResponseEnvelope).Data.(simulation.Result)
+	remaining := result.Context["remaining_path"].([]simulation.StatusStep)
+	if len(remaining) != 6 || remaining[0].Status != "awaiting deploy to QA" {
+		t.Errorf("Expected the 6 downstream statuses after 'developing', got %+v", remaining)
+	}
+	if result.Percentiles.CoinToss <= 0 || result.Percentiles.Likely < result.Percentiles.CoinToss {
+		t.Errorf("Expected positive, ordered remaining days, got %+v", result.Percentiles)
+	}
+	if _, ok := result.Context["completion_dates"]; !ok {
+		t.Errorf("Expected completion dates in the context")
+	}
+
+	if _, err := srv.handleForecastItem(ctx, testProject, testBoard, "ITEM-2", 0); err == nil {
+		t.Errorf("Expected an error for a finished item")
+	}
+	if _, err := srv.handleForecastItem(ctx, testProject, testBoard, "NOPE-1", 0); err == nil {
+		t.Errorf("Expected an error for an unknown item")
+	}
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"time"
@@ -11,12 +12,12 @@ import (
 	"mcs-mcp/internal/stats"
 )
 
-func (s *Server) handleGetItemJourney(projectKey string, boardID int, issueKey string) (any, error) {
+func (s *Server) handleGetItemJourney(ctx context.Context, projectKey string, boardID int, issueKey string) (any, error) {
 	if err := s.anchorContext(projectKey, boardID); err != nil {
 		return nil, err
 	}
 
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -27,8 +28,8 @@ func (s *Server) handleGetItemJourney(projectKey string, boardID int, issueKey s
 
 	// 2. Fallback to context-locked hydration if not found
 	if len(events) == 0 {
-		lockedJQL := fmt.Sprintf("(%s) AND key = %s", ctx.JQL, issueKey)
-		reg, err := s.events.Hydrate(sourceID, projectKey, lockedJQL, s.activeRegistry)
+		lockedJQL := jira.AndJQL(sc.JQL, jira.FieldEquals("key", issueKey))
+		reg, err := s.events.Hydrate(ctx, sourceID, projectKey, lockedJQL, s.activeRegistry)
 		if err != nil {
 			return nil, err
 		}
@@ -51,7 +52,7 @@ func (s *Server) handleGetItemJourney(projectKey string, boardID int, issueKey s
 		}
 	}
 
-	issue := eventlog.ReconstructIssue(events, s.Clock())
+	issue := eventlog.ReconstructIssue(events, s.Clock(ctx))
 
 	type JourneyStep struct {
 		Status string  `json:"status"`
@@ -84,7 +85,7 @@ func (s *Server) handleGetItemJourney(projectKey string, boardID int, issueKey s
 		if issue.ResolutionDate != nil {
 			finalDate = *issue.ResolutionDate
 		} else {
-			finalDate = s.Clock()
+			finalDate = s.Clock(ctx)
 		}
 		lastTrans := issue.Transitions[len(issue.Transitions)-1]
 		finalDuration := finalDate.Sub(lastTrans.Date).Seconds()
@@ -139,9 +140,14 @@ func (s *Server) handleGetItemJourney(projectKey string, boardID int, issueKey s
 		"warnings":       []string{},
 	}
 
-	guidance := []string{
-		"The 'path' shows chronological flow, while 'residency' shows cumulative totals.",
+	if len(issue.FormerKeys) > 0 {
+		res["former_keys"] = issue.FormerKeys
 	}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings([]jira.Issue{issue}), guidance), nil
+	guidance := s.guidanceFor("analyze_item_journey", guidanceFacts{})
+	if issue.Key != issueKey {
+		guidance = append(guidance, fmt.Sprintf("%s was moved to another project and is now %s; the journey shows %s since it arrived there.", issueKey, issue.Key, issue.Key))
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, []jira.Issue{issue}), guidance), nil
 }
@@ -0,0 +1,323 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Mapping document format identifiers.
+const (
+	mappingDocumentFormat   = "mcs-workflow-mapping"
+	mappingDocumentVersion  = 1
+	mappingDocumentMaxBytes = 1 << 20
+)
+
+// MappingDocument is the portable form of a board's confirmed workflow.
+// Statuses and resolutions are keyed by name (with their IDs as hints), so the
+// document applies to any board whose workflow uses the same names, even on
+// another Jira instance.
+type MappingDocument struct {
+	Format                 string            `json:"format"`
+	Version                int               `json:"version"`
+	Source                 string            `json:"source"`
+	ExportedAt             time.Time         `json:"exported_at"`
+	Statuses               []MappedStatus    `json:"statuses"`                            // Status order first, then unordered statuses by name
+	StatusOrder            []string          `json:"status_order,omitempty"`              // Status names
+	CommitmentPoint        string            `json:"commitment_point,omitempty"`          // Status name
+	CommitmentPointsByType map[string]string `json:"commitment_points_by_type,omitempty"` // Issue type → status name
+	Resolutions            []MappedOutcome   `json:"resolutions,omitempty"`
+}
+
+// MappedStatus is the confirmed metadata of one status in a MappingDocument.
+type MappedStatus struct {
+	Name    string `json:"name"`
+	ID      string `json:"id,omitempty"`
+	Tier    string `json:"tier"`
+	Role    string `json:"role,omitempty"`
+	Outcome string `json:"outcome,omitempty"`
+}
+
+// MappedOutcome is the confirmed outcome of one resolution in a MappingDocument.
+type MappedOutcome struct {
+	Name    string `json:"name"`
+	ID      string `json:"id,omitempty"`
+	Outcome string `json:"outcome"`
+}
+
+// statusName returns the display name of a status ID of the active board.
+func (s *Server) statusName(id string) string {
+	if m, ok := s.activeMapping[id]; ok && m.Name != "" {
+		return m.Name
+	}
+	if name := s.activeRegistry.GetStatusName(id); name != "" {
+		return name
+	}
+	return id
+}
+
+func (s *Server) handleExportWorkflowMapping(projectKey string, boardID int, path string) (any, error) {
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	sourceID := getCombinedID(projectKey, boardID)
+	if len(s.activeMapping) == 0 {
+		return nil, fmt.Errorf("no confirmed workflow mapping for %s: confirm it with 'workflow_set_mapping' first", sourceID)
+	}
+
+	doc := MappingDocument{
+		Format:     mappingDocumentFormat,
+		Version:    mappingDocumentVersion,
+		Source:     sourceID,
+		ExportedAt: time.Now().UTC(),
+	}
+	if s.activeCommitmentPoint != "" {
+		doc.CommitmentPoint = s.statusName(s.activeCommitmentPoint)
+	}
+	if len(s.activeTypeCommitments) > 0 {
+		doc.CommitmentPointsByType = make(map[string]string, len(s.activeTypeCommitments))
+		for issueType, id := range s.activeTypeCommitments {
+			doc.CommitmentPointsByType[issueType] = s.statusName(id)
+		}
+	}
+
+	ids := make([]string, 0, len(s.activeMapping))
+	for _, id := range s.activeStatusOrder {
+		doc.StatusOrder = append(doc.StatusOrder, s.statusName(id))
+		if _, ok := s.activeMapping[id]; ok && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	var unordered []string
+	for id := range s.activeMapping {
+		if !slices.Contains(ids, id) {
+			unordered = append(unordered, id)
+		}
+	}
+	slices.SortFunc(unordered, func(a, b string) int { return strings.Compare(s.statusName(a), s.statusName(b)) })
+	for _, id := range append(ids, unordered...) {
+		m := s.activeMapping[id]
+		doc.Statuses = append(doc.Statuses, MappedStatus{Name: s.statusName(id), ID: id, Tier: m.Tier, Role: m.Role, Outcome: m.Outcome})
+	}
+
+	for id, outcome := range s.activeResolutions {
+		name := s.activeRegistry.GetResolutionName(id)
+		if name == "" {
+			name = id
+		}
+		doc.Resolutions = append(doc.Resolutions, MappedOutcome{Name: name, ID: id, Outcome: outcome})
+	}
+	slices.SortFunc(doc.Resolutions, func(a, b MappedOutcome) int { return strings.Compare(a.Name, b.Name) })
+
+	res := map[string]any{"document": doc}
+	if path != "" {
+		if err := writeMappingDocument(path, doc); err != nil {
+			return nil, fmt.Errorf("failed to write mapping document: %w", err)
+		}
+		res["path"] = path
+		log.Info().Str("source", sourceID).Str("path", path).Msg("Workflow mapping exported")
+	}
+	guidance := []string{
+		"Apply this mapping to a board with a similar workflow via 'workflow_import_mapping' (pass 'path', or the document as 'document').",
+		"Statuses and resolutions are matched by name on the target board, so the document also works across Jira instances.",
+	}
+	return WrapResponse(res, projectKey, boardID, nil, nil, guidance), nil
+}
+
+// writeMappingDocument writes a mapping document atomically (temp file + rename).
+func writeMappingDocument(path string, doc MappingDocument) error {
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// readMappingDocument parses and validates a mapping document from a file
+// path or inline JSON; exactly one must be set.
+func readMappingDocument(path, document string) (MappingDocument, error) {
+	var doc MappingDocument
+	var data []byte
+	switch {
+	case (path == "") == (strings.TrimSpace(document) == ""):
+		return doc, fmt.Errorf("set either path or document")
+	case path != "":
+		f, err := os.Open(path)
+		if err != nil {
+			return doc, fmt.Errorf("failed to open mapping document: %w", err)
+		}
+		defer f.Close()
+		if data, err = io.ReadAll(io.LimitReader(f, mappingDocumentMaxBytes+1)); err != nil {
+			return doc, fmt.Errorf("failed to read mapping document: %w", err)
+		}
+	default:
+		data = []byte(document)
+	}
+	if len(data) > mappingDocumentMaxBytes {
+		return doc, fmt.Errorf("mapping document exceeds %d bytes", mappingDocumentMaxBytes)
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return doc, fmt.Errorf("invalid mapping document: %w", err)
+	}
+	if doc.Format != mappingDocumentFormat {
+		return doc, fmt.Errorf("not a workflow mapping document: format %q", doc.Format)
+	}
+	if doc.Version > mappingDocumentVersion {
+		return doc, fmt.Errorf("mapping document version %d is newer than supported version %d", doc.Version, mappingDocumentVersion)
+	}
+	if len(doc.Statuses) == 0 {
+		return doc, fmt.Errorf("mapping document has no statuses")
+	}
+	for _, st := range doc.Statuses {
+		if !slices.Contains([]string{stats.TierDemand, stats.TierUpstream, stats.TierDownstream, stats.TierFinished}, st.Tier) {
+			return doc, fmt.Errorf("status %q has invalid tier %q", st.Name, st.Tier)
+		}
+	}
+	return doc, nil
+}
+
+func (s *Server) handleImportWorkflowMapping(ctx context.Context, projectKey string, boardID int, path, document string, overwrite bool) (any, error) {
+	doc, err := readMappingDocument(path, document)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	sourceID := getCombinedID(projectKey, boardID)
+	if len(s.activeMapping) > 0 && !overwrite {
+		return nil, fmt.Errorf("%s already has a confirmed workflow mapping; re-run with overwrite=true to replace it", sourceID)
+	}
+
+	// The target's statuses come from its history; hydrate so names resolve to its IDs.
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	reg, err := s.events.Hydrate(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
+	if err != nil {
+		log.Error().Err(err).Str("source", sourceID).Msg("Hydration failed")
+	}
+	s.activeRegistry = reg
+
+	// Resolve a status of the document on the target board: by name first,
+	// then by ID for boards of the same Jira instance.
+	var unmatched []string
+	resolve := func(name, id string) string {
+		if target := s.activeRegistry.GetStatusID(name); target != "" {
+			return target
+		}
+		if id != "" && s.activeRegistry.GetStatusName(id) != "" {
+			return id
+		}
+		return ""
+	}
+	byName := make(map[string]string)
+	mapping := make(map[string]any, len(doc.Statuses))
+	for _, st := range doc.Statuses {
+		id := resolve(st.Name, st.ID)
+		if id == "" {
+			unmatched = append(unmatched, st.Name)
+			continue
+		}
+		byName[st.Name] = id
+		entry := map[string]any{"tier": st.Tier}
+		if st.Role != "" {
+			entry["role"] = st.Role
+		}
+		if st.Outcome != "" {
+			entry["outcome"] = st.Outcome
+		}
+		mapping[id] = entry
+	}
+	if len(mapping) == 0 {
+		return nil, fmt.Errorf("none of the %d statuses of the mapping document exist on %s", len(doc.Statuses), sourceID)
+	}
+
+	resolutions := make(map[string]any, len(doc.Resolutions))
+	for _, r := range doc.Resolutions {
+		id := s.activeRegistry.GetResolutionID(r.Name)
+		if id == "" && s.activeRegistry.GetResolutionName(r.ID) != "" {
+			id = r.ID
+		}
+		if id == "" {
+			unmatched = append(unmatched, r.Name)
+			continue
+		}
+		resolutions[id] = r.Outcome
+	}
+
+	commitmentPoint := byName[doc.CommitmentPoint]
+	byType := make(map[string]string, len(doc.CommitmentPointsByType))
+	for issueType, name := range doc.CommitmentPointsByType {
+		if id := byName[name]; id != "" {
+			byType[issueType] = id
+		}
+	}
+	var order []string
+	for _, name := range doc.StatusOrder {
+		if id := byName[name]; id != "" {
+			order = append(order, id)
+		}
+	}
+
+	if _, err := s.handleSetWorkflowMapping(ctx, projectKey, boardID, mapping, resolutions, commitmentPoint, byType); err != nil {
+		return nil, err
+	}
+	if len(order) > 0 {
+		if _, err := s.handleSetWorkflowOrder(ctx, projectKey, boardID, order); err != nil {
+			return nil, err
+		}
+	}
+	log.Info().Str("source", sourceID).Str("from", doc.Source).Int("statuses", len(mapping)).Msg("Workflow mapping imported")
+
+	// Statuses the target's history uses that the document does not cover.
+	var unmapped []string
+	for _, e := range s.events.GetIssuesInRange(sourceID, time.Time{}, s.Clock(ctx)) {
+		id := stats.PreferID(e.ToStatusID, e.ToStatus)
+		if _, ok := s.activeMapping[id]; !ok && id != "" && !slices.Contains(unmapped, s.statusName(id)) {
+			unmapped = append(unmapped, s.statusName(id))
+		}
+	}
+
+	res := map[string]any{
+		"status":            "success",
+		"imported_from":     doc.Source,
+		"statuses_mapped":   len(mapping),
+		"commitment_point":  s.statusName(s.activeCommitmentPoint),
+		"unmatched":         unmatched,
+		"unmapped_statuses": unmapped,
+	}
+	var warnings []string
+	if len(unmatched) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d status(es) or resolution(s) of the document do not exist on %s and were skipped: %s", len(unmatched), sourceID, strings.Join(unmatched, ", ")))
+	}
+	if doc.CommitmentPoint != "" && commitmentPoint == "" {
+		warnings = append(warnings, fmt.Sprintf("The commitment point '%s' does not exist on %s; confirm one with 'workflow_set_mapping'.", doc.CommitmentPoint, sourceID))
+	}
+	if len(unmapped) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d status(es) in the history of %s are not covered by the document: %s. Map them with 'workflow_set_mapping' and 'workflow_set_order'.", len(unmapped), sourceID, strings.Join(unmapped, ", ")))
+	}
+	guidance := []string{
+		"AI SHOULD present the imported mapping to the user (e.g. via 'workflow_discover_mapping', which now returns it as LOADED_FROM_CACHE) before running diagnostics.",
+	}
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
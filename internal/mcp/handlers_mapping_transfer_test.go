@@ -0,0 +1,83 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestWorkflowMappingTransfer(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	registry := srv.activeRegistry
+
+	path := filepath.Join(t.TempDir(), "mapping.json")
+	res, err := srv.handleExportWorkflowMapping(testProject, testBoard, path)
+	if err != nil {
+		t.Fatalf("export: %v", err)
+	}
+	doc := res.(ResponseEnvelope).Data.(map[string]any)["document"].(MappingDocument)
+	if len(doc.Statuses) != len(srv.activeMapping) || doc.CommitmentPoint != srv.statusName(srv.activeCommitmentPoint) {
+		t.Fatalf("Expected every mapped status and the commitment point by name, got %+v", doc)
+	}
+	mapping, resolutions, commitment := maps.Clone(srv.activeMapping), maps.Clone(srv.activeResolutions), srv.activeCommitmentPoint
+
+	// A second board of the project whose registry is already known.
+	events, err := os.ReadFile(filepath.Join(srv.cacheDir, testSourceID+".jsonl"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(srv.cacheDir, testProject+"_1.jsonl"), events, 0644); err != nil {
+		t.Fatal(err)
+	}
+	wf, _ := json.Marshal(WorkflowMetadata{SourceID: testProject + "_1", NameRegistry: registry})
+	if err := os.WriteFile(filepath.Join(srv.cacheDir, testProject+"_1_workflow.json"), wf, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err = srv.handleImportWorkflowMapping(ctx, testProject, 1, path, "", false)
+	if err != nil {
+		t.Fatalf("import: %v", err)
+	}
+	if len(res.(ResponseEnvelope).Data.(map[string]any)["unmatched"].([]string)) != 0 {
+		t.Errorf("Expected every status to match, got %+v", res.(ResponseEnvelope).Data)
+	}
+	for id, m := range mapping {
+		got := srv.activeMapping[id]
+		if got.Tier != m.Tier || got.Role != m.Role || got.Outcome != m.Outcome {
+			t.Errorf("Expected status %s to be imported as %+v, got %+v", id, m, got)
+		}
+	}
+	if srv.activeCommitmentPoint != commitment || !maps.Equal(srv.activeResolutions, resolutions) {
+		t.Errorf("Expected commitment point %s and resolutions %v, got %s and %v", commitment, resolutions, srv.activeCommitmentPoint, srv.activeResolutions)
+	}
+
+	if _, err := srv.handleImportWorkflowMapping(ctx, testProject, 1, path, "", false); err == nil {
+		t.Error("Expected an existing mapping not to be replaced without overwrite")
+	}
+
+	// Statuses the target does not have are skipped and reported.
+	doc.Statuses[0].Name, doc.Statuses[0].ID = "Nowhere", ""
+	inline, _ := json.Marshal(doc)
+	res, err = srv.handleImportWorkflowMapping(ctx, testProject, 1, "", string(inline), true)
+	if err != nil {
+		t.Fatalf("import inline: %v", err)
+	}
+	env := res.(ResponseEnvelope)
+	if unmatched := env.Data.(map[string]any)["unmatched"].([]string); len(unmatched) != 1 || unmatched[0] != "Nowhere" {
+		t.Errorf("Expected Nowhere to be unmatched, got %v", unmatched)
+	}
+	if !strings.Contains(strings.Join(env.Guardrails.Warnings, " "), "Nowhere") {
+		t.Errorf("Expected a warning naming the unmatched status, got %v", env.Guardrails.Warnings)
+	}
+
+	for _, bad := range []string{`{"format":"other","statuses":[{"name":"A","tier":"Downstream"}]}`, `{"format":"mcs-workflow-mapping","statuses":[{"name":"A","tier":"Middle"}]}`} {
+		if _, err := srv.handleImportWorkflowMapping(ctx, testProject, 1, "", bad, true); err == nil {
+			t.Errorf("Expected %s to be rejected", bad)
+		}
+	}
+}
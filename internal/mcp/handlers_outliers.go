@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+// Bounds of the limit parameter of find_outliers.
+const (
+	defaultOutlierLimit = 10
+	maxOutlierLimit     = 50
+)
+
+func (s *Server) handleFindOutliers(ctx context.Context, projectKey string, boardID int, by stats.SlowMeasure, status string, issueTypes []string, limit int) (any, error) {
+	measure, err := stats.ParseSlowMeasure(string(by))
+	if err != nil {
+		return nil, err
+	}
+	if limit < 0 || limit > maxOutlierLimit {
+		return nil, fmt.Errorf("invalid limit %d: expected 1 to %d", limit, maxOutlierLimit)
+	}
+	if limit == 0 {
+		limit = defaultOutlierLimit
+	}
+	if measure == stats.SlowByStatusTime && status == "" {
+		return nil, fmt.Errorf("status_time needs a 'status' to rank by")
+	}
+	if measure != stats.SlowByStatusTime && status != "" {
+		return nil, fmt.Errorf("'status' applies to status_time only")
+	}
+
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	statusID, statusName := "", ""
+	if measure == stats.SlowByStatusTime {
+		statusID = status
+		if resolved := s.activeRegistry.GetStatusID(status); resolved != "" {
+			statusID = resolved
+		}
+		statusName = s.activeRegistry.GetStatusName(statusID)
+		if statusName == "" {
+			statusName = s.activeMapping[statusID].Name
+		}
+		if statusName == "" {
+			return nil, fmt.Errorf("unknown status %q on this board", status)
+		}
+	}
+
+	session := s.openSession(ctx, hctx, s.AnalysisWindow(ctx, "day"))
+	all := session.GetAllIssues()
+	delivered := session.GetDelivered()
+	if len(delivered) == 0 {
+		return nil, fmt.Errorf("no historical delivery data found")
+	}
+
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", issueTypes)
+	if len(cycleTimes) == 0 {
+		return nil, fmt.Errorf("no cycle times found for criteria")
+	}
+
+	slowest := stats.RankSlowest(matchedIssues, cycleTimes, measure, statusID, limit)
+
+	var warnings []string
+	if summaries, err := s.fetchSummaries(ctx, hctx.Ctx.ProjectKey, slowest.Items); err != nil {
+		warnings = append(warnings, fmt.Sprintf("Summaries could not be fetched from Jira (%v); the items are listed by key only.", err))
+	} else {
+		for i, item := range slowest.Items {
+			slowest.Items[i].Summary = summaries[item.Key]
+		}
+	}
+
+	res := map[string]any{
+		"outliers": slowest,
+	}
+	if statusName != "" {
+		res["status_name"] = statusName
+	}
+
+	guidance := append(s.guidanceFor("find_outliers", guidanceFacts{}), s.windowingGuidance(ctx))
+	guidance = s.addCommitmentInsights(guidance, analysisCtx, analysisCtx.CommitmentPoint)
+	switch {
+	case len(slowest.Items) == 0 && measure == stats.SlowByBlockedTime:
+		guidance = append(guidance, "No delivered item in the window was ever flagged as blocked, so there is nothing to rank by blocked time.")
+	case len(slowest.Items) == 0:
+		guidance = append(guidance, fmt.Sprintf("No delivered item in the window spent time in '%s'.", statusName))
+	default:
+		keys := make([]string, len(slowest.Items))
+		for i, item := range slowest.Items {
+			keys[i] = item.Key
+		}
+		guidance = append(guidance, fmt.Sprintf("The %d slowest of %d ranked items: %s. Call 'analyze_item_journey' on each key to see where it waited.", len(keys), slowest.Population, strings.Join(keys, ", ")))
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, append(warnings, s.getQualityWarnings(ctx, all)...), guidance), nil
+}
+
+// fetchSummaries returns the summaries of items by key, fetched from Jira in
+// one search, since the event log does not keep them. MCSTEST and anonymized
+// results get none: the former has no Jira, and summaries would reveal what the
+// pseudonymized keys hide.
+func (s *Server) fetchSummaries(ctx context.Context, projectKey string, items []stats.SlowItem) (map[string]string, error) {
+	summaries := make(map[string]string)
+	if len(items) == 0 || projectKey == "MCSTEST" || s.anonymizer != nil {
+		return summaries, nil
+	}
+	keys := make([]string, len(items))
+	for i, item := range items {
+		keys[i] = item.Key
+	}
+	resp, err := s.jira.SearchIssues(ctx, jira.FieldIn("key", keys), 0, len(keys))
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return summaries, nil
+	}
+	for _, issue := range resp.Issues {
+		summaries[issue.Key] = issue.Fields.Summary
+	}
+	return summaries, nil
+}
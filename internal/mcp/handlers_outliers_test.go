@@ -0,0 +1,96 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+func TestFindOutliers(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleFindOutliers(ctx, testProject, testBoard, "", "", nil, 5)
+	if err != nil {
+		t.Fatalf("find_outliers: %v", err)
+	}
+	slowest := res.(ResponseEnvelope).Data.(map[string]any)["outliers"].(stats.SlowestItems)
+	if len(slowest.Items) != 5 || slowest.Measure != stats.SlowByCycleTime {
+		t.Fatalf("Expected the 5 slowest items by cycle time, got %d by %q", len(slowest.Items), slowest.Measure)
+	}
+	for i := 1; i < len(slowest.Items); i++ {
+		if slowest.Items[i].CycleTimeDays > slowest.Items[i-1].CycleTimeDays {
+			t.Errorf("Expected the slowest item first, got %+v", slowest.Items)
+		}
+	}
+	if slowest.Items[0].CycleTimePercentile != 100 || slowest.Items[0].CycleTimeDays < slowest.Bands.P95 {
+		t.Errorf("Expected the slowest item at the top of the distribution, got %+v with bands %+v", slowest.Items[0], slowest.Bands)
+	}
+
+	res, err = srv.handleFindOutliers(ctx, testProject, testBoard, stats.SlowByStatusTime, "developing", nil, 0)
+	if err != nil {
+		t.Fatalf("find_outliers by status: %v", err)
+	}
+	data := res.(ResponseEnvelope).Data.(map[string]any)
+	byStatus := data["outliers"].(stats.SlowestItems)
+	if byStatus.Status != "38777" || data["status_name"] != "developing" {
+		t.Errorf("Expected the status name to resolve to its ID, got %q (%v)", byStatus.Status, data["status_name"])
+	}
+	if len(byStatus.Items) != defaultOutlierLimit || byStatus.Items[0].StatusDays == 0 {
+		t.Errorf("Expected %d items with time in the status, got %+v", defaultOutlierLimit, byStatus.Items)
+	}
+
+	for _, tc := range []struct {
+		by     stats.SlowMeasure
+		status string
+		limit  int
+		want   string
+	}{
+		{stats.SlowByStatusTime, "", 0, "needs a 'status'"},
+		{stats.SlowByCycleTime, "developing", 0, "status_time only"},
+		{stats.SlowByStatusTime, "nowhere", 0, "unknown status"},
+		{"age", "", 0, "invalid measure"},
+		{"", "", maxOutlierLimit + 1, "invalid limit"},
+	} {
+		if _, err := srv.handleFindOutliers(ctx, testProject, testBoard, tc.by, tc.status, nil, tc.limit); err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q/%q/%d: expected an error containing %q, got %v", tc.by, tc.status, tc.limit, tc.want, err)
+		}
+	}
+}
+
+type summaryClient struct {
+	DummyClient
+	jql string
+}
+
+func (c *summaryClient) SearchIssues(_ context.Context, jql string, _ int, _ int) (*jira.SearchResponse, error) {
+	c.jql = jql
+	issue := jira.IssueDTO{Key: "PROJ-7"}
+	issue.Fields.Summary = "Export fails for large boards"
+	return &jira.SearchResponse{Total: 1, Issues: []jira.IssueDTO{issue}}, nil
+}
+
+func TestFetchSummaries(t *testing.T) {
+	ctx := context.Background()
+	client := &summaryClient{}
+	srv := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, client)
+	items := []stats.SlowItem{{Key: "PROJ-7"}}
+
+	summaries, err := srv.fetchSummaries(ctx, "PROJ", items)
+	if err != nil || summaries["PROJ-7"] != "Export fails for large boards" {
+		t.Fatalf("Expected the summary of PROJ-7, got %v (err %v)", summaries, err)
+	}
+	if client.jql != `key in ("PROJ-7")` {
+		t.Errorf("Expected a search for the returned keys only, got %q", client.jql)
+	}
+
+	client.jql = ""
+	srv.anonymizer = newAnonymizer("salt")
+	if summaries, _ := srv.fetchSummaries(ctx, "PROJ", items); len(summaries) != 0 || client.jql != "" {
+		t.Errorf("Expected no summaries for anonymized results, got %v", summaries)
+	}
+}
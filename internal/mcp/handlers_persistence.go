@@ -1,32 +1,48 @@
 package mcp
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"time"
 
+	"mcs-mcp/internal/discovery"
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
 )
 
-func (s *Server) handleGetStatusPersistence(projectKey string, boardID int) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleGetStatusPersistence(ctx context.Context, projectKey string, boardID int, trendBucket string, reconcile, byColumn bool) (any, error) {
+	if trendBucket != "" && trendBucket != "week" && trendBucket != "month" {
+		return nil, fmt.Errorf("invalid trend_bucket %q: use 'week' or 'month'", trendBucket)
+	}
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 1. Project using the session analysis window
-	window := s.AnalysisWindow("day")
-	session := s.openSession(hctx, window)
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
 
 	issues := session.GetDelivered()
 	if len(issues) == 0 {
 		return nil, fmt.Errorf("no historical data found to analyze status persistence (must have finished items)")
 	}
 
+	warnings := s.getQualityWarnings(ctx, issues)
+	mapping := s.activeMapping
+	if byColumn {
+		view, err := s.columnView(ctx, boardID)
+		if err != nil {
+			return nil, err
+		}
+		issues, mapping = view.Issues(issues), view.Mapping(mapping)
+	}
+
 	persistence := stats.CalculateStatusPersistence(issues)
-	persistence = stats.EnrichStatusPersistence(persistence, s.activeMapping)
+	persistence = stats.EnrichStatusPersistence(persistence, mapping)
 	stratified := stats.CalculateStratifiedStatusPersistence(issues)
-	tierSummary := stats.CalculateTierSummary(issues, s.activeMapping)
+	tierSummary := stats.CalculateTierSummary(issues, mapping)
 
 	res := map[string]any{
 		"persistence":            persistence,
@@ -34,19 +50,163 @@ func (s *Server) handleGetStatusPersistence(projectKey string, boardID int) (any
 		"tier_summary":           tierSummary,
 	}
 
-	guidance := []string{
-		s.windowingGuidance(),
-		"Status Persistence EXCLUSIVELY analyzes items that have successfully finished ('delivered') to prevent active WIP from skewing historical norms.",
-		"Persistence stats (coin_toss, likely, etc.) measure INTERNAL residency time WITHIN one status. They ARE NOT end-to-end completion forecasts.",
-		"Inner80 and IQR help distinguish between 'Stable Flow' and 'High Variance' bottlenecks.",
-		"Tier Summary aggregates performance by meta-workflow phase (Demand, Upstream, Downstream).",
+	if reconcile {
+		rec := stats.ReconcileResidency(issues)
+		if rec.ComparedItems == 0 {
+			return nil, fmt.Errorf("reconcile needs the time-in-status field, but no delivered item in the window has a value. Set JIRA_TIME_IN_STATUS_FIELD to the custom field (e.g. customfield_10020) and re-import the history")
+		}
+		res["residency_reconciliation"] = rec
+		if rec.ComparedPairs > 0 && rec.AgreementRate < 1-stats.ReconciliationTolerance {
+			warnings = append(warnings, fmt.Sprintf("RESIDENCY RECONCILIATION WARNING: %d of %d status residencies (%.0f%%) disagree with the time-in-status field by more than %.0f%%. Check 'residency_reconciliation' before relying on the persistence of the affected statuses.",
+				rec.Discrepancies, rec.ComparedPairs, (1-rec.AgreementRate)*100, stats.ReconciliationTolerance*100))
+		}
+	}
+
+	guidance := append([]string{s.windowingGuidance(ctx)}, s.guidanceFor("analyze_status_persistence", guidanceFacts{Reconciled: reconcile, ByColumn: byColumn})...)
+
+	if trendBucket != "" {
+		trendWindow := stats.NewAnalysisWindow(window.Start, window.End, trendBucket, window.Cutoff)
+		trend := stats.CalculateStatusPersistenceTrend(issues, persistence, trendWindow)
+		res["persistence_trend"] = trend
+		guidance = append(guidance, persistenceTrendGuidance(trend)...)
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
+
+// persistenceTrendGuidance names the statuses whose residency moved between
+// the earlier and later half of the window.
+func persistenceTrendGuidance(trend []stats.StatusPersistenceTrend) []string {
+	var out []string
+	for _, t := range trend {
+		switch t.Direction {
+		case "worsening":
+			out = append(out, fmt.Sprintf("Residency in '%s' is getting worse: P85 %.1f → %.1f days (%+.0f%%) between the earlier and later half of the window.", t.StatusName, t.EarlyP85, t.LateP85, t.P85Change*100))
+		case "improving":
+			out = append(out, fmt.Sprintf("Residency in '%s' is improving: P85 %.1f → %.1f days (%+.0f%%) between the earlier and later half of the window.", t.StatusName, t.EarlyP85, t.LateP85, t.P85Change*100))
+		}
+	}
+	return out
+}
+
+// handleAnalyzeFlowEfficiency splits the cycle time of the items delivered in
+// the session window into active and queue residency using the status roles
+// of the workflow mapping.
+func (s *Server) handleAnalyzeFlowEfficiency(ctx context.Context, projectKey string, boardID int, issueTypes []string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	hasRoles := false
+	for _, m := range s.activeMapping {
+		if m.Role == "active" || m.Role == "queue" {
+			hasRoles = true
+			break
+		}
+	}
+	if !hasRoles {
+		return nil, fmt.Errorf("flow efficiency needs status roles: confirm a workflow mapping with 'active' and 'queue' roles via 'workflow_set_mapping' first")
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+	delivered := session.GetDelivered()
+
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+	_, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", issueTypes)
+	if len(matchedIssues) == 0 {
+		return nil, fmt.Errorf("no delivered items with a cycle time in the analysis window")
+	}
+
+	rangeFor := s.cycleTimeRange(projectKey, boardID, analysisCtx.CommitmentPoint, "", delivered)
+	efficiency := stats.CalculateFlowEfficiency(matchedIssues, rangeFor, analysisCtx.WorkflowMappings)
+	for i, id := range efficiency.UnclassifiedStatuses {
+		if name := s.activeRegistry.GetStatusName(id); name != "" {
+			efficiency.UnclassifiedStatuses[i] = name
+		}
 	}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(issues), guidance), nil
+	var insights []string
+	if efficiency.Coverage < 0.8 {
+		insights = append(insights, fmt.Sprintf("Only %.0f%% of the cycle time falls in statuses with an 'active' or 'queue' role (unclassified: %s); assign roles to make the efficiency representative.",
+			efficiency.Coverage*100, strings.Join(efficiency.UnclassifiedStatuses, ", ")))
+	}
+
+	res := map[string]any{
+		"flow_efficiency": efficiency,
+	}
+	guidance := append(insights, s.guidanceFor("analyze_flow_efficiency", guidanceFacts{})...)
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, matchedIssues), guidance), nil
 }
 
-func (s *Server) handleGetAgingAnalysis(projectKey string, boardID int, agingType, tierFilter string) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+// handleAnalyzeRework counts the backward transitions of the items delivered
+// in the session window and the cycle time they spent in rework loops.
+func (s *Server) handleAnalyzeRework(ctx context.Context, projectKey string, boardID int, issueTypes []string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+	delivered := session.GetDelivered()
+
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", issueTypes)
+	if len(matchedIssues) == 0 {
+		return nil, fmt.Errorf("no delivered items with a cycle time in the analysis window")
+	}
+
+	// Backward means earlier in the confirmed workflow order, else in the
+	// discovered backbone order.
+	order := s.activeStatusOrder
+	if len(order) == 0 {
+		order = discovery.DiscoverStatusOrder(all)
+	}
+	rangeFor := s.cycleTimeRange(projectKey, boardID, analysisCtx.CommitmentPoint, "", delivered)
+	rework := stats.AnalyzeRework(matchedIssues, cycleTimes, order, rangeFor)
+	for i := range rework.Pairs {
+		p := &rework.Pairs[i]
+		if name := s.activeRegistry.GetStatusName(p.FromID); name != "" {
+			p.From = name
+		}
+		if name := s.activeRegistry.GetStatusName(p.ToID); name != "" {
+			p.To = name
+		}
+	}
+
+	var insights []string
+	if len(rework.Pairs) > 0 {
+		top := rework.Pairs[0]
+		insights = append(insights, fmt.Sprintf("Most frequent rework loop: %s → %s (%d times, %.0f%% of the items reaching %s).",
+			top.From, top.To, top.Transitions, top.ReworkRate*100, top.From))
+	}
+	if len(s.activeStatusOrder) == 0 {
+		insights = append(insights, "No confirmed workflow order: backward transitions were judged against the discovered status order. Confirm the order with 'workflow_set_order' for exact results.")
+	}
+
+	res := map[string]any{
+		"rework": rework,
+	}
+	guidance := append(insights, s.guidanceFor("analyze_rework", guidanceFacts{})...)
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, matchedIssues), guidance), nil
+}
+
+// AgingResult is the data of analyze_work_item_age. Fields are in JSON key
+// order so the result reads as it did when it was a map.
+type AgingResult struct {
+	Aging              []stats.InventoryAge `json:"aging"`
+	AgingChart         *stats.AgingChart    `json:"aging_chart,omitempty"`
+	Page               *Page                `json:"page,omitempty"`
+	Portfolio          *PortfolioSummary    `json:"portfolio,omitempty"`
+	RecommendedActions []stats.Action       `json:"recommended_actions"`
+	Summary            stats.AgingSummary   `json:"summary"`
+}
+
+func (s *Server) handleGetAgingAnalysis(ctx context.Context, projectKey string, boardID int, agingType, tierFilter string, byColumn bool) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -54,9 +214,9 @@ func (s *Server) handleGetAgingAnalysis(projectKey string, boardID int, agingTyp
 	// 1. Project — work item age is a point-in-time metric. The session window's
 	// End is the snapshot date; its Start is intentionally ignored (an item is
 	// "in-flight" at a moment, not over a range).
-	_, snapshotEnd, _ := s.Window()
+	_, snapshotEnd, _ := s.Window(ctx)
 	window := stats.NewAnalysisWindow(time.Time{}, snapshotEnd, "day", s.activeCutoff())
-	session := s.openSession(hctx, window)
+	session := s.openSession(ctx, hctx, window)
 
 	all := session.GetAllIssues()
 	wip := session.GetWIP()
@@ -65,25 +225,28 @@ func (s *Server) handleGetAgingAnalysis(projectKey string, boardID int, agingTyp
 	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
 
 	// Cycle times from history
-	cycleTimes, _ := s.getCycleTimes(projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
-
-	aging := stats.CalculateInventoryAge(wip, analysisCtx.CommitmentPoint, analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, cycleTimes, agingType, s.commitmentBackflowReset, window.End)
+	cycleTimes, _ := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+	cycleTimesByType := s.getCycleTimesByType(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+	warnings := s.getQualityWarnings(ctx, all)
 
-	// Apply tier filter if requested
-	if tierFilter != "All" && tierFilter != "" {
-		filtered := make([]stats.InventoryAge, 0)
-		for _, a := range aging {
-			if tierFilter == "WIP" {
-				if a.Tier != "Demand" && a.Tier != "Finished" {
-					filtered = append(filtered, a)
-				}
-			} else if a.Tier == tierFilter {
-				filtered = append(filtered, a)
-			}
+	// Cycle times keep the status-level commitment point; ages, persistence,
+	// and the chart are computed on the board columns from here on.
+	mapping := s.activeMapping
+	if byColumn {
+		view, err := s.columnView(ctx, boardID)
+		if err != nil {
+			return nil, err
 		}
-		aging = filtered
+		all, wip, delivered = view.Issues(all), view.Issues(wip), view.Issues(delivered)
+		analysisCtx = analysisCtx.ByColumn(view)
+		mapping = analysisCtx.WorkflowMappings
 	}
 
+	aging := stats.CalculateInventoryAgeByType(wip, analysisCtx.Commitments(), analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, cycleTimes, agingType, s.commitmentBackflowReset, window.End)
+
+	// Apply tier filter if requested
+	aging = filterAgingByTier(aging, tierFilter)
+
 	// Compute aggregate summary with risk-band distribution and stability index
 	throughput := 0.0
 	activeDays := float64(stats.CalendarDaysBetween(window.Start, window.End))
@@ -92,23 +255,68 @@ func (s *Server) handleGetAgingAnalysis(projectKey string, boardID int, agingTyp
 	}
 	summary := stats.CalculateAgingSummary(aging, cycleTimes, len(aging), throughput)
 
-	res := map[string]any{
-		"aging":   aging,
-		"summary": summary,
+	// Ranked actions: per-status WIP against Little's Law, and items older than their type's P85
+	persistence := stats.EnrichStatusPersistence(stats.CalculateStatusPersistence(delivered), mapping)
+	actions := stats.RankActions(
+		stats.StatusWIPActions(aging, persistence, throughput),
+		stats.AgingTypeActions(aging, cycleTimesByType),
+	)
+
+	res := AgingResult{
+		Aging:              aging,
+		Summary:            summary,
+		RecommendedActions: actions,
+	}
+	if agingType != "total" {
+		order := analysisCtx.StatusOrder
+		if len(order) == 0 {
+			order = discovery.DiscoverStatusOrder(all)
+		}
+		chart := stats.BuildAgingChart(aging, wip, delivered, order, analysisCtx.CommitmentPoint, analysisCtx.WorkflowMappings)
+		res.AgingChart = &chart
+	}
+	if err := s.pageAging(ctx, &res, agingType); err != nil {
+		return nil, err
 	}
 
-	guidance := []string{
-		"Work item age is a point-in-time metric, NOT a range metric. This tool ignores the session window's Start and uses ONLY its End as the as-of date for in-flight items and age calculation. To analyse 'as of' a different date, set the session window's End via 'set_analysis_window'.",
-		"Items in 'Demand' or 'Finished' tiers are usually excluded from WIP Age unless explicitly requested.",
-		"PercentileRelative helps identify which individual items are 'neglect' risks compared to historical performance.",
-		"AgeSinceCommitment reflects time since the LAST commitment (resets on backflow to Demand/Upstream).",
+	guidance := s.guidanceFor("analyze_work_item_age", guidanceFacts{ActionCount: len(actions), ByColumn: byColumn})
+
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
+
+// pageAging replaces the aging items of res with the page the call
+// asked for, if any.
+func (s *Server) pageAging(ctx context.Context, res *AgingResult, agingType string) error {
+	items, page, err := pageItems(ctx, "aging", res.Aging, agingOrders(agingType))
+	if err != nil || page == nil {
+		return err
 	}
+	res.Aging = items
+	res.Page = page
+	return nil
+}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(all), guidance), nil
+// filterAgingByTier keeps the items in tierFilter: a tier name, "WIP" (neither
+// Demand nor Finished), or "All"/"" for no filtering.
+func filterAgingByTier(aging []stats.InventoryAge, tierFilter string) []stats.InventoryAge {
+	if tierFilter == "All" || tierFilter == "" {
+		return aging
+	}
+	filtered := make([]stats.InventoryAge, 0)
+	for _, a := range aging {
+		if tierFilter == "WIP" {
+			if a.Tier != "Demand" && a.Tier != "Finished" {
+				filtered = append(filtered, a)
+			}
+		} else if a.Tier == tierFilter {
+			filtered = append(filtered, a)
+		}
+	}
+	return filtered
 }
 
-func (s *Server) handleAnalyzeResidenceTime(projectKey string, boardID int, issueTypes []string, granularity string) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleAnalyzeResidenceTime(ctx context.Context, projectKey string, boardID int, issueTypes []string, granularity string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -119,8 +327,8 @@ func (s *Server) handleAnalyzeResidenceTime(projectKey string, boardID int, issu
 
 	// 2. Project EVERYTHING from the beginning of time to capture stagnant/pre-window items
 	cutoff := s.activeCutoff()
-	fullWindow := stats.NewAnalysisWindow(time.Time{}, s.Clock(), "day", cutoff)
-	session := s.openSession(hctx, fullWindow)
+	fullWindow := stats.NewAnalysisWindow(time.Time{}, s.Clock(ctx), "day", cutoff)
+	session := s.openSession(ctx, hctx, fullWindow)
 
 	all := session.GetAllIssues()
 	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
@@ -142,7 +350,7 @@ func (s *Server) handleAnalyzeResidenceTime(projectKey string, boardID int, issu
 	}
 
 	// 4. Extract residence items with backflow-reset logic (always-on)
-	displayWindow := s.AnalysisWindow(granularity)
+	displayWindow := s.AnalysisWindow(ctx, granularity)
 	residenceItems := stats.ExtractResidenceItems(filteredIssues, analysisCtx.CommitmentPoint, analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, displayWindow.Start)
 
 	// 5. Compute the sample path time series
@@ -165,5 +373,5 @@ func (s *Server) handleAnalyzeResidenceTime(projectKey string, boardID int, issu
 		"POPULATION NOTE: The sample path population includes only items whose transition history shows at least one crossing of the commitment boundary (from a status below the commitment weight to at-or-above it). Items without such a transition have zero residence time and are excluded. D(T) may therefore be lower than throughput from analyze_throughput, which counts all delivered items regardless of commitment evidence.",
 	}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(all), guidance), nil
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
 }
@@ -0,0 +1,111 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/stats"
+)
+
+func TestFlowEfficiency(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleAnalyzeFlowEfficiency(ctx, testProject, testBoard, nil)
+	if err != nil {
+		t.Fatalf("analyze_flow_efficiency: %v", err)
+	}
+	fe := res.(ResponseEnvelope).Data.(map[string]any)["flow_efficiency"].(stats.FlowEfficiencyResult)
+	if len(fe.Items) == 0 {
+		t.Fatalf("Expected delivered items")
+	}
+	if fe.Efficiency <= 0 || fe.Efficiency >= 1 {
+		t.Errorf("Expected a pooled efficiency strictly between 0 and 1, got %v", fe.Efficiency)
+	}
+	if fe.Percentiles.P15 > fe.Percentiles.P50 || fe.Percentiles.P50 > fe.Percentiles.P85 {
+		t.Errorf("Expected ordered percentiles, got %+v", fe.Percentiles)
+	}
+	for _, item := range fe.Items {
+		if item.ActiveDays+item.QueueDays+item.UnclassifiedDays > item.CycleTimeDays+0.05 {
+			t.Errorf("%s: active, queue and unclassified time exceed the cycle time: %+v", item.Key, item)
+		}
+	}
+	for _, tier := range fe.ByTier {
+		if tier.Tier == stats.TierDemand || tier.Tier == stats.TierFinished {
+			t.Errorf("Expected only committed tiers in the breakdown, got %s", tier.Tier)
+		}
+	}
+
+	for id, m := range srv.activeMapping {
+		m.Role = ""
+		srv.activeMapping[id] = m
+	}
+	if _, err := srv.handleAnalyzeFlowEfficiency(ctx, testProject, testBoard, nil); err == nil {
+		t.Errorf("Expected an error without active/queue roles")
+	}
+}
+
+func TestRework(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleAnalyzeRework(ctx, testProject, testBoard, nil)
+	if err != nil {
+		t.Fatalf("analyze_rework: %v", err)
+	}
+	rework := res.(ResponseEnvelope).Data.(map[string]any)["rework"].(stats.ReworkResult)
+	if rework.Items == 0 {
+		t.Fatalf("Expected delivered items")
+	}
+	if rework.ReworkedItems != len(rework.ItemsWithRework) || rework.ReworkedItems > rework.Items {
+		t.Errorf("Inconsistent rework counts: %d of %d, %d listed", rework.ReworkedItems, rework.Items, len(rework.ItemsWithRework))
+	}
+	for _, p := range rework.Pairs {
+		if p.From == p.FromID && srv.activeRegistry.GetStatusName(p.FromID) != "" {
+			t.Errorf("Expected status names in pairs, got ID %s", p.FromID)
+		}
+		if p.ReworkRate <= 0 || p.ReworkRate > 1 {
+			t.Errorf("Expected a rework rate in (0, 1] for %s → %s, got %v", p.From, p.To, p.ReworkRate)
+		}
+	}
+	for _, item := range rework.ItemsWithRework {
+		if item.ReworkShare > 1 {
+			t.Errorf("%s: rework exceeds its cycle time: %+v", item.Key, item)
+		}
+	}
+}
+
+func TestStatusPersistenceTrend(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	if _, err := srv.handleGetStatusPersistence(ctx, testProject, testBoard, "quarter", false, false); err == nil {
+		t.Errorf("Expected an unknown trend bucket to be rejected")
+	}
+	res, err := srv.handleGetStatusPersistence(ctx, testProject, testBoard, "month", false, false)
+	if err != nil {
+		t.Fatalf("analyze_status_persistence: %v", err)
+	}
+	data := res.(ResponseEnvelope).Data.(map[string]any)
+	persistence := data["persistence"].([]stats.StatusPersistence)
+	trend := data["persistence_trend"].([]stats.StatusPersistenceTrend)
+	if len(trend) != len(persistence) {
+		t.Fatalf("Expected a trend per status, got %d of %d", len(trend), len(persistence))
+	}
+	for _, st := range trend {
+		if len(st.Buckets) < 6 || st.Direction == "" {
+			t.Errorf("Expected a monthly series over the 26-week window with a direction, got %d buckets, %q for %s", len(st.Buckets), st.Direction, st.StatusName)
+		}
+	}
+}
+
+func TestStatusPersistenceReconcile_NeedsField(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	_, err := srv.handleGetStatusPersistence(ctx, testProject, testBoard, "", true, false)
+	if err == nil || !strings.Contains(err.Error(), "JIRA_TIME_IN_STATUS_FIELD") {
+		t.Errorf("Expected reconcile without a time-in-status field to name the setting, got %v", err)
+	}
+}
@@ -0,0 +1,127 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// pointsForecast is the units=points branch of forecast_monte_carlo. It runs
+// req again in estimate points and compares the result with the item
+// forecast itemsRes of the same request. scope holds the backlog and WIP
+// items to forecast; they and the additional items are counted at the median
+// estimate of delivered items when they have no estimate of their own.
+func (s *Server) pointsForecast(ctx context.Context, req simulation.ForecastRequest, itemsRes simulation.Result, scope []jira.Issue, additionalItems, boardID int) (simulation.Result, error) {
+	median := simulation.MedianEstimate(req.Finished)
+	if median <= 0 {
+		return simulation.Result{}, s.noEstimatesError(ctx, boardID)
+	}
+
+	points, unestimated := 0.0, additionalItems
+	for _, issue := range scope {
+		if issue.Estimate != nil {
+			points += *issue.Estimate
+		} else {
+			points += median
+			unestimated++
+		}
+	}
+	points += float64(additionalItems) * median
+	scopePoints := int(math.Ceil(points))
+
+	res, err := simulation.RunPointsForecast(ctx, req, scopePoints)
+	if err != nil {
+		return simulation.Result{}, fmt.Errorf("points forecast failed: %w", err)
+	}
+	if res.Context == nil {
+		res.Context = make(map[string]any)
+	}
+	itemsRes.Percentiles.Round()
+	res.Context["units"] = "points"
+	res.Context["items_percentiles"] = itemsRes.Percentiles
+	if req.Mode == "duration" {
+		res.Context["scope_points"] = scopePoints
+		if unestimated > 0 {
+			res.Warnings = append(res.Warnings, fmt.Sprintf("UNESTIMATED SCOPE: %d item(s) to forecast have no estimate; each was counted at the median estimate of delivered items (%.1f points).", unestimated, median))
+		}
+	}
+	res.Warnings = append(res.Warnings, compareUnits(req.Mode, res.Percentiles, itemsRes.Percentiles, meanEstimate(req.Finished)))
+	return res, nil
+}
+
+// compareUnits describes how far the P85 of a points forecast is from that
+// of the item forecast. Scope forecasts are compared in points, converting
+// items at the historical mean estimate.
+func compareUnits(mode string, points, items simulation.Percentiles, mean float64) string {
+	var text string
+	var diff float64
+	if mode == "scope" {
+		asPoints := items.Likely * mean
+		diff = relativeDiff(points.Likely, asPoints)
+		text = fmt.Sprintf("POINTS VS ITEMS: at P85 the points forecast delivers %.0f points by the target date; the item forecast delivers %.0f items, about %.0f points at the historical mean of %.1f points per item (%+.0f%%).", points.Likely, items.Likely, asPoints, mean, diff*100)
+	} else {
+		diff = relativeDiff(points.Likely, items.Likely)
+		text = fmt.Sprintf("POINTS VS ITEMS: at P85 the points forecast needs %.0f days; the item forecast needs %.0f days (%+.0f%%).", points.Likely, items.Likely, diff*100)
+	}
+	if math.Abs(diff) > PointsItemsDivergence {
+		return text + " The forecasts diverge: the remaining work is sized differently from past deliveries, or estimates vary widely. Present both and ask which assumption the team trusts."
+	}
+	return text + " The forecasts agree; counting items is as good as summing estimates here."
+}
+
+// relativeDiff returns (a-b)/b, or 0 when b is 0.
+func relativeDiff(a, b float64) float64 {
+	if b == 0 {
+		return 0
+	}
+	return (a - b) / b
+}
+
+// meanEstimate returns the mean estimate of the delivered issues that have one.
+func meanEstimate(issues []jira.Issue) float64 {
+	sum, n := 0.0, 0
+	for _, issue := range issues {
+		if stats.IsDelivered(issue) && issue.Estimate != nil {
+			sum += *issue.Estimate
+			n++
+		}
+	}
+	if n == 0 {
+		return 0
+	}
+	return sum / float64(n)
+}
+
+// noEstimatesError explains that no estimates were fetched, naming the
+// estimation field of the board when Jira reports one.
+func (s *Server) noEstimatesError(ctx context.Context, boardID int) error {
+	hint := ""
+	if id, name := s.boardEstimationField(ctx, boardID); id != "" {
+		hint = fmt.Sprintf(" Board %d estimates with %s (%s).", boardID, name, id)
+	}
+	return fmt.Errorf("units=points needs estimates, but no delivered item in the sampling window has one. Set JIRA_ESTIMATE_FIELD to the board's estimation field and re-import the history.%s", hint)
+}
+
+// boardEstimationField returns the ID and name of the custom field a board
+// estimates with, from its configuration; empty for boards that estimate by
+// issue count or for query sources.
+func (s *Server) boardEstimationField(ctx context.Context, boardID int) (string, string) {
+	if boardID <= 0 {
+		return "", ""
+	}
+	cfg, err := s.jira.GetBoardConfig(ctx, boardID)
+	if err != nil {
+		return "", ""
+	}
+	conf, _ := cfg.(map[string]any)
+	estimation, _ := conf["estimation"].(map[string]any)
+	field, _ := estimation["field"].(map[string]any)
+	if asString(estimation["type"]) != "field" || field == nil {
+		return "", ""
+	}
+	return asString(field["fieldId"]), asString(field["displayName"])
+}
@@ -0,0 +1,607 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// portfolioMember is one board of a portfolio, projected with its own workflow.
+type portfolioMember struct {
+	ProjectKey string
+	BoardID    int
+	SourceID   string
+	Confirmed  bool // a user-confirmed workflow mapping was loaded
+	Session    *stats.AnalysisSession
+	Analysis   *AnalysisContext
+}
+
+// PortfolioSummary describes how a portfolio was consolidated.
+type PortfolioSummary struct {
+	Sources      []PortfolioSourceSummary `json:"sources"`
+	SharedIssues int                      `json:"shared_issues"` // issues visible on more than one board
+	Duplicates   stats.DuplicatePolicy    `json:"duplicates"`    // how shared issues are attributed
+}
+
+// PortfolioSourceSummary is one board's share of a consolidated portfolio.
+type PortfolioSourceSummary struct {
+	SourceID          string  `json:"source_id"`
+	ProjectKey        string  `json:"project_key"`
+	BoardID           int     `json:"board_id"`
+	WorkflowConfirmed bool    `json:"workflow_confirmed"`
+	Issues            float64 `json:"issues"` // issues attributed to this board; fractional under the split policy
+	Delivered         float64 `json:"delivered"`
+	WIP               float64 `json:"wip"`
+}
+
+// maxListedDuplicates caps the keys listed in the shared-issues warning.
+const maxListedDuplicates = 20
+
+// boardState is a snapshot of the per-board active fields.
+type boardState struct {
+	sourceID        string
+	mapping         map[string]stats.StatusMetadata
+	resolutions     map[string]string
+	statusOrder     []string
+	commitmentPoint string
+	typeCommitments map[string]string
+	sles            map[string]stats.ServiceLevelExpectation
+	wipLimits       map[string]stats.WIPLimit
+	alertRules      map[string]stats.AlertRule
+	typeAliases     simulation.TypeAliases
+	settings        SourceSettings
+	templates       map[string]ForecastTemplate
+	discoveryCutoff *time.Time
+	confirmedAt     *time.Time
+	evaluationDate  *time.Time
+	registry        *jira.NameRegistry
+	boardName       string
+	projectName     string
+}
+
+func (s *Server) snapshotBoardState() boardState {
+	return boardState{
+		sourceID:        s.activeSourceID,
+		mapping:         s.activeMapping,
+		resolutions:     s.activeResolutions,
+		statusOrder:     s.activeStatusOrder,
+		commitmentPoint: s.activeCommitmentPoint,
+		typeCommitments: s.activeTypeCommitments,
+		sles:            s.activeSLEs,
+		wipLimits:       s.activeWIPLimits,
+		alertRules:      s.activeAlertRules,
+		typeAliases:     s.activeTypeAliases,
+		settings:        s.activeSettings,
+		templates:       s.activeForecastTemplates,
+		discoveryCutoff: s.activeDiscoveryCutoff,
+		confirmedAt:     s.activeMappingConfirmed,
+		evaluationDate:  s.activeEvaluationDate,
+		registry:        s.activeRegistry,
+		boardName:       s.activeBoardName,
+		projectName:     s.activeProjectName,
+	}
+}
+
+func (s *Server) restoreBoardState(b boardState) {
+	s.activeSourceID = b.sourceID
+	s.activeMapping = b.mapping
+	s.activeResolutions = b.resolutions
+	s.activeStatusOrder = b.statusOrder
+	s.activeCommitmentPoint = b.commitmentPoint
+	s.activeTypeCommitments = b.typeCommitments
+	s.activeSLEs = b.sles
+	s.activeWIPLimits = b.wipLimits
+	s.activeAlertRules = b.alertRules
+	s.activeTypeAliases = b.typeAliases
+	s.activeSettings = b.settings
+	s.activeForecastTemplates = b.templates
+	s.activeDiscoveryCutoff = b.discoveryCutoff
+	s.activeMappingConfirmed = b.confirmedAt
+	s.activeEvaluationDate = b.evaluationDate
+	s.activeRegistry = b.registry
+	s.activeBoardName = b.boardName
+	s.activeProjectName = b.projectName
+}
+
+// loadPortfolioSource loads the saved workflow of a non-active board into the
+// active fields and hydrates its event log. Unlike anchorContext it does not
+// prune the event store, so every board of the portfolio stays in memory, and
+// it persists nothing: members are read-only projections.
+func (s *Server) loadPortfolioSource(ctx context.Context, src PortfolioSource) (*handlerContext, bool, error) {
+	sourceID := getCombinedID(src.ProjectKey, src.BoardID)
+	s.restoreBoardState(boardState{sourceID: sourceID})
+
+	confirmed, err := s.loadWorkflow(src.ProjectKey, src.BoardID)
+	if err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to load workflow metadata from disk")
+	}
+	sc, err := s.resolveSourceContext(ctx, src.ProjectKey, src.BoardID)
+	if err != nil {
+		return nil, false, err
+	}
+	reg, err := s.events.Hydrate(ctx, sourceID, src.ProjectKey, sc.JQL, s.activeRegistry)
+	if err != nil {
+		return nil, false, err
+	}
+	s.activeRegistry = reg
+	return &handlerContext{SourceID: sourceID, Ctx: sc}, confirmed, nil
+}
+
+// projectPortfolio projects the anchored board (hctx) and each additional
+// source over [start, end]. Every board uses its own workflow mapping,
+// commitment point, and discovery cutoff. each, if non-nil, runs while the
+// member's workflow is loaded into the active fields, for computations that
+// read server state. The anchored board's state is restored afterwards.
+// Sources repeating the anchored board or each other are ignored.
+func (s *Server) projectPortfolio(ctx context.Context, hctx *handlerContext, projectKey string, boardID int, sources []PortfolioSource, start, end time.Time, bucket string, each func(i int, m *portfolioMember)) ([]*portfolioMember, error) {
+	primary := s.snapshotBoardState()
+	defer s.restoreBoardState(primary)
+
+	seen := map[string]bool{hctx.SourceID: true}
+	var members []*portfolioMember
+	project := func(pk string, bid int, h *handlerContext, confirmed bool) {
+		window := stats.NewAnalysisWindow(start, end, bucket, s.activeCutoff())
+		session := s.openSession(ctx, h, window)
+		m := &portfolioMember{
+			ProjectKey: pk,
+			BoardID:    bid,
+			SourceID:   h.SourceID,
+			Confirmed:  confirmed,
+			Session:    session,
+			Analysis:   s.prepareAnalysisContext(pk, bid, session.GetAllIssues()),
+		}
+		if each != nil {
+			each(len(members), m)
+		}
+		members = append(members, m)
+	}
+
+	project(projectKey, boardID, hctx, len(s.activeMapping) > 0)
+	for _, src := range sources {
+		sourceID := getCombinedID(src.ProjectKey, src.BoardID)
+		if seen[sourceID] {
+			continue
+		}
+		seen[sourceID] = true
+
+		h, confirmed, err := s.loadPortfolioSource(ctx, src)
+		if err != nil {
+			return nil, fmt.Errorf("portfolio source %s: %w", sourceID, err)
+		}
+		project(src.ProjectKey, src.BoardID, h, confirmed)
+	}
+	return members, nil
+}
+
+// requireConfirmedWorkflows fails unless every member has a confirmed workflow
+// mapping: tiers and commitment points differ per board and cannot be guessed
+// for a consolidated analysis.
+func requireConfirmedWorkflows(members []*portfolioMember) error {
+	for _, m := range members {
+		if !m.Confirmed {
+			return fmt.Errorf("portfolio source %s has no confirmed workflow mapping: run 'import_board_context', 'workflow_discover_mapping', and 'workflow_set_mapping' for board %d first", m.SourceID, m.BoardID)
+		}
+	}
+	return nil
+}
+
+// attributePortfolio attributes the issues shared by several members under
+// the duplicates parameter of the call, else MCS_PORTFOLIO_DUPLICATES. The
+// attribution covers every issue a member projects, so that the delivered,
+// WIP, and backlog items of a shared issue all follow the same member.
+func (s *Server) attributePortfolio(members []*portfolioMember, policy stats.DuplicatePolicy) (stats.Attribution, error) {
+	if policy == "" {
+		policy = s.duplicatePolicy
+	}
+	policy, err := stats.ParseDuplicatePolicy(string(policy))
+	if err != nil {
+		return stats.Attribution{}, err
+	}
+	issues := make([][]jira.Issue, len(members))
+	for i, m := range members {
+		issues[i] = m.Session.GetAllIssues()
+	}
+	return stats.AttributeDuplicates(issues, policy), nil
+}
+
+// ownedIssues concatenates the members' issues selected by pick, keeping each
+// issue only from the members that count it.
+func ownedIssues(members []*portfolioMember, attr stats.Attribution, pick func(i int, m *portfolioMember) []jira.Issue) []jira.Issue {
+	var out []jira.Issue
+	for i, m := range members {
+		for _, issue := range pick(i, m) {
+			if attr.Counts(i, issue.Key) {
+				out = append(out, issue)
+			}
+		}
+	}
+	return out
+}
+
+// summarizePortfolio reports each member's share of the consolidated portfolio.
+func summarizePortfolio(members []*portfolioMember, attr stats.Attribution) PortfolioSummary {
+	summary := PortfolioSummary{SharedIssues: len(attr.Shared()), Duplicates: attr.Policy}
+	for i, m := range members {
+		src := PortfolioSourceSummary{
+			SourceID:          m.SourceID,
+			ProjectKey:        m.ProjectKey,
+			BoardID:           m.BoardID,
+			WorkflowConfirmed: m.Confirmed,
+		}
+		count := func(issues []jira.Issue) float64 {
+			n := 0.0
+			for _, issue := range issues {
+				n += attr.Share(i, issue.Key)
+			}
+			return stats.Round2(n)
+		}
+		src.Issues = count(m.Session.GetAllIssues())
+		src.Delivered = count(m.Session.GetDelivered())
+		src.WIP = count(m.Session.GetWIP())
+		summary.Sources = append(summary.Sources, src)
+	}
+	return summary
+}
+
+// duplicateWarnings lists the issues shared by several boards and says how
+// the policy counted them.
+func duplicateWarnings(attr stats.Attribution) []string {
+	shared := attr.Shared()
+	if len(shared) == 0 {
+		return nil
+	}
+	how := map[stats.DuplicatePolicy]string{
+		stats.DuplicatesFirst: "counted once, on the board that resolved them or else the first board listing them",
+		stats.DuplicatesSplit: "counted once, and shared equally among their boards in 'portfolio.sources'",
+		stats.DuplicatesAll:   "counted on every board listing them, so consolidated totals include them more than once",
+	}[attr.Policy]
+	keys := strings.Join(shared[:min(len(shared), maxListedDuplicates)], ", ")
+	if len(shared) > maxListedDuplicates {
+		keys += fmt.Sprintf(", and %d more", len(shared)-maxListedDuplicates)
+	}
+	return []string{fmt.Sprintf("%d issue(s) appear on more than one board (duplicates=%s: %s): %s.", len(shared), attr.Policy, how, keys)}
+}
+
+func (s *Server) handleImportPortfolio(ctx context.Context, sources []PortfolioSource, duplicates stats.DuplicatePolicy) (any, error) {
+	if len(sources) < 2 {
+		return nil, fmt.Errorf("a portfolio needs at least two sources")
+	}
+	first := sources[0]
+
+	hctx, err := s.prepareHandler(ctx, first.ProjectKey, first.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	members, err := s.projectPortfolio(ctx, hctx, first.ProjectKey, first.BoardID, sources[1:], time.Time{}, s.Clock(ctx), "day", nil)
+	if err != nil {
+		return nil, err
+	}
+	attr, err := s.attributePortfolio(members, duplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	res := map[string]any{
+		"portfolio": summarizePortfolio(members, attr),
+	}
+
+	guidance := s.guidanceFor("import_portfolio", guidanceFacts{})
+	if err := requireConfirmedWorkflows(members); err != nil {
+		guidance = append(guidance, "Not every board is ready for portfolio analysis: "+err.Error())
+	}
+
+	return WrapResponse(res, first.ProjectKey, first.BoardID, nil, duplicateWarnings(attr), guidance), nil
+}
+
+// handlePortfolioThroughput is analyze_throughput over several boards: delivered
+// items are deduplicated across boards and bucketed as one delivery system.
+func (s *Server) handlePortfolioThroughput(ctx context.Context, projectKey string, boardID int, sources []PortfolioSource, duplicates stats.DuplicatePolicy, bucket string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	window := s.AnalysisWindow(ctx, bucket)
+	start, end, _ := s.Window(ctx)
+
+	members, err := s.projectPortfolio(ctx, hctx, projectKey, boardID, sources, start, end, bucket, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := requireConfirmedWorkflows(members); err != nil {
+		return nil, err
+	}
+	attr, err := s.attributePortfolio(members, duplicates)
+	if err != nil {
+		return nil, err
+	}
+	delivered := ownedIssues(members, attr, func(_ int, m *portfolioMember) []jira.Issue { return m.Session.GetDelivered() })
+
+	throughput := stats.GetStratifiedThroughput(delivered, window)
+	throughput.XmR = stats.AnalyzeThroughputStability(throughput)
+
+	summary := summarizePortfolio(members, attr)
+	res := ThroughputResult{
+		TotalThroughput:      throughput.Pooled,
+		StratifiedThroughput: throughput.ByType,
+		Portfolio:            &summary,
+	}
+	if throughput.XmR != nil {
+		throughput.XmR.SetSignalDates(window.BucketDates())
+		throughput.XmR.Round()
+		res.Stability = throughput.XmR
+	}
+
+	guidance := append(s.guidanceFor("analyze_throughput", guidanceFacts{Portfolio: true}),
+		s.windowingGuidance(ctx),
+		fmt.Sprintf("Throughput is grouped by %s.", bucket),
+	)
+
+	warnings := append(duplicateWarnings(attr), s.getQualityWarnings(ctx, delivered)...)
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
+
+// handlePortfolioAging is analyze_work_item_age over several boards. Each board's
+// WIP is aged against its own commitment point; percentiles and the summary use
+// the consolidated cycle-time history.
+func (s *Server) handlePortfolioAging(ctx context.Context, projectKey string, boardID int, sources []PortfolioSource, duplicates stats.DuplicatePolicy, agingType, tierFilter string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	_, snapshotEnd, _ := s.Window(ctx)
+
+	cycleTimesByMember := make(map[int][]float64)
+	cycleTimesByType := make(map[string][]float64)
+	members, err := s.projectPortfolio(ctx, hctx, projectKey, boardID, sources, time.Time{}, snapshotEnd, "day", func(i int, m *portfolioMember) {
+		delivered := m.Session.GetDelivered()
+		cycleTimesByMember[i], _ = s.getCycleTimes(ctx, m.ProjectKey, m.BoardID, delivered, m.Analysis.CommitmentPoint, "", nil)
+		for t, cts := range s.getCycleTimesByType(ctx, m.ProjectKey, m.BoardID, delivered, m.Analysis.CommitmentPoint, "", nil) {
+			cycleTimesByType[t] = append(cycleTimesByType[t], cts...)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := requireConfirmedWorkflows(members); err != nil {
+		return nil, err
+	}
+	attr, err := s.attributePortfolio(members, duplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	var cycleTimes []float64
+	for i := range members {
+		cycleTimes = append(cycleTimes, cycleTimesByMember[i]...)
+	}
+
+	var aging []stats.InventoryAge
+	for i, m := range members {
+		var wip []jira.Issue
+		for _, issue := range m.Session.GetWIP() {
+			if attr.Counts(i, issue.Key) {
+				wip = append(wip, issue)
+			}
+		}
+		a := m.Analysis
+		aging = append(aging, stats.CalculateInventoryAgeByType(wip, a.Commitments(), a.StatusWeights, a.WorkflowMappings, cycleTimes, agingType, s.commitmentBackflowReset, m.Session.Window().End)...)
+	}
+	aging = filterAgingByTier(aging, tierFilter)
+
+	delivered := ownedIssues(members, attr, func(_ int, m *portfolioMember) []jira.Issue { return m.Session.GetDelivered() })
+	window := members[0].Session.Window()
+	throughput := 0.0
+	if activeDays := float64(stats.CalendarDaysBetween(window.Start, window.End)); activeDays > 0 {
+		throughput = float64(len(delivered)) / activeDays
+	}
+	summary := stats.CalculateAgingSummary(aging, cycleTimes, len(aging), throughput)
+	actions := stats.RankActions(stats.AgingTypeActions(aging, cycleTimesByType))
+
+	portfolio := summarizePortfolio(members, attr)
+	res := AgingResult{
+		Aging:              aging,
+		Summary:            summary,
+		RecommendedActions: actions,
+		Portfolio:          &portfolio,
+	}
+	if err := s.pageAging(ctx, &res, agingType); err != nil {
+		return nil, err
+	}
+
+	all := ownedIssues(members, attr, func(_ int, m *portfolioMember) []jira.Issue { return m.Session.GetAllIssues() })
+	guidance := s.guidanceFor("analyze_work_item_age", guidanceFacts{ActionCount: len(actions), Portfolio: true})
+
+	warnings := append(duplicateWarnings(attr), s.getQualityWarnings(ctx, all)...)
+	return WrapResponse(res, projectKey, boardID, nil, warnings, guidance), nil
+}
+
+// handlePortfolioSimulation is forecast_monte_carlo over several boards: the
+// deduplicated deliveries of all boards form one throughput sample, and
+// backlog and WIP are counted with each board's own tiers.
+func (s *Server) handlePortfolioSimulation(ctx context.Context, args ForecastMonteCarloInput) (any, error) {
+	mode, sampling := string(args.Mode), string(args.Sampling)
+	hctx, err := s.prepareHandler(ctx, args.ProjectKey, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	histStart, histEnd, err := s.forecastSampleWindow(ctx, args.HistoryWindowDays, args.HistoryStartDate, args.HistoryEndDate, false)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.resolveCalendar(args.Holidays, args.FreezePeriods)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateSampling(sampling); err != nil {
+		return nil, err
+	}
+	capacity, err := capacityScenario(args.CapacityFactor, args.TeamChange)
+	if err != nil {
+		return nil, err
+	}
+	finalTargetDays, err := s.resolveTargetDays(ctx, mode, args.TargetDays, args.TargetDate)
+	if err != nil {
+		return nil, err
+	}
+	deadlineDays, err := s.resolveDeadline(ctx, mode, args.TargetDays, args.TargetDate)
+	if err != nil {
+		return nil, err
+	}
+	window := stats.NewAnalysisWindow(histStart, histEnd, "day", s.activeCutoff())
+
+	backlogByMember := make(map[int][]jira.Issue)
+	wipByMember := make(map[int][]jira.Issue)
+	members, err := s.projectPortfolio(ctx, hctx, args.ProjectKey, args.BoardID, args.Sources, histStart, histEnd, "day", func(i int, m *portfolioMember) {
+		a := m.Analysis
+		all := m.Session.GetAllIssues()
+		containers := s.excludedContainers(ctx, all)
+		for _, issue := range all {
+			if containers[issue.Key] {
+				continue
+			}
+			if meta, ok := a.WorkflowMappings[issue.StatusID]; ok && (meta.Tier == "Demand" || meta.Tier == "Upstream") {
+				backlogByMember[i] = append(backlogByMember[i], issue)
+			}
+		}
+		wip := m.Session.GetWIP()
+		if s.commitmentBackflowReset {
+			wip = nil
+			keys, groups := a.Commitments().Group(m.Session.GetWIP())
+			for _, cp := range keys {
+				weight := 2
+				if w, ok := a.StatusWeights[cp]; ok && cp != "" {
+					weight = w
+				}
+				wip = append(wip, stats.ApplyBackflowPolicy(groups[cp], a.StatusWeights, weight, s.Clock(ctx))...)
+			}
+		}
+		wipByMember[i] = wip
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := requireConfirmedWorkflows(members); err != nil {
+		return nil, err
+	}
+	attr, err := s.attributePortfolio(members, args.Duplicates)
+	if err != nil {
+		return nil, err
+	}
+
+	all := ownedIssues(members, attr, func(_ int, m *portfolioMember) []jira.Issue { return m.Session.GetAllIssues() })
+	finished := ownedIssues(members, attr, func(_ int, m *portfolioMember) []jira.Issue { return m.Session.GetFinished() })
+	wip := ownedIssues(members, attr, func(i int, _ *portfolioMember) []jira.Issue { return wipByMember[i] })
+
+	actualTargets := make(map[string]int)
+	var backlogCount, wipCount int
+	if len(args.Targets) > 0 {
+		for k, v := range args.Targets {
+			actualTargets[k] = v
+		}
+	} else {
+		if args.IncludeExistingBacklog {
+			for _, issue := range ownedIssues(members, attr, func(i int, _ *portfolioMember) []jira.Issue { return backlogByMember[i] }) {
+				actualTargets[issue.IssueType]++
+				backlogCount++
+			}
+		}
+		if args.IncludeWIP {
+			for _, issue := range wip {
+				actualTargets[issue.IssueType]++
+				wipCount++
+			}
+		}
+		addAdditionalItems(actualTargets, args.IssueTypes, args.AdditionalItems)
+	}
+
+	log.Info().
+		Str("tool", "forecast_monte_carlo").
+		Str("engine", s.engineName).
+		Int("sources", len(members)).
+		Msg("tool executed")
+
+	// Boards have different workflows, so no single commitment point or status
+	// weighting applies to the consolidated sample.
+	req := simulation.ForecastRequest{
+		Mode:            mode,
+		AllIssues:       all,
+		Finished:        finished,
+		WIP:             wip,
+		WindowStart:     window.Start,
+		WindowEnd:       window.End,
+		DiscoveryCutoff: window.Cutoff,
+		Targets:         actualTargets,
+		MixOverrides:    args.MixOverrides,
+		TargetDays:      finalTargetDays,
+		DeadlineDays:    deadlineDays,
+		Calendar:        calendar,
+		Sampling:        sampling,
+		Capacity:        capacity,
+		Outcomes:        s.outcomes(ctx),
+		IssueTypes:      args.IssueTypes,
+		TypeAliases:     s.activeTypeAliases,
+		SimulationSeed:  s.simulationSeed,
+		Clock:           s.Clock(ctx),
+	}
+
+	selectedEngine, err := s.resolveEngine(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("engine resolution failed: %w", err)
+	}
+	resObj, err := selectedEngine.Run(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("simulation failed: %w", err)
+	}
+
+	resObj.Round()
+	resObj.Warnings = append(resObj.Warnings, duplicateWarnings(attr)...)
+	resObj.Warnings = append(resObj.Warnings, s.getQualityWarnings(ctx, all)...)
+	resObj.Composition = &simulation.Composition{
+		ExistingBacklog: backlogCount,
+		WIP:             wipCount,
+		AdditionalItems: args.AdditionalItems,
+		Total:           backlogCount + wipCount + args.AdditionalItems,
+	}
+	s.annotateForecast(ctx, &resObj, mode, finalTargetDays, calendar)
+	resObj.Context["portfolio"] = summarizePortfolio(members, attr)
+	sourceIDs := make([]string, len(members))
+	for i, m := range members {
+		sourceIDs[i] = m.SourceID
+	}
+	inputs := ForecastInputs{
+		Mode:                   mode,
+		TimeUnit:               "day",
+		Sources:                sourceIDs,
+		Duplicates:             string(attr.Policy),
+		Targets:                actualTargets,
+		IssueTypes:             args.IssueTypes,
+		IncludeExistingBacklog: args.IncludeExistingBacklog,
+		IncludeWIP:             args.IncludeWIP,
+		AdditionalItems:        args.AdditionalItems,
+		MixOverrides:           args.MixOverrides,
+		Sampling:               sampling,
+		CapacityFactor:         args.CapacityFactor,
+		TeamChange:             args.TeamChange,
+		SampleStart:            histStart.Format(stats.DateFormat),
+		SampleEnd:              histEnd.Format(stats.DateFormat),
+	}
+	annotateCapacity(&resObj, inputs)
+	if mode == "scope" {
+		inputs.TargetDays = finalTargetDays
+	}
+	s.trackForecast(ctx, getCombinedID(args.ProjectKey, args.BoardID), selectedEngine.Name(), inputs, &resObj)
+
+	warnings := resObj.Warnings
+	insights := append(resObj.Insights, s.guidanceFor("forecast_monte_carlo", guidanceFacts{FatTailRatio: resObj.FatTailRatio, Portfolio: true, SparseSample: sparseEmpiricalSample(resObj.Context)})...)
+	resObj.Warnings = nil
+	resObj.Insights = nil
+
+	return WrapResponse(resObj, args.ProjectKey, args.BoardID, nil, warnings, insights), nil
+}
@@ -0,0 +1,209 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// newPortfolioServer returns a golden server plus a second MCSTEST board that
+// shares every issue of the first and delivers one issue of its own.
+func newPortfolioServer(t *testing.T) *Server {
+	ctx := context.Background()
+	t.Helper()
+	srv := newGoldenServer(t)
+
+	data, err := os.ReadFile(filepath.Join(srv.cacheDir, testSourceID+".jsonl"))
+	if err != nil {
+		t.Fatalf("read events cache: %v", err)
+	}
+	ts := srv.Clock(ctx).AddDate(0, 0, -10).UnixMicro()
+	day := int64(86400 * 1e6)
+	for _, e := range []eventlog.IssueEvent{
+		{EventType: eventlog.Created, IssueKey: "OTHER-1", IssueType: "Story", ToStatus: "Open", ToStatusID: "1", Timestamp: ts},
+		{EventType: eventlog.Change, IssueKey: "OTHER-1", IssueType: "Story", ToStatus: "developing", ToStatusID: "38777", Timestamp: ts + day},
+		{EventType: eventlog.Change, IssueKey: "OTHER-1", IssueType: "Story", ToStatus: "Done", ToStatusID: "10003", Resolution: "Done", Timestamp: ts + 3*day},
+	} {
+		line, err := json.Marshal(e)
+		if err != nil {
+			t.Fatalf("marshal event: %v", err)
+		}
+		data = append(data, append(line, '\n')...)
+	}
+	if err := os.WriteFile(filepath.Join(srv.cacheDir, getCombinedID(testProject, 1)+".jsonl"), data, 0644); err != nil {
+		t.Fatalf("write second board cache: %v", err)
+	}
+	if err := srv.saveWorkflow(testProject, 1); err != nil {
+		t.Fatalf("save second board workflow: %v", err)
+	}
+	return srv
+}
+
+func TestPortfolio_MembersAreNotPersisted(t *testing.T) {
+	ctx := context.Background()
+	srv := newPortfolioServer(t)
+	member := filepath.Join(srv.cacheDir, getCombinedID(testProject, 1)+"_workflow.json")
+	saved := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	if err := os.Chtimes(member, saved, saved); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := srv.handlePortfolioThroughput(ctx, testProject, testBoard, []PortfolioSource{{ProjectKey: testProject, BoardID: 1}}, "", "week"); err != nil {
+		t.Fatalf("analyze_throughput: %v", err)
+	}
+	if info, err := os.Stat(member); err != nil || !info.ModTime().Equal(saved) {
+		t.Errorf("Expected the portfolio projection to leave the member's workflow file alone, got %v", err)
+	}
+}
+
+func TestPortfolioThroughput(t *testing.T) {
+	ctx := context.Background()
+	srv := newPortfolioServer(t)
+
+	single, err := srv.handleGetDeliveryCadence(ctx, testProject, testBoard, "week", "", false, "")
+	if err != nil {
+		t.Fatalf("analyze_throughput: %v", err)
+	}
+	res, err := srv.handlePortfolioThroughput(ctx, testProject, testBoard, []PortfolioSource{{ProjectKey: testProject, BoardID: 1}}, "", "week")
+	if err != nil {
+		t.Fatalf("portfolio throughput: %v", err)
+	}
+
+	sum := func(counts []int) int {
+		n := 0
+		for _, c := range counts {
+			n += c
+		}
+		return n
+	}
+	singleTotal := sum(single.(ResponseEnvelope).Data.(ThroughputResult).TotalThroughput)
+	data := res.(ResponseEnvelope).Data.(ThroughputResult)
+	if got := sum(data.TotalThroughput); got != singleTotal+1 {
+		t.Errorf("Expected shared issues to be counted once (%d + 1 delivered), got %d", singleTotal, got)
+	}
+
+	summary := *data.Portfolio
+	if len(summary.Sources) != 2 || summary.SharedIssues == 0 {
+		t.Fatalf("Expected two sources with shared issues, got %+v", summary)
+	}
+	if summary.Sources[1].Issues != 1 || summary.Sources[1].Delivered != 1 {
+		t.Errorf("Expected the second board to own only its own issue, got %+v", summary.Sources[1])
+	}
+	if srv.activeSourceID != testSourceID || len(srv.activeMapping) == 0 {
+		t.Errorf("Expected the anchored board's state to be restored, got source %q", srv.activeSourceID)
+	}
+}
+
+func TestPortfolioDuplicates(t *testing.T) {
+	ctx := context.Background()
+	srv := newPortfolioServer(t)
+	sources := []PortfolioSource{{ProjectKey: testProject, BoardID: 1}}
+
+	throughput := func(policy stats.DuplicatePolicy) (int, PortfolioSummary, []string) {
+		t.Helper()
+		res, err := srv.handlePortfolioThroughput(ctx, testProject, testBoard, sources, policy, "week")
+		if err != nil {
+			t.Fatalf("portfolio throughput (%s): %v", policy, err)
+		}
+		env := res.(ResponseEnvelope)
+		data := env.Data.(ThroughputResult)
+		n := 0
+		for _, c := range data.TotalThroughput {
+			n += c
+		}
+		return n, *data.Portfolio, env.Guardrails.Warnings
+	}
+
+	first, firstSummary, warnings := throughput("")
+	if firstSummary.Duplicates != stats.DuplicatesFirst {
+		t.Errorf("Expected the first policy by default, got %q", firstSummary.Duplicates)
+	}
+	if len(warnings) == 0 || !strings.Contains(warnings[0], "duplicates=first") || !strings.Contains(warnings[0], "more") {
+		t.Errorf("Expected a warning listing the shared keys, got %v", warnings)
+	}
+
+	split, splitSummary, _ := throughput(stats.DuplicatesSplit)
+	if split != first {
+		t.Errorf("Expected split to count shared issues once (%d), got %d", first, split)
+	}
+	board0, board1 := splitSummary.Sources[0], splitSummary.Sources[1]
+	if board0.Delivered != board1.Delivered-1 || board0.Delivered+board1.Delivered != firstSummary.Sources[0].Delivered+firstSummary.Sources[1].Delivered {
+		t.Errorf("Expected shared deliveries split equally between the boards, got %+v", splitSummary.Sources)
+	}
+
+	all, allSummary, _ := throughput(stats.DuplicatesAll)
+	if want := 2*first - 1; all != want {
+		t.Errorf("Expected all to count shared issues on both boards (%d), got %d", want, all)
+	}
+	if allSummary.Sources[1].Issues != allSummary.Sources[0].Issues+1 {
+		t.Errorf("Expected the second board to count every shared issue, got %+v", allSummary.Sources)
+	}
+
+	if _, err := srv.handlePortfolioThroughput(ctx, testProject, testBoard, sources, "some", "week"); err == nil {
+		t.Errorf("Expected an error for an unknown duplicates policy")
+	}
+}
+
+func TestPortfolioSimulationAndAging(t *testing.T) {
+	ctx := context.Background()
+	srv := newPortfolioServer(t)
+	sources := []PortfolioSource{{ProjectKey: testProject, BoardID: 1}}
+
+	res, err := srv.handlePortfolioSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey: testProject,
+		BoardID:    testBoard,
+		Sources:    sources,
+		Mode:       "duration",
+		Targets:    map[string]int{"Story": 10},
+	})
+	if err != nil {
+		t.Fatalf("portfolio forecast: %v", err)
+	}
+	result := res.(ResponseEnvelope).Data.(simulation.Result)
+	if result.Percentiles.Likely <= 0 {
+		t.Errorf("Expected a positive P85 duration, got %v", result.Percentiles.Likely)
+	}
+	if _, ok := result.Context["portfolio"].(PortfolioSummary); !ok {
+		t.Errorf("Expected context.portfolio, got %v", result.Context["portfolio"])
+	}
+
+	res, err = srv.handlePortfolioAging(ctx, testProject, testBoard, sources, "", "wip", "WIP")
+	if err != nil {
+		t.Fatalf("portfolio aging: %v", err)
+	}
+	single, err := srv.handleGetAgingAnalysis(ctx, testProject, testBoard, "wip", "WIP", false)
+	if err != nil {
+		t.Fatalf("analyze_work_item_age: %v", err)
+	}
+	got := len(res.(ResponseEnvelope).Data.(AgingResult).Aging)
+	want := len(single.(ResponseEnvelope).Data.(AgingResult).Aging)
+	if got != want {
+		t.Errorf("Expected shared WIP to be aged once (%d items), got %d", want, got)
+	}
+}
+
+func TestPortfolioRequiresConfirmedWorkflows(t *testing.T) {
+	ctx := context.Background()
+	srv := newPortfolioServer(t)
+	sources := []PortfolioSource{{ProjectKey: testProject, BoardID: 1}, {ProjectKey: testProject, BoardID: 2}}
+
+	if _, err := srv.handlePortfolioThroughput(ctx, testProject, testBoard, sources, "", "week"); err == nil {
+		t.Errorf("Expected an error for a board without a confirmed workflow")
+	}
+
+	res, err := srv.handleImportPortfolio(ctx, append([]PortfolioSource{{ProjectKey: testProject, BoardID: testBoard}}, sources...), "")
+	if err != nil {
+		t.Fatalf("import_portfolio: %v", err)
+	}
+	summary := res.(ResponseEnvelope).Data.(map[string]any)["portfolio"].(PortfolioSummary)
+	if len(summary.Sources) != 3 || !summary.Sources[1].WorkflowConfirmed || summary.Sources[2].WorkflowConfirmed {
+		t.Errorf("Expected boards 0 and 1 confirmed and board 2 not, got %+v", summary.Sources)
+	}
+}
@@ -0,0 +1,200 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"time"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// ScenarioOutcome is one row of the forecast_scenarios comparison.
+type ScenarioOutcome struct {
+	Name            string             `json:"name"`
+	AdditionalItems int                `json:"additional_items,omitempty"` // duration
+	TotalItems      int                `json:"total_items,omitempty"`      // duration: backlog, WIP, and additional items
+	TargetDays      int                `json:"target_days,omitempty"`      // scope: horizon in days
+	CapacityFactor  float64            `json:"capacity_factor,omitempty"`
+	TeamChange      *TeamChange        `json:"team_change,omitempty"`
+	MixOverrides    map[string]float64 `json:"mix_overrides,omitempty"`
+	P50             float64            `json:"p50"`
+	P85             float64            `json:"p85"`
+	P95             float64            `json:"p95"`
+	P85Date         string             `json:"p85_date,omitempty"` // duration: projected completion date at P85
+	P85Change       float64            `json:"p85_change"`         // P85 minus the P85 of the first scenario
+}
+
+// handleForecastScenarios runs several what-if variants of one forecast in a
+// single call. The source is hydrated and projected once, the engine resolved
+// once, and every scenario is simulated against the same throughput sample
+// with the same random seed, so the differences come from the scenarios alone.
+func (s *Server) handleForecastScenarios(ctx context.Context, args ForecastScenariosInput) (any, error) {
+	mode, sampling := string(args.Mode), string(args.Sampling)
+	if mode != string(SimModeDuration) && mode != string(SimModeScope) {
+		return nil, fmt.Errorf("invalid mode %q: must be 'duration' or 'scope'", mode)
+	}
+	if len(args.Scenarios) == 0 || len(args.Scenarios) > MaxForecastScenarios {
+		return nil, fmt.Errorf("scenarios must hold between 1 and %d entries, got %d", MaxForecastScenarios, len(args.Scenarios))
+	}
+	if err := validateSampling(sampling); err != nil {
+		return nil, err
+	}
+	capacities := make([]*simulation.CapacityScenario, len(args.Scenarios))
+	for i, sc := range args.Scenarios {
+		c, err := capacityScenario(sc.CapacityFactor, sc.TeamChange)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %d: %w", i+1, err)
+		}
+		capacities[i] = c
+	}
+
+	hctx, err := s.prepareHandler(ctx, args.ProjectKey, args.BoardID)
+	if err != nil {
+		return nil, err
+	}
+	histStart, histEnd, err := s.forecastSampleWindow(ctx, args.HistoryWindowDays, args.HistoryStartDate, args.HistoryEndDate, false)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.resolveCalendar(args.Holidays, args.FreezePeriods)
+	if err != nil {
+		return nil, err
+	}
+	cutoff := s.activeCutoff()
+	window := stats.NewAnalysisWindow(histStart, histEnd, "day", cutoff)
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+	wip := session.GetWIP()
+
+	analysisCtx := s.prepareAnalysisContext(args.ProjectKey, args.BoardID, all)
+	existing, backlogCount, wipCount, _ := s.forecastScope(ctx, all, wip, analysisCtx, analysisCtx.CommitmentPoint, args.IncludeExistingBacklog, args.IncludeWIP)
+
+	seed := s.simulationSeed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	base := simulation.ForecastRequest{
+		Mode:             mode,
+		AllIssues:        all,
+		Finished:         session.GetFinished(),
+		WIP:              wip,
+		WindowStart:      window.Start,
+		WindowEnd:        window.End,
+		DiscoveryCutoff:  cutoff,
+		Calendar:         calendar,
+		Sampling:         sampling,
+		Outliers:         s.outliers(ctx),
+		Outcomes:         s.outcomes(ctx),
+		IssueTypes:       args.IssueTypes,
+		TypeAliases:      s.activeTypeAliases,
+		CommitmentPoint:  analysisCtx.CommitmentPoint,
+		StatusWeights:    analysisCtx.StatusWeights,
+		WorkflowMappings: analysisCtx.WorkflowMappings,
+		Resolutions:      s.activeResolutions,
+		SimulationSeed:   seed,
+		Clock:            s.Clock(ctx),
+	}
+	engine, err := s.resolveEngine(ctx, base)
+	if err != nil {
+		return nil, fmt.Errorf("engine resolution failed: %w", err)
+	}
+
+	log.Info().
+		Str("tool", "forecast_scenarios").
+		Str("engine", engine.Name()).
+		Int("scenarios", len(args.Scenarios)).
+		Msg("tool executed")
+
+	outcomes := make([]ScenarioOutcome, len(args.Scenarios))
+	var warnings []string
+	for i, sc := range args.Scenarios {
+		out := ScenarioOutcome{
+			Name:           sc.Name,
+			CapacityFactor: sc.CapacityFactor,
+			TeamChange:     sc.TeamChange,
+			MixOverrides:   sc.MixOverrides,
+		}
+		if out.Name == "" {
+			out.Name = fmt.Sprintf("Scenario %d", i+1)
+		}
+
+		req := base
+		req.Capacity = capacities[i]
+		req.MixOverrides = sc.MixOverrides
+		if mode == string(SimModeScope) {
+			days, date := args.TargetDays, args.TargetDate
+			if sc.TargetDays != 0 || sc.TargetDate != "" {
+				days, date = sc.TargetDays, sc.TargetDate
+			}
+			if req.TargetDays, err = s.resolveTargetDays(ctx, mode, days, date); err != nil {
+				return nil, fmt.Errorf("scenario %q: %w", out.Name, err)
+			}
+			if req.TargetDays <= 0 {
+				return nil, fmt.Errorf("scenario %q: scope mode needs a target_days or a target_date in the future", out.Name)
+			}
+			out.TargetDays = req.TargetDays
+		} else {
+			out.AdditionalItems = args.AdditionalItems
+			if sc.AdditionalItems != nil {
+				out.AdditionalItems = *sc.AdditionalItems
+			}
+			if out.AdditionalItems < 0 {
+				return nil, fmt.Errorf("scenario %q: additional_items must not be negative", out.Name)
+			}
+			req.Targets = maps.Clone(existing)
+			addAdditionalItems(req.Targets, args.IssueTypes, out.AdditionalItems)
+			out.TotalItems = backlogCount + wipCount + out.AdditionalItems
+		}
+
+		res, err := engine.Run(ctx, req)
+		if err != nil {
+			return nil, fmt.Errorf("scenario %q: simulation failed: %w", out.Name, err)
+		}
+		res.Round()
+		out.P50, out.P85, out.P95 = res.Percentiles.CoinToss, res.Percentiles.Likely, res.Percentiles.Safe
+		if mode == string(SimModeDuration) {
+			out.P85Date = simulation.CompletionDates(res.Percentiles, s.Clock(ctx))["likely"]
+		}
+		if i > 0 {
+			out.P85Change = stats.Round2(out.P85 - outcomes[0].P85)
+		}
+		outcomes[i] = out
+		for _, w := range res.Warnings {
+			warnings = append(warnings, fmt.Sprintf("[%s] %s", out.Name, w))
+		}
+	}
+	warnings = append(warnings, s.getQualityWarnings(ctx, all)...)
+
+	insights := []string{scenarioVerdict(mode, outcomes)}
+	insights = append(insights, s.guidanceFor("forecast_scenarios", guidanceFacts{})...)
+
+	res := map[string]any{"scenarios": outcomes}
+	envelope := WrapResponse(res, args.ProjectKey, args.BoardID, nil, warnings, insights)
+	envelope.Context["engine"] = engine.Name()
+	envelope.Context["simulation_mode"] = mode
+	envelope.Context["sample_window"] = map[string]string{"start": window.Start.Format(stats.DateFormat), "end": window.End.Format(stats.DateFormat)}
+	envelope.Context["composition"] = simulation.Composition{ExistingBacklog: backlogCount, WIP: wipCount}
+	if calendar != nil {
+		envelope.Context["calendar"] = calendar.Summary()
+	}
+	return envelope, nil
+}
+
+// scenarioVerdict names the scenario with the best P85: the shortest duration,
+// or the most items delivered in scope mode.
+func scenarioVerdict(mode string, outcomes []ScenarioOutcome) string {
+	best := 0
+	for i, o := range outcomes {
+		if (mode == string(SimModeScope) && o.P85 > outcomes[best].P85) || (mode == string(SimModeDuration) && o.P85 < outcomes[best].P85) {
+			best = i
+		}
+	}
+	if mode == string(SimModeScope) {
+		return fmt.Sprintf("'%s' delivers the most with 85%% confidence: at least %.0f items.", outcomes[best].Name, outcomes[best].P85)
+	}
+	return fmt.Sprintf("'%s' finishes soonest with 85%% confidence: within %.0f days.", outcomes[best].Name, outcomes[best].P85)
+}
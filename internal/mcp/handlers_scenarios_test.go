@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestForecastScenarios(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.simulationSeed = 42
+
+	more := 40
+	res, err := srv.handleForecastScenarios(ctx, ForecastScenariosInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "duration",
+		AdditionalItems:   20,
+		IssueTypes:        []string{"Story"},
+		HistoryWindowDays: 365,
+		Scenarios: []ForecastScenario{
+			{Name: "Plan"},
+			{Name: "More scope", AdditionalItems: &more},
+			{Name: "Half team", CapacityFactor: 0.5},
+		},
+	})
+	if err != nil {
+		t.Fatalf("forecast_scenarios: %v", err)
+	}
+	rows := res.(ResponseEnvelope).Data.(map[string]any)["scenarios"].([]ScenarioOutcome)
+	if len(rows) != 3 {
+		t.Fatalf("Expected one row per scenario, got %+v", rows)
+	}
+	plan, scope, half := rows[0], rows[1], rows[2]
+	if plan.TotalItems != 20 || scope.TotalItems != 40 || half.TotalItems != 20 {
+		t.Errorf("Expected 20, 40, and 20 items, got %d, %d, %d", plan.TotalItems, scope.TotalItems, half.TotalItems)
+	}
+	if plan.P85Change != 0 || plan.P85Date == "" {
+		t.Errorf("Expected the first scenario as the baseline with a date, got %+v", plan)
+	}
+	if scope.P85 <= plan.P85 || scope.P85Change <= 0 {
+		t.Errorf("Expected more scope to take longer than %v, got %+v", plan.P85, scope)
+	}
+	if half.P85 <= plan.P85 {
+		t.Errorf("Expected half capacity to take longer than %v, got %+v", plan.P85, half)
+	}
+
+	res, err = srv.handleForecastScenarios(ctx, ForecastScenariosInput{
+		ProjectKey:        testProject,
+		BoardID:           testBoard,
+		Mode:              "scope",
+		TargetDays:        30,
+		HistoryWindowDays: 365,
+		Scenarios: []ForecastScenario{
+			{Name: "30 days"},
+			{Name: "60 days", TargetDays: 60},
+		},
+	})
+	if err != nil {
+		t.Fatalf("forecast_scenarios scope: %v", err)
+	}
+	rows = res.(ResponseEnvelope).Data.(map[string]any)["scenarios"].([]ScenarioOutcome)
+	if rows[0].TargetDays != 30 || rows[1].TargetDays != 60 || rows[1].P85 <= rows[0].P85 {
+		t.Errorf("Expected a longer horizon to deliver more, got %+v", rows)
+	}
+
+	if _, err := srv.handleForecastScenarios(ctx, ForecastScenariosInput{ProjectKey: testProject, BoardID: testBoard, Mode: "duration", AdditionalItems: 20, HistoryWindowDays: 365}); err == nil {
+		t.Errorf("Expected an error without scenarios")
+	}
+	if _, err := srv.handleForecastScenarios(ctx, ForecastScenariosInput{ProjectKey: testProject, BoardID: testBoard, Mode: "scope", HistoryWindowDays: 365, Scenarios: []ForecastScenario{{}}}); err == nil {
+		t.Errorf("Expected an error for a scope scenario without a horizon")
+	}
+}
@@ -0,0 +1,205 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"mcs-mcp/internal/render"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// Bounds of the source settings.
+const (
+	maxSettingsWindowWeeks = 520
+	maxSettingsSampleDays  = 3650
+)
+
+// SourceSettings are per-source defaults for parameters that would otherwise
+// be repeated in every call. They are persisted with the workflow metadata. A
+// parameter passed to a call wins over its setting, and an unset setting
+// leaves the server default in place. The percentile ladder is deliberately
+// not a setting: the forecast registry, backtests, and commitments compare
+// runs level by level, which a per-source ladder would break.
+type SourceSettings struct {
+	WindowWeeks        int                 `json:"window_weeks,omitempty"`         // Length of the default session window
+	ForecastSampleDays int                 `json:"forecast_sample_days,omitempty"` // Throughput sample of day-based forecasts
+	IssueTypes         []string            `json:"issue_types,omitempty"`          // Issue type filter of forecasts and backtests
+	SubtaskPolicy      stats.SubtaskPolicy `json:"subtask_policy,omitempty"`
+	Outliers           stats.OutlierPolicy `json:"outliers,omitempty"`
+	Format             render.Format       `json:"format,omitempty"`
+}
+
+// isZero reports whether no setting is set.
+func (set SourceSettings) isZero() bool {
+	return set.WindowWeeks == 0 && set.ForecastSampleDays == 0 && len(set.IssueTypes) == 0 &&
+		set.SubtaskPolicy == "" && set.Outliers == "" && set.Format == ""
+}
+
+// forecastIssueTypes returns the issue type filter of a forecast: issueTypes
+// when given, else the source setting.
+func (s *Server) forecastIssueTypes(issueTypes []string) []string {
+	if len(issueTypes) > 0 {
+		return issueTypes
+	}
+	return s.activeSettings.IssueTypes
+}
+
+// handleSetSourceSettings updates the settings of the active source and
+// persists them with the workflow metadata. Fields left unset keep their
+// value; reset drops every setting before applying the given ones.
+func (s *Server) handleSetSourceSettings(ctx context.Context, projectKey string, boardID int, in SourceSettings, reset bool) (any, error) {
+	if in.WindowWeeks < 0 || in.WindowWeeks > maxSettingsWindowWeeks {
+		return nil, fmt.Errorf("window_weeks must be between 1 and %d, got %d", maxSettingsWindowWeeks, in.WindowWeeks)
+	}
+	if in.ForecastSampleDays < 0 || in.ForecastSampleDays > maxSettingsSampleDays {
+		return nil, fmt.Errorf("forecast_sample_days must be between 1 and %d, got %d", maxSettingsSampleDays, in.ForecastSampleDays)
+	}
+	if in.SubtaskPolicy != "" {
+		policy, err := stats.ParseSubtaskPolicy(string(in.SubtaskPolicy))
+		if err != nil {
+			return nil, err
+		}
+		if policy != stats.SubtasksExclude && !s.ingestsSubtasks() {
+			return nil, fmt.Errorf("subtask_policy %q needs sub-tasks in the history, but they are not fetched: set MCS_SUBTASK_POLICY to include or rollup and re-import the board", policy)
+		}
+		in.SubtaskPolicy = policy
+	}
+	if in.Outliers != "" {
+		policy, err := stats.ParseOutlierPolicy(string(in.Outliers))
+		if err != nil {
+			return nil, err
+		}
+		in.Outliers = policy
+	}
+	if in.Format != "" {
+		format, err := render.ParseFormat(string(in.Format))
+		if err != nil {
+			return nil, err
+		}
+		in.Format = format
+	}
+	var types []string
+	for _, t := range in.IssueTypes {
+		if t = strings.TrimSpace(t); t != "" && !slices.Contains(types, t) {
+			types = append(types, t)
+		}
+	}
+	in.IssueTypes = types
+	if in.isZero() && !reset {
+		return nil, fmt.Errorf("no settings given: pass at least one setting, or reset=true")
+	}
+
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	before := s.configSnapshot()
+	settings := s.activeSettings
+	if reset {
+		settings = SourceSettings{}
+	}
+	if in.WindowWeeks > 0 {
+		settings.WindowWeeks = in.WindowWeeks
+	}
+	if in.ForecastSampleDays > 0 {
+		settings.ForecastSampleDays = in.ForecastSampleDays
+	}
+	if len(in.IssueTypes) > 0 {
+		settings.IssueTypes = in.IssueTypes
+	}
+	if in.SubtaskPolicy != "" {
+		settings.SubtaskPolicy = in.SubtaskPolicy
+	}
+	if in.Outliers != "" {
+		settings.Outliers = in.Outliers
+	}
+	if in.Format != "" {
+		settings.Format = in.Format
+	}
+	s.activeSettings = settings
+
+	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+		log.Error().Err(err).Msg("Failed to save workflow metadata")
+		return nil, fmt.Errorf("source settings updated in memory but failed to save to disk: %w", err)
+	}
+	s.recordAudit(ctx, projectKey, boardID, before)
+
+	return s.sourceSettingsResponse(ctx, projectKey, boardID), nil
+}
+
+// handleGetSourceSettings returns the settings of a source and the value
+// each parameter defaults to.
+func (s *Server) handleGetSourceSettings(ctx context.Context, projectKey string, boardID int) (any, error) {
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	return s.sourceSettingsResponse(ctx, projectKey, boardID), nil
+}
+
+// settingValue is the value a parameter defaults to and where it comes from:
+// "source" for a source setting, "server" for a server setting or built-in
+// default.
+type settingValue struct {
+	Value  any    `json:"value"`
+	Origin string `json:"origin"`
+}
+
+func (s *Server) sourceSettingsResponse(ctx context.Context, projectKey string, boardID int) ResponseEnvelope {
+	set := s.activeSettings
+	origin := func(isSet bool) string {
+		if isSet {
+			return "source"
+		}
+		return "server"
+	}
+	windowWeeks := DefaultWindowWeeks
+	if set.WindowWeeks > 0 {
+		windowWeeks = set.WindowWeeks
+	}
+	sampleDays := DefaultForecastSampleDays
+	if set.ForecastSampleDays > 0 {
+		sampleDays = set.ForecastSampleDays
+	}
+	issueTypes := set.IssueTypes
+	if issueTypes == nil {
+		issueTypes = []string{}
+	}
+
+	var delivered []string
+	for id, outcome := range s.activeResolutions {
+		if outcome == "delivered" {
+			name := s.activeRegistry.GetResolutionName(id)
+			if name == "" {
+				name = id
+			}
+			delivered = append(delivered, name)
+		}
+	}
+	slices.Sort(delivered)
+
+	res := map[string]any{
+		"settings": set,
+		"effective": map[string]settingValue{
+			"window_weeks":         {windowWeeks, origin(set.WindowWeeks > 0)},
+			"forecast_sample_days": {sampleDays, origin(set.ForecastSampleDays > 0)},
+			"issue_types":          {issueTypes, origin(len(set.IssueTypes) > 0)},
+			"subtask_policy":       {s.subtasks(ctx), origin(set.SubtaskPolicy != "" && s.subtasks(ctx) == set.SubtaskPolicy)},
+			"outliers":             {s.outliers(ctx), origin(set.Outliers != "")},
+			"format":               {formatName(s.resultFormat(ctx)), origin(set.Format != "")},
+		},
+		"delivered_resolutions": delivered,
+	}
+	guidance := s.guidanceFor("set_source_settings", guidanceFacts{})
+	return WrapResponse(res, projectKey, boardID, nil, nil, guidance)
+}
+
+// formatName names the JSON format the empty format stands for.
+func formatName(f render.Format) render.Format {
+	if f == "" {
+		return render.JSON
+	}
+	return f
+}
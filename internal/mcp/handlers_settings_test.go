@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/render"
+	"mcs-mcp/internal/stats"
+)
+
+func TestHandleSetSourceSettings(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	invalid := []SourceSettings{
+		{},
+		{WindowWeeks: -1},
+		{ForecastSampleDays: maxSettingsSampleDays + 1},
+		{Outliers: "trim"},
+		{Format: "xml"},
+		{SubtaskPolicy: stats.SubtasksInclude}, // sub-tasks are not fetched
+	}
+	for _, in := range invalid {
+		if _, err := srv.handleSetSourceSettings(ctx, testProject, testBoard, in, false); err == nil {
+			t.Errorf("Expected settings %+v to be rejected", in)
+		}
+	}
+
+	in := SourceSettings{WindowWeeks: 12, ForecastSampleDays: 30, IssueTypes: []string{"Story", " Story", ""}, Format: render.Markdown}
+	res, err := srv.handleSetSourceSettings(ctx, testProject, testBoard, in, false)
+	if err != nil {
+		t.Fatalf("handleSetSourceSettings: %v", err)
+	}
+	effective := res.(ResponseEnvelope).Data.(map[string]any)["effective"].(map[string]settingValue)
+	if got := effective["issue_types"]; got.Origin != "source" || len(got.Value.([]string)) != 1 {
+		t.Errorf("Expected the deduplicated issue types from the source, got %+v", got)
+	}
+	if got := effective["outliers"]; got.Origin != "server" || got.Value != stats.OutliersNone {
+		t.Errorf("Expected the server outlier policy, got %+v", got)
+	}
+	if srv.resultFormat(ctx) != render.Markdown {
+		t.Errorf("Expected the markdown format setting, got %q", srv.resultFormat(ctx))
+	}
+
+	// The settings replace the defaults of the window and the forecast sample.
+	win, _ := srv.handleGetAnalysisWindow(ctx)
+	if days := win.(ResponseEnvelope).Data.(map[string]any)["duration_days"]; days != 84 {
+		t.Errorf("Expected a 12-week default window, got %v days", days)
+	}
+	start, end, err := srv.forecastSampleWindow(ctx, 0, "", "", false)
+	if err != nil || stats.CalendarDaysBetween(start, end) != 30 {
+		t.Errorf("Expected a 30-day forecast sample, got %v to %v (%v)", start, end, err)
+	}
+	if types := srv.forecastIssueTypes([]string{"Bug"}); types[0] != "Bug" {
+		t.Errorf("Expected the call's issue types to win, got %v", types)
+	}
+
+	// Unset fields keep their value.
+	if _, err := srv.handleSetSourceSettings(ctx, testProject, testBoard, SourceSettings{Outliers: stats.OutliersIQR}, false); err != nil {
+		t.Fatal(err)
+	}
+	if srv.activeSettings.WindowWeeks != 12 || srv.outliers(ctx) != stats.OutliersIQR {
+		t.Errorf("Expected the outlier policy added to the settings, got %+v", srv.activeSettings)
+	}
+
+	// The settings are persisted with the workflow.
+	reloaded := NewServer(&config.AppConfig{CacheDir: srv.cacheDir}, &DummyClient{})
+	if _, err := reloaded.loadWorkflow(testProject, testBoard); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.activeSettings.ForecastSampleDays != 30 || reloaded.activeSettings.Outliers != stats.OutliersIQR {
+		t.Errorf("Expected persisted settings, got %+v", reloaded.activeSettings)
+	}
+
+	if _, err := srv.handleSetSourceSettings(ctx, testProject, testBoard, SourceSettings{}, true); err != nil {
+		t.Fatal(err)
+	}
+	if !srv.activeSettings.isZero() || srv.resultFormat(ctx) != "" {
+		t.Errorf("Expected reset to drop every setting, got %+v", srv.activeSettings)
+	}
+}
@@ -0,0 +1,167 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// DefaultSLEPercentile is the SLE percentile used when set_sle omits it.
+const DefaultSLEPercentile = 85
+
+// handleSetSLE records (or removes) the SLE of an issue type on the active
+// board and persists it with the workflow metadata.
+func (s *Server) handleSetSLE(ctx context.Context, projectKey string, boardID int, issueType string, percentile int, durationDays float64, remove bool) (any, error) {
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	if issueType == "" {
+		issueType = stats.AllIssueTypes
+	}
+	before := s.configSnapshot()
+
+	if remove {
+		if _, ok := s.activeSLEs[issueType]; !ok {
+			return nil, fmt.Errorf("no SLE recorded for issue type %q", issueType)
+		}
+		delete(s.activeSLEs, issueType)
+	} else {
+		if percentile == 0 {
+			percentile = DefaultSLEPercentile
+		}
+		if percentile < 1 || percentile > 99 {
+			return nil, fmt.Errorf("invalid percentile %d: expected 1–99", percentile)
+		}
+		if durationDays <= 0 {
+			return nil, fmt.Errorf("duration_days must be positive")
+		}
+		if s.activeSLEs == nil {
+			s.activeSLEs = make(map[string]stats.ServiceLevelExpectation)
+		}
+		s.activeSLEs[issueType] = stats.ServiceLevelExpectation{IssueType: issueType, Percentile: percentile, DurationDays: durationDays}
+	}
+
+	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+		log.Error().Err(err).Msg("Failed to save workflow metadata")
+		return nil, fmt.Errorf("SLE updated in memory but failed to save to disk: %w", err)
+	}
+	s.recordAudit(ctx, projectKey, boardID, before)
+
+	res := map[string]any{
+		"sles": s.sortedSLEs(),
+	}
+	guidance := s.guidanceFor("set_sle", guidanceFacts{})
+	return WrapResponse(res, projectKey, boardID, nil, nil, guidance), nil
+}
+
+// sortedSLEs returns the recorded SLEs, type-specific ones first and the
+// catch-all SLE last.
+func (s *Server) sortedSLEs() []stats.ServiceLevelExpectation {
+	sles := slices.Collect(maps.Values(s.activeSLEs))
+	slices.SortFunc(sles, func(a, b stats.ServiceLevelExpectation) int {
+		if (a.IssueType == stats.AllIssueTypes) != (b.IssueType == stats.AllIssueTypes) {
+			if a.IssueType == stats.AllIssueTypes {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Compare(a.IssueType, b.IssueType)
+	})
+	return sles
+}
+
+// handleAnalyzeSLECompliance reports, per recorded SLE, the hit rate of the
+// items delivered in the session window, the weekly breach trend, and which
+// in-flight items are on track to breach.
+func (s *Server) handleAnalyzeSLECompliance(ctx context.Context, projectKey string, boardID int, issueTypes []string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.activeSLEs) == 0 {
+		return nil, fmt.Errorf("no SLE recorded for %s; record one first with 'set_sle' (e.g. issue_type Story, percentile 85, duration_days 10)", hctx.SourceID)
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+	delivered := session.GetDelivered()
+
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", issueTypes)
+
+	// WIP is a point-in-time snapshot at the window's End, as in analyze_work_item_age.
+	snapshot := s.openSession(ctx, hctx, stats.NewAnalysisWindow(time.Time{}, window.End, "day", s.activeCutoff()))
+	wip := stats.CalculateInventoryAgeByType(snapshot.GetWIP(), analysisCtx.Commitments(), analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, cycleTimes, "wip", s.commitmentBackflowReset, window.End)
+	wip = filterAgingByTier(wip, "WIP")
+
+	typeFilter := make(map[string]bool, len(issueTypes))
+	for _, t := range issueTypes {
+		typeFilter[t] = true
+	}
+	// appliesTo reports whether an SLE governs items of the given type.
+	appliesTo := func(sle stats.ServiceLevelExpectation, issueType string) bool {
+		if len(typeFilter) > 0 && !typeFilter[issueType] {
+			return false
+		}
+		if sle.IssueType == stats.AllIssueTypes {
+			_, own := s.activeSLEs[issueType]
+			return !own
+		}
+		return sle.IssueType == issueType
+	}
+
+	weekWindow := stats.NewAnalysisWindow(window.Start, window.End, "week", window.Cutoff)
+	var compliance []stats.SLECompliance
+	var insights []string
+	for _, sle := range s.sortedSLEs() {
+		if sle.IssueType != stats.AllIssueTypes && len(typeFilter) > 0 && !typeFilter[sle.IssueType] {
+			continue
+		}
+		var issues []jira.Issue
+		var cts []float64
+		for i, issue := range matchedIssues {
+			if appliesTo(sle, issue.IssueType) {
+				issues = append(issues, issue)
+				cts = append(cts, cycleTimes[i])
+			}
+		}
+		var items []stats.InventoryAge
+		for _, item := range wip {
+			if appliesTo(sle, item.Type) {
+				items = append(items, item)
+			}
+		}
+
+		c := stats.EvaluateSLECompliance(sle, issues, cts, items, weekWindow)
+		compliance = append(compliance, c)
+		if c.Delivered > 0 && !c.Met {
+			insights = append(insights, fmt.Sprintf("SLE MISSED for %s: %.0f%% of %d delivered items finished within %.4g days (expected %d%%).",
+				sleLabel(sle), c.HitRate*100, c.Delivered, sle.DurationDays, sle.Percentile))
+		}
+	}
+	if len(compliance) == 0 {
+		return nil, fmt.Errorf("no recorded SLE applies to issue types %v", issueTypes)
+	}
+
+	res := map[string]any{
+		"compliance": compliance,
+	}
+	guidance := append(insights, s.guidanceFor("analyze_sle_compliance", guidanceFacts{})...)
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
+}
+
+func sleLabel(sle stats.ServiceLevelExpectation) string {
+	if sle.IssueType == stats.AllIssueTypes {
+		return "all other issue types"
+	}
+	return sle.IssueType
+}
@@ -0,0 +1,63 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/stats"
+)
+
+func TestSLECompliance(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	if _, err := srv.handleAnalyzeSLECompliance(ctx, testProject, testBoard, nil); err == nil {
+		t.Fatalf("Expected an error before any SLE is recorded")
+	}
+	if _, err := srv.handleSetSLE(ctx, testProject, testBoard, "Story", 120, 10, false); err == nil {
+		t.Errorf("Expected an out-of-range percentile to be rejected")
+	}
+	if _, err := srv.handleSetSLE(ctx, testProject, testBoard, "Story", 0, 10, false); err != nil {
+		t.Fatalf("set_sle Story: %v", err)
+	}
+	if _, err := srv.handleSetSLE(ctx, testProject, testBoard, "", 70, 20, false); err != nil {
+		t.Fatalf("set_sle catch-all: %v", err)
+	}
+
+	res, err := srv.handleAnalyzeSLECompliance(ctx, testProject, testBoard, nil)
+	if err != nil {
+		t.Fatalf("analyze_sle_compliance: %v", err)
+	}
+	compliance := res.(ResponseEnvelope).Data.(map[string]any)["compliance"].([]stats.SLECompliance)
+	if len(compliance) != 2 || compliance[0].SLE.IssueType != "Story" || compliance[1].SLE.IssueType != stats.AllIssueTypes {
+		t.Fatalf("Expected the Story SLE followed by the catch-all, got %+v", compliance)
+	}
+	story := compliance[0]
+	if story.SLE.Percentile != DefaultSLEPercentile || story.ExpectedHitRate != 0.85 {
+		t.Errorf("Expected the default P85, got %+v", story.SLE)
+	}
+	if story.Delivered == 0 || story.HitRate < 0 || story.HitRate > 1 {
+		t.Errorf("Expected delivered Stories with a hit rate in [0, 1], got %d and %v", story.Delivered, story.HitRate)
+	}
+	for _, item := range compliance[1].WIP {
+		if item.Type == "Story" {
+			t.Errorf("The catch-all SLE must not cover Stories, got %s", item.Key)
+		}
+	}
+
+	// SLEs survive a context switch via the workflow file.
+	srv.activeSLEs = nil
+	if _, err := srv.loadWorkflow(testProject, testBoard); err != nil {
+		t.Fatalf("loadWorkflow: %v", err)
+	}
+	if len(srv.activeSLEs) != 2 || srv.activeSLEs["Story"].DurationDays != 10 {
+		t.Errorf("Expected both SLEs to be persisted, got %+v", srv.activeSLEs)
+	}
+
+	if _, err := srv.handleSetSLE(ctx, testProject, testBoard, "Story", 0, 0, true); err != nil {
+		t.Fatalf("remove Story SLE: %v", err)
+	}
+	if _, err := srv.handleSetSLE(ctx, testProject, testBoard, "Story", 0, 0, true); err == nil {
+		t.Errorf("Expected an error when removing an SLE that is not recorded")
+	}
+}
@@ -0,0 +1,178 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+func (s *Server) handleAnalyzeSprintHistory(ctx context.Context, projectKey string, boardID int) (any, error) {
+	if isQuerySource(projectKey) {
+		return nil, errQuerySourceSprints
+	}
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints, err := s.jira.GetSprints(ctx, boardID, SprintHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sprints: %w", err)
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+
+	inWindow := stats.SprintsStartingWithin(sprints, window.Start, window.End)
+	if len(inWindow) == 0 {
+		return nil, fmt.Errorf("no sprints started within the analysis window; widen it via 'set_analysis_window' or check that board %d is a Scrum board", boardID)
+	}
+
+	history := stats.AnalyzeSprintHistory(inWindow, all, s.Clock(ctx))
+	history.Round()
+
+	res := map[string]any{
+		"sprint_history": history,
+	}
+
+	guidance := append(s.guidanceFor("analyze_sprint_history", guidanceFacts{}), s.windowingGuidance(ctx))
+	if history.Summary.ClosedSprints < stats.MinSprintSamples {
+		guidance = append(guidance, fmt.Sprintf("Only %d closed sprint(s) in the window — too few for a sprint-mode forecast (minimum %d).", history.Summary.ClosedSprints, stats.MinSprintSamples))
+	}
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
+}
+
+// runSprintForecast is the sprint-mode branch of forecast_monte_carlo. It samples
+// the throughput of closed sprints within the sampling window and simulates in
+// whole sprints, so durations and scope horizons are expressed in sprints.
+func (s *Server) runSprintForecast(ctx context.Context, projectKey string, boardID int, mode string, targets map[string]int, comp simulation.Composition, targetSprints int, issueTypes []string, finished []jira.Issue, histStart time.Time, all []jira.Issue, inputs ForecastInputs) (any, error) {
+	if isQuerySource(projectKey) {
+		return nil, errQuerySourceSprints
+	}
+	sprints, err := s.jira.GetSprints(ctx, boardID, SprintHistoryLimit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sprints: %w", err)
+	}
+
+	delivered := make([]jira.Issue, 0, len(finished))
+	typeSet := make(map[string]bool, len(issueTypes))
+	for _, t := range issueTypes {
+		typeSet[s.activeTypeAliases.Canonical(t)] = true
+	}
+	for _, issue := range finished {
+		if issue.Outcome == "delivered" && (len(typeSet) == 0 || typeSet[s.activeTypeAliases.Canonical(issue.IssueType)]) {
+			delivered = append(delivered, issue)
+		}
+	}
+
+	history := stats.AnalyzeSprintHistory(stats.SprintsStartingWithin(sprints, histStart, s.Clock(ctx)), delivered, s.Clock(ctx))
+	samples := stats.SprintThroughputSamples(history)
+	if len(samples) < stats.MinSprintSamples {
+		return nil, fmt.Errorf("sprint mode needs at least %d closed sprints in the sampling window, found %d; widen it via history_window_days or history_start_date", stats.MinSprintSamples, len(samples))
+	}
+
+	h := simulation.NewSprintHistogram(samples)
+	h.TrimOutliers(s.outliers(ctx))
+	samplingWarning := simulation.ApplySampling(h, inputs.Sampling, s.simulationSeed)
+	engine := simulation.NewEngine(h)
+	engine.SetContext(ctx)
+	if s.simulationSeed != 0 {
+		engine.SetSeed(s.simulationSeed)
+	}
+	capacity, err := capacityScenario(inputs.CapacityFactor, inputs.TeamChange)
+	if err != nil {
+		return nil, err
+	}
+	if capacity != nil {
+		// A team change applies from the first sprint starting after it.
+		changeStep := 0
+		if !capacity.ChangeDate.IsZero() && history.Summary.MedianLengthDays > 0 {
+			changeStep = int(math.Ceil(float64(stats.CalendarDaysBetween(s.Clock(ctx), capacity.ChangeDate)) / history.Summary.MedianLengthDays))
+		}
+		engine.SetCapacity(*capacity, changeStep)
+	}
+
+	var resObj simulation.Result
+	switch mode {
+	case "duration":
+		for _, c := range targets {
+			comp.Total += c
+		}
+		if comp.Total == 0 {
+			return nil, fmt.Errorf("no items to forecast: set include_existing_backlog, include_wip, additional_items, or targets")
+		}
+		resObj = engine.RunDurationSimulation(comp.Total, simulation.DefaultTrials)
+	case "scope":
+		if targetSprints <= 0 {
+			return nil, fmt.Errorf("target_sprints is required for a sprint-mode scope forecast")
+		}
+		resObj = engine.RunScopeSimulation(targetSprints, simulation.DefaultTrials)
+		comp = simulation.Composition{}
+	default:
+		return nil, fmt.Errorf("invalid mode %q: must be 'duration' or 'scope'", mode)
+	}
+	if err := engine.Err(); err != nil {
+		return nil, fmt.Errorf("simulation cancelled: %w", err)
+	}
+	resObj.Round()
+
+	if resObj.Context == nil {
+		resObj.Context = make(map[string]any)
+	}
+	resObj.Context["simulation_mode"] = mode
+	resObj.Context["time_unit"] = "sprint"
+	resObj.Context["sprints_sampled"] = len(samples)
+	resObj.Context["median_sprint_length_days"] = stats.Round2(history.Summary.MedianLengthDays)
+	if mode == "scope" {
+		resObj.Context["target_sprints"] = targetSprints
+	} else {
+		resObj.Composition = &comp
+		resObj.Context["sprint_end_dates"] = sprintEndDates(resObj.Percentiles, sprints, history.Summary.MedianLengthDays, s.Clock(ctx))
+	}
+	annotateCapacity(&resObj, inputs)
+	inputs.TimeUnit = "sprint"
+	inputs.TargetSprints = targetSprints
+	s.trackForecast(ctx, getCombinedID(projectKey, boardID), "sprint", inputs, &resObj)
+
+	if samplingWarning != "" {
+		resObj.Warnings = append(resObj.Warnings, samplingWarning)
+	}
+	warnings := append(resObj.Warnings, s.getQualityWarnings(ctx, all)...)
+	insights := append(resObj.Insights, s.guidanceFor("forecast_monte_carlo", guidanceFacts{FatTailRatio: resObj.FatTailRatio, SparseSample: sparseEmpiricalSample(resObj.Context)})...)
+	insights = append(insights, fmt.Sprintf("Sprint mode: sampled the throughput of %d closed sprint(s); percentiles are in sprints, not days.", len(samples)))
+	resObj.Warnings = nil
+	resObj.Insights = nil
+
+	return WrapResponse(resObj, projectKey, boardID, nil, warnings, insights), nil
+}
+
+// sprintEndDates maps each duration percentile (in sprints) to the projected
+// end date of that sprint.
+func sprintEndDates(p simulation.Percentiles, sprints []jira.Sprint, lengthDays float64, now time.Time) map[string]string {
+	horizon := int(math.Ceil(p.AlmostCertain))
+	boundaries := stats.NextSprintBoundaries(sprints, lengthDays, horizon, now)
+	at := func(n float64) string {
+		i := int(math.Ceil(n)) - 1
+		if i < 0 || i >= len(boundaries) {
+			return ""
+		}
+		return boundaries[i].Format(stats.DateFormat)
+	}
+	return map[string]string{
+		"aggressive":     at(p.Aggressive),
+		"unlikely":       at(p.Unlikely),
+		"coin_toss":      at(p.CoinToss),
+		"probable":       at(p.Probable),
+		"likely":         at(p.Likely),
+		"conservative":   at(p.Conservative),
+		"safe":           at(p.Safe),
+		"almost_certain": at(p.AlmostCertain),
+	}
+}
@@ -0,0 +1,86 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// sprintClient serves a fixed sprint history on top of DummyClient.
+type sprintClient struct {
+	DummyClient
+	sprints []jira.Sprint
+}
+
+func (c *sprintClient) GetSprints(_ context.Context, boardID int, limit int) ([]jira.Sprint, error) {
+	return c.sprints, nil
+}
+
+// twoWeekSprints builds n closed two-week sprints ending at end, followed by an active one.
+func twoWeekSprints(end time.Time, n int) []jira.Sprint {
+	var sprints []jira.Sprint
+	start := end.AddDate(0, 0, -14*n)
+	for i := range n {
+		s, e := start.AddDate(0, 0, 14*i), start.AddDate(0, 0, 14*(i+1))
+		sprints = append(sprints, jira.Sprint{ID: i + 1, State: "closed", Start: s, End: e, Complete: &e})
+	}
+	return append(sprints, jira.Sprint{ID: n + 1, State: "active", Start: end, End: end.AddDate(0, 0, 14)})
+}
+
+func TestSprintForecast(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.jira = &sprintClient{sprints: twoWeekSprints(srv.Clock(ctx), 10)}
+
+	res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey:      testProject,
+		BoardID:         testBoard,
+		Mode:            "duration",
+		AdditionalItems: 40,
+		SprintMode:      true,
+	})
+	if err != nil {
+		t.Fatalf("sprint-mode duration: %v", err)
+	}
+	result := res.(ResponseEnvelope).Data.(simulation.Result)
+	if result.Context["time_unit"] != "sprint" || result.Context["sprints_sampled"] != 10 {
+		t.Errorf("Expected 10 sampled sprints, got context %v", result.Context)
+	}
+	if result.Percentiles.Likely <= 0 || result.Percentiles.Likely > 20 {
+		t.Errorf("Expected a P85 of a few sprints for 40 items, got %v", result.Percentiles.Likely)
+	}
+	if dates := result.Context["sprint_end_dates"].(map[string]string); dates["likely"] == "" {
+		t.Errorf("Expected a projected sprint end date for P85, got %v", dates)
+	}
+
+	if _, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey: testProject,
+		BoardID:    testBoard,
+		Mode:       "scope",
+		SprintMode: true,
+	}); err == nil {
+		t.Errorf("Expected scope mode without target_sprints to fail")
+	}
+}
+
+func TestAnalyzeSprintHistory(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.jira = &sprintClient{sprints: twoWeekSprints(srv.Clock(ctx), 6)}
+
+	res, err := srv.handleAnalyzeSprintHistory(ctx, testProject, testBoard)
+	if err != nil {
+		t.Fatalf("analyze_sprint_history: %v", err)
+	}
+	history := res.(ResponseEnvelope).Data.(map[string]any)["sprint_history"].(stats.SprintHistoryResult)
+	if history.Summary.ClosedSprints != 6 || len(history.Sprints) != 7 {
+		t.Errorf("Expected 6 closed sprints plus the active one, got %+v", history.Summary)
+	}
+	if history.Summary.ThroughputP50 <= 0 {
+		t.Errorf("Expected positive per-sprint throughput, got %+v", history.Summary)
+	}
+}
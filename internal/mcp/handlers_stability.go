@@ -1,32 +1,35 @@
 package mcp
 
 import (
+	"context"
+	"fmt"
+	"slices"
 	"time"
 
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
 )
 
-func (s *Server) handleGetProcessStability(projectKey string, boardID int, includeRawSeries bool) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleGetProcessStability(ctx context.Context, projectKey string, boardID int, includeRawSeries bool) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 
 	// 2. Project
-	window := s.AnalysisWindow("week")
-	session := s.openSession(hctx, window)
+	window := s.AnalysisWindow(ctx, "week")
+	session := s.openSession(ctx, hctx, window)
 
 	all := session.GetAllIssues()
 	wip := session.GetWIP()
 	delivered := session.GetDelivered()
 
 	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
-	cycleTimes, matchedIssues := s.getCycleTimes(projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
 
 	// Stratified Analysis
-	ctByType := s.getCycleTimesByType(projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
-	wipByType := s.calculateWIPAges(wip, analysisCtx.CommitmentPoint, analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, cycleTimes)
+	ctByType := s.getCycleTimesByType(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+	wipByType := s.calculateWIPAges(ctx, wip, analysisCtx.Commitments(), analysisCtx.StatusWeights, analysisCtx.WorkflowMappings, cycleTimes)
 
 	issuesByType := make(map[string][]jira.Issue)
 	for _, iss := range delivered {
@@ -41,7 +44,10 @@ func (s *Server) handleGetProcessStability(projectKey string, boardID int, inclu
 	stratified := stats.CalculateStratifiedStability(issuesByType, ctByType, wipByType, float64(window.ActiveDayCount()))
 
 	// Scatterplot: chart-ready data with dates, cycle times, pooled moving ranges, and issue types.
-	scatterplot := stats.BuildScatterplot(matchedIssues, cycleTimes)
+	scatterplot, page, err := pageItems(ctx, "scatterplot", stats.BuildScatterplot(matchedIssues, cycleTimes), scatterOrders)
+	if err != nil {
+		return nil, err
+	}
 
 	// Round all numeric output to 2 decimal places (post-math, output boundary only).
 	stability.Round()
@@ -67,21 +73,17 @@ func (s *Server) handleGetProcessStability(projectKey string, boardID int, inclu
 		"stratified":  stratified,
 		"scatterplot": scatterplot,
 	}
-
-	guidance := []string{
-		"XmR charts detect 'Special Cause' variation. If stability is low (outliers/shifts), forecasts are unreliable.",
-		"Stability Index = (WIP / Throughput) / Average Cycle Time. A ratio > 1.3 indicates a 'Clogged' system.",
-		"The 'scatterplot' array contains one entry per delivered work item with cycle time (value), date (the work item's outcome date), pooled moving range, and issue type. " +
-			"Render a Cycle Time Scatterplot: X=date, Y=value. " +
-			"Reference lines from stability.xmr: average (center), upper_natural_process_limit, lower_natural_process_limit. " +
-			"For type-specific limits, use stratified[type].xmr.",
+	if page != nil {
+		res["page"] = page
 	}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(all), guidance), nil
+	guidance := s.guidanceFor("analyze_process_stability", guidanceFacts{StabilityIndex: stability.StabilityIndex})
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
 }
 
-func (s *Server) handleGetProcessEvolution(projectKey string, boardID int, bucket string) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleGetProcessEvolution(ctx context.Context, projectKey string, boardID int, bucket string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -91,14 +93,14 @@ func (s *Server) handleGetProcessEvolution(projectKey string, boardID int, bucke
 	}
 
 	// Process evolution is a long-term trend metric. It anchors at the session
-	// window's End (capped at s.Clock() — never extrapolates past "now") and
+	// window's End (capped at s.Clock(ctx) — never extrapolates past "now") and
 	// looks back a fixed horizon: 12 complete months or 26 complete weeks.
 	// Window Start is intentionally ignored: short ranges defeat the point of
 	// trend detection.
-	_, sessionEnd, _ := s.Window()
+	_, sessionEnd, _ := s.Window(ctx)
 	rightEdge := sessionEnd
-	if rightEdge.After(s.Clock()) {
-		rightEdge = s.Clock()
+	if rightEdge.After(s.Clock(ctx)) {
+		rightEdge = s.Clock(ctx)
 	}
 	end := stats.LastCompleteBucketEnd(rightEdge, bucket)
 
@@ -110,12 +112,12 @@ func (s *Server) handleGetProcessEvolution(projectKey string, boardID int, bucke
 	}
 
 	window := stats.NewAnalysisWindow(start, end, bucket, s.activeCutoff())
-	session := s.openSession(hctx, window)
+	session := s.openSession(ctx, hctx, window)
 
 	delivered := session.GetDelivered()
 	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, session.GetAllIssues())
 
-	cycleTimes, matchedIssues := s.getCycleTimes(projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
 	subgroups := stats.GroupIssuesByBucket(matchedIssues, cycleTimes, window)
 	evolution := stats.CalculateThreeWayXmR(subgroups)
 	evolution.Round()
@@ -131,23 +133,21 @@ func (s *Server) handleGetProcessEvolution(projectKey string, boardID int, bucke
 		},
 	}
 
-	guidance := []string{
-		"Process evolution is a long-term trend metric. This tool ignores the session window's Start and uses ONLY its End as the right edge. Lookback is fixed: 12 complete months (bucket='month') or 26 complete weeks (bucket='week'). To shift the trend's right edge, set the session window's End via 'set_analysis_window'.",
-	}
+	guidance := s.guidanceFor("analyze_process_evolution", guidanceFacts{})
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(delivered), guidance), nil
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, delivered), guidance), nil
 }
 
-func (s *Server) handleGetProcessYield(projectKey string, boardID int) (any, error) {
-	hctx, err := s.prepareHandler(projectKey, boardID)
+func (s *Server) handleGetProcessYield(ctx context.Context, projectKey string, boardID int) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 	sourceID := hctx.SourceID
 
 	// 2. Project using the session analysis window
-	window := s.AnalysisWindow("day")
-	session := s.openSession(hctx, window)
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
 
 	all := session.GetAllIssues()
 	yield := stats.CalculateProcessYield(all, s.activeMapping, s.getResolutionMap(sourceID))
@@ -163,10 +163,107 @@ func (s *Server) handleGetProcessYield(projectKey string, boardID int) (any, err
 		"stratified": stratified,
 	}
 
-	guidance := []string{
-		"High 'Abandoned Upstream' often points to discovery/refinement issues.",
-		"High 'Abandoned Downstream' points to execution or commitment issues.",
+	guidance := s.guidanceFor("analyze_yield", guidanceFacts{})
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
+}
+
+// handleAnalyzeAbandonment explains where the items finished in the session
+// window were abandoned, using the tiers and outcomes of the workflow mapping.
+func (s *Server) handleAnalyzeAbandonment(ctx context.Context, projectKey string, boardID int, issueTypes []string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.activeMapping) == 0 {
+		return nil, fmt.Errorf("abandonment analysis needs workflow tiers and outcomes: confirm a workflow mapping via 'workflow_set_mapping' first")
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+	finished := session.GetFinished()
+	if len(issueTypes) > 0 {
+		finished = slices.DeleteFunc(slices.Clone(finished), func(issue jira.Issue) bool {
+			return !slices.Contains(issueTypes, issue.IssueType)
+		})
+	}
+	if len(finished) == 0 {
+		return nil, fmt.Errorf("no finished items found in the analysis window")
+	}
+
+	abandonment := stats.AnalyzeAbandonment(finished, s.activeMapping)
+	abandonment.Round()
+
+	var insights []string
+	if abandonment.Abandoned == 0 {
+		insights = append(insights, fmt.Sprintf("None of the %d finished items in the window was abandoned.", abandonment.Delivered))
+	}
+	for _, p := range abandonment.ByTier {
+		if p.Tier == stats.TierDownstream {
+			insights = append(insights, fmt.Sprintf("%d abandoned item(s) (%.0f%%) were dropped after the commitment point, wasting %.1f downstream days in total.", p.Count, p.Share*100, abandonment.WastedDownstreamDays))
+		}
+	}
+	if age := abandonment.AgeAtExit; abandonment.Abandoned > 0 && age.AbandonedP50 > age.DeliveredP50 {
+		insights = append(insights, fmt.Sprintf("Abandoned items were older at exit than delivered ones (P50 %.1f vs. %.1f days): work is dropped late rather than early.", age.AbandonedP50, age.DeliveredP50))
+	}
+
+	res := map[string]any{
+		"abandonment": abandonment,
+	}
+	guidance := append(insights, s.guidanceFor("analyze_abandonment", guidanceFacts{})...)
+	guidance = append(guidance, s.windowingGuidance(ctx))
+
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, finished), guidance), nil
+}
+
+func (s *Server) handleAnalyzeCycleTimeScatter(ctx context.Context, projectKey string, boardID int, startStatus, endStatus string, issueTypes []string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+
+	window := s.AnalysisWindow(ctx, "day")
+	session := s.openSession(ctx, hctx, window)
+
+	all := session.GetAllIssues()
+	delivered := session.GetDelivered()
+	if len(delivered) == 0 {
+		return nil, fmt.Errorf("no historical delivery data found")
+	}
+
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+	if startStatus == "" {
+		startStatus = analysisCtx.CommitmentPoint
+	}
+
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, startStatus, endStatus, issueTypes)
+	if len(cycleTimes) == 0 {
+		return nil, fmt.Errorf("no cycle times found for criteria")
+	}
+
+	scatter := stats.BuildCycleTimeScatter(matchedIssues, cycleTimes)
+	scatter.Round()
+	points, page, err := pageItems(ctx, "cycle_time_scatter.points", scatter.Points, scatterOrders)
+	if err != nil {
+		return nil, err
+	}
+	scatter.Points = points
+
+	res := map[string]any{
+		"cycle_time_scatter": scatter,
+	}
+	if page != nil {
+		res["page"] = page
+	}
+
+	guidance := append(s.guidanceFor("analyze_cycle_time_scatter", guidanceFacts{}),
+		s.windowingGuidance(ctx),
+		fmt.Sprintf("Cycle time is measured from '%s'. Bands are pooled over all %d plotted items; per-type bands need at least %d items of a type.", startStatus, scatter.Bands.Count, stats.MinTypeWorkflowSample),
+	)
+	guidance = s.addCommitmentInsights(guidance, analysisCtx, startStatus)
+	if len(scatter.AboveP95) > 0 {
+		guidance = append(guidance, fmt.Sprintf("%d item(s) lie above the P95 band — use 'analyze_item_journey' on the keys in 'above_p95' to see where they waited.", len(scatter.AboveP95)))
 	}
 
-	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(all), guidance), nil
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
 }
@@ -0,0 +1,181 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/rs/zerolog/log"
+)
+
+// maxTemplateNameLength bounds the names of forecast templates.
+const maxTemplateNameLength = 64
+
+// ForecastTemplate is a named set of forecast_monte_carlo parameters of a
+// source, persisted with the workflow metadata so that recurring forecasts
+// need only the template name. A parameter passed to a call wins over the
+// template's.
+type ForecastTemplate struct {
+	Name                   string             `json:"name"`
+	IssueTypes             []string           `json:"issue_types,omitempty"`
+	StartStatus            string             `json:"start_status,omitempty"`
+	Targets                map[string]int     `json:"targets,omitempty"`
+	MixOverrides           map[string]float64 `json:"mix_overrides,omitempty"`
+	IncludeExistingBacklog bool               `json:"include_existing_backlog,omitempty"`
+	IncludeWIP             bool               `json:"include_wip,omitempty"`
+	AdditionalItems        int                `json:"additional_items,omitempty"`
+	HistoryWindowDays      int                `json:"history_window_days,omitempty"`
+}
+
+// isZero reports whether the template sets no parameter.
+func (t ForecastTemplate) isZero() bool {
+	return len(t.IssueTypes) == 0 && t.StartStatus == "" && len(t.Targets) == 0 && len(t.MixOverrides) == 0 &&
+		!t.IncludeExistingBacklog && !t.IncludeWIP && t.AdditionalItems == 0 && t.HistoryWindowDays == 0
+}
+
+// handleSaveForecastTemplate saves (or, with remove, deletes) a forecast
+// template of the active source and persists it with the workflow metadata.
+func (s *Server) handleSaveForecastTemplate(ctx context.Context, projectKey string, boardID int, in ForecastTemplate, remove bool) (any, error) {
+	in.Name = strings.TrimSpace(in.Name)
+	if in.Name == "" || len(in.Name) > maxTemplateNameLength {
+		return nil, fmt.Errorf("a template needs a name of 1 to %d characters", maxTemplateNameLength)
+	}
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+
+	before := s.configSnapshot()
+	templates := maps.Clone(s.activeForecastTemplates)
+	if templates == nil {
+		templates = make(map[string]ForecastTemplate)
+	}
+	if remove {
+		if _, ok := templates[in.Name]; !ok {
+			return nil, fmt.Errorf("no forecast template named %q saved", in.Name)
+		}
+		delete(templates, in.Name)
+	} else {
+		if err := s.validateForecastTemplate(&in); err != nil {
+			return nil, err
+		}
+		templates[in.Name] = in
+	}
+	s.activeForecastTemplates = templates
+	if len(templates) == 0 {
+		s.activeForecastTemplates = nil
+	}
+
+	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+		log.Error().Err(err).Msg("Failed to save workflow metadata")
+		return nil, fmt.Errorf("forecast templates updated in memory but failed to save to disk: %w", err)
+	}
+	s.recordAudit(ctx, projectKey, boardID, before)
+
+	return s.forecastTemplatesResponse(projectKey, boardID), nil
+}
+
+// validateForecastTemplate checks the parameters of t as forecast_monte_carlo
+// would, and trims its issue types and start status.
+func (s *Server) validateForecastTemplate(t *ForecastTemplate) error {
+	var types []string
+	for _, it := range t.IssueTypes {
+		if it = strings.TrimSpace(it); it != "" && !slices.Contains(types, it) {
+			types = append(types, it)
+		}
+	}
+	t.IssueTypes = types
+	t.StartStatus = strings.TrimSpace(t.StartStatus)
+	if t.isZero() {
+		return fmt.Errorf("template %q sets no parameter: pass at least one forecast parameter", t.Name)
+	}
+	for issueType, n := range t.Targets {
+		if n <= 0 {
+			return fmt.Errorf("target of %q must be positive, got %d", issueType, n)
+		}
+	}
+	for issueType, share := range t.MixOverrides {
+		if share < 0 || share > 1 {
+			return fmt.Errorf("mix override of %q must be between 0 and 1, got %g", issueType, share)
+		}
+	}
+	if t.AdditionalItems < 0 || t.HistoryWindowDays < 0 {
+		return fmt.Errorf("additional_items and history_window_days must not be negative")
+	}
+	if t.StartStatus != "" && s.activeRegistry.GetStatusID(t.StartStatus) == "" && s.activeRegistry.GetStatusName(t.StartStatus) == "" {
+		if _, ok := s.activeMapping[t.StartStatus]; !ok {
+			return fmt.Errorf("unknown start_status %q on this board", t.StartStatus)
+		}
+	}
+	return nil
+}
+
+// handleListForecastTemplates returns the forecast templates of a source.
+func (s *Server) handleListForecastTemplates(projectKey string, boardID int) (any, error) {
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	return s.forecastTemplatesResponse(projectKey, boardID), nil
+}
+
+func (s *Server) forecastTemplatesResponse(projectKey string, boardID int) ResponseEnvelope {
+	templates := make([]ForecastTemplate, 0, len(s.activeForecastTemplates))
+	for _, name := range slices.Sorted(maps.Keys(s.activeForecastTemplates)) {
+		templates = append(templates, s.activeForecastTemplates[name])
+	}
+	guidance := s.guidanceFor("save_forecast_template", guidanceFacts{})
+	return WrapResponse(map[string]any{"forecast_templates": templates}, projectKey, boardID, nil, nil, guidance)
+}
+
+// withTemplateContext records in the context of a forecast result the
+// template that filled its parameters.
+func withTemplateContext(data any, name string) any {
+	env, ok := data.(ResponseEnvelope)
+	if !ok || name == "" {
+		return data
+	}
+	if env.Context == nil {
+		env.Context = map[string]any{}
+	}
+	env.Context["forecast_template"] = name
+	return env
+}
+
+// applyForecastTemplate fills the parameters args leaves out from the
+// template it names, as saved for its source. Booleans a template sets stay
+// on; the other parameters of the call win over the template's.
+func (s *Server) applyForecastTemplate(args ForecastMonteCarloInput) (ForecastMonteCarloInput, error) {
+	name := strings.TrimSpace(args.Template)
+	if args.Template = name; name == "" {
+		return args, nil
+	}
+	if err := s.anchorContext(args.ProjectKey, args.BoardID); err != nil {
+		return args, err
+	}
+	t, ok := s.activeForecastTemplates[name]
+	if !ok {
+		return args, fmt.Errorf("no forecast template named %q for %s; see 'list_forecast_templates'", name, getCombinedID(args.ProjectKey, args.BoardID))
+	}
+	if len(args.IssueTypes) == 0 {
+		args.IssueTypes = slices.Clone(t.IssueTypes)
+	}
+	if args.StartStatus == "" {
+		args.StartStatus = t.StartStatus
+	}
+	if len(args.Targets) == 0 {
+		args.Targets = maps.Clone(t.Targets)
+	}
+	if len(args.MixOverrides) == 0 {
+		args.MixOverrides = maps.Clone(t.MixOverrides)
+	}
+	args.IncludeExistingBacklog = args.IncludeExistingBacklog || t.IncludeExistingBacklog
+	args.IncludeWIP = args.IncludeWIP || t.IncludeWIP
+	if args.AdditionalItems == 0 {
+		args.AdditionalItems = t.AdditionalItems
+	}
+	if args.HistoryWindowDays == 0 && args.HistoryStartDate == "" {
+		args.HistoryWindowDays = t.HistoryWindowDays
+	}
+	return args, nil
+}
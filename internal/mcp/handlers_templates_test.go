@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"mcs-mcp/internal/config"
+)
+
+func TestForecastTemplates(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	invalid := []ForecastTemplate{
+		{IssueTypes: []string{"Story"}},
+		{Name: "empty"},
+		{Name: "targets", Targets: map[string]int{"Story": 0}},
+		{Name: "mix", MixOverrides: map[string]float64{"Bug": 1.5}},
+		{Name: "window", HistoryWindowDays: -30},
+		{Name: "status", StartStatus: "Nowhere"},
+	}
+	for _, in := range invalid {
+		if _, err := srv.handleSaveForecastTemplate(ctx, testProject, testBoard, in, false); err == nil {
+			t.Errorf("Expected template %+v to be rejected", in)
+		}
+	}
+
+	in := ForecastTemplate{
+		Name:                   " roadmap ",
+		IssueTypes:             []string{"Story", " Story", ""},
+		Targets:                map[string]int{"Story": 10},
+		IncludeExistingBacklog: true,
+		HistoryWindowDays:      60,
+	}
+	res, err := srv.handleSaveForecastTemplate(ctx, testProject, testBoard, in, false)
+	if err != nil {
+		t.Fatalf("handleSaveForecastTemplate: %v", err)
+	}
+	templates := res.(ResponseEnvelope).Data.(map[string]any)["forecast_templates"].([]ForecastTemplate)
+	if len(templates) != 1 || templates[0].Name != "roadmap" || len(templates[0].IssueTypes) != 1 {
+		t.Errorf("Expected the trimmed template roadmap, got %+v", templates)
+	}
+
+	// The call's parameters win; the template fills the others.
+	args, err := srv.applyForecastTemplate(ForecastMonteCarloInput{
+		ProjectKey: testProject, BoardID: testBoard, Template: "roadmap",
+		Targets: map[string]int{"Bug": 3}, IncludeWIP: true,
+	})
+	if err != nil {
+		t.Fatalf("applyForecastTemplate: %v", err)
+	}
+	if args.Targets["Bug"] != 3 || args.Targets["Story"] != 0 {
+		t.Errorf("Expected the call's targets to win, got %v", args.Targets)
+	}
+	if !slices.Equal(args.IssueTypes, []string{"Story"}) || !args.IncludeExistingBacklog || !args.IncludeWIP || args.HistoryWindowDays != 60 {
+		t.Errorf("Expected the template to fill the parameters left out, got %+v", args)
+	}
+	args, _ = srv.applyForecastTemplate(ForecastMonteCarloInput{
+		ProjectKey: testProject, BoardID: testBoard, Template: "roadmap", HistoryStartDate: "2024-01-01",
+	})
+	if args.HistoryWindowDays != 0 {
+		t.Errorf("Expected history_start_date to keep the template's window out, got %d", args.HistoryWindowDays)
+	}
+	if _, err := srv.applyForecastTemplate(ForecastMonteCarloInput{ProjectKey: testProject, BoardID: testBoard, Template: "release"}); err == nil {
+		t.Error("Expected an unknown template to fail")
+	}
+
+	// The templates are persisted with the workflow.
+	reloaded := NewServer(&config.AppConfig{CacheDir: srv.cacheDir}, &DummyClient{})
+	if _, err := reloaded.loadWorkflow(testProject, testBoard); err != nil {
+		t.Fatal(err)
+	}
+	if got := reloaded.activeForecastTemplates["roadmap"]; got.Targets["Story"] != 10 {
+		t.Errorf("Expected the persisted template, got %+v", got)
+	}
+
+	if _, err := srv.handleSaveForecastTemplate(ctx, testProject, testBoard, ForecastTemplate{Name: "release"}, true); err == nil {
+		t.Error("Expected removing an unknown template to fail")
+	}
+	if _, err := srv.handleSaveForecastTemplate(ctx, testProject, testBoard, ForecastTemplate{Name: "roadmap"}, true); err != nil {
+		t.Fatal(err)
+	}
+	if srv.activeForecastTemplates != nil {
+		t.Errorf("Expected no template left, got %v", srv.activeForecastTemplates)
+	}
+}
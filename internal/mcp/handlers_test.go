@@ -1,14 +1,17 @@
 package mcp
 
 import (
+	"context"
 	"testing"
 	"time"
 
+	"mcs-mcp/internal/eventlog"
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
 )
 
 func TestAnalyticalIntegrity_TemporalOrdering(t *testing.T) {
+	ctx := context.Background()
 	// Setup Server with minimal context
 	s := &Server{
 		activeMapping: map[string]stats.StatusMetadata{
@@ -65,7 +68,7 @@ func TestAnalyticalIntegrity_TemporalOrdering(t *testing.T) {
 	}
 
 	// 1. Test getCycleTimes (The fix)
-	cycleTimes, matchedIssues := s.getCycleTimes("PROJ", 1, issues, "In Progress", "Done", nil)
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, "PROJ", 1, issues, "In Progress", "Done", nil)
 
 	if len(cycleTimes) != 3 {
 		t.Fatalf("expected 3 cycle times, got %d", len(cycleTimes))
@@ -86,7 +89,7 @@ func TestAnalyticalIntegrity_TemporalOrdering(t *testing.T) {
 
 	// 2. Test map iteration randomness protection (re-run a few times)
 	for try := 0; try < 10; try++ {
-		cts, matched := s.getCycleTimes("PROJ", 1, issues, "In Progress", "Done", nil)
+		cts, matched := s.getCycleTimes(ctx, "PROJ", 1, issues, "In Progress", "Done", nil)
 		if matched[1].Key != "PROJ-2" {
 			t.Fatalf("map iteration or type clustering broke order on try %d", try)
 		}
@@ -95,3 +98,148 @@ func TestAnalyticalIntegrity_TemporalOrdering(t *testing.T) {
 		}
 	}
 }
+
+func TestGetCycleTimes_TypeCommitmentPoints(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{
+		activeMapping: map[string]stats.StatusMetadata{
+			"Todo":        {Tier: "Demand"},
+			"Triaged":     {Tier: "Upstream"},
+			"In Progress": {Tier: "Downstream"},
+			"Done":        {Tier: "Finished", Outcome: "delivered"},
+		},
+		activeStatusOrder:     []string{"Todo", "Triaged", "In Progress", "Done"},
+		activeCommitmentPoint: "In Progress",
+		activeTypeCommitments: map[string]string{"Bug": "Triaged"},
+		activeSourceID:        "PROJ_1",
+	}
+
+	done := time.Now().AddDate(0, 0, -1)
+	residency := map[string]int64{"Triaged": 86400 * 2, "In Progress": 86400 * 3}
+	issues := []jira.Issue{
+		{Key: "PROJ-1", IssueType: "Story", Status: "Done", Outcome: "delivered", OutcomeDate: &done, StatusResidency: residency},
+		{Key: "PROJ-2", IssueType: "Bug", Status: "Done", Outcome: "delivered", OutcomeDate: &done, StatusResidency: residency},
+	}
+
+	// The board commitment point applies the Bug override.
+	cycleTimes, _ := s.getCycleTimes(ctx, "PROJ", 1, issues, "In Progress", "", nil)
+	if len(cycleTimes) != 2 || cycleTimes[0] != 3 || cycleTimes[1] != 5 {
+		t.Errorf("expected Story=3 and Bug=5 days, got %v", cycleTimes)
+	}
+
+	// An explicit, different start status applies to every type.
+	cycleTimes, _ = s.getCycleTimes(ctx, "PROJ", 1, issues, "Triaged", "", nil)
+	if len(cycleTimes) != 2 || cycleTimes[0] != 5 || cycleTimes[1] != 5 {
+		t.Errorf("expected explicit start to override per-type points, got %v", cycleTimes)
+	}
+}
+
+func TestResolveCalendar(t *testing.T) {
+	s := &Server{}
+	if cal, err := s.resolveCalendar(nil, nil); err != nil || cal != nil {
+		t.Fatalf("Expected no calendar without configuration or overrides, got %v, %v", cal, err)
+	}
+
+	cal, err := s.resolveCalendar([]string{"2026-12-25"}, []string{"2026-12-28..2026-12-31"})
+	if err != nil {
+		t.Fatalf("resolveCalendar: %v", err)
+	}
+	summary := cal.Summary()
+	if len(summary.Workdays) != 5 || len(summary.Holidays) != 1 || len(summary.FreezePeriods) != 1 {
+		t.Errorf("Expected a Mon–Fri calendar with one holiday and one freeze, got %+v", summary)
+	}
+
+	s.calendar = cal
+	if _, err := s.resolveCalendar([]string{"2027-01-01"}, nil); err != nil {
+		t.Fatalf("resolveCalendar: %v", err)
+	}
+	if len(s.calendar.Holidays) != 1 {
+		t.Errorf("Per-call holidays must not leak into the configured calendar, got %v", s.calendar.Holidays)
+	}
+
+	if _, err := s.resolveCalendar(nil, []string{"2026-12-28"}); err == nil {
+		t.Errorf("Expected a malformed freeze period to be rejected")
+	}
+}
+
+func TestAnalyzeCycleTimeScatter(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleAnalyzeCycleTimeScatter(ctx, testProject, testBoard, "", "", nil)
+	if err != nil {
+		t.Fatalf("analyze_cycle_time_scatter: %v", err)
+	}
+	scatter := res.(ResponseEnvelope).Data.(map[string]any)["cycle_time_scatter"].(stats.CycleTimeScatter)
+	if len(scatter.Points) == 0 || scatter.Bands.Count != len(scatter.Points) {
+		t.Fatalf("Expected bands over every plotted point, got %d points and %+v", len(scatter.Points), scatter.Bands)
+	}
+	if !(scatter.Bands.P50 <= scatter.Bands.P85 && scatter.Bands.P85 <= scatter.Bands.P95) {
+		t.Errorf("Bands must be ordered, got %+v", scatter.Bands)
+	}
+	for _, p := range scatter.Points {
+		if p.Key == "" || p.Date == "" {
+			t.Fatalf("Every point needs a key and a completion date, got %+v", p)
+		}
+	}
+}
+
+func TestAnalyzeAbandonment(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleAnalyzeAbandonment(ctx, testProject, testBoard, nil)
+	if err != nil {
+		t.Fatalf("analyze_abandonment: %v", err)
+	}
+	abandonment := res.(ResponseEnvelope).Data.(map[string]any)["abandonment"].(stats.AbandonmentAnalysis)
+	if abandonment.Abandoned == 0 || abandonment.Delivered == 0 {
+		t.Fatalf("Expected abandoned and delivered items in the fixture, got %+v", abandonment)
+	}
+	byTier := 0
+	for _, p := range abandonment.ByTier {
+		byTier += p.Count
+	}
+	if byTier != abandonment.Abandoned {
+		t.Errorf("Expected every abandoned item in one tier, got %d of %d", byTier, abandonment.Abandoned)
+	}
+
+	srv.activeMapping = nil
+	if _, err := srv.handleAnalyzeAbandonment(ctx, testProject, testBoard, nil); err == nil {
+		t.Error("Expected an error without a workflow mapping")
+	}
+}
+
+func TestAnalyzeReleaseBurnup(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	ts := srv.Clock(ctx).AddDate(0, 0, -20).UnixMicro()
+	day := int64(86400 * 1e6)
+	release := []jira.FixVersion{{Name: "2.4"}}
+	appendCachedEvents(t, srv, []eventlog.IssueEvent{
+		{EventType: eventlog.Created, IssueKey: "REL-1", IssueType: "Story", ToStatus: "Open", ToStatusID: "1", FixVersions: release, Timestamp: ts},
+		{EventType: eventlog.Created, IssueKey: "REL-2", IssueType: "Story", ToStatus: "Open", ToStatusID: "1", FixVersions: release, Timestamp: ts + 10*day},
+		{EventType: eventlog.Change, IssueKey: "REL-1", IssueType: "Story", ToStatus: "Done", ToStatusID: "10003", Resolution: "Done", Timestamp: ts + 12*day},
+	})
+
+	res, err := srv.handleAnalyzeReleaseBurnup(ctx, testProject, testBoard, "2.4", "")
+	if err != nil {
+		t.Fatalf("analyze_release_burnup: %v", err)
+	}
+	burnup := res.(ResponseEnvelope).Data.(map[string]any)["release_burnup"].(stats.ReleaseBurnup)
+	if burnup.Scope != 2 || burnup.Delivered != 1 || burnup.Remaining != 1 {
+		t.Errorf("Expected 1 of 2 items delivered, got %+v", burnup)
+	}
+	last := burnup.Series[len(burnup.Series)-1]
+	if last.Scope != 2 || last.Completed != 1 || burnup.InitialScope != 1 {
+		t.Errorf("Expected scope to grow from 1 to 2 with 1 completed, got initial %d and %+v", burnup.InitialScope, last)
+	}
+
+	if _, err := srv.handleAnalyzeReleaseBurnup(ctx, testProject, testBoard, "9.9", ""); err == nil {
+		t.Errorf("Expected an error for a release without issues")
+	}
+	if _, err := srv.handleAnalyzeReleaseBurnup(ctx, testProject, testBoard, "2.4", "quarter"); err == nil {
+		t.Errorf("Expected an error for an unsupported bucket")
+	}
+}
@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// ThroughputHistogram is the daily throughput sample a forecast_monte_carlo
+// run draws from, as the baseline engine builds it.
+type ThroughputHistogram struct {
+	Summary          simulation.HistogramSummary `json:"summary"`
+	Dates            []string                    `json:"dates,omitempty"` // day of each bucket; omitted when outlier trimming dropped days
+	Counts           []int                       `json:"counts"`
+	StratifiedCounts map[string][]int            `json:"stratified_counts"`
+	Meta             map[string]any              `json:"meta"`
+}
+
+// handleAnalyzeThroughputHistogram exposes the throughput histogram of a
+// forecast: the daily counts, the counts per type, and the histogram meta,
+// built from the same window, calendar, type aliases, and outlier policy as
+// forecast_monte_carlo.
+func (s *Server) handleAnalyzeThroughputHistogram(ctx context.Context, projectKey string, boardID int, sampleDays int, sampleStartDate, sampleEndDate string, holidays, freezePeriods []string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	calendar, err := s.resolveCalendar(holidays, freezePeriods)
+	if err != nil {
+		return nil, err
+	}
+	histStart, histEnd, err := s.forecastSampleWindow(ctx, sampleDays, sampleStartDate, sampleEndDate, false)
+	if err != nil {
+		return nil, err
+	}
+	window := stats.NewAnalysisWindow(histStart, histEnd, "day", s.activeCutoff())
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+	external := s.externalThroughput(hctx.SourceID, histStart, histEnd)
+
+	h := simulation.BaselineHistogram(simulation.ForecastRequest{
+		Finished:         session.GetFinished(),
+		WindowStart:      window.Start,
+		WindowEnd:        window.End,
+		Calendar:         calendar,
+		Outliers:         s.outliers(ctx),
+		Outcomes:         s.outcomes(ctx),
+		External:         external,
+		TypeAliases:      s.activeTypeAliases,
+		WorkflowMappings: s.activeMapping,
+		Resolutions:      s.activeResolutions,
+	})
+	res := ThroughputHistogram{
+		Summary:          h.Summary(),
+		Counts:           h.Counts,
+		StratifiedCounts: h.StratifiedCounts,
+		Meta:             h.Meta,
+	}
+	start := window.Start
+	if res.Summary.External != nil && external.Start.Before(start) {
+		start = external.Start
+	}
+	if dates := simulation.BucketDates(calendar, start, window.End); len(dates) == len(h.Counts) {
+		res.Dates = make([]string, len(dates))
+		for i, d := range dates {
+			res.Dates[i] = d.Format(stats.DateFormat)
+		}
+	}
+
+	var warnings []string
+	if res.Summary.Delivered == 0 {
+		warnings = append(warnings, fmt.Sprintf("NO THROUGHPUT: no item was delivered between %s and %s, so forecasts from this window would be infinite (duration) or zero (scope). Widen the window via history_window_days.",
+			window.Start.Format(stats.DateFormat), window.End.Format(stats.DateFormat)))
+	}
+	warnings = append(warnings, s.getQualityWarnings(ctx, all)...)
+	insights := []string{
+		fmt.Sprintf("%d of %d sampled days delivered nothing; the busiest day delivered %d items, the mean is %.3f per day.",
+			res.Summary.ZeroDays, res.Summary.Days, res.Summary.MaxPerDay, res.Summary.MeanPerDay),
+	}
+	insights = append(insights, s.guidanceFor("analyze_throughput_histogram", guidanceFacts{})...)
+
+	envelope := WrapResponse(res, projectKey, boardID, nil, warnings, insights)
+	envelope.Context["engine"] = s.engineName
+	envelope.Context["sample_window"] = map[string]string{"start": window.Start.Format(stats.DateFormat), "end": window.End.Format(stats.DateFormat)}
+	if calendar != nil {
+		envelope.Context["calendar"] = calendar.Summary()
+	}
+	return envelope, nil
+}
@@ -0,0 +1,55 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+)
+
+func TestAnalyzeThroughputHistogram(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	res, err := srv.handleAnalyzeThroughputHistogram(ctx, testProject, testBoard, 365, "", "", nil, nil)
+	if err != nil {
+		t.Fatalf("analyze_throughput_histogram: %v", err)
+	}
+	envelope := res.(ResponseEnvelope)
+	h := envelope.Data.(ThroughputHistogram)
+	if len(h.Counts) == 0 || len(h.Dates) != len(h.Counts) {
+		t.Fatalf("Expected one date per daily count, got %d dates for %d counts", len(h.Dates), len(h.Counts))
+	}
+	total := 0
+	for _, c := range h.Counts {
+		total += c
+	}
+	if total == 0 || total != h.Summary.Delivered {
+		t.Errorf("Expected the summary to count the %d delivered items, got %+v", total, h.Summary)
+	}
+	perType := 0
+	for _, counts := range h.StratifiedCounts {
+		if len(counts) != len(h.Counts) {
+			t.Errorf("Expected per-type rows as long as the counts, got %d vs %d", len(counts), len(h.Counts))
+		}
+		for _, c := range counts {
+			perType += c
+		}
+	}
+	if perType != total {
+		t.Errorf("Expected the per-type counts to add up to %d, got %d", total, perType)
+	}
+	if _, ok := h.Meta["type_distribution"]; !ok {
+		t.Errorf("Expected the histogram meta, got %v", h.Meta)
+	}
+
+	res, err = srv.handleAnalyzeThroughputHistogram(ctx, testProject, testBoard, 365, "", "", []string{"2026-01-01"}, nil)
+	if err != nil {
+		t.Fatalf("analyze_throughput_histogram with holidays: %v", err)
+	}
+	cal := res.(ResponseEnvelope).Data.(ThroughputHistogram)
+	if len(cal.Counts) >= len(h.Counts) || len(cal.Dates) != len(cal.Counts) {
+		t.Errorf("Expected working days only with a calendar, got %d counts and %d dates (vs %d days)", len(cal.Counts), len(cal.Dates), len(h.Counts))
+	}
+	if cal.Summary.Delivered != total {
+		t.Errorf("Expected the calendar to keep the volume %d, got %d", total, cal.Summary.Delivered)
+	}
+}
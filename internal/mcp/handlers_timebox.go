@@ -0,0 +1,237 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+// timebox is the committed item set of a sprint or of any start/end range.
+type timebox struct {
+	Sprint *jira.Sprint // nil when the timebox is not a sprint
+	Start  time.Time
+	End    time.Time // Last day of the timebox
+	Keys   []string
+}
+
+// resolveTimebox returns the timebox of a forecast_timebox call: the given
+// sprint, the board's active sprint when neither a sprint nor issue keys are
+// given, or the issue keys with their start and end dates. Explicit keys and
+// dates override those of the sprint.
+func (s *Server) resolveTimebox(ctx context.Context, projectKey string, boardID, sprintID int, issueKeys []string, startDate, endDate string) (timebox, error) {
+	var tb timebox
+	for _, k := range issueKeys {
+		if k = strings.ToUpper(strings.TrimSpace(k)); k != "" && !slices.Contains(tb.Keys, k) {
+			tb.Keys = append(tb.Keys, k)
+		}
+	}
+
+	if sprintID != 0 || len(tb.Keys) == 0 {
+		if isQuerySource(projectKey) {
+			return tb, errQuerySourceSprints
+		}
+		sprints, err := s.jira.GetSprints(ctx, boardID, SprintHistoryLimit)
+		if err != nil {
+			return tb, fmt.Errorf("failed to fetch sprints: %w", err)
+		}
+		for i, sp := range sprints {
+			if (sprintID != 0 && sp.ID == sprintID) || (sprintID == 0 && sp.State == "active" && (tb.Sprint == nil || sp.Start.After(tb.Sprint.Start))) {
+				tb.Sprint = &sprints[i]
+			}
+		}
+		switch {
+		case tb.Sprint == nil && sprintID != 0:
+			return tb, fmt.Errorf("sprint %d not found among the last %d sprints of board %d", sprintID, SprintHistoryLimit, boardID)
+		case tb.Sprint == nil:
+			return tb, fmt.Errorf("board %d has no active sprint; set sprint_id, or issue_keys with an end_date", boardID)
+		case tb.Sprint.State == "closed":
+			return tb, fmt.Errorf("sprint %q is closed; use analyze_sprint_history to review it", tb.Sprint.Name)
+		}
+		tb.Start, tb.End = tb.Sprint.Start, tb.Sprint.End
+		if len(tb.Keys) == 0 {
+			tb.Keys = tb.Sprint.IssueKeys
+		}
+	}
+
+	if startDate != "" {
+		t, err := time.Parse(stats.DateFormat, startDate)
+		if err != nil {
+			return tb, fmt.Errorf("invalid start_date format: %w", err)
+		}
+		tb.Start = t
+	}
+	if endDate != "" {
+		t, err := time.Parse(stats.DateFormat, endDate)
+		if err != nil {
+			return tb, fmt.Errorf("invalid end_date format: %w", err)
+		}
+		tb.End = t
+	}
+	switch {
+	case tb.End.IsZero():
+		return tb, fmt.Errorf("end_date is required with issue_keys")
+	case len(tb.Keys) == 0:
+		return tb, fmt.Errorf("no committed items: the sprint has no issues assigned; set issue_keys")
+	case tb.Start.IsZero():
+		tb.Start = s.Clock(ctx)
+	}
+	if tb.End.Before(tb.Start) {
+		return tb, fmt.Errorf("end_date %s is before the start %s", tb.End.Format(stats.DateFormat), tb.Start.Format(stats.DateFormat))
+	}
+	return tb, nil
+}
+
+// handleForecastTimebox forecasts whether the unfinished items committed to a
+// timebox are delivered by its last day, from the same daily throughput sample
+// as forecast_burnup. The remaining days count today.
+func (s *Server) handleForecastTimebox(ctx context.Context, projectKey string, boardID, sprintID int, issueKeys []string, startDate, endDate string, sampleDays int, holidays, freezePeriods []string) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	tb, err := s.resolveTimebox(ctx, projectKey, boardID, sprintID, issueKeys, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+	now := s.Clock(ctx)
+	days := stats.CalendarDaysBetween(now, tb.End) + 1
+	if days < 1 {
+		return nil, fmt.Errorf("the timebox ended on %s", tb.End.Format(stats.DateFormat))
+	}
+	calendar, err := s.resolveCalendar(holidays, freezePeriods)
+	if err != nil {
+		return nil, err
+	}
+
+	// Committed items are looked up over the whole history: they may have
+	// been created long before the throughput sample starts.
+	fullWindow := stats.NewAnalysisWindow(time.Time{}, now, "day", s.activeCutoff())
+	byKey := make(map[string]jira.Issue)
+	for _, issue := range s.openSession(ctx, hctx, fullWindow).GetAllIssues() {
+		byKey[issue.Key] = issue
+	}
+	var done, abandoned, unknown []string
+	var remaining []map[string]string
+	for _, key := range tb.Keys {
+		issue, ok := byKey[key]
+		switch {
+		case !ok:
+			unknown = append(unknown, key)
+			remaining = append(remaining, map[string]string{"key": key})
+		case stats.IsDelivered(issue):
+			done = append(done, key)
+		case stats.HasExited(issue):
+			abandoned = append(abandoned, key)
+		default:
+			remaining = append(remaining, map[string]string{"key": key, "issue_type": issue.IssueType, "status": issue.Status})
+		}
+	}
+
+	histStart, histEnd, err := s.forecastSampleWindow(ctx, sampleDays, "", "", false)
+	if err != nil {
+		return nil, err
+	}
+	window := stats.NewAnalysisWindow(histStart, histEnd, "day", s.activeCutoff())
+	session := s.openSession(ctx, hctx, window)
+	all := session.GetAllIssues()
+
+	sample := map[string]any{
+		"start_date": window.Start.Format(stats.DateFormat),
+		"end_date":   window.End.Format(stats.DateFormat),
+	}
+	var resObj simulation.Result
+	if len(remaining) == 0 {
+		resObj.Timebox = &simulation.TimeboxOutcome{Probability: 1}
+	} else {
+		var delivered int
+		resObj, delivered, err = s.simulateTimebox(ctx, session, window, calendar, len(remaining), days)
+		if err != nil {
+			return nil, err
+		}
+		sample["delivered_items"] = delivered
+	}
+
+	s.annotateForecast(ctx, &resObj, "scope", days, calendar)
+	totalDays := stats.CalendarDaysBetween(tb.Start, tb.End) + 1
+	elapsed := min(totalDays, max(0, stats.CalendarDaysBetween(tb.Start, now)))
+	box := map[string]any{
+		"start_date":      tb.Start.Format(stats.DateFormat),
+		"end_date":        tb.End.Format(stats.DateFormat),
+		"days_remaining":  days,
+		"days_elapsed":    elapsed,
+		"committed":       len(tb.Keys),
+		"done":            len(done),
+		"remaining_items": remaining,
+	}
+	if tb.Sprint != nil {
+		box["sprint_id"] = tb.Sprint.ID
+		box["sprint_name"] = tb.Sprint.Name
+	}
+	if len(abandoned) > 0 {
+		box["abandoned"] = abandoned
+	}
+	resObj.Context["timebox"] = box
+	resObj.Context["sample"] = sample
+
+	insights := resObj.Insights
+	warnings := resObj.Warnings
+	if outcome := resObj.Timebox; len(remaining) == 0 {
+		insights = append(insights, fmt.Sprintf("All %d committed items are finished.", len(tb.Keys)))
+	} else {
+		insights = append(insights, fmt.Sprintf("%.0f%% probability that all %d remaining items are done by %s (%d days left, counting today).",
+			outcome.Probability*100, outcome.Remaining, tb.End.Format(stats.DateFormat), days))
+		if outcome.DescopeP85 > 0 {
+			insights = append(insights, fmt.Sprintf("To keep the commitment at 85%% confidence, de-scope %d item(s): at least %.0f are delivered with 85%% probability. A coin-toss commitment needs %d dropped.",
+				outcome.DescopeP85, resObj.Percentiles.Likely, outcome.DescopeP50))
+		} else {
+			insights = append(insights, "No de-scope needed: the remaining items are delivered with at least 85% probability.")
+		}
+		if totalDays > 0 && elapsed > 0 {
+			insights = append(insights, fmt.Sprintf("%d%% of the timebox has elapsed and %d%% of the committed items are done.", 100*elapsed/totalDays, 100*len(done)/len(tb.Keys)))
+		}
+	}
+	insights = append(insights, s.guidanceFor("forecast_timebox", guidanceFacts{})...)
+	if len(unknown) > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d committed item(s) are not in the cached history and are counted as remaining: %s.", len(unknown), strings.Join(unknown, ", ")))
+	}
+	warnings = append(warnings, s.getQualityWarnings(ctx, all)...)
+	resObj.Warnings = nil
+	resObj.Insights = nil
+
+	return WrapResponse(resObj, projectKey, boardID, nil, warnings, insights), nil
+}
+
+// simulateTimebox runs the scope simulation over the next days (counting
+// today) with a timebox commitment of the given remaining items, sampling the
+// daily throughput of the session's window. Also returns the number of
+// delivered items in the sample.
+func (s *Server) simulateTimebox(ctx context.Context, session *stats.AnalysisSession, window stats.AnalysisWindow, calendar *simulation.Calendar, remaining, days int) (simulation.Result, int, error) {
+	h := simulation.NewHistogram(session.GetFinished(), window.Start, window.End, nil, s.activeMapping, s.activeResolutions, s.outcomes(ctx))
+	delivered, _ := h.Meta["issues_analyzed"].(int)
+	if delivered == 0 {
+		return simulation.Result{}, 0, fmt.Errorf("no delivered items between %s and %s to sample throughput from; widen the window via history_window_days", window.Start.Format(stats.DateFormat), window.End.Format(stats.DateFormat))
+	}
+	h.RestrictToWorkingDays(calendar, window.Start)
+
+	engine := simulation.NewEngine(h)
+	engine.SetContext(ctx)
+	if s.simulationSeed != 0 {
+		engine.SetSeed(s.simulationSeed)
+	}
+	if calendar != nil {
+		engine.SetCalendar(calendar, s.Clock(ctx))
+	}
+	engine.SetTimeboxCommitment(remaining)
+	res := engine.RunScopeSimulation(days, simulation.DefaultTrials)
+	if err := engine.Err(); err != nil {
+		return simulation.Result{}, 0, fmt.Errorf("simulation cancelled: %w", err)
+	}
+	res.Round()
+	return res, delivered, nil
+}
@@ -0,0 +1,76 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+)
+
+func TestForecastTimebox(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.simulationSeed = 42
+
+	ts := srv.Clock(ctx).AddDate(0, 0, -3).UnixMicro()
+	day := int64(86400 * 1e6)
+	var events []eventlog.IssueEvent
+	for _, key := range []string{"ITEM-1", "ITEM-2", "ITEM-3"} {
+		events = append(events, eventlog.IssueEvent{EventType: eventlog.Created, IssueKey: key, IssueType: "Story", ToStatus: "Open", ToStatusID: "1", Timestamp: ts})
+	}
+	events = append(events, eventlog.IssueEvent{EventType: eventlog.Change, IssueKey: "ITEM-3", IssueType: "Story", ToStatus: "Done", ToStatusID: "10003", Resolution: "Done", Timestamp: ts + day})
+	appendCachedEvents(t, srv, events)
+
+	sprints := twoWeekSprints(srv.Clock(ctx).AddDate(0, 0, -4), 3)
+	sprints[3].Name = "Sprint 4"
+	sprints[3].IssueKeys = []string{"ITEM-1", "ITEM-2", "ITEM-3", "NOPE-1"}
+	srv.jira = &sprintClient{sprints: sprints}
+
+	res, err := srv.handleForecastTimebox(ctx, testProject, testBoard, 0, nil, "", "", 365, nil, nil)
+	if err != nil {
+		t.Fatalf("forecast_timebox: %v", err)
+	}
+	env := res.(ResponseEnvelope)
+	result := env.Data.(simulation.Result)
+	if result.Timebox == nil || result.Timebox.Remaining != 3 {
+		t.Fatalf("Expected ITEM-1, ITEM-2 and the unknown NOPE-1 to remain, got %+v", result.Timebox)
+	}
+	if result.Timebox.Probability <= 0 || result.Timebox.Probability > 1 {
+		t.Errorf("Expected a probability in (0, 1], got %v", result.Timebox.Probability)
+	}
+	if result.Timebox.DescopeP85 < result.Timebox.DescopeP50 {
+		t.Errorf("Expected the P85 de-scope to be at least the P50 one, got %+v", result.Timebox)
+	}
+	box := result.Context["timebox"].(map[string]any)
+	if box["sprint_name"] != "Sprint 4" || box["done"] != 1 || box["days_remaining"] != 11 {
+		t.Errorf("Expected the active sprint with 1 done item and 11 days left, got %v", box)
+	}
+	if env.Guardrails == nil || len(env.Guardrails.Warnings) == 0 {
+		t.Errorf("Expected a warning for the unknown key NOPE-1")
+	}
+
+	end := srv.Clock(ctx).AddDate(0, 0, 6).Format(stats.DateFormat)
+	res, err = srv.handleForecastTimebox(ctx, testProject, testBoard, 0, []string{"item-3", " ITEM-3"}, "", end, 365, nil, nil)
+	if err != nil {
+		t.Fatalf("forecast_timebox with issue_keys: %v", err)
+	}
+	result = res.(ResponseEnvelope).Data.(simulation.Result)
+	if result.Timebox.Probability != 1 || result.Timebox.Remaining != 0 {
+		t.Errorf("Expected a finished commitment to be certain, got %+v", result.Timebox)
+	}
+
+	if _, err := srv.handleForecastTimebox(ctx, testProject, testBoard, 0, []string{"ITEM-1"}, "", "", 0, nil, nil); err == nil {
+		t.Errorf("Expected an error for issue_keys without end_date")
+	}
+	if _, err := srv.handleForecastTimebox(ctx, testProject, testBoard, 1, nil, "", "", 0, nil, nil); err == nil {
+		t.Errorf("Expected an error for a closed sprint")
+	}
+	if _, err := srv.handleForecastTimebox(ctx, testProject, testBoard, 99, nil, "", "", 0, nil, nil); err == nil {
+		t.Errorf("Expected an error for an unknown sprint")
+	}
+	if _, err := srv.handleForecastTimebox(ctx, testProject, testBoard, 0, []string{"ITEM-1"}, "", "2020-01-01", 0, nil, nil); err == nil {
+		t.Errorf("Expected an error for an end date in the past")
+	}
+}
@@ -0,0 +1,82 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"mcs-mcp/internal/simulation"
+
+	"github.com/rs/zerolog/log"
+)
+
+// TypeAliasGroup is a canonical issue type and the types grouped under it.
+type TypeAliasGroup struct {
+	Type           string         `json:"type"`
+	Aliases        []string       `json:"aliases"`
+	Delivered      map[string]int `json:"delivered"` // Delivered items in the session window per member type
+	DeliveredTotal int            `json:"delivered_total"`
+}
+
+// handleSetTypeAliases replaces the issue type aliases of the active board and
+// persists them with the workflow metadata. An empty map removes all aliases.
+func (s *Server) handleSetTypeAliases(ctx context.Context, projectKey string, boardID int, aliases map[string]string) (any, error) {
+	for alias, canonical := range aliases {
+		if alias == "" || canonical == "" {
+			return nil, fmt.Errorf("issue type aliases need a type name on both sides, got %q → %q", alias, canonical)
+		}
+		if alias == canonical {
+			return nil, fmt.Errorf("issue type %q cannot be an alias of itself", alias)
+		}
+		if next, ok := aliases[canonical]; ok {
+			return nil, fmt.Errorf("%q is aliased to %q, which is itself aliased to %q; map every alias to the canonical type directly", alias, canonical, next)
+		}
+	}
+
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	before := s.configSnapshot()
+	s.activeTypeAliases = nil
+	if len(aliases) > 0 {
+		s.activeTypeAliases = simulation.TypeAliases(maps.Clone(aliases))
+	}
+	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+		log.Error().Err(err).Msg("Failed to save workflow metadata")
+		return nil, fmt.Errorf("type aliases updated in memory but failed to save to disk: %w", err)
+	}
+	s.recordAudit(ctx, projectKey, boardID, before)
+
+	delivered := make(map[string]int)
+	for _, issue := range s.openSession(ctx, hctx, s.AnalysisWindow(ctx, "day")).GetDelivered() {
+		delivered[issue.IssueType]++
+	}
+
+	groups := make(map[string]*TypeAliasGroup)
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		canonical := aliases[alias]
+		g, ok := groups[canonical]
+		if !ok {
+			g = &TypeAliasGroup{Type: canonical, Delivered: map[string]int{canonical: delivered[canonical]}, DeliveredTotal: delivered[canonical]}
+			groups[canonical] = g
+		}
+		g.Aliases = append(g.Aliases, alias)
+		g.Delivered[alias] = delivered[alias]
+		g.DeliveredTotal += delivered[alias]
+	}
+	res := make([]TypeAliasGroup, 0, len(groups))
+	for _, canonical := range slices.Sorted(maps.Keys(groups)) {
+		res = append(res, *groups[canonical])
+	}
+
+	var warnings []string
+	for _, alias := range slices.Sorted(maps.Keys(aliases)) {
+		if delivered[alias] == 0 {
+			warnings = append(warnings, fmt.Sprintf("No delivered %q items in the analysis window; check the spelling of the issue type.", alias))
+		}
+	}
+	guidance := s.guidanceFor("workflow_set_type_aliases", guidanceFacts{})
+	return WrapResponse(map[string]any{"type_aliases": res}, projectKey, boardID, nil, warnings, guidance), nil
+}
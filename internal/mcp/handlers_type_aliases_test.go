@@ -0,0 +1,73 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/simulation"
+)
+
+func TestHandleSetTypeAliases(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	invalid := []map[string]string{
+		{"Defect": "Defect"},
+		{"Defect": ""},
+		{"Defect": "Bug", "Bug": "Story"},
+	}
+	for _, aliases := range invalid {
+		if _, err := srv.handleSetTypeAliases(ctx, testProject, testBoard, aliases); err == nil {
+			t.Errorf("Expected aliases %v to be rejected", aliases)
+		}
+	}
+
+	res, err := srv.handleSetTypeAliases(ctx, testProject, testBoard, map[string]string{"Defect": "Bug"})
+	if err != nil {
+		t.Fatalf("handleSetTypeAliases: %v", err)
+	}
+	groups := res.(ResponseEnvelope).Data.(map[string]any)["type_aliases"].([]TypeAliasGroup)
+	if len(groups) != 1 || groups[0].Type != "Bug" || groups[0].Delivered["Defect"] == 0 {
+		t.Fatalf("Expected Defect grouped under Bug with delivered items, got %+v", groups)
+	}
+	if groups[0].DeliveredTotal != groups[0].Delivered["Bug"]+groups[0].Delivered["Defect"] {
+		t.Errorf("Expected the total to sum the member types, got %+v", groups[0])
+	}
+
+	// The aliases are persisted with the workflow.
+	reloaded := NewServer(&config.AppConfig{CacheDir: srv.cacheDir}, &DummyClient{})
+	if _, err := reloaded.loadWorkflow(testProject, testBoard); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.activeTypeAliases.Canonical("Defect") != "Bug" {
+		t.Errorf("Expected persisted aliases, got %v", reloaded.activeTypeAliases)
+	}
+
+	// Targets of an alias are forecast as the canonical type.
+	forecast := func(targets map[string]int) simulation.Result {
+		res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+			ProjectKey:        testProject,
+			BoardID:           testBoard,
+			Mode:              "duration",
+			HistoryWindowDays: 90,
+			Targets:           targets,
+		})
+		if err != nil {
+			t.Fatalf("forecast %v: %v", targets, err)
+		}
+		return res.(ResponseEnvelope).Data.(simulation.Result)
+	}
+	split := forecast(map[string]int{"Bug": 5, "Defect": 5})
+	merged := forecast(map[string]int{"Bug": 10})
+	if split.Percentiles != merged.Percentiles {
+		t.Errorf("Expected aliased targets to forecast like the canonical type: %+v vs %+v", split.Percentiles, merged.Percentiles)
+	}
+
+	if _, err := srv.handleSetTypeAliases(ctx, testProject, testBoard, nil); err != nil {
+		t.Fatal(err)
+	}
+	if srv.activeTypeAliases != nil {
+		t.Errorf("Expected an empty map to remove the aliases, got %v", srv.activeTypeAliases)
+	}
+}
@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// wipLimitTiers are the tiers a WIP limit can be set on, in workflow order.
+var wipLimitTiers = []string{stats.TierDemand, stats.TierUpstream, stats.TierDownstream}
+
+// handleSetWIPLimits records (or removes, with limit 0) the WIP limits of
+// statuses or tiers on the active board and persists them with the workflow
+// metadata. The entries are validated together: one invalid entry leaves the
+// recorded limits unchanged.
+func (s *Server) handleSetWIPLimits(ctx context.Context, projectKey string, boardID int, entries []WIPLimitEntry, clear bool) (any, error) {
+	if err := s.anchorContext(projectKey, boardID); err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 && !clear {
+		return nil, fmt.Errorf("no limits given: pass at least one entry in 'limits', or clear=true")
+	}
+
+	before := s.configSnapshot()
+	limits := make(map[string]stats.WIPLimit)
+	if !clear {
+		maps.Copy(limits, s.activeWIPLimits)
+	}
+	for _, e := range entries {
+		limit, err := s.resolveWIPLimit(e)
+		if err != nil {
+			return nil, err
+		}
+		if e.Limit == 0 {
+			if _, ok := limits[limit.Key()]; !ok {
+				return nil, fmt.Errorf("no WIP limit recorded for %s %q", limit.Scope, limit.Label())
+			}
+			delete(limits, limit.Key())
+			continue
+		}
+		limits[limit.Key()] = limit
+	}
+	s.activeWIPLimits = limits
+	if len(limits) == 0 {
+		s.activeWIPLimits = nil
+	}
+
+	if err := s.saveWorkflow(projectKey, boardID); err != nil {
+		log.Error().Err(err).Msg("Failed to save workflow metadata")
+		return nil, fmt.Errorf("WIP limits updated in memory but failed to save to disk: %w", err)
+	}
+	s.recordAudit(ctx, projectKey, boardID, before)
+
+	res := map[string]any{
+		"wip_limits": s.sortedWIPLimits(),
+	}
+	guidance := s.guidanceFor("set_wip_limits", guidanceFacts{})
+	return WrapResponse(res, projectKey, boardID, nil, nil, guidance), nil
+}
+
+// resolveWIPLimit validates an entry of set_wip_limits and resolves its status
+// name to an ID.
+func (s *Server) resolveWIPLimit(e WIPLimitEntry) (stats.WIPLimit, error) {
+	if (e.Status == "") == (e.Tier == "") {
+		return stats.WIPLimit{}, fmt.Errorf("each limit needs exactly one of 'status' or 'tier'")
+	}
+	if e.Limit < 0 {
+		return stats.WIPLimit{}, fmt.Errorf("invalid limit %d: expected a positive number, or 0 to remove the limit", e.Limit)
+	}
+
+	if e.Tier != "" {
+		idx := slices.IndexFunc(wipLimitTiers, func(t string) bool { return strings.EqualFold(t, e.Tier) })
+		if idx < 0 {
+			return stats.WIPLimit{}, fmt.Errorf("invalid tier %q: expected one of %s", e.Tier, strings.Join(wipLimitTiers, ", "))
+		}
+		if len(s.activeMapping) == 0 {
+			return stats.WIPLimit{}, fmt.Errorf("tier limits need a workflow mapping; confirm one with 'workflow_set_mapping' first")
+		}
+		return stats.WIPLimit{Scope: stats.WIPLimitTier, Target: wipLimitTiers[idx], Limit: e.Limit}, nil
+	}
+
+	id := e.Status
+	if resolved := s.activeRegistry.GetStatusID(e.Status); resolved != "" {
+		id = resolved
+	}
+	name := s.activeRegistry.GetStatusName(id)
+	if name == "" {
+		name = s.activeMapping[id].Name
+	}
+	if name == "" {
+		return stats.WIPLimit{}, fmt.Errorf("unknown status %q on this board", e.Status)
+	}
+	return stats.WIPLimit{Scope: stats.WIPLimitStatus, Target: id, Name: name, Limit: e.Limit}, nil
+}
+
+// sortedWIPLimits returns the recorded limits, tiers first in workflow order,
+// then statuses in the confirmed workflow order.
+func (s *Server) sortedWIPLimits() []stats.WIPLimit {
+	rank := func(l stats.WIPLimit) int {
+		if l.Scope == stats.WIPLimitTier {
+			return slices.Index(wipLimitTiers, l.Target)
+		}
+		if i := slices.Index(s.activeStatusOrder, l.Target); i >= 0 {
+			return len(wipLimitTiers) + i
+		}
+		return len(wipLimitTiers) + len(s.activeStatusOrder)
+	}
+	limits := slices.Collect(maps.Values(s.activeWIPLimits))
+	slices.SortFunc(limits, func(a, b stats.WIPLimit) int {
+		if c := cmp.Compare(rank(a), rank(b)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Label(), b.Label())
+	})
+	return limits
+}
+
+// handleAnalyzeWIPLimits reconstructs the daily WIP of every limited status or
+// tier over the session window and reports the violations and their
+// correlation with cycle time.
+func (s *Server) handleAnalyzeWIPLimits(ctx context.Context, projectKey string, boardID int) (any, error) {
+	hctx, err := s.prepareHandler(ctx, projectKey, boardID)
+	if err != nil {
+		return nil, err
+	}
+	if len(s.activeWIPLimits) == 0 {
+		return nil, fmt.Errorf("no WIP limit recorded for %s; record one first with 'set_wip_limits' (e.g. status 'In Review', limit 3)", hctx.SourceID)
+	}
+
+	// Project everything from the beginning of time, as in analyze_wip_stability,
+	// so items that entered a status long before the window still count.
+	fullSession := s.openSession(ctx, hctx, stats.NewAnalysisWindow(time.Time{}, s.Clock(ctx), "day", s.activeCutoff()))
+	all := fullSession.GetAllIssues()
+	analysisCtx := s.prepareAnalysisContext(projectKey, boardID, all)
+
+	window := s.AnalysisWindow(ctx, "day")
+	delivered := s.openSession(ctx, hctx, window).GetDelivered()
+	cycleTimes, matchedIssues := s.getCycleTimes(ctx, projectKey, boardID, delivered, analysisCtx.CommitmentPoint, "", nil)
+
+	reports := stats.EvaluateWIPLimits(s.sortedWIPLimits(), all, analysisCtx.WorkflowMappings, window, matchedIssues, cycleTimes)
+
+	var insights []string
+	for _, r := range reports {
+		if r.ViolationDays == 0 {
+			continue
+		}
+		insights = append(insights, fmt.Sprintf("WIP LIMIT EXCEEDED for %s %q (limit %d) on %d of %d days (%.0f%%), longest for %d consecutive days.",
+			r.Limit.Scope, r.Limit.Label(), r.Limit.Limit, r.ViolationDays, r.DaysObserved, r.ViolationRate*100, r.LongestViolationDays))
+		if impact := r.CycleTimeImpact; impact.Inflation > 1 && impact.UnexposedCount > 0 {
+			insights = append(insights, fmt.Sprintf("Items caught in a %q violation had a P85 cycle time of %.1f days vs. %.1f days (%.1fx).",
+				r.Limit.Label(), impact.ExposedP85, impact.UnexposedP85, impact.Inflation))
+		}
+	}
+
+	res := map[string]any{
+		"wip_limits": reports,
+	}
+	guidance := append(insights, s.guidanceFor("analyze_wip_limits", guidanceFacts{})...)
+	return WrapResponse(res, projectKey, boardID, nil, s.getQualityWarnings(ctx, all), guidance), nil
+}
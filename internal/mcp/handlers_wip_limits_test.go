@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/stats"
+)
+
+func TestWIPLimits(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	if _, err := srv.handleAnalyzeWIPLimits(ctx, testProject, testBoard); err == nil {
+		t.Fatalf("Expected an error before any WIP limit is recorded")
+	}
+	for _, bad := range []WIPLimitEntry{
+		{Status: "developing", Tier: "Downstream", Limit: 3},
+		{Status: "no such status", Limit: 3},
+		{Tier: "Finished", Limit: 3},
+		{Status: "developing", Limit: -1},
+	} {
+		if _, err := srv.handleSetWIPLimits(ctx, testProject, testBoard, []WIPLimitEntry{bad}, false); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+	if _, err := srv.handleSetWIPLimits(ctx, testProject, testBoard, []WIPLimitEntry{
+		{Status: "developing", Limit: 1},
+		{Tier: "downstream", Limit: 1000},
+	}, false); err != nil {
+		t.Fatalf("set_wip_limits: %v", err)
+	}
+	if l := srv.activeWIPLimits["status:38777"]; l.Name != "developing" || l.Limit != 1 {
+		t.Errorf("Expected the status name to resolve to its ID, got %+v", srv.activeWIPLimits)
+	}
+
+	res, err := srv.handleAnalyzeWIPLimits(ctx, testProject, testBoard)
+	if err != nil {
+		t.Fatalf("analyze_wip_limits: %v", err)
+	}
+	reports := res.(ResponseEnvelope).Data.(map[string]any)["wip_limits"].([]stats.WIPLimitReport)
+	if len(reports) != 2 || reports[0].Limit.Scope != stats.WIPLimitTier || reports[1].Limit.Target != "38777" {
+		t.Fatalf("Expected the tier limit followed by the status limit, got %+v", reports)
+	}
+	if tier := reports[0]; tier.DaysObserved == 0 || tier.ViolationDays != 0 {
+		t.Errorf("Expected a generous tier limit never to be exceeded, got %d of %d days", tier.ViolationDays, tier.DaysObserved)
+	}
+	if status := reports[1]; status.PeakWIP > 1 && status.ViolationDays == 0 {
+		t.Errorf("Expected violations with a peak WIP of %d against a limit of 1", status.PeakWIP)
+	}
+
+	// Limits survive a context switch via the workflow file.
+	srv.activeWIPLimits = nil
+	if _, err := srv.loadWorkflow(testProject, testBoard); err != nil {
+		t.Fatalf("loadWorkflow: %v", err)
+	}
+	if len(srv.activeWIPLimits) != 2 {
+		t.Errorf("Expected both limits to be persisted, got %+v", srv.activeWIPLimits)
+	}
+
+	if _, err := srv.handleSetWIPLimits(ctx, testProject, testBoard, []WIPLimitEntry{{Status: "developing"}}, false); err != nil {
+		t.Fatalf("remove developing limit: %v", err)
+	}
+	if _, err := srv.handleSetWIPLimits(ctx, testProject, testBoard, []WIPLimitEntry{{Status: "developing"}}, false); err == nil {
+		t.Errorf("Expected an error when removing a limit that is not recorded")
+	}
+	if _, err := srv.handleSetWIPLimits(ctx, testProject, testBoard, nil, true); err != nil || srv.activeWIPLimits != nil {
+		t.Errorf("Expected clear=true to drop every limit, got %v and %+v", err, srv.activeWIPLimits)
+	}
+}
@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"strings"
@@ -9,7 +10,9 @@ import (
 	"mcs-mcp/internal/charts"
 	"mcs-mcp/internal/eventlog"
 	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/render"
 	"mcs-mcp/internal/stats"
+	"mcs-mcp/internal/visuals"
 
 	"github.com/rs/zerolog/log"
 )
@@ -26,16 +29,16 @@ type handlerContext struct {
 
 // prepareHandler performs the standard handler setup: anchor context, resolve source,
 // hydrate the event log, and persist workflow metadata.
-func (s *Server) prepareHandler(projectKey string, boardID int) (*handlerContext, error) {
+func (s *Server) prepareHandler(ctx context.Context, projectKey string, boardID int) (*handlerContext, error) {
 	if err := s.anchorContext(projectKey, boardID); err != nil {
 		return nil, err
 	}
-	ctx, err := s.resolveSourceContext(projectKey, boardID)
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
 	if err != nil {
 		return nil, err
 	}
 	sourceID := getCombinedID(projectKey, boardID)
-	reg, err := s.events.Hydrate(sourceID, projectKey, ctx.JQL, s.activeRegistry)
+	reg, err := s.events.Hydrate(ctx, sourceID, projectKey, sc.JQL, s.activeRegistry)
 	if err != nil {
 		return nil, err
 	}
@@ -43,7 +46,7 @@ func (s *Server) prepareHandler(projectKey string, boardID int) (*handlerContext
 	if err := s.saveWorkflow(projectKey, boardID); err != nil {
 		log.Warn().Err(err).Msg("Failed to persist workflow metadata to disk")
 	}
-	return &handlerContext{SourceID: sourceID, Ctx: ctx}, nil
+	return &handlerContext{SourceID: sourceID, Ctx: sc}, nil
 }
 
 // activeCutoff returns the active discovery cutoff, or the zero time if none is set.
@@ -55,13 +58,18 @@ func (s *Server) activeCutoff() time.Time {
 }
 
 // openSession loads the events for the given window and returns a new AnalysisSession
-// anchored to the handler context.
-func (s *Server) openSession(hctx *handlerContext, window stats.AnalysisWindow) *stats.AnalysisSession {
-	events := s.events.GetIssuesInRange(hctx.SourceID, window.Start, window.End)
-	return stats.NewAnalysisSession(events, hctx.SourceID, *hctx.Ctx, s.activeMapping, s.activeResolutions, window)
+// anchored to the handler context. While the event log is unchanged, the
+// projection of an earlier call with the same window is reused (sessionCache).
+func (s *Server) openSession(ctx context.Context, hctx *handlerContext, window stats.AnalysisWindow) *stats.AnalysisSession {
+	key := s.sessionKey(ctx, hctx, window)
+	if session := s.sessions.get(key); session != nil {
+		return session
+	}
+	histories := s.events.IssuesInRange(hctx.SourceID, window.Start, window.End)
+	return s.sessions.put(key, stats.NewStreamingAnalysisSession(histories, hctx.SourceID, *hctx.Ctx, s.activeMapping, s.activeResolutions, window).WithSubtaskPolicy(s.subtasks(ctx)).WithContainerPolicy(s.containers(ctx)).WithOutcomeScope(s.outcomes(ctx)))
 }
 
-func (s *Server) resolveSourceContext(projectKey string, boardID int) (*jira.SourceContext, error) {
+func (s *Server) resolveSourceContext(ctx context.Context, projectKey string, boardID int) (*jira.SourceContext, error) {
 	if projectKey == "MCSTEST" {
 		return &jira.SourceContext{
 			ProjectKey: "MCSTEST",
@@ -71,6 +79,10 @@ func (s *Server) resolveSourceContext(projectKey string, boardID int) (*jira.Sou
 		}, nil
 	}
 
+	if isQuerySource(projectKey) {
+		return s.resolveQuerySource(ctx, projectKey, boardID)
+	}
+
 	if boardID == 0 {
 		return &jira.SourceContext{
 			ProjectKey: projectKey,
@@ -80,7 +92,7 @@ func (s *Server) resolveSourceContext(projectKey string, boardID int) (*jira.Sou
 		}, nil
 	}
 
-	config, err := s.jira.GetBoard(boardID)
+	config, err := s.jira.GetBoard(ctx, boardID)
 	if err != nil {
 		return nil, err
 	}
@@ -119,7 +131,7 @@ func (s *Server) resolveSourceContext(projectKey string, boardID int) (*jira.Sou
 	if !ok {
 		// Fallback: Try Board Configuration
 		log.Debug().Int("boardID", boardID).Msg("Filter missing in board metadata, trying board configuration")
-		configObj, err := s.jira.GetBoardConfig(boardID)
+		configObj, err := s.jira.GetBoardConfig(ctx, boardID)
 		if err == nil {
 			if conf, isMap := configObj.(map[string]any); isMap {
 				filterObj, ok = conf["filter"].(map[string]any)
@@ -131,27 +143,26 @@ func (s *Server) resolveSourceContext(projectKey string, boardID int) (*jira.Sou
 		return nil, fmt.Errorf("board config missing filter information")
 	}
 
-	filterID := asString(filterObj["id"])
-	filter, err := s.jira.GetFilter(filterID)
+	jql, err := s.filterJQL(ctx, asString(filterObj["id"]))
 	if err != nil {
 		return nil, err
 	}
-	fMap, ok := filter.(map[string]any)
-	if !ok {
-		return nil, fmt.Errorf("invalid filter response format from Jira")
-	}
-	jql := asString(fMap["jql"])
 
-	// Strip and Normalize
-	jql = stripOrderBy(jql)
+	// Validate and strip ORDER BY, so a broken filter cannot escape its parentheses
+	jql, err = jira.NormalizeJQL(jql)
+	if err != nil {
+		return nil, fmt.Errorf("filter of board %d: %w", boardID, err)
+	}
 
 	// Anchoring: Ensure JQL is scoped to the project
 	if !strings.Contains(strings.ToLower(jql), "project =") && !strings.Contains(strings.ToLower(jql), "project in") {
-		jql = fmt.Sprintf("(%s) AND project = \"%s\"", jql, finalProjectKey)
+		jql = jira.AndJQL(jql, jira.FieldEquals("project", finalProjectKey))
 	}
 
-	// Exclude sub-tasks
-	jql = fmt.Sprintf("(%s) AND issuetype not in subtaskIssueTypes()", jql)
+	// Exclude sub-tasks unless they are ingested
+	if !s.ingestsSubtasks() {
+		jql = jira.AndJQL(jql, jira.NotSubTask)
+	}
 
 	return &jira.SourceContext{
 		ProjectKey: finalProjectKey,
@@ -161,30 +172,41 @@ func (s *Server) resolveSourceContext(projectKey string, boardID int) (*jira.Sou
 	}, nil
 }
 
-func stripOrderBy(jql string) string {
-	jqlLower := strings.ToLower(jql)
-	if idx := strings.Index(jqlLower, " order by"); idx != -1 {
-		return jql[:idx]
+// formatResult renders a tool result in the format of the call,
+// falling back to JSON if the result cannot be rendered. In Markdown, Mermaid
+// diagrams follow the tables as fenced blocks instead of table cells.
+func (s *Server) formatResult(ctx context.Context, data any) string {
+	format := s.resultFormat(ctx)
+	var diagrams []string
+	if envelope, ok := data.(ResponseEnvelope); ok && format == render.Markdown {
+		diagrams, envelope.Visuals = envelope.Visuals, nil
+		data = envelope
+	}
+	out, err := render.Render(data, format)
+	if err != nil {
+		log.Warn().Err(err).Str("format", string(format)).Msg("Failed to render tool result; falling back to JSON")
+		out, _ = render.Render(data, render.JSON)
 	}
-	return jql
-}
-
-func (s *Server) formatResult(data any) string {
-	out, _ := json.MarshalIndent(data, "", "  ")
-	return string(out)
+	for _, d := range diagrams {
+		out += "\n```mermaid\n" + d + "```\n"
+	}
+	return out
 }
 
 // ResponseEnvelope represents the standardized JSON structure for all MCP tool returns.
+// It is also the output schema of every tool (see outputSchemaFor), so the
+// jsonschema tags describe the envelope to clients reading structuredContent.
 type ResponseEnvelope struct {
-	Context     map[string]any      `json:"context,omitempty"`
-	Data        any                 `json:"data"`
-	Diagnostics map[string]any      `json:"diagnostics,omitempty"`
-	Guardrails  *ResponseGuardrails `json:"guardrails,omitempty"`
+	Context     map[string]any      `json:"context,omitempty" jsonschema:"Scope of the result: project, board, session analysis window, chart URL."`
+	Data        any                 `json:"data" jsonschema:"The tool-specific result."`
+	Diagnostics map[string]any      `json:"diagnostics,omitempty" jsonschema:"Data-quality and computation details."`
+	Guardrails  *ResponseGuardrails `json:"guardrails,omitempty" jsonschema:"Interpretation guidance to relay to the user."`
+	Visuals     []string            `json:"visuals,omitempty" jsonschema:"Mermaid diagrams of the result, when enabled via set_visual_preferences."`
 }
 
 type ResponseGuardrails struct {
-	Insights []string `json:"insights"`
-	Warnings []string `json:"warnings"`
+	Insights []string `json:"insights" jsonschema:"Observations to surface."`
+	Warnings []string `json:"warnings" jsonschema:"Caveats that limit how the result may be used."`
 }
 
 // WrapResponse constructs standard ResponseEnvelope for tools.
@@ -218,8 +240,8 @@ func WrapResponse(data any, proj string, board int, diagnostics map[string]any,
 // windowingGuidance returns the standard agent-facing guidance line for any
 // diagnostic that consumes the session analysis window. Use in handler
 // guidance arrays so the hint stays consistent across tools.
-func (s *Server) windowingGuidance() string {
-	start, end, _ := s.Window()
+func (s *Server) windowingGuidance(ctx context.Context) string {
+	start, end, _ := s.Window(ctx)
 	return fmt.Sprintf("This analysis uses the session analysis window (%s … %s). Adjust via 'set_analysis_window' or read it via 'get_analysis_window'.",
 		start.Format(stats.DateFormat), end.Format(stats.DateFormat))
 }
@@ -229,25 +251,36 @@ func (s *Server) windowingGuidance() string {
 // Forecasting tools and tools that use only a single field of the window
 // (work_item_age, process_evolution) still report the full window — the agent
 // reads the per-tool schema to know which field is honoured.
-func (s *Server) injectSessionContext(data any) any {
+func (s *Server) injectSessionContext(ctx context.Context, data any) any {
 	envelope, ok := data.(ResponseEnvelope)
 	if !ok {
 		return data
 	}
-	start, end, explicit := s.Window()
+	start, end, explicit := s.Window(ctx)
 	source := "default"
-	if explicit {
+	if callStateOf(ctx).window != nil {
+		source = "call"
+	} else if explicit {
 		source = "session"
 	}
 	if envelope.Context == nil {
 		envelope.Context = map[string]any{}
 	}
+	if name := s.namedInstance(); name != "" {
+		envelope.Context["instance"] = name
+	}
 	envelope.Context["session_window"] = map[string]any{
 		"start":         start.Format(stats.DateFormat),
 		"end":           end.Format(stats.DateFormat),
 		"duration_days": stats.CalendarDaysBetween(start, end),
 		"source":        source,
 	}
+	if asOf := callAsOfDate(ctx); asOf != nil {
+		envelope.Context["as_of_date"] = asOf.Format(stats.DateFormat)
+	}
+	if scope := s.outcomes(ctx); scope != stats.OutcomesDelivered {
+		envelope.Context["outcome_scope"] = scope
+	}
 	return envelope
 }
 
@@ -283,6 +316,30 @@ func (s *Server) injectChartURL(toolName string, data any) any {
 	return envelope
 }
 
+// injectVisuals adds the Mermaid diagrams of a tool result to its envelope
+// when the session enabled them via set_visual_preferences.
+func (s *Server) injectVisuals(toolName string, data any) any {
+	if !s.enableMermaidCharts || !visuals.HasGenerator(toolName) {
+		return data
+	}
+	envelope, ok := data.(ResponseEnvelope)
+	if !ok {
+		return data
+	}
+	envelopeJSON, err := json.Marshal(envelope)
+	if err != nil {
+		log.Warn().Err(err).Str("tool", toolName).Msg("Failed to serialize envelope for Mermaid diagrams")
+		return data
+	}
+	diagrams, err := visuals.Mermaid(toolName, envelopeJSON)
+	if err != nil {
+		log.Warn().Err(err).Str("tool", toolName).Msg("Failed to generate Mermaid diagrams")
+		return data
+	}
+	envelope.Visuals = diagrams
+	return envelope
+}
+
 func (s *Server) getResolutionMap(sourceID string) map[string]string {
 	if s.activeSourceID == sourceID && len(s.activeResolutions) > 0 {
 		return s.activeResolutions
@@ -361,9 +418,9 @@ func (s *Server) detectMappingStaleness(events []eventlog.IssueEvent) string {
 	return ""
 }
 
-func (s *Server) calculateWIPAges(issues []jira.Issue, startStatus string, statusWeights map[string]int, mappings map[string]stats.StatusMetadata, cycleTimes []float64) map[string][]float64 {
+func (s *Server) calculateWIPAges(ctx context.Context, issues []jira.Issue, commitments stats.CommitmentPoints, statusWeights map[string]int, mappings map[string]stats.StatusMetadata, cycleTimes []float64) map[string][]float64 {
 	ages := make(map[string][]float64)
-	results := stats.CalculateInventoryAge(issues, startStatus, statusWeights, mappings, cycleTimes, "wip", s.commitmentBackflowReset, s.Clock())
+	results := stats.CalculateInventoryAgeByType(issues, commitments, statusWeights, mappings, cycleTimes, "wip", s.commitmentBackflowReset, s.Clock(ctx))
 	for _, res := range results {
 		if res.AgeSinceCommitment != nil {
 			t := res.Type
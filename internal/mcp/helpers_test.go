@@ -1,10 +1,16 @@
 package mcp
 
 import (
+	"context"
 	"errors"
+	"strings"
 	"testing"
 
+	"mcs-mcp/internal/config"
 	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/render"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type mockJiraClient struct {
@@ -15,28 +21,28 @@ type mockJiraClient struct {
 	getProjectStatuses func(key string) (any, error)
 }
 
-func (m *mockJiraClient) GetBoard(id int) (any, error) {
+func (m *mockJiraClient) GetBoard(_ context.Context, id int) (any, error) {
 	if m.getBoard != nil {
 		return m.getBoard(id)
 	}
 	return nil, nil
 }
 
-func (m *mockJiraClient) GetBoardConfig(id int) (any, error) {
+func (m *mockJiraClient) GetBoardConfig(_ context.Context, id int) (any, error) {
 	if m.getBoardConfig != nil {
 		return m.getBoardConfig(id)
 	}
 	return nil, nil
 }
 
-func (m *mockJiraClient) GetFilter(id string) (any, error) {
+func (m *mockJiraClient) GetFilter(_ context.Context, id string) (any, error) {
 	if m.getFilter != nil {
 		return m.getFilter(id)
 	}
 	return nil, nil
 }
 
-func (m *mockJiraClient) GetProjectStatuses(key string) (any, error) {
+func (m *mockJiraClient) GetProjectStatuses(_ context.Context, key string) (any, error) {
 	if m.getProjectStatuses != nil {
 		return m.getProjectStatuses(key)
 	}
@@ -44,6 +50,7 @@ func (m *mockJiraClient) GetProjectStatuses(key string) (any, error) {
 }
 
 func TestResolveSourceContext_SafeAssertions(t *testing.T) {
+	ctx := context.Background()
 	s := &Server{
 		jira: &mockJiraClient{
 			getBoard: func(id int) (any, error) {
@@ -53,7 +60,7 @@ func TestResolveSourceContext_SafeAssertions(t *testing.T) {
 		},
 	}
 
-	_, err := s.resolveSourceContext("123", 456)
+	_, err := s.resolveSourceContext(ctx, "123", 456)
 	if err == nil {
 		t.Error("expected error for malformed board response, got nil")
 	}
@@ -78,15 +85,15 @@ func TestResolveSourceContext_SafeAssertions(t *testing.T) {
 		return map[string]any{"jql": "project = PROJ"}, nil
 	}
 
-	ctx, err := s.resolveSourceContext("PROJ", 123)
+	sc, err := s.resolveSourceContext(ctx, "PROJ", 123)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if ctx.JQL != "(project = PROJ) AND issuetype not in subtaskIssueTypes()" {
-		t.Errorf("unexpected JQL: %s", ctx.JQL)
+	if sc.JQL != "(project = PROJ) AND issuetype not in subtaskIssueTypes()" {
+		t.Errorf("unexpected JQL: %s", sc.JQL)
 	}
-	if ctx.ProjectKey != "PROJ" {
-		t.Errorf("unexpected project key: %s", ctx.ProjectKey)
+	if sc.ProjectKey != "PROJ" {
+		t.Errorf("unexpected project key: %s", sc.ProjectKey)
 	}
 
 	s.jira.(*mockJiraClient).getBoard = func(id int) (any, error) {
@@ -95,7 +102,7 @@ func TestResolveSourceContext_SafeAssertions(t *testing.T) {
 	s.jira.(*mockJiraClient).getBoardConfig = func(id int) (any, error) {
 		return nil, errors.New("config error")
 	}
-	_, err = s.resolveSourceContext("123", 123)
+	_, err = s.resolveSourceContext(ctx, "123", 123)
 	if err == nil {
 		t.Error("expected error when filter missing in metadata and config retrieval fails, got nil")
 	}
@@ -109,7 +116,7 @@ func TestResolveSourceContext_SafeAssertions(t *testing.T) {
 		// Filter response not a map
 		return "not a map", nil
 	}
-	_, err = s.resolveSourceContext("123", 123)
+	_, err = s.resolveSourceContext(ctx, "123", 123)
 	if err == nil {
 		t.Error("expected error for malformed filter response, got nil")
 	}
@@ -124,12 +131,45 @@ func TestResolveSourceContext_SafeAssertions(t *testing.T) {
 		return map[string]any{"jql": "project = PROJ ORDER BY created DESC"}, nil
 	}
 
-	ctx, err = s.resolveSourceContext("PROJ", 123)
+	sc, err = s.resolveSourceContext(ctx, "PROJ", 123)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	expectedJQL := "(project = PROJ) AND issuetype not in subtaskIssueTypes()"
-	if ctx.JQL != expectedJQL {
-		t.Errorf("expected JQL %q, got %q", expectedJQL, ctx.JQL)
+	if sc.JQL != expectedJQL {
+		t.Errorf("expected JQL %q, got %q", expectedJQL, sc.JQL)
+	}
+}
+
+func TestSetVisualPreferences(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	flowDebt := map[string]any{"flow_debt": map[string]any{"buckets": []map[string]any{{"label": "2026-W01", "arrivals": 3, "departures": 1}}}}
+	text := func() string {
+		res, _, _ := handleResult(ctx, s, "analyze_flow_debt", WrapResponse(flowDebt, "PROJ", 1, nil, nil, nil), nil)
+		return res.Content[0].(*mcp.TextContent).Text
+	}
+
+	if strings.Contains(text(), "visuals") {
+		t.Errorf("Expected no Mermaid diagrams by default")
+	}
+	if _, err := s.handleSetVisualPreferences(true); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(text(), `"visuals": [`) || !strings.Contains(text(), "xychart-beta") {
+		t.Errorf("Expected a Mermaid diagram once enabled, got %s", text())
+	}
+
+	s.outputFormat = render.Markdown
+	if !strings.Contains(text(), "```mermaid\nxychart-beta\n") {
+		t.Errorf("Expected a fenced Mermaid block in Markdown, got %s", text())
+	}
+
+	s.outputFormat = ""
+	if _, err := s.handleSetVisualPreferences(false); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(text(), "visuals") {
+		t.Errorf("Expected no Mermaid diagrams once disabled")
 	}
 }
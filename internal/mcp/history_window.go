@@ -0,0 +1,97 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// historyWindowed is implemented by tool inputs that embed HistoryWindow.
+type historyWindowed interface {
+	historyWindow() HistoryWindow
+}
+
+func (h HistoryWindow) historyWindow() HistoryWindow { return h }
+
+func (h HistoryWindow) isSet() bool {
+	return h.HistoryWindowDays != 0 || h.HistoryStartDate != "" || h.HistoryEndDate != ""
+}
+
+// windowOverride is a parsed HistoryWindow. It is applied to the session
+// window lazily, in Window, because anchoring a board during the call can
+// change the session window and the evaluation date it defaults to.
+type windowOverride struct {
+	start, end *time.Time
+	days       int
+}
+
+// parse validates the history window parameters of a call.
+func (h HistoryWindow) parse() (*windowOverride, error) {
+	o := &windowOverride{days: h.HistoryWindowDays}
+	if o.days < 0 {
+		return nil, fmt.Errorf("history_window_days must be positive")
+	}
+	if h.HistoryStartDate != "" && o.days > 0 {
+		return nil, fmt.Errorf("history_start_date and history_window_days are mutually exclusive")
+	}
+	for _, p := range []struct {
+		name, value string
+		dst         **time.Time
+	}{{"history_start_date", h.HistoryStartDate, &o.start}, {"history_end_date", h.HistoryEndDate, &o.end}} {
+		if p.value == "" {
+			continue
+		}
+		t, err := time.Parse(stats.DateFormat, p.value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s format: %w", p.name, err)
+		}
+		*p.dst = &t
+	}
+	if o.start != nil && o.end != nil && !o.start.Before(*o.end) {
+		return nil, fmt.Errorf("history_start_date must be strictly before history_end_date")
+	}
+	return o, nil
+}
+
+// apply narrows the session window [start, end]: the end date replaces the
+// session end, and the start is the start date, else end minus the days, else
+// the start that keeps the length of the session window.
+func (o *windowOverride) apply(start, end time.Time) (time.Time, time.Time) {
+	length := end.Sub(start)
+	if o.end != nil {
+		end = *o.end
+	}
+	switch {
+	case o.start != nil:
+		start = *o.start
+	case o.days > 0:
+		start = end.AddDate(0, 0, -o.days)
+	default:
+		start = end.Add(-length)
+	}
+	return start, end
+}
+
+// withHistoryWindow validates the history window parameters of a tool input
+// and makes them narrow the session window for the duration of the call.
+func withHistoryWindow[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		hw, ok := any(args).(historyWindowed)
+		if !ok || !hw.historyWindow().isSet() {
+			return handler(ctx, req, args)
+		}
+		o, err := hw.historyWindow().parse()
+		if err != nil {
+			return formatToolError(err), nil, nil
+		}
+		sessionStart, sessionEnd, _ := s.sessionWindow(ctx)
+		if start, end := o.apply(sessionStart, sessionEnd); !start.Before(end) {
+			return formatToolError(fmt.Errorf("history window %s … %s is empty: history_start_date must be before the window end", start.Format(stats.DateFormat), end.Format(stats.DateFormat))), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.window = o }), req, args)
+	}
+}
@@ -0,0 +1,67 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/config"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWithHistoryWindow(t *testing.T) {
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	eval := time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC)
+	s.activeEvaluationDate = &eval
+
+	type window struct {
+		start, end string
+		source     string
+	}
+	var seen window
+	handler := withHistoryWindow(s, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
+		start, end, _ := s.Window(ctx)
+		seen = window{start.Format("2006-01-02"), end.Format("2006-01-02"), ""}
+		envCtx := s.injectSessionContext(ctx, WrapResponse(nil, "", 0, nil, nil, nil)).(ResponseEnvelope).Context
+		seen.source = envCtx["session_window"].(map[string]any)["source"].(string)
+		return formatToolResult(ctx, s, nil), nil, nil
+	})
+
+	cases := []struct {
+		name string
+		in   HistoryWindow
+		want window
+	}{
+		{"session default", HistoryWindow{}, window{"2025-09-30", "2026-03-31", "default"}},
+		{"last N days", HistoryWindow{HistoryWindowDays: 30}, window{"2026-03-01", "2026-03-31", "call"}},
+		{"explicit range", HistoryWindow{HistoryStartDate: "2026-01-01", HistoryEndDate: "2026-02-01"}, window{"2026-01-01", "2026-02-01", "call"}},
+		{"end keeps the session length", HistoryWindow{HistoryEndDate: "2026-01-31"}, window{"2025-08-02", "2026-01-31", "call"}},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			res, _, _ := handler(context.Background(), nil, AnalyzeYieldInput{HistoryWindow: tc.in})
+			if res.IsError {
+				t.Fatalf("unexpected error: %v", res.Content[0].(*mcp.TextContent).Text)
+			}
+			if seen != tc.want {
+				t.Errorf("got %+v, want %+v", seen, tc.want)
+			}
+		})
+	}
+	if start, _, _ := s.Window(context.Background()); start.Format("2006-01-02") != "2025-09-30" {
+		t.Errorf("Expected the call window to stay with the call, got a session window from %s", start.Format("2006-01-02"))
+	}
+
+	for _, bad := range []HistoryWindow{
+		{HistoryWindowDays: -5},
+		{HistoryWindowDays: 30, HistoryStartDate: "2026-01-01"},
+		{HistoryStartDate: "01/01/2026"},
+		{HistoryStartDate: "2026-02-01", HistoryEndDate: "2026-01-01"},
+		{HistoryStartDate: "2026-06-01"},
+	} {
+		if res, _, _ := handler(context.Background(), nil, AnalyzeYieldInput{HistoryWindow: bad}); !res.IsError {
+			t.Errorf("Expected an error for %+v", bad)
+		}
+	}
+}
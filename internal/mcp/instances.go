@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// jiraInstance is one configured Jira connection together with the event
+// cache of its boards. Named instances cache in a subdirectory of the cache
+// directory, so equal project keys and board IDs on two instances never share
+// event logs or workflow metadata.
+type jiraInstance struct {
+	client   jira.Client
+	cacheDir string
+	events   *eventlog.LogProvider
+}
+
+// addInstance registers a Jira connection under name.
+func (s *Server) addInstance(cfg *config.AppConfig, name string, client jira.Client, cacheDir string) *jiraInstance {
+	inst := &jiraInstance{
+		client:   client,
+		cacheDir: cacheDir,
+		events: eventlog.NewLogProvider(client, eventlog.NewEventStore(s.sessionClock), cacheDir,
+			cfg.IngestionUpdatedLookback, cfg.IngestionCreatedLookback, cfg.IngestionMaxItems,
+			time.Duration(cfg.IngestionSyncInterval)*time.Minute),
+	}
+	inst.events.SetProgressFunc(reportIngestionProgress)
+	inst.events.SetStreaming(cfg.IngestionStreaming)
+	s.instances[name] = inst
+	return inst
+}
+
+// addNamedInstances registers the connections of JIRA_INSTANCES.
+func (s *Server) addNamedInstances(cfg *config.AppConfig) {
+	for name, jc := range cfg.JiraInstances {
+		cacheDir := ""
+		if cfg.CacheDir != "" {
+			cacheDir = filepath.Join(cfg.CacheDir, name)
+			if err := os.MkdirAll(cacheDir, 0755); err != nil {
+				log.Warn().Err(err).Str("path", cacheDir).Msg("Failed to create instance cache directory")
+			}
+		}
+		s.addInstance(cfg, name, jira.NewClient(jc), cacheDir)
+	}
+}
+
+// instanceNames returns the configured instances, the default one first.
+func (s *Server) instanceNames() []string {
+	names := make([]string, 0, len(s.instances))
+	for name := range s.instances {
+		if name != config.DefaultInstance {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return append([]string{config.DefaultInstance}, names...)
+}
+
+// namedInstance returns the active named Jira instance, or "" for the default one.
+func (s *Server) namedInstance() string {
+	if s.activeInstance == config.DefaultInstance {
+		return ""
+	}
+	return s.activeInstance
+}
+
+// useInstance makes a Jira instance the target of the following tool calls.
+// Switching drops the anchored context, which belongs to the previous instance.
+func (s *Server) useInstance(name string) error {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		name = config.DefaultInstance
+	}
+	inst, ok := s.instances[name]
+	if !ok {
+		return fmt.Errorf("unknown Jira instance %q; configured instances: %s", name, strings.Join(s.instanceNames(), ", "))
+	}
+	if name == s.activeInstance {
+		return nil
+	}
+
+	log.Info().Str("prev", s.activeInstance).Str("next", name).Msg("Switching Jira instance")
+	s.events.PruneExcept("")
+	s.clearActiveContext()
+	s.activeInstance = name
+	s.jira, s.cacheDir, s.events = inst.client, inst.cacheDir, inst.events
+	return nil
+}
+
+// instanceSelected is implemented by tool inputs that embed JiraInstance.
+type instanceSelected interface {
+	jiraInstance() string
+}
+
+func (i JiraInstance) jiraInstance() string { return i.Instance }
+
+// withJiraInstance switches to the Jira instance named by a tool input before
+// the call. The instance stays active for the following calls.
+func withJiraInstance[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		if is, ok := any(args).(instanceSelected); ok && is.jiraInstance() != "" {
+			if err := s.useInstance(is.jiraInstance()); err != nil {
+				return formatToolError(err), nil, nil
+			}
+		}
+		return handler(ctx, req, args)
+	}
+}
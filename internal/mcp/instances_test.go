@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/jira"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestJiraInstances(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	cfg := &config.AppConfig{CacheDir: cacheDir, JiraInstances: map[string]jira.Config{"dc": {BaseURL: "https://jira.example.com"}}}
+	cloud, dc := &mockJiraClient{}, &mockJiraClient{}
+	s := NewServer(cfg, cloud)
+	s.addInstance(cfg, "dc", dc, filepath.Join(cacheDir, "dc"))
+
+	if got := s.instanceNames(); len(got) != 2 || got[0] != config.DefaultInstance || got[1] != "dc" {
+		t.Fatalf("Expected the default instance first, got %v", got)
+	}
+	if err := s.useInstance("nope"); err == nil || !strings.Contains(err.Error(), "default, dc") {
+		t.Errorf("Expected an unknown instance to list the configured ones, got %v", err)
+	}
+
+	// The instance parameter switches client, cache directory, and context.
+	if err := s.anchorContext("PROJ", 1); err != nil {
+		t.Fatal(err)
+	}
+	var seen jira.Client
+	handler := withJiraInstance(s, func(_ context.Context, _ *mcp.CallToolRequest, _ ImportProjectsInput) (*mcp.CallToolResult, any, error) {
+		seen = s.jira
+		return nil, nil, nil
+	})
+	if res, _, _ := handler(context.Background(), nil, ImportProjectsInput{JiraInstance: JiraInstance{Instance: "DC"}}); res != nil {
+		t.Fatalf("Expected the call to reach the handler, got %+v", res)
+	}
+	if seen != dc || s.cacheDir != filepath.Join(cacheDir, "dc") || s.activeSourceID != "" {
+		t.Errorf("Expected the dc client and cache and no anchored board, got cache %s and source %q", s.cacheDir, s.activeSourceID)
+	}
+	// Without the parameter the active instance stays.
+	seen = nil
+	handler(context.Background(), nil, ImportProjectsInput{})
+	if seen != dc {
+		t.Errorf("Expected the dc instance to stay active")
+	}
+	if res, _, _ := handler(context.Background(), nil, ImportProjectsInput{JiraInstance: JiraInstance{Instance: "nope"}}); res == nil || !res.IsError {
+		t.Errorf("Expected an error result for an unknown instance")
+	}
+
+	envelope := s.injectSessionContext(ctx, WrapResponse(nil, "PROJ", 1, nil, nil, nil)).(ResponseEnvelope)
+	if envelope.Context["instance"] != "dc" {
+		t.Errorf("Expected responses to name the instance, got %v", envelope.Context)
+	}
+
+	// The active context is recorded with its instance and restored after a restart.
+	if err := s.anchorContext("PROJ", 1); err != nil {
+		t.Fatal(err)
+	}
+	restarted := NewServer(cfg, cloud)
+	restarted.addInstance(cfg, "dc", dc, filepath.Join(cacheDir, "dc"))
+	restarted.RestoreActiveContext()
+	if restarted.activeInstance != "dc" || restarted.activeSourceID != "PROJ_1" || restarted.jira != dc {
+		t.Errorf("Expected PROJ_1 on dc to be restored, got %q on %q", restarted.activeSourceID, restarted.activeInstance)
+	}
+}
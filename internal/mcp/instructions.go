@@ -7,7 +7,8 @@ package mcp
 const serverInstructions = `MCS-MCP is a Flow Metrics and Monte-Carlo Simulation server for Jira (Cycle Time, Throughput, WIP, Process Stability, probabilistic forecasts).
 
 OPERATIONAL FLOW — follow in order, do not skip:
-  1. import_projects / import_boards      — identify the target.
+  1. import_projects / import_boards      — identify the target. With several Jira instances (JIRA_INSTANCES),
+                                             pass 'instance' to the import_* tools; it stays active for later calls.
   2. import_board_context                  — eager-fetch history, anchor project context.
   3. workflow_discover_mapping             — propose tier mapping (Demand / Upstream / Downstream / Finished).
                                              YOU MUST present the proposed mapping to the user and obtain explicit confirmation
@@ -25,16 +26,48 @@ STRICT GUARDRAILS (anti-hallucination):
 TOOL SELECTION:
   - Predictability of Cycle Time        → analyze_process_stability (short term) or analyze_process_evolution (long term)
   - Delivery volume / cadence           → analyze_throughput
-  - Per-item duration / SLE             → analyze_cycle_time
+  - Delivery split by product / epic    → analyze_throughput_streams
+  - Bugs crowding out features          → analyze_interference
+  - Per-item duration / SLE             → analyze_cycle_time (by_type=true for every type's SLE at once; analyze_cycle_time_scatter for item-level points)
+  - Slowest items / what to drill into  → find_outliers (by cycle_time, status_time, or blocked_time), then analyze_item_journey
+  - SLE compliance / items set to breach → set_sle (once), then analyze_sle_compliance
   - Active WIP health                   → analyze_wip_stability, analyze_wip_age_stability, analyze_work_item_age
-  - Bottlenecks / queueing              → analyze_status_persistence, analyze_residence_time
+  - WIP limit violations                → set_wip_limits (once), then analyze_wip_limits
+  - Early warnings on thresholds        → set_alert_rules (once), then evaluate_alerts
+  - Bottlenecks / queueing              → analyze_status_persistence (trend_bucket for better/worse over time), analyze_residence_time
+  - Active vs. waiting time             → analyze_flow_efficiency
+  - Rework / work sent back             → analyze_rework
+  - Where / how late work is abandoned  → analyze_abandonment (analyze_yield for the rates alone)
   - Probabilistic forecast              → forecast_monte_carlo (requires a stable process)
+  - What-if comparison of forecasts     → forecast_scenarios
+  - Epic / initiative completion        → forecast_epic
+  - When one in-flight item will ship   → forecast_item
+  - Week-by-week delivery cone          → forecast_burnup
+  - Will the sprint commitment make it  → forecast_timebox
+  - Can the backlog make a target date  → forecast_monte_carlo mode=duration target_date=<date>
+  - What a forecast samples from        → analyze_throughput_histogram
+  - Team delivers in weekly batches     → analyze_throughput (batching), then forecast_monte_carlo sampling_granularity=week
+  - How a forecast moved over time      → compare_forecasts (after two or more forecast_monte_carlo runs)
+  - Are we keeping our commitments      → record_commitment (once per commitment), then analyze_commitment_health
+  - Why a forecast says what it says    → forecast_monte_carlo explain=true
+  - Several boards / program level      → import_portfolio, then 'sources' on forecast_monte_carlo, analyze_throughput, analyze_work_item_age
   - Backtesting accuracy                → forecast_backtest
+  - Release (fixVersion) progress      → analyze_release_burnup; add fix_version to other tools to scope them to a release
+  - Sub-team on a shared board          → add labels and/or components to any analyze_* tool or forecast_monte_carlo
+  - Done → in production lag            → analyze_release_lag (forecast_monte_carlo to_release=true for dates)
+  - Sprint commitment / carry-over      → analyze_sprint_history (forecast_monte_carlo sprint_mode=true to forecast in sprints)
+  - Mapping fit per issue type          → analyze_definition_of_workflow
+  - Duplicate issue types (User Story = Story) → workflow_set_type_aliases
+  - Same workflow on many boards         → workflow_export_mapping on a confirmed board, then workflow_import_mapping on the others
+  - Who/when changed the configuration  → workflow_get_audit
+  - Stop repeating parameters per call  → set_source_settings (get_source_settings to review)
+  - Recurring forecast of the same scope → save_forecast_template, then forecast_monte_carlo template=<name>
   Prefer the per-tool description for detailed WHEN TO USE / WHEN NOT TO USE rules.
 
 CHART RENDERING:
   Analytical responses carry a chart_url. Call open_in_browser with that URL to show the interactive chart.
-  Tool JSON responses no longer include any embedded chart markup — render via chart_url only.
+  Tool JSON responses include no chart markup unless the session enables it: set_visual_preferences(mermaid_charts: true)
+  adds server-generated Mermaid diagrams in 'visuals'. Show those unchanged in mermaid blocks; never write your own.
 
 DATA INTEGRITY:
   Series are time-ordered; do not reorder results. Cycle Time, Moving Range, and WIP Age are sensitive to ordering and
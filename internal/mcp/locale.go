@@ -0,0 +1,80 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+
+	"mcs-mcp/internal/i18n"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// clientLocaleMetaKey is the initialize _meta field in which a client
+// announces the language of its user, e.g. {"_meta": {"locale": "de-DE"}}.
+const clientLocaleMetaKey = "locale"
+
+// adoptClientLocale switches the result language to the locale the client
+// announced at initialize. Clients that announce none keep MCS_LOCALE, and an
+// unsupported locale is logged and ignored.
+func (s *Server) adoptClientLocale(_ context.Context, req *mcp.InitializedRequest) {
+	params := req.Session.InitializeParams()
+	if params == nil {
+		return
+	}
+	raw, ok := params.Meta[clientLocaleMetaKey].(string)
+	if !ok || raw == "" {
+		return
+	}
+	locale, err := i18n.ParseLocale(raw)
+	if err != nil {
+		log.Warn().Err(err).Msg("Ignoring the client locale")
+		return
+	}
+	s.localeMu.Lock()
+	defer s.localeMu.Unlock()
+	s.locale = locale
+}
+
+// resultLocale returns the language of tool results.
+func (s *Server) resultLocale() i18n.Locale {
+	s.localeMu.Lock()
+	defer s.localeMu.Unlock()
+	return s.locale
+}
+
+// localizeResult translates the guidance, insights, warnings, and labels of a
+// tool result into the result locale. Analytics produce English throughout;
+// this is the one place they are translated, so the text block,
+// structuredContent, charts, and result resources agree.
+func (s *Server) localizeResult(data any) any {
+	locale := s.resultLocale()
+	if locale == "" || locale == i18n.English {
+		return data
+	}
+	envelope, ok := data.(ResponseEnvelope)
+	if !ok {
+		return data
+	}
+
+	if envelope.Data != nil {
+		raw, err := json.Marshal(envelope.Data)
+		if err != nil {
+			log.Warn().Err(err).Msg("Failed to localize tool result; keeping English")
+			return data
+		}
+		envelope.Data = json.RawMessage(i18n.TranslateJSON(locale, raw))
+	}
+	if g := envelope.Guardrails; g != nil {
+		envelope.Guardrails = &ResponseGuardrails{Insights: localizeTexts(locale, g.Insights), Warnings: localizeTexts(locale, g.Warnings)}
+	}
+	return envelope
+}
+
+func localizeTexts(locale i18n.Locale, texts []string) []string {
+	out := make([]string, len(texts))
+	for i, t := range texts {
+		out[i] = i18n.Translate(locale, t)
+	}
+	return out
+}
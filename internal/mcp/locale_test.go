@@ -0,0 +1,34 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/i18n"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestLocalizeResult(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir(), Locale: i18n.German}, &mockJiraClient{})
+	envelope := WrapResponse(map[string]any{"percentile_labels": map[string]string{"probable": "P70 (Probable)"}}, "PROJ", 0, nil,
+		[]string{"Simulation based on a small sample size (12 items); results may have limited statistical significance."},
+		selectGuidance("analyze_status_persistence", guidanceFacts{}))
+
+	res, _, _ := handleResult(ctx, s, "forecast_monte_carlo", envelope, nil)
+	out := res.Content[0].(*mcp.TextContent).Text
+	for _, want := range []string{"P70 (Wahrscheinlich)", "kleinen Stichprobe (12 Elemente)", "AUSSCHLIESSLICH"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Expected %q in the German result, got:\n%s", want, out)
+		}
+	}
+
+	s.locale = i18n.English
+	res, _, _ = handleResult(ctx, s, "forecast_monte_carlo", envelope, nil)
+	if !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "P70 (Probable)") {
+		t.Error("Expected English results to be left as they are")
+	}
+}
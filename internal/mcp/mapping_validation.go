@@ -0,0 +1,207 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+
+	"github.com/rs/zerolog/log"
+)
+
+// refreshStatusRegistry adds the current statuses of a project and the
+// statuses in the loaded history of a source to the active registry, and
+// returns the statuses in that history (ID, or name when Jira sent no ID).
+// The history is not hydrated here; workflow_discover_mapping, which precedes
+// workflow_set_mapping, has loaded it. Issues moved in
+// from other projects can carry statuses outside the project's own scheme,
+// which is why the history counts as well. MCSTEST asks no Jira.
+func (s *Server) refreshStatusRegistry(ctx context.Context, projectKey, sourceID string) []string {
+	known := make(map[string]string)
+	if s.activeRegistry != nil {
+		maps.Copy(known, s.activeRegistry.Statuses)
+	}
+	if projectKey != "MCSTEST" {
+		reg, err := s.jira.GetRegistry(ctx, projectKey)
+		if err != nil {
+			log.Warn().Err(err).Str("project", projectKey).Msg("Failed to fetch project statuses, validating against the known ones")
+		} else if reg != nil {
+			maps.Copy(known, reg.Statuses)
+		}
+	}
+
+	var history []string
+	for _, e := range s.events.GetIssuesInRange(sourceID, time.Time{}, s.Clock(ctx)) {
+		id := stats.PreferID(e.ToStatusID, e.ToStatus)
+		if id == "" || slices.Contains(history, id) {
+			continue
+		}
+		history = append(history, id)
+		if _, ok := known[id]; !ok && e.ToStatusID != "" && e.ToStatus != "" {
+			known[id] = e.ToStatus
+		}
+	}
+
+	reg := &jira.NameRegistry{Statuses: known}
+	if s.activeRegistry != nil {
+		reg.Resolutions = s.activeRegistry.Resolutions
+	}
+	s.activeRegistry = reg
+	return history
+}
+
+// validateMappingStatuses rejects status references (mapping keys and
+// commitment points) that are neither a known status ID nor a known status
+// name, suggesting the closest known names. Nothing is rejected while no
+// status is known, e.g. before the first ingestion of a board without access
+// to the project's statuses.
+func (s *Server) validateMappingStatuses(sourceID string, history []string, mapping map[string]any, commitmentPoint string, typeCommitmentPoints map[string]string) error {
+	known := s.activeRegistry.Statuses
+	if len(known) == 0 && len(history) == 0 {
+		return nil
+	}
+	names := make([]string, 0, len(known)+len(history))
+	for _, name := range known {
+		names = append(names, name)
+	}
+	for _, id := range history {
+		if _, ok := known[id]; !ok {
+			names = append(names, id) // Name-only history entries
+		}
+	}
+	slices.Sort(names)
+	names = slices.Compact(names)
+
+	isKnown := func(ref string) bool {
+		if _, ok := known[ref]; ok || slices.Contains(history, ref) {
+			return true
+		}
+		return slices.ContainsFunc(names, func(n string) bool { return strings.EqualFold(n, ref) })
+	}
+
+	refs := slices.Sorted(maps.Keys(mapping))
+	if commitmentPoint != "" {
+		refs = append(refs, commitmentPoint)
+	}
+	for _, issueType := range slices.Sorted(maps.Keys(typeCommitmentPoints)) {
+		if cp := typeCommitmentPoints[issueType]; cp != "" {
+			refs = append(refs, cp)
+		}
+	}
+
+	var unknown []string
+	for _, ref := range refs {
+		if isKnown(ref) {
+			continue
+		}
+		entry := fmt.Sprintf("'%s'", ref)
+		if suggestions := suggestStatuses(ref, names); len(suggestions) > 0 {
+			entry += fmt.Sprintf(" (did you mean '%s'?)", strings.Join(suggestions, "', '"))
+		}
+		if !slices.Contains(unknown, entry) {
+			unknown = append(unknown, entry)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("mapping rejected: %d status(es) do not exist on %s: %s. Use the status names or IDs returned by 'workflow_discover_mapping', or 'column:<name>' for a board column", len(unknown), sourceID, strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// suggestStatuses returns up to 3 known status names close to ref: names
+// containing it (or contained in it) and names within a few typos.
+func suggestStatuses(ref string, names []string) []string {
+	type candidate struct {
+		name     string
+		distance int
+	}
+	needle := strings.ToLower(ref)
+	var candidates []candidate
+	for _, name := range names {
+		hay := strings.ToLower(name)
+		d := editDistance(needle, hay)
+		if d <= max(len(needle)/3, 2) || strings.Contains(hay, needle) || strings.Contains(needle, hay) {
+			candidates = append(candidates, candidate{name, d})
+		}
+	}
+	slices.SortStableFunc(candidates, func(a, b candidate) int { return a.distance - b.distance })
+	var out []string
+	for _, c := range candidates[:min(len(candidates), 3)] {
+		out = append(out, c.name)
+	}
+	return out
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// unmappedStatuses returns the names of the given statuses that the active
+// mapping does not cover.
+func (s *Server) unmappedStatuses(ids []string) []string {
+	var unmapped []string
+	for _, id := range ids {
+		if _, ok := s.activeMapping[id]; !ok {
+			unmapped = append(unmapped, s.statusName(id))
+		}
+	}
+	return unmapped
+}
+
+// workflowChangeWarning reports statuses that issues entered after the mapping
+// was confirmed with workflow_set_mapping and that the mapping does not cover:
+// the Jira workflow changed, and analyses treat those statuses as unmapped.
+func (s *Server) workflowChangeWarning(issues []jira.Issue) string {
+	if s.activeMappingConfirmed == nil || len(s.activeMapping) == 0 {
+		return ""
+	}
+	confirmed := *s.activeMappingConfirmed
+	var added []string
+	seen := func(id string) {
+		if _, ok := s.activeMapping[id]; !ok && id != "" && !slices.Contains(added, id) {
+			added = append(added, id)
+		}
+	}
+	for _, issue := range issues {
+		if issue.Created.After(confirmed) {
+			seen(stats.PreferID(issue.BirthStatusID, issue.BirthStatus))
+		}
+		for _, t := range issue.Transitions {
+			if t.Date.After(confirmed) {
+				seen(stats.PreferID(t.ToStatusID, t.ToStatus))
+			}
+		}
+	}
+	if len(added) == 0 {
+		return ""
+	}
+	names := make([]string, len(added))
+	for i, id := range added {
+		names[i] = s.statusName(id)
+	}
+	return fmt.Sprintf("WORKFLOW CHANGED: %d status(es) appeared after the mapping was confirmed on %s and are not mapped: %s. Items in them count as unmapped; re-run 'workflow_discover_mapping' with force_refresh and confirm the new statuses with 'workflow_set_mapping'.",
+		len(names), confirmed.Format(stats.DateFormat), strings.Join(names, ", "))
+}
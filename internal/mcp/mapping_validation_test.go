@@ -0,0 +1,70 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestSetWorkflowMapping_Validation(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	if _, err := srv.handleGetWorkflowDiscovery(ctx, testProject, testBoard, false); err != nil {
+		t.Fatalf("discovery: %v", err)
+	}
+	tier := func(t string) map[string]any { return map[string]any{"tier": t, "role": "active"} }
+
+	_, err := srv.handleSetWorkflowMapping(ctx, testProject, testBoard, map[string]any{"developng": tier("Downstream")}, nil, "", nil)
+	if err == nil || !strings.Contains(err.Error(), "developng") || !strings.Contains(err.Error(), "did you mean 'developing'") {
+		t.Fatalf("Expected the unknown status rejected with a suggestion, got %v", err)
+	}
+	_, err = srv.handleSetWorkflowMapping(ctx, testProject, testBoard, map[string]any{"Done": tier("Finished")}, nil, "Nowhere", nil)
+	if err == nil || !strings.Contains(err.Error(), "'Nowhere'") {
+		t.Fatalf("Expected the unknown commitment point rejected, got %v", err)
+	}
+
+	// Names (in any case) and IDs are accepted; statuses of the history left out are reported.
+	mapping := map[string]any{
+		"Open":                 tier("Demand"),
+		"AWAITING DEVELOPMENT": tier("Downstream"),
+		"38777":                tier("Downstream"),
+		"Done":                 tier("Finished"),
+	}
+	res, err := srv.handleSetWorkflowMapping(ctx, testProject, testBoard, mapping, nil, "awaiting development", nil)
+	if err != nil {
+		t.Fatalf("Expected a valid mapping stored, got %v", err)
+	}
+	env := res.(ResponseEnvelope)
+	if len(env.Guardrails.Warnings) != 1 || !strings.Contains(env.Guardrails.Warnings[0], "UNMAPPED STATUSES") || !strings.Contains(env.Guardrails.Warnings[0], "refining") {
+		t.Errorf("Expected the unmapped history statuses reported, got %v", env.Guardrails.Warnings)
+	}
+	if srv.activeCommitmentPoint != "38776" || srv.activeMappingConfirmed == nil {
+		t.Errorf("Expected the commitment point resolved and the confirmation recorded, got %q / %v", srv.activeCommitmentPoint, srv.activeMappingConfirmed)
+	}
+}
+
+func TestWorkflowChangeWarning(t *testing.T) {
+	srv := newGoldenServer(t)
+	if w := srv.workflowChangeWarning(nil); w != "" {
+		t.Fatalf("Expected no warning without a confirmation date, got %q", w)
+	}
+
+	confirmed := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+	srv.activeMappingConfirmed = &confirmed
+	issues := []jira.Issue{
+		{Created: confirmed.AddDate(0, 0, -10), BirthStatusID: "99", BirthStatus: "Legacy", Transitions: []jira.StatusTransition{
+			{ToStatusID: "98", ToStatus: "Retired", Date: confirmed.AddDate(0, 0, -1)},
+			{ToStatusID: "38777", ToStatus: "developing", Date: confirmed.AddDate(0, 0, 1)},
+		}},
+		{Created: confirmed.AddDate(0, 0, 2), BirthStatusID: "1", BirthStatus: "Open", Transitions: []jira.StatusTransition{
+			{ToStatusID: "97", ToStatus: "Triage", Date: confirmed.AddDate(0, 0, 3)},
+		}},
+	}
+	w := srv.workflowChangeWarning(issues)
+	if !strings.Contains(w, "WORKFLOW CHANGED: 1 status(es)") || !strings.Contains(w, "97") {
+		t.Errorf("Expected only the status entered after the confirmation reported, got %q", w)
+	}
+}
@@ -0,0 +1,41 @@
+package mcp
+
+import (
+	"context"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// outcomeScoped is implemented by tool inputs that embed OutcomeOption.
+type outcomeScoped interface {
+	outcomeScopeOption() stats.OutcomeScope
+}
+
+func (o OutcomeOption) outcomeScopeOption() stats.OutcomeScope { return o.OutcomeScope }
+
+// withOutcomeScope validates the outcome_scope parameter of a tool input and
+// makes it the outcome scope for the duration of the call.
+func withOutcomeScope[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		o, ok := any(args).(outcomeScoped)
+		if !ok || o.outcomeScopeOption() == "" {
+			return handler(ctx, req, args)
+		}
+		scope, err := stats.ParseOutcomeScope(string(o.outcomeScopeOption()))
+		if err != nil {
+			return formatToolError(err), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.outcomes = scope }), req, args)
+	}
+}
+
+// outcomes returns which finished items count as done in analyses: the
+// outcome_scope parameter of the call, else the delivered ones.
+func (s *Server) outcomes(ctx context.Context) stats.OutcomeScope {
+	if scope := callStateOf(ctx).outcomes; scope != "" {
+		return scope
+	}
+	return stats.OutcomesDelivered
+}
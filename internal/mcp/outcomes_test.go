@@ -0,0 +1,74 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWithOutcomeScope(t *testing.T) {
+	ctx := context.Background()
+	var seen stats.OutcomeScope
+	srv := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	wrapped := withOutcomeScope(srv, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeThroughputInput) (*mcp.CallToolResult, any, error) {
+		seen = srv.outcomes(ctx)
+		return formatToolResult(ctx, srv, nil), nil, nil
+	})
+	call := func(scope stats.OutcomeScope) *mcp.CallToolResult {
+		res, _, _ := wrapped(context.Background(), nil, AnalyzeThroughputInput{OutcomeOption: OutcomeOption{OutcomeScope: scope}})
+		return res
+	}
+
+	if res := call(""); res.IsError || seen != stats.OutcomesDelivered {
+		t.Errorf("Expected the default scope delivered, got %q", seen)
+	}
+	if res := call(stats.OutcomesAll); res.IsError || seen != stats.OutcomesAll {
+		t.Errorf("Expected the call scope all, got %q", seen)
+	}
+	if res := call("resolved"); !res.IsError {
+		t.Error("Expected an unknown scope to fail")
+	}
+	if got := srv.outcomes(ctx); got != stats.OutcomesDelivered {
+		t.Errorf("Expected the call scope to be reset after the call, got %q", got)
+	}
+}
+
+func TestOutcomeScopeCountsAbandonedThroughput(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+
+	total := func(scope stats.OutcomeScope) (int, ResponseEnvelope) {
+		t.Helper()
+		ctx := withCallOption(ctx, func(st *callState) { st.outcomes = scope })
+		res, err := srv.handleGetDeliveryCadence(ctx, testProject, testBoard, "week", "", false, "")
+		if err != nil {
+			t.Fatalf("analyze_throughput (%s): %v", scope, err)
+		}
+		env := srv.injectSessionContext(ctx, res).(ResponseEnvelope)
+		n := 0
+		for _, c := range env.Data.(ThroughputResult).TotalThroughput {
+			n += c
+		}
+		return n, env
+	}
+
+	delivered, env := total(stats.OutcomesDelivered)
+	if _, ok := env.Context["outcome_scope"]; ok {
+		t.Error("Expected the default scope not to be echoed")
+	}
+	abandoned, _ := total(stats.OutcomesAbandoned)
+	all, env := total(stats.OutcomesAll)
+	if delivered == 0 || abandoned == 0 {
+		t.Fatalf("Expected delivered and abandoned items in the fixture, got %d and %d", delivered, abandoned)
+	}
+	if all != delivered+abandoned {
+		t.Errorf("Expected all (%d) to be delivered (%d) plus abandoned (%d)", all, delivered, abandoned)
+	}
+	if env.Context["outcome_scope"] != stats.OutcomesAll {
+		t.Errorf("Expected context.outcome_scope all, got %v", env.Context["outcome_scope"])
+	}
+}
@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// outlierPolicied is implemented by tool inputs that embed OutlierOption.
+type outlierPolicied interface {
+	outlierPolicyOption() stats.OutlierPolicy
+}
+
+func (o OutlierOption) outlierPolicyOption() stats.OutlierPolicy { return o.Outliers }
+
+// withOutlierPolicy validates the outliers parameter of a tool input and
+// makes it the outlier policy for the duration of the call.
+func withOutlierPolicy[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		op, ok := any(args).(outlierPolicied)
+		if !ok || op.outlierPolicyOption() == "" {
+			return handler(ctx, req, args)
+		}
+		policy, err := stats.ParseOutlierPolicy(string(op.outlierPolicyOption()))
+		if err != nil {
+			return formatToolError(err), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.outliers = policy }), req, args)
+	}
+}
+
+// outliers returns the outlier policy of analyses: the outliers parameter of
+// the call, else the outliers source setting, else the MCS_OUTLIER_POLICY
+// setting.
+func (s *Server) outliers(ctx context.Context) stats.OutlierPolicy {
+	if p := callStateOf(ctx).outliers; p != "" {
+		return p
+	}
+	if s.activeSettings.Outliers != "" {
+		return s.activeSettings.Outliers
+	}
+	if s.outlierPolicy == "" {
+		return stats.OutliersNone
+	}
+	return s.outlierPolicy
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWithOutlierPolicy(t *testing.T) {
+	ctx := context.Background()
+	var seen stats.OutlierPolicy
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir(), OutlierPolicy: stats.OutliersWinsorize}, &mockJiraClient{})
+	call := func(policy stats.OutlierPolicy) *mcp.CallToolResult {
+		res, _, _ := withOutlierPolicy(s, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeCycleTimeInput) (*mcp.CallToolResult, any, error) {
+			seen = s.outliers(ctx)
+			return formatToolResult(ctx, s, nil), nil, nil
+		})(context.Background(), nil, AnalyzeCycleTimeInput{OutlierOption: OutlierOption{Outliers: policy}})
+		return res
+	}
+
+	if res := call(""); res.IsError || seen != stats.OutliersWinsorize {
+		t.Errorf("Expected the server policy winsorize, got %q", seen)
+	}
+	if res := call(stats.OutliersIQR); res.IsError || seen != stats.OutliersIQR {
+		t.Errorf("Expected the call policy iqr, got %q", seen)
+	}
+	if res := call("p95"); !res.IsError {
+		t.Error("Expected an unknown policy to fail")
+	}
+	if got := s.outliers(ctx); got != stats.OutliersWinsorize {
+		t.Errorf("Expected the call policy to be reset after the call, got %q", got)
+	}
+}
+
+func TestCycleTimeAssessment_Outliers(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	result := func(ctx context.Context) simulation.Result {
+		t.Helper()
+		res, err := srv.handleGetCycleTimeAssessment(ctx, testProject, testBoard, "", "", nil, 0, 0, false, "")
+		if err != nil {
+			t.Fatalf("handleGetCycleTimeAssessment: %v", err)
+		}
+		return res.(ResponseEnvelope).Data.(simulation.Result)
+	}
+
+	plain := result(ctx)
+	if _, ok := plain.Context["outliers"]; ok {
+		t.Error("Expected no outlier report without a policy")
+	}
+
+	trimmed := result(withCallOption(ctx, func(st *callState) { st.outliers = stats.OutliersIQR }))
+	report, ok := trimmed.Context["outliers"].(stats.OutlierTrim)
+	if !ok || report.Policy != stats.OutliersIQR || report.Trimmed == 0 {
+		t.Fatalf("Expected an iqr report with trimmed items, got %v", trimmed.Context["outliers"])
+	}
+	if trimmed.Percentiles.AlmostCertain >= plain.Percentiles.AlmostCertain {
+		t.Errorf("Expected trimming to lower P98, got %.1f vs %.1f", trimmed.Percentiles.AlmostCertain, plain.Percentiles.AlmostCertain)
+	}
+	if len(trimmed.Scatterplot) != len(plain.Scatterplot) {
+		t.Errorf("Expected the scatterplot to keep every item, got %d vs %d", len(trimmed.Scatterplot), len(plain.Scatterplot))
+	}
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Page describes the part of an item list a result returns.
+type Page struct {
+	Items      string   `json:"items"`                 // Field of the result holding the paged list
+	Total      int      `json:"total"`                 // Items in the full list
+	Offset     int      `json:"offset"`                // Items skipped before this page
+	Returned   int      `json:"returned"`              // Items on this page
+	NextOffset *int     `json:"next_offset,omitempty"` // Offset of the following page; absent on the last page
+	SortBy     ItemSort `json:"sort_by,omitempty"`
+}
+
+// paged is implemented by tool inputs that embed PageOption.
+type paged interface {
+	pageOption() PageOption
+}
+
+func (o PageOption) pageOption() PageOption { return o }
+
+// withPagination validates the limit, offset, and sort_by parameters of a
+// tool input and makes them the paging of the item list for the duration of
+// the call.
+func withPagination[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		p, ok := any(args).(paged)
+		if !ok || p.pageOption() == (PageOption{}) {
+			return handler(ctx, req, args)
+		}
+		opt := p.pageOption()
+		if opt.Limit < 0 || opt.Offset < 0 {
+			return formatToolError(fmt.Errorf("limit and offset must not be negative")), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.page = &opt }), req, args)
+	}
+}
+
+// pageItems applies the paging of the call ctx belongs to to the item list
+// held by the result field name: it sorts the items by one of orders, each a
+// comparison putting a first when negative, and cuts out the requested page.
+// Without paging parameters it returns the items unchanged and a nil Page.
+func pageItems[T any](ctx context.Context, name string, items []T, orders map[ItemSort]func(a, b T) int) ([]T, *Page, error) {
+	opt := callStateOf(ctx).page
+	if opt == nil {
+		return items, nil, nil
+	}
+	if opt.SortBy != "" {
+		cmp, ok := orders[opt.SortBy]
+		if !ok {
+			var valid []string
+			for order := range orders {
+				valid = append(valid, string(order))
+			}
+			slices.Sort(valid)
+			return nil, nil, fmt.Errorf("sort_by %q is not available for %s: expected one of %s", opt.SortBy, name, strings.Join(valid, ", "))
+		}
+		items = slices.Clone(items)
+		slices.SortStableFunc(items, cmp)
+	}
+
+	total := len(items)
+	start := min(opt.Offset, total)
+	end := total
+	if opt.Limit > 0 {
+		end = min(start+opt.Limit, total)
+	}
+	page := &Page{Items: name, Total: total, Offset: opt.Offset, Returned: end - start, SortBy: opt.SortBy}
+	if end < total {
+		page.NextOffset = &end
+	}
+	return items[start:end], page, nil
+}
+
+// agingOrders are the orders of the items of analyze_work_item_age. Their age
+// is the age since commitment for the agingType wip, else since creation.
+func agingOrders(agingType string) map[ItemSort]func(a, b stats.InventoryAge) int {
+	age := func(a stats.InventoryAge) float64 {
+		if agingType == "wip" && a.AgeSinceCommitment != nil {
+			return *a.AgeSinceCommitment
+		}
+		return a.TotalAgeSinceCreation
+	}
+	return map[ItemSort]func(a, b stats.InventoryAge) int{
+		SortByAge: func(a, b stats.InventoryAge) int { return cmp.Compare(age(b), age(a)) },
+		SortByPercentile: func(a, b stats.InventoryAge) int {
+			return cmp.Or(cmp.Compare(b.Percentile, a.Percentile), cmp.Compare(age(b), age(a)))
+		},
+		SortByKey: func(a, b stats.InventoryAge) int { return compareIssueKeys(a.Key, b.Key) },
+	}
+}
+
+// scatterOrders are the orders of the points of the cycle-time scatterplots.
+var scatterOrders = map[ItemSort]func(a, b stats.ScatterPoint) int{
+	SortByCycleTime: func(a, b stats.ScatterPoint) int { return cmp.Compare(b.Value, a.Value) },
+	SortByDate:      func(a, b stats.ScatterPoint) int { return strings.Compare(b.Date, a.Date) },
+	SortByKey:       func(a, b stats.ScatterPoint) int { return compareIssueKeys(a.Key, b.Key) },
+}
+
+// compareIssueKeys orders issue keys by project, then by number, so that
+// PROJ-9 comes before PROJ-10.
+func compareIssueKeys(a, b string) int {
+	pa, na := splitIssueKey(a)
+	pb, nb := splitIssueKey(b)
+	return cmp.Or(strings.Compare(pa, pb), cmp.Compare(na, nb), strings.Compare(a, b))
+}
+
+func splitIssueKey(key string) (string, int) {
+	i := strings.LastIndex(key, "-")
+	if i < 0 {
+		return key, 0
+	}
+	n, err := strconv.Atoi(key[i+1:])
+	if err != nil {
+		return key, 0
+	}
+	return key[:i], n
+}
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestPageItems(t *testing.T) {
+	age := func(days float64) *float64 { return &days }
+	items := []stats.InventoryAge{
+		{Key: "PROJ-10", AgeSinceCommitment: age(3), Percentile: 40},
+		{Key: "PROJ-9", AgeSinceCommitment: age(12), Percentile: 95},
+		{Key: "PROJ-11", AgeSinceCommitment: age(7), Percentile: 95},
+	}
+	paged := func(opt PageOption) context.Context {
+		return withCallOption(context.Background(), func(st *callState) { st.page = &opt })
+	}
+
+	got, page, err := pageItems(context.Background(), "aging", items, agingOrders("wip"))
+	if err != nil || page != nil || len(got) != 3 || got[0].Key != "PROJ-10" {
+		t.Fatalf("Expected the items unchanged without paging, got %v (page %v, err %v)", got, page, err)
+	}
+
+	got, page, err = pageItems(paged(PageOption{Limit: 2, SortBy: SortByAge}), "aging", items, agingOrders("wip"))
+	if err != nil || len(got) != 2 || got[0].Key != "PROJ-9" || got[1].Key != "PROJ-11" {
+		t.Fatalf("Expected the two oldest items, got %v (err %v)", got, err)
+	}
+	if page.Total != 3 || page.Returned != 2 || page.NextOffset == nil || *page.NextOffset != 2 {
+		t.Errorf("Expected 2 of 3 items with next_offset 2, got %+v", page)
+	}
+	if items[0].Key != "PROJ-10" {
+		t.Error("Expected sorting to leave the caller's items in place")
+	}
+
+	got, page, _ = pageItems(paged(PageOption{Limit: 2, Offset: 2, SortBy: SortByKey}), "aging", items, agingOrders("wip"))
+	if len(got) != 1 || got[0].Key != "PROJ-11" || page.NextOffset != nil {
+		t.Errorf("Expected PROJ-11 alone on the last page, got %v (%+v)", got, page)
+	}
+
+	if got, page, _ = pageItems(paged(PageOption{Offset: 5}), "aging", items, agingOrders("wip")); len(got) != 0 || page.Total != 3 {
+		t.Errorf("Expected an empty page past the end, got %v (%+v)", got, page)
+	}
+
+	if _, _, err = pageItems(paged(PageOption{SortBy: SortByDate}), "aging", items, agingOrders("wip")); err == nil {
+		t.Error("Expected an order the list does not offer to fail")
+	}
+}
+
+func TestWithPagination(t *testing.T) {
+	var seen *PageOption
+	srv := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	call := func(opt PageOption) *mcp.CallToolResult {
+		res, _, _ := withPagination(srv, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeWorkItemAgeInput) (*mcp.CallToolResult, any, error) {
+			seen = callStateOf(ctx).page
+			return formatToolResult(ctx, srv, nil), nil, nil
+		})(context.Background(), nil, AnalyzeWorkItemAgeInput{PageOption: opt})
+		return res
+	}
+
+	if res := call(PageOption{}); res.IsError || seen != nil {
+		t.Errorf("Expected no paging without parameters, got %+v", seen)
+	}
+	if res := call(PageOption{Limit: 10, SortBy: SortByPercentile}); res.IsError || seen == nil || seen.Limit != 10 {
+		t.Errorf("Expected the call's paging, got %+v", seen)
+	}
+	if res := call(PageOption{Limit: -1}); !res.IsError {
+		t.Error("Expected a negative limit to fail")
+	}
+}
+
+func TestAgingPagination(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	full, err := srv.handleGetAgingAnalysis(ctx, testProject, testBoard, "wip", "All", false)
+	if err != nil {
+		t.Fatalf("analyze_work_item_age: %v", err)
+	}
+	all := full.(ResponseEnvelope).Data.(AgingResult).Aging
+	if len(all) < 2 {
+		t.Skipf("Golden data has %d aging items", len(all))
+	}
+
+	paged := withCallOption(ctx, func(st *callState) { st.page = &PageOption{Limit: 1, SortBy: SortByAge} })
+	res, err := srv.handleGetAgingAnalysis(paged, testProject, testBoard, "wip", "All", false)
+	if err != nil {
+		t.Fatalf("analyze_work_item_age (paged): %v", err)
+	}
+	data := res.(ResponseEnvelope).Data.(AgingResult)
+	if got := data.Aging; len(got) != 1 {
+		t.Errorf("Expected one item, got %d", len(got))
+	}
+	if page := data.Page; page.Total != len(all) {
+		t.Errorf("Expected page.total %d, got %d", len(all), page.Total)
+	}
+	if summary := data.Summary; summary.TotalItems != len(all) {
+		t.Errorf("Expected the summary to cover all %d items, got %d", len(all), summary.TotalItems)
+	}
+}
@@ -0,0 +1,66 @@
+package mcp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// configTools are the tools that change a board's persisted configuration or
+// cached data. MCS_READ_ONLY disables them; session-only settings such as
+// set_analysis_window stay available.
+var configTools = map[string]bool{
+	"set_sle":                      true,
+	"set_wip_limits":               true,
+	"set_alert_rules":              true,
+	"record_commitment":            true,
+	"set_source_settings":          true,
+	"save_forecast_template":       true,
+	"workflow_set_mapping":         true,
+	"workflow_set_order":           true,
+	"workflow_import_mapping":      true,
+	"workflow_set_type_aliases":    true,
+	"workflow_set_evaluation_date": true,
+	"import_workspace":             true,
+	"import_throughput_csv":        true,
+}
+
+// toolEnabled reports whether a tool is registered: it must be on the
+// MCS_ENABLED_TOOLS allowlist when one is set, and must not change the
+// configuration of a read-only server.
+func (s *Server) toolEnabled(name string) bool {
+	if s.readOnly && configTools[name] {
+		return false
+	}
+	return s.enabledTools == nil || s.enabledTools[name]
+}
+
+// checkEnabledTools rejects allowlist entries that name no tool, so a typo
+// does not silently hide a tool.
+func (s *Server) checkEnabledTools() error {
+	var unknown []string
+	for name := range s.enabledTools {
+		if _, ok := toolDescriptions[name]; !ok {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return fmt.Errorf("MCS_ENABLED_TOOLS: unknown tool(s) %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// instructions returns the server instructions, with a note on the tools a
+// restricted deployment leaves out.
+func (s *Server) instructions() string {
+	if !s.readOnly && s.enabledTools == nil {
+		return serverInstructions
+	}
+	note := "\n\nRESTRICTED DEPLOYMENT:\n  This server does not offer every tool named above; only the listed tools are available."
+	if s.readOnly {
+		note += "\n  It is read-only: workflow mappings, status order, SLEs, WIP limits, type aliases, source settings, and the evaluation date cannot be changed."
+	}
+	note += "\n  When a step needs a tool that is not listed, tell the user which change an administrator has to make instead of working around it."
+	return serverInstructions + note
+}
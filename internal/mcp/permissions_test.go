@@ -0,0 +1,85 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolPermissions(t *testing.T) {
+	listTools := func(srv *Server) (*mcp.ClientSession, map[string]bool) {
+		t.Helper()
+		mcpSrv, err := NewMCPServer(srv, "test")
+		if err != nil {
+			t.Fatalf("NewMCPServer: %v", err)
+		}
+		ctx := context.Background()
+		serverTransport, clientTransport := mcp.NewInMemoryTransports()
+		ss, err := mcpSrv.Connect(ctx, serverTransport, nil)
+		if err != nil {
+			t.Fatalf("server connect: %v", err)
+		}
+		t.Cleanup(func() { ss.Close() })
+		client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{})
+		cs, err := client.Connect(ctx, clientTransport, nil)
+		if err != nil {
+			t.Fatalf("client connect: %v", err)
+		}
+		t.Cleanup(func() { cs.Close() })
+		tools, err := cs.ListTools(ctx, nil)
+		if err != nil {
+			t.Fatalf("ListTools: %v", err)
+		}
+		names := make(map[string]bool, len(tools.Tools))
+		for _, tool := range tools.Tools {
+			names[tool.Name] = true
+		}
+		return cs, names
+	}
+
+	t.Run("read-only", func(t *testing.T) {
+		srv := newGoldenServer(t)
+		srv.readOnly = true
+		cs, names := listTools(srv)
+		for name := range configTools {
+			if _, ok := toolDescriptions[name]; !ok {
+				t.Errorf("Configuration tool %s does not exist", name)
+			}
+			if names[name] {
+				t.Errorf("Expected %s to be hidden on a read-only server", name)
+			}
+		}
+		if !names["analyze_throughput"] || !names["set_analysis_window"] {
+			t.Errorf("Expected analyses and session settings to stay available, got %v", names)
+		}
+		res, err := cs.CallTool(context.Background(), &mcp.CallToolParams{
+			Name:      "workflow_set_mapping",
+			Arguments: map[string]any{"project_key": testProject, "board_id": testBoard},
+		})
+		if err == nil && !res.IsError {
+			t.Errorf("Expected a call of a disabled tool to fail")
+		}
+		if !strings.Contains(srv.instructions(), "read-only") {
+			t.Errorf("Expected the instructions to mention the read-only mode")
+		}
+	})
+
+	t.Run("allowlist", func(t *testing.T) {
+		srv := newGoldenServer(t)
+		srv.enabledTools = map[string]bool{"analyze_throughput": true, "set_sle": true}
+		_, names := listTools(srv)
+		if len(names) != 2 || !names["analyze_throughput"] || !names["set_sle"] {
+			t.Errorf("Expected only the allowlisted tools, got %v", names)
+		}
+	})
+
+	t.Run("unknown allowlist entry", func(t *testing.T) {
+		srv := newGoldenServer(t)
+		srv.enabledTools = map[string]bool{"analyse_throughput": true}
+		if _, err := NewMCPServer(srv, "test"); err == nil || !strings.Contains(err.Error(), "analyse_throughput") {
+			t.Errorf("Expected the unknown tool to be rejected, got %v", err)
+		}
+	})
+}
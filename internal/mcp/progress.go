@@ -0,0 +1,58 @@
+package mcp
+
+import (
+	"fmt"
+	"sync"
+
+	"mcs-mcp/internal/eventlog"
+)
+
+// progressReporter adapts the ingestion progress of a tool call to a
+// progressFunc. Progress is the number of issues fetched across all phases of
+// the call, so it never decreases when a call hydrates several sources.
+type progressReporter struct {
+	mu     sync.Mutex
+	notify progressFunc
+	base   int // issues fetched by completed phases
+	last   int
+}
+
+var phaseLabels = map[string]string{
+	eventlog.PhaseCache:     "served from cache",
+	eventlog.PhaseHydration: "initial hydration",
+	eventlog.PhaseDeltaSync: "delta sync",
+	eventlog.PhaseCatchUp:   "catch-up",
+}
+
+func (r *progressReporter) report(p eventlog.Progress) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	progress := max(r.base+p.Fetched, r.last)
+	r.last = progress
+
+	total := 0
+	if p.Total > 0 {
+		total = r.base + p.Total
+	}
+	if p.Done {
+		r.base = progress
+	}
+	r.notify(progress, total, progressMessage(p))
+}
+
+func progressMessage(p eventlog.Progress) string {
+	label := phaseLabels[p.Phase]
+	if label == "" {
+		label = p.Phase
+	}
+	switch {
+	case p.Phase == eventlog.PhaseCache:
+		return fmt.Sprintf("%s: %s", p.SourceID, label)
+	case p.Done:
+		return fmt.Sprintf("%s: %s complete, %d issues fetched", p.SourceID, label, p.Fetched)
+	case p.Total > 0:
+		return fmt.Sprintf("%s: %s, %d of %d issues fetched", p.SourceID, label, p.Fetched, p.Total)
+	default:
+		return fmt.Sprintf("%s: %s, %d issues fetched", p.SourceID, label, p.Fetched)
+	}
+}
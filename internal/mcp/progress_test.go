@@ -0,0 +1,91 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/eventlog"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestProgressReporter_AccumulatesAcrossPhases(t *testing.T) {
+	type update struct {
+		progress, total int
+		message         string
+	}
+	var sent []update
+	r := &progressReporter{notify: func(progress, total int, message string) {
+		sent = append(sent, update{progress, total, message})
+	}}
+
+	r.report(eventlog.Progress{SourceID: "A_1", Phase: eventlog.PhaseHydration, Fetched: 300, Total: 500})
+	r.report(eventlog.Progress{SourceID: "A_1", Phase: eventlog.PhaseHydration, Fetched: 500, Total: 500, Done: true})
+	r.report(eventlog.Progress{SourceID: "B_2", Phase: eventlog.PhaseDeltaSync})
+	r.report(eventlog.Progress{SourceID: "B_2", Phase: eventlog.PhaseDeltaSync, Fetched: 20})
+
+	want := []update{
+		{300, 500, "A_1: initial hydration, 300 of 500 issues fetched"},
+		{500, 500, "A_1: initial hydration complete, 500 issues fetched"},
+		{500, 0, "B_2: delta sync, 0 issues fetched"},
+		{520, 0, "B_2: delta sync, 20 issues fetched"},
+	}
+	if len(sent) != len(want) {
+		t.Fatalf("Expected %d notifications, got %+v", len(want), sent)
+	}
+	for i := range want {
+		if sent[i] != want[i] {
+			t.Errorf("Notification %d: expected %+v, got %+v", i, want[i], sent[i])
+		}
+	}
+}
+
+func TestToolCall_SendsProgressNotifications(t *testing.T) {
+	srv := newGoldenServer(t)
+	mcpSrv, err := NewMCPServer(srv, "test")
+	if err != nil {
+		t.Fatalf("NewMCPServer: %v", err)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := mcpSrv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	defer ss.Close()
+
+	messages := make(chan string, 16)
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{
+		ProgressNotificationHandler: func(_ context.Context, req *mcp.ProgressNotificationClientRequest) {
+			messages <- req.Params.Message
+		},
+	})
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer cs.Close()
+
+	params := &mcp.CallToolParams{
+		Name:      "analyze_throughput",
+		Arguments: map[string]any{"project_key": testProject, "board_id": testBoard},
+	}
+	params.SetProgressToken("ingest-1")
+	res, err := cs.CallTool(ctx, params)
+	if err != nil || res.IsError {
+		t.Fatalf("analyze_throughput failed: %v %+v", err, res)
+	}
+
+	// Notifications are handled asynchronously on the client side
+	select {
+	case msg := <-messages:
+		if !strings.Contains(msg, testSourceID+": served from cache") {
+			t.Errorf("Expected a cache progress notification for %s, got %q", testSourceID, msg)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Expected a progress notification")
+	}
+}
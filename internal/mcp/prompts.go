@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// diagnosticPrompt is an MCP prompt that turns a diagnostic roadmap into a
+// structured multi-step instruction, so clients with prompt support start an
+// analysis in the server's order instead of one the agent invents.
+type diagnosticPrompt struct {
+	name        string
+	title       string
+	description string
+	goal        DiagnosticGoal
+	task        string // opening sentence; %s is the board reference
+	extraArgs   []*mcp.PromptArgument
+	notes       func(args map[string]string) []string
+}
+
+var boardPromptArgs = []*mcp.PromptArgument{
+	{Name: "project_key", Title: "Project key", Description: "Jira project key, e.g. PROJ.", Required: true},
+	{Name: "board_id", Title: "Board ID", Description: "Jira board ID.", Required: true},
+}
+
+var diagnosticPrompts = []diagnosticPrompt{
+	{
+		name:        "forecast-release",
+		title:       "Forecast a release",
+		description: "Validate the delivery system, then forecast when the remaining work of a release or backlog will be done.",
+		goal:        GoalForecasting,
+		task:        "Forecast the delivery of %s.",
+		extraArgs: []*mcp.PromptArgument{
+			{Name: "fix_version", Title: "Release", Description: "Optional fixVersion (release name) to scope the analysis to."},
+			{Name: "target_date", Title: "Target date", Description: "Optional target date (YYYY-MM-DD) to assess the release against."},
+		},
+		notes: func(args map[string]string) []string {
+			notes := []string{"Run forecast_monte_carlo in duration mode with include_existing_backlog and include_wip, so the forecast covers all remaining work."}
+			if v := args["fix_version"]; v != "" {
+				notes = append(notes, fmt.Sprintf("Pass fix_version=%q to the analysis and forecast tools, and call analyze_release_burnup with it before forecasting to show the release's scope growth.", v))
+			}
+			if d := args["target_date"]; d != "" {
+				notes = append(notes, fmt.Sprintf("Compare the percentile dates of the forecast with the target date %s and report the likelihood of making it.", d))
+			}
+			return notes
+		},
+	},
+	{
+		name:        "find-bottlenecks",
+		title:       "Find bottlenecks",
+		description: "Locate where work waits, batches, or is abandoned in a board's workflow.",
+		goal:        GoalBottlenecks,
+		task:        "Find the bottlenecks in the workflow of %s.",
+	},
+	{
+		name:        "plan-capacity",
+		title:       "Plan capacity",
+		description: "Determine whether a team can take on more scope in the next period.",
+		goal:        GoalCapacityPlanning,
+		task:        "Assess whether %s can take on more scope.",
+	},
+	{
+		name:        "check-system-health",
+		title:       "Check system health",
+		description: "Audit the long-term stability and conversion efficiency of a board's delivery system.",
+		goal:        GoalSystemHealth,
+		task:        "Audit the long-term health of the delivery system of %s.",
+	},
+}
+
+// registerPrompts adds the diagnostic prompts to the MCP server.
+func registerPrompts(mcpSrv *mcp.Server) {
+	for _, p := range diagnosticPrompts {
+		mcpSrv.AddPrompt(&mcp.Prompt{
+			Name:        p.name,
+			Title:       p.title,
+			Description: p.description,
+			Arguments:   append(append([]*mcp.PromptArgument{}, boardPromptArgs...), p.extraArgs...),
+		}, p.handle)
+	}
+}
+
+func (p diagnosticPrompt) handle(_ context.Context, req *mcp.GetPromptRequest) (*mcp.GetPromptResult, error) {
+	args := req.Params.Arguments
+	projectKey := strings.TrimSpace(args["project_key"])
+	if projectKey == "" {
+		return nil, fmt.Errorf("prompt %s: project_key is required", p.name)
+	}
+	boardID, err := strconv.Atoi(strings.TrimSpace(args["board_id"]))
+	if err != nil {
+		return nil, fmt.Errorf("prompt %s: board_id must be a number, got %q", p.name, args["board_id"])
+	}
+
+	return &mcp.GetPromptResult{
+		Description: p.description,
+		Messages: []*mcp.PromptMessage{{
+			Role: "user",
+			Content: &mcp.TextContent{
+				Text: p.text(projectKey, boardID, args),
+			},
+		}},
+	}, nil
+}
+
+// text renders the prompt message: the task, the roadmap steps as an
+// ordered tool sequence, and any notes from optional arguments.
+func (p diagnosticPrompt) text(projectKey string, boardID int, args map[string]string) string {
+	roadmap := diagnosticRoadmaps[p.goal]
+	var b strings.Builder
+	fmt.Fprintf(&b, p.task, fmt.Sprintf("board %d of project %s", boardID, projectKey))
+	fmt.Fprintf(&b, "\n\nFollow this sequence (%s). If the board is not anchored yet, call import_board_context first. "+
+		"Call each tool with project_key=%q and board_id=%d, read its guardrails before the next step, and do not skip or reorder steps:\n\n",
+		roadmap.Title, projectKey, boardID)
+	for _, step := range roadmap.Steps {
+		fmt.Fprintf(&b, "%d. %s: %s\n", step.Step, step.Tool, step.Description)
+	}
+	if notes := p.optionalNotes(args); len(notes) > 0 {
+		b.WriteString("\n")
+		for _, n := range notes {
+			b.WriteString("- " + n + "\n")
+		}
+	}
+	b.WriteString("\nStop and ask the user if a step reports an unconfirmed workflow mapping or an unstable process. " +
+		"Finish with a short summary that cites the numbers of each step.")
+	return b.String()
+}
+
+func (p diagnosticPrompt) optionalNotes(args map[string]string) []string {
+	if p.notes == nil {
+		return nil
+	}
+	return p.notes(args)
+}
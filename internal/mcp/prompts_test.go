@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestPrompts_ListAndGet(t *testing.T) {
+	srv := newGoldenServer(t)
+	mcpSrv, err := NewMCPServer(srv, "test")
+	if err != nil {
+		t.Fatalf("NewMCPServer: %v", err)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := mcpSrv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	defer ss.Close()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{})
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer cs.Close()
+
+	if cs.InitializeResult().Capabilities.Prompts == nil {
+		t.Fatal("Expected the prompts capability in the initialize response")
+	}
+	listed, err := cs.ListPrompts(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListPrompts: %v", err)
+	}
+	if len(listed.Prompts) != len(diagnosticPrompts) {
+		t.Fatalf("Expected %d prompts, got %d", len(diagnosticPrompts), len(listed.Prompts))
+	}
+
+	res, err := cs.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "forecast-release",
+		Arguments: map[string]string{"project_key": "PROJ", "board_id": "42", "fix_version": "2.4.0"},
+	})
+	if err != nil {
+		t.Fatalf("GetPrompt: %v", err)
+	}
+	text := res.Messages[0].Content.(*mcp.TextContent).Text
+	for _, want := range []string{
+		"board 42 of project PROJ",
+		`project_key="PROJ" and board_id=42`,
+		"1. workflow_discover_mapping:",
+		"7. forecast_backtest:",
+		`fix_version="2.4.0"`,
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("Prompt text lacks %q:\n%s", want, text)
+		}
+	}
+	if strings.Index(text, "analyze_process_stability") > strings.Index(text, "forecast_monte_carlo:") {
+		t.Errorf("Expected the roadmap order, got:\n%s", text)
+	}
+
+	if _, err := cs.GetPrompt(ctx, &mcp.GetPromptParams{
+		Name:      "find-bottlenecks",
+		Arguments: map[string]string{"project_key": "PROJ", "board_id": "x"},
+	}); err == nil {
+		t.Error("Expected an error for a non-numeric board_id")
+	}
+}
@@ -0,0 +1,307 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/jira"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// Query sources let the board-scoped tools analyze an arbitrary JQL or saved
+// filter without a board. Each query maps to a synthetic project key and
+// board ID, so source IDs, workflow files, and context anchoring work exactly
+// as for boards: FILTER_<filter id> and JQL_<hash of the normalized query>.
+// A fix_version narrows any source to one release as a JQL source, and labels
+// and components narrow it to the issues of a sub-team.
+const (
+	filterSourceKey = "FILTER"
+	jqlSourceKey    = "JQL"
+)
+
+// errSubTeamSources is returned by the portfolio tools for labels or
+// components, which would narrow only the first board.
+var errSubTeamSources = errors.New("labels and components narrow one source and cannot be combined with sources")
+
+// errQuerySourceSprints is returned by the sprint tools for query sources.
+var errQuerySourceSprints = errors.New("sprint analysis needs a Scrum board; JQL and filter sources have no sprints")
+
+// isQuerySource reports whether projectKey is the synthetic key of a query source.
+func isQuerySource(projectKey string) bool {
+	return projectKey == filterSourceKey || projectKey == jqlSourceKey
+}
+
+// querySourced is implemented by tool inputs that embed QuerySource.
+type querySourced interface {
+	querySource() QuerySource
+}
+
+func (q QuerySource) querySource() QuerySource { return q }
+
+func (q QuerySource) isSet() bool {
+	return strings.TrimSpace(q.JQL) != "" || strings.TrimSpace(q.FilterID) != ""
+}
+
+// withQuerySource replaces project_key/board_id of a tool input with the
+// synthetic source of its jql or filter_id, when one is given.
+func withQuerySource[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		qs, ok := any(args).(querySourced)
+		if !ok {
+			return handler(ctx, req, args)
+		}
+		v := reflect.ValueOf(&args).Elem()
+		q := qs.querySource()
+		if q.isSet() {
+			projectKey, boardID, err := s.registerQuerySource(q)
+			if err != nil {
+				return formatToolError(err), nil, nil
+			}
+			v.FieldByName("ProjectKey").SetString(projectKey)
+			v.FieldByName("BoardID").SetInt(int64(boardID))
+		} else if v.FieldByName("ProjectKey").String() == "" && v.FieldByName("BoardID").Int() == 0 {
+			return formatToolError(fmt.Errorf("project_key is required unless jql or filter_id is set")), nil, nil
+		}
+		if version := strings.TrimSpace(q.FixVersion); version != "" {
+			projectKey, boardID, err := s.scopeToFixVersion(ctx, v.FieldByName("ProjectKey").String(), int(v.FieldByName("BoardID").Int()), version)
+			if err != nil {
+				return formatToolError(err), nil, nil
+			}
+			v.FieldByName("ProjectKey").SetString(projectKey)
+			v.FieldByName("BoardID").SetInt(int64(boardID))
+		}
+		if filter := q.issueFilter(); !filter.IsZero() {
+			projectKey, boardID, err := s.scopeToIssueFilter(ctx, v.FieldByName("ProjectKey").String(), int(v.FieldByName("BoardID").Int()), filter)
+			if err != nil {
+				return formatToolError(err), nil, nil
+			}
+			v.FieldByName("ProjectKey").SetString(projectKey)
+			v.FieldByName("BoardID").SetInt(int64(boardID))
+		}
+		return handler(ctx, req, args)
+	}
+}
+
+// registerQuerySource returns the synthetic project key and board ID of a
+// query source. The JQL of a JQL source is remembered (and persisted) so that
+// later calls can address it by JQL_<id> alone.
+func (s *Server) registerQuerySource(q QuerySource) (string, int, error) {
+	filterID := strings.TrimSpace(q.FilterID)
+
+	switch {
+	case strings.TrimSpace(q.JQL) != "" && filterID != "":
+		return "", 0, fmt.Errorf("set either jql or filter_id, not both")
+	case filterID != "":
+		id, err := strconv.Atoi(filterID)
+		if err != nil || id <= 0 {
+			return "", 0, fmt.Errorf("invalid filter_id %q: expected the numeric ID of a saved Jira filter", q.FilterID)
+		}
+		return filterSourceKey, id, nil
+	}
+	query, err := jira.NormalizeJQL(q.JQL)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid jql: %w", err)
+	}
+	return s.registerJQL(query, jira.IssueFilter{})
+}
+
+// registerJQL registers a normalized query as a JQL source. filter records the
+// labels and components the query was narrowed to, for in-memory filtering.
+func (s *Server) registerJQL(query string, filter jira.IssueFilter) (string, int, error) {
+	h := fnv.New32a()
+	h.Write([]byte(query))
+	id := int(h.Sum32() & 0x7fffffff)
+	if id == 0 {
+		id = 1
+	}
+
+	f := querySourceFile{JQL: query, IssueFilter: filter}
+	s.querySources[id] = f
+	if err := s.saveQuerySource(id, f); err != nil {
+		log.Warn().Err(err).Int("id", id).Msg("Failed to persist JQL source to disk")
+	}
+	return jqlSourceKey, id, nil
+}
+
+// scopeToFixVersion returns the JQL source of the issues of a source that are
+// assigned to version. The first time a release is scoped, it inherits the
+// persisted workflow of the source it narrows, so its mapping and commitment
+// point need not be confirmed again.
+func (s *Server) scopeToFixVersion(ctx context.Context, projectKey string, boardID int, version string) (string, int, error) {
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
+	if err != nil {
+		return "", 0, err
+	}
+	query := jira.AndJQL(sc.JQL, jira.FieldEquals("fixVersion", version))
+	key, id, err := s.registerQuerySource(QuerySource{JQL: query})
+	if err != nil {
+		return "", 0, err
+	}
+	if err := s.inheritWorkflow(projectKey, boardID, key, id); err != nil {
+		log.Warn().Err(err).Str("version", version).Msg("Failed to inherit workflow metadata for release scope")
+	}
+	return key, id, nil
+}
+
+// issueFilter returns the labels and components of a query source, trimmed.
+func (q QuerySource) issueFilter() jira.IssueFilter {
+	trim := func(values []string) []string {
+		var out []string
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" && !slices.Contains(out, v) {
+				out = append(out, v)
+			}
+		}
+		return out
+	}
+	return jira.IssueFilter{Labels: trim(q.Labels), Components: trim(q.Components)}
+}
+
+// scopeToIssueFilter returns the JQL source of the issues of a source that
+// carry the filter's labels and components. Like a release, the narrowed
+// source inherits the persisted workflow of the source it narrows; the filter
+// is kept with the query and re-applied to its event log.
+func (s *Server) scopeToIssueFilter(ctx context.Context, projectKey string, boardID int, filter jira.IssueFilter) (string, int, error) {
+	sc, err := s.resolveSourceContext(ctx, projectKey, boardID)
+	if err != nil {
+		return "", 0, err
+	}
+	query, err := jira.NormalizeJQL(jira.AndJQL(sc.JQL, filter.Clauses()...))
+	if err != nil {
+		return "", 0, fmt.Errorf("labels/components: %w", err)
+	}
+	key, id, err := s.registerJQL(query, filter)
+	if err != nil {
+		return "", 0, err
+	}
+	if err := s.inheritWorkflow(projectKey, boardID, key, id); err != nil {
+		log.Warn().Err(err).Strs("labels", filter.Labels).Strs("components", filter.Components).Msg("Failed to inherit workflow metadata for sub-team scope")
+	}
+	return key, id, nil
+}
+
+// inheritWorkflow copies the persisted workflow of a source to a source
+// derived from it, unless the derived source already has its own.
+func (s *Server) inheritWorkflow(fromKey string, fromID int, toKey string, toID int) error {
+	if s.cacheDir == "" {
+		return nil
+	}
+	to := filepath.Join(s.cacheDir, getCombinedID(toKey, toID)+"_workflow.json")
+	if _, err := os.Stat(to); err == nil {
+		return nil
+	}
+	data, err := os.ReadFile(filepath.Join(s.cacheDir, getCombinedID(fromKey, fromID)+"_workflow.json"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return os.WriteFile(to, data, 0644)
+}
+
+// querySourceFile holds the JQL of a JQL source, which cannot be recovered
+// from its hash, and the labels and components it was narrowed to.
+type querySourceFile struct {
+	JQL string `json:"jql"`
+	jira.IssueFilter
+}
+
+func (s *Server) querySourcePath(id int) string {
+	return filepath.Join(s.cacheDir, getCombinedID(jqlSourceKey, id)+"_query.json")
+}
+
+func (s *Server) saveQuerySource(id int, f querySourceFile) error {
+	if s.cacheDir == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.querySourcePath(id), data, 0644)
+}
+
+func (s *Server) loadQuerySource(id int) (querySourceFile, error) {
+	if f, ok := s.querySources[id]; ok {
+		return f, nil
+	}
+	if s.cacheDir != "" {
+		data, err := os.ReadFile(s.querySourcePath(id))
+		if err == nil {
+			var f querySourceFile
+			if err := json.Unmarshal(data, &f); err != nil {
+				return querySourceFile{}, fmt.Errorf("invalid JQL source file for %s: %w", getCombinedID(jqlSourceKey, id), err)
+			}
+			s.querySources[id] = f
+			return f, nil
+		}
+		if !os.IsNotExist(err) {
+			return querySourceFile{}, err
+		}
+	}
+	return querySourceFile{}, fmt.Errorf("unknown JQL source %s; pass the jql again to register it", getCombinedID(jqlSourceKey, id))
+}
+
+// resolveQuerySource builds the source context of a FILTER or JQL source.
+// Unlike boards, the query is not anchored to a project, so cross-project
+// queries stay cross-project. The labels and components a JQL source was
+// narrowed to are handed to the event log for in-memory filtering.
+func (s *Server) resolveQuerySource(ctx context.Context, projectKey string, id int) (*jira.SourceContext, error) {
+	var query string
+	var filter jira.IssueFilter
+	var err error
+	if projectKey == filterSourceKey {
+		query, err = s.filterJQL(ctx, strconv.Itoa(id))
+	} else {
+		var f querySourceFile
+		f, err = s.loadQuerySource(id)
+		query, filter = f.JQL, f.IssueFilter
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !filter.IsZero() {
+		s.events.SetFilter(getCombinedID(projectKey, id), filter)
+	}
+	query, err = jira.NormalizeJQL(query)
+	if err != nil {
+		return nil, fmt.Errorf("source %s: %w", getCombinedID(projectKey, id), err)
+	}
+
+	if !s.ingestsSubtasks() {
+		query = jira.AndJQL(query, jira.NotSubTask)
+	}
+	return &jira.SourceContext{
+		ProjectKey: projectKey,
+		BoardID:    id,
+		JQL:        query,
+		Filter:     filter,
+		FetchedAt:  time.Now(),
+	}, nil
+}
+
+// filterJQL fetches the JQL of a saved Jira filter.
+func (s *Server) filterJQL(ctx context.Context, filterID string) (string, error) {
+	filter, err := s.jira.GetFilter(ctx, filterID)
+	if err != nil {
+		return "", err
+	}
+	fMap, ok := filter.(map[string]any)
+	if !ok {
+		return "", fmt.Errorf("invalid filter response format from Jira")
+	}
+	return asString(fMap["jql"]), nil
+}
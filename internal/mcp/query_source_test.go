@@ -0,0 +1,198 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/jira"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRegisterQuerySource(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	s := NewServer(&config.AppConfig{CacheDir: cacheDir}, &mockJiraClient{})
+
+	key, id, err := s.registerQuerySource(QuerySource{JQL: " labels = platform ORDER BY rank"})
+	if err != nil {
+		t.Fatalf("register jql: %v", err)
+	}
+	if key != jqlSourceKey || id <= 0 {
+		t.Fatalf("Expected a JQL source with a positive ID, got %s_%d", key, id)
+	}
+	if _, again, _ := s.registerQuerySource(QuerySource{JQL: "labels = platform"}); again != id {
+		t.Errorf("Expected ORDER BY and whitespace not to change the source ID, got %d and %d", id, again)
+	}
+
+	// A restarted server resolves the source from disk.
+	restarted := NewServer(&config.AppConfig{CacheDir: cacheDir}, &mockJiraClient{})
+	sc, err := restarted.resolveSourceContext(ctx, key, id)
+	if err != nil {
+		t.Fatalf("resolve persisted jql source: %v", err)
+	}
+	if sc.JQL != "(labels = platform) AND issuetype not in subtaskIssueTypes()" {
+		t.Errorf("Expected the query without project anchoring, got %q", sc.JQL)
+	}
+	if _, err := restarted.resolveSourceContext(ctx, jqlSourceKey, id+1); err == nil {
+		t.Error("Expected an error for an unknown JQL source")
+	}
+
+	if key, id, err := s.registerQuerySource(QuerySource{FilterID: "10042"}); err != nil || key != filterSourceKey || id != 10042 {
+		t.Errorf("Expected FILTER_10042, got %s_%d (%v)", key, id, err)
+	}
+	if _, _, err := s.registerQuerySource(QuerySource{FilterID: "mine"}); err == nil {
+		t.Error("Expected an error for a non-numeric filter_id")
+	}
+	if _, _, err := s.registerQuerySource(QuerySource{JQL: "labels = platform", FilterID: "10042"}); err == nil {
+		t.Error("Expected an error when both jql and filter_id are set")
+	}
+	if _, _, err := s.registerQuerySource(QuerySource{JQL: "labels = platform) OR (project = OTHER"}); err == nil {
+		t.Error("Expected an error for a jql escaping its parentheses")
+	}
+}
+
+func TestResolveSourceContext_Filter(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{jira: &mockJiraClient{
+		getFilter: func(id string) (any, error) {
+			if id != "10042" {
+				t.Errorf("Expected filter 10042, got %s", id)
+			}
+			return map[string]any{"jql": "project in (A, B) AND labels = platform ORDER BY created"}, nil
+		},
+		getBoard: func(id int) (any, error) {
+			t.Errorf("Did not expect a board lookup for a filter source")
+			return nil, nil
+		},
+	}}
+
+	sc, err := s.resolveSourceContext(ctx, filterSourceKey, 10042)
+	if err != nil {
+		t.Fatalf("resolve filter source: %v", err)
+	}
+	want := "(project in (A, B) AND labels = platform) AND issuetype not in subtaskIssueTypes()"
+	if sc.JQL != want || sc.ProjectKey != filterSourceKey || sc.BoardID != 10042 {
+		t.Errorf("Expected %q for FILTER_10042, got %q for %s_%d", want, sc.JQL, sc.ProjectKey, sc.BoardID)
+	}
+}
+
+func TestResolveSourceContext_UnsafeFilter(t *testing.T) {
+	ctx := context.Background()
+	s := &Server{jira: &mockJiraClient{
+		getFilter: func(id string) (any, error) {
+			return map[string]any{"jql": `project = A) OR (summary ~ "x`}, nil
+		},
+	}}
+	if _, err := s.resolveSourceContext(ctx, filterSourceKey, 10042); !errors.Is(err, jira.ErrUnsafeJQL) {
+		t.Errorf("Expected a malformed filter to be rejected, got %v", err)
+	}
+}
+
+func TestWithQuerySource(t *testing.T) {
+	s := NewServer(&config.AppConfig{}, &mockJiraClient{})
+	var got AnalyzeYieldInput
+	handler := withQuerySource(s, func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
+		got = args
+		return nil, nil, nil
+	})
+
+	if _, _, err := handler(context.Background(), nil, AnalyzeYieldInput{QuerySource: QuerySource{FilterID: "7"}}); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if got.ProjectKey != filterSourceKey || got.BoardID != 7 {
+		t.Errorf("Expected the input rewritten to FILTER_7, got %s_%d", got.ProjectKey, got.BoardID)
+	}
+
+	if _, _, err := handler(context.Background(), nil, AnalyzeYieldInput{ProjectKey: "PROJ", BoardID: 3}); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if got.ProjectKey != "PROJ" || got.BoardID != 3 {
+		t.Errorf("Expected a board input to pass through, got %s_%d", got.ProjectKey, got.BoardID)
+	}
+
+	res, _, _ := handler(context.Background(), nil, AnalyzeYieldInput{})
+	if res == nil || !res.IsError || !strings.Contains(res.Content[0].(*mcp.TextContent).Text, "project_key is required") {
+		t.Errorf("Expected an error result without project_key, jql, or filter_id, got %+v", res)
+	}
+}
+
+func TestScopeToFixVersion(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	s := NewServer(&config.AppConfig{CacheDir: cacheDir}, &mockJiraClient{})
+	if err := os.WriteFile(filepath.Join(cacheDir, "PROJ_0_workflow.json"), []byte(`{"commitment_point":"10"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got AnalyzeYieldInput
+	handler := withQuerySource(s, func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
+		got = args
+		return nil, nil, nil
+	})
+	if _, _, err := handler(context.Background(), nil, AnalyzeYieldInput{ProjectKey: "PROJ", QuerySource: QuerySource{FixVersion: ` 2.4 "beta" `}}); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if got.ProjectKey != jqlSourceKey {
+		t.Fatalf("Expected the release to be rewritten to a JQL source, got %s_%d", got.ProjectKey, got.BoardID)
+	}
+
+	sc, err := s.resolveSourceContext(ctx, got.ProjectKey, got.BoardID)
+	if err != nil {
+		t.Fatalf("resolve release source: %v", err)
+	}
+	if !strings.Contains(sc.JQL, `(project = "PROJ") AND fixVersion = "2.4 \"beta\""`) {
+		t.Errorf("Expected the project query narrowed to the release, got %q", sc.JQL)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, getCombinedID(got.ProjectKey, got.BoardID)+"_workflow.json"))
+	if err != nil || !strings.Contains(string(data), `"commitment_point":"10"`) {
+		t.Errorf("Expected the release to inherit the project's workflow, got %q (%v)", data, err)
+	}
+}
+
+func TestScopeToIssueFilter(t *testing.T) {
+	ctx := context.Background()
+	cacheDir := t.TempDir()
+	s := NewServer(&config.AppConfig{CacheDir: cacheDir}, &mockJiraClient{})
+	if err := os.WriteFile(filepath.Join(cacheDir, "PROJ_0_workflow.json"), []byte(`{"commitment_point":"10"}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var got AnalyzeYieldInput
+	handler := withQuerySource(s, func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
+		got = args
+		return nil, nil, nil
+	})
+	in := AnalyzeYieldInput{ProjectKey: "PROJ", QuerySource: QuerySource{Labels: []string{" payments ", "payments", ""}, Components: []string{"API"}}}
+	if _, _, err := handler(context.Background(), nil, in); err != nil {
+		t.Fatalf("handler: %v", err)
+	}
+	if got.ProjectKey != jqlSourceKey {
+		t.Fatalf("Expected the sub-team to be rewritten to a JQL source, got %s_%d", got.ProjectKey, got.BoardID)
+	}
+
+	// A restarted server recovers the filter from the persisted query.
+	restarted := NewServer(&config.AppConfig{CacheDir: cacheDir}, &mockJiraClient{})
+	sc, err := restarted.resolveSourceContext(ctx, got.ProjectKey, got.BoardID)
+	if err != nil {
+		t.Fatalf("resolve sub-team source: %v", err)
+	}
+	if !strings.Contains(sc.JQL, `(project = "PROJ") AND labels in ("payments") AND component in ("API")`) {
+		t.Errorf("Expected the project query narrowed to the sub-team, got %q", sc.JQL)
+	}
+	if !reflect.DeepEqual(sc.Filter, jira.IssueFilter{Labels: []string{"payments"}, Components: []string{"API"}}) {
+		t.Errorf("Expected the trimmed filter on the source context, got %+v", sc.Filter)
+	}
+
+	data, err := os.ReadFile(filepath.Join(cacheDir, getCombinedID(got.ProjectKey, got.BoardID)+"_workflow.json"))
+	if err != nil || !strings.Contains(string(data), `"commitment_point":"10"`) {
+		t.Errorf("Expected the sub-team to inherit the project's workflow, got %q (%v)", data, err)
+	}
+}
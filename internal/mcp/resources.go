@@ -0,0 +1,226 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+
+	"mcs-mcp/internal/render"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// Resource URIs. Event logs are the JSONL cache files of the hydrated
+// sources; results are the last response of each analytical tool, as the
+// JSON envelope or as CSV tables of its data.
+const (
+	eventsURIPrefix  = "mcs://events/"
+	resultsURIPrefix = "mcs://results/"
+)
+
+// sourceIDPattern guards event log reads against path traversal.
+var sourceIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// resultToolPrefixes are the tools whose last result is published as a resource.
+var resultToolPrefixes = []string{"analyze_", "forecast_", "generate_", "compare_"}
+
+// resourceRegistry publishes cached datasets as MCP resources. Concrete
+// resources are listed as they become available; the URI templates keep
+// every dataset readable by URI.
+type resourceRegistry struct {
+	srv        *mcp.Server
+	cacheDir   string
+	hideEvents bool // MCS_ANONYMIZE: event logs carry the original issue keys
+
+	mu      sync.Mutex
+	results map[string]json.RawMessage // tool name → last response envelope
+	listed  map[string]bool            // URIs already added to the server
+}
+
+func newResourceRegistry(srv *mcp.Server, cacheDir string) *resourceRegistry {
+	r := &resourceRegistry{
+		srv:      srv,
+		cacheDir: cacheDir,
+		results:  make(map[string]json.RawMessage),
+		listed:   make(map[string]bool),
+	}
+
+	srv.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "event_log",
+		Title:       "Event log",
+		Description: "Hydrated Jira event log of a source (PROJECTKEY_BOARDID or JQL_<id>), one JSON event per line.",
+		URITemplate: eventsURIPrefix + "{source_id}",
+		MIMEType:    "application/x-ndjson",
+	}, r.readEvents)
+	srv.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "tool_result_json",
+		Title:       "Last tool result (JSON)",
+		Description: "Response envelope of the last call of an analytical tool.",
+		URITemplate: resultsURIPrefix + "{tool}.json",
+		MIMEType:    "application/json",
+	}, r.readResult)
+	srv.AddResourceTemplate(&mcp.ResourceTemplate{
+		Name:        "tool_result_csv",
+		Title:       "Last tool result (CSV)",
+		Description: "Data of the last call of an analytical tool as CSV tables.",
+		URITemplate: resultsURIPrefix + "{tool}.csv",
+		MIMEType:    "text/csv",
+	}, r.readResult)
+
+	files, _ := filepath.Glob(filepath.Join(cacheDir, "*.jsonl"))
+	for _, file := range files {
+		r.publishEvents(strings.TrimSuffix(filepath.Base(file), ".jsonl"))
+	}
+	return r
+}
+
+// publish adds a resource to the server unless it is already listed.
+func (r *resourceRegistry) publish(res *mcp.Resource, h mcp.ResourceHandler) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.listed[res.URI] {
+		return
+	}
+	r.listed[res.URI] = true
+	r.srv.AddResource(res, h)
+}
+
+// publishEvents lists the event log of a source once its cache file exists.
+func (r *resourceRegistry) publishEvents(sourceID string) {
+	if r.hideEvents || !sourceIDPattern.MatchString(sourceID) {
+		return
+	}
+	info, err := os.Stat(r.eventsPath(sourceID))
+	if err != nil {
+		return
+	}
+	r.publish(&mcp.Resource{
+		URI:         eventsURIPrefix + sourceID,
+		Name:        "events_" + sourceID,
+		Title:       "Event log " + sourceID,
+		Description: "Hydrated Jira event log of " + sourceID + ", one JSON event per line.",
+		MIMEType:    "application/x-ndjson",
+		Size:        info.Size(),
+	}, r.readEvents)
+}
+
+// recordResult keeps the response envelope of an analytical tool and lists
+// its JSON and CSV renditions.
+func (r *resourceRegistry) recordResult(toolName string, envelope ResponseEnvelope) {
+	if !isResultTool(toolName) {
+		return
+	}
+	raw, err := json.Marshal(envelope)
+	if err != nil {
+		log.Warn().Err(err).Str("tool", toolName).Msg("Failed to serialize result resource")
+		return
+	}
+	r.mu.Lock()
+	r.results[toolName] = raw
+	r.mu.Unlock()
+
+	r.publish(&mcp.Resource{
+		URI:         resultsURIPrefix + toolName + ".json",
+		Name:        toolName + "_json",
+		Title:       "Last " + toolName + " result (JSON)",
+		Description: "Response envelope of the last " + toolName + " call.",
+		MIMEType:    "application/json",
+	}, r.readResult)
+	r.publish(&mcp.Resource{
+		URI:         resultsURIPrefix + toolName + ".csv",
+		Name:        toolName + "_csv",
+		Title:       "Last " + toolName + " result (CSV)",
+		Description: "Data of the last " + toolName + " call as CSV tables.",
+		MIMEType:    "text/csv",
+	}, r.readResult)
+}
+
+func isResultTool(toolName string) bool {
+	for _, prefix := range resultToolPrefixes {
+		if strings.HasPrefix(toolName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *resourceRegistry) eventsPath(sourceID string) string {
+	return filepath.Join(r.cacheDir, sourceID+".jsonl")
+}
+
+func (r *resourceRegistry) readEvents(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	sourceID := strings.TrimPrefix(uri, eventsURIPrefix)
+	if r.hideEvents || !sourceIDPattern.MatchString(sourceID) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	data, err := os.ReadFile(r.eventsPath(sourceID))
+	if os.IsNotExist(err) {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("read event log %s: %w", sourceID, err)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{{
+		URI:      uri,
+		MIMEType: "application/x-ndjson",
+		Text:     string(data),
+	}}}, nil
+}
+
+func (r *resourceRegistry) readResult(_ context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+	uri := req.Params.URI
+	name := strings.TrimPrefix(uri, resultsURIPrefix)
+	ext := filepath.Ext(name)
+	toolName := strings.TrimSuffix(name, ext)
+
+	r.mu.Lock()
+	raw, ok := r.results[toolName]
+	r.mu.Unlock()
+	if !ok {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	contents := &mcp.ResourceContents{URI: uri}
+	switch ext {
+	case ".json":
+		contents.MIMEType = "application/json"
+		contents.Text = string(raw)
+	case ".csv":
+		var envelope struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.Unmarshal(raw, &envelope); err != nil {
+			return nil, fmt.Errorf("decode %s result: %w", toolName, err)
+		}
+		text, err := render.Render(envelope.Data, render.CSV)
+		if err != nil {
+			return nil, fmt.Errorf("render %s result as CSV: %w", toolName, err)
+		}
+		contents.MIMEType = "text/csv"
+		contents.Text = text
+	default:
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+	return &mcp.ReadResourceResult{Contents: []*mcp.ResourceContents{contents}}, nil
+}
+
+// publishResources records the result of a tool call and lists the event log
+// of the active source, when the server is wired to an MCP server.
+func (s *Server) publishResources(toolName string, data any) {
+	if s.resources == nil {
+		return
+	}
+	if envelope, ok := data.(ResponseEnvelope); ok {
+		s.resources.recordResult(toolName, envelope)
+	}
+	if s.activeSourceID != "" {
+		s.resources.publishEvents(s.activeSourceID)
+	}
+}
@@ -0,0 +1,88 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestResources_EventLogsAndResults(t *testing.T) {
+	srv := newGoldenServer(t)
+	mcpSrv, err := NewMCPServer(srv, "test")
+	if err != nil {
+		t.Fatalf("NewMCPServer: %v", err)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := mcpSrv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	defer ss.Close()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{})
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer cs.Close()
+
+	if caps := cs.InitializeResult().Capabilities; caps.Resources == nil {
+		t.Fatal("Expected the resources capability in the initialize response")
+	}
+
+	eventsURI := eventsURIPrefix + testSourceID
+	listed, err := cs.ListResources(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListResources: %v", err)
+	}
+	if len(listed.Resources) != 1 || listed.Resources[0].URI != eventsURI || listed.Resources[0].Size == 0 {
+		t.Fatalf("Expected the cached event log as the only resource, got %+v", listed.Resources)
+	}
+	events, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: eventsURI})
+	if err != nil {
+		t.Fatalf("ReadResource(%s): %v", eventsURI, err)
+	}
+	var first map[string]any
+	if line, _, _ := strings.Cut(events.Contents[0].Text, "\n"); json.Unmarshal([]byte(line), &first) != nil {
+		t.Errorf("Expected JSON lines, got %q", line)
+	}
+
+	for _, uri := range []string{eventsURIPrefix + "../secrets", resultsURIPrefix + "analyze_throughput.csv"} {
+		if _, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: uri}); err == nil {
+			t.Errorf("Expected %s to be not found", uri)
+		}
+	}
+
+	res, err := cs.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "analyze_throughput",
+		Arguments: map[string]any{"project_key": testProject, "board_id": testBoard},
+	})
+	if err != nil || res.IsError {
+		t.Fatalf("analyze_throughput failed: %v %+v", err, res)
+	}
+
+	result, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: resultsURIPrefix + "analyze_throughput.json"})
+	if err != nil {
+		t.Fatalf("ReadResource(json): %v", err)
+	}
+	var envelope map[string]any
+	if err := json.Unmarshal([]byte(result.Contents[0].Text), &envelope); err != nil || envelope["data"] == nil {
+		t.Errorf("Expected the response envelope, got %q", result.Contents[0].Text)
+	}
+	csv, err := cs.ReadResource(ctx, &mcp.ReadResourceParams{URI: resultsURIPrefix + "analyze_throughput.csv"})
+	if err != nil {
+		t.Fatalf("ReadResource(csv): %v", err)
+	}
+	if c := csv.Contents[0]; c.MIMEType != "text/csv" || !strings.Contains(c.Text, ",") {
+		t.Errorf("Expected CSV data, got %s %q", c.MIMEType, c.Text)
+	}
+
+	listed, _ = cs.ListResources(ctx, nil)
+	if len(listed.Resources) != 3 {
+		t.Errorf("Expected the event log and two result resources, got %+v", listed.Resources)
+	}
+}
@@ -0,0 +1,45 @@
+package mcp
+
+import (
+	"context"
+
+	"mcs-mcp/internal/render"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// resultFormatted is implemented by tool inputs that embed ResultFormat.
+type resultFormatted interface {
+	resultFormatOption() render.Format
+}
+
+func (f ResultFormat) resultFormatOption() render.Format { return f.Format }
+
+// withResultFormat validates the format parameter of a tool input and makes
+// it the output format for the duration of the call.
+func withResultFormat[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		rf, ok := any(args).(resultFormatted)
+		if !ok || rf.resultFormatOption() == "" {
+			return handler(ctx, req, args)
+		}
+		format, err := render.ParseFormat(string(rf.resultFormatOption()))
+		if err != nil {
+			return formatToolError(err), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.format = format }), req, args)
+	}
+}
+
+// resultFormat returns the format tool results are rendered in: the format
+// parameter of the call, else the format source setting, else the
+// MCS_OUTPUT_FORMAT setting.
+func (s *Server) resultFormat(ctx context.Context) render.Format {
+	if f := callStateOf(ctx).format; f != "" {
+		return f
+	}
+	if s.activeSettings.Format != "" {
+		return s.activeSettings.Format
+	}
+	return s.outputFormat
+}
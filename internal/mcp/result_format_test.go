@@ -0,0 +1,39 @@
+package mcp
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/render"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWithResultFormat(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(&config.AppConfig{CacheDir: t.TempDir(), OutputFormat: render.Markdown}, &mockJiraClient{})
+	handler := withResultFormat(s, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
+		return formatToolResult(ctx, s, map[string]any{"rows": []map[string]int{{"delivered": 3}}}), nil, nil
+	})
+	text := func(res *mcp.CallToolResult) string { return res.Content[0].(*mcp.TextContent).Text }
+
+	res, _, _ := handler(context.Background(), nil, AnalyzeYieldInput{})
+	if !strings.Contains(text(res), "### rows\n\n| delivered |") {
+		t.Errorf("Expected the server default (markdown), got %q", text(res))
+	}
+
+	res, _, _ = handler(context.Background(), nil, AnalyzeYieldInput{ResultFormat: ResultFormat{Format: render.CSV}})
+	if text(res) != "delivered\n3\n" {
+		t.Errorf("Expected the format parameter to override the default, got %q", text(res))
+	}
+	if s.resultFormat(ctx) != render.Markdown {
+		t.Errorf("Expected the call format to be reset after the call, got %q", s.resultFormat(ctx))
+	}
+
+	res, _, _ = handler(context.Background(), nil, AnalyzeYieldInput{ResultFormat: ResultFormat{Format: "xml"}})
+	if !res.IsError {
+		t.Errorf("Expected an error result for an unsupported format, got %q", text(res))
+	}
+}
@@ -10,7 +10,11 @@ func TestSchemaForAllToolInputs(t *testing.T) {
 		fn   func() error
 	}{
 		{"AnalyzeCycleTimeInput", func() error { _, err := schemaFor[AnalyzeCycleTimeInput](); return err }},
+		{"AnalyzeCycleTimeScatterInput", func() error { _, err := schemaFor[AnalyzeCycleTimeScatterInput](); return err }},
 		{"AnalyzeThroughputInput", func() error { _, err := schemaFor[AnalyzeThroughputInput](); return err }},
+		{"AnalyzeThroughputStreamsInput", func() error { _, err := schemaFor[AnalyzeThroughputStreamsInput](); return err }},
+		{"AnalyzeReleaseLagInput", func() error { _, err := schemaFor[AnalyzeReleaseLagInput](); return err }},
+		{"AnalyzeSprintHistoryInput", func() error { _, err := schemaFor[AnalyzeSprintHistoryInput](); return err }},
 		{"AnalyzeProcessStabilityInput", func() error { _, err := schemaFor[AnalyzeProcessStabilityInput](); return err }},
 		{"AnalyzeFlowDebtInput", func() error { _, err := schemaFor[AnalyzeFlowDebtInput](); return err }},
 		{"GenerateCFDDataInput", func() error { _, err := schemaFor[GenerateCFDDataInput](); return err }},
@@ -21,10 +25,31 @@ func TestSchemaForAllToolInputs(t *testing.T) {
 		{"AnalyzeWorkItemAgeInput", func() error { _, err := schemaFor[AnalyzeWorkItemAgeInput](); return err }},
 		{"AnalyzeProcessEvolutionInput", func() error { _, err := schemaFor[AnalyzeProcessEvolutionInput](); return err }},
 		{"AnalyzeYieldInput", func() error { _, err := schemaFor[AnalyzeYieldInput](); return err }},
+		{"AnalyzeDefinitionOfWorkflowInput", func() error { _, err := schemaFor[AnalyzeDefinitionOfWorkflowInput](); return err }},
 		{"ForecastMonteCarloInput", func() error { _, err := schemaFor[ForecastMonteCarloInput](); return err }},
+		{"ForecastScenariosInput", func() error { _, err := schemaFor[ForecastScenariosInput](); return err }},
+		{"ForecastEpicInput", func() error { _, err := schemaFor[ForecastEpicInput](); return err }},
+		{"CompareForecastsInput", func() error { _, err := schemaFor[CompareForecastsInput](); return err }},
+		{"RecordCommitmentInput", func() error { _, err := schemaFor[RecordCommitmentInput](); return err }},
+		{"AnalyzeCommitmentHealthInput", func() error { _, err := schemaFor[AnalyzeCommitmentHealthInput](); return err }},
+		{"ImportPortfolioInput", func() error { _, err := schemaFor[ImportPortfolioInput](); return err }},
 		{"ForecastBacktestInput", func() error { _, err := schemaFor[ForecastBacktestInput](); return err }},
+		{"AnalyzeReleaseBurnupInput", func() error { _, err := schemaFor[AnalyzeReleaseBurnupInput](); return err }},
+		{"ForecastItemInput", func() error { _, err := schemaFor[ForecastItemInput](); return err }},
+		{"ImportHistoryStatusInput", func() error { _, err := schemaFor[ImportHistoryStatusInput](); return err }},
+		{"ForecastBurnUpInput", func() error { _, err := schemaFor[ForecastBurnUpInput](); return err }},
+		{"AnalyzeThroughputHistogramInput", func() error { _, err := schemaFor[AnalyzeThroughputHistogramInput](); return err }},
+		{"WorkflowSetTypeAliasesInput", func() error { _, err := schemaFor[WorkflowSetTypeAliasesInput](); return err }},
+		{"SetSLEInput", func() error { _, err := schemaFor[SetSLEInput](); return err }},
+		{"AnalyzeSLEComplianceInput", func() error { _, err := schemaFor[AnalyzeSLEComplianceInput](); return err }},
+		{"AnalyzeFlowEfficiencyInput", func() error { _, err := schemaFor[AnalyzeFlowEfficiencyInput](); return err }},
+		{"AnalyzeReworkInput", func() error { _, err := schemaFor[AnalyzeReworkInput](); return err }},
+		{"SetWIPLimitsInput", func() error { _, err := schemaFor[SetWIPLimitsInput](); return err }},
+		{"WorkflowGetAuditInput", func() error { _, err := schemaFor[WorkflowGetAuditInput](); return err }},
+		{"AnalyzeWIPLimitsInput", func() error { _, err := schemaFor[AnalyzeWIPLimitsInput](); return err }},
 		{"SetAnalysisWindowInput", func() error { _, err := schemaFor[SetAnalysisWindowInput](); return err }},
 		{"GetAnalysisWindowInput", func() error { _, err := schemaFor[GetAnalysisWindowInput](); return err }},
+		{"SetVisualPreferencesInput", func() error { _, err := schemaFor[SetVisualPreferencesInput](); return err }},
 	}
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
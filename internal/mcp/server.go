@@ -11,12 +11,15 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"mcs-mcp/internal/chartbuf"
 	"mcs-mcp/internal/config"
 	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/i18n"
 	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/render"
 	"mcs-mcp/internal/simulation"
 	"mcs-mcp/internal/stats"
 	"mcs-mcp/internal/discovery"
@@ -26,60 +29,130 @@ import (
 )
 
 type Server struct {
-	jira                  jira.Client
-	events                *eventlog.LogProvider
-	cacheDir              string
-	activeSourceID        string
-	activeMapping         map[string]stats.StatusMetadata
-	activeResolutions     map[string]string
-	activeStatusOrder     []string
-	activeCommitmentPoint string
-	activeDiscoveryCutoff *time.Time
-	activeEvaluationDate  *time.Time
-	activeWindowStart     *time.Time
-	activeWindowEnd       *time.Time
+	jira                    jira.Client           // client of the active Jira instance
+	events                  *eventlog.LogProvider // event cache of the active Jira instance
+	cacheDir                string                // cache directory of the active Jira instance
+	instances               map[string]*jiraInstance
+	activeInstance          string
+	activeSourceID          string
+	activeMapping           map[string]stats.StatusMetadata
+	activeResolutions       map[string]string
+	activeStatusOrder       []string
+	activeCommitmentPoint   string
+	activeTypeCommitments   map[string]string                        // Issue type → commitment point status ID override
+	activeSLEs              map[string]stats.ServiceLevelExpectation // Issue type (or stats.AllIssueTypes) → recorded SLE
+	activeWIPLimits         map[string]stats.WIPLimit                // WIPLimit.Key() → recorded WIP limit
+	activeAlertRules        map[string]stats.AlertRule               // Rule name → recorded alert rule
+	activeTypeAliases       simulation.TypeAliases                   // Issue type → canonical type it is forecast as
+	activeSettings          SourceSettings                           // Per-source parameter defaults (set_source_settings)
+	activeForecastTemplates map[string]ForecastTemplate              // Template name → forecast parameters (save_forecast_template)
+	activeDiscoveryCutoff   *time.Time
+	activeMappingConfirmed  *time.Time // When workflow_set_mapping last stored the mapping
+	activeEvaluationDate    *time.Time
+	activeWindowStart       *time.Time
+	activeWindowEnd         *time.Time
 	activeRegistry          *jira.NameRegistry
 	commitmentBackflowReset bool
 	simulationSeed          int64 // 0 = random (production); non-zero = fixed seed (tests)
 	engineRegistry          *simulation.Registry
-	engineName              string         // from MCS_ENGINE: "crude", "bbak", "auto"
-	engineWeights           map[string]int // from MCS_ENGINE_<NAME>
-	activeBoardName         string         // human-readable board name from Jira API
-	activeProjectName       string         // human-readable project name from Jira API
+	engineName              string                // from MCS_ENGINE: "crude", "bbak", "auto"
+	engineWeights           map[string]int        // from MCS_ENGINE_<NAME>
+	calendar                *simulation.Calendar  // from MCS_WORKDAYS, MCS_HOLIDAYS, MCS_FREEZE_PERIODS; nil = not configured
+	outputFormat            render.Format         // from MCS_OUTPUT_FORMAT; "" = JSON
+	maxResponseBytes        int                   // from MCS_MAX_RESPONSE_BYTES; 0 = no budget
+	anonymizer              *anonymizer           // from MCS_ANONYMIZE; nil = results show issue keys and names
+	locale                  i18n.Locale           // from MCS_LOCALE, or the locale the client announced at initialize
+	subtaskPolicy           stats.SubtaskPolicy   // from MCS_SUBTASK_POLICY; anything but exclude ingests sub-tasks
+	outlierPolicy           stats.OutlierPolicy   // from MCS_OUTLIER_POLICY; "" = none
+	containerPolicy         stats.ContainerPolicy // from MCS_CONTAINER_POLICY; "" = exclude
+	duplicatePolicy         stats.DuplicatePolicy // from MCS_PORTFOLIO_DUPLICATES; "" = first
+	readOnly                bool                  // from MCS_READ_ONLY: configuration tools are not registered
+	enabledTools            map[string]bool       // from MCS_ENABLED_TOOLS; nil = all tools
+	enableMermaidCharts     bool                  // session toggle of Mermaid diagrams in responses (set_visual_preferences)
+	activeBoardName         string                // human-readable board name from Jira API
+	activeProjectName       string                // human-readable project name from Jira API
 	chartBuf                *chartbuf.Buffer
 	httpPort                int
+	querySources            map[int]querySourceFile // JQL of the JQL_<id> sources seen this session
+	resources               *resourceRegistry       // MCP resources of cached datasets; nil without an MCP server
+	sessions                *sessionCache           // projected sessions of recent tool calls, from INGESTION_CACHE_TTL; nil = off
+	localeMu                sync.Mutex
 }
 
-func (s *Server) Clock() time.Time {
+// Clock returns the date analyses are evaluated at: the as_of_date of the
+// call ctx belongs to, else the evaluation date, else now.
+func (s *Server) Clock(ctx context.Context) time.Time {
+	if asOf := callAsOfDate(ctx); asOf != nil {
+		return *asOf
+	}
 	if s.activeEvaluationDate != nil {
 		return *s.activeEvaluationDate
 	}
 	return time.Now()
 }
 
+// sessionClock is the clock of the event store: the evaluation date, else
+// now. The as_of_date of a call bounds the events of the call through the end
+// of its window instead (see Window).
+func (s *Server) sessionClock() time.Time {
+	return s.Clock(context.Background())
+}
+
 // DefaultWindowWeeks is the lazy-default lookback for the session analysis window.
 const DefaultWindowWeeks = 26
 
-// Window returns the effective [start, end] session analysis window.
-// If unset, returns the lazy default [Clock()-DefaultWindowWeeks, Clock()].
+// Window returns the effective [start, end] analysis window: the session
+// window, narrowed by the history window parameters of the call ctx belongs
+// to. If unset, returns the lazy default [Clock()-DefaultWindowWeeks, Clock()];
+// the window_weeks source setting replaces DefaultWindowWeeks.
 // The third return value is true when the window is explicitly set by the user.
-func (s *Server) Window() (start, end time.Time, explicit bool) {
+func (s *Server) Window(ctx context.Context) (start, end time.Time, explicit bool) {
+	start, end, explicit = s.sessionWindow(ctx)
+	if o := callStateOf(ctx).window; o != nil {
+		start, end = o.apply(start, end)
+		explicit = true
+	}
+	// No window reaches past the as_of_date of the call.
+	if asOf := callAsOfDate(ctx); asOf != nil && end.After(*asOf) {
+		end = *asOf
+	}
+	return start, end, explicit
+}
+
+// sessionWindow returns the session analysis window, ignoring call overrides.
+func (s *Server) sessionWindow(ctx context.Context) (start, end time.Time, explicit bool) {
 	if s.activeWindowStart != nil && s.activeWindowEnd != nil {
-		return *s.activeWindowStart, *s.activeWindowEnd, true
+		start, end = *s.activeWindowStart, *s.activeWindowEnd
+		// An as_of_date before the window end moves the window back to it.
+		if asOf := callAsOfDate(ctx); asOf != nil && end.After(*asOf) {
+			start, end = asOf.Add(-end.Sub(start)), *asOf
+		}
+		return start, end, true
+	}
+	end = s.Clock(ctx)
+	weeks := DefaultWindowWeeks
+	if s.activeSettings.WindowWeeks > 0 {
+		weeks = s.activeSettings.WindowWeeks
 	}
-	end = s.Clock()
-	return end.AddDate(0, 0, -DefaultWindowWeeks*7), end, false
+	return end.AddDate(0, 0, -weeks*7), end, false
 }
 
 // AnalysisWindow returns a fully-resolved stats.AnalysisWindow for the given
 // bucket size, using the session window for [start, end] and activeCutoff for
 // steady-state clamping. Convenience wrapper around Window() +
 // stats.NewAnalysisWindow that every diagnostic handler should use.
-func (s *Server) AnalysisWindow(bucket string) stats.AnalysisWindow {
-	start, end, _ := s.Window()
+func (s *Server) AnalysisWindow(ctx context.Context, bucket string) stats.AnalysisWindow {
+	start, end, _ := s.Window(ctx)
 	return stats.NewAnalysisWindow(start, end, bucket, s.activeCutoff())
 }
 
+// AlignedWindow is AnalysisWindow with buckets aligned as alignment says
+// (calendar when empty).
+func (s *Server) AlignedWindow(ctx context.Context, bucket string, alignment BucketAlignment) (stats.AnalysisWindow, error) {
+	start, end, _ := s.Window(ctx)
+	return stats.NewAlignedWindow(start, end, bucket, string(alignment), s.activeCutoff())
+}
+
 func NewServer(cfg *config.AppConfig, jiraClient jira.Client) *Server {
 	reg := simulation.NewRegistry()
 	reg.Register(&simulation.CrudeEngine{})
@@ -101,15 +174,36 @@ func NewServer(cfg *config.AppConfig, jiraClient jira.Client) *Server {
 		engineRegistry:          reg,
 		engineName:              engineName,
 		engineWeights:           engineWeights,
+		calendar:                cfg.Calendar,
+		outputFormat:            cfg.OutputFormat,
+		maxResponseBytes:        cfg.MaxResponseBytes,
+		locale:                  cfg.Locale,
+		subtaskPolicy:           cfg.SubtaskPolicy,
+		outlierPolicy:           cfg.OutlierPolicy,
+		containerPolicy:         cfg.ContainerPolicy,
+		duplicatePolicy:         cfg.PortfolioDuplicates,
+		readOnly:                cfg.ReadOnly,
+		querySources:            make(map[int]querySourceFile),
+		instances:               make(map[string]*jiraInstance),
+		activeInstance:          config.DefaultInstance,
+		sessions:                newSessionCache(time.Duration(cfg.IngestionCacheTTL) * time.Minute),
 	}
 
+	if len(cfg.EnabledTools) > 0 {
+		s.enabledTools = make(map[string]bool, len(cfg.EnabledTools))
+		for _, name := range cfg.EnabledTools {
+			s.enabledTools[name] = true
+		}
+	}
 	if cfg.ChartsBufferSize > 0 {
 		s.chartBuf = chartbuf.NewBuffer(cfg.ChartsBufferSize)
 	}
+	if cfg.Anonymize {
+		s.anonymizer = newAnonymizer(cfg.AnonymizeSalt)
+	}
 
-	store := eventlog.NewEventStore(s.Clock)
-	s.events = eventlog.NewLogProvider(jiraClient, store, cfg.CacheDir,
-		cfg.IngestionUpdatedLookback, cfg.IngestionCreatedLookback, cfg.IngestionMaxItems)
+	s.events = s.addInstance(cfg, config.DefaultInstance, jiraClient, cfg.CacheDir).events
+	s.addNamedInstances(cfg)
 
 	return s
 }
@@ -149,13 +243,21 @@ func (s *Server) workflowJSON() json.RawMessage {
 	}
 
 	wf := map[string]any{
-		"board_id":           boardID,
-		"project_key":        projectKey,
-		"board_name":         s.activeBoardName,
-		"project_name":       s.activeProjectName,
-		"status_order":       s.activeStatusOrder,
-		"status_order_names": statusOrderNames,
-		"commitment_point":   s.activeCommitmentPoint,
+		"board_id":                  boardID,
+		"project_key":               projectKey,
+		"board_name":                s.activeBoardName,
+		"project_name":              s.activeProjectName,
+		"status_order":              s.activeStatusOrder,
+		"status_order_names":        statusOrderNames,
+		"commitment_point":          s.activeCommitmentPoint,
+		"commitment_points_by_type": s.activeTypeCommitments,
+		"type_aliases":              s.activeTypeAliases,
+	}
+
+	if s.anonymizer != nil {
+		for _, k := range redactedContextKeys {
+			delete(wf, k)
+		}
 	}
 
 	out, _ := json.Marshal(wf)
@@ -168,25 +270,32 @@ func NewMCPServer(s *Server, version string) (*mcp.Server, error) {
 		Name:    "mcs-mcp",
 		Version: version,
 	}, &mcp.ServerOptions{
-		Instructions: serverInstructions,
+		Instructions:       s.instructions(),
+		InitializedHandler: s.adoptClientLocale,
 	})
+	if err := s.checkEnabledTools(); err != nil {
+		return nil, err
+	}
 	if err := registerTools(mcpSrv, s); err != nil {
 		return nil, fmt.Errorf("register tools: %w", err)
 	}
+	registerPrompts(mcpSrv)
+	s.resources = newResourceRegistry(mcpSrv, s.cacheDir)
+	s.resources.hideEvents = s.anonymizer != nil
 	return mcpSrv, nil
 }
 
-// Run starts the MCP server on the stdio transport.
+// Run starts the MCP server on the stdio transport (see stdioTransport).
 func (s *Server) Run(ctx context.Context, version string) error {
 	mcpSrv, err := NewMCPServer(s, version)
 	if err != nil {
 		return err
 	}
-	return mcpSrv.Run(ctx, &mcp.StdioTransport{})
+	return mcpSrv.Run(ctx, newStdioTransport(os.Stdin, os.Stdout))
 }
 
 // handleImportProjects searches for Jira projects, injecting mock data for MCSTEST.
-func (s *Server) handleImportProjects(query string) (any, error) {
+func (s *Server) handleImportProjects(ctx context.Context, query string) (any, error) {
 	var projects []any
 
 	isMockQuery := strings.Contains(strings.ToUpper(query), "MCSTEST") || query == ""
@@ -198,7 +307,7 @@ func (s *Server) handleImportProjects(query string) (any, error) {
 	}
 
 	if strings.ToUpper(query) != "MCSTEST" {
-		jiraProjects, err := s.jira.FindProjects(query)
+		jiraProjects, err := s.jira.FindProjects(ctx, query)
 		if err == nil {
 			projects = append(projects, jiraProjects...)
 		} else if !isMockQuery {
@@ -215,7 +324,7 @@ func (s *Server) handleImportProjects(query string) (any, error) {
 }
 
 // handleImportBoards searches for Jira boards, injecting mock data for MCSTEST.
-func (s *Server) handleImportBoards(projectKey, nameFilter string) (any, error) {
+func (s *Server) handleImportBoards(ctx context.Context, projectKey, nameFilter string) (any, error) {
 	var boards []any
 
 	isMockKey := strings.ToUpper(projectKey) == "MCSTEST" || projectKey == ""
@@ -228,7 +337,7 @@ func (s *Server) handleImportBoards(projectKey, nameFilter string) (any, error)
 	}
 
 	if strings.ToUpper(projectKey) != "MCSTEST" {
-		jiraBoards, err := s.jira.FindBoards(projectKey, nameFilter)
+		jiraBoards, err := s.jira.FindBoards(ctx, projectKey, nameFilter)
 		if err == nil {
 			boards = append(boards, jiraBoards...)
 		} else if !isMockKey {
@@ -245,27 +354,46 @@ func (s *Server) handleImportBoards(projectKey, nameFilter string) (any, error)
 }
 
 type WorkflowMetadata struct {
-	SourceID        string                          `json:"source_id"`
-	Mapping         map[string]stats.StatusMetadata `json:"mapping"`
-	Resolutions     map[string]string               `json:"resolutions,omitempty"`
-	StatusOrder     []string                        `json:"status_order,omitempty"`
-	CommitmentPoint string                          `json:"commitment_point,omitempty"`
-	DiscoveryCutoff *time.Time                      `json:"discovery_cutoff,omitempty"`
-	EvaluationDate  *time.Time                      `json:"evaluation_date,omitempty"`
-	NameRegistry    *jira.NameRegistry              `json:"name_registry,omitempty"`
+	SourceID             string                                   `json:"source_id"`
+	Mapping              map[string]stats.StatusMetadata          `json:"mapping"`
+	Resolutions          map[string]string                        `json:"resolutions,omitempty"`
+	StatusOrder          []string                                 `json:"status_order,omitempty"`
+	CommitmentPoint      string                                   `json:"commitment_point,omitempty"`
+	TypeCommitmentPoints map[string]string                        `json:"type_commitment_points,omitempty"` // Issue type → status ID
+	SLEs                 map[string]stats.ServiceLevelExpectation `json:"sles,omitempty"`                   // Issue type → recorded SLE
+	WIPLimits            map[string]stats.WIPLimit                `json:"wip_limits,omitempty"`             // WIPLimit.Key() → WIP limit
+	AlertRules           map[string]stats.AlertRule               `json:"alert_rules,omitempty"`            // Rule name → alert rule
+	TypeAliases          simulation.TypeAliases                   `json:"type_aliases,omitempty"`           // Issue type → canonical type
+	Settings             *SourceSettings                          `json:"settings,omitempty"`               // Per-source parameter defaults
+	ForecastTemplates    map[string]ForecastTemplate              `json:"forecast_templates,omitempty"`     // Template name → forecast parameters
+	DiscoveryCutoff      *time.Time                               `json:"discovery_cutoff,omitempty"`
+	MappingConfirmedAt   *time.Time                               `json:"mapping_confirmed_at,omitempty"` // Statuses entered later are reported as a workflow change
+	EvaluationDate       *time.Time                               `json:"evaluation_date,omitempty"`
+	NameRegistry         *jira.NameRegistry                       `json:"name_registry,omitempty"`
 }
 
 func (s *Server) saveWorkflow(projectKey string, boardID int) error {
 	sourceID := getCombinedID(projectKey, boardID)
 	meta := WorkflowMetadata{
-		SourceID:        sourceID,
-		Mapping:         s.activeMapping,
-		Resolutions:     s.activeResolutions,
-		StatusOrder:     s.activeStatusOrder,
-		CommitmentPoint: s.activeCommitmentPoint,
-		DiscoveryCutoff: s.activeDiscoveryCutoff,
-		EvaluationDate:  s.activeEvaluationDate,
-		NameRegistry:    s.activeRegistry,
+		SourceID:             sourceID,
+		Mapping:              s.activeMapping,
+		Resolutions:          s.activeResolutions,
+		StatusOrder:          s.activeStatusOrder,
+		CommitmentPoint:      s.activeCommitmentPoint,
+		TypeCommitmentPoints: s.activeTypeCommitments,
+		SLEs:                 s.activeSLEs,
+		WIPLimits:            s.activeWIPLimits,
+		AlertRules:           s.activeAlertRules,
+		TypeAliases:          s.activeTypeAliases,
+		ForecastTemplates:    s.activeForecastTemplates,
+		DiscoveryCutoff:      s.activeDiscoveryCutoff,
+		MappingConfirmedAt:   s.activeMappingConfirmed,
+		EvaluationDate:       s.activeEvaluationDate,
+		NameRegistry:         s.activeRegistry,
+	}
+	if !s.activeSettings.isZero() {
+		settings := s.activeSettings
+		meta.Settings = &settings
 	}
 
 	path := filepath.Join(s.cacheDir, fmt.Sprintf("%s_%d_workflow.json", projectKey, boardID))
@@ -308,6 +436,7 @@ func (s *Server) loadWorkflow(projectKey string, boardID int) (bool, error) {
 	s.activeStatusOrder = meta.StatusOrder
 	s.activeCommitmentPoint = meta.CommitmentPoint
 	s.activeDiscoveryCutoff = meta.DiscoveryCutoff
+	s.activeMappingConfirmed = meta.MappingConfirmedAt
 	s.activeEvaluationDate = meta.EvaluationDate
 	s.activeRegistry = meta.NameRegistry
 
@@ -327,6 +456,16 @@ func (s *Server) loadWorkflow(projectKey string, boardID int) (bool, error) {
 	if cp := s.activeRegistry.GetStatusID(s.activeCommitmentPoint); cp != "" {
 		s.activeCommitmentPoint = cp
 	}
+	s.activeTypeCommitments = s.resolveTypeCommitments(meta.TypeCommitmentPoints)
+	s.activeSLEs = meta.SLEs
+	s.activeWIPLimits = meta.WIPLimits
+	s.activeAlertRules = meta.AlertRules
+	s.activeTypeAliases = meta.TypeAliases
+	s.activeSettings = SourceSettings{}
+	if meta.Settings != nil {
+		s.activeSettings = *meta.Settings
+	}
+	s.activeForecastTemplates = meta.ForecastTemplates
 
 	// Migration: If mappings/resolutions are name-based, try to convert them to IDs
 	// for internal stability (Analytical Guardrail).
@@ -372,15 +511,7 @@ func (s *Server) anchorContext(projectKey string, boardID int) error {
 	log.Info().Str("prev", s.activeSourceID).Str("next", sourceID).Msg("Switching active context")
 
 	// 1. Clear old active state
-	s.activeSourceID = ""
-	s.activeMapping = nil
-	s.activeResolutions = nil
-	s.activeStatusOrder = nil
-	s.activeCommitmentPoint = ""
-	s.activeEvaluationDate = nil
-	s.activeWindowStart = nil
-	s.activeWindowEnd = nil
-	s.activeRegistry = nil
+	s.clearActiveContext()
 
 	// 2. Prune EventStore RAM
 	s.events.PruneExcept(sourceID)
@@ -399,17 +530,106 @@ func (s *Server) anchorContext(projectKey string, boardID int) error {
 	}
 
 	s.activeSourceID = sourceID
+	if err := s.saveActiveContext(projectKey, boardID); err != nil {
+		log.Warn().Err(err).Str("source", sourceID).Msg("Failed to record active context")
+	}
 	return nil
 }
 
-func (s *Server) recalculateDiscoveryCutoff(sourceID string) {
+// clearActiveContext drops the anchored board and its workflow state.
+func (s *Server) clearActiveContext() {
+	s.activeSourceID = ""
+	s.activeMapping = nil
+	s.activeResolutions = nil
+	s.activeStatusOrder = nil
+	s.activeCommitmentPoint = ""
+	s.activeTypeCommitments = nil
+	s.activeSLEs = nil
+	s.activeWIPLimits = nil
+	s.activeAlertRules = nil
+	s.activeTypeAliases = nil
+	s.activeSettings = SourceSettings{}
+	s.activeForecastTemplates = nil
+	s.activeMappingConfirmed = nil
+	s.activeEvaluationDate = nil
+	s.activeWindowStart = nil
+	s.activeWindowEnd = nil
+	s.activeRegistry = nil
+}
+
+// activeContextFile records the last anchored project/board so that a
+// restarted server resumes with its confirmed workflow already loaded. It is
+// kept in the cache directory of the default instance.
+const activeContextFile = "active_context.json"
+
+type activeContext struct {
+	ProjectKey string `json:"project_key"`
+	BoardID    int    `json:"board_id"`
+	Instance   string `json:"instance,omitempty"` // Named Jira instance; empty = default
+}
+
+func (s *Server) saveActiveContext(projectKey string, boardID int) error {
+	cacheDir := s.cacheDir
+	if inst, ok := s.instances[config.DefaultInstance]; ok {
+		cacheDir = inst.cacheDir
+	}
+	if cacheDir == "" {
+		return nil
+	}
+	ac := activeContext{ProjectKey: projectKey, BoardID: boardID, Instance: s.namedInstance()}
+	data, err := json.Marshal(ac)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(cacheDir, activeContextFile)
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// RestoreActiveContext re-anchors the project/board that was active when the
+// server last ran, loading its persisted mapping, resolutions, status order,
+// and commitment points. A missing or unreadable record leaves the server
+// unanchored.
+func (s *Server) RestoreActiveContext() {
+	if s.cacheDir == "" {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(s.cacheDir, activeContextFile))
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Warn().Err(err).Msg("Failed to read active context")
+		}
+		return
+	}
+	var ac activeContext
+	if err := json.Unmarshal(data, &ac); err != nil || ac.ProjectKey == "" || ac.BoardID == 0 {
+		log.Warn().Err(err).Msg("Ignoring malformed active context")
+		return
+	}
+	if ac.Instance != "" {
+		if err := s.useInstance(ac.Instance); err != nil {
+			log.Warn().Err(err).Msg("Failed to restore active context")
+			return
+		}
+	}
+	if err := s.anchorContext(ac.ProjectKey, ac.BoardID); err != nil {
+		log.Warn().Err(err).Str("project", ac.ProjectKey).Int("board", ac.BoardID).Msg("Failed to restore active context")
+		return
+	}
+	log.Info().Str("source", s.activeSourceID).Bool("mapped", len(s.activeMapping) > 0).Msg("Restored active context from previous run")
+}
+
+func (s *Server) recalculateDiscoveryCutoff(ctx context.Context, sourceID string) {
 	if s.activeMapping == nil {
 		return
 	}
 
-	window := stats.NewAnalysisWindow(time.Time{}, s.Clock(), "day", time.Time{})
-	events := s.events.GetIssuesInRange(sourceID, window.Start, window.End)
-	domainIssues, _, _, _ := stats.ProjectScope(events, window, s.activeCommitmentPoint, s.activeMapping, s.activeResolutions, nil)
+	window := stats.NewAnalysisWindow(time.Time{}, s.Clock(ctx), "day", time.Time{})
+	histories := s.events.IssuesInRange(sourceID, window.Start, window.End)
+	domainIssues, _, _, _ := stats.ProjectHistories(histories, window, s.activeCommitmentPoint, s.activeMapping, s.activeResolutions, nil)
 
 	finishedMap := make(map[string]bool)
 	for name, meta := range s.activeMapping {
@@ -0,0 +1,137 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"mcs-mcp/internal/stats"
+)
+
+// maxCachedSessions bounds the projected sessions kept by sessionCache; the
+// oldest entry is evicted first.
+const maxCachedSessions = 32
+
+// sessionKey identifies a projection: the same events, window, and workflow
+// configuration always project to the same issues.
+type sessionKey struct {
+	instance   string
+	sourceID   string
+	jql        string
+	start, end int64 // UnixMicro of the snapped window bounds
+	subtasks   stats.SubtaskPolicy
+	containers stats.ContainerPolicy
+	outcomes   stats.OutcomeScope
+	workflow   uint64 // hash of the status mapping and resolutions
+	events     int    // event count of the source; changes with every ingestion
+	latest     int64  // latest event timestamp of the source
+}
+
+type cachedSession struct {
+	session *stats.AnalysisSession
+	created time.Time
+}
+
+// sessionCache keeps the projected analysis sessions of recent tool calls, so
+// a conversation that asks several tools about the same board and window
+// reconstructs the issues from the event log once. Entries expire after ttl
+// (INGESTION_CACHE_TTL), are bypassed as soon as the event log of their source
+// changes, and are dropped by import_history_update. A nil cache or a zero ttl
+// disables caching.
+type sessionCache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[sessionKey]cachedSession
+}
+
+func newSessionCache(ttl time.Duration) *sessionCache {
+	if ttl <= 0 {
+		return nil
+	}
+	return &sessionCache{ttl: ttl, entries: make(map[sessionKey]cachedSession)}
+}
+
+// get returns a copy of the cached session of key, or nil when there is none
+// or it has expired.
+func (c *sessionCache) get(key sessionKey) *stats.AnalysisSession {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	if time.Since(e.created) > c.ttl {
+		delete(c.entries, key)
+		return nil
+	}
+	return e.session.Clone()
+}
+
+// put caches the projection of session under key and returns a copy of it
+// for the caller.
+func (c *sessionCache) put(key sessionKey, session *stats.AnalysisSession) *stats.AnalysisSession {
+	cached := session.Clone()
+	if c == nil {
+		return cached
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if len(c.entries) >= maxCachedSessions {
+		var oldest sessionKey
+		var oldestAt time.Time
+		for k, e := range c.entries {
+			if oldestAt.IsZero() || e.created.Before(oldestAt) {
+				oldest, oldestAt = k, e.created
+			}
+		}
+		delete(c.entries, oldest)
+	}
+	c.entries[key] = cachedSession{session: session, created: time.Now()}
+	return cached
+}
+
+// invalidate drops the cached sessions of a source.
+func (c *sessionCache) invalidate(sourceID string) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for k := range c.entries {
+		if k.sourceID == sourceID {
+			delete(c.entries, k)
+		}
+	}
+}
+
+// sessionKey returns the cache key of the session openSession builds for
+// hctx and window.
+func (s *Server) sessionKey(ctx context.Context, hctx *handlerContext, window stats.AnalysisWindow) sessionKey {
+	return sessionKey{
+		instance:   s.activeInstance,
+		sourceID:   hctx.SourceID,
+		jql:        hctx.Ctx.JQL,
+		start:      window.Start.UnixMicro(),
+		end:        window.End.UnixMicro(),
+		subtasks:   s.subtasks(ctx),
+		containers: s.containers(ctx),
+		outcomes:   s.outcomes(ctx),
+		workflow:   workflowHash(s.activeMapping, s.activeResolutions),
+		events:     s.events.GetEventCount(hctx.SourceID),
+		latest:     s.events.GetLatestTimestamp(hctx.SourceID).UnixMicro(),
+	}
+}
+
+// workflowHash fingerprints the status mapping and resolutions a projection
+// depends on. encoding/json sorts map keys, so equal maps hash equally.
+func workflowHash(mapping map[string]stats.StatusMetadata, resolutions map[string]string) uint64 {
+	h := fnv.New64a()
+	_ = json.NewEncoder(h).Encode(mapping)
+	_ = json.NewEncoder(h).Encode(resolutions)
+	return h.Sum64()
+}
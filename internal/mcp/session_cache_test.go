@@ -0,0 +1,62 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/stats"
+)
+
+func TestOpenSession_Cache(t *testing.T) {
+	ctx := context.Background()
+	srv := newGoldenServer(t)
+	srv.sessions = newSessionCache(time.Minute)
+
+	hctx, err := srv.prepareHandler(ctx, testProject, testBoard)
+	if err != nil {
+		t.Fatalf("prepareHandler: %v", err)
+	}
+	window := srv.AnalysisWindow(ctx, "day")
+
+	first := srv.openSession(ctx, hctx, window).GetAllIssues()
+	if len(first) == 0 {
+		t.Fatal("Expected issues in the analysis window")
+	}
+	if len(srv.sessions.entries) != 1 {
+		t.Fatalf("Expected the projection to be cached, got %d entries", len(srv.sessions.entries))
+	}
+	want := first[0].Key
+	first[0] = first[len(first)-1] // callers may reorder their copy
+
+	second := srv.openSession(ctx, hctx, window).GetAllIssues()
+	if len(second) != len(first) || second[0].Key != want {
+		t.Errorf("Expected the cached projection unchanged by an earlier caller, got %d issues starting with %s", len(second), second[0].Key)
+	}
+	if len(srv.sessions.entries) != 1 {
+		t.Errorf("Expected a cache hit for the same window, got %d entries", len(srv.sessions.entries))
+	}
+
+	srv.subtaskPolicy = stats.SubtasksInclude
+	srv.openSession(ctx, hctx, window)
+	if len(srv.sessions.entries) != 2 {
+		t.Errorf("Expected another subtask policy to project again, got %d entries", len(srv.sessions.entries))
+	}
+
+	srv.sessions.invalidate(testSourceID)
+	if len(srv.sessions.entries) != 0 {
+		t.Errorf("Expected invalidate to drop the source's projections, got %d entries", len(srv.sessions.entries))
+	}
+
+	srv.openSession(ctx, hctx, window)
+	for k, e := range srv.sessions.entries {
+		e.created = time.Now().Add(-2 * time.Minute)
+		srv.sessions.entries[k] = e
+		if srv.sessions.get(k) != nil {
+			t.Errorf("Expected an expired projection to be ignored")
+		}
+	}
+	if len(srv.sessions.entries) != 0 {
+		t.Errorf("Expected the expired projection to be dropped, got %d entries", len(srv.sessions.entries))
+	}
+}
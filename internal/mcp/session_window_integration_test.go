@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 )
@@ -12,12 +13,14 @@ import (
 // sample window — so we assert it does NOT inherit the diagnostic window
 // (its sampling is independent of session state).
 func TestSessionWindow_PropagationAcrossDiagnostics(t *testing.T) {
+	ctx := context.Background()
 	srv := newGoldenServer(t)
 
-	wantEnd := srv.Clock().UTC()
+	wantEnd := srv.Clock(ctx).UTC()
 	wantStart := wantEnd.AddDate(0, 0, -60)
 
 	if _, err := srv.handleSetAnalysisWindow(
+		ctx,
 		wantStart.Format("2006-01-02"),
 		wantEnd.Format("2006-01-02"),
 		0, false,
@@ -25,7 +28,7 @@ func TestSessionWindow_PropagationAcrossDiagnostics(t *testing.T) {
 		t.Fatalf("set_analysis_window: %v", err)
 	}
 
-	got, err := srv.handleGetAnalysisWindow()
+	got, err := srv.handleGetAnalysisWindow(ctx)
 	if err != nil {
 		t.Fatalf("get_analysis_window: %v", err)
 	}
@@ -54,25 +57,25 @@ func TestSessionWindow_PropagationAcrossDiagnostics(t *testing.T) {
 		{
 			"analyze_throughput",
 			func() (any, error) {
-				return srv.handleGetDeliveryCadence(testProject, testBoard, "week", false)
+				return srv.handleGetDeliveryCadence(ctx, testProject, testBoard, "week", "", false, "")
 			},
 		},
 		{
 			"analyze_wip_stability",
 			func() (any, error) {
-				return srv.handleAnalyzeWIPStability(testProject, testBoard)
+				return srv.handleAnalyzeWIPStability(ctx, testProject, testBoard, false)
 			},
 		},
 		{
 			"analyze_cycle_time",
 			func() (any, error) {
-				return srv.handleGetCycleTimeAssessment(testProject, testBoard, "", "", nil, 0, 0)
+				return srv.handleGetCycleTimeAssessment(ctx, testProject, testBoard, "", "", nil, 0, 0, false, "")
 			},
 		},
 		{
 			"analyze_flow_debt",
 			func() (any, error) {
-				return srv.handleGetFlowDebt(testProject, testBoard, "week")
+				return srv.handleGetFlowDebt(ctx, testProject, testBoard, "week", "")
 			},
 		},
 	}
@@ -86,7 +89,7 @@ func TestSessionWindow_PropagationAcrossDiagnostics(t *testing.T) {
 			if err != nil {
 				t.Fatalf("%s: %v", tc.name, err)
 			}
-			res = srv.injectSessionContext(res)
+			res = srv.injectSessionContext(ctx, res)
 			env, ok := res.(ResponseEnvelope)
 			if !ok {
 				t.Fatalf("%s: expected ResponseEnvelope, got %T", tc.name, res)
@@ -113,18 +116,16 @@ func TestSessionWindow_PropagationAcrossDiagnostics(t *testing.T) {
 	// duration_days session window so that a forecast forced to inherit it
 	// would lack throughput data — and inspect the simulation's reported
 	// sample range instead.
-	if _, err := srv.handleSetAnalysisWindow("", "", 5, false); err != nil {
+	if _, err := srv.handleSetAnalysisWindow(ctx, "", "", 5, false); err != nil {
 		t.Fatalf("set_analysis_window narrow: %v", err)
 	}
 
-	res, err := srv.handleRunSimulation(
-		testProject, testBoard,
-		"scope",
-		false, 0, 60, "",
-		"", nil, false,
-		0, "", "",
-		nil, nil,
-	)
+	res, err := srv.handleRunSimulation(ctx, ForecastMonteCarloInput{
+		ProjectKey: testProject,
+		BoardID:    testBoard,
+		Mode:       "scope",
+		TargetDays: 60,
+	})
 	if err != nil {
 		t.Fatalf("forecast_monte_carlo: %v", err)
 	}
@@ -0,0 +1,161 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	"mcs-mcp/internal/stats"
+)
+
+// setupTool and setupClient identify the setup command in the audit trail.
+const (
+	setupTool   = "setup"
+	setupClient = "mcs-mcp setup"
+)
+
+// SetupBoard is a board offered by the setup command.
+type SetupBoard struct {
+	ID   int
+	Name string
+	Type string // kanban, scrum, or simple
+}
+
+// SetupStatus is one status of a proposed workflow mapping.
+type SetupStatus struct {
+	ID      string
+	Name    string
+	Tier    string
+	Role    string
+	Outcome string
+}
+
+// SetupProposal is the workflow mapping that discovery proposes for a board,
+// in status order, as the setup command presents it for confirmation.
+type SetupProposal struct {
+	ProjectKey      string
+	BoardID         int
+	Statuses        []SetupStatus
+	CommitmentPoint string            // Status ID
+	Resolutions     map[string]string // Resolution name → outcome
+	Issues          int               // Issues in the event log
+	Confirmed       bool              // A confirmed mapping exists and would be replaced
+}
+
+// CommitmentPointName returns the name of the proposed commitment point.
+func (p SetupProposal) CommitmentPointName() string {
+	for _, st := range p.Statuses {
+		if st.ID == p.CommitmentPoint {
+			return st.Name
+		}
+	}
+	return p.CommitmentPoint
+}
+
+// CheckConnection tests the connection to a Jira instance ("" for the default
+// one) and returns the number of projects visible to the configured account.
+func (s *Server) CheckConnection(ctx context.Context, instance string) (int, error) {
+	ctx = directContext(ctx, setupTool, setupClient, nil)
+	if err := s.useInstance(instance); err != nil {
+		return 0, err
+	}
+	projects, err := s.jira.FindProjects(ctx, "")
+	if err != nil {
+		return 0, fmt.Errorf("cannot reach Jira: %w", err)
+	}
+	return len(projects), nil
+}
+
+// SetupBoards lists the boards of a project visible to the configured account.
+func (s *Server) SetupBoards(ctx context.Context, projectKey string) ([]SetupBoard, error) {
+	ctx = directContext(ctx, setupTool, setupClient, nil)
+	found, err := s.jira.FindBoards(ctx, projectKey, "")
+	if err != nil {
+		return nil, err
+	}
+	boards := make([]SetupBoard, 0, len(found))
+	for _, b := range found {
+		m, ok := b.(map[string]any)
+		if !ok {
+			continue
+		}
+		id, ok := m["id"].(float64)
+		if !ok {
+			continue
+		}
+		boards = append(boards, SetupBoard{ID: int(id), Name: asString(m["name"]), Type: asString(m["type"])})
+	}
+	slices.SortFunc(boards, func(a, b SetupBoard) int { return a.ID - b.ID })
+	return boards, nil
+}
+
+// ProposeSetup ingests the history of a board and returns the workflow mapping
+// discovery proposes for it, as workflow_discover_mapping with force_refresh
+// does. Nothing is confirmed until ConfirmSetup.
+func (s *Server) ProposeSetup(ctx context.Context, projectKey string, boardID int, progress func(fetched, total int, message string)) (SetupProposal, error) {
+	ctx = directContext(ctx, setupTool, setupClient, progress)
+	cached, _ := s.loadWorkflow(projectKey, boardID)
+	confirmed := cached && len(s.activeMapping) > 0
+	res, err := s.handleGetWorkflowDiscovery(ctx, projectKey, boardID, true)
+	if err != nil {
+		return SetupProposal{}, err
+	}
+	if err := ctx.Err(); err != nil {
+		return SetupProposal{}, err
+	}
+
+	env, ok := res.(ResponseEnvelope)
+	data, _ := env.Data.(map[string]any)
+	workflow, _ := data["workflow"].(map[string]any)
+	mapping, _ := workflow["status_mapping"].(map[string]stats.StatusMetadata)
+	if !ok || len(mapping) == 0 {
+		return SetupProposal{}, fmt.Errorf("no workflow could be discovered for %s: the board has no issue history yet", getCombinedID(projectKey, boardID))
+	}
+	summary, _ := data["data_summary"].(stats.MetadataSummary)
+
+	p := SetupProposal{
+		ProjectKey:      projectKey,
+		BoardID:         boardID,
+		CommitmentPoint: summary.RecommendedCommitmentPoint,
+		Issues:          summary.Whole.TotalItems,
+		Confirmed:       confirmed,
+	}
+	if resolutions, ok := workflow["proposed_resolutions"].(map[string]string); ok {
+		p.Resolutions = resolutions
+	}
+	order, _ := workflow["status_order"].([]string)
+	for _, id := range order {
+		if meta, ok := mapping[id]; ok {
+			p.Statuses = append(p.Statuses, SetupStatus{ID: id, Name: meta.Name, Tier: meta.Tier, Role: meta.Role, Outcome: meta.Outcome})
+			delete(mapping, id)
+		}
+	}
+	// Statuses outside the discovered order follow it, sorted by ID.
+	for _, id := range slices.Sorted(maps.Keys(mapping)) {
+		meta := mapping[id]
+		p.Statuses = append(p.Statuses, SetupStatus{ID: id, Name: meta.Name, Tier: meta.Tier, Role: meta.Role, Outcome: meta.Outcome})
+	}
+	return p, nil
+}
+
+// ConfirmSetup persists a proposal as the confirmed workflow mapping and
+// status order of its board, as workflow_set_mapping and workflow_set_order do.
+func (s *Server) ConfirmSetup(ctx context.Context, p SetupProposal) error {
+	ctx = directContext(ctx, setupTool, setupClient, nil)
+	mapping := make(map[string]any, len(p.Statuses))
+	order := make([]string, 0, len(p.Statuses))
+	for _, st := range p.Statuses {
+		mapping[st.ID] = map[string]any{"tier": st.Tier, "role": st.Role, "outcome": st.Outcome}
+		order = append(order, st.ID)
+	}
+	resolutions := make(map[string]any, len(p.Resolutions))
+	for name, outcome := range p.Resolutions {
+		resolutions[name] = outcome
+	}
+	if _, err := s.handleSetWorkflowMapping(ctx, p.ProjectKey, p.BoardID, mapping, resolutions, p.CommitmentPoint, nil); err != nil {
+		return err
+	}
+	_, err := s.handleSetWorkflowOrder(ctx, p.ProjectKey, p.BoardID, order)
+	return err
+}
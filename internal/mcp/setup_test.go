@@ -0,0 +1,49 @@
+package mcp
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"mcs-mcp/internal/config"
+)
+
+func TestSetup(t *testing.T) {
+	srv := newGoldenServer(t)
+	srv.activeMapping = nil // A board nobody has configured yet
+	ctx := context.Background()
+
+	p, err := srv.ProposeSetup(ctx, testProject, testBoard, nil)
+	if err != nil {
+		t.Fatalf("ProposeSetup: %v", err)
+	}
+	if len(p.Statuses) == 0 || p.CommitmentPoint == "" || p.Confirmed {
+		t.Fatalf("Expected a new proposal with statuses and a commitment point, got %+v", p)
+	}
+	if p.CommitmentPointName() == p.CommitmentPoint {
+		t.Errorf("Expected the commitment point %s to resolve to a status name", p.CommitmentPoint)
+	}
+
+	if err := srv.ConfirmSetup(ctx, p); err != nil {
+		t.Fatalf("ConfirmSetup: %v", err)
+	}
+	reloaded := NewServer(&config.AppConfig{CacheDir: srv.cacheDir}, &DummyClient{})
+	if ok, err := reloaded.loadWorkflow(testProject, testBoard); !ok || err != nil {
+		t.Fatalf("Expected a persisted workflow, got %v (%v)", ok, err)
+	}
+	if len(reloaded.activeMapping) != len(p.Statuses) || reloaded.activeCommitmentPoint != p.CommitmentPoint {
+		t.Errorf("Expected the proposal persisted, got %d statuses and commitment point %q", len(reloaded.activeMapping), reloaded.activeCommitmentPoint)
+	}
+	order := make([]string, len(p.Statuses))
+	for i, st := range p.Statuses {
+		order[i] = st.ID
+	}
+	if !slices.Equal(reloaded.activeStatusOrder, order) {
+		t.Errorf("Expected the status order %v, got %v", order, reloaded.activeStatusOrder)
+	}
+
+	again, err := reloaded.ProposeSetup(ctx, testProject, testBoard, nil)
+	if err != nil || !again.Confirmed {
+		t.Errorf("Expected the next proposal to note the confirmed mapping, got %+v (%v)", again.Confirmed, err)
+	}
+}
@@ -0,0 +1,60 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// subtaskPolicied is implemented by tool inputs that embed SubtaskOption.
+type subtaskPolicied interface {
+	subtaskPolicyOption() stats.SubtaskPolicy
+}
+
+func (o SubtaskOption) subtaskPolicyOption() stats.SubtaskPolicy { return o.SubtaskPolicy }
+
+// withSubtaskPolicy validates the subtask_policy parameter of a tool input
+// and makes it the subtask policy for the duration of the call. Including or
+// rolling up sub-tasks needs them in the history, which only
+// MCS_SUBTASK_POLICY other than exclude fetches.
+func withSubtaskPolicy[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (*mcp.CallToolResult, any, error) {
+		sp, ok := any(args).(subtaskPolicied)
+		if !ok || sp.subtaskPolicyOption() == "" {
+			return handler(ctx, req, args)
+		}
+		policy, err := stats.ParseSubtaskPolicy(string(sp.subtaskPolicyOption()))
+		if err != nil {
+			return formatToolError(err), nil, nil
+		}
+		if policy != stats.SubtasksExclude && !s.ingestsSubtasks() {
+			return formatToolError(fmt.Errorf("subtask_policy %q needs sub-tasks in the history, but they are not fetched: set MCS_SUBTASK_POLICY to include or rollup and re-import the board", policy)), nil, nil
+		}
+		return handler(withCallOption(ctx, func(st *callState) { st.subtasks = policy }), req, args)
+	}
+}
+
+// subtasks returns the subtask policy of analyses: the subtask_policy
+// parameter of the call, else the subtask_policy source setting, else the
+// MCS_SUBTASK_POLICY setting. A source setting that needs sub-tasks is
+// ignored while they are not fetched.
+func (s *Server) subtasks(ctx context.Context) stats.SubtaskPolicy {
+	if p := callStateOf(ctx).subtasks; p != "" {
+		return p
+	}
+	if p := s.activeSettings.SubtaskPolicy; p == stats.SubtasksExclude || (p != "" && s.ingestsSubtasks()) {
+		return p
+	}
+	if s.subtaskPolicy == "" {
+		return stats.SubtasksExclude
+	}
+	return s.subtaskPolicy
+}
+
+// ingestsSubtasks reports whether sub-tasks are fetched into the event log.
+func (s *Server) ingestsSubtasks() bool {
+	return s.subtaskPolicy != "" && s.subtaskPolicy != stats.SubtasksExclude
+}
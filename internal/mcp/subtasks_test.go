@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"context"
+	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/stats"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestWithSubtaskPolicy(t *testing.T) {
+	ctx := context.Background()
+	var seen stats.SubtaskPolicy
+	wrap := func(s *Server) func(context.Context, *mcp.CallToolRequest, AnalyzeCycleTimeInput) (*mcp.CallToolResult, any, error) {
+		return withSubtaskPolicy(s, func(ctx context.Context, _ *mcp.CallToolRequest, _ AnalyzeCycleTimeInput) (*mcp.CallToolResult, any, error) {
+			seen = s.subtasks(ctx)
+			return formatToolResult(ctx, s, nil), nil, nil
+		})
+	}
+	call := func(s *Server, policy stats.SubtaskPolicy) *mcp.CallToolResult {
+		res, _, _ := wrap(s)(context.Background(), nil, AnalyzeCycleTimeInput{SubtaskOption: SubtaskOption{SubtaskPolicy: policy}})
+		return res
+	}
+
+	excluding := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &mockJiraClient{})
+	if res := call(excluding, ""); res.IsError || seen != stats.SubtasksExclude {
+		t.Errorf("Expected the default policy exclude, got %q", seen)
+	}
+	if res := call(excluding, stats.SubtasksRollup); !res.IsError {
+		t.Error("Expected rollup to fail when sub-tasks are not fetched")
+	}
+
+	ingesting := NewServer(&config.AppConfig{CacheDir: t.TempDir(), SubtaskPolicy: stats.SubtasksRollup}, &mockJiraClient{})
+	if res := call(ingesting, ""); res.IsError || seen != stats.SubtasksRollup {
+		t.Errorf("Expected the server policy rollup, got %q", seen)
+	}
+	if res := call(ingesting, stats.SubtasksInclude); res.IsError || seen != stats.SubtasksInclude {
+		t.Errorf("Expected the call policy include, got %q", seen)
+	}
+	if res := call(ingesting, "parent"); !res.IsError {
+		t.Error("Expected an unknown policy to fail")
+	}
+	if got := ingesting.subtasks(ctx); got != stats.SubtasksRollup {
+		t.Errorf("Expected the call policy to be reset after the call, got %q", got)
+	}
+}
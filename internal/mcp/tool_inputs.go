@@ -1,6 +1,9 @@
 package mcp
 
-import "mcs-mcp/internal/stats"
+import (
+	"mcs-mcp/internal/render"
+	"mcs-mcp/internal/stats"
+)
 
 // --- Enum types ---
 
@@ -12,6 +15,85 @@ const (
 	SimModeScope    SimulationMode = "scope"
 )
 
+// StreamDimension represents the attribute used to split throughput into delivery streams.
+type StreamDimension string
+
+const (
+	StreamByComponent StreamDimension = "component"
+	StreamByEpic      StreamDimension = "epic"
+	StreamByLabel     StreamDimension = "label"
+)
+
+// InterferenceDimension represents the attribute whose streams are correlated
+// by analyze_interference.
+type InterferenceDimension string
+
+const (
+	InterferenceByIssueType InterferenceDimension = "issue_type"
+	InterferenceByComponent InterferenceDimension = "component"
+)
+
+// GroupDimension represents the attribute used to segment throughput and
+// cycle time into groups (see grouping.go).
+type GroupDimension string
+
+const (
+	GroupByAssignee  GroupDimension = "assignee"
+	GroupByTeam      GroupDimension = "team"
+	GroupByComponent GroupDimension = "component"
+)
+
+// BucketAlignment represents how time buckets line up with the calendar.
+type BucketAlignment string
+
+const (
+	AlignCalendar BucketAlignment = stats.AlignCalendar
+	AlignRolling  BucketAlignment = stats.AlignRolling
+)
+
+// BacktestPrecision represents the accuracy-vs-speed tradeoff of a backtest.
+type BacktestPrecision string
+
+const (
+	PrecisionStandard BacktestPrecision = "standard"
+	PrecisionFast     BacktestPrecision = "fast"
+)
+
+// SamplingMode represents how a forecast samples historical throughput.
+type SamplingMode string
+
+const (
+	SamplingEmpirical  SamplingMode = "empirical"
+	SamplingParametric SamplingMode = "parametric"
+)
+
+// StratificationMode represents whether a forecast samples issue types as
+// streams of their own.
+type StratificationMode string
+
+const (
+	StratificationAuto StratificationMode = "auto"
+	StratificationOn   StratificationMode = "on"
+	StratificationOff  StratificationMode = "off"
+)
+
+// SampleGranularity represents whether a forecast samples throughput per
+// day or per week.
+type SampleGranularity string
+
+const (
+	SampleByDay  SampleGranularity = "day"
+	SampleByWeek SampleGranularity = "week"
+)
+
+// ForecastUnits represents what a forecast counts.
+type ForecastUnits string
+
+const (
+	UnitsItems  ForecastUnits = "items"
+	UnitsPoints ForecastUnits = "points"
+)
+
 // AgeType represents the type of age calculation.
 type AgeType string
 
@@ -32,6 +114,18 @@ const (
 	TierFilterAll        TierFilter = "All"
 )
 
+// ItemSort represents the order of the rows of an item-level result before
+// it is paged (see pagination.go).
+type ItemSort string
+
+const (
+	SortByAge        ItemSort = "age"
+	SortByPercentile ItemSort = "percentile"
+	SortByCycleTime  ItemSort = "cycle_time"
+	SortByDate       ItemSort = "date"
+	SortByKey        ItemSort = "key"
+)
+
 // DiagnosticGoal represents the analytical goal for the diagnostic roadmap.
 type DiagnosticGoal string
 
@@ -86,38 +180,147 @@ type StatusMappingEntry struct {
 
 // --- Input structs ---
 
+// QuerySource is an alternative to project_key/board_id for analyzing an
+// arbitrary JQL or saved filter without a board (see query_source.go).
+type QuerySource struct {
+	JQL      string `json:"jql,omitempty" jsonschema:"Optional: analyze the issues of this JQL instead of a board (e.g. a cross-project label query). Replaces project_key and board_id; ORDER BY is ignored."`
+	FilterID string `json:"filter_id,omitempty" jsonschema:"Optional: analyze the issues of this saved Jira filter instead of a board. Replaces project_key and board_id."`
+	// FixVersion narrows any of the sources above to one release.
+	FixVersion string `json:"fix_version,omitempty" jsonschema:"Optional: restrict the analysis to the issues of this fixVersion (release name, e.g. 2.4.0). Narrows project_key/board_id, jql, or filter_id; the release inherits the confirmed workflow of the source it narrows."`
+	// Labels and Components narrow any of the sources above to a sub-team.
+	Labels     []string `json:"labels,omitempty" jsonschema:"Optional: restrict the analysis to issues carrying any of these labels (exact, case-sensitive). Narrows the source like fix_version and inherits its confirmed workflow."`
+	Components []string `json:"components,omitempty" jsonschema:"Optional: restrict the analysis to issues in any of these components (by name). Combined with labels, an issue must match both."`
+}
+
+// JiraInstance selects one of the configured Jira connections for the
+// import_* tools; the selection stays active for later calls (see instances.go).
+type JiraInstance struct {
+	Instance string `json:"instance,omitempty" jsonschema:"Optional: the named Jira connection to use from now on (from JIRA_INSTANCES, e.g. cloud or dc; 'default' is the JIRA_URL connection). Switching instances drops the active board context. Default: the instance in use."`
+}
+
+// HistoryWindow lets the windowed diagnostics narrow the historical baseline
+// of one call without changing the session window (see history_window.go).
+type HistoryWindow struct {
+	HistoryWindowDays int    `json:"history_window_days,omitempty" jsonschema:"Optional: analyze only the last N days up to the window end, for this call only. Narrow to 30–60 days after a process change. Mutually exclusive with history_start_date. Default: the session analysis window."`
+	HistoryStartDate  string `json:"history_start_date,omitempty" jsonschema:"Optional: start of the historical baseline for this call (YYYY-MM-DD)."`
+	HistoryEndDate    string `json:"history_end_date,omitempty" jsonschema:"Optional: end of the historical baseline for this call (YYYY-MM-DD). Default: the end of the session analysis window."`
+}
+
+// SubtaskOption lets the cycle-time, throughput, and forecasting tools choose
+// how sub-tasks enter the analysis for one call (see subtasks.go).
+type SubtaskOption struct {
+	SubtaskPolicy stats.SubtaskPolicy `json:"subtask_policy,omitempty" jsonschema:"Optional: 'exclude' leaves sub-tasks out, 'include' counts them as items of their own, 'rollup' leaves them out but starts a parent's clock when its first sub-task started. Default: the subtask_policy source setting, else the server setting MCS_SUBTASK_POLICY (exclude)."`
+}
+
+// ContainerOption lets the cycle-time, throughput, forecasting, and export
+// tools choose whether container items count as work for one call (see
+// containers.go).
+type ContainerOption struct {
+	Containers stats.ContainerPolicy `json:"containers,omitempty" jsonschema:"Optional: 'exclude' leaves container items (epics, initiatives, and other issues that are the parent of other items) out of throughput, cycle times, WIP, and forecast scope, since they group work rather than flow; 'include' counts them as items of their own. Default: the server setting MCS_CONTAINER_POLICY (exclude)."`
+}
+
+// OutcomeOption lets the cycle-time, throughput, and forecasting tools choose
+// which finished items count as done for one call (see outcomes.go).
+type OutcomeOption struct {
+	OutcomeScope stats.OutcomeScope `json:"outcome_scope,omitempty" jsonschema:"Optional: which finished items count as done. 'delivered' (default) counts delivered items only, the basis of SLEs and of forecasts of delivered value; 'abandoned' counts abandoned items only; 'all' counts every finished item, the capacity the system processed whatever the outcome. Outcomes come from the resolutions and Finished statuses of the workflow mapping."`
+}
+
+// OutlierOption lets the cycle-time and forecasting tools choose how extreme
+// cycle times or throughput days are treated for one call (see outliers.go).
+type OutlierOption struct {
+	Outliers stats.OutlierPolicy `json:"outliers,omitempty" jsonschema:"Optional: 'none' keeps every sample, 'winsorize' caps samples above the P99 at the P99, 'iqr' drops samples outside the IQR fences (Q1 − 1.5·IQR, Q3 + 1.5·IQR). Applies to cycle times in analyze_cycle_time and to daily throughput in forecasts; the number of trimmed items is reported in context.outliers. Default: the outliers source setting, else the server setting MCS_OUTLIER_POLICY (none)."`
+}
+
+// AsOfOption lets analyze_work_item_age, analyze_throughput, and
+// forecast_monte_carlo reproduce a past state for one call (see as_of.go).
+type AsOfOption struct {
+	AsOfDate string `json:"as_of_date,omitempty" jsonschema:"Optional: evaluate this call as of a past date (YYYY-MM-DD), from the history known on that date only. Reproduces e.g. what a forecast said in January for a retrospective. Unlike workflow_set_evaluation_date, it applies to this call only. Default: the evaluation date, else today."`
+}
+
+// ResultFormat lets the tools with tabular results choose how their result is
+// rendered (see result_format.go).
+type ResultFormat struct {
+	Format render.Format `json:"format,omitempty" jsonschema:"Optional: render the result as 'json' (default), 'markdown' (tables, for humans reading the transcript), or 'csv' (tables for spreadsheets). Defaults to the format source setting, else the server setting MCS_OUTPUT_FORMAT."`
+}
+
+// PageOption lets the tools with item-level results return one page of their
+// items, in a chosen order (see pagination.go).
+type PageOption struct {
+	Limit  int      `json:"limit,omitempty" jsonschema:"Optional: return at most this many items of the item list. The result then carries 'page' with the total item count, the items returned, and next_offset for the following page. Summaries, bands, and actions always cover every item. Default: all items."`
+	Offset int      `json:"offset,omitempty" jsonschema:"Optional: skip this many items of the (sorted) item list, e.g. the next_offset of the previous page. Default: 0."`
+	SortBy ItemSort `json:"sort_by,omitempty" jsonschema:"Optional: order the item list before paging. Work item age: 'age' (oldest first), 'percentile' (highest percentile of the historical cycle times first), 'key'. Scatterplots: 'cycle_time' (slowest first), 'date' (newest first), 'key'. Default: the order of the analysis."`
+}
+
+// ResponseBudget lets the analysis tools raise or lower the size budget of
+// their result for one call (see budget.go).
+type ResponseBudget struct {
+	MaxBytes int `json:"max_bytes,omitempty" jsonschema:"Optional: size budget of this result in bytes. Above it, the longest lists of the result are cut to their 10 most telling items and context.truncated is set. Raise it when the full detail is needed. Default: the server setting MCS_MAX_RESPONSE_BYTES (100000)."`
+}
+
 // ImportProjectsInput holds arguments for the import_projects tool.
 type ImportProjectsInput struct {
 	Query string `json:"query" jsonschema:"Project name or key to search for"`
+	JiraInstance
 }
 
 // ImportBoardsInput holds arguments for the import_boards tool.
 type ImportBoardsInput struct {
 	ProjectKey string `json:"project_key,omitempty" jsonschema:"Optional project key"`
 	NameFilter string `json:"name_filter,omitempty" jsonschema:"Filter by board name"`
+	JiraInstance
 }
 
 // ImportProjectContextInput holds arguments for the import_project_context tool.
 type ImportProjectContextInput struct {
 	ProjectKey string `json:"project_key" jsonschema:"The project key (e.g. PROJ)"`
+	JiraInstance
 }
 
 // ImportBoardContextInput holds arguments for the import_board_context tool.
 type ImportBoardContextInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key (e.g. PROJ)"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+	JiraInstance
+}
+
+// PortfolioSource identifies one board of a multi-board portfolio.
+type PortfolioSource struct {
+	ProjectKey string `json:"project_key" jsonschema:"The project key"`
 	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
 }
 
+// DuplicateOption sets how portfolio tools attribute issues listed by several
+// of the boards (see stats.DuplicatePolicy).
+type DuplicateOption struct {
+	Duplicates stats.DuplicatePolicy `json:"duplicates,omitempty" jsonschema:"Portfolio mode: attribution of issues listed by several boards with overlapping filters. 'first': counted once, on the board that resolved it, else the first board listing it. 'split': counted once in the consolidated result, with an equal share on each listing board. 'all': counted on every listing board, so the consolidated result includes it once per board. The shared keys are listed in a warning. Default: the server setting MCS_PORTFOLIO_DUPLICATES (first)."`
+}
+
+// TeamChange is a change in team size for a forecast's capacity scenario.
+type TeamChange struct {
+	From          int    `json:"from" jsonschema:"Current team size (people)"`
+	To            int    `json:"to" jsonschema:"Team size after the change"`
+	EffectiveDate string `json:"effective_date" jsonschema:"Date the change takes effect (YYYY-MM-DD). A date on or before today applies from the first simulated day."`
+}
+
+// ImportPortfolioInput holds arguments for the import_portfolio tool.
+type ImportPortfolioInput struct {
+	Sources []PortfolioSource `json:"sources" jsonschema:"The boards of the portfolio (at least two). The first board becomes the active context."`
+	DuplicateOption
+	JiraInstance
+}
+
 // ForecastMonteCarloInput holds arguments for the forecast_monte_carlo tool.
 type ForecastMonteCarloInput struct {
-	ProjectKey             string             `json:"project_key" jsonschema:"The project key"`
-	BoardID                int                `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey             string             `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID                int                `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Template               string             `json:"template,omitempty" jsonschema:"Name of a forecast template saved with save_forecast_template. Its issue_types, start_status, targets, mix_overrides, include_existing_backlog, include_wip, additional_items, and history_window_days fill the parameters this call leaves out."`
 	Mode                   SimulationMode     `json:"mode" jsonschema:"duration: forecast the completion date for a known set of items (deadline question). scope: forecast how many items will be done by a given date (capacity question)."`
 	IncludeExistingBacklog bool               `json:"include_existing_backlog,omitempty" jsonschema:"If true automatically counts and includes all unstarted items (Demand Tier or Backlog) from Jira. Set both include_existing_backlog and include_wip to true for real commitment forecasts — omitting either understates total scope."`
 	IncludeWIP             bool               `json:"include_wip,omitempty" jsonschema:"If true also includes items already in progress (past the Commitment Point). Set both include_wip and include_existing_backlog to true for real commitment forecasts — omitting either understates total scope."`
 	AdditionalItems        int                `json:"additional_items,omitempty" jsonschema:"Additional items to include beyond what Jira contains (e.g. new initiative not yet in Jira). Ignored if targets is provided."`
-	TargetDays             int                `json:"target_days,omitempty" jsonschema:"Number of days to forecast into the future (required for scope mode). Calculated automatically if target_date is provided."`
-	TargetDate             string             `json:"target_date,omitempty" jsonschema:"Target date (YYYY-MM-DD). If provided target_days is calculated automatically."`
+	TargetDays             int                `json:"target_days,omitempty" jsonschema:"Number of days to forecast into the future (required for scope mode). In duration mode, the days to check the forecast against. Calculated automatically if target_date is provided."`
+	TargetDate             string             `json:"target_date,omitempty" jsonschema:"Target date (YYYY-MM-DD). If provided target_days is calculated automatically. In duration mode, returns the probability of finishing the scope by that date."`
 	StartStatus            string             `json:"start_status,omitempty" jsonschema:"Override the Commitment Point status (default: configured commitment point)."`
 	IssueTypes             []string           `json:"issue_types,omitempty" jsonschema:"Filter to specific issue types (e.g. Story Bug). If omitted all mapped types are included."`
 	HistoryWindowDays      int                `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample. Default: all available history. Narrow to 30–60 days after a process change. Use recommended_window_days from analyze_residence_time when that tool returns a non-stationary signal (λ/θ > 1.1)."`
@@ -125,115 +328,579 @@ type ForecastMonteCarloInput struct {
 	HistoryEndDate         string             `json:"history_end_date,omitempty" jsonschema:"Explicit end date for the historical baseline (YYYY-MM-DD). Default: today."`
 	Targets                map[string]int     `json:"targets,omitempty" jsonschema:"Exact counts of items to simulate per type (e.g. Story:10 Bug:5). If provided additional_items is ignored."`
 	MixOverrides           map[string]float64 `json:"mix_overrides,omitempty" jsonschema:"Override the historical capacity distribution per type (e.g. Bug:0.1). Values (0.0–1.0) represent target share of capacity; remaining capacity is distributed proportionally to other types."`
+	ToRelease              bool               `json:"to_release,omitempty" jsonschema:"Duration mode only. If true also forecasts the in-production (released) date by adding the historical resolution-to-release lag. Result in context.released_percentiles."`
+	ReleaseStatus          string             `json:"release_status,omitempty" jsonschema:"Optional: Status that marks an item as released (e.g. Released). If omitted release dates come from released fixVersions."`
+	SprintMode             bool               `json:"sprint_mode,omitempty" jsonschema:"Scrum boards only. If true samples per-sprint throughput of closed sprints and forecasts in sprints instead of calendar days. Duration results are sprint counts with projected sprint end dates."`
+	TargetSprints          int                `json:"target_sprints,omitempty" jsonschema:"Sprint mode with scope only: number of upcoming sprints to forecast delivery for."`
+	Holidays               []string           `json:"holidays,omitempty" jsonschema:"Non-working dates (YYYY-MM-DD) added to the working calendar for this forecast. Enables a Mon–Fri calendar if none is configured."`
+	FreezePeriods          []string           `json:"freeze_periods,omitempty" jsonschema:"Date ranges with no delivery (YYYY-MM-DD..YYYY-MM-DD) e.g. a year-end change freeze. Enables a Mon–Fri calendar if none is configured."`
+	Sampling               SamplingMode       `json:"sampling,omitempty" jsonschema:"empirical (default): bootstrap from the observed daily (or per-sprint) throughput. parametric: sample from a Weibull or lognormal distribution fitted to it — smoother and less overconfident when fewer than ~30 items were delivered in the sample."`
+	Stratification         StratificationMode `json:"stratification,omitempty" jsonschema:"auto (default): sample an issue type as a stream of its own when it has at least stratification_min_sample items and its cycle times differ from the pool by 15% or more. on: stratify every type with enough items. off: sample every type from the pooled throughput. The per-type decisions are in context.stratification_decisions."`
+	MinStratumSize         int                `json:"stratification_min_sample,omitempty" jsonschema:"Fewest finished items a type needs to be stratified. Default 15."`
+	Granularity            SampleGranularity  `json:"sampling_granularity,omitempty" jsonschema:"day (default): sample every simulated day independently. week: replay whole weeks of observed days, so weekly batches and slow weeks stay together — choose it when analyze_throughput reports batching. Not with sprint_mode, units=points, or sampling=parametric."`
+	Units                  ForecastUnits      `json:"units,omitempty" jsonschema:"items (default): forecast item counts. points: forecast estimate points (e.g. story points) read from the estimation field JIRA_ESTIMATE_FIELD; the item forecast is run alongside and compared in a warning. Not supported with sprint_mode, model_arrivals, targets, mix_overrides, or sources."`
+	CapacityFactor         float64            `json:"capacity_factor,omitempty" jsonschema:"Capacity scenario: multiplier on the sampled throughput for the whole forecast (e.g. 0.7 while a third of the team is on another project). Default 1."`
+	TeamChange             *TeamChange        `json:"team_change,omitempty" jsonschema:"Capacity scenario: a team size change from effective_date on (e.g. from 5 to 3 people in March). Throughput is scaled by to/from; combines with capacity_factor."`
+	ModelArrivals          bool               `json:"model_arrivals,omitempty" jsonschema:"Duration mode only. If true also samples the historical arrival rate (items created per day) and forecasts the backlog as a moving target. Result in with_arrivals next to the fixed-scope percentiles. Not supported with sprint_mode or sources."`
+	Sources                []PortfolioSource  `json:"sources,omitempty" jsonschema:"Portfolio mode: additional boards to consolidate with project_key/board_id. Shared issues are attributed by duplicates. Not supported with sprint_mode, start_status, to_release, or model_arrivals."`
+	DryRun                 bool               `json:"dry_run,omitempty" jsonschema:"If true returns the scope per type and the throughput sample the forecast would simulate, without running trials. Use it to debug infinite or tiny forecasts. Not supported with sprint_mode, units=points, or sources."`
+	Explain                bool               `json:"explain,omitempty" jsonschema:"If true adds an 'explain' section quantifying the drivers of the forecast: recent vs older throughput, ±20% throughput, the backflow policy, and excluded resolutions. Each driver reruns the forecast once, so the call takes several times longer. Not supported with sprint_mode, units=points, dry_run, or sources."`
+	Ensemble               bool               `json:"ensemble,omitempty" jsonschema:"Duration mode only. If true also forecasts the scope from item cycle times: each item gets a sampled cycle time and at most wip_limit items are worked at once. Result in 'ensemble' with the disagreement of the two models as a model-risk indicator. Not supported with sprint_mode, units=points, dry_run, or sources."`
+	WIPLimit               int                `json:"wip_limit,omitempty" jsonschema:"Ensemble only: number of items worked at once in the cycle-time model. Default: the Downstream tier WIP limit of set_wip_limits, else the current WIP count, else Little's law (throughput × mean cycle time)."`
+	DuplicateOption
+	QuerySource
+	SubtaskOption
+	ContainerOption
+	OutcomeOption
+	OutlierOption
+	AsOfOption
+}
+
+// ForecastScenario is one what-if of the forecast_scenarios tool. Fields left
+// empty keep the value of the base forecast.
+type ForecastScenario struct {
+	Name            string             `json:"name,omitempty" jsonschema:"Label of the scenario in the comparison (e.g. 'Hire 2', 'Bugs capped'). Default: 'Scenario N'."`
+	AdditionalItems *int               `json:"additional_items,omitempty" jsonschema:"Additional items in this scenario, replacing the base additional_items (0 removes them)."`
+	TargetDays      int                `json:"target_days,omitempty" jsonschema:"Scope mode: horizon in days for this scenario."`
+	TargetDate      string             `json:"target_date,omitempty" jsonschema:"Scope mode: target date (YYYY-MM-DD) for this scenario. Overrides target_days."`
+	MixOverrides    map[string]float64 `json:"mix_overrides,omitempty" jsonschema:"Capacity share per type for this scenario (e.g. Bug:0.1), as in forecast_monte_carlo."`
+	CapacityFactor  float64            `json:"capacity_factor,omitempty" jsonschema:"Multiplier on the sampled throughput for this scenario (e.g. 0.7)."`
+	TeamChange      *TeamChange        `json:"team_change,omitempty" jsonschema:"A team size change for this scenario, as in forecast_monte_carlo."`
+}
+
+// ForecastScenariosInput holds arguments for the forecast_scenarios tool.
+type ForecastScenariosInput struct {
+	ProjectKey             string             `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID                int                `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Mode                   SimulationMode     `json:"mode" jsonschema:"duration: completion date of a known scope per scenario. scope: items delivered by a date per scenario."`
+	IncludeExistingBacklog bool               `json:"include_existing_backlog,omitempty" jsonschema:"Base forecast: include all unstarted items (Demand Tier or Backlog) from Jira."`
+	IncludeWIP             bool               `json:"include_wip,omitempty" jsonschema:"Base forecast: include items already in progress (past the Commitment Point)."`
+	AdditionalItems        int                `json:"additional_items,omitempty" jsonschema:"Base forecast: additional items beyond what Jira contains. Scenarios may replace it."`
+	TargetDays             int                `json:"target_days,omitempty" jsonschema:"Base forecast, scope mode: horizon in days. Scenarios may replace it."`
+	TargetDate             string             `json:"target_date,omitempty" jsonschema:"Base forecast, scope mode: target date (YYYY-MM-DD). Scenarios may replace it."`
+	IssueTypes             []string           `json:"issue_types,omitempty" jsonschema:"Filter to specific issue types (e.g. Story Bug). If omitted all mapped types are included."`
+	HistoryWindowDays      int                `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample shared by all scenarios. Default: 90 days."`
+	HistoryStartDate       string             `json:"history_start_date,omitempty" jsonschema:"Explicit start date of the throughput sample (YYYY-MM-DD). Overrides history_window_days."`
+	HistoryEndDate         string             `json:"history_end_date,omitempty" jsonschema:"Explicit end date of the throughput sample (YYYY-MM-DD). Default: today."`
+	Holidays               []string           `json:"holidays,omitempty" jsonschema:"Non-working dates (YYYY-MM-DD) added to the working calendar of all scenarios. Enables a Mon–Fri calendar if none is configured."`
+	FreezePeriods          []string           `json:"freeze_periods,omitempty" jsonschema:"Date ranges with no delivery (YYYY-MM-DD..YYYY-MM-DD) for all scenarios. Enables a Mon–Fri calendar if none is configured."`
+	Sampling               SamplingMode       `json:"sampling,omitempty" jsonschema:"empirical (default) or parametric, as in forecast_monte_carlo. Applies to all scenarios."`
+	Scenarios              []ForecastScenario `json:"scenarios" jsonschema:"The what-ifs to compare (1–10). Each is simulated against the same throughput sample and compared with the first."`
+	QuerySource
+	SubtaskOption
+	ContainerOption
+	OutcomeOption
+	OutlierOption
+	ResultFormat
+}
+
+// ForecastEpicInput holds arguments for the forecast_epic tool.
+type ForecastEpicInput struct {
+	ProjectKey        string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	EpicKey           string `json:"epic_key" jsonschema:"Key of the epic or parent issue (e.g. PROJ-123). Children are found via the Jira issue hierarchy; nested parents (initiative → epic → story) are walked to their leaves."`
+	HistoryWindowDays int    `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample. Default: 90 days."`
+	QuerySource
+}
+
+// CompareForecastsInput holds arguments for the compare_forecasts tool.
+type CompareForecastsInput struct {
+	ProjectKey   string `json:"project_key,omitempty" jsonschema:"The project key whose latest forecast is the current run. Not needed when current_id is set."`
+	BoardID      int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	BaselineID   string `json:"baseline_id,omitempty" jsonschema:"forecast_id of the earlier run (e.g. PROJ_12-F3). Default: the run before current_id, or the latest run on or before baseline_date."`
+	CurrentID    string `json:"current_id,omitempty" jsonschema:"forecast_id of the later run. Default: the latest run of the board."`
+	BaselineDate string `json:"baseline_date,omitempty" jsonschema:"Optional: use the latest run made on or before this date (YYYY-MM-DD) as baseline, e.g. a month ago. Ignored when baseline_id is set."`
+}
+
+// RecordCommitmentInput holds arguments for the record_commitment tool.
+type RecordCommitmentInput struct {
+	ProjectKey        string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Name              string   `json:"name,omitempty" jsonschema:"Label of the commitment (e.g. 'Q3 release'). Default: 'Commitment N'."`
+	ForecastID        string   `json:"forecast_id,omitempty" jsonschema:"forecast_id of the duration forecast the commitment was made from (e.g. PROJ_12-F3). Its percentiles are kept as the snapshot; it defaults target_date, items, and issue_types."`
+	Percentile        int      `json:"percentile,omitempty" jsonschema:"Confidence the date was committed at (e.g. 85). With forecast_id one of 10 30 50 70 85 90 95 98; target_date defaults to that percentile's completion date. Default: 85."`
+	TargetDate        string   `json:"target_date,omitempty" jsonschema:"Committed date (YYYY-MM-DD). Required without forecast_id."`
+	Items             int      `json:"items,omitempty" jsonschema:"Committed number of items. Default: the forecast's scope, or the number of issue_keys."`
+	IssueKeys         []string `json:"issue_keys,omitempty" jsonschema:"Optional: the committed items (e.g. PROJ-1 PROJ-2). Progress is then tracked per item; without them every delivery since the commitment counts."`
+	IssueTypes        []string `json:"issue_types,omitempty" jsonschema:"Optional: count only deliveries of these types towards the scope. Default: the forecast's issue types, or all."`
+	Withdraw          []string `json:"withdraw,omitempty" jsonschema:"IDs of open commitments to withdraw (e.g. PROJ_12-C1). May be passed alone."`
+	HistoryWindowDays int      `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample of the baseline check. Default: 90 days."`
+	QuerySource
+}
+
+// AnalyzeCommitmentHealthInput holds arguments for the analyze_commitment_health tool.
+type AnalyzeCommitmentHealthInput struct {
+	ProjectKey        string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	HistoryWindowDays int    `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample. Default: 90 days."`
+	QuerySource
 }
 
 // AnalyzeCycleTimeInput holds arguments for the analyze_cycle_time tool.
+
 type AnalyzeCycleTimeInput struct {
-	ProjectKey      string   `json:"project_key" jsonschema:"The project key"`
-	BoardID         int      `json:"board_id" jsonschema:"The board ID"`
-	IssueTypes      []string `json:"issue_types,omitempty" jsonschema:"Optional: List of issue types to include in the calculation (e.g. Story or Bug)."`
-	StartStatus     string   `json:"start_status,omitempty" jsonschema:"Optional: Explicit start status (default: Commitment Point)."`
-	EndStatus       string   `json:"end_status,omitempty" jsonschema:"Optional: Explicit end status (default: Finished Tier)."`
-	SLEPercentile   int      `json:"sle_percentile,omitempty" jsonschema:"Optional: percentile (50, 70, 85, 95) used as the SLE for adherence trending. Default: 85."`
-	SLEDurationDays float64  `json:"sle_duration_days,omitempty" jsonschema:"Optional: fixed SLE duration in days. If supplied, adherence is trended against this constant baseline; otherwise the rolling-window percentile is used."`
+	ProjectKey      string         `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID         int            `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueTypes      []string       `json:"issue_types,omitempty" jsonschema:"Optional: List of issue types to include in the calculation (e.g. Story or Bug)."`
+	StartStatus     string         `json:"start_status,omitempty" jsonschema:"Optional: Explicit start status (default: Commitment Point)."`
+	EndStatus       string         `json:"end_status,omitempty" jsonschema:"Optional: Explicit end status (default: Finished Tier)."`
+	SLEPercentile   int            `json:"sle_percentile,omitempty" jsonschema:"Optional: percentile (50, 70, 85, 95) used as the SLE for adherence trending. Default: 85."`
+	SLEDurationDays float64        `json:"sle_duration_days,omitempty" jsonschema:"Optional: fixed SLE duration in days. If supplied, adherence is trended against this constant baseline; otherwise the rolling-window percentile is used."`
+	ByType          bool           `json:"by_type,omitempty" jsonschema:"Optional: also return a percentile table per issue type, with sample sizes and predictability flags. Default: false."`
+	GroupBy         GroupDimension `json:"group_by,omitempty" jsonschema:"Optional: also return a percentile table per 'assignee', 'team' (the JIRA_TEAM_FIELD custom field), or 'component'. Default: no grouping."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	SubtaskOption
+	ContainerOption
+	OutcomeOption
+	OutlierOption
+	PageOption
+	ResultFormat
+}
+
+// AnalyzeCycleTimeScatterInput holds arguments for the analyze_cycle_time_scatter tool.
+type AnalyzeCycleTimeScatterInput struct {
+	ProjectKey  string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID     int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueTypes  []string `json:"issue_types,omitempty" jsonschema:"Optional: List of issue types to plot (e.g. Story or Bug)."`
+	StartStatus string   `json:"start_status,omitempty" jsonschema:"Optional: Explicit start status (default: Commitment Point)."`
+	EndStatus   string   `json:"end_status,omitempty" jsonschema:"Optional: Explicit end status (default: Finished Tier)."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	SubtaskOption
+	ContainerOption
+	OutcomeOption
+	PageOption
+}
+
+// FindOutliersInput holds arguments for the find_outliers tool.
+type FindOutliersInput struct {
+	ProjectKey string            `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int               `json:"board_id,omitempty" jsonschema:"The board ID"`
+	By         stats.SlowMeasure `json:"by,omitempty" jsonschema:"What makes an item slow: cycle_time (default), status_time (time in 'status'), or blocked_time (time flagged as blocked)."`
+	Status     string            `json:"status,omitempty" jsonschema:"Status name or ID ranked by status_time. Required for status_time."`
+	IssueTypes []string          `json:"issue_types,omitempty" jsonschema:"Optional: List of issue types to rank (e.g. Story or Bug)."`
+	Limit      int               `json:"limit,omitempty" jsonschema:"Optional: Number of items to return (default 10, max 50)."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	SubtaskOption
+	ContainerOption
+	ResultFormat
 }
 
 // AnalyzeStatusPersistenceInput holds arguments for the analyze_status_persistence tool.
 type AnalyzeStatusPersistenceInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey  string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID     int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	TrendBucket string `json:"trend_bucket,omitempty" jsonschema:"Optional: 'month' or 'week' adds a persistence trend: per-status P50/P85 residency per bucket of delivery date, and whether each status improved or worsened across the window. Default: no trend."`
+	Reconcile   bool   `json:"reconcile,omitempty" jsonschema:"Optional: compare the residency derived from the changelog with the time-in-status custom field (JIRA_TIME_IN_STATUS_FIELD) per item and status, and report the discrepancies. Default: false."`
+	ByColumn    bool   `json:"by_column,omitempty" jsonschema:"Optional: aggregate statuses to the columns of the board's column configuration (several statuses per column). Needs a board_id. Default: false."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// AnalyzeFlowEfficiencyInput holds arguments for the analyze_flow_efficiency tool.
+type AnalyzeFlowEfficiencyInput struct {
+	ProjectKey string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueTypes []string `json:"issue_types,omitempty" jsonschema:"Optional: restrict the analysis to these issue types."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// AnalyzeReworkInput holds arguments for the analyze_rework tool.
+type AnalyzeReworkInput struct {
+	ProjectKey string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueTypes []string `json:"issue_types,omitempty" jsonschema:"Optional: restrict the analysis to these issue types."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
 }
 
 // AnalyzeWorkItemAgeInput holds arguments for the analyze_work_item_age tool.
 type AnalyzeWorkItemAgeInput struct {
-	ProjectKey string     `json:"project_key" jsonschema:"The project key"`
-	BoardID    int        `json:"board_id" jsonschema:"The board ID"`
-	AgeType    AgeType    `json:"age_type" jsonschema:"'wip': age since commitment point (standard SLE comparison — requires correct commitment point mapping). 'total': age since creation (surfaces items that entered the system long ago but have not yet committed)."`
-	TierFilter TierFilter `json:"tier_filter,omitempty" jsonschema:"Filter results to a specific tier. Default 'WIP' excludes Demand and Finished (shows only in-flight items). Use 'Upstream' or 'Downstream' to focus on a specific stage. Use 'All' to include Demand and Finished items."`
+	ProjectKey string            `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int               `json:"board_id,omitempty" jsonschema:"The board ID"`
+	AgeType    AgeType           `json:"age_type" jsonschema:"'wip': age since commitment point (standard SLE comparison — requires correct commitment point mapping). 'total': age since creation (surfaces items that entered the system long ago but have not yet committed)."`
+	TierFilter TierFilter        `json:"tier_filter,omitempty" jsonschema:"Filter results to a specific tier. Default 'WIP' excludes Demand and Finished (shows only in-flight items). Use 'Upstream' or 'Downstream' to focus on a specific stage. Use 'All' to include Demand and Finished items."`
+	Sources    []PortfolioSource `json:"sources,omitempty" jsonschema:"Portfolio mode: additional boards to consolidate with project_key/board_id. Shared issues are attributed by duplicates."`
+	ByColumn   bool              `json:"by_column,omitempty" jsonschema:"Optional: aggregate statuses to the columns of the board's column configuration (several statuses per column). Needs a board_id. Not supported with sources. Default: false."`
+	DuplicateOption
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	AsOfOption
+	PageOption
+	ResultFormat
 }
 
 // AnalyzeThroughputInput holds arguments for the analyze_throughput tool.
 type AnalyzeThroughputInput struct {
-	ProjectKey       string `json:"project_key" jsonschema:"The project key"`
-	BoardID          int    `json:"board_id" jsonschema:"The board ID"`
-	IncludeAbandoned bool   `json:"include_abandoned,omitempty" jsonschema:"If true includes items with abandoned outcome. Default: false (delivered items only)."`
-	Bucket           string `json:"bucket,omitempty" jsonschema:"Group data by 'week' (default) or 'month'. Use 'month' for low-volume teams where weekly counts are too sparse to be meaningful."`
+	ProjectKey       string            `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID          int               `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IncludeAbandoned bool              `json:"include_abandoned,omitempty" jsonschema:"If true includes items with abandoned outcome. Default: false (delivered items only)."`
+	Bucket           string            `json:"bucket,omitempty" jsonschema:"Group data by 'week' (default) or 'month'. Use 'month' for low-volume teams where weekly counts are too sparse to be meaningful."`
+	Sources          []PortfolioSource `json:"sources,omitempty" jsonschema:"Portfolio mode: additional boards to consolidate with project_key/board_id. Shared issues are attributed by duplicates."`
+	GroupBy          GroupDimension    `json:"group_by,omitempty" jsonschema:"Optional: also return the throughput series of each 'assignee', 'team' (the JIRA_TEAM_FIELD custom field), or 'component', with its share of delivery and XmR stability. Default: no grouping."`
+	BucketAlignment  BucketAlignment   `json:"bucket_alignment,omitempty" jsonschema:"'calendar' (default): ISO weeks from Monday to Sunday, calendar months. 'rolling': whole weeks or months counted back from the last day of the window, so the latest bucket ends on that day."`
+	DuplicateOption
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	SubtaskOption
+	ContainerOption
+	OutcomeOption
+	AsOfOption
+	ResultFormat
+}
+
+// AnalyzeThroughputStreamsInput holds arguments for the analyze_throughput_streams tool.
+type AnalyzeThroughputStreamsInput struct {
+	ProjectKey string          `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int             `json:"board_id,omitempty" jsonschema:"The board ID"`
+	StreamBy   StreamDimension `json:"stream_by" jsonschema:"Attribute that defines a delivery stream: 'component', 'epic' (parent issue), or 'label'."`
+	Bucket     string          `json:"bucket,omitempty" jsonschema:"Group data by 'week' (default) or 'month'."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// AnalyzeInterferenceInput holds arguments for the analyze_interference tool.
+type AnalyzeInterferenceInput struct {
+	ProjectKey  string                `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID     int                   `json:"board_id,omitempty" jsonschema:"The board ID"`
+	By          InterferenceDimension `json:"by,omitempty" jsonschema:"Streams to correlate: 'issue_type' (default) or 'component'."`
+	TaxerFactor float64               `json:"taxer_factor,omitempty" jsonschema:"Optional: multiplier of the taxer's volume in the impact simulation, e.g. 2 for twice as many bugs. Default: 1.5."`
+	HorizonDays int                   `json:"horizon_days,omitempty" jsonschema:"Optional: calendar days the impact simulation forecasts the taxed stream's delivery over. Default: 30."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// AnalyzeReleaseLagInput holds arguments for the analyze_release_lag tool.
+type AnalyzeReleaseLagInput struct {
+	ProjectKey    string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID       int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	ReleaseStatus string `json:"release_status,omitempty" jsonschema:"Optional: Status that marks an item as released (e.g. Released or Deployed). If omitted release dates come from released fixVersions."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// AnalyzeReleaseBurnupInput holds arguments for the analyze_release_burnup tool.
+// The release is chosen with the fix_version field of QuerySource.
+type AnalyzeReleaseBurnupInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Bucket     string `json:"bucket,omitempty" jsonschema:"Group the series by 'week' (default), 'day', or 'month'."`
+	QuerySource
+	ResultFormat
+}
+
+// AnalyzeSprintHistoryInput holds arguments for the analyze_sprint_history tool.
+type AnalyzeSprintHistoryInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID (must be a Scrum board)"`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
 }
 
 // AnalyzeProcessStabilityInput holds arguments for the analyze_process_stability tool.
 type AnalyzeProcessStabilityInput struct {
-	ProjectKey       string `json:"project_key" jsonschema:"The project key"`
-	BoardID          int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey       string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID          int    `json:"board_id,omitempty" jsonschema:"The board ID"`
 	IncludeRawSeries bool   `json:"include_raw_series,omitempty" jsonschema:"If true includes the full Values and MovingRange arrays in the response. Default: false. Enable when you need to inspect individual data points or plot the raw series."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	PageOption
 }
 
 // AnalyzeFlowDebtInput holds arguments for the analyze_flow_debt tool.
 type AnalyzeFlowDebtInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
 	BucketSize string `json:"bucket_size,omitempty" jsonschema:"Group data by 'week' (default) or 'month'. Use 'month' for low-volume teams where weekly counts are too sparse to be meaningful."`
+	BucketAlignment BucketAlignment `json:"bucket_alignment,omitempty" jsonschema:"'calendar' (default): ISO weeks from Monday to Sunday, calendar months. 'rolling': whole weeks or months counted back from the last day of the window, so the latest bucket ends on that day."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
 }
 
 // GenerateCFDDataInput holds arguments for the generate_cfd_data tool.
 type GenerateCFDDataInput struct {
-	ProjectKey  string      `json:"project_key" jsonschema:"The project key"`
-	BoardID     int         `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey  string      `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID     int         `json:"board_id,omitempty" jsonschema:"The board ID"`
 	Granularity Granularity `json:"granularity,omitempty" jsonschema:"Time series granularity. 'daily' (default) gives the full picture. 'weekly' keeps only the last data point per ISO week — use this to reduce payload size for long windows or low-volume teams."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
 }
 
 // AnalyzeWIPStabilityInput holds arguments for the analyze_wip_stability tool.
 type AnalyzeWIPStabilityInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	ByColumn   bool   `json:"by_column,omitempty" jsonschema:"Optional: add 'column_wip', the weekly WIP of each board column with XmR limits, from the board's column configuration. Needs a board_id. Default: false."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
 }
 
 // AnalyzeWIPAgeStabilityInput holds arguments for the analyze_wip_age_stability tool.
 type AnalyzeWIPAgeStabilityInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
 }
 
 // AnalyzeProcessEvolutionInput holds arguments for the analyze_process_evolution tool.
 type AnalyzeProcessEvolutionInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
-	Bucket     string `json:"bucket,omitempty" jsonschema:"Subgroup granularity: 'month' (default, looks back 12 complete months) or 'week' (looks back 26 complete weeks). Lookback is fixed by bucket type — adjust the right edge via set_analysis_window's End if needed."`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Bucket     string `json:"bucket,omitempty" jsonschema:"Subgroup granularity: 'month' (default, looks back 12 complete months) or 'week' (looks back 26 complete weeks). Lookback is fixed by bucket type — adjust the right edge via history_end_date or set_analysis_window's End if needed."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
 }
 
 // AnalyzeYieldInput holds arguments for the analyze_yield tool.
 type AnalyzeYieldInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// AnalyzeAbandonmentInput holds arguments for the analyze_abandonment tool.
+type AnalyzeAbandonmentInput struct {
+	ProjectKey string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueTypes []string `json:"issue_types,omitempty" jsonschema:"Optional: List of issue types to analyze (e.g. Story or Bug)."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	SubtaskOption
+	ContainerOption
+	ResultFormat
+}
+
+// AnalyzeDefinitionOfWorkflowInput holds arguments for the analyze_definition_of_workflow tool.
+type AnalyzeDefinitionOfWorkflowInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
 }
 
 // WorkflowDiscoverMappingInput holds arguments for the workflow_discover_mapping tool.
 type WorkflowDiscoverMappingInput struct {
-	ProjectKey   string `json:"project_key" jsonschema:"The project key"`
-	BoardID      int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey   string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID      int    `json:"board_id,omitempty" jsonschema:"The board ID"`
 	ForceRefresh bool   `json:"force_refresh,omitempty" jsonschema:"If true bypasses the persistent cache and recalculates the mapping from historical data."`
+	QuerySource
 }
 
 // WorkflowSetMappingInput holds arguments for the workflow_set_mapping tool.
 type WorkflowSetMappingInput struct {
-	ProjectKey      string                        `json:"project_key" jsonschema:"The project key"`
-	BoardID         int                           `json:"board_id" jsonschema:"The board ID"`
-	Mapping         map[string]StatusMappingEntry  `json:"mapping" jsonschema:"A map of status names to metadata (tier role and optional outcome)."`
-	Resolutions     map[string]WorkflowOutcome     `json:"resolutions,omitempty" jsonschema:"Optional: A map of Jira resolution names to outcomes (delivered or abandoned)."`
-	CommitmentPoint string                         `json:"commitment_point,omitempty" jsonschema:"Optional: The Downstream status where the clock starts."`
+	ProjectKey             string                        `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID                int                           `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Mapping                map[string]StatusMappingEntry `json:"mapping" jsonschema:"A map of status names to metadata (tier role and optional outcome). A key 'column:<name>' maps all statuses of that board column."`
+	Resolutions            map[string]WorkflowOutcome    `json:"resolutions,omitempty" jsonschema:"Optional: A map of Jira resolution names to outcomes (delivered or abandoned)."`
+	CommitmentPoint        string                        `json:"commitment_point,omitempty" jsonschema:"Optional: The Downstream status where the clock starts, or 'column:<name>' for the first status of a board column."`
+	CommitmentPointsByType map[string]string             `json:"commitment_points_by_type,omitempty" jsonschema:"Optional: Per-issue-type commitment point overrides as a map of issue type to status name (e.g. Bug: Triaged). Types not listed use commitment_point."`
+	QuerySource
 }
 
 // WorkflowSetOrderInput holds arguments for the workflow_set_order tool.
 type WorkflowSetOrderInput struct {
-	ProjectKey string   `json:"project_key" jsonschema:"The project key"`
-	BoardID    int      `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int      `json:"board_id,omitempty" jsonschema:"The board ID"`
 	Order      []string `json:"order" jsonschema:"Ordered list of status names."`
+	QuerySource
+}
+
+// WorkflowExportMappingInput holds arguments for the workflow_export_mapping tool.
+type WorkflowExportMappingInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Path       string `json:"path,omitempty" jsonschema:"Optional: Also write the mapping document to this file (.json)."`
+	QuerySource
+}
+
+// WorkflowImportMappingInput holds arguments for the workflow_import_mapping tool.
+type WorkflowImportMappingInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Path       string `json:"path,omitempty" jsonschema:"Path of a mapping document written by workflow_export_mapping. Set either path or document."`
+	Document   string `json:"document,omitempty" jsonschema:"The mapping document as JSON (the 'document' returned by workflow_export_mapping). Set either path or document."`
+	Overwrite  bool   `json:"overwrite,omitempty" jsonschema:"If true replaces a mapping already confirmed for the board. Default: false."`
+	QuerySource
+}
+
+// WorkflowSetTypeAliasesInput holds arguments for the workflow_set_type_aliases tool.
+type WorkflowSetTypeAliasesInput struct {
+	ProjectKey string            `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int               `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Aliases    map[string]string `json:"aliases" jsonschema:"Map of issue type to the canonical type it is grouped under (e.g. User Story: Story and Improvement: Story). Replaces all aliases of the board; an empty map removes them."`
+	QuerySource
 }
 
 // WorkflowSetEvaluationDateInput holds arguments for the workflow_set_evaluation_date tool.
 type WorkflowSetEvaluationDateInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
 	Date       string `json:"date" jsonschema:"Evaluation date (YYYY-MM-DD)"`
+	QuerySource
+}
+
+// WorkflowGetAuditInput holds arguments for the workflow_get_audit tool.
+type WorkflowGetAuditInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Field      string `json:"field,omitempty" jsonschema:"Only changes of this configuration field: mapping, resolutions, commitment_point, type_commitment_points, status_order, sles, wip_limits, alert_rules, type_aliases, settings, forecast_templates, or evaluation_date."`
+	Limit      int    `json:"limit,omitempty" jsonschema:"Maximum entries to return, newest first (default 50)."`
+	QuerySource
+}
+
+// GetSourceSettingsInput holds arguments for the get_source_settings tool.
+type GetSourceSettingsInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+}
+
+// SetSourceSettingsInput holds arguments for the set_source_settings tool.
+type SetSourceSettingsInput struct {
+	ProjectKey         string              `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID            int                 `json:"board_id,omitempty" jsonschema:"The board ID"`
+	WindowWeeks        int                 `json:"window_weeks,omitempty" jsonschema:"Optional: length in weeks of the default analysis window, used while no set_analysis_window is active (server default: 26). Max 520."`
+	ForecastSampleDays int                 `json:"forecast_sample_days,omitempty" jsonschema:"Optional: days of throughput history day-based forecasts sample from (server default: 90). Max 3650."`
+	IssueTypes         []string            `json:"issue_types,omitempty" jsonschema:"Optional: issue types forecasts, burn-ups and backtests are restricted to when a call passes none. Not applied to forecast_monte_carlo calls with targets."`
+	SubtaskPolicy      stats.SubtaskPolicy `json:"subtask_policy,omitempty" jsonschema:"Optional: default subtask_policy of analyses. 'include' and 'rollup' need sub-tasks to be fetched (MCS_SUBTASK_POLICY)."`
+	Outliers           stats.OutlierPolicy `json:"outliers,omitempty" jsonschema:"Optional: default outliers policy of cycle-time analyses and forecasts."`
+	Format             render.Format       `json:"format,omitempty" jsonschema:"Optional: default result format ('json', 'markdown' or 'csv')."`
+	Reset              bool                `json:"reset,omitempty" jsonschema:"Optional: drop every stored setting before applying the given ones. With no other setting, restores the server defaults."`
+	QuerySource
+}
+
+// SaveForecastTemplateInput holds arguments for the save_forecast_template tool.
+type SaveForecastTemplateInput struct {
+	ProjectKey             string             `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID                int                `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Name                   string             `json:"name" jsonschema:"Name of the template, e.g. quarterly-roadmap. Saving under an existing name replaces that template."`
+	IssueTypes             []string           `json:"issue_types,omitempty" jsonschema:"Optional: issue types the forecast is restricted to."`
+	StartStatus            string             `json:"start_status,omitempty" jsonschema:"Optional: Commitment Point status (name or ID) the forecast starts from."`
+	Targets                map[string]int     `json:"targets,omitempty" jsonschema:"Optional: exact counts of items to simulate per type (e.g. Story:10 Bug:5)."`
+	MixOverrides           map[string]float64 `json:"mix_overrides,omitempty" jsonschema:"Optional: capacity share per type (0.0–1.0), as in forecast_monte_carlo."`
+	IncludeExistingBacklog bool               `json:"include_existing_backlog,omitempty" jsonschema:"Optional: count the unstarted items of the board."`
+	IncludeWIP             bool               `json:"include_wip,omitempty" jsonschema:"Optional: count the items already in progress."`
+	AdditionalItems        int                `json:"additional_items,omitempty" jsonschema:"Optional: items to add beyond what Jira contains."`
+	HistoryWindowDays      int                `json:"history_window_days,omitempty" jsonschema:"Optional: lookback window in days for the throughput sample."`
+	Remove                 bool               `json:"remove,omitempty" jsonschema:"If true removes the template named 'name' instead of saving one."`
+	QuerySource
+}
+
+// ListForecastTemplatesInput holds arguments for the list_forecast_templates tool.
+type ListForecastTemplatesInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+}
+
+// SetSLEInput holds arguments for the set_sle tool.
+type SetSLEInput struct {
+	ProjectKey   string  `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID      int     `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueType    string  `json:"issue_type,omitempty" jsonschema:"Issue type the SLE applies to (e.g. Story). Omit for an SLE that covers every type without its own."`
+	Percentile   int     `json:"percentile,omitempty" jsonschema:"Share of items (1–99) expected to finish within duration_days. Default: 85."`
+	DurationDays float64 `json:"duration_days,omitempty" jsonschema:"SLE duration in days from the commitment point (e.g. 10 for '85% of Stories in 10 days or less'). Required unless remove is true."`
+	Remove       bool    `json:"remove,omitempty" jsonschema:"If true deletes the SLE of issue_type instead of recording one."`
+	QuerySource
+}
+
+// AnalyzeSLEComplianceInput holds arguments for the analyze_sle_compliance tool.
+type AnalyzeSLEComplianceInput struct {
+	ProjectKey string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueTypes []string `json:"issue_types,omitempty" jsonschema:"Optional: restrict the report to these issue types. Default: all types covered by a recorded SLE."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// WIPLimitEntry is one limit of the set_wip_limits tool.
+type WIPLimitEntry struct {
+	Status string `json:"status,omitempty" jsonschema:"Status name or ID the limit applies to. Set either status or tier."`
+	Tier   string `json:"tier,omitempty" jsonschema:"Workflow tier the limit applies to (Demand, Upstream or Downstream): caps the items across all its statuses. Set either status or tier."`
+	Limit  int    `json:"limit" jsonschema:"Maximum number of items in the status or tier. 0 removes the recorded limit."`
+}
+
+// SetWIPLimitsInput holds arguments for the set_wip_limits tool.
+type SetWIPLimitsInput struct {
+	ProjectKey string          `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int             `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Limits     []WIPLimitEntry `json:"limits,omitempty" jsonschema:"Limits to record or update (limit 0 removes one). Limits not listed are kept."`
+	Clear      bool            `json:"clear,omitempty" jsonschema:"If true drops every recorded limit before applying 'limits'."`
+	QuerySource
+}
+
+// AnalyzeWIPLimitsInput holds arguments for the analyze_wip_limits tool.
+type AnalyzeWIPLimitsInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
+}
+
+// AlertRuleEntry is one rule of the set_alert_rules tool.
+type AlertRuleEntry struct {
+	Name        string  `json:"name,omitempty" jsonschema:"Name of the rule. Default: its condition, e.g. 'stale_wip_pct > 30'. A rule recorded under an existing name replaces it."`
+	Metric      string  `json:"metric" jsonschema:"Metric to watch: stale_wip_pct, aging_outliers, wip_count, cycle_time_p85, fat_tail_ratio, flow_debt, weekly_flow_debt, or weekly_throughput."`
+	Operator    string  `json:"operator" jsonschema:"Comparison that fires the rule: >, >=, <, or <=."`
+	Threshold   float64 `json:"threshold" jsonschema:"Value the metric is compared with, in the metric's unit (percent for stale_wip_pct, days for cycle_time_p85, items otherwise)."`
+	Consecutive int     `json:"consecutive,omitempty" jsonschema:"Weekly metrics only: the rule fires when each of the latest N complete weeks meets the condition. Default: the latest week."`
+}
+
+// SetAlertRulesInput holds arguments for the set_alert_rules tool.
+type SetAlertRulesInput struct {
+	ProjectKey string           `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int              `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Rules      []AlertRuleEntry `json:"rules,omitempty" jsonschema:"Rules to record or replace. Rules not listed are kept."`
+	Remove     []string         `json:"remove,omitempty" jsonschema:"Names of recorded rules to remove."`
+	Clear      bool             `json:"clear,omitempty" jsonschema:"If true drops every recorded rule before applying 'rules'."`
+	QuerySource
+}
+
+// EvaluateAlertsInput holds arguments for the evaluate_alerts tool.
+type EvaluateAlertsInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
 }
 
 // SetAnalysisWindowInput holds arguments for the set_analysis_window tool.
@@ -247,11 +914,69 @@ type SetAnalysisWindowInput struct {
 // GetAnalysisWindowInput holds arguments for the get_analysis_window tool. Empty payload.
 type GetAnalysisWindowInput struct{}
 
+// SetVisualPreferencesInput holds arguments for the set_visual_preferences tool.
+type SetVisualPreferencesInput struct {
+	MermaidCharts bool `json:"mermaid_charts" jsonschema:"If true analytical responses include Mermaid diagrams in 'visuals'. Default: false."`
+}
+
 // AnalyzeItemJourneyInput holds arguments for the analyze_item_journey tool.
 type AnalyzeItemJourneyInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
-	IssueKey   string `json:"issue_key" jsonschema:"The Jira issue key (e.g. PROJ-123)"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueKey   string `json:"issue_key" jsonschema:"The Jira issue key (e.g. PROJ-123), or its ITEM- pseudonym when results are anonymized"`
+	QuerySource
+	ResultFormat
+}
+
+// ForecastItemInput holds arguments for the forecast_item tool.
+type ForecastItemInput struct {
+	ProjectKey        string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	IssueKey          string `json:"issue_key" jsonschema:"Key of the in-flight issue to forecast (e.g. PROJ-123), or its ITEM- pseudonym when results are anonymized"`
+	HistoryWindowDays int    `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the residence-time sample. Default: the session analysis window."`
+	QuerySource
+}
+
+// ForecastBurnUpInput holds arguments for the forecast_burnup tool.
+type ForecastBurnUpInput struct {
+	ProjectKey        string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	HorizonWeeks      int      `json:"horizon_weeks,omitempty" jsonschema:"Number of weeks to forecast, one cone point per week (1–52). Default: 12."`
+	IssueTypes        []string `json:"issue_types,omitempty" jsonschema:"Optional: only count delivered items of these types (e.g. Story Bug). Default: all types."`
+	HistoryWindowDays int      `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample. Default: 90 days."`
+	Holidays          []string `json:"holidays,omitempty" jsonschema:"Non-working dates (YYYY-MM-DD) added to the working calendar for this forecast. Enables a Mon–Fri calendar if none is configured."`
+	FreezePeriods     []string `json:"freeze_periods,omitempty" jsonschema:"Date ranges with no delivery (YYYY-MM-DD..YYYY-MM-DD). Enables a Mon–Fri calendar if none is configured."`
+	QuerySource
+}
+
+// ForecastTimeboxInput holds arguments for the forecast_timebox tool.
+type ForecastTimeboxInput struct {
+	ProjectKey        string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	SprintID          int      `json:"sprint_id,omitempty" jsonschema:"Optional: the sprint whose committed items and end date form the timebox. Default: the board's active sprint when issue_keys is not set."`
+	IssueKeys         []string `json:"issue_keys,omitempty" jsonschema:"Optional: the committed items (e.g. PROJ-1 PROJ-2). Replaces the sprint's items; requires end_date when no sprint is used."`
+	StartDate         string   `json:"start_date,omitempty" jsonschema:"Optional: first day of the timebox (YYYY-MM-DD). Default: the sprint start, or today."`
+	EndDate           string   `json:"end_date,omitempty" jsonschema:"Optional: last day of the timebox (YYYY-MM-DD). Default: the sprint end."`
+	HistoryWindowDays int      `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample. Default: 90 days."`
+	Holidays          []string `json:"holidays,omitempty" jsonschema:"Non-working dates (YYYY-MM-DD) added to the working calendar for this forecast. Enables a Mon–Fri calendar if none is configured."`
+	FreezePeriods     []string `json:"freeze_periods,omitempty" jsonschema:"Date ranges with no delivery (YYYY-MM-DD..YYYY-MM-DD). Enables a Mon–Fri calendar if none is configured."`
+	QuerySource
+}
+
+// AnalyzeThroughputHistogramInput holds arguments for the analyze_throughput_histogram tool.
+type AnalyzeThroughputHistogramInput struct {
+	ProjectKey        string   `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int      `json:"board_id,omitempty" jsonschema:"The board ID"`
+	HistoryWindowDays int      `json:"history_window_days,omitempty" jsonschema:"Lookback window in days for the throughput sample, as in forecast_monte_carlo. Default: 90 days."`
+	HistoryStartDate  string   `json:"history_start_date,omitempty" jsonschema:"Explicit start date of the sample (YYYY-MM-DD). Overrides history_window_days."`
+	HistoryEndDate    string   `json:"history_end_date,omitempty" jsonschema:"Explicit end date of the sample (YYYY-MM-DD). Default: today."`
+	Holidays          []string `json:"holidays,omitempty" jsonschema:"Non-working dates (YYYY-MM-DD) added to the working calendar. Enables a Mon–Fri calendar if none is configured."`
+	FreezePeriods     []string `json:"freeze_periods,omitempty" jsonschema:"Date ranges with no delivery (YYYY-MM-DD..YYYY-MM-DD). Enables a Mon–Fri calendar if none is configured."`
+	QuerySource
+	SubtaskOption
+	ContainerOption
+	OutcomeOption
+	OutlierOption
 }
 
 // GuideDiagnosticRoadmapInput holds arguments for the guide_diagnostic_roadmap tool.
@@ -261,29 +986,80 @@ type GuideDiagnosticRoadmapInput struct {
 
 // ForecastBacktestInput holds arguments for the forecast_backtest tool.
 type ForecastBacktestInput struct {
-	ProjectKey        string         `json:"project_key" jsonschema:"The project key"`
-	BoardID           int            `json:"board_id" jsonschema:"The board ID"`
-	SimulationMode    SimulationMode `json:"simulation_mode" jsonschema:"Simulation mode: duration or scope."`
-	ItemsToForecast   int            `json:"items_to_forecast,omitempty" jsonschema:"Number of items to forecast (duration mode). Default: 5"`
-	ForecastHorizon   int            `json:"forecast_horizon_days,omitempty" jsonschema:"Number of days to forecast (scope mode). Default: 14"`
-	IssueTypes        []string       `json:"issue_types,omitempty" jsonschema:"Optional: List of issue types to include in the validation."`
-	HistoryWindowDays int            `json:"history_window_days,omitempty" jsonschema:"Optional: How far back the validation iterates, controlling the number of checkpoints (not the per-checkpoint sampling window). Default: 175 days (25 weekly checkpoints). Each checkpoint always samples from a fixed 90-day window ending at that checkpoint."`
-	HistoryStartDate  string         `json:"history_start_date,omitempty" jsonschema:"Optional: Explicit start date for the validation range (YYYY-MM-DD). Overrides history_window_days."`
-	HistoryEndDate    string         `json:"history_end_date,omitempty" jsonschema:"Optional: Explicit end date for the validation range (YYYY-MM-DD). Defaults to today."`
+	ProjectKey        string            `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID           int               `json:"board_id,omitempty" jsonschema:"The board ID"`
+	SimulationMode    SimulationMode    `json:"simulation_mode" jsonschema:"Simulation mode: duration or scope."`
+	ItemsToForecast   int               `json:"items_to_forecast,omitempty" jsonschema:"Number of items to forecast (duration mode). Default: 5"`
+	ForecastHorizon   int               `json:"forecast_horizon_days,omitempty" jsonschema:"Number of days to forecast (scope mode). Default: 14"`
+	IssueTypes        []string          `json:"issue_types,omitempty" jsonschema:"Optional: List of issue types to include in the validation."`
+	HistoryWindowDays int               `json:"history_window_days,omitempty" jsonschema:"Optional: How far back the validation iterates, controlling the number of checkpoints (not the per-checkpoint sampling window). Default: 175 days (25 weekly checkpoints). Each checkpoint samples from a 90-day window ending at that checkpoint unless sweep_sample_days is set."`
+	HistoryStartDate  string            `json:"history_start_date,omitempty" jsonschema:"Optional: Explicit start date for the validation range (YYYY-MM-DD). Overrides history_window_days."`
+	HistoryEndDate    string            `json:"history_end_date,omitempty" jsonschema:"Optional: Explicit end date for the validation range (YYYY-MM-DD). Defaults to today."`
+	Precision         BacktestPrecision `json:"precision,omitempty" jsonschema:"Accuracy-vs-speed tradeoff. 'standard' (default) runs 10000 trials per checkpoint. 'fast' runs 1000 trials per checkpoint — roughly 10x faster but percentiles are about 3x noisier; use for a quick read, re-run with 'standard' before committing."`
+	SweepSampleDays   []int             `json:"sweep_sample_days,omitempty" jsonschema:"Optional: per-checkpoint sampling windows (days) to compare in one call, e.g. [30, 60, 90, 180]. Runs one backtest per window (at most 6) and recommends the best-calibrated window as history_window_days for forecast_monte_carlo."`
+	QuerySource
 }
 
 // AnalyzeResidenceTimeInput holds arguments for the analyze_residence_time tool.
 type AnalyzeResidenceTimeInput struct {
-	ProjectKey  string      `json:"project_key" jsonschema:"The project key"`
-	BoardID     int         `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey  string      `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID     int         `json:"board_id,omitempty" jsonschema:"The board ID"`
 	IssueTypes  []string    `json:"issue_types,omitempty" jsonschema:"Filter to specific issue types (e.g. Story Bug). If omitted all mapped types are included."`
 	Granularity Granularity `json:"granularity,omitempty" jsonschema:"Time series granularity. 'daily' (default) for full resolution. 'weekly' to reduce payload size for long windows."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	ResultFormat
 }
 
 // ImportHistoryUpdateInput holds arguments for the import_history_update tool.
 type ImportHistoryUpdateInput struct {
-	ProjectKey string `json:"project_key" jsonschema:"The project key"`
-	BoardID    int    `json:"board_id" jsonschema:"The board ID"`
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	QuerySource
+	JiraInstance
+}
+
+// ImportHistoryStatusInput holds arguments for the import_history_status tool.
+type ImportHistoryStatusInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"Optional: the project key of one source. Default: every cached source."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"Optional: the board ID of one source."`
+	JiraInstance
+}
+
+// ExportWorkspaceInput holds arguments for the export_workspace tool.
+type ExportWorkspaceInput struct {
+	Path string `json:"path,omitempty" jsonschema:"Optional: Destination file for the bundle (.zip). Default: workspace_<timestamp>.zip in the cache directory."`
+}
+
+// ExportDatasetInput holds arguments for the export_dataset tool.
+type ExportDatasetInput struct {
+	ProjectKey string `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID    int    `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Dir        string `json:"dir,omitempty" jsonschema:"Optional: Directory to write the CSV files to, relative to exports/ in the cache directory or an absolute path inside the cache directory. Default: exports/<source>_<timestamp> in the cache directory."`
+	QuerySource
+	HistoryWindow
+	ResponseBudget
+	SubtaskOption
+	ContainerOption
+}
+
+// ImportWorkspaceInput holds arguments for the import_workspace tool.
+type ImportWorkspaceInput struct {
+	Path      string `json:"path" jsonschema:"Path of the workspace bundle (.zip) created by export_workspace."`
+	Overwrite bool   `json:"overwrite,omitempty" jsonschema:"If true replaces existing local configuration for the same boards. Default: false (existing files are kept)."`
+	JiraInstance
+}
+
+// ImportThroughputCSVInput holds arguments for the import_throughput_csv tool.
+type ImportThroughputCSVInput struct {
+	ProjectKey  string      `json:"project_key,omitempty" jsonschema:"The project key. Not needed when jql or filter_id is set."`
+	BoardID     int         `json:"board_id,omitempty" jsonschema:"The board ID"`
+	Path        string      `json:"path,omitempty" jsonschema:"Path of a CSV file with date,count rows (YYYY-MM-DD, delivered items). A header row is allowed."`
+	CSV         string      `json:"csv,omitempty" jsonschema:"The CSV content itself, instead of path."`
+	Granularity Granularity `json:"granularity,omitempty" jsonschema:"'weekly' (default): each date starts a week and its count is the week's throughput. 'daily': one count per day; missing days count as zero."`
+	Remove      bool        `json:"remove,omitempty" jsonschema:"If true deletes the imported throughput of the board instead of importing."`
+	QuerySource
 }
 
 // OpenInBrowserInput holds arguments for the open_in_browser tool.
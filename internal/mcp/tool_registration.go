@@ -6,6 +6,11 @@ import (
 	"fmt"
 	"reflect"
 	"runtime/debug"
+	"sync"
+
+	"mcs-mcp/internal/render"
+	"mcs-mcp/internal/simulation"
+	"mcs-mcp/internal/stats"
 
 	"github.com/google/jsonschema-go/jsonschema"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -22,18 +27,52 @@ var toolDescriptions = map[string]string{
 		"WHEN TO USE: User asks 'How long does an item take?', 'What is our cycle time?', 'What is our lead time?', 'How long from commit to done?', 'What is our SLE?', 'What percentile should we commit to?', 'Show the cycle time distribution / histogram / scatterplot', 'How long do stories / bugs typically take?'\n" +
 		"WHEN NOT TO USE: Do not use to assess delivery volume stability — use 'analyze_throughput' for that. Do not use to assess predictability of the process — use 'analyze_process_stability' for that. Do not use for residence time / sample-path analysis — use 'analyze_residence_time' for that.\n\n" +
 		"PREREQUISITE: Proper workflow mapping/commitment point MUST be confirmed via 'workflow_set_mapping' for accurate results.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
-		"OUTPUT: Per-item cycle times, percentile distribution (P50/P70/P85/P95), Fat-Tail Ratio, scatterplot data, and SLE adherence trend.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"SUB-TASKS: Left out by default. subtask_policy 'include' measures them as items of their own; 'rollup' starts a parent's clock when its first sub-task entered the Downstream tier, if that was earlier. Both need sub-tasks in the history (MCS_SUBTASK_POLICY).\n\n" +
+		"OUTLIERS: One extreme item can inflate P95 and the Fat-Tail Ratio. outliers 'winsorize' caps cycle times above the P99; 'iqr' drops those outside the IQR fences. Only the percentiles are trimmed; 'context.outliers' reports how many items were. Say so when presenting trimmed results.\n\n" +
+		"OUTCOMES: Delivered items only by default, the basis of SLEs. outcome_scope 'abandoned' measures how long abandoned items ran before they were dropped; 'all' measures every finished item. 'context.outcome_scope' echoes a non-default scope.\n\n" +
+		"PER-TYPE SLEs: Set by_type=true to assess every issue type in one call instead of one call per type. issue_types still narrows which types are included.\n\n" +
+		"PER-GROUP: Set group_by to 'assignee' (fetched only with JIRA_FETCH_ASSIGNEE=true), 'team' (the custom field JIRA_TEAM_FIELD), or 'component' to add 'group_breakdown', the same table per group. Compare the groups' systems, not their people: differences usually come from the work each group gets.\n\n" +
+		"OUTPUT: Per-item cycle times, percentile distribution (P50/P70/P85/P95), Fat-Tail Ratio, scatterplot data, and SLE adherence trend. With by_type, 'type_breakdown' lists each type with its sample size, percentiles, tail ratios, predictability, and a small_sample flag, largest type first.\n\n" +
 		"INTERPRETATION: Primary signals are the Fat-Tail Ratio and P85 (SLE). A Fat-Tail Ratio > 1.5 means the distribution has a long tail — P85 is a more reliable SLE than the mean.",
 
+	"analyze_cycle_time_scatter": "Returns the item-level Cycle Time Scatterplot: one point per delivered item (completion date, cycle time, key, issue type) plus P50/P85/P95 percentile bands.\n\n" +
+		"WHEN TO USE: User asks to 'draw / plot / export the cycle time scatterplot', 'which items took longest?', or wants to drill down from a percentile to the individual items behind it.\n" +
+		"WHEN NOT TO USE: For SLEs, distribution shape, or adherence trends — use 'analyze_cycle_time'. For process limits and signals — use 'analyze_process_stability'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"PAGINATION: Long windows plot a thousand points or more. Pass limit (and offset, e.g. the next_offset of 'page') to return part of 'points', ordered by sort_by 'cycle_time' (slowest first), 'date' (newest first), or 'key'. Bands and above_p95 always cover every point.\n\n" +
+		"OUTCOMES: outcome_scope 'abandoned' or 'all' plots the abandoned items, or every finished item, instead of the delivered ones.\n\n" +
+		"OUTPUT: 'points' (chronological unless sorted), pooled 'bands', 'bands_by_type' for types with enough items, 'above_p95' — keys of items slower than the P95 band, slowest first — and 'page' when paged.\n\n" +
+		"INTERPRETATION: Points above the P85 band are the items that broke the usual expectation; follow up with 'analyze_item_journey' on their keys.",
+
+	"find_outliers": "Returns the top-N slowest delivered items of the analysis window with their keys and summaries, ranked by cycle time, by the time spent in one status, or by the time spent blocked.\n\n" +
+		"WHEN TO USE: User asks 'which items took longest?', 'what got stuck in Code Review?', 'which items were blocked the most?', or wants the items behind a fat tail or a slow status before explaining them.\n" +
+		"WHEN NOT TO USE: For the whole distribution or SLEs — use 'analyze_cycle_time'. For every delivered point — use 'analyze_cycle_time_scatter'. For items still in progress — use 'analyze_work_item_age'.\n\n" +
+		"RANKING: by 'cycle_time' (default, from the commitment point), 'status_time' with 'status' (name or ID) for the days spent in that status, or 'blocked_time' for the days flagged as blocked. Items without time in the measure are not ranked. limit sets N (default 10, max 50).\n\n" +
+		"SUMMARIES: Fetched from Jira for the returned keys only; left out for anonymized results and when Jira cannot be reached (with a warning).\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"OUTPUT: 'outliers.items' slowest first (key, summary, issue_type, delivered, cycle_time_days, cycle_time_percentile, status_days, blocked_days), 'outliers.population' and 'outliers.bands' (P50/P85/P95 of the measure over the ranked items).\n\n" +
+		"Next step: 'analyze_item_journey' on each key to see where the time went.",
+
+	"set_sle": "Records the team's stated Service Level Expectation (SLE) for an issue type, e.g. '85% of Stories finish within 10 days'. Persisted with the board's workflow.\n\n" +
+		"WHEN TO USE: The user states an SLE or agrees to one (often after reviewing 'analyze_cycle_time'). Omit issue_type for an SLE covering every type without its own. remove=true deletes one.\n\n" +
+		"Next step: 'analyze_sle_compliance'.",
+
+	"analyze_sle_compliance": "Tracks compliance with the SLEs recorded via 'set_sle': hit rate of delivered items, weekly breach trend, and which in-flight items are on track to breach.\n\n" +
+		"WHEN TO USE: User asks 'Are we meeting our SLE?', 'How often do we breach?', 'Which items will miss the SLE?'\n" +
+		"WHEN NOT TO USE: To derive an SLE from history — use 'analyze_cycle_time'. For general WIP aging without an SLE — use 'analyze_work_item_age'.\n\n" +
+		"PREREQUISITE: At least one SLE recorded via 'set_sle', and a confirmed commitment point.\n\n" +
+		"WINDOWING: Hit rate and trend use the session analysis window; WIP is a snapshot at its End.\n\n" +
+		"OUTPUT: Per SLE — delivered, breaches, hit_rate vs. expected_hit_rate, met, breach_trend (improving/worsening/stable), weekly 'trend' buckets, and 'wip' items with age, remaining_days, breach_probability, and state (on_track/at_risk/breached).",
+
 	"analyze_process_stability": "Measures the predictability of Cycle Times using Wheeler XmR Process Behavior Charts.\n\n" +
 		"WHEN TO USE: Use as the FIRST diagnostic step when users ask about forecasting reliability, prediction confidence, or whether historical data is a valid proxy for the future. " +
 		"Ask: 'Is our process stable enough to forecast?'\n" +
 		"WHEN NOT TO USE: Do not use to measure delivery volume — use 'analyze_throughput' for that. " +
 		"Do not use for long-term trend analysis spanning many months — use 'analyze_process_evolution' for that.\n\n" +
 		"PREREQUISITE: Proper workflow mapping is required for accurate results.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
-		"INTERPRETATION: Primary signals are UNPL and the total number of signals (outliers + shifts). " +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"INTERPRETATION: Primary signals are UNPL and the total number of signals. Each signal names the Wheeler rule that fired — 1 'outlier' (beyond the limits), 2 'cluster' (3 of 4 points beyond one sigma), 3 'shift' (8 points on one side of the average), 4 'trend' (6 points steadily rising or falling) — and the delivery dates of its first and last item ('from', 'to'). " +
 		"If stability is low (many signals), simulations will produce MISLEADING results. " +
 		"Combine with 'analyze_residence_time' when λ/θ > 1.1 to understand why cycle times are unstable.",
 
@@ -41,7 +80,7 @@ var toolDescriptions = map[string]string{
 		"WHEN TO USE: Deep history analysis, post-reorganization audits, quarterly/annual process reviews. " +
 		"User asks: 'Has our delivery capability improved over the past year?' or 'When did the process change?'\n" +
 		"WHEN NOT TO USE: Not for routine analysis — use 'analyze_process_stability' for that. Throughput-agnostic: does not measure delivery volume.\n\n" +
-		"WINDOWING: Long-term trend metric. This tool ignores the session window's range and uses ONLY its End as the right edge. Lookback is FIXED: 12 complete months (bucket='month', default) or 26 complete weeks (bucket='week'). Only complete buckets are included — no partial trailing month/week. To shift the right edge, set the session window's End via 'set_analysis_window'.\n\n" +
+		"WINDOWING: Long-term trend metric. This tool ignores the session window's range and uses ONLY its End as the right edge. Lookback is FIXED: 12 complete months (bucket='month', default) or 26 complete weeks (bucket='week'). Only complete buckets are included — no partial trailing month/week. To shift the right edge, set the session window's End via 'set_analysis_window', or pass history_end_date for this call only.\n\n" +
 		"PARAMETER GUIDANCE:\n" +
 		"- bucket: 'month' (default) for quarterly/annual audits or 'week' for tighter regime-shift detection.\n\n" +
 		"INTERPRETATION: Primary signals are detected regime shifts (process resets) and the long-term capability trend. " +
@@ -52,35 +91,114 @@ var toolDescriptions = map[string]string{
 		"WHEN NOT TO USE: Do not use for active WIP — this tool only analyzes finished items. " +
 		"Do not confuse with 'analyze_process_stability', which measures overall Cycle Time predictability, not per-status breakdown.\n\n" +
 		"PREREQUISITE: Proper workflow mapping (Upstream/Downstream tiers) is required. Results are SUBPAR if tiers are unmapped.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks ≈ 6 months). Adjust via 'set_analysis_window'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks ≈ 6 months). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"TREND: The user asks whether a bottleneck is getting better or worse, e.g. after a process change. Set trend_bucket 'month' (or 'week' for short windows) to add 'persistence_trend': per status, the P50/P85 residency of the items delivered in each bucket, and a direction comparing the P85 of the earlier and later half of the window. To compare before/after a known change date, set the window so the change falls near its middle.\n\n" +
+		"RECONCILIATION: The user doubts the residency figures, or a time-in-status app shows different numbers. Set reconcile=true to add 'residency_reconciliation': the residency derived from the changelog compared with the time-in-status field JIRA_TIME_IN_STATUS_FIELD, per status, with the largest item-level discrepancies. Fails when that field was not fetched.\n\n" +
+		"COLUMNS: The team works several statuses of one board column in parallel (e.g. 'In Dev' and 'In Review' both in 'In Progress'). Set by_column=true to analyze the board's columns instead of its statuses: residencies are summed per column and moves within a column are ignored. Board sources only.\n\n" +
 		"INTERPRETATION: Primary signal is IQR concentration — a status with high median but low IQR is a consistent queue; " +
 		"high IQR indicates unpredictable, variable dwell time worth investigating.",
 
+	"analyze_flow_efficiency": "Measures flow efficiency: the share of each delivered item's cycle time spent in 'active' statuses rather than 'queue' statuses, using the roles of the workflow mapping.\n\n" +
+		"WHEN TO USE: User asks 'How much of our cycle time is waiting?', 'What is our flow efficiency?', 'Is waiting worse upstream or downstream?'\n" +
+		"WHEN NOT TO USE: To find which single status holds items longest — use 'analyze_status_persistence'. For overall cycle time — use 'analyze_cycle_time'.\n\n" +
+		"PREREQUISITE: A confirmed workflow mapping with 'active' and 'queue' roles, and a commitment point.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"OUTPUT: Pooled 'efficiency' (total active / total active+queue time), item 'percentiles' (p15/p50/p85), 'coverage' (share of cycle time in statuses with either role), 'by_tier' breakdown, " +
+		"and 'items' with active_days, queue_days and efficiency, lowest efficiency first.\n\n" +
+		"INTERPRETATION: Flow efficiency of 15% or less is common in knowledge work; the lever is usually removing queues, not working faster.",
+
+	"analyze_rework": "Measures rework from backward transitions of delivered items (e.g. Validation → In Development): first-time-right rate, rework rate per status pair, and the cycle time spent in rework loops.\n\n" +
+		"WHEN TO USE: User asks 'How much rework do we have?', 'How often does work bounce back from testing?', 'What share of our cycle time is rework?'\n" +
+		"WHEN NOT TO USE: For where items wait — use 'analyze_status_persistence'. For work that is abandoned — use 'analyze_yield'.\n\n" +
+		"PREREQUISITE: A confirmed workflow order ('workflow_set_order') and commitment point; without an order, the discovered backbone order is used.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"OUTPUT: first_time_right_rate, rework_share of total cycle time, P85 cycle time of first-time-right vs. reworked items, 'pairs' (from → to, transitions, items, rework_rate, loop days) most frequent first, " +
+		"and 'items_with_rework' with their loops and rework_days, most rework first.\n\n" +
+		"INTERPRETATION: A loop lasts from the backward move until the item re-enters the status it left. Moves back before the commitment point are backflows, not rework, and are not counted.",
+
 	"analyze_throughput": "Measures delivery volume — the number of items completed per week or month — and its stability using Wheeler XmR Process Behavior Charts.\n\n" +
 		"WHEN TO USE: User asks 'How many items do we deliver per week?', 'Is our delivery cadence stable?', 'Do we have batching or zero-delivery weeks?'\n" +
 		"WHEN NOT TO USE: Do not use to measure how long individual items take — use 'analyze_cycle_time' for that. " +
 		"Do not use to assess Cycle Time predictability — use 'analyze_process_stability' for that.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
 		"PARAMETER GUIDANCE:\n" +
-		"- bucket: Default 'week'. Switch to 'month' for low-volume teams where weekly counts are too sparse to be meaningful.\n\n" +
+		"- bucket: Default 'week'. Switch to 'month' for low-volume teams where weekly counts are too sparse to be meaningful.\n" +
+		"- bucket_alignment: 'calendar' (default) buckets ISO weeks (Monday to Sunday, labelled '2024-W01') and calendar months, so they match reporting weeks; the latest bucket is usually partial. 'rolling' counts whole weeks or months back from the last day of the window, labelled by their first and last day. Not combinable with sources.\n" +
+		"- subtask_policy: 'include' counts delivered sub-tasks as items of their own. Default: the server setting MCS_SUBTASK_POLICY.\n" +
+		"- outcome_scope: 'delivered' (default) counts delivered items; 'all' counts every finished item, the capacity the system processed; 'abandoned' counts only the items that were dropped.\n" +
+		"- sources: Portfolio mode (see 'import_portfolio'). Consolidates delivered items of several boards; 'duplicates' sets how issues shared by several boards are counted (default: once).\n" +
+		"- group_by: 'assignee', 'team', or 'component' adds 'grouped_throughput': each group's series, share of delivery, and XmR stability. 'assignee' needs JIRA_FETCH_ASSIGNEE=true; 'team' reads the custom field JIRA_TEAM_FIELD. Not combinable with sources. For epics or labels — use 'analyze_throughput_streams'.\n" +
+		"- as_of_date: Reproduce the throughput as it was known on a past date, for retrospectives. Items delivered later are left out, and the window ends on that date.\n\n" +
+		"BATCHING: 'batching' compares the variance of weekly totals with the variance independent days imply (dispersion_ratio, over the last whole weeks of working days; at least 8 weeks). At 1.5 or more, deliveries cluster in weeks and 'recommended_granularity' is 'week': forecast with sampling_granularity=week.\n\n" +
 		"INTERPRETATION: Primary signals are UNPL and zero-count weeks. " +
 		"Zero-delivery weeks signal batching or blockage. UNPL breaches signal unusual surges. " +
+		"'stability.signals' name the Wheeler rule that fired (1 outlier, 2 cluster of 3 of 4 points beyond one sigma, 3 shift of 8 points on one side, 4 trend of 6 points) and the buckets it spans ('from', 'to'): a shift or trend means the delivery rate itself changed. " +
 		"Use 'analyze_flow_debt' as a leading indicator if throughput is declining.",
 
+	"analyze_throughput_streams": "Attributes delivered items to delivery streams (component, epic, or label) and reports each stream's weekly/monthly throughput, share of delivery, and XmR stability separately.\n\n" +
+		"WHEN TO USE: A board serves several products, teams, or initiatives and the user asks 'Which product is getting the capacity?', 'Is one stream starving?', 'Total throughput looks fine — is every stream moving?'\n" +
+		"WHEN NOT TO USE: Do not use for the pooled delivery cadence — use 'analyze_throughput' for that. Do not use to compare issue types — 'analyze_throughput' already stratifies by type.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- stream_by: 'component' or 'label' for product/area splits; 'epic' to roll delivered items up to their parent. Components and labels are multi-valued — an item with two components counts in both streams (see 'multi_attributed').\n" +
+		"- bucket: Default 'week'. Use 'month' when individual streams are too sparse for weekly counts.\n\n" +
+		"INTERPRETATION: Primary signals are 'starving' (recent share below half of the stream's window share), zero-count buckets, and per-stream XmR signals. " +
+		"A large 'unattributed' count means the chosen attribute is not used consistently — the stream picture is incomplete.",
+
+	"analyze_interference": "Reports which issue types (or components) crowd each other out: negative correlations in their daily throughput, with effect size, significance, and the simulated impact on the crowded-out stream when the other's volume changes.\n\n" +
+		"WHEN TO USE: User asks 'Do bugs eat our feature capacity?', 'Is support work slowing the roadmap?', 'What happens to our stories if incidents double?', or forecasts show a capacity tax between types and the user wants to know how strong it is.\n" +
+		"WHEN NOT TO USE: Do not use for each stream's share of delivery or starvation — use 'analyze_throughput_streams'. Do not use for whole-board what-ifs — use 'forecast_scenarios'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- by: 'issue_type' (default, type aliases applied) or 'component'.\n" +
+		"- taxer_factor: Volume multiplier of the taxer in the impact simulation. Default 1.5; 2 answers 'what if bugs double?', 0.5 'what if they halve?'.\n" +
+		"- horizon_days: Calendar days of the impact simulation. Default 30.\n\n" +
+		"INTERPRETATION: Each pair names a 'taxer' (the more volatile stream) and a 'taxed' stream. 'correlation' is Pearson r over the days with deliveries; 'strength' is Cohen's band of |r| (weak ≥ 0.1, moderate ≥ 0.3, strong ≥ 0.5); " +
+		"'slope' is the change in taxed items per extra taxer item per day. 'impact' (significant pairs only) compares the taxed stream's P50/P85 delivery over the horizon as sampled and with the scaled taxer volume. " +
+		"'modeled_by_forecasts' says whether stratified forecasts already apply a capacity tax to the pair. Correlation shows shared capacity, not its cause.",
+
 	"analyze_wip_stability": "Measures Work-In-Progress (WIP) count stability over time using XmR charts and a daily run chart.\n\n" +
 		"WHEN TO USE: User asks 'Is our WIP under control?', 'Are we respecting WIP limits?', 'How variable is the number of active items?'\n" +
 		"WHEN NOT TO USE: WIP count stability does NOT imply age stability — a stable count of 10 items can still be accumulating age. " +
-		"Follow up with 'analyze_wip_age_stability' to check this. Do not use to detect individual aging items — use 'analyze_work_item_age' for that.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
+		"Follow up with 'analyze_wip_age_stability' to check this. Do not use to detect individual aging items — use 'analyze_work_item_age' for that. " +
+		"For the team's recorded per-status or per-tier limits — use 'analyze_wip_limits'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
 		"INTERPRETATION: Primary signals are UNPL breaches and the trend direction. " +
-		"A rising trend in WIP count, even within limits, is an early warning. Combine with 'analyze_residence_time' when λ/θ > 1.1.",
+		"A rising trend in WIP count, even within limits, is an early warning. Combine with 'analyze_residence_time' when λ/θ > 1.1. " +
+		"With by_column=true (board sources only), 'column_wip' adds a weekly XmR of the WIP of each Upstream and Downstream board column, so parallel statuses count toward their shared column.",
+
+	"set_wip_limits": "Records the team's WIP limits per workflow status or tier, e.g. 'at most 3 items in Review'. Persisted with the board's workflow.\n\n" +
+		"WHEN TO USE: The user states WIP limits (often read from the board's column limits). Several limits can be set in one call; limit 0 removes one, clear=true drops all.\n\n" +
+		"Next step: 'analyze_wip_limits'.",
+
+	"analyze_wip_limits": "Reconstructs the daily WIP of every status or tier with a limit recorded via 'set_wip_limits' and reports when, how often, and how long the limit was exceeded.\n\n" +
+		"WHEN TO USE: User asks 'Are we respecting our WIP limits?', 'Which column is always over its limit?', 'Does breaking the limit slow us down?'\n" +
+		"WHEN NOT TO USE: For overall WIP count stability without limits — use 'analyze_wip_stability'. For where items wait — use 'analyze_status_persistence'.\n\n" +
+		"PREREQUISITE: At least one limit recorded via 'set_wip_limits'; tier limits need a confirmed workflow mapping.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"OUTPUT: Per limit — violation_days, violation_rate, longest_violation_days, average/peak/current WIP, 'violations' (consecutive runs, longest first), a daily 'run_chart', and 'cycle_time_impact': " +
+		"P50/P85 cycle time of delivered items that sat in the status on a violation day vs. those that passed through it within the limit, with 'inflation' = exposed P85 / unexposed P85.",
+
+	"set_alert_rules": "Records early-warning rules on flow metrics, e.g. 'stale WIP > 30%', 'fat-tail ratio ≥ 5.6', 'flow debt positive for 3 consecutive weeks'. Persisted with the board's workflow.\n\n" +
+		"WHEN TO USE: The user wants to be warned when a metric crosses a threshold instead of checking it. Several rules can be set in one call; 'remove' drops rules by name, clear=true drops all.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- metric: stale_wip_pct (% of WIP older than the historical P85 cycle time), aging_outliers, wip_count, cycle_time_p85 (days), fat_tail_ratio (P98/P50; ≥ 5.6 is unpredictable), flow_debt (window total), weekly_flow_debt, weekly_throughput.\n" +
+		"- consecutive: weekly metrics only, e.g. weekly_flow_debt > 0 with consecutive=3.\n\n" +
+		"Next step: 'evaluate_alerts'.",
+
+	"evaluate_alerts": "Evaluates the rules recorded via 'set_alert_rules' against the current data and reports which fired, with the metric value (and the weekly values for weekly rules) as evidence.\n\n" +
+		"WHEN TO USE: 'Is anything off on this board?', 'Did any of our alerts fire?', or at the start of a recurring review. The digest command runs it on a schedule.\n" +
+		"WHEN NOT TO USE: To explain a fired rule — follow up with the analysis behind its metric ('analyze_work_item_age', 'analyze_cycle_time', 'analyze_flow_debt', 'analyze_throughput').\n\n" +
+		"PREREQUISITE: At least one rule recorded via 'set_alert_rules'.\n\n" +
+		"WINDOWING: WIP and aging are taken at the session window's End; cycle times and weekly series cover the window, in whole weeks counted back from its End.\n\n" +
+		"OUTPUT: 'fired' (count) and 'evaluations', fired rules first: rule, condition, fired, value, 'weeks' (weekly rules, oldest first), and evidence.",
 
 	"analyze_wip_age_stability": "Measures the cumulative age burden of all active WIP items over time using XmR charts — a leading indicator of future delivery problems.\n\n" +
 		"WHEN TO USE: After 'analyze_wip_stability' — stable WIP count does not guarantee stable age. " +
 		"User asks: 'Are items stagnating even though count looks fine?', 'Is the total age of WIP growing?'\n" +
 		"WHEN NOT TO USE: Do not use to measure WIP count — use 'analyze_wip_stability' for that. " +
 		"Do not use to find which specific items are aging — use 'analyze_work_item_age' for that.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
 		"INTERPRETATION: Primary signal is UNPL breaches of total age (not average). " +
 		"Growing total WIP age signals trouble before throughput drops. XmR is applied to total age, not the mean.",
 
@@ -89,28 +207,40 @@ var toolDescriptions = map[string]string{
 		"WHEN NOT TO USE: Do not use to assess overall WIP stability — use 'analyze_wip_stability' or 'analyze_wip_age_stability' for that. " +
 		"An aging outlier is NOT necessarily blocked — it simply exceeds historical P85 for its current status.\n\n" +
 		"PREREQUISITE: Commitment Point MUST be correctly mapped via 'workflow_set_mapping' for accurate 'WIP Age'. Results are UNRELIABLE otherwise.\n\n" +
-		"WINDOWING: Work item age is a POINT-IN-TIME metric, not a range metric. This tool uses ONLY the End of the session analysis window as the as-of snapshot date — Start is intentionally ignored. Default snapshot is today (or the active evaluation date). Move the snapshot via 'set_analysis_window' (only the End matters for this tool).\n\n" +
+		"WINDOWING: Work item age is a POINT-IN-TIME metric, not a range metric. This tool uses ONLY the End of the session analysis window as the as-of snapshot date — Start is intentionally ignored. Default snapshot is today (or the active evaluation date). Move the snapshot via 'set_analysis_window' (only the End matters for this tool), or pass history_end_date for this call only.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- sources: Portfolio mode (see 'import_portfolio'). Ages each board's WIP against its own commitment point; percentiles use the consolidated cycle-time history. Per-status WIP actions and the aging chart are omitted because statuses differ between boards.\n" +
+		"- by_column: Compare items with the history of their board column instead of their status, when the team works several statuses of a column in parallel. The aging chart and WIP actions then show columns. Board sources only; not combinable with sources.\n" +
+		"- as_of_date: Reproduce the aging WIP of a past date from the history known then: items finished later count as in progress, and ages are measured to that date. Prefer it over history_end_date when the user asks what the board looked like back then.\n" +
+		"- limit / offset / sort_by: Large boards have hundreds of items in progress. Ask for a page, e.g. limit=25 with sort_by='age' (oldest first) or 'percentile'; 'page' reports the total and next_offset. Summary, actions, and the aging chart still cover every item.\n\n" +
 		"INTERPRETATION: Primary signals are 'stability_index', outlier count, and P85/P95 thresholds. " +
-		"Use 'age_type=wip' for standard SLE comparison; use 'age_type=total' to surface items that entered the system long ago but have not yet committed.",
+		"Use 'age_type=wip' for standard SLE comparison; use 'age_type=total' to surface items that entered the system long ago but have not yet committed. " +
+		"'recommended_actions' ranks data-backed next steps (reduce WIP in a status, split items older than their type's P85) with the metric evidence attached — present them in rank order. " +
+		"With age_type=wip, 'aging_chart' is the classic Aging WIP chart: 'columns' are the statuses from the commitment point in workflow order, each with P50/P70/P85/P95 of the WIP age at which delivered items left it; 'items' place each in-progress item in its column at its WIP age, with 'above_percentile' naming the highest line it has passed. " +
+		"An item above its column's P85 is older than 85% of past items were when they moved on — act on it before its cycle time does.",
 
 	"analyze_flow_debt": "Measures the systemic imbalance between item arrivals (commitments) and departures (deliveries) — a leading indicator of cycle time inflation.\n\n" +
 		"WHEN TO USE: Use before 'forecast_monte_carlo' to validate that WIP is not growing. " +
 		"User asks: 'Are we taking on more work than we finish?', 'Is WIP accumulating?', 'Why are cycle times increasing?'\n" +
 		"WHEN NOT TO USE: Do not confuse with 'analyze_residence_time' — Flow Debt is a leading indicator (arrival vs. departure counts); " +
 		"Residence Time is a Little's Law analysis (L = λ · W) unifying cycle time, WIP age, and flow balance into a single coherent view.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
 		"PARAMETER GUIDANCE:\n" +
-		"- bucket_size: Default 'week'. Use 'month' for low-volume teams.\n\n" +
+		"- bucket_size: Default 'week'. Use 'month' for low-volume teams.\n" +
+		"- bucket_alignment: As in 'analyze_throughput': 'calendar' (default, ISO weeks and calendar months) or 'rolling' (whole buckets ending on the last day of the window).\n\n" +
+		"OUTPUT: 'buckets' (arrivals, departures, debt per bucket) and 'totalDebt'; 'tiers' and 'statuses' with the items entering and leaving each tier or status per bucket and their 'totalNet'; 'accumulatingStatus', the committed status gaining the most inventory; 'projection', the Little's Law cycle time (WIP ÷ throughput) now and in 30 days if the debt rate continues.\n\n" +
 		"INTERPRETATION: Primary signals are 'totalDebt' and the oscillation pattern. " +
 		"Sustained positive debt (Arrivals > Departures) mathematically guarantees higher future cycle times (Little's Law). " +
-		"Oscillating debt is less concerning than a monotonically growing one.",
+		"Oscillating debt is less concerning than a monotonically growing one. " +
+		"Per-status net flow locates the stage where inventory piles up even when the pooled debt is balanced. " +
+		"When debt is positive, 'recommended_actions' carries a ranked 'stop starting' action with the debt rate as evidence.",
 
 	"analyze_residence_time": "Performs a Sample Path Analysis (Little's Law: L = Λ · W) unifying cycle time, WIP age, WIP stability, and flow balance into a single coherent view.\n\n" +
 		"WHEN TO USE: When you need to understand *why* a system is non-stationary — connects flow debt, WIP age, and cycle time into one analysis. " +
 		"Use when 'analyze_wip_stability' or 'analyze_flow_debt' raises concerns and you want to quantify the severity.\n" +
 		"WHEN NOT TO USE: Do not use as a first-line diagnostic — start with 'analyze_process_stability' or 'analyze_throughput'. " +
 		"Do not confuse with 'analyze_flow_debt': Flow Debt counts arrivals vs. departures; Residence Time measures how long items actually accumulate in the system.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'. " +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date. " +
 		"Sample Path analysis benefits from longer windows — widen via 'set_analysis_window' (e.g. 52 weeks) when convergence requires more path length.\n\n" +
 		"INTERPRETATION: Primary signals are the λ/θ ratio, coherence gap, and 'stationary' flag. " +
 		"λ/θ > 1.1 means arrivals outpace completions — system is accumulating. " +
@@ -121,26 +251,70 @@ var toolDescriptions = map[string]string{
 	"analyze_yield": "Measures delivery efficiency across workflow tiers — what fraction of committed work reaches delivery vs. abandonment at each stage.\n\n" +
 		"WHEN TO USE: User asks 'How much work do we abandon?', 'Where in the funnel do we lose the most?', 'What is our downstream abandonment rate?'\n" +
 		"WHEN NOT TO USE: Do not use for throughput volume — use 'analyze_throughput'. " +
-		"Do not use for cycle time — use 'analyze_cycle_time'. Yield measures outcome rates, not timing.\n\n" +
+		"Do not use for cycle time — use 'analyze_cycle_time'. Yield measures outcome rates, not timing. For the statuses work is dropped from and how late — use 'analyze_abandonment'.\n\n" +
 		"PREREQUISITE: Workflow tiers (Demand, Upstream, Downstream) and resolution outcomes MUST be verified with the user before interpreting results.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'. " +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date. " +
 		"Note: this scopes yield to items active in the window, not all-time. Widen the window for project-lifetime totals.\n\n" +
 		"INTERPRETATION: Primary signal is 'overallYieldRate' per tier. " +
 		"Downstream abandonment (items that passed the commitment point and were then discarded) is the most severe signal — it represents consumed capacity with no value delivered.",
 
+	"analyze_abandonment": "Explains where finished work is abandoned: the tier and status each abandoned item was dropped from, its age at abandonment compared with delivered items, and the downstream days it consumed.\n\n" +
+		"WHEN TO USE: User asks 'Where do we drop work?', 'How late do we cancel items?', 'How much effort do abandoned items waste?', or follows up on a low yield from 'analyze_yield'.\n" +
+		"WHEN NOT TO USE: For delivered vs. abandoned rates per tier alone — use 'analyze_yield'. For items still in progress — use 'analyze_work_item_age'.\n\n" +
+		"PREREQUISITE: A confirmed workflow mapping: tiers place the abandonment, and resolutions or Finished statuses with outcome 'abandoned' decide which items were abandoned.\n\n" +
+		"WINDOWING: Covers the items finished in the session analysis window (default rolling 26 weeks). Narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"OUTPUT: 'abandonment' with the abandoned and delivered counts, 'abandonment_rate', 'wasted_downstream_days', 'age_at_exit' (P50/P85 from creation to outcome, abandoned vs. delivered), 'by_tier' and 'by_status' (count, share, median age, wasted downstream days), and the ten 'costliest_items' by downstream days.\n\n" +
+		"INTERPRETATION: Abandonment from Demand is healthy backlog pruning. Abandonment from Downstream consumed committed capacity without delivering value — look at the statuses it happens in and ask why the items were started.",
+
+	"analyze_definition_of_workflow": "Compares the observed status paths of each issue type and flags types whose flow diverges from the confirmed mapping.\n\n" +
+		"WHEN TO USE: User asks 'Do Bugs follow the same process as Stories?', 'Is our mapping right for every type?', or metrics for one type look implausible (e.g. near-zero cycle times for Bugs).\n" +
+		"WHEN NOT TO USE: Does not propose a mapping — use 'workflow_discover_mapping' for that. For a single item's path, use 'analyze_item_journey'.\n\n" +
+		"PREREQUISITE: A confirmed mapping ('workflow_set_mapping') and commitment point. Divergences are measured against them.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
+		"INTERPRETATION: Primary signals are each type's 'divergences' and the 'recommendations' list. " +
+		"'skips_tier' means a type rarely visits a tier the board normally uses (e.g. Bugs skipping Upstream). " +
+		"'bypasses_commitment' means the type enters Downstream without passing the commitment point — its cycle time and WIP age are understated; 'suggested_commitment_point' names where it actually starts. " +
+		"'unmapped_status' means the type uses a status the mapping does not cover.",
+
 	"generate_cfd_data": "Calculates daily (or weekly) item counts per status to produce Cumulative Flow Diagram (CFD) data.\n\n" +
 		"WHEN TO USE: User asks for a CFD visualization, wants to see WIP accumulation over time by status, or needs to detect stage-level congestion.\n" +
 		"WHEN NOT TO USE: This tool returns raw structured data — it is not a standalone diagnostic. " +
 		"For overall WIP count stability, use 'analyze_wip_stability'. For arrival/departure imbalance, use 'analyze_flow_debt'.\n\n" +
-		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window'.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
 		"PARAMETER GUIDANCE:\n" +
 		"- granularity: Default 'daily'. Use 'weekly' to reduce payload size for long windows or low-volume teams.\n\n" +
 		"INTERPRETATION: Primary signals are band width changes (widening = accumulation) and which status bands are growing. " +
 		"A widening Downstream band with a flat Finished band means delivery is stalling.",
 
+	"analyze_release_lag": "Measures the 'done-done' lag — calendar days between an item's resolution (delivery) and its actual release to production.\n\n" +
+		"WHEN TO USE: User asks 'How long after done does work reach production?', 'When will customers actually see it?', 'How much does our release cadence add to lead time?'\n" +
+		"WHEN NOT TO USE: Does not measure cycle time up to resolution — use 'analyze_cycle_time' for that.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- release_status: Name of the status that marks deployment (e.g. 'Released'). Omit to use the release dates of released fixVersions.\n\n" +
+		"INTERPRETATION: Primary signals are P85 lag and 'unreleased_count'. A large unreleased count means delivered work is waiting for a release, or release markers are not maintained. " +
+		"To forecast in-production dates, run 'forecast_monte_carlo' with to_release=true.",
+
+	"analyze_release_burnup": "Charts a release (fixVersion) over its lifetime: cumulative scope against cumulative delivered items per bucket, with totals, remaining items, and scope growth.\n\n" +
+		"WHEN TO USE: 'How is release 2.4 progressing?', 'How much scope was added to the release?', 'Will we make the release?' (follow up with 'forecast_monte_carlo' and the same fix_version).\n" +
+		"WHEN NOT TO USE: For the lag between done and deployed, use 'analyze_release_lag'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- fix_version (required): Exact release name. The board (or jql/filter) is narrowed to the release's issues.\n" +
+		"- bucket: 'week' (default), 'day', or 'month'. The series starts when the first item of the release was created, independent of the session analysis window.\n\n" +
+		"INTERPRETATION: A scope line rising as fast as the completed line means the release is not converging. Scope is dated by item creation, because Jira does not record when an item joined a fixVersion.",
+
+	"analyze_sprint_history": "Measures delivery per sprint on a Scrum board — committed items, completed items, carry-over, and throughput — from the Agile API sprint assignments and closures.\n\n" +
+		"WHEN TO USE: User asks 'How much do we finish per sprint?', 'How much carries over?', 'Are our sprint commitments realistic?'\n" +
+		"WHEN NOT TO USE: Kanban boards have no sprints — use 'analyze_throughput' instead.\n\n" +
+		"WINDOWING: Uses the session analysis window (default rolling 26 weeks). Sprints that started within it are included. Adjust via 'set_analysis_window', or narrow this call only with history_window_days / history_start_date / history_end_date.\n\n" +
+		"INTERPRETATION: 'committed' counts items assigned to the sprint; 'completed' those delivered by the close; 'carry_over' those still open at the close. " +
+		"'throughput' counts every item delivered during the sprint, assigned or not — this is the per-sprint sample used by 'forecast_monte_carlo' with sprint_mode=true. " +
+		"A high 'avg_carry_over_ratio' means sprint commitments routinely exceed capacity.",
+
 	"analyze_item_journey": "Provides a single-item deep-dive into where one Jira issue spent its time across all workflow steps.\n\n" +
 		"WHEN TO USE: User asks about a specific item: 'Why is PROJ-123 taking so long?', 'Where did this ticket get stuck?', 'Show me the history of this item.'\n" +
-		"WHEN NOT TO USE: This is NOT a population-level diagnostic. For patterns across many items, use 'analyze_status_persistence' or 'analyze_work_item_age'.",
+		"WHEN NOT TO USE: This is NOT a population-level diagnostic. For patterns across many items, use 'analyze_status_persistence' or 'analyze_work_item_age'.\n\n" +
+		"MOVED ITEMS: An item moved between projects is also found by a key it had before; the result then carries its current 'key' and its 'former_keys'.",
 
 	// ── GROUP: Forecast & Simulation ─────────────────────────────────────────
 
@@ -150,43 +324,200 @@ var toolDescriptions = map[string]string{
 		"- mode=scope: 'How much will be done by a given date?' (capacity question — fixed date, unknown scope)\n" +
 		"WHEN NOT TO USE: Does NOT analyze cycle times or individual item durations — use 'analyze_cycle_time' for that.\n\n" +
 		"PARAMETER GUIDANCE:\n" +
+		"- target_date (duration mode): Set when the question is 'can we make it by this date?'. Checks the forecast against the date: 'deadline.probability' is the share of trials finishing the scope by then, 'deadline.gap_days_p85' how many days the P85 date lies beyond it, and 'deadline.gap_items_p85' how many items to drop to finish the rest by then with 85% confidence. Relay the 'TARGET DATE' line instead of comparing percentiles by eye. Not supported with sprint_mode or units=points.\n" +
 		"- history_window_days: Default uses all available history. Narrow to 30–60 days after a process change, or use 'recommended_window_days' from 'analyze_residence_time' when that tool returns a non-stationary signal (λ/θ > 1.1).\n" +
-		"- include_wip + include_existing_backlog: Set both to true for real commitment forecasts — this counts ALL outstanding work (started + unstarted). Omitting either understates the total scope.\n\n" +
+		"- include_wip + include_existing_backlog: Set both to true for real commitment forecasts — this counts ALL outstanding work (started + unstarted). Omitting either understates the total scope.\n" +
+		"- to_release (duration mode): Set when stakeholders ask for in-production dates rather than 'done' dates. Adds the historical resolution-to-release lag (see 'analyze_release_lag'); results land in 'context.released_percentiles'.\n" +
+		"- holidays / freeze_periods: Non-working dates and change-freeze ranges. Together with the configured working calendar (MCS_WORKDAYS, MCS_HOLIDAYS, MCS_FREEZE_PERIODS), throughput is sampled from working days only and nothing is delivered on non-working days; durations stay in calendar days. Ignored in sprint_mode.\n" +
+		"- sprint_mode: Scrum boards only. Samples per-sprint throughput of closed sprints (see 'analyze_sprint_history') and forecasts in sprints. Duration results are sprint counts, with projected end dates in 'context.sprint_end_dates'; scope mode requires target_sprints. Default sampling window is 26 weeks.\n" +
+		"- sampling: 'parametric' samples from a Weibull or lognormal distribution fitted to the observed throughput instead of the raw days (or sprints). Use it when fewer than ~30 items back the forecast; the fit lands in 'context.throughput_fit'. Per-type stratification is off in parametric mode.\n" +
+		"- stratification / stratification_min_sample: Whether issue types are sampled as streams of their own. 'auto' (default) stratifies a type with at least stratification_min_sample (default 15) finished items whose cycle times differ from the pool by 15% or more; 'on' stratifies every type with enough items; 'off' samples one pooled stream. 'context.stratification_decisions' lists each type with its decision, reason, and volume (the sample size), and 'context.stratification' the stratified types. Not supported with sprint_mode, units=points, or sources; 'on' not with sampling=parametric.\n" +
+		"- sampling_granularity: 'day' (default) samples every simulated day independently. 'week' replays whole weeks of consecutive observed days, so weekly batches and slow weeks stay together and the forecast spread is not understated; use it when 'analyze_throughput' reports batching (recommended_granularity 'week'). Echoed in 'context.sampling_granularity'. Not supported with sprint_mode, units=points, sampling=parametric, or sources.\n" +
+		"- capacity_factor / team_change: Capacity scenarios for 'what if' questions ('what if we lose two people in March?'). capacity_factor scales the sampled throughput for the whole forecast; team_change {from, to, effective_date} scales it by to/from from that date on (in sprint_mode, from the first sprint starting after it). Throughput is assumed to scale linearly with team size — say so when presenting the result, and run the unscaled forecast alongside for contrast. The scenario is echoed in 'context.capacity_scenario'.\n" +
+		"- model_arrivals (duration mode): Set when the backlog keeps growing while it is worked off. Also samples the historical arrival rate (items created per day) and forecasts the moving target; 'with_arrivals' reports those percentiles next to the fixed-scope ones. Not supported in sprint_mode or portfolio mode.\n" +
+		"- fix_version: Forecast a release. Narrows the board to the issues of that fixVersion, so include_wip and include_existing_backlog count only the release's unfinished items. Throughput is then sampled from the release's own delivered items; pass history_window_days wide enough to cover them.\n" +
+		"- labels / components: Forecast one sub-team of a shared board. Narrows backlog, WIP, and throughput to the issues carrying any of the labels (and, if both are given, in any of the components).\n" +
+		"- sources: Portfolio mode (see 'import_portfolio'). Samples the combined throughput of project_key/board_id and the listed boards, with shared issues attributed by 'duplicates' (default: counted once); backlog and WIP are counted with each board's own tiers. Not combinable with sprint_mode, start_status, to_release, fix_version, labels, or components.\n" +
+		"- units: 'points' forecasts story points (or other estimates) instead of items, from the estimation field JIRA_ESTIMATE_FIELD. Duration answers how long the backlog's points take; scope answers how many points get done. The item forecast runs alongside; relay the 'POINTS VS ITEMS' warning, which says how far the two disagree.\n" +
+		"- subtask_policy: 'include' counts sub-tasks as items of their own in throughput, backlog, and WIP; 'rollup' leaves them out but treats a parent as started once its first sub-task is. Both need sub-tasks in the history (MCS_SUBTASK_POLICY).\n" +
+		"- outliers: 'winsorize' caps days of extreme throughput (e.g. a bulk closure) at the P99; 'iqr' drops days outside the IQR fences. 'context.outliers' reports how many items were trimmed. Default: the server setting MCS_OUTLIER_POLICY (none).\n" +
+		"- outcome_scope: 'delivered' (default) samples the throughput of delivered items, for forecasts of delivered value. 'all' samples every finished item, for forecasts of how much work the team will get off its plate when the backlog will also be abandoned in part. Say which scope was used when presenting the result.\n" +
+		"- as_of_date: Reproduce the forecast made on a past date ('what did the forecast say in January?'): backlog, WIP, and throughput sample come from the history known on that date, and completion dates count from it. The run is recorded with that date. Compare with 'compare_forecasts' or the actual delivery. Not supported with sprint_mode.\n" +
+		"- dry_run: Resolves the source, ingests, filters, and counts the scope exactly as a real run, then returns the targets per type and the throughput sample (days, zero days, mean per day, type mix, dropped items) instead of simulating. Use it when a forecast comes out infinite or implausibly small, before re-running. Not supported with sprint_mode, units=points, or sources.\n" +
+		"- explain: Set when stakeholders ask why the forecast says what it says or why the date moved. Reruns the forecast with one input changed at a time, on the same random seed: only the recent or the older half of the sample, throughput -20% and +20%, abandoned items counted as delivered, and the opposite backflow policy (duration mode with include_wip). 'explain.drivers' lists each with its P85 and a one-line summary such as 'With throughput -20%, P85 moves from 42 → 55 days.' Not supported with sprint_mode, units=points, dry_run, or sources.\n" +
+		"- template: Name of a forecast template of the board (see 'save_forecast_template'). It fills issue_types, start_status, targets, mix_overrides, include_existing_backlog, include_wip, additional_items, and history_window_days where the call leaves them out; parameters passed to the call win. The template is echoed in 'context.forecast_template'.\n" +
+		"- ensemble (duration mode): Set for high-stakes commitments, to check the throughput forecast against a second model. Also forecasts the scope from item cycle times: every item gets a cycle time sampled from delivered items, in-progress items finish the rest of a cycle time longer than their age, and at most wip_limit items are worked at once (default: the Downstream tier limit of 'set_wip_limits', else the current WIP, else Little's law). 'ensemble' reports its percentiles, the parallelism and where it came from, and 'disagreement' — the P85 gap relative to the larger P85 — as 'model_risk' low (<15%), moderate, or high (≥35%). Relay the 'ENSEMBLE' line; at high model risk present both dates, not the more convenient one. Not supported with sprint_mode, units=points, dry_run, or sources.\n\n" +
+		"OUTPUT: Duration results carry 'context.completion_dates' — each percentile as a projected calendar date. Every run is recorded; 'context.forecast_id' identifies it for 'compare_forecasts'.\n\n" +
 		"FAILURE HANDLING: If the tool fails or returns zero throughput, do not provide estimated dates or probabilities. " +
 		"If the result is unexpectedly far in the future, warn the user that throughput sampling may be too low due to filtered resolutions or issue types.\n\n" +
 		"STATIONARITY ASSESSMENT: The result includes 'stationarity_assessment' in the 'context' field. " +
 		"When 'stationary' is false, surface the warnings to the user and suggest re-running with 'recommended_window_days'. " +
 		"Run 'forecast_backtest' first when stationarity is uncertain.",
 
+	"forecast_epic": "Forecasts when an epic or initiative will be done. Walks the parent's child items via the Jira issue hierarchy and runs a duration simulation scoped to the unfinished children, using their actual issue-type mix.\n\n" +
+		"WHEN TO USE: 'When will epic X be finished?' — instead of counting children by hand and passing them to 'forecast_monte_carlo' as additional_items.\n" +
+		"WHEN NOT TO USE: For scope or capacity questions, or to model expected growth of the epic — use 'forecast_monte_carlo' with targets.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- epic_key: Any parent issue. Intermediate parents (epics under an initiative) are containers; only leaf items are forecast.\n" +
+		"- history_window_days: Same meaning as in 'forecast_monte_carlo'.\n\n" +
+		"INTERPRETATION: 'context.epic_rollup' lists delivered, abandoned, in-progress (past the type's commitment point) and not-started children, plus the remaining keys. " +
+		"Only children visible through the board's filter are counted. Epics usually grow after they start, so treat the forecast as a lower bound and re-run as children are added.",
+
+	"forecast_item": "Forecasts when a single in-flight issue will be done. Simulates the rest of its journey: how much longer it stays in its current status given the days it has already spent there, then the statuses after it in the confirmed status order, each visited at its historical rate with a residence time sampled from delivered items.\n\n" +
+		"WHEN TO USE: 'When will PROJ-123 ship?' for one item that is already in progress.\n" +
+		"WHEN NOT TO USE: For several items, an epic, or a backlog — use 'forecast_epic' or 'forecast_monte_carlo'. To explain why an item is late, use 'analyze_item_journey'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- history_window_days: Lookback for the residence-time sample. Defaults to the session analysis window. Residence times come from delivered items of the same type when there are at least 20, otherwise from all delivered items.\n\n" +
+		"OUTPUT: Percentiles are remaining calendar days; 'context.completion_dates' gives each as a date. 'context.remaining_path' lists the statuses still ahead with their visit rates and P50/P85 residence times. " +
+		"Requires the confirmed status order ('workflow_set_order'); without it, the order inferred from history is used.",
+
+	"forecast_burnup": "Forecasts a burn-up cone: for each week up to the horizon, the cumulative number of items delivered at least with 50%, 85% and 95% probability. Runs the scope-mode Monte-Carlo simulation once and records every trial's running total at the end of each week.\n\n" +
+		"WHEN TO USE: 'How much will we have delivered week by week over the next quarter?', drawing a forecast cone on a release plan or roadmap, or showing how uncertainty widens with the horizon.\n" +
+		"WHEN NOT TO USE: For one target date, use 'forecast_monte_carlo' in scope mode. For a completion date of known scope, use 'forecast_monte_carlo' in duration mode. For the actual burn-up of a release, use 'analyze_release_burnup'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- horizon_weeks: 1–52, default 12. Each week adds one point to 'burn_up'.\n" +
+		"- history_window_days: Lookback for the daily throughput sample. Default 90 days, as in 'forecast_monte_carlo'.\n" +
+		"- holidays / freeze_periods: As in 'forecast_monte_carlo'. Non-working days deliver nothing, so the cone flattens over them.\n\n" +
+		"OUTPUT: 'burn_up' lists one point per week with its date and the P50/P85/P95 cumulative items. Like all scope forecasts, P85 is the count delivered AT LEAST with 85% probability, so P95 ≤ P85 ≤ P50. 'percentiles' are the full scope percentiles at the horizon. " +
+		"The cone assumes the sampled throughput continues unchanged; it does not subtract work that arrives meanwhile.",
+
+	"forecast_timebox": "Forecasts whether the unfinished items committed to a sprint or other timebox are delivered by its last day: the probability of hitting the commitment, and how many items to de-scope to hit it at 85% confidence. Runs the scope-mode Monte-Carlo simulation over the days left, counting today.\n\n" +
+		"WHEN TO USE: 'Will we make the sprint?', 'What should we drop to keep the commitment?', a mid-sprint check of an active sprint, or any fixed-date commitment of a known item set.\n" +
+		"WHEN NOT TO USE: For how much gets done by a date without a committed set, use 'forecast_monte_carlo' in scope mode. For the completion date of a backlog, use duration mode. For past sprints, use 'analyze_sprint_history'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- sprint_id: The sprint whose items and end date form the timebox. Omit it, and issue_keys, for the board's active sprint. Not available for JQL or filter sources.\n" +
+		"- issue_keys / start_date / end_date: A timebox that is not a sprint, or overrides of the sprint's items and dates. end_date is the last day of the timebox.\n" +
+		"- history_window_days: Lookback for the daily throughput sample. Default 90 days, as in 'forecast_monte_carlo'.\n" +
+		"- holidays / freeze_periods: As in 'forecast_monte_carlo'.\n\n" +
+		"OUTPUT: 'timebox.probability' is the share of trials delivering all 'timebox.remaining' items by the end date. 'timebox.descope_p85' is the number of items to move out so the rest are delivered with 85% probability ('descope_p50' for a coin toss). " +
+		"'context.timebox' lists the committed, done and remaining items; committed items already delivered do not count, and abandoned ones are dropped. 'percentiles' are the items delivered by the end date, with the scope convention. " +
+		"The sampled throughput includes unplanned work, so unplanned work that keeps arriving makes the forecast optimistic.",
+
+	"forecast_scenarios": "Runs several what-if variants of one Monte-Carlo forecast in a single call and returns them side by side: different additional_items, capacity factors, team changes, type mixes, or target dates. The board is loaded and the throughput sample built once, and all scenarios share one random seed, so the differences come from the scenarios alone.\n\n" +
+		"WHEN TO USE: 'What if we add 20 items?', 'What if we lose a third of the team?', 'June or July?' — any comparison of two or more forecast variants. Faster and more consistent than calling 'forecast_monte_carlo' once per variant.\n" +
+		"WHEN NOT TO USE: For a single forecast, use 'forecast_monte_carlo'. To compare a forecast with an earlier run, use 'compare_forecasts'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- mode, include_existing_backlog, include_wip, additional_items, target_days / target_date: The base forecast, as in 'forecast_monte_carlo'.\n" +
+		"- scenarios: 1–10 what-ifs. Fields a scenario leaves out keep the base value; additional_items replaces the base count (duration mode), target_days / target_date the base horizon (scope mode). Put the plan of record first — every other scenario is compared with it.\n" +
+		"- history_window_days, holidays, freeze_periods, sampling, outcome_scope: Shared by all scenarios.\n\n" +
+		"OUTPUT: 'scenarios' has one row per scenario with its P50, P85, and P95 (days in duration mode, items in scope mode), 'p85_date' in duration mode, and 'p85_change' against the first scenario. " +
+		"Scenario runs are not recorded for 'compare_forecasts'. Not supported with sprint_mode, units=points, or sources — run 'forecast_monte_carlo' for those.",
+
+	"analyze_throughput_histogram": "Shows the daily throughput histogram that 'forecast_monte_carlo' samples from: the delivered items per day, the same counts per issue type, and the histogram meta (type distribution, volatility, dependencies between types, stratification decisions, and the items dropped by window or resolution).\n\n" +
+		"WHEN TO USE: To sanity-check a forecast — 'What is the simulation actually sampling?', 'Why is the forecast so wide / so slow?', 'Are bugs forecast separately?'\n" +
+		"WHEN NOT TO USE: For delivery trends over weeks or months, use 'analyze_throughput'. To check a whole forecast setup including its scope, use 'forecast_monte_carlo' with dry_run=true.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- history_window_days / history_start_date / history_end_date: The same sampling window as in 'forecast_monte_carlo' (default 90 days). Pass the values of the forecast you are checking.\n" +
+		"- holidays / freeze_periods: As in 'forecast_monte_carlo'. With a working calendar, deliveries on non-working days are credited to the next working day and only working days are listed.\n" +
+		"- outcome_scope: As in 'forecast_monte_carlo'. Pass the scope of the forecast you are checking.\n\n" +
+		"OUTPUT: 'counts[i]' is the number of items delivered on 'dates[i]'; 'stratified_counts' holds one such row per type. 'summary' condenses both. " +
+		"This is the histogram of the default 'crude' engine; the 'bbak' engine (MCS_ENGINE) may sample a narrower window than the one shown.",
+
+	"compare_forecasts": "Compares two recorded 'forecast_monte_carlo' runs and reports how the forecast moved: P50/P85 drift (with projected completion dates in day-based duration mode), scope composition, per-type targets, and the sampled throughput and type mix.\n\n" +
+		"WHEN TO USE: 'How has our forecast moved since last month?', 'Why is the date slipping?' Every forecast_monte_carlo run is recorded and returns its ID as 'context.forecast_id'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- With only project_key/board_id: compares the latest run with the one before it.\n" +
+		"- baseline_date: compares the latest run with the latest run made on or before that date.\n" +
+		"- baseline_id / current_id: compare specific runs.\n\n" +
+		"Only runs of the same mode and time unit (days or sprints) can be compared.",
+
+	"record_commitment": "Records a delivery commitment made to stakeholders — a scope promised by a date at a confidence level — so that 'analyze_commitment_health' can track it. Keeps a snapshot of the forecast it was made from and checks it once as its baseline.\n\n" +
+		"WHEN TO USE: After a date has been committed from a forecast: 'We told the business 40 items by September at 85%.' Also to withdraw a commitment that was renegotiated.\n" +
+		"WHEN NOT TO USE: To forecast a date, use 'forecast_monte_carlo' first. For a sprint, use 'forecast_timebox'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- forecast_id: The 'context.forecast_id' of the day-based duration forecast behind the commitment. target_date defaults to its completion date at 'percentile', items to its scope, issue_types to its issue types.\n" +
+		"- target_date / items: The commitment as made when it differs from the forecast, or without a forecast.\n" +
+		"- issue_keys: The committed items, when known. Without them, progress is every delivery of the board (of issue_types) since the commitment, which includes unplanned work.\n" +
+		"- withdraw: Commitment IDs to close as withdrawn, e.g. before recording a renegotiated one.\n\n" +
+		"OUTPUT: 'commitment' with its ID ('{source}-C{n}'), the forecast snapshot, and 'baseline' — the remaining items and the probability of hitting the date as of today.",
+
+	"analyze_commitment_health": "Checks every open commitment recorded with 'record_commitment': the current probability of delivering the remaining items by the committed date, and how it has moved since the commitment and since the previous check. Each run is kept as a check, so run it regularly to build the trend.\n\n" +
+		"WHEN TO USE: 'Are we still on track for what we promised?', 'Which commitments are at risk?', a weekly or monthly accountability review.\n" +
+		"WHEN NOT TO USE: For a new forecast, use 'forecast_monte_carlo'. To see why the forecast itself moved, use 'compare_forecasts'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- history_window_days: Lookback for the daily throughput sample. Default 90 days, as in 'forecast_timebox'.\n\n" +
+		"OUTPUT: 'commitments' lists the commitments checked today with 'probability', 'remaining', 'at_risk' (below the committed percentile), 'change_since_commitment', 'change_since_previous', 'trend', and every check so far. " +
+		"Commitments whose items are all delivered close as met, those past their date as missed. 'closed' counts the closed commitments by status. " +
+		"Probabilities come from the scope simulation of 'forecast_timebox' over the days left, counting today.",
+
 	"forecast_backtest": "Validates Monte-Carlo forecast accuracy via Walk-Forward Analysis — reconstructs past system states and checks whether actual outcomes fell within predicted ranges.\n\n" +
 		"WHEN TO USE: Before committing to a forecast when stationarity is uncertain. " +
 		"User asks: 'How accurate are our forecasts historically?', 'Should we trust the Monte Carlo result?'\n\n" +
 		"PARAMETER GUIDANCE:\n" +
 		"- simulation_mode: Use the same decision rule as 'forecast_monte_carlo' — duration for deadline questions, scope for capacity questions.\n" +
 		"- history_window_days: Controls how many checkpoints are generated (default 175 days = ~25 weekly checkpoints). " +
-		"This is NOT the per-checkpoint sampling window — each checkpoint always samples from a fixed 90-day window ending at that point.\n\n" +
+		"This is NOT the per-checkpoint sampling window — each checkpoint samples from a 90-day window ending at that point (see sweep_sample_days).\n" +
+		"- precision: 'standard' (default, 10000 trials per checkpoint) or 'fast' (1000 trials per checkpoint). Fast is roughly 10x quicker but percentiles are ~3x noisier — good for a first look, re-run with 'standard' before drawing conclusions. " +
+		"- sweep_sample_days: Compare per-checkpoint sampling windows (e.g. [30, 60, 90, 180]) when the user asks how much history to feed the simulation. 'window_sweep.recommended_sample_days' is the best-calibrated window; 'accuracy' then holds that window's backtest. Each window is a full backtest, so combine with precision='fast' for a first look.\n" +
+		"Clients that send a progress token receive one progress notification per checkpoint; cancelling the request stops the backtest.\n\n" +
 		"INTERPRETATION: Key field is 'stationarity_correlation.signal'. " +
 		"'predictive' means non-stationary checkpoints miss at >2x the rate of stationary ones — the stationarity guardrail is validated for this project; surface stationarity warnings prominently. " +
-		"'not_predictive' means both groups miss at similar rates — stationarity may not be the main accuracy driver here.",
+		"'not_predictive' means both groups miss at similar rates — stationarity may not be the main accuracy driver here. " +
+		"'calibration' lists, for P50/P85/P95, the fraction of checkpoints whose actual outcome honored the forecast (finished within the predicted days, or delivered at least the predicted items). " +
+		"A P85 honored at ~85% is 'calibrated'; 'overconfident' means commitments at that level are riskier than stated.",
 
 	// ── GROUP: Import & Setup ─────────────────────────────────────────────────
 	// Canonical setup sequence is documented in serverInstructions (instructions.go).
 
 	"import_projects": "Searches for Jira projects by name or key.\n\n" +
+		"With several Jira instances configured (JIRA_INSTANCES), pass 'instance' to choose the connection; every import_* tool accepts it, and the chosen instance stays active for all later calls until another is selected. Switching drops the active board context.\n\n" +
 		"Next step: call 'import_boards' with the project key to find the board ID needed for all analytical tools.",
 
 	"import_boards": "Searches for Agile boards, optionally filtering by project key or name.\n\n" +
 		"Next step: call 'import_board_context' with the board ID to anchor the data shape context.",
 
 	"import_board_context": "Returns a Data Shape Anchor — whole dataset volumes vs. sample distributions — for a specific Agile board.\n\n" +
+		"To analyze issues no board covers (e.g. a cross-project label query), pass 'jql' or 'filter_id' instead of project_key/board_id. The response reports the synthetic source (project_key JQL or FILTER with its board_id); every analyze_*, forecast_*, and workflow_* tool accepts the same 'jql'/'filter_id'.\n\n" +
 		"MUST be called before 'workflow_discover_mapping'. Next step: call 'workflow_discover_mapping'.",
 
 	"import_project_context": "Returns a Data Shape Anchor for a project (not board-level). Use for general project metadata only.\n\n" +
 		"NOTE: All analytical tools require a Board ID. If you plan to run diagnostics or forecasts, use 'import_board_context' instead.",
 
+	"import_portfolio": "Loads several boards as one portfolio and reports how their issues consolidate: per-board volumes and the issues shared between boards.\n\n" +
+		"WHEN TO USE: Program-level questions spanning several teams or boards (e.g. 'when will the release train finish?', 'what is our combined throughput?').\n\n" +
+		"PREREQUISITE: Every board needs a confirmed workflow mapping ('import_board_context' → 'workflow_discover_mapping' → 'workflow_set_mapping'), since tiers and commitment points are applied per board. 'workflow_confirmed' shows which boards are ready.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- duplicates: How an issue listed by several boards (overlapping filters) is counted: 'first' (default) once, on the board that resolved it; 'split' once, with an equal share per board in 'portfolio.sources'; 'all' on every board. Pass the same value to the portfolio analyses. A warning lists the shared keys.\n\n" +
+		"Next step: pass the other boards as 'sources' to 'forecast_monte_carlo', 'analyze_throughput', or 'analyze_work_item_age' (with the first board as project_key/board_id).",
+
 	"import_history_update": "Incrementally fetches items changed since the last sync to keep the local cache current.\n\n" +
-		"WHEN TO USE: At the start of any session to ensure analysis reflects recent Jira changes. This is a lightweight forward-only sync. " +
-		"To extend history further back than the current cache, raise INGESTION_CREATED_LOOKBACK / INGESTION_UPDATED_LOOKBACK in .env and re-hydrate via 'import_board_context' (after deleting the existing cache file).",
+		"WHEN TO USE: When the user needs analysis to reflect Jira changes made in the last few minutes. Other tools delta-sync automatically once the cache is older than INGESTION_SYNC_INTERVAL (default 10 minutes); this tool syncs immediately. This is a lightweight forward-only sync. " +
+		"To extend history further back than the current cache, raise INGESTION_CREATED_LOOKBACK / INGESTION_UPDATED_LOOKBACK in .env and re-hydrate via 'import_board_context' (after deleting the existing cache file). " +
+		"An interrupted initial hydration is resumed first.",
+
+	"import_history_status": "Reports the cached history per source: event and issue counts, first and last event, OMRC/NMRC boundaries, last sync, and the gaps a sync still has to fill.\n\n" +
+		"WHEN TO USE: Before deep-history analysis, after a crash or restart during a large hydration, or when the user asks how complete or current the cached data is.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- project_key / board_id: One source. Omit both to list every cached board.\n\n" +
+		"OUTPUT: 'sources' lists one status per source. 'complete' is false while the initial hydration is interrupted or after it hit INGESTION_MAX_ITEMS. Gap kinds: 'backfill' (interrupted initial hydration; 'backfill' holds its checkpoint, and the next sync resumes below it), 'truncated' (history older than the OMRC was cut by INGESTION_MAX_ITEMS), 'head' (changes since the last sync; 'import_history_update' fetches them), 'watermark' (persisted watermark disagrees with the cached events; heals on the next sync). " +
+		"Reads the cache only; it never calls Jira.",
+
+	"export_workspace": "Exports the complete analysis workspace — confirmed workflow mappings, status orders, commitment points, resolution mappings, and evaluation dates for every board, plus the forecast registries that 'compare_forecasts' reads and the recorded commitments — into a single .zip bundle. No Jira issue data is included.\n\n" +
+		"WHEN TO USE: User wants to move to another machine, back up their configuration, or hand a configured setup to someone else (e.g. a client's internal team).\n\n" +
+		"Next step on the target machine: 'import_workspace' with the bundle path.",
+
+	"export_dataset": "Writes the delivered items of the session window with their cycle times, their residency per status, and the event log to CSV files, and returns their paths.\n\n" +
+		"WHEN TO USE: The user wants to continue the analysis outside this server (e.g. in Python or a spreadsheet) without re-fetching Jira.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- dir: Optional destination directory inside the cache directory, relative to its 'exports' directory or absolute. Paths outside the cache directory are rejected. Default: a new directory under 'exports'.\n\n" +
+		"OUTPUT: 'files' holds the path and row count of 'items' (one row per delivered item: dates, resolution, cycle_time_days from the commitment point, components, labels, parent, fix versions, estimate, assignee, team), " +
+		"'residency' (days and blocked days per item and status, with the status tier) and 'events' (status, resolution, and flag changes of every item in the window). All files share the 'key' column. " +
+		"Multi-valued columns are joined by ';'. With MCS_ANONYMIZE set, keys and assignees are pseudonyms. There is no Parquet output; the files convert with pandas or polars.",
+
+	"import_workspace": "Restores a workspace bundle created by 'export_workspace' into the local cache directory.\n\n" +
+		"WHEN TO USE: User received or moved a workspace bundle and wants to continue with the same board configuration.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- overwrite: Default false keeps any existing local configuration for the same board. Ask the user before setting true.\n\n" +
+		"Next step: 'import_board_context' for each board — issue history is fetched from Jira, the restored mapping is applied automatically.",
+
+	"import_throughput_csv": "Imports throughput recorded outside Jira (e.g. a spreadsheet from before the team used Jira) for a board. Forecasts then sample the imported days before the first Jira delivery of their sample window next to the Jira history.\n\n" +
+		"WHEN TO USE: The team has only a short Jira history but older throughput records, and forecasts from the Jira history alone are too sparse.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- path / csv: Set either a CSV file or its content: date,count rows of delivered items, e.g. '2024-01-01,7'. Re-importing replaces the previous import.\n" +
+		"- granularity: 'weekly' (default) spreads each week's count over its seven days; 'daily' takes the counts as they are.\n" +
+		"- remove: Deletes the import; forecasts sample Jira history only again.\n\n" +
+		"OUTPUT: The imported range, items, and mean per day. Forecasts that sample imported days report it in context.external_throughput and a HYBRID SAMPLE warning; AI MUST mention the warning when presenting them. " +
+		"The imported days carry no issue types, so such forecasts do not stratify by type. Jira history wins where both cover a day.",
 
 	"workflow_discover_mapping": "Probes status categories, residence times, and resolution frequencies to propose a semantic workflow mapping for user verification.\n\n" +
 		"AI MUST present the proposed tier mapping AND the 'status_order' array to the user for verification. " +
@@ -203,8 +534,13 @@ var toolDescriptions = map[string]string{
 		"WITHOUT this step, ALL analytical tools will return subpar or incorrect results.\n\n" +
 		"AI MUST verify with the user before calling:\n" +
 		"1. Tier assignments (Demand, Upstream, Downstream, Finished) for all statuses.\n" +
-		"2. Commitment Point: the first Downstream status where the clock starts.\n" +
+		"2. Commitment Point: the first Downstream status where the clock starts. " +
+		"If some issue types start elsewhere (e.g. Bugs at 'Triaged', Stories at 'In Development'), pass 'commitment_points_by_type'; 'analyze_definition_of_workflow' suggests candidates.\n" +
 		"3. Outcomes: only required for Finished-tier statuses when Jira resolutions are missing or unreliable.\n\n" +
+		"COLUMNS: A key 'column:<name>' maps every status of that board column at once (e.g. 'column:In Progress' for parallel 'In Dev' and 'In Review'); entries for single statuses win over their column. " +
+		"'commitment_point' also accepts 'column:<name>', which resolves to the column's first status.\n\n" +
+		"VALIDATION: Status keys and commitment points must be statuses of the project or of the board's history, by name (any case) or ID. Unknown ones reject the whole call with 'did you mean' suggestions; AI MUST correct them with the user, not guess. " +
+		"Statuses in the board's history the mapping leaves out are reported as UNMAPPED STATUSES. Statuses that appear after this call (a changed Jira workflow) raise a WORKFLOW CHANGED warning on later analyses.\n\n" +
 		"METAWORKFLOW GUIDANCE:\n" +
 		"- TIERS: 'Demand' (Backlog), 'Upstream' (Analysis/Refinement), 'Downstream' (Development/Execution/Testing), 'Finished' (Terminal).\n" +
 		"- ROLES: 'active' (Value-adding work), 'queue' (Waiting), 'ignore' (Admin). Omit for 'Finished' tier.\n" +
@@ -215,8 +551,60 @@ var toolDescriptions = map[string]string{
 		"This order drives CFD charts, flow debt analysis, and all range-based analytics. " +
 		"If the user accepts the discovered order unchanged, pass it back as-is.",
 
+	"workflow_export_mapping": "Exports the confirmed workflow of a board — status tiers, roles and outcomes, status order, commitment points, and resolution outcomes — as a portable JSON mapping document keyed by status and resolution names.\n\n" +
+		"WHEN TO USE: The user has confirmed the mapping of one board and wants to apply it to other boards with a similar workflow instead of repeating the discovery and confirmation loop per board.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- path: Optional file to also write the document to.\n\n" +
+		"Next step: 'workflow_import_mapping' for each target board.",
+
+	"workflow_import_mapping": "Applies a mapping document from 'workflow_export_mapping' to a board and persists it, as 'workflow_set_mapping' and 'workflow_set_order' would.\n\n" +
+		"WHEN TO USE: Several boards share a workflow and the mapping was confirmed on one of them.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- path / document: Set either the file written by the export or the exported 'document' JSON.\n" +
+		"- overwrite: Default false refuses to replace a mapping already confirmed for the board. Ask the user before setting true.\n\n" +
+		"OUTPUT: Statuses and resolutions are matched by name on the target board (then by ID). 'unmatched' lists document entries the target does not have; 'unmapped_statuses' lists statuses in the target's history the document does not cover. " +
+		"AI MUST show both lists to the user and resolve gaps with 'workflow_set_mapping'/'workflow_set_order'.",
+
+	"workflow_set_type_aliases": "Groups semantically identical issue types under one canonical type for forecasting (e.g. 'User Story' and 'Improvement' as 'Story'). Persisted with the board's workflow.\n\n" +
+		"WHEN TO USE: Several Jira issue types mean the same kind of work, so per-type throughput streams are split into sparse buckets and stratified simulation suffers. " +
+		"Confirm the grouping with the user first.\n" +
+		"WHEN NOT TO USE: Do not merge types that differ in size or flow (e.g. Bugs and Stories) — that hides real variation the stratified model relies on.\n\n" +
+		"OUTPUT: Each canonical type with its aliases and the delivered items per member type in the session window.",
+
+	"workflow_get_audit": "Returns the audit trail of a board's semantic configuration: every change made by a set_* tool (mapping, resolutions, commitment points, status order, SLEs, WIP limits, type aliases, source settings, evaluation date), newest first.\n\n" +
+		"WHEN TO USE: The user asks who or when the configuration changed, or a forecast or diagnostic moved and a configuration change might explain it.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- field: Narrow the trail to one configuration field, e.g. 'commitment_point'.\n\n" +
+		"OUTPUT: Each entry carries its time, the tool, the MCP client that made the call, the field, and its previous and new value (omitted when unset). 'total' counts all matching entries.",
+
+	"get_source_settings": "Returns the stored parameter defaults of a board (see 'set_source_settings') and, per parameter, the value calls default to and whether it comes from the source or the server.\n\n" +
+		"WHEN TO USE: Before a series of analyses, to know which defaults apply without repeating parameters, or when a result used an unexpected window, sample, or issue type filter.\n\n" +
+		"OUTPUT: 'settings' holds the stored values, 'effective' each parameter's default with its origin ('source' or 'server'), and 'delivered_resolutions' the resolutions counted as delivered (change those with 'workflow_set_mapping').",
+
+	"set_source_settings": "Stores per-board defaults for parameters that would otherwise be repeated in every call: default window length, forecast sample, issue type filter of forecasts, subtask policy, outlier policy, and result format. Persisted with the board's workflow and audited.\n\n" +
+		"WHEN TO USE: The user settles on a convention for a board (e.g. 'always forecast Stories only', 'use a year of history') and wants it kept across calls and sessions.\n" +
+		"WHEN NOT TO USE: For a one-off scope, pass the parameter to the call or use 'set_analysis_window' — an explicit parameter or window always wins over a setting.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- Only the given settings change; the others keep their value.\n" +
+		"- reset: Drop all stored settings first. reset alone restores the server defaults.\n" +
+		"- issue_types: Applies to forecast_monte_carlo (without targets), forecast_burnup, and forecast_backtest calls that pass no issue_types.\n" +
+		"- The percentile ladder has no setting: every forecast reports the same levels (P10 to P98), so runs of different boards and dates stay comparable in 'compare_forecasts', backtests, and commitments.\n\n" +
+		"OUTPUT: Same as 'get_source_settings'. Confirm the new defaults with the user.",
+
+	"save_forecast_template": "Saves a named set of forecast_monte_carlo parameters of a board — issue types, start status, targets, mix overrides, backlog and WIP inclusion, additional items, and history window — so recurring forecasts need only the template name. Persisted with the board's workflow and audited.\n\n" +
+		"WHEN TO USE: The user re-runs the same forecast regularly (e.g. 'the quarterly roadmap forecast', 'the release forecast every Monday') and wants its scope kept across sessions.\n" +
+		"WHEN NOT TO USE: For defaults that apply to every call of a board, use 'set_source_settings'.\n\n" +
+		"PARAMETER GUIDANCE:\n" +
+		"- name: Saving under an existing name replaces that template. With remove=true the template is deleted.\n" +
+		"- Parameters are checked as forecast_monte_carlo checks them; a template must set at least one.\n\n" +
+		"OUTPUT: Same as 'list_forecast_templates'. Run the forecast with forecast_monte_carlo template=<name>.",
+
+	"list_forecast_templates": "Lists the forecast templates saved for a board (see 'save_forecast_template') with their parameters, sorted by name.\n\n" +
+		"WHEN TO USE: Before a recurring forecast, to find the template to pass to 'forecast_monte_carlo', or when a templated forecast used an unexpected scope.",
+
 	"workflow_set_evaluation_date": "Sets a custom evaluation date so all time-based calculations use that date instead of today.\n\n" +
-		"WHEN TO USE: Historical scenario analysis, or when the user wants to evaluate the system state as of a specific past date.",
+		"WHEN TO USE: Historical scenario analysis, or when the user wants to evaluate the system state as of a specific past date.\n" +
+		"WHEN NOT TO USE: For a single look back (e.g. 'what did the forecast say in January?'), pass as_of_date to 'forecast_monte_carlo', 'analyze_throughput', or 'analyze_work_item_age' instead; it leaves the persisted evaluation date alone.",
 
 	"set_analysis_window": "Sets the session analysis window — a single [start, end] range that ALL windowed diagnostics use.\n\n" +
 		"WHEN TO USE: When the user wants to scope multiple analyses to the same period (e.g. 'analyse Q1', 'look at the last 8 weeks', 'move one month back'). " +
@@ -233,6 +621,12 @@ var toolDescriptions = map[string]string{
 	"get_analysis_window": "Returns the currently active session analysis window and its source ('session' if explicitly set, 'default' otherwise).\n\n" +
 		"WHEN TO USE: To verify which window will scope subsequent diagnostics before running them, or to confirm a 'set_analysis_window' call took effect.",
 
+	"set_visual_preferences": "Toggles Mermaid diagrams in analytical responses for the rest of the session.\n\n" +
+		"WHEN TO USE: The user wants charts inline in the conversation, e.g. because their client renders Mermaid but cannot open a browser, or wants them switched off again to save tokens.\n\n" +
+		"OUTPUT: With mermaid_charts=true, responses of the listed tools carry a 'visuals' array of server-generated Mermaid diagrams. " +
+		"Show each one unchanged in a ```mermaid block. The chart_url of open_in_browser is unaffected.\n\n" +
+		"PERSISTENCE: In-memory only. Off by default and after a server restart.",
+
 	"guide_diagnostic_roadmap": "Returns a recommended sequence of analysis steps tailored to a specific analytical goal.\n\n" +
 		"WHEN TO USE: At the start of a session when the user's goal is clear but the right tool sequence is not. " +
 		"Goals: 'forecasting', 'bottlenecks', 'capacity_planning', 'system_health'.",
@@ -245,14 +639,31 @@ var toolDescriptions = map[string]string{
 
 // customSchemas maps Go enum types to their JSON Schema representations.
 var customSchemas = map[reflect.Type]*jsonschema.Schema{
-	reflect.TypeFor[SimulationMode]():  {Type: "string", Enum: []any{SimModeDuration, SimModeScope}},
-	reflect.TypeFor[AgeType]():         {Type: "string", Enum: []any{AgeTypeTotal, AgeTypeWIP}},
-	reflect.TypeFor[TierFilter]():      {Type: "string", Enum: []any{TierFilterWIP, TierFilterDemand, TierFilterUpstream, TierFilterDownstream, TierFilterFinished, TierFilterAll}},
-	reflect.TypeFor[DiagnosticGoal]():  {Type: "string", Enum: []any{GoalForecasting, GoalBottlenecks, GoalCapacityPlanning, GoalSystemHealth}},
-	reflect.TypeFor[Granularity]():     {Type: "string", Enum: []any{GranularityDaily, GranularityWeekly}},
-	reflect.TypeFor[WorkflowTier]():    {Type: "string", Enum: []any{TierDemand, TierUpstream, TierDownstream, TierFinished}},
-	reflect.TypeFor[WorkflowRole]():    {Type: "string", Enum: []any{RoleActive, RoleQueue, RoleIgnore}},
-	reflect.TypeFor[WorkflowOutcome](): {Type: "string", Enum: []any{OutcomeDelivered, OutcomeAbandoned}},
+	reflect.TypeFor[SimulationMode]():        {Type: "string", Enum: []any{SimModeDuration, SimModeScope}},
+	reflect.TypeFor[InterferenceDimension](): {Type: "string", Enum: []any{InterferenceByIssueType, InterferenceByComponent}},
+	reflect.TypeFor[StreamDimension]():       {Type: "string", Enum: []any{StreamByComponent, StreamByEpic, StreamByLabel}},
+	reflect.TypeFor[GroupDimension]():        {Type: "string", Enum: []any{GroupByAssignee, GroupByTeam, GroupByComponent}},
+	reflect.TypeFor[BucketAlignment]():       {Type: "string", Enum: []any{AlignCalendar, AlignRolling}},
+	reflect.TypeFor[BacktestPrecision]():     {Type: "string", Enum: []any{PrecisionStandard, PrecisionFast}},
+	reflect.TypeFor[SamplingMode]():          {Type: "string", Enum: []any{SamplingEmpirical, SamplingParametric}},
+	reflect.TypeFor[StratificationMode]():    {Type: "string", Enum: []any{StratificationAuto, StratificationOn, StratificationOff}},
+	reflect.TypeFor[SampleGranularity]():     {Type: "string", Enum: []any{SampleByDay, SampleByWeek}},
+	reflect.TypeFor[ForecastUnits]():         {Type: "string", Enum: []any{UnitsItems, UnitsPoints}},
+	reflect.TypeFor[render.Format]():         {Type: "string", Enum: []any{render.JSON, render.Markdown, render.CSV}},
+	reflect.TypeFor[stats.SubtaskPolicy]():   {Type: "string", Enum: []any{stats.SubtasksExclude, stats.SubtasksInclude, stats.SubtasksRollup}},
+	reflect.TypeFor[stats.OutlierPolicy]():   {Type: "string", Enum: []any{stats.OutliersNone, stats.OutliersWinsorize, stats.OutliersIQR}},
+	reflect.TypeFor[stats.DuplicatePolicy](): {Type: "string", Enum: []any{stats.DuplicatesFirst, stats.DuplicatesSplit, stats.DuplicatesAll}},
+	reflect.TypeFor[stats.ContainerPolicy](): {Type: "string", Enum: []any{stats.ContainersExclude, stats.ContainersInclude}},
+	reflect.TypeFor[stats.OutcomeScope]():    {Type: "string", Enum: []any{stats.OutcomesDelivered, stats.OutcomesAbandoned, stats.OutcomesAll}},
+	reflect.TypeFor[stats.SlowMeasure]():     {Type: "string", Enum: []any{stats.SlowByCycleTime, stats.SlowByStatusTime, stats.SlowByBlockedTime}},
+	reflect.TypeFor[ItemSort]():              {Type: "string", Enum: []any{SortByAge, SortByPercentile, SortByCycleTime, SortByDate, SortByKey}},
+	reflect.TypeFor[AgeType]():               {Type: "string", Enum: []any{AgeTypeTotal, AgeTypeWIP}},
+	reflect.TypeFor[TierFilter]():            {Type: "string", Enum: []any{TierFilterWIP, TierFilterDemand, TierFilterUpstream, TierFilterDownstream, TierFilterFinished, TierFilterAll}},
+	reflect.TypeFor[DiagnosticGoal]():        {Type: "string", Enum: []any{GoalForecasting, GoalBottlenecks, GoalCapacityPlanning, GoalSystemHealth}},
+	reflect.TypeFor[Granularity]():           {Type: "string", Enum: []any{GranularityDaily, GranularityWeekly}},
+	reflect.TypeFor[WorkflowTier]():          {Type: "string", Enum: []any{TierDemand, TierUpstream, TierDownstream, TierFinished}},
+	reflect.TypeFor[WorkflowRole]():          {Type: "string", Enum: []any{RoleActive, RoleQueue, RoleIgnore}},
+	reflect.TypeFor[WorkflowOutcome]():       {Type: "string", Enum: []any{OutcomeDelivered, OutcomeAbandoned}},
 }
 
 // schemaFor infers a JSON Schema for type T with custom enum type mappings.
@@ -264,13 +675,71 @@ func schemaFor[T any]() (*jsonschema.Schema, error) {
 	return s, nil
 }
 
-// formatToolResult wraps handler output into an MCP CallToolResult with text content.
-func formatToolResult(s *Server, data any) *mcp.CallToolResult {
+// envelopeSchema is the ResponseEnvelope every tool returns, whose data
+// field is tool-specific; see outputSchemaFor.
+var envelopeSchema = sync.OnceValues(schemaFor[ResponseEnvelope])
+
+// toolResultTypes lists the types a tool returns as the data of its
+// envelope, one per variant of the result. Tools not listed declare data as
+// an open object.
+var toolResultTypes = map[string][]reflect.Type{
+	"forecast_monte_carlo":  {reflect.TypeFor[simulation.Result](), reflect.TypeFor[ForecastPreview]()},
+	"forecast_item":         {reflect.TypeFor[simulation.Result]()},
+	"forecast_burnup":       {reflect.TypeFor[simulation.Result]()},
+	"forecast_timebox":      {reflect.TypeFor[simulation.Result]()},
+	"analyze_cycle_time":    {reflect.TypeFor[simulation.Result]()},
+	"analyze_throughput":    {reflect.TypeFor[ThroughputResult]()},
+	"analyze_work_item_age": {reflect.TypeFor[AgingResult]()},
+}
+
+// outputSchemaFor returns the output schema of the named tool: the
+// ResponseEnvelope, with data described by the tool's result types.
+func outputSchemaFor(name string) (*jsonschema.Schema, error) {
+	envelope, err := envelopeSchema()
+	if err != nil {
+		return nil, err
+	}
+	types := toolResultTypes[name]
+	if len(types) == 0 {
+		return envelope, nil
+	}
+	variants := make([]*jsonschema.Schema, 0, len(types))
+	for _, rt := range types {
+		variant, err := jsonschema.ForType(rt, &jsonschema.ForOptions{TypeSchemas: customSchemas})
+		if err != nil {
+			return nil, fmt.Errorf("output schema of %s: %w", rt, err)
+		}
+		variants = append(variants, variant)
+	}
+	data := variants[0]
+	if len(variants) > 1 {
+		data = &jsonschema.Schema{AnyOf: variants}
+	}
+	schema := envelope.CloneSchemas()
+	data.Description = schema.Properties["data"].Description
+	schema.Properties["data"] = data
+	return schema, nil
+}
+
+// formatToolResult wraps handler output into an MCP CallToolResult with text
+// content in the session's output format, and the result as structuredContent.
+func formatToolResult(ctx context.Context, s *Server, data any) *mcp.CallToolResult {
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: s.formatResult(data)},
+			&mcp.TextContent{Text: s.formatResult(ctx, data)},
 		},
+		StructuredContent: structuredContent(data),
+	}
+}
+
+// structuredContent returns the structuredContent of a tool result: the
+// envelope itself, or other data wrapped as the data of an envelope, so every
+// result matches its output schema.
+func structuredContent(data any) ResponseEnvelope {
+	if envelope, ok := data.(ResponseEnvelope); ok {
+		return envelope
 	}
+	return ResponseEnvelope{Data: data}
 }
 
 // formatToolError wraps an error into an MCP CallToolResult with IsError set.
@@ -296,147 +765,333 @@ func registerTools(mcpSrv *mcp.Server, s *Server) error {
 
 	// GROUP: Import & Setup
 	//   import_projects, import_boards, import_board_context, import_project_context,
-	//   import_history_update,
-	//   workflow_discover_mapping, workflow_set_mapping, workflow_set_order,
-	//   workflow_set_evaluation_date, guide_diagnostic_roadmap, open_in_browser
+	//   import_portfolio, import_history_update, import_history_status, export_workspace, export_dataset, import_workspace, import_throughput_csv,
+	//   workflow_discover_mapping, workflow_set_mapping, workflow_set_order, workflow_export_mapping,
+	//   workflow_import_mapping, workflow_set_type_aliases, workflow_set_evaluation_date, workflow_get_audit,
+	//   get_source_settings, set_source_settings, guide_diagnostic_roadmap, open_in_browser
 
 	must(addTool(mcpSrv, s, "import_projects",
-		func(_ context.Context, _ *mcp.CallToolRequest, args ImportProjectsInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleImportProjects(args.Query)
-			return handleResult(s, "import_projects", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportProjectsInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleImportProjects(ctx, args.Query)
+			return handleResult(ctx, s, "import_projects", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "import_boards",
-		func(_ context.Context, _ *mcp.CallToolRequest, args ImportBoardsInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleImportBoards(args.ProjectKey, args.NameFilter)
-			return handleResult(s, "import_boards", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportBoardsInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleImportBoards(ctx, args.ProjectKey, args.NameFilter)
+			return handleResult(ctx, s, "import_boards", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "import_project_context",
-		func(_ context.Context, _ *mcp.CallToolRequest, args ImportProjectContextInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetProjectDetails(args.ProjectKey)
-			return handleResult(s, "import_project_context", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportProjectContextInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetProjectDetails(ctx, args.ProjectKey)
+			return handleResult(ctx, s, "import_project_context", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "import_board_context",
-		func(_ context.Context, _ *mcp.CallToolRequest, args ImportBoardContextInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetBoardDetails(args.ProjectKey, args.BoardID)
-			return handleResult(s, "import_board_context", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportBoardContextInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetBoardDetails(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "import_board_context", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "import_portfolio",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportPortfolioInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleImportPortfolio(ctx, args.Sources, args.Duplicates)
+			return handleResult(ctx, s, "import_portfolio", data, err)
 		}))
 
 	// GROUP: Forecast & Simulation
-	//   forecast_monte_carlo, forecast_backtest
+	//   forecast_monte_carlo, forecast_scenarios, forecast_epic, forecast_item, forecast_burnup, forecast_timebox,
+	//   analyze_throughput_histogram, compare_forecasts, record_commitment, analyze_commitment_health, forecast_backtest
 
 	must(addTool(mcpSrv, s, "forecast_monte_carlo",
-		func(_ context.Context, _ *mcp.CallToolRequest, args ForecastMonteCarloInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleRunSimulation(
-				args.ProjectKey, args.BoardID, string(args.Mode),
-				args.IncludeExistingBacklog, args.AdditionalItems,
-				args.TargetDays, args.TargetDate,
-				args.StartStatus,
-				args.IssueTypes, args.IncludeWIP,
-				args.HistoryWindowDays, args.HistoryStartDate, args.HistoryEndDate,
-				args.Targets, args.MixOverrides,
-			)
-			return handleResult(s, "forecast_monte_carlo", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ForecastMonteCarloInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleForecastMonteCarlo(ctx, args)
+			return handleResult(ctx, s, "forecast_monte_carlo", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "forecast_scenarios",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ForecastScenariosInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleForecastScenarios(ctx, args)
+			return handleResult(ctx, s, "forecast_scenarios", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "forecast_epic",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ForecastEpicInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleForecastEpic(ctx, args.ProjectKey, args.BoardID, args.EpicKey, args.HistoryWindowDays)
+			return handleResult(ctx, s, "forecast_epic", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "forecast_item",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ForecastItemInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleForecastItem(ctx, args.ProjectKey, args.BoardID, s.resolveIssueKey(args.IssueKey), args.HistoryWindowDays)
+			return handleResult(ctx, s, "forecast_item", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "forecast_burnup",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ForecastBurnUpInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleForecastBurnUp(ctx, args.ProjectKey, args.BoardID, args.HorizonWeeks, args.IssueTypes, args.HistoryWindowDays, args.Holidays, args.FreezePeriods)
+			return handleResult(ctx, s, "forecast_burnup", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "forecast_timebox",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ForecastTimeboxInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleForecastTimebox(ctx, args.ProjectKey, args.BoardID, args.SprintID, args.IssueKeys, args.StartDate, args.EndDate, args.HistoryWindowDays, args.Holidays, args.FreezePeriods)
+			return handleResult(ctx, s, "forecast_timebox", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_throughput_histogram",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeThroughputHistogramInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeThroughputHistogram(ctx, args.ProjectKey, args.BoardID, args.HistoryWindowDays, args.HistoryStartDate, args.HistoryEndDate, args.Holidays, args.FreezePeriods)
+			return handleResult(ctx, s, "analyze_throughput_histogram", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "compare_forecasts",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args CompareForecastsInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleCompareForecasts(args.ProjectKey, args.BoardID, args.BaselineID, args.CurrentID, args.BaselineDate)
+			return handleResult(ctx, s, "compare_forecasts", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "record_commitment",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args RecordCommitmentInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleRecordCommitment(ctx, args.ProjectKey, args.BoardID, args.Name, args.ForecastID, args.Percentile, args.TargetDate, args.Items, args.IssueKeys, args.IssueTypes, args.Withdraw, args.HistoryWindowDays)
+			return handleResult(ctx, s, "record_commitment", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_commitment_health",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeCommitmentHealthInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeCommitmentHealth(ctx, args.ProjectKey, args.BoardID, args.HistoryWindowDays)
+			return handleResult(ctx, s, "analyze_commitment_health", data, err)
 		}))
 
 	// GROUP: Diagnostics — Process, Cycle Time, WIP & Flow
-	//   analyze_cycle_time, analyze_process_stability, analyze_process_evolution,
-	//   analyze_status_persistence, analyze_throughput, analyze_wip_stability,
-	//   analyze_wip_age_stability, analyze_work_item_age, analyze_flow_debt,
-	//   analyze_residence_time, analyze_yield, generate_cfd_data, analyze_item_journey
+	//   analyze_cycle_time, analyze_cycle_time_scatter, find_outliers, set_sle, analyze_sle_compliance, analyze_process_stability, analyze_process_evolution,
+	//   analyze_status_persistence, analyze_flow_efficiency, analyze_rework, analyze_throughput, analyze_throughput_streams,
+	//   analyze_interference, analyze_release_lag, analyze_release_burnup, analyze_sprint_history, analyze_wip_stability,
+	//   analyze_wip_age_stability, set_wip_limits, analyze_wip_limits, set_alert_rules, evaluate_alerts,
+	//   analyze_work_item_age, analyze_flow_debt,
+	//   analyze_residence_time, analyze_yield, analyze_abandonment, generate_cfd_data, analyze_item_journey,
+	//   analyze_definition_of_workflow
 
 	must(addTool(mcpSrv, s, "analyze_cycle_time",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeCycleTimeInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetCycleTimeAssessment(args.ProjectKey, args.BoardID, args.StartStatus, args.EndStatus, args.IssueTypes, args.SLEPercentile, args.SLEDurationDays)
-			return handleResult(s, "analyze_cycle_time", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeCycleTimeInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetCycleTimeAssessment(ctx, args.ProjectKey, args.BoardID, args.StartStatus, args.EndStatus, args.IssueTypes, args.SLEPercentile, args.SLEDurationDays, args.ByType, args.GroupBy)
+			return handleResult(ctx, s, "analyze_cycle_time", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_cycle_time_scatter",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeCycleTimeScatterInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeCycleTimeScatter(ctx, args.ProjectKey, args.BoardID, args.StartStatus, args.EndStatus, args.IssueTypes)
+			return handleResult(ctx, s, "analyze_cycle_time_scatter", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "find_outliers",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args FindOutliersInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleFindOutliers(ctx, args.ProjectKey, args.BoardID, args.By, args.Status, args.IssueTypes, args.Limit)
+			return handleResult(ctx, s, "find_outliers", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "set_sle",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args SetSLEInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetSLE(ctx, args.ProjectKey, args.BoardID, args.IssueType, args.Percentile, args.DurationDays, args.Remove)
+			return handleResult(ctx, s, "set_sle", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_sle_compliance",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeSLEComplianceInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeSLECompliance(ctx, args.ProjectKey, args.BoardID, args.IssueTypes)
+			return handleResult(ctx, s, "analyze_sle_compliance", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_status_persistence",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeStatusPersistenceInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetStatusPersistence(args.ProjectKey, args.BoardID)
-			return handleResult(s, "analyze_status_persistence", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeStatusPersistenceInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetStatusPersistence(ctx, args.ProjectKey, args.BoardID, args.TrendBucket, args.Reconcile, args.ByColumn)
+			return handleResult(ctx, s, "analyze_status_persistence", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_flow_efficiency",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeFlowEfficiencyInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeFlowEfficiency(ctx, args.ProjectKey, args.BoardID, args.IssueTypes)
+			return handleResult(ctx, s, "analyze_flow_efficiency", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_rework",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeReworkInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeRework(ctx, args.ProjectKey, args.BoardID, args.IssueTypes)
+			return handleResult(ctx, s, "analyze_rework", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_work_item_age",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeWorkItemAgeInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetAgingAnalysis(args.ProjectKey, args.BoardID, string(args.AgeType), string(args.TierFilter))
-			return handleResult(s, "analyze_work_item_age", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeWorkItemAgeInput) (*mcp.CallToolResult, any, error) {
+			if len(args.Sources) > 0 {
+				if args.ByColumn {
+					return handleResult(ctx, s, "analyze_work_item_age", nil, fmt.Errorf("by_column reads the columns of one board and cannot be combined with sources"))
+				}
+				if len(args.Labels) > 0 || len(args.Components) > 0 {
+					return handleResult(ctx, s, "analyze_work_item_age", nil, errSubTeamSources)
+				}
+				data, err := s.handlePortfolioAging(ctx, args.ProjectKey, args.BoardID, args.Sources, args.Duplicates, string(args.AgeType), string(args.TierFilter))
+				return handleResult(ctx, s, "analyze_work_item_age", data, err)
+			}
+			data, err := s.handleGetAgingAnalysis(ctx, args.ProjectKey, args.BoardID, string(args.AgeType), string(args.TierFilter), args.ByColumn)
+			return handleResult(ctx, s, "analyze_work_item_age", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_throughput",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeThroughputInput) (*mcp.CallToolResult, any, error) {
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeThroughputInput) (*mcp.CallToolResult, any, error) {
 			bucket := args.Bucket
 			if bucket == "" {
 				bucket = "week"
 			}
-			data, err := s.handleGetDeliveryCadence(args.ProjectKey, args.BoardID, bucket, args.IncludeAbandoned)
-			return handleResult(s, "analyze_throughput", data, err)
+			if len(args.Sources) > 0 {
+				if len(args.Labels) > 0 || len(args.Components) > 0 {
+					return handleResult(ctx, s, "analyze_throughput", nil, errSubTeamSources)
+				}
+				if args.GroupBy != "" {
+					return handleResult(ctx, s, "analyze_throughput", nil, fmt.Errorf("group_by cannot be combined with sources"))
+				}
+				if args.BucketAlignment == AlignRolling {
+					return handleResult(ctx, s, "analyze_throughput", nil, fmt.Errorf("bucket_alignment 'rolling' cannot be combined with sources"))
+				}
+				data, err := s.handlePortfolioThroughput(ctx, args.ProjectKey, args.BoardID, args.Sources, args.Duplicates, bucket)
+				return handleResult(ctx, s, "analyze_throughput", data, err)
+			}
+			data, err := s.handleGetDeliveryCadence(ctx, args.ProjectKey, args.BoardID, bucket, args.BucketAlignment, args.IncludeAbandoned, args.GroupBy)
+			return handleResult(ctx, s, "analyze_throughput", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_throughput_streams",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeThroughputStreamsInput) (*mcp.CallToolResult, any, error) {
+			bucket := args.Bucket
+			if bucket == "" {
+				bucket = "week"
+			}
+			data, err := s.handleAnalyzeThroughputStreams(ctx, args.ProjectKey, args.BoardID, string(args.StreamBy), bucket)
+			return handleResult(ctx, s, "analyze_throughput_streams", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_interference",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeInterferenceInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeInterference(ctx, args.ProjectKey, args.BoardID, args.By, args.TaxerFactor, args.HorizonDays)
+			return handleResult(ctx, s, "analyze_interference", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_release_lag",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeReleaseLagInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeReleaseLag(ctx, args.ProjectKey, args.BoardID, args.ReleaseStatus)
+			return handleResult(ctx, s, "analyze_release_lag", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_release_burnup",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeReleaseBurnupInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeReleaseBurnup(ctx, args.ProjectKey, args.BoardID, args.FixVersion, args.Bucket)
+			return handleResult(ctx, s, "analyze_release_burnup", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_sprint_history",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeSprintHistoryInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeSprintHistory(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "analyze_sprint_history", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_process_stability",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeProcessStabilityInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetProcessStability(args.ProjectKey, args.BoardID, args.IncludeRawSeries)
-			return handleResult(s, "analyze_process_stability", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeProcessStabilityInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetProcessStability(ctx, args.ProjectKey, args.BoardID, args.IncludeRawSeries)
+			return handleResult(ctx, s, "analyze_process_stability", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_flow_debt",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeFlowDebtInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetFlowDebt(args.ProjectKey, args.BoardID, args.BucketSize)
-			return handleResult(s, "analyze_flow_debt", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeFlowDebtInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetFlowDebt(ctx, args.ProjectKey, args.BoardID, args.BucketSize, args.BucketAlignment)
+			return handleResult(ctx, s, "analyze_flow_debt", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "generate_cfd_data",
-		func(_ context.Context, _ *mcp.CallToolRequest, args GenerateCFDDataInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetCFDData(args.ProjectKey, args.BoardID, string(args.Granularity))
-			return handleResult(s, "generate_cfd_data", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args GenerateCFDDataInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetCFDData(ctx, args.ProjectKey, args.BoardID, string(args.Granularity))
+			return handleResult(ctx, s, "generate_cfd_data", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_wip_stability",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeWIPStabilityInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleAnalyzeWIPStability(args.ProjectKey, args.BoardID)
-			return handleResult(s, "analyze_wip_stability", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeWIPStabilityInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeWIPStability(ctx, args.ProjectKey, args.BoardID, args.ByColumn)
+			return handleResult(ctx, s, "analyze_wip_stability", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_wip_age_stability",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeWIPAgeStabilityInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleAnalyzeWIPAgeStability(args.ProjectKey, args.BoardID)
-			return handleResult(s, "analyze_wip_age_stability", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeWIPAgeStabilityInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeWIPAgeStability(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "analyze_wip_age_stability", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "set_wip_limits",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args SetWIPLimitsInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetWIPLimits(ctx, args.ProjectKey, args.BoardID, args.Limits, args.Clear)
+			return handleResult(ctx, s, "set_wip_limits", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "set_alert_rules",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args SetAlertRulesInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetAlertRules(ctx, args.ProjectKey, args.BoardID, args.Rules, args.Remove, args.Clear)
+			return handleResult(ctx, s, "set_alert_rules", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "evaluate_alerts",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args EvaluateAlertsInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleEvaluateAlerts(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "evaluate_alerts", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_wip_limits",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeWIPLimitsInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeWIPLimits(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "analyze_wip_limits", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_residence_time",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeResidenceTimeInput) (*mcp.CallToolResult, any, error) {
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeResidenceTimeInput) (*mcp.CallToolResult, any, error) {
 			granularity := string(args.Granularity)
 			if granularity == "weekly" {
 				granularity = "week"
 			} else if granularity == "" {
 				granularity = "day"
 			}
-			data, err := s.handleAnalyzeResidenceTime(args.ProjectKey, args.BoardID, args.IssueTypes, granularity)
-			return handleResult(s, "analyze_residence_time", data, err)
+			data, err := s.handleAnalyzeResidenceTime(ctx, args.ProjectKey, args.BoardID, args.IssueTypes, granularity)
+			return handleResult(ctx, s, "analyze_residence_time", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_process_evolution",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeProcessEvolutionInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetProcessEvolution(args.ProjectKey, args.BoardID, args.Bucket)
-			return handleResult(s, "analyze_process_evolution", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeProcessEvolutionInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetProcessEvolution(ctx, args.ProjectKey, args.BoardID, args.Bucket)
+			return handleResult(ctx, s, "analyze_process_evolution", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_yield",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetProcessYield(args.ProjectKey, args.BoardID)
-			return handleResult(s, "analyze_yield", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeYieldInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetProcessYield(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "analyze_yield", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_abandonment",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeAbandonmentInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeAbandonment(ctx, args.ProjectKey, args.BoardID, args.IssueTypes)
+			return handleResult(ctx, s, "analyze_abandonment", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "analyze_definition_of_workflow",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeDefinitionOfWorkflowInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleAnalyzeDefinitionOfWorkflow(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "analyze_definition_of_workflow", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "workflow_discover_mapping",
-		func(_ context.Context, _ *mcp.CallToolRequest, args WorkflowDiscoverMappingInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetWorkflowDiscovery(args.ProjectKey, args.BoardID, args.ForceRefresh)
-			return handleResult(s, "workflow_discover_mapping", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowDiscoverMappingInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetWorkflowDiscovery(ctx, args.ProjectKey, args.BoardID, args.ForceRefresh)
+			return handleResult(ctx, s, "workflow_discover_mapping", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "workflow_set_mapping",
-		func(_ context.Context, _ *mcp.CallToolRequest, args WorkflowSetMappingInput) (*mcp.CallToolResult, any, error) {
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowSetMappingInput) (*mcp.CallToolResult, any, error) {
 			// Convert typed structs to map[string]any for the handler interface
 			mappingAny := make(map[string]any, len(args.Mapping))
 			for k, v := range args.Mapping {
@@ -456,90 +1111,234 @@ func registerTools(mcpSrv *mcp.Server, s *Server) error {
 					resolutionsAny[k] = string(v)
 				}
 			}
-			data, err := s.handleSetWorkflowMapping(args.ProjectKey, args.BoardID, mappingAny, resolutionsAny, args.CommitmentPoint)
-			return handleResult(s, "workflow_set_mapping", data, err)
+			data, err := s.handleSetWorkflowMapping(ctx, args.ProjectKey, args.BoardID, mappingAny, resolutionsAny, args.CommitmentPoint, args.CommitmentPointsByType)
+			return handleResult(ctx, s, "workflow_set_mapping", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "workflow_set_order",
-		func(_ context.Context, _ *mcp.CallToolRequest, args WorkflowSetOrderInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleSetWorkflowOrder(args.ProjectKey, args.BoardID, args.Order)
-			return handleResult(s, "workflow_set_order", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowSetOrderInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetWorkflowOrder(ctx, args.ProjectKey, args.BoardID, args.Order)
+			return handleResult(ctx, s, "workflow_set_order", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "workflow_export_mapping",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowExportMappingInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleExportWorkflowMapping(args.ProjectKey, args.BoardID, args.Path)
+			return handleResult(ctx, s, "workflow_export_mapping", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "workflow_import_mapping",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowImportMappingInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleImportWorkflowMapping(ctx, args.ProjectKey, args.BoardID, args.Path, args.Document, args.Overwrite)
+			return handleResult(ctx, s, "workflow_import_mapping", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "workflow_set_type_aliases",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowSetTypeAliasesInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetTypeAliases(ctx, args.ProjectKey, args.BoardID, args.Aliases)
+			return handleResult(ctx, s, "workflow_set_type_aliases", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "workflow_set_evaluation_date",
-		func(_ context.Context, _ *mcp.CallToolRequest, args WorkflowSetEvaluationDateInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleSetEvaluationDate(args.ProjectKey, args.BoardID, args.Date)
-			return handleResult(s, "workflow_set_evaluation_date", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowSetEvaluationDateInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetEvaluationDate(ctx, args.ProjectKey, args.BoardID, args.Date)
+			return handleResult(ctx, s, "workflow_set_evaluation_date", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "workflow_get_audit",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args WorkflowGetAuditInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetWorkflowAudit(args.ProjectKey, args.BoardID, args.Field, args.Limit)
+			return handleResult(ctx, s, "workflow_get_audit", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "get_source_settings",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args GetSourceSettingsInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetSourceSettings(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "get_source_settings", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "set_source_settings",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args SetSourceSettingsInput) (*mcp.CallToolResult, any, error) {
+			settings := SourceSettings{
+				WindowWeeks:        args.WindowWeeks,
+				ForecastSampleDays: args.ForecastSampleDays,
+				IssueTypes:         args.IssueTypes,
+				SubtaskPolicy:      args.SubtaskPolicy,
+				Outliers:           args.Outliers,
+				Format:             args.Format,
+			}
+			data, err := s.handleSetSourceSettings(ctx, args.ProjectKey, args.BoardID, settings, args.Reset)
+			return handleResult(ctx, s, "set_source_settings", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "save_forecast_template",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args SaveForecastTemplateInput) (*mcp.CallToolResult, any, error) {
+			template := ForecastTemplate{
+				Name:                   args.Name,
+				IssueTypes:             args.IssueTypes,
+				StartStatus:            args.StartStatus,
+				Targets:                args.Targets,
+				MixOverrides:           args.MixOverrides,
+				IncludeExistingBacklog: args.IncludeExistingBacklog,
+				IncludeWIP:             args.IncludeWIP,
+				AdditionalItems:        args.AdditionalItems,
+				HistoryWindowDays:      args.HistoryWindowDays,
+			}
+			data, err := s.handleSaveForecastTemplate(ctx, args.ProjectKey, args.BoardID, template, args.Remove)
+			return handleResult(ctx, s, "save_forecast_template", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "list_forecast_templates",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ListForecastTemplatesInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleListForecastTemplates(args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "list_forecast_templates", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "set_analysis_window",
-		func(_ context.Context, _ *mcp.CallToolRequest, args SetAnalysisWindowInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleSetAnalysisWindow(args.StartDate, args.EndDate, args.DurationDays, args.Reset)
-			return handleResult(s, "set_analysis_window", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args SetAnalysisWindowInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetAnalysisWindow(ctx, args.StartDate, args.EndDate, args.DurationDays, args.Reset)
+			return handleResult(ctx, s, "set_analysis_window", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "get_analysis_window",
-		func(_ context.Context, _ *mcp.CallToolRequest, _ GetAnalysisWindowInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetAnalysisWindow()
-			return handleResult(s, "get_analysis_window", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, _ GetAnalysisWindowInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetAnalysisWindow(ctx)
+			return handleResult(ctx, s, "get_analysis_window", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "set_visual_preferences",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args SetVisualPreferencesInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleSetVisualPreferences(args.MermaidCharts)
+			return handleResult(ctx, s, "set_visual_preferences", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "analyze_item_journey",
-		func(_ context.Context, _ *mcp.CallToolRequest, args AnalyzeItemJourneyInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleGetItemJourney(args.ProjectKey, args.BoardID, args.IssueKey)
-			return handleResult(s, "analyze_item_journey", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args AnalyzeItemJourneyInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleGetItemJourney(ctx, args.ProjectKey, args.BoardID, s.resolveIssueKey(args.IssueKey))
+			return handleResult(ctx, s, "analyze_item_journey", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "guide_diagnostic_roadmap",
-		func(_ context.Context, _ *mcp.CallToolRequest, args GuideDiagnosticRoadmapInput) (*mcp.CallToolResult, any, error) {
+		func(ctx context.Context, _ *mcp.CallToolRequest, args GuideDiagnosticRoadmapInput) (*mcp.CallToolResult, any, error) {
 			data, err := s.handleGetDiagnosticRoadmap(string(args.Goal))
-			return handleResult(s, "guide_diagnostic_roadmap", data, err)
+			return handleResult(ctx, s, "guide_diagnostic_roadmap", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "forecast_backtest",
-		func(_ context.Context, _ *mcp.CallToolRequest, args ForecastBacktestInput) (*mcp.CallToolResult, any, error) {
+		func(ctx context.Context, req *mcp.CallToolRequest, args ForecastBacktestInput) (*mcp.CallToolResult, any, error) {
 			data, err := s.handleGetForecastAccuracy(
-				args.ProjectKey, args.BoardID, string(args.SimulationMode),
+				ctx, args.ProjectKey, args.BoardID, string(args.SimulationMode),
 				args.ItemsToForecast, args.ForecastHorizon,
 				args.IssueTypes, args.HistoryWindowDays,
 				args.HistoryStartDate, args.HistoryEndDate,
+				string(args.Precision), args.SweepSampleDays, progressNotifier(ctx, req),
 			)
-			return handleResult(s, "forecast_backtest", data, err)
+			return handleResult(ctx, s, "forecast_backtest", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "import_history_update",
-		func(_ context.Context, _ *mcp.CallToolRequest, args ImportHistoryUpdateInput) (*mcp.CallToolResult, any, error) {
-			data, err := s.handleCacheCatchUp(args.ProjectKey, args.BoardID)
-			return handleResult(s, "import_history_update", data, err)
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportHistoryUpdateInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleCacheCatchUp(ctx, args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "import_history_update", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "import_history_status",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportHistoryStatusInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleImportHistoryStatus(args.ProjectKey, args.BoardID)
+			return handleResult(ctx, s, "import_history_status", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "export_workspace",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ExportWorkspaceInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleExportWorkspace(args.Path)
+			return handleResult(ctx, s, "export_workspace", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "export_dataset",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ExportDatasetInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleExportDataset(ctx, args.ProjectKey, args.BoardID, args.Dir)
+			return handleResult(ctx, s, "export_dataset", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "import_workspace",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportWorkspaceInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleImportWorkspace(ctx, args.Path, args.Overwrite)
+			return handleResult(ctx, s, "import_workspace", data, err)
+		}))
+
+	must(addTool(mcpSrv, s, "import_throughput_csv",
+		func(ctx context.Context, _ *mcp.CallToolRequest, args ImportThroughputCSVInput) (*mcp.CallToolResult, any, error) {
+			data, err := s.handleImportThroughputCSV(args.ProjectKey, args.BoardID, args.Path, args.CSV, args.Granularity, args.Remove)
+			return handleResult(ctx, s, "import_throughput_csv", data, err)
 		}))
 
 	must(addTool(mcpSrv, s, "open_in_browser",
-		func(_ context.Context, _ *mcp.CallToolRequest, args OpenInBrowserInput) (*mcp.CallToolResult, any, error) {
+		func(ctx context.Context, _ *mcp.CallToolRequest, args OpenInBrowserInput) (*mcp.CallToolResult, any, error) {
 			data, err := s.handleOpenInBrowser(args.URL)
-			return handleResult(s, "open_in_browser", data, err)
+			return handleResult(ctx, s, "open_in_browser", data, err)
 		}))
 
 	return errors.Join(errs...)
 }
 
+// progressFunc reports progress of a long-running tool call. total is 0 when unknown.
+type progressFunc func(progress, total int, message string)
+
+// progressNotifier returns a progressFunc that forwards updates to the client as
+// MCP progress notifications. It is a no-op when the client sent no progress token.
+func progressNotifier(ctx context.Context, req *mcp.CallToolRequest) progressFunc {
+	if req == nil || req.Session == nil || req.Params == nil || req.Params.GetProgressToken() == nil {
+		return func(int, int, string) {}
+	}
+	token := req.Params.GetProgressToken()
+	return func(progress, total int, message string) {
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      float64(progress),
+			Total:         float64(total),
+			Message:       message,
+		})
+		if err != nil {
+			log.Debug().Err(err).Msg("Failed to send progress notification")
+		}
+	}
+}
+
 // addTool registers a tool with the SDK using the generic mcp.AddTool API.
 // If the input type contains custom enum types, it pre-builds the schema
-// with customSchemas to include enum constraints.
+// with customSchemas to include enum constraints. Tools disabled by
+// MCS_READ_ONLY or MCS_ENABLED_TOOLS are skipped.
 func addTool[In any](mcpSrv *mcp.Server, s *Server, name string, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) error {
+	if !s.toolEnabled(name) {
+		return nil // Disabled tools are neither listed nor callable
+	}
 	schema, err := schemaFor[In]()
 	if err != nil {
 		return fmt.Errorf("tool %q: %w", name, err)
 	}
+	outputSchema, err := outputSchemaFor(name)
+	if err != nil {
+		return fmt.Errorf("tool %q: %w", name, err)
+	}
 	desc := toolDescriptions[name]
 	tool := &mcp.Tool{
-		Name:        name,
-		Description: desc,
-		InputSchema: schema,
+		Name:         name,
+		Description:  desc,
+		InputSchema:  schema,
+		OutputSchema: outputSchema,
 	}
-	mcp.AddTool(mcpSrv, tool, withPanicRecovery(name, handler))
+	mcp.AddTool(mcpSrv, tool, withPanicRecovery(name, withCallContext(name, withToolOptions(s, handler))))
 	return nil
 }
 
+// withToolOptions applies the parameters tool inputs share (Jira instance,
+// query source, as_of_date, history window, policies, pagination, response
+// budget, format) to the call. The Go API runs its handlers through it too,
+// so its calls honor the same parameters as the tools.
+func withToolOptions[In any](s *Server, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
+	return withJiraInstance(s, withQuerySource(s, withAsOfDate(s, withHistoryWindow(s, withSubtaskPolicy(s, withContainerPolicy(s, withOutcomeScope(s, withOutlierPolicy(s, withPagination(s, withResponseBudget(s, withResultFormat(s, handler)))))))))))
+}
+
 // withPanicRecovery wraps a tool handler with panic recovery and logging.
 func withPanicRecovery[In any](name string, handler func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error)) func(context.Context, *mcp.CallToolRequest, In) (*mcp.CallToolResult, any, error) {
 	return func(ctx context.Context, req *mcp.CallToolRequest, args In) (result *mcp.CallToolResult, out any, err error) {
@@ -574,12 +1373,20 @@ func withPanicRecovery[In any](name string, handler func(context.Context, *mcp.C
 // handleResult converts a (data, error) pair to the SDK's 3-return convention.
 // For chart-eligible tools, it also pushes the result into the MRU buffer and
 // injects a chart_url into the response context. It also injects session_context
-// so the agent always sees which analysis window shaped the output.
-func handleResult(s *Server, toolName string, data any, err error) (*mcp.CallToolResult, any, error) {
+// so the agent always sees which analysis window shaped the output, and the
+// Mermaid diagrams of the result when the session enabled them. With
+// MCS_ANONYMIZE, every output derived from the result carries pseudonyms, and
+// with a locale other than English, translated guidance.
+func handleResult(ctx context.Context, s *Server, toolName string, data any, err error) (*mcp.CallToolResult, any, error) {
 	if err != nil {
-		return formatToolError(err), nil, nil
+		return formatToolError(s.anonymizeError(err)), nil, nil
 	}
-	data = s.injectSessionContext(data)
+	data = s.injectSessionContext(ctx, data)
+	data = s.anonymizeResult(data)
+	data = s.localizeResult(data)
 	data = s.injectChartURL(toolName, data)
-	return formatToolResult(s, data), nil, nil
+	data = s.injectVisuals(toolName, data)
+	s.publishResources(toolName, data)
+	data = s.budgetResult(ctx, data)
+	return formatToolResult(ctx, s, data), nil, nil
 }
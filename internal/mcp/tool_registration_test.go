@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/google/jsonschema-go/jsonschema"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestToolResults_StructuredContent(t *testing.T) {
+	srv := newGoldenServer(t)
+	mcpSrv, err := NewMCPServer(srv, "test")
+	if err != nil {
+		t.Fatalf("NewMCPServer: %v", err)
+	}
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := mcpSrv.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("server connect: %v", err)
+	}
+	defer ss.Close()
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "test"}, &mcp.ClientOptions{})
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("client connect: %v", err)
+	}
+	defer cs.Close()
+
+	tools, err := cs.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatalf("ListTools: %v", err)
+	}
+	schemas := make(map[string]*jsonschema.Resolved)
+	for _, tool := range tools.Tools {
+		if tool.OutputSchema == nil {
+			t.Errorf("Tool %s declares no output schema", tool.Name)
+			continue
+		}
+		var schema *jsonschema.Schema
+		raw, _ := json.Marshal(tool.OutputSchema)
+		if err := json.Unmarshal(raw, &schema); err != nil {
+			t.Fatalf("decode output schema of %s: %v", tool.Name, err)
+		}
+		if _, typed := toolResultTypes[tool.Name]; typed && schema.Properties["data"].Type == "" && len(schema.Properties["data"].AnyOf) == 0 {
+			t.Errorf("Expected %s to describe its data, got %s", tool.Name, raw)
+		}
+		if schemas[tool.Name], err = schema.Resolve(nil); err != nil {
+			t.Fatalf("resolve output schema of %s: %v", tool.Name, err)
+		}
+	}
+
+	call := func(name string, args map[string]any) (*mcp.CallToolResult, map[string]any) {
+		t.Helper()
+		args["project_key"], args["board_id"] = testProject, testBoard
+		res, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: name, Arguments: args})
+		if err != nil || res.IsError {
+			t.Fatalf("%s %v failed: %v %+v", name, args, err, res)
+		}
+		raw, _ := json.Marshal(res.StructuredContent)
+		var structured map[string]any
+		if err := json.Unmarshal(raw, &structured); err != nil || structured["data"] == nil {
+			t.Fatalf("Expected the envelope as structuredContent (%s %v), got %s", name, args, raw)
+		}
+		if err := schemas[name].Validate(structured); err != nil {
+			t.Errorf("structuredContent of %s %v does not match its output schema: %v", name, args, err)
+		}
+		return res, structured
+	}
+
+	for _, format := range []string{"json", "csv"} {
+		res, _ := call("analyze_throughput", map[string]any{"format": format})
+		if text := res.Content[0].(*mcp.TextContent).Text; format == "csv" && strings.HasPrefix(text, "{") {
+			t.Errorf("Expected CSV text next to the structured content, got %q", text[:40])
+		}
+	}
+	call("analyze_throughput", map[string]any{"bucket_alignment": "rolling"})
+	call("analyze_cycle_time", map[string]any{"by_type": true})
+	_, aging := call("analyze_work_item_age", map[string]any{"age_type": "wip"})
+	call("analyze_work_item_age", map[string]any{"age_type": "total", "tier_filter": "All", "limit": 1})
+	call("forecast_monte_carlo", map[string]any{"mode": "duration", "include_existing_backlog": true, "include_wip": true})
+	call("forecast_monte_carlo", map[string]any{"mode": "scope", "target_days": 30})
+	call("forecast_monte_carlo", map[string]any{"mode": "duration", "targets": map[string]int{"Story": 5}, "dry_run": true})
+	call("forecast_burnup", map[string]any{"horizon_weeks": 4})
+	call("forecast_timebox", map[string]any{"issue_keys": []string{"NOPE-1"}, "end_date": srv.Clock(ctx).AddDate(0, 0, 14).Format("2006-01-02")})
+	items, _ := aging["data"].(map[string]any)["aging"].([]any)
+	if len(items) == 0 {
+		t.Fatal("Expected in-flight items to forecast")
+	}
+	call("forecast_item", map[string]any{"issue_key": items[0].(map[string]any)["key"]})
+}
+
+func TestStructuredContent_WrapsNonEnvelopeData(t *testing.T) {
+	if got := structuredContent([]string{"a"}); got.Data == nil || got.Guardrails != nil {
+		t.Errorf("Expected the data wrapped in an envelope, got %+v", got)
+	}
+	envelope := WrapResponse("x", "PROJ", 1, nil, nil, nil)
+	if got := structuredContent(envelope); got.Data != "x" || got.Guardrails == nil {
+		t.Errorf("Expected the envelope unchanged, got %+v", got)
+	}
+}
@@ -0,0 +1,354 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/zerolog/log"
+)
+
+// serverMethods are the request methods the SDK server dispatches. Requests
+// for anything else are answered with "method not found" by the transport,
+// because the SDK rejects them before any middleware with a code-less error.
+var serverMethods = map[string]bool{
+	"initialize":               true,
+	"ping":                     true,
+	"tools/list":               true,
+	"tools/call":               true,
+	"prompts/list":             true,
+	"prompts/get":              true,
+	"resources/list":           true,
+	"resources/templates/list": true,
+	"resources/read":           true,
+	"resources/subscribe":      true,
+	"resources/unsubscribe":    true,
+	"completion/complete":      true,
+	"logging/setLevel":         true,
+}
+
+// maxExactID is the largest integer request ID that survives the SDK's
+// float64 decoding unchanged.
+const maxExactID = 1 << 53
+
+// stdioTransport is a newline-delimited JSON-RPC 2.0 transport that answers
+// protocol errors instead of dropping the connection. The SDK's stdio
+// transport closes the session on the first malformed line, rejects batches
+// from 2025-06-18 clients, and truncates fractional IDs; this one answers
+// parse errors (-32700), invalid requests (-32600), and unknown methods
+// (-32601) in line, and accepts batches from every protocol version.
+type stdioTransport struct {
+	in  io.ReadCloser
+	out io.WriteCloser
+}
+
+func newStdioTransport(in io.ReadCloser, out io.WriteCloser) *stdioTransport {
+	return &stdioTransport{in: in, out: out}
+}
+
+func (t *stdioTransport) Connect(context.Context) (mcp.Connection, error) {
+	return newRPCConn(t.in, t.out), nil
+}
+
+type lineOrErr struct {
+	line []byte
+	err  error
+}
+
+// rpcBatch collects the responses to one incoming batch until every request
+// in it has been answered.
+type rpcBatch struct {
+	pending   int
+	responses []json.RawMessage
+}
+
+type rpcConn struct {
+	in  io.ReadCloser
+	out io.WriteCloser
+
+	incoming <-chan lineOrErr
+	queue    []jsonrpc.Message // decoded messages of the last batch not yet read
+
+	writeMu sync.Mutex
+	batchMu sync.Mutex
+	batches map[jsonrpc.ID]*rpcBatch // request ID → batch awaiting its response
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	closeErr  error
+}
+
+func newRPCConn(in io.ReadCloser, out io.WriteCloser) *rpcConn {
+	incoming := make(chan lineOrErr)
+	closed := make(chan struct{})
+	// Read in a goroutine so Close unblocks a pending Read.
+	go func() {
+		r := bufio.NewReader(in)
+		for {
+			line, err := r.ReadBytes('\n')
+			if len(line) > 0 {
+				select {
+				case incoming <- lineOrErr{line: line}:
+				case <-closed:
+					return
+				}
+			}
+			if err != nil {
+				select {
+				case incoming <- lineOrErr{err: err}:
+				case <-closed:
+				}
+				return
+			}
+		}
+	}()
+	return &rpcConn{
+		in:       in,
+		out:      out,
+		incoming: incoming,
+		batches:  make(map[jsonrpc.ID]*rpcBatch),
+		closed:   closed,
+	}
+}
+
+func (c *rpcConn) SessionID() string { return "" }
+
+func (c *rpcConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	for {
+		if len(c.queue) > 0 {
+			next := c.queue[0]
+			c.queue = c.queue[1:]
+			return next, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-c.closed:
+			return nil, io.EOF
+		case v := <-c.incoming:
+			if v.err != nil {
+				return nil, v.err
+			}
+			msgs, err := c.decodeLine(v.line)
+			if err != nil {
+				return nil, err
+			}
+			c.queue = msgs
+		}
+	}
+}
+
+// decodeLine decodes one line into the messages to hand to the SDK. Invalid
+// messages are answered here and left out.
+func (c *rpcConn) decodeLine(line []byte) ([]jsonrpc.Message, error) {
+	line = bytes.TrimSpace(line)
+	if len(line) == 0 {
+		return nil, nil
+	}
+	if line[0] != '[' {
+		msg, errResp := decodeMessage(line)
+		if errResp != nil {
+			return nil, c.writeLine(errResp)
+		}
+		if msg == nil {
+			return nil, nil
+		}
+		return []jsonrpc.Message{msg}, nil
+	}
+
+	var raws []json.RawMessage
+	if err := json.Unmarshal(line, &raws); err != nil {
+		return nil, c.writeLine(errorResponse(nil, jsonrpc.CodeParseError, "Parse error"))
+	}
+	if len(raws) == 0 {
+		return nil, c.writeLine(errorResponse(nil, jsonrpc.CodeInvalidRequest, "Invalid Request: empty batch"))
+	}
+
+	batch := &rpcBatch{}
+	var msgs []jsonrpc.Message
+	c.batchMu.Lock()
+	for _, raw := range raws {
+		msg, errResp := decodeMessage(raw)
+		if errResp != nil {
+			batch.responses = append(batch.responses, errResp)
+			continue
+		}
+		if req, ok := msg.(*jsonrpc.Request); ok && req.IsCall() {
+			if _, dup := c.batches[req.ID]; dup {
+				batch.responses = append(batch.responses, errorResponse(idJSON(req.ID), jsonrpc.CodeInvalidRequest, "Invalid Request: duplicate id"))
+				continue
+			}
+			c.batches[req.ID] = batch
+			batch.pending++
+		}
+		if msg != nil {
+			msgs = append(msgs, msg)
+		}
+	}
+	c.batchMu.Unlock()
+
+	if batch.pending == 0 && len(batch.responses) > 0 {
+		return msgs, c.writeBatch(batch.responses)
+	}
+	return msgs, nil
+}
+
+// decodeMessage validates a single JSON-RPC message. It returns the message
+// for the SDK, or the error response to send instead. Notifications of
+// unknown methods yield neither: JSON-RPC never answers a notification.
+func decodeMessage(raw []byte) (jsonrpc.Message, []byte) {
+	if !json.Valid(raw) {
+		return nil, errorResponse(nil, jsonrpc.CodeParseError, "Parse error")
+	}
+	var probe struct {
+		Version *string         `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Method  *string         `json:"method"`
+		Result  json.RawMessage `json:"result"`
+		Error   json.RawMessage `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &probe); err != nil {
+		return nil, errorResponse(nil, jsonrpc.CodeInvalidRequest, "Invalid Request: not a JSON-RPC object")
+	}
+	if !validID(probe.ID) {
+		return nil, errorResponse(nil, jsonrpc.CodeInvalidRequest, "Invalid Request: id must be a string or an integer")
+	}
+	id := probe.ID
+	if isNull(id) {
+		id = nil
+	}
+	if probe.Version == nil || *probe.Version != "2.0" {
+		return nil, errorResponse(id, jsonrpc.CodeInvalidRequest, `Invalid Request: jsonrpc must be "2.0"`)
+	}
+	if probe.Method != nil {
+		if *probe.Method == "" {
+			return nil, errorResponse(id, jsonrpc.CodeInvalidRequest, "Invalid Request: empty method")
+		}
+		if !serverMethods[*probe.Method] && !isNotificationMethod(*probe.Method) {
+			if id == nil {
+				log.Debug().Str("method", *probe.Method).Msg("Ignoring notification of unknown method")
+				return nil, nil
+			}
+			return nil, errorResponse(id, jsonrpc.CodeMethodNotFound, "Method not found: "+*probe.Method)
+		}
+	}
+	if probe.Method == nil && probe.Result == nil && probe.Error == nil {
+		return nil, errorResponse(id, jsonrpc.CodeInvalidRequest, "Invalid Request: neither a request nor a response")
+	}
+	msg, err := jsonrpc.DecodeMessage(raw)
+	if err != nil {
+		return nil, errorResponse(id, jsonrpc.CodeInvalidRequest, "Invalid Request: "+err.Error())
+	}
+	return msg, nil
+}
+
+// isNotificationMethod reports whether method is a notification, which the
+// SDK handles or ignores itself.
+func isNotificationMethod(method string) bool {
+	return strings.HasPrefix(method, "notifications/")
+}
+
+// validID reports whether a raw request ID is absent, null, a string, or an
+// integer the SDK can carry without loss.
+func validID(raw json.RawMessage) bool {
+	if len(raw) == 0 || isNull(raw) {
+		return true
+	}
+	switch raw[0] {
+	case '"':
+		var s string
+		return json.Unmarshal(raw, &s) == nil
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		n, err := strconv.ParseInt(string(raw), 10, 64)
+		return err == nil && n <= maxExactID && n >= -maxExactID
+	}
+	return false
+}
+
+func isNull(raw json.RawMessage) bool {
+	return string(raw) == "null"
+}
+
+func idJSON(id jsonrpc.ID) json.RawMessage {
+	raw, _ := json.Marshal(id.Raw())
+	return raw
+}
+
+// errorResponse encodes a JSON-RPC error response. A nil id is sent as null,
+// as the specification requires when the request ID could not be determined.
+func errorResponse(id json.RawMessage, code int64, message string) []byte {
+	if id == nil {
+		id = json.RawMessage("null")
+	}
+	out, _ := json.Marshal(struct {
+		Version string          `json:"jsonrpc"`
+		ID      json.RawMessage `json:"id"`
+		Error   jsonrpc.Error   `json:"error"`
+	}{"2.0", id, jsonrpc.Error{Code: code, Message: message}})
+	return out
+}
+
+func (c *rpcConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+	}
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	if resp, ok := msg.(*jsonrpc.Response); ok {
+		// Decide under the lock which response completes the batch, so that
+		// exactly one writer sends it.
+		var done bool
+		var responses []json.RawMessage
+		c.batchMu.Lock()
+		batch, inBatch := c.batches[resp.ID]
+		if inBatch {
+			delete(c.batches, resp.ID)
+			batch.responses = append(batch.responses, data)
+			batch.pending--
+			done, responses = batch.pending == 0, batch.responses
+		}
+		c.batchMu.Unlock()
+		if inBatch {
+			if !done {
+				return nil
+			}
+			return c.writeBatch(responses)
+		}
+	}
+	return c.writeLine(data)
+}
+
+func (c *rpcConn) writeBatch(responses []json.RawMessage) error {
+	data, err := json.Marshal(responses)
+	if err != nil {
+		return err
+	}
+	return c.writeLine(data)
+}
+
+func (c *rpcConn) writeLine(data []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_, err := c.out.Write(append(data, '\n'))
+	return err
+}
+
+func (c *rpcConn) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.closeErr = errors.Join(c.in.Close(), c.out.Close())
+	})
+	return c.closeErr
+}
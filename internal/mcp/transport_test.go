@@ -0,0 +1,183 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+)
+
+// rawSession drives the stdio transport with raw JSON-RPC lines.
+type rawSession struct {
+	t     *testing.T
+	in    *io.PipeWriter
+	lines chan string
+}
+
+func newRawSession(t *testing.T) *rawSession {
+	t.Helper()
+	mcpSrv, err := NewMCPServer(newGoldenServer(t), "test")
+	if err != nil {
+		t.Fatalf("NewMCPServer: %v", err)
+	}
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = mcpSrv.Run(ctx, newStdioTransport(inR, outW)) }()
+	t.Cleanup(func() { cancel(); inW.Close() })
+
+	rs := &rawSession{t: t, in: inW, lines: make(chan string, 16)}
+	go func() {
+		sc := bufio.NewScanner(outR)
+		sc.Buffer(nil, 1<<24)
+		for sc.Scan() {
+			rs.lines <- sc.Text()
+		}
+	}()
+	rs.call(`{"jsonrpc":"2.0","id":"init-1","method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"raw","version":"1"}}}`)
+	rs.send(`{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	return rs
+}
+
+func (rs *rawSession) send(line string) {
+	rs.t.Helper()
+	if _, err := fmt.Fprintln(rs.in, line); err != nil {
+		rs.t.Fatalf("write %s: %v", line, err)
+	}
+}
+
+// call sends a line and decodes the next line the server writes.
+func (rs *rawSession) call(line string) any {
+	rs.t.Helper()
+	rs.send(line)
+	select {
+	case out := <-rs.lines:
+		var v any
+		if err := json.Unmarshal([]byte(out), &v); err != nil {
+			rs.t.Fatalf("response to %s is not JSON: %s", line, out)
+		}
+		return v
+	case <-time.After(5 * time.Second):
+		rs.t.Fatalf("no response to %s", line)
+		return nil
+	}
+}
+
+func errorCode(v any) float64 {
+	resp, _ := v.(map[string]any)
+	e, _ := resp["error"].(map[string]any)
+	code, _ := e["code"].(float64)
+	return code
+}
+
+func TestStdioTransport_Conformance(t *testing.T) {
+	rs := newRawSession(t)
+
+	cases := []struct {
+		name string
+		line string
+		id   any
+		code float64
+	}{
+		{"ping with numeric id", `{"jsonrpc":"2.0","id":7,"method":"ping"}`, float64(7), 0},
+		{"ping with string id", `{"jsonrpc":"2.0","id":"p-8","method":"ping"}`, "p-8", 0},
+		{"unknown method", `{"jsonrpc":"2.0","id":9,"method":"no/such"}`, float64(9), -32601},
+		{"parse error", `{"jsonrpc":"2.0","id":10,`, nil, -32700},
+		{"missing version", `{"id":11,"method":"ping"}`, float64(11), -32600},
+		{"fractional id", `{"jsonrpc":"2.0","id":1.5,"method":"ping"}`, nil, -32600},
+		{"neither request nor response", `{"jsonrpc":"2.0","id":12}`, float64(12), -32600},
+		{"empty batch", `[]`, nil, -32600},
+		{"not an object", `42`, nil, -32600},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			resp := rs.call(tc.line).(map[string]any)
+			if resp["id"] != tc.id {
+				t.Errorf("Expected id %v, got %v", tc.id, resp["id"])
+			}
+			if code := errorCode(resp); code != tc.code {
+				t.Errorf("Expected error code %v, got %v (%v)", tc.code, code, resp)
+			}
+		})
+	}
+
+	// Notifications are never answered: the next line belongs to the ping.
+	rs.send(`{"jsonrpc":"2.0","method":"no/such/notification"}`)
+	rs.send(`{"jsonrpc":"2.0","method":"notifications/unknown"}`)
+	if resp := rs.call(`{"jsonrpc":"2.0","id":13,"method":"ping"}`).(map[string]any); resp["id"] != float64(13) {
+		t.Errorf("Expected the ping response after the notifications, got %v", resp)
+	}
+}
+
+func TestStdioTransport_Batch(t *testing.T) {
+	rs := newRawSession(t)
+
+	out := rs.call(`[{"jsonrpc":"2.0","id":1,"method":"ping"},{"jsonrpc":"2.0","method":"notifications/initialized"},{"jsonrpc":"2.0","id":"two","method":"no/such"},{"jsonrpc":"2.0","id":3,"method":"tools/list"}]`)
+	batch, ok := out.([]any)
+	if !ok || len(batch) != 3 {
+		t.Fatalf("Expected a batch of 3 responses, got %v", out)
+	}
+	byID := map[any]map[string]any{}
+	for _, r := range batch {
+		resp := r.(map[string]any)
+		byID[resp["id"]] = resp
+	}
+	if resp := byID[float64(1)]; resp == nil || resp["result"] == nil {
+		t.Errorf("Expected a ping result, got %v", resp)
+	}
+	if code := errorCode(byID["two"]); code != -32601 {
+		t.Errorf("Expected method not found for id two, got %v", byID["two"])
+	}
+	if resp := byID[float64(3)]; resp == nil || resp["result"] == nil {
+		t.Errorf("Expected a tools/list result, got %v", resp)
+	}
+
+	if resp := rs.call(`{"jsonrpc":"2.0","id":4,"method":"ping"}`).(map[string]any); resp["id"] != float64(4) {
+		t.Errorf("Expected single messages to work after a batch, got %v", resp)
+	}
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// TestRPCConn_ConcurrentBatchResponses answers every request of a batch from
+// its own goroutine; run it with -race. Exactly one of them must send the
+// batch.
+func TestRPCConn_ConcurrentBatchResponses(t *testing.T) {
+	const size = 16
+	for range 50 {
+		var out bytes.Buffer
+		c := &rpcConn{out: nopWriteCloser{&out}, batches: make(map[jsonrpc.ID]*rpcBatch)}
+		batch := &rpcBatch{pending: size}
+		ids := make([]jsonrpc.ID, size)
+		for i := range ids {
+			ids[i], _ = jsonrpc.MakeID(float64(i))
+			c.batches[ids[i]] = batch
+		}
+
+		var wg sync.WaitGroup
+		for _, id := range ids {
+			wg.Go(func() {
+				if err := c.Write(context.Background(), &jsonrpc.Response{ID: id, Result: json.RawMessage(`{}`)}); err != nil {
+					t.Errorf("Write: %v", err)
+				}
+			})
+		}
+		wg.Wait()
+
+		lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+		var responses []any
+		if len(lines) != 1 || json.Unmarshal([]byte(lines[0]), &responses) != nil || len(responses) != size {
+			t.Fatalf("Expected one batch of %d responses, got %d line(s): %s", size, len(lines), out.String())
+		}
+	}
+}
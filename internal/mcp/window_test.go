@@ -1,6 +1,7 @@
 package mcp
 
 import (
+	"context"
 	"strings"
 	"testing"
 	"time"
@@ -9,8 +10,9 @@ import (
 )
 
 func TestWindow_DefaultLazy(t *testing.T) {
+	ctx := context.Background()
 	s := &Server{}
-	start, end, explicit := s.Window()
+	start, end, explicit := s.Window(ctx)
 
 	if explicit {
 		t.Fatalf("expected explicit=false on a fresh server, got true")
@@ -26,10 +28,11 @@ func TestWindow_DefaultLazy(t *testing.T) {
 }
 
 func TestWindow_DefaultRespectsEvaluationDate(t *testing.T) {
+	ctx := context.Background()
 	pinned := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
 	s := &Server{activeEvaluationDate: &pinned}
 
-	start, end, explicit := s.Window()
+	start, end, explicit := s.Window(ctx)
 
 	if explicit {
 		t.Fatalf("expected explicit=false, got true")
@@ -44,11 +47,12 @@ func TestWindow_DefaultRespectsEvaluationDate(t *testing.T) {
 }
 
 func TestWindow_ExplicitSession(t *testing.T) {
+	ctx := context.Background()
 	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
 	s := &Server{activeWindowStart: &start, activeWindowEnd: &end}
 
-	gotStart, gotEnd, explicit := s.Window()
+	gotStart, gotEnd, explicit := s.Window(ctx)
 
 	if !explicit {
 		t.Fatalf("expected explicit=true")
@@ -59,14 +63,15 @@ func TestWindow_ExplicitSession(t *testing.T) {
 }
 
 func TestSetAnalysisWindow_DurationOnly(t *testing.T) {
+	ctx := context.Background()
 	pinned := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
 	s := &Server{activeEvaluationDate: &pinned}
 
-	if _, err := s.handleSetAnalysisWindow("", "", 60, false); err != nil {
+	if _, err := s.handleSetAnalysisWindow(ctx, "", "", 60, false); err != nil {
 		t.Fatalf("set window: %v", err)
 	}
 
-	start, end, explicit := s.Window()
+	start, end, explicit := s.Window(ctx)
 	if !explicit {
 		t.Fatalf("expected explicit window after set")
 	}
@@ -80,14 +85,15 @@ func TestSetAnalysisWindow_DurationOnly(t *testing.T) {
 }
 
 func TestSetAnalysisWindow_ExplicitDates(t *testing.T) {
+	ctx := context.Background()
 	pinned := time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)
 	s := &Server{activeEvaluationDate: &pinned}
 
-	if _, err := s.handleSetAnalysisWindow("2024-01-01", "2024-04-01", 0, false); err != nil {
+	if _, err := s.handleSetAnalysisWindow(ctx, "2024-01-01", "2024-04-01", 0, false); err != nil {
 		t.Fatalf("set window: %v", err)
 	}
 
-	start, end, _ := s.Window()
+	start, end, _ := s.Window(ctx)
 	if start.Format("2006-01-02") != "2024-01-01" {
 		t.Fatalf("start = %v, want 2024-01-01", start)
 	}
@@ -97,19 +103,21 @@ func TestSetAnalysisWindow_ExplicitDates(t *testing.T) {
 }
 
 func TestSetAnalysisWindow_Reset(t *testing.T) {
+	ctx := context.Background()
 	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
 	s := &Server{activeWindowStart: &start, activeWindowEnd: &end}
 
-	if _, err := s.handleSetAnalysisWindow("", "", 0, true); err != nil {
+	if _, err := s.handleSetAnalysisWindow(ctx, "", "", 0, true); err != nil {
 		t.Fatalf("reset: %v", err)
 	}
-	if _, _, explicit := s.Window(); explicit {
+	if _, _, explicit := s.Window(ctx); explicit {
 		t.Fatalf("expected explicit=false after reset")
 	}
 }
 
 func TestSetAnalysisWindow_Validation(t *testing.T) {
+	ctx := context.Background()
 	pinned := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
 	cases := []struct {
 		name      string
@@ -129,7 +137,7 @@ func TestSetAnalysisWindow_Validation(t *testing.T) {
 	for _, tc := range cases {
 		t.Run(tc.name, func(t *testing.T) {
 			s := &Server{activeEvaluationDate: &pinned}
-			_, err := s.handleSetAnalysisWindow(tc.start, tc.end, tc.days, tc.reset)
+			_, err := s.handleSetAnalysisWindow(ctx, tc.start, tc.end, tc.days, tc.reset)
 			if err == nil || !strings.Contains(err.Error(), tc.wantError) {
 				t.Fatalf("got err=%v, want substring %q", err, tc.wantError)
 			}
@@ -138,6 +146,7 @@ func TestSetAnalysisWindow_Validation(t *testing.T) {
 }
 
 func TestAnchorContext_ResetsWindow(t *testing.T) {
+	ctx := context.Background()
 	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
 	end := time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)
 	s := &Server{
@@ -150,7 +159,7 @@ func TestAnchorContext_ResetsWindow(t *testing.T) {
 	s.activeWindowStart = nil
 	s.activeWindowEnd = nil
 
-	if _, _, explicit := s.Window(); explicit {
+	if _, _, explicit := s.Window(ctx); explicit {
 		t.Fatalf("expected window cleared")
 	}
 }
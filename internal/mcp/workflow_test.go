@@ -3,6 +3,9 @@ package mcp
 import (
 	"reflect"
 	"testing"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/stats"
 )
 
 func TestSliceRange(t *testing.T) {
@@ -54,3 +57,35 @@ func TestSliceRange(t *testing.T) {
 		})
 	}
 }
+
+func TestRestoreActiveContext(t *testing.T) {
+	dir := t.TempDir()
+
+	first := NewServer(&config.AppConfig{CacheDir: dir}, &DummyClient{})
+	if err := first.anchorContext("PROJ", 1); err != nil {
+		t.Fatal(err)
+	}
+	first.activeMapping = map[string]stats.StatusMetadata{"10": {Name: "In Progress", Tier: "Downstream"}}
+	first.activeStatusOrder = []string{"10"}
+	first.activeCommitmentPoint = "10"
+	if err := first.saveWorkflow("PROJ", 1); err != nil {
+		t.Fatal(err)
+	}
+
+	// A restarted server resumes the last board with its confirmed workflow.
+	restarted := NewServer(&config.AppConfig{CacheDir: dir}, &DummyClient{})
+	restarted.RestoreActiveContext()
+	if restarted.activeSourceID != "PROJ_1" {
+		t.Fatalf("Expected PROJ_1 to be restored, got %q", restarted.activeSourceID)
+	}
+	if restarted.activeCommitmentPoint != "10" || len(restarted.activeMapping) != 1 {
+		t.Errorf("Expected persisted workflow to be loaded, got cp=%q mapping=%v", restarted.activeCommitmentPoint, restarted.activeMapping)
+	}
+
+	// Without a record the server stays unanchored.
+	fresh := NewServer(&config.AppConfig{CacheDir: t.TempDir()}, &DummyClient{})
+	fresh.RestoreActiveContext()
+	if fresh.activeSourceID != "" {
+		t.Errorf("Expected no active context, got %q", fresh.activeSourceID)
+	}
+}
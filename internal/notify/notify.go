@@ -0,0 +1,199 @@
+// Package notify posts flow digests to chat webhooks. A Digest is built
+// once and rendered per chat: Slack incoming webhooks receive mrkdwn text,
+// Microsoft Teams workflow webhooks an Adaptive Card.
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// Kind is the chat a webhook posts to.
+type Kind string
+
+const (
+	Slack Kind = "slack"
+	Teams Kind = "teams"
+)
+
+// ParseKind validates a webhook kind; "" is Slack.
+func ParseKind(s string) (Kind, error) {
+	switch k := Kind(strings.ToLower(strings.TrimSpace(s))); k {
+	case "", Slack:
+		return Slack, nil
+	case Teams:
+		return Teams, nil
+	default:
+		return "", fmt.Errorf("unknown webhook kind %q: expected slack or teams", s)
+	}
+}
+
+// Digest is a report of findings per source.
+type Digest struct {
+	Title    string
+	Sections []Section
+}
+
+// Section holds the findings about one source.
+type Section struct {
+	Title    string
+	Findings []Finding
+}
+
+// Finding is the outcome of one analysis.
+type Finding struct {
+	Analysis string   // Short label, e.g. "Aging"
+	Summary  string   // One sentence
+	Details  []string // Bullet lines, e.g. the items concerned
+	Alert    bool     // Needs attention
+}
+
+// Alerts counts the findings that need attention.
+func (d Digest) Alerts() int {
+	n := 0
+	for _, s := range d.Sections {
+		for _, f := range s.Findings {
+			if f.Alert {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// String renders the digest as plain text.
+func (d Digest) String() string {
+	var b strings.Builder
+	b.WriteString(d.Title + "\n")
+	for _, s := range d.Sections {
+		b.WriteString("\n" + s.Title + "\n")
+		for _, f := range s.Findings {
+			mark := ""
+			if f.Alert {
+				mark = "! "
+			}
+			fmt.Fprintf(&b, "  %s%s: %s\n", mark, f.Analysis, f.Summary)
+			for _, l := range f.Details {
+				b.WriteString("    - " + l + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// Payload renders the digest as the JSON body of a webhook of kind k.
+func Payload(k Kind, d Digest) ([]byte, error) {
+	switch k {
+	case Slack:
+		return json.Marshal(map[string]string{"text": slackText(d)})
+	case Teams:
+		return json.Marshal(teamsMessage(d))
+	default:
+		return nil, fmt.Errorf("unknown webhook kind %q", k)
+	}
+}
+
+// slackEscape escapes the characters Slack reserves for links and mentions.
+var slackEscape = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func slackText(d Digest) string {
+	var b strings.Builder
+	b.WriteString("*" + slackEscape.Replace(d.Title) + "*\n")
+	for _, s := range d.Sections {
+		b.WriteString("\n*" + slackEscape.Replace(s.Title) + "*\n")
+		for _, f := range s.Findings {
+			mark := ""
+			if f.Alert {
+				mark = ":warning: "
+			}
+			fmt.Fprintf(&b, "%s*%s:* %s\n", mark, slackEscape.Replace(f.Analysis), slackEscape.Replace(f.Summary))
+			for _, l := range f.Details {
+				b.WriteString("    • " + slackEscape.Replace(l) + "\n")
+			}
+		}
+	}
+	return b.String()
+}
+
+// teamsMessage wraps the digest in an Adaptive Card, the format accepted by
+// Teams workflow ("Post to a channel when a webhook request is received")
+// webhooks.
+func teamsMessage(d Digest) map[string]any {
+	text := func(s string, extra map[string]any) map[string]any {
+		block := map[string]any{"type": "TextBlock", "text": s, "wrap": true}
+		for k, v := range extra {
+			block[k] = v
+		}
+		return block
+	}
+	body := []map[string]any{text(d.Title, map[string]any{"size": "Large", "weight": "Bolder"})}
+	for _, s := range d.Sections {
+		body = append(body, text(s.Title, map[string]any{"size": "Medium", "weight": "Bolder", "separator": true}))
+		for _, f := range s.Findings {
+			mark := ""
+			if f.Alert {
+				mark = "⚠️ "
+			}
+			lines := []string{fmt.Sprintf("%s**%s:** %s", mark, f.Analysis, f.Summary)}
+			for _, l := range f.Details {
+				lines = append(lines, "- "+l)
+			}
+			body = append(body, text(strings.Join(lines, "\n"), nil))
+		}
+	}
+	return map[string]any{
+		"type": "message",
+		"attachments": []map[string]any{{
+			"contentType": "application/vnd.microsoft.card.adaptive",
+			"content": map[string]any{
+				"$schema": "http://adaptivecards.io/schemas/adaptive-card.json",
+				"type":    "AdaptiveCard",
+				"version": "1.4",
+				"body":    body,
+			},
+		}},
+	}
+}
+
+// Webhook posts digests to a chat.
+type Webhook struct {
+	Kind   Kind
+	URL    string
+	Client *http.Client // nil = a client with a 30-second timeout
+}
+
+var defaultClient = &http.Client{Timeout: 30 * time.Second}
+
+// Send posts the digest. A response other than 2xx is an error.
+func (w Webhook) Send(ctx context.Context, d Digest) error {
+	body, err := Payload(w.Kind, d)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", w.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := w.Client
+	if client == nil {
+		client = defaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s webhook: %w", w.Kind, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s webhook returned %s: %s", w.Kind, resp.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
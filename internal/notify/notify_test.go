@@ -0,0 +1,96 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+var testDigest = Digest{
+	Title: "Flow digest 2026-10-17",
+	Sections: []Section{{
+		Title: "PROJ board 42",
+		Findings: []Finding{
+			{Analysis: "Aging", Summary: "2 of 9 items in progress are older than P85.", Details: []string{"PROJ-1 <Story>", "PROJ-2 & co"}, Alert: true},
+			{Analysis: "Flow debt", Summary: "Balanced."},
+		},
+	}},
+}
+
+func TestParseKind(t *testing.T) {
+	for in, want := range map[string]Kind{"": Slack, "slack": Slack, " Teams ": Teams} {
+		if got, err := ParseKind(in); err != nil || got != want {
+			t.Errorf("ParseKind(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseKind("discord"); err == nil {
+		t.Error("Expected an unknown kind rejected")
+	}
+}
+
+func TestWebhookSend(t *testing.T) {
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		if r.Header.Get("Content-Type") != "application/json" {
+			w.WriteHeader(http.StatusUnsupportedMediaType)
+		}
+	}))
+	defer srv.Close()
+	ctx := context.Background()
+
+	if err := (Webhook{Kind: Slack, URL: srv.URL}).Send(ctx, testDigest); err != nil {
+		t.Fatalf("Slack: %v", err)
+	}
+	var slack struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(body, &slack); err != nil {
+		t.Fatalf("Expected a Slack message, got %s", body)
+	}
+	for _, want := range []string{"*PROJ board 42*", ":warning: *Aging:*", "PROJ-1 &lt;Story&gt;", "PROJ-2 &amp; co"} {
+		if !strings.Contains(slack.Text, want) {
+			t.Errorf("Expected %q in the Slack text, got %q", want, slack.Text)
+		}
+	}
+
+	if err := (Webhook{Kind: Teams, URL: srv.URL}).Send(ctx, testDigest); err != nil {
+		t.Fatalf("Teams: %v", err)
+	}
+	var teams struct {
+		Attachments []struct {
+			ContentType string `json:"contentType"`
+			Content     struct {
+				Body []struct {
+					Text string `json:"text"`
+				} `json:"body"`
+			} `json:"content"`
+		} `json:"attachments"`
+	}
+	if err := json.Unmarshal(body, &teams); err != nil || len(teams.Attachments) != 1 {
+		t.Fatalf("Expected one card, got %s", body)
+	}
+	card := teams.Attachments[0]
+	if card.ContentType != "application/vnd.microsoft.card.adaptive" || len(card.Content.Body) != 4 {
+		t.Fatalf("Expected an Adaptive Card with title, source, and two findings, got %s", body)
+	}
+	if got := card.Content.Body[2].Text; !strings.HasPrefix(got, "⚠️ **Aging:**") || !strings.Contains(got, "\n- PROJ-1 <Story>") {
+		t.Errorf("Unexpected finding block %q", got)
+	}
+}
+
+func TestWebhookSend_Rejected(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid_token", http.StatusForbidden)
+	}))
+	defer srv.Close()
+
+	err := Webhook{Kind: Slack, URL: srv.URL}.Send(context.Background(), testDigest)
+	if err == nil || !strings.Contains(err.Error(), "403") || !strings.Contains(err.Error(), "invalid_token") {
+		t.Errorf("Expected the rejection reported, got %v", err)
+	}
+}
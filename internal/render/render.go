@@ -0,0 +1,340 @@
+// Package render turns tool results into the text returned to the MCP client:
+// indented JSON (the default), Markdown tables for humans reading agent
+// transcripts, or CSV for spreadsheets. Markdown and CSV are rendered from the
+// JSON encoding of the result, so every result renders without per-tool code:
+// arrays of objects (and objects of identically shaped objects) become tables,
+// and the remaining fields of each object a field/value table.
+package render
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Format is an output format of tool results.
+type Format string
+
+const (
+	JSON     Format = "json"
+	Markdown Format = "markdown"
+	CSV      Format = "csv"
+)
+
+// Formats lists the supported output formats.
+var Formats = []Format{JSON, Markdown, CSV}
+
+// ParseFormat validates a format name. The empty name is JSON.
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(name))); f {
+	case "":
+		return JSON, nil
+	case "md":
+		return Markdown, nil
+	case JSON, Markdown, CSV:
+		return f, nil
+	}
+	return "", fmt.Errorf("unsupported output format %q: use json, markdown, or csv", name)
+}
+
+// Render renders v in format f.
+func Render(v any, f Format) (string, error) {
+	if f == "" || f == JSON {
+		out, err := json.MarshalIndent(v, "", "  ")
+		return string(out), err
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	tree, err := decodeValue(dec)
+	if err != nil {
+		return "", err
+	}
+
+	var tables []table
+	collect("", tree, &tables)
+	switch f {
+	case Markdown:
+		return renderMarkdown(tables), nil
+	case CSV:
+		return renderCSV(tables)
+	}
+	return "", fmt.Errorf("unsupported output format %q", f)
+}
+
+// object is a decoded JSON object that keeps the order of its keys, so tables
+// list struct fields in declaration order.
+type object struct {
+	keys   []string
+	values map[string]any
+}
+
+func decodeValue(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch tok {
+	case json.Delim('{'):
+		obj := &object{values: make(map[string]any)}
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			obj.keys = append(obj.keys, key.(string))
+			obj.values[key.(string)] = v
+		}
+		_, err = dec.Token()
+		return obj, err
+	case json.Delim('['):
+		arr := []any{}
+		for dec.More() {
+			v, err := decodeValue(dec)
+			if err != nil {
+				return nil, err
+			}
+			arr = append(arr, v)
+		}
+		_, err = dec.Token()
+		return arr, err
+	}
+	return tok, nil
+}
+
+// table is a titled grid of cells; the title is the dotted path of the value
+// it was built from ("" for the top level).
+type table struct {
+	title   string
+	columns []string
+	rows    [][]string
+}
+
+// collect appends the tables of v, found at path, to tables.
+func collect(path string, v any, tables *[]table) {
+	obj, ok := v.(*object)
+	if !ok {
+		if rows, ok := v.([]any); ok && isRecords(rows) {
+			*tables = append(*tables, recordTable(path, "", nil, rows))
+			return
+		}
+		*tables = append(*tables, table{title: path, columns: []string{"value"}, rows: [][]string{{cell(v)}}})
+		return
+	}
+
+	if len(obj.keys) > 1 && sameShape(obj) {
+		rows := make([]any, len(obj.keys))
+		for i, k := range obj.keys {
+			rows[i] = obj.values[k]
+		}
+		*tables = append(*tables, recordTable(path, "key", obj.keys, rows))
+		return
+	}
+
+	fields := table{title: path, columns: []string{"field", "value"}}
+	var nested []string
+	for _, k := range obj.keys {
+		switch child := obj.values[k].(type) {
+		case *object:
+			nested = append(nested, k)
+		case []any:
+			if isRecords(child) {
+				nested = append(nested, k)
+				continue
+			}
+			fields.rows = append(fields.rows, []string{k, cell(child)})
+		default:
+			fields.rows = append(fields.rows, []string{k, cell(child)})
+		}
+	}
+	if len(fields.rows) > 0 {
+		*tables = append(*tables, fields)
+	}
+	for _, k := range nested {
+		collect(join(path, k), obj.values[k], tables)
+	}
+}
+
+// isRecords reports whether rows is a non-empty array of objects.
+func isRecords(rows []any) bool {
+	if len(rows) == 0 {
+		return false
+	}
+	for _, r := range rows {
+		if _, ok := r.(*object); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// sameShape reports whether all values of obj are objects with the same keys,
+// like a map of issue type to its statistics.
+func sameShape(obj *object) bool {
+	var first []string
+	for i, k := range obj.keys {
+		child, ok := obj.values[k].(*object)
+		if !ok {
+			return false
+		}
+		if i == 0 {
+			first = child.keys
+		} else if !slices.Equal(child.keys, first) {
+			return false
+		}
+	}
+	return true
+}
+
+// recordTable builds a table with one row per record. The columns are the
+// union of the record keys in order of first appearance, preceded by a
+// keyColumn holding keys when the records came from an object.
+func recordTable(path, keyColumn string, keys []string, records []any) table {
+	t := table{title: path}
+	if keyColumn != "" {
+		t.columns = append(t.columns, keyColumn)
+	}
+	offset := len(t.columns)
+	for _, r := range records {
+		for _, k := range r.(*object).keys {
+			if !slices.Contains(t.columns[offset:], k) {
+				t.columns = append(t.columns, k)
+			}
+		}
+	}
+	for i, r := range records {
+		row := make([]string, len(t.columns))
+		if keyColumn != "" {
+			row[0] = keys[i]
+		}
+		values := r.(*object).values
+		for j, col := range t.columns[offset:] {
+			if v, ok := values[col]; ok {
+				row[offset+j] = cell(v)
+			}
+		}
+		t.rows = append(t.rows, row)
+	}
+	return t
+}
+
+// cell renders a value as table cell text: scalars as is, arrays of scalars
+// one per line, and anything nested as compact JSON.
+func cell(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return fmt.Sprint(v)
+	case []any:
+		if !slices.ContainsFunc(v, func(e any) bool { _, nested := e.(*object); _, arr := e.([]any); return nested || arr }) {
+			lines := make([]string, len(v))
+			for i, e := range v {
+				lines[i] = cell(e)
+			}
+			return strings.Join(lines, "\n")
+		}
+	}
+	var b strings.Builder
+	writeCompact(&b, v)
+	return b.String()
+}
+
+func writeCompact(b *strings.Builder, v any) {
+	switch v := v.(type) {
+	case *object:
+		b.WriteByte('{')
+		for i, k := range v.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(k)
+			b.Write(key)
+			b.WriteByte(':')
+			writeCompact(b, v.values[k])
+		}
+		b.WriteByte('}')
+	case []any:
+		b.WriteByte('[')
+		for i, e := range v {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeCompact(b, e)
+		}
+		b.WriteByte(']')
+	default:
+		out, _ := json.Marshal(v)
+		b.Write(out)
+	}
+}
+
+func join(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func renderMarkdown(tables []table) string {
+	escape := strings.NewReplacer("|", `\|`, "\n", "<br>")
+	var b strings.Builder
+	for i, t := range tables {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		if t.title != "" {
+			fmt.Fprintf(&b, "### %s\n\n", t.title)
+		}
+		b.WriteString("| " + strings.Join(t.columns, " | ") + " |\n")
+		b.WriteString("|" + strings.Repeat(" --- |", len(t.columns)) + "\n")
+		for _, row := range t.rows {
+			cells := make([]string, len(row))
+			for j, c := range row {
+				cells[j] = escape.Replace(c)
+			}
+			b.WriteString("| " + strings.Join(cells, " | ") + " |\n")
+		}
+	}
+	return b.String()
+}
+
+// renderCSV writes the tables one after another, each preceded by a "# title"
+// record and separated by an empty line, so a single table stays plain CSV.
+func renderCSV(tables []table) (string, error) {
+	var b strings.Builder
+	w := csv.NewWriter(&b)
+	for i, t := range tables {
+		if i > 0 {
+			w.Flush()
+			b.WriteString("\n")
+		}
+		if len(tables) > 1 && t.title != "" {
+			if err := w.Write([]string{"# " + t.title}); err != nil {
+				return "", err
+			}
+		}
+		if err := w.Write(t.columns); err != nil {
+			return "", err
+		}
+		if err := w.WriteAll(t.rows); err != nil {
+			return "", err
+		}
+	}
+	w.Flush()
+	return b.String(), w.Error()
+}
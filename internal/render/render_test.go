@@ -0,0 +1,70 @@
+package render
+
+import (
+	"strings"
+	"testing"
+)
+
+type stat struct {
+	Status string  `json:"status"`
+	P50    float64 `json:"p50"`
+}
+
+func TestRender(t *testing.T) {
+	result := map[string]any{
+		"data": map[string]any{
+			"persistence": []stat{{"Dev", 2.5}, {"QA | Review", 1}},
+			"total":       2,
+		},
+		"warnings": []string{"small sample", "no SLE"},
+	}
+
+	md, err := Render(result, Markdown)
+	if err != nil {
+		t.Fatalf("markdown: %v", err)
+	}
+	for _, want := range []string{
+		"| field | value |\n| --- | --- |\n| warnings | small sample<br>no SLE |",
+		"### data\n\n| field | value |\n| --- | --- |\n| total | 2 |",
+		"### data.persistence\n\n| status | p50 |\n| --- | --- |\n| Dev | 2.5 |\n| QA \\| Review | 1 |",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown lacks %q:\n%s", want, md)
+		}
+	}
+
+	out, err := Render(result, CSV)
+	if err != nil {
+		t.Fatalf("csv: %v", err)
+	}
+	if want := "# data.persistence\nstatus,p50\nDev,2.5\nQA | Review,1\n"; !strings.Contains(out, want) {
+		t.Errorf("csv lacks %q:\n%s", want, out)
+	}
+	if want := "field,value\nwarnings,\"small sample\nno SLE\"\n"; !strings.HasPrefix(out, want) {
+		t.Errorf("csv does not start with %q:\n%s", want, out)
+	}
+
+	single, _ := Render([]stat{{"Dev", 2.5}}, CSV)
+	if single != "status,p50\nDev,2.5\n" {
+		t.Errorf("a single table should render as plain CSV, got %q", single)
+	}
+}
+
+func TestRender_SameShapeObjects(t *testing.T) {
+	byType := map[string]stat{"Bug": {"Dev", 1}, "Story": {"QA", 3}}
+	out, _ := Render(byType, CSV)
+	if out != "key,status,p50\nBug,Dev,1\nStory,QA,3\n" {
+		t.Errorf("objects of objects should render as a keyed table, got %q", out)
+	}
+}
+
+func TestParseFormat(t *testing.T) {
+	for name, want := range map[string]Format{"": JSON, "JSON": JSON, "md": Markdown, "markdown": Markdown, " csv ": CSV} {
+		if got, err := ParseFormat(name); err != nil || got != want {
+			t.Errorf("ParseFormat(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+	if _, err := ParseFormat("xml"); err == nil {
+		t.Errorf("expected an error for an unsupported format")
+	}
+}
@@ -1,18 +1,31 @@
 package simulation
 
 import (
+	"fmt"
 	"math"
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
 	"slices"
 )
 
+// Stratification modes: whether item types are sampled as streams of their own.
+const (
+	// StratificationAuto stratifies the types that meet the volume and
+	// variance criteria (default).
+	StratificationAuto = "auto"
+	// StratificationOn stratifies every type with enough volume, however
+	// close its cycle times are to the pool.
+	StratificationOn = "on"
+	// StratificationOff samples every type from the pooled counts.
+	StratificationOff = "off"
+)
+
 // StratificationDecision holds the result of the eligibility check for a specific type.
 type StratificationDecision struct {
 	Type           string  `json:"type"`
 	Eligible       bool    `json:"eligible"`
 	Reason         string  `json:"reason"`
-	Volume         int     `json:"volume"`
+	Volume         int     `json:"volume"` // Finished items of the type: the sample size
 	P85CycleTime   float64 `json:"p85_cycle_time"`
 	DistanceToPool float64 `json:"distance_to_pool"` // % difference in P85
 }
@@ -22,7 +35,7 @@ func (d *StratificationDecision) Round() {
 	roundFields(&d.P85CycleTime, &d.DistanceToPool)
 }
 
-// AssessStratificationNeeds analyzes a set of delivered issues to decide which types should be stratified.
+// AssessStratificationNeeds analyzes a set of finished issues (those a histogram counts) to decide which types should be stratified.
 func AssessStratificationNeeds(issues []jira.Issue) []StratificationDecision {
 	if len(issues) == 0 {
 		return nil
@@ -33,7 +46,7 @@ func AssessStratificationNeeds(issues []jira.Issue) []StratificationDecision {
 	var allCycleTimes []float64
 
 	for _, iss := range issues {
-		if !stats.IsDelivered(iss) {
+		if !stats.HasExited(iss) {
 			continue
 		}
 
@@ -81,27 +94,42 @@ func AssessStratificationNeeds(issues []jira.Issue) []StratificationDecision {
 			decision.DistanceToPool = (decision.P85CycleTime - pooledP85) / pooledP85
 		}
 
-		// Eligibility Criteria
-		eligible := true
-		reason := "Meets volume and variance criteria"
-
-		if decision.Volume < StratificationMinVolume {
-			eligible = false
-			reason = "Volume too low (< 15 items)"
-		} else if decision.DistanceToPool > -StratificationMinVariance && decision.DistanceToPool < StratificationMinVariance {
-			// If variance is less than 15%, it's close enough to the average to be pooled
-			eligible = false
-			reason = "Insufficient variance from pooled average (< 15%)"
-		}
-
-		decision.Eligible = eligible
-		decision.Reason = reason
+		decision.decide(StratificationAuto, StratificationMinVolume)
 		decisions = append(decisions, decision)
 	}
 
 	return decisions
 }
 
+// decide sets the eligibility of d under mode, with minVolume the fewest
+// items a type needs to be sampled on its own.
+func (d *StratificationDecision) decide(mode string, minVolume int) {
+	d.Eligible = false
+	switch {
+	case mode == StratificationOff:
+		d.Reason = "Stratification off: pooled by request"
+	case d.Volume < minVolume:
+		d.Reason = fmt.Sprintf("Volume too low (< %d items)", minVolume)
+	case mode == StratificationOn:
+		d.Eligible = true
+		d.Reason = "Stratification on: meets the volume criterion"
+	case d.DistanceToPool > -StratificationMinVariance && d.DistanceToPool < StratificationMinVariance:
+		// If variance is less than 15%, it's close enough to the average to be pooled
+		d.Reason = "Insufficient variance from pooled average (< 15%)"
+	default:
+		d.Eligible = true
+		d.Reason = "Meets volume and variance criteria"
+	}
+}
+
+// StratificationSettings records how the stratification of a histogram was
+// decided.
+type StratificationSettings struct {
+	Mode       string   `json:"mode"`
+	MinSample  int      `json:"min_sample"`
+	Stratified []string `json:"stratified"` // Types sampled as streams of their own
+}
+
 // CalculateCorrelation calculates Pearson correlation between two daily throughput series.
 func CalculateCorrelation(a, b []int) float64 {
 	if len(a) != len(b) || len(a) == 0 {
@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestHistogram_Stratify(t *testing.T) {
+	newHistogram := func() *Histogram {
+		return &Histogram{Meta: map[string]any{
+			"stratification_decisions": []StratificationDecision{
+				{Type: "Bug", Volume: 20, DistanceToPool: 0.40},
+				{Type: "Story", Volume: 30, DistanceToPool: 0.05},
+				{Type: "Spike", Volume: 8, DistanceToPool: 0.60},
+			},
+		}}
+	}
+	stratified := func(h *Histogram) []string {
+		settings, ok := h.Meta["stratification"].(StratificationSettings)
+		if !ok {
+			t.Fatalf("Expected the stratification settings in Meta, got %v", h.Meta)
+		}
+		eligible, _ := h.Meta["stratification_eligible"].(map[string]bool)
+		if len(eligible) != len(settings.Stratified) {
+			t.Errorf("Expected the eligibility to match %v, got %v", settings.Stratified, eligible)
+		}
+		return settings.Stratified
+	}
+
+	auto := newHistogram()
+	auto.Stratify("", 0)
+	if got := stratified(auto); !slices.Equal(got, []string{"Bug"}) {
+		t.Errorf("auto: expected only Bug stratified, got %v", got)
+	}
+	if settings := auto.Meta["stratification"].(StratificationSettings); settings.Mode != StratificationAuto || settings.MinSample != StratificationMinVolume {
+		t.Errorf("auto: expected the default settings, got %+v", settings)
+	}
+
+	on := newHistogram()
+	on.Stratify(StratificationOn, 0)
+	if got := stratified(on); !slices.Equal(got, []string{"Bug", "Story"}) {
+		t.Errorf("on: expected Bug and Story stratified regardless of variance, got %v", got)
+	}
+
+	lower := newHistogram()
+	lower.Stratify(StratificationAuto, 5)
+	if got := stratified(lower); !slices.Equal(got, []string{"Bug", "Spike"}) {
+		t.Errorf("min sample 5: expected Bug and Spike stratified, got %v", got)
+	}
+
+	off := newHistogram()
+	off.Stratify(StratificationOff, 0)
+	if got := stratified(off); len(got) != 0 {
+		t.Errorf("off: expected every type pooled, got %v", got)
+	}
+	for _, d := range off.Meta["stratification_decisions"].([]StratificationDecision) {
+		if d.Eligible || d.Reason == "" {
+			t.Errorf("off: expected %s to be pooled with a reason, got %+v", d.Type, d)
+		}
+	}
+	if insight, _ := off.Meta["modeling_insight"].(string); insight != modelingInsight(0) {
+		t.Errorf("off: expected the pooled modeling insight, got %q", insight)
+	}
+}
@@ -0,0 +1,296 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/stats"
+)
+
+// Calendar is a working calendar: the weekdays on which work is delivered,
+// minus holidays and freeze periods. When an engine has a calendar, it samples
+// and simulates working days only and reports durations in calendar days.
+type Calendar struct {
+	Workdays map[time.Weekday]bool
+	Holidays map[string]bool // YYYY-MM-DD
+	Freezes  []FreezePeriod
+}
+
+// FreezePeriod is an inclusive range of dates on which nothing is delivered.
+type FreezePeriod struct {
+	Start time.Time
+	End   time.Time
+}
+
+// CalendarSummary is the output representation of a Calendar.
+type CalendarSummary struct {
+	Workdays      []string `json:"workdays"`
+	Holidays      []string `json:"holidays,omitempty"`
+	FreezePeriods []string `json:"freeze_periods,omitempty"`
+}
+
+var weekdayNames = map[string]time.Weekday{
+	"sun": time.Sunday, "mon": time.Monday, "tue": time.Tuesday, "wed": time.Wednesday,
+	"thu": time.Thursday, "fri": time.Friday, "sat": time.Saturday,
+}
+
+// DefaultCalendar returns a Monday–Friday calendar without holidays.
+func DefaultCalendar() *Calendar {
+	return &Calendar{
+		Workdays: map[time.Weekday]bool{
+			time.Monday: true, time.Tuesday: true, time.Wednesday: true, time.Thursday: true, time.Friday: true,
+		},
+		Holidays: make(map[string]bool),
+	}
+}
+
+// ParseCalendar builds a calendar from comma-separated lists: workdays as
+// three-letter weekday names (empty = Mon–Fri), holidays as YYYY-MM-DD, and
+// freeze periods as YYYY-MM-DD..YYYY-MM-DD.
+func ParseCalendar(workdays, holidays, freezes string) (*Calendar, error) {
+	cal := DefaultCalendar()
+	if names := splitList(workdays); len(names) > 0 {
+		cal.Workdays = make(map[time.Weekday]bool)
+		for _, name := range names {
+			day, ok := weekdayNames[strings.ToLower(name)]
+			if !ok {
+				return nil, fmt.Errorf("invalid workday %q: use Mon, Tue, Wed, Thu, Fri, Sat, or Sun", name)
+			}
+			cal.Workdays[day] = true
+		}
+	}
+	if err := cal.AddHolidays(splitList(holidays)); err != nil {
+		return nil, err
+	}
+	if err := cal.AddFreezes(splitList(freezes)); err != nil {
+		return nil, err
+	}
+	return cal, nil
+}
+
+// Clone returns an independent copy of the calendar.
+func (c *Calendar) Clone() *Calendar {
+	out := &Calendar{
+		Workdays: make(map[time.Weekday]bool, len(c.Workdays)),
+		Holidays: make(map[string]bool, len(c.Holidays)),
+		Freezes:  slices.Clone(c.Freezes),
+	}
+	for d, ok := range c.Workdays {
+		out.Workdays[d] = ok
+	}
+	for d, ok := range c.Holidays {
+		out.Holidays[d] = ok
+	}
+	return out
+}
+
+// AddHolidays marks the given YYYY-MM-DD dates as non-working.
+func (c *Calendar) AddHolidays(dates []string) error {
+	for _, d := range dates {
+		if _, err := time.Parse(stats.DateFormat, d); err != nil {
+			return fmt.Errorf("invalid holiday %q: expected YYYY-MM-DD", d)
+		}
+		c.Holidays[d] = true
+	}
+	return nil
+}
+
+// AddFreezes adds freeze periods given as YYYY-MM-DD..YYYY-MM-DD.
+func (c *Calendar) AddFreezes(periods []string) error {
+	for _, p := range periods {
+		from, to, ok := strings.Cut(p, "..")
+		start, errStart := time.Parse(stats.DateFormat, strings.TrimSpace(from))
+		end, errEnd := time.Parse(stats.DateFormat, strings.TrimSpace(to))
+		if !ok || errStart != nil || errEnd != nil || end.Before(start) {
+			return fmt.Errorf("invalid freeze period %q: expected YYYY-MM-DD..YYYY-MM-DD", p)
+		}
+		c.Freezes = append(c.Freezes, FreezePeriod{Start: start, End: end})
+	}
+	return nil
+}
+
+// IsWorkingDay reports whether work is delivered on the calendar date of t.
+func (c *Calendar) IsWorkingDay(t time.Time) bool {
+	if !c.Workdays[t.Weekday()] {
+		return false
+	}
+	date := t.Format(stats.DateFormat)
+	if c.Holidays[date] {
+		return false
+	}
+	for _, f := range c.Freezes {
+		if date >= f.Start.Format(stats.DateFormat) && date <= f.End.Format(stats.DateFormat) {
+			return false
+		}
+	}
+	return true
+}
+
+// WorkingDaysIn counts the working days among the days calendar days after start.
+func (c *Calendar) WorkingDaysIn(start time.Time, days int) int {
+	n := 0
+	for i := 1; i <= days; i++ {
+		if c.IsWorkingDay(start.AddDate(0, 0, i)) {
+			n++
+		}
+	}
+	return n
+}
+
+// calendarOffsets returns, for k = 0..n, the number of calendar days from
+// start until the k-th working day after it. Index 0 is 0.
+func (c *Calendar) calendarOffsets(start time.Time, n int) []int {
+	offsets := make([]int, n+1)
+	day := 0
+	for k := 1; k <= n; k++ {
+		day++
+		for !c.IsWorkingDay(start.AddDate(0, 0, day)) {
+			day++
+			if day > MaxForecastDays*2 {
+				// A calendar without working days cannot advance; cap instead of looping forever.
+				for ; k <= n; k++ {
+					offsets[k] = day
+				}
+				return offsets
+			}
+		}
+		offsets[k] = day
+	}
+	return offsets
+}
+
+// Summary returns the output representation of the calendar.
+func (c *Calendar) Summary() CalendarSummary {
+	var s CalendarSummary
+	for d := time.Sunday; d <= time.Saturday; d++ {
+		if c.Workdays[d] {
+			s.Workdays = append(s.Workdays, d.String()[:3])
+		}
+	}
+	for d := range c.Holidays {
+		s.Holidays = append(s.Holidays, d)
+	}
+	slices.Sort(s.Holidays)
+	for _, f := range c.Freezes {
+		s.FreezePeriods = append(s.FreezePeriods, f.Start.Format(stats.DateFormat)+".."+f.End.Format(stats.DateFormat))
+	}
+	return s
+}
+
+// RestrictToWorkingDays drops the non-working days of a daily histogram whose
+// first bucket is start. Deliveries recorded on a non-working day are credited
+// to the next working day (the last one for trailing days), so volume is kept.
+func (h *Histogram) RestrictToWorkingDays(cal *Calendar, start time.Time) {
+	if cal == nil || len(h.Counts) == 0 {
+		return
+	}
+	working := make([]bool, len(h.Counts))
+	anyWorking := false
+	for i := range h.Counts {
+		working[i] = cal.IsWorkingDay(start.AddDate(0, 0, i))
+		anyWorking = anyWorking || working[i]
+	}
+	if !anyWorking {
+		return
+	}
+
+	fold := func(counts []int) []int {
+		out := make([]int, 0, len(counts))
+		carry := 0
+		for i, c := range counts {
+			if !working[i] {
+				carry += c
+				continue
+			}
+			out = append(out, c+carry)
+			carry = 0
+		}
+		out[len(out)-1] += carry
+		return out
+	}
+
+	h.Counts = fold(h.Counts)
+	for t, counts := range h.StratifiedCounts {
+		h.StratifiedCounts[t] = fold(counts)
+	}
+	if h.Meta != nil {
+		h.Meta["days_in_sample"] = len(h.Counts)
+	}
+}
+
+// BucketDates returns the day each bucket of the daily histogram of [start,
+// end] stands for: every day without a calendar, the working days once
+// RestrictToWorkingDays has folded the histogram with cal.
+func BucketDates(cal *Calendar, start, end time.Time) []time.Time {
+	days := stats.CalendarDaysBetween(start, end) + 1
+	var all, working []time.Time
+	for i := range max(days, 0) {
+		d := start.AddDate(0, 0, i)
+		all = append(all, d)
+		if cal != nil && cal.IsWorkingDay(d) {
+			working = append(working, d)
+		}
+	}
+	if len(working) == 0 {
+		return all
+	}
+	return working
+}
+
+// SetCalendar makes the engine simulate working days of cal from start on:
+// scope horizons are given in calendar days and converted to working days,
+// and duration results are converted back to calendar days.
+func (e *Engine) SetCalendar(cal *Calendar, start time.Time) {
+	e.calendar = cal
+	e.calendarStart = start
+}
+
+// toCalendarDays converts sorted working-day durations to calendar days in place.
+// The mapping is monotonic, so the slice stays sorted.
+func (e *Engine) toCalendarDays(durations []int) {
+	if e.calendar == nil || len(durations) == 0 {
+		return
+	}
+	offsets := e.calendar.calendarOffsets(e.calendarStart, durations[len(durations)-1])
+	for i, d := range durations {
+		durations[i] = offsets[d]
+	}
+}
+
+// workingDays converts a calendar-day horizon to the working days it contains.
+func (e *Engine) workingDays(days int) int {
+	if e.calendar == nil {
+		return days
+	}
+	return e.calendar.WorkingDaysIn(e.calendarStart, days)
+}
+
+// CompletionDates maps duration percentiles (calendar days from start) to dates.
+func CompletionDates(p Percentiles, start time.Time) map[string]string {
+	at := func(days float64) string {
+		return start.AddDate(0, 0, int(math.Ceil(days))).Format(stats.DateFormat)
+	}
+	return map[string]string{
+		"aggressive":     at(p.Aggressive),
+		"unlikely":       at(p.Unlikely),
+		"coin_toss":      at(p.CoinToss),
+		"probable":       at(p.Probable),
+		"likely":         at(p.Likely),
+		"conservative":   at(p.Conservative),
+		"safe":           at(p.Safe),
+		"almost_certain": at(p.AlmostCertain),
+	}
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
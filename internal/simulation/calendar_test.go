@@ -0,0 +1,94 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseCalendar(t *testing.T) {
+	cal, err := ParseCalendar("Mon,Tue,Wed,Thu", "2026-03-10", "2026-03-16..2026-03-17")
+	if err != nil {
+		t.Fatalf("ParseCalendar: %v", err)
+	}
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	cases := map[int]bool{
+		9:  true,  // Monday
+		10: false, // Holiday
+		13: false, // Friday is not a workday
+		16: false, // Freeze
+		17: false, // Freeze (inclusive end)
+		18: true,
+	}
+	for d, want := range cases {
+		if got := cal.IsWorkingDay(day(d)); got != want {
+			t.Errorf("2026-03-%02d: expected working=%v, got %v", d, want, got)
+		}
+	}
+
+	for _, bad := range [][3]string{{"Mon,Funday", "", ""}, {"", "2026-13-01", ""}, {"", "", "2026-03-10..2026-03-01"}} {
+		if _, err := ParseCalendar(bad[0], bad[1], bad[2]); err == nil {
+			t.Errorf("Expected %v to be rejected", bad)
+		}
+	}
+}
+
+func TestHistogram_RestrictToWorkingDays(t *testing.T) {
+	// 2026-03-06 is a Friday: Fri, Sat, Sun, Mon
+	start := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	h := &Histogram{
+		Counts:           []int{2, 1, 3, 4},
+		StratifiedCounts: map[string][]int{"Story": {1, 0, 1, 2}},
+	}
+	h.RestrictToWorkingDays(DefaultCalendar(), start)
+
+	if len(h.Counts) != 2 || h.Counts[0] != 2 || h.Counts[1] != 8 {
+		t.Errorf("Expected weekend deliveries credited to Monday [2 8], got %v", h.Counts)
+	}
+	if s := h.StratifiedCounts["Story"]; len(s) != 2 || s[1] != 3 {
+		t.Errorf("Expected stratified counts folded the same way, got %v", s)
+	}
+}
+
+func TestEngine_Calendar(t *testing.T) {
+	friday := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	h := &Histogram{Counts: []int{1, 1, 1, 1, 1}}
+
+	e := NewEngine(h)
+	e.SetSeed(42)
+	e.SetCalendar(DefaultCalendar(), friday)
+	res := e.RunDurationSimulation(10, 100)
+	if res.Percentiles.CoinToss != 14 {
+		t.Errorf("Expected 10 working days from a Friday to take 14 calendar days, got %v", res.Percentiles.CoinToss)
+	}
+
+	cal := DefaultCalendar()
+	_ = cal.AddHolidays([]string{"2026-03-11"})
+	e.SetCalendar(cal, friday)
+	res = e.RunDurationSimulation(10, 100)
+	if res.Percentiles.CoinToss != 17 {
+		t.Errorf("Expected a mid-forecast holiday to push completion over the next weekend (17 days), got %v", res.Percentiles.CoinToss)
+	}
+	if dates := CompletionDates(res.Percentiles, friday); dates["coin_toss"] != "2026-03-23" {
+		t.Errorf("Expected coin-toss completion on 2026-03-23, got %v", dates["coin_toss"])
+	}
+
+	e.SetCalendar(DefaultCalendar(), friday)
+	scope := e.RunScopeSimulation(7, 100)
+	if scope.Percentiles.CoinToss != 5 {
+		t.Errorf("Expected 7 calendar days to hold 5 working days, got %v", scope.Percentiles.CoinToss)
+	}
+}
+
+func TestBucketDates(t *testing.T) {
+	// 2026-03-06 is a Friday: Fri, Sat, Sun, Mon
+	start := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	end := start.AddDate(0, 0, 3)
+
+	if got := BucketDates(nil, start, end); len(got) != 4 {
+		t.Errorf("Expected one date per calendar day without a calendar, got %v", got)
+	}
+	got := BucketDates(DefaultCalendar(), start, end)
+	if len(got) != 2 || !got[0].Equal(start) || !got[1].Equal(end) {
+		t.Errorf("Expected the working days [Fri Mon], got %v", got)
+	}
+}
@@ -0,0 +1,61 @@
+package simulation
+
+import (
+	"math/rand/v2"
+	"time"
+
+	"mcs-mcp/internal/stats"
+)
+
+// CapacityScenario scales sampled throughput to model a change in team size
+// or availability. Throughput is assumed to scale linearly with capacity.
+type CapacityScenario struct {
+	// Factor scales every simulated day (0 = unchanged).
+	Factor float64
+	// ChangeFactor additionally scales the days from ChangeDate on (0 = no change).
+	ChangeFactor float64
+	ChangeDate   time.Time
+}
+
+// SetCapacity makes the engine scale sampled throughput by c. changeStep is
+// the first simulated step (day, working day, or sprint; 0-based) that
+// c.ChangeFactor applies to.
+func (e *Engine) SetCapacity(c CapacityScenario, changeStep int) {
+	e.capacity = &c
+	e.capacityChangeStep = max(changeStep, 0)
+}
+
+// setCapacityFrom applies c to a day-based engine whose forecast starts at
+// start. Call it after SetCalendar so the change date is counted in working days.
+func (e *Engine) setCapacityFrom(c *CapacityScenario, start time.Time) {
+	if c == nil {
+		return
+	}
+	changeStep := 0
+	if !c.ChangeDate.IsZero() {
+		changeStep = e.workingDays(max(stats.CalendarDaysBetween(start, c.ChangeDate), 0))
+	}
+	e.SetCapacity(*c, changeStep)
+}
+
+// scaleThroughput scales the deliveries of simulated step (0-based). The
+// fraction of the scaled count is rounded stochastically, so the expected
+// throughput scales exactly.
+func (e *Engine) scaleThroughput(step, count int, rng *rand.Rand) int {
+	if e.capacity == nil || count == 0 {
+		return count
+	}
+	factor := 1.0
+	if e.capacity.Factor > 0 {
+		factor = e.capacity.Factor
+	}
+	if e.capacity.ChangeFactor > 0 && step >= e.capacityChangeStep {
+		factor *= e.capacity.ChangeFactor
+	}
+	scaled := float64(count) * factor
+	n := int(scaled)
+	if rng.Float64() < scaled-float64(n) {
+		n++
+	}
+	return n
+}
@@ -19,6 +19,26 @@ const (
 	CapacityTaxRate = 0.5
 )
 
+// Interference analysis — reports how much one stream's deliveries crowd out another's.
+const (
+	// InterferenceMinItems is the minimum number of delivered items a stream needs in the window
+	// to be correlated with the others.
+	InterferenceMinItems = 10
+	// InterferenceMinDays is the minimum number of delivery days (days with at least one
+	// delivered item) a correlation is computed on.
+	InterferenceMinDays = 20
+	// InterferenceMinEffect is the absolute correlation below which a pair is negligible and
+	// not reported (Cohen's lower bound of a weak effect).
+	InterferenceMinEffect = 0.1
+	// InterferenceSignificance is the p-value below which a negative correlation is reported
+	// as significant and its forecast impact simulated.
+	InterferenceSignificance = 0.05
+	// InterferenceTrials is the number of bootstrap iterations of an impact simulation.
+	InterferenceTrials = 2000
+	// DefaultInterferenceHorizonDays is the calendar-day horizon of an impact simulation.
+	DefaultInterferenceHorizonDays = 30
+)
+
 // Fat-tail / predictability classification — based on Kanban University heuristics.
 const (
 	// FatTailThreshold is the P98/P50 throughput ratio above which the process is classified as "Unstable".
@@ -28,6 +48,9 @@ const (
 	// SparseFatTailSymbol is returned by CalculateFatTail when the throughput median is zero
 	// (sparse process), acting as a symbolic high-risk indicator.
 	SparseFatTailSymbol = 10.0
+	// SmallSampleSize is the number of items below which percentiles carry limited statistical
+	// significance and are flagged as such.
+	SmallSampleSize = 30
 )
 
 // Simulation trial count — controls Monte Carlo sample size.
@@ -35,6 +58,30 @@ const (
 	// DefaultTrials is the number of Monte Carlo iterations per simulation run.
 	// Higher values improve percentile stability at the cost of latency.
 	DefaultTrials = 10000
+	// FastTrials is the reduced per-checkpoint trial count used by fast backtests.
+	// Percentile noise scales with 1/sqrt(trials), so percentiles are roughly 3x
+	// noisier than with DefaultTrials; good enough for a quick calibration read.
+	FastTrials = 1000
+)
+
+// Walk-forward sampling.
+const (
+	// DefaultCheckpointSampleDays is the sampling window each backtest checkpoint builds its
+	// histogram from, aligned with the default window of single-run forecasts.
+	DefaultCheckpointSampleDays = 90
+	// MaxSweepWindows caps the number of sampling windows one window sweep compares; each
+	// window is a full backtest.
+	MaxSweepWindows = 6
+)
+
+// Ensemble forecasts — how far the throughput and cycle-time models may disagree.
+const (
+	// EnsembleModerateDisagreement is the relative P85 gap between the two models from which
+	// the choice of model matters (15%).
+	EnsembleModerateDisagreement = 0.15
+	// EnsembleHighDisagreement is the relative P85 gap from which at least one model misreads
+	// the system and neither forecast should be committed to alone (35%).
+	EnsembleHighDisagreement = 0.35
 )
 
 // Forecast safeguards — prevent infinite loops and degenerate results.
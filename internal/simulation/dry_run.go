@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+)
+
+// InputPreview describes what a forecast would simulate, without running any
+// trials: the items per type and the throughput sample they would be drawn
+// against.
+type InputPreview struct {
+	Mode                string           `json:"mode"`
+	Targets             map[string]int   `json:"targets,omitempty"`     // duration: items per type to complete
+	TotalItems          int              `json:"total_items"`           // duration: sum of targets
+	TargetDays          int              `json:"target_days,omitempty"` // scope: horizon in days
+	UnforecastableTypes []string         `json:"unforecastable_types,omitempty"`
+	NaiveDays           float64          `json:"naive_days,omitempty"` // duration: total_items / mean_per_day
+	Throughput          HistogramSummary `json:"throughput"`
+	Warnings            []string         `json:"-"`
+}
+
+// PreviewInputs summarizes the baseline histogram of req and reports it with
+// the targets, flagging inputs that make the forecast infinite or meaningless. No trials are run. Engines that refine
+// the sample (bbak's adaptive window) may narrow it further.
+func PreviewInputs(req ForecastRequest) InputPreview {
+	req = req.withTypeAliases()
+	tp := BaselineHistogram(req).Summary()
+
+	p := InputPreview{Mode: req.Mode, TargetDays: req.TargetDays, Throughput: tp}
+	if tp.Delivered == 0 {
+		p.Warnings = append(p.Warnings, fmt.Sprintf("NO THROUGHPUT: none of the %d finished items was delivered inside the sampling window, so a duration forecast would be infinite and a scope forecast zero. Widen the window or check the issue_types and resolution filters.", tp.IssuesTotal))
+	}
+
+	switch req.Mode {
+	case "duration":
+		dist := tp.TypeDistribution
+		if len(req.MixOverrides) > 0 {
+			dist = req.MixOverrides
+		}
+		p.Targets = req.Targets
+		for t, c := range req.Targets {
+			p.TotalItems += c
+			if dist[t] <= 0 && c > 0 {
+				p.UnforecastableTypes = append(p.UnforecastableTypes, t)
+			}
+		}
+		slices.Sort(p.UnforecastableTypes)
+		if p.TotalItems == 0 {
+			p.Warnings = append(p.Warnings, "NOTHING TO FORECAST: the scope is empty. Set include_existing_backlog and include_wip, additional_items, or targets.")
+		}
+		if len(p.UnforecastableTypes) > 0 {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("The types [%s] have no delivery history in the sample and would be excluded from the forecast.", strings.Join(p.UnforecastableTypes, ", ")))
+		}
+		if tp.MeanPerDay > 0 {
+			p.NaiveDays = math.Round(float64(p.TotalItems)/tp.MeanPerDay*10) / 10
+			if p.NaiveDays >= MaxForecastDays {
+				p.Warnings = append(p.Warnings, fmt.Sprintf("At %.3f items per day, %d items take about %.0f days, beyond the %d-day simulation limit.", tp.MeanPerDay, p.TotalItems, p.NaiveDays, MaxForecastDays))
+			}
+		}
+	case "scope":
+		if req.TargetDays <= 0 {
+			p.Warnings = append(p.Warnings, "NO HORIZON: target_days (or target_date) is missing or in the past, so a scope forecast would deliver nothing.")
+		}
+	}
+	return p
+}
@@ -0,0 +1,43 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestPreviewInputs(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	var finished []jira.Issue
+	for day := 0; day < 10; day += 2 {
+		resolved := start.AddDate(0, 0, day).Add(10 * time.Hour)
+		finished = append(finished, jira.Issue{IssueType: "Story", Outcome: "delivered", ResolutionDate: &resolved})
+	}
+	req := ForecastRequest{
+		Mode:        "duration",
+		Finished:    finished,
+		WindowStart: start,
+		WindowEnd:   start.AddDate(0, 0, 9),
+		Targets:     map[string]int{"Story": 4, "Bug": 1},
+	}
+
+	p := PreviewInputs(req)
+	if p.Throughput.Days != 10 || p.Throughput.Delivered != 5 || p.Throughput.ZeroDays != 5 || p.Throughput.MeanPerDay != 0.5 {
+		t.Errorf("Unexpected throughput sample %+v", p.Throughput)
+	}
+	if p.TotalItems != 5 || p.NaiveDays != 10 {
+		t.Errorf("Expected 5 items over a naive 10 days, got %d items over %v days", p.TotalItems, p.NaiveDays)
+	}
+	if len(p.UnforecastableTypes) != 1 || p.UnforecastableTypes[0] != "Bug" {
+		t.Errorf("Expected Bug to be unforecastable, got %v", p.UnforecastableTypes)
+	}
+
+	req.WindowStart = start.AddDate(0, 1, 0)
+	req.WindowEnd = start.AddDate(0, 2, 0)
+	p = PreviewInputs(req)
+	if p.Throughput.Delivered != 0 || len(p.Warnings) == 0 || !strings.HasPrefix(p.Warnings[0], "NO THROUGHPUT") {
+		t.Errorf("Expected a zero-throughput warning, got %v", p.Warnings)
+	}
+}
@@ -5,6 +5,7 @@
 package simulation
 
 import (
+	"context"
 	"fmt"
 	"math"
 	"math/rand/v2"
@@ -28,8 +29,18 @@ type ThroughputTrend struct {
 
 // Engine performs the Monte-Carlo simulation.
 type Engine struct {
-	histogram *Histogram
-	rng       *rand.Rand
+	histogram     *Histogram
+	rng           *rand.Rand
+	calendar      *Calendar // nil = every calendar day is a working day
+	calendarStart time.Time
+	ctx           context.Context // nil = not cancellable
+
+	capacity           *CapacityScenario // nil = throughput as sampled
+	capacityChangeStep int
+
+	burnUpDays   []int // Calendar-day checkpoints recorded by RunScopeSimulation
+	timeboxItems int   // Committed items RunScopeSimulation checks the horizon against
+	deadlineDays int   // Calendar days to the target date duration runs are checked against
 }
 
 // Percentiles holds the probabilistic outcomes of a simulation.
@@ -147,8 +158,17 @@ type Result struct {
 	ModelingInsight          string                    `json:"modeling_insight,omitempty"`
 	VolatilityAttribution    map[string]string         `json:"volatility_attribution,omitempty"`
 	TypeSLEs                 map[string]Percentiles    `json:"type_sles,omitempty"`
+	TypeBreakdown            []TypeCycleTime           `json:"type_breakdown,omitempty"`  // Per-type percentile table (see CycleTimeByType)
+	GroupBy                  string                    `json:"group_by,omitempty"`        // Dimension of GroupBreakdown (assignee, team, or component)
+	GroupBreakdown           []GroupCycleTime          `json:"group_breakdown,omitempty"` // Per-group percentile table (see CycleTimeByGroup)
 	Scatterplot              []stats.ScatterPoint      `json:"scatterplot,omitempty"`
 	SLEAdherence             *stats.SLEAdherenceResult `json:"sle_adherence,omitempty"`
+	WithArrivals             *ArrivalForecast          `json:"with_arrivals,omitempty"` // Duration forecast of scope + projected arrivals
+	BurnUp                   []BurnUpPoint             `json:"burn_up,omitempty"`       // Cumulative scope percentiles per checkpoint (see SetBurnUpCheckpoints)
+	Timebox                  *TimeboxOutcome           `json:"timebox,omitempty"`       // Whether committed items fit the horizon (see SetTimeboxCommitment)
+	Deadline                 *DeadlineOutcome          `json:"deadline,omitempty"`      // Whether the scope is done by a target date (see SetDeadline)
+	Explanation              *ForecastExplanation      `json:"explain,omitempty"`       // Drivers of the forecast (see ExplainForecast)
+	Ensemble                 *EnsembleForecast         `json:"ensemble,omitempty"`      // Cycle-time model of an ensemble forecast (see RunCycleTimeForecast)
 }
 
 // Round rounds all numeric fields to 2 decimal places for output compactness.
@@ -159,10 +179,28 @@ func (r *Result) Round() {
 	r.FatTailRatio = stats.Round2(r.FatTailRatio)
 	r.TailToMedianRatio = stats.Round2(r.TailToMedianRatio)
 	r.ThroughputTrend.PercentageChange = stats.Round2(r.ThroughputTrend.PercentageChange)
+	if r.WithArrivals != nil {
+		r.WithArrivals.Round()
+	}
+	if r.Ensemble != nil {
+		r.Ensemble.Round()
+	}
+	if r.Timebox != nil {
+		r.Timebox.Probability = stats.Round2(r.Timebox.Probability)
+	}
+	if r.Deadline != nil {
+		r.Deadline.Probability = stats.Round2(r.Deadline.Probability)
+	}
 	for k, p := range r.TypeSLEs {
 		p.Round()
 		r.TypeSLEs[k] = p
 	}
+	for i := range r.TypeBreakdown {
+		r.TypeBreakdown[i].Percentiles.Round()
+	}
+	for i := range r.GroupBreakdown {
+		r.GroupBreakdown[i].Percentiles.Round()
+	}
 	if decisions, ok := r.Context["stratification_decisions"].([]StratificationDecision); ok {
 		for i := range decisions {
 			decisions[i].Round()
@@ -193,6 +231,28 @@ func NewEngine(h *Histogram) *Engine {
 func (e *Engine) SetSeed(seed int64) {
 	e.rng = rand.New(rand.NewPCG(uint64(seed), 0))
 }
+
+// cancelCheckInterval is the number of trials a worker runs between checks of the context.
+const cancelCheckInterval = 1000
+
+// SetContext makes the simulation workers stop early once ctx is cancelled.
+// A cancelled run returns an empty Result; check Err afterwards.
+func (e *Engine) SetContext(ctx context.Context) {
+	e.ctx = ctx
+}
+
+// Err returns the error of the engine's context, nil if not cancelled.
+func (e *Engine) Err() error {
+	if e.ctx == nil {
+		return nil
+	}
+	return e.ctx.Err()
+}
+
+// stopped reports whether a worker at trial i should give up.
+func (e *Engine) stopped(i int) bool {
+	return i%cancelCheckInterval == 0 && e.Err() != nil
+}
 func getPercentileLabels(mode string) map[string]string {
 	labels := make(map[string]string)
 	switch mode {
@@ -227,27 +287,37 @@ func getPercentileLabels(mode string) map[string]string {
 	return labels
 }
 
-func (e *Engine) assessPredictability(res *Result) {
-	if res.Percentiles.CoinToss > 0 {
-		res.FatTailRatio = math.Round(res.Percentiles.AlmostCertain/res.Percentiles.CoinToss*100) / 100
-		res.TailToMedianRatio = math.Round(res.Percentiles.Likely/res.Percentiles.CoinToss*100) / 100
-
-		predictability := "Stable"
-		if res.FatTailRatio >= FatTailThreshold {
-			predictability = "Unstable"
-			res.Insights = append(res.Insights, fmt.Sprintf("Fat-Tail Warning (Ratio %.2f): Extreme outliers are in control of this process (Kanban heuristic >= 5.6). Your forecasts are high-risk.", res.FatTailRatio))
-		}
-		if res.TailToMedianRatio > HeavyTailThreshold {
-			if predictability == "Stable" {
-				predictability = "Highly Volatile"
-			} else {
-				predictability = "Unstable & Volatile"
-			}
-			res.Insights = append(res.Insights, fmt.Sprintf("Heavy-Tail Warning (Ratio %.2f): The process is highly volatile, indicating a significant risk of extreme delay (Volatility heuristic > 3).", res.TailToMedianRatio))
+// classifyTails returns the Fat-Tail Ratio (P98/P50), the Tail-to-Median Ratio
+// (P85/P50), and the predictability class they imply. Without a median both
+// ratios are 0 and the class is "Unknown".
+func classifyTails(p Percentiles) (fatTail, tailToMedian float64, predictability string) {
+	if p.CoinToss <= 0 {
+		return 0, 0, "Unknown"
+	}
+	fatTail = math.Round(p.AlmostCertain/p.CoinToss*100) / 100
+	tailToMedian = math.Round(p.Likely/p.CoinToss*100) / 100
+
+	predictability = "Stable"
+	if fatTail >= FatTailThreshold {
+		predictability = "Unstable"
+	}
+	if tailToMedian > HeavyTailThreshold {
+		if predictability == "Stable" {
+			predictability = "Highly Volatile"
+		} else {
+			predictability = "Unstable & Volatile"
 		}
-		res.Predictability = predictability
-	} else {
-		res.Predictability = "Unknown"
+	}
+	return fatTail, tailToMedian, predictability
+}
+
+func (e *Engine) assessPredictability(res *Result) {
+	res.FatTailRatio, res.TailToMedianRatio, res.Predictability = classifyTails(res.Percentiles)
+	if res.FatTailRatio >= FatTailThreshold {
+		res.Insights = append(res.Insights, fmt.Sprintf("Fat-Tail Warning (Ratio %.2f): Extreme outliers are in control of this process (Kanban heuristic >= 5.6). Your forecasts are high-risk.", res.FatTailRatio))
+	}
+	if res.TailToMedianRatio > HeavyTailThreshold {
+		res.Insights = append(res.Insights, fmt.Sprintf("Heavy-Tail Warning (Ratio %.2f): The process is highly volatile, indicating a significant risk of extreme delay (Volatility heuristic > 3).", res.TailToMedianRatio))
 	}
 
 	if e.histogram == nil || e.histogram.Meta == nil {
@@ -277,7 +347,7 @@ func (e *Engine) assessPredictability(res *Result) {
 		}
 	}
 
-	if analyzed, ok := e.histogram.Meta["issues_analyzed"].(int); ok && analyzed < 30 {
+	if analyzed, ok := e.histogram.Meta["issues_analyzed"].(int); ok && analyzed < SmallSampleSize {
 		res.Warnings = append(res.Warnings, fmt.Sprintf("Simulation based on a small sample size (%d items); results may have limited statistical significance.", analyzed))
 	}
 }
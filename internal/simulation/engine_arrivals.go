@@ -0,0 +1,131 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+// ArrivalForecast is a duration forecast against a moving target: the backlog
+// plus the items that keep arriving at the historical rate while it is worked off.
+type ArrivalForecast struct {
+	Percentiles       Percentiles       `json:"percentiles"`
+	CompletionDates   map[string]string `json:"completion_dates,omitempty"`
+	ArrivalRate       float64           `json:"arrival_rate"`       // Mean new items per sampled day
+	ThroughputRate    float64           `json:"throughput_rate"`    // Mean deliveries per sampled day
+	ArrivalsPredicted int               `json:"arrivals_predicted"` // Median items arriving before completion
+}
+
+// Round rounds all fields to 2 decimal places for output compactness.
+func (a *ArrivalForecast) Round() {
+	a.Percentiles.Round()
+	roundFields(&a.ArrivalRate, &a.ThroughputRate)
+}
+
+// NewArrivalHistogram counts the issues created on each day between startTime
+// and endTime, in the same day buckets NewHistogram uses for deliveries.
+func NewArrivalHistogram(issues []jira.Issue, startTime, endTime time.Time) *Histogram {
+	days := stats.CalendarDaysBetween(startTime, endTime) + 1
+	if days <= 0 {
+		return &Histogram{Counts: []int{0}, StratifiedCounts: make(map[string][]int)}
+	}
+	buckets := make([]int, days)
+	total := 0
+	for _, issue := range issues {
+		if issue.Created.IsZero() {
+			continue
+		}
+		if dayIdx := stats.CalendarDaysBetween(startTime, issue.Created); dayIdx >= 0 && dayIdx < days {
+			buckets[dayIdx]++
+			total++
+		}
+	}
+	return &Histogram{
+		Counts:           buckets,
+		StratifiedCounts: make(map[string][]int),
+		Meta:             map[string]any{"arrivals_total": total, "days_in_sample": days},
+	}
+}
+
+// RunArrivalDurationSimulation forecasts how long a pooled backlog takes to
+// drain when each simulated day also adds a number of new items sampled from
+// arrivals. Types are not tracked: arrivals and throughput are both pooled.
+func (e *Engine) RunArrivalDurationSimulation(backlogSize int, arrivals *Histogram, trials int) ArrivalForecast {
+	if e.histogram == nil || len(e.histogram.Counts) == 0 || arrivals == nil || len(arrivals.Counts) == 0 {
+		return ArrivalForecast{}
+	}
+
+	durations := make([]int, trials)
+	arrived := make([]int, trials)
+	for i := range trials {
+		if e.stopped(i) {
+			return ArrivalForecast{}
+		}
+		remaining, days := backlogSize, 0
+		sampler := newDaySampler(len(e.histogram.Counts), e.histogram.Block)
+		for remaining > 0 && days < MaxForecastDays {
+			days++
+			remaining -= e.scaleThroughput(days-1, e.histogram.Counts[sampler.draw(e.rng)], e.rng)
+			if remaining <= 0 {
+				break
+			}
+			a := arrivals.Counts[e.rng.IntN(len(arrivals.Counts))]
+			remaining += a
+			arrived[i] += a
+		}
+		durations[i] = days
+	}
+
+	slices.Sort(durations)
+	e.toCalendarDays(durations)
+	slices.Sort(arrived)
+	return ArrivalForecast{
+		Percentiles:       percentilesFromSorted(intsToFloat64(durations)),
+		ArrivalRate:       mean(arrivals.Counts),
+		ThroughputRate:    mean(e.histogram.Counts),
+		ArrivalsPredicted: arrived[trials/2],
+	}
+}
+
+// applyArrivals adds the moving-target forecast to a duration result when the
+// request models arrivals. Arrivals are sampled over the same window and
+// working calendar as throughput.
+func (e *Engine) applyArrivals(res *Result, req ForecastRequest, windowStart, windowEnd time.Time) {
+	if !req.ModelArrivals || req.Mode != "duration" {
+		return
+	}
+	backlog := 0
+	for _, c := range req.Targets {
+		backlog += c
+	}
+	arrivals := NewArrivalHistogram(req.AllIssues, windowStart, windowEnd)
+	arrivals.RestrictToWorkingDays(req.Calendar, windowStart)
+
+	forecast := e.RunArrivalDurationSimulation(backlog, arrivals, DefaultTrials)
+	if e.Err() != nil || forecast.Percentiles == (Percentiles{}) {
+		return
+	}
+	res.WithArrivals = &forecast
+	res.Insights = append(res.Insights, fmt.Sprintf(
+		"Moving target: %.2f items arrive per day against %.2f delivered. With projected arrivals, P85 moves from %.0f to %.0f days (about %d new items arrive before completion at P50).",
+		forecast.ArrivalRate, forecast.ThroughputRate, res.Percentiles.Likely, forecast.Percentiles.Likely, forecast.ArrivalsPredicted))
+	if forecast.Percentiles.CoinToss >= MaxForecastDays || forecast.ArrivalRate >= forecast.ThroughputRate {
+		res.Warnings = append(res.Warnings, fmt.Sprintf(
+			"WARNING: Items arrive (%.2f/day) at least as fast as they are delivered (%.2f/day). With arrivals the backlog does not drain reliably; the moving-target percentiles are capped at %d days.",
+			forecast.ArrivalRate, forecast.ThroughputRate, MaxForecastDays))
+	}
+}
+
+func mean(counts []int) float64 {
+	if len(counts) == 0 {
+		return 0
+	}
+	total := 0
+	for _, c := range counts {
+		total += c
+	}
+	return float64(total) / float64(len(counts))
+}
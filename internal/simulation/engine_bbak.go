@@ -1,6 +1,7 @@
 package simulation
 
 import (
+	"context"
 	"fmt"
 	"math/rand/v2"
 	"time"
@@ -20,7 +21,8 @@ type BbakEngine struct{}
 
 func (b *BbakEngine) Name() string { return "bbak" }
 
-func (b *BbakEngine) Run(req ForecastRequest) (Result, error) {
+func (b *BbakEngine) Run(ctx context.Context, req ForecastRequest) (Result, error) {
+	req = req.withTypeAliases()
 	finished := req.Finished
 	windowStart := req.WindowStart
 	windowEnd := req.WindowEnd
@@ -60,7 +62,9 @@ func (b *BbakEngine) Run(req ForecastRequest) (Result, error) {
 	}
 
 	// Build histogram from (possibly refined) inputs
-	h := NewHistogram(finished, windowStart, windowEnd, req.IssueTypes, req.WorkflowMappings, req.Resolutions)
+	h := NewHistogram(finished, windowStart, windowEnd, req.IssueTypes, req.WorkflowMappings, req.Resolutions, req.Outcomes)
+	h.Stratify(req.Stratification, req.MinStratumSize)
+	h.RestrictToWorkingDays(req.Calendar, h.MergeExternal(windowStart, req.External))
 
 	// Post-histogram WIP/aging resampling
 	if spaDiagnostics != nil {
@@ -79,10 +83,20 @@ func (b *BbakEngine) Run(req ForecastRequest) (Result, error) {
 		}
 	}
 
+	h.TrimOutliers(req.Outliers)
+	h.SetGranularity(req.Granularity, req.Calendar)
+	samplingWarning := ApplySampling(h, req.Sampling, req.SimulationSeed)
+
 	engine := NewEngine(h)
+	engine.SetContext(ctx)
 	if req.SimulationSeed != 0 {
 		engine.SetSeed(req.SimulationSeed)
 	}
+	if req.Calendar != nil {
+		engine.SetCalendar(req.Calendar, req.Clock)
+	}
+	engine.setCapacityFrom(req.Capacity, req.Clock)
+	engine.SetDeadline(req.DeadlineDays)
 
 	// Resolve distribution
 	var dist map[string]float64
@@ -101,6 +115,14 @@ func (b *BbakEngine) Run(req ForecastRequest) (Result, error) {
 	default:
 		return Result{}, fmt.Errorf("unknown simulation mode: %q", req.Mode)
 	}
+	engine.applyArrivals(&res, req, windowStart, windowEnd)
+	if err := engine.Err(); err != nil {
+		return Result{}, fmt.Errorf("simulation cancelled: %w", err)
+	}
+	if samplingWarning != "" {
+		res.Warnings = append(res.Warnings, samplingWarning)
+	}
+	annotateExternal(&res, h)
 
 	// Attach SPA diagnostics to result context
 	if spaDiagnostics != nil {
@@ -1,6 +1,9 @@
 package simulation
 
-import "fmt"
+import (
+	"context"
+	"fmt"
+)
 
 // CrudeEngine is the baseline Monte Carlo simulation engine.
 // It builds a throughput histogram from all delivered items in a fixed window
@@ -9,13 +12,21 @@ type CrudeEngine struct{}
 
 func (c *CrudeEngine) Name() string { return "crude" }
 
-func (c *CrudeEngine) Run(req ForecastRequest) (Result, error) {
-	h := NewHistogram(req.Finished, req.WindowStart, req.WindowEnd, req.IssueTypes, req.WorkflowMappings, req.Resolutions)
+func (c *CrudeEngine) Run(ctx context.Context, req ForecastRequest) (Result, error) {
+	req = req.withTypeAliases()
+	h := BaselineHistogram(req)
+	samplingWarning := ApplySampling(h, req.Sampling, req.SimulationSeed)
 
 	engine := NewEngine(h)
+	engine.SetContext(ctx)
 	if req.SimulationSeed != 0 {
 		engine.SetSeed(req.SimulationSeed)
 	}
+	if req.Calendar != nil {
+		engine.SetCalendar(req.Calendar, req.Clock)
+	}
+	engine.setCapacityFrom(req.Capacity, req.Clock)
+	engine.SetDeadline(req.DeadlineDays)
 
 	// Resolve distribution: explicit overrides → histogram meta
 	var dist map[string]float64
@@ -25,12 +36,22 @@ func (c *CrudeEngine) Run(req ForecastRequest) (Result, error) {
 		dist = histDist
 	}
 
+	var res Result
 	switch req.Mode {
 	case "scope":
-		return engine.RunMultiTypeScopeSimulation(req.TargetDays, DefaultTrials, req.IssueTypes, dist, true), nil
+		res = engine.RunMultiTypeScopeSimulation(req.TargetDays, DefaultTrials, req.IssueTypes, dist, true)
 	case "duration":
-		return engine.RunMultiTypeDurationSimulation(req.Targets, dist, DefaultTrials, true), nil
+		res = engine.RunMultiTypeDurationSimulation(req.Targets, dist, DefaultTrials, true)
 	default:
 		return Result{}, fmt.Errorf("unknown simulation mode: %q", req.Mode)
 	}
+	engine.applyArrivals(&res, req, req.WindowStart, req.WindowEnd)
+	if err := engine.Err(); err != nil {
+		return Result{}, fmt.Errorf("simulation cancelled: %w", err)
+	}
+	if samplingWarning != "" {
+		res.Warnings = append(res.Warnings, samplingWarning)
+	}
+	annotateExternal(&res, h)
+	return res, nil
 }
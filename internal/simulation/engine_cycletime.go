@@ -2,6 +2,7 @@ package simulation
 
 import (
 	"slices"
+	"strings"
 )
 
 // RunCycleTimeAnalysis calculates percentiles from a list of historical cycle times (in days).
@@ -39,3 +40,74 @@ func (e *Engine) RunCycleTimeAnalysis(cycleTimes []float64, ctByType map[string]
 
 	return res
 }
+
+// TypeCycleTime is the cycle time assessment of one issue type.
+type TypeCycleTime struct {
+	IssueType         string      `json:"issue_type"`
+	Count             int         `json:"count"`
+	Percentiles       Percentiles `json:"percentiles"`
+	FatTailRatio      float64     `json:"fat_tail_ratio"`       // P98/P50
+	TailToMedianRatio float64     `json:"tail_to_median_ratio"` // P85/P50
+	Predictability    string      `json:"predictability"`
+	SmallSample       bool        `json:"small_sample"` // Fewer than SmallSampleSize items
+}
+
+// CycleTimeByType assesses the cycle times of each issue type on its own:
+// percentiles, sample size, and the tail ratios that classify its
+// predictability. Types are ordered by sample size, largest first.
+func CycleTimeByType(ctByType map[string][]float64) []TypeCycleTime {
+	var table []TypeCycleTime
+	for t, cts := range ctByType {
+		if len(cts) == 0 {
+			continue
+		}
+		sorted := slices.Clone(cts)
+		slices.Sort(sorted)
+		row := TypeCycleTime{
+			IssueType:   t,
+			Count:       len(sorted),
+			Percentiles: percentilesFromSorted(sorted),
+			SmallSample: len(sorted) < SmallSampleSize,
+		}
+		row.FatTailRatio, row.TailToMedianRatio, row.Predictability = classifyTails(row.Percentiles)
+		table = append(table, row)
+	}
+	slices.SortFunc(table, func(a, b TypeCycleTime) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return strings.Compare(a.IssueType, b.IssueType)
+	})
+	return table
+}
+
+// GroupCycleTime is the cycle time assessment of one group of items, such as
+// the items of one assignee, team, or component.
+type GroupCycleTime struct {
+	Group             string      `json:"group"`
+	Count             int         `json:"count"`
+	Percentiles       Percentiles `json:"percentiles"`
+	FatTailRatio      float64     `json:"fat_tail_ratio"`       // P98/P50
+	TailToMedianRatio float64     `json:"tail_to_median_ratio"` // P85/P50
+	Predictability    string      `json:"predictability"`
+	SmallSample       bool        `json:"small_sample"` // Fewer than SmallSampleSize items
+}
+
+// CycleTimeByGroup assesses the cycle times of each group on its own, like
+// CycleTimeByType. Groups are ordered by sample size, largest first.
+func CycleTimeByGroup(ctByGroup map[string][]float64) []GroupCycleTime {
+	rows := CycleTimeByType(ctByGroup)
+	table := make([]GroupCycleTime, len(rows))
+	for i, r := range rows {
+		table[i] = GroupCycleTime{
+			Group:             r.IssueType,
+			Count:             r.Count,
+			Percentiles:       r.Percentiles,
+			FatTailRatio:      r.FatTailRatio,
+			TailToMedianRatio: r.TailToMedianRatio,
+			Predictability:    r.Predictability,
+			SmallSample:       r.SmallSample,
+		}
+	}
+	return table
+}
@@ -5,11 +5,29 @@ import (
 	"math"
 	"math/rand/v2"
 	"slices"
+	"sort"
 	"strings"
 
 	"github.com/rs/zerolog/log"
 )
 
+// DeadlineOutcome is whether the scope of a duration forecast is delivered
+// by a target date, and how far the forecast misses it at P85.
+type DeadlineOutcome struct {
+	TargetDays  int     `json:"target_days"`   // Calendar days to the target date
+	Items       int     `json:"items"`         // Items of the forecast scope
+	Probability float64 `json:"probability"`   // Fraction of trials finishing by the target date
+	GapDaysP85  int     `json:"gap_days_p85"`  // Days the P85 completion lies beyond the target date
+	GapItemsP85 int     `json:"gap_items_p85"` // Items to drop so the rest is delivered by the target date with 85% probability
+}
+
+// SetDeadline makes RunMultiTypeDurationSimulation also record in
+// Result.Deadline how likely the scope is delivered within the given
+// calendar days, and how far it falls short at P85 (0 = no target date).
+func (e *Engine) SetDeadline(days int) {
+	e.deadlineDays = days
+}
+
 // RunDurationSimulation is a backward-compatible wrapper for simple backlog forecasts.
 // It assumes the backlog follows the historical distribution.
 func (e *Engine) RunDurationSimulation(backlogSize int, trials int) Result {
@@ -148,6 +166,9 @@ func (e *Engine) RunMultiTypeDurationSimulation(targets map[string]int, distribu
 			}
 
 			for i := range count {
+				if e.stopped(i) {
+					break
+				}
 				var duration int
 				var bg map[string]int
 				if useStratification {
@@ -179,7 +200,11 @@ func (e *Engine) RunMultiTypeDurationSimulation(targets map[string]int, distribu
 		}
 	}
 
+	if e.Err() != nil {
+		return Result{}
+	}
 	slices.Sort(durations)
+	e.toCalendarDays(durations)
 
 	// Calculate median background items
 	medianBG := make(map[string]int)
@@ -214,6 +239,9 @@ func (e *Engine) RunMultiTypeDurationSimulation(targets map[string]int, distribu
 	}
 
 	e.assessPredictability(&res)
+	if e.deadlineDays > 0 {
+		res.Deadline = e.deadlineOutcome(durations, targets, distribution, trials, expansionEnabled, res.Percentiles.Likely)
+	}
 
 	// Check for infinite duration / zero throughput
 	isInfinite := true
@@ -259,6 +287,31 @@ func (e *Engine) RunMultiTypeDurationSimulation(targets map[string]int, distribu
 	return res
 }
 
+// deadlineOutcome checks the sorted calendar-day durations of a run against
+// the deadline. The items short at P85 come from a scope simulation of the
+// targeted types over the days to the deadline, as in a timebox.
+func (e *Engine) deadlineOutcome(durations []int, targets map[string]int, distribution map[string]float64, trials int, expansionEnabled bool, p85 float64) *DeadlineOutcome {
+	out := &DeadlineOutcome{
+		TargetDays: e.deadlineDays,
+		GapDaysP85: max(0, int(math.Ceil(p85))-e.deadlineDays),
+	}
+	if len(durations) > 0 {
+		out.Probability = float64(sort.SearchInts(durations, e.deadlineDays+1)) / float64(len(durations))
+	}
+	var types []string
+	for t, n := range targets {
+		if n > 0 {
+			out.Items += n
+			types = append(types, t)
+		}
+	}
+	slices.Sort(types)
+	if out.GapDaysP85 > 0 {
+		scope := e.RunMultiTypeScopeSimulation(e.deadlineDays, trials, types, distribution, expansionEnabled)
+		out.GapItemsP85 = max(0, out.Items-int(scope.Percentiles.Likely))
+	}
+	return out
+}
 
 func (e *Engine) simulateDurationTrialStratified(targets map[string]int, capacityCap int, rng *rand.Rand) (int, map[string]int) {
 	days := 0
@@ -279,6 +332,10 @@ func (e *Engine) simulateDurationTrialStratified(targets map[string]int, capacit
 		types = append(types, t)
 	}
 	slices.Sort(types)
+	samplers := make(map[string]*daySampler, len(types))
+	for _, t := range types {
+		samplers[t] = newDaySampler(len(e.histogram.StratifiedCounts[t]), e.histogram.Block)
+	}
 
 	taxers := make([]string, 0, len(deps))
 	for taxer := range deps {
@@ -305,7 +362,7 @@ func (e *Engine) simulateDurationTrialStratified(targets map[string]int, capacit
 					}
 				}
 			} else {
-				idx := rng.IntN(len(counts))
+				idx := samplers[t].draw(rng)
 				h = counts[idx]
 			}
 
@@ -367,7 +424,7 @@ func (e *Engine) simulateDurationTrialStratified(targets map[string]int, capacit
 
 		// 3. Consume Targets
 		for _, t := range types {
-			h := sampled[t]
+			h := e.scaleThroughput(days-1, sampled[t], rng)
 			if count, ok := remaining[t]; ok && count > 0 {
 				delivered := min(h, count)
 				remaining[t] -= delivered
@@ -423,10 +480,11 @@ func (e *Engine) simulateDurationTrialWithTypeMixLocal(targets map[string]int, d
 		return 3650, background
 	}
 
+	sampler := newDaySampler(len(e.histogram.Counts), e.histogram.Block)
 	for totalRemaining > 0 {
 		days++
-		idx := rng.IntN(len(e.histogram.Counts))
-		slots := e.histogram.Counts[idx]
+		idx := sampler.draw(rng)
+		slots := e.scaleThroughput(days-1, e.histogram.Counts[idx], rng)
 
 		for range slots {
 			r := rng.Float64()
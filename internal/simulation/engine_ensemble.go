@@ -0,0 +1,110 @@
+package simulation
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// EnsembleForecast is the item-level cycle-time model of an ensemble
+// duration forecast, next to the throughput model of the same request.
+type EnsembleForecast struct {
+	Percentiles       Percentiles `json:"percentiles"`        // Calendar days until the last item is done
+	Parallelism       int         `json:"parallelism"`        // Items worked at once
+	ParallelismSource string      `json:"parallelism_source"` // "wip_limit", "tier_limit", "current_wip", or "littles_law"
+	CycleTimeSample   int         `json:"cycle_time_sample"`  // Delivered items the cycle times are drawn from
+	Disagreement      float64     `json:"disagreement"`       // |P85 gap| / larger P85 of the two models
+	ModelRisk         string      `json:"model_risk"`         // "low", "moderate", or "high"
+}
+
+// Round rounds all fields to 2 decimal places for output compactness.
+func (f *EnsembleForecast) Round() {
+	f.Percentiles.Round()
+	roundFields(&f.Disagreement)
+}
+
+// RunCycleTimeForecast forecasts the calendar days until backlog new items
+// and the in-progress items with the given WIP ages are done, from the
+// historical cycle times alone. Each trial gives every item a sampled cycle
+// time and works at most parallelism items at once: in-progress items finish
+// the rest of a cycle time longer than their age, and each backlog item starts
+// when a slot frees up. warnings names in-progress items older than every
+// historical cycle time, whose rest cannot be sampled and counts as zero.
+func (e *Engine) RunCycleTimeForecast(cycleTimes, wipAges []float64, backlog, parallelism, trials int) (Percentiles, []string) {
+	sorted := slices.Sorted(slices.Values(cycleTimes))
+	parallelism = max(parallelism, 1)
+
+	var beyond int
+	for _, age := range wipAges {
+		if age >= sorted[len(sorted)-1] {
+			beyond++
+		}
+	}
+
+	durations := make([]float64, trials)
+	active := &finishHeap{}
+	for i := range trials {
+		if e.stopped(i) {
+			return Percentiles{}, nil
+		}
+		*active = (*active)[:0]
+		last := 0.0
+		for _, age := range wipAges {
+			rest := 0.0
+			if j, _ := slices.BinarySearch(sorted, math.Nextafter(age, math.Inf(1))); j < len(sorted) {
+				rest = sorted[j+e.rng.IntN(len(sorted)-j)] - age
+			}
+			heap.Push(active, rest)
+			last = max(last, rest)
+		}
+		now := 0.0
+		for range backlog {
+			for active.Len() >= parallelism {
+				now = max(now, heap.Pop(active).(float64))
+			}
+			done := now + sorted[e.rng.IntN(len(sorted))]
+			heap.Push(active, done)
+			last = max(last, done)
+		}
+		durations[i] = last
+	}
+	slices.Sort(durations)
+
+	var warnings []string
+	if beyond > 0 {
+		warnings = append(warnings, fmt.Sprintf("%d in-progress item(s) are older than every historical cycle time; the cycle-time model counts their remaining time as zero and is optimistic.", beyond))
+	}
+	return percentilesFromSorted(durations), warnings
+}
+
+// CompareModels returns the disagreement of two duration forecasts — the gap
+// between their P85s relative to the larger one — and its model-risk class.
+func CompareModels(a, b Percentiles) (float64, string) {
+	hi := max(a.Likely, b.Likely)
+	if hi <= 0 {
+		return 0, "low"
+	}
+	d := math.Abs(a.Likely-b.Likely) / hi
+	switch {
+	case d >= EnsembleHighDisagreement:
+		return d, "high"
+	case d >= EnsembleModerateDisagreement:
+		return d, "moderate"
+	}
+	return d, "low"
+}
+
+// finishHeap is a min-heap of the finish times of the items in progress.
+type finishHeap []float64
+
+func (h finishHeap) Len() int           { return len(h) }
+func (h finishHeap) Less(i, j int) bool { return h[i] < h[j] }
+func (h finishHeap) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h *finishHeap) Push(x any)        { *h = append(*h, x.(float64)) }
+func (h *finishHeap) Pop() any {
+	old := *h
+	x := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return x
+}
@@ -0,0 +1,45 @@
+package simulation
+
+import "testing"
+
+func TestRunCycleTimeForecast(t *testing.T) {
+	e := NewEngine(nil)
+	e.SetSeed(42)
+
+	// Constant cycle times make the schedule exact: 6 items, 2 at a time, 5 days each.
+	p, warnings := e.RunCycleTimeForecast([]float64{5}, nil, 6, 2, 100)
+	if p.CoinToss != 15 || p.AlmostCertain != 15 || len(warnings) != 0 {
+		t.Errorf("Expected 3 rounds of 5 days, got %+v (%v)", p, warnings)
+	}
+
+	// In-progress items hold slots for the rest of their cycle time.
+	p, _ = e.RunCycleTimeForecast([]float64{2, 10}, []float64{4}, 1, 1, 100)
+	if p.CoinToss != 8 && p.CoinToss != 16 {
+		t.Errorf("Expected the 4-day-old item to finish after 6 more days, got %+v", p)
+	}
+	if p.Aggressive < 8 {
+		t.Errorf("Expected the backlog item to wait for the in-progress one, got %+v", p)
+	}
+
+	_, warnings = e.RunCycleTimeForecast([]float64{2, 3}, []float64{30}, 1, 1, 10)
+	if len(warnings) != 1 {
+		t.Errorf("Expected a warning for an item older than every cycle time, got %v", warnings)
+	}
+}
+
+func TestCompareModels(t *testing.T) {
+	cases := []struct {
+		a, b float64
+		risk string
+	}{
+		{100, 95, "low"},
+		{100, 80, "moderate"},
+		{40, 100, "high"},
+		{0, 0, "low"},
+	}
+	for _, c := range cases {
+		if _, risk := CompareModels(Percentiles{Likely: c.a}, Percentiles{Likely: c.b}); risk != c.risk {
+			t.Errorf("CompareModels(%v, %v) = %s, expected %s", c.a, c.b, risk, c.risk)
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+)
+
+// StatusStep is a workflow status an in-flight item has yet to leave or pass,
+// with the residence times of delivered items in it.
+type StatusStep struct {
+	Status    string    `json:"status"`
+	Durations []float64 `json:"-"`          // Residence times (days) of delivered items that visited the status
+	VisitRate float64   `json:"visit_rate"` // Share of delivered items that visited the status
+	P50       float64   `json:"p50_days"`
+	P85       float64   `json:"p85_days"`
+}
+
+// NewStatusStep builds a step from the residence times of the delivered items
+// that visited status, out of total delivered items.
+func NewStatusStep(status string, durations []float64, total int) StatusStep {
+	step := StatusStep{Status: status, Durations: slices.Sorted(slices.Values(durations))}
+	if total > 0 {
+		step.VisitRate = float64(len(durations)) / float64(total)
+	}
+	if n := len(step.Durations); n > 0 {
+		step.P50 = step.Durations[percentilesIdx(n, 0.50)]
+		step.P85 = step.Durations[percentilesIdx(n, 0.85)]
+	}
+	return step
+}
+
+// Round rounds all fields to 2 decimal places for output compactness.
+func (s *StatusStep) Round() {
+	roundFields(&s.VisitRate, &s.P50, &s.P85)
+}
+
+// RunItemSimulation forecasts the remaining calendar days of a single
+// in-flight item. Each trial samples how much longer the item stays in its
+// current status, conditioned on the days it has already spent there, then
+// walks the remaining steps, visiting each with its historical visit rate and
+// sampling a residence time for it.
+func (e *Engine) RunItemSimulation(current StatusStep, daysInStatus float64, remaining []StatusStep, trials int) Result {
+	// Residence times of delivered items that stayed longer than the item has so far.
+	var longer []float64
+	if i, _ := slices.BinarySearch(current.Durations, daysInStatus); i < len(current.Durations) {
+		longer = current.Durations[i:]
+	}
+
+	durations := make([]float64, trials)
+	for i := range trials {
+		if e.stopped(i) {
+			return Result{}
+		}
+		days := 0.0
+		if len(longer) > 0 {
+			days = longer[e.rng.IntN(len(longer))] - daysInStatus
+		}
+		for _, step := range remaining {
+			if len(step.Durations) == 0 || e.rng.Float64() >= step.VisitRate {
+				continue
+			}
+			days += step.Durations[e.rng.IntN(len(step.Durations))]
+		}
+		durations[i] = days
+	}
+
+	slices.Sort(durations)
+	res := Result{
+		Percentiles:      percentilesFromSorted(durations),
+		Spread:           spreadFromSorted(durations),
+		PercentileLabels: getPercentileLabels("duration"),
+	}
+	if len(longer) == 0 {
+		res.Warnings = append(res.Warnings, fmt.Sprintf(
+			"The item has been in '%s' for %.1f days, longer than any delivered item stayed there. History cannot tell how much longer it stays; the forecast only covers the remaining statuses and is optimistic.",
+			current.Status, daysInStatus))
+	}
+	e.assessPredictability(&res)
+	return res
+}
@@ -5,15 +5,58 @@ import (
 	"math"
 	"math/rand/v2"
 	"slices"
+	"sort"
 
 	"github.com/rs/zerolog/log"
 )
 
+// BurnUpPoint is the simulated cumulative delivery at one checkpoint of a scope
+// forecast. Like the scope percentiles, P85 is the count delivered at least
+// with 85% probability.
+type BurnUpPoint struct {
+	Day  int     `json:"day"`            // Calendar days from the forecast start
+	Date string  `json:"date,omitempty"` // Set by the caller, which knows the start date
+	P50  float64 `json:"p50"`
+	P85  float64 `json:"p85"`
+	P95  float64 `json:"p95"`
+}
+
+// SetBurnUpCheckpoints makes RunScopeSimulation also record the distribution
+// of cumulative deliveries at each of the given calendar days (ascending) into
+// Result.BurnUp. Checkpoints beyond the simulated horizon are ignored.
+func (e *Engine) SetBurnUpCheckpoints(days []int) {
+	e.burnUpDays = days
+}
+
+// TimeboxOutcome is whether the items committed to a timebox (e.g. the
+// remainder of a sprint) are delivered within the scope horizon.
+type TimeboxOutcome struct {
+	Remaining   int     `json:"remaining"`   // Committed items not finished yet
+	Probability float64 `json:"probability"` // Fraction of trials delivering all of them
+	DescopeP50  int     `json:"descope_p50"` // Items to drop so the rest is delivered with 50% probability
+	DescopeP85  int     `json:"descope_p85"` // Items to drop so the rest is delivered with 85% probability
+}
+
+// SetTimeboxCommitment makes RunScopeSimulation also record in Result.Timebox
+// how likely the horizon delivers the given number of remaining items, and
+// how many to drop to make the commitment at P50 and P85.
+func (e *Engine) SetTimeboxCommitment(remaining int) {
+	e.timeboxItems = remaining
+}
+
 // RunScopeSimulation predicts how many items can be finished within a given number of days.
 func (e *Engine) RunScopeSimulation(days int, trials int) Result {
 	if e.histogram == nil || len(e.histogram.Counts) == 0 {
 		return Result{}
 	}
+	var checkpoints, marks []int
+	for _, d := range e.burnUpDays {
+		if d <= days {
+			checkpoints = append(checkpoints, d)
+			marks = append(marks, e.workingDays(d))
+		}
+	}
+	days = e.workingDays(days)
 
 	// Parallel Execution Setup
 	numGo := 4
@@ -22,26 +65,47 @@ func (e *Engine) RunScopeSimulation(days int, trials int) Result {
 	}
 	trialsPerGo := trials / numGo
 
-	resultsChan := make(chan []int, numGo)
+	type scopeTrialResult struct {
+		scopes     []int
+		cumulative [][]int // checkpoint → trial → items delivered so far
+	}
+	resultsChan := make(chan scopeTrialResult, numGo)
 
 	for g := 0; g < numGo; g++ {
 		workerSeed := e.rng.Uint64()
 		go func(count int, seed uint64) {
 			rng := rand.New(rand.NewPCG(seed, 0))
-			res := make([]int, count)
+			res := scopeTrialResult{scopes: make([]int, count), cumulative: make([][]int, len(marks))}
+			for k := range marks {
+				res.cumulative[k] = make([]int, count)
+			}
+			trial := make([]int, len(marks))
 			for i := range count {
-				res[i] = e.simulateScopeTrialLocal(days, rng)
+				if e.stopped(i) {
+					break
+				}
+				res.scopes[i] = e.simulateScopeTrialLocal(days, marks, trial, rng)
+				for k, c := range trial {
+					res.cumulative[k][i] = c
+				}
 			}
 			resultsChan <- res
 		}(trialsPerGo, workerSeed)
 	}
 
 	scopes := make([]int, 0, trials)
+	cumulative := make([][]int, len(marks))
 	for g := 0; g < numGo; g++ {
 		res := <-resultsChan
-		scopes = append(scopes, res...)
+		scopes = append(scopes, res.scopes...)
+		for k := range marks {
+			cumulative[k] = append(cumulative[k], res.cumulative[k]...)
+		}
 	}
 
+	if e.Err() != nil {
+		return Result{}
+	}
 	slices.Sort(scopes)
 
 	scopesF := intsToFloat64(scopes)
@@ -50,6 +114,20 @@ func (e *Engine) RunScopeSimulation(days int, trials int) Result {
 		Spread:           spreadFromSorted(scopesF),
 		PercentileLabels: getPercentileLabels("scope"),
 	}
+	if e.timeboxItems > 0 && len(scopes) > 0 {
+		hits := len(scopes) - sort.SearchInts(scopes, e.timeboxItems)
+		res.Timebox = &TimeboxOutcome{
+			Remaining:   e.timeboxItems,
+			Probability: float64(hits) / float64(len(scopes)),
+			DescopeP50:  max(0, e.timeboxItems-int(res.Percentiles.CoinToss)),
+			DescopeP85:  max(0, e.timeboxItems-int(res.Percentiles.Likely)),
+		}
+	}
+	for k, day := range checkpoints {
+		slices.Sort(cumulative[k])
+		p := percentilesFromSortedInverted(intsToFloat64(cumulative[k]))
+		res.BurnUp = append(res.BurnUp, BurnUpPoint{Day: day, P50: p.CoinToss, P85: p.Likely, P95: p.Safe})
+	}
 
 	// Window exclusion warning
 	if droppedWindow, ok := e.histogram.Meta["dropped_by_window"].(int); ok && droppedWindow > 0 {
@@ -73,6 +151,7 @@ func (e *Engine) RunMultiTypeScopeSimulation(targetDays int, trials int, filterT
 	if e.histogram == nil || len(e.histogram.Counts) == 0 {
 		return Result{}
 	}
+	targetDays = e.workingDays(targetDays)
 
 	filterMap := make(map[string]bool)
 	for _, t := range filterTypes {
@@ -127,6 +206,9 @@ func (e *Engine) RunMultiTypeScopeSimulation(targetDays int, trials int, filterT
 			}
 
 			for i := range count {
+				if e.stopped(i) {
+					break
+				}
 				var scope int
 				var bg map[string]int
 				if useStratification {
@@ -157,6 +239,9 @@ func (e *Engine) RunMultiTypeScopeSimulation(targetDays int, trials int, filterT
 		}
 	}
 
+	if e.Err() != nil {
+		return Result{}
+	}
 	slices.Sort(scopes)
 
 	// Calculate median background items
@@ -190,6 +275,10 @@ func (e *Engine) simulateScopeTrialStratified(targetDays int, filterMap map[stri
 		types = append(types, t)
 	}
 	slices.Sort(types)
+	samplers := make(map[string]*daySampler, len(types))
+	for _, t := range types {
+		samplers[t] = newDaySampler(len(e.histogram.StratifiedCounts[t]), e.histogram.Block)
+	}
 
 	taxers := make([]string, 0, len(deps))
 	for taxer := range deps {
@@ -197,7 +286,7 @@ func (e *Engine) simulateScopeTrialStratified(targetDays int, filterMap map[stri
 	}
 	slices.Sort(taxers)
 
-	for range targetDays {
+	for day := range targetDays {
 		// 1. Independent Stratified Sampling (with Blending and Dependency Awareness)
 		sampled := make(map[string]int)
 		totalSampled := 0
@@ -213,7 +302,7 @@ func (e *Engine) simulateScopeTrialStratified(targetDays int, filterMap map[stri
 					}
 				}
 			} else {
-				idx := rng.IntN(len(counts))
+				idx := samplers[t].draw(rng)
 				h = counts[idx]
 			}
 			sampled[t] = h
@@ -264,7 +353,7 @@ func (e *Engine) simulateScopeTrialStratified(targetDays int, filterMap map[stri
 
 		// 3. Count Scope
 		for _, t := range types {
-			h := sampled[t]
+			h := e.scaleThroughput(day, sampled[t], rng)
 			if filterMap[t] || len(filterMap) == 0 {
 				totalScope += h
 			} else {
@@ -279,10 +368,11 @@ func (e *Engine) simulateScopeTrialStratified(targetDays int, filterMap map[stri
 func (e *Engine) simulateMultiTypeScopeTrialLocal(targetDays int, filterMap map[string]bool, distribution map[string]float64, rng *rand.Rand) (int, map[string]int) {
 	scope := 0
 	bgItems := make(map[string]int)
+	sampler := newDaySampler(len(e.histogram.Counts), e.histogram.Block)
 
-	for range targetDays {
-		idx := rng.IntN(len(e.histogram.Counts))
-		slots := e.histogram.Counts[idx]
+	for day := range targetDays {
+		idx := sampler.draw(rng)
+		slots := e.scaleThroughput(day, e.histogram.Counts[idx], rng)
 		for range slots {
 			r := rng.Float64()
 			var sampledType string
@@ -319,11 +409,21 @@ func (e *Engine) simulateMultiTypeScopeTrialLocal(targetDays int, filterMap map[
 	return scope, bgItems
 }
 
-func (e *Engine) simulateScopeTrialLocal(targetDays int, rng *rand.Rand) int {
+// simulateScopeTrialLocal runs one scope trial. cumulative[k] receives the items
+// delivered within the first marks[k] working days.
+func (e *Engine) simulateScopeTrialLocal(targetDays int, marks []int, cumulative []int, rng *rand.Rand) int {
 	totalScope := 0
-	for range targetDays {
-		idx := rng.IntN(len(e.histogram.Counts))
-		totalScope += e.histogram.Counts[idx]
+	k := 0
+	for ; k < len(marks) && marks[k] == 0; k++ {
+		cumulative[k] = 0
+	}
+	sampler := newDaySampler(len(e.histogram.Counts), e.histogram.Block)
+	for day := range targetDays {
+		idx := sampler.draw(rng)
+		totalScope += e.scaleThroughput(day, e.histogram.Counts[idx], rng)
+		for ; k < len(marks) && marks[k] == day+1; k++ {
+			cumulative[k] = totalScope
+		}
 	}
 	return totalScope
 }
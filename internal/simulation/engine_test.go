@@ -1,10 +1,16 @@
 package simulation
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math"
 	"mcs-mcp/internal/eventlog"
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
+	"reflect"
+	"slices"
+	"strings"
 	"testing"
 	"time"
 )
@@ -419,7 +425,7 @@ func TestWalkForwardEngine_Execute_Scope(t *testing.T) {
 		ForecastHorizon: 10,
 	}
 
-	res, err := engine.Execute(cfg)
+	res, err := engine.Execute(context.Background(), cfg)
 	if err != nil {
 		t.Fatalf("Execution failed: %v", err)
 	}
@@ -474,7 +480,7 @@ func TestWalkForwardEngine_Driftlimit(t *testing.T) {
 		ForecastHorizon: 30,
 	}
 
-	res, err := engine.Execute(cfg)
+	res, err := engine.Execute(context.Background(), cfg)
 	if err != nil {
 		t.Fatalf("Execution failed: %v", err)
 	}
@@ -523,7 +529,7 @@ func TestWalkForwardEngine_Execute_Duration(t *testing.T) {
 		ItemsToForecast: 10,
 	}
 
-	res, err := engine.Execute(cfg)
+	res, err := engine.Execute(context.Background(), cfg)
 	if err != nil {
 		t.Fatalf("Execution failed: %v", err)
 	}
@@ -545,3 +551,388 @@ func TestWalkForwardEngine_Execute_Duration(t *testing.T) {
 		t.Errorf("Expected Duration Accuracy Score >= 0.7, got %.2f", res.AccuracyScore)
 	}
 }
+
+func TestWalkForwardEngine_Execute_ProgressAndCancel(t *testing.T) {
+	now := time.Now().Truncate(24 * time.Hour)
+	t0 := now.AddDate(0, 0, -200)
+	events := make([]eventlog.IssueEvent, 0)
+	for i := 0; i < 200; i++ {
+		key := fmt.Sprintf("PROJ-%d", i)
+		events = append(events, eventlog.IssueEvent{
+			IssueKey: key, EventType: eventlog.Created, ToStatus: "Open", ToStatusID: "1", Timestamp: t0.AddDate(0, 0, i).UnixMicro(),
+		})
+		events = append(events, eventlog.IssueEvent{
+			IssueKey: key, EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Done", Resolution: "Fixed", Timestamp: t0.AddDate(0, 0, i+1).UnixMicro(),
+		})
+	}
+	mappings := map[string]stats.StatusMetadata{
+		"Done": {Tier: "Finished", Outcome: "delivered"},
+	}
+
+	t.Run("progress and fast trials", func(t *testing.T) {
+		engine := NewWalkForwardEngine(events, mappings, nil)
+		var calls [][2]int
+		cfg := WalkForwardConfig{
+			SimulationMode:  "scope",
+			LookbackWindow:  40,
+			StepSize:        10,
+			ForecastHorizon: 10,
+			Trials:          FastTrials,
+			OnCheckpoint:    func(done, total int) { calls = append(calls, [2]int{done, total}) },
+		}
+		res, err := engine.Execute(context.Background(), cfg)
+		if err != nil {
+			t.Fatalf("Execution failed: %v", err)
+		}
+		if res.TrialsPerCheckpoint != FastTrials {
+			t.Errorf("Expected %d trials per checkpoint, got %d", FastTrials, res.TrialsPerCheckpoint)
+		}
+		if len(calls) == 0 {
+			t.Fatal("Expected progress callbacks, got none")
+		}
+		last := calls[len(calls)-1]
+		if last[0] != last[1] {
+			t.Errorf("Expected final progress to be complete, got %d/%d", last[0], last[1])
+		}
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		engine := NewWalkForwardEngine(events, mappings, nil)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		cfg := WalkForwardConfig{
+			SimulationMode:  "scope",
+			LookbackWindow:  40,
+			StepSize:        10,
+			ForecastHorizon: 10,
+		}
+		if _, err := engine.Execute(ctx, cfg); !errors.Is(err, context.Canceled) {
+			t.Errorf("Expected context.Canceled, got %v", err)
+		}
+	})
+}
+
+func TestComputeCalibration(t *testing.T) {
+	// 20 duration checkpoints predicting P50 10d, P85 14d, P95 20d. Actuals:
+	// 10 at 9 days, 4 at 15 days, 6 at 30 days → P50 and P85 honored 50%, P95 70%.
+	var checkpoints []ValidationCheckpoint
+	for i := range 20 {
+		actual := 9.0
+		if i >= 10 {
+			actual = 15
+		}
+		if i >= 14 {
+			actual = 30
+		}
+		checkpoints = append(checkpoints, ValidationCheckpoint{ActualValue: actual, PredictedP50: 10, PredictedP85: 14, PredictedP95: 20})
+	}
+	checkpoints = append(checkpoints, ValidationCheckpoint{ActualValue: 1, PredictedP50: 2000, IsDegenerate: true})
+
+	got := computeCalibration(checkpoints, "duration")
+	want := []CalibrationPoint{
+		{Percentile: "P50", Nominal: 0.50, Observed: 0.5, Hits: 10, Checkpoints: 20, Verdict: "calibrated"},
+		{Percentile: "P85", Nominal: 0.85, Observed: 0.5, Hits: 10, Checkpoints: 20, Verdict: "overconfident"},
+		{Percentile: "P95", Nominal: 0.95, Observed: 0.7, Hits: 14, Checkpoints: 20, Verdict: "overconfident"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Duration calibration:\n got %+v\nwant %+v", got, want)
+	}
+
+	// Scope forecasts are lower bounds: delivering more than the P50 honors it.
+	scope := []ValidationCheckpoint{{ActualValue: 12, PredictedP50: 10, PredictedP85: 8, PredictedP95: 6}}
+	if got := computeCalibration(scope, "scope"); got[0].Hits != 1 || got[2].Hits != 1 || got[0].Verdict != "insufficient_data" {
+		t.Errorf("Scope calibration: got %+v", got)
+	}
+	if got := computeCalibration(nil, "scope"); got != nil {
+		t.Errorf("Expected no calibration without checkpoints, got %+v", got)
+	}
+	if msg := calibrationSummary(want); !strings.Contains(msg, "10/20") || !strings.Contains(msg, "overconfident") {
+		t.Errorf("Unexpected calibration summary %q", msg)
+	}
+}
+
+func TestEngine_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	e := NewEngine(&Histogram{Counts: []int{1, 0, 2, 1, 3, 0, 1}})
+	e.SetContext(ctx)
+	if res := e.RunDurationSimulation(20, DefaultTrials); res.Percentiles.Likely != 0 {
+		t.Errorf("Expected an empty result from a cancelled simulation, got %+v", res.Percentiles)
+	}
+	if !errors.Is(e.Err(), context.Canceled) {
+		t.Errorf("Expected context.Canceled, got %v", e.Err())
+	}
+
+	now := time.Now()
+	req := ForecastRequest{
+		Mode:        "scope",
+		TargetDays:  14,
+		WindowStart: now.AddDate(0, 0, -30),
+		WindowEnd:   now,
+		Clock:       now,
+	}
+	for _, eng := range []ForecastEngine{&CrudeEngine{}, &BbakEngine{}} {
+		if _, err := eng.Run(ctx, req); !errors.Is(err, context.Canceled) {
+			t.Errorf("%s: expected context.Canceled, got %v", eng.Name(), err)
+		}
+	}
+}
+
+func TestArrivalDurationSimulation(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	issues := []jira.Issue{
+		{Key: "A-1", Created: start},
+		{Key: "A-2", Created: start.Add(5 * time.Hour)},
+		{Key: "A-3", Created: start.AddDate(0, 0, 3)},
+		{Key: "A-4", Created: start.AddDate(0, 0, 10)}, // outside the window
+	}
+	arrivals := NewArrivalHistogram(issues, start, start.AddDate(0, 0, 4))
+	if want := []int{2, 0, 0, 1, 0}; fmt.Sprint(arrivals.Counts) != fmt.Sprint(want) {
+		t.Fatalf("Expected daily arrivals %v, got %v", want, arrivals.Counts)
+	}
+
+	e := NewEngine(&Histogram{Counts: []int{2, 2, 2, 2}})
+	e.SetSeed(42)
+	fixed := e.RunDurationSimulation(20, 1000)
+	none := e.RunArrivalDurationSimulation(20, &Histogram{Counts: []int{0}}, 1000)
+	if none.Percentiles != fixed.Percentiles {
+		t.Errorf("Expected no arrivals to match the fixed-scope forecast, got %+v vs %+v", none.Percentiles, fixed.Percentiles)
+	}
+	moving := e.RunArrivalDurationSimulation(20, &Histogram{Counts: []int{1}}, 1000)
+	if moving.Percentiles.CoinToss != 19 || moving.ArrivalsPredicted != 18 {
+		t.Errorf("Expected 20 items at a net drain of 1/day to take 19 days with 18 arrivals, got %+v", moving)
+	}
+	flooded := e.RunArrivalDurationSimulation(20, &Histogram{Counts: []int{3}}, 100)
+	if flooded.Percentiles.CoinToss != MaxForecastDays {
+		t.Errorf("Expected a backlog that never drains to hit the safety limit, got %v", flooded.Percentiles.CoinToss)
+	}
+}
+
+func TestItemSimulation(t *testing.T) {
+	current := NewStatusStep("In Progress", []float64{10, 1, 3, 2}, 4)
+	review := NewStatusStep("Review", []float64{2, 2}, 2)
+	skipped := NewStatusStep("Security Review", []float64{30}, 100)
+	skipped.VisitRate = 0
+	if current.VisitRate != 1 || current.P50 != 3 {
+		t.Fatalf("Expected sorted durations with full visit rate, got %+v", current)
+	}
+
+	e := NewEngine(nil)
+	e.SetSeed(42)
+	// Only the 10-day item stayed longer than 5 days: 5 days left, then 2 in review.
+	res := e.RunItemSimulation(current, 5, []StatusStep{review, skipped}, 1000)
+	if res.Percentiles.Aggressive != 7 || res.Percentiles.AlmostCertain != 7 {
+		t.Errorf("Expected exactly 7 remaining days, got %+v", res.Percentiles)
+	}
+	if len(res.Warnings) != 0 {
+		t.Errorf("Expected no warnings, got %v", res.Warnings)
+	}
+
+	// Older than every delivered item: only the remaining statuses are forecast.
+	res = e.RunItemSimulation(current, 20, []StatusStep{review}, 1000)
+	if res.Percentiles.CoinToss != 2 || len(res.Warnings) != 1 {
+		t.Errorf("Expected 2 remaining days with an outlier warning, got %+v %v", res.Percentiles, res.Warnings)
+	}
+}
+
+func TestCapacityScenario(t *testing.T) {
+	run := func(c CapacityScenario, changeStep int) Result {
+		e := NewEngine(&Histogram{Counts: []int{2}})
+		e.SetSeed(42)
+		e.SetCapacity(c, changeStep)
+		return e.RunDurationSimulation(20, 1000)
+	}
+	if got := run(CapacityScenario{Factor: 0.5}, 0).Percentiles.CoinToss; got != 20 {
+		t.Errorf("Expected half capacity to take 20 days, got %v", got)
+	}
+	// 5 days at 2/day, then the remaining 10 items at 4/day.
+	if got := run(CapacityScenario{ChangeFactor: 2}, 5).Percentiles.CoinToss; got != 8 {
+		t.Errorf("Expected a doubled team from day 5 to finish in 8 days, got %v", got)
+	}
+
+	e := NewEngine(&Histogram{Counts: []int{2}})
+	e.SetSeed(42)
+	e.SetCapacity(CapacityScenario{Factor: 0.75}, 0)
+	res := e.RunScopeSimulation(1000, 100)
+	if got := res.Percentiles.CoinToss; got < 1450 || got > 1550 {
+		t.Errorf("Expected fractional scaling to keep the mean at 1.5/day (~1500 items), got %v", got)
+	}
+}
+
+func TestExtendWithLag(t *testing.T) {
+	p := Percentiles{Aggressive: 10, Unlikely: 12, CoinToss: 14, Probable: 16, Likely: 18, Conservative: 19, Safe: 20, AlmostCertain: 22}
+
+	if got := ExtendWithLag(p, nil, 1000, 1); got != p {
+		t.Errorf("Expected unchanged percentiles without lag data, got %+v", got)
+	}
+
+	// A constant lag shifts every percentile by exactly that amount (within interpolation noise).
+	got := ExtendWithLag(p, []float64{5}, 20000, 1)
+	if got.CoinToss < 18.5 || got.CoinToss > 19.5 {
+		t.Errorf("Expected P50 ≈ 19, got %.2f", got.CoinToss)
+	}
+	if got.Likely < 22.5 || got.Likely > 23.5 {
+		t.Errorf("Expected P85 ≈ 23, got %.2f", got.Likely)
+	}
+	if got.Aggressive < p.Aggressive+5 {
+		t.Errorf("Expected P10 >= %.2f, got %.2f", p.Aggressive+5, got.Aggressive)
+	}
+}
+
+func TestRunScopeSimulationBurnUp(t *testing.T) {
+	e := NewEngine(&Histogram{Counts: []int{0, 2}})
+	e.SetSeed(42)
+	e.SetBurnUpCheckpoints([]int{7, 14, 21, 28})
+	res := e.RunScopeSimulation(21, 2000)
+
+	if len(res.BurnUp) != 3 {
+		t.Fatalf("Expected checkpoints beyond the horizon to be dropped, got %+v", res.BurnUp)
+	}
+	for i, p := range res.BurnUp {
+		if p.Day != 7*(i+1) {
+			t.Errorf("Expected checkpoint %d on day %d, got %d", i, 7*(i+1), p.Day)
+		}
+		if p.P95 > p.P85 || p.P85 > p.P50 {
+			t.Errorf("Expected P95 <= P85 <= P50 (delivered at least), got %+v", p)
+		}
+		if i > 0 && p.P50 < res.BurnUp[i-1].P50 {
+			t.Errorf("Expected a non-decreasing cone, got %+v", res.BurnUp)
+		}
+	}
+	// 1 item/day on average.
+	if mid := res.BurnUp[1].P50; mid < 12 || mid > 16 {
+		t.Errorf("Expected about 14 items by day 14, got %v", mid)
+	}
+	if last := res.BurnUp[2]; last.P50 != res.Percentiles.CoinToss || last.P85 != res.Percentiles.Likely {
+		t.Errorf("Expected the last checkpoint to match the horizon percentiles, got %+v vs %+v", last, res.Percentiles)
+	}
+
+	friday := time.Date(2026, 3, 6, 0, 0, 0, 0, time.UTC)
+	e = NewEngine(&Histogram{Counts: []int{1}})
+	e.SetCalendar(DefaultCalendar(), friday)
+	e.SetBurnUpCheckpoints([]int{2, 3, 7})
+	res = e.RunScopeSimulation(7, 100)
+	if res.BurnUp[0].P50 != 0 || res.BurnUp[1].P50 != 1 || res.BurnUp[2].P50 != 5 {
+		t.Errorf("Expected nothing over the weekend, 1 item by Monday and 5 in the first week, got %+v", res.BurnUp)
+	}
+}
+
+func TestRunScopeSimulationTimebox(t *testing.T) {
+	// 1 item/day on average: 10 days deliver about 10 items.
+	e := NewEngine(&Histogram{Counts: []int{0, 2}})
+	e.SetSeed(42)
+	e.SetTimeboxCommitment(10)
+	res := e.RunScopeSimulation(10, 2000)
+
+	tb := res.Timebox
+	if tb == nil {
+		t.Fatal("Expected a timebox outcome")
+	}
+	if tb.Probability < 0.4 || tb.Probability > 0.75 {
+		t.Errorf("Expected roughly a coin toss for 10 items in 10 days, got %.2f", tb.Probability)
+	}
+	if tb.DescopeP85 < tb.DescopeP50 || tb.DescopeP85 != 10-int(res.Percentiles.Likely) {
+		t.Errorf("Expected the P85 de-scope to drop the items beyond the P85 count, got %+v (P85 %.0f)", tb, res.Percentiles.Likely)
+	}
+
+	e = NewEngine(&Histogram{Counts: []int{0, 2}})
+	e.SetSeed(42)
+	e.SetTimeboxCommitment(2)
+	if tb := e.RunScopeSimulation(20, 2000).Timebox; tb.Probability < 0.99 || tb.DescopeP85 != 0 {
+		t.Errorf("Expected a safe commitment for 2 items in 20 days, got %+v", tb)
+	}
+}
+
+func TestRunDurationSimulationDeadline(t *testing.T) {
+	run := func(days int) Result {
+		// 1 item/day on average: 10 items take about 10 days.
+		e := NewEngine(&Histogram{Counts: []int{0, 2}})
+		e.SetSeed(42)
+		e.SetDeadline(days)
+		return e.RunMultiTypeDurationSimulation(map[string]int{"Story": 10}, map[string]float64{"Story": 1}, 2000, true)
+	}
+
+	res := run(10)
+	d := res.Deadline
+	if d == nil {
+		t.Fatal("Expected a deadline outcome")
+	}
+	if d.Items != 10 || d.Probability < 0.4 || d.Probability > 0.75 {
+		t.Errorf("Expected roughly a coin toss for 10 items in 10 days, got %+v", d)
+	}
+	if d.GapDaysP85 != int(math.Ceil(res.Percentiles.Likely))-10 || d.GapDaysP85 <= 0 || d.GapItemsP85 <= 0 {
+		t.Errorf("Expected the P85 to miss the deadline by days and items, got %+v (P85 %.0f)", d, res.Percentiles.Likely)
+	}
+
+	if d := run(40).Deadline; d.Probability < 0.99 || d.GapDaysP85 != 0 || d.GapItemsP85 != 0 {
+		t.Errorf("Expected a safe deadline 40 days out, got %+v", d)
+	}
+	if res := NewEngine(&Histogram{Counts: []int{0, 2}}).RunMultiTypeDurationSimulation(map[string]int{"Story": 10}, map[string]float64{"Story": 1}, 200, true); res.Deadline != nil {
+		t.Errorf("Expected no deadline outcome without a deadline, got %+v", res.Deadline)
+	}
+}
+
+func TestHistogram_TrimOutliers(t *testing.T) {
+	newHistogram := func() *Histogram {
+		return &Histogram{
+			Counts:           []int{1, 0, 2, 1, 40, 0, 1, 2, 1, 0},
+			StratifiedCounts: map[string][]int{"Story": {1, 0, 2, 1, 40, 0, 1, 2, 1, 0}},
+			Meta:             map[string]any{},
+		}
+	}
+
+	h := newHistogram()
+	if report := h.TrimOutliers(stats.OutliersNone); report.Trimmed != 0 || len(h.Counts) != 10 {
+		t.Errorf("none: expected the histogram unchanged, got %v", h.Counts)
+	}
+
+	h = newHistogram()
+	report := h.TrimOutliers(stats.OutliersWinsorize)
+	if len(h.Counts) != 10 || h.Counts[4] >= 40 || report.Trimmed != 40-h.Counts[4] {
+		t.Errorf("winsorize: expected the 40-item day capped, got %v (%+v)", h.Counts, report)
+	}
+
+	h = newHistogram()
+	report = h.TrimOutliers(stats.OutliersIQR)
+	if len(h.Counts) != 9 || slices.Contains(h.Counts, 40) || report.Trimmed != 40 {
+		t.Errorf("iqr: expected the 40-item day dropped, got %v (%+v)", h.Counts, report)
+	}
+	if len(h.StratifiedCounts["Story"]) != 9 || h.Meta["days_in_sample"] != 9 || h.Meta["outliers"] != report {
+		t.Errorf("iqr: expected per-type counts and meta to follow, got %v %v", h.StratifiedCounts, h.Meta)
+	}
+}
+
+func TestCycleTimeByType(t *testing.T) {
+	tail := make([]float64, 40)
+	for i := range tail {
+		tail[i] = 2
+	}
+	tail[39] = 40 // P98 far out: unstable
+
+	table := CycleTimeByType(map[string][]float64{
+		"Bug":   {1, 2, 3},
+		"Story": tail,
+		"Epic":  nil,
+	})
+	if len(table) != 2 || table[0].IssueType != "Story" || table[1].IssueType != "Bug" {
+		t.Fatalf("Expected Story then Bug, got %+v", table)
+	}
+	story, bug := table[0], table[1]
+	if story.Count != 40 || story.SmallSample || story.Predictability != "Unstable" {
+		t.Errorf("Expected 40 unstable Stories, got %+v", story)
+	}
+	if bug.Count != 3 || !bug.SmallSample || bug.Predictability != "Stable" {
+		t.Errorf("Expected 3 stable Bugs flagged as small sample, got %+v", bug)
+	}
+}
+
+func TestCycleTimeByGroup(t *testing.T) {
+	table := CycleTimeByGroup(map[string][]float64{"Payments": {1, 2, 3, 4}, "Search": {5}})
+	if len(table) != 2 || table[0].Group != "Payments" || table[0].Count != 4 || table[1].Group != "Search" {
+		t.Fatalf("Expected Payments then Search, got %+v", table)
+	}
+	if !table[1].SmallSample {
+		t.Error("Expected a single item to be flagged as small sample")
+	}
+}
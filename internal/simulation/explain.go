@@ -0,0 +1,149 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+
+	"mcs-mcp/internal/stats"
+)
+
+// ExplainThroughputShift is the relative throughput change of the
+// one-at-a-time throughput sensitivity of a forecast explanation.
+const ExplainThroughputShift = 0.20
+
+// ForecastExplanation quantifies the drivers of a forecast: each driver reruns
+// the forecast with one input changed, on the same engine and random seed, so
+// the change of the percentiles is due to that input alone.
+type ForecastExplanation struct {
+	Unit        string           `json:"unit"`         // "days" (duration) or "items" (scope)
+	BaselineP50 float64          `json:"baseline_p50"` // Engine output before post-processing such as release lag
+	BaselineP85 float64          `json:"baseline_p85"`
+	Drivers     []ForecastDriver `json:"drivers"`
+}
+
+// ForecastDriver is the outcome of one variation of the forecast inputs.
+type ForecastDriver struct {
+	Driver    string  `json:"driver"`
+	Variation string  `json:"variation"`
+	P50       float64 `json:"p50"`
+	P85       float64 `json:"p85"`
+	P85Change float64 `json:"p85_change"` // P85 minus the baseline P85
+	Summary   string  `json:"summary"`
+}
+
+// ExplainVariant is one variation of a forecast request.
+type ExplainVariant struct {
+	Driver    string
+	Variation string // Describes the change, e.g. "throughput -20%"
+	Req       ForecastRequest
+}
+
+// ThroughputVariants returns the variants explaining the throughput sample of
+// req: only its recent half, only its older half, and throughput scaled down
+// and up by ExplainThroughputShift on top of any capacity scenario.
+func ThroughputVariants(req ForecastRequest) []ExplainVariant {
+	var variants []ExplainVariant
+	if days := stats.CalendarDaysBetween(req.WindowStart, req.WindowEnd); days >= 14 {
+		mid := req.WindowStart.AddDate(0, 0, days/2)
+		recent, older := req, req
+		recent.WindowStart = mid
+		older.WindowEnd = mid
+		variants = append(variants,
+			ExplainVariant{Driver: "recent_throughput", Variation: fmt.Sprintf("sample only %s to %s", mid.Format(stats.DateFormat), req.WindowEnd.Format(stats.DateFormat)), Req: recent},
+			ExplainVariant{Driver: "older_throughput", Variation: fmt.Sprintf("sample only %s to %s", req.WindowStart.Format(stats.DateFormat), mid.Format(stats.DateFormat)), Req: older},
+		)
+	}
+	for _, shift := range []float64{-ExplainThroughputShift, ExplainThroughputShift} {
+		v := req
+		c := CapacityScenario{}
+		if req.Capacity != nil {
+			c = *req.Capacity
+		}
+		factor := c.Factor
+		if factor == 0 {
+			factor = 1
+		}
+		c.Factor = factor * (1 + shift)
+		v.Capacity = &c
+		variants = append(variants, ExplainVariant{Driver: "throughput_sensitivity", Variation: fmt.Sprintf("throughput %+.0f%%", shift*100), Req: v})
+	}
+	return variants
+}
+
+// ResolutionVariant returns the variant counting the items that exited as
+// abandoned, and so are left out of the throughput sample, as deliveries. ok
+// is false when no such item is in the sample, and when req.Outcomes already
+// samples abandoned items.
+func ResolutionVariant(req ForecastRequest) (ExplainVariant, bool) {
+	if req.Outcomes != "" && req.Outcomes != stats.OutcomesDelivered {
+		return ExplainVariant{}, false
+	}
+	finished := slices.Clone(req.Finished)
+	excluded := 0
+	for i, issue := range finished {
+		if stats.HasExited(issue) && !stats.IsDelivered(issue) {
+			issue.Outcome = "delivered"
+			finished[i] = issue
+			excluded++
+		}
+	}
+	if excluded == 0 {
+		return ExplainVariant{}, false
+	}
+	req.Finished = finished
+	return ExplainVariant{Driver: "excluded_resolutions", Variation: fmt.Sprintf("count %d abandoned item(s) as delivered", excluded), Req: req}, true
+}
+
+// ExplainForecast runs each variant with engine and compares it with the
+// baseline percentiles. Every variant must carry the seed of the baseline run.
+func ExplainForecast(ctx context.Context, engine ForecastEngine, mode string, baseline Percentiles, variants []ExplainVariant) (*ForecastExplanation, error) {
+	unit := "days"
+	if mode == "scope" {
+		unit = "items"
+	}
+	exp := &ForecastExplanation{
+		Unit:        unit,
+		BaselineP50: stats.Round2(baseline.CoinToss),
+		BaselineP85: stats.Round2(baseline.Likely),
+	}
+	for _, v := range variants {
+		res, err := engine.Run(ctx, v.Req)
+		if err != nil {
+			return nil, fmt.Errorf("%s (%s): %w", v.Driver, v.Variation, err)
+		}
+		d := ForecastDriver{
+			Driver:    v.Driver,
+			Variation: v.Variation,
+			P50:       stats.Round2(res.Percentiles.CoinToss),
+			P85:       stats.Round2(res.Percentiles.Likely),
+		}
+		d.P85Change = stats.Round2(d.P85 - exp.BaselineP85)
+		d.Summary = fmt.Sprintf("With %s, P85 moves from %s → %s %s.", v.Variation, formatAmount(exp.BaselineP85), formatAmount(d.P85), unit)
+		exp.Drivers = append(exp.Drivers, d)
+	}
+	return exp, nil
+}
+
+// DominantDriver returns the driver whose variation moves the P85 the most.
+func (e *ForecastExplanation) DominantDriver() (ForecastDriver, bool) {
+	if e == nil || len(e.Drivers) == 0 {
+		return ForecastDriver{}, false
+	}
+	best := e.Drivers[0]
+	for _, d := range e.Drivers[1:] {
+		if math.Abs(d.P85Change) > math.Abs(best.P85Change) {
+			best = d
+		}
+	}
+	return best, true
+}
+
+// formatAmount prints whole numbers without decimals.
+func formatAmount(v float64) string {
+	if v == math.Trunc(v) {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("%.1f", v)
+}
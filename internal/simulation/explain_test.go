@@ -0,0 +1,47 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestThroughputVariants(t *testing.T) {
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	req := ForecastRequest{WindowStart: start, WindowEnd: start.AddDate(0, 0, 90), Capacity: &CapacityScenario{Factor: 0.5}}
+
+	variants := ThroughputVariants(req)
+	if len(variants) != 4 {
+		t.Fatalf("Expected two sample halves and two throughput shifts, got %d", len(variants))
+	}
+	recent, older := variants[0].Req, variants[1].Req
+	if !recent.WindowStart.Equal(start.AddDate(0, 0, 45)) || !recent.WindowEnd.Equal(req.WindowEnd) || !older.WindowEnd.Equal(recent.WindowStart) {
+		t.Errorf("Expected the window split at its middle, got %v–%v and %v–%v", recent.WindowStart, recent.WindowEnd, older.WindowStart, older.WindowEnd)
+	}
+	if down, up := variants[2].Req.Capacity.Factor, variants[3].Req.Capacity.Factor; down != 0.4 || up != 0.6 {
+		t.Errorf("Expected ±20%% on top of the 0.5 capacity factor, got %v and %v", down, up)
+	}
+	if req.Capacity.Factor != 0.5 {
+		t.Errorf("Expected the request's capacity scenario to be left alone, got %v", req.Capacity.Factor)
+	}
+
+	short := ThroughputVariants(ForecastRequest{WindowStart: start, WindowEnd: start.AddDate(0, 0, 7)})
+	if len(short) != 2 || short[0].Variation != "throughput -20%" {
+		t.Errorf("Expected only the throughput shifts for a one-week sample, got %+v", short)
+	}
+}
+
+func TestResolutionVariant(t *testing.T) {
+	finished := []jira.Issue{{Key: "A", Outcome: "delivered"}, {Key: "B", Outcome: "abandoned"}}
+	v, ok := ResolutionVariant(ForecastRequest{Finished: finished})
+	if !ok || v.Req.Finished[1].Outcome != "delivered" {
+		t.Fatalf("Expected the abandoned item counted as delivered, got %+v", v.Req.Finished)
+	}
+	if finished[1].Outcome != "abandoned" {
+		t.Error("Expected the request's issues to be left alone")
+	}
+	if _, ok := ResolutionVariant(ForecastRequest{Finished: finished[:1]}); ok {
+		t.Error("Expected no variant without abandoned items")
+	}
+}
@@ -0,0 +1,99 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+
+	"mcs-mcp/internal/stats"
+)
+
+// ExternalThroughput is daily throughput recorded outside Jira, e.g. in a
+// spreadsheet from before the team moved to Jira.
+type ExternalThroughput struct {
+	Source string    // Provenance, e.g. the imported file
+	Start  time.Time // Day of Counts[0]
+	Counts []int     // Delivered items per calendar day
+}
+
+// ExternalMerge reports the imported days a histogram samples. It is recorded
+// as Meta["external_throughput"].
+type ExternalMerge struct {
+	Source      string `json:"source"`
+	Days        int    `json:"days"`                   // Imported calendar days in the sample
+	Items       int    `json:"items"`                  // Items delivered on those days
+	JiraDays    int    `json:"jira_days"`              // Calendar days from the first Jira delivery on
+	IgnoredDays int    `json:"ignored_days,omitempty"` // Imported days Jira history already covers
+}
+
+// Warning describes the provenance of a hybrid sample for forecast results.
+func (m ExternalMerge) Warning() string {
+	return fmt.Sprintf("HYBRID SAMPLE: %d of %d sampled calendar days (%d items) come from throughput imported from %s, not from Jira. Per-type stratification is off, as the imported days carry no issue types.",
+		m.Days, m.Days+m.JiraDays, m.Items, m.Source)
+}
+
+// MergeExternal fills the days of h before its first Jira delivery with the
+// imported throughput of ext, extending h to the past when ext starts before
+// start, the day of the first bucket. Imported days from the first Jira
+// delivery on are ignored: Jira history wins. As the imported days carry no
+// issue types, stratification is switched off. It returns the day of the
+// first bucket afterwards.
+func (h *Histogram) MergeExternal(start time.Time, ext *ExternalThroughput) time.Time {
+	if ext == nil || len(ext.Counts) == 0 {
+		return start
+	}
+	first := len(h.Counts)
+	for i, c := range h.Counts {
+		if c > 0 {
+			first = i
+			break
+		}
+	}
+
+	newStart := start
+	if ext.Start.Before(start) {
+		newStart = ext.Start
+	}
+	lead := stats.CalendarDaysBetween(newStart, start)
+	counts := make([]int, lead+len(h.Counts))
+	copy(counts[lead:], h.Counts)
+
+	merge := ExternalMerge{Source: ext.Source, JiraDays: len(h.Counts) - first}
+	for i := range lead + first {
+		j := stats.CalendarDaysBetween(ext.Start, newStart.AddDate(0, 0, i))
+		if j < 0 || j >= len(ext.Counts) {
+			continue
+		}
+		counts[i] = ext.Counts[j]
+		merge.Days++
+		merge.Items += ext.Counts[j]
+	}
+	merge.IgnoredDays = len(ext.Counts) - merge.Days
+	if merge.Days == 0 {
+		return start
+	}
+
+	h.Counts = counts
+	for t, c := range h.StratifiedCounts {
+		h.StratifiedCounts[t] = append(make([]int, lead), c...)
+	}
+	if h.Meta == nil {
+		h.Meta = make(map[string]any)
+	}
+	h.poolStreams()
+	h.Meta["external_throughput"] = merge
+	return newStart
+}
+
+// annotateExternal flags a result sampled from a hybrid histogram: the merge
+// goes to Context["external_throughput"], its provenance to the warnings.
+func annotateExternal(res *Result, h *Histogram) {
+	m, ok := h.Meta["external_throughput"].(ExternalMerge)
+	if !ok {
+		return
+	}
+	if res.Context == nil {
+		res.Context = make(map[string]any)
+	}
+	res.Context["external_throughput"] = m
+	res.Warnings = append(res.Warnings, m.Warning())
+}
@@ -0,0 +1,50 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestHistogram_MergeExternal(t *testing.T) {
+	start := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	h := &Histogram{
+		Counts:           []int{0, 0, 0, 2, 1}, // Jira history starts on Jan 13
+		StratifiedCounts: map[string][]int{"Story": {0, 0, 0, 2, 1}},
+		Meta:             map[string]any{"stratification_eligible": map[string]bool{"Story": true}},
+	}
+	ext := &ExternalThroughput{Source: "legacy.csv", Start: start.AddDate(0, 0, -2), Counts: []int{1, 1, 1, 1, 1, 1, 1}}
+
+	got := h.MergeExternal(start, ext)
+
+	if !got.Equal(ext.Start) {
+		t.Errorf("Expected the histogram to start with the imported days, got %s", got)
+	}
+	if !slices.Equal(h.Counts, []int{1, 1, 1, 1, 1, 2, 1}) {
+		t.Errorf("Expected imported days up to the first Jira delivery, got %v", h.Counts)
+	}
+	if len(h.StratifiedCounts["Story"]) != len(h.Counts) {
+		t.Errorf("Expected the per-type counts to stay aligned, got %v", h.StratifiedCounts["Story"])
+	}
+	if _, ok := h.Meta["stratification_eligible"]; ok {
+		t.Error("Expected stratification to be switched off")
+	}
+	m, _ := h.Meta["external_throughput"].(ExternalMerge)
+	if m.Days != 5 || m.Items != 5 || m.JiraDays != 2 || m.IgnoredDays != 2 {
+		t.Errorf("Unexpected merge report %+v", m)
+	}
+}
+
+func TestHistogram_MergeExternal_JiraCovered(t *testing.T) {
+	start := time.Date(2024, 1, 10, 0, 0, 0, 0, time.UTC)
+	h := &Histogram{Counts: []int{1, 0, 2}, Meta: map[string]any{}}
+
+	got := h.MergeExternal(start, &ExternalThroughput{Start: start, Counts: []int{5, 5, 5}})
+
+	if !got.Equal(start) || !slices.Equal(h.Counts, []int{1, 0, 2}) {
+		t.Errorf("Expected Jira history to win, got %v from %s", h.Counts, got)
+	}
+	if _, ok := h.Meta["external_throughput"]; ok {
+		t.Error("Expected no merge to be recorded")
+	}
+}
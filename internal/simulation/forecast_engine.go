@@ -1,6 +1,7 @@
 package simulation
 
 import (
+	"context"
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
 	"time"
@@ -25,13 +26,51 @@ type ForecastRequest struct {
 	// Duration mode
 	Targets     map[string]int
 	MixOverrides map[string]float64
+	// ModelArrivals adds a moving-target forecast that also samples the
+	// historical arrival rate (Result.WithArrivals).
+	ModelArrivals bool
+	// DeadlineDays checks the forecast against a target date that many
+	// calendar days ahead (Result.Deadline; 0 = none).
+	DeadlineDays int
 
 	// Scope mode
 	TargetDays int
 
+	// Working calendar (nil = every calendar day is a working day)
+	Calendar *Calendar
+
+	// Throughput sampling: SamplingEmpirical ("" = default) or SamplingParametric
+	Sampling string
+
+	// Days sampled independently or as whole weeks: GranularityDay ("" =
+	// default) or GranularityWeek
+	Granularity string
+
+	// Per-type stratification: StratificationAuto ("" = default),
+	// StratificationOn, or StratificationOff, with the fewest items a type
+	// needs to be sampled on its own (0 = StratificationMinVolume)
+	Stratification string
+	MinStratumSize int
+
+	// Capacity scenario applied to sampled throughput (nil = as observed)
+	Capacity *CapacityScenario
+
+	// Outlier treatment of the daily throughput ("" = none)
+	Outliers stats.OutlierPolicy
+
+	// Finished items sampled as throughput ("" = delivered only)
+	Outcomes stats.OutcomeScope
+
+	// Throughput imported from outside Jira, sampled for the days before the
+	// first Jira delivery (nil = Jira history only)
+	External *ExternalThroughput
+
 	// Filters
 	IssueTypes []string
 
+	// Issue types grouped under a canonical type before sampling (nil = none)
+	TypeAliases TypeAliases
+
 	// Workflow context
 	CommitmentPoint  string
 	StatusWeights    map[string]int
@@ -50,6 +89,7 @@ type ForecastEngine interface {
 	// Name returns the unique identifier for this engine (e.g., "crude", "bbak").
 	Name() string
 
-	// Run executes the full forecast pipeline and returns a Result.
-	Run(req ForecastRequest) (Result, error)
+	// Run executes the full forecast pipeline and returns a Result. It
+	// returns ctx.Err() when ctx is cancelled before the trials complete.
+	Run(ctx context.Context, req ForecastRequest) (Result, error)
 }
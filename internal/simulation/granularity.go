@@ -0,0 +1,147 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Sampling granularities of the daily throughput histogram.
+const (
+	// GranularityDay samples every simulated day independently (default).
+	GranularityDay = "day"
+	// GranularityWeek replays whole weeks of consecutive sampled days, so
+	// batches and slow weeks stay together.
+	GranularityWeek = "week"
+)
+
+const (
+	// BatchingMinWeeks is the fewest whole weeks AssessBatching judges.
+	BatchingMinWeeks = 8
+	// BatchingDispersionThreshold is the ratio of the observed variance of
+	// weekly throughput to the variance independent days imply from which
+	// daily sampling understates the spread of a forecast.
+	BatchingDispersionThreshold = 1.5
+)
+
+// Granularity describes how trials sample a histogram.
+type Granularity struct {
+	Unit      string `json:"unit"`       // GranularityDay or GranularityWeek
+	BlockDays int    `json:"block_days"` // Consecutive sampled days per draw
+}
+
+// ParseGranularity validates a granularity name; "" is GranularityDay.
+func ParseGranularity(s string) (string, error) {
+	switch s {
+	case "":
+		return GranularityDay, nil
+	case GranularityDay, GranularityWeek:
+		return s, nil
+	}
+	return "", fmt.Errorf("invalid sampling_granularity %q: expected day or week", s)
+}
+
+// WeekLength returns the sampled days of a week: 7, or the workdays of cal
+// once RestrictToWorkingDays folded the histogram with it.
+func WeekLength(cal *Calendar) int {
+	if cal == nil {
+		return 7
+	}
+	n := 0
+	for _, ok := range cal.Workdays {
+		if ok {
+			n++
+		}
+	}
+	if n == 0 {
+		return 7
+	}
+	return n
+}
+
+// SetGranularity makes trials sample h per day or per week, a week being
+// WeekLength(cal) consecutive days. Call it after RestrictToWorkingDays. A
+// weekly granularity is recorded as Meta["sampling_granularity"].
+func (h *Histogram) SetGranularity(granularity string, cal *Calendar) {
+	h.Block = 0
+	if granularity != GranularityWeek {
+		return
+	}
+	h.Block = WeekLength(cal)
+	if h.Meta == nil {
+		h.Meta = make(map[string]any)
+	}
+	h.Meta["sampling_granularity"] = Granularity{Unit: GranularityWeek, BlockDays: h.Block}
+}
+
+// daySampler draws the bucket of each simulated day of one trial. Without
+// a block every day is drawn independently; with one, the trial replays
+// runs of block consecutive buckets, each starting at random.
+type daySampler struct {
+	size, block int
+	next, left  int
+}
+
+func newDaySampler(size, block int) *daySampler {
+	return &daySampler{size: size, block: min(block, size)}
+}
+
+func (d *daySampler) draw(rng *rand.Rand) int {
+	if d.block <= 1 {
+		return rng.IntN(d.size)
+	}
+	if d.left == 0 {
+		d.next, d.left = rng.IntN(d.size-d.block+1), d.block
+	}
+	d.left--
+	d.next++
+	return d.next - 1
+}
+
+// BatchingAssessment compares the variance of weekly throughput with the
+// variance sampling independent days implies. A ratio well above 1 means
+// good and bad days cluster in weeks (e.g. weekly batches), so daily
+// sampling understates the spread of a forecast.
+type BatchingAssessment struct {
+	Weeks           int     `json:"weeks"`            // Whole weeks assessed, the most recent
+	WeekDays        int     `json:"week_days"`        // Sampled days per week
+	DispersionRatio float64 `json:"dispersion_ratio"` // Variance of weekly totals / (week_days × variance of days)
+	Batched         bool    `json:"batched"`
+	Recommended     string  `json:"recommended_granularity"` // GranularityDay or GranularityWeek
+}
+
+// AssessBatching judges the last whole weeks of h, weeks of weekLength
+// days, and recommends a sampling granularity. Fewer than BatchingMinWeeks
+// weeks keep the daily default.
+func (h *Histogram) AssessBatching(weekLength int) BatchingAssessment {
+	out := BatchingAssessment{WeekDays: weekLength, Recommended: GranularityDay}
+	if weekLength <= 1 {
+		return out
+	}
+	out.Weeks = len(h.Counts) / weekLength
+	if out.Weeks < BatchingMinWeeks {
+		return out
+	}
+	days := h.Counts[len(h.Counts)-out.Weeks*weekLength:]
+	weeks := make([]int, 0, out.Weeks)
+	for chunk := range slices.Chunk(days, weekLength) {
+		total := 0
+		for _, c := range chunk {
+			total += c
+		}
+		weeks = append(weeks, total)
+	}
+	_, daily := meanStdDev(days)
+	if daily == 0 {
+		out.DispersionRatio = 1
+		return out
+	}
+	_, weekly := meanStdDev(weeks)
+	out.DispersionRatio = math.Round(weekly*weekly/(float64(weekLength)*daily*daily)*100) / 100
+	out.Batched = out.DispersionRatio >= BatchingDispersionThreshold
+	if out.Batched {
+		out.Recommended = GranularityWeek
+	}
+	return out
+}
@@ -0,0 +1,86 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+// weeklyBatches returns n weeks of 7 days alternating between a busy week
+// (3 items a day) and an idle one.
+func weeklyBatches(n int) []int {
+	counts := make([]int, 0, n*7)
+	for w := range n {
+		for range 7 {
+			counts = append(counts, 3*((w+1)%2))
+		}
+	}
+	return counts
+}
+
+func TestHistogram_AssessBatching(t *testing.T) {
+	batched := (&Histogram{Counts: weeklyBatches(10)}).AssessBatching(7)
+	if !batched.Batched || batched.Recommended != GranularityWeek || batched.Weeks != 10 || batched.DispersionRatio < BatchingDispersionThreshold {
+		t.Errorf("Expected alternating weeks to be batched, got %+v", batched)
+	}
+
+	// Every week delivers the same: weekly totals vary less than days imply.
+	steady := make([]int, 0, 70)
+	for range 10 {
+		steady = append(steady, 5, 0, 1, 0, 1, 0, 0)
+	}
+	if got := (&Histogram{Counts: steady}).AssessBatching(7); got.Batched || got.Recommended != GranularityDay || got.DispersionRatio >= 1 {
+		t.Errorf("Expected steady weeks not to be batched, got %+v", got)
+	}
+
+	if got := (&Histogram{Counts: weeklyBatches(5)}).AssessBatching(7); got.Batched || got.Weeks != 5 || got.DispersionRatio != 0 {
+		t.Errorf("Expected fewer than %d weeks to keep the daily default, got %+v", BatchingMinWeeks, got)
+	}
+}
+
+func TestHistogram_SetGranularity(t *testing.T) {
+	h := &Histogram{Counts: weeklyBatches(8)}
+	h.SetGranularity(GranularityWeek, DefaultCalendar())
+	if h.Block != 5 || h.Meta["sampling_granularity"] != (Granularity{Unit: GranularityWeek, BlockDays: 5}) {
+		t.Errorf("Expected blocks of 5 working days, got %d and %v", h.Block, h.Meta)
+	}
+	h.SetGranularity(GranularityDay, nil)
+	if h.Block != 0 {
+		t.Errorf("Expected independent days, got blocks of %d", h.Block)
+	}
+
+	if _, err := ParseGranularity("month"); err == nil {
+		t.Error("Expected an unknown granularity to fail")
+	}
+	if g, _ := ParseGranularity(""); g != GranularityDay {
+		t.Errorf("Expected day by default, got %q", g)
+	}
+}
+
+func TestDaySampler_ReplaysConsecutiveDays(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	d := newDaySampler(20, 5)
+	for range 10 {
+		first := d.draw(rng)
+		for i := 1; i < 5; i++ {
+			if got := d.draw(rng); got != first+i {
+				t.Fatalf("Expected day %d of a block starting at %d, got %d", first+i, first, got)
+			}
+		}
+	}
+}
+
+func TestRunScopeSimulation_WeeklyGranularityWidensSpread(t *testing.T) {
+	spread := func(granularity string) float64 {
+		h := &Histogram{Counts: weeklyBatches(8)}
+		h.SetGranularity(granularity, nil)
+		e := NewEngine(h)
+		e.SetSeed(42)
+		res := e.RunScopeSimulation(7, 2000)
+		return math.Abs(res.Percentiles.Aggressive - res.Percentiles.Safe)
+	}
+	day, week := spread(GranularityDay), spread(GranularityWeek)
+	if week <= day {
+		t.Errorf("Expected weekly sampling to keep batches together and widen the spread, got %.1f (week) vs %.1f (day)", week, day)
+	}
+}
@@ -2,6 +2,7 @@ package simulation
 
 import (
 	"fmt"
+	"math"
 	"math/rand/v2"
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
@@ -14,10 +15,12 @@ type Histogram struct {
 	Counts           []int
 	StratifiedCounts map[string][]int
 	Meta             map[string]any
+	Block            int // Consecutive days a trial samples together (0 = independent days; see SetGranularity)
 }
 
-// NewHistogram creates a histogram from a list of resolved issues.
-func NewHistogram(issues []jira.Issue, startTime, endTime time.Time, issueTypes []string, mappings map[string]stats.StatusMetadata, resolutionMappings map[string]string) *Histogram {
+// NewHistogram creates a histogram from a list of resolved issues, counting
+// those that are done under outcomes ("" = delivered only).
+func NewHistogram(issues []jira.Issue, startTime, endTime time.Time, issueTypes []string, mappings map[string]stats.StatusMetadata, resolutionMappings map[string]string, outcomes stats.OutcomeScope) *Histogram {
 	// Create maps for fast lookup
 	typeMap := make(map[string]bool)
 	for _, t := range issueTypes {
@@ -41,7 +44,7 @@ func NewHistogram(issues []jira.Issue, startTime, endTime time.Time, issueTypes
 	// 1. First pass: Collect delivered items and their types
 	deliveredIssues := make([]jira.Issue, 0)
 	for _, issue := range issues {
-		if !stats.IsDelivered(issue) {
+		if !outcomes.Includes(issue) {
 			droppedByOutcome++
 			continue
 		}
@@ -146,18 +149,6 @@ func NewHistogram(issues []jira.Issue, startTime, endTime time.Time, issueTypes
 		volatility[t] = CalculateFatTail(counts)
 	}
 
-	// Determine modeling insight (Disclosure)
-	insight := "Pooled: Overall process is homogeneous enough for single-stream modeling."
-	stratCount := 0
-	for _, isEligible := range stratEligible {
-		if isEligible {
-			stratCount++
-		}
-	}
-	if stratCount > 0 {
-		insight = fmt.Sprintf("Stratified: Modeling %d distinct delivery streams independently to capture capacity clashes and variance.", stratCount)
-	}
-
 	meta := map[string]any{
 		"issues_total":                len(issues),
 		"issues_analyzed":             totalDelivered,
@@ -172,7 +163,7 @@ func NewHistogram(issues []jira.Issue, startTime, endTime time.Time, issueTypes
 		"stratification_decisions":    decisions,
 		"stratification_eligible":     stratEligible,
 		"stratification_dependencies": dependencies,
-		"modeling_insight":            insight,
+		"modeling_insight":            modelingInsight(len(stratEligible)),
 	}
 
 	return &Histogram{
@@ -182,6 +173,140 @@ func NewHistogram(issues []jira.Issue, startTime, endTime time.Time, issueTypes
 	}
 }
 
+// modelingInsight discloses whether a forecast samples streams of
+// its own or one pooled stream.
+func modelingInsight(streams int) string {
+	if streams > 0 {
+		return fmt.Sprintf("Stratified: Modeling %d distinct delivery streams independently to capture capacity clashes and variance.", streams)
+	}
+	return "Pooled: Overall process is homogeneous enough for single-stream modeling."
+}
+
+// Stratify re-decides which types of h are sampled as streams of their own:
+// StratificationAuto ("") applies the volume and variance criteria,
+// StratificationOn the volume criterion only, StratificationOff none.
+// minSample replaces StratificationMinVolume when above 0. The decisions,
+// eligibility, and modeling insight of Meta are updated, and the settings
+// recorded as Meta["stratification"].
+func (h *Histogram) Stratify(mode string, minSample int) {
+	if mode == "" {
+		mode = StratificationAuto
+	}
+	if minSample <= 0 {
+		minSample = StratificationMinVolume
+	}
+	if h.Meta == nil {
+		h.Meta = make(map[string]any)
+	}
+	decisions, _ := h.Meta["stratification_decisions"].([]StratificationDecision)
+	eligible := make(map[string]bool)
+	settings := StratificationSettings{Mode: mode, MinSample: minSample, Stratified: make([]string, 0)}
+	for i := range decisions {
+		decisions[i].decide(mode, minSample)
+		if decisions[i].Eligible {
+			eligible[decisions[i].Type] = true
+			settings.Stratified = append(settings.Stratified, decisions[i].Type)
+		}
+	}
+	h.Meta["stratification_eligible"] = eligible
+	h.Meta["modeling_insight"] = modelingInsight(len(eligible))
+	h.Meta["stratification"] = settings
+}
+
+// poolStreams samples every type of h from the pooled counts, for samples
+// whose per-type counts are not kept.
+func (h *Histogram) poolStreams() {
+	delete(h.Meta, "stratification_eligible")
+	if settings, ok := h.Meta["stratification"].(StratificationSettings); ok {
+		settings.Stratified = make([]string, 0)
+		h.Meta["stratification"] = settings
+	}
+}
+
+// HistogramSummary describes the daily counts of a histogram and the items
+// that went into them.
+type HistogramSummary struct {
+	Days             int                      `json:"days"`      // sampled days (working days with a calendar)
+	ZeroDays         int                      `json:"zero_days"` // sampled days without a delivery
+	Delivered        int                      `json:"delivered"` // items in the sampled counts
+	MeanPerDay       float64                  `json:"mean_per_day"`
+	MaxPerDay        int                      `json:"max_per_day"`
+	RecentPerDay     float64                  `json:"recent_per_day"` // last 30 days
+	IssuesTotal      int                      `json:"issues_total"`
+	IssuesAnalyzed   int                      `json:"issues_analyzed"`
+	DroppedByOutcome int                      `json:"dropped_by_outcome"`
+	DroppedByWindow  int                      `json:"dropped_by_window"`
+	TypeCounts       map[string]int           `json:"type_counts"`
+	TypeDistribution map[string]float64       `json:"type_distribution"`
+	Stratification   []StratificationDecision `json:"stratification_decisions,omitempty"`
+	Strata           *StratificationSettings  `json:"stratification,omitempty"` // Mode and threshold of the decisions
+	Outliers         *stats.OutlierTrim       `json:"outliers,omitempty"`
+	External         *ExternalMerge           `json:"external,omitempty"` // Imported days in the sample
+}
+
+// BaselineHistogram builds the daily throughput histogram the crude engine
+// samples for req: issue types aliased, types stratified per req.Stratification,
+// imported throughput merged, non-working days folded into working days,
+// outliers trimmed per req.Outliers, and sampled per req.Granularity.
+func BaselineHistogram(req ForecastRequest) *Histogram {
+	req = req.withTypeAliases()
+	h := NewHistogram(req.Finished, req.WindowStart, req.WindowEnd, req.IssueTypes, req.WorkflowMappings, req.Resolutions, req.Outcomes)
+	h.Stratify(req.Stratification, req.MinStratumSize)
+	start := h.MergeExternal(req.WindowStart, req.External)
+	h.RestrictToWorkingDays(req.Calendar, start)
+	h.TrimOutliers(req.Outliers)
+	h.SetGranularity(req.Granularity, req.Calendar)
+	return h
+}
+
+// Summary describes the daily counts of h and the Meta of NewHistogram.
+func (h *Histogram) Summary() HistogramSummary {
+	sum := HistogramSummary{Days: len(h.Counts)}
+	for _, c := range h.Counts {
+		sum.Delivered += c
+		sum.MaxPerDay = max(sum.MaxPerDay, c)
+		if c == 0 {
+			sum.ZeroDays++
+		}
+	}
+	if sum.Days > 0 {
+		sum.MeanPerDay = math.Round(float64(sum.Delivered)/float64(sum.Days)*1000) / 1000
+	}
+	recent, _ := h.Meta["throughput_recent"].(float64)
+	sum.RecentPerDay = math.Round(recent*1000) / 1000
+	sum.IssuesTotal, _ = h.Meta["issues_total"].(int)
+	sum.IssuesAnalyzed, _ = h.Meta["issues_analyzed"].(int)
+	sum.DroppedByOutcome, _ = h.Meta["dropped_by_outcome"].(int)
+	sum.DroppedByWindow, _ = h.Meta["dropped_by_window"].(int)
+	sum.TypeCounts, _ = h.Meta["type_counts"].(map[string]int)
+	sum.TypeDistribution, _ = h.Meta["type_distribution"].(map[string]float64)
+	sum.Stratification, _ = h.Meta["stratification_decisions"].([]StratificationDecision)
+	if settings, ok := h.Meta["stratification"].(StratificationSettings); ok {
+		sum.Strata = &settings
+	}
+	if trim, ok := h.Meta["outliers"].(stats.OutlierTrim); ok {
+		sum.Outliers = &trim
+	}
+	if merge, ok := h.Meta["external_throughput"].(ExternalMerge); ok {
+		sum.External = &merge
+	}
+	return sum
+}
+
+// NewSprintHistogram creates a histogram whose time unit is one sprint: each
+// count is the throughput of one closed sprint. Engines sample it exactly like
+// daily counts, so durations come out in sprints and scope horizons are
+// expressed in sprints.
+func NewSprintHistogram(samples []int) *Histogram {
+	return &Histogram{
+		Counts:           slices.Clone(samples),
+		StratifiedCounts: make(map[string][]int),
+		Meta: map[string]any{
+			"modeling_insight": "Sprint-sampled: each simulated step draws the throughput of one historical sprint.",
+		},
+	}
+}
+
 // Resample adjusts the histogram's effective mean throughput by duplicating or
 // removing days. factor > 1.0 duplicates random days (biased toward higher counts)
 // to scale up; factor < 1.0 removes random days to scale down. This preserves
@@ -237,3 +362,43 @@ func (h *Histogram) Resample(factor float64, rng *rand.Rand) {
 		}
 	}
 }
+
+// TrimOutliers applies policy to the daily counts: winsorize caps days above
+// the P99 at the P99 rounded down, iqr drops the days outside the fences.
+// Engines sample each stream independently, so the per-type counts are
+// trimmed on their own. The report, which counts the items of the pooled
+// counts that were capped away or dropped, is recorded as Meta["outliers"].
+func (h *Histogram) TrimOutliers(policy stats.OutlierPolicy) stats.OutlierTrim {
+	trim := func(counts []int) ([]int, stats.OutlierTrim) {
+		values := make([]float64, len(counts))
+		for i, c := range counts {
+			values[i] = float64(c)
+		}
+		trimmed, _, report := stats.TrimOutliers(values, policy)
+		out := make([]int, len(trimmed))
+		report.Trimmed = 0
+		for _, c := range counts {
+			report.Trimmed += c
+		}
+		for i, v := range trimmed {
+			out[i] = int(math.Floor(v))
+			report.Trimmed -= out[i]
+		}
+		return out, report
+	}
+
+	if policy == stats.OutliersNone || policy == "" || len(h.Counts) == 0 {
+		return stats.OutlierTrim{Policy: stats.OutliersNone}
+	}
+	var report stats.OutlierTrim
+	h.Counts, report = trim(h.Counts)
+	for t, counts := range h.StratifiedCounts {
+		h.StratifiedCounts[t], _ = trim(counts)
+	}
+	if h.Meta == nil {
+		h.Meta = make(map[string]any)
+	}
+	h.Meta["days_in_sample"] = len(h.Counts)
+	h.Meta["outliers"] = report
+	return report
+}
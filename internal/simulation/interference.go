@@ -0,0 +1,264 @@
+package simulation
+
+import (
+	"cmp"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"mcs-mcp/internal/stats"
+)
+
+// InterferencePair is a negative correlation between the daily throughput of
+// two streams: on days the taxer delivers more, the taxed stream delivers
+// less. The taxer is the more volatile stream of the two.
+type InterferencePair struct {
+	Taxer       string  `json:"taxer"`
+	Taxed       string  `json:"taxed"`
+	Correlation float64 `json:"correlation"` // Pearson r over the delivery days
+	Strength    string  `json:"strength"`    // Cohen's bands of |r|: weak, moderate, strong
+	PValue      float64 `json:"p_value"`     // Two-sided, Fisher z approximation
+	Significant bool    `json:"significant"`
+	// ModeledByForecasts is set when r is below DependencyCorrelationThreshold:
+	// stratified forecasts then apply the capacity tax to the pair.
+	ModeledByForecasts bool `json:"modeled_by_forecasts"`
+	// Slope is the change in taxed items per day for each additional taxer item
+	// (least-squares regression of taxed on taxer).
+	Slope          float64             `json:"slope"`
+	TaxedWhenBusy  float64             `json:"taxed_per_day_when_taxer_busy"`  // Mean on days the taxer is above its mean
+	TaxedWhenQuiet float64             `json:"taxed_per_day_when_taxer_quiet"` // Mean on the other delivery days
+	Impact         *InterferenceImpact `json:"impact,omitempty"`
+}
+
+// InterferenceImpact is the simulated delivery of the taxed stream over a
+// horizon, as sampled (baseline) and with the taxer volume scaled by
+// TaxerFactor (scenario). Both sample the same days.
+type InterferenceImpact struct {
+	HorizonDays int     `json:"horizon_days"`
+	TaxerFactor float64 `json:"taxer_factor"`
+	BaselineP50 int     `json:"baseline_p50"`
+	BaselineP85 int     `json:"baseline_p85"` // Taxed items delivered in at least 85% of trials
+	ScenarioP50 int     `json:"scenario_p50"`
+	ScenarioP85 int     `json:"scenario_p85"`
+	ChangeP50   float64 `json:"change_p50"` // Relative change of the P50, e.g. -0.12
+}
+
+// InterferenceResult reports the interfering pairs among the streams of a
+// dimension, strongest first.
+type InterferenceResult struct {
+	DeliveryDays int                `json:"delivery_days"` // Days with at least one delivery: the correlation sample
+	Streams      []string           `json:"streams"`       // Streams correlated
+	Sparse       []string           `json:"sparse,omitempty"`
+	Pairs        []InterferencePair `json:"pairs"`
+}
+
+// Round rounds all numeric fields for output compactness.
+func (r *InterferenceResult) Round() {
+	for i := range r.Pairs {
+		p := &r.Pairs[i]
+		p.Correlation = stats.Round2(p.Correlation)
+		p.PValue = stats.RoundTo(p.PValue, 4)
+		p.Slope = stats.Round2(p.Slope)
+		p.TaxedWhenBusy = stats.Round2(p.TaxedWhenBusy)
+		p.TaxedWhenQuiet = stats.Round2(p.TaxedWhenQuiet)
+		if p.Impact != nil {
+			p.Impact.ChangeP50 = stats.Round2(p.Impact.ChangeP50)
+		}
+	}
+}
+
+// AnalyzeInterference correlates the daily throughput of each pair of streams.
+// pooled is the daily throughput of all streams; days without any delivery
+// (weekends, holidays) are left out of the correlation so they do not mask a
+// crowding-out effect. Streams with fewer than InterferenceMinItems items are
+// skipped as sparse. For significant pairs the taxed stream is simulated over
+// horizonDays, with the taxer volume scaled by taxerFactor. A seed of 0 seeds
+// from the clock.
+func AnalyzeInterference(pooled []int, streams map[string][]int, taxerFactor float64, horizonDays int, seed int64) InterferenceResult {
+	res := InterferenceResult{Streams: []string{}, Pairs: []InterferencePair{}}
+	var active []int
+	for d, c := range pooled {
+		if c > 0 {
+			active = append(active, d)
+		}
+	}
+	res.DeliveryDays = len(active)
+
+	names := make([]string, 0, len(streams))
+	for name := range streams {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	series := make(map[string][]int)
+	for _, name := range names {
+		total := 0
+		for _, c := range streams[name] {
+			total += c
+		}
+		if total < InterferenceMinItems || len(streams[name]) != len(pooled) {
+			res.Sparse = append(res.Sparse, name)
+			continue
+		}
+		s := make([]int, len(active))
+		for i, d := range active {
+			s[i] = streams[name][d]
+		}
+		series[name] = s
+		res.Streams = append(res.Streams, name)
+	}
+	if len(active) < InterferenceMinDays {
+		return res
+	}
+
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	rng := rand.New(rand.NewPCG(uint64(seed), 0))
+
+	for i, a := range res.Streams {
+		for _, b := range res.Streams[i+1:] {
+			r := CalculateCorrelation(series[a], series[b])
+			if r > -InterferenceMinEffect {
+				continue
+			}
+			taxer, taxed := a, b
+			if variation(series[b]) > variation(series[a]) {
+				taxer, taxed = b, a
+			}
+			pair := InterferencePair{
+				Taxer:              taxer,
+				Taxed:              taxed,
+				Correlation:        r,
+				Strength:           effectStrength(r),
+				PValue:             correlationPValue(r, len(active)),
+				ModeledByForecasts: r < DependencyCorrelationThreshold,
+			}
+			pair.Significant = pair.PValue < InterferenceSignificance
+			pair.Slope = regressionSlope(series[taxer], series[taxed])
+			pair.TaxedWhenBusy, pair.TaxedWhenQuiet = conditionalMeans(series[taxer], series[taxed])
+			if pair.Significant && horizonDays > 0 {
+				pair.Impact = simulateInterference(streams[taxer], streams[taxed], pair.Slope, taxerFactor, horizonDays, rng)
+			}
+			res.Pairs = append(res.Pairs, pair)
+		}
+	}
+	slices.SortStableFunc(res.Pairs, func(x, y InterferencePair) int { return cmp.Compare(x.Correlation, y.Correlation) })
+	return res
+}
+
+// simulateInterference bootstraps the taxed delivery over horizon calendar
+// days of the window. Each sampled day shifts the taxed count by
+// slope·(factor−1)·taxer, floored at zero.
+func simulateInterference(taxer, taxed []int, slope, factor float64, horizon int, rng *rand.Rand) *InterferenceImpact {
+	baseline := make([]float64, InterferenceTrials)
+	scenario := make([]float64, InterferenceTrials)
+	for t := range InterferenceTrials {
+		for range horizon {
+			d := rng.IntN(len(taxed))
+			baseline[t] += float64(taxed[d])
+			scenario[t] += max(0, float64(taxed[d])+slope*(factor-1)*float64(taxer[d]))
+		}
+	}
+	slices.Sort(baseline)
+	slices.Sort(scenario)
+
+	impact := &InterferenceImpact{
+		HorizonDays: horizon,
+		TaxerFactor: factor,
+		BaselineP50: int(math.Round(stats.CalculatePercentile(baseline, 0.50))),
+		BaselineP85: int(math.Round(stats.CalculatePercentile(baseline, 0.15))),
+		ScenarioP50: int(math.Round(stats.CalculatePercentile(scenario, 0.50))),
+		ScenarioP85: int(math.Round(stats.CalculatePercentile(scenario, 0.15))),
+	}
+	if impact.BaselineP50 > 0 {
+		impact.ChangeP50 = float64(impact.ScenarioP50-impact.BaselineP50) / float64(impact.BaselineP50)
+	}
+	return impact
+}
+
+// effectStrength classifies |r| into Cohen's bands.
+func effectStrength(r float64) string {
+	switch r = math.Abs(r); {
+	case r >= 0.5:
+		return "strong"
+	case r >= 0.3:
+		return "moderate"
+	case r >= 0.1:
+		return "weak"
+	}
+	return "negligible"
+}
+
+// correlationPValue is the two-sided p-value of a Pearson r over n samples,
+// from the Fisher z transform.
+func correlationPValue(r float64, n int) float64 {
+	if n <= 3 {
+		return 1
+	}
+	r = max(-0.999999, min(0.999999, r))
+	z := math.Atanh(r) * math.Sqrt(float64(n-3))
+	return math.Erfc(math.Abs(z) / math.Sqrt2)
+}
+
+// variation is the coefficient of variation of a series.
+func variation(s []int) float64 {
+	mean, sd := meanStdDev(s)
+	if mean == 0 {
+		return 0
+	}
+	return sd / mean
+}
+
+func meanStdDev(s []int) (float64, float64) {
+	if len(s) == 0 {
+		return 0, 0
+	}
+	sum := 0.0
+	for _, v := range s {
+		sum += float64(v)
+	}
+	mean := sum / float64(len(s))
+	ss := 0.0
+	for _, v := range s {
+		ss += (float64(v) - mean) * (float64(v) - mean)
+	}
+	return mean, math.Sqrt(ss / float64(len(s)))
+}
+
+// regressionSlope is the least-squares slope of y on x.
+func regressionSlope(x, y []int) float64 {
+	mx, sx := meanStdDev(x)
+	if sx == 0 {
+		return 0
+	}
+	my, _ := meanStdDev(y)
+	cov := 0.0
+	for i := range x {
+		cov += (float64(x[i]) - mx) * (float64(y[i]) - my)
+	}
+	return cov / float64(len(x)) / (sx * sx)
+}
+
+// conditionalMeans returns the mean of y on days x is above its mean, and on
+// the other days.
+func conditionalMeans(x, y []int) (busy, quiet float64) {
+	mx, _ := meanStdDev(x)
+	var nBusy, nQuiet int
+	for i := range x {
+		if float64(x[i]) > mx {
+			busy += float64(y[i])
+			nBusy++
+		} else {
+			quiet += float64(y[i])
+			nQuiet++
+		}
+	}
+	if nBusy > 0 {
+		busy /= float64(nBusy)
+	}
+	if nQuiet > 0 {
+		quiet /= float64(nQuiet)
+	}
+	return busy, quiet
+}
@@ -0,0 +1,80 @@
+package simulation
+
+import (
+	"testing"
+)
+
+func TestAnalyzeInterference(t *testing.T) {
+	// 60 days: Bugs arrive in bursts that crowd out Stories; Tasks are flat;
+	// every seventh day nothing is delivered.
+	const days = 60
+	pooled := make([]int, days)
+	streams := map[string][]int{
+		"Bug":   make([]int, days),
+		"Story": make([]int, days),
+		"Task":  make([]int, days),
+		"Spike": make([]int, days),
+	}
+	for d := range days {
+		if d%7 == 6 {
+			continue
+		}
+		bugs := []int{0, 0, 1, 4, 0, 3}[d%6]
+		streams["Bug"][d] = bugs
+		streams["Story"][d] = max(0, 4-bugs)
+		streams["Task"][d] = 1
+		pooled[d] = bugs + streams["Story"][d] + 1
+	}
+	streams["Spike"][10] = 1
+
+	res := AnalyzeInterference(pooled, streams, 2, 20, 42)
+
+	if res.DeliveryDays != days-days/7 {
+		t.Errorf("Expected days without delivery to be left out, got %d delivery days", res.DeliveryDays)
+	}
+	if len(res.Sparse) != 1 || res.Sparse[0] != "Spike" {
+		t.Errorf("Expected Spike to be skipped as sparse, got %v", res.Sparse)
+	}
+	if len(res.Pairs) != 1 {
+		t.Fatalf("Expected only Bug/Story to interfere, got %+v", res.Pairs)
+	}
+	p := res.Pairs[0]
+	if p.Taxer != "Bug" || p.Taxed != "Story" {
+		t.Errorf("Expected the bursty Bug stream as taxer, got %s taxing %s", p.Taxer, p.Taxed)
+	}
+	if !p.Significant || p.Strength != "strong" || !p.ModeledByForecasts {
+		t.Errorf("Expected a strong significant dependency, got %+v", p)
+	}
+	if p.Slope >= 0 || p.TaxedWhenBusy >= p.TaxedWhenQuiet {
+		t.Errorf("Expected Stories to drop on busy Bug days, got slope %.2f, busy %.2f, quiet %.2f", p.Slope, p.TaxedWhenBusy, p.TaxedWhenQuiet)
+	}
+	if p.Impact == nil {
+		t.Fatal("Expected the impact of doubling Bugs to be simulated")
+	}
+	if p.Impact.ScenarioP50 >= p.Impact.BaselineP50 || p.Impact.ChangeP50 >= 0 {
+		t.Errorf("Expected fewer Stories with twice the Bugs, got %+v", p.Impact)
+	}
+	if p.Impact.BaselineP85 > p.Impact.BaselineP50 {
+		t.Errorf("Expected the P85 to be the more conservative count, got %+v", p.Impact)
+	}
+}
+
+func TestAnalyzeInterference_TooFewDays(t *testing.T) {
+	pooled := []int{3, 3, 3}
+	streams := map[string][]int{"Bug": {10, 0, 0}, "Story": {0, 10, 0}}
+
+	res := AnalyzeInterference(pooled, streams, 1.5, 30, 1)
+
+	if len(res.Pairs) != 0 || len(res.Streams) != 2 {
+		t.Errorf("Expected streams but no pairs below %d delivery days, got %+v", InterferenceMinDays, res)
+	}
+}
+
+func TestCorrelationPValue(t *testing.T) {
+	if p := correlationPValue(-0.5, 50); p > 0.001 {
+		t.Errorf("Expected r=-0.5 over 50 days to be significant, got p=%.4f", p)
+	}
+	if p := correlationPValue(-0.1, 20); p < 0.5 {
+		t.Errorf("Expected r=-0.1 over 20 days not to be significant, got p=%.4f", p)
+	}
+}
@@ -0,0 +1,217 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// Throughput sampling modes.
+const (
+	// SamplingEmpirical bootstraps from the observed daily counts (default).
+	SamplingEmpirical = "empirical"
+	// SamplingParametric samples from a Weibull or lognormal distribution
+	// fitted to the observed daily counts.
+	SamplingParametric = "parametric"
+)
+
+// Fitted distribution families.
+const (
+	FamilyWeibull   = "weibull"
+	FamilyLognormal = "lognormal"
+)
+
+const (
+	// ParametricPoolSize is the number of synthetic days drawn from a fitted
+	// distribution. It only needs to be large enough for the engines'
+	// bootstrap to see a smooth distribution.
+	ParametricPoolSize = 5000
+	// ParametricMinSamples is the number of days with deliveries a fit needs.
+	ParametricMinSamples = 3
+	// SparseSampleSize is the number of delivered items (or sprints) below
+	// which bootstrapped percentiles get jagged and parametric sampling helps.
+	SparseSampleSize = 30
+)
+
+// ThroughputFit is a parametric model of daily throughput: a share of days
+// without deliveries and a continuous distribution over the positive counts,
+// rounded to whole items (at least 1) when sampled.
+type ThroughputFit struct {
+	Family        string  `json:"family"`
+	Shape         float64 `json:"shape,omitempty"` // Weibull k
+	Scale         float64 `json:"scale,omitempty"` // Weibull λ
+	Mu            float64 `json:"mu,omitempty"`    // Lognormal μ of ln(x)
+	Sigma         float64 `json:"sigma,omitempty"` // Lognormal σ of ln(x)
+	ZeroShare     float64 `json:"zero_share"`      // Share of days without deliveries
+	LogLikelihood float64 `json:"log_likelihood"`  // Of the positive counts; the higher of both families wins
+	Days          int     `json:"days"`            // Observed days the fit is based on
+	EmpiricalMean float64 `json:"empirical_mean"`
+	FittedMean    float64 `json:"fitted_mean"` // Mean daily throughput of the synthetic pool
+}
+
+// FitThroughput fits both families to the positive daily counts and keeps the
+// one with the higher log-likelihood (both have two parameters).
+func FitThroughput(counts []int) (ThroughputFit, error) {
+	var positive []float64
+	total := 0
+	for _, c := range counts {
+		total += c
+		if c > 0 {
+			positive = append(positive, float64(c))
+		}
+	}
+	if len(positive) < ParametricMinSamples {
+		return ThroughputFit{}, fmt.Errorf("only %d days with deliveries; a parametric fit needs at least %d", len(positive), ParametricMinSamples)
+	}
+	if slices.Min(positive) == slices.Max(positive) {
+		return ThroughputFit{}, fmt.Errorf("every day with deliveries delivered %.0f items; there is no spread to fit", positive[0])
+	}
+
+	k, lambda := fitWeibull(positive)
+	mu, sigma := fitLognormal(positive)
+	fit := ThroughputFit{
+		Family:        FamilyWeibull,
+		Shape:         k,
+		Scale:         lambda,
+		LogLikelihood: weibullLogLikelihood(positive, k, lambda),
+	}
+	if ll := lognormalLogLikelihood(positive, mu, sigma); ll > fit.LogLikelihood {
+		fit = ThroughputFit{Family: FamilyLognormal, Mu: mu, Sigma: sigma, LogLikelihood: ll}
+	}
+	fit.ZeroShare = 1 - float64(len(positive))/float64(len(counts))
+	fit.Days = len(counts)
+	fit.EmpiricalMean = float64(total) / float64(len(counts))
+	return fit, nil
+}
+
+// Sample draws one synthetic day.
+func (f ThroughputFit) Sample(rng *rand.Rand) int {
+	if rng.Float64() < f.ZeroShare {
+		return 0
+	}
+	var x float64
+	if f.Family == FamilyWeibull {
+		x = f.Scale * math.Pow(-math.Log(1-rng.Float64()), 1/f.Shape)
+	} else {
+		x = math.Exp(f.Mu + f.Sigma*rng.NormFloat64())
+	}
+	return max(1, int(math.Round(x)))
+}
+
+// Round rounds the fit parameters for output.
+func (f *ThroughputFit) Round() {
+	roundFields(&f.Shape, &f.Scale, &f.Mu, &f.Sigma, &f.ZeroShare, &f.LogLikelihood, &f.EmpiricalMean, &f.FittedMean)
+}
+
+// Parametrize replaces the pooled daily counts with ParametricPoolSize days
+// drawn from a distribution fitted to them. Per-type streams cannot be
+// fitted independently from sparse data, so stratification is switched off.
+func (h *Histogram) Parametrize(rng *rand.Rand) (ThroughputFit, error) {
+	fit, err := FitThroughput(h.Counts)
+	if err != nil {
+		return ThroughputFit{}, err
+	}
+	pool := make([]int, ParametricPoolSize)
+	total := 0
+	for i := range pool {
+		pool[i] = fit.Sample(rng)
+		total += pool[i]
+	}
+	fit.FittedMean = float64(total) / float64(len(pool))
+	fit.Round()
+
+	h.Counts = pool
+	if h.Meta == nil {
+		h.Meta = make(map[string]any)
+	}
+	h.poolStreams()
+	h.Meta["sampling"] = SamplingParametric
+	h.Meta["throughput_fit"] = fit
+	return fit, nil
+}
+
+// ApplySampling switches h to the requested sampling mode. It returns a
+// warning when parametric sampling was requested but no distribution could
+// be fitted; h then stays empirical.
+func ApplySampling(h *Histogram, sampling string, seed int64) string {
+	if sampling != SamplingParametric {
+		return ""
+	}
+	rng := rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0))
+	if seed != 0 {
+		rng = rand.New(rand.NewPCG(uint64(seed), 2))
+	}
+	if _, err := h.Parametrize(rng); err != nil {
+		return fmt.Sprintf("PARAMETRIC SAMPLING UNAVAILABLE: %v. Sampled the observed daily throughput instead.", err)
+	}
+	return ""
+}
+
+// fitWeibull returns the maximum-likelihood shape k and scale λ. The shape
+// solves 1/k + mean(ln x) − Σxᵏln x / Σxᵏ = 0, found by bisection.
+func fitWeibull(xs []float64) (k, lambda float64) {
+	meanLog := 0.0
+	for _, x := range xs {
+		meanLog += math.Log(x)
+	}
+	meanLog /= float64(len(xs))
+
+	score := func(k float64) float64 {
+		var sumK, sumKLog float64
+		for _, x := range xs {
+			xk := math.Pow(x, k)
+			sumK += xk
+			sumKLog += xk * math.Log(x)
+		}
+		return 1/k + meanLog - sumKLog/sumK
+	}
+	lo, hi := 0.01, 100.0
+	for range 100 {
+		mid := (lo + hi) / 2
+		if score(mid) > 0 {
+			lo = mid
+		} else {
+			hi = mid
+		}
+	}
+	k = (lo + hi) / 2
+
+	sumK := 0.0
+	for _, x := range xs {
+		sumK += math.Pow(x, k)
+	}
+	return k, math.Pow(sumK/float64(len(xs)), 1/k)
+}
+
+// fitLognormal returns the maximum-likelihood μ and σ of ln(x).
+func fitLognormal(xs []float64) (mu, sigma float64) {
+	for _, x := range xs {
+		mu += math.Log(x)
+	}
+	mu /= float64(len(xs))
+	for _, x := range xs {
+		d := math.Log(x) - mu
+		sigma += d * d
+	}
+	return mu, math.Sqrt(sigma / float64(len(xs)))
+}
+
+func weibullLogLikelihood(xs []float64, k, lambda float64) float64 {
+	ll := 0.0
+	for _, x := range xs {
+		z := x / lambda
+		ll += math.Log(k/lambda) + (k-1)*math.Log(z) - math.Pow(z, k)
+	}
+	return ll
+}
+
+func lognormalLogLikelihood(xs []float64, mu, sigma float64) float64 {
+	ll := 0.0
+	for _, x := range xs {
+		d := math.Log(x) - mu
+		ll += -math.Log(x*sigma*math.Sqrt(2*math.Pi)) - d*d/(2*sigma*sigma)
+	}
+	return ll
+}
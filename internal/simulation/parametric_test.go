@@ -0,0 +1,80 @@
+package simulation
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+)
+
+func TestFitWeibull_RecoversParameters(t *testing.T) {
+	rng := rand.New(rand.NewPCG(42, 0))
+	xs := make([]float64, 5000)
+	for i := range xs {
+		xs[i] = 3 * math.Pow(-math.Log(1-rng.Float64()), 1/1.5)
+	}
+	k, lambda := fitWeibull(xs)
+	if math.Abs(k-1.5) > 0.1 || math.Abs(lambda-3) > 0.15 {
+		t.Errorf("Expected k≈1.5 and λ≈3, got %.3f and %.3f", k, lambda)
+	}
+	if weibullLogLikelihood(xs, k, lambda) <= lognormalLogLikelihood(xs, 0, 1) {
+		t.Errorf("Expected the Weibull fit to beat an arbitrary lognormal")
+	}
+}
+
+func TestFitLognormal_RecoversParameters(t *testing.T) {
+	rng := rand.New(rand.NewPCG(42, 0))
+	xs := make([]float64, 5000)
+	for i := range xs {
+		xs[i] = math.Exp(0.5 + 0.8*rng.NormFloat64())
+	}
+	mu, sigma := fitLognormal(xs)
+	if math.Abs(mu-0.5) > 0.05 || math.Abs(sigma-0.8) > 0.05 {
+		t.Errorf("Expected μ≈0.5 and σ≈0.8, got %.3f and %.3f", mu, sigma)
+	}
+}
+
+func TestFitThroughput(t *testing.T) {
+	if _, err := FitThroughput([]int{0, 1, 0, 2, 0}); err == nil {
+		t.Errorf("Expected an error with two days of deliveries")
+	}
+	if _, err := FitThroughput([]int{1, 0, 1, 1, 0, 1}); err == nil {
+		t.Errorf("Expected an error when every delivery day has the same count")
+	}
+
+	counts := []int{0, 0, 1, 0, 2, 1, 0, 0, 3, 1, 0, 1, 0, 0, 2, 0, 1, 0, 0, 5}
+	fit, err := FitThroughput(counts)
+	if err != nil {
+		t.Fatalf("FitThroughput: %v", err)
+	}
+	if fit.Family != FamilyWeibull && fit.Family != FamilyLognormal {
+		t.Errorf("Unexpected family %q", fit.Family)
+	}
+	if fit.ZeroShare != 0.55 || fit.Days != 20 || fit.EmpiricalMean != 0.85 {
+		t.Errorf("Expected zero share 0.55 over 20 days with mean 0.85, got %+v", fit)
+	}
+}
+
+func TestHistogramParametrize(t *testing.T) {
+	h := &Histogram{
+		Counts: []int{0, 0, 1, 0, 2, 1, 0, 0, 3, 1, 0, 1, 0, 0, 2, 0, 1, 0, 0, 5},
+		Meta:   map[string]any{"stratification_eligible": map[string]bool{"Story": true}},
+	}
+	fit, err := h.Parametrize(rand.New(rand.NewPCG(42, 0)))
+	if err != nil {
+		t.Fatalf("Parametrize: %v", err)
+	}
+	if len(h.Counts) != ParametricPoolSize {
+		t.Errorf("Expected a pool of %d synthetic days, got %d", ParametricPoolSize, len(h.Counts))
+	}
+	if math.Abs(fit.FittedMean-fit.EmpiricalMean)/fit.EmpiricalMean > 0.2 {
+		t.Errorf("Expected the synthetic mean within 20%% of the observed mean, got %v vs %v", fit.FittedMean, fit.EmpiricalMean)
+	}
+	if _, ok := h.Meta["stratification_eligible"]; ok || h.Meta["sampling"] != SamplingParametric {
+		t.Errorf("Expected stratification off and sampling recorded, got %v", h.Meta)
+	}
+
+	sparse := &Histogram{Counts: []int{0, 1, 0}}
+	if w := ApplySampling(sparse, SamplingParametric, 42); w == "" || len(sparse.Counts) != 3 {
+		t.Errorf("Expected a warning and the empirical counts when no fit is possible, got %q", w)
+	}
+}
@@ -0,0 +1,142 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/stats"
+)
+
+// NewPointsHistogram creates a daily histogram of completed estimate points
+// (e.g. story points): each day counts the estimates of the delivered issues
+// resolved on it, so engines sample points instead of items and forecasts
+// come out in points. The running total is rounded rather than each day, so
+// fractional estimates keep their sum. Unestimated items deliver nothing and
+// are counted in issues_unestimated. The histogram is pooled, not stratified.
+func NewPointsHistogram(issues []jira.Issue, startTime, endTime time.Time, issueTypes []string) *Histogram {
+	days := stats.CalendarDaysBetween(startTime, endTime) + 1
+	if days <= 0 {
+		return &Histogram{Counts: []int{0}, StratifiedCounts: make(map[string][]int), Meta: map[string]any{}}
+	}
+
+	points := make([]float64, days)
+	var estimates []float64
+	unestimated := 0
+	for _, issue := range issues {
+		if !stats.IsDelivered(issue) || (len(issueTypes) > 0 && !slices.Contains(issueTypes, issue.IssueType)) {
+			continue
+		}
+		resDate := issue.Updated
+		if issue.ResolutionDate != nil {
+			resDate = *issue.ResolutionDate
+		}
+		dayIdx := stats.CalendarDaysBetween(startTime, resDate)
+		if resDate.IsZero() || dayIdx < 0 || dayIdx >= days {
+			continue
+		}
+		if issue.Estimate == nil {
+			unestimated++
+			continue
+		}
+		points[dayIdx] += *issue.Estimate
+		estimates = append(estimates, *issue.Estimate)
+	}
+
+	counts := make([]int, days)
+	total, recent, rounded := 0.0, 0.0, 0
+	recentDays := min(days, 30)
+	for i, p := range points {
+		total += p
+		if i >= days-recentDays {
+			recent += p
+		}
+		next := int(math.Round(total))
+		counts[i] = next - rounded
+		rounded = next
+	}
+
+	slices.Sort(estimates)
+	median := 0.0
+	if len(estimates) > 0 {
+		median = stats.CalculatePercentile(estimates, 0.50)
+	}
+	return &Histogram{
+		Counts:           counts,
+		StratifiedCounts: make(map[string][]int),
+		Meta: map[string]any{
+			"issues_analyzed":    len(estimates),
+			"issues_unestimated": unestimated,
+			"points_total":       stats.Round2(total),
+			"median_estimate":    stats.Round2(median),
+			"days_in_sample":     days,
+			"throughput_overall": total / float64(days),
+			"throughput_recent":  recent / float64(recentDays),
+			"modeling_insight":   "Points-sampled: each simulated day draws the estimate points delivered on one historical day.",
+		},
+	}
+}
+
+// MedianEstimate returns the median estimate of the delivered issues that
+// have one, or 0 if none has.
+func MedianEstimate(issues []jira.Issue) float64 {
+	var estimates []float64
+	for _, issue := range issues {
+		if stats.IsDelivered(issue) && issue.Estimate != nil {
+			estimates = append(estimates, *issue.Estimate)
+		}
+	}
+	if len(estimates) == 0 {
+		return 0
+	}
+	slices.Sort(estimates)
+	return stats.CalculatePercentile(estimates, 0.50)
+}
+
+// RunPointsForecast forecasts in estimate points: scope mode returns the
+// points delivered within req.TargetDays, duration mode the days needed to
+// deliver scopePoints. It follows the pooled path of the crude engine
+// (calendar, sampling, and capacity apply alike); targets, mix overrides,
+// and arrivals count items and are ignored.
+func RunPointsForecast(ctx context.Context, req ForecastRequest, scopePoints int) (Result, error) {
+	h := NewPointsHistogram(req.Finished, req.WindowStart, req.WindowEnd, req.IssueTypes)
+	if n, _ := h.Meta["issues_analyzed"].(int); n == 0 {
+		return Result{}, fmt.Errorf("no delivered item in the sampling window has an estimate")
+	}
+	h.RestrictToWorkingDays(req.Calendar, req.WindowStart)
+	h.TrimOutliers(req.Outliers)
+	samplingWarning := ApplySampling(h, req.Sampling, req.SimulationSeed)
+
+	engine := NewEngine(h)
+	engine.SetContext(ctx)
+	if req.SimulationSeed != 0 {
+		engine.SetSeed(req.SimulationSeed)
+	}
+	if req.Calendar != nil {
+		engine.SetCalendar(req.Calendar, req.Clock)
+	}
+	engine.setCapacityFrom(req.Capacity, req.Clock)
+
+	var res Result
+	switch req.Mode {
+	case "scope":
+		res = engine.RunScopeSimulation(req.TargetDays, DefaultTrials)
+	case "duration":
+		if scopePoints <= 0 {
+			return Result{}, fmt.Errorf("no estimate points to forecast")
+		}
+		res = engine.RunDurationSimulation(scopePoints, DefaultTrials)
+	default:
+		return Result{}, fmt.Errorf("unknown simulation mode: %q", req.Mode)
+	}
+	if err := engine.Err(); err != nil {
+		return Result{}, fmt.Errorf("simulation cancelled: %w", err)
+	}
+	if samplingWarning != "" {
+		res.Warnings = append(res.Warnings, samplingWarning)
+	}
+	return res, nil
+}
@@ -0,0 +1,73 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestNewPointsHistogram(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	delivered := func(day int, estimate *float64, issueType string) jira.Issue {
+		resolved := start.AddDate(0, 0, day).Add(10 * time.Hour)
+		return jira.Issue{IssueType: issueType, Outcome: "delivered", ResolutionDate: &resolved, Estimate: estimate}
+	}
+	pts := func(v float64) *float64 { return &v }
+	issues := []jira.Issue{
+		delivered(0, pts(0.5), "Story"),
+		delivered(1, pts(0.5), "Story"),
+		delivered(1, pts(3), "Story"),
+		delivered(2, nil, "Story"),
+		delivered(3, pts(8), "Bug"),
+		{IssueType: "Story", Outcome: "abandoned", Estimate: pts(13)},
+	}
+
+	h := NewPointsHistogram(issues, start, start.AddDate(0, 0, 3), nil)
+	want := []int{1, 3, 0, 8} // running total 0.5, 4, 4, 12 rounded half away from zero
+	for i, c := range want {
+		if h.Counts[i] != c {
+			t.Fatalf("Expected daily points %v, got %v", want, h.Counts)
+		}
+	}
+	if h.Meta["issues_analyzed"] != 4 || h.Meta["issues_unestimated"] != 1 || h.Meta["points_total"] != 12.0 {
+		t.Errorf("Unexpected meta %v", h.Meta)
+	}
+
+	stories := NewPointsHistogram(issues, start, start.AddDate(0, 0, 3), []string{"Story"})
+	if stories.Meta["points_total"] != 4.0 {
+		t.Errorf("Expected 4 story points, got %v", stories.Meta["points_total"])
+	}
+	if got := MedianEstimate(issues); got != 3 {
+		t.Errorf("Expected a median estimate of 3, got %v", got)
+	}
+}
+
+func TestRunPointsForecast(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	start := now.AddDate(0, 0, -59)
+	var finished []jira.Issue
+	for day := 0; day < 60; day += 2 {
+		resolved := start.AddDate(0, 0, day)
+		estimate := 5.0
+		finished = append(finished, jira.Issue{IssueType: "Story", Outcome: "delivered", ResolutionDate: &resolved, Estimate: &estimate})
+	}
+	req := ForecastRequest{Mode: "duration", Finished: finished, WindowStart: start, WindowEnd: now, SimulationSeed: 7, Clock: now}
+
+	res, err := RunPointsForecast(context.Background(), req, 50)
+	if err != nil {
+		t.Fatalf("RunPointsForecast: %v", err)
+	}
+	// 5 points every other day: 50 points take about 20 days.
+	if res.Percentiles.CoinToss < 15 || res.Percentiles.CoinToss > 25 {
+		t.Errorf("Expected about 20 days at P50, got %v", res.Percentiles.CoinToss)
+	}
+
+	for i := range finished {
+		finished[i].Estimate = nil
+	}
+	if _, err := RunPointsForecast(context.Background(), req, 50); err == nil {
+		t.Error("Expected an error without estimates")
+	}
+}
@@ -0,0 +1,46 @@
+package simulation
+
+import (
+	"math/rand/v2"
+	"slices"
+	"time"
+)
+
+// ExtendWithLag shifts a duration forecast from "resolved" to "released" by
+// convolving it with an empirical release-lag sample (days). Each trial draws a
+// completion time from the forecast's piecewise-linear quantile function
+// (P10…P98, clamped at the ends) and adds an independently drawn lag.
+// Returns the input unchanged when the lag sample is empty. seed 0 = random.
+func ExtendWithLag(p Percentiles, lags []float64, trials int, seed int64) Percentiles {
+	if len(lags) == 0 || trials <= 0 {
+		return p
+	}
+	probs := []float64{0.10, 0.30, 0.50, 0.70, 0.85, 0.90, 0.95, 0.98}
+	values := []float64{p.Aggressive, p.Unlikely, p.CoinToss, p.Probable, p.Likely, p.Conservative, p.Safe, p.AlmostCertain}
+
+	quantile := func(u float64) float64 {
+		if u <= probs[0] {
+			return values[0]
+		}
+		for i := 1; i < len(probs); i++ {
+			if u <= probs[i] {
+				frac := (u - probs[i-1]) / (probs[i] - probs[i-1])
+				return values[i-1] + frac*(values[i]-values[i-1])
+			}
+		}
+		return values[len(values)-1]
+	}
+
+	rngSeed := uint64(time.Now().UnixNano())
+	if seed != 0 {
+		rngSeed = uint64(seed)
+	}
+	rng := rand.New(rand.NewPCG(rngSeed, 2))
+
+	sums := make([]float64, trials)
+	for i := range sums {
+		sums[i] = quantile(rng.Float64()) + lags[rng.IntN(len(lags))]
+	}
+	slices.Sort(sums)
+	return percentilesFromSorted(sums)
+}
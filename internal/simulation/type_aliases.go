@@ -0,0 +1,77 @@
+package simulation
+
+import (
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// TypeAliases maps issue type names to the canonical type they are grouped
+// under (e.g. "User Story" → "Story"). Types without an entry are their own
+// canonical type. Grouping semantically identical types keeps per-type
+// throughput streams from being split into sparse buckets.
+type TypeAliases map[string]string
+
+// Canonical returns the type t is grouped under.
+func (a TypeAliases) Canonical(t string) string {
+	if c, ok := a[t]; ok {
+		return c
+	}
+	return t
+}
+
+// Issues returns issues with aliased types renamed to their canonical type.
+// The input is returned as is when no issue is affected.
+func (a TypeAliases) Issues(issues []jira.Issue) []jira.Issue {
+	if len(a) == 0 || !slices.ContainsFunc(issues, func(i jira.Issue) bool { _, ok := a[i.IssueType]; return ok }) {
+		return issues
+	}
+	out := slices.Clone(issues)
+	for i := range out {
+		out[i].IssueType = a.Canonical(out[i].IssueType)
+	}
+	return out
+}
+
+// Types returns the canonical types of ts, without duplicates, in the order
+// they first appear.
+func (a TypeAliases) Types(ts []string) []string {
+	if len(a) == 0 || len(ts) == 0 {
+		return ts
+	}
+	out := make([]string, 0, len(ts))
+	for _, t := range ts {
+		if c := a.Canonical(t); !slices.Contains(out, c) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// mergeAliased sums the values of aliased types into their canonical type.
+func mergeAliased[V int | float64](a TypeAliases, m map[string]V) map[string]V {
+	if len(a) == 0 || len(m) == 0 {
+		return m
+	}
+	out := make(map[string]V, len(m))
+	for t, v := range m {
+		out[a.Canonical(t)] += v
+	}
+	return out
+}
+
+// withTypeAliases returns req with every issue type (issues, targets, mix
+// overrides, and filters) replaced by its canonical type.
+func (req ForecastRequest) withTypeAliases() ForecastRequest {
+	a := req.TypeAliases
+	if len(a) == 0 {
+		return req
+	}
+	req.AllIssues = a.Issues(req.AllIssues)
+	req.Finished = a.Issues(req.Finished)
+	req.WIP = a.Issues(req.WIP)
+	req.Targets = mergeAliased(a, req.Targets)
+	req.MixOverrides = mergeAliased(a, req.MixOverrides)
+	req.IssueTypes = a.Types(req.IssueTypes)
+	return req
+}
@@ -0,0 +1,51 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestTypeAliases_WithTypeAliases(t *testing.T) {
+	now := time.Now()
+	issues := []jira.Issue{
+		{Key: "A-1", IssueType: "Story", Outcome: "delivered", ResolutionDate: &now},
+		{Key: "A-2", IssueType: "User Story", Outcome: "delivered", ResolutionDate: &now},
+		{Key: "A-3", IssueType: "Bug", Outcome: "delivered", ResolutionDate: &now},
+	}
+	req := ForecastRequest{
+		AllIssues:    issues,
+		Finished:     issues,
+		Targets:      map[string]int{"Story": 3, "User Story": 2},
+		MixOverrides: map[string]float64{"User Story": 0.5, "Bug": 0.5},
+		IssueTypes:   []string{"User Story", "Story", "Bug"},
+		TypeAliases:  TypeAliases{"User Story": "Story"},
+	}
+
+	got := req.withTypeAliases()
+	if got.Finished[1].IssueType != "Story" {
+		t.Errorf("Expected the aliased issue to be renamed, got %q", got.Finished[1].IssueType)
+	}
+	if issues[1].IssueType != "User Story" {
+		t.Errorf("Expected the caller's issues to be left unchanged, got %q", issues[1].IssueType)
+	}
+	if got.Targets["Story"] != 5 || len(got.Targets) != 1 {
+		t.Errorf("Expected targets to be merged into Story, got %v", got.Targets)
+	}
+	if got.MixOverrides["Story"] != 0.5 || got.MixOverrides["Bug"] != 0.5 {
+		t.Errorf("Expected mix overrides to be merged, got %v", got.MixOverrides)
+	}
+	if !slices.Equal(got.IssueTypes, []string{"Story", "Bug"}) {
+		t.Errorf("Expected deduplicated canonical filters, got %v", got.IssueTypes)
+	}
+
+	h := NewHistogram(got.Finished, now.AddDate(0, 0, -1), now, got.IssueTypes, nil, nil, "")
+	if _, ok := h.StratifiedCounts["User Story"]; ok {
+		t.Errorf("Expected no separate stream for the alias, got %v", h.StratifiedCounts)
+	}
+	if n := slices.Max(h.StratifiedCounts["Story"]); n != 2 {
+		t.Errorf("Expected both stories in one stream, got %v", h.StratifiedCounts["Story"])
+	}
+}
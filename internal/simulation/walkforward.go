@@ -1,7 +1,9 @@
 package simulation
 
 import (
+	"context"
 	"fmt"
+	"math"
 	"mcs-mcp/internal/eventlog"
 	"mcs-mcp/internal/jira"
 	"mcs-mcp/internal/stats"
@@ -15,10 +17,12 @@ type WalkForwardConfig struct {
 	SourceID        string
 	SimulationMode  string   // "duration" or "scope"
 	LookbackWindow  int      // Days to look back for the validation (e.g., 90 days)
+	SampleDays      int      // Sampling window of each checkpoint's histogram. Zero means DefaultCheckpointSampleDays.
 	StepSize        int      // Days between checkouts (e.g., 7 days)
 	ForecastHorizon int      // Days to forecast into the future (only for Scope mode, e.g. 14 days)
 	ItemsToForecast int      // Number of items (only for Duration mode)
 	IssueTypes      []string // Optional: Filter by issue types
+	TypeAliases     TypeAliases
 	Resolutions     map[string]string
 	EvaluationDate  time.Time
 
@@ -29,6 +33,14 @@ type WalkForwardConfig struct {
 	// SimulationSeed, when non-zero, seeds each checkpoint's RNG deterministically
 	// as (SimulationSeed + checkpointIndex), making walk-forward results reproducible.
 	SimulationSeed int64
+
+	// Trials is the number of Monte-Carlo trials per checkpoint. Zero means DefaultTrials.
+	// Lower values (e.g. FastTrials) trade percentile precision for speed.
+	Trials int
+
+	// OnCheckpoint, when non-nil, is called before each checkpoint is simulated and
+	// once more after the last one, with the number of completed and total checkpoints.
+	OnCheckpoint func(done, total int)
 }
 
 // ValidationCheckpoint represents a single point in the past where we ran a simulation.
@@ -53,14 +65,34 @@ type StationarityCorrelation struct {
 	Signal                   string  `json:"signal"`                    // "predictive", "not_predictive", "insufficient_data"
 }
 
+// CalibrationPoint compares a nominal forecast percentile with how often the
+// actual outcome honored it across the backtest checkpoints. A forecast is
+// honored when the work finished within the predicted days (duration mode) or
+// delivered at least the predicted items (scope mode).
+type CalibrationPoint struct {
+	Percentile  string  `json:"percentile"`  // "P50", "P85", "P95"
+	Nominal     float64 `json:"nominal"`     // Promised hit rate (0.85 for P85)
+	Observed    float64 `json:"observed"`    // Fraction of checkpoints that honored the forecast
+	Hits        int     `json:"hits"`        // Checkpoints that honored the forecast
+	Checkpoints int     `json:"checkpoints"` // Non-degenerate checkpoints evaluated
+	Verdict     string  `json:"verdict"`     // "calibrated", "overconfident", "underconfident", "insufficient_data"
+}
+
+// MinCalibrationCheckpoints is the number of non-degenerate checkpoints below
+// which calibration verdicts are withheld.
+const MinCalibrationCheckpoints = 8
+
 // WalkForwardResult holds the aggregate results of the analysis.
 type WalkForwardResult struct {
-	AccuracyScore           float64                  `json:"accuracy_score"` // % of checkpoints within cone
+	AccuracyScore           float64                  `json:"accuracy_score"`        // % of checkpoints within cone
+	SampleDays              int                      `json:"sample_days,omitempty"` // Per-checkpoint sampling window
+	TrialsPerCheckpoint     int                      `json:"trials_per_checkpoint,omitempty"`
 	DegenerateCheckpoints   int                      `json:"degenerate_checkpoints,omitempty"`
 	Checkpoints             []ValidationCheckpoint   `json:"checkpoints"`
 	DriftWarning            string                   `json:"drift_warning,omitempty"`
 	ValidationMessage       string                   `json:"validation_message"`
 	StationarityCorrelation *StationarityCorrelation `json:"stationarity_correlation,omitempty"`
+	Calibration             []CalibrationPoint       `json:"calibration,omitempty"`
 }
 
 // WalkForwardEngine orchestrates the time-travel validation.
@@ -83,15 +115,27 @@ func (w *WalkForwardEngine) GetAnalyzedIssues() []jira.Issue {
 	return w.analyzedIssues
 }
 
-// Execute performs the walk-forward analysis.
-func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, error) {
-	result := WalkForwardResult{
-		Checkpoints: make([]ValidationCheckpoint, 0),
-	}
-
+// Execute performs the walk-forward analysis. It stops between checkpoints and
+// returns ctx.Err() when ctx is cancelled.
+func (w *WalkForwardEngine) Execute(ctx context.Context, cfg WalkForwardConfig) (WalkForwardResult, error) {
 	if cfg.EvaluationDate.IsZero() {
 		cfg.EvaluationDate = time.Now()
 	}
+	trials := cfg.Trials
+	if trials <= 0 {
+		trials = DefaultTrials
+	}
+
+	sampleDays := cfg.SampleDays
+	if sampleDays <= 0 {
+		sampleDays = DefaultCheckpointSampleDays
+	}
+
+	result := WalkForwardResult{
+		SampleDays:          sampleDays,
+		Checkpoints:         make([]ValidationCheckpoint, 0),
+		TrialsPerCheckpoint: trials,
+	}
 
 	// 1. Detect System Drift (Three-Way Chart)
 	// We analyze the last year of data to find if there was a major process shift.
@@ -166,7 +210,19 @@ func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, e
 	totalHits := 0
 	checkpointIndex := 0
 
+	totalCheckpoints := 0
+	for d := now.AddDate(0, 0, -cfg.StepSize); d.After(startTime); d = d.AddDate(0, 0, -cfg.StepSize) {
+		totalCheckpoints++
+	}
+
 	for d := now.AddDate(0, 0, -cfg.StepSize); d.After(startTime); d = d.AddDate(0, 0, -cfg.StepSize) {
+		if err := ctx.Err(); err != nil {
+			return result, fmt.Errorf("backtest cancelled after %d/%d checkpoints: %w", checkpointIndex, totalCheckpoints, err)
+		}
+		if cfg.OnCheckpoint != nil {
+			cfg.OnCheckpoint(checkpointIndex, totalCheckpoints)
+		}
+
 		// 3. Time Travel: State at 'd'
 		// Filter events for Simulation Input (only known at 'd')
 		pastEvents := w.sliceEvents(d)
@@ -176,7 +232,7 @@ func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, e
 
 		// 3.5. Stationarity assessment at this checkpoint (if commitment point available).
 		// Runs before histogram construction so the experimental branch can narrow historyStart.
-		historyStart := d.AddDate(0, 0, -90) // 90-day rolling window (aligned with MCS default)
+		historyStart := d.AddDate(0, 0, -sampleDays) // Rolling window (90 days by default, aligned with MCS)
 		if globalCutoff != nil && historyStart.Before(*globalCutoff) {
 			historyStart = *globalCutoff
 			// If history range is too short (e.g. project just started),
@@ -198,9 +254,10 @@ func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, e
 		}
 
 		// Build Histogram (Capability) using historyStart.
-		h := NewHistogram(pastIssues, historyStart, d, cfg.IssueTypes, w.mappings, w.resolutions)
+		h := NewHistogram(cfg.TypeAliases.Issues(pastIssues), historyStart, d, cfg.TypeAliases.Types(cfg.IssueTypes), w.mappings, w.resolutions, stats.OutcomesDelivered)
 
 		engine := NewEngine(h)
+		engine.SetContext(ctx)
 		if cfg.SimulationSeed != 0 {
 			engine.SetSeed(cfg.SimulationSeed + int64(checkpointIndex))
 		}
@@ -215,7 +272,7 @@ func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, e
 
 		if cfg.SimulationMode == "scope" {
 			// FORECAST: "In next 14 days, check how many done"
-			simRes := engine.RunScopeSimulation(cfg.ForecastHorizon, DefaultTrials)
+			simRes := engine.RunScopeSimulation(cfg.ForecastHorizon, trials)
 
 			// ACTUAL: Look at 'fullHistory' (which contains future relative to 'd')
 			// Count how many items finished between d and d + ForecastHorizon
@@ -239,7 +296,7 @@ func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, e
 			if cfg.ItemsToForecast <= 0 {
 				continue // Can't forecast 0 items
 			}
-			simRes := engine.RunDurationSimulation(cfg.ItemsToForecast, DefaultTrials)
+			simRes := engine.RunDurationSimulation(cfg.ItemsToForecast, trials)
 
 			// ACTUAL: Find the Nth item resolved AFTER 'd'
 			actualDays := w.measureDurationForNItems(allIssues, d, cfg.ItemsToForecast)
@@ -278,6 +335,9 @@ func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, e
 		}
 		result.Checkpoints = append(result.Checkpoints, cp)
 	}
+	if cfg.OnCheckpoint != nil {
+		cfg.OnCheckpoint(totalCheckpoints, totalCheckpoints)
+	}
 
 	if totalHits > 0 {
 		result.AccuracyScore = float64(activeHits) / float64(totalHits)
@@ -297,9 +357,91 @@ func (w *WalkForwardEngine) Execute(cfg WalkForwardConfig) (WalkForwardResult, e
 	// Compute stationarity correlation (only if we have stationarity data)
 	result.StationarityCorrelation = computeStationarityCorrelation(result.Checkpoints)
 
+	result.Calibration = computeCalibration(result.Checkpoints, cfg.SimulationMode)
+	if summary := calibrationSummary(result.Calibration); summary != "" {
+		result.ValidationMessage += " " + summary
+	}
+
 	return result, nil
 }
 
+// computeCalibration measures, for each reported percentile, the fraction of
+// non-degenerate checkpoints whose actual outcome honored the forecast. A
+// well-calibrated model honors its P85 forecast at about 85% of checkpoints;
+// fewer means it is overconfident, more means it is needlessly conservative.
+func computeCalibration(checkpoints []ValidationCheckpoint, mode string) []CalibrationPoint {
+	levels := []struct {
+		label     string
+		nominal   float64
+		predicted func(ValidationCheckpoint) float64
+	}{
+		{"P50", 0.50, func(cp ValidationCheckpoint) float64 { return cp.PredictedP50 }},
+		{"P85", 0.85, func(cp ValidationCheckpoint) float64 { return cp.PredictedP85 }},
+		{"P95", 0.95, func(cp ValidationCheckpoint) float64 { return cp.PredictedP95 }},
+	}
+
+	var evaluated []ValidationCheckpoint
+	for _, cp := range checkpoints {
+		if !cp.IsDegenerate {
+			evaluated = append(evaluated, cp)
+		}
+	}
+	if len(evaluated) == 0 {
+		return nil
+	}
+
+	points := make([]CalibrationPoint, 0, len(levels))
+	for _, l := range levels {
+		hits := 0
+		for _, cp := range evaluated {
+			// Duration forecasts are upper bounds on days; scope forecasts are
+			// lower bounds on items. Same 0.1 tolerance as the cone check.
+			if (mode == "scope" && cp.ActualValue >= l.predicted(cp)-0.1) ||
+				(mode != "scope" && cp.ActualValue <= l.predicted(cp)+0.1) {
+				hits++
+			}
+		}
+		n := len(evaluated)
+		observed := float64(hits) / float64(n)
+		points = append(points, CalibrationPoint{
+			Percentile:  l.label,
+			Nominal:     l.nominal,
+			Observed:    stats.Round2(observed),
+			Hits:        hits,
+			Checkpoints: n,
+			Verdict:     calibrationVerdict(observed, l.nominal, n),
+		})
+	}
+	return points
+}
+
+// calibrationVerdict compares an observed hit rate with its nominal rate,
+// tolerating two binomial standard errors (at least 5 points) of sampling noise.
+func calibrationVerdict(observed, nominal float64, n int) string {
+	if n < MinCalibrationCheckpoints {
+		return "insufficient_data"
+	}
+	tolerance := max(0.05, 2*math.Sqrt(nominal*(1-nominal)/float64(n)))
+	switch {
+	case observed < nominal-tolerance:
+		return "overconfident"
+	case observed > nominal+tolerance:
+		return "underconfident"
+	}
+	return "calibrated"
+}
+
+// calibrationSummary describes the P85 calibration, the level commitments are
+// usually made at, in one sentence.
+func calibrationSummary(points []CalibrationPoint) string {
+	i := slices.IndexFunc(points, func(p CalibrationPoint) bool { return p.Percentile == "P85" })
+	if i < 0 || points[i].Verdict == "insufficient_data" {
+		return ""
+	}
+	p := points[i]
+	return fmt.Sprintf("Calibration: P85 forecasts were honored at %d/%d checkpoints (%.0f%%, nominal 85%%) — %s.", p.Hits, p.Checkpoints, p.Observed*100, p.Verdict)
+}
+
 // computeStationarityCorrelation partitions checkpoints into stationary vs non-stationary
 // and compares miss rates to determine if the stationarity signal is predictive.
 func computeStationarityCorrelation(checkpoints []ValidationCheckpoint) *StationarityCorrelation {
@@ -442,6 +584,87 @@ func (w *WalkForwardEngine) measureDurationForNItems(allIssues []jira.Issue, sta
 	return nthDate.Sub(start).Hours() / 24.0
 }
 
+// WindowSweepEntry summarizes the backtest of one per-checkpoint sampling window.
+type WindowSweepEntry struct {
+	SampleDays    int     `json:"sample_days"`
+	AccuracyScore float64 `json:"accuracy_score"`
+	Checkpoints   int     `json:"checkpoints"`
+	// CalibrationError is the mean absolute gap between observed and nominal hit
+	// rates over P50/P85/P95. Nil when there are too few checkpoints to judge.
+	CalibrationError *float64           `json:"calibration_error,omitempty"`
+	Calibration      []CalibrationPoint `json:"calibration,omitempty"`
+
+	Result WalkForwardResult `json:"-"`
+}
+
+// WindowSweepResult compares backtests over several sampling windows.
+type WindowSweepResult struct {
+	Windows     []WindowSweepEntry `json:"windows"`
+	Recommended int                `json:"recommended_sample_days,omitempty"`
+	Reason      string             `json:"recommendation_reason"`
+}
+
+// Sweep runs the backtest once per sampling window and recommends the window
+// whose forecasts were best calibrated. Ties go to the more accurate window,
+// then to the longer one, which is less sensitive to short-lived noise. All
+// windows share the seed, so their differences come from the window alone.
+func (w *WalkForwardEngine) Sweep(ctx context.Context, cfg WalkForwardConfig, sampleDays []int) (WindowSweepResult, error) {
+	res := WindowSweepResult{Windows: make([]WindowSweepEntry, 0, len(sampleDays))}
+
+	onCheckpoint := cfg.OnCheckpoint
+	for i, days := range sampleDays {
+		wcfg := cfg
+		wcfg.SampleDays = days
+		if onCheckpoint != nil {
+			wcfg.OnCheckpoint = func(done, total int) {
+				onCheckpoint(i*total+done, len(sampleDays)*total)
+			}
+		}
+		wres, err := w.Execute(ctx, wcfg)
+		if err != nil {
+			return res, fmt.Errorf("window sweep (%d days): %w", days, err)
+		}
+
+		entry := WindowSweepEntry{
+			SampleDays:    wres.SampleDays,
+			AccuracyScore: stats.Round2(wres.AccuracyScore),
+			Checkpoints:   len(wres.Checkpoints) - wres.DegenerateCheckpoints,
+			Calibration:   wres.Calibration,
+			Result:        wres,
+		}
+		if len(wres.Calibration) > 0 && wres.Calibration[0].Verdict != "insufficient_data" {
+			gap := 0.0
+			for _, p := range wres.Calibration {
+				gap += math.Abs(p.Observed - p.Nominal)
+			}
+			gap = stats.Round2(gap / float64(len(wres.Calibration)))
+			entry.CalibrationError = &gap
+		}
+		res.Windows = append(res.Windows, entry)
+	}
+
+	var best *WindowSweepEntry
+	for i := range res.Windows {
+		e := &res.Windows[i]
+		if e.CalibrationError == nil {
+			continue
+		}
+		if best == nil || *e.CalibrationError < *best.CalibrationError ||
+			(*e.CalibrationError == *best.CalibrationError && (e.AccuracyScore > best.AccuracyScore ||
+				(e.AccuracyScore == best.AccuracyScore && e.SampleDays > best.SampleDays))) {
+			best = e
+		}
+	}
+	if best == nil {
+		res.Reason = fmt.Sprintf("No window produced the %d non-degenerate checkpoints needed to judge calibration; widen the validation range.", MinCalibrationCheckpoints)
+		return res, nil
+	}
+	res.Recommended = best.SampleDays
+	res.Reason = fmt.Sprintf("%d days had the smallest mean calibration gap (%.0f points across P50/P85/P95) with %.0f%% of outcomes inside the cone.",
+		best.SampleDays, *best.CalibrationError*100, best.AccuracyScore*100)
+	return res, nil
+}
+
 // MultiEngineResult holds per-engine backtest results for auto mode selection.
 type MultiEngineResult struct {
 	PerEngine map[string]WalkForwardResult `json:"per_engine"`
@@ -451,7 +674,8 @@ type MultiEngineResult struct {
 
 // ExecuteMultiEngine runs a walk-forward backtest using multiple ForecastEngines
 // at each checkpoint and returns per-engine accuracy scores plus the selected engine.
-func (w *WalkForwardEngine) ExecuteMultiEngine(cfg WalkForwardConfig, engines []ForecastEngine, weights map[string]int) (*MultiEngineResult, error) {
+// It stops between checkpoints and returns ctx.Err() when ctx is cancelled.
+func (w *WalkForwardEngine) ExecuteMultiEngine(ctx context.Context, cfg WalkForwardConfig, engines []ForecastEngine, weights map[string]int) (*MultiEngineResult, error) {
 	if len(engines) == 0 {
 		return nil, fmt.Errorf("no engines provided")
 	}
@@ -460,6 +684,11 @@ func (w *WalkForwardEngine) ExecuteMultiEngine(cfg WalkForwardConfig, engines []
 		cfg.EvaluationDate = time.Now()
 	}
 
+	sampleDays := cfg.SampleDays
+	if sampleDays <= 0 {
+		sampleDays = DefaultCheckpointSampleDays
+	}
+
 	allIssues := w.reconstructAllIssuesAt(cfg.EvaluationDate)
 
 	// Discovery cutoff
@@ -521,10 +750,13 @@ func (w *WalkForwardEngine) ExecuteMultiEngine(cfg WalkForwardConfig, engines []
 
 	checkpointIndex := 0
 	for d := now.AddDate(0, 0, -cfg.StepSize); d.After(startTime); d = d.AddDate(0, 0, -cfg.StepSize) {
+		if err := ctx.Err(); err != nil {
+			return nil, fmt.Errorf("engine selection cancelled after %d checkpoints: %w", checkpointIndex, err)
+		}
 		pastEvents := w.sliceEvents(d)
 		pastIssues := w.reconstructAllIssues(pastEvents, d)
 
-		historyStart := d.AddDate(0, 0, -90)
+		historyStart := d.AddDate(0, 0, -sampleDays)
 		if globalCutoff != nil && historyStart.Before(*globalCutoff) {
 			historyStart = *globalCutoff
 		}
@@ -543,6 +775,7 @@ func (w *WalkForwardEngine) ExecuteMultiEngine(cfg WalkForwardConfig, engines []
 			WindowEnd:        d,
 			DiscoveryCutoff:  cutoff,
 			IssueTypes:       cfg.IssueTypes,
+			TypeAliases:      cfg.TypeAliases,
 			CommitmentPoint:  cfg.CommitmentPoint,
 			StatusWeights:    cfg.StatusWeights,
 			WorkflowMappings: w.mappings,
@@ -580,7 +813,10 @@ func (w *WalkForwardEngine) ExecuteMultiEngine(cfg WalkForwardConfig, engines []
 
 		// Run each engine and evaluate
 		for _, eng := range engines {
-			result, err := eng.Run(req)
+			result, err := eng.Run(ctx, req)
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				return nil, fmt.Errorf("engine selection cancelled after %d checkpoints: %w", checkpointIndex, ctxErr)
+			}
 			if err != nil {
 				checkpointIndex++
 				continue
@@ -0,0 +1,222 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// MaxCostliestAbandoned is the number of abandoned items listed by their
+// wasted downstream time.
+const MaxCostliestAbandoned = 10
+
+// AbandonmentAnalysis explains where and how late finished work is abandoned.
+type AbandonmentAnalysis struct {
+	Abandoned            int                `json:"abandoned"`
+	Delivered            int                `json:"delivered"`
+	AbandonmentRate      float64            `json:"abandonment_rate"`       // Abandoned share of the finished items
+	WastedDownstreamDays float64            `json:"wasted_downstream_days"` // Days abandoned items spent in Downstream statuses
+	AgeAtExit            ExitAgeComparison  `json:"age_at_exit"`
+	ByTier               []AbandonmentPoint `json:"by_tier"`         // Demand, Upstream, Downstream
+	ByStatus             []AbandonmentPoint `json:"by_status"`       // Most abandoned first
+	CostliestItems       []AbandonedItem    `json:"costliest_items"` // Most wasted downstream days first
+}
+
+// ExitAgeComparison compares the age (creation to outcome) at which items
+// were abandoned with the age at which items were delivered.
+type ExitAgeComparison struct {
+	AbandonedP50 float64 `json:"abandoned_p50_days"`
+	AbandonedP85 float64 `json:"abandoned_p85_days"`
+	DeliveredP50 float64 `json:"delivered_p50_days"`
+	DeliveredP85 float64 `json:"delivered_p85_days"`
+}
+
+// AbandonmentPoint counts the items abandoned from one tier or status.
+type AbandonmentPoint struct {
+	Tier                 string  `json:"tier"`
+	Status               string  `json:"status,omitempty"`
+	StatusID             string  `json:"status_id,omitempty"`
+	Count                int     `json:"count"`
+	Share                float64 `json:"share"` // Of the abandoned items
+	MedianAgeDays        float64 `json:"median_age_days"`
+	WastedDownstreamDays float64 `json:"wasted_downstream_days"`
+}
+
+// AbandonedItem is one abandoned item with the time it consumed.
+type AbandonedItem struct {
+	Key            string  `json:"key"`
+	IssueType      string  `json:"issue_type"`
+	Tier           string  `json:"tier"`   // Tier it was abandoned from
+	Status         string  `json:"status"` // Status it was abandoned from
+	Abandoned      string  `json:"abandoned"`
+	AgeDays        float64 `json:"age_days"`
+	DownstreamDays float64 `json:"downstream_days"`
+}
+
+// abandonedFrom returns the status an abandoned item left for a Finished one,
+// and its tier: the latest status it entered outside the Finished tier, else
+// the status it was created in. Statuses without a mapped tier count as
+// Demand.
+func abandonedFrom(issue jira.Issue, mappings map[string]StatusMetadata) (status, statusID, tier string) {
+	for i := len(issue.Transitions) - 1; i >= 0; i-- {
+		tr := issue.Transitions[i]
+		t := DetermineTier(jira.Issue{Status: tr.ToStatus, StatusID: tr.ToStatusID}, "", mappings)
+		if t != TierFinished && t != "Unknown" {
+			return tr.ToStatus, tr.ToStatusID, t
+		}
+	}
+	status, statusID = issue.BirthStatus, issue.BirthStatusID
+	if len(issue.Transitions) > 0 && status == "" {
+		status, statusID = issue.Transitions[0].FromStatus, issue.Transitions[0].FromStatusID
+	}
+	tier = DetermineTier(jira.Issue{Status: status, StatusID: statusID}, "", mappings)
+	if tier == TierFinished || tier == "Unknown" {
+		tier = TierDemand
+	}
+	return status, statusID, tier
+}
+
+// tierDays returns the days an issue spent in the statuses of tier.
+func tierDays(issue jira.Issue, tier string, mappings map[string]StatusMetadata) float64 {
+	var seconds int64
+	for status, s := range issue.StatusResidency {
+		if mappings[status].Tier == tier {
+			seconds += s
+		}
+	}
+	return float64(seconds) / 86400.0
+}
+
+// exitAge returns the days from creation to the outcome of a finished issue.
+func exitAge(issue jira.Issue) float64 {
+	if issue.OutcomeDate == nil {
+		return 0
+	}
+	return issue.OutcomeDate.Sub(issue.Created).Hours() / 24
+}
+
+// AnalyzeAbandonment attributes the abandoned items among finished to the
+// tier and status they were abandoned from, compares their age at exit with
+// that of the delivered items, and sums the downstream time they consumed.
+func AnalyzeAbandonment(finished []jira.Issue, mappings map[string]StatusMetadata) AbandonmentAnalysis {
+	res := AbandonmentAnalysis{
+		ByTier:         make([]AbandonmentPoint, 0),
+		ByStatus:       make([]AbandonmentPoint, 0),
+		CostliestItems: make([]AbandonedItem, 0),
+	}
+
+	type group struct {
+		point AbandonmentPoint
+		ages  []float64
+	}
+	tiers := make(map[string]*group)
+	statuses := make(map[string]*group)
+	add := func(groups map[string]*group, key string, point AbandonmentPoint, item AbandonedItem) {
+		g, ok := groups[key]
+		if !ok {
+			g = &group{point: point}
+			groups[key] = g
+		}
+		g.point.Count++
+		g.point.WastedDownstreamDays += item.DownstreamDays
+		g.ages = append(g.ages, item.AgeDays)
+	}
+
+	var abandonedAges, deliveredAges []float64
+	for _, issue := range finished {
+		switch issue.Outcome {
+		case "delivered":
+			res.Delivered++
+			deliveredAges = append(deliveredAges, exitAge(issue))
+		case "abandoned":
+			res.Abandoned++
+			status, statusID, tier := abandonedFrom(issue, mappings)
+			item := AbandonedItem{
+				Key:            issue.Key,
+				IssueType:      issue.IssueType,
+				Tier:           tier,
+				Status:         status,
+				AgeDays:        exitAge(issue),
+				DownstreamDays: tierDays(issue, TierDownstream, mappings),
+			}
+			if issue.OutcomeDate != nil {
+				item.Abandoned = issue.OutcomeDate.Format("2006-01-02")
+			}
+			abandonedAges = append(abandonedAges, item.AgeDays)
+			res.WastedDownstreamDays += item.DownstreamDays
+			res.CostliestItems = append(res.CostliestItems, item)
+
+			add(tiers, tier, AbandonmentPoint{Tier: tier}, item)
+			key := statusID
+			if key == "" {
+				key = status
+			}
+			add(statuses, key, AbandonmentPoint{Tier: tier, Status: status, StatusID: statusID}, item)
+		}
+	}
+
+	if total := res.Abandoned + res.Delivered; total > 0 {
+		res.AbandonmentRate = float64(res.Abandoned) / float64(total)
+	}
+	res.AgeAtExit = ExitAgeComparison{
+		AbandonedP50: PercentileOf(abandonedAges, 0.50),
+		AbandonedP85: PercentileOf(abandonedAges, 0.85),
+		DeliveredP50: PercentileOf(deliveredAges, 0.50),
+		DeliveredP85: PercentileOf(deliveredAges, 0.85),
+	}
+
+	point := func(g *group) AbandonmentPoint {
+		p := g.point
+		p.Share = float64(p.Count) / float64(res.Abandoned)
+		p.MedianAgeDays = PercentileOf(g.ages, 0.50)
+		return p
+	}
+	for _, tier := range []string{TierDemand, TierUpstream, TierDownstream} {
+		if g, ok := tiers[tier]; ok {
+			res.ByTier = append(res.ByTier, point(g))
+		}
+	}
+	for _, g := range statuses {
+		res.ByStatus = append(res.ByStatus, point(g))
+	}
+	slices.SortFunc(res.ByStatus, func(a, b AbandonmentPoint) int {
+		if c := cmp.Compare(b.Count, a.Count); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Status, b.Status)
+	})
+
+	slices.SortFunc(res.CostliestItems, func(a, b AbandonedItem) int {
+		if c := cmp.Compare(b.DownstreamDays, a.DownstreamDays); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.AgeDays, a.AgeDays); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	res.CostliestItems = res.CostliestItems[:min(MaxCostliestAbandoned, len(res.CostliestItems))]
+	return res
+}
+
+// Round rounds all numeric fields to 2 decimal places for output compactness.
+func (a *AbandonmentAnalysis) Round() {
+	a.AbandonmentRate = Round2(a.AbandonmentRate)
+	a.WastedDownstreamDays = Round2(a.WastedDownstreamDays)
+	a.AgeAtExit.AbandonedP50 = Round2(a.AgeAtExit.AbandonedP50)
+	a.AgeAtExit.AbandonedP85 = Round2(a.AgeAtExit.AbandonedP85)
+	a.AgeAtExit.DeliveredP50 = Round2(a.AgeAtExit.DeliveredP50)
+	a.AgeAtExit.DeliveredP85 = Round2(a.AgeAtExit.DeliveredP85)
+	for _, points := range [][]AbandonmentPoint{a.ByTier, a.ByStatus} {
+		for i := range points {
+			points[i].Share = Round2(points[i].Share)
+			points[i].MedianAgeDays = Round2(points[i].MedianAgeDays)
+			points[i].WastedDownstreamDays = Round2(points[i].WastedDownstreamDays)
+		}
+	}
+	for i := range a.CostliestItems {
+		a.CostliestItems[i].AgeDays = Round2(a.CostliestItems[i].AgeDays)
+		a.CostliestItems[i].DownstreamDays = Round2(a.CostliestItems[i].DownstreamDays)
+	}
+}
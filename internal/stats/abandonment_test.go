@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestAnalyzeAbandonment(t *testing.T) {
+	mappings := map[string]StatusMetadata{
+		"1": {Name: "Open", Tier: TierDemand},
+		"2": {Name: "Refined", Tier: TierUpstream},
+		"3": {Name: "In Flight", Tier: TierDownstream},
+		"4": {Name: "Done", Tier: TierFinished, Outcome: "delivered"},
+		"5": {Name: "Discarded", Tier: TierFinished, Outcome: "abandoned"},
+	}
+	created := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	after := func(days int) *time.Time {
+		d := created.AddDate(0, 0, days)
+		return &d
+	}
+	day := int64(86400)
+	issues := []jira.Issue{
+		{Key: "ISS-1", Outcome: "delivered", Created: created, OutcomeDate: after(4)},
+		{Key: "ISS-2", Outcome: "delivered", Created: created, OutcomeDate: after(6)},
+		{Key: "ISS-3", Outcome: "abandoned", Created: created, OutcomeDate: after(2), BirthStatus: "Open", BirthStatusID: "1",
+			Transitions: []jira.StatusTransition{{FromStatus: "Open", FromStatusID: "1", ToStatus: "Discarded", ToStatusID: "5"}}},
+		{Key: "ISS-4", Outcome: "abandoned", Created: created, OutcomeDate: after(20), StatusResidency: map[string]int64{"1": 2 * day, "3": 12 * day},
+			Transitions: []jira.StatusTransition{{ToStatus: "In Flight", ToStatusID: "3"}, {ToStatus: "Discarded", ToStatusID: "5"}}},
+		{Key: "ISS-5", Outcome: "abandoned", Created: created, OutcomeDate: after(10), StatusResidency: map[string]int64{"3": 3 * day},
+			Transitions: []jira.StatusTransition{{ToStatus: "In Flight", ToStatusID: "3"}, {ToStatus: "Discarded", ToStatusID: "5"}}},
+	}
+
+	res := AnalyzeAbandonment(issues, mappings)
+	if res.Abandoned != 3 || res.Delivered != 2 || res.AbandonmentRate != 0.6 {
+		t.Errorf("Expected 3 of 5 finished items abandoned, got %d/%d (%v)", res.Abandoned, res.Delivered, res.AbandonmentRate)
+	}
+	if res.WastedDownstreamDays != 15 {
+		t.Errorf("Expected 15 wasted downstream days, got %v", res.WastedDownstreamDays)
+	}
+	if len(res.ByTier) != 2 || res.ByTier[0].Tier != TierDemand || res.ByTier[1].Tier != TierDownstream || res.ByTier[1].Count != 2 {
+		t.Fatalf("Expected one item abandoned from Demand and two from Downstream, got %+v", res.ByTier)
+	}
+	if top := res.ByStatus[0]; top.Status != "In Flight" || top.StatusID != "3" || top.Count != 2 || top.MedianAgeDays != 20 {
+		t.Errorf("Expected In Flight as the top abandonment status, got %+v", top)
+	}
+	if res.CostliestItems[0].Key != "ISS-4" || res.CostliestItems[0].Abandoned != "2024-03-21" {
+		t.Errorf("Expected ISS-4 as the costliest item, got %+v", res.CostliestItems[0])
+	}
+	if res.AgeAtExit.AbandonedP50 != 10 || res.AgeAtExit.DeliveredP50 != 6 {
+		t.Errorf("Expected exit ages P50 10 (abandoned) and 6 (delivered), got %+v", res.AgeAtExit)
+	}
+}
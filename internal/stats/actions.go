@@ -0,0 +1,168 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+)
+
+// Action kinds, in the order they are ranked. Stopping new starts comes first
+// because it is the systemic lever; reducing WIP per status and splitting aged
+// items act on work already in the system.
+const (
+	ActionStopStarting = "stop_starting"
+	ActionReduceWIP    = "reduce_wip"
+	ActionSplitItems   = "split_items"
+)
+
+var actionPriority = map[string]int{
+	ActionStopStarting: 0,
+	ActionReduceWIP:    1,
+	ActionSplitItems:   2,
+}
+
+// ActionEvidence is a single metric backing a recommended action.
+type ActionEvidence struct {
+	Metric    string  `json:"metric"`
+	Value     float64 `json:"value"`
+	Threshold float64 `json:"threshold,omitempty"`
+	Unit      string  `json:"unit,omitempty"`
+}
+
+// Action is a structured, data-backed recommendation derived from a diagnostic finding.
+type Action struct {
+	Rank     int              `json:"rank"`
+	Kind     string           `json:"kind"`
+	Summary  string           `json:"summary"`
+	Target   string           `json:"target,omitempty"`
+	Change   int              `json:"change,omitempty"` // Number of items to remove, split, or stop starting
+	Evidence []ActionEvidence `json:"evidence"`
+
+	magnitude float64 // Ranking weight within a kind
+}
+
+// FlowDebtActions recommends stopping new starts when arrivals outpace departures.
+// The debt rate is averaged over all buckets of the window.
+func FlowDebtActions(res FlowDebtResult, bucket string) []Action {
+	if res.TotalDebt <= 0 || len(res.Buckets) == 0 {
+		return nil
+	}
+	rate := Round2(float64(res.TotalDebt) / float64(len(res.Buckets)))
+	return []Action{{
+		Kind:    ActionStopStarting,
+		Summary: fmt.Sprintf("Stop starting: flow debt +%.1f/%s. Finish in-flight work before pulling new items past the commitment point.", rate, bucket),
+		Change:  int(math.Ceil(rate)),
+		Evidence: []ActionEvidence{
+			{Metric: "total_flow_debt", Value: float64(res.TotalDebt), Unit: "items"},
+			{Metric: "flow_debt_rate", Value: rate, Unit: "items/" + bucket},
+		},
+		magnitude: rate,
+	}}
+}
+
+// StatusWIPActions recommends reducing WIP in statuses holding more items than
+// Little's Law sustains at the current delivery rate: throughput × share of
+// items visiting the status × their P85 residence there. Using P85 rather than
+// the median keeps the target generous, so only clear overload is flagged.
+func StatusWIPActions(wip []InventoryAge, persistence []StatusPersistence, throughputPerDay float64) []Action {
+	if throughputPerDay <= 0 {
+		return nil
+	}
+
+	current := make(map[string]int)
+	for _, a := range wip {
+		current[a.Status]++
+	}
+
+	var actions []Action
+	for _, p := range persistence {
+		n := current[p.StatusName]
+		if n == 0 || p.Tier == "Demand" || p.Tier == "Finished" {
+			continue
+		}
+		sustainable := int(math.Ceil(throughputPerDay * p.Share * p.P85))
+		excess := n - max(sustainable, 1)
+		if excess < 1 {
+			continue
+		}
+		actions = append(actions, Action{
+			Kind:    ActionReduceWIP,
+			Summary: fmt.Sprintf("Reduce WIP in %s by %d (from %d to %d).", p.StatusName, excess, n, n-excess),
+			Target:  p.StatusName,
+			Change:  excess,
+			Evidence: []ActionEvidence{
+				{Metric: "current_wip", Value: float64(n), Threshold: float64(n - excess), Unit: "items"},
+				{Metric: "status_p85_residence", Value: p.P85, Unit: "days"},
+				{Metric: "throughput", Value: Round2(throughputPerDay), Unit: "items/day"},
+			},
+			magnitude: float64(excess),
+		})
+	}
+	return actions
+}
+
+// AgingTypeActions recommends splitting in-flight items whose WIP age already
+// exceeds the P85 cycle time of their own issue type. Types with fewer than
+// MinTypeWorkflowSample historical cycle times are skipped.
+func AgingTypeActions(wip []InventoryAge, cycleTimesByType map[string][]float64) []Action {
+	exceeding := make(map[string]int)
+	oldest := make(map[string]float64)
+	thresholds := make(map[string]float64)
+	for _, a := range wip {
+		if a.AgeSinceCommitment == nil {
+			continue
+		}
+		cts := cycleTimesByType[a.Type]
+		if len(cts) < MinTypeWorkflowSample {
+			continue
+		}
+		p85, ok := thresholds[a.Type]
+		if !ok {
+			p85 = Round2(PercentileOf(cts, 0.85))
+			thresholds[a.Type] = p85
+		}
+		if *a.AgeSinceCommitment > p85 {
+			exceeding[a.Type]++
+			oldest[a.Type] = max(oldest[a.Type], *a.AgeSinceCommitment)
+		}
+	}
+
+	var actions []Action
+	for issueType, n := range exceeding {
+		p85 := thresholds[issueType]
+		actions = append(actions, Action{
+			Kind:    ActionSplitItems,
+			Summary: fmt.Sprintf("Split or swarm %d %s item(s) exceeding %.0f days (the %s P85 cycle time).", n, issueType, p85, issueType),
+			Target:  issueType,
+			Change:  n,
+			Evidence: []ActionEvidence{
+				{Metric: "items_over_p85", Value: float64(n), Unit: "items"},
+				{Metric: "type_p85_cycle_time", Value: p85, Unit: "days"},
+				{Metric: "oldest_wip_age", Value: Round2(oldest[issueType]), Threshold: p85, Unit: "days"},
+			},
+			magnitude: float64(n),
+		})
+	}
+	return actions
+}
+
+// RankActions merges action lists, orders them by kind priority and then by
+// magnitude (largest first), and assigns 1-based ranks.
+func RankActions(groups ...[]Action) []Action {
+	ranked := make([]Action, 0)
+	for _, g := range groups {
+		ranked = append(ranked, g...)
+	}
+	slices.SortStableFunc(ranked, func(a, b Action) int {
+		return cmp.Or(
+			cmp.Compare(actionPriority[a.Kind], actionPriority[b.Kind]),
+			cmp.Compare(b.magnitude, a.magnitude),
+			cmp.Compare(a.Target, b.Target),
+		)
+	})
+	for i := range ranked {
+		ranked[i].Rank = i + 1
+	}
+	return ranked
+}
@@ -0,0 +1,73 @@
+package stats
+
+import "testing"
+
+func TestFlowDebtActions(t *testing.T) {
+	if got := FlowDebtActions(FlowDebtResult{TotalDebt: -3, Buckets: make([]FlowDebtBucket, 4)}, "week"); len(got) != 0 {
+		t.Errorf("Expected no action for negative debt, got %+v", got)
+	}
+
+	got := FlowDebtActions(FlowDebtResult{TotalDebt: 16, Buckets: make([]FlowDebtBucket, 4)}, "week")
+	if len(got) != 1 || got[0].Kind != ActionStopStarting || got[0].Change != 4 {
+		t.Fatalf("Expected a stop-starting action of 4/week, got %+v", got)
+	}
+	if got[0].Summary != "Stop starting: flow debt +4.0/week. Finish in-flight work before pulling new items past the commitment point." {
+		t.Errorf("Unexpected summary %q", got[0].Summary)
+	}
+}
+
+func TestStatusWIPActions(t *testing.T) {
+	wip := make([]InventoryAge, 0)
+	for range 6 {
+		wip = append(wip, InventoryAge{Status: "Code Review", Tier: "Downstream"})
+	}
+	wip = append(wip, InventoryAge{Status: "In Dev", Tier: "Downstream"})
+	persistence := []StatusPersistence{
+		{StatusName: "Code Review", Tier: "Downstream", Share: 1.0, P85: 2.0},
+		{StatusName: "In Dev", Tier: "Downstream", Share: 1.0, P85: 5.0},
+	}
+
+	// 0.75 items/day × 1.0 × 2 days → 2 sustainable items in Code Review.
+	got := StatusWIPActions(wip, persistence, 0.75)
+	if len(got) != 1 || got[0].Target != "Code Review" || got[0].Change != 4 {
+		t.Fatalf("Expected 'reduce WIP in Code Review by 4', got %+v", got)
+	}
+	if StatusWIPActions(wip, persistence, 0) != nil {
+		t.Errorf("Expected no actions without throughput")
+	}
+}
+
+func TestAgingTypeActions(t *testing.T) {
+	age := func(d float64) *float64 { return &d }
+	wip := []InventoryAge{
+		{Key: "S-1", Type: "Story", AgeSinceCommitment: age(20)},
+		{Key: "S-2", Type: "Story", AgeSinceCommitment: age(3)},
+		{Key: "B-1", Type: "Bug", AgeSinceCommitment: age(20)},
+	}
+	byType := map[string][]float64{
+		"Story": {5, 8, 10, 12, 15, 15},
+		"Bug":   {1, 2}, // below the sample floor
+	}
+
+	got := AgingTypeActions(wip, byType)
+	if len(got) != 1 || got[0].Target != "Story" || got[0].Change != 1 {
+		t.Fatalf("Expected one Story split action, got %+v", got)
+	}
+}
+
+func TestRankActions(t *testing.T) {
+	ranked := RankActions(
+		[]Action{{Kind: ActionSplitItems, Target: "Story", magnitude: 9}},
+		[]Action{{Kind: ActionReduceWIP, Target: "QA", magnitude: 1}, {Kind: ActionReduceWIP, Target: "Review", magnitude: 3}},
+		[]Action{{Kind: ActionStopStarting, magnitude: 0.5}},
+	)
+	want := []string{ActionStopStarting + ":", ActionReduceWIP + ":Review", ActionReduceWIP + ":QA", ActionSplitItems + ":Story"}
+	for i, a := range ranked {
+		if a.Rank != i+1 || a.Kind+":"+a.Target != want[i] {
+			t.Errorf("At rank %d: expected %s, got %s:%s (rank %d)", i+1, want[i], a.Kind, a.Target, a.Rank)
+		}
+	}
+	if len(RankActions()) != 0 || RankActions() == nil {
+		t.Errorf("Expected an empty, non-nil list")
+	}
+}
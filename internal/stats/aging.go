@@ -161,8 +161,6 @@ func downstreamSince(issue jira.Issue, since time.Time, mappings map[string]Stat
 // only from the last backflow date forward (backflow = transition to a status with weight < commitment weight).
 // Total age and upstream days always reflect the full history.
 func CalculateInventoryAge(wipIssues []jira.Issue, startStatus string, statusWeights map[string]int, mappings map[string]StatusMetadata, persistence []float64, agingType string, commitmentBackflowReset bool, evaluationTime time.Time) []InventoryAge {
-	var results []InventoryAge
-
 	// 1. Copy and Sort historical values for percentile calculation
 	// We MUST copy here to avoid modifying the caller's slice (side-effect protection).
 	sortedPersistence := make([]float64, len(persistence))
@@ -181,7 +179,7 @@ func CalculateInventoryAge(wipIssues []jira.Issue, startStatus string, statusWei
 		return 100
 	}
 
-	for _, issue := range wipIssues {
+	ageOf := func(issue jira.Issue) (InventoryAge, bool) {
 		// 0. Determine Tier Context
 		currentTier := DetermineTier(issue, startStatus, mappings)
 		isFinished := false
@@ -295,7 +293,7 @@ func CalculateInventoryAge(wipIssues []jira.Issue, startStatus string, statusWei
 		}
 
 		if ageSinceCommitment == nil && agingType != "total" {
-			continue
+			return InventoryAge{}, false
 		}
 
 		analysis := InventoryAge{
@@ -324,19 +322,58 @@ func CalculateInventoryAge(wipIssues []jira.Issue, startStatus string, statusWei
 			}
 		}
 
-		results = append(results, analysis)
+		return analysis, true
 	}
 
-	slices.SortFunc(results, func(a, b InventoryAge) int {
-		if a.AgeSinceCommitment != nil && b.AgeSinceCommitment != nil {
-			return cmp.Compare(*b.AgeSinceCommitment, *a.AgeSinceCommitment)
+	// Items age independently, so chunks of the WIP are aged in parallel and
+	// concatenated in order before the final sort.
+	chunks := parallelChunks(len(wipIssues), func(lo, hi int) []InventoryAge {
+		var aged []InventoryAge
+		for _, issue := range wipIssues[lo:hi] {
+			if a, ok := ageOf(issue); ok {
+				aged = append(aged, a)
+			}
 		}
-		return cmp.Compare(b.AgeInCurrentStatus, a.AgeInCurrentStatus)
+		return aged
 	})
+	var results []InventoryAge
+	for _, c := range chunks {
+		results = append(results, c...)
+	}
+
+	slices.SortFunc(results, compareInventoryAge)
+
+	return results
+}
 
+// CalculateInventoryAgeByType is CalculateInventoryAge with the commitment
+// point resolved per issue type, so types with their own start status age
+// from it rather than from the board default.
+func CalculateInventoryAgeByType(wipIssues []jira.Issue, commitments CommitmentPoints, statusWeights map[string]int, mappings map[string]StatusMetadata, persistence []float64, agingType string, commitmentBackflowReset bool, evaluationTime time.Time) []InventoryAge {
+	if !commitments.HasOverrides() {
+		return CalculateInventoryAge(wipIssues, commitments.Default, statusWeights, mappings, persistence, agingType, commitmentBackflowReset, evaluationTime)
+	}
+
+	keys, groups := commitments.Group(wipIssues)
+	aged := parallelGroups(groups, func(cp string, group []jira.Issue) []InventoryAge {
+		return CalculateInventoryAge(group, cp, statusWeights, mappings, persistence, agingType, commitmentBackflowReset, evaluationTime)
+	})
+	var results []InventoryAge
+	for _, cp := range keys {
+		results = append(results, aged[cp]...)
+	}
+	slices.SortStableFunc(results, compareInventoryAge)
 	return results
 }
 
+// compareInventoryAge orders items oldest first.
+func compareInventoryAge(a, b InventoryAge) int {
+	if a.AgeSinceCommitment != nil && b.AgeSinceCommitment != nil {
+		return cmp.Compare(*b.AgeSinceCommitment, *a.AgeSinceCommitment)
+	}
+	return cmp.Compare(b.AgeInCurrentStatus, a.AgeInCurrentStatus)
+}
+
 // AgingSummary provides an aggregate WIP health snapshot with risk-band distribution
 // and Little's Law stability index. Designed to complement the per-item InventoryAge data.
 type AgingSummary struct {
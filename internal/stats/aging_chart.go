@@ -0,0 +1,167 @@
+package stats
+
+import (
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// AgingChartColumn is one workflow column of the Aging WIP chart: a status
+// from the commitment point onward, with the WIP ages at which delivered
+// items historically left it.
+type AgingChartColumn struct {
+	StatusID string  `json:"status_id"`
+	Status   string  `json:"status"`
+	Position int     `json:"position"` // 0-based, in confirmed status order
+	WIP      int     `json:"wip"`      // In-progress items currently in the column
+	Sample   int     `json:"sample"`   // Delivered items that left the column
+	P50      float64 `json:"p50"`
+	P70      float64 `json:"p70"`
+	P85      float64 `json:"p85"`
+	P95      float64 `json:"p95"`
+}
+
+// AgingChartItem places one in-progress item on the Aging WIP chart.
+type AgingChartItem struct {
+	Key      string  `json:"key"`
+	Type     string  `json:"type"`
+	Position int     `json:"position"` // Column position
+	Age      float64 `json:"age_days"` // WIP age since commitment
+	// AbovePercentile is the highest percentile line of its column the item has
+	// passed (50, 70, 85 or 95), or 0 when it is younger than the column's P50.
+	AbovePercentile int `json:"above_percentile"`
+}
+
+// AgingChart is the classic Aging WIP chart: one column per in-progress
+// status, each in-progress item at its WIP age, and per-column percentile
+// lines of the age at which delivered items left that column.
+type AgingChart struct {
+	Columns []AgingChartColumn `json:"columns"`
+	Items   []AgingChartItem   `json:"items"`
+}
+
+// BuildAgingChart lays out the Aging WIP chart. The columns are the statuses
+// of order (status IDs, in confirmed order) from the commitment point up to
+// the Finished tier. Items come from aging (WIP ages since commitment), with
+// their columns looked up in wip. A column's percentile lines are computed
+// from the delivered items' cumulative time in the columns up to their last
+// exit from it, the same clock as WIP age. Statuses without items are left out.
+func BuildAgingChart(aging []InventoryAge, wip, delivered []jira.Issue, order []string, commitmentPoint string, mappings map[string]StatusMetadata) AgingChart {
+	chart := AgingChart{Columns: make([]AgingChartColumn, 0), Items: make([]AgingChartItem, 0)}
+
+	start := slices.Index(order, commitmentPoint)
+	position := make(map[string]int)
+	for i, id := range order {
+		m := mappings[id]
+		if m.Tier == TierFinished {
+			continue
+		}
+		if (start < 0 && m.Tier != TierDownstream) || (start >= 0 && i < start) {
+			continue
+		}
+		position[id] = len(chart.Columns)
+		chart.Columns = append(chart.Columns, AgingChartColumn{StatusID: id, Status: m.Name, Position: len(chart.Columns)})
+	}
+	if len(chart.Columns) == 0 {
+		return chart
+	}
+
+	exitAges := make([][]float64, len(chart.Columns))
+	for _, issue := range delivered {
+		for id, age := range columnExitAges(issue, position) {
+			exitAges[position[id]] = append(exitAges[position[id]], age)
+		}
+	}
+	for i := range chart.Columns {
+		c := &chart.Columns[i]
+		if c.Status == "" {
+			c.Status = statusNameOf(c.StatusID, delivered, wip)
+		}
+		sorted := exitAges[i]
+		slices.Sort(sorted)
+		c.Sample = len(sorted)
+		if c.Sample > 0 {
+			c.P50 = RoundTo(CalculatePercentile(sorted, 0.50), 1)
+			c.P70 = RoundTo(CalculatePercentile(sorted, 0.70), 1)
+			c.P85 = RoundTo(CalculatePercentile(sorted, 0.85), 1)
+			c.P95 = RoundTo(CalculatePercentile(sorted, 0.95), 1)
+		}
+	}
+
+	statusOf := make(map[string]string, len(wip))
+	for _, issue := range wip {
+		statusOf[issue.Key] = PreferID(issue.StatusID, issue.Status)
+	}
+	for _, a := range aging {
+		pos, ok := position[statusOf[a.Key]]
+		if !ok || a.AgeSinceCommitment == nil || a.IsCompleted {
+			continue
+		}
+		c := &chart.Columns[pos]
+		c.WIP++
+		item := AgingChartItem{Key: a.Key, Type: a.Type, Position: pos, Age: *a.AgeSinceCommitment}
+		if c.Sample > 0 {
+			for _, line := range []struct {
+				p     int
+				limit float64
+			}{{95, c.P95}, {85, c.P85}, {70, c.P70}, {50, c.P50}} {
+				if item.Age > line.limit {
+					item.AbovePercentile = line.p
+					break
+				}
+			}
+		}
+		chart.Items = append(chart.Items, item)
+	}
+
+	// Drop statuses no item has used, renumbering the columns.
+	renumbered := make([]int, len(chart.Columns))
+	used := chart.Columns[:0]
+	for i, c := range chart.Columns {
+		renumbered[i] = len(used)
+		if c.Sample > 0 || c.WIP > 0 {
+			c.Position = len(used)
+			used = append(used, c)
+		}
+	}
+	chart.Columns = used
+	for i := range chart.Items {
+		chart.Items[i].Position = renumbered[chart.Items[i].Position]
+	}
+	return chart
+}
+
+// columnExitAges replays an issue's transitions and returns, per column it
+// left, its cumulative days in the columns at its last exit from that column.
+func columnExitAges(issue jira.Issue, position map[string]int) map[string]float64 {
+	ages := make(map[string]float64)
+	current := PreferID(issue.BirthStatusID, issue.BirthStatus)
+	entered := issue.Created
+	inColumns := 0.0
+	for _, t := range issue.Transitions {
+		if _, ok := position[current]; ok {
+			inColumns += t.Date.Sub(entered).Hours() / 24
+			ages[current] = inColumns
+		}
+		current = PreferID(t.ToStatusID, t.ToStatus)
+		entered = t.Date
+	}
+	return ages
+}
+
+// statusNameOf finds the display name of a status in the issues' histories.
+func statusNameOf(statusID string, issueSets ...[]jira.Issue) string {
+	for _, issues := range issueSets {
+		for _, issue := range issues {
+			if issue.StatusID == statusID && issue.Status != "" {
+				return issue.Status
+			}
+			for _, t := range issue.Transitions {
+				if t.ToStatusID == statusID && t.ToStatus != "" {
+					return t.ToStatus
+				}
+			}
+		}
+	}
+	return statusID
+}
@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestBuildAgingChart(t *testing.T) {
+	mappings := map[string]StatusMetadata{
+		"1": {Name: "Backlog", Tier: TierDemand},
+		"2": {Name: "Dev", Tier: TierDownstream},
+		"3": {Name: "Review", Tier: TierDownstream},
+		"4": {Name: "Done", Tier: TierFinished},
+	}
+	order := []string{"1", "2", "3", "4"}
+	day := func(d int) time.Time { return time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d) }
+
+	// Delivered items spend 2, 4, ..., 20 days in Dev, then 1 day in Review.
+	var delivered []jira.Issue
+	for i := 1; i <= 10; i++ {
+		dev := 2 * i
+		delivered = append(delivered, jira.Issue{
+			Key: "D", BirthStatusID: "1", Created: day(0),
+			Transitions: []jira.StatusTransition{
+				{ToStatusID: "2", ToStatus: "Dev", Date: day(1)},
+				{ToStatusID: "3", ToStatus: "Review", Date: day(1 + dev)},
+				{ToStatusID: "4", ToStatus: "Done", Date: day(2 + dev)},
+			},
+		})
+	}
+
+	wip := []jira.Issue{{Key: "W-1", StatusID: "2"}, {Key: "W-2", StatusID: "3"}, {Key: "W-3", StatusID: "1"}}
+	age := func(d float64) *float64 { return &d }
+	aging := []InventoryAge{
+		{Key: "W-1", Type: "Story", AgeSinceCommitment: age(19)},
+		{Key: "W-2", Type: "Bug", AgeSinceCommitment: age(3)},
+		{Key: "W-3", Type: "Story"}, // Not committed yet
+	}
+
+	chart := BuildAgingChart(aging, wip, delivered, order, "2", mappings)
+
+	if len(chart.Columns) != 2 || chart.Columns[0].Status != "Dev" || chart.Columns[1].Status != "Review" {
+		t.Fatalf("Expected the columns Dev and Review, got %+v", chart.Columns)
+	}
+	dev, review := chart.Columns[0], chart.Columns[1]
+	if dev.Sample != 10 || dev.P50 != 12 || dev.P95 != 20 {
+		t.Errorf("Expected Dev exits at 2..20 days, got %+v", dev)
+	}
+	if review.P50 != 13 || review.WIP != 1 {
+		t.Errorf("Expected Review exits one day after Dev, got %+v", review)
+	}
+	if len(chart.Items) != 2 {
+		t.Fatalf("Expected the two committed items, got %+v", chart.Items)
+	}
+	if w1 := chart.Items[0]; w1.Position != 0 || w1.AbovePercentile != 85 {
+		t.Errorf("Expected W-1 in Dev above its P85, got %+v", w1)
+	}
+	if w2 := chart.Items[1]; w2.Position != 1 || w2.AbovePercentile != 0 {
+		t.Errorf("Expected W-2 in Review below its P50, got %+v", w2)
+	}
+}
@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Alert metrics. Weekly metrics are series of complete weeks ending at the
+// window's End; the others are a single value.
+const (
+	AlertStaleWIPPct      = "stale_wip_pct"     // Share (%) of items in progress older than the historical P85 cycle time
+	AlertAgingOutliers    = "aging_outliers"    // Items in progress older than the historical P85 cycle time
+	AlertWIPCount         = "wip_count"         // Items in progress
+	AlertCycleTimeP85     = "cycle_time_p85"    // P85 cycle time (days) of the items delivered in the window
+	AlertFatTailRatio     = "fat_tail_ratio"    // P98/P50 cycle time of the items delivered in the window
+	AlertFlowDebt         = "flow_debt"         // Commitments minus deliveries over the window
+	AlertWeeklyFlowDebt   = "weekly_flow_debt"  // Commitments minus deliveries per week
+	AlertWeeklyThroughput = "weekly_throughput" // Deliveries per week
+)
+
+// AlertMetrics lists the metrics an alert rule can watch.
+var AlertMetrics = []string{
+	AlertStaleWIPPct, AlertAgingOutliers, AlertWIPCount, AlertCycleTimeP85,
+	AlertFatTailRatio, AlertFlowDebt, AlertWeeklyFlowDebt, AlertWeeklyThroughput,
+}
+
+// IsWeeklyAlertMetric reports whether a metric is a weekly series.
+func IsWeeklyAlertMetric(metric string) bool {
+	return metric == AlertWeeklyFlowDebt || metric == AlertWeeklyThroughput
+}
+
+var alertOperators = []string{">", ">=", "<", "<="}
+
+// AlertRule is a recorded early-warning condition: it fires when Metric
+// compares to Threshold by Operator, on weekly metrics in each of the latest
+// Consecutive weeks.
+type AlertRule struct {
+	Name        string  `json:"name"`
+	Metric      string  `json:"metric"`
+	Operator    string  `json:"operator"`
+	Threshold   float64 `json:"threshold"`
+	Consecutive int     `json:"consecutive,omitempty"` // Weekly metrics only; 0 = the latest week
+}
+
+// Condition renders the rule as "metric op threshold [for N weeks]".
+func (r AlertRule) Condition() string {
+	c := fmt.Sprintf("%s %s %g", r.Metric, r.Operator, r.Threshold)
+	if r.Consecutive > 1 {
+		c += fmt.Sprintf(" for %d consecutive weeks", r.Consecutive)
+	}
+	return c
+}
+
+// Validate checks the metric, the operator, and the consecutive count.
+func (r AlertRule) Validate() error {
+	if !slices.Contains(AlertMetrics, r.Metric) {
+		return fmt.Errorf("unknown metric %q: expected one of %s", r.Metric, strings.Join(AlertMetrics, ", "))
+	}
+	if !slices.Contains(alertOperators, r.Operator) {
+		return fmt.Errorf("invalid operator %q: expected one of %s", r.Operator, strings.Join(alertOperators, ", "))
+	}
+	if r.Consecutive < 0 {
+		return fmt.Errorf("invalid consecutive %d: expected a positive number of weeks", r.Consecutive)
+	}
+	if r.Consecutive > 0 && !IsWeeklyAlertMetric(r.Metric) {
+		return fmt.Errorf("consecutive applies to the weekly metrics only (%s, %s)", AlertWeeklyFlowDebt, AlertWeeklyThroughput)
+	}
+	return nil
+}
+
+func (r AlertRule) holds(v float64) bool {
+	switch r.Operator {
+	case ">":
+		return v > r.Threshold
+	case ">=":
+		return v >= r.Threshold
+	case "<":
+		return v < r.Threshold
+	default:
+		return v <= r.Threshold
+	}
+}
+
+// AlertEvaluation is the outcome of one rule.
+type AlertEvaluation struct {
+	Rule      AlertRule `json:"rule"`
+	Condition string    `json:"condition"`
+	Fired     bool      `json:"fired"`
+	Value     float64   `json:"value"`           // Current value; the latest week of weekly metrics
+	Weeks     []float64 `json:"weeks,omitempty"` // Weekly metrics: the weeks the rule looked at, oldest first
+	Evidence  string    `json:"evidence"`
+}
+
+// EvaluateAlertRule applies a rule to the current value of its metric or, for
+// weekly metrics, to the weekly series (oldest first). A weekly rule needs as
+// many weeks as it looks back; with fewer it does not fire.
+func EvaluateAlertRule(r AlertRule, value float64, weeks []float64) AlertEvaluation {
+	ev := AlertEvaluation{Rule: r, Condition: r.Condition(), Value: Round2(value)}
+	if !IsWeeklyAlertMetric(r.Metric) {
+		ev.Fired = r.holds(value)
+		ev.Evidence = fmt.Sprintf("%s is %g.", r.Metric, ev.Value)
+		return ev
+	}
+
+	n := max(r.Consecutive, 1)
+	if len(weeks) < n {
+		ev.Evidence = fmt.Sprintf("Only %d complete week(s) in the window; the rule looks at %d.", len(weeks), n)
+		return ev
+	}
+	ev.Weeks = weeks[len(weeks)-n:]
+	ev.Value = ev.Weeks[n-1]
+	ev.Fired = true
+	values := make([]string, n)
+	for i, v := range ev.Weeks {
+		ev.Fired = ev.Fired && r.holds(v)
+		values[i] = fmt.Sprintf("%g", v)
+	}
+	ev.Evidence = fmt.Sprintf("%s over the last %d week(s), oldest first: %s.", r.Metric, n, strings.Join(values, ", "))
+	return ev
+}
@@ -0,0 +1,43 @@
+package stats
+
+import "testing"
+
+func TestAlertRuleValidate(t *testing.T) {
+	for _, bad := range []AlertRule{
+		{Metric: "velocity", Operator: ">", Threshold: 1},
+		{Metric: AlertWIPCount, Operator: "==", Threshold: 1},
+		{Metric: AlertWIPCount, Operator: ">", Threshold: 1, Consecutive: 2},
+		{Metric: AlertWeeklyFlowDebt, Operator: ">", Consecutive: -1},
+	} {
+		if err := bad.Validate(); err == nil {
+			t.Errorf("Expected %+v to be rejected", bad)
+		}
+	}
+	if err := (AlertRule{Metric: AlertWeeklyFlowDebt, Operator: ">", Consecutive: 3}).Validate(); err != nil {
+		t.Errorf("Expected a weekly rule accepted, got %v", err)
+	}
+}
+
+func TestEvaluateAlertRule(t *testing.T) {
+	stale := AlertRule{Metric: AlertStaleWIPPct, Operator: ">", Threshold: 30}
+	if ev := EvaluateAlertRule(stale, 42.123, nil); !ev.Fired || ev.Value != 42.12 {
+		t.Errorf("Expected 42%% stale WIP to fire, got %+v", ev)
+	}
+	if ev := EvaluateAlertRule(stale, 30, nil); ev.Fired {
+		t.Errorf("Expected exactly 30%% not to fire a '>' rule, got %+v", ev)
+	}
+
+	debt := AlertRule{Metric: AlertWeeklyFlowDebt, Operator: ">", Threshold: 0, Consecutive: 3}
+	if ev := EvaluateAlertRule(debt, 0, []float64{-1, 2, 1, 4}); !ev.Fired || len(ev.Weeks) != 3 || ev.Value != 4 {
+		t.Errorf("Expected three positive weeks to fire, got %+v", ev)
+	}
+	if ev := EvaluateAlertRule(debt, 0, []float64{2, 0, 1, 4}); ev.Fired {
+		t.Errorf("Expected a balanced week to break the streak, got %+v", ev)
+	}
+	if ev := EvaluateAlertRule(debt, 0, []float64{5, 5}); ev.Fired {
+		t.Errorf("Expected too few weeks not to fire, got %+v", ev)
+	}
+	if got := debt.Condition(); got != "weekly_flow_debt > 0 for 3 consecutive weeks" {
+		t.Errorf("Unexpected condition %q", got)
+	}
+}
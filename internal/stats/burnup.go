@@ -0,0 +1,98 @@
+package stats
+
+import (
+	"slices"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+// BurnupPoint is the cumulative scope and delivery of a release at the end of a bucket.
+type BurnupPoint struct {
+	Label     string `json:"label"`
+	Scope     int    `json:"scope"`     // Items created by the bucket end, less those abandoned by then
+	Completed int    `json:"completed"` // Items delivered by the bucket end
+	Added     int    `json:"added"`     // Items created during the bucket
+	Delivered int    `json:"delivered"` // Items delivered during the bucket
+}
+
+// ReleaseBurnup tracks the scope of a fixVersion against the items delivered from it.
+type ReleaseBurnup struct {
+	Version        string        `json:"version"`
+	Released       bool          `json:"released"`
+	ReleaseDate    string        `json:"release_date,omitempty"`
+	Scope          int           `json:"scope"` // Items assigned to the version, less abandoned ones
+	Delivered      int           `json:"delivered"`
+	Abandoned      int           `json:"abandoned"`
+	Remaining      int           `json:"remaining"`
+	InitialScope   int           `json:"initial_scope"`    // Scope at the end of the first bucket
+	ScopeGrowthPct float64       `json:"scope_growth_pct"` // Scope growth since the first bucket
+	Series         []BurnupPoint `json:"series"`
+}
+
+// CalculateReleaseBurnup buckets the scope and deliveries of the items
+// assigned to version over window. The fixVersion assignment history is not
+// recorded, so items count as scope from their creation; abandoned items leave
+// the scope at their outcome date.
+func CalculateReleaseBurnup(issues []jira.Issue, version string, window AnalysisWindow) ReleaseBurnup {
+	res := ReleaseBurnup{Version: version}
+	var items []jira.Issue
+	for _, issue := range issues {
+		idx := slices.IndexFunc(issue.FixVersions, func(v jira.FixVersion) bool { return v.Name == version })
+		if idx < 0 {
+			continue
+		}
+		items = append(items, issue)
+		if v := issue.FixVersions[idx]; v.Released || v.ReleaseDate != "" {
+			res.Released, res.ReleaseDate = v.Released, v.ReleaseDate
+		}
+		switch {
+		case issue.OutcomeDate != nil && IsDelivered(issue):
+			res.Delivered++
+		case issue.OutcomeDate != nil && issue.Outcome == "abandoned":
+			res.Abandoned++
+		}
+	}
+	res.Scope = len(items) - res.Abandoned
+	res.Remaining = res.Scope - res.Delivered
+
+	reachedBy := func(t *time.Time, end time.Time) bool { return t != nil && !t.After(end) }
+	prevEnd := time.Time{}
+	for _, b := range window.Subdivide() {
+		end := SnapToEnd(b, window.Bucket)
+		if end.After(window.End) {
+			end = window.End
+		}
+		p := BurnupPoint{Label: window.GenerateLabel(b)}
+		for _, issue := range items {
+			created := issue.Created
+			if !created.After(end) {
+				p.Scope++
+				if created.After(prevEnd) || prevEnd.IsZero() {
+					p.Added++
+				}
+			}
+			if !reachedBy(issue.OutcomeDate, end) {
+				continue
+			}
+			if IsDelivered(issue) {
+				p.Completed++
+				if issue.OutcomeDate.After(prevEnd) || prevEnd.IsZero() {
+					p.Delivered++
+				}
+			} else if issue.Outcome == "abandoned" {
+				p.Scope--
+			}
+		}
+		res.Series = append(res.Series, p)
+		prevEnd = end
+	}
+
+	if len(res.Series) > 0 {
+		res.InitialScope = res.Series[0].Scope
+		if res.InitialScope > 0 {
+			res.ScopeGrowthPct = Round2(float64(res.Scope-res.InitialScope) / float64(res.InitialScope) * 100)
+		}
+	}
+	return res
+}
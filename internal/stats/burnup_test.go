@@ -0,0 +1,46 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestCalculateReleaseBurnup(t *testing.T) {
+	monday := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	day := func(n int) time.Time { return monday.AddDate(0, 0, n) }
+	ptr := func(t time.Time) *time.Time { return &t }
+	v24 := []jira.FixVersion{{Name: "2.4", Released: false, ReleaseDate: "2024-04-01"}}
+
+	issues := []jira.Issue{
+		{Key: "A-1", Created: day(0), FixVersions: v24, Outcome: "delivered", OutcomeDate: ptr(day(3))},
+		{Key: "A-2", Created: day(1), FixVersions: v24, Outcome: "delivered", OutcomeDate: ptr(day(9))},
+		{Key: "A-3", Created: day(8), FixVersions: v24},
+		{Key: "A-4", Created: day(2), FixVersions: v24, Outcome: "abandoned", OutcomeDate: ptr(day(10))},
+		{Key: "B-1", Created: day(0), FixVersions: []jira.FixVersion{{Name: "2.5"}}},
+	}
+	window := NewAnalysisWindow(day(0), day(13), "week", time.Time{})
+	res := CalculateReleaseBurnup(issues, "2.4", window)
+
+	if res.Scope != 3 || res.Delivered != 2 || res.Abandoned != 1 || res.Remaining != 1 {
+		t.Errorf("Expected scope 3 with 2 delivered, 1 abandoned, 1 remaining, got %+v", res)
+	}
+	if res.ReleaseDate != "2024-04-01" || res.Released {
+		t.Errorf("Expected the unreleased version's date, got %q released=%v", res.ReleaseDate, res.Released)
+	}
+	if len(res.Series) != 2 {
+		t.Fatalf("Expected 2 weekly points, got %+v", res.Series)
+	}
+	first, second := res.Series[0], res.Series[1]
+	if first.Scope != 3 || first.Completed != 1 || first.Added != 3 || first.Delivered != 1 {
+		t.Errorf("Unexpected first week %+v", first)
+	}
+	// A-3 is added, A-4 abandoned, and A-2 delivered in the second week.
+	if second.Scope != 3 || second.Completed != 2 || second.Added != 1 || second.Delivered != 1 {
+		t.Errorf("Unexpected second week %+v", second)
+	}
+	if res.InitialScope != 3 || res.ScopeGrowthPct != 0 {
+		t.Errorf("Expected no net growth from an initial scope of 3, got %d / %v", res.InitialScope, res.ScopeGrowthPct)
+	}
+}
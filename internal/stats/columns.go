@@ -0,0 +1,220 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+)
+
+// ColumnView maps the statuses of a board onto its columns, so that analyses
+// keyed by status ID run at column granularity. A column is keyed by its name;
+// statuses the board does not show keep their own ID.
+type ColumnView struct {
+	Columns  []jira.BoardColumn
+	ofStatus map[string]string // Status ID → column name
+}
+
+// NewColumnView builds the view of the given board columns.
+func NewColumnView(columns []jira.BoardColumn) ColumnView {
+	v := ColumnView{Columns: columns, ofStatus: make(map[string]string)}
+	for _, c := range columns {
+		for _, id := range c.StatusIDs {
+			v.ofStatus[id] = c.Name
+		}
+	}
+	return v
+}
+
+// Column returns the column showing the status, or the status itself when no
+// column does.
+func (v ColumnView) Column(statusID string) string {
+	if col, ok := v.ofStatus[statusID]; ok {
+		return col
+	}
+	return statusID
+}
+
+// Order returns the column names in board order.
+func (v ColumnView) Order() []string {
+	order := make([]string, len(v.Columns))
+	for i, c := range v.Columns {
+		order[i] = c.Name
+	}
+	return order
+}
+
+// Issues returns copies of the issues with their statuses replaced by their
+// columns: residencies are summed per column, and transitions between two
+// statuses of the same column are dropped.
+func (v ColumnView) Issues(issues []jira.Issue) []jira.Issue {
+	out := make([]jira.Issue, len(issues))
+	for i, issue := range issues {
+		issue.StatusID, issue.Status = v.columnOf(issue.StatusID, issue.Status)
+		issue.BirthStatusID, issue.BirthStatus = v.columnOf(issue.BirthStatusID, issue.BirthStatus)
+		issue.StatusResidency = v.sumByColumn(issue.StatusResidency)
+		issue.BlockedResidency = v.sumByColumn(issue.BlockedResidency)
+		issue.FieldResidency = v.sumByColumn(issue.FieldResidency)
+
+		transitions := make([]jira.StatusTransition, 0, len(issue.Transitions))
+		for _, t := range issue.Transitions {
+			t.FromStatusID, t.FromStatus = v.columnOf(t.FromStatusID, t.FromStatus)
+			t.ToStatusID, t.ToStatus = v.columnOf(t.ToStatusID, t.ToStatus)
+			if t.FromStatusID != t.ToStatusID {
+				transitions = append(transitions, t)
+			}
+		}
+		issue.Transitions = transitions
+		out[i] = issue
+	}
+	return out
+}
+
+// columnOf returns the column of a status as both its ID and its name.
+func (v ColumnView) columnOf(id, name string) (string, string) {
+	if col, ok := v.ofStatus[id]; ok {
+		return col, col
+	}
+	return id, name
+}
+
+func (v ColumnView) sumByColumn(residency map[string]int64) map[string]int64 {
+	if residency == nil {
+		return nil
+	}
+	out := make(map[string]int64, len(residency))
+	for id, seconds := range residency {
+		out[v.Column(id)] += seconds
+	}
+	return out
+}
+
+// representative returns the first status of the column, in board order, for
+// which has is true; the column inherits that status's workflow settings.
+func representative(c jira.BoardColumn, has func(string) bool) (string, bool) {
+	for _, id := range c.StatusIDs {
+		if has(id) {
+			return id, true
+		}
+	}
+	return "", false
+}
+
+// Mapping returns the workflow mapping at column granularity: each column
+// takes the metadata of its first mapped status, under the column's name.
+// Statuses no column shows keep their entry.
+func (v ColumnView) Mapping(mapping map[string]StatusMetadata) map[string]StatusMetadata {
+	out := make(map[string]StatusMetadata, len(mapping))
+	for id, m := range mapping {
+		if _, ok := v.ofStatus[id]; !ok {
+			out[id] = m
+		}
+	}
+	for _, c := range v.Columns {
+		if id, ok := representative(c, func(id string) bool { _, ok := mapping[id]; return ok }); ok {
+			m := mapping[id]
+			m.Name = c.Name
+			out[c.Name] = m
+		}
+	}
+	return out
+}
+
+// Weights returns status weights at column granularity, each column taking
+// the weight of its first weighted status.
+func (v ColumnView) Weights(weights map[string]int) map[string]int {
+	out := make(map[string]int, len(weights))
+	for id, w := range weights {
+		if _, ok := v.ofStatus[id]; !ok {
+			out[id] = w
+		}
+	}
+	for _, c := range v.Columns {
+		if id, ok := representative(c, func(id string) bool { _, ok := weights[id]; return ok }); ok {
+			out[c.Name] = weights[id]
+		}
+	}
+	return out
+}
+
+// Statuses returns a set of status IDs (e.g. the finished statuses) at column
+// granularity: a column belongs to it when its first status known to the set
+// does.
+func (v ColumnView) Statuses(set map[string]bool) map[string]bool {
+	out := make(map[string]bool, len(set))
+	for id, in := range set {
+		if _, ok := v.ofStatus[id]; !ok {
+			out[id] = in
+		}
+	}
+	for _, c := range v.Columns {
+		if id, ok := representative(c, func(id string) bool { _, ok := set[id]; return ok }); ok {
+			out[c.Name] = set[id]
+		}
+	}
+	return out
+}
+
+// CommitmentPoints returns the commitment points at column granularity: the
+// clock starts when an item enters the column of the commitment point.
+func (v ColumnView) CommitmentPoints(cp CommitmentPoints) CommitmentPoints {
+	out := CommitmentPoints{Default: v.Column(cp.Default)}
+	if cp.ByType != nil {
+		out.ByType = make(map[string]string, len(cp.ByType))
+		for t, status := range cp.ByType {
+			out.ByType[t] = v.Column(status)
+		}
+	}
+	return out
+}
+
+// ColumnWIPStability is the XmR assessment of the WIP of one board column,
+// sampled at the end of each week.
+type ColumnWIPStability struct {
+	Column     string    `json:"column"`
+	Tier       string    `json:"tier,omitempty"`
+	AverageWIP float64   `json:"average_wip"`
+	XmR        XmRResult `json:"xmr"`
+	Status     string    `json:"status"` // "stable" or "unstable"
+}
+
+// AnalyzeColumnWIP applies XmR limits to the weekly WIP of each column in
+// order, except Demand and Finished columns. issues must be at column
+// granularity (see ColumnView.Issues).
+func AnalyzeColumnWIP(issues []jira.Issue, window AnalysisWindow, order []string, mappings map[string]StatusMetadata) []ColumnWIPStability {
+	weekly := NewAnalysisWindow(window.Start, window.End, "week", window.Cutoff)
+	var sampleEnds []int64
+	var keys []string
+	for _, start := range weekly.Subdivide() {
+		end := SnapToEnd(start, "week")
+		if end.After(window.End) {
+			end = window.End
+		}
+		sampleEnds = append(sampleEnds, end.UnixMicro())
+		keys = append(keys, weekly.GenerateLabel(start))
+	}
+	if len(sampleEnds) == 0 {
+		return nil
+	}
+
+	var out []ColumnWIPStability
+	for _, column := range order {
+		tier := mappings[column].Tier
+		if tier == "Demand" || tier == "Finished" {
+			continue
+		}
+		values := make([]float64, len(sampleEnds))
+		for i, ts := range sampleEnds {
+			for _, issue := range issues {
+				if getStatusAt(issue, ts) == column {
+					values[i]++
+				}
+			}
+		}
+		xmr := CalculateXmRWithKeys(values, keys)
+		xmr.Round()
+		status := "stable"
+		if len(xmr.Signals) > 0 {
+			status = "unstable"
+		}
+		out = append(out, ColumnWIPStability{Column: column, Tier: tier, AverageWIP: xmr.Average, XmR: xmr, Status: status})
+	}
+	return out
+}
@@ -0,0 +1,95 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestColumnView(t *testing.T) {
+	view := NewColumnView([]jira.BoardColumn{
+		{Name: "To Do", StatusIDs: []string{"1"}},
+		{Name: "In Progress", StatusIDs: []string{"2", "3"}}, // Dev and Review in parallel
+		{Name: "Done", StatusIDs: []string{"5"}},
+	})
+
+	t.Run("Issues", func(t *testing.T) {
+		issue := jira.Issue{
+			Key: "A-1", StatusID: "3", Status: "Review", BirthStatusID: "1", BirthStatus: "Open",
+			StatusResidency: map[string]int64{"1": 10, "2": 20, "3": 30, "9": 5},
+			Transitions: []jira.StatusTransition{
+				{FromStatusID: "1", ToStatusID: "2"},
+				{FromStatusID: "2", ToStatusID: "3"}, // Within In Progress
+				{FromStatusID: "3", ToStatusID: "2"}, // Within In Progress
+				{FromStatusID: "2", ToStatusID: "3"}, // Within In Progress
+			},
+		}
+		got := view.Issues([]jira.Issue{issue})[0]
+
+		if got.StatusID != "In Progress" || got.Status != "In Progress" || got.BirthStatusID != "To Do" {
+			t.Errorf("Expected the statuses to become columns, got %q/%q born %q", got.StatusID, got.Status, got.BirthStatusID)
+		}
+		if got.StatusResidency["In Progress"] != 50 || got.StatusResidency["To Do"] != 10 || got.StatusResidency["9"] != 5 {
+			t.Errorf("Expected residencies summed per column, got %v", got.StatusResidency)
+		}
+		if len(got.Transitions) != 1 || got.Transitions[0].ToStatusID != "In Progress" {
+			t.Errorf("Expected only the move into In Progress, got %+v", got.Transitions)
+		}
+		if issue.StatusResidency["3"] != 30 || len(issue.Transitions) != 4 {
+			t.Error("Expected the original issue to be left untouched")
+		}
+	})
+
+	t.Run("Mapping", func(t *testing.T) {
+		mapping := view.Mapping(map[string]StatusMetadata{
+			"1": {Name: "Open", Tier: "Demand"},
+			"3": {Name: "Review", Tier: "Downstream", Role: "queue"},
+			"5": {Name: "Done", Tier: "Finished", Outcome: "delivered"},
+			"9": {Name: "Parked", Tier: "Upstream"},
+		})
+		if m := mapping["In Progress"]; m.Tier != "Downstream" || m.Name != "In Progress" {
+			t.Errorf("Expected In Progress to take the Review metadata, got %+v", m)
+		}
+		if _, ok := mapping["3"]; ok {
+			t.Error("Expected the status entries of columns to be dropped")
+		}
+		if mapping["9"].Name != "Parked" || len(mapping) != 4 {
+			t.Errorf("Expected the unshown status to keep its entry, got %+v", mapping)
+		}
+	})
+
+	t.Run("CommitmentPoints", func(t *testing.T) {
+		cp := view.CommitmentPoints(CommitmentPoints{Default: "3", ByType: map[string]string{"Bug": "9"}})
+		if cp.Default != "In Progress" || cp.ByType["Bug"] != "9" {
+			t.Errorf("Expected the commitment point to become its column, got %+v", cp)
+		}
+	})
+}
+
+func TestAnalyzeColumnWIP(t *testing.T) {
+	end := time.Date(2024, 3, 31, 23, 59, 59, 0, time.UTC)
+	window := NewAnalysisWindow(end.AddDate(0, 0, -7*8), end, "week", time.Time{})
+	mappings := map[string]StatusMetadata{
+		"To Do":       {Tier: "Demand"},
+		"In Progress": {Tier: "Downstream"},
+		"Done":        {Tier: "Finished"},
+	}
+
+	var issues []jira.Issue
+	for i := range 3 {
+		started := window.Start.AddDate(0, 0, i)
+		issues = append(issues, jira.Issue{
+			Key: "A", Created: window.Start.AddDate(0, 0, -1), StatusID: "In Progress",
+			Transitions: []jira.StatusTransition{{ToStatusID: "In Progress", Date: started}},
+		})
+	}
+
+	got := AnalyzeColumnWIP(issues, window, []string{"To Do", "In Progress", "Done"}, mappings)
+
+	if len(got) != 1 || got[0].Column != "In Progress" {
+		t.Fatalf("Expected only the In Progress column, got %+v", got)
+	}
+	if got[0].AverageWIP != 3 || got[0].Status != "stable" {
+		t.Errorf("Expected a stable WIP of 3, got %+v", got[0])
+	}
+}
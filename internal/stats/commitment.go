@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// CommitmentPoints resolves the commitment point (a status ID) per issue type:
+// a type-specific override when one is configured, otherwise the board default.
+type CommitmentPoints struct {
+	Default string
+	ByType  map[string]string
+}
+
+// For returns the commitment point that applies to an issue type.
+func (c CommitmentPoints) For(issueType string) string {
+	if cp, ok := c.ByType[issueType]; ok && cp != "" {
+		return cp
+	}
+	return c.Default
+}
+
+// HasOverrides reports whether any issue type has its own commitment point.
+func (c CommitmentPoints) HasOverrides() bool {
+	for _, cp := range c.ByType {
+		if cp != "" && cp != c.Default {
+			return true
+		}
+	}
+	return false
+}
+
+// Group partitions issues by the commitment point that applies to them.
+// Keys are returned sorted so callers iterate deterministically.
+func (c CommitmentPoints) Group(issues []jira.Issue) ([]string, map[string][]jira.Issue) {
+	groups := make(map[string][]jira.Issue)
+	for _, issue := range issues {
+		cp := c.For(issue.IssueType)
+		groups[cp] = append(groups[cp], issue)
+	}
+	keys := make([]string, 0, len(groups))
+	for k := range groups {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys, groups
+}
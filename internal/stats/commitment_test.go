@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestCommitmentPoints(t *testing.T) {
+	cps := CommitmentPoints{Default: "dev", ByType: map[string]string{"Bug": "triaged"}}
+
+	if got := cps.For("Bug"); got != "triaged" {
+		t.Errorf("Expected Bug override 'triaged', got %q", got)
+	}
+	if got := cps.For("Story"); got != "dev" {
+		t.Errorf("Expected board default 'dev' for Story, got %q", got)
+	}
+	if !cps.HasOverrides() {
+		t.Errorf("Expected overrides to be detected")
+	}
+	if (CommitmentPoints{Default: "dev", ByType: map[string]string{"Bug": "dev"}}).HasOverrides() {
+		t.Errorf("An override equal to the default is not an override")
+	}
+
+	keys, groups := cps.Group([]jira.Issue{
+		{Key: "B-1", IssueType: "Bug"},
+		{Key: "S-1", IssueType: "Story"},
+		{Key: "S-2", IssueType: "Story"},
+	})
+	if len(keys) != 2 || keys[0] != "dev" || keys[1] != "triaged" {
+		t.Fatalf("Expected sorted keys [dev triaged], got %v", keys)
+	}
+	if len(groups["dev"]) != 2 || len(groups["triaged"]) != 1 {
+		t.Errorf("Unexpected grouping: %+v", groups)
+	}
+}
+
+func TestCalculateInventoryAgeByType(t *testing.T) {
+	now := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	weights := map[string]int{"bl": 1, "tr": 2, "dev": 3}
+	mappings := map[string]StatusMetadata{
+		"bl":  {Tier: "Demand", Name: "Backlog"},
+		"tr":  {Tier: "Upstream", Name: "Triaged"},
+		"dev": {Tier: "Downstream", Name: "In Dev"},
+	}
+	bug := jira.Issue{
+		Key: "B-1", IssueType: "Bug", Status: "Triaged", StatusID: "tr", Created: now.AddDate(0, 0, -3),
+		Transitions:     []jira.StatusTransition{{ToStatus: "Triaged", ToStatusID: "tr", Date: now.AddDate(0, 0, -2)}},
+		StatusResidency: map[string]int64{"Backlog": 86400, "Triaged": 2 * 86400},
+	}
+
+	// Under the board commitment point the triaged Bug has not started.
+	board := CalculateInventoryAgeByType([]jira.Issue{bug}, CommitmentPoints{Default: "dev"}, weights, mappings, nil, "wip", false, now)
+	if len(board) != 0 {
+		t.Errorf("Expected Bug to be excluded under the board commitment point, got %+v", board)
+	}
+
+	// With a Bug override at "Triaged" it is in progress.
+	typed := CalculateInventoryAgeByType([]jira.Issue{bug}, CommitmentPoints{Default: "dev", ByType: map[string]string{"Bug": "tr"}}, weights, mappings, nil, "wip", false, now)
+	if len(typed) != 1 || typed[0].AgeSinceCommitment == nil {
+		t.Errorf("Expected Bug to be aged from its override, got %+v", typed)
+	}
+}
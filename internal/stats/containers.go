@@ -0,0 +1,62 @@
+package stats
+
+import (
+	"fmt"
+	"strings"
+
+	"mcs-mcp/internal/jira"
+)
+
+// ContainerPolicy decides whether container items (epics, initiatives, and
+// other issues that group work rather than being delivered themselves) enter
+// the flow sets of an analysis.
+type ContainerPolicy string
+
+const (
+	// ContainersExclude leaves containers out of the delivered, finished, and
+	// WIP items, and so out of throughput histograms and cycle-time
+	// baselines (default).
+	ContainersExclude ContainerPolicy = "exclude"
+	// ContainersInclude counts containers as work items of their own.
+	ContainersInclude ContainerPolicy = "include"
+)
+
+// ParseContainerPolicy validates a policy name; "" is ContainersExclude.
+func ParseContainerPolicy(s string) (ContainerPolicy, error) {
+	switch p := ContainerPolicy(s); p {
+	case "":
+		return ContainersExclude, nil
+	case ContainersExclude, ContainersInclude:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid container policy %q: expected exclude or include", s)
+}
+
+// ContainerKeys returns the keys of the container items among issues: those
+// whose type sits above the standard level of the Jira hierarchy, those named
+// as parent by another item that is not a sub-task, and, for histories
+// without a hierarchy level (Data Center, or fetched before it was recorded),
+// those of the type Epic.
+func ContainerKeys(issues []jira.Issue) map[string]bool {
+	containers := make(map[string]bool)
+	for _, issue := range issues {
+		if issue.HierarchyLevel > 0 || strings.EqualFold(issue.IssueType, "Epic") {
+			containers[issue.Key] = true
+		}
+		if issue.ParentKey != "" && !issue.IsSubtask {
+			containers[issue.ParentKey] = true
+		}
+	}
+	return containers
+}
+
+// withoutContainers returns the issues whose keys are not in containers.
+func withoutContainers(issues []jira.Issue, containers map[string]bool) []jira.Issue {
+	var out []jira.Issue
+	for _, issue := range issues {
+		if !containers[issue.Key] {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
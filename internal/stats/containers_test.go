@@ -0,0 +1,82 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+)
+
+func TestContainerKeys(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "INIT-1", IssueType: "Initiative", HierarchyLevel: 2},
+		{Key: "FEAT-1", IssueType: "Feature", ParentKey: "INIT-1"},
+		{Key: "EP-1", IssueType: "epic"},
+		{Key: "S-1", IssueType: "Story", ParentKey: "FEAT-1"},
+		{Key: "S-2", IssueType: "Story"},
+		{Key: "T-1", IssueType: "Sub-task", ParentKey: "S-2", IsSubtask: true},
+	}
+	got := ContainerKeys(issues)
+	for _, key := range []string{"INIT-1", "FEAT-1", "EP-1"} {
+		if !got[key] {
+			t.Errorf("Expected %s to be a container", key)
+		}
+	}
+	if got["S-1"] || got["S-2"] || got["T-1"] {
+		t.Errorf("Expected stories with sub-tasks not to be containers, got %v", got)
+	}
+}
+
+func TestAnalysisSession_ContainerPolicies(t *testing.T) {
+	day := func(n int) int64 { return time.Date(2024, 3, 1+n, 10, 0, 0, 0, time.UTC).UnixMicro() }
+	events := []eventlog.IssueEvent{
+		{IssueKey: "PROJ-1", IssueType: "Epic", EventType: eventlog.Created, ToStatus: "Open", HierarchyLevel: 1, Timestamp: day(0)},
+		{IssueKey: "PROJ-2", IssueType: "Story", EventType: eventlog.Created, ToStatus: "Open", ParentKey: "PROJ-1", Timestamp: day(1)},
+		{IssueKey: "PROJ-3", IssueType: "Story", EventType: eventlog.Created, ToStatus: "Open", ParentKey: "PROJ-1", Timestamp: day(1)},
+		{IssueKey: "PROJ-1", IssueType: "Epic", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Dev", Timestamp: day(2)},
+		{IssueKey: "PROJ-2", IssueType: "Story", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Dev", Timestamp: day(2)},
+		{IssueKey: "PROJ-2", IssueType: "Story", EventType: eventlog.Change, FromStatus: "Dev", ToStatus: "Done", Resolution: "Done", Timestamp: day(4)},
+		{IssueKey: "PROJ-3", IssueType: "Story", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Dev", Timestamp: day(5)},
+		{IssueKey: "PROJ-4", IssueType: "Initiative", EventType: eventlog.Created, ToStatus: "Open", HierarchyLevel: 2, Timestamp: day(0)},
+		{IssueKey: "PROJ-4", IssueType: "Initiative", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Done", Resolution: "Done", Timestamp: day(6)},
+	}
+	mappings := map[string]StatusMetadata{
+		"Open": {Tier: TierDemand},
+		"Dev":  {Tier: TierDownstream},
+		"Done": {Tier: TierFinished, Outcome: "delivered"},
+	}
+	window := AnalysisWindow{End: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}
+	resolutions := map[string]string{"Done": "delivered"}
+
+	session := NewAnalysisSession(events, "PROJ", jira.SourceContext{}, mappings, resolutions, window)
+	if got := session.GetDelivered(); len(got) != 1 || got[0].Key != "PROJ-2" {
+		t.Errorf("exclude: expected only PROJ-2 delivered, got %v", got)
+	}
+	if got := session.GetWIP(); len(got) != 1 || got[0].Key != "PROJ-3" {
+		t.Errorf("exclude: expected only PROJ-3 in progress, got %v", got)
+	}
+	if got := len(session.GetAllIssues()); got != 4 {
+		t.Errorf("exclude: expected all 4 issues for hierarchy walks, got %d", got)
+	}
+
+	session.WithContainerPolicy(ContainersInclude)
+	if got := len(session.GetDelivered()); got != 2 {
+		t.Errorf("include: expected PROJ-2 and PROJ-4 delivered, got %d", got)
+	}
+	if got := len(session.GetWIP()); got != 2 {
+		t.Errorf("include: expected PROJ-1 and PROJ-3 in progress, got %d", got)
+	}
+}
+
+func TestParseContainerPolicy(t *testing.T) {
+	if p, err := ParseContainerPolicy(""); err != nil || p != ContainersExclude {
+		t.Errorf("Expected \"\" to mean exclude, got %q (err %v)", p, err)
+	}
+	if p, err := ParseContainerPolicy("include"); err != nil || p != ContainersInclude {
+		t.Errorf("Expected include, got %q (err %v)", p, err)
+	}
+	if _, err := ParseContainerPolicy("epics"); err == nil {
+		t.Error("Expected an unknown policy to fail")
+	}
+}
@@ -0,0 +1,167 @@
+package stats
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+// DriftAssessment compares the recent cycle-time distribution against the
+// baseline that precedes it. Produced by stats, consumed by the MCP handlers
+// to attach a drift warning before forecasts are trusted.
+type DriftAssessment struct {
+	BaselineCount int       `json:"baseline_count"`
+	RecentCount   int       `json:"recent_count"`
+	RecentStart   time.Time `json:"recent_start"`
+	KS            float64   `json:"ks_statistic"`
+	KSCritical    float64   `json:"ks_critical"`
+	PSI           float64   `json:"psi"`
+	Drifted       bool      `json:"drifted"`
+	Warnings      []string  `json:"-"`
+}
+
+// Drift thresholds.
+const (
+	// DriftRecentWeeks is the length of the "live" window compared against the baseline.
+	DriftRecentWeeks = 8
+
+	// minDriftBaseline and minDriftRecent are the sample floors below which
+	// the comparison is too noisy to report.
+	minDriftBaseline = 20
+	minDriftRecent   = 10
+
+	// ksAlphaCoefficient is c(α) for α = 0.05 in the two-sample KS critical value
+	// D_crit = c(α) · sqrt((n+m)/(n·m)).
+	ksAlphaCoefficient = 1.358
+
+	// psiDriftThreshold is the conventional "moderate shift" PSI level. PSI is
+	// biased upwards on small samples, so it only confirms a significant KS result;
+	// KS in turn is trivially significant on very large samples without PSI.
+	psiDriftThreshold = 0.1
+
+	// psiBins is the number of baseline quantile bins used for PSI.
+	psiBins = 10
+
+	// psiEpsilon floors empty-bin proportions so the log term stays finite.
+	psiEpsilon = 1e-4
+)
+
+// KolmogorovSmirnov returns the two-sample KS statistic D: the largest vertical
+// distance between the empirical CDFs of a and b.
+func KolmogorovSmirnov(a, b []float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	sa := slices.Clone(a)
+	sb := slices.Clone(b)
+	slices.Sort(sa)
+	slices.Sort(sb)
+
+	var i, j int
+	var d float64
+	na, nb := float64(len(sa)), float64(len(sb))
+	for i < len(sa) && j < len(sb) {
+		x := min(sa[i], sb[j])
+		for i < len(sa) && sa[i] <= x {
+			i++
+		}
+		for j < len(sb) && sb[j] <= x {
+			j++
+		}
+		d = math.Max(d, math.Abs(float64(i)/na-float64(j)/nb))
+	}
+	return d
+}
+
+// PopulationStabilityIndex returns the PSI of recent against baseline, using
+// baseline quantiles as bin edges so every bin holds ~1/bins of the baseline.
+func PopulationStabilityIndex(baseline, recent []float64, bins int) float64 {
+	if len(baseline) == 0 || len(recent) == 0 || bins < 2 {
+		return 0
+	}
+	sorted := slices.Clone(baseline)
+	slices.Sort(sorted)
+
+	edges := make([]float64, 0, bins-1)
+	for k := 1; k < bins; k++ {
+		edge := CalculatePercentileInterpolated(sorted, float64(k)*100/float64(bins))
+		if len(edges) == 0 || edge > edges[len(edges)-1] {
+			edges = append(edges, edge)
+		}
+	}
+
+	bucket := func(v float64) int {
+		idx, _ := slices.BinarySearch(edges, v)
+		return idx
+	}
+	base := make([]float64, len(edges)+1)
+	rec := make([]float64, len(edges)+1)
+	for _, v := range baseline {
+		base[bucket(v)]++
+	}
+	for _, v := range recent {
+		rec[bucket(v)]++
+	}
+
+	var psi float64
+	for k := range base {
+		p := math.Max(base[k]/float64(len(baseline)), psiEpsilon)
+		q := math.Max(rec[k]/float64(len(recent)), psiEpsilon)
+		psi += (q - p) * math.Log(q/p)
+	}
+	return psi
+}
+
+// AssessCycleTimeDrift splits delivered items at recentStart into a baseline
+// (before) and a recent sample (on or after) and compares their cycle-time
+// distributions with KS and PSI. Returns nil when either sample is below the
+// reporting floor. cycleTimes must be index-aligned with issues.
+func AssessCycleTimeDrift(issues []jira.Issue, cycleTimes []float64, recentStart time.Time) *DriftAssessment {
+	var baseline, recent []float64
+	for i, issue := range issues {
+		if i >= len(cycleTimes) || issue.OutcomeDate == nil {
+			continue
+		}
+		if issue.OutcomeDate.Before(recentStart) {
+			baseline = append(baseline, cycleTimes[i])
+		} else {
+			recent = append(recent, cycleTimes[i])
+		}
+	}
+	if len(baseline) < minDriftBaseline || len(recent) < minDriftRecent {
+		return nil
+	}
+
+	n, m := float64(len(baseline)), float64(len(recent))
+	a := &DriftAssessment{
+		BaselineCount: len(baseline),
+		RecentCount:   len(recent),
+		RecentStart:   recentStart,
+		KS:            KolmogorovSmirnov(baseline, recent),
+		KSCritical:    ksAlphaCoefficient * math.Sqrt((n+m)/(n*m)),
+		PSI:           PopulationStabilityIndex(baseline, recent, psiBins),
+	}
+	a.Drifted = a.KS > a.KSCritical && a.PSI >= psiDriftThreshold
+
+	if a.Drifted {
+		direction := "shorter"
+		if CalculateMedianContinuous(recent) > CalculateMedianContinuous(baseline) {
+			direction = "longer"
+		}
+		a.Warnings = append(a.Warnings, fmt.Sprintf(
+			"DISTRIBUTION DRIFT WARNING: Cycle times delivered since %s (n=%d) diverge from the preceding baseline (n=%d) and are generally %s (KS D=%.2f vs critical %.2f, PSI=%.2f). "+
+				"The baseline no longer describes current behavior — refresh it (e.g. narrow the window via 'set_analysis_window') before trusting forecasts.",
+			recentStart.Format(DateFormat), a.RecentCount, a.BaselineCount, direction, a.KS, a.KSCritical, a.PSI))
+	}
+	return a
+}
+
+// Round rounds all numeric fields to 2 decimal places for output compactness.
+func (a *DriftAssessment) Round() {
+	a.KS = Round2(a.KS)
+	a.KSCritical = Round2(a.KSCritical)
+	a.PSI = Round2(a.PSI)
+}
@@ -0,0 +1,85 @@
+package stats
+
+import (
+	"math"
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestKolmogorovSmirnov(t *testing.T) {
+	a := []float64{1, 2, 3, 4, 5}
+	if d := KolmogorovSmirnov(a, a); d != 0 {
+		t.Errorf("Identical samples: expected D=0, got %.3f", d)
+	}
+	if d := KolmogorovSmirnov(a, []float64{10, 11, 12}); d != 1 {
+		t.Errorf("Disjoint samples: expected D=1, got %.3f", d)
+	}
+	// ECDFs differ by at most 0.4 at x=2 (0.4 vs 0.0)
+	if d := KolmogorovSmirnov(a, []float64{3, 4, 5, 6, 7}); math.Abs(d-0.4) > 1e-9 {
+		t.Errorf("Shifted samples: expected D=0.4, got %.3f", d)
+	}
+}
+
+func TestPopulationStabilityIndex(t *testing.T) {
+	var base []float64
+	for i := 1; i <= 100; i++ {
+		base = append(base, float64(i))
+	}
+	if psi := PopulationStabilityIndex(base, base, 10); psi > 1e-9 {
+		t.Errorf("Identical samples: expected PSI≈0, got %.4f", psi)
+	}
+	shifted := make([]float64, len(base))
+	for i, v := range base {
+		shifted[i] = v + 60
+	}
+	if psi := PopulationStabilityIndex(base, shifted, 10); psi < psiDriftThreshold {
+		t.Errorf("Shifted samples: expected PSI >= %.2f, got %.4f", psiDriftThreshold, psi)
+	}
+}
+
+func TestAssessCycleTimeDrift(t *testing.T) {
+	recentStart := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	before := recentStart.AddDate(0, 0, -30)
+	after := recentStart.AddDate(0, 0, 10)
+
+	build := func(nBase, nRecent int, recentCT func(i int) float64) ([]jira.Issue, []float64) {
+		var issues []jira.Issue
+		var cts []float64
+		for i := 0; i < nBase; i++ {
+			issues = append(issues, jira.Issue{OutcomeDate: &before})
+			cts = append(cts, float64(2+i%8))
+		}
+		for i := 0; i < nRecent; i++ {
+			issues = append(issues, jira.Issue{OutcomeDate: &after})
+			cts = append(cts, recentCT(i))
+		}
+		return issues, cts
+	}
+
+	t.Run("insufficient data", func(t *testing.T) {
+		issues, cts := build(10, 5, func(i int) float64 { return 3 })
+		if a := AssessCycleTimeDrift(issues, cts, recentStart); a != nil {
+			t.Errorf("Expected nil assessment below sample floor, got %+v", a)
+		}
+	})
+
+	t.Run("stable", func(t *testing.T) {
+		issues, cts := build(40, 16, func(i int) float64 { return float64(2 + i%8) })
+		a := AssessCycleTimeDrift(issues, cts, recentStart)
+		if a == nil || a.Drifted || len(a.Warnings) != 0 {
+			t.Errorf("Expected no drift, got %+v", a)
+		}
+	})
+
+	t.Run("drifted", func(t *testing.T) {
+		issues, cts := build(40, 16, func(i int) float64 { return float64(15 + i%8) })
+		a := AssessCycleTimeDrift(issues, cts, recentStart)
+		if a == nil || !a.Drifted || len(a.Warnings) != 1 {
+			t.Fatalf("Expected drift warning, got %+v", a)
+		}
+		if a.BaselineCount != 40 || a.RecentCount != 16 {
+			t.Errorf("Unexpected sample sizes: %d/%d", a.BaselineCount, a.RecentCount)
+		}
+	})
+}
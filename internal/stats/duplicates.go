@@ -0,0 +1,114 @@
+package stats
+
+import (
+	"fmt"
+	"slices"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+)
+
+// DuplicatePolicy decides how an issue listed by several sources of a
+// consolidated analysis (boards with overlapping filters) is attributed.
+type DuplicatePolicy string
+
+const (
+	// DuplicatesFirst counts the issue once, on the source that resolved it,
+	// or else on the first source listing it (default).
+	DuplicatesFirst DuplicatePolicy = "first"
+	// DuplicatesSplit counts the issue once in consolidated results, and
+	// attributes an equal share of it to every source listing it.
+	DuplicatesSplit DuplicatePolicy = "split"
+	// DuplicatesAll counts the issue on every source listing it, so
+	// consolidated results include it once per source.
+	DuplicatesAll DuplicatePolicy = "all"
+)
+
+// ParseDuplicatePolicy validates a policy name; "" is DuplicatesFirst.
+func ParseDuplicatePolicy(s string) (DuplicatePolicy, error) {
+	switch p := DuplicatePolicy(s); p {
+	case "":
+		return DuplicatesFirst, nil
+	case DuplicatesFirst, DuplicatesSplit, DuplicatesAll:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid duplicate policy %q: expected first, split, or all", s)
+}
+
+// Attribution records which sources list each issue and which of them count
+// it under a DuplicatePolicy.
+type Attribution struct {
+	Policy  DuplicatePolicy
+	owners  map[string]int       // first and split: the source counting the issue
+	sources map[string][]int     // every source listing the issue, ascending
+	ids     eventlog.IdentityMap // former keys of moved issues; issues are attributed by current key
+}
+
+// AttributeDuplicates attributes the issues of each source (by index) under
+// the policy. An issue a source still lists under a key it had before a move
+// is the same issue as the one listed under its current key.
+func AttributeDuplicates(sources [][]jira.Issue, policy DuplicatePolicy) Attribution {
+	a := Attribution{Policy: policy, owners: make(map[string]int), sources: make(map[string][]int), ids: IdentityOf(slices.Concat(sources...))}
+	resolved := make(map[string]bool)
+	for i, issues := range sources {
+		for _, issue := range issues {
+			key := a.ids.Resolve(issue.Key)
+			listed := a.sources[key]
+			if len(listed) > 0 && listed[len(listed)-1] == i {
+				continue
+			}
+			a.sources[key] = append(listed, i)
+
+			isResolved := issue.ResolutionDate != nil
+			if len(listed) == 0 || (isResolved && !resolved[key]) {
+				a.owners[key] = i
+				resolved[key] = isResolved
+			}
+		}
+	}
+	return a
+}
+
+// Counts reports whether the issue enters consolidated results from the
+// given source.
+func (a Attribution) Counts(source int, key string) bool {
+	key = a.ids.Resolve(key)
+	if a.Policy == DuplicatesAll {
+		return slices.Contains(a.sources[key], source)
+	}
+	owner, ok := a.owners[key]
+	return ok && owner == source
+}
+
+// Share is the part of the issue attributed to the source: 1 or 0 under
+// DuplicatesFirst, 1/n for each of n listing sources under DuplicatesSplit,
+// and 1 for every listing source under DuplicatesAll.
+func (a Attribution) Share(source int, key string) float64 {
+	key = a.ids.Resolve(key)
+	listed := a.sources[key]
+	if !slices.Contains(listed, source) {
+		return 0
+	}
+	switch a.Policy {
+	case DuplicatesSplit:
+		return 1 / float64(len(listed))
+	case DuplicatesAll:
+		return 1
+	}
+	if a.owners[key] == source {
+		return 1
+	}
+	return 0
+}
+
+// Shared returns the keys listed by more than one source, sorted.
+func (a Attribution) Shared() []string {
+	var keys []string
+	for key, listed := range a.sources {
+		if len(listed) > 1 {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	return keys
+}
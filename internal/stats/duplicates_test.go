@@ -0,0 +1,64 @@
+package stats
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestAttributeDuplicates(t *testing.T) {
+	done := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	sources := [][]jira.Issue{
+		{{Key: "A-1"}, {Key: "A-2"}, {Key: "A-2"}},
+		{{Key: "A-1", ResolutionDate: &done}, {Key: "A-2"}, {Key: "B-1"}},
+		{{Key: "A-1"}},
+	}
+
+	first := AttributeDuplicates(sources, DuplicatesFirst)
+	if got := first.Shared(); !slices.Equal(got, []string{"A-1", "A-2"}) {
+		t.Errorf("Expected A-1 and A-2 shared, got %v", got)
+	}
+	if !first.Counts(1, "A-1") || first.Counts(0, "A-1") || first.Counts(2, "A-1") {
+		t.Error("Expected A-1 to count only on the source that resolved it")
+	}
+	if !first.Counts(0, "A-2") || first.Counts(1, "A-2") || first.Share(1, "A-2") != 0 {
+		t.Error("Expected the unresolved A-2 to count only on the first source listing it")
+	}
+
+	split := AttributeDuplicates(sources, DuplicatesSplit)
+	if split.Counts(0, "A-1") || !split.Counts(1, "A-1") {
+		t.Error("Expected split to count A-1 once in consolidated results")
+	}
+	for i := range sources {
+		if got := split.Share(i, "A-1"); got != 1.0/3 {
+			t.Errorf("Expected a third of A-1 on source %d, got %v", i, got)
+		}
+	}
+	if got := split.Share(2, "A-2"); got != 0 {
+		t.Errorf("Expected no share of A-2 on a source not listing it, got %v", got)
+	}
+
+	all := AttributeDuplicates(sources, DuplicatesAll)
+	for i := range sources {
+		if !all.Counts(i, "A-1") || all.Share(i, "A-1") != 1 {
+			t.Errorf("Expected A-1 to count fully on source %d", i)
+		}
+	}
+	if all.Counts(0, "B-1") || !all.Counts(1, "B-1") {
+		t.Error("Expected B-1 to count only on its own source")
+	}
+}
+
+func TestParseDuplicatePolicy(t *testing.T) {
+	if p, err := ParseDuplicatePolicy(""); err != nil || p != DuplicatesFirst {
+		t.Errorf("Expected \"\" to mean first, got %q (err %v)", p, err)
+	}
+	if p, err := ParseDuplicatePolicy("split"); err != nil || p != DuplicatesSplit {
+		t.Errorf("Expected split, got %q (err %v)", p, err)
+	}
+	if _, err := ParseDuplicatePolicy("last"); err == nil {
+		t.Error("Expected an unknown policy to fail")
+	}
+}
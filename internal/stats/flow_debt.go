@@ -1,10 +1,16 @@
 package stats
 
 import (
+	"cmp"
 	"mcs-mcp/internal/jira"
+	"slices"
 	"time"
 )
 
+// FlowDebtProjectionDays is how far ahead analyze_flow_debt projects the
+// Little's Law cycle time when flow debt continues at the window's rate.
+const FlowDebtProjectionDays = 30
+
 // CalculateFlowDebt compares arrival rates (commitment) vs. departure rates (exits) across the window.
 func CalculateFlowDebt(issues []jira.Issue, window AnalysisWindow, commitmentPoint string, weights map[string]int, resolutions map[string]string, mappings map[string]StatusMetadata) FlowDebtResult {
 	buckets := window.Subdivide()
@@ -18,29 +24,18 @@ func CalculateFlowDebt(issues []jira.Issue, window AnalysisWindow, commitmentPoi
 
 	targetWeight, hasCommitment := weights[commitmentPoint]
 
+	wip := 0
 	for _, issue := range issues {
 		// 1. Calculate Arrivals
 		if hasCommitment {
-			var arrivalDate *time.Time
-
-			// check birth
-			if bw, ok := weights[issue.BirthStatusID]; ok && bw >= targetWeight {
-				arrivalDate = &issue.Created
-			} else {
-				// check transitions
-				for _, t := range issue.Transitions {
-					if tw, ok := weights[t.ToStatusID]; ok && tw >= targetWeight {
-						arrivalDate = &t.Date
-						break
-					}
-				}
-			}
-
-			if arrivalDate != nil {
+			if arrivalDate := commitmentDate(issue, weights, targetWeight); arrivalDate != nil {
 				idx := window.FindBucketIndex(*arrivalDate)
 				if idx >= 0 && idx < len(results) {
 					results[idx].Arrivals++
 				}
+				if inFlightAt(issue, *arrivalDate, window.End) {
+					wip++
+				}
 			}
 		}
 
@@ -53,14 +48,170 @@ func CalculateFlowDebt(issues []jira.Issue, window AnalysisWindow, commitmentPoi
 		}
 	}
 
-	totalDebt := 0
+	totalDebt, departures := 0, 0
 	for i := range results {
 		results[i].Debt = results[i].Arrivals - results[i].Departures
 		totalDebt += results[i].Debt
+		departures += results[i].Departures
 	}
 
-	return FlowDebtResult{
+	res := FlowDebtResult{
 		Buckets:   results,
 		TotalDebt: totalDebt,
 	}
+	res.Tiers, res.Statuses = calculateNetFlow(issues, window, mappings)
+	accumulated := 0
+	for _, st := range res.Statuses {
+		committed := st.Tier == TierUpstream || st.Tier == TierDownstream
+		if committed && st.TotalNet > accumulated {
+			res.AccumulatingStatus, accumulated = st.Name, st.TotalNet
+		}
+	}
+	if hasCommitment {
+		res.Projection = projectCycleTimeInflation(wip, departures, totalDebt, window.DayCount())
+	}
+	return res
+}
+
+// commitmentDate returns when an issue first reached a status at or past the
+// commitment point, or nil if it never did.
+func commitmentDate(issue jira.Issue, weights map[string]int, targetWeight int) *time.Time {
+	if bw, ok := weights[issue.BirthStatusID]; ok && bw >= targetWeight {
+		return &issue.Created
+	}
+	for _, t := range issue.Transitions {
+		if tw, ok := weights[t.ToStatusID]; ok && tw >= targetWeight {
+			return &t.Date
+		}
+	}
+	return nil
+}
+
+// inFlightAt reports whether an issue committed at committed was still in
+// flight at t.
+func inFlightAt(issue jira.Issue, committed, t time.Time) bool {
+	if committed.After(t) {
+		return false
+	}
+	return !HasExited(issue) || issue.OutcomeDate == nil || issue.OutcomeDate.After(t)
+}
+
+// calculateNetFlow counts, per bucket, the items entering and leaving each
+// tier and each status. A transition between two statuses of the same tier
+// moves no item across the tier's boundary. Finished statuses are left out:
+// items only ever enter them. Statuses are ordered by tier, then by name.
+func calculateNetFlow(issues []jira.Issue, window AnalysisWindow, mappings map[string]StatusMetadata) (tiers, statuses []NetFlowSeries) {
+	buckets := window.Subdivide()
+	if len(buckets) == 0 {
+		return nil, nil
+	}
+
+	tierFlows := make(map[string]*NetFlowSeries)
+	statusFlows := make(map[string]*NetFlowSeries)
+	series := func(flows map[string]*NetFlowSeries, key, name, tier string) *NetFlowSeries {
+		f, ok := flows[key]
+		if !ok {
+			f = &NetFlowSeries{Name: name, Tier: tier, Buckets: make([]NetFlowBucket, len(buckets))}
+			for i, start := range buckets {
+				f.Buckets[i].Label = window.GenerateLabel(start)
+			}
+			flows[key] = f
+		}
+		return f
+	}
+	statusSeries := func(id, name string) *NetFlowSeries {
+		if m, ok := mappings[id]; ok && m.Name != "" {
+			name = m.Name
+		}
+		if name == "" {
+			name = id
+		}
+		return series(statusFlows, id, name, mappings[id].Tier)
+	}
+	move := func(fromID, fromName, toID, toName string, at time.Time) {
+		idx := window.FindBucketIndex(at)
+		if idx < 0 || idx >= len(buckets) || fromID == toID {
+			return
+		}
+		fromTier, toTier := mappings[fromID].Tier, mappings[toID].Tier
+		if fromID != "" && fromTier != TierFinished {
+			statusSeries(fromID, fromName).Buckets[idx].Left++
+		}
+		if toID != "" && toTier != TierFinished {
+			statusSeries(toID, toName).Buckets[idx].Entered++
+		}
+		if fromTier == toTier {
+			return
+		}
+		if fromTier != "" && fromTier != TierFinished {
+			series(tierFlows, fromTier, fromTier, fromTier).Buckets[idx].Left++
+		}
+		if toTier != "" && toTier != TierFinished {
+			series(tierFlows, toTier, toTier, toTier).Buckets[idx].Entered++
+		}
+	}
+
+	for _, issue := range issues {
+		move("", "", issue.BirthStatusID, issue.BirthStatus, issue.Created)
+		prevID, prevName := issue.BirthStatusID, issue.BirthStatus
+		for _, t := range issue.Transitions {
+			move(prevID, prevName, t.ToStatusID, t.ToStatus, t.Date)
+			prevID, prevName = t.ToStatusID, t.ToStatus
+		}
+	}
+
+	total := func(f *NetFlowSeries) NetFlowSeries {
+		for i := range f.Buckets {
+			b := &f.Buckets[i]
+			b.Net = b.Entered - b.Left
+			f.TotalNet += b.Net
+		}
+		return *f
+	}
+	tierOrder := []string{TierDemand, TierUpstream, TierDownstream}
+	for _, tier := range tierOrder {
+		if f, ok := tierFlows[tier]; ok {
+			tiers = append(tiers, total(f))
+		}
+	}
+	for _, f := range statusFlows {
+		statuses = append(statuses, total(f))
+	}
+	rank := func(tier string) int {
+		if i := slices.Index(tierOrder, tier); i >= 0 {
+			return i
+		}
+		return len(tierOrder) // unmapped statuses last
+	}
+	slices.SortFunc(statuses, func(a, b NetFlowSeries) int {
+		if c := cmp.Compare(rank(a.Tier), rank(b.Tier)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return tiers, statuses
+}
+
+// projectCycleTimeInflation applies Little's Law (CT = WIP / throughput) to
+// the WIP at the end of the window and projects it FlowDebtProjectionDays
+// ahead, assuming arrivals keep outpacing departures at the window's rate.
+func projectCycleTimeInflation(wip, departures, totalDebt, days int) *CycleTimeProjection {
+	if days <= 0 || departures == 0 || wip == 0 {
+		return nil
+	}
+	throughput := float64(departures) / float64(days)
+	debtRate := float64(totalDebt) / float64(days)
+	projectedWIP := max(0, float64(wip)+debtRate*FlowDebtProjectionDays)
+	current := float64(wip) / throughput
+	projected := projectedWIP / throughput
+	return &CycleTimeProjection{
+		WIP:                    wip,
+		ThroughputPerDay:       Round2(throughput),
+		CycleTimeDays:          Round2(current),
+		DebtPerDay:             Round2(debtRate),
+		HorizonDays:            FlowDebtProjectionDays,
+		ProjectedWIP:           Round2(projectedWIP),
+		ProjectedCycleTimeDays: Round2(projected),
+		InflationPct:           Round2((projected - current) / current * 100),
+	}
 }
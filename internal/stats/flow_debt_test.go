@@ -99,3 +99,72 @@ func TestCalculateFlowDebt(t *testing.T) {
 		t.Errorf("Expected total debt 1 (3 arrivals - 2 departures), got %d", res.TotalDebt)
 	}
 }
+
+func TestCalculateFlowDebt_NetFlow(t *testing.T) {
+	start := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC) // Monday
+	day := func(d int) time.Time { return start.AddDate(0, 0, d) }
+	weights := map[string]int{"1": 1, "2": 2, "3": 3, "4": 4}
+	mappings := map[string]StatusMetadata{
+		"1": {Name: "Backlog", Tier: TierDemand},
+		"2": {Name: "Development", Tier: TierDownstream},
+		"3": {Name: "Validation", Tier: TierDownstream},
+		"4": {Name: "Done", Tier: TierFinished, Outcome: "delivered"},
+	}
+
+	// Four items reach Development in week 1; all move on to Validation,
+	// only one leaves it for Done.
+	var issues []jira.Issue
+	for i := range 4 {
+		issue := jira.Issue{
+			Key:           "N",
+			BirthStatusID: "1",
+			BirthStatus:   "Backlog",
+			Created:       day(0),
+			Transitions: []jira.StatusTransition{
+				{ToStatusID: "2", ToStatus: "Development", Date: day(1)},
+				{ToStatusID: "3", ToStatus: "Validation", Date: day(8)},
+			},
+		}
+		if i == 0 {
+			done := day(9)
+			issue.Transitions = append(issue.Transitions, jira.StatusTransition{ToStatusID: "4", ToStatus: "Done", Date: done})
+			issue.OutcomeDate, issue.Outcome = &done, "delivered"
+		}
+		issues = append(issues, issue)
+	}
+	window := NewAnalysisWindow(start, day(13), "week", time.Time{})
+
+	res := CalculateFlowDebt(issues, window, "2", weights, nil, mappings)
+
+	if len(res.Tiers) != 2 || res.Tiers[0].Name != TierDemand || res.Tiers[1].Name != TierDownstream {
+		t.Fatalf("Expected Demand and Downstream tiers, got %+v", res.Tiers)
+	}
+	downstream := res.Tiers[1]
+	if downstream.Buckets[0].Entered != 4 || downstream.Buckets[1].Left != 1 || downstream.TotalNet != 3 {
+		t.Errorf("Expected 4 items into Downstream and 1 out, got %+v", downstream)
+	}
+	byName := make(map[string]NetFlowSeries)
+	for _, st := range res.Statuses {
+		byName[st.Name] = st
+	}
+	if _, ok := byName["Done"]; ok {
+		t.Error("Expected Finished statuses to be left out")
+	}
+	if byName["Development"].TotalNet != 0 || byName["Validation"].TotalNet != 3 {
+		t.Errorf("Expected Development to drain and Validation to accumulate 3, got %+v", res.Statuses)
+	}
+	if res.AccumulatingStatus != "Validation" {
+		t.Errorf("Expected Validation to accumulate, got %q", res.AccumulatingStatus)
+	}
+
+	p := res.Projection
+	if p == nil {
+		t.Fatal("Expected a Little's Law projection")
+	}
+	if p.WIP != 3 || p.ThroughputPerDay != Round2(1.0/13) || p.CycleTimeDays != 39 {
+		t.Errorf("Expected 3 items at 1/13 per day, a 39-day cycle time, got %+v", p)
+	}
+	if p.ProjectedWIP <= 3 || p.InflationPct <= 0 {
+		t.Errorf("Expected positive debt to inflate the cycle time, got %+v", p)
+	}
+}
@@ -0,0 +1,174 @@
+package stats
+
+import (
+	"cmp"
+	"mcs-mcp/internal/jira"
+	"slices"
+)
+
+// FlowEfficiencyItem splits the cycle time of one delivered item into the time
+// spent in active and in queue statuses.
+type FlowEfficiencyItem struct {
+	Key              string  `json:"key"`
+	Type             string  `json:"type"`
+	CycleTimeDays    float64 `json:"cycle_time_days"`
+	ActiveDays       float64 `json:"active_days"`
+	QueueDays        float64 `json:"queue_days"`
+	UnclassifiedDays float64 `json:"unclassified_days,omitempty"` // Statuses without an active or queue role
+	Efficiency       float64 `json:"efficiency"`                  // ActiveDays / (ActiveDays + QueueDays)
+}
+
+// FlowEfficiencyTier aggregates flow efficiency over the statuses of one tier.
+type FlowEfficiencyTier struct {
+	Tier          string  `json:"tier"`
+	Items         int     `json:"items"` // Items with classified time in the tier
+	ActiveDays    float64 `json:"active_days"`
+	QueueDays     float64 `json:"queue_days"`
+	Efficiency    float64 `json:"efficiency"`     // Pooled: total active / total classified time
+	P50Efficiency float64 `json:"p50_efficiency"` // Median of the items' efficiency within the tier
+}
+
+// FlowEfficiencyPercentiles are percentiles of the item-level flow efficiency.
+type FlowEfficiencyPercentiles struct {
+	P15 float64 `json:"p15"`
+	P50 float64 `json:"p50"`
+	P85 float64 `json:"p85"`
+}
+
+// FlowEfficiencyResult is the flow efficiency of a set of delivered items.
+type FlowEfficiencyResult struct {
+	Items                []FlowEfficiencyItem      `json:"items"` // Lowest efficiency first
+	Efficiency           float64                   `json:"efficiency"`
+	Percentiles          FlowEfficiencyPercentiles `json:"percentiles"`
+	Coverage             float64                   `json:"coverage"` // Share of cycle time in active or queue statuses
+	ByTier               []FlowEfficiencyTier      `json:"by_tier"`
+	UnclassifiedStatuses []string                  `json:"unclassified_statuses,omitempty"` // Status IDs in the cycle time without an active or queue role
+}
+
+// CalculateFlowEfficiency splits the cycle time of each delivered item into
+// active and queue residency using the roles of the workflow mapping.
+//
+// rangeFor returns the statuses that make up the cycle time of an issue type,
+// as used for the cycle time itself. Time in statuses without an active or
+// queue role is reported as unclassified and left out of the efficiency.
+func CalculateFlowEfficiency(issues []jira.Issue, rangeFor func(issueType string) []string, mappings map[string]StatusMetadata) FlowEfficiencyResult {
+	type tierAcc struct {
+		active, queue float64
+		efficiencies  []float64
+	}
+	tiers := make(map[string]*tierAcc)
+	unclassified := make(map[string]bool)
+
+	res := FlowEfficiencyResult{Items: make([]FlowEfficiencyItem, 0, len(issues)), ByTier: make([]FlowEfficiencyTier, 0)}
+	var totalActive, totalQueue, totalCycle float64
+	var efficiencies []float64
+	for _, issue := range issues {
+		item := FlowEfficiencyItem{Key: issue.Key, Type: issue.IssueType}
+		itemTiers := make(map[string]*[2]float64) // tier → {active, queue}
+		for _, status := range rangeFor(issue.IssueType) {
+			seconds, ok := issue.StatusResidency[status]
+			if !ok {
+				continue
+			}
+			days := float64(seconds) / 86400.0
+			item.CycleTimeDays += days
+
+			m := mappings[status]
+			slot := -1
+			switch m.Role {
+			case "active":
+				item.ActiveDays += days
+				slot = 0
+			case "queue":
+				item.QueueDays += days
+				slot = 1
+			default:
+				item.UnclassifiedDays += days
+				if days > 0 {
+					unclassified[status] = true
+				}
+			}
+			if slot >= 0 && m.Tier != "" {
+				if itemTiers[m.Tier] == nil {
+					itemTiers[m.Tier] = &[2]float64{}
+				}
+				itemTiers[m.Tier][slot] += days
+			}
+		}
+		if item.CycleTimeDays <= 0 {
+			continue
+		}
+
+		classified := item.ActiveDays + item.QueueDays
+		if classified > 0 {
+			item.Efficiency = item.ActiveDays / classified
+			efficiencies = append(efficiencies, item.Efficiency)
+		}
+		totalActive += item.ActiveDays
+		totalQueue += item.QueueDays
+		totalCycle += item.CycleTimeDays
+
+		for tier, t := range itemTiers {
+			acc := tiers[tier]
+			if acc == nil {
+				acc = &tierAcc{}
+				tiers[tier] = acc
+			}
+			acc.active += t[0]
+			acc.queue += t[1]
+			if t[0]+t[1] > 0 {
+				acc.efficiencies = append(acc.efficiencies, t[0]/(t[0]+t[1]))
+			}
+		}
+
+		item.CycleTimeDays = Round2(item.CycleTimeDays)
+		item.ActiveDays = Round2(item.ActiveDays)
+		item.QueueDays = Round2(item.QueueDays)
+		item.UnclassifiedDays = Round2(item.UnclassifiedDays)
+		item.Efficiency = Round2(item.Efficiency)
+		res.Items = append(res.Items, item)
+	}
+
+	slices.SortStableFunc(res.Items, func(a, b FlowEfficiencyItem) int {
+		if c := cmp.Compare(a.Efficiency, b.Efficiency); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.CycleTimeDays, a.CycleTimeDays)
+	})
+	if totalActive+totalQueue > 0 {
+		res.Efficiency = Round2(totalActive / (totalActive + totalQueue))
+	}
+	if totalCycle > 0 {
+		res.Coverage = Round2((totalActive + totalQueue) / totalCycle)
+	}
+	slices.Sort(efficiencies)
+	res.Percentiles = FlowEfficiencyPercentiles{
+		P15: Round2(PercentileOfSorted(efficiencies, 0.15)),
+		P50: Round2(PercentileOfSorted(efficiencies, 0.50)),
+		P85: Round2(PercentileOfSorted(efficiencies, 0.85)),
+	}
+
+	for _, tier := range []string{TierDemand, TierUpstream, TierDownstream, TierFinished} {
+		acc, ok := tiers[tier]
+		if !ok {
+			continue
+		}
+		t := FlowEfficiencyTier{
+			Tier:          tier,
+			Items:         len(acc.efficiencies),
+			ActiveDays:    Round2(acc.active),
+			QueueDays:     Round2(acc.queue),
+			P50Efficiency: Round2(PercentileOf(acc.efficiencies, 0.50)),
+		}
+		if acc.active+acc.queue > 0 {
+			t.Efficiency = Round2(acc.active / (acc.active + acc.queue))
+		}
+		res.ByTier = append(res.ByTier, t)
+	}
+
+	for status := range unclassified {
+		res.UnclassifiedStatuses = append(res.UnclassifiedStatuses, status)
+	}
+	slices.Sort(res.UnclassifiedStatuses)
+	return res
+}
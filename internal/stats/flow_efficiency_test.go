@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+)
+
+func TestCalculateFlowEfficiency(t *testing.T) {
+	const day = 86400
+	mappings := map[string]StatusMetadata{
+		"refine":  {Role: "queue", Tier: TierUpstream},
+		"ready":   {Role: "queue", Tier: TierDownstream},
+		"dev":     {Role: "active", Tier: TierDownstream},
+		"blocked": {Role: "ignore", Tier: TierDownstream},
+	}
+	issues := []jira.Issue{
+		{Key: "A-1", IssueType: "Story", StatusResidency: map[string]int64{"backlog": 30 * day, "ready": 2 * day, "dev": 6 * day}},
+		{Key: "A-2", IssueType: "Story", StatusResidency: map[string]int64{"ready": 6 * day, "dev": 2 * day, "blocked": 2 * day}},
+		{Key: "A-3", IssueType: "Bug", StatusResidency: map[string]int64{"refine": 2 * day, "dev": 2 * day}},
+		{Key: "A-4", IssueType: "Story", StatusResidency: map[string]int64{"backlog": 5 * day}}, // nothing in the cycle time range
+	}
+	rangeFor := func(issueType string) []string {
+		if issueType == "Bug" {
+			return []string{"refine", "ready", "dev", "blocked"}
+		}
+		return []string{"ready", "dev", "blocked"}
+	}
+
+	res := CalculateFlowEfficiency(issues, rangeFor, mappings)
+	if len(res.Items) != 3 {
+		t.Fatalf("Expected the item without cycle time to be skipped, got %d items", len(res.Items))
+	}
+	if first := res.Items[0]; first.Key != "A-2" || first.Efficiency != 0.25 || first.UnclassifiedDays != 2 || first.CycleTimeDays != 10 {
+		t.Errorf("Expected A-2 (2 active of 8 classified days) first, got %+v", first)
+	}
+	if last := res.Items[2]; last.Key != "A-1" || last.Efficiency != 0.75 {
+		t.Errorf("Expected A-1 (backlog outside the range) last with 0.75, got %+v", last)
+	}
+	// 10 active of 20 classified days, and 20 of 22 cycle time days classified.
+	if res.Efficiency != 0.5 || res.Coverage != 0.91 {
+		t.Errorf("Expected pooled efficiency 0.5 and coverage 0.91, got %v and %v", res.Efficiency, res.Coverage)
+	}
+	if res.Percentiles.P50 != 0.5 {
+		t.Errorf("Expected a median efficiency of 0.5, got %+v", res.Percentiles)
+	}
+	if len(res.UnclassifiedStatuses) != 1 || res.UnclassifiedStatuses[0] != "blocked" {
+		t.Errorf("Expected 'blocked' as the only unclassified status, got %v", res.UnclassifiedStatuses)
+	}
+
+	if len(res.ByTier) != 2 || res.ByTier[0].Tier != TierUpstream || res.ByTier[1].Tier != TierDownstream {
+		t.Fatalf("Expected Upstream then Downstream, got %+v", res.ByTier)
+	}
+	if up := res.ByTier[0]; up.Items != 1 || up.Efficiency != 0 || up.QueueDays != 2 {
+		t.Errorf("Expected the Upstream tier to be pure queue time of A-3, got %+v", up)
+	}
+	if down := res.ByTier[1]; down.Items != 3 || down.ActiveDays != 10 || down.QueueDays != 8 || down.Efficiency != 0.56 {
+		t.Errorf("Unexpected Downstream breakdown %+v", down)
+	}
+}
@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// RollupResult summarizes the work items below a parent (epic or initiative).
+// Only leaf items count as work; intermediate parents (e.g. epics under an
+// initiative) are containers whose progress is the progress of their children.
+type RollupResult struct {
+	RootKey         string         `json:"root_key"`
+	Containers      int            `json:"containers"`        // Intermediate parents below the root
+	Leaves          int            `json:"leaves"`            // Work items below the root
+	Delivered       int            `json:"delivered"`         // Leaves with a delivered outcome
+	Abandoned       int            `json:"abandoned"`         // Leaves with an abandoned outcome
+	WIP             int            `json:"wip"`               // Unfinished leaves past their commitment point
+	Backlog         int            `json:"backlog"`           // Unfinished leaves not yet committed
+	RemainingByType map[string]int `json:"remaining_by_type"` // Unfinished leaves per issue type
+	RemainingKeys   []string       `json:"remaining_keys"`
+	PercentComplete float64        `json:"percent_complete"` // Delivered / (Delivered + remaining)
+	MaxDepth        int            `json:"max_depth"`        // 1 = direct children only
+}
+
+// Round rounds all numeric fields to 2 decimal places for output compactness.
+func (r *RollupResult) Round() {
+	r.PercentComplete = Round2(r.PercentComplete)
+}
+
+// RollupDescendants walks the parent hierarchy below rootKey (breadth first,
+// cycle-safe) and classifies every leaf: finished by outcome, otherwise WIP when
+// its current status weight is at or past the commitment point that applies to
+// its type, and backlog when not.
+func RollupDescendants(issues []jira.Issue, rootKey string, commitments CommitmentPoints, weights map[string]int) RollupResult {
+	children := make(map[string][]jira.Issue)
+	for _, issue := range issues {
+		if issue.ParentKey != "" {
+			children[issue.ParentKey] = append(children[issue.ParentKey], issue)
+		}
+	}
+
+	res := RollupResult{RootKey: rootKey, RemainingByType: make(map[string]int)}
+	seen := map[string]bool{rootKey: true}
+	level := []string{rootKey}
+	for depth := 1; len(level) > 0; depth++ {
+		var next []string
+		for _, parent := range level {
+			for _, child := range children[parent] {
+				if seen[child.Key] {
+					continue
+				}
+				seen[child.Key] = true
+				res.MaxDepth = depth
+				if len(children[child.Key]) > 0 {
+					res.Containers++
+					next = append(next, child.Key)
+					continue
+				}
+				res.classifyLeaf(child, commitments, weights)
+			}
+		}
+		level = next
+	}
+
+	slices.Sort(res.RemainingKeys)
+	if total := res.Delivered + res.WIP + res.Backlog; total > 0 {
+		res.PercentComplete = float64(res.Delivered) / float64(total) * 100
+	}
+	return res
+}
+
+func (r *RollupResult) classifyLeaf(issue jira.Issue, commitments CommitmentPoints, weights map[string]int) {
+	r.Leaves++
+	switch issue.Outcome {
+	case "delivered":
+		r.Delivered++
+		return
+	case "abandoned":
+		r.Abandoned++
+		return
+	}
+
+	cw, hasCommitment := weights[commitments.For(issue.IssueType)]
+	if w, ok := weights[issue.StatusID]; ok && hasCommitment && w >= cw {
+		r.WIP++
+	} else {
+		r.Backlog++
+	}
+	r.RemainingByType[issue.IssueType]++
+	r.RemainingKeys = append(r.RemainingKeys, issue.Key)
+}
@@ -0,0 +1,54 @@
+package stats
+
+import (
+	"testing"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestRollupDescendants(t *testing.T) {
+	weights := map[string]int{"todo": 1, "dev": 2, "review": 3, "done": 4}
+	commitments := CommitmentPoints{Default: "dev", ByType: map[string]string{"Bug": "review"}}
+
+	issues := []jira.Issue{
+		{Key: "INIT-1", IssueType: "Initiative", StatusID: "dev"},
+		{Key: "EP-1", IssueType: "Epic", StatusID: "dev", ParentKey: "INIT-1"},
+		{Key: "EP-2", IssueType: "Epic", StatusID: "todo", ParentKey: "INIT-1"},
+		{Key: "S-1", IssueType: "Story", StatusID: "done", ParentKey: "EP-1", Outcome: "delivered"},
+		{Key: "S-2", IssueType: "Story", StatusID: "dev", ParentKey: "EP-1"},
+		{Key: "S-3", IssueType: "Story", StatusID: "todo", ParentKey: "EP-2"},
+		{Key: "B-1", IssueType: "Bug", StatusID: "dev", ParentKey: "EP-2"}, // Bugs commit at review
+		{Key: "S-4", IssueType: "Story", StatusID: "done", ParentKey: "EP-2", Outcome: "abandoned"},
+		{Key: "S-9", IssueType: "Story", StatusID: "dev", ParentKey: "OTHER-1"},
+	}
+
+	res := RollupDescendants(issues, "INIT-1", commitments, weights)
+
+	if res.Containers != 2 || res.Leaves != 5 || res.MaxDepth != 2 {
+		t.Errorf("Expected 2 containers, 5 leaves at depth 2, got %+v", res)
+	}
+	if res.Delivered != 1 || res.Abandoned != 1 || res.WIP != 1 || res.Backlog != 2 {
+		t.Errorf("Expected delivered=1 abandoned=1 wip=1 backlog=2, got %+v", res)
+	}
+	if res.RemainingByType["Story"] != 2 || res.RemainingByType["Bug"] != 1 {
+		t.Errorf("Unexpected remaining mix %v", res.RemainingByType)
+	}
+	if len(res.RemainingKeys) != 3 || res.RemainingKeys[0] != "B-1" {
+		t.Errorf("Expected sorted remaining keys, got %v", res.RemainingKeys)
+	}
+	if res.PercentComplete != 25 {
+		t.Errorf("Expected 25%% complete (abandoned excluded), got %v", res.PercentComplete)
+	}
+}
+
+func TestRollupDescendants_CycleSafe(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "A", ParentKey: "B", IssueType: "Epic"},
+		{Key: "B", ParentKey: "A", IssueType: "Epic"},
+		{Key: "C", ParentKey: "B", IssueType: "Story"},
+	}
+	res := RollupDescendants(issues, "A", CommitmentPoints{}, nil)
+	if res.Containers != 1 || res.Leaves != 1 || res.Backlog != 1 {
+		t.Errorf("Expected the cycle back to the root to be ignored, got %+v", res)
+	}
+}
@@ -0,0 +1,33 @@
+package stats
+
+import (
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+)
+
+// IdentityOf returns the identity map of issues: their former keys, from
+// moves between projects, to their current key.
+func IdentityOf(issues []jira.Issue) eventlog.IdentityMap {
+	ids := make(eventlog.IdentityMap)
+	for _, issue := range issues {
+		for _, key := range issue.FormerKeys {
+			ids[key] = issue.Key
+		}
+	}
+	return ids
+}
+
+// relinkMovedParents points the parent links of issues that still name a
+// moved parent by a former key to the parent's current key.
+func relinkMovedParents(ids eventlog.IdentityMap, lists ...[]jira.Issue) {
+	if len(ids) == 0 {
+		return
+	}
+	for _, issues := range lists {
+		for i := range issues {
+			if issues[i].ParentKey != "" {
+				issues[i].ParentKey = ids.Resolve(issues[i].ParentKey)
+			}
+		}
+	}
+}
@@ -0,0 +1,26 @@
+package stats
+
+import (
+	"testing"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestIdentityOf_RelinksAndDeduplicates(t *testing.T) {
+	parent := jira.Issue{Key: "NEW-1", FormerKeys: []string{"OLD-1"}}
+	child := jira.Issue{Key: "NEW-2", ParentKey: "OLD-1"}
+	issues := []jira.Issue{parent, child}
+
+	relinkMovedParents(IdentityOf(issues), issues)
+	if issues[1].ParentKey != "NEW-1" {
+		t.Errorf("Expected the child to link to the parent's current key, got %s", issues[1].ParentKey)
+	}
+
+	attr := AttributeDuplicates([][]jira.Issue{{{Key: "OLD-1"}}, {parent}}, DuplicatesFirst)
+	if got := attr.Shared(); len(got) != 1 || got[0] != "NEW-1" {
+		t.Errorf("Expected the moved issue to be one shared issue, got %v", got)
+	}
+	if !attr.Counts(0, "OLD-1") || attr.Counts(1, "NEW-1") {
+		t.Error("Expected the issue to count once, on the first source listing it")
+	}
+}
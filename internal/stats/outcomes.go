@@ -0,0 +1,56 @@
+package stats
+
+import (
+	"fmt"
+
+	"mcs-mcp/internal/jira"
+)
+
+// OutcomeScope decides which finished items count as done in throughput,
+// cycle-time, and forecasting analyses.
+type OutcomeScope string
+
+const (
+	// OutcomesDelivered counts the delivered items only (default): the
+	// basis of SLEs and of forecasts of delivered value.
+	OutcomesDelivered OutcomeScope = "delivered"
+	// OutcomesAbandoned counts the abandoned items only.
+	OutcomesAbandoned OutcomeScope = "abandoned"
+	// OutcomesAll counts every finished item, delivered or abandoned: the
+	// capacity the system processed.
+	OutcomesAll OutcomeScope = "all"
+)
+
+// ParseOutcomeScope validates a scope name; "" is OutcomesDelivered.
+func ParseOutcomeScope(s string) (OutcomeScope, error) {
+	switch o := OutcomeScope(s); o {
+	case "":
+		return OutcomesDelivered, nil
+	case OutcomesDelivered, OutcomesAbandoned, OutcomesAll:
+		return o, nil
+	}
+	return "", fmt.Errorf("invalid outcome scope %q: expected delivered, abandoned, or all", s)
+}
+
+// Includes reports whether issue counts as done in scope; "" is
+// OutcomesDelivered.
+func (o OutcomeScope) Includes(issue jira.Issue) bool {
+	switch o {
+	case OutcomesAbandoned:
+		return HasExited(issue) && !IsDelivered(issue)
+	case OutcomesAll:
+		return HasExited(issue)
+	}
+	return IsDelivered(issue)
+}
+
+// FilterOutcomes returns the issues that count as done in scope.
+func FilterOutcomes(issues []jira.Issue, scope OutcomeScope) []jira.Issue {
+	var out []jira.Issue
+	for _, issue := range issues {
+		if scope.Includes(issue) {
+			out = append(out, issue)
+		}
+	}
+	return out
+}
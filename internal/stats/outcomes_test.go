@@ -0,0 +1,59 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/eventlog"
+	"mcs-mcp/internal/jira"
+)
+
+func TestSessionOutcomeScope(t *testing.T) {
+	day := func(d int) int64 {
+		return time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, d).UnixMicro()
+	}
+	events := []eventlog.IssueEvent{
+		{IssueKey: "PROJ-1", IssueType: "Story", EventType: eventlog.Created, ToStatus: "Open", Timestamp: day(0)},
+		{IssueKey: "PROJ-1", IssueType: "Story", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Done", Resolution: "Done", Timestamp: day(4)},
+		{IssueKey: "PROJ-2", IssueType: "Story", EventType: eventlog.Created, ToStatus: "Open", Timestamp: day(0)},
+		{IssueKey: "PROJ-2", IssueType: "Story", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Cancelled", Resolution: "Won't Do", Timestamp: day(6)},
+		{IssueKey: "PROJ-3", IssueType: "Story", EventType: eventlog.Created, ToStatus: "Open", Timestamp: day(0)},
+	}
+	mappings := map[string]StatusMetadata{
+		"Open":      {Tier: TierDemand},
+		"Done":      {Tier: TierFinished, Outcome: "delivered"},
+		"Cancelled": {Tier: TierFinished, Outcome: "abandoned"},
+	}
+	window := AnalysisWindow{End: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}
+	resolutions := map[string]string{"Done": "delivered", "Won't Do": "abandoned"}
+
+	session := NewAnalysisSession(events, "PROJ", jira.SourceContext{}, mappings, resolutions, window)
+	if got := session.GetDelivered(); len(got) != 1 || got[0].Key != "PROJ-1" {
+		t.Errorf("delivered: expected only PROJ-1, got %v", got)
+	}
+
+	session.WithOutcomeScope(OutcomesAbandoned)
+	if got := session.GetDelivered(); len(got) != 1 || got[0].Key != "PROJ-2" {
+		t.Errorf("abandoned: expected only PROJ-2, got %v", got)
+	}
+
+	session.WithOutcomeScope(OutcomesAll)
+	if got := len(session.GetDelivered()); got != 2 {
+		t.Errorf("all: expected PROJ-1 and PROJ-2, got %d", got)
+	}
+	if got := len(session.GetFinished()); got != 2 {
+		t.Errorf("Expected the finished items to ignore the scope, got %d", got)
+	}
+}
+
+func TestParseOutcomeScope(t *testing.T) {
+	if o, err := ParseOutcomeScope(""); err != nil || o != OutcomesDelivered {
+		t.Errorf("Expected \"\" to mean delivered, got %q (err %v)", o, err)
+	}
+	if o, err := ParseOutcomeScope("all"); err != nil || o != OutcomesAll {
+		t.Errorf("Expected all, got %q (err %v)", o, err)
+	}
+	if _, err := ParseOutcomeScope("resolved"); err == nil {
+		t.Error("Expected an unknown scope to fail")
+	}
+}
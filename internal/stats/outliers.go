@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"fmt"
+	"slices"
+)
+
+// OutlierPolicy decides how extreme values of a sample (cycle times, daily
+// throughput) are treated before percentiles and forecasts are taken.
+type OutlierPolicy string
+
+const (
+	// OutliersNone keeps every value (default).
+	OutliersNone OutlierPolicy = "none"
+	// OutliersWinsorize caps values above the P99 of the sample at the P99.
+	OutliersWinsorize OutlierPolicy = "winsorize"
+	// OutliersIQR drops values outside the Tukey fences
+	// Q1 − 1.5·IQR and Q3 + 1.5·IQR.
+	OutliersIQR OutlierPolicy = "iqr"
+)
+
+// ParseOutlierPolicy validates a policy name; "" is OutliersNone.
+func ParseOutlierPolicy(s string) (OutlierPolicy, error) {
+	switch p := OutlierPolicy(s); p {
+	case "":
+		return OutliersNone, nil
+	case OutliersNone, OutliersWinsorize, OutliersIQR:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid outlier policy %q: expected none, winsorize, or iqr", s)
+}
+
+// OutlierTrim reports what an outlier policy did to a sample.
+type OutlierTrim struct {
+	Policy  OutlierPolicy `json:"policy"`
+	Trimmed int           `json:"items_trimmed"`         // values capped (winsorize) or dropped (iqr)
+	Lower   *float64      `json:"lower_bound,omitempty"` // iqr only
+	Upper   *float64      `json:"upper_bound,omitempty"`
+}
+
+// outlierBounds returns the range of values policy keeps as they are, or
+// ok=false when it keeps everything.
+func outlierBounds(values []float64, policy OutlierPolicy) (lower, upper float64, ok bool) {
+	if len(values) < 2 || (policy != OutliersWinsorize && policy != OutliersIQR) {
+		return 0, 0, false
+	}
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	if policy == OutliersWinsorize {
+		return sorted[0], CalculatePercentileInterpolated(sorted, 99), true
+	}
+	q1 := CalculatePercentileInterpolated(sorted, 25)
+	q3 := CalculatePercentileInterpolated(sorted, 75)
+	iqr := q3 - q1
+	return q1 - 1.5*iqr, q3 + 1.5*iqr, true
+}
+
+// TrimOutliers applies policy to values without modifying them. It returns
+// the treated values, the indices into values they came from (so aligned
+// slices can follow), and a report of what was trimmed.
+func TrimOutliers(values []float64, policy OutlierPolicy) ([]float64, []int, OutlierTrim) {
+	report := OutlierTrim{Policy: policy}
+	kept := make([]int, 0, len(values))
+	lower, upper, ok := outlierBounds(values, policy)
+	if !ok {
+		for i := range values {
+			kept = append(kept, i)
+		}
+		return slices.Clone(values), kept, report
+	}
+
+	out := make([]float64, 0, len(values))
+	for i, v := range values {
+		switch {
+		case policy == OutliersWinsorize && v > upper:
+			out = append(out, upper)
+			kept = append(kept, i)
+			report.Trimmed++
+		case policy == OutliersIQR && (v < lower || v > upper):
+			report.Trimmed++
+		default:
+			out = append(out, v)
+			kept = append(kept, i)
+		}
+	}
+	upper = Round2(upper)
+	report.Upper = &upper
+	if policy == OutliersIQR {
+		lower = Round2(lower)
+		report.Lower = &lower
+	}
+	return out, kept, report
+}
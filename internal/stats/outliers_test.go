@@ -0,0 +1,43 @@
+package stats
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestTrimOutliers(t *testing.T) {
+	values := []float64{5, 1, 400, 2, 3, 4, 6, 7, 8, 9}
+
+	out, kept, report := TrimOutliers(values, OutliersNone)
+	if !slices.Equal(out, values) || len(kept) != len(values) || report.Trimmed != 0 {
+		t.Errorf("none: expected the sample unchanged, got %v (%+v)", out, report)
+	}
+
+	out, kept, report = TrimOutliers(values, OutliersWinsorize)
+	if report.Trimmed != 1 || len(out) != len(values) || out[2] >= 400 || Round2(out[2]) != *report.Upper {
+		t.Errorf("winsorize: expected 400 capped at the P99, got %v (%+v)", out, report)
+	}
+	if values[2] != 400 {
+		t.Error("Expected the input to be left unmodified")
+	}
+
+	out, kept, report = TrimOutliers(values, OutliersIQR)
+	if report.Trimmed != 1 || slices.Contains(out, 400) || slices.Contains(kept, 2) || len(kept) != len(out) {
+		t.Errorf("iqr: expected 400 dropped, got %v at %v (%+v)", out, kept, report)
+	}
+	if report.Lower == nil || *report.Lower != -3.5 || *report.Upper != 14.5 {
+		t.Errorf("iqr: expected fences -3.5 and 14.5, got %+v", report)
+	}
+}
+
+func TestParseOutlierPolicy(t *testing.T) {
+	if p, err := ParseOutlierPolicy(""); err != nil || p != OutliersNone {
+		t.Errorf("Expected \"\" to mean none, got %q (err %v)", p, err)
+	}
+	if p, err := ParseOutlierPolicy("iqr"); err != nil || p != OutliersIQR {
+		t.Errorf("Expected iqr, got %q (err %v)", p, err)
+	}
+	if _, err := ParseOutlierPolicy("p95"); err == nil {
+		t.Error("Expected an unknown policy to fail")
+	}
+}
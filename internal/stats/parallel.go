@@ -0,0 +1,63 @@
+package stats
+
+import (
+	"runtime"
+	"sync"
+)
+
+// minParallelChunk is the smallest number of items a goroutine of
+// parallelChunks works on; inputs below twice this size run serially, where
+// the goroutine overhead would outweigh the gain.
+const minParallelChunk = 512
+
+// parallelChunks splits the range [0, n) into contiguous chunks, runs work on
+// each chunk in its own goroutine, and returns the chunk results in range
+// order, so callers that merge them in order get the same result as a serial
+// pass. The number of chunks is bounded by GOMAXPROCS.
+func parallelChunks[T any](n int, work func(lo, hi int) T) []T {
+	if n <= 0 {
+		return nil
+	}
+	chunks := min(runtime.GOMAXPROCS(0), n/minParallelChunk)
+	if chunks <= 1 {
+		return []T{work(0, n)}
+	}
+
+	size := (n + chunks - 1) / chunks
+	results := make([]T, chunks)
+	var wg sync.WaitGroup
+	for c := range chunks {
+		lo := c * size
+		hi := min(lo+size, n)
+		wg.Go(func() {
+			results[c] = work(lo, hi)
+		})
+	}
+	wg.Wait()
+	return results
+}
+
+// parallelGroups runs work once per key concurrently and collects the results
+// by key. It is used for stratified analyses, where each group is independent.
+func parallelGroups[K comparable, V, R any](groups map[K]V, work func(K, V) R) map[K]R {
+	res := make(map[K]R, len(groups))
+	if len(groups) <= 1 {
+		for k, v := range groups {
+			res[k] = work(k, v)
+		}
+		return res
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for k, v := range groups {
+		wg.Go(func() {
+			r := work(k, v)
+			mu.Lock()
+			res[k] = r
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+	return res
+}
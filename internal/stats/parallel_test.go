@@ -0,0 +1,136 @@
+package stats
+
+import (
+	"fmt"
+	"mcs-mcp/internal/jira"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+)
+
+// syntheticIssues builds n issues walking a five-status workflow, the shape
+// of a large board's event log.
+func syntheticIssues(n int) []jira.Issue {
+	statuses := []string{"Open", "Refinement", "In Progress", "Review", "Done"}
+	types := []string{"Story", "Bug", "Task"}
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	issues := make([]jira.Issue, n)
+	for i := range issues {
+		created := base.Add(time.Duration(i%365) * 24 * time.Hour)
+		reached := 1 + i%len(statuses)
+		issue := jira.Issue{
+			Key:              fmt.Sprintf("SYN-%d", i),
+			IssueType:        types[i%len(types)],
+			Created:          created,
+			BirthStatus:      statuses[0],
+			BirthStatusID:    statuses[0],
+			StatusResidency:  make(map[string]int64),
+			BlockedResidency: make(map[string]int64),
+		}
+		at := created
+		for s := range reached {
+			seconds := int64((1 + (i*7+s*13)%20) * 86400)
+			issue.StatusResidency[statuses[s]] = seconds
+			if (i+s)%9 == 0 {
+				issue.BlockedResidency[statuses[s]] = seconds / 3
+			}
+			at = at.Add(time.Duration(seconds) * time.Second)
+			if s+1 < reached {
+				issue.Transitions = append(issue.Transitions, jira.StatusTransition{
+					FromStatus: statuses[s], FromStatusID: statuses[s],
+					ToStatus: statuses[s+1], ToStatusID: statuses[s+1],
+					Date: at,
+				})
+			}
+		}
+		issue.Status = statuses[reached-1]
+		issue.StatusID = issue.Status
+		issues[i] = issue
+	}
+	return issues
+}
+
+var syntheticMappings = map[string]StatusMetadata{
+	"Open":        {Tier: "Demand", Role: "active"},
+	"Refinement":  {Tier: "Upstream", Role: "active"},
+	"In Progress": {Tier: "Downstream", Role: "active"},
+	"Review":      {Tier: "Downstream", Role: "active"},
+	"Done":        {Tier: "Finished", Role: "terminal"},
+}
+
+var syntheticWeights = map[string]int{"Open": 1, "Refinement": 2, "In Progress": 3, "Review": 4, "Done": 5}
+
+func TestParallelChunks(t *testing.T) {
+	for _, n := range []int{1, minParallelChunk, 10*minParallelChunk + 7} {
+		chunks := parallelChunks(n, func(lo, hi int) []int {
+			var idx []int
+			for i := lo; i < hi; i++ {
+				idx = append(idx, i)
+			}
+			return idx
+		})
+		if got := slices.Concat(chunks...); len(got) != n || !slices.IsSorted(got) || got[0] != 0 || got[n-1] != n-1 {
+			t.Errorf("n=%d: chunks do not cover the range in order", n)
+		}
+	}
+	if got := parallelChunks(0, func(lo, hi int) int { return hi - lo }); got != nil {
+		t.Errorf("expected no chunks for an empty range, got %v", got)
+	}
+}
+
+func TestCalculateInventoryAge_ParallelMatchesSerial(t *testing.T) {
+	issues := syntheticIssues(8 * minParallelChunk)
+	persistence := []float64{2, 4, 6, 8, 10, 15, 20}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	got := CalculateInventoryAge(issues, "In Progress", syntheticWeights, syntheticMappings, persistence, "wip", true, now)
+	if len(got) == 0 {
+		t.Fatal("expected started items to age")
+	}
+
+	// Single issues never split, so aging them one by one is the serial pass.
+	var want []InventoryAge
+	for _, issue := range issues {
+		want = append(want, CalculateInventoryAge([]jira.Issue{issue}, "In Progress", syntheticWeights, syntheticMappings, persistence, "wip", true, now)...)
+	}
+	slices.SortFunc(want, compareInventoryAge)
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("parallel aging differs from the serial pass: %d vs %d items", len(got), len(want))
+	}
+}
+
+func BenchmarkCalculateStatusPersistence(b *testing.B) {
+	issues := syntheticIssues(10000)
+	for b.Loop() {
+		CalculateStatusPersistence(issues)
+	}
+}
+
+func BenchmarkCalculateStratifiedStatusPersistence(b *testing.B) {
+	issues := syntheticIssues(10000)
+	for b.Loop() {
+		CalculateStratifiedStatusPersistence(issues)
+	}
+}
+
+func BenchmarkCalculateInventoryAge(b *testing.B) {
+	issues := syntheticIssues(10000)
+	persistence := []float64{2, 4, 6, 8, 10, 15, 20}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	for b.Loop() {
+		CalculateInventoryAge(issues, "In Progress", syntheticWeights, syntheticMappings, persistence, "wip", true, now)
+	}
+}
+
+func BenchmarkCalculateInventoryAgeByType(b *testing.B) {
+	issues := syntheticIssues(10000)
+	persistence := []float64{2, 4, 6, 8, 10, 15, 20}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	commitments := CommitmentPoints{Default: "In Progress", ByType: map[string]string{"Bug": "Refinement"}}
+	for b.Loop() {
+		CalculateInventoryAgeByType(issues, commitments, syntheticWeights, syntheticMappings, persistence, "wip", true, now)
+	}
+}
@@ -30,46 +30,27 @@ func CalculateStatusPersistence(issues []jira.Issue) []StatusPersistence {
 		return nil
 	}
 
-	statusDurations := make(map[string][]float64)
-	blockedDurations := make(map[string][]float64)
 	totalIssues := float64(len(issues))
 
-	// Track the most recent name seen for each ID as a fallback for display
-	idToName := make(map[string]string)
-
-	for _, issue := range issues {
-		// Populate names from current, birth and all transitions
-		if issue.StatusID != "" && issue.Status != "" {
-			idToName[issue.StatusID] = issue.Status
-		}
-		if issue.BirthStatusID != "" && issue.BirthStatus != "" {
-			idToName[issue.BirthStatusID] = issue.BirthStatus
-		}
-		for _, t := range issue.Transitions {
-			if t.FromStatusID != "" && t.FromStatus != "" {
-				idToName[t.FromStatusID] = t.FromStatus
-			}
-			if t.ToStatusID != "" && t.ToStatus != "" {
-				idToName[t.ToStatusID] = t.ToStatus
-			}
+	// Issues are independent, so chunks are collected in parallel and merged
+	// in order; the durations are sorted below, so the merge order only
+	// matters for the names.
+	partials := parallelChunks(len(issues), func(lo, hi int) persistencePartial {
+		return collectPersistence(issues[lo:hi])
+	})
+	merged := partials[0]
+	for _, p := range partials[1:] {
+		for id, d := range p.statusDurations {
+			merged.statusDurations[id] = append(merged.statusDurations[id], d...)
 		}
-
-		for statusID, seconds := range issue.StatusResidency {
-			// Signal-Aware: Preserve terminal statuses even if residency is < 1m.
-			isTerminal := (issue.ResolutionDate != nil || issue.Resolution != "") && (issue.StatusID == statusID || issue.Status == statusID)
-
-			if seconds >= 60 || isTerminal {
-				days := float64(seconds) / 86400.0
-				statusDurations[statusID] = append(statusDurations[statusID], days)
-			}
+		for id, d := range p.blockedDurations {
+			merged.blockedDurations[id] = append(merged.blockedDurations[id], d...)
 		}
-		for statusID, seconds := range issue.BlockedResidency {
-			if seconds >= 60 {
-				days := float64(seconds) / 86400.0
-				blockedDurations[statusID] = append(blockedDurations[statusID], days)
-			}
+		for id, name := range p.idToName {
+			merged.idToName[id] = name
 		}
 	}
+	statusDurations, blockedDurations, idToName := merged.statusDurations, merged.blockedDurations, merged.idToName
 
 	var results []StatusPersistence
 	for statusID, durations := range statusDurations {
@@ -115,6 +96,56 @@ func CalculateStatusPersistence(issues []jira.Issue) []StatusPersistence {
 	return results
 }
 
+// persistencePartial holds the residency samples of a chunk of issues.
+type persistencePartial struct {
+	statusDurations  map[string][]float64
+	blockedDurations map[string][]float64
+	idToName         map[string]string // most recent name seen for each ID, used as a display fallback
+}
+
+// collectPersistence gathers the residency samples and status names of issues.
+func collectPersistence(issues []jira.Issue) persistencePartial {
+	statusDurations := make(map[string][]float64)
+	blockedDurations := make(map[string][]float64)
+	idToName := make(map[string]string)
+
+	for _, issue := range issues {
+		// Populate names from current, birth and all transitions
+		if issue.StatusID != "" && issue.Status != "" {
+			idToName[issue.StatusID] = issue.Status
+		}
+		if issue.BirthStatusID != "" && issue.BirthStatus != "" {
+			idToName[issue.BirthStatusID] = issue.BirthStatus
+		}
+		for _, t := range issue.Transitions {
+			if t.FromStatusID != "" && t.FromStatus != "" {
+				idToName[t.FromStatusID] = t.FromStatus
+			}
+			if t.ToStatusID != "" && t.ToStatus != "" {
+				idToName[t.ToStatusID] = t.ToStatus
+			}
+		}
+
+		for statusID, seconds := range issue.StatusResidency {
+			// Signal-Aware: Preserve terminal statuses even if residency is < 1m.
+			isTerminal := (issue.ResolutionDate != nil || issue.Resolution != "") && (issue.StatusID == statusID || issue.Status == statusID)
+
+			if seconds >= 60 || isTerminal {
+				days := float64(seconds) / 86400.0
+				statusDurations[statusID] = append(statusDurations[statusID], days)
+			}
+		}
+		for statusID, seconds := range issue.BlockedResidency {
+			if seconds >= 60 {
+				days := float64(seconds) / 86400.0
+				blockedDurations[statusID] = append(blockedDurations[statusID], days)
+			}
+		}
+	}
+
+	return persistencePartial{statusDurations: statusDurations, blockedDurations: blockedDurations, idToName: idToName}
+}
+
 // EnrichStatusPersistence adds semantic context to the persistence results.
 func EnrichStatusPersistence(results []StatusPersistence, mappings map[string]StatusMetadata) []StatusPersistence {
 	for i := range results {
@@ -231,6 +262,21 @@ func CalculateTierSummary(issues []jira.Issue, mappings map[string]StatusMetadat
 	return summary
 }
 
+// StatusResidenceDays returns the residence times in days of the issues in
+// each status they visited, keyed by status ID. Visits under a minute are
+// ignored, as in CalculateStatusPersistence.
+func StatusResidenceDays(issues []jira.Issue) map[string][]float64 {
+	days := make(map[string][]float64)
+	for _, issue := range issues {
+		for statusID, seconds := range issue.StatusResidency {
+			if seconds >= 60 {
+				days[statusID] = append(days[statusID], float64(seconds)/86400.0)
+			}
+		}
+	}
+	return days
+}
+
 // CalculateStratifiedStatusPersistence analyzes residency per status, grouped by issue type.
 func CalculateStratifiedStatusPersistence(issues []jira.Issue) map[string][]StatusPersistence {
 	if len(issues) == 0 {
@@ -246,10 +292,7 @@ func CalculateStratifiedStatusPersistence(issues []jira.Issue) map[string][]Stat
 		byType[t] = append(byType[t], iss)
 	}
 
-	res := make(map[string][]StatusPersistence)
-	for t, issSlice := range byType {
-		res[t] = CalculateStatusPersistence(issSlice)
-	}
-
-	return res
+	return parallelGroups(byType, func(_ string, group []jira.Issue) []StatusPersistence {
+		return CalculateStatusPersistence(group)
+	})
 }
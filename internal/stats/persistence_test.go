@@ -180,3 +180,62 @@ func TestCalculateStatusPersistence_TerminalPreservation(t *testing.T) {
 		t.Error("Expected intermediate status 'In Review' to be filtered out as noise (< 60s)")
 	}
 }
+
+func TestStatusResidenceDays(t *testing.T) {
+	issues := []jira.Issue{
+		{Key: "A", StatusResidency: map[string]int64{"10": 2 * 86400, "11": 30}},
+		{Key: "B", StatusResidency: map[string]int64{"10": 86400 / 2}},
+	}
+	days := StatusResidenceDays(issues)
+	if len(days["10"]) != 2 || days["10"][0] != 2 || days["10"][1] != 0.5 {
+		t.Errorf("Expected residence days [2 0.5] for status 10, got %v", days["10"])
+	}
+	if _, ok := days["11"]; ok {
+		t.Errorf("Expected visits under a minute to be ignored, got %v", days["11"])
+	}
+}
+
+func TestCalculateStatusPersistenceTrend(t *testing.T) {
+	// Four months of deliveries: Validation takes 2 days early on and 6 days
+	// later, Development stays at 3 days.
+	var issues []jira.Issue
+	for month := 1; month <= 4; month++ {
+		validation := int64(2 * 86400)
+		if month > 2 {
+			validation = 6 * 86400
+		}
+		for day := 1; day <= 5; day++ {
+			done := time.Date(2024, time.Month(month), day*5, 0, 0, 0, 0, time.UTC)
+			issues = append(issues, jira.Issue{
+				Key:         "V",
+				OutcomeDate: &done,
+				StatusResidency: map[string]int64{
+					"10": validation,
+					"20": 3 * 86400,
+				},
+			})
+		}
+	}
+	statuses := []StatusPersistence{{StatusID: "10", StatusName: "Validation"}, {StatusID: "20", StatusName: "Development"}}
+	window := NewAnalysisWindow(time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 4, 30, 0, 0, 0, 0, time.UTC), "month", time.Time{})
+
+	trend := CalculateStatusPersistenceTrend(issues, statuses, window)
+	if len(trend) != 2 || trend[0].StatusName != "Validation" {
+		t.Fatalf("Expected a series per status in the given order, got %+v", trend)
+	}
+	validation, development := trend[0], trend[1]
+	if len(validation.Buckets) != 4 || validation.Buckets[0].Count != 5 || validation.Buckets[3].P85 != 6 {
+		t.Errorf("Expected 4 monthly buckets of 5 items, got %+v", validation.Buckets)
+	}
+	if validation.Direction != "worsening" || validation.EarlyP85 != 2 || validation.LateP85 != 6 || validation.P85Change != 2 {
+		t.Errorf("Expected Validation to worsen from 2 to 6 days, got %+v", validation)
+	}
+	if development.Direction != "stable" {
+		t.Errorf("Expected Development to be stable, got %s", development.Direction)
+	}
+
+	sparse := CalculateStatusPersistenceTrend(issues[:3], statuses, window)
+	if sparse[0].Direction != "insufficient_data" {
+		t.Errorf("Expected too few samples to give no direction, got %s", sparse[0].Direction)
+	}
+}
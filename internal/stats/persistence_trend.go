@@ -0,0 +1,121 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"slices"
+	"time"
+)
+
+// PersistenceTrendThreshold is the relative change of a status's P85 residency
+// between the earlier and the later half of the window above which its trend
+// is reported as improving or worsening.
+const PersistenceTrendThreshold = 0.20
+
+// persistenceTrendMinSamples is the number of residency samples each half of
+// the window needs before a direction is reported.
+const persistenceTrendMinSamples = 5
+
+// PersistenceTrendPoint holds the residency percentiles of one status among
+// the items delivered in one bucket.
+type PersistenceTrendPoint struct {
+	BucketStart time.Time `json:"bucket_start"`
+	BucketLabel string    `json:"bucket_label"`
+	Count       int       `json:"count"` // Delivered items that visited the status
+	P50         float64   `json:"coin_toss"`
+	P85         float64   `json:"likely"`
+	IsPartial   bool      `json:"is_partial,omitempty"`
+}
+
+// StatusPersistenceTrend is the bucketed persistence series of one status.
+type StatusPersistenceTrend struct {
+	StatusID   string                  `json:"statusID,omitempty"`
+	StatusName string                  `json:"statusName"`
+	Tier       string                  `json:"tier,omitempty"`
+	Buckets    []PersistenceTrendPoint `json:"buckets"`
+	EarlyP85   float64                 `json:"early_p85"`            // P85 over the earlier half of the buckets
+	LateP85    float64                 `json:"late_p85"`             // P85 over the later half of the buckets
+	P85Change  float64                 `json:"p85_change,omitempty"` // (late - early) / early
+	Direction  string                  `json:"direction"`            // "improving", "worsening", "stable", or "insufficient_data"
+}
+
+// CalculateStatusPersistenceTrend buckets the residency of each of statuses by
+// the outcome date of the delivered issues, using the window's subdivision,
+// and compares the P85 of the earlier half of the buckets with the later half.
+// statuses is typically the result of CalculateStatusPersistence and sets the
+// order of the series.
+func CalculateStatusPersistenceTrend(issues []jira.Issue, statuses []StatusPersistence, window AnalysisWindow) []StatusPersistenceTrend {
+	bucketStarts := window.Subdivide()
+	if len(bucketStarts) == 0 || len(statuses) == 0 {
+		return nil
+	}
+
+	byBucket := make([][]jira.Issue, len(bucketStarts))
+	for _, issue := range issues {
+		dt := issue.OutcomeDate
+		if dt == nil {
+			dt = issue.ResolutionDate
+		}
+		if dt == nil {
+			continue
+		}
+		idx := window.FindBucketIndex(*dt)
+		if idx < 0 || idx >= len(byBucket) {
+			continue
+		}
+		byBucket[idx] = append(byBucket[idx], issue)
+	}
+	samples := make([]map[string][]float64, len(bucketStarts))
+	for i, bucketIssues := range byBucket {
+		samples[i] = collectPersistence(bucketIssues).statusDurations
+	}
+
+	half := len(bucketStarts) / 2
+	trends := make([]StatusPersistenceTrend, 0, len(statuses))
+	for _, sp := range statuses {
+		trend := StatusPersistenceTrend{
+			StatusID:   sp.StatusID,
+			StatusName: sp.StatusName,
+			Tier:       sp.Tier,
+			Buckets:    make([]PersistenceTrendPoint, 0, len(bucketStarts)),
+		}
+		var early, late []float64
+		for i, start := range bucketStarts {
+			durations := slices.Clone(samples[i][sp.StatusID])
+			slices.Sort(durations)
+			trend.Buckets = append(trend.Buckets, PersistenceTrendPoint{
+				BucketStart: start,
+				BucketLabel: window.GenerateLabel(start),
+				Count:       len(durations),
+				P50:         RoundTo(CalculatePercentile(durations, 0.50), 1),
+				P85:         RoundTo(CalculatePercentile(durations, 0.85), 1),
+				IsPartial:   window.IsPartial(start),
+			})
+			if i < half {
+				early = append(early, durations...)
+			} else {
+				late = append(late, durations...)
+			}
+		}
+
+		trend.Direction = "insufficient_data"
+		if len(early) >= persistenceTrendMinSamples && len(late) >= persistenceTrendMinSamples {
+			slices.Sort(early)
+			slices.Sort(late)
+			earlyP85, lateP85 := CalculatePercentile(early, 0.85), CalculatePercentile(late, 0.85)
+			trend.EarlyP85, trend.LateP85 = RoundTo(earlyP85, 1), RoundTo(lateP85, 1)
+			trend.Direction = "stable"
+			if earlyP85 > 0 {
+				change := (lateP85 - earlyP85) / earlyP85
+				trend.P85Change = RoundTo(change, 2)
+				switch {
+				case change > PersistenceTrendThreshold:
+					trend.Direction = "worsening"
+				case change < -PersistenceTrendThreshold:
+					trend.Direction = "improving"
+				}
+			}
+		}
+		trends = append(trends, trend)
+	}
+	return trends
+}
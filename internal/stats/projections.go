@@ -2,6 +2,8 @@ package stats
 
 import (
 	"cmp"
+	"iter"
+	"maps"
 	"mcs-mcp/internal/eventlog"
 	"mcs-mcp/internal/jira"
 	"slices"
@@ -14,21 +16,52 @@ import (
 // 2. Downstream: Items in active execution tiers at the window's END point.
 // 3. Upstream: Items in refinement or analysis tiers at the window's END point.
 // 4. Demand: Items existing in the initial entry tier at the window's END point.
+// Sub-tasks are left out; see projectScope for the other subtask policies.
 func ProjectScope(events []eventlog.IssueEvent, window AnalysisWindow, commitmentPoint string, mappings map[string]StatusMetadata, resolutions map[string]string, issueTypes []string) ([]jira.Issue, []jira.Issue, []jira.Issue, []jira.Issue) {
+	return projectScope(events, window, commitmentPoint, mappings, resolutions, issueTypes, SubtasksExclude)
+}
+
+// projectScope is ProjectScope under a subtask policy.
+func projectScope(events []eventlog.IssueEvent, window AnalysisWindow, commitmentPoint string, mappings map[string]StatusMetadata, resolutions map[string]string, issueTypes []string, subtasks SubtaskPolicy) ([]jira.Issue, []jira.Issue, []jira.Issue, []jira.Issue) {
+	return projectHistories(eventlog.GroupByIssue(events), window, commitmentPoint, mappings, resolutions, issueTypes, subtasks)
+}
+
+// ProjectHistories is ProjectScope over per-issue histories, as yielded by
+// eventlog.LogProvider.IssuesInRange. Each history is reconstructed and
+// classified as it arrives, so only the resulting issues are kept in memory.
+func ProjectHistories(histories iter.Seq[[]eventlog.IssueEvent], window AnalysisWindow, commitmentPoint string, mappings map[string]StatusMetadata, resolutions map[string]string, issueTypes []string) ([]jira.Issue, []jira.Issue, []jira.Issue, []jira.Issue) {
+	return projectHistories(histories, window, commitmentPoint, mappings, resolutions, issueTypes, SubtasksExclude)
+}
+
+// projectHistories is ProjectHistories under a subtask policy. Rolling
+// sub-tasks up moves their parents' start dates, so under SubtasksRollup the
+// histories are collected before any is reconstructed.
+func projectHistories(histories iter.Seq[[]eventlog.IssueEvent], window AnalysisWindow, commitmentPoint string, mappings map[string]StatusMetadata, resolutions map[string]string, issueTypes []string, subtasks SubtaskPolicy) ([]jira.Issue, []jira.Issue, []jira.Issue, []jira.Issue) {
 	typeMap := make(map[string]bool)
 	for _, t := range issueTypes {
 		typeMap[t] = true
 	}
 
-	grouped := make(map[string][]eventlog.IssueEvent)
-	for _, e := range events {
-		if !window.End.IsZero() && e.Timestamp > window.End.UnixMicro() {
-			continue
+	// scoped drops the events after the window end and those of other types.
+	outside := func(e eventlog.IssueEvent) bool {
+		return (!window.End.IsZero() && e.Timestamp > window.End.UnixMicro()) || (len(issueTypes) > 0 && !typeMap[e.IssueType])
+	}
+	scoped := func(history []eventlog.IssueEvent) []eventlog.IssueEvent {
+		if !slices.ContainsFunc(history, outside) {
+			return history
 		}
-		if len(issueTypes) > 0 && !typeMap[e.IssueType] {
-			continue
+		return slices.DeleteFunc(slices.Clone(history), outside)
+	}
+
+	if subtasks == SubtasksRollup {
+		grouped := make(map[string][]eventlog.IssueEvent)
+		for history := range histories {
+			if events := scoped(history); len(events) > 0 {
+				grouped[events[0].IssueKey] = events
+			}
 		}
-		grouped[e.IssueKey] = append(grouped[e.IssueKey], e)
+		rollupSubtasks(grouped, mappings)
+		histories = maps.Values(grouped)
 	}
 
 	finishedMap := make(map[string]bool)
@@ -43,9 +76,15 @@ func ProjectScope(events []eventlog.IssueEvent, window AnalysisWindow, commitmen
 	var upstream []jira.Issue
 	var demand []jira.Issue
 
-	for _, issueEvents := range grouped {
+	for history := range histories {
+		issueEvents := history
+		if subtasks != SubtasksRollup {
+			if issueEvents = scoped(history); len(issueEvents) == 0 {
+				continue
+			}
+		}
 		issue := eventlog.ReconstructIssue(issueEvents, window.End)
-		if issue.IsSubtask {
+		if issue.IsSubtask && subtasks != SubtasksInclude {
 			continue
 		}
 
@@ -146,6 +146,7 @@ func TestThroughputProjection_BoundaryResolved_Impact(t *testing.T) {
 				Name             string `json:"name"`
 				UntranslatedName string `json:"untranslatedName,omitempty"`
 				Subtask          bool   `json:"subtask"`
+				HierarchyLevel   int    `json:"hierarchyLevel,omitempty"`
 			}{Name: "Story"},
 			Status: struct {
 				ID               string `json:"id"`
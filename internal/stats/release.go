@@ -0,0 +1,127 @@
+package stats
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+// Release marker sources reported in ReleaseLagResult.Source.
+const (
+	ReleaseSourceStatus     = "status"
+	ReleaseSourceFixVersion = "fix_version"
+)
+
+// ReleaseLagResult describes the "done-done" gap between an item's delivery
+// (resolution) and its actual release to production.
+type ReleaseLagResult struct {
+	Source          string             `json:"source"`                   // "status" or "fix_version"
+	ReleaseStatus   string             `json:"release_status,omitempty"` // Set when Source == "status"
+	DeliveredCount  int                `json:"delivered_count"`
+	ReleasedCount   int                `json:"released_count"`
+	UnreleasedCount int                `json:"unreleased_count"` // Delivered but no release marker yet
+	P50             float64            `json:"coin_toss"`
+	P85             float64            `json:"likely"`
+	P95             float64            `json:"safe_bet"`
+	Mean            float64            `json:"mean"`
+	Max             float64            `json:"max"`
+	ByType          map[string]float64 `json:"p85_by_type,omitempty"`
+	Lags            []float64          `json:"-"` // Raw lag sample in days, for forecast extension
+}
+
+// Round rounds all numeric fields to 2 decimal places for output compactness.
+func (r *ReleaseLagResult) Round() {
+	r.P50 = Round2(r.P50)
+	r.P85 = Round2(r.P85)
+	r.P95 = Round2(r.P95)
+	r.Mean = Round2(r.Mean)
+	r.Max = Round2(r.Max)
+	for k, v := range r.ByType {
+		r.ByType[k] = Round2(v)
+	}
+}
+
+// ReleaseDate returns when an issue reached production.
+// With a releaseStatus, it is the first transition into that status (name match,
+// case-insensitive). Otherwise it is the earliest release date among the
+// issue's released fixVersions. Returns nil when no marker is present.
+func ReleaseDate(issue jira.Issue, releaseStatus string) *time.Time {
+	if releaseStatus != "" {
+		for _, t := range issue.Transitions {
+			if strings.EqualFold(t.ToStatus, releaseStatus) {
+				d := t.Date
+				return &d
+			}
+		}
+		return nil
+	}
+
+	var earliest *time.Time
+	for _, v := range issue.FixVersions {
+		if !v.Released || v.ReleaseDate == "" {
+			continue
+		}
+		d, err := time.Parse(DateFormat, v.ReleaseDate)
+		if err != nil {
+			continue
+		}
+		if earliest == nil || d.Before(*earliest) {
+			earliest = &d
+		}
+	}
+	return earliest
+}
+
+// AnalyzeReleaseLag measures, for delivered items, the calendar days between
+// the delivery outcome date and the release date. Releases dated before the
+// delivery (common with day-granular fixVersion dates) count as zero lag.
+func AnalyzeReleaseLag(issues []jira.Issue, releaseStatus string) ReleaseLagResult {
+	res := ReleaseLagResult{Source: ReleaseSourceFixVersion}
+	if releaseStatus != "" {
+		res.Source = ReleaseSourceStatus
+		res.ReleaseStatus = releaseStatus
+	}
+
+	byType := make(map[string][]float64)
+	for _, issue := range issues {
+		if !IsDelivered(issue) || issue.OutcomeDate == nil {
+			continue
+		}
+		res.DeliveredCount++
+
+		released := ReleaseDate(issue, releaseStatus)
+		if released == nil {
+			res.UnreleasedCount++
+			continue
+		}
+		lag := max(released.Sub(*issue.OutcomeDate).Hours()/24, 0)
+		res.Lags = append(res.Lags, lag)
+		byType[issue.IssueType] = append(byType[issue.IssueType], lag)
+	}
+
+	res.ReleasedCount = len(res.Lags)
+	if res.ReleasedCount == 0 {
+		return res
+	}
+
+	sorted := slices.Clone(res.Lags)
+	slices.Sort(sorted)
+	res.P50 = CalculatePercentileInterpolated(sorted, 50)
+	res.P85 = CalculatePercentileInterpolated(sorted, 85)
+	res.P95 = CalculatePercentileInterpolated(sorted, 95)
+	res.Max = sorted[len(sorted)-1]
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	res.Mean = sum / float64(len(sorted))
+
+	res.ByType = make(map[string]float64, len(byType))
+	for t, lags := range byType {
+		slices.Sort(lags)
+		res.ByType[t] = CalculatePercentileInterpolated(lags, 85)
+	}
+	return res
+}
@@ -0,0 +1,57 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeReleaseLag_FixVersion(t *testing.T) {
+	resolved := time.Date(2026, 3, 2, 10, 0, 0, 0, time.UTC)
+	issues := []jira.Issue{
+		{Key: "A", IssueType: "Story", Outcome: "delivered", OutcomeDate: &resolved,
+			FixVersions: []jira.FixVersion{{Name: "1.1", Released: true, ReleaseDate: "2026-03-12"}, {Name: "1.0", Released: true, ReleaseDate: "2026-03-06"}}},
+		{Key: "B", IssueType: "Bug", Outcome: "delivered", OutcomeDate: &resolved,
+			FixVersions: []jira.FixVersion{{Name: "0.9", Released: true, ReleaseDate: "2026-03-01"}}}, // released before resolution → 0
+		{Key: "C", IssueType: "Story", Outcome: "delivered", OutcomeDate: &resolved,
+			FixVersions: []jira.FixVersion{{Name: "2.0", ReleaseDate: "2026-04-01"}}}, // not yet released
+		{Key: "D", IssueType: "Story", Outcome: "abandoned", OutcomeDate: &resolved},
+	}
+
+	res := AnalyzeReleaseLag(issues, "")
+	if res.Source != ReleaseSourceFixVersion {
+		t.Errorf("Expected fix_version source, got %s", res.Source)
+	}
+	if res.DeliveredCount != 3 || res.ReleasedCount != 2 || res.UnreleasedCount != 1 {
+		t.Fatalf("Unexpected counts: %+v", res)
+	}
+	// A: earliest released version 2026-03-06 00:00 minus 03-02 10:00 = 3.58 days
+	if res.Max < 3.5 || res.Max > 3.6 {
+		t.Errorf("Expected max lag ≈ 3.58, got %.2f", res.Max)
+	}
+	if res.ByType["Bug"] != 0 {
+		t.Errorf("Expected zero lag for Bug released before resolution, got %.2f", res.ByType["Bug"])
+	}
+}
+
+func TestAnalyzeReleaseLag_Status(t *testing.T) {
+	resolved := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	issues := []jira.Issue{
+		{Key: "A", Outcome: "delivered", OutcomeDate: &resolved, Transitions: []jira.StatusTransition{
+			{ToStatus: "Done", Date: resolved},
+			{ToStatus: "Released", Date: resolved.AddDate(0, 0, 4)},
+		}},
+		{Key: "B", Outcome: "delivered", OutcomeDate: &resolved},
+	}
+
+	res := AnalyzeReleaseLag(issues, "released")
+	if res.Source != ReleaseSourceStatus || res.ReleaseStatus != "released" {
+		t.Errorf("Unexpected source: %s/%s", res.Source, res.ReleaseStatus)
+	}
+	if res.ReleasedCount != 1 || res.UnreleasedCount != 1 {
+		t.Fatalf("Unexpected counts: %+v", res)
+	}
+	if res.P50 != 4 {
+		t.Errorf("Expected 4-day lag, got %.2f", res.P50)
+	}
+}
@@ -0,0 +1,143 @@
+package stats
+
+import (
+	"cmp"
+	"math"
+	"mcs-mcp/internal/jira"
+	"slices"
+)
+
+// ReconciliationTolerance is the relative difference between the residency
+// derived from the event log and the time-in-status field above which a status
+// of an item is reported as a discrepancy.
+const ReconciliationTolerance = 0.10
+
+// reconciliationMinSeconds is the absolute difference below which residencies
+// agree regardless of their relative difference, so that statuses passed
+// through in minutes do not flood the report.
+const reconciliationMinSeconds = 3600
+
+// reconciliationMaxDiscrepancies caps the item-level discrepancies listed.
+const reconciliationMaxDiscrepancies = 20
+
+// ResidencyDiscrepancy is one status of one item whose derived residency
+// disagrees with the time-in-status field.
+type ResidencyDiscrepancy struct {
+	Key            string  `json:"key"`
+	StatusID       string  `json:"statusID"`
+	StatusName     string  `json:"statusName"`
+	DerivedDays    float64 `json:"derived_days"`
+	FieldDays      float64 `json:"field_days"`
+	DifferenceDays float64 `json:"difference_days"` // Derived minus field
+}
+
+// StatusReconciliation summarizes the comparison for one status.
+type StatusReconciliation struct {
+	StatusID      string  `json:"statusID"`
+	StatusName    string  `json:"statusName"`
+	Compared      int     `json:"compared"`      // Items with a residency on either side
+	Discrepancies int     `json:"discrepancies"` // Items beyond the tolerance
+	AgreementRate float64 `json:"agreement_rate"`
+	MeanDiffDays  float64 `json:"mean_difference_days"` // Mean of derived minus field
+}
+
+// ResidencyReconciliation compares the residency derived from the event log
+// with the time-in-status field of the same items.
+type ResidencyReconciliation struct {
+	ComparedItems    int                    `json:"compared_items"`
+	MissingField     int                    `json:"missing_field"` // Items without a field value
+	ComparedPairs    int                    `json:"compared_pairs"`
+	Discrepancies    int                    `json:"discrepancies"`
+	AgreementRate    float64                `json:"agreement_rate"`
+	Statuses         []StatusReconciliation `json:"statuses"`
+	TopDiscrepancies []ResidencyDiscrepancy `json:"top_discrepancies,omitempty"`
+}
+
+// ReconcileResidency compares issue.StatusResidency with issue.FieldResidency
+// per status. The current status of each item is left out: the field keeps
+// counting it until the fetch, while the derived residency of a finished item
+// stops at its outcome. A status agrees when the residencies differ by less
+// than an hour or by less than ReconciliationTolerance of the larger one.
+func ReconcileResidency(issues []jira.Issue) ResidencyReconciliation {
+	var rec ResidencyReconciliation
+	names := collectPersistence(issues).idToName
+	byStatus := make(map[string]*StatusReconciliation)
+	diffSums := make(map[string]float64)
+	var discrepancies []ResidencyDiscrepancy
+
+	for _, issue := range issues {
+		if issue.FieldResidency == nil {
+			rec.MissingField++
+			continue
+		}
+		rec.ComparedItems++
+
+		statusIDs := make(map[string]bool)
+		for id := range issue.StatusResidency {
+			statusIDs[id] = true
+		}
+		for id := range issue.FieldResidency {
+			statusIDs[id] = true
+		}
+		for id := range statusIDs {
+			if id == issue.StatusID {
+				continue
+			}
+			derived, field := issue.StatusResidency[id], issue.FieldResidency[id]
+			sr, ok := byStatus[id]
+			if !ok {
+				sr = &StatusReconciliation{StatusID: id, StatusName: cmp.Or(names[id], id)}
+				byStatus[id] = sr
+			}
+			sr.Compared++
+			rec.ComparedPairs++
+			diff := derived - field
+			diffSums[id] += float64(diff) / 86400.0
+
+			absDiff := diff
+			if absDiff < 0 {
+				absDiff = -absDiff
+			}
+			if absDiff < reconciliationMinSeconds || float64(absDiff) <= ReconciliationTolerance*float64(max(derived, field)) {
+				continue
+			}
+			sr.Discrepancies++
+			rec.Discrepancies++
+			discrepancies = append(discrepancies, ResidencyDiscrepancy{
+				Key:            issue.Key,
+				StatusID:       id,
+				StatusName:     sr.StatusName,
+				DerivedDays:    RoundTo(float64(derived)/86400.0, 2),
+				FieldDays:      RoundTo(float64(field)/86400.0, 2),
+				DifferenceDays: RoundTo(float64(diff)/86400.0, 2),
+			})
+		}
+	}
+
+	if rec.ComparedPairs > 0 {
+		rec.AgreementRate = RoundTo(1-float64(rec.Discrepancies)/float64(rec.ComparedPairs), 3)
+	}
+	for id, sr := range byStatus {
+		sr.AgreementRate = RoundTo(1-float64(sr.Discrepancies)/float64(sr.Compared), 3)
+		sr.MeanDiffDays = RoundTo(diffSums[id]/float64(sr.Compared), 2)
+		rec.Statuses = append(rec.Statuses, *sr)
+	}
+	slices.SortFunc(rec.Statuses, func(a, b StatusReconciliation) int {
+		if c := cmp.Compare(b.Discrepancies, a.Discrepancies); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.StatusName, b.StatusName)
+	})
+
+	slices.SortFunc(discrepancies, func(a, b ResidencyDiscrepancy) int {
+		if c := cmp.Compare(math.Abs(b.DifferenceDays), math.Abs(a.DifferenceDays)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Key, b.Key)
+	})
+	if len(discrepancies) > reconciliationMaxDiscrepancies {
+		discrepancies = discrepancies[:reconciliationMaxDiscrepancies]
+	}
+	rec.TopDiscrepancies = discrepancies
+	return rec
+}
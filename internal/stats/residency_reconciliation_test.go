@@ -0,0 +1,48 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+)
+
+func TestReconcileResidency(t *testing.T) {
+	const day = 86400
+	issue := func(key string, derived, field map[string]int64) jira.Issue {
+		return jira.Issue{
+			Key: key, Status: "Done", StatusID: "5",
+			Transitions:     []jira.StatusTransition{{ToStatusID: "2", ToStatus: "Dev"}, {ToStatusID: "3", ToStatus: "Review"}},
+			StatusResidency: derived, FieldResidency: field,
+		}
+	}
+	issues := []jira.Issue{
+		// Agrees; the final status differs but is never compared.
+		issue("A-1", map[string]int64{"2": 4 * day, "3": 2 * day, "5": 1}, map[string]int64{"2": 4*day + 600, "3": 2 * day, "5": 9 * day}),
+		// Review is counted twice as long by the field.
+		issue("A-2", map[string]int64{"2": 3 * day, "3": 1 * day}, map[string]int64{"2": 3 * day, "3": 2 * day}),
+		// Dev is missing from the field.
+		issue("A-3", map[string]int64{"2": 5 * day, "3": 1 * day}, map[string]int64{"3": 1 * day}),
+		// No field value.
+		issue("A-4", map[string]int64{"2": day}, nil),
+	}
+
+	rec := ReconcileResidency(issues)
+
+	if rec.ComparedItems != 3 || rec.MissingField != 1 || rec.ComparedPairs != 6 || rec.Discrepancies != 2 {
+		t.Fatalf("Expected 2 of 6 pairs of 3 items to disagree, got %+v", rec)
+	}
+	if rec.AgreementRate != 0.667 {
+		t.Errorf("Expected an agreement rate of 0.667, got %v", rec.AgreementRate)
+	}
+	if len(rec.Statuses) != 2 || rec.Statuses[0].StatusName != "Dev" || rec.Statuses[0].Discrepancies != 1 {
+		t.Errorf("Expected a Dev and a Review summary, got %+v", rec.Statuses)
+	}
+	if len(rec.TopDiscrepancies) != 2 {
+		t.Fatalf("Expected two discrepancies, got %+v", rec.TopDiscrepancies)
+	}
+	if top := rec.TopDiscrepancies[0]; top.Key != "A-3" || top.DerivedDays != 5 || top.FieldDays != 0 || top.DifferenceDays != 5 {
+		t.Errorf("Expected the missing Dev residency of A-3 first, got %+v", top)
+	}
+	if second := rec.TopDiscrepancies[1]; second.Key != "A-2" || second.StatusName != "Review" || second.DifferenceDays != -1 {
+		t.Errorf("Expected the doubled Review residency of A-2 second, got %+v", second)
+	}
+}
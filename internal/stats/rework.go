@@ -0,0 +1,217 @@
+package stats
+
+import (
+	"cmp"
+	"mcs-mcp/internal/jira"
+	"slices"
+	"time"
+)
+
+// ReworkPair is the rework of one backward transition, e.g. Validation → In Development.
+type ReworkPair struct {
+	FromID      string  `json:"from_id"`
+	From        string  `json:"from"`
+	ToID        string  `json:"to_id"`
+	To          string  `json:"to"`
+	Transitions int     `json:"transitions"`
+	Items       int     `json:"items"`       // Distinct items with this backward transition
+	ReworkRate  float64 `json:"rework_rate"` // Items / items that visited From
+	P50LoopDays float64 `json:"p50_loop_days"`
+	P85LoopDays float64 `json:"p85_loop_days"`
+	LoopDays    float64 `json:"total_loop_days"`
+}
+
+// ReworkItem is the rework of one delivered item.
+type ReworkItem struct {
+	Key           string  `json:"key"`
+	Type          string  `json:"type"`
+	Loops         int     `json:"loops"`
+	ReworkDays    float64 `json:"rework_days"` // Union of its loops, so nested loops count once
+	CycleTimeDays float64 `json:"cycle_time_days"`
+	ReworkShare   float64 `json:"rework_share"` // ReworkDays / CycleTimeDays, at most 1
+}
+
+// ReworkResult summarizes backward transitions of delivered items.
+type ReworkResult struct {
+	Items              int          `json:"items"`
+	ReworkedItems      int          `json:"reworked_items"`
+	FirstTimeRightRate float64      `json:"first_time_right_rate"`
+	ReworkShare        float64      `json:"rework_share"` // Share of total cycle time spent in rework loops
+	FirstTimeRightP85  float64      `json:"first_time_right_p85"`
+	ReworkedP85        float64      `json:"reworked_p85"`
+	Pairs              []ReworkPair `json:"pairs"`             // Most frequent first
+	ItemsWithRework    []ReworkItem `json:"items_with_rework"` // Most rework days first
+}
+
+// reworkLoop is one backward transition and the time until the item was back
+// at (or beyond) the status it left.
+type reworkLoop struct {
+	from, to   string
+	start, end time.Time
+}
+
+// AnalyzeRework counts the backward transitions of delivered items and the
+// cycle time spent in the loops they open.
+//
+// A transition is backward when its target comes earlier in order than its
+// source and both are part of the item's cycle time range; a move back before
+// the commitment point is a backflow, not rework (see ApplyBackflowPolicy). The loop
+// lasts until the item next enters a status at or beyond the source, or until
+// its outcome date. issues and cycleTimes must be aligned by index.
+func AnalyzeRework(issues []jira.Issue, cycleTimes []float64, order []string, rangeFor func(issueType string) []string) ReworkResult {
+	rank := make(map[string]int, len(order))
+	for i, status := range order {
+		rank[status] = i
+	}
+
+	type pairAcc struct {
+		transitions int
+		items       map[string]bool
+		loops       []float64
+	}
+	pairs := make(map[[2]string]*pairAcc)
+	visited := make(map[string]int) // status → items that visited it
+	names := make(map[string]string)
+
+	res := ReworkResult{Items: len(issues), Pairs: make([]ReworkPair, 0), ItemsWithRework: make([]ReworkItem, 0)}
+	var ftrCycleTimes, reworkedCycleTimes []float64
+	var totalCycle, totalRework float64
+	for i, issue := range issues {
+		inRange := make(map[string]bool)
+		for _, status := range rangeFor(issue.IssueType) {
+			inRange[status] = true
+		}
+
+		seen := map[string]bool{}
+		current := PreferID(issue.BirthStatusID, issue.BirthStatus)
+		if issue.BirthStatus != "" {
+			names[current] = issue.BirthStatus
+		}
+		seen[current] = true
+		var loops []reworkLoop
+		for _, tr := range issue.Transitions {
+			to := PreferID(tr.ToStatusID, tr.ToStatus)
+			if tr.ToStatus != "" {
+				names[to] = tr.ToStatus
+			}
+			seen[to] = true
+			fromRank, okFrom := rank[current]
+			toRank, okTo := rank[to]
+			if okFrom && okTo && toRank < fromRank && inRange[current] && inRange[to] {
+				loops = append(loops, reworkLoop{from: current, to: to, start: tr.Date})
+			}
+			current = to
+		}
+		for status := range seen {
+			visited[status]++
+		}
+
+		end := issue.Updated
+		if issue.OutcomeDate != nil {
+			end = *issue.OutcomeDate
+		}
+		for j := range loops {
+			loops[j].end = end
+			for _, tr := range issue.Transitions {
+				if tr.Date.After(loops[j].start) && rank[PreferID(tr.ToStatusID, tr.ToStatus)] >= rank[loops[j].from] {
+					loops[j].end = tr.Date
+					break
+				}
+			}
+			l := loops[j]
+			acc := pairs[[2]string{l.from, l.to}]
+			if acc == nil {
+				acc = &pairAcc{items: make(map[string]bool)}
+				pairs[[2]string{l.from, l.to}] = acc
+			}
+			acc.transitions++
+			acc.items[issue.Key] = true
+			acc.loops = append(acc.loops, l.end.Sub(l.start).Hours()/24)
+		}
+
+		ct := cycleTimes[i]
+		totalCycle += ct
+		if len(loops) == 0 {
+			ftrCycleTimes = append(ftrCycleTimes, ct)
+			continue
+		}
+		reworkedCycleTimes = append(reworkedCycleTimes, ct)
+		days := min(unionDays(loops), ct)
+		totalRework += days
+		item := ReworkItem{
+			Key:           issue.Key,
+			Type:          issue.IssueType,
+			Loops:         len(loops),
+			ReworkDays:    Round2(days),
+			CycleTimeDays: Round2(ct),
+		}
+		if ct > 0 {
+			item.ReworkShare = Round2(days / ct)
+		}
+		res.ItemsWithRework = append(res.ItemsWithRework, item)
+	}
+
+	res.ReworkedItems = len(res.ItemsWithRework)
+	if res.Items > 0 {
+		res.FirstTimeRightRate = Round2(1 - float64(res.ReworkedItems)/float64(res.Items))
+	}
+	if totalCycle > 0 {
+		res.ReworkShare = Round2(totalRework / totalCycle)
+	}
+	res.FirstTimeRightP85 = Round2(PercentileOf(ftrCycleTimes, 0.85))
+	res.ReworkedP85 = Round2(PercentileOf(reworkedCycleTimes, 0.85))
+
+	for key, acc := range pairs {
+		p := ReworkPair{
+			FromID:      key[0],
+			From:        PreferID(names[key[0]], key[0]),
+			ToID:        key[1],
+			To:          PreferID(names[key[1]], key[1]),
+			Transitions: acc.transitions,
+			Items:       len(acc.items),
+			P50LoopDays: Round2(PercentileOf(acc.loops, 0.50)),
+			P85LoopDays: Round2(PercentileOf(acc.loops, 0.85)),
+		}
+		for _, d := range acc.loops {
+			p.LoopDays += d
+		}
+		p.LoopDays = Round2(p.LoopDays)
+		if n := visited[key[0]]; n > 0 {
+			p.ReworkRate = Round2(float64(p.Items) / float64(n))
+		}
+		res.Pairs = append(res.Pairs, p)
+	}
+	slices.SortFunc(res.Pairs, func(a, b ReworkPair) int {
+		if c := cmp.Compare(b.Transitions, a.Transitions); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.LoopDays, a.LoopDays); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.From+"→"+a.To, b.From+"→"+b.To)
+	})
+	slices.SortStableFunc(res.ItemsWithRework, func(a, b ReworkItem) int {
+		return cmp.Compare(b.ReworkDays, a.ReworkDays)
+	})
+	return res
+}
+
+// unionDays returns the days covered by at least one loop.
+func unionDays(loops []reworkLoop) float64 {
+	sorted := slices.Clone(loops)
+	slices.SortFunc(sorted, func(a, b reworkLoop) int { return a.start.Compare(b.start) })
+	var total time.Duration
+	var curStart, curEnd time.Time
+	for i, l := range sorted {
+		if i == 0 || l.start.After(curEnd) {
+			total += curEnd.Sub(curStart)
+			curStart, curEnd = l.start, l.end
+			continue
+		}
+		if l.end.After(curEnd) {
+			curEnd = l.end
+		}
+	}
+	total += curEnd.Sub(curStart)
+	return total.Hours() / 24
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestAnalyzeRework(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 4, d, 0, 0, 0, 0, time.UTC) }
+	tr := func(to string, d int) jira.StatusTransition {
+		return jira.StatusTransition{ToStatusID: to, ToStatus: "S" + to, Date: day(d)}
+	}
+	delivered := func(key string, transitions ...jira.StatusTransition) jira.Issue {
+		done := transitions[len(transitions)-1].Date
+		return jira.Issue{Key: key, IssueType: "Story", BirthStatusID: "1", BirthStatus: "S1", Transitions: transitions, OutcomeDate: &done}
+	}
+	order := []string{"1", "2", "3", "4", "5"} // 1 backlog, 2 dev, 3 review, 4 validation, 5 done
+	issues := []jira.Issue{
+		// Straight through.
+		delivered("A-1", tr("2", 1), tr("3", 3), tr("4", 4), tr("5", 5)),
+		// Validation → Dev on day 5, back in Validation on day 8: a 3-day loop.
+		delivered("A-2", tr("2", 1), tr("3", 3), tr("4", 4), tr("2", 5), tr("3", 7), tr("4", 8), tr("5", 10)),
+		// Review → Dev twice; the second loop (day 6 → 8) nests in none.
+		delivered("A-3", tr("2", 1), tr("3", 2), tr("2", 3), tr("3", 4), tr("2", 6), tr("3", 8), tr("5", 9)),
+		// Dev → Backlog is before the commitment range and not rework.
+		delivered("A-4", tr("2", 1), tr("1", 2), tr("2", 4), tr("5", 6)),
+	}
+	cycleTimes := []float64{4, 9, 8, 5}
+	rangeFor := func(string) []string { return []string{"2", "3", "4"} }
+
+	res := AnalyzeRework(issues, cycleTimes, order, rangeFor)
+
+	if res.Items != 4 || res.ReworkedItems != 2 || res.FirstTimeRightRate != 0.5 {
+		t.Fatalf("Expected 2 of 4 items with rework, got %+v", res)
+	}
+	if len(res.Pairs) != 2 {
+		t.Fatalf("Expected two backward pairs, got %+v", res.Pairs)
+	}
+	reviewDev := res.Pairs[0]
+	if reviewDev.From != "S3" || reviewDev.To != "S2" || reviewDev.Transitions != 2 || reviewDev.Items != 1 {
+		t.Errorf("Expected Review → Dev twice for one item first, got %+v", reviewDev)
+	}
+	if reviewDev.LoopDays != 3 || reviewDev.ReworkRate != 0.33 {
+		t.Errorf("Expected 1 + 2 loop days and 1 of 3 Review visitors, got %+v", reviewDev)
+	}
+	if validation := res.Pairs[1]; validation.From != "S4" || validation.LoopDays != 3 || validation.ReworkRate != 0.5 {
+		t.Errorf("Expected a 3-day Validation → Dev loop for 1 of 2 visitors, got %+v", validation)
+	}
+
+	if first := res.ItemsWithRework[0]; first.Key != "A-3" && first.Key != "A-2" || first.ReworkDays != 3 {
+		t.Errorf("Expected 3 rework days for the top item, got %+v", first)
+	}
+	// 6 rework days of 26 cycle time days.
+	if res.ReworkShare != 0.23 {
+		t.Errorf("Expected a rework share of 0.23, got %v", res.ReworkShare)
+	}
+	if res.ReworkedP85 != 9 || res.FirstTimeRightP85 != 5 {
+		t.Errorf("Expected P85 9 for reworked vs. 5 for first-time-right items, got %v and %v", res.ReworkedP85, res.FirstTimeRightP85)
+	}
+}
+
+func TestUnionDays(t *testing.T) {
+	at := func(d int) time.Time { return time.Date(2026, 4, d, 0, 0, 0, 0, time.UTC) }
+	loops := []reworkLoop{
+		{start: at(5), end: at(9)},
+		{start: at(1), end: at(3)},
+		{start: at(6), end: at(7)}, // nested
+	}
+	if got := unionDays(loops); got != 6 {
+		t.Errorf("Expected 2 + 4 days, got %v", got)
+	}
+}
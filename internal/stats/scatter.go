@@ -0,0 +1,93 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// ScatterBands are the percentile lines drawn across a cycle time scatterplot.
+type ScatterBands struct {
+	Count int     `json:"count"`
+	P50   float64 `json:"p50"`
+	P85   float64 `json:"p85"`
+	P95   float64 `json:"p95"`
+}
+
+// CycleTimeScatter is the item-level cycle time scatterplot of delivered work.
+type CycleTimeScatter struct {
+	Points      []ScatterPoint          `json:"points"`
+	Bands       ScatterBands            `json:"bands"`
+	BandsByType map[string]ScatterBands `json:"bands_by_type,omitempty"` // Types with at least MinTypeWorkflowSample items
+	AboveP95    []string                `json:"above_p95"`               // Keys of items above the pooled P95, slowest first
+}
+
+// Round rounds all numeric fields to 2 decimal places for output compactness.
+func (s *CycleTimeScatter) Round() {
+	s.Bands.round()
+	for t, b := range s.BandsByType {
+		b.round()
+		s.BandsByType[t] = b
+	}
+}
+
+func (b *ScatterBands) round() {
+	b.P50 = Round2(b.P50)
+	b.P85 = Round2(b.P85)
+	b.P95 = Round2(b.P95)
+}
+
+func scatterBands(values []float64) ScatterBands {
+	sorted := slices.Clone(values)
+	slices.Sort(sorted)
+	return ScatterBands{
+		Count: len(sorted),
+		P50:   PercentileOfSorted(sorted, 0.50),
+		P85:   PercentileOfSorted(sorted, 0.85),
+		P95:   PercentileOfSorted(sorted, 0.95),
+	}
+}
+
+// BuildCycleTimeScatter pairs each delivered item's completion date with its
+// cycle time and adds pooled and per-type P50/P85/P95 bands. issues and
+// cycleTimes are parallel slices, as returned by the cycle time extraction.
+func BuildCycleTimeScatter(issues []jira.Issue, cycleTimes []float64) CycleTimeScatter {
+	res := CycleTimeScatter{
+		Points:      BuildScatterplot(issues, cycleTimes),
+		BandsByType: make(map[string]ScatterBands),
+		AboveP95:    make([]string, 0),
+	}
+	if res.Points == nil {
+		res.Points = make([]ScatterPoint, 0)
+	}
+
+	values := make([]float64, 0, len(res.Points))
+	byType := make(map[string][]float64)
+	for _, p := range res.Points {
+		values = append(values, p.Value)
+		byType[p.IssueType] = append(byType[p.IssueType], p.Value)
+	}
+	res.Bands = scatterBands(values)
+	for t, v := range byType {
+		if len(v) >= MinTypeWorkflowSample {
+			res.BandsByType[t] = scatterBands(v)
+		}
+	}
+
+	if len(values) > 0 {
+		above := make([]ScatterPoint, 0)
+		for _, p := range res.Points {
+			if p.Value > res.Bands.P95 {
+				above = append(above, p)
+			}
+		}
+		slices.SortStableFunc(above, func(a, b ScatterPoint) int {
+			return cmp.Compare(b.Value, a.Value)
+		})
+		for _, p := range above {
+			res.AboveP95 = append(res.AboveP95, p.Key)
+		}
+	}
+	return res
+}
@@ -0,0 +1,50 @@
+package stats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestBuildCycleTimeScatter(t *testing.T) {
+	var issues []jira.Issue
+	var cycleTimes []float64
+	for i := range 40 {
+		done := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, i)
+		issueType := "Story"
+		if i%10 == 0 {
+			issueType = "Bug"
+		}
+		issues = append(issues, jira.Issue{Key: fmt.Sprintf("P-%d", i+1), IssueType: issueType, OutcomeDate: &done})
+		cycleTimes = append(cycleTimes, float64(i+1))
+	}
+	issues = append(issues, jira.Issue{Key: "P-99"}) // No outcome date: not plotted
+	cycleTimes = append(cycleTimes, 100)
+
+	res := BuildCycleTimeScatter(issues, cycleTimes)
+
+	if len(res.Points) != 40 || res.Points[0].Key != "P-1" || res.Points[0].Date != "2026-03-01" {
+		t.Fatalf("Expected 40 dated points keyed by issue, got %d (%+v)", len(res.Points), res.Points[0])
+	}
+	if res.Bands.Count != 40 || res.Bands.P50 != 21 || res.Bands.P85 != 35 || res.Bands.P95 != 39 {
+		t.Errorf("Unexpected pooled bands %+v", res.Bands)
+	}
+	if _, ok := res.BandsByType["Bug"]; ok {
+		t.Errorf("Bug has fewer than %d items and must not get its own bands, got %+v", MinTypeWorkflowSample, res.BandsByType)
+	}
+	if b := res.BandsByType["Story"]; b.Count != 36 {
+		t.Errorf("Expected Story bands over 36 items, got %+v", b)
+	}
+	if len(res.AboveP95) != 1 || res.AboveP95[0] != "P-40" {
+		t.Errorf("Expected only P-40 above P95, got %v", res.AboveP95)
+	}
+}
+
+func TestBuildCycleTimeScatter_Empty(t *testing.T) {
+	res := BuildCycleTimeScatter(nil, nil)
+	if res.Points == nil || res.AboveP95 == nil || res.Bands.Count != 0 {
+		t.Errorf("Expected empty, non-nil output, got %+v", res)
+	}
+}
@@ -1,6 +1,9 @@
 package stats
 
 import (
+	"iter"
+	"slices"
+
 	"mcs-mcp/internal/eventlog"
 	"mcs-mcp/internal/jira"
 )
@@ -9,12 +12,15 @@ import (
 // It manages data hydration, projection, and applying meta-workflow policies
 // to provide a consistent set of items for various analytical tools.
 type AnalysisSession struct {
-	events      []eventlog.IssueEvent
+	histories   iter.Seq[[]eventlog.IssueEvent]
 	sourceID    string
 	ctx         jira.SourceContext
 	mappings    map[string]StatusMetadata
 	resolutions map[string]string
 	window      AnalysisWindow
+	subtasks    SubtaskPolicy
+	containers  ContainerPolicy
+	outcomes    OutcomeScope
 
 	// Cached projections
 	allIssues []jira.Issue
@@ -27,16 +33,51 @@ type AnalysisSession struct {
 
 // NewAnalysisSession creates a new orchestration session.
 func NewAnalysisSession(events []eventlog.IssueEvent, sourceID string, ctx jira.SourceContext, mapping map[string]StatusMetadata, resolutions map[string]string, window AnalysisWindow) *AnalysisSession {
+	return NewStreamingAnalysisSession(eventlog.GroupByIssue(events), sourceID, ctx, mapping, resolutions, window)
+}
+
+// NewStreamingAnalysisSession creates a session that projects per-issue
+// histories, such as those of eventlog.LogProvider.IssuesInRange, as they are
+// yielded. The session keeps only the projected issues; histories is iterated
+// again when the subtask policy changes.
+func NewStreamingAnalysisSession(histories iter.Seq[[]eventlog.IssueEvent], sourceID string, ctx jira.SourceContext, mapping map[string]StatusMetadata, resolutions map[string]string, window AnalysisWindow) *AnalysisSession {
 	return &AnalysisSession{
-		events:      events,
+		histories:   histories,
 		sourceID:    sourceID,
 		ctx:         ctx,
 		mappings:    mapping,
 		resolutions: resolutions,
 		window:      window,
+		subtasks:    SubtasksExclude,
+		containers:  ContainersExclude,
+		outcomes:    OutcomesDelivered,
 	}
 }
 
+// WithSubtaskPolicy sets how sub-tasks enter the session's projections.
+func (s *AnalysisSession) WithSubtaskPolicy(policy SubtaskPolicy) *AnalysisSession {
+	s.subtasks = policy
+	s.isProjected = false
+	return s
+}
+
+// WithOutcomeScope sets which finished items GetDelivered returns: the
+// delivered ones (default), the abandoned ones, or all.
+func (s *AnalysisSession) WithOutcomeScope(scope OutcomeScope) *AnalysisSession {
+	s.outcomes = scope
+	s.isProjected = false
+	return s
+}
+
+// WithContainerPolicy sets whether container items (epics, initiatives)
+// enter the session's delivered, finished, and WIP items. GetAllIssues keeps
+// them under either policy, so that hierarchy walks still find every parent.
+func (s *AnalysisSession) WithContainerPolicy(policy ContainerPolicy) *AnalysisSession {
+	s.containers = policy
+	s.isProjected = false
+	return s
+}
+
 // Project ensures that events are projected into domain issues for the session's window.
 func (s *AnalysisSession) Project() error {
 	if s.isProjected {
@@ -44,13 +85,19 @@ func (s *AnalysisSession) Project() error {
 	}
 
 	// 1. Process events into basic domain issues
-	finished, downstream, upstream, demand := ProjectScope(s.events, s.window, "", s.mappings, s.resolutions, nil)
+	finished, downstream, upstream, demand := projectHistories(s.histories, s.window, "", s.mappings, s.resolutions, nil, s.subtasks)
+	relinkMovedParents(IdentityOf(slices.Concat(finished, downstream, upstream, demand)), finished, downstream, upstream, demand)
 
 	// We'll store all un-filtered items first
 	s.allIssues = append(finished, append(downstream, append(upstream, demand...)...)...)
+	if s.containers == ContainersExclude {
+		containers := ContainerKeys(s.allIssues)
+		finished = withoutContainers(finished, containers)
+		downstream = withoutContainers(downstream, containers)
+	}
 	s.wip = downstream
 	s.finished = finished
-	s.delivered = FilterDelivered(finished)
+	s.delivered = FilterOutcomes(finished, s.outcomes)
 
 	_ = upstream
 	_ = demand
@@ -59,7 +106,20 @@ func (s *AnalysisSession) Project() error {
 	return nil
 }
 
-// GetDelivered returns the set of successfully finished items in the window.
+// Clone projects s and returns a copy whose issue lists can be filtered and
+// sorted without affecting s. The issues themselves are shared.
+func (s *AnalysisSession) Clone() *AnalysisSession {
+	_ = s.Project()
+	c := *s
+	c.allIssues = slices.Clone(s.allIssues)
+	c.delivered = slices.Clone(s.delivered)
+	c.finished = slices.Clone(s.finished)
+	c.wip = slices.Clone(s.wip)
+	return &c
+}
+
+// GetDelivered returns the finished items in the window that count as done
+// under the outcome scope: the successfully delivered ones by default.
 func (s *AnalysisSession) GetDelivered() []jira.Issue {
 	_ = s.Project()
 	return s.delivered
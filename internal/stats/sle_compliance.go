@@ -0,0 +1,180 @@
+package stats
+
+import (
+	"cmp"
+	"mcs-mcp/internal/jira"
+	"slices"
+	"sort"
+)
+
+// AllIssueTypes is the issue type of an SLE that applies to every type
+// without an SLE of its own.
+const AllIssueTypes = "*"
+
+// ServiceLevelExpectation is a recorded SLE: Percentile % of the items of
+// IssueType finish within DurationDays of their commitment point.
+type ServiceLevelExpectation struct {
+	IssueType    string  `json:"issue_type"`
+	Percentile   int     `json:"percentile"`
+	DurationDays float64 `json:"duration_days"`
+}
+
+// SLE outlook states of an in-flight item.
+const (
+	SLEOnTrack  = "on_track"
+	SLEAtRisk   = "at_risk"
+	SLEBreached = "breached"
+)
+
+// SLE breach trend directions.
+const (
+	SLETrendImproving    = "improving"
+	SLETrendWorsening    = "worsening"
+	SLETrendStable       = "stable"
+	SLETrendInsufficient = "insufficient_data"
+)
+
+const (
+	// sleRiskThreshold is the breach probability from which an in-flight item is at risk.
+	sleRiskThreshold = 0.5
+	// sleTrendTolerance is the attainment change between the halves of the
+	// window below which the breach trend counts as stable.
+	sleTrendTolerance = 0.10
+	// sleTrendMinBuckets is the number of buckets with deliveries a trend needs.
+	sleTrendMinBuckets = 4
+)
+
+// SLEWIPItem is the SLE outlook of one in-flight item.
+type SLEWIPItem struct {
+	Key               string  `json:"key"`
+	Type              string  `json:"type"`
+	Status            string  `json:"status"`
+	AgeDays           float64 `json:"age_days"`
+	RemainingDays     float64 `json:"remaining_days"`     // Negative once breached
+	BreachProbability float64 `json:"breach_probability"` // Share of historical items of this age that breached
+	State             string  `json:"state"`
+}
+
+// SLECompliance is the compliance report of one SLE over an analysis window.
+type SLECompliance struct {
+	SLE             ServiceLevelExpectation `json:"sle"`
+	Delivered       int                     `json:"delivered"`
+	Breaches        int                     `json:"breaches"`
+	HitRate         float64                 `json:"hit_rate"`
+	ExpectedHitRate float64                 `json:"expected_hit_rate"`
+	Met             bool                    `json:"met"`
+	BreachTrend     string                  `json:"breach_trend"`
+	Trend           []AdherenceBucket       `json:"trend"`
+	AtRisk          int                     `json:"at_risk_count"`
+	BreachedWIP     int                     `json:"breached_wip_count"`
+	WIP             []SLEWIPItem            `json:"wip"` // Highest breach probability first
+}
+
+// EvaluateSLECompliance reports how an SLE was met by delivered items and how
+// the in-flight items are tracking against it.
+//
+// issues and cycleTimes must be aligned by index and hold only the delivered
+// items the SLE applies to; wip holds its in-flight items. window provides
+// the trend buckets (typically weekly).
+func EvaluateSLECompliance(sle ServiceLevelExpectation, issues []jira.Issue, cycleTimes []float64, wip []InventoryAge, window AnalysisWindow) SLECompliance {
+	adherence := ComputeSLEAdherence(issues, cycleTimes, sle.DurationDays, sle.Percentile, "user", window)
+
+	res := SLECompliance{
+		SLE:             sle,
+		ExpectedHitRate: adherence.ExpectedRate,
+		HitRate:         adherence.OverallRate,
+		Trend:           adherence.Buckets,
+		BreachTrend:     breachTrend(adherence.Buckets),
+		WIP:             make([]SLEWIPItem, 0),
+	}
+	for _, b := range adherence.Buckets {
+		res.Delivered += b.DeliveredCount
+		res.Breaches += b.BreachCount
+	}
+	res.Met = res.Delivered > 0 && res.HitRate >= res.ExpectedHitRate
+
+	history := slices.Clone(cycleTimes)
+	slices.Sort(history)
+	for _, item := range wip {
+		if item.AgeSinceCommitment == nil {
+			continue
+		}
+		age := *item.AgeSinceCommitment
+		out := SLEWIPItem{
+			Key:               item.Key,
+			Type:              item.Type,
+			Status:            item.Status,
+			AgeDays:           Round2(age),
+			RemainingDays:     Round2(sle.DurationDays - age),
+			BreachProbability: Round2(breachProbability(history, age, sle.DurationDays)),
+		}
+		switch {
+		case age > sle.DurationDays:
+			out.State = SLEBreached
+			res.BreachedWIP++
+		case out.BreachProbability >= sleRiskThreshold:
+			out.State = SLEAtRisk
+			res.AtRisk++
+		default:
+			out.State = SLEOnTrack
+		}
+		res.WIP = append(res.WIP, out)
+	}
+	slices.SortStableFunc(res.WIP, func(a, b SLEWIPItem) int {
+		if c := cmp.Compare(b.BreachProbability, a.BreachProbability); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.AgeDays, a.AgeDays)
+	})
+
+	return res
+}
+
+// breachProbability estimates P(CT > sle | CT > age) from sorted historical
+// cycle times. Without historical items older than age, the item is past
+// anything seen before and counts as certain to breach.
+func breachProbability(sorted []float64, age, sle float64) float64 {
+	if age > sle {
+		return 1
+	}
+	olderThan := func(x float64) int {
+		return len(sorted) - sort.Search(len(sorted), func(i int) bool { return sorted[i] > x })
+	}
+	survivors := olderThan(age)
+	if survivors == 0 {
+		return 1
+	}
+	return float64(olderThan(sle)) / float64(survivors)
+}
+
+// breachTrend compares the attainment rate of the first and second half of
+// the complete buckets with deliveries.
+func breachTrend(buckets []AdherenceBucket) string {
+	var delivered [][2]int // {delivered, breaches}
+	for _, b := range buckets {
+		if b.DeliveredCount > 0 && !b.IsPartial {
+			delivered = append(delivered, [2]int{b.DeliveredCount, b.BreachCount})
+		}
+	}
+	if len(delivered) < sleTrendMinBuckets {
+		return SLETrendInsufficient
+	}
+	rate := func(part [][2]int) float64 {
+		n, breaches := 0, 0
+		for _, p := range part {
+			n += p[0]
+			breaches += p[1]
+		}
+		return 1 - float64(breaches)/float64(n)
+	}
+	half := len(delivered) / 2
+	change := rate(delivered[half:]) - rate(delivered[:half])
+	switch {
+	case change > sleTrendTolerance:
+		return SLETrendImproving
+	case change < -sleTrendTolerance:
+		return SLETrendWorsening
+	default:
+		return SLETrendStable
+	}
+}
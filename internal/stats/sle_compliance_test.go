@@ -0,0 +1,92 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestBreachProbability(t *testing.T) {
+	history := []float64{2, 4, 6, 8, 12, 20}
+
+	if got := breachProbability(history, 0, 10); got != 2.0/6.0 {
+		t.Errorf("At age 0 expected the unconditional breach rate 2/6, got %v", got)
+	}
+	if got := breachProbability(history, 7, 10); got != 2.0/3.0 {
+		t.Errorf("At age 7 expected 2 of the 3 older items to breach, got %v", got)
+	}
+	if got := breachProbability(history, 11, 10); got != 1 {
+		t.Errorf("Expected an item past the SLE to be certain to breach, got %v", got)
+	}
+	if got := breachProbability(history, 25, 30); got != 1 {
+		t.Errorf("Expected an item older than all history to count as certain to breach, got %v", got)
+	}
+}
+
+func TestBreachTrend(t *testing.T) {
+	bucket := func(delivered, breaches int) AdherenceBucket {
+		return AdherenceBucket{DeliveredCount: delivered, BreachCount: breaches}
+	}
+
+	worsening := []AdherenceBucket{bucket(10, 0), bucket(10, 1), bucket(10, 4), bucket(10, 5)}
+	if got := breachTrend(worsening); got != SLETrendWorsening {
+		t.Errorf("Expected %s, got %s", SLETrendWorsening, got)
+	}
+	improving := []AdherenceBucket{bucket(10, 5), bucket(10, 4), bucket(10, 1), bucket(10, 0)}
+	if got := breachTrend(improving); got != SLETrendImproving {
+		t.Errorf("Expected %s, got %s", SLETrendImproving, got)
+	}
+	stable := []AdherenceBucket{bucket(10, 1), bucket(10, 2), bucket(10, 1), bucket(10, 2), {DeliveredCount: 3, BreachCount: 3, IsPartial: true}}
+	if got := breachTrend(stable); got != SLETrendStable {
+		t.Errorf("Expected %s (partial bucket ignored), got %s", SLETrendStable, got)
+	}
+	if got := breachTrend(worsening[:3]); got != SLETrendInsufficient {
+		t.Errorf("Expected %s for three buckets, got %s", SLETrendInsufficient, got)
+	}
+}
+
+func TestEvaluateSLECompliance(t *testing.T) {
+	start := time.Date(2026, 1, 5, 0, 0, 0, 0, time.UTC) // Monday
+	w := mkWeeklyWindow(start, 4)
+
+	issues := []jira.Issue{
+		mkIssue("A-1", start.AddDate(0, 0, 1)),
+		mkIssue("A-2", start.AddDate(0, 0, 8)),
+		mkIssue("A-3", start.AddDate(0, 0, 15)),
+		mkIssue("A-4", start.AddDate(0, 0, 22)),
+	}
+	cts := []float64{3, 5, 12, 8}
+	age := func(d float64) *float64 { return &d }
+	wip := []InventoryAge{
+		{Key: "W-1", Type: "Story", AgeSinceCommitment: age(1)},
+		{Key: "W-2", Type: "Story", AgeSinceCommitment: age(9)},
+		{Key: "W-3", Type: "Story", AgeSinceCommitment: age(11)},
+		{Key: "W-4", Type: "Story"}, // not committed yet
+	}
+
+	sle := ServiceLevelExpectation{IssueType: "Story", Percentile: 85, DurationDays: 10}
+	res := EvaluateSLECompliance(sle, issues, cts, wip, w)
+
+	if res.Delivered != 4 || res.Breaches != 1 || res.HitRate != 0.75 {
+		t.Errorf("Expected 1 breach in 4 deliveries (hit rate 0.75), got %d/%d (%v)", res.Breaches, res.Delivered, res.HitRate)
+	}
+	if res.ExpectedHitRate != 0.85 || res.Met {
+		t.Errorf("Expected a missed P85 SLE, got expected %v met %v", res.ExpectedHitRate, res.Met)
+	}
+	if len(res.WIP) != 3 {
+		t.Fatalf("Expected the 3 committed items, got %d", len(res.WIP))
+	}
+	states := map[string]string{}
+	for _, item := range res.WIP {
+		states[item.Key] = item.State
+	}
+	if states["W-1"] != SLEOnTrack || states["W-2"] != SLEAtRisk || states["W-3"] != SLEBreached {
+		t.Errorf("Expected W-1 on track, W-2 at risk, W-3 breached, got %v", states)
+	}
+	if res.WIP[0].Key != "W-3" || res.WIP[0].RemainingDays != -1 {
+		t.Errorf("Expected the breached item first with -1 remaining days, got %+v", res.WIP[0])
+	}
+	if res.AtRisk != 1 || res.BreachedWIP != 1 {
+		t.Errorf("Expected 1 at-risk and 1 breached item, got %d and %d", res.AtRisk, res.BreachedWIP)
+	}
+}
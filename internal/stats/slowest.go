@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// SlowMeasure is the duration by which delivered items are ranked as the
+// slowest.
+type SlowMeasure string
+
+const (
+	// SlowByCycleTime ranks by cycle time (default).
+	SlowByCycleTime SlowMeasure = "cycle_time"
+	// SlowByStatusTime ranks by the time spent in one status.
+	SlowByStatusTime SlowMeasure = "status_time"
+	// SlowByBlockedTime ranks by the time spent flagged as blocked.
+	SlowByBlockedTime SlowMeasure = "blocked_time"
+)
+
+// ParseSlowMeasure validates a measure name; "" is SlowByCycleTime.
+func ParseSlowMeasure(s string) (SlowMeasure, error) {
+	switch m := SlowMeasure(s); m {
+	case "":
+		return SlowByCycleTime, nil
+	case SlowByCycleTime, SlowByStatusTime, SlowByBlockedTime:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid measure %q: expected cycle_time, status_time, or blocked_time", s)
+}
+
+// SlowItem is a delivered item of a slowest-items ranking.
+type SlowItem struct {
+	Key                 string  `json:"key"`
+	Summary             string  `json:"summary,omitempty"`
+	IssueType           string  `json:"issue_type"`
+	Delivered           string  `json:"delivered"` // Outcome date, YYYY-MM-DD
+	CycleTimeDays       float64 `json:"cycle_time_days"`
+	CycleTimePercentile int     `json:"cycle_time_percentile"` // Share of the delivered items at or below this cycle time
+	StatusDays          float64 `json:"status_days,omitempty"` // Time in the ranked status (status_time only)
+	BlockedDays         float64 `json:"blocked_days"`
+}
+
+// SlowestItems is the result of RankSlowest.
+type SlowestItems struct {
+	Measure    SlowMeasure  `json:"measure"`
+	Status     string       `json:"status,omitempty"` // ID of the ranked status (status_time only)
+	Population int          `json:"population"`       // Delivered items with time in the measure
+	Bands      ScatterBands `json:"bands"`            // P50/P85/P95 of the measure over the population
+	Items      []SlowItem   `json:"items"`            // Slowest first
+}
+
+// RankSlowest returns the n delivered items with the most time in measure,
+// slowest first. issues and cycleTimes are parallel slices, as returned by the
+// cycle time extraction; status is the status ID ranked by SlowByStatusTime.
+// Items without time in the measure (never in the status, never blocked) are
+// not ranked. Ties go to the longer cycle time, then the key.
+func RankSlowest(issues []jira.Issue, cycleTimes []float64, measure SlowMeasure, status string, n int) SlowestItems {
+	sortedCT := slices.Clone(cycleTimes)
+	slices.Sort(sortedCT)
+
+	res := SlowestItems{Measure: measure, Items: make([]SlowItem, 0)}
+	if measure == SlowByStatusTime {
+		res.Status = status
+	}
+
+	type ranked struct {
+		item  SlowItem
+		value float64
+	}
+	var candidates []ranked
+	var values []float64
+	for i, issue := range issues {
+		item := SlowItem{
+			Key:           issue.Key,
+			IssueType:     issue.IssueType,
+			CycleTimeDays: Round2(cycleTimes[i]),
+			BlockedDays:   Round2(blockedDays(issue)),
+		}
+		if issue.OutcomeDate != nil {
+			item.Delivered = issue.OutcomeDate.Format("2006-01-02")
+		}
+		atOrBelow, _ := slices.BinarySearch(sortedCT, math.Nextafter(cycleTimes[i], math.Inf(1)))
+		item.CycleTimePercentile = atOrBelow * 100 / len(sortedCT)
+
+		var value float64
+		switch measure {
+		case SlowByStatusTime:
+			value = float64(issue.StatusResidency[status]) / 86400.0
+			item.StatusDays = Round2(value)
+		case SlowByBlockedTime:
+			value = blockedDays(issue)
+		default:
+			value = cycleTimes[i]
+		}
+		if value <= 0 {
+			continue
+		}
+		candidates = append(candidates, ranked{item: item, value: value})
+		values = append(values, value)
+	}
+
+	res.Population = len(candidates)
+	res.Bands = scatterBands(values)
+	res.Bands.round()
+	slices.SortFunc(candidates, func(a, b ranked) int {
+		if c := cmp.Compare(b.value, a.value); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.item.CycleTimeDays, a.item.CycleTimeDays); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.item.Key, b.item.Key)
+	})
+	for _, c := range candidates[:min(n, len(candidates))] {
+		res.Items = append(res.Items, c.item)
+	}
+	return res
+}
+
+// blockedDays returns the days an issue spent flagged as blocked, over all
+// statuses.
+func blockedDays(issue jira.Issue) float64 {
+	var seconds int64
+	for _, s := range issue.BlockedResidency {
+		seconds += s
+	}
+	return float64(seconds) / 86400.0
+}
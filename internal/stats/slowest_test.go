@@ -0,0 +1,49 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestRankSlowest(t *testing.T) {
+	done := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	issues := []jira.Issue{
+		{Key: "PROJ-1", IssueType: "Story", OutcomeDate: &done, StatusResidency: map[string]int64{"review": 2 * 86400}},
+		{Key: "PROJ-2", IssueType: "Story", OutcomeDate: &done, StatusResidency: map[string]int64{"review": 6 * 86400}, BlockedResidency: map[string]int64{"dev": 86400, "review": 86400}},
+		{Key: "PROJ-3", IssueType: "Bug", OutcomeDate: &done},
+		{Key: "PROJ-4", IssueType: "Bug", OutcomeDate: &done, BlockedResidency: map[string]int64{"dev": 3 * 86400}},
+	}
+	cycleTimes := []float64{10, 8, 20, 4}
+
+	byCT := RankSlowest(issues, cycleTimes, SlowByCycleTime, "", 2)
+	if len(byCT.Items) != 2 || byCT.Items[0].Key != "PROJ-3" || byCT.Items[1].Key != "PROJ-1" {
+		t.Fatalf("cycle_time: expected PROJ-3 then PROJ-1, got %+v", byCT.Items)
+	}
+	if byCT.Population != 4 || byCT.Items[0].CycleTimePercentile != 100 || byCT.Items[1].CycleTimePercentile != 75 {
+		t.Errorf("cycle_time: expected a population of 4 and percentiles 100 and 75, got %d and %+v", byCT.Population, byCT.Items)
+	}
+	if byCT.Items[0].Delivered != "2024-05-01" {
+		t.Errorf("Expected the delivery date, got %q", byCT.Items[0].Delivered)
+	}
+
+	byStatus := RankSlowest(issues, cycleTimes, SlowByStatusTime, "review", 10)
+	if byStatus.Population != 2 || byStatus.Items[0].Key != "PROJ-2" || byStatus.Items[0].StatusDays != 6 {
+		t.Errorf("status_time: expected PROJ-2 first of the 2 items that visited the status, got %+v", byStatus)
+	}
+
+	byBlocked := RankSlowest(issues, cycleTimes, SlowByBlockedTime, "", 10)
+	if byBlocked.Population != 2 || byBlocked.Items[0].Key != "PROJ-4" || byBlocked.Items[1].BlockedDays != 2 {
+		t.Errorf("blocked_time: expected PROJ-4 then PROJ-2 with 2 blocked days, got %+v", byBlocked.Items)
+	}
+}
+
+func TestParseSlowMeasure(t *testing.T) {
+	if m, err := ParseSlowMeasure(""); err != nil || m != SlowByCycleTime {
+		t.Errorf("Expected \"\" to mean cycle_time, got %q (err %v)", m, err)
+	}
+	if _, err := ParseSlowMeasure("age"); err == nil {
+		t.Error("Expected an unknown measure to fail")
+	}
+}
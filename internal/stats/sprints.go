@@ -0,0 +1,189 @@
+package stats
+
+import (
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+// MinSprintSamples is the number of closed sprints required before per-sprint
+// throughput is used as a forecasting sample.
+const MinSprintSamples = 3
+
+// SprintStats summarizes delivery within a single sprint.
+type SprintStats struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	State           string  `json:"state"`
+	Start           string  `json:"start"`
+	End             string  `json:"end"` // Actual close, or planned end while active
+	LengthDays      float64 `json:"length_days"`
+	Committed       int     `json:"committed"`        // Tracked items assigned to the sprint
+	Completed       int     `json:"completed"`        // Assigned items delivered by the close
+	CarryOver       int     `json:"carry_over"`       // Assigned items still open at the close
+	Throughput      int     `json:"throughput"`       // All items delivered within the sprint, assigned or not
+	CompletionRatio float64 `json:"completion_ratio"` // Completed / Committed
+}
+
+// SprintSummary aggregates closed sprints.
+type SprintSummary struct {
+	ClosedSprints      int     `json:"closed_sprints"`
+	MedianLengthDays   float64 `json:"median_length_days"`
+	ThroughputP50      float64 `json:"throughput_p50"`
+	ThroughputP85      float64 `json:"throughput_p85"` // 85% of sprints delivered at least this many items
+	AvgCompletionRatio float64 `json:"avg_completion_ratio"`
+	AvgCarryOverRatio  float64 `json:"avg_carry_over_ratio"` // CarryOver / Committed
+}
+
+// SprintHistoryResult is the per-sprint delivery history of a Scrum board.
+type SprintHistoryResult struct {
+	Sprints []SprintStats `json:"sprints"`
+	Summary SprintSummary `json:"summary"`
+}
+
+// Round rounds all numeric fields to 2 decimal places for output compactness.
+func (r *SprintHistoryResult) Round() {
+	for i := range r.Sprints {
+		r.Sprints[i].LengthDays = Round2(r.Sprints[i].LengthDays)
+		r.Sprints[i].CompletionRatio = Round2(r.Sprints[i].CompletionRatio)
+	}
+	r.Summary.MedianLengthDays = Round2(r.Summary.MedianLengthDays)
+	r.Summary.ThroughputP50 = Round2(r.Summary.ThroughputP50)
+	r.Summary.ThroughputP85 = Round2(r.Summary.ThroughputP85)
+	r.Summary.AvgCompletionRatio = Round2(r.Summary.AvgCompletionRatio)
+	r.Summary.AvgCarryOverRatio = Round2(r.Summary.AvgCarryOverRatio)
+}
+
+// sprintClose returns when a sprint ended: its actual close (or planned end
+// when the close was not recorded), or evaluationTime while it is active.
+func sprintClose(sp jira.Sprint, evaluationTime time.Time) time.Time {
+	if sp.Complete != nil {
+		return *sp.Complete
+	}
+	if sp.State == "closed" && !sp.End.IsZero() {
+		return sp.End
+	}
+	return evaluationTime
+}
+
+// SprintsStartingWithin returns the sprints that started within [start, end].
+func SprintsStartingWithin(sprints []jira.Sprint, start, end time.Time) []jira.Sprint {
+	var out []jira.Sprint
+	for _, sp := range sprints {
+		if !sp.Start.Before(start) && !sp.Start.After(end) {
+			out = append(out, sp)
+		}
+	}
+	return out
+}
+
+// AnalyzeSprintHistory measures commitment, completion, carry-over, and
+// throughput per sprint. Assigned issues absent from issues (sub-tasks, other
+// projects) are not tracked. Only closed sprints enter the summary.
+func AnalyzeSprintHistory(sprints []jira.Sprint, issues []jira.Issue, evaluationTime time.Time) SprintHistoryResult {
+	byKey := make(map[string]jira.Issue, len(issues))
+	for _, issue := range issues {
+		byKey[issue.Key] = issue
+	}
+
+	res := SprintHistoryResult{Sprints: make([]SprintStats, 0, len(sprints))}
+	var lengths, throughputs []float64
+	var completionSum, carrySum float64
+	var ratioCount int
+
+	for _, sp := range sprints {
+		end := sprintClose(sp, evaluationTime)
+		st := SprintStats{
+			ID:         sp.ID,
+			Name:       sp.Name,
+			State:      sp.State,
+			Start:      sp.Start.Format(DateFormat),
+			End:        end.Format(DateFormat),
+			LengthDays: end.Sub(sp.Start).Hours() / 24,
+		}
+
+		for _, key := range sp.IssueKeys {
+			issue, ok := byKey[key]
+			if !ok {
+				continue
+			}
+			st.Committed++
+			exited := issue.OutcomeDate != nil && !issue.OutcomeDate.After(end)
+			switch {
+			case exited && issue.Outcome == "delivered":
+				st.Completed++
+			case !exited:
+				st.CarryOver++
+			}
+		}
+		for _, issue := range issues {
+			if issue.Outcome == "delivered" && issue.OutcomeDate != nil &&
+				!issue.OutcomeDate.Before(sp.Start) && !issue.OutcomeDate.After(end) {
+				st.Throughput++
+			}
+		}
+		if st.Committed > 0 {
+			st.CompletionRatio = float64(st.Completed) / float64(st.Committed)
+		}
+		res.Sprints = append(res.Sprints, st)
+
+		if sp.State != "closed" {
+			continue
+		}
+		res.Summary.ClosedSprints++
+		lengths = append(lengths, st.LengthDays)
+		throughputs = append(throughputs, float64(st.Throughput))
+		if st.Committed > 0 {
+			completionSum += st.CompletionRatio
+			carrySum += float64(st.CarryOver) / float64(st.Committed)
+			ratioCount++
+		}
+	}
+
+	if res.Summary.ClosedSprints > 0 {
+		res.Summary.MedianLengthDays = PercentileOf(lengths, 0.50)
+		res.Summary.ThroughputP50 = PercentileOf(throughputs, 0.50)
+		res.Summary.ThroughputP85 = PercentileOf(throughputs, 0.15)
+	}
+	if ratioCount > 0 {
+		res.Summary.AvgCompletionRatio = completionSum / float64(ratioCount)
+		res.Summary.AvgCarryOverRatio = carrySum / float64(ratioCount)
+	}
+	return res
+}
+
+// SprintThroughputSamples returns the throughput of each closed sprint, in
+// sprint order, for use as a per-sprint forecasting sample.
+func SprintThroughputSamples(res SprintHistoryResult) []int {
+	var samples []int
+	for _, st := range res.Sprints {
+		if st.State == "closed" {
+			samples = append(samples, st.Throughput)
+		}
+	}
+	return samples
+}
+
+// NextSprintBoundaries projects the end dates of the next n sprints. The first
+// boundary is the planned end of the active sprint when one is running;
+// subsequent sprints last lengthDays each.
+func NextSprintBoundaries(sprints []jira.Sprint, lengthDays float64, n int, evaluationTime time.Time) []time.Time {
+	if n <= 0 || lengthDays <= 0 {
+		return nil
+	}
+	step := time.Duration(lengthDays * 24 * float64(time.Hour))
+
+	next := evaluationTime.Add(step)
+	for _, sp := range sprints {
+		if sp.State == "active" && !sp.End.IsZero() && sp.End.After(evaluationTime) {
+			next = sp.End
+		}
+	}
+
+	out := make([]time.Time, n)
+	for i := range out {
+		out[i] = next
+		next = next.Add(step)
+	}
+	return out
+}
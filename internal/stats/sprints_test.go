@@ -0,0 +1,58 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/jira"
+)
+
+func TestAnalyzeSprintHistory(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	issues := []jira.Issue{
+		{Key: "P-1", Outcome: "delivered", OutcomeDate: ptr(day(3))},  // Sprint 1, planned
+		{Key: "P-2", Outcome: "delivered", OutcomeDate: ptr(day(20))}, // Planned in Sprint 1, carried over
+		{Key: "P-3", Outcome: "delivered", OutcomeDate: ptr(day(5))},  // Unplanned, delivered in Sprint 1
+		{Key: "P-4"}, // Still open
+	}
+	sprints := []jira.Sprint{
+		{ID: 1, Name: "S1", State: "closed", Start: day(1), End: day(14), Complete: ptr(day(14)), IssueKeys: []string{"P-1", "P-2", "X-9"}},
+		{ID: 2, Name: "S2", State: "closed", Start: day(15), End: day(28), Complete: ptr(day(28)), IssueKeys: []string{"P-2", "P-4"}},
+		{ID: 3, Name: "S3", State: "active", Start: day(29), End: time.Date(2026, 4, 11, 12, 0, 0, 0, time.UTC), IssueKeys: []string{"P-4"}},
+	}
+
+	res := AnalyzeSprintHistory(sprints, issues, day(30))
+
+	s1 := res.Sprints[0]
+	if s1.Committed != 2 || s1.Completed != 1 || s1.CarryOver != 1 || s1.Throughput != 2 {
+		t.Errorf("Sprint 1: expected committed=2 completed=1 carry_over=1 throughput=2, got %+v", s1)
+	}
+	s2 := res.Sprints[1]
+	if s2.Committed != 2 || s2.Completed != 1 || s2.CarryOver != 1 || s2.Throughput != 1 {
+		t.Errorf("Sprint 2: expected committed=2 completed=1 carry_over=1 throughput=1, got %+v", s2)
+	}
+	if res.Sprints[2].End != day(30).Format(DateFormat) {
+		t.Errorf("Active sprint should be measured up to the evaluation time, got %s", res.Sprints[2].End)
+	}
+	if res.Summary.ClosedSprints != 2 || res.Summary.AvgCarryOverRatio != 0.5 {
+		t.Errorf("Unexpected summary %+v", res.Summary)
+	}
+	if got := SprintThroughputSamples(res); len(got) != 2 || got[0] != 2 || got[1] != 1 {
+		t.Errorf("Expected closed-sprint samples [2 1], got %v", got)
+	}
+
+	boundaries := NextSprintBoundaries(sprints, 14, 2, day(30))
+	if len(boundaries) != 2 || !boundaries[0].Equal(sprints[2].End) || !boundaries[1].Equal(sprints[2].End.AddDate(0, 0, 14)) {
+		t.Errorf("Expected boundaries anchored on the active sprint end, got %v", boundaries)
+	}
+}
+
+func TestSprintsStartingWithin(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	sprints := []jira.Sprint{{ID: 1, Start: day(1)}, {ID: 2, Start: day(15)}}
+	if got := SprintsStartingWithin(sprints, day(10), day(31)); len(got) != 1 || got[0].ID != 2 {
+		t.Errorf("Expected only sprint 2, got %+v", got)
+	}
+}
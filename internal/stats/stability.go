@@ -1,6 +1,7 @@
 package stats
 
 import (
+	"fmt"
 	"math"
 	"mcs-mcp/internal/jira"
 )
@@ -42,14 +43,42 @@ func (r *XmRResult) Round() {
 	}
 }
 
+// Wheeler's detection rules for special cause variation, in the order of the
+// evidence they need.
+const (
+	RuleBeyondLimits = 1 // A point outside the natural process limits
+	RuleCluster      = 2 // 3 of 4 successive points beyond one sigma on the same side
+	RuleRun          = 3 // 8 successive points on the same side of the average
+	RuleTrend        = 4 // 6 successive points steadily increasing or decreasing
+)
+
 // Signal represents a detected special cause variation.
 type Signal struct {
-	Index       int    `json:"index"`
+	Index       int    `json:"index"` // The point at which the rule fired
 	Key         string `json:"key"`
-	Type        string `json:"type"` // "outlier", "shift"
+	Type        string `json:"type"`           // "outlier", "cluster", "shift", "trend"
+	Rule        int    `json:"rule,omitempty"` // The Wheeler rule that fired (RuleBeyondLimits … RuleTrend)
+	StartIndex  int    `json:"start_index"`    // First point of the pattern
+	EndIndex    int    `json:"end_index"`      // Last point of the pattern
+	From        string `json:"from,omitempty"` // Date of the first point, see SetSignalDates
+	To          string `json:"to,omitempty"`   // Date of the last point
 	Description string `json:"description"`
 }
 
+// SetSignalDates dates the signals: from[i] and to[i] are the first and last
+// day of point i, e.g. of a weekly bucket, or both the delivery date of an item.
+func (r *XmRResult) SetSignalDates(from, to []string) {
+	for i := range r.Signals {
+		sig := &r.Signals[i]
+		if sig.StartIndex < len(from) {
+			sig.From = from[sig.StartIndex]
+		}
+		if sig.EndIndex < len(to) {
+			sig.To = to[sig.EndIndex]
+		}
+	}
+}
+
 // CalculateXmR performs the math for an Individuals and Moving Range chart.
 func CalculateXmR(values []float64) XmRResult {
 	return CalculateXmRWithKeys(values, nil)
@@ -128,6 +157,13 @@ func CalculateProcessStability(issues []jira.Issue, cycleTimes []float64, wipCou
 	}
 
 	xmr := CalculateXmRWithKeys(cycleTimes, keys)
+	dates := make([]string, len(issues))
+	for i, iss := range issues {
+		if iss.OutcomeDate != nil {
+			dates[i] = iss.OutcomeDate.Format(DateFormat)
+		}
+	}
+	xmr.SetSignalDates(dates, dates)
 
 	stabilityIndex := 0.0
 	expectedLeadTime := 0.0
@@ -188,6 +224,8 @@ func AnalyzeTimeStability(historicalCycleTimes []float64, currentWIPAges []float
 			result.WIPSignals = append(result.WIPSignals, Signal{
 				Index:       i,
 				Type:        "wip_outlier",
+				StartIndex:  i,
+				EndIndex:    i,
 				Description: "Active WIP Age exceeds historical Upper Natural Process Limit (UNPL)",
 			})
 			if result.Status == "stable" {
@@ -245,14 +283,15 @@ func CalculateThreeWayXmR(subgroups []SubgroupStats) ThreeWayResult {
 		Status:       "stable",
 	}
 
-	// 3. Interpret System Evolution
+	// 3. Interpret System Evolution: sustained patterns (runs, trends) mean
+	// the process moved; isolated excursions mean it is volatile.
 	shiftCount := 0
 	outlierCount := 0
 	for _, signal := range avgChart.Signals {
-		if signal.Type == "shift" {
+		switch signal.Rule {
+		case RuleRun, RuleTrend:
 			shiftCount++
-		}
-		if signal.Type == "outlier" {
+		case RuleBeyondLimits, RuleCluster:
 			outlierCount++
 		}
 	}
@@ -392,60 +431,150 @@ func CalculateSystemPressure(activeIssues []jira.Issue) SystemPressureResult {
 }
 
 func detectSignals(values []float64, avg, unpl, lnpl float64, keys []string) []Signal {
-	var signals []Signal
-
-	for i, v := range values {
-		key := ""
+	keyAt := func(i int) string {
 		if i < len(keys) {
-			key = keys[i]
+			return keys[i]
 		}
+		return ""
+	}
+	var signals []Signal
 
+	// Rule 1: points beyond the limits.
+	for i, v := range values {
 		if v > unpl {
 			signals = append(signals, Signal{
 				Index:       i,
-				Key:         key,
+				Key:         keyAt(i),
 				Type:        "outlier",
+				Rule:        RuleBeyondLimits,
+				StartIndex:  i,
+				EndIndex:    i,
 				Description: "Point above Upper Natural Process Limit (UNPL)",
 			})
 		} else if v < lnpl {
 			signals = append(signals, Signal{
 				Index:       i,
-				Key:         key,
+				Key:         keyAt(i),
 				Type:        "outlier",
+				Rule:        RuleBeyondLimits,
+				StartIndex:  i,
+				EndIndex:    i,
 				Description: "Point below Lower Natural Process Limit (LNPL)",
 			})
 		}
 	}
 
-	if len(values) >= 8 {
-		side := 0
-		count := 0
-		for i, v := range values {
-			currentSide := 0
-			if v > avg {
-				currentSide = 1
-			} else if v < avg {
-				currentSide = -1
+	side := func(v, threshold float64) int {
+		if v > avg+threshold {
+			return 1
+		}
+		if v < avg-threshold {
+			return -1
+		}
+		return 0
+	}
+
+	// Rule 2: 3 of 4 successive points beyond one sigma on the same side.
+	if sigma := (unpl - avg) / 3; sigma > 0 {
+		open, openSide := -1, 0 // The signal overlapping windows extend
+		for i := 3; i < len(values); i++ {
+			for _, dir := range []int{1, -1} {
+				first, last, count := -1, -1, 0
+				for j := i - 3; j <= i; j++ {
+					if side(values[j], sigma) == dir {
+						if first < 0 {
+							first = j
+						}
+						last = j
+						count++
+					}
+				}
+				if count < 3 {
+					continue
+				}
+				if open >= 0 && openSide == dir && first <= signals[open].EndIndex {
+					signals[open].EndIndex = max(signals[open].EndIndex, last)
+					continue
+				}
+				where := "above"
+				if dir < 0 {
+					where = "below"
+				}
+				signals = append(signals, Signal{
+					Index:       i,
+					Key:         keyAt(i),
+					Type:        "cluster",
+					Rule:        RuleCluster,
+					StartIndex:  first,
+					EndIndex:    last,
+					Description: fmt.Sprintf("3 of 4 successive points more than one sigma %s the average (Moderate Shift)", where),
+				})
+				open, openSide = len(signals)-1, dir
 			}
+		}
+	}
 
-			if currentSide == side && currentSide != 0 {
+	// Rule 3: 8 successive points on the same side of the average.
+	if len(values) >= 8 {
+		runSide, count := 0, 0
+		for i, v := range values {
+			current := side(v, 0)
+			if current == runSide && current != 0 {
 				count++
 			} else {
-				side = currentSide
+				runSide = current
 				count = 1
 			}
 
 			if count == 8 {
-				key := ""
-				if i < len(keys) {
-					key = keys[i]
-				}
 				signals = append(signals, Signal{
 					Index:       i,
-					Key:         key,
+					Key:         keyAt(i),
 					Type:        "shift",
+					Rule:        RuleRun,
+					StartIndex:  i - 7,
+					EndIndex:    i,
 					Description: "8 consecutive points on one side of the average identified (Process Shift)",
 				})
+			} else if count > 8 {
+				signals[len(signals)-1].EndIndex = i
+			}
+		}
+	}
+
+	// Rule 4: 6 successive points steadily increasing or decreasing.
+	if len(values) >= 6 {
+		direction, count := 0, 1
+		for i := 1; i < len(values); i++ {
+			current := 0
+			if values[i] > values[i-1] {
+				current = 1
+			} else if values[i] < values[i-1] {
+				current = -1
+			}
+			if current == direction && current != 0 {
+				count++
+			} else {
+				direction = current
+				count = 2
+			}
+
+			if current != 0 && count == 6 {
+				trend := "increasing"
+				if direction < 0 {
+					trend = "decreasing"
+				}
+				signals = append(signals, Signal{
+					Index:       i,
+					Key:         keyAt(i),
+					Type:        "trend",
+					Rule:        RuleTrend,
+					StartIndex:  i - 5,
+					EndIndex:    i,
+					Description: fmt.Sprintf("6 successive points steadily %s (Trend)", trend),
+				})
+			} else if current != 0 && count > 6 {
+				signals[len(signals)-1].EndIndex = i
 			}
 		}
 	}
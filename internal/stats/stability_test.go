@@ -62,6 +62,48 @@ func TestXmRSignals(t *testing.T) {
 	}
 }
 
+func TestDetectSignals_WheelerRules(t *testing.T) {
+	find := func(signals []Signal, rule int) *Signal {
+		for i := range signals {
+			if signals[i].Rule == rule {
+				return &signals[i]
+			}
+		}
+		return nil
+	}
+
+	// Average 10, UNPL 13: one sigma is 1.
+	cluster := detectSignals([]float64{10, 10, 11.5, 11.5, 10, 11.5, 10, 10}, 10, 13, 7, nil)
+	if len(cluster) != 1 {
+		t.Fatalf("Expected exactly one signal, got %+v", cluster)
+	}
+	if s := cluster[0]; s.Rule != RuleCluster || s.Type != "cluster" || s.StartIndex != 2 || s.EndIndex != 5 {
+		t.Errorf("Expected a cluster over points 2-5, got %+v", s)
+	}
+
+	run := detectSignals([]float64{10.5, 10.5, 10.5, 10.5, 10.5, 10.5, 10.5, 10.5, 10.5, 10.5, 9}, 10, 13, 7, nil)
+	if s := find(run, RuleRun); s == nil || s.Index != 7 || s.StartIndex != 0 || s.EndIndex != 9 {
+		t.Errorf("Expected a shift firing at point 7 and spanning points 0-9, got %+v", s)
+	}
+
+	trend := detectSignals([]float64{8, 9, 10, 11, 12, 12.5, 12.8, 9}, 10, 13, 7, nil)
+	if s := find(trend, RuleTrend); s == nil || s.Index != 5 || s.StartIndex != 0 || s.EndIndex != 6 || s.Type != "trend" {
+		t.Errorf("Expected an increasing trend over points 0-6, got %+v", s)
+	}
+	if s := find(trend, RuleBeyondLimits); s != nil {
+		t.Errorf("Expected no point beyond the limits, got %+v", s)
+	}
+}
+
+func TestSetSignalDates(t *testing.T) {
+	xmr := XmRResult{Signals: []Signal{{Rule: RuleRun, StartIndex: 1, EndIndex: 2}}}
+	xmr.SetSignalDates([]string{"2024-01-01", "2024-01-08", "2024-01-15"}, []string{"2024-01-07", "2024-01-14", "2024-01-21"})
+
+	if s := xmr.Signals[0]; s.From != "2024-01-08" || s.To != "2024-01-21" {
+		t.Errorf("Expected the signal to span 2024-01-08 to 2024-01-21, got %s to %s", s.From, s.To)
+	}
+}
+
 func TestAnalyzeTimeStability(t *testing.T) {
 	hist := []float64{10, 12, 11, 13, 11} // UNPL ~16.05
 	wip := []float64{12, 20}              // 20 is an outlier
@@ -0,0 +1,177 @@
+package stats
+
+import (
+	"cmp"
+	"slices"
+
+	"mcs-mcp/internal/jira"
+)
+
+// Stream dimensions supported by GetStreamThroughput. Assignee and team are
+// the group_by dimensions of throughput and cycle time; issue type is used by
+// the interference analysis.
+const (
+	StreamByIssueType = "issue_type"
+	StreamByComponent = "component"
+	StreamByEpic      = "epic"
+	StreamByLabel     = "label"
+	StreamByAssignee  = "assignee"
+	StreamByTeam      = "team"
+)
+
+// StreamRecentBuckets is the number of trailing buckets compared against the
+// full window to detect a stream whose share of delivery is collapsing.
+const StreamRecentBuckets = 4
+
+// StreamStarvationRatio: a stream is flagged as starving when its recent share
+// of delivery falls below this fraction of its share over the whole window.
+const StreamStarvationRatio = 0.5
+
+// StreamThroughput is the delivery series of a single stream.
+type StreamThroughput struct {
+	Stream      string     `json:"stream"`
+	Counts      []int      `json:"counts"`
+	Total       int        `json:"total"`
+	Share       float64    `json:"share"`        // Fraction of delivered items attributed to this stream (whole window)
+	RecentShare float64    `json:"recent_share"` // Same fraction over the trailing StreamRecentBuckets buckets
+	ZeroBuckets int        `json:"zero_buckets"`
+	Starving    bool       `json:"starving"`
+	XmR         *XmRResult `json:"stability,omitempty"`
+}
+
+// StreamThroughputResult attributes pooled delivery to streams along one dimension.
+type StreamThroughputResult struct {
+	Dimension       string             `json:"dimension"`
+	Pooled          []int              `json:"pooled"`
+	Streams         []StreamThroughput `json:"streams"`
+	Unattributed    int                `json:"unattributed"`     // Delivered items with no value for the dimension
+	MultiAttributed int                `json:"multi_attributed"` // Items counted in more than one stream (components/labels)
+}
+
+// Round rounds all numeric fields for output compactness.
+func (r *StreamThroughputResult) Round() {
+	for i := range r.Streams {
+		r.Streams[i].Share = Round2(r.Streams[i].Share)
+		r.Streams[i].RecentShare = Round2(r.Streams[i].RecentShare)
+		if r.Streams[i].XmR != nil {
+			r.Streams[i].XmR.Round()
+		}
+	}
+}
+
+// StreamKeys returns the stream values of an issue for the given dimension.
+// Components and labels are multi-valued; an issue type, epic, assignee, or
+// team is at most one value.
+func StreamKeys(issue jira.Issue, dimension string) []string {
+	switch dimension {
+	case StreamByIssueType:
+		if issue.IssueType != "" {
+			return []string{issue.IssueType}
+		}
+	case StreamByComponent:
+		return issue.Components
+	case StreamByLabel:
+		return issue.Labels
+	case StreamByEpic:
+		if issue.ParentKey != "" {
+			return []string{issue.ParentKey}
+		}
+	case StreamByAssignee:
+		if issue.Assignee != "" {
+			return []string{issue.Assignee}
+		}
+	case StreamByTeam:
+		if issue.Team != "" {
+			return []string{issue.Team}
+		}
+	}
+	return nil
+}
+
+// GetStreamThroughput buckets delivered items by outcome date and attributes each
+// bucket to the streams of the given dimension. Each stream gets its own XmR
+// stability assessment so a starving stream is visible even when pooled
+// throughput looks stable. Streams are sorted by total delivery, descending.
+func GetStreamThroughput(issues []jira.Issue, window AnalysisWindow, dimension string) StreamThroughputResult {
+	buckets := window.Subdivide()
+	res := StreamThroughputResult{
+		Dimension: dimension,
+		Pooled:    make([]int, len(buckets)),
+		Streams:   []StreamThroughput{},
+	}
+	byStream := make(map[string][]int)
+
+	for _, issue := range issues {
+		if !IsDelivered(issue) || issue.OutcomeDate == nil {
+			continue
+		}
+		idx := window.FindBucketIndex(*issue.OutcomeDate)
+		if idx < 0 || idx >= len(buckets) {
+			continue
+		}
+		res.Pooled[idx]++
+
+		keys := StreamKeys(issue, dimension)
+		if len(keys) == 0 {
+			res.Unattributed++
+			continue
+		}
+		if len(keys) > 1 {
+			res.MultiAttributed++
+		}
+		for _, k := range keys {
+			if _, ok := byStream[k]; !ok {
+				byStream[k] = make([]int, len(buckets))
+			}
+			byStream[k][idx]++
+		}
+	}
+
+	recentFrom := max(len(buckets)-StreamRecentBuckets, 0)
+	pooledTotal, pooledRecent := 0, 0
+	for i, c := range res.Pooled {
+		pooledTotal += c
+		if i >= recentFrom {
+			pooledRecent += c
+		}
+	}
+
+	from, to := window.BucketDates()
+	for name, counts := range byStream {
+		st := StreamThroughput{Stream: name, Counts: counts}
+		recent := 0
+		values := make([]float64, len(counts))
+		for i, c := range counts {
+			st.Total += c
+			if c == 0 {
+				st.ZeroBuckets++
+			}
+			if i >= recentFrom {
+				recent += c
+			}
+			values[i] = float64(c)
+		}
+		if pooledTotal > 0 {
+			st.Share = float64(st.Total) / float64(pooledTotal)
+		}
+		if pooledRecent > 0 {
+			st.RecentShare = float64(recent) / float64(pooledRecent)
+		}
+		st.Starving = st.Share > 0 && st.RecentShare < st.Share*StreamStarvationRatio
+		if len(values) > 0 {
+			xmr := CalculateXmR(values)
+			xmr.SetSignalDates(from, to)
+			st.XmR = &xmr
+		}
+		res.Streams = append(res.Streams, st)
+	}
+
+	slices.SortFunc(res.Streams, func(a, b StreamThroughput) int {
+		if c := cmp.Compare(b.Total, a.Total); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Stream, b.Stream)
+	})
+
+	return res
+}
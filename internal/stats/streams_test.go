@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestGetStreamThroughput(t *testing.T) {
+	end := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC) // Sunday
+	start := SnapToStart(end, "week").AddDate(0, 0, -7*7)
+	window := NewAnalysisWindow(start, end, "week", time.Time{})
+
+	at := func(week, day int) *time.Time {
+		d := start.AddDate(0, 0, week*7+day)
+		return &d
+	}
+	delivered := func(key string, date *time.Time, components ...string) jira.Issue {
+		return jira.Issue{Key: key, IssueType: "Story", Outcome: "delivered", OutcomeDate: date, Components: components}
+	}
+
+	var issues []jira.Issue
+	// "API" delivers steadily over all 8 weeks.
+	for w := 0; w < 8; w++ {
+		issues = append(issues, delivered("A", at(w, 1), "API"))
+	}
+	// "Web" delivers only in the first 4 weeks — starving at the end.
+	for w := 0; w < 4; w++ {
+		issues = append(issues, delivered("W", at(w, 2), "Web"))
+	}
+	issues = append(issues,
+		delivered("X", at(6, 2), "API", "Web"), // multi-attributed
+		delivered("N", at(7, 2)),               // no component
+		jira.Issue{Key: "O", Outcome: "abandoned", OutcomeDate: at(7, 3), Components: []string{"Web"}},
+	)
+
+	res := GetStreamThroughput(issues, window, StreamByComponent)
+
+	if len(res.Pooled) != 8 {
+		t.Fatalf("Expected 8 buckets, got %d", len(res.Pooled))
+	}
+	if res.Unattributed != 1 {
+		t.Errorf("Expected 1 unattributed item, got %d", res.Unattributed)
+	}
+	if res.MultiAttributed != 1 {
+		t.Errorf("Expected 1 multi-attributed item, got %d", res.MultiAttributed)
+	}
+	if len(res.Streams) != 2 || res.Streams[0].Stream != "API" {
+		t.Fatalf("Expected API first, got %+v", res.Streams)
+	}
+
+	api, web := res.Streams[0], res.Streams[1]
+	if api.Total != 9 || web.Total != 5 {
+		t.Errorf("Expected totals API=9 Web=5, got API=%d Web=%d", api.Total, web.Total)
+	}
+	if api.Starving {
+		t.Errorf("API should not be starving")
+	}
+	if !web.Starving {
+		t.Errorf("Web should be starving (share %.2f, recent %.2f)", web.Share, web.RecentShare)
+	}
+	if web.ZeroBuckets != 3 {
+		t.Errorf("Expected 3 zero buckets for Web, got %d", web.ZeroBuckets)
+	}
+	if api.XmR == nil || web.XmR == nil {
+		t.Errorf("Expected per-stream XmR results")
+	}
+}
+
+func TestGetStreamThroughput_Epic(t *testing.T) {
+	now := time.Now()
+	window := NewAnalysisWindow(SnapToStart(now, "week"), now, "week", time.Time{})
+
+	issues := []jira.Issue{
+		{Key: "S1", Outcome: "delivered", OutcomeDate: &now, ParentKey: "EPIC-1"},
+		{Key: "S2", Outcome: "delivered", OutcomeDate: &now, ParentKey: "EPIC-1", Labels: []string{"a", "b"}},
+		{Key: "S3", Outcome: "delivered", OutcomeDate: &now},
+	}
+
+	res := GetStreamThroughput(issues, window, StreamByEpic)
+	if len(res.Streams) != 1 || res.Streams[0].Total != 2 {
+		t.Fatalf("Expected a single epic stream with 2 items, got %+v", res.Streams)
+	}
+	if res.Unattributed != 1 || res.MultiAttributed != 0 {
+		t.Errorf("Unexpected attribution counts: unattributed=%d multi=%d", res.Unattributed, res.MultiAttributed)
+	}
+}
+
+func TestStreamKeys_AssigneeAndTeam(t *testing.T) {
+	issue := jira.Issue{Assignee: "Ada", Team: "Payments"}
+	if got := StreamKeys(issue, StreamByAssignee); len(got) != 1 || got[0] != "Ada" {
+		t.Errorf("Expected the assignee, got %v", got)
+	}
+	if got := StreamKeys(issue, StreamByTeam); len(got) != 1 || got[0] != "Payments" {
+		t.Errorf("Expected the team, got %v", got)
+	}
+	if got := StreamKeys(jira.Issue{}, StreamByTeam); got != nil {
+		t.Errorf("Expected no team for an unset field, got %v", got)
+	}
+}
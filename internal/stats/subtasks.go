@@ -0,0 +1,100 @@
+package stats
+
+import (
+	"fmt"
+	"slices"
+
+	"mcs-mcp/internal/eventlog"
+)
+
+// SubtaskPolicy decides how sub-tasks enter an analysis.
+type SubtaskPolicy string
+
+const (
+	// SubtasksExclude leaves sub-tasks out entirely (default).
+	SubtasksExclude SubtaskPolicy = "exclude"
+	// SubtasksInclude counts sub-tasks as work items of their own.
+	SubtasksInclude SubtaskPolicy = "include"
+	// SubtasksRollup does not count sub-tasks, but starts a parent's clock
+	// when its first sub-task entered the Downstream tier, if that was
+	// before the parent itself did.
+	SubtasksRollup SubtaskPolicy = "rollup"
+)
+
+// ParseSubtaskPolicy validates a policy name; "" is SubtasksExclude.
+func ParseSubtaskPolicy(s string) (SubtaskPolicy, error) {
+	switch p := SubtaskPolicy(s); p {
+	case "":
+		return SubtasksExclude, nil
+	case SubtasksExclude, SubtasksInclude, SubtasksRollup:
+		return p, nil
+	}
+	return "", fmt.Errorf("invalid subtask policy %q: expected exclude, include, or rollup", s)
+}
+
+// rollupSubtasks drops the sub-tasks from events grouped by issue key and
+// moves their parents into Downstream when the first of their sub-tasks
+// entered it, by a synthetic (healed) transition on the parent. Parents that
+// reached Downstream or Finished on their own before are left unchanged.
+func rollupSubtasks(grouped map[string][]eventlog.IssueEvent, mappings map[string]StatusMetadata) {
+	tierOf := func(e eventlog.IssueEvent) string {
+		return mappings[PreferID(e.ToStatusID, e.ToStatus)].Tier
+	}
+	firstDownstream := func(events []eventlog.IssueEvent, tiers ...string) (eventlog.IssueEvent, bool) {
+		for _, e := range events {
+			if e.EventType == eventlog.Change && e.ToStatus != "" && slices.Contains(tiers, tierOf(e)) {
+				return e, true
+			}
+		}
+		return eventlog.IssueEvent{}, false
+	}
+
+	starts := make(map[string]eventlog.IssueEvent)
+	for key, events := range grouped {
+		created := slices.IndexFunc(events, func(e eventlog.IssueEvent) bool { return e.EventType == eventlog.Created })
+		if created < 0 || !events[created].Subtask {
+			continue
+		}
+		delete(grouped, key)
+		parent := events[created].ParentKey
+		if start, ok := firstDownstream(events, TierDownstream); ok && parent != "" {
+			if prev, seen := starts[parent]; !seen || start.Timestamp < prev.Timestamp {
+				starts[parent] = start
+			}
+		}
+	}
+
+	for parent, start := range starts {
+		events, ok := grouped[parent]
+		if !ok || events[0].Timestamp > start.Timestamp {
+			continue
+		}
+		if own, ok := firstDownstream(events, TierDownstream, TierFinished); ok && own.Timestamp <= start.Timestamp {
+			continue
+		}
+
+		at := len(events)
+		from := eventlog.IssueEvent{}
+		for i, e := range events {
+			if e.Timestamp > start.Timestamp {
+				at = i
+				break
+			}
+			if e.ToStatus != "" {
+				from = e
+			}
+		}
+		synthetic := eventlog.IssueEvent{
+			IssueKey:     parent,
+			IssueType:    events[0].IssueType,
+			EventType:    eventlog.Change,
+			Timestamp:    start.Timestamp,
+			FromStatus:   from.ToStatus,
+			FromStatusID: from.ToStatusID,
+			ToStatus:     start.ToStatus,
+			ToStatusID:   start.ToStatusID,
+			IsHealed:     true,
+		}
+		grouped[parent] = slices.Insert(slices.Clone(events), at, synthetic)
+	}
+}
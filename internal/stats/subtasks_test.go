@@ -0,0 +1,88 @@
+package stats
+
+import (
+	"testing"
+	"time"
+
+	"mcs-mcp/internal/eventlog"
+)
+
+func TestProjectScope_SubtaskPolicies(t *testing.T) {
+	day := func(n int) int64 { return time.Date(2024, 3, 1+n, 10, 0, 0, 0, time.UTC).UnixMicro() }
+	events := []eventlog.IssueEvent{
+		{IssueKey: "PROJ-1", IssueType: "Story", EventType: eventlog.Created, ToStatus: "Open", Timestamp: day(0)},
+		{IssueKey: "PROJ-2", IssueType: "Sub-task", EventType: eventlog.Created, ToStatus: "Open", ParentKey: "PROJ-1", Subtask: true, Timestamp: day(1)},
+		{IssueKey: "PROJ-2", IssueType: "Sub-task", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Dev", Timestamp: day(2)},
+		{IssueKey: "PROJ-2", IssueType: "Sub-task", EventType: eventlog.Change, FromStatus: "Dev", ToStatus: "Done", Resolution: "Done", Timestamp: day(4)},
+		{IssueKey: "PROJ-1", IssueType: "Story", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Dev", Timestamp: day(5)},
+		{IssueKey: "PROJ-1", IssueType: "Story", EventType: eventlog.Change, FromStatus: "Dev", ToStatus: "Done", Resolution: "Done", Timestamp: day(8)},
+	}
+	mappings := map[string]StatusMetadata{
+		"Open": {Tier: TierDemand},
+		"Dev":  {Tier: TierDownstream},
+		"Done": {Tier: TierFinished, Outcome: "delivered"},
+	}
+	window := AnalysisWindow{End: time.Date(2024, 4, 1, 0, 0, 0, 0, time.UTC)}
+	resolutions := map[string]string{"Done": "delivered"}
+
+	delivered := func(policy SubtaskPolicy) map[string]float64 {
+		finished, _, _, _ := projectScope(events, window, "Dev", mappings, resolutions, nil, policy)
+		cycleTimes := make(map[string]float64)
+		for _, issue := range finished {
+			var started time.Time
+			for _, tr := range issue.Transitions {
+				if tr.ToStatus == "Dev" {
+					started = tr.Date
+					break
+				}
+			}
+			cycleTimes[issue.Key] = issue.ResolutionDate.Sub(started).Hours() / 24
+		}
+		return cycleTimes
+	}
+
+	if got := delivered(SubtasksExclude); len(got) != 1 || got["PROJ-1"] != 3 {
+		t.Errorf("exclude: expected only PROJ-1, started on day 5, got %v", got)
+	}
+	if got := delivered(SubtasksInclude); len(got) != 2 || got["PROJ-2"] != 2 {
+		t.Errorf("include: expected PROJ-1 and PROJ-2, got %v", got)
+	}
+	if got := delivered(SubtasksRollup); len(got) != 1 || got["PROJ-1"] != 6 {
+		t.Errorf("rollup: expected PROJ-1 to start with its sub-task on day 2, got %v", got)
+	}
+}
+
+func TestRollupSubtasks_ParentAlreadyStarted(t *testing.T) {
+	grouped := map[string][]eventlog.IssueEvent{
+		"PROJ-1": {
+			{IssueKey: "PROJ-1", EventType: eventlog.Created, ToStatus: "Open", Timestamp: 1},
+			{IssueKey: "PROJ-1", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Dev", Timestamp: 2},
+		},
+		"PROJ-2": {
+			{IssueKey: "PROJ-2", EventType: eventlog.Created, ToStatus: "Open", ParentKey: "PROJ-1", Subtask: true, Timestamp: 3},
+			{IssueKey: "PROJ-2", EventType: eventlog.Change, FromStatus: "Open", ToStatus: "Dev", Timestamp: 4},
+		},
+	}
+	mappings := map[string]StatusMetadata{"Open": {Tier: TierDemand}, "Dev": {Tier: TierDownstream}}
+
+	rollupSubtasks(grouped, mappings)
+
+	if _, ok := grouped["PROJ-2"]; ok {
+		t.Error("Expected the sub-task to be dropped")
+	}
+	if len(grouped["PROJ-1"]) != 2 {
+		t.Errorf("Expected the parent that started first to be unchanged, got %+v", grouped["PROJ-1"])
+	}
+}
+
+func TestParseSubtaskPolicy(t *testing.T) {
+	if p, err := ParseSubtaskPolicy(""); err != nil || p != SubtasksExclude {
+		t.Errorf("Expected \"\" to mean exclude, got %q (err %v)", p, err)
+	}
+	if p, err := ParseSubtaskPolicy("rollup"); err != nil || p != SubtasksRollup {
+		t.Errorf("Expected rollup, got %q (err %v)", p, err)
+	}
+	if _, err := ParseSubtaskPolicy("parent"); err == nil {
+		t.Error("Expected an unknown policy to fail")
+	}
+}
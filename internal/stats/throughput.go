@@ -4,18 +4,15 @@ import (
 	"mcs-mcp/internal/jira"
 )
 
-// GetStratifiedThroughput aggregates resolved items into time buckets, both pooled and stratified by type.
+// GetStratifiedThroughput aggregates finished items into time buckets, both pooled and stratified by type.
+// Callers pass the items that count as done, such as AnalysisSession.GetDelivered under its outcome scope.
 func GetStratifiedThroughput(issues []jira.Issue, window AnalysisWindow) StratifiedThroughput {
 	buckets := window.Subdivide()
 	pooled := make([]int, len(buckets))
 	byType := make(map[string][]int)
 
 	for _, issue := range issues {
-		if !IsDelivered(issue) {
-			continue
-		}
-
-		if issue.OutcomeDate == nil {
+		if !HasExited(issue) || issue.OutcomeDate == nil {
 			continue
 		}
 
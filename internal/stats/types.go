@@ -75,8 +75,12 @@ type StratifiedThroughput struct {
 
 // FlowDebtResult represents the comparison between Arrival and Departure rates.
 type FlowDebtResult struct {
-	Buckets   []FlowDebtBucket `json:"buckets"`
-	TotalDebt int              `json:"totalDebt"` // Cumulative debt over the window
+	Buckets            []FlowDebtBucket     `json:"buckets"`
+	TotalDebt          int                  `json:"totalDebt"`                    // Cumulative debt over the window
+	Tiers              []NetFlowSeries      `json:"tiers,omitempty"`              // Net flow per tier (Demand, Upstream, Downstream)
+	Statuses           []NetFlowSeries      `json:"statuses,omitempty"`           // Net flow per non-Finished status
+	AccumulatingStatus string               `json:"accumulatingStatus,omitempty"` // Committed status with the largest positive net flow
+	Projection         *CycleTimeProjection `json:"projection,omitempty"`         // Little's Law cycle time inflation
 }
 
 // FlowDebtBucket represents a temporal snapshot of arrivals vs departures.
@@ -87,6 +91,35 @@ type FlowDebtBucket struct {
 	Debt       int    `json:"debt"` // Arrivals - Departures
 }
 
+// NetFlowSeries is the bucketed flow of items into and out of one tier or status.
+type NetFlowSeries struct {
+	Name     string          `json:"name"`
+	Tier     string          `json:"tier,omitempty"`
+	Buckets  []NetFlowBucket `json:"buckets"`
+	TotalNet int             `json:"totalNet"` // Inventory gained over the window
+}
+
+// NetFlowBucket counts the items entering and leaving a tier or status in one bucket.
+type NetFlowBucket struct {
+	Label   string `json:"label"`
+	Entered int    `json:"entered"`
+	Left    int    `json:"left"`
+	Net     int    `json:"net"` // Entered - Left
+}
+
+// CycleTimeProjection projects cycle time with Little's Law (CT = WIP / throughput)
+// if flow debt continues at its current rate.
+type CycleTimeProjection struct {
+	WIP                    int     `json:"wip"`              // Committed items in flight at the end of the window
+	ThroughputPerDay       float64 `json:"throughputPerDay"` // Departures per day over the window
+	CycleTimeDays          float64 `json:"cycleTimeDays"`    // WIP / throughput
+	DebtPerDay             float64 `json:"debtPerDay"`       // Net arrivals per day over the window
+	HorizonDays            int     `json:"horizonDays"`
+	ProjectedWIP           float64 `json:"projectedWip"`
+	ProjectedCycleTimeDays float64 `json:"projectedCycleTimeDays"`
+	InflationPct           float64 `json:"inflationPct"` // Relative change of the Little's Law cycle time
+}
+
 // CFDResult represents the time-series data for a Cumulative Flow Diagram.
 type CFDResult struct {
 	Buckets             []CFDBucket `json:"buckets"`
@@ -5,13 +5,24 @@ import (
 	"time"
 )
 
+// Bucket alignments.
+const (
+	// AlignCalendar snaps buckets to calendar periods: ISO weeks (Monday to
+	// Sunday) and calendar months. The default.
+	AlignCalendar = "calendar"
+	// AlignRolling counts whole buckets back from the last day of the window:
+	// weeks of 7 days, and months ending on the same day of the month.
+	AlignRolling = "rolling"
+)
+
 // AnalysisWindow defines the temporal context for analytical projections and diagnostics.
 type AnalysisWindow struct {
-	Start    time.Time `json:"start"`
-	End      time.Time `json:"end"`
-	Bucket   string    `json:"bucket"` // "day", "week", "month"
-	Cutoff   time.Time `json:"cutoff"` // Steady-State floor
-	EvalTime time.Time `json:"-"`      // Evaluation time for partial-bucket detection (derived from end)
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Bucket    string    `json:"bucket"`              // "day", "week", "month"
+	Alignment string    `json:"alignment,omitempty"` // AlignCalendar (default when empty) or AlignRolling
+	Cutoff    time.Time `json:"cutoff"`              // Steady-State floor
+	EvalTime  time.Time `json:"-"`                   // Evaluation time for partial-bucket detection (derived from end)
 }
 
 // NewAnalysisWindow creates a new window with normalized boundaries and cutoff clamping.
@@ -39,6 +50,93 @@ func NewAnalysisWindow(start, end time.Time, bucket string, cutoff time.Time) An
 	}
 }
 
+// NewAlignedWindow creates a window like NewAnalysisWindow, with buckets
+// aligned as alignment says. Rolling buckets end on the last day of the
+// window; the first bucket is the one containing start (or cutoff, when
+// later), so it may begin before start.
+func NewAlignedWindow(start, end time.Time, bucket, alignment string, cutoff time.Time) (AnalysisWindow, error) {
+	switch alignment {
+	case "", AlignCalendar:
+		w := NewAnalysisWindow(start, end, bucket, cutoff)
+		w.Alignment = AlignCalendar
+		return w, nil
+	case AlignRolling:
+	default:
+		return AnalysisWindow{}, fmt.Errorf("invalid bucket alignment %q: must be '%s' or '%s'", alignment, AlignCalendar, AlignRolling)
+	}
+
+	if bucket == "" {
+		bucket = "day"
+	}
+	w := AnalysisWindow{
+		Start:     start,
+		End:       SnapToEnd(end, "day"),
+		Bucket:    bucket,
+		Alignment: AlignRolling,
+		Cutoff:    cutoff,
+		EvalTime:  end,
+	}
+	if !cutoff.IsZero() && cutoff.After(start) {
+		start = cutoff
+	}
+	if !start.IsZero() {
+		w.Start = w.rollingStart(w.rollingBucketsBefore(start))
+	}
+	return w, nil
+}
+
+// rollingEnd returns the exclusive end of a rolling window: the start of the
+// day after its last day.
+func (w AnalysisWindow) rollingEnd() time.Time {
+	return time.Date(w.End.Year(), w.End.Month(), w.End.Day()+1, 0, 0, 0, 0, w.End.Location())
+}
+
+// rollingStart returns the start of the rolling bucket k buckets before the
+// end of the window; k = 0 is the exclusive end itself. A month ending on a
+// day the earlier month does not have starts on that month's last day.
+func (w AnalysisWindow) rollingStart(k int) time.Time {
+	e := w.rollingEnd()
+	switch w.Bucket {
+	case "month":
+		first := time.Date(e.Year(), e.Month()-time.Month(k), 1, 0, 0, 0, 0, e.Location())
+		last := first.AddDate(0, 1, -1).Day()
+		return time.Date(first.Year(), first.Month(), min(e.Day(), last), 0, 0, 0, 0, e.Location())
+	case "week":
+		return time.Date(e.Year(), e.Month(), e.Day()-7*k, 0, 0, 0, 0, e.Location())
+	default: // day
+		return time.Date(e.Year(), e.Month(), e.Day()-k, 0, 0, 0, 0, e.Location())
+	}
+}
+
+// rollingBucketsBefore returns k such that t falls into the rolling bucket
+// starting k buckets before the end of the window.
+func (w AnalysisWindow) rollingBucketsBefore(t time.Time) int {
+	days := CalendarDaysBetween(t, w.rollingEnd())
+	switch w.Bucket {
+	case "month":
+		k := max(1, days/31)
+		for w.rollingStart(k).After(t) {
+			k++
+		}
+		for k > 1 && !w.rollingStart(k-1).After(t) {
+			k--
+		}
+		return k
+	case "week":
+		return (days + 6) / 7
+	default: // day
+		return days
+	}
+}
+
+// BucketEnd returns the last nanosecond of the bucket starting at start.
+func (w AnalysisWindow) BucketEnd(start time.Time) time.Time {
+	if w.Alignment == AlignRolling {
+		return w.rollingStart(w.rollingBucketsBefore(start) - 1).Add(-time.Nanosecond)
+	}
+	return SnapToEnd(start, w.Bucket)
+}
+
 // SnapToStart normalizes a timestamp to the beginning of its bucket (0:00:00).
 func SnapToStart(t time.Time, bucket string) time.Time {
 	if t.IsZero() {
@@ -111,13 +209,22 @@ func (w AnalysisWindow) IsPartial(bucketStart time.Time) bool {
 	if now.IsZero() {
 		now = time.Now()
 	}
-	bucketEnd := SnapToEnd(bucketStart, w.Bucket)
+	bucketEnd := w.BucketEnd(bucketStart)
 	return (now.After(bucketStart) || now.Equal(bucketStart)) && (now.Before(bucketEnd) || now.Equal(bucketEnd))
 }
 
 // Subdivide returns a list of bucket start times within the window.
 func (w AnalysisWindow) Subdivide() []time.Time {
 	var buckets []time.Time
+	if w.Alignment == AlignRolling {
+		if w.Start.IsZero() {
+			return nil
+		}
+		for k := w.rollingBucketsBefore(w.Start); k > 0; k-- {
+			buckets = append(buckets, w.rollingStart(k))
+		}
+		return buckets
+	}
 	current := w.Start
 
 	for current.Before(w.End) {
@@ -134,8 +241,24 @@ func (w AnalysisWindow) Subdivide() []time.Time {
 	return buckets
 }
 
+// BucketDates returns the first and last day of each bucket, as used by
+// XmRResult.SetSignalDates.
+func (w AnalysisWindow) BucketDates() (from, to []string) {
+	for _, start := range w.Subdivide() {
+		from = append(from, start.Format(DateFormat))
+		to = append(to, w.BucketEnd(start).Format(DateFormat))
+	}
+	return from, to
+}
+
 // FindBucketIndex returns the index of the bucket containing t. Returns -1 if out of bounds.
 func (w AnalysisWindow) FindBucketIndex(t time.Time) int {
+	if w.Alignment == AlignRolling {
+		if t.Before(w.Start) || t.After(w.End) {
+			return -1
+		}
+		return w.rollingBucketsBefore(w.Start) - w.rollingBucketsBefore(t)
+	}
 	tNorm := SnapToStart(t, w.Bucket)
 	if tNorm.Before(w.Start) || tNorm.After(w.End) {
 		return -1
@@ -178,7 +301,7 @@ func (w AnalysisWindow) ActiveDayCount() int {
 		if !w.IsPartial(b) {
 			switch w.Bucket {
 			case "month":
-				activeCount += CalendarDaysBetween(b, SnapToEnd(b, "month"))
+				activeCount += CalendarDaysBetween(b, w.BucketEnd(b))
 			case "week":
 				activeCount += 7
 			default:
@@ -193,7 +316,12 @@ func (w AnalysisWindow) ActiveDayCount() int {
 }
 
 // GenerateLabel returns a human-readable label for a bucket (e.g., "Jan 2024" or "2024-W01").
+// Rolling weeks and months are labelled with their first and last day
+// ("2024-01-03..2024-01-09"), since they cross calendar periods.
 func (w AnalysisWindow) GenerateLabel(t time.Time) string {
+	if w.Alignment == AlignRolling && w.Bucket != "day" {
+		return t.Format(DateFormat) + ".." + w.BucketEnd(t).Format(DateFormat)
+	}
 	switch w.Bucket {
 	case "month":
 		return t.Format("Jan 2006")
@@ -54,3 +54,57 @@ func TestLastCompleteBucketEnd(t *testing.T) {
 		})
 	}
 }
+
+func TestNewAlignedWindow_Rolling(t *testing.T) {
+	loc := time.UTC
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, loc)
+	end := time.Date(2024, 4, 24, 12, 0, 0, 0, loc) // Wednesday
+
+	w, err := NewAlignedWindow(start, end, "week", AlignRolling, time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	buckets := w.Subdivide()
+	if len(buckets) != 8 || !buckets[0].Equal(time.Date(2024, 2, 29, 0, 0, 0, 0, loc)) {
+		t.Fatalf("Expected 8 weeks from Thu Feb 29, got %v", buckets)
+	}
+	last := buckets[len(buckets)-1]
+	if !last.Equal(time.Date(2024, 4, 18, 0, 0, 0, 0, loc)) || !w.BucketEnd(last).Equal(SnapToEnd(end, "day")) {
+		t.Errorf("Expected the last week to end on the window's last day, got %v..%v", last, w.BucketEnd(last))
+	}
+	if idx := w.FindBucketIndex(time.Date(2024, 4, 18, 9, 0, 0, 0, loc)); idx != 7 {
+		t.Errorf("Expected Apr 18 in the last bucket, got %d", idx)
+	}
+	if idx := w.FindBucketIndex(time.Date(2024, 4, 17, 23, 0, 0, 0, loc)); idx != 6 {
+		t.Errorf("Expected Apr 17 in the bucket before, got %d", idx)
+	}
+	if label := w.GenerateLabel(last); label != "2024-04-18..2024-04-24" {
+		t.Errorf("Expected a first..last day label, got %q", label)
+	}
+
+	// Months start on the 30th; February has no 30th and starts on its last day.
+	end = time.Date(2024, 4, 29, 12, 0, 0, 0, loc)
+	w, _ = NewAlignedWindow(start, end, "month", AlignRolling, time.Time{})
+	from, to := w.BucketDates()
+	wantFrom := []string{"2024-02-29", "2024-03-30"}
+	wantTo := []string{"2024-03-29", "2024-04-29"}
+	if len(from) != len(wantFrom) {
+		t.Fatalf("Expected rolling months %v..%v, got %v..%v", wantFrom, wantTo, from, to)
+	}
+	for i := range wantFrom {
+		if from[i] != wantFrom[i] || to[i] != wantTo[i] {
+			t.Fatalf("Expected rolling months %v..%v, got %v..%v", wantFrom, wantTo, from, to)
+		}
+	}
+	if idx := w.FindBucketIndex(time.Date(2024, 3, 30, 0, 0, 0, 0, loc)); idx != 1 {
+		t.Errorf("Expected Mar 30 to start the second month, got %d", idx)
+	}
+
+	calendar, _ := NewAlignedWindow(start, end, "week", "", time.Time{})
+	if first := calendar.Subdivide()[0]; first.Weekday() != time.Monday {
+		t.Errorf("Expected calendar weeks to start on Monday, got %v", first)
+	}
+	if _, err := NewAlignedWindow(start, end, "week", "fiscal", time.Time{}); err == nil {
+		t.Error("Expected an error for an unknown alignment")
+	}
+}
@@ -83,6 +83,9 @@ func AnalyzeHistoricalWIP(issues []jira.Issue, window AnalysisWindow, commitment
 				Index:       i,
 				Key:         key,
 				Type:        "outlier",
+				Rule:        RuleBeyondLimits,
+				StartIndex:  i,
+				EndIndex:    i,
 				Description: "WIP count above Upper Natural Process Limit (UNPL)",
 			})
 		} else if val < xmr.LNPL {
@@ -90,6 +93,9 @@ func AnalyzeHistoricalWIP(issues []jira.Issue, window AnalysisWindow, commitment
 				Index:       i,
 				Key:         key,
 				Type:        "outlier",
+				Rule:        RuleBeyondLimits,
+				StartIndex:  i,
+				EndIndex:    i,
 				Description: "WIP count below Lower Natural Process Limit (LNPL)",
 			})
 		}
@@ -406,6 +412,9 @@ func AnalyzeHistoricalWIPAge(issues []jira.Issue, window AnalysisWindow, commitm
 				Index:       i,
 				Key:         key,
 				Type:        "outlier",
+				Rule:        RuleBeyondLimits,
+				StartIndex:  i,
+				EndIndex:    i,
 				Description: "Total WIP Age above Upper Natural Process Limit (UNPL)",
 			})
 		} else if val < xmr.LNPL {
@@ -413,6 +422,9 @@ func AnalyzeHistoricalWIPAge(issues []jira.Issue, window AnalysisWindow, commitm
 				Index:       i,
 				Key:         key,
 				Type:        "outlier",
+				Rule:        RuleBeyondLimits,
+				StartIndex:  i,
+				EndIndex:    i,
 				Description: "Total WIP Age below Lower Natural Process Limit (LNPL)",
 			})
 		}
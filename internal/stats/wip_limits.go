@@ -0,0 +1,197 @@
+package stats
+
+import (
+	"cmp"
+	"mcs-mcp/internal/jira"
+	"slices"
+	"time"
+)
+
+// WIP limit scopes.
+const (
+	WIPLimitStatus = "status"
+	WIPLimitTier   = "tier"
+)
+
+// WIPLimit is a recorded WIP limit: at most Limit items reside in the status
+// (by ID) or tier named by Target at any time.
+type WIPLimit struct {
+	Scope  string `json:"scope"`          // WIPLimitStatus or WIPLimitTier
+	Target string `json:"target"`         // Status ID or tier name
+	Name   string `json:"name,omitempty"` // Status name for display
+	Limit  int    `json:"limit"`
+}
+
+// Key identifies the limit among the recorded limits of a board.
+func (l WIPLimit) Key() string {
+	return l.Scope + ":" + l.Target
+}
+
+// Label is the human-readable name of the limited status or tier.
+func (l WIPLimit) Label() string {
+	if l.Name != "" {
+		return l.Name
+	}
+	return l.Target
+}
+
+// covers reports whether an item in the given status counts against the limit.
+func (l WIPLimit) covers(status string, mappings map[string]StatusMetadata) bool {
+	if l.Scope == WIPLimitTier {
+		m, ok := mappings[status]
+		return ok && m.Tier == l.Target
+	}
+	return status == l.Target
+}
+
+// WIPLimitViolation is a run of consecutive days over the limit.
+type WIPLimitViolation struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Days    int       `json:"days"`
+	PeakWIP int       `json:"peak_wip"`
+	Ongoing bool      `json:"ongoing,omitempty"` // Still over the limit on the last day of the window
+}
+
+// WIPLimitCycleTimeImpact compares the cycle times of delivered items that sat
+// in the limited status or tier on a violation day (exposed) with those that
+// passed through it only while the limit held (unexposed).
+type WIPLimitCycleTimeImpact struct {
+	ExposedCount   int     `json:"exposed_count"`
+	UnexposedCount int     `json:"unexposed_count"`
+	ExposedP50     float64 `json:"exposed_p50"`
+	UnexposedP50   float64 `json:"unexposed_p50"`
+	ExposedP85     float64 `json:"exposed_p85"`
+	UnexposedP85   float64 `json:"unexposed_p85"`
+	Inflation      float64 `json:"inflation"` // ExposedP85 / UnexposedP85; 0 when either group is empty
+}
+
+// WIPLimitReport is the violation history of one WIP limit over an analysis window.
+type WIPLimitReport struct {
+	Limit                WIPLimit                `json:"limit"`
+	DaysObserved         int                     `json:"days_observed"`
+	ViolationDays        int                     `json:"violation_days"`
+	ViolationRate        float64                 `json:"violation_rate"` // Share of observed days over the limit
+	LongestViolationDays int                     `json:"longest_violation_days"`
+	AverageWIP           float64                 `json:"average_wip"`
+	PeakWIP              int                     `json:"peak_wip"`
+	CurrentWIP           int                     `json:"current_wip"` // WIP on the last day of the window
+	Violations           []WIPLimitViolation     `json:"violations"`  // Longest first
+	CycleTimeImpact      WIPLimitCycleTimeImpact `json:"cycle_time_impact"`
+	RunChart             []WIPRunChartPoint      `json:"run_chart"`
+}
+
+// EvaluateWIPLimits reconstructs the daily population of each limited status
+// or tier and reports when and for how long the limit was exceeded.
+//
+// Like the CFD, an item counts towards the status it is in at the end of each
+// day. delivered and cycleTimes must be aligned by index; they feed the cycle
+// time comparison of items exposed to a violation with those that were not.
+func EvaluateWIPLimits(limits []WIPLimit, issues []jira.Issue, mappings map[string]StatusMetadata, window AnalysisWindow, delivered []jira.Issue, cycleTimes []float64) []WIPLimitReport {
+	days, bounds := buildDayTimeline(window)
+
+	reports := make([]WIPLimitReport, 0, len(limits))
+	for _, limit := range limits {
+		r := WIPLimitReport{
+			Limit:        limit,
+			DaysObserved: len(days),
+			Violations:   make([]WIPLimitViolation, 0),
+			RunChart:     make([]WIPRunChartPoint, len(days)),
+		}
+		violated := make([]bool, len(days))
+		total := 0
+		var current *WIPLimitViolation
+		for i, d := range days {
+			count := 0
+			for _, issue := range issues {
+				if status := getStatusAt(issue, bounds[i].end); status != "" && limit.covers(status, mappings) {
+					count++
+				}
+			}
+			r.RunChart[i] = WIPRunChartPoint{Date: d, Count: count}
+			total += count
+			r.PeakWIP = max(r.PeakWIP, count)
+
+			if count <= limit.Limit {
+				current = nil
+				continue
+			}
+			violated[i] = true
+			r.ViolationDays++
+			if current == nil {
+				r.Violations = append(r.Violations, WIPLimitViolation{Start: d})
+				current = &r.Violations[len(r.Violations)-1]
+			}
+			current.End = d
+			current.Days++
+			current.PeakWIP = max(current.PeakWIP, count)
+		}
+		if current != nil {
+			current.Ongoing = true
+		}
+		if len(days) > 0 {
+			r.AverageWIP = Round2(float64(total) / float64(len(days)))
+			r.ViolationRate = Round2(float64(r.ViolationDays) / float64(len(days)))
+			r.CurrentWIP = r.RunChart[len(days)-1].Count
+		}
+		for _, v := range r.Violations {
+			r.LongestViolationDays = max(r.LongestViolationDays, v.Days)
+		}
+		slices.SortStableFunc(r.Violations, func(a, b WIPLimitViolation) int {
+			return cmp.Compare(b.Days, a.Days)
+		})
+
+		r.CycleTimeImpact = wipLimitImpact(limit, mappings, bounds, violated, delivered, cycleTimes)
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// wipLimitImpact splits the delivered items that passed through the limited
+// status or tier by whether they sat in it on a violation day.
+func wipLimitImpact(limit WIPLimit, mappings map[string]StatusMetadata, bounds []dayBounds, violated []bool, delivered []jira.Issue, cycleTimes []float64) WIPLimitCycleTimeImpact {
+	var exposed, unexposed []float64
+	for i, issue := range delivered {
+		if !passesThrough(issue, limit, mappings) {
+			continue
+		}
+		hit := false
+		for d := range bounds {
+			if violated[d] && limit.covers(getStatusAt(issue, bounds[d].end), mappings) {
+				hit = true
+				break
+			}
+		}
+		if hit {
+			exposed = append(exposed, cycleTimes[i])
+		} else {
+			unexposed = append(unexposed, cycleTimes[i])
+		}
+	}
+
+	impact := WIPLimitCycleTimeImpact{
+		ExposedCount:   len(exposed),
+		UnexposedCount: len(unexposed),
+		ExposedP50:     Round2(PercentileOf(exposed, 0.50)),
+		UnexposedP50:   Round2(PercentileOf(unexposed, 0.50)),
+		ExposedP85:     Round2(PercentileOf(exposed, 0.85)),
+		UnexposedP85:   Round2(PercentileOf(unexposed, 0.85)),
+	}
+	if impact.ExposedCount > 0 && impact.UnexposedP85 > 0 {
+		impact.Inflation = Round2(impact.ExposedP85 / impact.UnexposedP85)
+	}
+	return impact
+}
+
+// passesThrough reports whether an issue was ever in the limited status or tier.
+func passesThrough(issue jira.Issue, limit WIPLimit, mappings map[string]StatusMetadata) bool {
+	if limit.covers(PreferID(issue.BirthStatusID, issue.BirthStatus), mappings) {
+		return true
+	}
+	for _, tr := range issue.Transitions {
+		if limit.covers(PreferID(tr.ToStatusID, tr.ToStatus), mappings) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package stats
+
+import (
+	"mcs-mcp/internal/jira"
+	"testing"
+	"time"
+)
+
+func TestEvaluateWIPLimits(t *testing.T) {
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+	// item enters "3" (Review) on day `in` and leaves for "4" (Done) on day `out`.
+	item := func(key string, in, out int) jira.Issue {
+		return jira.Issue{
+			Key:           key,
+			Created:       day(1).Add(-time.Hour),
+			BirthStatusID: "1",
+			Transitions: []jira.StatusTransition{
+				{ToStatusID: "3", Date: day(in)},
+				{ToStatusID: "4", Date: day(out)},
+			},
+		}
+	}
+	issues := []jira.Issue{
+		item("A-1", 1, 3),  // Review on days 1–2
+		item("A-2", 2, 6),  // Review on days 2–5
+		item("A-3", 2, 5),  // Review on days 2–4
+		item("A-4", 7, 8),  // Review on day 7, alone
+		item("A-5", 9, 10), // Review on day 9, alone
+	}
+	mappings := map[string]StatusMetadata{
+		"1": {Name: "To Do", Tier: TierDemand},
+		"3": {Name: "Review", Tier: TierDownstream},
+		"4": {Name: "Done", Tier: TierFinished},
+	}
+	window := NewAnalysisWindow(day(1), day(10), "day", time.Time{})
+	limits := []WIPLimit{
+		{Scope: WIPLimitStatus, Target: "3", Name: "Review", Limit: 2},
+		{Scope: WIPLimitTier, Target: TierDownstream, Limit: 1},
+	}
+
+	reports := EvaluateWIPLimits(limits, issues, mappings, window, issues, []float64{2, 10, 8, 1, 1})
+	if len(reports) != 2 {
+		t.Fatalf("Expected one report per limit, got %d", len(reports))
+	}
+
+	review := reports[0]
+	if review.DaysObserved != 10 || review.PeakWIP != 3 {
+		t.Errorf("Expected 10 days with a peak of 3, got %d days and peak %d", review.DaysObserved, review.PeakWIP)
+	}
+	if review.ViolationDays != 1 || review.LongestViolationDays != 1 || len(review.Violations) != 1 {
+		t.Fatalf("Expected a single one-day violation on day 2, got %+v", review.Violations)
+	}
+	if v := review.Violations[0]; !v.Start.Equal(SnapToStart(day(2), "day")) || v.PeakWIP != 3 || v.Ongoing {
+		t.Errorf("Unexpected violation %+v", v)
+	}
+
+	// A-1, A-2 and A-3 sat in Review on day 2; A-4 and A-5 never saw a violation.
+	impact := review.CycleTimeImpact
+	if impact.ExposedCount != 3 || impact.UnexposedCount != 2 {
+		t.Fatalf("Expected 3 exposed and 2 unexposed items, got %+v", impact)
+	}
+	if impact.Inflation != 10 {
+		t.Errorf("Expected an inflation of 10 (P85 10 vs. 1), got %v", impact.Inflation)
+	}
+
+	tier := reports[1]
+	if tier.ViolationDays != 3 || tier.LongestViolationDays != 3 {
+		t.Errorf("Expected the Downstream tier over its limit on days 2–4, got %d days (longest %d)", tier.ViolationDays, tier.LongestViolationDays)
+	}
+	if tier.CurrentWIP != 0 || tier.Violations[0].Ongoing {
+		t.Errorf("Expected the tier to be within its limit at the end of the window, got %d", tier.CurrentWIP)
+	}
+}
@@ -0,0 +1,359 @@
+package stats
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"mcs-mcp/internal/jira"
+)
+
+// Workflow drift thresholds.
+const (
+	// MinTypeWorkflowSample is the number of items of a type below which its
+	// path profile is reported but not flagged as divergent.
+	MinTypeWorkflowSample = 5
+
+	// workflowSkipShare is the coverage below which a type is considered to skip
+	// a tier or status, and workflowUsedShare the board-wide coverage above which
+	// that tier or status counts as part of the board's normal flow.
+	workflowSkipShare = 0.2
+	workflowUsedShare = 0.5
+
+	// workflowTopPaths is the number of most common paths reported per type.
+	workflowTopPaths = 3
+)
+
+// Divergence kinds reported in WorkflowDivergence.Kind.
+const (
+	DivergenceSkipsTier          = "skips_tier"
+	DivergenceBypassesCommitment = "bypasses_commitment"
+	DivergenceUnmappedStatus     = "unmapped_status"
+)
+
+// WorkflowPath is a distinct sequence of statuses (in first-visit order) and
+// how many items of a type followed it.
+type WorkflowPath struct {
+	Path  []string `json:"path"`
+	Count int      `json:"count"`
+	Share float64  `json:"share"`
+}
+
+// WorkflowDivergence flags one way a type's observed flow departs from the
+// board's mapping or from the other types on the board.
+type WorkflowDivergence struct {
+	Kind       string  `json:"kind"`
+	Subject    string  `json:"subject"` // Tier or status name the divergence concerns
+	TypeShare  float64 `json:"type_share"`
+	BoardShare float64 `json:"board_share"`
+	Detail     string  `json:"detail"`
+}
+
+// TypeWorkflow summarizes how one issue type traverses the board.
+type TypeWorkflow struct {
+	IssueType                string               `json:"issue_type"`
+	Count                    int                  `json:"count"`
+	TierCoverage             map[string]float64   `json:"tier_coverage"`   // Share of items that visited each tier
+	StatusCoverage           map[string]float64   `json:"status_coverage"` // Share of items that visited each status
+	CommonPaths              []WorkflowPath       `json:"common_paths"`
+	Divergences              []WorkflowDivergence `json:"divergences,omitempty"`
+	SuggestedCommitmentPoint string               `json:"suggested_commitment_point,omitempty"`
+}
+
+// WorkflowDriftResult compares observed status paths across issue types.
+type WorkflowDriftResult struct {
+	TotalItems        int                `json:"total_items"`
+	BoardTierCoverage map[string]float64 `json:"board_tier_coverage"`
+	Types             []TypeWorkflow     `json:"types"`
+	Recommendations   []string           `json:"recommendations"`
+}
+
+// Round rounds all shares to 2 decimal places for output compactness.
+func (r *WorkflowDriftResult) Round() {
+	for k, v := range r.BoardTierCoverage {
+		r.BoardTierCoverage[k] = Round2(v)
+	}
+	for i := range r.Types {
+		t := &r.Types[i]
+		for k, v := range t.TierCoverage {
+			t.TierCoverage[k] = Round2(v)
+		}
+		for k, v := range t.StatusCoverage {
+			t.StatusCoverage[k] = Round2(v)
+		}
+		for j := range t.CommonPaths {
+			t.CommonPaths[j].Share = Round2(t.CommonPaths[j].Share)
+		}
+		for j := range t.Divergences {
+			t.Divergences[j].TypeShare = Round2(t.Divergences[j].TypeShare)
+			t.Divergences[j].BoardShare = Round2(t.Divergences[j].BoardShare)
+		}
+	}
+}
+
+// visitedStatus is a status an item entered, identified by ID with its display name.
+type visitedStatus struct {
+	ID   string
+	Name string
+}
+
+// visitedStatuses returns the distinct statuses an issue entered, in first-visit order.
+func visitedStatuses(issue jira.Issue) []visitedStatus {
+	seen := make(map[string]bool)
+	var out []visitedStatus
+	add := func(id, name string) {
+		key := id
+		if key == "" {
+			key = name
+		}
+		if key == "" || seen[key] {
+			return
+		}
+		seen[key] = true
+		out = append(out, visitedStatus{ID: key, Name: name})
+	}
+	add(issue.BirthStatusID, issue.BirthStatus)
+	for _, t := range issue.Transitions {
+		add(t.ToStatusID, t.ToStatus)
+	}
+	if len(out) == 0 {
+		add(issue.StatusID, issue.Status)
+	}
+	return out
+}
+
+// AnalyzeWorkflowDrift profiles the status paths of each issue type and flags
+// types whose flow diverges from the mapping or from the rest of the board:
+// skipping a tier the board normally uses, bypassing the commitment point, or
+// visiting statuses the mapping does not cover. commitmentPoint is a status ID.
+func AnalyzeWorkflowDrift(issues []jira.Issue, mappings map[string]StatusMetadata, commitmentPoint string) WorkflowDriftResult {
+	res := WorkflowDriftResult{
+		TotalItems:        len(issues),
+		BoardTierCoverage: make(map[string]float64),
+		Recommendations:   []string{},
+	}
+	if len(issues) == 0 {
+		return res
+	}
+
+	tierOf := func(id string) string {
+		if m, ok := mappings[id]; ok && m.Tier != "" {
+			return m.Tier
+		}
+		return ""
+	}
+
+	type typeAcc struct {
+		count        int
+		tiers        map[string]int
+		statuses     map[string]int
+		names        map[string]string
+		paths        map[string]int
+		pathSeq      map[string][]string
+		firstDown    map[string]int
+		reachedDown  int
+		hitCommitted int
+	}
+	accs := make(map[string]*typeAcc)
+	boardTiers := make(map[string]int)
+	var boardReachedDown, boardHitCommitted int
+
+	for _, issue := range issues {
+		t := issue.IssueType
+		if t == "" {
+			t = "Unknown"
+		}
+		acc, ok := accs[t]
+		if !ok {
+			acc = &typeAcc{
+				tiers:     make(map[string]int),
+				statuses:  make(map[string]int),
+				names:     make(map[string]string),
+				paths:     make(map[string]int),
+				pathSeq:   make(map[string][]string),
+				firstDown: make(map[string]int),
+			}
+			accs[t] = acc
+		}
+		acc.count++
+
+		visited := visitedStatuses(issue)
+		tiers := make(map[string]bool)
+		path := make([]string, 0, len(visited))
+		firstDown := ""
+		hitCommitment := false
+		for _, v := range visited {
+			acc.statuses[v.ID]++
+			acc.names[v.ID] = v.Name
+			path = append(path, v.Name)
+			if v.ID == commitmentPoint {
+				hitCommitment = true
+			}
+			tier := tierOf(v.ID)
+			if tier == "" {
+				continue
+			}
+			tiers[tier] = true
+			if tier == TierDownstream && firstDown == "" {
+				firstDown = v.ID
+			}
+		}
+		for tier := range tiers {
+			acc.tiers[tier]++
+			boardTiers[tier]++
+		}
+		if firstDown != "" {
+			acc.firstDown[firstDown]++
+			acc.reachedDown++
+			boardReachedDown++
+			if hitCommitment {
+				acc.hitCommitted++
+				boardHitCommitted++
+			}
+		}
+
+		key := strings.Join(path, "\x00")
+		acc.paths[key]++
+		acc.pathSeq[key] = path
+	}
+
+	total := float64(len(issues))
+	for tier, n := range boardTiers {
+		res.BoardTierCoverage[tier] = float64(n) / total
+	}
+	boardCommitShare := 0.0
+	if boardReachedDown > 0 {
+		boardCommitShare = float64(boardHitCommitted) / float64(boardReachedDown)
+	}
+	commitName := commitmentPoint
+	if m, ok := mappings[commitmentPoint]; ok && m.Name != "" {
+		commitName = m.Name
+	}
+
+	for issueType, acc := range accs {
+		n := float64(acc.count)
+		tw := TypeWorkflow{
+			IssueType:      issueType,
+			Count:          acc.count,
+			TierCoverage:   make(map[string]float64),
+			StatusCoverage: make(map[string]float64),
+		}
+		for tier, c := range acc.tiers {
+			tw.TierCoverage[tier] = float64(c) / n
+		}
+		for id, c := range acc.statuses {
+			tw.StatusCoverage[acc.names[id]] = float64(c) / n
+		}
+
+		for key, c := range acc.paths {
+			tw.CommonPaths = append(tw.CommonPaths, WorkflowPath{Path: acc.pathSeq[key], Count: c, Share: float64(c) / n})
+		}
+		slices.SortFunc(tw.CommonPaths, func(a, b WorkflowPath) int {
+			if a.Count != b.Count {
+				return b.Count - a.Count
+			}
+			return cmp.Compare(strings.Join(a.Path, ","), strings.Join(b.Path, ","))
+		})
+		if len(tw.CommonPaths) > workflowTopPaths {
+			tw.CommonPaths = tw.CommonPaths[:workflowTopPaths]
+		}
+
+		if acc.count >= MinTypeWorkflowSample {
+			tw.Divergences = typeDivergences(acc.count, acc.tiers, acc.statuses, acc.names, res.BoardTierCoverage, mappings)
+
+			if commitmentPoint != "" && acc.reachedDown > 0 {
+				share := float64(acc.hitCommitted) / float64(acc.reachedDown)
+				if share < workflowSkipShare && boardCommitShare >= workflowUsedShare {
+					tw.Divergences = append(tw.Divergences, WorkflowDivergence{
+						Kind:       DivergenceBypassesCommitment,
+						Subject:    commitName,
+						TypeShare:  share,
+						BoardShare: boardCommitShare,
+						Detail:     fmt.Sprintf("Only %.0f%% of %s items that reached Downstream passed through the commitment point '%s'.", share*100, issueType, commitName),
+					})
+					if id := mostFrequent(acc.firstDown); id != "" && id != commitmentPoint {
+						tw.SuggestedCommitmentPoint = acc.names[id]
+					}
+				}
+			}
+		}
+		res.Types = append(res.Types, tw)
+	}
+
+	slices.SortFunc(res.Types, func(a, b TypeWorkflow) int {
+		if a.Count != b.Count {
+			return b.Count - a.Count
+		}
+		return cmp.Compare(a.IssueType, b.IssueType)
+	})
+
+	for _, tw := range res.Types {
+		if tw.SuggestedCommitmentPoint != "" {
+			res.Recommendations = append(res.Recommendations, fmt.Sprintf("%s: set commitment_points_by_type to '%s' in workflow_set_mapping — it is where %s items actually enter Downstream.", tw.IssueType, tw.SuggestedCommitmentPoint, tw.IssueType))
+		}
+		for _, d := range tw.Divergences {
+			switch d.Kind {
+			case DivergenceSkipsTier:
+				res.Recommendations = append(res.Recommendations, fmt.Sprintf("%s: skips the %s tier (%.0f%% vs %.0f%% board-wide). Tier-based metrics for this type start or end at a different point; analyze it separately via issue_types.", tw.IssueType, d.Subject, d.TypeShare*100, d.BoardShare*100))
+			case DivergenceUnmappedStatus:
+				res.Recommendations = append(res.Recommendations, fmt.Sprintf("%s: status '%s' is visited by %.0f%% of items but has no tier in the mapping. Add it via 'workflow_set_mapping'.", tw.IssueType, d.Subject, d.TypeShare*100))
+			}
+		}
+	}
+	return res
+}
+
+// typeDivergences flags skipped tiers and unmapped statuses for one type.
+func typeDivergences(count int, tiers, statuses map[string]int, names map[string]string, boardTiers map[string]float64, mappings map[string]StatusMetadata) []WorkflowDivergence {
+	var out []WorkflowDivergence
+	n := float64(count)
+	for _, tier := range []string{TierDemand, TierUpstream, TierDownstream} {
+		share := float64(tiers[tier]) / n
+		board := boardTiers[tier]
+		if share < workflowSkipShare && board >= workflowUsedShare {
+			out = append(out, WorkflowDivergence{
+				Kind:       DivergenceSkipsTier,
+				Subject:    tier,
+				TypeShare:  share,
+				BoardShare: board,
+				Detail:     fmt.Sprintf("%.0f%% of items visit %s, against %.0f%% board-wide.", share*100, tier, board*100),
+			})
+		}
+	}
+
+	if len(mappings) == 0 {
+		return out
+	}
+	ids := make([]string, 0, len(statuses))
+	for id := range statuses {
+		if _, ok := mappings[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		share := float64(statuses[id]) / n
+		if share < workflowSkipShare {
+			continue
+		}
+		out = append(out, WorkflowDivergence{
+			Kind:      DivergenceUnmappedStatus,
+			Subject:   names[id],
+			TypeShare: share,
+			Detail:    fmt.Sprintf("Status '%s' is not in the confirmed mapping.", names[id]),
+		})
+	}
+	return out
+}
+
+// mostFrequent returns the key with the highest count (ties broken by key).
+func mostFrequent(counts map[string]int) string {
+	best, bestN := "", 0
+	for k, n := range counts {
+		if n > bestN || (n == bestN && k < best) {
+			best, bestN = k, n
+		}
+	}
+	return best
+}
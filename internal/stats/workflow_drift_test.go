@@ -0,0 +1,80 @@
+package stats
+
+import (
+	"fmt"
+	"mcs-mcp/internal/jira"
+	"testing"
+)
+
+func TestAnalyzeWorkflowDrift(t *testing.T) {
+	mappings := map[string]StatusMetadata{
+		"1": {Name: "Backlog", Tier: "Demand"},
+		"2": {Name: "Refining", Tier: "Upstream"},
+		"3": {Name: "In Dev", Tier: "Downstream"},
+		"4": {Name: "Done", Tier: "Finished", Outcome: "delivered"},
+		"5": {Name: "Triaged", Tier: "Downstream"},
+	}
+	to := func(id, name string) jira.StatusTransition {
+		return jira.StatusTransition{ToStatusID: id, ToStatus: name}
+	}
+
+	var issues []jira.Issue
+	// Stories follow the full flow through the commitment point "In Dev".
+	for i := 0; i < 10; i++ {
+		issues = append(issues, jira.Issue{
+			Key: fmt.Sprintf("S-%d", i), IssueType: "Story", BirthStatusID: "1", BirthStatus: "Backlog",
+			Transitions: []jira.StatusTransition{to("2", "Refining"), to("3", "In Dev"), to("4", "Done")},
+		})
+	}
+	// Bugs skip Upstream, enter Downstream at "Triaged", and pass an unmapped "Verify".
+	for i := 0; i < 6; i++ {
+		issues = append(issues, jira.Issue{
+			Key: fmt.Sprintf("B-%d", i), IssueType: "Bug", BirthStatusID: "1", BirthStatus: "Backlog",
+			Transitions: []jira.StatusTransition{to("5", "Triaged"), to("9", "Verify"), to("4", "Done")},
+		})
+	}
+
+	res := AnalyzeWorkflowDrift(issues, mappings, "3")
+
+	if len(res.Types) != 2 || res.Types[0].IssueType != "Story" {
+		t.Fatalf("Expected Story then Bug, got %+v", res.Types)
+	}
+	story, bug := res.Types[0], res.Types[1]
+	if len(story.Divergences) != 0 {
+		t.Errorf("Expected no divergences for Story, got %+v", story.Divergences)
+	}
+	if len(story.CommonPaths) != 1 || story.CommonPaths[0].Count != 10 {
+		t.Errorf("Expected a single Story path, got %+v", story.CommonPaths)
+	}
+
+	kinds := make(map[string]string)
+	for _, d := range bug.Divergences {
+		kinds[d.Kind] = d.Subject
+	}
+	if kinds[DivergenceSkipsTier] != "Upstream" {
+		t.Errorf("Expected Bug to skip Upstream, got %+v", bug.Divergences)
+	}
+	if kinds[DivergenceBypassesCommitment] != "In Dev" {
+		t.Errorf("Expected Bug to bypass the commitment point, got %+v", bug.Divergences)
+	}
+	if kinds[DivergenceUnmappedStatus] != "Verify" {
+		t.Errorf("Expected unmapped status 'Verify', got %+v", bug.Divergences)
+	}
+	if bug.SuggestedCommitmentPoint != "Triaged" {
+		t.Errorf("Expected suggested commitment point 'Triaged', got %q", bug.SuggestedCommitmentPoint)
+	}
+	if len(res.Recommendations) == 0 {
+		t.Errorf("Expected recommendations")
+	}
+}
+
+func TestAnalyzeWorkflowDrift_SmallSample(t *testing.T) {
+	mappings := map[string]StatusMetadata{"1": {Name: "Backlog", Tier: "Demand"}}
+	issues := []jira.Issue{{Key: "X-1", IssueType: "Spike", BirthStatusID: "1", BirthStatus: "Backlog",
+		Transitions: []jira.StatusTransition{{ToStatusID: "9", ToStatus: "Unknown"}}}}
+
+	res := AnalyzeWorkflowDrift(issues, mappings, "")
+	if len(res.Types) != 1 || len(res.Types[0].Divergences) != 0 {
+		t.Errorf("Expected profile without divergences below the sample floor, got %+v", res.Types)
+	}
+}
@@ -46,17 +46,8 @@ func CalculateProcessYield(issues []jira.Issue, mappings map[string]StatusMetada
 		} else if issue.Outcome == "abandoned" {
 			yield.AbandonedCount++
 
-			// 3. Attribute to Tier (Heuristic-based Attribution)
-			// We walk backwards and skip Finished-tier statuses to find where the item was abandoned.
-			tier := "Demand"
-			for i := len(issue.Transitions) - 1; i >= 0; i-- {
-				tr := issue.Transitions[i]
-				t := DetermineTier(jira.Issue{Status: tr.ToStatus, StatusID: tr.ToStatusID}, "", mappings)
-				if t != "Finished" && t != "Unknown" {
-					tier = t
-					break
-				}
-			}
+			// 3. Attribute to the tier the item was abandoned from
+			_, _, tier := abandonedFrom(issue, mappings)
 
 			// Total age in the process as the 'cost' of the loss
 			age := 0.0
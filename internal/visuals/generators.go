@@ -0,0 +1,523 @@
+package visuals
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// generator turns the data field of a tool response into Mermaid diagrams.
+// It returns no diagrams when the result has nothing to draw.
+type generator func(data json.RawMessage) ([]string, error)
+
+// generators maps MCP tool names to their diagram generators.
+var generators = map[string]generator{
+	"analyze_cycle_time":         cycleTime,
+	"analyze_status_persistence": statusPersistence,
+	"analyze_work_item_age":      workItemAge,
+	"analyze_throughput":         throughput,
+	"analyze_release_burnup":     releaseBurnup,
+	"analyze_process_stability":  processStability,
+	"analyze_process_evolution":  processEvolution,
+	"analyze_wip_stability":      wipStability,
+	"analyze_wip_age_stability":  wipAgeStability,
+	"analyze_flow_debt":          flowDebt,
+	"analyze_yield":              yield,
+	"analyze_residence_time":     residenceTime,
+	"analyze_item_journey":       itemJourney,
+	"generate_cfd_data":          cfd,
+	"forecast_monte_carlo":       forecast,
+	"forecast_epic":              forecast,
+	"forecast_item":              forecast,
+	"forecast_burnup":            burnUp,
+	"forecast_backtest":          backtest,
+}
+
+// HasGenerator reports whether the given tool has a Mermaid generator.
+func HasGenerator(toolName string) bool {
+	_, ok := generators[toolName]
+	return ok
+}
+
+// Tools returns the names of the tools with a Mermaid generator, sorted.
+func Tools() []string {
+	return slices.Sorted(maps.Keys(generators))
+}
+
+// Mermaid returns the Mermaid diagrams of a tool result, given the JSON
+// encoding of its response envelope. Tools without a generator get none.
+func Mermaid(toolName string, envelopeJSON []byte) ([]string, error) {
+	gen, ok := generators[toolName]
+	if !ok {
+		return nil, nil
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := json.Unmarshal(envelopeJSON, &envelope); err != nil {
+		return nil, fmt.Errorf("%s: %w", toolName, err)
+	}
+	if len(envelope.Data) == 0 {
+		return nil, nil
+	}
+	diagrams, err := gen(envelope.Data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", toolName, err)
+	}
+	return diagrams, nil
+}
+
+// percentiles is the wire format of cycle time and forecast percentiles.
+type percentiles struct {
+	Aggressive    float64 `json:"aggressive"`
+	Unlikely      float64 `json:"unlikely"`
+	CoinToss      float64 `json:"coin_toss"`
+	Probable      float64 `json:"probable"`
+	Likely        float64 `json:"likely"`
+	Conservative  float64 `json:"conservative"`
+	Safe          float64 `json:"safe"`
+	AlmostCertain float64 `json:"almost_certain"`
+}
+
+var percentileLabels = []string{"P10", "P30", "P50", "P70", "P85", "P90", "P95", "P98"}
+
+func (p percentiles) values() []float64 {
+	return []float64{p.Aggressive, p.Unlikely, p.CoinToss, p.Probable, p.Likely, p.Conservative, p.Safe, p.AlmostCertain}
+}
+
+// xmr is the wire format of an XmR process behavior chart.
+type xmr struct {
+	Average float64   `json:"average"`
+	UNPL    float64   `json:"upper_natural_process_limit"`
+	LNPL    float64   `json:"lower_natural_process_limit"`
+	Values  []float64 `json:"values"`
+}
+
+func (x xmr) chart(title, yLabel string, labels []string) []string {
+	if len(x.Values) == 0 {
+		return nil
+	}
+	if len(labels) != len(x.Values) {
+		labels = indexLabels(len(x.Values))
+	}
+	return []string{xmrChart(title, yLabel, labels, x.Values, x.Average, x.UNPL, x.LNPL)}
+}
+
+func cycleTime(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Percentiles *percentiles `json:"percentiles"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || res.Percentiles == nil {
+		return nil, err
+	}
+	return []string{xyChart("Cycle time percentiles", "Days", percentileLabels, series{bar: true, values: res.Percentiles.values()})}, nil
+}
+
+func forecast(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Percentiles *percentiles   `json:"percentiles"`
+		Context     map[string]any `json:"context"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || res.Percentiles == nil {
+		return nil, err
+	}
+	if res.Context["simulation_mode"] == "scope" {
+		return []string{xyChart("Forecast: items delivered by the target date", "Items", percentileLabels, series{bar: true, values: res.Percentiles.values()})}, nil
+	}
+	return []string{xyChart("Forecast: days to completion", "Days", percentileLabels, series{bar: true, values: res.Percentiles.values()})}, nil
+}
+
+func statusPersistence(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Persistence []struct {
+			Status   string  `json:"statusName"`
+			CoinToss float64 `json:"coin_toss"`
+			Likely   float64 `json:"likely"`
+		} `json:"persistence"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.Persistence) == 0 {
+		return nil, err
+	}
+	labels := make([]string, len(res.Persistence))
+	p85, p50 := make([]float64, len(labels)), make([]float64, len(labels))
+	for i, p := range res.Persistence {
+		labels[i], p85[i], p50[i] = p.Status, p.Likely, p.CoinToss
+	}
+	return []string{xyChart("Days in status (bars: P85, line: P50)", "Days", labels, series{bar: true, values: p85}, series{values: p50})}, nil
+}
+
+// ageBands lists the age distribution bands of analyze_work_item_age from young to old.
+var ageBands = []string{"Inconspicuous (within P50)", "Aging (P50-P85)", "Warning (P85-P95)", "Extreme (>P95)"}
+
+func workItemAge(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Summary struct {
+			Distribution map[string]float64 `json:"distribution"`
+		} `json:"summary"`
+		AgingChart *struct {
+			Columns []struct {
+				Status string  `json:"status"`
+				P50    float64 `json:"p50"`
+				P85    float64 `json:"p85"`
+				P95    float64 `json:"p95"`
+			} `json:"columns"`
+			Items []struct {
+				Position int     `json:"position"`
+				Age      float64 `json:"age_days"`
+			} `json:"items"`
+		} `json:"aging_chart"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.Summary.Distribution) == 0 {
+		return nil, err
+	}
+	keys := slices.Sorted(maps.Keys(res.Summary.Distribution))
+	slices.SortStableFunc(keys, func(a, b string) int {
+		rank := func(k string) int {
+			if i := slices.Index(ageBands, k); i >= 0 {
+				return i
+			}
+			return len(ageBands)
+		}
+		return rank(a) - rank(b)
+	})
+	parts := make([]slice, len(keys))
+	for i, k := range keys {
+		parts[i] = slice{k, res.Summary.Distribution[k]}
+	}
+	diagrams := []string{pieChart("Work item age against cycle time percentiles", parts...)}
+
+	// Aging WIP chart: Mermaid has no scatter, so each column shows its oldest item.
+	if chart := res.AgingChart; chart != nil && len(chart.Columns) > 0 && len(chart.Items) > 0 {
+		n := len(chart.Columns)
+		labels := make([]string, n)
+		oldest, p50, p85, p95 := make([]float64, n), make([]float64, n), make([]float64, n), make([]float64, n)
+		for i, c := range chart.Columns {
+			labels[i], p50[i], p85[i], p95[i] = c.Status, c.P50, c.P85, c.P95
+		}
+		for _, item := range chart.Items {
+			if item.Position >= 0 && item.Position < n {
+				oldest[item.Position] = max(oldest[item.Position], item.Age)
+			}
+		}
+		diagrams = append(diagrams, xyChart("Aging WIP (bars: oldest item per column, lines: P50, P85 and P95 age at column exit)", "Days", labels,
+			series{bar: true, values: oldest}, series{values: p50}, series{values: p85}, series{values: p95}))
+	}
+	return diagrams, nil
+}
+
+func throughput(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Buckets []struct {
+			Label string `json:"label"`
+		} `json:"@metadata"`
+		Total     []float64 `json:"total_throughput"`
+		Stability *xmr      `json:"stability"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.Total) == 0 {
+		return nil, err
+	}
+	labels := indexLabels(len(res.Total))
+	if len(res.Buckets) == len(res.Total) {
+		for i, b := range res.Buckets {
+			labels[i] = b.Label
+		}
+	}
+	plotted := []series{{bar: true, values: res.Total}}
+	if res.Stability != nil {
+		avg := make([]float64, len(res.Total))
+		for i := range avg {
+			avg[i] = res.Stability.Average
+		}
+		plotted = append(plotted, series{values: avg})
+	}
+	return []string{xyChart("Throughput (line: average)", "Items", labels, plotted...)}, nil
+}
+
+func releaseBurnup(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Burnup struct {
+			Version string `json:"version"`
+			Series  []struct {
+				Label     string  `json:"label"`
+				Scope     float64 `json:"scope"`
+				Completed float64 `json:"completed"`
+			} `json:"series"`
+		} `json:"release_burnup"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.Burnup.Series) == 0 {
+		return nil, err
+	}
+	points := res.Burnup.Series
+	labels := make([]string, len(points))
+	scope, completed := make([]float64, len(points)), make([]float64, len(points))
+	for i, p := range points {
+		labels[i], scope[i], completed[i] = p.Label, p.Scope, p.Completed
+	}
+	return []string{xyChart(fmt.Sprintf("Release %s burnup (bars: completed, line: scope)", res.Burnup.Version), "Items", labels, series{bar: true, values: completed}, series{values: scope})}, nil
+}
+
+func processStability(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Scatter []struct {
+			Key string `json:"key"`
+		} `json:"scatterplot"`
+		Stability struct {
+			XmR xmr `json:"xmr"`
+		} `json:"stability"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	labels := make([]string, len(res.Scatter))
+	for i, p := range res.Scatter {
+		labels[i] = p.Key
+	}
+	return res.Stability.XmR.chart("Cycle time XmR (lines: values, average, UNPL, LNPL)", "Days", labels), nil
+}
+
+func processEvolution(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Evolution struct {
+			Subgroups []struct {
+				Label string `json:"label"`
+			} `json:"subgroups"`
+			AverageChart xmr `json:"average_chart"`
+		} `json:"evolution"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	labels := make([]string, len(res.Evolution.Subgroups))
+	for i, g := range res.Evolution.Subgroups {
+		labels[i] = g.Label
+	}
+	return res.Evolution.AverageChart.chart("Average cycle time per subgroup (lines: values, average, UNPL, LNPL)", "Days", labels), nil
+}
+
+func wipStability(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Stability struct {
+			XmR xmr `json:"xmr"`
+		} `json:"wip_stability"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res.Stability.XmR.chart("WIP XmR (lines: values, average, UNPL, LNPL)", "Items", nil), nil
+}
+
+func wipAgeStability(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Stability struct {
+			XmR xmr `json:"xmr"`
+		} `json:"wip_age_stability"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	return res.Stability.XmR.chart("Total WIP age XmR (lines: values, average, UNPL, LNPL)", "Days", nil), nil
+}
+
+func flowDebt(data json.RawMessage) ([]string, error) {
+	var res struct {
+		FlowDebt struct {
+			Buckets []struct {
+				Label      string  `json:"label"`
+				Arrivals   float64 `json:"arrivals"`
+				Departures float64 `json:"departures"`
+			} `json:"buckets"`
+		} `json:"flow_debt"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.FlowDebt.Buckets) == 0 {
+		return nil, err
+	}
+	buckets := res.FlowDebt.Buckets
+	labels := make([]string, len(buckets))
+	arrivals, departures := make([]float64, len(buckets)), make([]float64, len(buckets))
+	for i, b := range buckets {
+		labels[i], arrivals[i], departures[i] = b.Label, b.Arrivals, b.Departures
+	}
+	return []string{xyChart("Flow debt (bars: commitments, line: deliveries)", "Items", labels, series{bar: true, values: arrivals}, series{values: departures})}, nil
+}
+
+type yieldResult struct {
+	Ingested  float64 `json:"totalIngested"`
+	Delivered float64 `json:"deliveredCount"`
+	Abandoned float64 `json:"abandonedCount"`
+	Rate      float64 `json:"overallYieldRate"`
+}
+
+func yield(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Yield      *yieldResult           `json:"yield"`
+		Stratified map[string]yieldResult `json:"stratified"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || res.Yield == nil {
+		return nil, err
+	}
+	y := res.Yield
+	diagrams := []string{pieChart("Outcome of ingested items",
+		slice{"Delivered", y.Delivered},
+		slice{"Abandoned", y.Abandoned},
+		slice{"Not finished", y.Ingested - y.Delivered - y.Abandoned},
+	)}
+	var types []string
+	var rates []float64
+	for _, t := range slices.Sorted(maps.Keys(res.Stratified)) {
+		if res.Stratified[t].Ingested > 0 {
+			types = append(types, t)
+			rates = append(rates, res.Stratified[t].Rate*100)
+		}
+	}
+	if len(types) > 1 {
+		diagrams = append(diagrams, xyChart("Yield rate by issue type", "% delivered", types, series{bar: true, values: rates}))
+	}
+	return diagrams, nil
+}
+
+func residenceTime(data json.RawMessage) ([]string, error) {
+	var res struct {
+		ResidenceTime struct {
+			Series []struct {
+				Label string  `json:"label"`
+				W     float64 `json:"w"`
+				WStar float64 `json:"w_star"`
+			} `json:"series"`
+		} `json:"residence_time"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.ResidenceTime.Series) == 0 {
+		return nil, err
+	}
+	points := pick(res.ResidenceTime.Series, thin(len(res.ResidenceTime.Series)))
+	labels := make([]string, len(points))
+	w, wStar := make([]float64, len(points)), make([]float64, len(points))
+	for i, p := range points {
+		labels[i], w[i], wStar[i] = p.Label, p.W, p.WStar
+	}
+	return []string{xyChart("Residence time (first line: w, second line: w*)", "Days", labels, series{values: w}, series{values: wStar})}, nil
+}
+
+func cfd(data json.RawMessage) ([]string, error) {
+	var res struct {
+		CFD struct {
+			Buckets []struct {
+				Label       string                        `json:"label"`
+				ByIssueType map[string]map[string]float64 `json:"by_issue_type"`
+			} `json:"buckets"`
+			Statuses []string `json:"statuses"`
+		} `json:"cfd_data"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.CFD.Buckets) == 0 {
+		return nil, err
+	}
+	// Mermaid xy charts have no legend or stacking, so a full CFD is unreadable;
+	// the last bucket shows where the items are today.
+	last := res.CFD.Buckets[len(res.CFD.Buckets)-1]
+	var labels []string
+	var counts []float64
+	for _, status := range res.CFD.Statuses {
+		n := 0.0
+		for _, byStatus := range last.ByIssueType {
+			n += byStatus[status]
+		}
+		if n > 0 {
+			labels = append(labels, status)
+			counts = append(counts, n)
+		}
+	}
+	if len(labels) == 0 {
+		return nil, nil
+	}
+	return []string{xyChart("Items per status on "+last.Label, "Items", labels, series{bar: true, values: counts})}, nil
+}
+
+func itemJourney(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Key  string `json:"key"`
+		Path []struct {
+			Status string  `json:"status"`
+			Days   float64 `json:"days"`
+		} `json:"path"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.Path) == 0 {
+		return nil, err
+	}
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+	for i, step := range res.Path {
+		fmt.Fprintf(&b, "    %s[%s]\n", nodeID(i), quote(fmt.Sprintf("%s · %sd", step.Status, number(step.Days))))
+		if i > 0 {
+			fmt.Fprintf(&b, "    %s --> %s\n", nodeID(i-1), nodeID(i))
+		}
+	}
+	return []string{b.String()}, nil
+}
+
+// checkpoint is the wire format of a forecast_backtest checkpoint.
+type checkpoint struct {
+	Date   string  `json:"date"`
+	Actual float64 `json:"actual_value"`
+	P50    float64 `json:"predicted_p50"`
+	P85    float64 `json:"predicted_p85"`
+}
+
+// calibrationPoint is the wire format of a backtest calibration level.
+type calibrationPoint struct {
+	Percentile string  `json:"percentile"`
+	Nominal    float64 `json:"nominal"`
+	Observed   float64 `json:"observed"`
+}
+
+func backtest(data json.RawMessage) ([]string, error) {
+	var res struct {
+		Accuracy struct {
+			Checkpoints []checkpoint       `json:"checkpoints"`
+			Calibration []calibrationPoint `json:"calibration"`
+		} `json:"accuracy"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.Accuracy.Checkpoints) == 0 {
+		return nil, err
+	}
+	// Plot checkpoints in date order, whatever order the engine produced them in.
+	points := slices.Clone(res.Accuracy.Checkpoints)
+	slices.SortStableFunc(points, func(a, b checkpoint) int { return strings.Compare(a.Date, b.Date) })
+	labels := make([]string, len(points))
+	actual, p50, p85 := make([]float64, len(points)), make([]float64, len(points)), make([]float64, len(points))
+	for i, p := range points {
+		labels[i], actual[i], p50[i], p85[i] = p.Date, p.Actual, p.P50, p.P85
+	}
+	diagrams := []string{xyChart("Backtest (bars: actual, lines: predicted P50 and P85)", "Value", labels, series{bar: true, values: actual}, series{values: p50}, series{values: p85})}
+
+	// Reliability diagram: a calibrated model's bars reach its nominal line.
+	if levels := res.Accuracy.Calibration; len(levels) > 0 {
+		labels := make([]string, len(levels))
+		observed, nominal := make([]float64, len(levels)), make([]float64, len(levels))
+		for i, l := range levels {
+			labels[i], observed[i], nominal[i] = l.Percentile, l.Observed*100, l.Nominal*100
+		}
+		diagrams = append(diagrams, xyChart("Calibration (bars: % of forecasts honored, line: nominal %)", "%", labels, series{bar: true, values: observed}, series{values: nominal}))
+	}
+	return diagrams, nil
+}
+
+func burnUp(data json.RawMessage) ([]string, error) {
+	var res struct {
+		BurnUp []struct {
+			Date string  `json:"date"`
+			P50  float64 `json:"p50"`
+			P85  float64 `json:"p85"`
+			P95  float64 `json:"p95"`
+		} `json:"burn_up"`
+	}
+	if err := json.Unmarshal(data, &res); err != nil || len(res.BurnUp) == 0 {
+		return nil, err
+	}
+	labels := make([]string, len(res.BurnUp))
+	p50, p85, p95 := make([]float64, len(res.BurnUp)), make([]float64, len(res.BurnUp)), make([]float64, len(res.BurnUp))
+	for i, p := range res.BurnUp {
+		labels[i], p50[i], p85[i], p95[i] = p.Date, p.P50, p.P85, p.P95
+	}
+	return []string{xyChart("Burn-up cone (lines: items delivered at least, P50, P85 and P95)", "Items", labels, series{values: p50}, series{values: p85}, series{values: p95})}, nil
+}
@@ -0,0 +1,130 @@
+// Package visuals renders tool results as Mermaid diagrams that agents can show
+// inline in chat clients, without the chart HTTP server. Like the chart
+// templates of internal/charts, generators read the JSON response envelope of
+// a tool, so they depend on its wire format only.
+package visuals
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// MaxPoints caps the x-axis of a chart; longer series are thinned evenly,
+// always keeping the last point.
+const MaxPoints = 60
+
+// series is a named line or bar of an xy chart.
+type series struct {
+	bar    bool
+	values []float64
+}
+
+// xyChart renders a Mermaid xychart-beta with one x-axis category per label.
+func xyChart(title, yLabel string, labels []string, data ...series) string {
+	var b strings.Builder
+	b.WriteString("xychart-beta\n")
+	fmt.Fprintf(&b, "    title %s\n", quote(title))
+	quoted := make([]string, len(labels))
+	for i, l := range labels {
+		quoted[i] = quote(l)
+	}
+	fmt.Fprintf(&b, "    x-axis [%s]\n", strings.Join(quoted, ", "))
+	fmt.Fprintf(&b, "    y-axis %s\n", quote(yLabel))
+	for _, s := range data {
+		kind := "line"
+		if s.bar {
+			kind = "bar"
+		}
+		fmt.Fprintf(&b, "    %s [%s]\n", kind, numbers(s.values))
+	}
+	return b.String()
+}
+
+// xmrChart renders an XmR process behavior chart: the values with their
+// average and natural process limits as flat lines.
+func xmrChart(title, yLabel string, labels []string, values []float64, average, upper, lower float64) string {
+	flat := func(v float64) series {
+		s := series{values: make([]float64, len(values))}
+		for i := range s.values {
+			s.values[i] = v
+		}
+		return s
+	}
+	return xyChart(title, yLabel, labels, series{values: values}, flat(average), flat(upper), flat(lower))
+}
+
+// slice is a labeled share of a pie chart.
+type slice struct {
+	label string
+	value float64
+}
+
+// pieChart renders a Mermaid pie chart, leaving out empty slices.
+func pieChart(title string, slices ...slice) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "pie showData\n    title %s\n", strings.ReplaceAll(title, "\"", "'"))
+	for _, s := range slices {
+		if s.value > 0 {
+			fmt.Fprintf(&b, "    %s : %s\n", quote(s.label), number(s.value))
+		}
+	}
+	return b.String()
+}
+
+// thin returns the indices of at most MaxPoints evenly spaced points of a
+// series of n points, always including the last.
+func thin(n int) []int {
+	if n <= MaxPoints {
+		idx := make([]int, n)
+		for i := range idx {
+			idx[i] = i
+		}
+		return idx
+	}
+	idx := make([]int, MaxPoints)
+	for i := range idx {
+		idx[i] = int(math.Round(float64(i) * float64(n-1) / float64(MaxPoints-1)))
+	}
+	return idx
+}
+
+// pick returns the elements of s at idx.
+func pick[T any](s []T, idx []int) []T {
+	out := make([]T, len(idx))
+	for i, j := range idx {
+		out[i] = s[j]
+	}
+	return out
+}
+
+// indexLabels labels n points 1…n.
+func indexLabels(n int) []string {
+	labels := make([]string, n)
+	for i := range labels {
+		labels[i] = strconv.Itoa(i + 1)
+	}
+	return labels
+}
+
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(s, `"`, "'") + `"`
+}
+
+func number(v float64) string {
+	return strconv.FormatFloat(math.Round(v*100)/100, 'f', -1, 64)
+}
+
+func numbers(vs []float64) string {
+	out := make([]string, len(vs))
+	for i, v := range vs {
+		out[i] = number(v)
+	}
+	return strings.Join(out, ", ")
+}
+
+// nodeID is the Mermaid node identifier of the i-th step of a flowchart.
+func nodeID(i int) string {
+	return "s" + strconv.Itoa(i)
+}
@@ -0,0 +1,131 @@
+package visuals
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHasGenerator(t *testing.T) {
+	if !HasGenerator("analyze_flow_debt") {
+		t.Error("expected HasGenerator to return true for analyze_flow_debt")
+	}
+	if HasGenerator("import_boards") {
+		t.Error("expected HasGenerator to return false for import_boards")
+	}
+}
+
+// TestMermaid_GoldenResults draws every golden tool response.
+func TestMermaid_GoldenResults(t *testing.T) {
+	files, err := filepath.Glob(filepath.Join("..", "testdata", "golden", "mcp", "*.json"))
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no golden results found: %v", err)
+	}
+	for _, file := range files {
+		name := strings.TrimSuffix(filepath.Base(file), ".json")
+		tool := strings.TrimSuffix(strings.TrimSuffix(name, "_duration"), "_scope")
+		t.Run(name, func(t *testing.T) {
+			raw, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			diagrams, err := Mermaid(tool, raw)
+			if err != nil {
+				t.Fatalf("Mermaid: %v", err)
+			}
+			if len(diagrams) == 0 {
+				t.Fatalf("expected a diagram for %s", tool)
+			}
+			for _, d := range diagrams {
+				if !strings.HasPrefix(d, "xychart-beta\n") && !strings.HasPrefix(d, "pie showData\n") {
+					t.Errorf("unexpected diagram type:\n%s", d)
+				}
+			}
+		})
+	}
+}
+
+func TestMermaid_FlowDebt(t *testing.T) {
+	envelope := []byte(`{"data": {"flow_debt": {"buckets": [
+		{"label": "2026-W01", "arrivals": 3, "departures": 1},
+		{"label": "2026-W\"02", "arrivals": 2.555, "departures": 4}
+	]}}}`)
+	diagrams, err := Mermaid("analyze_flow_debt", envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `xychart-beta
+    title "Flow debt (bars: commitments, line: deliveries)"
+    x-axis ["2026-W01", "2026-W'02"]
+    y-axis "Items"
+    bar [3, 2.56]
+    line [1, 4]
+`
+	if len(diagrams) != 1 || diagrams[0] != want {
+		t.Errorf("got %q, want %q", diagrams, want)
+	}
+}
+
+func TestMermaid_ItemJourney(t *testing.T) {
+	envelope := []byte(`{"data": {"key": "PROJ-1", "path": [{"status": "Open", "days": 2}, {"status": "In Progress", "days": 0.5}]}}`)
+	diagrams, _ := Mermaid("analyze_item_journey", envelope)
+	want := "flowchart LR\n    s0[\"Open · 2d\"]\n    s1[\"In Progress · 0.5d\"]\n    s0 --> s1\n"
+	if len(diagrams) != 1 || diagrams[0] != want {
+		t.Errorf("got %q, want %q", diagrams, want)
+	}
+}
+
+func TestMermaid_BurnUp(t *testing.T) {
+	envelope := []byte(`{"data": {"burn_up": [
+		{"day": 7, "date": "2026-04-08", "p50": 5, "p85": 3, "p95": 2},
+		{"day": 14, "date": "2026-04-15", "p50": 11, "p85": 8, "p95": 6}
+	]}}`)
+	diagrams, _ := Mermaid("forecast_burnup", envelope)
+	want := `xychart-beta
+    title "Burn-up cone (lines: items delivered at least, P50, P85 and P95)"
+    x-axis ["2026-04-08", "2026-04-15"]
+    y-axis "Items"
+    line [5, 11]
+    line [3, 8]
+    line [2, 6]
+`
+	if len(diagrams) != 1 || diagrams[0] != want {
+		t.Errorf("got %q, want %q", diagrams, want)
+	}
+}
+
+func TestMermaid_BacktestCalibration(t *testing.T) {
+	envelope := []byte(`{"data": {"accuracy": {
+		"checkpoints": [{"date": "2026-04-08", "actual_value": 9, "predicted_p50": 10, "predicted_p85": 14}],
+		"calibration": [{"percentile": "P50", "nominal": 0.5, "observed": 0.55}, {"percentile": "P85", "nominal": 0.85, "observed": 0.7}]
+	}}}`)
+	diagrams, _ := Mermaid("forecast_backtest", envelope)
+	want := `xychart-beta
+    title "Calibration (bars: % of forecasts honored, line: nominal %)"
+    x-axis ["P50", "P85"]
+    y-axis "%"
+    bar [55, 70]
+    line [50, 85]
+`
+	if len(diagrams) != 2 || diagrams[1] != want {
+		t.Errorf("got %q, want %q as the second diagram", diagrams, want)
+	}
+}
+
+func TestMermaid_EmptyResult(t *testing.T) {
+	diagrams, err := Mermaid("analyze_flow_debt", []byte(`{"data": {"flow_debt": {"buckets": []}}}`))
+	if err != nil || diagrams != nil {
+		t.Errorf("expected no diagram for an empty result, got %q, %v", diagrams, err)
+	}
+}
+
+func TestThin(t *testing.T) {
+	if idx := thin(10); len(idx) != 10 || idx[9] != 9 {
+		t.Errorf("short series should be kept whole, got %v", idx)
+	}
+	idx := thin(183)
+	if len(idx) != MaxPoints || idx[0] != 0 || idx[len(idx)-1] != 182 {
+		t.Errorf("expected %d points from first to last, got %d (%d..%d)", MaxPoints, len(idx), idx[0], idx[len(idx)-1])
+	}
+}
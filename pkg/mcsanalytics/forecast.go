@@ -0,0 +1,109 @@
+package mcsanalytics
+
+import (
+	"context"
+	"time"
+
+	"mcs-mcp/internal/mcp"
+	"mcs-mcp/internal/stats"
+)
+
+// ForecastRequest describes the scope and the history of a forecast. Board
+// settings (working calendar, WIP limits, type aliases) apply as they do to
+// forecast_monte_carlo.
+type ForecastRequest struct {
+	IncludeBacklog    bool           // Unstarted items (Demand and Upstream tiers) of the board
+	IncludeWIP        bool           // Items past the commitment point
+	AdditionalItems   int            // Items not yet in Jira; ignored when Targets is set
+	Targets           map[string]int // Exact item counts per issue type
+	IssueTypes        []string       // Restrict the scope and the sampled throughput; empty = all types
+	TargetDays        int            // Scope forecasts: horizon in days
+	TargetDate        time.Time      // Scope forecasts: horizon as a date; overrides TargetDays
+	HistoryWindowDays int            // Throughput sample; 0 = the default window
+	AsOf              time.Time      // Forecast as of this past date; zero = now
+	Ensemble          bool           // Duration forecasts: also run the cycle-time model
+}
+
+// Percentiles of a forecast, named by confidence as in forecast_monte_carlo:
+// calendar days until the scope is done (duration) or items done by the
+// target (scope).
+type Percentiles struct {
+	Aggressive    float64 // P10
+	Unlikely      float64 // P30
+	CoinToss      float64 // P50
+	Probable      float64 // P70
+	Likely        float64 // P85
+	Conservative  float64 // P90
+	Safe          float64 // P95
+	AlmostCertain float64 // P98
+}
+
+// Ensemble is the cycle-time model of an ensemble duration forecast.
+type Ensemble struct {
+	Percentiles  Percentiles
+	Parallelism  int     // Items worked at once
+	Disagreement float64 // P85 gap between the models relative to the larger P85
+	ModelRisk    string  // "low", "moderate", or "high"
+}
+
+// Forecast is the result of a Monte-Carlo forecast.
+type Forecast struct {
+	Percentiles    Percentiles
+	Predictability string    // Classification of the fat-tail ratio
+	FatTailRatio   float64   // P98/P50
+	Ensemble       *Ensemble // Set for ensemble duration forecasts
+	Warnings       []string  // Caveats that limit how the forecast may be used
+	Insights       []string
+}
+
+// ForecastDuration forecasts the calendar days until the requested scope is
+// done.
+func (c *Client) ForecastDuration(ctx context.Context, projectKey string, boardID int, req ForecastRequest) (Forecast, error) {
+	return c.forecast(ctx, projectKey, boardID, mcp.SimModeDuration, req)
+}
+
+// ForecastScope forecasts how many items are done by TargetDate (or within
+// TargetDays).
+func (c *Client) ForecastScope(ctx context.Context, projectKey string, boardID int, req ForecastRequest) (Forecast, error) {
+	return c.forecast(ctx, projectKey, boardID, mcp.SimModeScope, req)
+}
+
+func (c *Client) forecast(ctx context.Context, projectKey string, boardID int, mode mcp.SimulationMode, req ForecastRequest) (Forecast, error) {
+	args := mcp.ForecastMonteCarloInput{
+		ProjectKey:             projectKey,
+		BoardID:                boardID,
+		Mode:                   mode,
+		IncludeExistingBacklog: req.IncludeBacklog,
+		IncludeWIP:             req.IncludeWIP,
+		AdditionalItems:        req.AdditionalItems,
+		Targets:                req.Targets,
+		IssueTypes:             req.IssueTypes,
+		TargetDays:             req.TargetDays,
+		HistoryWindowDays:      req.HistoryWindowDays,
+		Ensemble:               req.Ensemble,
+	}
+	if !req.TargetDate.IsZero() {
+		args.TargetDate = req.TargetDate.Format(stats.DateFormat)
+	}
+	if !req.AsOf.IsZero() {
+		args.AsOfDate = req.AsOf.Format(stats.DateFormat)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	res, guardrails, err := c.srv.Forecast(ctx, args)
+	if err != nil {
+		return Forecast{}, err
+	}
+	f := Forecast{
+		Percentiles:    Percentiles(res.Percentiles),
+		Predictability: res.Predictability,
+		FatTailRatio:   res.FatTailRatio,
+		Warnings:       guardrails.Warnings,
+		Insights:       guardrails.Insights,
+	}
+	if e := res.Ensemble; e != nil {
+		f.Ensemble = &Ensemble{Percentiles: Percentiles(e.Percentiles), Parallelism: e.Parallelism, Disagreement: e.Disagreement, ModelRisk: e.ModelRisk}
+	}
+	return f, nil
+}
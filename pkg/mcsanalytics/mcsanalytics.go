@@ -0,0 +1,71 @@
+// Package mcsanalytics embeds the analytics engine of mcs-mcp in Go programs
+// that do not speak MCP, such as dashboards and CI gates. It exposes the
+// ingestion of a board's Jira history, workflow discovery and confirmation,
+// and Monte-Carlo forecasting as typed functions.
+//
+// A Client works on the same cache directory as the MCP server: event logs,
+// confirmed workflow mappings, and settings are shared, and changes are
+// recorded in the configuration audit trail under the client "mcsanalytics".
+// A typical session ingests a board, confirms its workflow once, and then
+// forecasts:
+//
+//	c, err := mcsanalytics.New(mcsanalytics.Options{})
+//	...
+//	if _, err := c.Ingest(ctx, "PROJ", 42, nil); err != nil { ... }
+//	w, err := c.DiscoverWorkflow(ctx, "PROJ", 42)
+//	if !w.Confirmed {
+//		err = c.ConfirmWorkflow(ctx, w) // after review
+//	}
+//	f, err := c.ForecastDuration(ctx, "PROJ", 42, mcsanalytics.ForecastRequest{IncludeBacklog: true, IncludeWIP: true})
+package mcsanalytics
+
+import (
+	"fmt"
+	"sync"
+
+	"mcs-mcp/internal/config"
+	"mcs-mcp/internal/jira"
+	"mcs-mcp/internal/mcp"
+)
+
+// Options configure a Client. Empty fields keep the value of the environment
+// and the .env files the MCP server reads (JIRA_URL, JIRA_TOKEN, ...).
+type Options struct {
+	JiraURL       string // JIRA_URL
+	JiraToken     string // JIRA_TOKEN
+	JiraTokenType string // JIRA_TOKEN_TYPE: "pat" (Data Center) or "api" (Cloud)
+	JiraUserEmail string // JIRA_USER_EMAIL, required with "api" tokens
+	CacheDir      string // Event logs and workflow mappings; default: the server's cache directory
+}
+
+// Client runs analyses against Jira boards. Its methods are safe for
+// concurrent use but run one at a time, since every call works on the board
+// it names.
+type Client struct {
+	mu  sync.Mutex
+	srv *mcp.Server
+}
+
+// New creates a Client from the environment, overridden by opts.
+func New(opts Options) (*Client, error) {
+	cfg, err := config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if opts.JiraURL != "" {
+		cfg.Jira.BaseURL = opts.JiraURL
+	}
+	if opts.JiraToken != "" {
+		cfg.Jira.Token = opts.JiraToken
+	}
+	if opts.JiraTokenType != "" {
+		cfg.Jira.TokenType = opts.JiraTokenType
+	}
+	if opts.JiraUserEmail != "" {
+		cfg.Jira.UserEmail = opts.JiraUserEmail
+	}
+	if opts.CacheDir != "" {
+		cfg.CacheDir = opts.CacheDir
+	}
+	return &Client{srv: mcp.NewServer(cfg, jira.NewClient(cfg.Jira))}, nil
+}
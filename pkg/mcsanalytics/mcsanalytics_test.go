@@ -0,0 +1,65 @@
+package mcsanalytics
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestClient runs a session on the synthetic MCSTEST board, which is served
+// from the cache without Jira.
+func TestClient(t *testing.T) {
+	cacheDir := t.TempDir()
+	events, err := os.ReadFile(filepath.Join("..", "..", "internal", "testdata", "golden", "simulated_events.jsonl"))
+	if err != nil {
+		t.Fatalf("read fixture: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(cacheDir, "MCSTEST_0.jsonl"), events, 0644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+
+	c, err := New(Options{CacheDir: cacheDir})
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	ctx := context.Background()
+
+	ds, err := c.Ingest(ctx, "MCSTEST", 0, nil)
+	if err != nil || ds.Issues == 0 || ds.LastEvent.IsZero() {
+		t.Fatalf("Expected the fixture ingested, got %+v (%v)", ds, err)
+	}
+
+	w, err := c.DiscoverWorkflow(ctx, "MCSTEST", 0)
+	if err != nil || len(w.Statuses) == 0 || w.CommitmentPoint == "" || w.Confirmed {
+		t.Fatalf("Expected an unconfirmed proposal, got %+v (%v)", w, err)
+	}
+	if err := c.ConfirmWorkflow(ctx, w); err != nil {
+		t.Fatalf("ConfirmWorkflow: %v", err)
+	}
+	if again, err := c.DiscoverWorkflow(ctx, "MCSTEST", 0); err != nil || !again.Confirmed {
+		t.Errorf("Expected the mapping confirmed, got %v (%v)", again.Confirmed, err)
+	}
+
+	asOf := ds.LastEvent.Truncate(24 * time.Hour)
+	f, err := c.ForecastDuration(ctx, "MCSTEST", 0, ForecastRequest{IncludeBacklog: true, IncludeWIP: true, AsOf: asOf})
+	if err != nil {
+		t.Fatalf("ForecastDuration: %v", err)
+	}
+	if f.Percentiles.CoinToss <= 0 || f.Percentiles.Likely < f.Percentiles.CoinToss {
+		t.Errorf("Expected increasing positive durations, got %+v", f.Percentiles)
+	}
+
+	s, err := c.ForecastScope(ctx, "MCSTEST", 0, ForecastRequest{TargetDays: 30, AsOf: asOf})
+	if err != nil {
+		t.Fatalf("ForecastScope: %v", err)
+	}
+	if s.Percentiles.CoinToss <= 0 {
+		t.Errorf("Expected items done within 30 days, got %+v", s.Percentiles)
+	}
+
+	if _, err := c.ForecastScope(ctx, "MCSTEST", 0, ForecastRequest{TargetDays: 30, AsOf: time.Now().AddDate(0, 0, 2)}); err == nil {
+		t.Error("Expected a future as-of date rejected")
+	}
+}
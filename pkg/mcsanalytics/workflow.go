@@ -0,0 +1,103 @@
+package mcsanalytics
+
+import (
+	"context"
+	"time"
+
+	"mcs-mcp/internal/mcp"
+)
+
+// ProgressFunc receives the progress of an ingestion: issues fetched so far,
+// the expected total (0 when unknown), and a short description.
+type ProgressFunc func(fetched, total int, message string)
+
+// Dataset summarizes the ingested history of a board.
+type Dataset struct {
+	Issues      int
+	FirstEvent  time.Time
+	LastEvent   time.Time
+	IssueTypes  map[string]float64 // Share of each issue type in the probe sample
+	Resolutions []string           // Resolution names in the probe sample
+}
+
+// Status is one status of a workflow mapping.
+type Status struct {
+	ID      string
+	Name    string
+	Tier    string // "Demand", "Upstream", "Downstream", or "Finished"
+	Role    string // "active", "queue", or "ignore"; empty for Finished statuses
+	Outcome string // "delivered" or "abandoned" for Finished statuses
+}
+
+// Workflow is the workflow mapping of a board, in status order.
+type Workflow struct {
+	ProjectKey      string
+	BoardID         int
+	Statuses        []Status
+	CommitmentPoint string            // Status ID where the cycle-time clock starts
+	Resolutions     map[string]string // Resolution name → "delivered" or "abandoned"
+	Issues          int               // Issues the discovery is based on
+	Confirmed       bool              // The board already has a confirmed mapping
+}
+
+// Ingest fetches the Jira history of a board into the cache, or catches up a
+// cached one, and summarizes it. The first ingestion of a board fetches every
+// issue and may take minutes; progress may be nil.
+func (c *Client) Ingest(ctx context.Context, projectKey string, boardID int, progress ProgressFunc) (Dataset, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	summary, err := c.srv.Ingest(ctx, projectKey, boardID, progress)
+	if err != nil {
+		return Dataset{}, err
+	}
+	return Dataset{
+		Issues:      summary.Whole.TotalItems,
+		FirstEvent:  summary.Whole.FirstEventAt,
+		LastEvent:   summary.Whole.LastEventAt,
+		IssueTypes:  summary.Sample.WorkItemWeights,
+		Resolutions: summary.Sample.ResolutionNames,
+	}, nil
+}
+
+// DiscoverWorkflow proposes a workflow mapping for a board from its recent
+// history, ingesting it first when needed. Nothing is stored; a board with a
+// confirmed mapping reports Confirmed and is analyzed with that mapping until
+// ConfirmWorkflow replaces it.
+func (c *Client) DiscoverWorkflow(ctx context.Context, projectKey string, boardID int) (Workflow, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p, err := c.srv.ProposeSetup(ctx, projectKey, boardID, nil)
+	if err != nil {
+		return Workflow{}, err
+	}
+	w := Workflow{
+		ProjectKey:      p.ProjectKey,
+		BoardID:         p.BoardID,
+		CommitmentPoint: p.CommitmentPoint,
+		Resolutions:     p.Resolutions,
+		Issues:          p.Issues,
+		Confirmed:       p.Confirmed,
+	}
+	for _, st := range p.Statuses {
+		w.Statuses = append(w.Statuses, Status(st))
+	}
+	return w, nil
+}
+
+// ConfirmWorkflow stores w as the confirmed workflow mapping and status order
+// of its board, as workflow_set_mapping and workflow_set_order do. Statuses
+// unknown to the project are rejected.
+func (c *Client) ConfirmWorkflow(ctx context.Context, w Workflow) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	p := mcp.SetupProposal{
+		ProjectKey:      w.ProjectKey,
+		BoardID:         w.BoardID,
+		CommitmentPoint: w.CommitmentPoint,
+		Resolutions:     w.Resolutions,
+	}
+	for _, st := range w.Statuses {
+		p.Statuses = append(p.Statuses, mcp.SetupStatus(st))
+	}
+	return c.srv.ConfirmSetup(ctx, p)
+}